@@ -0,0 +1,3572 @@
+// Code generated by cmd/openapi. DO NOT EDIT.
+
+// Package client is a client of the The Nom Database API (version 1.0), generated from its OpenAPI
+// document. Methods return an *Error for non-2xx responses.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Client calls the API at BaseURL
+type Client struct {
+	BaseURL    string // Including /api, e.g. https://nomdb.example.com/api
+	HTTPClient *http.Client
+
+	// RequestEditor, when set, edits every request before it is sent, e.g. to add
+	// the Authorization or X-API-Key header
+	RequestEditor func(req *http.Request) error
+}
+
+// NewClient creates a client of the API at baseURL
+func NewClient(baseURL string) *Client {
+	return &Client{BaseURL: strings.TrimSuffix(baseURL, "/"), HTTPClient: http.DefaultClient}
+}
+
+// WithBearerToken returns a RequestEditor that authenticates with an access token
+func WithBearerToken(token string) func(req *http.Request) error {
+	return func(req *http.Request) error {
+		req.Header.Set("Authorization", "Bearer "+token)
+		return nil
+	}
+}
+
+// WithAPIKey returns a RequestEditor that authenticates with an API key
+func WithAPIKey(key string) func(req *http.Request) error {
+	return func(req *http.Request) error {
+		req.Header.Set("X-API-Key", key)
+		return nil
+	}
+}
+
+// Error is a non-2xx response. Problem holds the problem details (RFC 7807) when the
+// API sent them.
+type Error struct {
+	StatusCode int
+	Problem    *ErrorResponse
+	Body       []byte
+}
+
+func (e *Error) Error() string {
+	if e.Problem != nil && e.Problem.Detail != nil {
+		return fmt.Sprintf("%d %s: %s", e.StatusCode, http.StatusText(e.StatusCode), *e.Problem.Detail)
+	}
+	return fmt.Sprintf("%d %s", e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+// FormFile is a file field of a multipart form
+type FormFile struct {
+	Name    string // File name sent to the API
+	Content io.Reader
+}
+
+// requestBody is an encoded request body with its content type
+type requestBody struct {
+	contentType string
+	content     io.Reader
+	err         error
+}
+
+func jsonBody(v any) *requestBody {
+	data, err := json.Marshal(v)
+	return &requestBody{contentType: "application/json", content: bytes.NewReader(data), err: err}
+}
+
+// formBody encodes fields, leaving out nil pointers and files
+func formBody(contentType string, fields map[string]any) *requestBody {
+	values := url.Values{}
+	var files []string
+	for name, value := range fields {
+		switch v := value.(type) {
+		case *FormFile:
+			if v != nil {
+				files = append(files, name)
+			}
+		default:
+			if s, ok := formValue(v); ok {
+				values.Set(name, s)
+			}
+		}
+	}
+	if contentType != "multipart/form-data" {
+		return &requestBody{contentType: contentType, content: strings.NewReader(values.Encode())}
+	}
+
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	for name := range values {
+		if err := w.WriteField(name, values.Get(name)); err != nil {
+			return &requestBody{err: err}
+		}
+	}
+	for _, name := range files {
+		file := fields[name].(*FormFile)
+		part, err := w.CreateFormFile(name, file.Name)
+		if err == nil {
+			_, err = io.Copy(part, file.Content)
+		}
+		if err != nil {
+			return &requestBody{err: err}
+		}
+	}
+	if err := w.Close(); err != nil {
+		return &requestBody{err: err}
+	}
+	return &requestBody{contentType: w.FormDataContentType(), content: &buf}
+}
+
+// formValue formats a form field, dereferencing pointers; nil pointers are left out
+func formValue(v any) (string, bool) {
+	switch v := v.(type) {
+	case *string:
+		if v == nil {
+			return "", false
+		}
+		return *v, true
+	case *int:
+		if v == nil {
+			return "", false
+		}
+		return fmt.Sprint(*v), true
+	case *float64:
+		if v == nil {
+			return "", false
+		}
+		return fmt.Sprint(*v), true
+	case *bool:
+		if v == nil {
+			return "", false
+		}
+		return fmt.Sprint(*v), true
+	}
+	return fmt.Sprint(v), true
+}
+
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, header http.Header, body *requestBody) (*http.Response, error) {
+	target := c.BaseURL + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	var content io.Reader
+	if body != nil {
+		if body.err != nil {
+			return nil, body.err
+		}
+		content = body.content
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, content)
+	if err != nil {
+		return nil, err
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", body.contentType)
+	}
+	if c.RequestEditor != nil {
+		if err := c.RequestEditor(req); err != nil {
+			return nil, err
+		}
+	}
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		defer resp.Body.Close()
+		apiErr := &Error{StatusCode: resp.StatusCode}
+		apiErr.Body, _ = io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+		var problem ErrorResponse
+		if json.Unmarshal(apiErr.Body, &problem) == nil {
+			apiErr.Problem = &problem
+		}
+		return nil, apiErr
+	}
+	return resp, nil
+}
+
+// decode decodes a JSON response into v and closes it
+func decode(resp *http.Response, v any) error {
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+type APIKey struct {
+	CreatedAt  *string `json:"created_at,omitempty"`
+	ExpiresAt  *string `json:"expires_at,omitempty"`
+	ID         *int    `json:"id,omitempty"`
+	IsActive   *bool   `json:"is_active,omitempty"`
+	KeyPrefix  *string `json:"key_prefix,omitempty"`
+	LastUsedAt *string `json:"last_used_at,omitempty"`
+	Name       *string `json:"name,omitempty"`
+	// Requests per minute, nil for the server default
+	RateLimit *int     `json:"rate_limit,omitempty"`
+	Scopes    []string `json:"scopes,omitempty"`
+	UserID    *int     `json:"user_id,omitempty"`
+}
+
+type AccountExport struct {
+	APIKeys    []map[string]json.RawMessage `json:"api_keys,omitempty"`
+	Badges     []map[string]json.RawMessage `json:"badges,omitempty"`
+	ExportedAt *string                      `json:"exported_at,omitempty"`
+	// Users the user follows
+	Following []map[string]json.RawMessage `json:"following,omitempty"`
+	Photos    []map[string]json.RawMessage `json:"photos,omitempty"`
+	Ratings   []map[string]json.RawMessage `json:"ratings,omitempty"`
+	// Restaurants created by the user
+	Restaurants      []map[string]json.RawMessage `json:"restaurants,omitempty"`
+	Sessions         []map[string]json.RawMessage `json:"sessions,omitempty"`
+	SuggestionEvents []map[string]json.RawMessage `json:"suggestion_events,omitempty"`
+	Suggestions      []map[string]json.RawMessage `json:"suggestions,omitempty"`
+	User             *User                        `json:"user,omitempty"`
+}
+
+type ActivityItem struct {
+	// The rating comment or suggestion notes
+	Comment   *string `json:"comment,omitempty"`
+	CreatedAt *string `json:"created_at,omitempty"`
+	// Of the rating or suggestion
+	ID *int `json:"id,omitempty"`
+	// Of the restaurant or suggested restaurant
+	Name *string `json:"name,omitempty"`
+	// The rated restaurant, nil for suggestions
+	RestaurantID *int `json:"restaurant_id,omitempty"`
+	// Average of the food, service and ambiance ratings
+	Score *float64 `json:"score,omitempty"`
+	// Of suggestions
+	Status *string `json:"status,omitempty"`
+	// rating or suggestion
+	Type     *string `json:"type,omitempty"`
+	UserID   *int    `json:"user_id,omitempty"`
+	Username *string `json:"username,omitempty"`
+}
+
+type ApproveSuggestionRequest struct {
+	Note *string `json:"note,omitempty"`
+}
+
+type AuditLogEntry struct {
+	ActorUserID   *int                       `json:"actor_user_id,omitempty"`
+	ActorUsername *string                    `json:"actor_username,omitempty"`
+	CreatedAt     *string                    `json:"created_at,omitempty"`
+	Details       map[string]json.RawMessage `json:"details,omitempty"`
+	Event         *string                    `json:"event,omitempty"`
+	ID            *int                       `json:"id,omitempty"`
+	IPAddress     *string                    `json:"ip_address,omitempty"`
+	TargetID      *string                    `json:"target_id,omitempty"`
+	TargetType    *string                    `json:"target_type,omitempty"`
+	UserAgent     *string                    `json:"user_agent,omitempty"`
+}
+
+type AuthProvider struct {
+	DisplayName *string `json:"display_name,omitempty"`
+	LoginURL    *string `json:"login_url,omitempty"`
+	Name        *string `json:"name,omitempty"`
+	// oidc, google, github
+	Type *string `json:"type,omitempty"`
+}
+
+type AuthProvidersResponse struct {
+	// Email/password login is enabled
+	LocalLogin *bool          `json:"local_login,omitempty"`
+	Providers  []AuthProvider `json:"providers,omitempty"`
+	// open, invite or closed
+	Registration *string `json:"registration,omitempty"`
+}
+
+type AvgRating struct {
+	Ambiance *float64 `json:"ambiance,omitempty"`
+	Count    *int     `json:"count,omitempty"`
+	Food     *float64 `json:"food,omitempty"`
+	Overall  *float64 `json:"overall,omitempty"`
+	Service  *float64 `json:"service,omitempty"`
+}
+
+type BackupInfo struct {
+	CreatedAt *string `json:"created_at,omitempty"`
+	Key       *string `json:"key,omitempty"`
+	Rows      *int    `json:"rows,omitempty"`
+	// Only for a backup just taken
+	SchemaVersion *int `json:"schema_version,omitempty"`
+	Size          *int `json:"size,omitempty"`
+	// Rows per table
+	Tables map[string]int `json:"tables,omitempty"`
+}
+
+type BackupStatus struct {
+	Key *string `json:"key,omitempty"`
+	// started
+	Status *string `json:"status,omitempty"`
+}
+
+type Badge struct {
+	// In the language of the request
+	Description *string `json:"description,omitempty"`
+	Key         *string `json:"key,omitempty"`
+	// In the language of the request
+	Name *string `json:"name,omitempty"`
+}
+
+type CSRFTokenResponse struct {
+	CSRFToken *string `json:"csrf_token,omitempty"`
+}
+
+type Category struct {
+	// Hex color, e.g. #e4572e
+	Color     *string `json:"color,omitempty"`
+	CreatedAt *string `json:"created_at,omitempty"`
+	// An emoji or a short icon name
+	Icon *string `json:"icon,omitempty"`
+	ID   *int    `json:"id,omitempty"`
+	Name *string `json:"name,omitempty"`
+	// Null for top-level categories
+	ParentID *int `json:"parent_id,omitempty"`
+	// Restaurants in the category, in category lists only
+	RestaurantCount *int    `json:"restaurant_count,omitempty"`
+	UpdatedAt       *string `json:"updated_at,omitempty"`
+}
+
+type CategoryNode struct {
+	Children []CategoryNode `json:"children,omitempty"`
+	// Hex color, e.g. #e4572e
+	Color     *string `json:"color,omitempty"`
+	CreatedAt *string `json:"created_at,omitempty"`
+	// An emoji or a short icon name
+	Icon *string `json:"icon,omitempty"`
+	ID   *int    `json:"id,omitempty"`
+	Name *string `json:"name,omitempty"`
+	// Null for top-level categories
+	ParentID *int `json:"parent_id,omitempty"`
+	// Restaurants in the category, in category lists only
+	RestaurantCount *int    `json:"restaurant_count,omitempty"`
+	UpdatedAt       *string `json:"updated_at,omitempty"`
+}
+
+type ConfirmPhotoUploadRequest struct {
+	// Overrides the caption given at presign time
+	Caption     *string `json:"caption,omitempty"`
+	UploadToken *string `json:"upload_token,omitempty"`
+}
+
+type ContributionCounts struct {
+	// Suggestions converted to restaurants
+	ConvertedSuggestions *int `json:"converted_suggestions,omitempty"`
+	Photos               *int `json:"photos,omitempty"`
+	Ratings              *int `json:"ratings,omitempty"`
+	// Including converted ones
+	Suggestions *int `json:"suggestions,omitempty"`
+}
+
+type ConvertSuggestionRequest struct {
+	AmbianceRating *int    `json:"ambiance_rating,omitempty"`
+	CategoryID     *int    `json:"category_id,omitempty"`
+	Comment        *string `json:"comment,omitempty"`
+	Description    *string `json:"description,omitempty"`
+	FoodRating     *int    `json:"food_rating,omitempty"`
+	ServiceRating  *int    `json:"service_rating,omitempty"`
+}
+
+type CreateAPIKeyRequest struct {
+	// Never expires if omitted
+	ExpiresInDays *int    `json:"expires_in_days,omitempty"`
+	Name          *string `json:"name,omitempty"`
+	// Requests per minute
+	RateLimit *int `json:"rate_limit,omitempty"`
+	// read, write, admin; defaults to read
+	Scopes []string `json:"scopes,omitempty"`
+}
+
+type CreateAPIKeyResponse struct {
+	APIKey *APIKey `json:"api_key,omitempty"`
+	// Only returned once
+	Key *string `json:"key,omitempty"`
+}
+
+type CreateCategoryRequest struct {
+	// #rrggbb; empty removes the color, omitted keeps it on update
+	Color *string `json:"color,omitempty"`
+	// Empty removes the icon; omitted keeps it on update
+	Icon *string `json:"icon,omitempty"`
+	Name *string `json:"name,omitempty"`
+	// 0 moves a category to the top level; omitted keeps the parent on update
+	ParentID *int `json:"parent_id,omitempty"`
+}
+
+type CreateFoodTypeRequest struct {
+	// #rrggbb; empty removes the color, omitted keeps it on update
+	Color *string `json:"color,omitempty"`
+	// Empty removes the icon; omitted keeps it on update
+	Icon *string `json:"icon,omitempty"`
+	Name *string `json:"name,omitempty"`
+}
+
+type CreateInviteRequest struct {
+	// Default 7, 0 for no expiry
+	ExpiresInDays *int `json:"expires_in_days,omitempty"`
+	// Default 1
+	MaxUses *int    `json:"max_uses,omitempty"`
+	Note    *string `json:"note,omitempty"`
+}
+
+type CreateInviteResponse struct {
+	Code   *string `json:"code,omitempty"`
+	Invite *Invite `json:"invite,omitempty"`
+	URL    *string `json:"url,omitempty"`
+}
+
+type CreateRatingRequest struct {
+	AmbianceRating *int    `json:"ambiance_rating,omitempty"`
+	Comment        *string `json:"comment,omitempty"`
+	FoodRating     *int    `json:"food_rating,omitempty"`
+	RestaurantID   *int    `json:"restaurant_id,omitempty"`
+	ServiceRating  *int    `json:"service_rating,omitempty"`
+}
+
+type CreateRestaurantClaimRequest struct {
+	// For the admins, e.g. the user's role at the restaurant and how to verify it
+	Message *string `json:"message,omitempty"`
+}
+
+type CreateRestaurantRequest struct {
+	Address       *string  `json:"address,omitempty"`
+	CategoryID    *int     `json:"category_id,omitempty"`
+	Description   *string  `json:"description,omitempty"`
+	FoodTypeIDs   []int    `json:"food_type_ids,omitempty"`
+	GooglePlaceID *string  `json:"google_place_id,omitempty"`
+	Latitude      *float64 `json:"latitude,omitempty"`
+	Longitude     *float64 `json:"longitude,omitempty"`
+	Name          *string  `json:"name,omitempty"`
+	Phone         *string  `json:"phone,omitempty"`
+	Website       *string  `json:"website,omitempty"`
+}
+
+type CreateServiceAccountRequest struct {
+	Description *string `json:"description,omitempty"`
+	IsAdmin     *bool   `json:"is_admin,omitempty"`
+	// Lowercase letters, digits, dashes and underscores
+	Name      *string `json:"name,omitempty"`
+	RateLimit *int    `json:"rate_limit,omitempty"`
+}
+
+type CreateSpaceInviteRequest struct {
+	// Default 7, 0 for no expiry
+	ExpiresInDays *int `json:"expires_in_days,omitempty"`
+	// Default 1
+	MaxUses *int `json:"max_uses,omitempty"`
+	// Default member
+	Role *string `json:"role,omitempty"`
+}
+
+type CreateSpaceInviteResponse struct {
+	Code   *string      `json:"code,omitempty"`
+	Invite *SpaceInvite `json:"invite,omitempty"`
+}
+
+type CreateSpaceRequest struct {
+	Name   *string `json:"name,omitempty"`
+	Public *bool   `json:"public,omitempty"`
+	// Lowercase letters, digits and dashes
+	Slug *string `json:"slug,omitempty"`
+}
+
+type CreateSuggestionRequest struct {
+	Address             *string  `json:"address,omitempty"`
+	FoodTypeIDs         []int    `json:"food_type_ids,omitempty"`
+	GooglePlaceID       *string  `json:"google_place_id,omitempty"`
+	Latitude            *float64 `json:"latitude,omitempty"`
+	Longitude           *float64 `json:"longitude,omitempty"`
+	Name                *string  `json:"name,omitempty"`
+	Notes               *string  `json:"notes,omitempty"`
+	Phone               *string  `json:"phone,omitempty"`
+	SuggestedCategoryID *int     `json:"suggested_category_id,omitempty"`
+	Website             *string  `json:"website,omitempty"`
+}
+
+type DeleteAccountRequest struct {
+	// anonymize (default) keeps contributions without the user, purge deletes them
+	Mode *string `json:"mode,omitempty"`
+	// Required for accounts with a password
+	Password *string `json:"password,omitempty"`
+}
+
+type DeleteAccountResponse struct {
+	Mode *string `json:"mode,omitempty"`
+	// When the account is permanently removed
+	ScheduledAt *string `json:"scheduled_at,omitempty"`
+}
+
+type ErrorResponse struct {
+	// Error code for programmatic handling
+	Code *string `json:"code,omitempty"`
+	// User-friendly error message
+	Detail *string `json:"detail,omitempty"`
+	// Additional details (optional)
+	Details *string `json:"details,omitempty"`
+	// Matches the X-Request-ID header and the server logs
+	RequestID *string `json:"request_id,omitempty"`
+	// HTTP status code
+	Status *int `json:"status,omitempty"`
+	// HTTP status text
+	Title *string `json:"title,omitempty"`
+	// Always about:blank; Code identifies the error
+	Type *string `json:"type,omitempty"`
+}
+
+type Event struct {
+	// Type-specific payload
+	Data map[string]any `json:"data,omitempty"`
+	// ID of the changed entity
+	ID *int `json:"id,omitempty"`
+	// Cache keys of cache.invalidated
+	Keys []string `json:"keys,omitempty"`
+	// Restaurant the entity belongs to
+	RestaurantID *int `json:"restaurant_id,omitempty"`
+	// Space of the entity, 0 for data of every space
+	SpaceID *int `json:"space_id,omitempty"`
+	// e.g. restaurant.updated, photo.ready
+	Type *string `json:"type,omitempty"`
+}
+
+type ExternalSource struct {
+	Categories []string `json:"categories,omitempty"`
+	ExternalID *string  `json:"external_id,omitempty"`
+	FetchedAt  *string  `json:"fetched_at,omitempty"`
+	// Name of the matched business
+	Name *string `json:"name,omitempty"`
+	// 1 (inexpensive) to 4 (very expensive)
+	PriceLevel *int `json:"price_level,omitempty"`
+	// yelp, foursquare
+	Provider *string `json:"provider,omitempty"`
+	// 0 to 5
+	Rating *float64 `json:"rating,omitempty"`
+	// Reviews the rating is based on
+	RatingCount *int    `json:"rating_count,omitempty"`
+	URL         *string `json:"url,omitempty"`
+}
+
+type FollowedUser struct {
+	AvatarURL  *string `json:"avatar_url,omitempty"`
+	FollowedAt *string `json:"followed_at,omitempty"`
+	ID         *int    `json:"id,omitempty"`
+	Username   *string `json:"username,omitempty"`
+}
+
+type FoodType struct {
+	// Hex color, e.g. #e4572e
+	Color     *string `json:"color,omitempty"`
+	CreatedAt *string `json:"created_at,omitempty"`
+	// An emoji or a short icon name
+	Icon *string `json:"icon,omitempty"`
+	ID   *int    `json:"id,omitempty"`
+	Name *string `json:"name,omitempty"`
+	// Restaurants serving the food type, in food type lists only
+	RestaurantCount *int    `json:"restaurant_count,omitempty"`
+	UpdatedAt       *string `json:"updated_at,omitempty"`
+}
+
+type ForgotPasswordRequest struct {
+	Email *string `json:"email,omitempty"`
+}
+
+type GoogleOpeningHours struct {
+	OpenNow *bool `json:"open_now,omitempty"`
+	// One line per day, e.g. "Monday: 11:00 AM – 10:00 PM"
+	Weekdays []string `json:"weekdays,omitempty"`
+}
+
+type GooglePlacePhoto struct {
+	// Authors that must be credited when showing the photo
+	Attributions []string `json:"attributions,omitempty"`
+	Height       *int     `json:"height,omitempty"`
+	Name         *string  `json:"name,omitempty"`
+	Width        *int     `json:"width,omitempty"`
+}
+
+type GooglePlaceResult struct {
+	Address *string `json:"address,omitempty"`
+	// Only filled in by Google place details
+	BusinessStatus *string             `json:"business_status,omitempty"`
+	Latitude       *float64            `json:"latitude,omitempty"`
+	Longitude      *float64            `json:"longitude,omitempty"`
+	Name           *string             `json:"name,omitempty"`
+	OpeningHours   *GoogleOpeningHours `json:"opening_hours,omitempty"`
+	Phone          *string             `json:"phone,omitempty"`
+	Photos         []GooglePlacePhoto  `json:"photos,omitempty"`
+	PlaceID        *string             `json:"place_id,omitempty"`
+	// 0 (free) to 4 (very expensive)
+	PriceLevel *int `json:"price_level,omitempty"`
+	// Only filled in with the Places API (New), and opening hours by legacy place details
+	Rating      *float64 `json:"rating,omitempty"`
+	RatingCount *int     `json:"rating_count,omitempty"`
+	Website     *string  `json:"website,omitempty"`
+}
+
+type HealthCheck struct {
+	Details   *string `json:"details,omitempty"`
+	Error     *string `json:"error,omitempty"`
+	LatencyMs *int    `json:"latency_ms,omitempty"`
+	// ok or error
+	Status *string `json:"status,omitempty"`
+}
+
+type HealthResponse struct {
+	Checks map[string]HealthCheck `json:"checks,omitempty"`
+	// ok, unavailable or shutting_down
+	Status *string `json:"status,omitempty"`
+}
+
+type Invite struct {
+	CodePrefix      *string `json:"code_prefix,omitempty"`
+	CreatedAt       *string `json:"created_at,omitempty"`
+	CreatedByUserID *int    `json:"created_by_user_id,omitempty"`
+	ExpiresAt       *string `json:"expires_at,omitempty"`
+	ID              *int    `json:"id,omitempty"`
+	MaxUses         *int    `json:"max_uses,omitempty"`
+	Note            *string `json:"note,omitempty"`
+	// Usernames of the users who registered with the invite
+	RedeemedBy []string `json:"redeemed_by,omitempty"`
+	RevokedAt  *string  `json:"revoked_at,omitempty"`
+	UseCount   *int     `json:"use_count,omitempty"`
+}
+
+type JoinSpaceRequest struct {
+	Code *string `json:"code,omitempty"`
+}
+
+type Leaderboard struct {
+	// Suggestions converted to restaurants
+	ConvertedSuggestions []LeaderboardEntry `json:"converted_suggestions,omitempty"`
+	// month or all
+	Period  *string            `json:"period,omitempty"`
+	Photos  []LeaderboardEntry `json:"photos,omitempty"`
+	Ratings []LeaderboardEntry `json:"ratings,omitempty"`
+	// Start of the period, nil for all time
+	Since *string `json:"since,omitempty"`
+}
+
+type LeaderboardEntry struct {
+	AvatarURL *string `json:"avatar_url,omitempty"`
+	Count     *int    `json:"count,omitempty"`
+	Rank      *int    `json:"rank,omitempty"`
+	UserID    *int    `json:"user_id,omitempty"`
+	Username  *string `json:"username,omitempty"`
+}
+
+type LogLevels struct {
+	// Modules whose level can be set
+	AvailableModules []string `json:"available_modules,omitempty"`
+	// debug, info, warn or error
+	Level *string `json:"level,omitempty"`
+	// e.g. {"photos": "debug"}
+	Modules map[string]string `json:"modules,omitempty"`
+}
+
+type LogLevelsRequest struct {
+	Level   *string           `json:"level,omitempty"`
+	Modules map[string]string `json:"modules,omitempty"`
+}
+
+type LoginRequest struct {
+	Email    *string `json:"email,omitempty"`
+	Password *string `json:"password,omitempty"`
+	// Keep the session for JWT_REMEMBER_ME_TTL instead of JWT_REFRESH_TOKEN_TTL
+	RememberMe *bool `json:"remember_me,omitempty"`
+}
+
+type LoginResponse struct {
+	AccessToken *string `json:"access_token,omitempty"`
+	// seconds
+	ExpiresIn *int `json:"expires_in,omitempty"`
+	// Seconds until the refresh token expires; omitted on refresh, which keeps the session's expiry
+	RefreshExpiresIn *int `json:"refresh_expires_in,omitempty"`
+	// Omitted when refreshed from the cookie
+	RefreshToken *string `json:"refresh_token,omitempty"`
+	TokenType    *string `json:"token_type,omitempty"`
+	User         *User   `json:"user,omitempty"`
+}
+
+type MapBoundsResponse struct {
+	Clustered *bool        `json:"clustered,omitempty"`
+	Clusters  []MapCluster `json:"clusters,omitempty"`
+	Markers   []MapMarker  `json:"markers,omitempty"`
+	Total     *int         `json:"total,omitempty"`
+}
+
+type MapCluster struct {
+	Count *int `json:"count,omitempty"`
+	// Centroid of the restaurants
+	Latitude *float64 `json:"latitude,omitempty"`
+	// Centroid of the restaurants
+	Longitude *float64 `json:"longitude,omitempty"`
+	// Set when the cell holds a single restaurant
+	RestaurantID *int `json:"restaurant_id,omitempty"`
+}
+
+type MapDirections struct {
+	Apple  *string `json:"apple,omitempty"`
+	Google *string `json:"google,omitempty"`
+}
+
+type MapMarker struct {
+	CategoryID *int     `json:"category_id,omitempty"`
+	ID         *int     `json:"id,omitempty"`
+	Latitude   *float64 `json:"latitude,omitempty"`
+	Longitude  *float64 `json:"longitude,omitempty"`
+	Name       *string  `json:"name,omitempty"`
+	// Overall average, omitted when unrated
+	Rating *float64 `json:"rating,omitempty"`
+}
+
+type MapsQuota struct {
+	// Calls per UTC day, 0 for no limit
+	Budget *int `json:"budget,omitempty"`
+	// Newest first
+	Days []MapsUsageDay `json:"days,omitempty"`
+	// Missing without a budget
+	Remaining *int    `json:"remaining,omitempty"`
+	ResetsAt  *string `json:"resets_at,omitempty"`
+	// Calls today
+	Used *int `json:"used,omitempty"`
+}
+
+type MapsUsageDay struct {
+	Calls *int `json:"calls,omitempty"`
+	// YYYY-MM-DD
+	Date *string `json:"date,omitempty"`
+	// Calls by operation, e.g. text_search
+	Operations map[string]int `json:"operations,omitempty"`
+}
+
+type MenuPhoto struct {
+	Caption   *string `json:"caption,omitempty"`
+	CreatedAt *string `json:"created_at,omitempty"`
+	FileSize  *int    `json:"file_size,omitempty"`
+	Filename  *string `json:"filename,omitempty"`
+	ID        *int    `json:"id,omitempty"`
+	IsCover   *bool   `json:"is_cover,omitempty"`
+	MimeType  *string `json:"mime_type,omitempty"`
+	// Reasons given by the moderation provider
+	ModerationLabels []string `json:"moderation_labels,omitempty"`
+	// pending, flagged, approved
+	ModerationStatus *string `json:"moderation_status,omitempty"`
+	OriginalFilename *string `json:"original_filename,omitempty"`
+	// menu, food, interior, receipt
+	PhotoType *string `json:"photo_type,omitempty"`
+	Position  *int    `json:"position,omitempty"`
+	// Set when status is failed
+	ProcessingError *string `json:"processing_error,omitempty"`
+	RestaurantID    *int    `json:"restaurant_id,omitempty"`
+	// processing, ready, failed
+	Status           *string `json:"status,omitempty"`
+	UpdatedAt        *string `json:"updated_at,omitempty"`
+	UploadedByUserID *int    `json:"uploaded_by_user_id,omitempty"`
+	// Computed field
+	URL *string `json:"url,omitempty"`
+}
+
+type MergeFoodTypesRequest struct {
+	DuplicateIDs []int `json:"duplicate_ids,omitempty"`
+}
+
+type Meta struct {
+	// Badges users can be awarded
+	Badges           []Badge           `json:"badges,omitempty"`
+	Categories       []Category        `json:"categories,omitempty"`
+	FoodTypes        []FoodType        `json:"food_types,omitempty"`
+	RatingDimensions []RatingDimension `json:"rating_dimensions,omitempty"`
+}
+
+type MetricsHistoryResponse struct {
+	// When the counters last started from zero
+	CountingSince *string           `json:"counting_since,omitempty"`
+	Snapshots     []MetricsSnapshot `json:"snapshots,omitempty"`
+	Window        *string           `json:"window,omitempty"`
+}
+
+type MetricsSnapshot struct {
+	AvgResponseTimeMs *float64 `json:"avg_response_time_ms,omitempty"`
+	Errors            *int     `json:"errors,omitempty"`
+	// Set in cluster mode
+	Instance          *string          `json:"instance,omitempty"`
+	P50ResponseTimeMs *float64         `json:"p50_response_time_ms,omitempty"`
+	P95ResponseTimeMs *float64         `json:"p95_response_time_ms,omitempty"`
+	P99ResponseTimeMs *float64         `json:"p99_response_time_ms,omitempty"`
+	RecordedAt        *string          `json:"recorded_at,omitempty"`
+	Requests          *int             `json:"requests,omitempty"`
+	RequestsByStatus  map[string]int64 `json:"requests_by_status,omitempty"`
+	TotalErrors       *int             `json:"total_errors,omitempty"`
+	TotalRequests     *int             `json:"total_requests,omitempty"`
+}
+
+type ModeratePhotoRequest struct {
+	// approve, reject
+	Action *string `json:"action,omitempty"`
+}
+
+type OwnerResponse struct {
+	RespondedAt *string `json:"responded_at,omitempty"`
+	Response    *string `json:"response,omitempty"`
+}
+
+type OwnerResponseRequest struct {
+	Response *string `json:"response,omitempty"`
+}
+
+type PaginatedResponse struct {
+	Data       json.RawMessage `json:"data,omitempty"`
+	HasMore    *bool           `json:"has_more,omitempty"`
+	NextCursor *string         `json:"next_cursor,omitempty"`
+	Total      *int            `json:"total,omitempty"`
+}
+
+type PhotoCleanupReport struct {
+	DeletedObjects *int `json:"deleted_objects,omitempty"`
+	// Only with remove_missing
+	DeletedPhotos *int  `json:"deleted_photos,omitempty"`
+	DryRun        *bool `json:"dry_run,omitempty"`
+	// Expired pending direct uploads removed
+	ExpiredUploads *int    `json:"expired_uploads,omitempty"`
+	FinishedAt     *string `json:"finished_at,omitempty"`
+	// IDs of photos whose original file is missing
+	MissingFiles   []int `json:"missing_files,omitempty"`
+	ObjectsScanned *int  `json:"objects_scanned,omitempty"`
+	// Stored files with no matching photo
+	OrphanedObjects []string `json:"orphaned_objects,omitempty"`
+	StartedAt       *string  `json:"started_at,omitempty"`
+}
+
+type PhotoEvent struct {
+	Error        *string `json:"error,omitempty"`
+	PhotoID      *int    `json:"photo_id,omitempty"`
+	RestaurantID *int    `json:"restaurant_id,omitempty"`
+	Status       *string `json:"status,omitempty"`
+	// photo.ready, photo.failed
+	Type *string `json:"type,omitempty"`
+}
+
+type PlaceChange struct {
+	CreatedAt *string `json:"created_at,omitempty"`
+	// phone, website, business_status or opening_hours
+	Field    *string        `json:"field,omitempty"`
+	ID       *int           `json:"id,omitempty"`
+	NewValue map[string]any `json:"new_value,omitempty"`
+	// String, or array of strings for opening_hours; null when unset
+	OldValue         map[string]any `json:"old_value,omitempty"`
+	RestaurantID     *int           `json:"restaurant_id,omitempty"`
+	RestaurantName   *string        `json:"restaurant_name,omitempty"`
+	ReviewedAt       *string        `json:"reviewed_at,omitempty"`
+	ReviewedByUserID *int           `json:"reviewed_by_user_id,omitempty"`
+	// pending, accepted or rejected
+	Status *string `json:"status,omitempty"`
+}
+
+type PlaceSyncReport struct {
+	// Pending changes recorded
+	Changes *int `json:"changes,omitempty"`
+	// Restaurants whose place details were fetched
+	Checked *int `json:"checked,omitempty"`
+	// Restaurants whose place details could not be fetched
+	Failed     *int    `json:"failed,omitempty"`
+	FinishedAt *string `json:"finished_at,omitempty"`
+	StartedAt  *string `json:"started_at,omitempty"`
+}
+
+type PresignPhotoUploadRequest struct {
+	Caption     *string `json:"caption,omitempty"`
+	ContentType *string `json:"content_type,omitempty"`
+	FileSize    *int    `json:"file_size,omitempty"`
+	Filename    *string `json:"filename,omitempty"`
+	// Defaults to menu
+	PhotoType *string `json:"photo_type,omitempty"`
+}
+
+type PresignPhotoUploadResponse struct {
+	ExpiresAt *string `json:"expires_at,omitempty"`
+	// Headers the client must send with the PUT
+	Headers     map[string]string `json:"headers,omitempty"`
+	Method      *string           `json:"method,omitempty"`
+	UploadToken *string           `json:"upload_token,omitempty"`
+	UploadURL   *string           `json:"upload_url,omitempty"`
+}
+
+type Rating struct {
+	AmbianceRating *int    `json:"ambiance_rating,omitempty"`
+	Comment        *string `json:"comment,omitempty"`
+	CreatedAt      *string `json:"created_at,omitempty"`
+	FoodRating     *int    `json:"food_rating,omitempty"`
+	ID             *int    `json:"id,omitempty"`
+	// Official response of the restaurant's owner, nil if none
+	OwnerResponse *OwnerResponse `json:"owner_response,omitempty"`
+	RestaurantID  *int           `json:"restaurant_id,omitempty"`
+	ServiceRating *int           `json:"service_rating,omitempty"`
+	// Author, nil for ratings created before authentication
+	UserID *int `json:"user_id,omitempty"`
+}
+
+type RatingDimension struct {
+	// The rating field is <key>_rating
+	Key *string `json:"key,omitempty"`
+	Max *int    `json:"max,omitempty"`
+	Min *int    `json:"min,omitempty"`
+	// In the language of the request
+	Name *string `json:"name,omitempty"`
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken *string `json:"refresh_token,omitempty"`
+}
+
+type RegisterRequest struct {
+	Email    *string `json:"email,omitempty"`
+	FullName *string `json:"full_name,omitempty"`
+	// Required when REGISTRATION_MODE=invite
+	InviteCode *string `json:"invite_code,omitempty"`
+	Password   *string `json:"password,omitempty"`
+	Username   *string `json:"username,omitempty"`
+}
+
+type ReorderPhotosRequest struct {
+	// Every photo of the restaurant, in display order
+	PhotoIDs []int `json:"photo_ids,omitempty"`
+}
+
+type ResetPasswordRequest struct {
+	Password *string `json:"password,omitempty"`
+	Token    *string `json:"token,omitempty"`
+}
+
+type Restaurant struct {
+	Address   *string    `json:"address,omitempty"`
+	AvgRating *AvgRating `json:"avg_rating,omitempty"`
+	// From the place sync, e.g. CLOSED_PERMANENTLY
+	BusinessStatus *string    `json:"business_status,omitempty"`
+	Category       *Category  `json:"category,omitempty"`
+	CategoryID     *int       `json:"category_id,omitempty"`
+	CoverPhoto     *MenuPhoto `json:"cover_photo,omitempty"`
+	CreatedAt      *string    `json:"created_at,omitempty"`
+	Description    *string    `json:"description,omitempty"`
+	// Distance in km from search location
+	Distance *float64 `json:"distance,omitempty"`
+	// Yelp or Foursquare data, on the restaurant detail
+	ExternalSources []ExternalSource `json:"external_sources,omitempty"`
+	FoodTypes       []FoodType       `json:"food_types,omitempty"`
+	GooglePlaceID   *string          `json:"google_place_id,omitempty"`
+	ID              *int             `json:"id,omitempty"`
+	// Indicates if this is from suggestions table
+	IsSuggestion *bool    `json:"is_suggestion,omitempty"`
+	Latitude     *float64 `json:"latitude,omitempty"`
+	Longitude    *float64 `json:"longitude,omitempty"`
+	Name         *string  `json:"name,omitempty"`
+	// From the place sync, one line per day
+	OpeningHours []string `json:"opening_hours,omitempty"`
+	Phone        *string  `json:"phone,omitempty"`
+	// For suggestions: pending, approved, tested, rejected
+	Status       *string `json:"status,omitempty"`
+	SuggestionID *int    `json:"suggestion_id,omitempty"`
+	// Travel time from the search location, when requested
+	TravelMinutes *float64 `json:"travel_minutes,omitempty"`
+	UpdatedAt     *string  `json:"updated_at,omitempty"`
+	Website       *string  `json:"website,omitempty"`
+}
+
+type RestaurantClaim struct {
+	CreatedAt        *string `json:"created_at,omitempty"`
+	ID               *int    `json:"id,omitempty"`
+	Message          *string `json:"message,omitempty"`
+	RestaurantID     *int    `json:"restaurant_id,omitempty"`
+	RestaurantName   *string `json:"restaurant_name,omitempty"`
+	ReviewedAt       *string `json:"reviewed_at,omitempty"`
+	ReviewedByUserID *int    `json:"reviewed_by_user_id,omitempty"`
+	// pending, approved or rejected
+	Status   *string `json:"status,omitempty"`
+	UserID   *int    `json:"user_id,omitempty"`
+	Username *string `json:"username,omitempty"`
+}
+
+type RestaurantMap struct {
+	Directions *MapDirections `json:"directions,omitempty"`
+	// Static map served by the API; missing when Google Maps is not configured
+	ImageURL *string `json:"image_url,omitempty"`
+}
+
+type RestaurantSuggestion struct {
+	Address             *string    `json:"address,omitempty"`
+	Category            *Category  `json:"category,omitempty"`
+	CreatedAt           *string    `json:"created_at,omitempty"`
+	FoodTypes           []FoodType `json:"food_types,omitempty"`
+	GooglePlaceID       *string    `json:"google_place_id,omitempty"`
+	ID                  *int       `json:"id,omitempty"`
+	Latitude            *float64   `json:"latitude,omitempty"`
+	Longitude           *float64   `json:"longitude,omitempty"`
+	Name                *string    `json:"name,omitempty"`
+	Notes               *string    `json:"notes,omitempty"`
+	Phone               *string    `json:"phone,omitempty"`
+	Status              *string    `json:"status,omitempty"`
+	SuggestedCategoryID *int       `json:"suggested_category_id,omitempty"`
+	UpdatedAt           *string    `json:"updated_at,omitempty"`
+	// Author, nil for anonymous suggestions
+	UserID  *int    `json:"user_id,omitempty"`
+	Website *string `json:"website,omitempty"`
+}
+
+type ReviewPlaceChangeRequest struct {
+	// accept, reject
+	Action *string `json:"action,omitempty"`
+}
+
+type ReviewRestaurantClaimRequest struct {
+	// approve, reject
+	Action *string `json:"action,omitempty"`
+}
+
+type ServiceAccount struct {
+	APIKeyCount *int    `json:"api_key_count,omitempty"`
+	CreatedAt   *string `json:"created_at,omitempty"`
+	Description *string `json:"description,omitempty"`
+	ID          *int    `json:"id,omitempty"`
+	IsActive    *bool   `json:"is_active,omitempty"`
+	IsAdmin     *bool   `json:"is_admin,omitempty"`
+	// Last use of any of its API keys
+	LastUsedAt *string `json:"last_used_at,omitempty"`
+	Name       *string `json:"name,omitempty"`
+	// Requests per minute across all keys, nil for the server default
+	RateLimit *int `json:"rate_limit,omitempty"`
+}
+
+type Setting struct {
+	// From the startup configuration
+	Default     json.RawMessage `json:"default,omitempty"`
+	Description *string         `json:"description,omitempty"`
+	Key         *string         `json:"key,omitempty"`
+	Max         *int            `json:"max,omitempty"`
+	// Bounds of int settings
+	Min *int `json:"min,omitempty"`
+	// Allowed values of string settings
+	Options    []string `json:"options,omitempty"`
+	Overridden *bool    `json:"overridden,omitempty"`
+	// int, bool or string
+	Type      *string `json:"type,omitempty"`
+	UpdatedAt *string `json:"updated_at,omitempty"`
+	// Set while overridden
+	UpdatedByUserID *int `json:"updated_by_user_id,omitempty"`
+	// Effective value
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+type SettingRequest struct {
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+type Space struct {
+	CreatedAt       *string `json:"created_at,omitempty"`
+	CreatedByUserID *int    `json:"created_by_user_id,omitempty"`
+	ID              *int    `json:"id,omitempty"`
+	Name            *string `json:"name,omitempty"`
+	// Readable by everyone, not only members
+	Public *bool `json:"public,omitempty"`
+	// Of the current user, nil if not a member
+	Role      *string `json:"role,omitempty"`
+	Slug      *string `json:"slug,omitempty"`
+	UpdatedAt *string `json:"updated_at,omitempty"`
+}
+
+type SpaceInvite struct {
+	CodePrefix      *string `json:"code_prefix,omitempty"`
+	CreatedAt       *string `json:"created_at,omitempty"`
+	CreatedByUserID *int    `json:"created_by_user_id,omitempty"`
+	ExpiresAt       *string `json:"expires_at,omitempty"`
+	ID              *int    `json:"id,omitempty"`
+	MaxUses         *int    `json:"max_uses,omitempty"`
+	RevokedAt       *string `json:"revoked_at,omitempty"`
+	// admin or member
+	Role     *string `json:"role,omitempty"`
+	SpaceID  *int    `json:"space_id,omitempty"`
+	UseCount *int    `json:"use_count,omitempty"`
+}
+
+type SpaceMember struct {
+	CreatedAt *string `json:"created_at,omitempty"`
+	// owner, admin or member
+	Role     *string `json:"role,omitempty"`
+	UserID   *int    `json:"user_id,omitempty"`
+	Username *string `json:"username,omitempty"`
+}
+
+type SpaceMemberRequest struct {
+	Role *string `json:"role,omitempty"`
+}
+
+type SuggestionApproval struct {
+	CreatedAt    *string `json:"created_at,omitempty"`
+	ID           *int    `json:"id,omitempty"`
+	Note         *string `json:"note,omitempty"`
+	SuggestionID *int    `json:"suggestion_id,omitempty"`
+	UserID       *int    `json:"user_id,omitempty"`
+	Username     *string `json:"username,omitempty"`
+}
+
+type SuggestionApprovalStatus struct {
+	Approvals         []SuggestionApproval `json:"approvals,omitempty"`
+	CanConvert        *bool                `json:"can_convert,omitempty"`
+	Mode              *string              `json:"mode,omitempty"`
+	RequiredApprovals *int                 `json:"required_approvals,omitempty"`
+	SuggestionID      *int                 `json:"suggestion_id,omitempty"`
+}
+
+type SuggestionEvent struct {
+	CreatedAt    *string `json:"created_at,omitempty"`
+	FromStatus   *string `json:"from_status,omitempty"`
+	ID           *int    `json:"id,omitempty"`
+	Note         *string `json:"note,omitempty"`
+	SuggestionID *int    `json:"suggestion_id,omitempty"`
+	ToStatus     *string `json:"to_status,omitempty"`
+	UserID       *int    `json:"user_id,omitempty"`
+	Username     *string `json:"username,omitempty"`
+}
+
+type Translation struct {
+	Locale    *string `json:"locale,omitempty"`
+	Name      *string `json:"name,omitempty"`
+	UpdatedAt *string `json:"updated_at,omitempty"`
+}
+
+type TranslationRequest struct {
+	Name *string `json:"name,omitempty"`
+}
+
+type UpdateRestaurantBusinessRequest struct {
+	// One line per day; an empty list clears them
+	OpeningHours []string `json:"opening_hours,omitempty"`
+	Phone        *string  `json:"phone,omitempty"`
+	Website      *string  `json:"website,omitempty"`
+}
+
+type UpdateRestaurantRequest struct {
+	Address       *string  `json:"address,omitempty"`
+	CategoryID    *int     `json:"category_id,omitempty"`
+	Description   *string  `json:"description,omitempty"`
+	FoodTypeIDs   []int    `json:"food_type_ids,omitempty"`
+	GooglePlaceID *string  `json:"google_place_id,omitempty"`
+	Latitude      *float64 `json:"latitude,omitempty"`
+	Longitude     *float64 `json:"longitude,omitempty"`
+	Name          *string  `json:"name,omitempty"`
+	Phone         *string  `json:"phone,omitempty"`
+	Website       *string  `json:"website,omitempty"`
+}
+
+type UpdateSpaceRequest struct {
+	Name   *string `json:"name,omitempty"`
+	Public *bool   `json:"public,omitempty"`
+}
+
+type UpdateSuggestionStatusRequest struct {
+	Note   *string `json:"note,omitempty"`
+	Status *string `json:"status,omitempty"`
+}
+
+type UploadPhotoFromURLRequest struct {
+	Caption *string `json:"caption,omitempty"`
+	// Defaults to menu
+	PhotoType *string `json:"photo_type,omitempty"`
+	URL       *string `json:"url,omitempty"`
+}
+
+type UploadPhotoResponse struct {
+	Photo *MenuPhoto `json:"photo,omitempty"`
+}
+
+type User struct {
+	AvatarURL     *string `json:"avatar_url,omitempty"`
+	CreatedAt     *string `json:"created_at,omitempty"`
+	Email         *string `json:"email,omitempty"`
+	EmailVerified *bool   `json:"email_verified,omitempty"`
+	FullName      *string `json:"full_name,omitempty"`
+	ID            *int    `json:"id,omitempty"`
+	IsActive      *bool   `json:"is_active,omitempty"`
+	IsAdmin       *bool   `json:"is_admin,omitempty"`
+	LastLoginAt   *string `json:"last_login_at,omitempty"`
+	Provider      *string `json:"provider,omitempty"`
+	ProviderID    *string `json:"provider_id,omitempty"`
+	UpdatedAt     *string `json:"updated_at,omitempty"`
+	Username      *string `json:"username,omitempty"`
+}
+
+type UserBadge struct {
+	AwardedAt *string `json:"awarded_at,omitempty"`
+	// In the language of the request
+	Description *string `json:"description,omitempty"`
+	Key         *string `json:"key,omitempty"`
+	// In the language of the request
+	Name *string `json:"name,omitempty"`
+}
+
+type UserStats struct {
+	AvatarURL *string `json:"avatar_url,omitempty"`
+	// Awarded for contributions in any space
+	Badges      []UserBadge `json:"badges,omitempty"`
+	MemberSince *string     `json:"member_since,omitempty"`
+	// Since the start of the month (UTC)
+	ThisMonth *ContributionCounts `json:"this_month,omitempty"`
+	Total     *ContributionCounts `json:"total,omitempty"`
+	UserID    *int                `json:"user_id,omitempty"`
+	Username  *string             `json:"username,omitempty"`
+}
+
+type Webhook struct {
+	Active          *bool   `json:"active,omitempty"`
+	CreatedAt       *string `json:"created_at,omitempty"`
+	CreatedByUserID *int    `json:"created_by_user_id,omitempty"`
+	Description     *string `json:"description,omitempty"`
+	// restaurant.created, rating.created, suggestion.converted, photo.uploaded
+	Events []string `json:"events,omitempty"`
+	ID     *int     `json:"id,omitempty"`
+	// Only returned when the webhook is created
+	Secret    *string `json:"secret,omitempty"`
+	UpdatedAt *string `json:"updated_at,omitempty"`
+	URL       *string `json:"url,omitempty"`
+}
+
+type WebhookDelivery struct {
+	Attempts    *int    `json:"attempts,omitempty"`
+	CreatedAt   *string `json:"created_at,omitempty"`
+	DeliveredAt *string `json:"delivered_at,omitempty"`
+	Event       *string `json:"event,omitempty"`
+	ID          *int    `json:"id,omitempty"`
+	LastError   *string `json:"last_error,omitempty"`
+	// Response status of the last attempt
+	LastStatusCode *int `json:"last_status_code,omitempty"`
+	// Only while pending
+	NextAttemptAt *string `json:"next_attempt_at,omitempty"`
+	// Request body
+	Payload map[string]any `json:"payload,omitempty"`
+	// pending, delivered or failed
+	Status    *string `json:"status,omitempty"`
+	WebhookID *int    `json:"webhook_id,omitempty"`
+}
+
+type WebhookRequest struct {
+	// Defaults to true
+	Active      *bool    `json:"active,omitempty"`
+	Description *string  `json:"description,omitempty"`
+	Events      []string `json:"events,omitempty"`
+	// Generated when empty on creation, kept when empty on update
+	Secret *string `json:"secret,omitempty"`
+	URL    *string `json:"url,omitempty"`
+}
+
+// GetAuditLogParams are the query and header parameters of GetAuditLog
+type GetAuditLogParams struct {
+	// Comma-separated events, e.g. login_failed,role_granted
+	Event *string
+	// Acting user ID
+	UserID *int
+	// Target type, e.g. user
+	TargetType *string
+	// Target ID
+	TargetID *string
+	// Client IP address
+	IP *string
+	// Only events at or after this time (RFC 3339)
+	From *string
+	// Only events before this time (RFC 3339)
+	To *string
+	// Number of entries per page (default: 20, max: 100)
+	Limit *int
+	// Pagination cursor from X-Next-Cursor
+	Cursor *string
+}
+
+// GetAuditLog calls GET /admin/audit-log: Get the audit log
+func (c *Client) GetAuditLog(ctx context.Context, params *GetAuditLogParams) ([]AuditLogEntry, error) {
+	query, header := url.Values{}, http.Header{}
+	if params != nil {
+		if params.Event != nil {
+			query.Set("event", fmt.Sprint(*params.Event))
+		}
+		if params.UserID != nil {
+			query.Set("user_id", fmt.Sprint(*params.UserID))
+		}
+		if params.TargetType != nil {
+			query.Set("target_type", fmt.Sprint(*params.TargetType))
+		}
+		if params.TargetID != nil {
+			query.Set("target_id", fmt.Sprint(*params.TargetID))
+		}
+		if params.IP != nil {
+			query.Set("ip", fmt.Sprint(*params.IP))
+		}
+		if params.From != nil {
+			query.Set("from", fmt.Sprint(*params.From))
+		}
+		if params.To != nil {
+			query.Set("to", fmt.Sprint(*params.To))
+		}
+		if params.Limit != nil {
+			query.Set("limit", fmt.Sprint(*params.Limit))
+		}
+		if params.Cursor != nil {
+			query.Set("cursor", fmt.Sprint(*params.Cursor))
+		}
+	}
+	resp, err := c.do(ctx, "GET", "/admin/audit-log", query, header, nil)
+	if err != nil {
+		return nil, err
+	}
+	var result []AuditLogEntry
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// CreateBackup calls POST /admin/backup: Back up the database
+func (c *Client) CreateBackup(ctx context.Context) (*BackupStatus, error) {
+	resp, err := c.do(ctx, "POST", "/admin/backup", nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var result BackupStatus
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ListBackups calls GET /admin/backups: List database backups
+func (c *Client) ListBackups(ctx context.Context) ([]BackupInfo, error) {
+	resp, err := c.do(ctx, "GET", "/admin/backups", nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var result []BackupInfo
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ListInvites calls GET /admin/invites: List invites
+func (c *Client) ListInvites(ctx context.Context) ([]Invite, error) {
+	resp, err := c.do(ctx, "GET", "/admin/invites", nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var result []Invite
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// CreateInvite calls POST /admin/invites: Create an invite
+func (c *Client) CreateInvite(ctx context.Context, body CreateInviteRequest) (*CreateInviteResponse, error) {
+	resp, err := c.do(ctx, "POST", "/admin/invites", nil, nil, jsonBody(body))
+	if err != nil {
+		return nil, err
+	}
+	var result CreateInviteResponse
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// RevokeInvite calls DELETE /admin/invites/{id}: Revoke an invite
+func (c *Client) RevokeInvite(ctx context.Context, id int) error {
+	resp, err := c.do(ctx, "DELETE", "/admin/invites/"+url.PathEscape(fmt.Sprint(id)), nil, nil, nil)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// GetLogLevels calls GET /admin/log-level: Get log levels
+func (c *Client) GetLogLevels(ctx context.Context) (*LogLevels, error) {
+	resp, err := c.do(ctx, "GET", "/admin/log-level", nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var result LogLevels
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// UpdateLogLevels calls PUT /admin/log-level: Set log levels
+func (c *Client) UpdateLogLevels(ctx context.Context, body LogLevelsRequest) (*LogLevels, error) {
+	resp, err := c.do(ctx, "PUT", "/admin/log-level", nil, nil, jsonBody(body))
+	if err != nil {
+		return nil, err
+	}
+	var result LogLevels
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// CleanupPhotoStorageParams are the query and header parameters of CleanupPhotoStorage
+type CleanupPhotoStorageParams struct {
+	// Only report, do not delete anything
+	DryRun *bool
+	// Also delete photos whose original file is missing
+	RemoveMissing *bool
+}
+
+// CleanupPhotoStorage calls POST /admin/photos/cleanup: Reconcile photo storage
+func (c *Client) CleanupPhotoStorage(ctx context.Context, params *CleanupPhotoStorageParams) (*PhotoCleanupReport, error) {
+	query, header := url.Values{}, http.Header{}
+	if params != nil {
+		if params.DryRun != nil {
+			query.Set("dry_run", fmt.Sprint(*params.DryRun))
+		}
+		if params.RemoveMissing != nil {
+			query.Set("remove_missing", fmt.Sprint(*params.RemoveMissing))
+		}
+	}
+	resp, err := c.do(ctx, "POST", "/admin/photos/cleanup", query, header, nil)
+	if err != nil {
+		return nil, err
+	}
+	var result PhotoCleanupReport
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetPhotoModerationQueueParams are the query and header parameters of GetPhotoModerationQueue
+type GetPhotoModerationQueueParams struct {
+	// Filter by moderation status (pending, flagged)
+	Status *string
+}
+
+// GetPhotoModerationQueue calls GET /admin/photos/moderation: List the photo moderation queue
+func (c *Client) GetPhotoModerationQueue(ctx context.Context, params *GetPhotoModerationQueueParams) ([]MenuPhoto, error) {
+	query, header := url.Values{}, http.Header{}
+	if params != nil {
+		if params.Status != nil {
+			query.Set("status", fmt.Sprint(*params.Status))
+		}
+	}
+	resp, err := c.do(ctx, "GET", "/admin/photos/moderation", query, header, nil)
+	if err != nil {
+		return nil, err
+	}
+	var result []MenuPhoto
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ModeratePhoto calls POST /admin/photos/{id}/moderation: Moderate a photo
+func (c *Client) ModeratePhoto(ctx context.Context, id int, body ModeratePhotoRequest) (*MenuPhoto, error) {
+	resp, err := c.do(ctx, "POST", "/admin/photos/"+url.PathEscape(fmt.Sprint(id))+"/moderation", nil, nil, jsonBody(body))
+	if err != nil {
+		return nil, err
+	}
+	var result MenuPhoto
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetPlaceChangesParams are the query and header parameters of GetPlaceChanges
+type GetPlaceChangesParams struct {
+	// Filter by status (pending, accepted, rejected)
+	Status *string
+}
+
+// GetPlaceChanges calls GET /admin/place-changes: List place changes
+func (c *Client) GetPlaceChanges(ctx context.Context, params *GetPlaceChangesParams) ([]PlaceChange, error) {
+	query, header := url.Values{}, http.Header{}
+	if params != nil {
+		if params.Status != nil {
+			query.Set("status", fmt.Sprint(*params.Status))
+		}
+	}
+	resp, err := c.do(ctx, "GET", "/admin/place-changes", query, header, nil)
+	if err != nil {
+		return nil, err
+	}
+	var result []PlaceChange
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ReviewPlaceChange calls POST /admin/place-changes/{id}: Review a place change
+func (c *Client) ReviewPlaceChange(ctx context.Context, id int, body ReviewPlaceChangeRequest) (*PlaceChange, error) {
+	resp, err := c.do(ctx, "POST", "/admin/place-changes/"+url.PathEscape(fmt.Sprint(id)), nil, nil, jsonBody(body))
+	if err != nil {
+		return nil, err
+	}
+	var result PlaceChange
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// SyncPlacesParams are the query and header parameters of SyncPlaces
+type SyncPlacesParams struct {
+	// Restaurants to sync (default 50, max 1000)
+	Limit *int
+}
+
+// SyncPlaces calls POST /admin/places/sync: Sync restaurants with their places
+func (c *Client) SyncPlaces(ctx context.Context, params *SyncPlacesParams) (*PlaceSyncReport, error) {
+	query, header := url.Values{}, http.Header{}
+	if params != nil {
+		if params.Limit != nil {
+			query.Set("limit", fmt.Sprint(*params.Limit))
+		}
+	}
+	resp, err := c.do(ctx, "POST", "/admin/places/sync", query, header, nil)
+	if err != nil {
+		return nil, err
+	}
+	var result PlaceSyncReport
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetMapsQuotaParams are the query and header parameters of GetMapsQuota
+type GetMapsQuotaParams struct {
+	// Days of history, today included (default 30, max 366)
+	Days *int
+}
+
+// GetMapsQuota calls GET /admin/quota: Get Google Maps usage
+func (c *Client) GetMapsQuota(ctx context.Context, params *GetMapsQuotaParams) (*MapsQuota, error) {
+	query, header := url.Values{}, http.Header{}
+	if params != nil {
+		if params.Days != nil {
+			query.Set("days", fmt.Sprint(*params.Days))
+		}
+	}
+	resp, err := c.do(ctx, "GET", "/admin/quota", query, header, nil)
+	if err != nil {
+		return nil, err
+	}
+	var result MapsQuota
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetRestaurantClaimsParams are the query and header parameters of GetRestaurantClaims
+type GetRestaurantClaimsParams struct {
+	// Filter by status (pending, approved, rejected)
+	Status *string
+}
+
+// GetRestaurantClaims calls GET /admin/restaurant-claims: List restaurant claims
+func (c *Client) GetRestaurantClaims(ctx context.Context, params *GetRestaurantClaimsParams) ([]RestaurantClaim, error) {
+	query, header := url.Values{}, http.Header{}
+	if params != nil {
+		if params.Status != nil {
+			query.Set("status", fmt.Sprint(*params.Status))
+		}
+	}
+	resp, err := c.do(ctx, "GET", "/admin/restaurant-claims", query, header, nil)
+	if err != nil {
+		return nil, err
+	}
+	var result []RestaurantClaim
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ReviewRestaurantClaim calls POST /admin/restaurant-claims/{id}: Review a restaurant claim
+func (c *Client) ReviewRestaurantClaim(ctx context.Context, id int, body ReviewRestaurantClaimRequest) (*RestaurantClaim, error) {
+	resp, err := c.do(ctx, "POST", "/admin/restaurant-claims/"+url.PathEscape(fmt.Sprint(id)), nil, nil, jsonBody(body))
+	if err != nil {
+		return nil, err
+	}
+	var result RestaurantClaim
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// FetchExternalSource calls POST /admin/restaurants/{id}/external-sources: Fetch review site data of a restaurant
+func (c *Client) FetchExternalSource(ctx context.Context, id int) (*ExternalSource, error) {
+	resp, err := c.do(ctx, "POST", "/admin/restaurants/"+url.PathEscape(fmt.Sprint(id))+"/external-sources", nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var result ExternalSource
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// DeleteExternalSource calls DELETE /admin/restaurants/{id}/external-sources/{provider}: Remove review site data of a restaurant
+func (c *Client) DeleteExternalSource(ctx context.Context, id int, provider string) error {
+	resp, err := c.do(ctx, "DELETE", "/admin/restaurants/"+url.PathEscape(fmt.Sprint(id))+"/external-sources/"+url.PathEscape(fmt.Sprint(provider)), nil, nil, nil)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// ListServiceAccounts calls GET /admin/service-accounts: List service accounts
+func (c *Client) ListServiceAccounts(ctx context.Context) ([]ServiceAccount, error) {
+	resp, err := c.do(ctx, "GET", "/admin/service-accounts", nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var result []ServiceAccount
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// CreateServiceAccount calls POST /admin/service-accounts: Create a service account
+func (c *Client) CreateServiceAccount(ctx context.Context, body CreateServiceAccountRequest) (*ServiceAccount, error) {
+	resp, err := c.do(ctx, "POST", "/admin/service-accounts", nil, nil, jsonBody(body))
+	if err != nil {
+		return nil, err
+	}
+	var result ServiceAccount
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// DisableServiceAccount calls DELETE /admin/service-accounts/{id}: Disable a service account
+func (c *Client) DisableServiceAccount(ctx context.Context, id int) error {
+	resp, err := c.do(ctx, "DELETE", "/admin/service-accounts/"+url.PathEscape(fmt.Sprint(id)), nil, nil, nil)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// ListServiceAccountAPIKeys calls GET /admin/service-accounts/{id}/keys: List service account API keys
+func (c *Client) ListServiceAccountAPIKeys(ctx context.Context, id int) ([]APIKey, error) {
+	resp, err := c.do(ctx, "GET", "/admin/service-accounts/"+url.PathEscape(fmt.Sprint(id))+"/keys", nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var result []APIKey
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// CreateServiceAccountAPIKey calls POST /admin/service-accounts/{id}/keys: Create a service account API key
+func (c *Client) CreateServiceAccountAPIKey(ctx context.Context, id int, body CreateAPIKeyRequest) (*CreateAPIKeyResponse, error) {
+	resp, err := c.do(ctx, "POST", "/admin/service-accounts/"+url.PathEscape(fmt.Sprint(id))+"/keys", nil, nil, jsonBody(body))
+	if err != nil {
+		return nil, err
+	}
+	var result CreateAPIKeyResponse
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// DeleteServiceAccountAPIKey calls DELETE /admin/service-accounts/{id}/keys/{keyId}: Revoke a service account API key
+func (c *Client) DeleteServiceAccountAPIKey(ctx context.Context, id int, keyID int) error {
+	resp, err := c.do(ctx, "DELETE", "/admin/service-accounts/"+url.PathEscape(fmt.Sprint(id))+"/keys/"+url.PathEscape(fmt.Sprint(keyID)), nil, nil, nil)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// ListSettings calls GET /admin/settings: List settings
+func (c *Client) ListSettings(ctx context.Context) ([]Setting, error) {
+	resp, err := c.do(ctx, "GET", "/admin/settings", nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var result []Setting
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// UpdateSetting calls PUT /admin/settings/{key}: Change a setting
+func (c *Client) UpdateSetting(ctx context.Context, key string, body SettingRequest) (*Setting, error) {
+	resp, err := c.do(ctx, "PUT", "/admin/settings/"+url.PathEscape(fmt.Sprint(key)), nil, nil, jsonBody(body))
+	if err != nil {
+		return nil, err
+	}
+	var result Setting
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ResetSetting calls DELETE /admin/settings/{key}: Reset a setting
+func (c *Client) ResetSetting(ctx context.Context, key string) (*Setting, error) {
+	resp, err := c.do(ctx, "DELETE", "/admin/settings/"+url.PathEscape(fmt.Sprint(key)), nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var result Setting
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// RestoreUser calls POST /admin/users/{id}/restore: Restore a deleted account
+func (c *Client) RestoreUser(ctx context.Context, id int) error {
+	resp, err := c.do(ctx, "POST", "/admin/users/"+url.PathEscape(fmt.Sprint(id))+"/restore", nil, nil, nil)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// UnlockUser calls POST /admin/users/{id}/unlock: Unlock a user account
+func (c *Client) UnlockUser(ctx context.Context, id int) error {
+	resp, err := c.do(ctx, "POST", "/admin/users/"+url.PathEscape(fmt.Sprint(id))+"/unlock", nil, nil, nil)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// ListWebhooks calls GET /admin/webhooks: List webhooks
+func (c *Client) ListWebhooks(ctx context.Context) ([]Webhook, error) {
+	resp, err := c.do(ctx, "GET", "/admin/webhooks", nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var result []Webhook
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// CreateWebhook calls POST /admin/webhooks: Register a webhook
+func (c *Client) CreateWebhook(ctx context.Context, body WebhookRequest) (*Webhook, error) {
+	resp, err := c.do(ctx, "POST", "/admin/webhooks", nil, nil, jsonBody(body))
+	if err != nil {
+		return nil, err
+	}
+	var result Webhook
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// UpdateWebhook calls PUT /admin/webhooks/{id}: Change a webhook
+func (c *Client) UpdateWebhook(ctx context.Context, id int, body WebhookRequest) (*Webhook, error) {
+	resp, err := c.do(ctx, "PUT", "/admin/webhooks/"+url.PathEscape(fmt.Sprint(id)), nil, nil, jsonBody(body))
+	if err != nil {
+		return nil, err
+	}
+	var result Webhook
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// DeleteWebhook calls DELETE /admin/webhooks/{id}: Delete a webhook
+func (c *Client) DeleteWebhook(ctx context.Context, id int) error {
+	resp, err := c.do(ctx, "DELETE", "/admin/webhooks/"+url.PathEscape(fmt.Sprint(id)), nil, nil, nil)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// GetWebhookDeliveriesParams are the query and header parameters of GetWebhookDeliveries
+type GetWebhookDeliveriesParams struct {
+	// Filter by status (pending, delivered, failed)
+	Status *string
+	// Deliveries to return (default 50, max 200)
+	Limit *int
+}
+
+// GetWebhookDeliveries calls GET /admin/webhooks/{id}/deliveries: List deliveries of a webhook
+func (c *Client) GetWebhookDeliveries(ctx context.Context, id int, params *GetWebhookDeliveriesParams) ([]WebhookDelivery, error) {
+	query, header := url.Values{}, http.Header{}
+	if params != nil {
+		if params.Status != nil {
+			query.Set("status", fmt.Sprint(*params.Status))
+		}
+		if params.Limit != nil {
+			query.Set("limit", fmt.Sprint(*params.Limit))
+		}
+	}
+	resp, err := c.do(ctx, "GET", "/admin/webhooks/"+url.PathEscape(fmt.Sprint(id))+"/deliveries", query, header, nil)
+	if err != nil {
+		return nil, err
+	}
+	var result []WebhookDelivery
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetCSRFToken calls GET /auth/csrf: Get a CSRF token
+func (c *Client) GetCSRFToken(ctx context.Context) (*CSRFTokenResponse, error) {
+	resp, err := c.do(ctx, "GET", "/auth/csrf", nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var result CSRFTokenResponse
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ForgotPassword calls POST /auth/forgot-password: Request a password reset
+// The caller closes the response body.
+func (c *Client) ForgotPassword(ctx context.Context, body ForgotPasswordRequest) (*http.Response, error) {
+	resp, err := c.do(ctx, "POST", "/auth/forgot-password", nil, nil, jsonBody(body))
+	return resp, err
+}
+
+// Login calls POST /auth/login: Login
+func (c *Client) Login(ctx context.Context, body LoginRequest) (*LoginResponse, error) {
+	resp, err := c.do(ctx, "POST", "/auth/login", nil, nil, jsonBody(body))
+	if err != nil {
+		return nil, err
+	}
+	var result LoginResponse
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Logout calls POST /auth/logout: Logout
+func (c *Client) Logout(ctx context.Context, body RefreshTokenRequest) (*string, error) {
+	resp, err := c.do(ctx, "POST", "/auth/logout", nil, nil, jsonBody(body))
+	if err != nil {
+		return nil, err
+	}
+	var result string
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetMe calls GET /auth/me: Get current user
+func (c *Client) GetMe(ctx context.Context) (*User, error) {
+	resp, err := c.do(ctx, "GET", "/auth/me", nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var result User
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// DeleteMyAccount calls POST /auth/me/delete: Delete my account
+func (c *Client) DeleteMyAccount(ctx context.Context, body DeleteAccountRequest) (*DeleteAccountResponse, error) {
+	resp, err := c.do(ctx, "POST", "/auth/me/delete", nil, nil, jsonBody(body))
+	if err != nil {
+		return nil, err
+	}
+	var result DeleteAccountResponse
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ExportMyAccount calls GET /auth/me/export: Export my data
+func (c *Client) ExportMyAccount(ctx context.Context) (*AccountExport, error) {
+	resp, err := c.do(ctx, "GET", "/auth/me/export", nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var result AccountExport
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetFollowing calls GET /auth/me/following: List followed users
+func (c *Client) GetFollowing(ctx context.Context) ([]FollowedUser, error) {
+	resp, err := c.do(ctx, "GET", "/auth/me/following", nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var result []FollowedUser
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetMyRestaurantClaims calls GET /auth/me/restaurant-claims: List my restaurant claims
+func (c *Client) GetMyRestaurantClaims(ctx context.Context) ([]RestaurantClaim, error) {
+	resp, err := c.do(ctx, "GET", "/auth/me/restaurant-claims", nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var result []RestaurantClaim
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// OIDCCallbackParams are the query and header parameters of OIDCCallback
+type OIDCCallbackParams struct {
+	// Authorization code
+	Code string
+	// OIDC state
+	State string
+}
+
+// OIDCCallback calls GET /auth/oidc/callback: OIDC callback
+func (c *Client) OIDCCallback(ctx context.Context, params *OIDCCallbackParams) (*LoginResponse, error) {
+	query, header := url.Values{}, http.Header{}
+	if params != nil {
+		query.Set("code", fmt.Sprint(params.Code))
+		query.Set("state", fmt.Sprint(params.State))
+	}
+	resp, err := c.do(ctx, "GET", "/auth/oidc/callback", query, header, nil)
+	if err != nil {
+		return nil, err
+	}
+	var result LoginResponse
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// OIDCLoginParams are the query and header parameters of OIDCLogin
+type OIDCLoginParams struct {
+	// Invite code, required to create an account when REGISTRATION_MODE=invite
+	Invite *string
+}
+
+// OIDCLogin calls GET /auth/oidc/login: OIDC login
+func (c *Client) OIDCLogin(ctx context.Context, params *OIDCLoginParams) error {
+	query, header := url.Values{}, http.Header{}
+	if params != nil {
+		if params.Invite != nil {
+			query.Set("invite", fmt.Sprint(*params.Invite))
+		}
+	}
+	resp, err := c.do(ctx, "GET", "/auth/oidc/login", query, header, nil)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// GetAuthOIDCByProviderCallbackParams are the query and header parameters of GetAuthOIDCByProviderCallback
+type GetAuthOIDCByProviderCallbackParams struct {
+	// Authorization code
+	Code string
+	// OIDC state
+	State string
+}
+
+// GetAuthOIDCByProviderCallback calls GET /auth/oidc/{provider}/callback: OIDC callback
+func (c *Client) GetAuthOIDCByProviderCallback(ctx context.Context, provider string, params *GetAuthOIDCByProviderCallbackParams) (*LoginResponse, error) {
+	query, header := url.Values{}, http.Header{}
+	if params != nil {
+		query.Set("code", fmt.Sprint(params.Code))
+		query.Set("state", fmt.Sprint(params.State))
+	}
+	resp, err := c.do(ctx, "GET", "/auth/oidc/"+url.PathEscape(fmt.Sprint(provider))+"/callback", query, header, nil)
+	if err != nil {
+		return nil, err
+	}
+	var result LoginResponse
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetAuthOIDCByProviderLoginParams are the query and header parameters of GetAuthOIDCByProviderLogin
+type GetAuthOIDCByProviderLoginParams struct {
+	// Invite code, required to create an account when REGISTRATION_MODE=invite
+	Invite *string
+}
+
+// GetAuthOIDCByProviderLogin calls GET /auth/oidc/{provider}/login: OIDC login
+func (c *Client) GetAuthOIDCByProviderLogin(ctx context.Context, provider string, params *GetAuthOIDCByProviderLoginParams) error {
+	query, header := url.Values{}, http.Header{}
+	if params != nil {
+		if params.Invite != nil {
+			query.Set("invite", fmt.Sprint(*params.Invite))
+		}
+	}
+	resp, err := c.do(ctx, "GET", "/auth/oidc/"+url.PathEscape(fmt.Sprint(provider))+"/login", query, header, nil)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// GetAuthProviders calls GET /auth/providers: List login providers
+func (c *Client) GetAuthProviders(ctx context.Context) (*AuthProvidersResponse, error) {
+	resp, err := c.do(ctx, "GET", "/auth/providers", nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var result AuthProvidersResponse
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// RefreshToken calls POST /auth/refresh: Refresh token
+func (c *Client) RefreshToken(ctx context.Context, body RefreshTokenRequest) (*LoginResponse, error) {
+	resp, err := c.do(ctx, "POST", "/auth/refresh", nil, nil, jsonBody(body))
+	if err != nil {
+		return nil, err
+	}
+	var result LoginResponse
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Register calls POST /auth/register: Register a new user
+func (c *Client) Register(ctx context.Context, body RegisterRequest) (*LoginResponse, error) {
+	resp, err := c.do(ctx, "POST", "/auth/register", nil, nil, jsonBody(body))
+	if err != nil {
+		return nil, err
+	}
+	var result LoginResponse
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ResetPassword calls POST /auth/reset-password: Reset password
+// The caller closes the response body.
+func (c *Client) ResetPassword(ctx context.Context, body ResetPasswordRequest) (*http.Response, error) {
+	resp, err := c.do(ctx, "POST", "/auth/reset-password", nil, nil, jsonBody(body))
+	return resp, err
+}
+
+// GetCategories calls GET /categories: List all categories
+func (c *Client) GetCategories(ctx context.Context) ([]Category, error) {
+	resp, err := c.do(ctx, "GET", "/categories", nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var result []Category
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// CreateCategory calls POST /categories: Create a new category
+func (c *Client) CreateCategory(ctx context.Context, body CreateCategoryRequest) (*Category, error) {
+	resp, err := c.do(ctx, "POST", "/categories", nil, nil, jsonBody(body))
+	if err != nil {
+		return nil, err
+	}
+	var result Category
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetCategoryTree calls GET /categories/tree: Get the category tree
+func (c *Client) GetCategoryTree(ctx context.Context) ([]CategoryNode, error) {
+	resp, err := c.do(ctx, "GET", "/categories/tree", nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var result []CategoryNode
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetCategory calls GET /categories/{id}: Get a category by ID
+func (c *Client) GetCategory(ctx context.Context, id int) (*Category, error) {
+	resp, err := c.do(ctx, "GET", "/categories/"+url.PathEscape(fmt.Sprint(id)), nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var result Category
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// UpdateCategory calls PUT /categories/{id}: Update a category
+func (c *Client) UpdateCategory(ctx context.Context, id int, body CreateCategoryRequest) (*Category, error) {
+	resp, err := c.do(ctx, "PUT", "/categories/"+url.PathEscape(fmt.Sprint(id)), nil, nil, jsonBody(body))
+	if err != nil {
+		return nil, err
+	}
+	var result Category
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// DeleteCategoryParams are the query and header parameters of DeleteCategory
+type DeleteCategoryParams struct {
+	// Category to move the restaurants and suggestions of the deleted category to
+	ReassignTo *int
+}
+
+// DeleteCategory calls DELETE /categories/{id}: Delete a category
+func (c *Client) DeleteCategory(ctx context.Context, id int, params *DeleteCategoryParams) error {
+	query, header := url.Values{}, http.Header{}
+	if params != nil {
+		if params.ReassignTo != nil {
+			query.Set("reassign_to", fmt.Sprint(*params.ReassignTo))
+		}
+	}
+	resp, err := c.do(ctx, "DELETE", "/categories/"+url.PathEscape(fmt.Sprint(id)), query, header, nil)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// GetCategoryTranslations calls GET /categories/{id}/translations: List translations of a category
+func (c *Client) GetCategoryTranslations(ctx context.Context, id int) ([]Translation, error) {
+	resp, err := c.do(ctx, "GET", "/categories/"+url.PathEscape(fmt.Sprint(id))+"/translations", nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var result []Translation
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// SetCategoryTranslation calls PUT /categories/{id}/translations/{locale}: Set the translation of a category
+func (c *Client) SetCategoryTranslation(ctx context.Context, id int, locale string, body TranslationRequest) (*Translation, error) {
+	resp, err := c.do(ctx, "PUT", "/categories/"+url.PathEscape(fmt.Sprint(id))+"/translations/"+url.PathEscape(fmt.Sprint(locale)), nil, nil, jsonBody(body))
+	if err != nil {
+		return nil, err
+	}
+	var result Translation
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// DeleteCategoryTranslation calls DELETE /categories/{id}/translations/{locale}: Delete the translation of a category
+func (c *Client) DeleteCategoryTranslation(ctx context.Context, id int, locale string) error {
+	resp, err := c.do(ctx, "DELETE", "/categories/"+url.PathEscape(fmt.Sprint(id))+"/translations/"+url.PathEscape(fmt.Sprint(locale)), nil, nil, nil)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// StreamEventsParams are the query and header parameters of StreamEvents
+type StreamEventsParams struct {
+	// Only events of this restaurant
+	RestaurantID *int
+}
+
+// StreamEvents calls GET /events: Stream live updates
+// The caller closes the response body.
+func (c *Client) StreamEvents(ctx context.Context, params *StreamEventsParams) (*http.Response, error) {
+	query, header := url.Values{}, http.Header{}
+	if params != nil {
+		if params.RestaurantID != nil {
+			query.Set("restaurant_id", fmt.Sprint(*params.RestaurantID))
+		}
+	}
+	resp, err := c.do(ctx, "GET", "/events", query, header, nil)
+	return resp, err
+}
+
+// GetFeatures calls GET /features: Get feature flags
+func (c *Client) GetFeatures(ctx context.Context) (map[string]bool, error) {
+	resp, err := c.do(ctx, "GET", "/features", nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var result map[string]bool
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetFeedParams are the query and header parameters of GetFeed
+type GetFeedParams struct {
+	// Only activity of followed users
+	Following *bool
+	// Only activity created before this time (RFC 3339)
+	Before *string
+	// Number of items (default 20, max 100)
+	Limit *int
+}
+
+// GetFeed calls GET /feed: Get the activity feed
+func (c *Client) GetFeed(ctx context.Context, params *GetFeedParams) ([]ActivityItem, error) {
+	query, header := url.Values{}, http.Header{}
+	if params != nil {
+		if params.Following != nil {
+			query.Set("following", fmt.Sprint(*params.Following))
+		}
+		if params.Before != nil {
+			query.Set("before", fmt.Sprint(*params.Before))
+		}
+		if params.Limit != nil {
+			query.Set("limit", fmt.Sprint(*params.Limit))
+		}
+	}
+	resp, err := c.do(ctx, "GET", "/feed", query, header, nil)
+	if err != nil {
+		return nil, err
+	}
+	var result []ActivityItem
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetFoodTypes calls GET /food-types: List all food types
+func (c *Client) GetFoodTypes(ctx context.Context) ([]FoodType, error) {
+	resp, err := c.do(ctx, "GET", "/food-types", nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var result []FoodType
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// CreateFoodType calls POST /food-types: Create a new food type
+func (c *Client) CreateFoodType(ctx context.Context, body CreateFoodTypeRequest) (*FoodType, error) {
+	resp, err := c.do(ctx, "POST", "/food-types", nil, nil, jsonBody(body))
+	if err != nil {
+		return nil, err
+	}
+	var result FoodType
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetFoodType calls GET /food-types/{id}: Get a food type by ID
+func (c *Client) GetFoodType(ctx context.Context, id int) (*FoodType, error) {
+	resp, err := c.do(ctx, "GET", "/food-types/"+url.PathEscape(fmt.Sprint(id)), nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var result FoodType
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// UpdateFoodType calls PUT /food-types/{id}: Update a food type
+func (c *Client) UpdateFoodType(ctx context.Context, id int, body CreateFoodTypeRequest) (*FoodType, error) {
+	resp, err := c.do(ctx, "PUT", "/food-types/"+url.PathEscape(fmt.Sprint(id)), nil, nil, jsonBody(body))
+	if err != nil {
+		return nil, err
+	}
+	var result FoodType
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// DeleteFoodType calls DELETE /food-types/{id}: Delete a food type
+func (c *Client) DeleteFoodType(ctx context.Context, id int) error {
+	resp, err := c.do(ctx, "DELETE", "/food-types/"+url.PathEscape(fmt.Sprint(id)), nil, nil, nil)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// MergeFoodTypes calls POST /food-types/{id}/merge: Merge duplicate food types
+func (c *Client) MergeFoodTypes(ctx context.Context, id int, body MergeFoodTypesRequest) (*FoodType, error) {
+	resp, err := c.do(ctx, "POST", "/food-types/"+url.PathEscape(fmt.Sprint(id))+"/merge", nil, nil, jsonBody(body))
+	if err != nil {
+		return nil, err
+	}
+	var result FoodType
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetFoodTypeTranslations calls GET /food-types/{id}/translations: List translations of a food type
+func (c *Client) GetFoodTypeTranslations(ctx context.Context, id int) ([]Translation, error) {
+	resp, err := c.do(ctx, "GET", "/food-types/"+url.PathEscape(fmt.Sprint(id))+"/translations", nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var result []Translation
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// SetFoodTypeTranslation calls PUT /food-types/{id}/translations/{locale}: Set the translation of a food type
+func (c *Client) SetFoodTypeTranslation(ctx context.Context, id int, locale string, body TranslationRequest) (*Translation, error) {
+	resp, err := c.do(ctx, "PUT", "/food-types/"+url.PathEscape(fmt.Sprint(id))+"/translations/"+url.PathEscape(fmt.Sprint(locale)), nil, nil, jsonBody(body))
+	if err != nil {
+		return nil, err
+	}
+	var result Translation
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// DeleteFoodTypeTranslation calls DELETE /food-types/{id}/translations/{locale}: Delete the translation of a food type
+func (c *Client) DeleteFoodTypeTranslation(ctx context.Context, id int, locale string) error {
+	resp, err := c.do(ctx, "DELETE", "/food-types/"+url.PathEscape(fmt.Sprint(id))+"/translations/"+url.PathEscape(fmt.Sprint(locale)), nil, nil, nil)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// GeocodeCitiesParams are the query and header parameters of GeocodeCities
+type GeocodeCitiesParams struct {
+	// City name to geocode
+	Q string
+	// Look up the coordinates of each city (default true)
+	Coordinates *bool
+}
+
+// GeocodeCities calls GET /geocode/cities: Geocode cities
+func (c *Client) GeocodeCities(ctx context.Context, params *GeocodeCitiesParams) ([]GooglePlaceResult, error) {
+	query, header := url.Values{}, http.Header{}
+	if params != nil {
+		query.Set("q", fmt.Sprint(params.Q))
+		if params.Coordinates != nil {
+			query.Set("coordinates", fmt.Sprint(*params.Coordinates))
+		}
+	}
+	resp, err := c.do(ctx, "GET", "/geocode/cities", query, header, nil)
+	if err != nil {
+		return nil, err
+	}
+	var result []GooglePlaceResult
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ReadinessCheck calls GET /health: Readiness probe
+func (c *Client) ReadinessCheck(ctx context.Context) (*HealthResponse, error) {
+	resp, err := c.do(ctx, "GET", "/health", nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var result HealthResponse
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// LivenessCheck calls GET /health/live: Liveness probe
+// The caller closes the response body.
+func (c *Client) LivenessCheck(ctx context.Context) (*http.Response, error) {
+	resp, err := c.do(ctx, "GET", "/health/live", nil, nil, nil)
+	return resp, err
+}
+
+// GetHealthReady calls GET /health/ready: Readiness probe
+func (c *Client) GetHealthReady(ctx context.Context) (*HealthResponse, error) {
+	resp, err := c.do(ctx, "GET", "/health/ready", nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var result HealthResponse
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ListAPIKeys calls GET /keys: List API keys
+func (c *Client) ListAPIKeys(ctx context.Context) ([]APIKey, error) {
+	resp, err := c.do(ctx, "GET", "/keys", nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var result []APIKey
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// CreateAPIKey calls POST /keys: Create an API key
+func (c *Client) CreateAPIKey(ctx context.Context, body CreateAPIKeyRequest) (*CreateAPIKeyResponse, error) {
+	resp, err := c.do(ctx, "POST", "/keys", nil, nil, jsonBody(body))
+	if err != nil {
+		return nil, err
+	}
+	var result CreateAPIKeyResponse
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// DeleteAPIKey calls DELETE /keys/{id}: Revoke an API key
+func (c *Client) DeleteAPIKey(ctx context.Context, id int) error {
+	resp, err := c.do(ctx, "DELETE", "/keys/"+url.PathEscape(fmt.Sprint(id)), nil, nil, nil)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// GetLeaderboardParams are the query and header parameters of GetLeaderboard
+type GetLeaderboardParams struct {
+	// month (default) or all
+	Period *string
+	// Number of users per ranking (default 20, max 100)
+	Limit *int
+}
+
+// GetLeaderboard calls GET /leaderboard: Get the leaderboard
+func (c *Client) GetLeaderboard(ctx context.Context, params *GetLeaderboardParams) (*Leaderboard, error) {
+	query, header := url.Values{}, http.Header{}
+	if params != nil {
+		if params.Period != nil {
+			query.Set("period", fmt.Sprint(*params.Period))
+		}
+		if params.Limit != nil {
+			query.Set("limit", fmt.Sprint(*params.Limit))
+		}
+	}
+	resp, err := c.do(ctx, "GET", "/leaderboard", query, header, nil)
+	if err != nil {
+		return nil, err
+	}
+	var result Leaderboard
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetMeta calls GET /meta: Get reference data
+func (c *Client) GetMeta(ctx context.Context) (*Meta, error) {
+	resp, err := c.do(ctx, "GET", "/meta", nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var result Meta
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetMenuPhoto calls GET /photos/{id}: Get a menu photo
+func (c *Client) GetMenuPhoto(ctx context.Context, id int) (*MenuPhoto, error) {
+	resp, err := c.do(ctx, "GET", "/photos/"+url.PathEscape(fmt.Sprint(id)), nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var result MenuPhoto
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// UpdatePhotoCaptionBody is the request body of UpdatePhotoCaption
+type UpdatePhotoCaptionBody struct {
+	Caption   *string `json:"caption,omitempty"`
+	PhotoType *string `json:"photo_type,omitempty"`
+}
+
+// UpdatePhotoCaption calls PATCH /photos/{id}: Update photo caption
+func (c *Client) UpdatePhotoCaption(ctx context.Context, id int, body UpdatePhotoCaptionBody) (*MenuPhoto, error) {
+	resp, err := c.do(ctx, "PATCH", "/photos/"+url.PathEscape(fmt.Sprint(id)), nil, nil, jsonBody(body))
+	if err != nil {
+		return nil, err
+	}
+	var result MenuPhoto
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// DeleteMenuPhoto calls DELETE /photos/{id}: Delete a menu photo
+func (c *Client) DeleteMenuPhoto(ctx context.Context, id int) error {
+	resp, err := c.do(ctx, "DELETE", "/photos/"+url.PathEscape(fmt.Sprint(id)), nil, nil, nil)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// SetCoverPhoto calls PUT /photos/{id}/cover: Set cover photo
+func (c *Client) SetCoverPhoto(ctx context.Context, id int) (*MenuPhoto, error) {
+	resp, err := c.do(ctx, "PUT", "/photos/"+url.PathEscape(fmt.Sprint(id))+"/cover", nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var result MenuPhoto
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// UnsetCoverPhoto calls DELETE /photos/{id}/cover: Unset cover photo
+func (c *Client) UnsetCoverPhoto(ctx context.Context, id int) error {
+	resp, err := c.do(ctx, "DELETE", "/photos/"+url.PathEscape(fmt.Sprint(id))+"/cover", nil, nil, nil)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// GetPhotoImageParams are the query and header parameters of GetPhotoImage
+type GetPhotoImageParams struct {
+	// Maximum width in pixels (160, 320, 640, 1280 or 1920)
+	W *int
+	// Maximum height in pixels (160, 320, 640, 1280 or 1920)
+	H *int
+	// Output format (auto, jpeg, png, webp, avif)
+	Format *string
+}
+
+// GetPhotoImage calls GET /photos/{id}/image: Get a resized photo
+// The caller closes the response body.
+func (c *Client) GetPhotoImage(ctx context.Context, id int, params *GetPhotoImageParams) (*http.Response, error) {
+	query, header := url.Values{}, http.Header{}
+	if params != nil {
+		if params.W != nil {
+			query.Set("w", fmt.Sprint(*params.W))
+		}
+		if params.H != nil {
+			query.Set("h", fmt.Sprint(*params.H))
+		}
+		if params.Format != nil {
+			query.Set("format", fmt.Sprint(*params.Format))
+		}
+	}
+	resp, err := c.do(ctx, "GET", "/photos/"+url.PathEscape(fmt.Sprint(id))+"/image", query, header, nil)
+	return resp, err
+}
+
+// GetPlacePhotoParams are the query and header parameters of GetPlacePhoto
+type GetPlacePhotoParams struct {
+	// Photo name (places/{placeId}/photos/{photoId})
+	Name string
+	// Maximum width in pixels (default 800, max 4800)
+	MaxWidth *int
+}
+
+// GetPlacePhoto calls GET /places/photo: Get a place photo
+func (c *Client) GetPlacePhoto(ctx context.Context, params *GetPlacePhotoParams) error {
+	query, header := url.Values{}, http.Header{}
+	if params != nil {
+		query.Set("name", fmt.Sprint(params.Name))
+		if params.MaxWidth != nil {
+			query.Set("max_width", fmt.Sprint(*params.MaxWidth))
+		}
+	}
+	resp, err := c.do(ctx, "GET", "/places/photo", query, header, nil)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// SearchPlacesParams are the query and header parameters of SearchPlaces
+type SearchPlacesParams struct {
+	// Search query
+	Q string
+	// Latitude to bias results towards (with lng)
+	Lat *float64
+	// Longitude to bias results towards (with lat)
+	Lng *float64
+	// Bias radius in km around lat/lng (default 5, max 50)
+	Radius *float64
+	// Next page token from X-Next-Page-Token
+	PageToken *string
+}
+
+// SearchPlaces calls GET /places/search: Search for places
+func (c *Client) SearchPlaces(ctx context.Context, params *SearchPlacesParams) ([]GooglePlaceResult, error) {
+	query, header := url.Values{}, http.Header{}
+	if params != nil {
+		query.Set("q", fmt.Sprint(params.Q))
+		if params.Lat != nil {
+			query.Set("lat", fmt.Sprint(*params.Lat))
+		}
+		if params.Lng != nil {
+			query.Set("lng", fmt.Sprint(*params.Lng))
+		}
+		if params.Radius != nil {
+			query.Set("radius", fmt.Sprint(*params.Radius))
+		}
+		if params.PageToken != nil {
+			query.Set("page_token", fmt.Sprint(*params.PageToken))
+		}
+	}
+	resp, err := c.do(ctx, "GET", "/places/search", query, header, nil)
+	if err != nil {
+		return nil, err
+	}
+	var result []GooglePlaceResult
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetPlaceDetails calls GET /places/{placeId}: Get place details
+func (c *Client) GetPlaceDetails(ctx context.Context, placeID string) (*GooglePlaceResult, error) {
+	resp, err := c.do(ctx, "GET", "/places/"+url.PathEscape(fmt.Sprint(placeID)), nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var result GooglePlaceResult
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// CreateRating calls POST /ratings: Create a new rating
+func (c *Client) CreateRating(ctx context.Context, body CreateRatingRequest) (*Rating, error) {
+	resp, err := c.do(ctx, "POST", "/ratings", nil, nil, jsonBody(body))
+	if err != nil {
+		return nil, err
+	}
+	var result Rating
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// DeleteRating calls DELETE /ratings/{id}: Delete a rating
+func (c *Client) DeleteRating(ctx context.Context, id int) error {
+	resp, err := c.do(ctx, "DELETE", "/ratings/"+url.PathEscape(fmt.Sprint(id)), nil, nil, nil)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// SetRatingResponse calls PUT /ratings/{id}/response: Respond to a rating
+func (c *Client) SetRatingResponse(ctx context.Context, id int, body OwnerResponseRequest) (*Rating, error) {
+	resp, err := c.do(ctx, "PUT", "/ratings/"+url.PathEscape(fmt.Sprint(id))+"/response", nil, nil, jsonBody(body))
+	if err != nil {
+		return nil, err
+	}
+	var result Rating
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// DeleteRatingResponse calls DELETE /ratings/{id}/response: Delete a rating response
+func (c *Client) DeleteRatingResponse(ctx context.Context, id int) error {
+	resp, err := c.do(ctx, "DELETE", "/ratings/"+url.PathEscape(fmt.Sprint(id))+"/response", nil, nil, nil)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// GetRestaurantsParams are the query and header parameters of GetRestaurants
+type GetRestaurantsParams struct {
+	// Filter by category ID
+	CategoryID *int
+	// Filter by food type IDs (comma-separated)
+	FoodTypeIDs *string
+	// Latitude for distance filtering
+	Lat *float64
+	// Longitude for distance filtering
+	Lng *float64
+	// Radius in kilometers for distance filtering
+	Radius *float64
+}
+
+// GetRestaurants calls GET /restaurants: List all restaurants
+func (c *Client) GetRestaurants(ctx context.Context, params *GetRestaurantsParams) ([]Restaurant, error) {
+	query, header := url.Values{}, http.Header{}
+	if params != nil {
+		if params.CategoryID != nil {
+			query.Set("category_id", fmt.Sprint(*params.CategoryID))
+		}
+		if params.FoodTypeIDs != nil {
+			query.Set("food_type_ids", fmt.Sprint(*params.FoodTypeIDs))
+		}
+		if params.Lat != nil {
+			query.Set("lat", fmt.Sprint(*params.Lat))
+		}
+		if params.Lng != nil {
+			query.Set("lng", fmt.Sprint(*params.Lng))
+		}
+		if params.Radius != nil {
+			query.Set("radius", fmt.Sprint(*params.Radius))
+		}
+	}
+	resp, err := c.do(ctx, "GET", "/restaurants", query, header, nil)
+	if err != nil {
+		return nil, err
+	}
+	var result []Restaurant
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// CreateRestaurant calls POST /restaurants: Create a new restaurant
+func (c *Client) CreateRestaurant(ctx context.Context, body CreateRestaurantRequest) (*Restaurant, error) {
+	resp, err := c.do(ctx, "POST", "/restaurants", nil, nil, jsonBody(body))
+	if err != nil {
+		return nil, err
+	}
+	var result Restaurant
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetRestaurantsInBoundsParams are the query and header parameters of GetRestaurantsInBounds
+type GetRestaurantsInBoundsParams struct {
+	// Latitude of the north-east corner
+	NeLat float64
+	// Longitude of the north-east corner
+	NeLng float64
+	// Latitude of the south-west corner
+	SwLat float64
+	// Longitude of the south-west corner
+	SwLng float64
+	// Filter by category ID
+	CategoryID *int
+	// Filter by food type IDs (comma-separated)
+	FoodTypeIDs *string
+	// Search query for name or description
+	Q *string
+}
+
+// GetRestaurantsInBounds calls GET /restaurants/in-bounds: Get restaurants in a map area
+func (c *Client) GetRestaurantsInBounds(ctx context.Context, params *GetRestaurantsInBoundsParams) (*MapBoundsResponse, error) {
+	query, header := url.Values{}, http.Header{}
+	if params != nil {
+		query.Set("ne_lat", fmt.Sprint(params.NeLat))
+		query.Set("ne_lng", fmt.Sprint(params.NeLng))
+		query.Set("sw_lat", fmt.Sprint(params.SwLat))
+		query.Set("sw_lng", fmt.Sprint(params.SwLng))
+		if params.CategoryID != nil {
+			query.Set("category_id", fmt.Sprint(*params.CategoryID))
+		}
+		if params.FoodTypeIDs != nil {
+			query.Set("food_type_ids", fmt.Sprint(*params.FoodTypeIDs))
+		}
+		if params.Q != nil {
+			query.Set("q", fmt.Sprint(*params.Q))
+		}
+	}
+	resp, err := c.do(ctx, "GET", "/restaurants/in-bounds", query, header, nil)
+	if err != nil {
+		return nil, err
+	}
+	var result MapBoundsResponse
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetRestaurantsNearbyParams are the query and header parameters of GetRestaurantsNearby
+type GetRestaurantsNearbyParams struct {
+	// Latitude of the location
+	Lat float64
+	// Longitude of the location
+	Lng float64
+	// Maximum number of restaurants (default 20, max 100)
+	Limit *int
+	// Search radius in km (default 5, or how far the mode gets in max_minutes; max 50)
+	Radius *float64
+	// Travel mode: walking or driving
+	Mode *string
+	// Maximum travel time in minutes (1 to 120), required with mode
+	MaxMinutes *float64
+	// Filter by category ID
+	CategoryID *int
+	// Filter by food type IDs (comma-separated)
+	FoodTypeIDs *string
+	// Search query for name or description
+	Q *string
+}
+
+// GetRestaurantsNearby calls GET /restaurants/nearby: Get restaurants near a location
+func (c *Client) GetRestaurantsNearby(ctx context.Context, params *GetRestaurantsNearbyParams) ([]Restaurant, error) {
+	query, header := url.Values{}, http.Header{}
+	if params != nil {
+		query.Set("lat", fmt.Sprint(params.Lat))
+		query.Set("lng", fmt.Sprint(params.Lng))
+		if params.Limit != nil {
+			query.Set("limit", fmt.Sprint(*params.Limit))
+		}
+		if params.Radius != nil {
+			query.Set("radius", fmt.Sprint(*params.Radius))
+		}
+		if params.Mode != nil {
+			query.Set("mode", fmt.Sprint(*params.Mode))
+		}
+		if params.MaxMinutes != nil {
+			query.Set("max_minutes", fmt.Sprint(*params.MaxMinutes))
+		}
+		if params.CategoryID != nil {
+			query.Set("category_id", fmt.Sprint(*params.CategoryID))
+		}
+		if params.FoodTypeIDs != nil {
+			query.Set("food_type_ids", fmt.Sprint(*params.FoodTypeIDs))
+		}
+		if params.Q != nil {
+			query.Set("q", fmt.Sprint(*params.Q))
+		}
+	}
+	resp, err := c.do(ctx, "GET", "/restaurants/nearby", query, header, nil)
+	if err != nil {
+		return nil, err
+	}
+	var result []Restaurant
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetRestaurantsPaginatedParams are the query and header parameters of GetRestaurantsPaginated
+type GetRestaurantsPaginatedParams struct {
+	// Pagination cursor (next_cursor of the previous page)
+	Cursor *string
+	// Sort order: id (default, oldest first), name (A to Z) or rating (highest first)
+	Sort *string
+	// Number of items per page (default 20, max 100)
+	Limit *int
+	// Filter by category ID
+	CategoryID *int
+	// Filter by food type IDs (comma-separated)
+	FoodTypeIDs *string
+	// Search query for name or description
+	Q *string
+}
+
+// GetRestaurantsPaginated calls GET /restaurants/paginated: Get paginated list of restaurants
+func (c *Client) GetRestaurantsPaginated(ctx context.Context, params *GetRestaurantsPaginatedParams) (*PaginatedResponse, error) {
+	query, header := url.Values{}, http.Header{}
+	if params != nil {
+		if params.Cursor != nil {
+			query.Set("cursor", fmt.Sprint(*params.Cursor))
+		}
+		if params.Sort != nil {
+			query.Set("sort", fmt.Sprint(*params.Sort))
+		}
+		if params.Limit != nil {
+			query.Set("limit", fmt.Sprint(*params.Limit))
+		}
+		if params.CategoryID != nil {
+			query.Set("category_id", fmt.Sprint(*params.CategoryID))
+		}
+		if params.FoodTypeIDs != nil {
+			query.Set("food_type_ids", fmt.Sprint(*params.FoodTypeIDs))
+		}
+		if params.Q != nil {
+			query.Set("q", fmt.Sprint(*params.Q))
+		}
+	}
+	resp, err := c.do(ctx, "GET", "/restaurants/paginated", query, header, nil)
+	if err != nil {
+		return nil, err
+	}
+	var result PaginatedResponse
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetRestaurant calls GET /restaurants/{id}: Get a restaurant by ID
+func (c *Client) GetRestaurant(ctx context.Context, id int) (*Restaurant, error) {
+	resp, err := c.do(ctx, "GET", "/restaurants/"+url.PathEscape(fmt.Sprint(id)), nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var result Restaurant
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// UpdateRestaurant calls PUT /restaurants/{id}: Update a restaurant
+func (c *Client) UpdateRestaurant(ctx context.Context, id int, body UpdateRestaurantRequest) (*Restaurant, error) {
+	resp, err := c.do(ctx, "PUT", "/restaurants/"+url.PathEscape(fmt.Sprint(id)), nil, nil, jsonBody(body))
+	if err != nil {
+		return nil, err
+	}
+	var result Restaurant
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// DeleteRestaurant calls DELETE /restaurants/{id}: Delete a restaurant
+func (c *Client) DeleteRestaurant(ctx context.Context, id int) error {
+	resp, err := c.do(ctx, "DELETE", "/restaurants/"+url.PathEscape(fmt.Sprint(id)), nil, nil, nil)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// UpdateRestaurantBusiness calls PATCH /restaurants/{id}/business: Update business details
+func (c *Client) UpdateRestaurantBusiness(ctx context.Context, id int, body UpdateRestaurantBusinessRequest) (*Restaurant, error) {
+	resp, err := c.do(ctx, "PATCH", "/restaurants/"+url.PathEscape(fmt.Sprint(id))+"/business", nil, nil, jsonBody(body))
+	if err != nil {
+		return nil, err
+	}
+	var result Restaurant
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ClaimRestaurant calls POST /restaurants/{id}/claims: Claim a restaurant
+func (c *Client) ClaimRestaurant(ctx context.Context, id int, body CreateRestaurantClaimRequest) (*RestaurantClaim, error) {
+	resp, err := c.do(ctx, "POST", "/restaurants/"+url.PathEscape(fmt.Sprint(id))+"/claims", nil, nil, jsonBody(body))
+	if err != nil {
+		return nil, err
+	}
+	var result RestaurantClaim
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetRestaurantMapParams are the query and header parameters of GetRestaurantMap
+type GetRestaurantMapParams struct {
+	// Image width in pixels (default 400, max 640)
+	Width *int
+	// Image height in pixels (default 300, max 640)
+	Height *int
+	// Zoom level from 0 (world) to 21 (buildings) (default 15)
+	Zoom *int
+	// 1, or 2 for high-density screens (default 1)
+	Scale *int
+}
+
+// GetRestaurantMap calls GET /restaurants/{id}/map: Get map links of a restaurant
+func (c *Client) GetRestaurantMap(ctx context.Context, id int, params *GetRestaurantMapParams) (*RestaurantMap, error) {
+	query, header := url.Values{}, http.Header{}
+	if params != nil {
+		if params.Width != nil {
+			query.Set("width", fmt.Sprint(*params.Width))
+		}
+		if params.Height != nil {
+			query.Set("height", fmt.Sprint(*params.Height))
+		}
+		if params.Zoom != nil {
+			query.Set("zoom", fmt.Sprint(*params.Zoom))
+		}
+		if params.Scale != nil {
+			query.Set("scale", fmt.Sprint(*params.Scale))
+		}
+	}
+	resp, err := c.do(ctx, "GET", "/restaurants/"+url.PathEscape(fmt.Sprint(id))+"/map", query, header, nil)
+	if err != nil {
+		return nil, err
+	}
+	var result RestaurantMap
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetRestaurantMapImageParams are the query and header parameters of GetRestaurantMapImage
+type GetRestaurantMapImageParams struct {
+	// Image width in pixels (default 400, max 640)
+	Width *int
+	// Image height in pixels (default 300, max 640)
+	Height *int
+	// Zoom level from 0 (world) to 21 (buildings) (default 15)
+	Zoom *int
+	// 1, or 2 for high-density screens (default 1)
+	Scale *int
+}
+
+// GetRestaurantMapImage calls GET /restaurants/{id}/map/image: Get a map image of a restaurant
+// The caller closes the response body.
+func (c *Client) GetRestaurantMapImage(ctx context.Context, id int, params *GetRestaurantMapImageParams) (*http.Response, error) {
+	query, header := url.Values{}, http.Header{}
+	if params != nil {
+		if params.Width != nil {
+			query.Set("width", fmt.Sprint(*params.Width))
+		}
+		if params.Height != nil {
+			query.Set("height", fmt.Sprint(*params.Height))
+		}
+		if params.Zoom != nil {
+			query.Set("zoom", fmt.Sprint(*params.Zoom))
+		}
+		if params.Scale != nil {
+			query.Set("scale", fmt.Sprint(*params.Scale))
+		}
+	}
+	resp, err := c.do(ctx, "GET", "/restaurants/"+url.PathEscape(fmt.Sprint(id))+"/map/image", query, header, nil)
+	return resp, err
+}
+
+// GetMenuPhotosParams are the query and header parameters of GetMenuPhotos
+type GetMenuPhotosParams struct {
+	// Photo type (menu, food, interior, receipt)
+	Type *string
+	// Number of photos per page (default: 50, max: 100)
+	Limit *int
+	// Pagination cursor from X-Next-Cursor
+	Cursor *string
+}
+
+// GetMenuPhotos calls GET /restaurants/{restaurantId}/photos: Get menu photos for a restaurant
+func (c *Client) GetMenuPhotos(ctx context.Context, restaurantID int, params *GetMenuPhotosParams) ([]MenuPhoto, error) {
+	query, header := url.Values{}, http.Header{}
+	if params != nil {
+		if params.Type != nil {
+			query.Set("type", fmt.Sprint(*params.Type))
+		}
+		if params.Limit != nil {
+			query.Set("limit", fmt.Sprint(*params.Limit))
+		}
+		if params.Cursor != nil {
+			query.Set("cursor", fmt.Sprint(*params.Cursor))
+		}
+	}
+	resp, err := c.do(ctx, "GET", "/restaurants/"+url.PathEscape(fmt.Sprint(restaurantID))+"/photos", query, header, nil)
+	if err != nil {
+		return nil, err
+	}
+	var result []MenuPhoto
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// UploadMenuPhotoForm is the form of UploadMenuPhoto
+type UploadMenuPhotoForm struct {
+	// Photo caption
+	Caption string
+	// Menu photo file
+	Photo *FormFile
+	// Photo type (menu, food, interior, receipt)
+	PhotoType *string
+}
+
+// UploadMenuPhoto calls POST /restaurants/{restaurantId}/photos: Upload a menu photo
+func (c *Client) UploadMenuPhoto(ctx context.Context, restaurantID int, form UploadMenuPhotoForm) (*UploadPhotoResponse, error) {
+	resp, err := c.do(ctx, "POST", "/restaurants/"+url.PathEscape(fmt.Sprint(restaurantID))+"/photos", nil, nil, formBody("multipart/form-data", map[string]any{"caption": form.Caption, "photo": form.Photo, "photo_type": form.PhotoType}))
+	if err != nil {
+		return nil, err
+	}
+	var result UploadPhotoResponse
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// DownloadPhotoArchive calls GET /restaurants/{restaurantId}/photos/archive: Download all photos of a restaurant
+// The caller closes the response body.
+func (c *Client) DownloadPhotoArchive(ctx context.Context, restaurantID int) (*http.Response, error) {
+	resp, err := c.do(ctx, "GET", "/restaurants/"+url.PathEscape(fmt.Sprint(restaurantID))+"/photos/archive", nil, nil, nil)
+	return resp, err
+}
+
+// ConfirmMenuPhotoUpload calls POST /restaurants/{restaurantId}/photos/confirm: Confirm a presigned photo upload
+func (c *Client) ConfirmMenuPhotoUpload(ctx context.Context, restaurantID int, body ConfirmPhotoUploadRequest) (*UploadPhotoResponse, error) {
+	resp, err := c.do(ctx, "POST", "/restaurants/"+url.PathEscape(fmt.Sprint(restaurantID))+"/photos/confirm", nil, nil, jsonBody(body))
+	if err != nil {
+		return nil, err
+	}
+	var result UploadPhotoResponse
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// StreamPhotoEvents calls GET /restaurants/{restaurantId}/photos/events: Stream photo processing events
+// The caller closes the response body.
+func (c *Client) StreamPhotoEvents(ctx context.Context, restaurantID int) (*http.Response, error) {
+	resp, err := c.do(ctx, "GET", "/restaurants/"+url.PathEscape(fmt.Sprint(restaurantID))+"/photos/events", nil, nil, nil)
+	return resp, err
+}
+
+// UploadMenuPhotoFromURL calls POST /restaurants/{restaurantId}/photos/from-url: Upload a menu photo from a URL
+func (c *Client) UploadMenuPhotoFromURL(ctx context.Context, restaurantID int, body UploadPhotoFromURLRequest) (*UploadPhotoResponse, error) {
+	resp, err := c.do(ctx, "POST", "/restaurants/"+url.PathEscape(fmt.Sprint(restaurantID))+"/photos/from-url", nil, nil, jsonBody(body))
+	if err != nil {
+		return nil, err
+	}
+	var result UploadPhotoResponse
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ReorderMenuPhotos calls PATCH /restaurants/{restaurantId}/photos/order: Reorder menu photos
+func (c *Client) ReorderMenuPhotos(ctx context.Context, restaurantID int, body ReorderPhotosRequest) ([]MenuPhoto, error) {
+	resp, err := c.do(ctx, "PATCH", "/restaurants/"+url.PathEscape(fmt.Sprint(restaurantID))+"/photos/order", nil, nil, jsonBody(body))
+	if err != nil {
+		return nil, err
+	}
+	var result []MenuPhoto
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// PresignMenuPhotoUpload calls POST /restaurants/{restaurantId}/photos/presign: Request a presigned photo upload
+func (c *Client) PresignMenuPhotoUpload(ctx context.Context, restaurantID int, body PresignPhotoUploadRequest) (*PresignPhotoUploadResponse, error) {
+	resp, err := c.do(ctx, "POST", "/restaurants/"+url.PathEscape(fmt.Sprint(restaurantID))+"/photos/presign", nil, nil, jsonBody(body))
+	if err != nil {
+		return nil, err
+	}
+	var result PresignPhotoUploadResponse
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetRatings calls GET /restaurants/{restaurantId}/ratings: Get ratings for a restaurant
+func (c *Client) GetRatings(ctx context.Context, restaurantID int) ([]Rating, error) {
+	resp, err := c.do(ctx, "GET", "/restaurants/"+url.PathEscape(fmt.Sprint(restaurantID))+"/ratings", nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var result []Rating
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GlobalSearchParams are the query and header parameters of GlobalSearch
+type GlobalSearchParams struct {
+	// Search query string
+	Q string
+}
+
+// GlobalSearch calls GET /search: Global search for restaurants and suggestions
+func (c *Client) GlobalSearch(ctx context.Context, params *GlobalSearchParams) ([]Restaurant, error) {
+	query, header := url.Values{}, http.Header{}
+	if params != nil {
+		query.Set("q", fmt.Sprint(params.Q))
+	}
+	resp, err := c.do(ctx, "GET", "/search", query, header, nil)
+	if err != nil {
+		return nil, err
+	}
+	var result []Restaurant
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ListSpaces calls GET /spaces: List spaces
+func (c *Client) ListSpaces(ctx context.Context) ([]Space, error) {
+	resp, err := c.do(ctx, "GET", "/spaces", nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var result []Space
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// CreateSpace calls POST /spaces: Create a space
+func (c *Client) CreateSpace(ctx context.Context, body CreateSpaceRequest) (*Space, error) {
+	resp, err := c.do(ctx, "POST", "/spaces", nil, nil, jsonBody(body))
+	if err != nil {
+		return nil, err
+	}
+	var result Space
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// JoinSpace calls POST /spaces/join: Join a space
+func (c *Client) JoinSpace(ctx context.Context, body JoinSpaceRequest) (*Space, error) {
+	resp, err := c.do(ctx, "POST", "/spaces/join", nil, nil, jsonBody(body))
+	if err != nil {
+		return nil, err
+	}
+	var result Space
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetSpace calls GET /spaces/{slug}: Get a space
+func (c *Client) GetSpace(ctx context.Context, slug string) (*Space, error) {
+	resp, err := c.do(ctx, "GET", "/spaces/"+url.PathEscape(fmt.Sprint(slug)), nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var result Space
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// UpdateSpace calls PUT /spaces/{slug}: Update a space
+func (c *Client) UpdateSpace(ctx context.Context, slug string, body UpdateSpaceRequest) (*Space, error) {
+	resp, err := c.do(ctx, "PUT", "/spaces/"+url.PathEscape(fmt.Sprint(slug)), nil, nil, jsonBody(body))
+	if err != nil {
+		return nil, err
+	}
+	var result Space
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// DeleteSpace calls DELETE /spaces/{slug}: Delete a space
+func (c *Client) DeleteSpace(ctx context.Context, slug string) error {
+	resp, err := c.do(ctx, "DELETE", "/spaces/"+url.PathEscape(fmt.Sprint(slug)), nil, nil, nil)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// ListSpaceInvites calls GET /spaces/{slug}/invites: List space invites
+func (c *Client) ListSpaceInvites(ctx context.Context, slug string) ([]SpaceInvite, error) {
+	resp, err := c.do(ctx, "GET", "/spaces/"+url.PathEscape(fmt.Sprint(slug))+"/invites", nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var result []SpaceInvite
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// CreateSpaceInvite calls POST /spaces/{slug}/invites: Create a space invite
+func (c *Client) CreateSpaceInvite(ctx context.Context, slug string, body CreateSpaceInviteRequest) (*CreateSpaceInviteResponse, error) {
+	resp, err := c.do(ctx, "POST", "/spaces/"+url.PathEscape(fmt.Sprint(slug))+"/invites", nil, nil, jsonBody(body))
+	if err != nil {
+		return nil, err
+	}
+	var result CreateSpaceInviteResponse
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// RevokeSpaceInvite calls DELETE /spaces/{slug}/invites/{id}: Revoke a space invite
+func (c *Client) RevokeSpaceInvite(ctx context.Context, slug string, id int) error {
+	resp, err := c.do(ctx, "DELETE", "/spaces/"+url.PathEscape(fmt.Sprint(slug))+"/invites/"+url.PathEscape(fmt.Sprint(id)), nil, nil, nil)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// ListSpaceMembers calls GET /spaces/{slug}/members: List the members of a space
+func (c *Client) ListSpaceMembers(ctx context.Context, slug string) ([]SpaceMember, error) {
+	resp, err := c.do(ctx, "GET", "/spaces/"+url.PathEscape(fmt.Sprint(slug))+"/members", nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var result []SpaceMember
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// SetSpaceMember calls PUT /spaces/{slug}/members/{userId}: Add or change a member of a space
+func (c *Client) SetSpaceMember(ctx context.Context, slug string, userID int, body SpaceMemberRequest) (*SpaceMember, error) {
+	resp, err := c.do(ctx, "PUT", "/spaces/"+url.PathEscape(fmt.Sprint(slug))+"/members/"+url.PathEscape(fmt.Sprint(userID)), nil, nil, jsonBody(body))
+	if err != nil {
+		return nil, err
+	}
+	var result SpaceMember
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// RemoveSpaceMember calls DELETE /spaces/{slug}/members/{userId}: Remove a member from a space
+func (c *Client) RemoveSpaceMember(ctx context.Context, slug string, userID int) error {
+	resp, err := c.do(ctx, "DELETE", "/spaces/"+url.PathEscape(fmt.Sprint(slug))+"/members/"+url.PathEscape(fmt.Sprint(userID)), nil, nil, nil)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// GetSuggestionsParams are the query and header parameters of GetSuggestions
+type GetSuggestionsParams struct {
+	// Filter by status (pending, approved, tested, rejected)
+	Status *string
+}
+
+// GetSuggestions calls GET /suggestions: List all restaurant suggestions
+func (c *Client) GetSuggestions(ctx context.Context, params *GetSuggestionsParams) ([]RestaurantSuggestion, error) {
+	query, header := url.Values{}, http.Header{}
+	if params != nil {
+		if params.Status != nil {
+			query.Set("status", fmt.Sprint(*params.Status))
+		}
+	}
+	resp, err := c.do(ctx, "GET", "/suggestions", query, header, nil)
+	if err != nil {
+		return nil, err
+	}
+	var result []RestaurantSuggestion
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// CreateSuggestion calls POST /suggestions: Create a new restaurant suggestion
+func (c *Client) CreateSuggestion(ctx context.Context, body CreateSuggestionRequest) (*RestaurantSuggestion, error) {
+	resp, err := c.do(ctx, "POST", "/suggestions", nil, nil, jsonBody(body))
+	if err != nil {
+		return nil, err
+	}
+	var result RestaurantSuggestion
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetSuggestion calls GET /suggestions/{id}: Get a suggestion by ID
+func (c *Client) GetSuggestion(ctx context.Context, id int) (*RestaurantSuggestion, error) {
+	resp, err := c.do(ctx, "GET", "/suggestions/"+url.PathEscape(fmt.Sprint(id)), nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var result RestaurantSuggestion
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// DeleteSuggestion calls DELETE /suggestions/{id}: Delete a suggestion
+func (c *Client) DeleteSuggestion(ctx context.Context, id int) error {
+	resp, err := c.do(ctx, "DELETE", "/suggestions/"+url.PathEscape(fmt.Sprint(id)), nil, nil, nil)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// GetSuggestionApprovals calls GET /suggestions/{id}/approvals: Get suggestion approvals
+func (c *Client) GetSuggestionApprovals(ctx context.Context, id int) (*SuggestionApprovalStatus, error) {
+	resp, err := c.do(ctx, "GET", "/suggestions/"+url.PathEscape(fmt.Sprint(id))+"/approvals", nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var result SuggestionApprovalStatus
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ApproveSuggestion calls POST /suggestions/{id}/approvals: Approve a suggestion
+func (c *Client) ApproveSuggestion(ctx context.Context, id int, body ApproveSuggestionRequest) (*SuggestionApprovalStatus, error) {
+	resp, err := c.do(ctx, "POST", "/suggestions/"+url.PathEscape(fmt.Sprint(id))+"/approvals", nil, nil, jsonBody(body))
+	if err != nil {
+		return nil, err
+	}
+	var result SuggestionApprovalStatus
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// RevokeSuggestionApproval calls DELETE /suggestions/{id}/approvals: Revoke a suggestion approval
+func (c *Client) RevokeSuggestionApproval(ctx context.Context, id int) (*SuggestionApprovalStatus, error) {
+	resp, err := c.do(ctx, "DELETE", "/suggestions/"+url.PathEscape(fmt.Sprint(id))+"/approvals", nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var result SuggestionApprovalStatus
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ConvertSuggestion calls POST /suggestions/{id}/convert: Convert suggestion to restaurant
+func (c *Client) ConvertSuggestion(ctx context.Context, id int, body ConvertSuggestionRequest) (map[string]any, error) {
+	resp, err := c.do(ctx, "POST", "/suggestions/"+url.PathEscape(fmt.Sprint(id))+"/convert", nil, nil, jsonBody(body))
+	if err != nil {
+		return nil, err
+	}
+	var result map[string]any
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetSuggestionEvents calls GET /suggestions/{id}/events: Get suggestion activity log
+func (c *Client) GetSuggestionEvents(ctx context.Context, id int) ([]SuggestionEvent, error) {
+	resp, err := c.do(ctx, "GET", "/suggestions/"+url.PathEscape(fmt.Sprint(id))+"/events", nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var result []SuggestionEvent
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// UpdateSuggestionStatus calls PATCH /suggestions/{id}/status: Update suggestion status
+func (c *Client) UpdateSuggestionStatus(ctx context.Context, id int, body UpdateSuggestionStatusRequest) (*RestaurantSuggestion, error) {
+	resp, err := c.do(ctx, "PATCH", "/suggestions/"+url.PathEscape(fmt.Sprint(id))+"/status", nil, nil, jsonBody(body))
+	if err != nil {
+		return nil, err
+	}
+	var result RestaurantSuggestion
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// FollowUser calls POST /users/{id}/follow: Follow a user
+func (c *Client) FollowUser(ctx context.Context, id int) error {
+	resp, err := c.do(ctx, "POST", "/users/"+url.PathEscape(fmt.Sprint(id))+"/follow", nil, nil, nil)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// UnfollowUser calls DELETE /users/{id}/follow: Unfollow a user
+func (c *Client) UnfollowUser(ctx context.Context, id int) error {
+	resp, err := c.do(ctx, "DELETE", "/users/"+url.PathEscape(fmt.Sprint(id))+"/follow", nil, nil, nil)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// GetUserStats calls GET /users/{id}/stats: Get the contribution stats of a user
+func (c *Client) GetUserStats(ctx context.Context, id int) (*UserStats, error) {
+	resp, err := c.do(ctx, "GET", "/users/"+url.PathEscape(fmt.Sprint(id))+"/stats", nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var result UserStats
+	if err := decode(resp, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
@@ -0,0 +1,84 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	c := NewClient(server.URL + "/api/")
+	c.RequestEditor = WithAPIKey("nomdb_test")
+	return c
+}
+
+func TestGetCategory(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/categories/7" || r.Header.Get("X-API-Key") != "nomdb_test" {
+			t.Errorf("Unexpected request %s %v", r.URL.Path, r.Header)
+		}
+		json.NewEncoder(w).Encode(map[string]any{"id": 7, "name": "Italian"})
+	})
+
+	category, err := c.GetCategory(context.Background(), 7)
+	if err != nil {
+		t.Fatalf("GetCategory failed: %v", err)
+	}
+	if *category.ID != 7 || *category.Name != "Italian" {
+		t.Errorf("Unexpected category %+v", category)
+	}
+}
+
+func TestError(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/problem+json")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"status":404,"detail":"Category not found","code":"NOT_FOUND"}`))
+	})
+
+	_, err := c.GetCategory(context.Background(), 8)
+	var apiErr *Error
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Expected an *Error, got %v", err)
+	}
+	if apiErr.StatusCode != http.StatusNotFound || *apiErr.Problem.Code != "NOT_FOUND" {
+		t.Errorf("Unexpected error %+v", apiErr)
+	}
+	if apiErr.Error() != "404 Not Found: Category not found" {
+		t.Errorf("Unexpected message %q", apiErr.Error())
+	}
+}
+
+func TestUploadMenuPhoto(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		file, header, err := r.FormFile("photo")
+		if err != nil {
+			t.Fatalf("Expected a photo: %v", err)
+		}
+		content, _ := io.ReadAll(file)
+		if header.Filename != "menu.jpg" || string(content) != "jpeg" || r.FormValue("caption") != "Lunch" {
+			t.Errorf("Unexpected form %q %q %q", header.Filename, content, r.FormValue("caption"))
+		}
+		if _, ok := r.MultipartForm.Value["photo_type"]; ok {
+			t.Error("Expected unset fields to be left out")
+		}
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte(`{"message":"Processing"}`))
+	})
+
+	_, err := c.UploadMenuPhoto(context.Background(), 3, UploadMenuPhotoForm{
+		Caption: "Lunch",
+		Photo:   &FormFile{Name: "menu.jpg", Content: strings.NewReader("jpeg")},
+	})
+	if err != nil {
+		t.Fatalf("UploadMenuPhoto failed: %v", err)
+	}
+}
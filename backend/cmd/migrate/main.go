@@ -1,44 +1,92 @@
 package main
 
 import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"flag"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
 	"strconv"
+	"strings"
+	"text/tabwriter"
 	"time"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/nomdb/backend/db"
+	"github.com/nomdb/backend/internal/backup"
 	"github.com/nomdb/backend/internal/database"
 	"github.com/nomdb/backend/internal/logger"
 )
 
+// Exit codes
+const (
+	exitOK      = 0
+	exitFailure = 1
+	exitUsage   = 2
+)
+
+// defaultSeedAdminPassword is the demo admin password unless SEED_ADMIN_PASSWORD is set
+const defaultSeedAdminPassword = "nomdb-demo-admin"
+
 func main() {
-	if len(os.Args) < 2 {
+	os.Exit(run(os.Args[1:]))
+}
+
+func run(args []string) int {
+	flags := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	flags.Usage = printUsage
+	databaseURLFlag := flags.String("database-url", "", "Database URL (overrides DATABASE_URL)")
+	yes := flags.Bool("yes", false, "Do not ask for confirmation before restoring")
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+	args = flags.Args()
+
+	if len(args) < 1 {
 		printUsage()
-		os.Exit(1)
+		return exitUsage
 	}
+	command := args[0]
 
-	databaseURL := os.Getenv("DATABASE_URL")
-	if databaseURL == "" {
-		logger.Fatal("DATABASE_URL environment variable is required")
+	// Migrations are embedded; only create writes to the source tree
+	if command == "create" {
+		if len(args) < 2 {
+			logger.Error("Migration name required for create command")
+			return exitUsage
+		}
+		return createMigration(filepath.Join("db", db.MigrationsDir), args[1])
 	}
 
-	migrationsPath := "./db/migrations_new"
-	command := os.Args[1]
+	databaseURL := *databaseURLFlag
+	if databaseURL == "" {
+		databaseURL = os.Getenv("DATABASE_URL")
+	}
+	if databaseURL == "" {
+		logger.Error("DATABASE_URL environment variable or --database-url flag is required")
+		return exitUsage
+	}
 
 	switch command {
 	case "up":
-		if err := database.RunMigrations(databaseURL, migrationsPath); err != nil {
-			logger.Fatal("Migration failed: %v", err)
+		if err := database.RunMigrations(databaseURL); err != nil {
+			logger.Error("Migration failed: %v", err)
+			return exitFailure
 		}
 
 	case "down":
-		if err := database.MigrateDown(databaseURL, migrationsPath); err != nil {
-			logger.Fatal("Rollback failed: %v", err)
+		if err := database.MigrateDown(databaseURL); err != nil {
+			logger.Error("Rollback failed: %v", err)
+			return exitFailure
 		}
 
 	case "version":
-		version, dirty, err := database.MigrateVersion(databaseURL, migrationsPath)
+		version, dirty, err := database.MigrateVersion(databaseURL)
 		if err != nil {
-			logger.Fatal("Failed to get version: %v", err)
+			logger.Error("Failed to get version: %v", err)
+			return exitFailure
 		}
 		dirtyStatus := ""
 		if dirty {
@@ -46,64 +94,259 @@ func main() {
 		}
 		logger.Info("Current migration version: %d%s", version, dirtyStatus)
 
-	case "force":
-		if len(os.Args) < 3 {
-			logger.Fatal("Version number required for force command")
+	case "status":
+		statuses, err := database.MigrationStatuses(databaseURL)
+		if err != nil {
+			logger.Error("Failed to get migration status: %v", err)
+			return exitFailure
+		}
+		printStatus(statuses)
+
+	case "goto":
+		if len(args) < 2 {
+			logger.Error("Version number required for goto command")
+			return exitUsage
 		}
-		version, err := strconv.Atoi(os.Args[2])
+		version, err := strconv.ParseUint(args[1], 10, 0)
 		if err != nil {
-			logger.Fatal("Invalid version number: %v", err)
+			logger.Error("Invalid version number: %v", err)
+			return exitUsage
+		}
+		if err := database.MigrateTo(databaseURL, uint(version)); err != nil {
+			logger.Error("Migration failed: %v", err)
+			return exitFailure
+		}
+
+	case "steps":
+		if len(args) < 2 {
+			logger.Error("Number of steps required for steps command")
+			return exitUsage
+		}
+		n, err := strconv.Atoi(args[1])
+		if err != nil || n == 0 {
+			logger.Error("Invalid number of steps: %s", args[1])
+			return exitUsage
+		}
+		if err := database.MigrateSteps(databaseURL, n); err != nil {
+			logger.Error("Migration failed: %v", err)
+			return exitFailure
+		}
+
+	case "redo":
+		if err := database.MigrateRedo(databaseURL); err != nil {
+			logger.Error("Redo failed: %v", err)
+			return exitFailure
+		}
+
+	case "seed":
+		environment := "development"
+		if len(args) > 1 {
+			environment = args[1]
+		}
+		adminPassword := os.Getenv("SEED_ADMIN_PASSWORD")
+		if adminPassword == "" {
+			adminPassword = defaultSeedAdminPassword
+		}
+		if err := database.Seed(databaseURL, environment, adminPassword); err != nil {
+			logger.Error("Seeding failed: %v", err)
+			return exitFailure
+		}
+		logger.Info("✓ Seeded %s fixtures", environment)
+
+	case "backup":
+		if len(args) < 2 {
+			logger.Error("Output file required for backup command")
+			return exitUsage
+		}
+		if err := backupToFile(databaseURL, args[1]); err != nil {
+			logger.Error("Backup failed: %v", err)
+			return exitFailure
+		}
+
+	case "restore":
+		if len(args) < 2 {
+			logger.Error("Backup file required for restore command")
+			return exitUsage
 		}
-		if err := database.MigrateForce(databaseURL, migrationsPath, version); err != nil {
-			logger.Fatal("Force migration failed: %v", err)
+		if !*yes && !confirm("This replaces ALL data in the database with the backup. Continue?") {
+			logger.Info("Restore cancelled")
+			return exitFailure
+		}
+		if err := restoreFromFile(databaseURL, args[1]); err != nil {
+			logger.Error("Restore failed: %v", err)
+			return exitFailure
 		}
 
-	case "create":
-		if len(os.Args) < 3 {
-			logger.Fatal("Migration name required for create command")
+	case "force":
+		if len(args) < 2 {
+			logger.Error("Version number required for force command")
+			return exitUsage
+		}
+		version, err := strconv.Atoi(args[1])
+		if err != nil {
+			logger.Error("Invalid version number: %v", err)
+			return exitUsage
+		}
+		if err := database.MigrateForce(databaseURL, version); err != nil {
+			logger.Error("Force migration failed: %v", err)
+			return exitFailure
 		}
-		name := os.Args[2]
-		createMigration(migrationsPath, name)
 
 	default:
-		logger.Fatal("Unknown command: %s", command)
+		logger.Error("Unknown command: %s", command)
 		printUsage()
+		return exitUsage
 	}
+
+	return exitOK
+}
+
+func printStatus(statuses []database.MigrationStatus) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+	for _, s := range statuses {
+		state := "pending"
+		switch {
+		case s.Dirty:
+			state = "dirty"
+		case s.Applied:
+			state = "applied"
+		}
+		appliedAt := "-"
+		if s.AppliedAt != nil {
+			appliedAt = s.AppliedAt.Local().Format(time.RFC3339)
+		} else if s.Applied {
+			appliedAt = "unknown"
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", s.Version, s.Name, state, appliedAt)
+	}
+	w.Flush()
+}
+
+// backupToFile writes a gzip-compressed backup of the database to path
+func backupToFile(databaseURL, path string) error {
+	ctx := context.Background()
+	conn, err := pgx.Connect(ctx, databaseURL)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer conn.Close(ctx)
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	gz := gzip.NewWriter(f)
+	stats, err := backup.Export(ctx, conn, gz)
+	if err != nil {
+		os.Remove(path)
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	logger.Info("✓ Backed up %d rows from %d tables at schema version %d to %s", stats.Rows, len(stats.Tables), stats.SchemaVersion, path)
+	return nil
+}
+
+// restoreFromFile replaces the data of the database with the backup at path ("-" for
+// standard input), plain or gzip-compressed
+func restoreFromFile(databaseURL, path string) error {
+	var r io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	ctx := context.Background()
+	conn, err := pgx.Connect(ctx, databaseURL)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer conn.Close(ctx)
+
+	stats, err := backup.Restore(ctx, conn, r)
+	if err != nil {
+		return err
+	}
+
+	logger.Info("✓ Restored %d rows into %d tables at schema version %d", stats.Rows, len(stats.Tables), stats.SchemaVersion)
+	return nil
+}
+
+// confirm asks a yes/no question on the terminal
+func confirm(question string) bool {
+	fmt.Printf("%s [y/N] ", question)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
 }
 
-func createMigration(path, name string) {
+func createMigration(path, name string) int {
 	timestamp := time.Now().Unix()
 	upFile := fmt.Sprintf("%s/%d_%s.up.sql", path, timestamp, name)
 	downFile := fmt.Sprintf("%s/%d_%s.down.sql", path, timestamp, name)
 
 	// Create up migration
 	if err := os.WriteFile(upFile, []byte("-- Write your migration here\n"), 0644); err != nil {
-		logger.Fatal("Failed to create up migration: %v", err)
+		logger.Error("Failed to create up migration: %v", err)
+		return exitFailure
 	}
 
 	// Create down migration
 	if err := os.WriteFile(downFile, []byte("-- Write your rollback here\n"), 0644); err != nil {
-		logger.Fatal("Failed to create down migration: %v", err)
+		logger.Error("Failed to create down migration: %v", err)
+		return exitFailure
 	}
 
 	logger.Info("✓ Created migration files:")
 	logger.Info("  %s", upFile)
 	logger.Info("  %s", downFile)
+	return exitOK
 }
 
 func printUsage() {
 	fmt.Println("Database Migration Tool")
 	fmt.Println("\nUsage:")
-	fmt.Println("  go run cmd/migrate/main.go <command> [arguments]")
+	fmt.Println("  go run cmd/migrate/main.go [--database-url <url>] <command> [arguments]")
 	fmt.Println("\nCommands:")
 	fmt.Println("  up              Run all pending migrations")
 	fmt.Println("  down            Rollback the last migration")
+	fmt.Println("  status          List applied and pending migrations")
 	fmt.Println("  version         Show current migration version")
+	fmt.Println("  goto <version>  Migrate up or down to a version")
+	fmt.Println("  steps <n>       Apply n migrations, or roll back n if negative")
+	fmt.Println("  redo            Roll back and reapply the last migration")
 	fmt.Println("  force <version> Force set migration version (use with caution)")
+	fmt.Println("  seed [env]      Load seed fixtures for development (default), ci or production")
+	fmt.Println("  backup <file>   Write a gzip-compressed backup of all data to a file")
+	fmt.Println("  restore <file>  Replace all data with a backup file (\"-\" for stdin)")
 	fmt.Println("  create <name>   Create a new migration file")
+	fmt.Println("\nFlags:")
+	fmt.Println("  --database-url  Database URL, overrides DATABASE_URL")
+	fmt.Println("  --yes           Restore without asking for confirmation")
+	fmt.Println("\nExit codes:")
+	fmt.Println("  0  Success")
+	fmt.Println("  1  Migration failed")
+	fmt.Println("  2  Invalid usage")
 	fmt.Println("\nExamples:")
 	fmt.Println("  go run cmd/migrate/main.go up")
 	fmt.Println("  go run cmd/migrate/main.go down")
+	fmt.Println("  go run cmd/migrate/main.go status")
+	fmt.Println("  go run cmd/migrate/main.go goto 20")
+	fmt.Println("  go run cmd/migrate/main.go steps -2")
+	fmt.Println("  go run cmd/migrate/main.go seed ci")
+	fmt.Println("  go run cmd/migrate/main.go --yes restore nomdb-20261015T020000Z.jsonl.gz")
 	fmt.Println("  go run cmd/migrate/main.go create add_user_table")
 	fmt.Println("  go run cmd/migrate/main.go force 3")
 }
@@ -0,0 +1,118 @@
+// Command openapi generates the OpenAPI 3 document of the API from the router and the
+// handler annotations, and the TypeScript and Go clients from the document. Run it from
+// the backend directory (make openapi):
+//
+//	go run ./cmd/openapi          # Write the document and the clients
+//	go run ./cmd/openapi -check   # Fail when they are out of date (CI)
+//
+// Routes and annotations that disagree are reported; -strict makes that an error.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/gorilla/mux"
+	"github.com/nomdb/backend/internal/logger"
+	"github.com/nomdb/backend/internal/openapi"
+	"github.com/nomdb/backend/internal/routes"
+)
+
+// Exit codes
+const (
+	exitOK      = 0
+	exitFailure = 1
+	exitUsage   = 2
+)
+
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+func run(args []string) int {
+	flags := flag.NewFlagSet("openapi", flag.ContinueOnError)
+	specPath := flags.String("spec", "docs/openapi.json", "Output path of the OpenAPI document")
+	tsPath := flags.String("ts", "../clients/typescript/src/index.ts", "Output path of the TypeScript client")
+	goPath := flags.String("go", "client/client.gen.go", "Output path of the Go client")
+	check := flags.Bool("check", false, "Fail if the outputs are out of date instead of writing them")
+	strict := flags.Bool("strict", false, "Fail if routes and annotations disagree")
+	if err := flags.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	annotations, err := openapi.ParseAnnotations(".", "cmd/server/main.go", "internal/handlers")
+	if err != nil {
+		logger.Error("Failed to parse annotations: %v", err)
+		return exitFailure
+	}
+	// Debug endpoints are optional and admin-only; they are not part of the client
+	r := mux.NewRouter()
+	routes.Register(r, routes.Options{})
+	doc, drift, err := openapi.Build(r, annotations)
+	if err != nil {
+		logger.Error("Failed to build the OpenAPI document: %v", err)
+		return exitFailure
+	}
+	for _, d := range drift {
+		logger.Warn("⚠️  %s", d)
+	}
+
+	spec, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		logger.Error("Failed to encode the OpenAPI document: %v", err)
+		return exitFailure
+	}
+	goClient, err := openapi.Go(doc, filepath.Base(filepath.Dir(*goPath)))
+	if err != nil {
+		logger.Error("Failed to generate the Go client: %v", err)
+		return exitFailure
+	}
+	outputs := []struct {
+		path    string
+		content []byte
+	}{
+		{*specPath, append(spec, '\n')},
+		{*tsPath, openapi.TypeScript(doc)},
+		{*goPath, goClient},
+	}
+
+	status := exitOK
+	for _, out := range outputs {
+		if *check {
+			current, err := os.ReadFile(out.path)
+			if err != nil || !bytes.Equal(current, out.content) {
+				logger.Error("%s is out of date; run make openapi", out.path)
+				status = exitFailure
+			}
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(out.path), 0o755); err != nil {
+			logger.Error("Failed to create %s: %v", filepath.Dir(out.path), err)
+			return exitFailure
+		}
+		if err := os.WriteFile(out.path, out.content, 0o644); err != nil {
+			logger.Error("Failed to write %s: %v", out.path, err)
+			return exitFailure
+		}
+		logger.Info("✅ Wrote %s", out.path)
+	}
+
+	if *strict && len(drift) > 0 {
+		logger.Error("%d routes and annotations disagree", len(drift))
+		return exitFailure
+	}
+	fmt.Printf("%d operations, %d schemas, %d disagreements\n", countOperations(doc), len(doc.Components.Schemas), len(drift))
+	return status
+}
+
+func countOperations(doc *openapi.Document) int {
+	n := 0
+	for _, item := range doc.Paths {
+		n += len(item)
+	}
+	return n
+}
@@ -1,21 +1,39 @@
 package main
 
 import (
+	"context"
+	"crypto/tls"
 	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
 	"net/http"
 	"os"
+	"os/signal"
+	"slices"
+	"syscall"
+	"text/tabwriter"
 	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/nomdb/backend/internal/cache"
 	"github.com/nomdb/backend/internal/config"
 	"github.com/nomdb/backend/internal/database"
+	"github.com/nomdb/backend/internal/events"
+	"github.com/nomdb/backend/internal/frontend"
 	"github.com/nomdb/backend/internal/handlers"
 	"github.com/nomdb/backend/internal/logger"
 	"github.com/nomdb/backend/internal/middleware"
+	"github.com/nomdb/backend/internal/models"
+	"github.com/nomdb/backend/internal/repository"
+	"github.com/nomdb/backend/internal/routes"
 	"github.com/nomdb/backend/internal/services"
+	"github.com/nomdb/backend/internal/storage"
+	"github.com/nomdb/backend/internal/tracing"
 	"github.com/rs/cors"
 	httpSwagger "github.com/swaggo/http-swagger"
-	"golang.org/x/time/rate"
+	"golang.org/x/crypto/acme"
+	"golang.org/x/crypto/acme/autocert"
 
 	_ "github.com/nomdb/backend/docs" // Import generated docs
 )
@@ -39,9 +57,15 @@ import (
 //
 // @schemes http https
 //
-// @securityDefinitions.apikey ApiKeyAuth
+// @securityDefinitions.apikey BearerAuth
 // @in header
 // @name Authorization
+// @description Access token as "Bearer <token>"
+//
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name X-API-Key
+// @description API key of a service account
 //
 // @tag.name Restaurants
 // @tag.description Restaurant management endpoints
@@ -67,230 +91,449 @@ import (
 // @tag.name Search
 // @tag.description Global search functionality
 //
+// @tag.name Spaces
+// @tag.description Spaces, their members and invites
+//
 // @tag.name Health
 // @tag.description Health check endpoints
 func main() {
+	configFile := flag.String("config", os.Getenv("CONFIG_FILE"), "YAML or TOML config file; environment variables take precedence")
+	validateConfig := flag.Bool("validate-config", false, "print the effective configuration, secrets redacted, and exit")
+	check := flag.Bool("check", false, "check the database, schema version, storage and Google Maps key, print the report and exit (1 if a check fails)")
+	flag.Parse()
+
+	// Load and validate configuration
+	cfg, err := config.LoadFile(*configFile)
+	if *validateConfig {
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		printEffectiveConfig(os.Stdout, cfg)
+		return
+	}
+	if err != nil {
+		logger.Fatal("Configuration error: %v", err)
+	}
+	logger.Configure(cfg.Debug, cfg.LogFormat)
+	logger.SetDebugSampling(cfg.LogDebugSample)
+	if err := logger.SetLevels(cfg.LogLevel, cfg.LogModuleLevels); err != nil {
+		logger.Fatal("Configuration error: %v", err)
+	}
+
 	logger.Info("🚀 Starting The Nom Database server...")
 	if logger.IsDebugMode() {
 		logger.Debug("🐛 Debug mode enabled - detailed logging active")
 	}
+	logger.SetSlowThresholds(cfg.SlowRequestThreshold, cfg.SlowQueryThreshold)
 
-	// Load and validate configuration
-	cfg, err := config.Load()
+	// Set up tracing before the database, so the pool's queries are traced
+	shutdownTracing, err := tracing.Init(context.Background(), tracing.Config{
+		Enabled:     cfg.TracingEnabled,
+		ServiceName: cfg.OTelServiceName,
+	})
 	if err != nil {
-		logger.Fatal("Configuration error: %v", err)
+		logger.Fatal("Failed to initialize tracing: %v", err)
 	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			logger.Error("Failed to flush traces: %v", err)
+		}
+	}()
 
 	// Connect to database
-	if err := database.Connect(); err != nil {
+	if err := database.Connect(cfg.DatabaseURL, database.PoolConfig{
+		MaxConns:          cfg.DBMaxConns,
+		MinConns:          cfg.DBMinConns,
+		MaxConnLifetime:   cfg.DBMaxConnLifetime,
+		MaxConnIdleTime:   cfg.DBMaxConnIdleTime,
+		HealthCheckPeriod: cfg.DBHealthCheckPeriod,
+	}); err != nil {
 		logger.Fatal("Failed to connect to database: %v", err)
 	}
 	defer database.Close()
 
-	// Run database migrations
-	databaseURL := os.Getenv("DATABASE_URL")
-	migrationsPath := "/app/db/migrations_new"
-	if _, err := os.Stat("./db/migrations_new"); err == nil {
-		// Local development
-		migrationsPath = "./db/migrations_new"
+	// Run database migrations; replicas starting together take turns under an advisory lock
+	// A check reports pending migrations instead of running them
+	if cfg.RunMigrationsOnStart && !*check {
+		if err := database.RunMigrations(cfg.DatabaseURL); err != nil {
+			logger.Fatal("Failed to run migrations: %v", err)
+		}
+	} else {
+		logger.Info("Skipping migrations on startup (RUN_MIGRATIONS_ON_START=false)")
+	}
+
+	// The readiness check expects the schema to stay at the version just migrated to
+	migrationVersion, _, err := database.MigrateVersion(cfg.DatabaseURL)
+	if err != nil {
+		logger.Fatal("Failed to read migration version: %v", err)
+	}
+	handlers.InitHealthChecks(migrationVersion, cfg.HealthCheckStorage, cfg.HealthCheckGoogleMaps)
+
+	// Restaurant, rating and suggestion handlers go through the repository layer
+	handlers.InitRepositories(repository.New(database.GetPool()))
+
+	// Google Maps calls time out, retry transient failures and fail fast during outages
+	handlers.InitGoogleMaps(services.GoogleMapsOptions{
+		APIKey:           cfg.GoogleMapsAPIKey,
+		Timeout:          cfg.GoogleMapsTimeout,
+		MaxRetries:       cfg.GoogleMapsMaxRetries,
+		RetryDelay:       services.DefaultGoogleMapsOptions.RetryDelay,
+		BreakerThreshold: cfg.GoogleMapsBreakerThreshold,
+		BreakerCooldown:  cfg.GoogleMapsBreakerCooldown,
+		PlacesAPI:        cfg.GooglePlacesAPI,
+		SigningSecret:    cfg.GoogleMapsSigningSecret,
+		SearchType:       cfg.GooglePlacesSearchType,
+		SearchKeyword:    cfg.GooglePlacesSearchKeyword,
+		DailyBudget:      cfg.GoogleMapsDailyBudget,
+	})
+	// Self-hosters without a Google billing account can search OpenStreetMap instead
+	if cfg.PlacesProvider == services.PlacesProviderNominatim {
+		handlers.InitPlacesProvider(services.NewNominatimProvider(services.NominatimOptions{
+			BaseURL:     cfg.NominatimURL,
+			Email:       cfg.NominatimEmail,
+			MinInterval: cfg.NominatimMinInterval,
+			Timeout:     cfg.GoogleMapsTimeout,
+		}))
+	}
+
+	// Restaurants can be looked up on a review site for external ratings
+	switch cfg.EnrichmentProvider {
+	case services.EnrichmentProviderYelp:
+		handlers.InitEnrichmentProvider(services.NewYelpProvider(cfg.YelpAPIKey, cfg.GoogleMapsTimeout))
+	case services.EnrichmentProviderFoursquare:
+		handlers.InitEnrichmentProvider(services.NewFoursquareProvider(cfg.FoursquareAPIKey, cfg.GoogleMapsTimeout))
+	}
+
+	// Initialize photo storage (local disk unless a cloud backend is configured)
+	if err := storage.Init(storage.Config{
+		Backend:            cfg.StorageBackend,
+		LocalDir:           cfg.LocalStorageDir,
+		LocalURLPrefix:     "/api/uploads",
+		S3AccessKeyID:      cfg.AWSAccessKeyID,
+		S3SecretAccessKey:  cfg.AWSSecretAccessKey,
+		S3Region:           cfg.AWSRegion,
+		S3Bucket:           cfg.S3BucketName,
+		S3Endpoint:         cfg.S3Endpoint,
+		S3UsePathStyle:     cfg.S3UsePathStyle,
+		GCSAccessKeyID:     cfg.GCSHMACAccessKey,
+		GCSSecretAccessKey: cfg.GCSHMACSecret,
+		GCSBucket:          cfg.GCSBucketName,
+		AzureAccountName:   cfg.AzureStorageAccount,
+		AzureAccountKey:    cfg.AzureStorageKey,
+		AzureContainer:     cfg.AzureStorageContainer,
+		AzureEndpoint:      cfg.AzureStorageEndpoint,
+	}); err != nil {
+		logger.Fatal("Failed to initialize storage: %v", err)
+	}
+
+	// Verify the dependencies before accepting traffic
+	if *check || cfg.Preflight != config.PreflightOff {
+		passed := reportPreflight(handlers.Preflight(context.Background()))
+		if *check {
+			if !passed {
+				os.Exit(1)
+			}
+			return
+		}
+		if !passed && cfg.Preflight == config.PreflightStrict {
+			logger.Fatal("Preflight checks failed (PREFLIGHT=strict)")
+		}
+	}
+
+	// Initialize the response cache (in process unless Redis is configured)
+	if err := cache.Init(cache.Config{
+		Backend:  cfg.CacheBackend,
+		TTL:      cfg.CacheTTL,
+		RedisURL: cfg.RedisURL,
+	}); err != nil {
+		logger.Fatal("Failed to initialize cache: %v", err)
+	}
+	services.InitPlacesCache(cfg.PlacesSearchCacheTTL, cfg.PlaceDetailsCacheTTL)
+
+	// Replicas share their rate limit buckets in Redis
+	if cfg.ClusterMode {
+		logger.Info("🌐 Cluster mode: running as instance %s", cfg.InstanceName)
+	}
+	if cfg.RateLimitStore == middleware.RateLimitStoreRedis {
+		client, err := cache.NewRedisClient(cfg.RedisURL)
+		if err != nil {
+			logger.Fatal("Failed to initialize rate limit store: %v", err)
+		}
+		middleware.SetRateLimitStore(middleware.NewRedisRateLimitStore(client))
 	}
-	if err := database.RunMigrations(databaseURL, migrationsPath); err != nil {
-		logger.Fatal("Failed to run migrations: %v", err)
+
+	// Share changes with the other instances: cache invalidations and live updates
+	if err := events.Init(events.Config{
+		Backend: cfg.EventsBackend,
+		Pool:    database.GetPool(),
+	}); err != nil {
+		logger.Fatal("Failed to initialize event bus: %v", err)
+	}
+	handlers.InitLiveUpdates()
+	handlers.InitLogLevels()
+
+	// Queue events for webhooks and deliver them with retries
+	handlers.InitWebhooks(services.NewWebhookSender(cfg.WebhookTimeout), cfg.WebhookTimeout, cfg.WebhookMaxAttempts, cfg.WebhookPollInterval)
+
+	// Post suggestions and first ratings to the group chat
+	var notifiers []services.Notifier
+	if cfg.NotifyDiscordWebhookURL != "" {
+		notifiers = append(notifiers, services.NewDiscordNotifier(cfg.NotifyDiscordWebhookURL, cfg.WebhookTimeout))
 	}
+	if cfg.NotifySlackWebhookURL != "" {
+		notifiers = append(notifiers, services.NewSlackNotifier(cfg.NotifySlackWebhookURL, cfg.WebhookTimeout))
+	}
+	handlers.InitNotifications(notifiers, cfg.NotifyEvents, cfg.NotifyAppURL)
 
-	// Initialize Google Maps service
-	_ = services.NewGoogleMapsService()
+	// Award badges for contribution milestones
+	handlers.InitBadges()
 
-	// Initialize S3 service (optional - falls back to local storage if not configured)
-	if err := services.InitS3(); err != nil {
-		logger.Debug("S3 initialization skipped: %v", err)
+	// Alert operators of panics
+	var alerters []services.Alerter
+	if cfg.PanicAlertWebhookURL != "" {
+		alerters = append(alerters, services.NewWebhookAlerter(cfg.PanicAlertWebhookURL, cfg.WebhookTimeout))
+	}
+	if cfg.SentryDSN != "" {
+		sentry, err := services.NewSentryAlerter(cfg.SentryDSN, cfg.SentryEnvironment, cfg.WebhookTimeout)
+		if err != nil {
+			logger.Fatal("Failed to configure Sentry: %v", err)
+		}
+		alerters = append(alerters, sentry)
+	}
+	if len(alerters) > 0 {
+		middleware.SetPanicAlerter(func(p middleware.PanicReport) { alertPanic(alerters, p) }, cfg.PanicAlertInterval)
+		logger.Info("🚨 Panic alerts enabled (at most one per panic every %v)", cfg.PanicAlertInterval)
 	}
 
 	// Initialize authentication
-	jwtSvc := handlers.InitAuthService()
+	jwtSvc := handlers.InitAuthService(cfg.JWTSecretKey, cfg.AuthMode, cfg.AccessTokenTTL, cfg.RefreshTokenTTL, cfg.RememberMeTTL)
+	handlers.InitCursorSigning(cfg.JWTSecretKey)
 
 	// Initialize OIDC (optional)
-	if err := handlers.InitOIDC(); err != nil {
-		logger.Warn("OIDC initialization skipped: %v", err)
+	if err := handlers.InitOIDC(cfg.OAuthProviders, cfg.OIDCStateStore); err != nil {
+		logger.Warn("OIDC initialization incomplete: %v", err)
 	}
 
+	// Configure who may create accounts
+	handlers.InitRegistration(cfg.RegistrationMode, cfg.InviteURL)
+	handlers.InitEmailValidation(services.NewEmailValidator(services.EmailValidationConfig{
+		BlockDisposable: cfg.EmailBlockDisposable,
+		BlockedDomains:  cfg.EmailBlockedDomains,
+		CheckMX:         cfg.EmailMXCheck,
+	}))
+
+	// Configure the browser handoff of OIDC logins
+	handlers.InitAuthCookies(cfg.OIDCFrontendRedirectURL, cfg.AuthCookieSecure, cfg.AuthCookieSameSite)
+
 	// Initialize auth middleware
-	middleware.InitAuthMiddleware(jwtSvc)
+	middleware.InitAuthMiddleware(jwtSvc, cfg.AuthMode)
+	middleware.InitAPIKeyAuth(cfg.APIKeyRateLimit, cfg.ServiceAccountRateLimit)
+
+	// Configure brute-force protection for password logins
+	handlers.InitLoginLockout(cfg.LoginMaxAttempts, cfg.LoginIPMaxAttempts, cfg.LoginLockoutDuration)
+
+	// Purge self-deleted accounts once their grace period ends
+	handlers.InitAccountDeletion(cfg.AccountDeletionGracePeriod)
+
+	// Configure password reset emails (logged only when SMTP is not configured)
+	handlers.InitPasswordReset(services.NewEmailSender(services.EmailConfig{
+		SMTPHost:     cfg.SMTPHost,
+		SMTPPort:     cfg.SMTPPort,
+		SMTPUsername: cfg.SMTPUsername,
+		SMTPPassword: cfg.SMTPPassword,
+		From:         cfg.SMTPFrom,
+	}), cfg.PasswordResetURL, cfg.PasswordResetTokenTTL)
+
+	// Configure suggestion review workflow
+	handlers.InitSuggestionWorkflow(cfg.SuggestionApprovalMode)
+
+	// Configure photo variant formats, upload quotas, storage cleanup and background processing
+	handlers.InitPhotoVariants(cfg.ImageAVIFEnabled)
+	handlers.InitPhotoLimits(cfg.PhotoDailyUploadLimit)
+	handlers.InitHTTPCache(cfg.HTTPCacheMaxAge)
+	handlers.InitMapClustering(cfg.MapClusterThreshold, cfg.MapClusterGridSize)
+	handlers.StartPhotoCleanupJob(cfg.PhotoCleanupInterval)
+	handlers.StartBackupJob(cfg.BackupInterval, cfg.BackupRetention)
+	handlers.StartPlaceSyncJob(cfg.PlaceSyncInterval, cfg.PlaceSyncBatchSize)
+	handlers.InitPhotoProcessing(cfg.ImageWorkers)
+
+	// Initialize upload moderation (optional)
+	moderator, err := services.NewModerationProvider(services.ModerationConfig{
+		Provider:           cfg.ModerationProvider,
+		Endpoint:           cfg.ModerationEndpoint,
+		APIKey:             cfg.ModerationAPIKey,
+		MinConfidence:      cfg.ModerationMinConfidence,
+		AWSAccessKeyID:     cfg.AWSAccessKeyID,
+		AWSSecretAccessKey: cfg.AWSSecretAccessKey,
+		AWSRegion:          cfg.AWSRegion,
+	})
+	if err != nil {
+		logger.Fatal("Failed to initialize moderation: %v", err)
+	}
+	handlers.InitPhotoModeration(moderator)
+
+	// Responses of POST requests with an Idempotency-Key are replayed to retries
+	middleware.InitIdempotency(cfg.IdempotencyKeyTTL)
 
-	// Create router
+	// Request metrics survive restarts through periodic snapshots
+	middleware.InitMetricsPersistence(cfg.MetricsPersistInterval, cfg.MetricsRetention, cfg.InstanceName)
+
+	// Create router with the API routes
 	r := mux.NewRouter()
+	routes.Register(r, routes.Options{DebugEndpoints: cfg.DebugEndpointsEnabled})
+
+	// Metrics and API docs are public, admin-only, only served on the internal listener
+	// or disabled (METRICS_ACCESS, DOCS_ACCESS). They are registered with full paths, as
+	// the internal listener has no /api subrouter.
+	var internalRouter *mux.Router
+	if cfg.InternalAddr != "" {
+		internalRouter = mux.NewRouter()
+	}
 
-	// Create uploads directory and serve static files
-	uploadsDir := "./uploads"
-	if err := os.MkdirAll(uploadsDir+"/menu_photos", 0755); err != nil {
-		logger.Warn("Failed to create uploads directory: %v", err)
-	} else {
-		logger.Debug("📁 Uploads directory ready: %s", uploadsDir)
-	}
-	r.PathPrefix("/api/uploads/").Handler(
-		http.StripPrefix("/api/uploads/", http.FileServer(http.Dir(uploadsDir))))
-
-	// API routes
-	api := r.PathPrefix("/api").Subrouter()
-
-	// Public auth routes (no authentication required)
-	api.HandleFunc("/auth/register", handlers.Register).Methods("POST")
-	api.HandleFunc("/auth/login", handlers.Login).Methods("POST")
-	api.HandleFunc("/auth/refresh", handlers.RefreshToken).Methods("POST")
-	api.HandleFunc("/auth/logout", handlers.Logout).Methods("POST")
-	api.HandleFunc("/auth/oidc/login", handlers.OIDCLogin).Methods("GET")
-	api.HandleFunc("/auth/oidc/callback", handlers.OIDCCallback).Methods("GET")
-
-	// Protected auth routes (authentication required)
-	authRoutes := api.PathPrefix("/auth").Subrouter()
-	authRoutes.Use(middleware.AuthMiddleware)
-	authRoutes.HandleFunc("/me", handlers.GetMe).Methods("GET")
-
-	// Public read routes (no auth required for browsing)
-	publicRoutes := api.PathPrefix("").Subrouter()
-	publicRoutes.Use(middleware.OptionalAuthMiddleware)
-
-	// Categories (read-only public, write requires auth)
-	publicRoutes.HandleFunc("/categories", handlers.GetCategories).Methods("GET")
-	publicRoutes.HandleFunc("/categories/{id}", handlers.GetCategory).Methods("GET")
-
-	categoriesProtected := api.PathPrefix("/categories").Subrouter()
-	categoriesProtected.Use(middleware.AuthMiddleware)
-	categoriesProtected.HandleFunc("", handlers.CreateCategory).Methods("POST")
-	categoriesProtected.HandleFunc("/{id}", handlers.UpdateCategory).Methods("PUT")
-	categoriesProtected.HandleFunc("/{id}", handlers.DeleteCategory).Methods("DELETE")
-
-	// Food Types (read-only public, write requires auth)
-	publicRoutes.HandleFunc("/food-types", handlers.GetFoodTypes).Methods("GET")
-	publicRoutes.HandleFunc("/food-types/{id}", handlers.GetFoodType).Methods("GET")
-
-	foodTypesProtected := api.PathPrefix("/food-types").Subrouter()
-	foodTypesProtected.Use(middleware.AuthMiddleware)
-	foodTypesProtected.HandleFunc("", handlers.CreateFoodType).Methods("POST")
-	foodTypesProtected.HandleFunc("/{id}", handlers.UpdateFoodType).Methods("PUT")
-	foodTypesProtected.HandleFunc("/{id}", handlers.DeleteFoodType).Methods("DELETE")
-
-	// Restaurants (read-only public, write requires auth)
-	publicRoutes.HandleFunc("/restaurants", handlers.GetRestaurants).Methods("GET")
-	publicRoutes.HandleFunc("/restaurants/paginated", handlers.GetRestaurantsPaginated).Methods("GET")
-	publicRoutes.HandleFunc("/restaurants/{id}", handlers.GetRestaurant).Methods("GET")
-
-	restaurantsProtected := api.PathPrefix("/restaurants").Subrouter()
-	restaurantsProtected.Use(middleware.AuthMiddleware)
-	restaurantsProtected.HandleFunc("", handlers.CreateRestaurant).Methods("POST")
-	restaurantsProtected.HandleFunc("/{id}", handlers.UpdateRestaurant).Methods("PUT")
-	restaurantsProtected.HandleFunc("/{id}", handlers.DeleteRestaurant).Methods("DELETE")
-
-	// Global Search (public)
-	publicRoutes.HandleFunc("/search", handlers.GlobalSearch).Methods("GET")
-
-	// Ratings (read public, write requires auth)
-	publicRoutes.HandleFunc("/restaurants/{restaurantId}/ratings", handlers.GetRatings).Methods("GET")
-
-	ratingsProtected := api.PathPrefix("/ratings").Subrouter()
-	ratingsProtected.Use(middleware.AuthMiddleware)
-	ratingsProtected.HandleFunc("", handlers.CreateRating).Methods("POST")
-	ratingsProtected.HandleFunc("/{id}", handlers.DeleteRating).Methods("DELETE")
-
-	// Google Maps (proxied through backend - public with rate limiting)
-	publicRoutes.HandleFunc("/places/search", handlers.SearchPlaces).Methods("GET")
-	publicRoutes.HandleFunc("/places/{placeId}", handlers.GetPlaceDetails).Methods("GET")
-	publicRoutes.HandleFunc("/geocode/cities", handlers.GeocodeCities).Methods("GET")
-
-	// Restaurant Suggestions (requires auth)
-	suggestionsProtected := api.PathPrefix("/suggestions").Subrouter()
-	suggestionsProtected.Use(middleware.AuthMiddleware)
-	suggestionsProtected.HandleFunc("", handlers.GetSuggestions).Methods("GET")
-	suggestionsProtected.HandleFunc("/{id}", handlers.GetSuggestion).Methods("GET")
-	suggestionsProtected.HandleFunc("", handlers.CreateSuggestion).Methods("POST")
-	suggestionsProtected.HandleFunc("/{id}/status", handlers.UpdateSuggestionStatus).Methods("PATCH")
-	suggestionsProtected.HandleFunc("/{id}/convert", handlers.ConvertSuggestion).Methods("POST")
-	suggestionsProtected.HandleFunc("/{id}", handlers.DeleteSuggestion).Methods("DELETE")
-
-	// Menu Photos (read public, write requires auth)
-	publicRoutes.HandleFunc("/restaurants/{restaurantId}/photos", handlers.GetMenuPhotos).Methods("GET")
-
-	photosProtected := api.PathPrefix("").Subrouter()
-	photosProtected.Use(middleware.AuthMiddleware)
-	photosProtected.HandleFunc("/restaurants/{restaurantId}/photos", handlers.UploadMenuPhoto).Methods("POST")
-	photosProtected.HandleFunc("/photos/{id}", handlers.UpdatePhotoCaption).Methods("PATCH")
-	photosProtected.HandleFunc("/photos/{id}", handlers.DeleteMenuPhoto).Methods("DELETE")
-
-	// Health check (support both GET and HEAD for Docker healthcheck)
-	api.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		if r.Method == "GET" {
-			_, _ = w.Write([]byte("OK"))
-		}
-	}).Methods("GET", "HEAD")
-
-	// Metrics endpoint
-	api.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusOK)
-		stats := middleware.GetMetrics().GetStats()
-		json.NewEncoder(w).Encode(stats)
-	}).Methods("GET")
-
-	// Serve the swagger.yaml file first
-	api.HandleFunc("/swagger.yaml", func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "application/x-yaml")
-		http.ServeFile(w, r, "./docs/swagger.yaml")
-	}).Methods("GET")
-
-	// Redirect /docs to /docs/ for Swagger UI
-	api.HandleFunc("/docs", func(w http.ResponseWriter, r *http.Request) {
-		http.Redirect(w, r, "/api/docs/", http.StatusMovedPermanently)
-	}).Methods("GET")
-
-	// Swagger UI - serve at /api/docs/ (must be after swagger.yaml)
-	api.PathPrefix("/docs/").Handler(httpSwagger.Handler(
-		httpSwagger.URL("/api/swagger.yaml"),
-	))
-
-	// Initialize rate limiter
-	// Allow 100 requests per minute per IP, with burst of 20
-	rateLimiter := middleware.NewIPRateLimiter(rate.Every(time.Minute/100), 20)
+	// Prometheus metrics (requests per route template, DB pool and query latency, Places cache,
+	// Google Maps requests, Go runtime)
+	middleware.MetricsRegistry.MustRegister(database.NewPoolCollector(), database.QueryDurationCollector(),
+		services.PlacesCacheCollector(), services.GoogleMapsRequestsCollector())
+	if ops, guard := opsRouter(cfg.MetricsAccess, r, internalRouter); ops != nil {
+		ops.Handle("/metrics", guard(middleware.PrometheusHandler())).Methods("GET")
+
+		// Metrics endpoint
+		ops.Handle("/api/metrics", guard(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			stats := middleware.GetMetrics().GetStats()
+			stats["database_pool"] = database.PoolStats()
+			json.NewEncoder(w).Encode(stats)
+		}))).Methods("GET")
+		ops.Handle("/api/metrics/history", guard(http.HandlerFunc(handlers.GetMetricsHistory))).Methods("GET")
+	}
+
+	if ops, guard := opsRouter(cfg.DocsAccess, r, internalRouter); ops != nil {
+		// OpenAPI 3 document generated from the router (make openapi)
+		ops.Handle("/api/openapi.json", guard(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			http.ServeFile(w, r, "./docs/openapi.json")
+		}))).Methods("GET")
+
+		// Swagger 2 document of the annotations, kept for older tools
+		ops.Handle("/api/swagger.yaml", guard(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/x-yaml")
+			http.ServeFile(w, r, "./docs/swagger.yaml")
+		}))).Methods("GET")
+
+		// Redirect /docs to /docs/ for Swagger UI
+		ops.Handle("/api/docs", guard(http.RedirectHandler("/api/docs/", http.StatusMovedPermanently))).Methods("GET")
+
+		// Swagger UI - serve at /api/docs/ (must be after the documents)
+		ops.PathPrefix("/api/docs/").Handler(guard(httpSwagger.Handler(
+			httpSwagger.URL("/api/openapi.json"),
+		)))
+	}
+
+	// The embedded frontend (built with -tags embedfrontend) answers everything else, so it
+	// is registered last
+	if frontend.Enabled() {
+		r.PathPrefix("/").Handler(frontend.Handler()).Methods("GET", "HEAD")
+		logger.Info("🖥️  Serving the embedded frontend")
+	}
+
+	// Only believe X-Forwarded-For from known reverse proxies
+	middleware.InitTrustedProxies(cfg.TrustedProxies)
+
+	// Initialize rate limiters: per IP for each route group, and per user
+	rateLimits := middleware.RateLimits{
+		Auth:   cfg.RateLimitAuth,
+		Upload: cfg.RateLimitUpload,
+		Read:   cfg.RateLimitRead,
+		Write:  cfg.RateLimitWrite,
+		User:   cfg.RateLimitUser,
+	}
+	rateLimiter := middleware.NewRouteRateLimiter(rateLimits)
 	// Start cleanup task to prevent memory leaks (run every 10 minutes)
 	rateLimiter.StartCleanupTask(10 * time.Minute)
-	logger.Info("🔒 Rate limiting enabled: 100 req/min per IP, burst: 20")
+
+	// Settings admins changed at runtime override the configuration above
+	handlers.InitSettings(rateLimiter, rateLimits)
+
+	// Shed load before requests pile up waiting for database connections
+	concurrencyLimiter := middleware.NewConcurrencyLimiter(middleware.ConcurrencyLimits{
+		Global:       cfg.ConcurrencyLimit,
+		Auth:         cfg.ConcurrencyLimitAuth,
+		Upload:       cfg.ConcurrencyLimitUpload,
+		Read:         cfg.ConcurrencyLimitRead,
+		Write:        cfg.ConcurrencyLimitWrite,
+		QueueTimeout: cfg.ConcurrencyQueueTimeout,
+	})
 
 	// CORS middleware - more restrictive configuration
 	c := cors.New(cors.Options{
 		AllowedOrigins:   cfg.AllowedOrigins,
 		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Content-Type", "Authorization", "X-Requested-With"},
+		AllowedHeaders:   []string{"Content-Type", "Authorization", "X-Requested-With", middleware.APIKeyHeader, middleware.IdempotencyKeyHeader, middleware.CSRFHeader, "traceparent", "tracestate"},
+		ExposedHeaders:   []string{"X-Total-Count", "X-Next-Cursor", "X-Next-Page-Token", "X-RateLimit-Limit", "X-RateLimit-Remaining", "X-RateLimit-Reset", "Retry-After", "Idempotent-Replayed"},
 		AllowCredentials: true,
 		MaxAge:           300, // Cache preflight for 5 minutes
 	})
 	logger.Info("🌐 CORS configured for origins: %v", cfg.AllowedOrigins)
 
+	securityHeaders := middleware.SecurityHeaders{
+		ContentSecurityPolicy:     cfg.ContentSecurityPolicy,
+		DocsContentSecurityPolicy: cfg.DocsContentSecurityPolicy,
+		HSTSMaxAge:                cfg.HSTSMaxAge,
+		HSTSIncludeSubdomains:     cfg.HSTSIncludeSubdomains,
+		HSTSPreload:               cfg.HSTSPreload,
+		FrameOptions:              cfg.FrameOptions,
+		PermissionsPolicy:         cfg.PermissionsPolicy,
+	}
+	if frontend.Enabled() {
+		securityHeaders.FrontendContentSecurityPolicy = cfg.FrontendContentSecurityPolicy
+		securityHeaders.FrontendPermissionsPolicy = cfg.FrontendPermissionsPolicy
+	}
+
 	// Apply middleware chain (order matters)
-	// Recovery -> RequestID -> Security headers -> Rate limiting -> Request validation -> Max bytes -> Sanitization -> Compression -> Logging -> CORS -> Router
+	// Access log -> Recovery -> RequestID -> Locale -> Security headers -> Rate limiting -> Load shedding -> Request validation -> Max bytes -> Sanitization -> Compression -> Route -> Logging -> Prometheus -> Tracing -> Timeout -> CORS -> Router
 	handler := middleware.RecoveryMiddleware(
 		middleware.RequestIDMiddleware(
-			middleware.SecurityHeadersMiddleware(
-				middleware.RateLimitMiddleware(rateLimiter)(
-					middleware.ValidateContentTypeMiddleware(
-						middleware.MaxBytesMiddleware(10 * 1024 * 1024)( // 10MB max request size
-							middleware.SanitizeInputMiddleware(
-								middleware.CompressionMiddleware(
-									middleware.LoggingMiddleware(
-										c.Handler(r))))))))))
+			middleware.LocaleMiddleware(
+				middleware.SecurityHeadersMiddleware(securityHeaders)(
+					middleware.RateLimitMiddleware(rateLimiter)(
+						middleware.ConcurrencyLimitMiddleware(concurrencyLimiter)(
+							middleware.ValidateContentTypeMiddleware(
+								middleware.MaxBytesMiddleware(10 * 1024 * 1024)( // 10MB max request size
+									middleware.SanitizeInputMiddleware(
+										middleware.CompressionMiddleware(
+											middleware.RouteMiddleware(r)(
+												middleware.LoggingMiddleware(
+													middleware.PrometheusMiddleware(r)(
+														middleware.TracingMiddleware(r)(
+															middleware.TimeoutMiddleware(cfg.RequestTimeout)(
+																c.Handler(r))))))))))))))))
+
+	if cfg.AccessLog != "" {
+		accessLog, err := openAccessLog(cfg.AccessLog)
+		if err != nil {
+			logger.Fatal("Failed to open access log: %v", err)
+		}
+		defer accessLog.Close()
+		handler = middleware.AccessLogMiddleware(accessLog, cfg.AccessLogFormat)(handler)
+		logger.Info("📝 Access log (%s format) written to %s", cfg.AccessLogFormat, cfg.AccessLog)
+	}
 
 	// Start server
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
+	port := cfg.Port
+	scheme := "http"
+	if cfg.TLSEnabled() {
+		scheme = "https"
 	}
-
-	logger.Info("🌐 Server listening on http://localhost:%s", port)
-	logger.Info("📡 API available at http://localhost:%s/api", port)
-	logger.Info("📚 Swagger UI available at http://localhost:%s/api/docs", port)
+	logger.Info("🌐 Server listening on %s://localhost:%s", scheme, port)
+	logger.Info("📡 API available at %s://localhost:%s/api", scheme, port)
+	logger.Info("📚 Swagger UI available at %s://localhost:%s/api/docs", scheme, port)
 	logger.Info("🛡️  Security features enabled:")
 	logger.Info("   ✓ Panic recovery and error handling")
 	logger.Info("   ✓ Authentication mode: %s", cfg.AuthMode)
-	logger.Info("   ✓ Rate limiting (100 req/min per IP)")
+	logger.Info("   ✓ Rate limiting (per IP and route group, per user)")
+	logger.Info("   ✓ Load shedding (concurrency limits per route group)")
 	logger.Info("   ✓ Request size limits (10MB max)")
 	logger.Info("   ✓ Content-Type validation")
 	logger.Info("   ✓ Input sanitization")
@@ -298,7 +541,246 @@ func main() {
 	logger.Info("   ✓ CORS restrictions")
 	logger.Info("✅ Server ready to accept connections")
 
-	if err := http.ListenAndServe(":"+port, handler); err != nil {
+	server := &http.Server{
+		Addr:              ":" + port,
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       cfg.HTTPReadTimeout,  // Includes the body, so leave room for uploads
+		WriteTimeout:      cfg.HTTPWriteTimeout, // Lifted by streaming handlers
+		IdleTimeout:       cfg.HTTPIdleTimeout,
+	}
+	logger.Info("⏱️  Timeouts: request %s, read %s, write %s, idle %s",
+		cfg.RequestTimeout, cfg.HTTPReadTimeout, cfg.HTTPWriteTimeout, cfg.HTTPIdleTimeout)
+
+	// HTTP/2 is negotiated over TLS; reverse proxies can speak it in cleartext (h2c) with
+	// prior knowledge
+	server.Protocols = new(http.Protocols)
+	server.Protocols.SetHTTP1(true)
+	if cfg.HTTP2Enabled {
+		server.Protocols.SetHTTP2(true)
+		server.Protocols.SetUnencryptedHTTP2(true)
+		logger.Info("⚡ HTTP/2 enabled (TLS and h2c)")
+	}
+
+	// Terminate TLS when configured, for deployments without a reverse proxy
+	var redirectServer *http.Server
+	if cfg.TLSEnabled() {
+		redirectServer = configureTLS(cfg, server)
+	}
+
+	// Internal listener for metrics and docs, not to be exposed through the reverse proxy
+	var internalServer *http.Server
+	if internalRouter != nil {
+		internalServer = &http.Server{
+			Addr: cfg.InternalAddr,
+			Handler: middleware.RecoveryMiddleware(
+				middleware.RequestIDMiddleware(
+					middleware.SecurityHeadersMiddleware(securityHeaders)(
+						middleware.CompressionMiddleware(internalRouter)))),
+			ReadHeaderTimeout: 10 * time.Second,
+			ReadTimeout:       cfg.HTTPReadTimeout,
+			IdleTimeout:       cfg.HTTPIdleTimeout,
+		}
+		logger.Info("🔒 Internal endpoints (metrics: %s, docs: %s) listening on %s",
+			cfg.MetricsAccess, cfg.DocsAccess, cfg.InternalAddr)
+	}
+
+	// Serve until SIGTERM (docker stop, Kubernetes) or SIGINT (Ctrl+C)
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	defer stop()
+
+	serverErr := make(chan error, 1)
+	go func() {
+		if cfg.TLSEnabled() {
+			// The files are empty with ACME, whose certificates come from the TLS config
+			serverErr <- server.ListenAndServeTLS(cfg.TLSCertFile, cfg.TLSKeyFile)
+			return
+		}
+		serverErr <- server.ListenAndServe()
+	}()
+	if redirectServer != nil {
+		go func() {
+			serverErr <- redirectServer.ListenAndServe()
+		}()
+	}
+	if internalServer != nil {
+		go func() {
+			serverErr <- internalServer.ListenAndServe()
+		}()
+	}
+
+	select {
+	case err := <-serverErr:
 		logger.Fatal("Server failed to start: %v", err)
+	case <-ctx.Done():
+	}
+	stop()
+
+	// Fail health checks first, so load balancers stop sending new requests before the
+	// listener closes, then wait for in-flight requests. The deferred calls close the
+	// database pool and flush traces once no handler can use them anymore.
+	logger.Info("🛑 Shutting down, draining for %s", cfg.ShutdownDrainDelay)
+	handlers.BeginShutdown()
+	time.Sleep(cfg.ShutdownDrainDelay)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+	if internalServer != nil {
+		internalServer.Shutdown(shutdownCtx)
+	}
+	if redirectServer != nil {
+		redirectServer.Shutdown(shutdownCtx)
+	}
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		logger.Error("Failed to finish in-flight requests within %s: %v", cfg.ShutdownTimeout, err)
+	} else {
+		logger.Info("👋 All requests finished, server stopped")
+	}
+	middleware.SaveMetricsSnapshot(context.Background())
+}
+
+// configureTLS sets up server for HTTPS with the certificate files or certificates from
+// an ACME CA, and returns the plain HTTP listener redirecting to it, if enabled
+func configureTLS(cfg *config.Config, server *http.Server) *http.Server {
+	redirect := middleware.HTTPSRedirectHandler(cfg.Port)
+	if len(cfg.TLSAutocertDomains) > 0 {
+		manager := &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			HostPolicy: autocert.HostWhitelist(cfg.TLSAutocertDomains...),
+			Cache:      autocert.DirCache(cfg.TLSAutocertCacheDir),
+			Email:      cfg.TLSAutocertEmail,
+		}
+		if cfg.TLSAutocertDirectoryURL != "" {
+			manager.Client = &acme.Client{DirectoryURL: cfg.TLSAutocertDirectoryURL}
+		}
+		server.TLSConfig = manager.TLSConfig()
+		// Answer HTTP-01 challenges, redirect everything else
+		redirect = manager.HTTPHandler(redirect)
+		logger.Info("🔐 TLS certificates for %v obtained automatically, cached in %s",
+			cfg.TLSAutocertDomains, cfg.TLSAutocertCacheDir)
+	} else {
+		// Fail at startup rather than on the first handshake
+		if _, err := tls.LoadX509KeyPair(cfg.TLSCertFile, cfg.TLSKeyFile); err != nil {
+			logger.Fatal("Failed to load TLS certificate: %v", err)
+		}
+		server.TLSConfig = &tls.Config{}
+		logger.Info("🔐 TLS certificate loaded from %s", cfg.TLSCertFile)
+	}
+	server.TLSConfig.MinVersion = tls.VersionTLS12
+	if cfg.HSTSMaxAge > 0 {
+		logger.Info("   ✓ HSTS (max-age %s)", cfg.HSTSMaxAge)
+	}
+
+	if cfg.TLSRedirectAddr == "" {
+		return nil
+	}
+	logger.Info("↪️  Redirecting HTTP on %s to HTTPS", cfg.TLSRedirectAddr)
+	return &http.Server{
+		Addr:              cfg.TLSRedirectAddr,
+		Handler:           redirect,
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       10 * time.Second,
+		IdleTimeout:       cfg.HTTPIdleTimeout,
+	}
+}
+
+// reportPreflight logs the result of every preflight check and whether all passed
+func reportPreflight(results map[string]models.HealthCheck) bool {
+	names := make([]string, 0, len(results))
+	for name := range results {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	passed := true
+	logger.Info("🛫 Preflight checks:")
+	for _, name := range names {
+		result := results[name]
+		if result.Status == "ok" {
+			logger.Info("  ✅ %-16s %s (%dms)", name, result.Details, result.LatencyMs)
+			continue
+		}
+		passed = false
+		if result.Details != "" {
+			logger.Error("  ❌ %-16s %s: %s (%dms)", name, result.Details, result.Error, result.LatencyMs)
+		} else {
+			logger.Error("  ❌ %-16s %s (%dms)", name, result.Error, result.LatencyMs)
+		}
+	}
+	if passed {
+		logger.Info("🛫 All %d preflight checks passed", len(results))
+	} else {
+		logger.Error("🛫 Preflight checks failed")
+	}
+	return passed
+}
+
+// alertPanic sends a recovered panic to every alerter
+func alertPanic(alerters []services.Alerter, p middleware.PanicReport) {
+	alert := services.Alert{
+		Fingerprint: p.Fingerprint,
+		Title:       fmt.Sprintf("Panic in %s %s", p.Method, p.Path),
+		Message:     p.Message,
+		Stack:       p.Stack,
+		Tags:        map[string]string{"request_id": p.RequestID, "method": p.Method, "path": p.Path},
+		Occurrences: p.Occurrences,
+		Time:        p.Time,
+	}
+	for _, alerter := range alerters {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		if err := alerter.Alert(ctx, alert); err != nil {
+			logger.Error("❌ Failed to send panic alert to %s: %v", alerter.Name(), err)
+		}
+		cancel()
+	}
+}
+
+// openAccessLog returns the destination of the access log: stdout, stderr or a file
+// that is appended to
+func openAccessLog(destination string) (io.WriteCloser, error) {
+	switch destination {
+	case "stdout":
+		return nopWriteCloser{os.Stdout}, nil
+	case "stderr":
+		return nopWriteCloser{os.Stderr}, nil
+	}
+	return os.OpenFile(destination, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+}
+
+// nopWriteCloser keeps the standard streams open when the access log is closed
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
+
+// printEffectiveConfig lists every setting with its value and where it came from
+func printEffectiveConfig(w io.Writer, cfg *config.Config) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "SETTING\tVALUE\tSOURCE")
+	for _, setting := range cfg.Effective() {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", setting.Name, setting.Value, setting.Source)
+	}
+	tw.Flush()
+}
+
+// opsRouter returns the router an operational endpoint (metrics, API docs) with the
+// given access is registered on, and the middleware guarding it. The router is nil if
+// the endpoint is disabled.
+func opsRouter(access string, public, internal *mux.Router) (*mux.Router, func(http.Handler) http.Handler) {
+	unguarded := func(next http.Handler) http.Handler { return next }
+
+	switch access {
+	case config.OpsAccessAdmin:
+		// Outside the /api subrouter, so API keys are checked here
+		return public, func(next http.Handler) http.Handler {
+			return middleware.APIKeyAuthMiddleware(middleware.AuthMiddleware(middleware.AdminOnlyMiddleware(next)))
+		}
+	case config.OpsAccessInternal:
+		return internal, unguarded
+	case config.OpsAccessDisabled:
+		return nil, nil
+	default:
+		return public, unguarded
 	}
 }
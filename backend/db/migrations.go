@@ -0,0 +1,13 @@
+// Package db embeds the database migrations and seed fixtures into the binaries
+package db
+
+import "embed"
+
+// Migrations holds the migration files under migrations_new/
+//
+//go:embed migrations_new/*.sql
+var Migrations embed.FS
+
+// MigrationsDir is the directory of the migrations in Migrations and, relative to the
+// backend directory, on disk
+const MigrationsDir = "migrations_new"
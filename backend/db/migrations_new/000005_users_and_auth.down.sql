@@ -13,7 +13,7 @@ DROP TABLE IF EXISTS sessions;
 -- Remove columns from existing tables
 ALTER TABLE restaurants DROP COLUMN IF EXISTS updated_by;
 ALTER TABLE restaurants DROP COLUMN IF EXISTS created_by;
-ALTER TABLE suggestions DROP COLUMN IF EXISTS user_id;
+ALTER TABLE restaurant_suggestions DROP COLUMN IF EXISTS user_id;
 ALTER TABLE ratings DROP COLUMN IF EXISTS user_id;
 
 -- Drop users table
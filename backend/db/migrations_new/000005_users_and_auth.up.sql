@@ -41,8 +41,8 @@ ALTER TABLE ratings ADD COLUMN IF NOT EXISTS user_id INTEGER REFERENCES users(id
 CREATE INDEX IF NOT EXISTS idx_ratings_user_id ON ratings(user_id);
 
 -- Add user_id to suggestions to track who suggested them
-ALTER TABLE suggestions ADD COLUMN IF NOT EXISTS user_id INTEGER REFERENCES users(id) ON DELETE SET NULL;
-CREATE INDEX IF NOT EXISTS idx_suggestions_user_id ON suggestions(user_id);
+ALTER TABLE restaurant_suggestions ADD COLUMN IF NOT EXISTS user_id INTEGER REFERENCES users(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_suggestions_user_id ON restaurant_suggestions(user_id);
 
 -- Add user_id to restaurants to track who created them
 ALTER TABLE restaurants ADD COLUMN IF NOT EXISTS created_by INTEGER REFERENCES users(id) ON DELETE SET NULL;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_suggestion_approvals_suggestion;
+DROP TABLE IF EXISTS suggestion_approvals;
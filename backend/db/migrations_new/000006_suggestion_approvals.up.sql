@@ -0,0 +1,12 @@
+-- Admin approvals for the two-step suggestion review workflow.
+-- Each admin can approve a given suggestion at most once.
+CREATE TABLE IF NOT EXISTS suggestion_approvals (
+    id SERIAL PRIMARY KEY,
+    suggestion_id INTEGER NOT NULL REFERENCES restaurant_suggestions(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    note TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (suggestion_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_suggestion_approvals_suggestion ON suggestion_approvals(suggestion_id);
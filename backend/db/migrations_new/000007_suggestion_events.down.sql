@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_suggestion_events_suggestion;
+DROP TABLE IF EXISTS suggestion_events;
@@ -0,0 +1,14 @@
+-- Activity log of suggestion status transitions.
+-- suggestion_id intentionally has no foreign key so the history survives
+-- conversion (which deletes the suggestion) and manual deletes.
+CREATE TABLE IF NOT EXISTS suggestion_events (
+    id SERIAL PRIMARY KEY,
+    suggestion_id INTEGER NOT NULL,
+    user_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    from_status VARCHAR(20),
+    to_status VARCHAR(20) NOT NULL,
+    note TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_suggestion_events_suggestion ON suggestion_events(suggestion_id, created_at);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_photo_uploads_expires_at;
+DROP TABLE IF EXISTS photo_uploads;
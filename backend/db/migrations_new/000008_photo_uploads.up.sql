@@ -0,0 +1,15 @@
+-- Pending direct-to-S3 photo uploads awaiting confirmation
+CREATE TABLE IF NOT EXISTS photo_uploads (
+    token VARCHAR(64) PRIMARY KEY,
+    restaurant_id INTEGER NOT NULL REFERENCES restaurants(id) ON DELETE CASCADE,
+    user_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    filename VARCHAR(255) NOT NULL,
+    original_filename VARCHAR(255),
+    caption VARCHAR(255) NOT NULL,
+    content_type VARCHAR(50) NOT NULL,
+    file_size INTEGER NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_photo_uploads_expires_at ON photo_uploads(expires_at);
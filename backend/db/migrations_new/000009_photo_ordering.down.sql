@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_menu_photos_one_cover;
+DROP INDEX IF EXISTS idx_menu_photos_restaurant_position;
+ALTER TABLE menu_photos DROP COLUMN IF EXISTS is_cover;
+ALTER TABLE menu_photos DROP COLUMN IF EXISTS position;
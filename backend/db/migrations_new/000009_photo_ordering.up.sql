@@ -0,0 +1,16 @@
+-- Manual photo ordering and cover photo selection
+ALTER TABLE menu_photos ADD COLUMN IF NOT EXISTS position INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE menu_photos ADD COLUMN IF NOT EXISTS is_cover BOOLEAN NOT NULL DEFAULT false;
+
+-- Keep the current newest-first gallery order for existing photos
+UPDATE menu_photos m SET position = ordered.rn - 1
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY restaurant_id ORDER BY created_at DESC, id DESC) AS rn
+    FROM menu_photos
+) ordered
+WHERE m.id = ordered.id;
+
+CREATE INDEX IF NOT EXISTS idx_menu_photos_restaurant_position ON menu_photos(restaurant_id, position);
+
+-- At most one cover photo per restaurant
+CREATE UNIQUE INDEX IF NOT EXISTS idx_menu_photos_one_cover ON menu_photos(restaurant_id) WHERE is_cover;
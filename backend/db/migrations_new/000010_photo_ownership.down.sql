@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_photo_uploads_user;
+DROP INDEX IF EXISTS idx_menu_photos_uploaded_by;
+ALTER TABLE menu_photos DROP COLUMN IF EXISTS uploaded_by_user_id;
@@ -0,0 +1,5 @@
+-- Track who uploaded each photo for ownership checks and upload quotas
+ALTER TABLE menu_photos ADD COLUMN IF NOT EXISTS uploaded_by_user_id INTEGER REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_menu_photos_uploaded_by ON menu_photos(uploaded_by_user_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_photo_uploads_user ON photo_uploads(user_id, created_at);
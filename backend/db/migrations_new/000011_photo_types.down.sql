@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_menu_photos_restaurant_type;
+ALTER TABLE photo_uploads DROP COLUMN IF EXISTS photo_type;
+ALTER TABLE menu_photos DROP COLUMN IF EXISTS photo_type;
@@ -0,0 +1,6 @@
+-- Distinguish menu photos from dish, interior and receipt photos
+ALTER TABLE menu_photos ADD COLUMN IF NOT EXISTS photo_type VARCHAR(20) NOT NULL DEFAULT 'menu'
+    CHECK (photo_type IN ('menu', 'food', 'interior', 'receipt'));
+ALTER TABLE photo_uploads ADD COLUMN IF NOT EXISTS photo_type VARCHAR(20) NOT NULL DEFAULT 'menu';
+
+CREATE INDEX IF NOT EXISTS idx_menu_photos_restaurant_type ON menu_photos(restaurant_id, photo_type);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_menu_photos_processing;
+ALTER TABLE menu_photos DROP COLUMN IF EXISTS processing_error;
+ALTER TABLE menu_photos DROP COLUMN IF EXISTS status;
@@ -0,0 +1,6 @@
+-- Photos are processed by background workers after upload
+ALTER TABLE menu_photos ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'ready'
+    CHECK (status IN ('processing', 'ready', 'failed'));
+ALTER TABLE menu_photos ADD COLUMN IF NOT EXISTS processing_error TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_menu_photos_processing ON menu_photos(created_at) WHERE status = 'processing';
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_menu_photos_moderation_queue;
+ALTER TABLE menu_photos DROP COLUMN IF EXISTS moderated_at;
+ALTER TABLE menu_photos DROP COLUMN IF EXISTS moderated_by_user_id;
+ALTER TABLE menu_photos DROP COLUMN IF EXISTS moderation_labels;
+ALTER TABLE menu_photos DROP COLUMN IF EXISTS moderation_status;
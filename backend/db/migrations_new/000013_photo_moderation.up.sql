@@ -0,0 +1,9 @@
+-- Uploads flagged by the moderation provider stay hidden until an admin reviews them.
+-- pending: awaiting the automated check, flagged: awaiting human review
+ALTER TABLE menu_photos ADD COLUMN IF NOT EXISTS moderation_status VARCHAR(20) NOT NULL DEFAULT 'approved'
+    CHECK (moderation_status IN ('pending', 'flagged', 'approved'));
+ALTER TABLE menu_photos ADD COLUMN IF NOT EXISTS moderation_labels TEXT[];
+ALTER TABLE menu_photos ADD COLUMN IF NOT EXISTS moderated_by_user_id INTEGER REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE menu_photos ADD COLUMN IF NOT EXISTS moderated_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_menu_photos_moderation_queue ON menu_photos(created_at) WHERE moderation_status <> 'approved';
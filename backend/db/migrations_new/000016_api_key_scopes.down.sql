@@ -0,0 +1,3 @@
+ALTER TABLE api_keys DROP COLUMN IF EXISTS rate_limit;
+ALTER TABLE api_keys DROP COLUMN IF EXISTS scopes;
+ALTER TABLE api_keys DROP COLUMN IF EXISTS key_prefix;
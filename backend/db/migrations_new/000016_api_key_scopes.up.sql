@@ -0,0 +1,5 @@
+-- Scoped API keys. key_prefix identifies a key in listings without revealing it,
+-- rate_limit overrides the default requests per minute
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS key_prefix VARCHAR(16);
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS scopes TEXT[] NOT NULL DEFAULT '{read}';
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS rate_limit INTEGER CHECK (rate_limit > 0);
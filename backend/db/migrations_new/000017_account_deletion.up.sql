@@ -0,0 +1,7 @@
+-- Self-service account deletion. Accounts are deactivated immediately and purged
+-- after a grace period; deletion_mode decides whether contributions are kept anonymously.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS deletion_requested_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS deletion_mode VARCHAR(20)
+    CHECK (deletion_mode IN ('anonymize', 'purge'));
+
+CREATE INDEX IF NOT EXISTS idx_users_deletion_requested_at ON users(deletion_requested_at) WHERE deletion_requested_at IS NOT NULL;
@@ -0,0 +1,20 @@
+-- External login identities, qualified by provider name so one account can sign in
+-- through several providers and subjects from different providers never collide
+CREATE TABLE IF NOT EXISTS user_identities (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    provider VARCHAR(50) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    email VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    last_login_at TIMESTAMP WITH TIME ZONE,
+    UNIQUE (provider, subject)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_identities_user_id ON user_identities(user_id);
+
+-- Carry over identities of the single-provider OIDC integration
+INSERT INTO user_identities (user_id, provider, subject, email)
+SELECT id, provider, provider_id, email FROM users
+WHERE provider IS NOT NULL AND provider <> 'local' AND provider_id IS NOT NULL
+ON CONFLICT (provider, subject) DO NOTHING;
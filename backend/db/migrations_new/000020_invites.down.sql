@@ -0,0 +1,4 @@
+ALTER TABLE oidc_login_states DROP COLUMN IF EXISTS invite_hash;
+DROP INDEX IF EXISTS idx_users_invite_id;
+ALTER TABLE users DROP COLUMN IF EXISTS invite_id;
+DROP TABLE IF EXISTS invites;
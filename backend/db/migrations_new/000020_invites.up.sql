@@ -0,0 +1,20 @@
+-- Invite codes for REGISTRATION_MODE=invite; only the SHA-256 hash of a code is stored
+CREATE TABLE IF NOT EXISTS invites (
+    id SERIAL PRIMARY KEY,
+    code_hash VARCHAR(64) UNIQUE NOT NULL,
+    code_prefix VARCHAR(16) NOT NULL,
+    note VARCHAR(255),
+    max_uses INTEGER NOT NULL DEFAULT 1 CHECK (max_uses > 0),
+    use_count INTEGER NOT NULL DEFAULT 0,
+    expires_at TIMESTAMP WITH TIME ZONE,
+    created_by_user_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- The invite each user registered with
+ALTER TABLE users ADD COLUMN IF NOT EXISTS invite_id INTEGER REFERENCES invites(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_users_invite_id ON users(invite_id);
+
+-- Invites presented when starting an OIDC login, redeemed if the login creates an account
+ALTER TABLE oidc_login_states ADD COLUMN IF NOT EXISTS invite_hash VARCHAR(64);
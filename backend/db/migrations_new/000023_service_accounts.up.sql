@@ -0,0 +1,3 @@
+-- Service accounts are users with provider 'service': no password, API keys only.
+-- Their requests are rate limited per account in addition to per key.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS api_rate_limit INTEGER CHECK (api_rate_limit > 0);
@@ -0,0 +1,19 @@
+-- Periodic snapshots of the in-memory request metrics. Counters are cumulative since
+-- counting_since, so the latest snapshot restores them after a restart.
+CREATE TABLE IF NOT EXISTS metrics_snapshots (
+    id BIGSERIAL PRIMARY KEY,
+    recorded_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    counting_since TIMESTAMP WITH TIME ZONE NOT NULL,
+    total_requests BIGINT NOT NULL,
+    total_errors BIGINT NOT NULL,
+    requests_by_method JSONB NOT NULL DEFAULT '{}',
+    requests_by_route JSONB NOT NULL DEFAULT '{}',
+    requests_by_status JSONB NOT NULL DEFAULT '{}',
+    response_time_count BIGINT NOT NULL DEFAULT 0,
+    response_time_total_ms DOUBLE PRECISION NOT NULL DEFAULT 0,
+    p50_response_time_ms DOUBLE PRECISION NOT NULL DEFAULT 0,
+    p95_response_time_ms DOUBLE PRECISION NOT NULL DEFAULT 0,
+    p99_response_time_ms DOUBLE PRECISION NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS idx_metrics_snapshots_recorded_at ON metrics_snapshots(recorded_at);
@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS idx_suggestion_events_user;
+DROP INDEX IF EXISTS idx_suggestion_approvals_user;
+DROP INDEX IF EXISTS idx_invites_created_by;
+DROP INDEX IF EXISTS idx_photo_uploads_restaurant;
+DROP INDEX IF EXISTS idx_menu_photos_moderated_by;
+DROP INDEX IF EXISTS idx_restaurants_updated_by;
+
+ALTER TABLE restaurant_suggestions DROP CONSTRAINT IF EXISTS restaurant_suggestions_longitude_range;
+ALTER TABLE restaurant_suggestions DROP CONSTRAINT IF EXISTS restaurant_suggestions_latitude_range;
+ALTER TABLE restaurants DROP CONSTRAINT IF EXISTS restaurants_longitude_range;
+ALTER TABLE restaurants DROP CONSTRAINT IF EXISTS restaurants_latitude_range;
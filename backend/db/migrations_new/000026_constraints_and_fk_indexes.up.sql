@@ -0,0 +1,23 @@
+-- Coordinate range checks. DECIMAL(10,8) and DECIMAL(11,8) accept up to ±99.99999999
+-- and ±999.99999999, so out-of-range coordinates were stored as is. Invalid coordinates
+-- left by older versions are cleared rather than failing the migration.
+UPDATE restaurants SET latitude = NULL, longitude = NULL
+WHERE latitude NOT BETWEEN -90 AND 90 OR longitude NOT BETWEEN -180 AND 180;
+UPDATE restaurant_suggestions SET latitude = NULL, longitude = NULL
+WHERE latitude NOT BETWEEN -90 AND 90 OR longitude NOT BETWEEN -180 AND 180;
+
+ALTER TABLE restaurants ADD CONSTRAINT restaurants_latitude_range CHECK (latitude BETWEEN -90 AND 90);
+ALTER TABLE restaurants ADD CONSTRAINT restaurants_longitude_range CHECK (longitude BETWEEN -180 AND 180);
+ALTER TABLE restaurant_suggestions ADD CONSTRAINT restaurant_suggestions_latitude_range CHECK (latitude BETWEEN -90 AND 90);
+ALTER TABLE restaurant_suggestions ADD CONSTRAINT restaurant_suggestions_longitude_range CHECK (longitude BETWEEN -180 AND 180);
+
+-- Indexes on foreign keys that had none. Deleting a referenced row scans the referencing
+-- table for ON DELETE CASCADE/SET NULL, which is a sequential scan without an index.
+-- (ratings.restaurant_id, restaurant_food_types and menu_photos.restaurant_id are
+-- indexed since 000001/000004.)
+CREATE INDEX IF NOT EXISTS idx_restaurants_updated_by ON restaurants(updated_by);
+CREATE INDEX IF NOT EXISTS idx_menu_photos_moderated_by ON menu_photos(moderated_by_user_id);
+CREATE INDEX IF NOT EXISTS idx_photo_uploads_restaurant ON photo_uploads(restaurant_id);
+CREATE INDEX IF NOT EXISTS idx_invites_created_by ON invites(created_by_user_id);
+CREATE INDEX IF NOT EXISTS idx_suggestion_approvals_user ON suggestion_approvals(user_id);
+CREATE INDEX IF NOT EXISTS idx_suggestion_events_user ON suggestion_events(user_id);
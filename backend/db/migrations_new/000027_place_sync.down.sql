@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS restaurant_place_changes;
+DROP INDEX IF EXISTS idx_restaurants_place_sync;
+ALTER TABLE restaurants DROP COLUMN IF EXISTS place_synced_at;
+ALTER TABLE restaurants DROP COLUMN IF EXISTS opening_hours;
+ALTER TABLE restaurants DROP COLUMN IF EXISTS business_status;
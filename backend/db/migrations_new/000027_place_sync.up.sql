@@ -0,0 +1,28 @@
+-- Data refreshed from the places provider by the place sync job. business_status is
+-- OPERATIONAL, CLOSED_TEMPORARILY or CLOSED_PERMANENTLY; opening_hours has one line per day.
+ALTER TABLE restaurants ADD COLUMN IF NOT EXISTS business_status VARCHAR(32);
+ALTER TABLE restaurants ADD COLUMN IF NOT EXISTS opening_hours TEXT[];
+ALTER TABLE restaurants ADD COLUMN IF NOT EXISTS place_synced_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_restaurants_place_sync ON restaurants(place_synced_at NULLS FIRST, id)
+    WHERE google_place_id IS NOT NULL;
+
+-- Differences found by the sync, applied to the restaurant only once an admin accepts them.
+-- Values are JSON: strings for phone, website and business_status, an array for opening_hours.
+CREATE TABLE IF NOT EXISTS restaurant_place_changes (
+    id SERIAL PRIMARY KEY,
+    restaurant_id INTEGER NOT NULL REFERENCES restaurants(id) ON DELETE CASCADE,
+    field VARCHAR(32) NOT NULL CHECK (field IN ('phone', 'website', 'business_status', 'opening_hours')),
+    old_value JSONB,
+    new_value JSONB NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'accepted', 'rejected')),
+    reviewed_by_user_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    reviewed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- One pending change per field; a newer difference replaces it
+CREATE UNIQUE INDEX IF NOT EXISTS idx_restaurant_place_changes_pending
+    ON restaurant_place_changes(restaurant_id, field) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_restaurant_place_changes_restaurant ON restaurant_place_changes(restaurant_id);
+CREATE INDEX IF NOT EXISTS idx_restaurant_place_changes_reviewed_by ON restaurant_place_changes(reviewed_by_user_id);
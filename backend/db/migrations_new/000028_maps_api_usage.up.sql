@@ -0,0 +1,8 @@
+-- Billed Google Maps Platform requests per UTC day and operation (text_search,
+-- place_details, distance_matrix, ...), shared by all replicas for the daily budget
+CREATE TABLE IF NOT EXISTS maps_api_usage (
+    day DATE NOT NULL,
+    operation VARCHAR(64) NOT NULL,
+    calls INTEGER NOT NULL DEFAULT 0 CHECK (calls >= 0),
+    PRIMARY KEY (day, operation)
+);
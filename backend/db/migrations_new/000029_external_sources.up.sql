@@ -0,0 +1,16 @@
+-- Review site data of restaurants, matched by name and location. One row per restaurant
+-- and provider, replaced on every lookup; ratings are on a 0 to 5 scale.
+CREATE TABLE IF NOT EXISTS external_sources (
+    id SERIAL PRIMARY KEY,
+    restaurant_id INTEGER NOT NULL REFERENCES restaurants(id) ON DELETE CASCADE,
+    provider VARCHAR(32) NOT NULL CHECK (provider IN ('yelp', 'foursquare')),
+    external_id VARCHAR(255) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    url TEXT,
+    rating DOUBLE PRECISION CHECK (rating BETWEEN 0 AND 5),
+    rating_count INTEGER CHECK (rating_count >= 0),
+    price_level SMALLINT CHECK (price_level BETWEEN 1 AND 4),
+    categories TEXT[] NOT NULL DEFAULT '{}',
+    fetched_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (restaurant_id, provider)
+);
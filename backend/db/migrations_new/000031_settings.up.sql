@@ -0,0 +1,8 @@
+-- Runtime settings changed by admins, overriding the startup configuration until reset.
+-- Values are stored as text and parsed by the type the server defines for each key.
+CREATE TABLE IF NOT EXISTS settings (
+    key VARCHAR(64) PRIMARY KEY,
+    value TEXT NOT NULL,
+    updated_by_user_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,21 @@
+-- Restaurants and suggestions outside the default space are removed with their spaces
+DELETE FROM restaurant_suggestions WHERE space_id <> 1;
+DELETE FROM restaurants WHERE space_id <> 1;
+
+DROP INDEX IF EXISTS idx_restaurants_google_place_id;
+DROP INDEX IF EXISTS idx_restaurants_name_address;
+DROP INDEX IF EXISTS idx_suggestions_google_place_id;
+DROP INDEX IF EXISTS idx_suggestions_name_address;
+CREATE UNIQUE INDEX idx_restaurants_google_place_id ON restaurants(google_place_id) WHERE google_place_id IS NOT NULL;
+CREATE UNIQUE INDEX idx_restaurants_name_address ON restaurants(LOWER(name), LOWER(address)) WHERE address IS NOT NULL;
+CREATE UNIQUE INDEX idx_suggestions_google_place_id ON restaurant_suggestions(google_place_id) WHERE google_place_id IS NOT NULL;
+CREATE UNIQUE INDEX idx_suggestions_name_address ON restaurant_suggestions(LOWER(name), LOWER(address)) WHERE address IS NOT NULL;
+
+DROP INDEX IF EXISTS idx_suggestions_space_id;
+ALTER TABLE restaurant_suggestions DROP COLUMN IF EXISTS space_id;
+DROP INDEX IF EXISTS idx_restaurants_space_id;
+ALTER TABLE restaurants DROP COLUMN IF EXISTS space_id;
+
+DROP TABLE IF EXISTS space_invites;
+DROP TABLE IF EXISTS space_members;
+DROP TABLE IF EXISTS spaces;
@@ -0,0 +1,55 @@
+-- Spaces let several groups keep separate restaurant lists on one instance. Existing
+-- data belongs to the public default space, which requests without a space use.
+CREATE TABLE IF NOT EXISTS spaces (
+    id SERIAL PRIMARY KEY,
+    slug VARCHAR(50) UNIQUE NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    public BOOLEAN NOT NULL DEFAULT FALSE,
+    created_by_user_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+INSERT INTO spaces (id, slug, name, public) VALUES (1, 'default', 'Default', TRUE) ON CONFLICT DO NOTHING;
+SELECT setval('spaces_id_seq', GREATEST((SELECT MAX(id) FROM spaces), 1));
+
+CREATE TABLE IF NOT EXISTS space_members (
+    space_id INTEGER NOT NULL REFERENCES spaces(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role VARCHAR(20) NOT NULL DEFAULT 'member' CHECK (role IN ('owner', 'admin', 'member')),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (space_id, user_id)
+);
+CREATE INDEX IF NOT EXISTS idx_space_members_user_id ON space_members(user_id);
+
+-- Invite codes joining a space; only the SHA-256 hash of a code is stored
+CREATE TABLE IF NOT EXISTS space_invites (
+    id SERIAL PRIMARY KEY,
+    space_id INTEGER NOT NULL REFERENCES spaces(id) ON DELETE CASCADE,
+    code_hash VARCHAR(64) UNIQUE NOT NULL,
+    code_prefix VARCHAR(16) NOT NULL,
+    role VARCHAR(20) NOT NULL DEFAULT 'member' CHECK (role IN ('admin', 'member')),
+    max_uses INTEGER NOT NULL DEFAULT 1 CHECK (max_uses > 0),
+    use_count INTEGER NOT NULL DEFAULT 0,
+    expires_at TIMESTAMP WITH TIME ZONE,
+    created_by_user_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_space_invites_space_id ON space_invites(space_id);
+
+-- Restaurants and suggestions belong to a space; ratings and photos follow their restaurant
+ALTER TABLE restaurants ADD COLUMN IF NOT EXISTS space_id INTEGER NOT NULL DEFAULT 1 REFERENCES spaces(id) ON DELETE CASCADE;
+CREATE INDEX IF NOT EXISTS idx_restaurants_space_id ON restaurants(space_id);
+ALTER TABLE restaurant_suggestions ADD COLUMN IF NOT EXISTS space_id INTEGER NOT NULL DEFAULT 1 REFERENCES spaces(id) ON DELETE CASCADE;
+CREATE INDEX IF NOT EXISTS idx_suggestions_space_id ON restaurant_suggestions(space_id);
+
+-- The same place may be listed in several spaces, but only once per space
+DROP INDEX IF EXISTS idx_restaurants_google_place_id;
+DROP INDEX IF EXISTS idx_restaurants_name_address;
+DROP INDEX IF EXISTS idx_suggestions_google_place_id;
+DROP INDEX IF EXISTS idx_suggestions_name_address;
+CREATE UNIQUE INDEX idx_restaurants_google_place_id ON restaurants(space_id, google_place_id) WHERE google_place_id IS NOT NULL;
+CREATE UNIQUE INDEX idx_restaurants_name_address ON restaurants(space_id, LOWER(name), LOWER(address)) WHERE address IS NOT NULL;
+CREATE UNIQUE INDEX idx_suggestions_google_place_id ON restaurant_suggestions(space_id, google_place_id) WHERE google_place_id IS NOT NULL;
+CREATE UNIQUE INDEX idx_suggestions_name_address ON restaurant_suggestions(space_id, LOWER(name), LOWER(address)) WHERE address IS NOT NULL;
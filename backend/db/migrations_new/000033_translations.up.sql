@@ -0,0 +1,52 @@
+-- Translated names of categories and food types. The name stored on the category or
+-- food type itself is the English one and is used for locales without a translation.
+CREATE TABLE IF NOT EXISTS category_translations (
+    category_id INTEGER NOT NULL REFERENCES categories(id) ON DELETE CASCADE,
+    locale VARCHAR(10) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (category_id, locale)
+);
+
+CREATE TABLE IF NOT EXISTS food_type_translations (
+    food_type_id INTEGER NOT NULL REFERENCES food_types(id) ON DELETE CASCADE,
+    locale VARCHAR(10) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (food_type_id, locale)
+);
+
+-- German names of the categories and food types created by the initial schema
+INSERT INTO category_translations (category_id, locale, name)
+SELECT c.id, 'de', t.name
+FROM categories c
+JOIN (VALUES
+    ('Italian', 'Italienisch'),
+    ('Asian', 'Asiatisch'),
+    ('Mexican', 'Mexikanisch'),
+    ('American', 'Amerikanisch'),
+    ('French', 'Französisch'),
+    ('Indian', 'Indisch'),
+    ('Mediterranean', 'Mediterran'),
+    ('Japanese', 'Japanisch'),
+    ('Chinese', 'Chinesisch'),
+    ('Thai', 'Thailändisch')
+) AS t(original, name) ON t.original = c.name
+ON CONFLICT DO NOTHING;
+
+INSERT INTO food_type_translations (food_type_id, locale, name)
+SELECT f.id, 'de', t.name
+FROM food_types f
+JOIN (VALUES
+    ('Pizza', 'Pizza'),
+    ('Pasta', 'Pasta'),
+    ('Sushi', 'Sushi'),
+    ('Burgers', 'Burger'),
+    ('Tacos', 'Tacos'),
+    ('Curry', 'Curry'),
+    ('Steak', 'Steak'),
+    ('Seafood', 'Meeresfrüchte'),
+    ('Salads', 'Salate'),
+    ('Desserts', 'Desserts')
+) AS t(original, name) ON t.original = f.name
+ON CONFLICT DO NOTHING;
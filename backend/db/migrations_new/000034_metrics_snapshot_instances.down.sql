@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_metrics_snapshots_instance;
+ALTER TABLE metrics_snapshots DROP COLUMN IF EXISTS instance;
@@ -0,0 +1,5 @@
+-- The instance that recorded a snapshot. In cluster mode each replica counts its own
+-- requests and restores only its own snapshots; a single instance records ''.
+ALTER TABLE metrics_snapshots ADD COLUMN IF NOT EXISTS instance VARCHAR(255) NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_metrics_snapshots_instance ON metrics_snapshots(instance, recorded_at);
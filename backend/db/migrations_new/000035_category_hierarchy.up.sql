@@ -0,0 +1,6 @@
+-- Parent categories, e.g. "Japanese" and "Korean" under "Asian". Filtering by a parent
+-- also matches the places of its descendants. Deleting a parent moves its children to
+-- the top level.
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS parent_id INTEGER REFERENCES categories(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_categories_parent_id ON categories(parent_id);
@@ -0,0 +1,4 @@
+ALTER TABLE food_types DROP COLUMN IF EXISTS color;
+ALTER TABLE food_types DROP COLUMN IF EXISTS icon;
+ALTER TABLE categories DROP COLUMN IF EXISTS color;
+ALTER TABLE categories DROP COLUMN IF EXISTS icon;
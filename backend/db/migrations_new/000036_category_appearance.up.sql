@@ -0,0 +1,9 @@
+-- Icons (an emoji or a short name) and hex colors of categories and food types, for
+-- clients to render them, e.g. as map pins
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS icon VARCHAR(32);
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS color VARCHAR(7)
+    CONSTRAINT categories_color_check CHECK (color ~ '^#[0-9a-f]{6}$');
+
+ALTER TABLE food_types ADD COLUMN IF NOT EXISTS icon VARCHAR(32);
+ALTER TABLE food_types ADD COLUMN IF NOT EXISTS color VARCHAR(7)
+    CONSTRAINT food_types_color_check CHECK (color ~ '^#[0-9a-f]{6}$');
@@ -0,0 +1,3 @@
+ALTER TABLE ratings DROP COLUMN IF EXISTS owner_response_at;
+ALTER TABLE ratings DROP COLUMN IF EXISTS owner_response;
+DROP TABLE IF EXISTS restaurant_claims;
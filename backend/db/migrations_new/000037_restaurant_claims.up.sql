@@ -0,0 +1,24 @@
+-- Claims of users to own a restaurant's listing, reviewed by admins. The user of the
+-- approved claim is the restaurant's owner; a restaurant has at most one.
+CREATE TABLE IF NOT EXISTS restaurant_claims (
+    id SERIAL PRIMARY KEY,
+    restaurant_id INTEGER NOT NULL REFERENCES restaurants(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    message TEXT,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected')),
+    reviewed_by_user_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    reviewed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_restaurant_claims_owner
+    ON restaurant_claims(restaurant_id) WHERE status = 'approved';
+-- One pending claim per user and restaurant
+CREATE UNIQUE INDEX IF NOT EXISTS idx_restaurant_claims_pending
+    ON restaurant_claims(restaurant_id, user_id) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_restaurant_claims_user ON restaurant_claims(user_id);
+CREATE INDEX IF NOT EXISTS idx_restaurant_claims_reviewed_by ON restaurant_claims(reviewed_by_user_id);
+
+-- The official response of the restaurant's owner to a rating, one per rating
+ALTER TABLE ratings ADD COLUMN IF NOT EXISTS owner_response TEXT;
+ALTER TABLE ratings ADD COLUMN IF NOT EXISTS owner_response_at TIMESTAMP WITH TIME ZONE;
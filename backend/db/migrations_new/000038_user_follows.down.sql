@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_suggestions_user_created;
+DROP INDEX IF EXISTS idx_ratings_user_created;
+DROP TABLE IF EXISTS user_follows;
@@ -0,0 +1,15 @@
+-- Users following other users, whose ratings and suggestions the activity feed can be
+-- limited to
+CREATE TABLE IF NOT EXISTS user_follows (
+    follower_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    followee_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (follower_id, followee_id),
+    CHECK (follower_id <> followee_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_follows_followee ON user_follows(followee_id);
+
+-- The feed lists ratings and suggestions by user, newest first
+CREATE INDEX IF NOT EXISTS idx_ratings_user_created ON ratings(user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_suggestions_user_created ON restaurant_suggestions(user_id, created_at DESC);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_restaurants_suggested_by;
+ALTER TABLE restaurants DROP COLUMN IF EXISTS suggested_by_user_id;
@@ -0,0 +1,18 @@
+-- The author of the suggestion a restaurant was converted from, credited in the
+-- contribution stats and leaderboard
+ALTER TABLE restaurants ADD COLUMN IF NOT EXISTS suggested_by_user_id INTEGER REFERENCES users(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_restaurants_suggested_by ON restaurants(suggested_by_user_id, created_at)
+    WHERE suggested_by_user_id IS NOT NULL;
+
+-- Credit earlier conversions from the suggestion activity log: the author is the user of
+-- the first event, and the conversion note names the restaurant
+UPDATE restaurants r
+SET suggested_by_user_id = created.user_id
+FROM suggestion_events converted
+JOIN suggestion_events created ON created.suggestion_id = converted.suggestion_id AND created.from_status IS NULL
+WHERE converted.to_status = 'converted'
+    AND converted.note ~ '^Converted to restaurant #[0-9]+$'
+    AND r.id = substring(converted.note FROM '#([0-9]+)$')::integer
+    AND r.suggested_by_user_id IS NULL
+    AND created.user_id IS NOT NULL;
+
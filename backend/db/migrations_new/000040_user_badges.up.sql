@@ -0,0 +1,35 @@
+-- Badges awarded to users for contribution milestones. The badges and their thresholds are
+-- defined in code (handlers/badges.go) and stored by key.
+CREATE TABLE IF NOT EXISTS user_badges (
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    badge VARCHAR(50) NOT NULL,
+    awarded_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, badge)
+);
+
+-- Award the badges of contributions made before badges existed. Later badges are awarded
+-- as users contribute.
+INSERT INTO user_badges (user_id, badge)
+SELECT u.id, 'first_rating'
+FROM users u
+WHERE u.provider <> 'service' AND EXISTS (SELECT 1 FROM ratings rt WHERE rt.user_id = u.id)
+ON CONFLICT DO NOTHING;
+
+INSERT INTO user_badges (user_id, badge)
+SELECT rt.user_id, 'cuisine_explorer'
+FROM ratings rt
+JOIN users u ON u.id = rt.user_id
+JOIN restaurant_food_types rft ON rft.restaurant_id = rt.restaurant_id
+WHERE u.provider <> 'service'
+GROUP BY rt.user_id
+HAVING COUNT(DISTINCT rft.food_type_id) >= 10
+ON CONFLICT DO NOTHING;
+
+INSERT INTO user_badges (user_id, badge)
+SELECT r.suggested_by_user_id, 'trendsetter'
+FROM restaurants r
+JOIN users u ON u.id = r.suggested_by_user_id
+WHERE u.provider <> 'service'
+GROUP BY r.suggested_by_user_id
+HAVING COUNT(*) >= 5
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_suggestion_events_space_id;
+ALTER TABLE suggestion_events DROP COLUMN IF EXISTS space_id;
@@ -0,0 +1,21 @@
+-- Suggestion events outlive their suggestion (no foreign key), so they record the space
+-- of the suggestion and its history is only shown in that space
+ALTER TABLE suggestion_events ADD COLUMN IF NOT EXISTS space_id INTEGER REFERENCES spaces(id) ON DELETE CASCADE;
+CREATE INDEX IF NOT EXISTS idx_suggestion_events_space_id ON suggestion_events(space_id);
+
+UPDATE suggestion_events e
+SET space_id = s.space_id
+FROM restaurant_suggestions s
+WHERE s.id = e.suggestion_id AND e.space_id IS NULL;
+
+-- Suggestions converted or deleted before there were spaces were in the default space,
+-- created by 000032_spaces. The space of those gone since is unknown, so their history
+-- stays hidden.
+UPDATE suggestion_events e
+SET space_id = 1
+WHERE e.space_id IS NULL
+    AND NOT EXISTS (
+        SELECT 1 FROM suggestion_events later
+        WHERE later.suggestion_id = e.suggestion_id
+            AND later.created_at >= (SELECT created_at FROM spaces WHERE id = 1)
+    );
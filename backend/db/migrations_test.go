@@ -0,0 +1,30 @@
+package db
+
+import (
+	"io/fs"
+	"strings"
+	"testing"
+)
+
+func TestMigrationsEmbedded(t *testing.T) {
+	entries, err := fs.ReadDir(Migrations, MigrationsDir)
+	if err != nil {
+		t.Fatalf("Failed to read embedded migrations: %v", err)
+	}
+	if len(entries) == 0 {
+		t.Fatal("Expected embedded migrations")
+	}
+
+	files := make(map[string]bool)
+	for _, entry := range entries {
+		files[entry.Name()] = true
+	}
+	for name := range files {
+		if up, ok := strings.CutSuffix(name, ".up.sql"); ok && !files[up+".down.sql"] {
+			t.Errorf("Migration %s has no down migration", name)
+		}
+		if down, ok := strings.CutSuffix(name, ".down.sql"); ok && !files[down+".up.sql"] {
+			t.Errorf("Migration %s has no up migration", name)
+		}
+	}
+}
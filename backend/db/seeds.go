@@ -0,0 +1,16 @@
+package db
+
+import "embed"
+
+// Seeds holds the seed fixtures, one directory per fixture set, applied in file
+// name order
+//
+//go:embed seeds/*/*.sql
+var Seeds embed.FS
+
+// SeedSets lists the fixture sets loaded for each environment
+var SeedSets = map[string][]string{
+	"production":  {"base"},
+	"development": {"base", "demo"},
+	"ci":          {"base", "demo"},
+}
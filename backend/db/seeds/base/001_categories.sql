@@ -0,0 +1,19 @@
+-- Default categories
+INSERT INTO categories (name) VALUES
+    ('Italian'),
+    ('Asian'),
+    ('Mexican'),
+    ('American'),
+    ('French'),
+    ('Indian'),
+    ('Mediterranean'),
+    ('Japanese'),
+    ('Chinese'),
+    ('Thai'),
+    ('Vietnamese'),
+    ('Korean'),
+    ('Greek'),
+    ('Spanish'),
+    ('Middle Eastern'),
+    ('German')
+ON CONFLICT (name) DO NOTHING;
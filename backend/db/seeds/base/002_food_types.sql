@@ -0,0 +1,23 @@
+-- Common food types
+INSERT INTO food_types (name) VALUES
+    ('Pizza'),
+    ('Pasta'),
+    ('Sushi'),
+    ('Burgers'),
+    ('Tacos'),
+    ('Curry'),
+    ('Steak'),
+    ('Seafood'),
+    ('Salads'),
+    ('Desserts'),
+    ('Ramen'),
+    ('Pho'),
+    ('Dumplings'),
+    ('Kebab'),
+    ('Falafel'),
+    ('Sandwiches'),
+    ('Breakfast'),
+    ('Coffee'),
+    ('Vegetarian'),
+    ('Vegan')
+ON CONFLICT (name) DO NOTHING;
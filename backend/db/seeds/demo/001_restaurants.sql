@@ -0,0 +1,28 @@
+-- Sample restaurants, skipped when a restaurant with the same name and address exists
+INSERT INTO restaurants (name, description, address, phone, website, latitude, longitude, category_id)
+SELECT r.name, r.description, r.address, r.phone, r.website, r.latitude, r.longitude, c.id
+FROM (VALUES
+    ('Trattoria Da Luigi', 'Family-run trattoria with wood-fired pizza and fresh pasta', 'Via Roma 12, 20121 Milano', '+39 02 1234567', 'https://example.com/da-luigi', 45.46542190, 9.18592430, 'Italian'),
+    ('Sakura Sushi', 'Omakase counter and classic rolls', '1-2-3 Ginza, Chuo City, Tokyo', '+81 3-1234-5678', 'https://example.com/sakura', 35.67124300, 139.76503000, 'Japanese'),
+    ('Taquería El Sol', 'Street-style tacos al pastor and fresh salsas', 'Calle Madero 45, Centro, Ciudad de México', '+52 55 1234 5678', NULL, 19.43349500, -99.13894700, 'Mexican'),
+    ('Bangkok Kitchen', 'Spicy curries and noodle dishes', '88 Sukhumvit Rd, Bangkok', NULL, NULL, 13.73770000, 100.56030000, 'Thai'),
+    ('The Burger Joint', 'Smash burgers, fries and shakes', '118 W 57th St, New York, NY 10019', '+1 212-708-7414', 'https://example.com/burger-joint', 40.76447000, -73.97851000, 'American')
+) AS r (name, description, address, phone, website, latitude, longitude, category)
+LEFT JOIN categories c ON c.name = r.category
+ON CONFLICT (LOWER(name), LOWER(address)) WHERE address IS NOT NULL DO NOTHING;
+
+INSERT INTO restaurant_food_types (restaurant_id, food_type_id)
+SELECT r.id, ft.id
+FROM (VALUES
+    ('Trattoria Da Luigi', 'Pizza'),
+    ('Trattoria Da Luigi', 'Pasta'),
+    ('Sakura Sushi', 'Sushi'),
+    ('Sakura Sushi', 'Seafood'),
+    ('Taquería El Sol', 'Tacos'),
+    ('Bangkok Kitchen', 'Curry'),
+    ('The Burger Joint', 'Burgers'),
+    ('The Burger Joint', 'Desserts')
+) AS f (restaurant, food_type)
+JOIN restaurants r ON r.name = f.restaurant
+JOIN food_types ft ON ft.name = f.food_type
+ON CONFLICT DO NOTHING;
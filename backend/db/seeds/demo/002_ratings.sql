@@ -0,0 +1,13 @@
+-- Sample ratings for the sample restaurants that have none yet
+INSERT INTO ratings (restaurant_id, food_rating, service_rating, ambiance_rating, comment)
+SELECT r.id, s.food, s.service, s.ambiance, s.comment
+FROM (VALUES
+    ('Trattoria Da Luigi', 5, 4, 4, 'The best carbonara in town'),
+    ('Trattoria Da Luigi', 4, 5, 5, NULL),
+    ('Sakura Sushi', 5, 5, 4, 'Worth the wait for the omakase'),
+    ('Taquería El Sol', 5, 3, 3, 'Great tacos, expect a queue'),
+    ('Bangkok Kitchen', 4, 4, 3, NULL),
+    ('The Burger Joint', 4, 3, 4, 'Hidden behind the curtain in the hotel lobby')
+) AS s (restaurant, food, service, ambiance, comment)
+JOIN restaurants r ON r.name = s.restaurant
+WHERE NOT EXISTS (SELECT 1 FROM ratings WHERE restaurant_id = r.id);
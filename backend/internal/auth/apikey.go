@@ -0,0 +1,70 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+)
+
+// APIKeyPrefix marks nomdb API keys so they are easy to recognize, e.g. by secret scanners
+const APIKeyPrefix = "nomdb_"
+
+// API key scopes. write implies read; admin is additionally required on admin routes.
+const (
+	ScopeRead  = "read"
+	ScopeWrite = "write"
+	ScopeAdmin = "admin"
+)
+
+// ValidScopes lists the scopes a key can be issued with
+var ValidScopes = []string{ScopeRead, ScopeWrite, ScopeAdmin}
+
+// GenerateAPIKey creates a new random API key. It returns the key to hand to the user
+// once, a short prefix to identify it in listings and the hash to store.
+func GenerateAPIKey() (key, prefix, hash string, err error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", "", err
+	}
+	key = APIKeyPrefix + hex.EncodeToString(b)
+	return key, key[:len(APIKeyPrefix)+8], HashAPIKey(key), nil
+}
+
+// HashAPIKey returns the stored form of an API key. Keys are high-entropy random
+// values, so a fast hash is sufficient.
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// IsValidScope reports whether scope can be granted to a key
+func IsValidScope(scope string) bool {
+	for _, s := range ValidScopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// HasScope reports whether scopes grant scope, taking write to imply read
+func HasScope(scopes []string, scope string) bool {
+	for _, s := range scopes {
+		if s == scope || (s == ScopeWrite && scope == ScopeRead) {
+			return true
+		}
+	}
+	return false
+}
+
+// ScopeForMethod returns the scope needed for an HTTP method
+func ScopeForMethod(method string) string {
+	switch strings.ToUpper(method) {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return ScopeRead
+	default:
+		return ScopeWrite
+	}
+}
@@ -0,0 +1,59 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerateAPIKey(t *testing.T) {
+	key, prefix, hash, err := GenerateAPIKey()
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	if !strings.HasPrefix(key, APIKeyPrefix) || !strings.HasPrefix(key, prefix) {
+		t.Errorf("Expected key %s to start with %s and %s", key, APIKeyPrefix, prefix)
+	}
+	if hash != HashAPIKey(key) || strings.Contains(hash, key) {
+		t.Error("Expected the stored hash to be derived from, but not contain, the key")
+	}
+
+	other, _, _, _ := GenerateAPIKey()
+	if other == key {
+		t.Error("Expected distinct keys")
+	}
+}
+
+func TestHasScope(t *testing.T) {
+	tests := []struct {
+		name     string
+		scopes   []string
+		scope    string
+		expected bool
+	}{
+		{"Read key reads", []string{ScopeRead}, ScopeRead, true},
+		{"Read key cannot write", []string{ScopeRead}, ScopeWrite, false},
+		{"Write implies read", []string{ScopeWrite}, ScopeRead, true},
+		{"Write is not admin", []string{ScopeWrite}, ScopeAdmin, false},
+		{"Admin does not imply write", []string{ScopeAdmin}, ScopeWrite, false},
+		{"No scopes", nil, ScopeRead, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := HasScope(tt.scopes, tt.scope); got != tt.expected {
+				t.Errorf("HasScope(%v, %s) = %v, expected %v", tt.scopes, tt.scope, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestScopeForMethod(t *testing.T) {
+	for method, expected := range map[string]string{
+		"GET": ScopeRead, "HEAD": ScopeRead, "OPTIONS": ScopeRead,
+		"POST": ScopeWrite, "PUT": ScopeWrite, "PATCH": ScopeWrite, "DELETE": ScopeWrite,
+	} {
+		if got := ScopeForMethod(method); got != expected {
+			t.Errorf("ScopeForMethod(%s) = %s, expected %s", method, got, expected)
+		}
+	}
+}
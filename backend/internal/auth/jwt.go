@@ -30,14 +30,17 @@ type JWTService struct {
 	secretKey            []byte
 	accessTokenDuration  time.Duration
 	refreshTokenDuration time.Duration
+	rememberMeDuration   time.Duration
 }
 
-// NewJWTService creates a new JWT service
-func NewJWTService(secretKey string, accessTokenDuration, refreshTokenDuration time.Duration) *JWTService {
+// NewJWTService creates a new JWT service. Refresh tokens of "remember me" logins last
+// rememberMeDuration instead of refreshTokenDuration.
+func NewJWTService(secretKey string, accessTokenDuration, refreshTokenDuration, rememberMeDuration time.Duration) *JWTService {
 	return &JWTService{
 		secretKey:            []byte(secretKey),
 		accessTokenDuration:  accessTokenDuration,
 		refreshTokenDuration: refreshTokenDuration,
+		rememberMeDuration:   rememberMeDuration,
 	}
 }
 
@@ -104,3 +107,12 @@ func (s *JWTService) GetAccessTokenDuration() time.Duration {
 func (s *JWTService) GetRefreshTokenDuration() time.Duration {
 	return s.refreshTokenDuration
 }
+
+// RefreshTokenDurationFor returns the refresh token duration of a login, extended for
+// "remember me" logins
+func (s *JWTService) RefreshTokenDurationFor(rememberMe bool) time.Duration {
+	if rememberMe && s.rememberMeDuration > s.refreshTokenDuration {
+		return s.rememberMeDuration
+	}
+	return s.refreshTokenDuration
+}
@@ -0,0 +1,46 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nomdb/backend/internal/models"
+)
+
+func TestRefreshTokenDurationFor(t *testing.T) {
+	svc := NewJWTService("test-secret-key-with-at-least-32-chars", 15*time.Minute, 7*24*time.Hour, 90*24*time.Hour)
+
+	if d := svc.RefreshTokenDurationFor(false); d != 7*24*time.Hour {
+		t.Errorf("Expected the default refresh duration, got %v", d)
+	}
+	if d := svc.RefreshTokenDurationFor(true); d != 90*24*time.Hour {
+		t.Errorf("Expected the remember me duration, got %v", d)
+	}
+
+	// A remember me duration shorter than the default never shortens a session
+	short := NewJWTService("test-secret-key-with-at-least-32-chars", 15*time.Minute, 7*24*time.Hour, time.Hour)
+	if d := short.RefreshTokenDurationFor(true); d != 7*24*time.Hour {
+		t.Errorf("Expected the default refresh duration, got %v", d)
+	}
+}
+
+func TestAccessTokenDuration(t *testing.T) {
+	svc := NewJWTService("test-secret-key-with-at-least-32-chars", 5*time.Minute, time.Hour, time.Hour)
+
+	token, err := svc.GenerateAccessToken(&models.User{ID: 7, Email: "user@example.com", Username: "user"})
+	if err != nil {
+		t.Fatalf("Failed to generate access token: %v", err)
+	}
+	claims, err := svc.ValidateAccessToken(token)
+	if err != nil {
+		t.Fatalf("Failed to validate access token: %v", err)
+	}
+
+	lifetime := claims.ExpiresAt.Sub(claims.IssuedAt.Time)
+	if lifetime != 5*time.Minute {
+		t.Errorf("Expected a 5m access token, got %v", lifetime)
+	}
+	if claims.UserID != 7 {
+		t.Errorf("Expected user ID 7, got %d", claims.UserID)
+	}
+}
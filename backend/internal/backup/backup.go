@@ -0,0 +1,399 @@
+// Package backup exports the database as JSON lines and restores such exports.
+//
+// An export starts with a Header line followed by one line per row:
+//
+//	{"format":"nomdb-backup","version":1,"schema_version":25,"created_at":"..."}
+//	{"table":"categories","row":{"id":1,"name":"Italian",...}}
+//
+// Rows are written with row_to_json and read back with json_populate_recordset, so an
+// export can only be restored into a database at the same migration version.
+package backup
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/nomdb/backend/internal/database"
+)
+
+// Format identifies an export in its header
+const (
+	Format        = "nomdb-backup"
+	FormatVersion = 1
+)
+
+// lockKey is the advisory lock held by exports and restores, so replicas running the
+// scheduled backup do not export at the same time and nothing exports during a restore
+const lockKey int64 = 0x6e6f6d6462 + 1
+
+// restoreBatchSize is the number of rows inserted per statement on restore
+const restoreBatchSize = 500
+
+// excludedTables hold the migration state of a database rather than its data
+var excludedTables = []string{"schema_migrations", "schema_migrations_history"}
+
+var (
+	// ErrLocked is returned by Export when another export or restore is running
+	ErrLocked = errors.New("another backup or restore is running")
+	// ErrSchemaMismatch is returned by Restore when the export and the database are at
+	// different migration versions
+	ErrSchemaMismatch = errors.New("backup and database schema versions differ")
+)
+
+// Header is the first line of an export
+type Header struct {
+	Format        string    `json:"format"`
+	Version       int       `json:"version"`
+	SchemaVersion uint      `json:"schema_version"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// row is every line after the header
+type row struct {
+	Table string          `json:"table"`
+	Row   json.RawMessage `json:"row"`
+}
+
+// Stats counts the rows of an export or restore
+type Stats struct {
+	SchemaVersion uint           `json:"schema_version"`
+	Rows          int            `json:"rows"`
+	Tables        map[string]int `json:"tables"` // Rows per table
+}
+
+func (s *Stats) add(table string, n int) {
+	s.Rows += n
+	s.Tables[table] += n
+}
+
+// Export writes every table from a consistent snapshot of the database to w. It returns
+// ErrLocked without waiting if another export or restore holds the backup lock.
+func Export(ctx context.Context, db database.TxBeginner, w io.Writer) (*Stats, error) {
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, "SET TRANSACTION ISOLATION LEVEL REPEATABLE READ, READ ONLY"); err != nil {
+		return nil, err
+	}
+	var locked bool
+	if err := tx.QueryRow(ctx, "SELECT pg_try_advisory_xact_lock($1)", lockKey).Scan(&locked); err != nil {
+		return nil, err
+	}
+	if !locked {
+		return nil, ErrLocked
+	}
+
+	version, err := schemaVersion(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	tables, err := listTables(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &Stats{SchemaVersion: version, Tables: make(map[string]int, len(tables))}
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+	if err := enc.Encode(Header{Format: Format, Version: FormatVersion, SchemaVersion: version, CreatedAt: time.Now().UTC()}); err != nil {
+		return nil, err
+	}
+
+	for _, table := range tables {
+		rows, err := tx.Query(ctx, "SELECT row_to_json(t)::text FROM "+pgx.Identifier{table}.Sanitize()+" t")
+		if err != nil {
+			return nil, fmt.Errorf("failed to export %s: %w", table, err)
+		}
+		n := 0
+		for rows.Next() {
+			var data string
+			if err := rows.Scan(&data); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("failed to export %s: %w", table, err)
+			}
+			if err := enc.Encode(row{Table: table, Row: json.RawMessage(data)}); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			n++
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("failed to export %s: %w", table, err)
+		}
+		stats.add(table, n)
+	}
+
+	if err := bw.Flush(); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// Restore replaces the data of every table with the export read from r, plain or
+// gzip-compressed, in one transaction. The database must be migrated to the version
+// of the export. Foreign keys are dropped while loading and added back afterwards,
+// which validates them, and sequences continue after the restored IDs.
+func Restore(ctx context.Context, db database.TxBeginner, r io.Reader) (*Stats, error) {
+	br := bufio.NewReader(r)
+	var reader io.Reader = br
+	if magic, err := br.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read backup: %w", err)
+		}
+		defer gz.Close()
+		reader = gz
+	}
+
+	dec := json.NewDecoder(reader)
+	var header Header
+	if err := dec.Decode(&header); err != nil {
+		return nil, fmt.Errorf("failed to read backup header: %w", err)
+	}
+	if header.Format != Format || header.Version != FormatVersion {
+		return nil, fmt.Errorf("not a %s version %d file", Format, FormatVersion)
+	}
+
+	stats := &Stats{SchemaVersion: header.SchemaVersion, Tables: make(map[string]int)}
+	err := database.RunInTx(ctx, db, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, "SELECT pg_advisory_xact_lock($1)", lockKey); err != nil {
+			return err
+		}
+
+		version, err := schemaVersion(ctx, tx)
+		if err != nil {
+			return err
+		}
+		if version != header.SchemaVersion {
+			return fmt.Errorf("%w: backup is at version %d, database at %d", ErrSchemaMismatch, header.SchemaVersion, version)
+		}
+
+		tables, err := listTables(ctx, tx)
+		if err != nil {
+			return err
+		}
+		columns, err := insertableColumns(ctx, tx)
+		if err != nil {
+			return err
+		}
+		foreignKeys, err := dropForeignKeys(ctx, tx)
+		if err != nil {
+			return err
+		}
+
+		quoted := make([]string, len(tables))
+		for i, table := range tables {
+			quoted[i] = pgx.Identifier{table}.Sanitize()
+		}
+		if _, err := tx.Exec(ctx, "TRUNCATE "+strings.Join(quoted, ", ")); err != nil {
+			return fmt.Errorf("failed to truncate tables: %w", err)
+		}
+
+		var table string
+		var batch []json.RawMessage
+		flush := func() error {
+			if len(batch) == 0 {
+				return nil
+			}
+			if err := insertRows(ctx, tx, table, columns[table], batch); err != nil {
+				return err
+			}
+			stats.add(table, len(batch))
+			batch = batch[:0]
+			return nil
+		}
+		for {
+			var line row
+			if err := dec.Decode(&line); err == io.EOF {
+				break
+			} else if err != nil {
+				return fmt.Errorf("failed to read backup: %w", err)
+			}
+			if _, ok := columns[line.Table]; !ok {
+				return fmt.Errorf("backup contains unknown table %q", line.Table)
+			}
+			if line.Table != table || len(batch) == restoreBatchSize {
+				if err := flush(); err != nil {
+					return err
+				}
+				table = line.Table
+			}
+			batch = append(batch, line.Row)
+		}
+		if err := flush(); err != nil {
+			return err
+		}
+
+		if err := resetSequences(ctx, tx); err != nil {
+			return err
+		}
+		for _, fk := range foreignKeys {
+			if _, err := tx.Exec(ctx, "ALTER TABLE "+fk.table+" ADD CONSTRAINT "+pgx.Identifier{fk.name}.Sanitize()+" "+fk.definition); err != nil {
+				return fmt.Errorf("failed to restore foreign key %s: %w", fk.name, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// schemaVersion returns the migration version of a clean database
+func schemaVersion(ctx context.Context, tx pgx.Tx) (uint, error) {
+	var version int64
+	var dirty bool
+	if err := tx.QueryRow(ctx, "SELECT version, dirty FROM schema_migrations").Scan(&version, &dirty); err != nil {
+		return 0, fmt.Errorf("failed to read schema version: %w", err)
+	}
+	if dirty {
+		return 0, fmt.Errorf("schema version %d is dirty", version)
+	}
+	return uint(version), nil
+}
+
+// listTables returns the tables in the public schema that hold data
+func listTables(ctx context.Context, tx pgx.Tx) ([]string, error) {
+	rows, err := tx.Query(ctx, `
+		SELECT tablename FROM pg_tables
+		WHERE schemaname = 'public' AND NOT (tablename = ANY($1))
+		ORDER BY tablename
+	`, excludedTables)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tables: %w", err)
+	}
+	return pgx.CollectRows(rows, pgx.RowTo[string])
+}
+
+// insertableColumns returns the quoted non-generated columns of every public table
+func insertableColumns(ctx context.Context, tx pgx.Tx) (map[string][]string, error) {
+	rows, err := tx.Query(ctx, `
+		SELECT table_name, array_agg(quote_ident(column_name) ORDER BY ordinal_position)
+		FROM information_schema.columns
+		WHERE table_schema = 'public' AND is_generated = 'NEVER'
+		GROUP BY table_name
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list columns: %w", err)
+	}
+	defer rows.Close()
+
+	columns := make(map[string][]string)
+	for rows.Next() {
+		var table string
+		var cols []string
+		if err := rows.Scan(&table, &cols); err != nil {
+			return nil, err
+		}
+		columns[table] = cols
+	}
+	return columns, rows.Err()
+}
+
+type foreignKey struct {
+	table      string // Already quoted
+	name       string
+	definition string
+}
+
+// dropForeignKeys drops the foreign keys of the public schema and returns them, so
+// tables can be loaded in any order, including tables that reference each other
+func dropForeignKeys(ctx context.Context, tx pgx.Tx) ([]foreignKey, error) {
+	rows, err := tx.Query(ctx, `
+		SELECT conrelid::regclass::text, conname, pg_get_constraintdef(oid)
+		FROM pg_constraint
+		WHERE contype = 'f' AND connamespace = 'public'::regnamespace
+		ORDER BY conrelid::regclass::text, conname
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list foreign keys: %w", err)
+	}
+	var foreignKeys []foreignKey
+	for rows.Next() {
+		var fk foreignKey
+		if err := rows.Scan(&fk.table, &fk.name, &fk.definition); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		foreignKeys = append(foreignKeys, fk)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, fk := range foreignKeys {
+		if _, err := tx.Exec(ctx, "ALTER TABLE "+fk.table+" DROP CONSTRAINT "+pgx.Identifier{fk.name}.Sanitize()); err != nil {
+			return nil, fmt.Errorf("failed to drop foreign key %s: %w", fk.name, err)
+		}
+	}
+	return foreignKeys, nil
+}
+
+// insertRows inserts a batch of exported rows into table
+func insertRows(ctx context.Context, tx pgx.Tx, table string, columns []string, batch []json.RawMessage) error {
+	quoted := pgx.Identifier{table}.Sanitize()
+	cols := strings.Join(columns, ", ")
+	data, err := json.Marshal(batch)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx,
+		"INSERT INTO "+quoted+" ("+cols+") SELECT "+cols+" FROM json_populate_recordset(NULL::"+quoted+", $1::json)",
+		string(data)); err != nil {
+		return fmt.Errorf("failed to restore %s: %w", table, err)
+	}
+	return nil
+}
+
+// resetSequences moves every serial sequence past the highest restored ID
+func resetSequences(ctx context.Context, tx pgx.Tx) error {
+	rows, err := tx.Query(ctx, `
+		SELECT quote_ident(table_name), quote_ident(column_name), seq
+		FROM (
+			SELECT table_name, column_name, pg_get_serial_sequence(quote_ident(table_name), column_name) AS seq
+			FROM information_schema.columns
+			WHERE table_schema = 'public'
+		) c
+		WHERE seq IS NOT NULL
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to list sequences: %w", err)
+	}
+	type sequence struct{ table, column, name string }
+	var sequences []sequence
+	for rows.Next() {
+		var s sequence
+		if err := rows.Scan(&s.table, &s.column, &s.name); err != nil {
+			rows.Close()
+			return err
+		}
+		sequences = append(sequences, s)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, s := range sequences {
+		if _, err := tx.Exec(ctx,
+			"SELECT setval($1, COALESCE((SELECT MAX("+s.column+") FROM "+s.table+"), 0) + 1, false)",
+			s.name); err != nil {
+			return fmt.Errorf("failed to reset sequence %s: %w", s.name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package backup
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/pashagolub/pgxmock/v4"
+)
+
+func newMock(t *testing.T) pgxmock.PgxPoolIface {
+	t.Helper()
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatalf("Failed to create mock: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Unmet expectations: %v", err)
+		}
+		mock.Close()
+	})
+	return mock
+}
+
+func expectSnapshot(mock pgxmock.PgxPoolIface, locked bool) {
+	mock.ExpectBegin()
+	mock.ExpectExec(`SET TRANSACTION ISOLATION LEVEL REPEATABLE READ, READ ONLY`).WillReturnResult(pgxmock.NewResult("SET", 0))
+	mock.ExpectQuery(`pg_try_advisory_xact_lock`).WithArgs(lockKey).
+		WillReturnRows(pgxmock.NewRows([]string{"locked"}).AddRow(locked))
+}
+
+func TestExport(t *testing.T) {
+	mock := newMock(t)
+	expectSnapshot(mock, true)
+	mock.ExpectQuery(`SELECT version, dirty FROM schema_migrations`).
+		WillReturnRows(pgxmock.NewRows([]string{"version", "dirty"}).AddRow(int64(25), false))
+	mock.ExpectQuery(`FROM pg_tables`).WithArgs(excludedTables).
+		WillReturnRows(pgxmock.NewRows([]string{"tablename"}).AddRow("categories").AddRow("food_types"))
+	mock.ExpectQuery(`SELECT row_to_json\(t\)::text FROM "categories" t`).
+		WillReturnRows(pgxmock.NewRows([]string{"row_to_json"}).
+			AddRow(`{"id":1,"name":"Italian"}`).AddRow(`{"id":2,"name":"Thai"}`))
+	mock.ExpectQuery(`SELECT row_to_json\(t\)::text FROM "food_types" t`).
+		WillReturnRows(pgxmock.NewRows([]string{"row_to_json"}))
+	mock.ExpectRollback()
+
+	var buf bytes.Buffer
+	stats, err := Export(context.Background(), mock, &buf)
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	if stats.SchemaVersion != 25 || stats.Rows != 2 || stats.Tables["categories"] != 2 || stats.Tables["food_types"] != 0 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected header and 2 rows, got %d lines: %s", len(lines), buf.String())
+	}
+	var header Header
+	if err := json.Unmarshal([]byte(lines[0]), &header); err != nil || header.Format != Format || header.SchemaVersion != 25 {
+		t.Errorf("Unexpected header %s (%v)", lines[0], err)
+	}
+	if lines[1] != `{"table":"categories","row":{"id":1,"name":"Italian"}}` {
+		t.Errorf("Unexpected row %s", lines[1])
+	}
+}
+
+func TestExport_Locked(t *testing.T) {
+	mock := newMock(t)
+	expectSnapshot(mock, false)
+	mock.ExpectRollback()
+
+	if _, err := Export(context.Background(), mock, &bytes.Buffer{}); !errors.Is(err, ErrLocked) {
+		t.Errorf("Expected ErrLocked, got %v", err)
+	}
+}
+
+func backupFile(t *testing.T, schemaVersion uint, rows ...string) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	header, _ := json.Marshal(Header{Format: Format, Version: FormatVersion, SchemaVersion: schemaVersion})
+	gz.Write(append(header, '\n'))
+	for _, r := range rows {
+		gz.Write([]byte(r + "\n"))
+	}
+	gz.Close()
+	return &buf
+}
+
+func TestRestore(t *testing.T) {
+	mock := newMock(t)
+	ctx := context.Background()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`pg_advisory_xact_lock`).WithArgs(lockKey).WillReturnResult(pgxmock.NewResult("SELECT", 1))
+	mock.ExpectQuery(`SELECT version, dirty FROM schema_migrations`).
+		WillReturnRows(pgxmock.NewRows([]string{"version", "dirty"}).AddRow(int64(25), false))
+	mock.ExpectQuery(`FROM pg_tables`).WithArgs(excludedTables).
+		WillReturnRows(pgxmock.NewRows([]string{"tablename"}).AddRow("categories").AddRow("restaurants"))
+	mock.ExpectQuery(`FROM information_schema.columns\s+WHERE table_schema = 'public' AND is_generated`).
+		WillReturnRows(pgxmock.NewRows([]string{"table_name", "columns"}).
+			AddRow("categories", []string{"id", "name"}).
+			AddRow("restaurants", []string{"id", "name", "category_id"}))
+	mock.ExpectQuery(`FROM pg_constraint`).
+		WillReturnRows(pgxmock.NewRows([]string{"table", "name", "definition"}).
+			AddRow("restaurants", "restaurants_category_id_fkey", "FOREIGN KEY (category_id) REFERENCES categories(id) ON DELETE SET NULL"))
+	mock.ExpectExec(`ALTER TABLE restaurants DROP CONSTRAINT "restaurants_category_id_fkey"`).WillReturnResult(pgxmock.NewResult("ALTER", 0))
+	mock.ExpectExec(`TRUNCATE "categories", "restaurants"`).WillReturnResult(pgxmock.NewResult("TRUNCATE", 0))
+	// Restaurants first: foreign keys are dropped, so the order of the file does not matter
+	mock.ExpectExec(`INSERT INTO "restaurants" \(id, name, category_id\) SELECT id, name, category_id FROM json_populate_recordset\(NULL::"restaurants", \$1::json\)`).
+		WithArgs(`[{"id":3,"name":"Luigi's","category_id":1}]`).WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	mock.ExpectExec(`INSERT INTO "categories"`).
+		WithArgs(`[{"id":1,"name":"Italian"},{"id":2,"name":"Thai"}]`).WillReturnResult(pgxmock.NewResult("INSERT", 2))
+	mock.ExpectQuery(`pg_get_serial_sequence`).
+		WillReturnRows(pgxmock.NewRows([]string{"table", "column", "seq"}).AddRow("categories", "id", "public.categories_id_seq"))
+	mock.ExpectExec(`SELECT setval\(\$1, COALESCE\(\(SELECT MAX\(id\) FROM categories\), 0\) \+ 1, false\)`).
+		WithArgs("public.categories_id_seq").WillReturnResult(pgxmock.NewResult("SELECT", 1))
+	mock.ExpectExec(`ALTER TABLE restaurants ADD CONSTRAINT "restaurants_category_id_fkey" FOREIGN KEY`).WillReturnResult(pgxmock.NewResult("ALTER", 0))
+	mock.ExpectCommit()
+
+	file := backupFile(t, 25,
+		`{"table":"restaurants","row":{"id":3,"name":"Luigi's","category_id":1}}`,
+		`{"table":"categories","row":{"id":1,"name":"Italian"}}`,
+		`{"table":"categories","row":{"id":2,"name":"Thai"}}`,
+	)
+	stats, err := Restore(ctx, mock, file)
+	if err != nil {
+		t.Fatalf("Restore failed: %v", err)
+	}
+	if stats.Rows != 3 || stats.Tables["categories"] != 2 || stats.Tables["restaurants"] != 1 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+}
+
+func TestRestore_SchemaMismatch(t *testing.T) {
+	mock := newMock(t)
+	mock.ExpectBegin()
+	mock.ExpectExec(`pg_advisory_xact_lock`).WithArgs(lockKey).WillReturnResult(pgxmock.NewResult("SELECT", 1))
+	mock.ExpectQuery(`SELECT version, dirty FROM schema_migrations`).
+		WillReturnRows(pgxmock.NewRows([]string{"version", "dirty"}).AddRow(int64(26), false))
+	mock.ExpectRollback()
+
+	if _, err := Restore(context.Background(), mock, backupFile(t, 25)); !errors.Is(err, ErrSchemaMismatch) {
+		t.Errorf("Expected ErrSchemaMismatch, got %v", err)
+	}
+}
+
+func TestRestore_NotABackup(t *testing.T) {
+	mock := newMock(t)
+	if _, err := Restore(context.Background(), mock, strings.NewReader(`{"format":"other"}`)); err == nil {
+		t.Error("Expected an error for a file that is not a backup")
+	}
+}
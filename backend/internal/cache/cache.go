@@ -0,0 +1,197 @@
+// Package cache caches the JSON of hot, rarely changing read endpoints in process or in
+// Redis. Handlers invalidate the affected keys on writes, and the invalidation is
+// broadcast to the other instances over the event bus, which clear their in-process
+// cache and track when each key last changed for Last-Modified headers. The TTL bounds
+// staleness for changes made elsewhere (background jobs, missed broadcasts).
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/nomdb/backend/internal/events"
+	"github.com/nomdb/backend/internal/logger"
+)
+
+// Cache backends selectable via CACHE_BACKEND
+const (
+	BackendMemory = "memory"
+	BackendRedis  = "redis"
+	BackendNone   = "none"
+)
+
+// Cached responses
+const (
+	KeyCategories   = "categories"
+	KeyFoodTypes    = "food_types"
+	KeyRestaurants  = "restaurants"  // Unfiltered restaurant list
+	KeyTranslations = "translations" // Category and food type names in all locales
+)
+
+// Cache is a key-value store with expiring entries
+type Cache interface {
+	// Name returns the backend identifier for logging
+	Name() string
+	// Get returns the value of key, and false if it is not cached
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	// Set stores value under key for ttl
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// Delete removes keys; missing keys are ignored
+	Delete(ctx context.Context, keys ...string) error
+}
+
+// Config selects and configures a cache backend
+type Config struct {
+	Backend  string
+	TTL      time.Duration
+	RedisURL string
+}
+
+var (
+	store   Cache = noopCache{}
+	backend Cache = noopCache{} // Also when a zero TTL disables response caching
+	ttl     time.Duration
+
+	subscribeOnce sync.Once
+
+	// When keys were last invalidated; keys never invalidated changed at allModified,
+	// the start of the process or the last resync
+	modifiedMu  sync.RWMutex
+	modified    = map[string]time.Time{}
+	allModified = time.Now()
+)
+
+// Init creates the configured cache backend
+func Init(cfg Config) error {
+	var c Cache
+	switch cfg.Backend {
+	case BackendMemory, "":
+		c = NewMemoryCache()
+	case BackendRedis:
+		var err error
+		if c, err = NewRedisCache(cfg.RedisURL); err != nil {
+			return fmt.Errorf("failed to initialize redis cache: %w", err)
+		}
+	case BackendNone:
+		c = noopCache{}
+	default:
+		return fmt.Errorf("unknown cache backend %q", cfg.Backend)
+	}
+
+	store, backend = c, c
+	ttl = cfg.TTL
+	if cfg.TTL <= 0 {
+		store = noopCache{}
+	}
+	subscribeOnce.Do(func() { events.Subscribe(handleEvent) })
+	logger.Info("✅ Response cache: %s (TTL %s)", store.Name(), cfg.TTL)
+	return nil
+}
+
+// Get returns the initialized cache backend
+func Get() Cache {
+	return store
+}
+
+// Backend returns the configured backend regardless of the response TTL, for callers
+// caching with their own TTLs
+func Backend() Cache {
+	return backend
+}
+
+// GetJSON decodes the cached value of key into v and reports whether it was cached.
+// Cache errors are logged and reported as a miss, so they never fail a request.
+func GetJSON(ctx context.Context, key string, v any) bool {
+	data, ok, err := store.Get(ctx, key)
+	if err != nil {
+		logger.Warn("Cache get %s failed: %v", key, err)
+		return false
+	}
+	if !ok {
+		return false
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		logger.Warn("Cache entry %s is invalid: %v", key, err)
+		return false
+	}
+	return true
+}
+
+// SetJSON caches v under key for the configured TTL, logging errors
+func SetJSON(ctx context.Context, key string, v any) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		logger.Warn("Failed to encode cache entry %s: %v", key, err)
+		return
+	}
+	if err := store.Set(ctx, key, data, ttl); err != nil {
+		logger.Warn("Cache set %s failed: %v", key, err)
+	}
+}
+
+// Invalidate removes keys after a write, logging errors. In-process caches of the
+// other instances are invalidated through the event bus.
+func Invalidate(ctx context.Context, keys ...string) {
+	if err := store.Delete(ctx, keys...); err != nil {
+		logger.Warn("Cache invalidation of %v failed: %v", keys, err)
+	}
+	markModified(keys)
+	events.Publish(ctx, events.Event{Type: events.TypeCacheInvalidated, Keys: keys})
+}
+
+// Modified returns when the data cached under key last changed, as far as this
+// instance knows
+func Modified(key string) time.Time {
+	modifiedMu.RLock()
+	defer modifiedMu.RUnlock()
+	if t, ok := modified[key]; ok && t.After(allModified) {
+		return t
+	}
+	return allModified
+}
+
+func markModified(keys []string) {
+	now := time.Now()
+	modifiedMu.Lock()
+	defer modifiedMu.Unlock()
+	for _, key := range keys {
+		modified[key] = now
+	}
+}
+
+// handleEvent applies invalidations of other instances to the in-process cache
+func handleEvent(e events.Event) {
+	if !e.Remote {
+		return
+	}
+	memory, _ := store.(*MemoryCache)
+	switch e.Type {
+	case events.TypeCacheInvalidated:
+		markModified(e.Keys)
+		if memory != nil {
+			memory.Delete(context.Background(), e.Keys...)
+		}
+	case events.TypeResync:
+		// Invalidations may have been missed while the listener was disconnected
+		modifiedMu.Lock()
+		allModified = time.Now()
+		modifiedMu.Unlock()
+		if memory != nil {
+			memory.clear()
+		}
+	}
+}
+
+// noopCache caches nothing; used when caching is disabled and before Init
+type noopCache struct{}
+
+func (noopCache) Name() string { return BackendNone }
+
+func (noopCache) Get(context.Context, string) ([]byte, bool, error) { return nil, false, nil }
+
+func (noopCache) Set(context.Context, string, []byte, time.Duration) error { return nil }
+
+func (noopCache) Delete(context.Context, ...string) error { return nil }
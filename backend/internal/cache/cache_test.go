@@ -0,0 +1,191 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/nomdb/backend/internal/events"
+)
+
+func testBackend(t *testing.T, c Cache) {
+	t.Helper()
+	ctx := context.Background()
+
+	if _, ok, err := c.Get(ctx, "missing"); ok || err != nil {
+		t.Errorf("Expected a miss for a missing key, got ok=%v err=%v", ok, err)
+	}
+	if err := c.Set(ctx, "a", []byte("1"), time.Minute); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if err := c.Set(ctx, "b", []byte("2"), time.Minute); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if value, ok, err := c.Get(ctx, "a"); !ok || err != nil || string(value) != "1" {
+		t.Errorf("Expected a hit with 1, got %q ok=%v err=%v", value, ok, err)
+	}
+	if err := c.Delete(ctx, "a", "missing"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if _, ok, _ := c.Get(ctx, "a"); ok {
+		t.Error("Expected a miss after Delete")
+	}
+	if _, ok, _ := c.Get(ctx, "b"); !ok {
+		t.Error("Expected other keys to stay cached")
+	}
+}
+
+func TestMemoryCache(t *testing.T) {
+	testBackend(t, NewMemoryCache())
+}
+
+func TestMemoryCache_Expiry(t *testing.T) {
+	c := NewMemoryCache()
+	now := time.Now()
+	c.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	c.Set(ctx, "a", []byte("1"), time.Minute)
+	now = now.Add(59 * time.Second)
+	if _, ok, _ := c.Get(ctx, "a"); !ok {
+		t.Error("Expected a hit before the TTL")
+	}
+	now = now.Add(time.Second)
+	if _, ok, _ := c.Get(ctx, "a"); ok {
+		t.Error("Expected a miss after the TTL")
+	}
+
+	c.Set(ctx, "b", []byte("2"), time.Minute)
+	if _, ok := c.entries["a"]; ok {
+		t.Error("Expected expired entries to be dropped on Set")
+	}
+}
+
+func TestRedisCache(t *testing.T) {
+	server := miniredis.RunT(t)
+	c, err := NewRedisCache("redis://" + server.Addr())
+	if err != nil {
+		t.Fatalf("NewRedisCache failed: %v", err)
+	}
+	testBackend(t, c)
+
+	if !server.Exists(redisKeyPrefix + "b") {
+		t.Error("Expected keys to be prefixed")
+	}
+	server.FastForward(time.Minute)
+	if _, ok, _ := c.Get(context.Background(), "b"); ok {
+		t.Error("Expected a miss after the TTL")
+	}
+}
+
+func TestNewRedisCache_Invalid(t *testing.T) {
+	for _, url := range []string{"", "http://localhost"} {
+		if _, err := NewRedisCache(url); err == nil {
+			t.Errorf("Expected an error for %q", url)
+		}
+	}
+}
+
+func TestJSONHelpers(t *testing.T) {
+	t.Cleanup(func() { store, ttl = noopCache{}, 0 })
+	if err := Init(Config{Backend: BackendMemory, TTL: time.Minute}); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	ctx := context.Background()
+
+	var names []string
+	if GetJSON(ctx, KeyCategories, &names) {
+		t.Fatal("Expected a miss before SetJSON")
+	}
+	SetJSON(ctx, KeyCategories, []string{"Italian", "Thai"})
+	if !GetJSON(ctx, KeyCategories, &names) || len(names) != 2 || names[1] != "Thai" {
+		t.Errorf("Expected the cached list, got %v", names)
+	}
+
+	// An entry that does not decode into v is a miss
+	var n int
+	if GetJSON(ctx, KeyCategories, &n) {
+		t.Error("Expected a miss for an entry of another type")
+	}
+
+	Invalidate(ctx, KeyCategories)
+	if GetJSON(ctx, KeyCategories, &names) {
+		t.Error("Expected a miss after Invalidate")
+	}
+}
+
+func TestRemoteInvalidation(t *testing.T) {
+	t.Cleanup(func() { store, ttl = noopCache{}, 0 })
+	if err := Init(Config{Backend: BackendMemory, TTL: time.Minute}); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	ctx := context.Background()
+
+	var published []events.Event
+	unsubscribe := events.Subscribe(func(e events.Event) { published = append(published, e) })
+	defer unsubscribe()
+
+	SetJSON(ctx, KeyCategories, []string{"Italian"})
+	SetJSON(ctx, KeyFoodTypes, []string{"Pizza"})
+	Invalidate(ctx, KeyCategories)
+	if len(published) != 1 || published[0].Type != events.TypeCacheInvalidated || published[0].Keys[0] != KeyCategories {
+		t.Errorf("Expected the invalidation to be published, got %+v", published)
+	}
+
+	// Invalidations of other instances delete local entries
+	SetJSON(ctx, KeyCategories, []string{"Italian"})
+	handleEvent(events.Event{Type: events.TypeCacheInvalidated, Keys: []string{KeyCategories}, Remote: true})
+	var names []string
+	if GetJSON(ctx, KeyCategories, &names) {
+		t.Error("Expected a miss after a remote invalidation")
+	}
+	if !GetJSON(ctx, KeyFoodTypes, &names) {
+		t.Error("Expected other keys to stay cached")
+	}
+
+	handleEvent(events.Event{Type: events.TypeResync, Remote: true})
+	if GetJSON(ctx, KeyFoodTypes, &names) {
+		t.Error("Expected a resync to clear the cache")
+	}
+}
+
+func TestModified(t *testing.T) {
+	before := Modified(KeyFoodTypes)
+	time.Sleep(time.Millisecond)
+
+	Invalidate(context.Background(), KeyCategories)
+	if !Modified(KeyCategories).After(before) {
+		t.Error("Expected an invalidation to update the modification time")
+	}
+	if !Modified(KeyFoodTypes).Equal(before) {
+		t.Error("Expected other keys to keep their modification time")
+	}
+
+	time.Sleep(time.Millisecond)
+	handleEvent(events.Event{Type: events.TypeCacheInvalidated, Keys: []string{KeyFoodTypes}, Remote: true})
+	if !Modified(KeyFoodTypes).After(before) {
+		t.Error("Expected a remote invalidation to update the modification time")
+	}
+
+	time.Sleep(time.Millisecond)
+	restaurants := Modified(KeyRestaurants)
+	handleEvent(events.Event{Type: events.TypeResync, Remote: true})
+	if !Modified(KeyRestaurants).After(restaurants) {
+		t.Error("Expected a resync to update every modification time")
+	}
+}
+
+func TestInit(t *testing.T) {
+	t.Cleanup(func() { store, ttl = noopCache{}, 0 })
+
+	if err := Init(Config{Backend: "memcached", TTL: time.Minute}); err == nil {
+		t.Error("Expected an error for an unknown backend")
+	}
+	if err := Init(Config{Backend: BackendMemory}); err != nil || Get().Name() != BackendNone {
+		t.Errorf("Expected a zero TTL to disable caching, got %s (%v)", Get().Name(), err)
+	}
+	if err := Init(Config{Backend: BackendMemory, TTL: time.Minute}); err != nil || Get().Name() != BackendMemory {
+		t.Errorf("Expected the memory backend, got %s (%v)", Get().Name(), err)
+	}
+}
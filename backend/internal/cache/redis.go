@@ -0,0 +1,76 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// redisKeyPrefix namespaces the cache keys in a shared Redis
+const redisKeyPrefix = "nomdb:cache:"
+
+// RedisCache caches in Redis, shared by every instance
+type RedisCache struct {
+	client *redis.Client
+}
+
+// NewRedisCache connects to the Redis server at url (redis://[user:password@]host:port/db)
+func NewRedisCache(url string) (*RedisCache, error) {
+	client, err := NewRedisClient(url)
+	if err != nil {
+		return nil, err
+	}
+	return &RedisCache{client: client}, nil
+}
+
+// NewRedisClient connects to the Redis server at url, for state other packages share
+// between instances in the same Redis
+func NewRedisClient(url string) (*redis.Client, error) {
+	if url == "" {
+		return nil, errors.New("REDIS_URL is required")
+	}
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, fmt.Errorf("invalid REDIS_URL: %w", err)
+	}
+	client := redis.NewClient(opts)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to connect to redis: %w", err)
+	}
+	return client, nil
+}
+
+func (c *RedisCache) Name() string { return BackendRedis }
+
+func (c *RedisCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	value, err := c.client.Get(ctx, redisKeyPrefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+func (c *RedisCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return c.client.Set(ctx, redisKeyPrefix+key, value, ttl).Err()
+}
+
+func (c *RedisCache) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	prefixed := make([]string, len(keys))
+	for i, key := range keys {
+		prefixed[i] = redisKeyPrefix + key
+	}
+	return c.client.Del(ctx, prefixed...).Err()
+}
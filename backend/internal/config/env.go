@@ -1,20 +1,96 @@
 package config
 
 import (
+	"encoding/base64"
 	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/nomdb/backend/internal/logger"
 )
 
+// Strict security header defaults for a JSON API; the Swagger UI needs inline scripts and
+// styles, and the frontend loads Google Maps and shows photos from storage backends
+const (
+	defaultContentSecurityPolicy         = "default-src 'none'; frame-ancestors 'none'; base-uri 'none'; form-action 'none'"
+	defaultDocsContentSecurityPolicy     = "default-src 'self'; script-src 'self' 'unsafe-inline'; style-src 'self' 'unsafe-inline'; img-src 'self' data:; frame-ancestors 'none'"
+	defaultFrontendContentSecurityPolicy = "default-src 'self'; script-src 'self' https://maps.googleapis.com; style-src 'self' 'unsafe-inline' https://fonts.googleapis.com; font-src 'self' https://fonts.gstatic.com; img-src 'self' data: blob: https:; connect-src 'self' https://maps.googleapis.com; frame-ancestors 'none'; base-uri 'self'"
+	defaultPermissionsPolicy             = "geolocation=(), microphone=(), camera=(), payment=(), usb=()"
+	defaultFrontendPermissionsPolicy     = "geolocation=(self), microphone=(), camera=(), payment=(), usb=()" // "Near me" search
+)
+
+// Access levels of the metrics and API docs endpoints
+const (
+	OpsAccessPublic   = "public"
+	OpsAccessAdmin    = "admin"    // Admin session or API key with the admin scope
+	OpsAccessInternal = "internal" // Only served on INTERNAL_ADDR
+	OpsAccessDisabled = "disabled"
+)
+
+// Modes of the startup preflight checks
+const (
+	PreflightWarn   = "warn"
+	PreflightStrict = "strict"
+	PreflightOff    = "off"
+)
+
 // Config holds all configuration for the application
 type Config struct {
 	// Database
 	DatabaseURL string
 
 	// Google Maps
-	GoogleMapsAPIKey string
+	GoogleMapsAPIKey           string
+	GoogleMapsTimeout          time.Duration // Per attempt
+	GoogleMapsMaxRetries       int
+	GoogleMapsBreakerThreshold int // Consecutive failed calls opening the circuit breaker (0 = disabled)
+	GoogleMapsBreakerCooldown  time.Duration
+	GooglePlacesAPI            string // "legacy" (Places Web Service) or "new" (Places API (New))
+	GoogleMapsSigningSecret    string // Base64url URL signing secret for Static Maps requests (optional)
+	GooglePlacesSearchType     string // Place type searches are restricted to (empty = any)
+	GooglePlacesSearchKeyword  string // Appended to legacy text search queries (empty = none)
+	GoogleMapsDailyBudget      int    // Calls per UTC day before Maps features answer 503 (0 = no limit)
+
+	// Places provider for place search and city geocoding
+	PlacesProvider       string // "google" or "nominatim"
+	NominatimURL         string
+	NominatimEmail       string
+	NominatimMinInterval time.Duration // Between requests
+
+	// Scheduled refresh of restaurant details from their places (0 = disabled), and the
+	// restaurants checked per run
+	PlaceSyncInterval  time.Duration
+	PlaceSyncBatchSize int
+
+	// Review site restaurants are looked up on for external ratings ("none", "yelp" or "foursquare")
+	EnrichmentProvider string
+	YelpAPIKey         string
+	FoursquareAPIKey   string
+
+	// Outbound webhooks: request timeout, attempts before a delivery fails, and how often
+	// this instance looks for due deliveries (0 = never, e.g. on API-only replicas)
+	WebhookTimeout      time.Duration
+	WebhookMaxAttempts  int
+	WebhookPollInterval time.Duration
+
+	// Chat notifications: incoming webhook URLs of a Discord and a Slack channel (empty =
+	// off), the events posted, and the app URL messages link to
+	NotifyDiscordWebhookURL string
+	NotifySlackWebhookURL   string
+	NotifyEvents            []string
+	NotifyAppURL            string
+
+	// Panic alerts: a URL receiving JSON reports and a Sentry project (empty = off), the
+	// environment events are tagged with, and how often the same panic is alerted at most
+	PanicAlertWebhookURL string
+	SentryDSN            string
+	SentryEnvironment    string
+	PanicAlertInterval   time.Duration
 
 	// Authentication
 	AuthMode        string
@@ -23,51 +99,775 @@ type Config struct {
 	OIDCClientID    string
 	OIDCClientSecret string
 	OIDCRedirectURL string
+	OAuthProviders  []OAuthProviderConfig
+	OIDCStateStore  string
+
+	// Browser login handoff
+	OIDCFrontendRedirectURL string
+	AuthCookieSecure        bool
+	AuthCookieSameSite      string
+
+	// Registration
+	RegistrationMode     string
+	InviteURL            string
+	EmailBlockDisposable bool
+	EmailBlockedDomains  []string
+	EmailMXCheck         bool
+
+	// Email and password resets
+	SMTPHost              string
+	SMTPPort              int
+	SMTPUsername          string
+	SMTPPassword          string
+	SMTPFrom              string
+	PasswordResetURL      string
+	PasswordResetTokenTTL time.Duration
+
+	// Token lifetimes
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+	RememberMeTTL   time.Duration // Refresh token lifetime of logins with remember_me
+
+	// Brute-force protection
+	LoginMaxAttempts     int
+	LoginIPMaxAttempts   int
+	LoginLockoutDuration time.Duration
 
-	// AWS S3
+	// Rate limits in requests per minute: per IP for each route group, and per user
+	RateLimitAuth   int
+	RateLimitUpload int
+	RateLimitRead   int
+	RateLimitWrite  int
+	RateLimitUser   int
+
+	// Concurrency limits in in-flight requests, in total and per route group (0 = unlimited)
+	ConcurrencyLimit        int
+	ConcurrencyLimitAuth    int
+	ConcurrencyLimitUpload  int
+	ConcurrencyLimitRead    int
+	ConcurrencyLimitWrite   int
+	ConcurrencyQueueTimeout time.Duration // How long requests wait for a slot before they are shed
+
+	// Requests and database queries taking longer are logged as warnings (0 = never)
+	SlowRequestThreshold time.Duration
+	SlowQueryThreshold   time.Duration
+
+	// Request metrics snapshots in the database, restored on startup (0 = not persisted)
+	MetricsPersistInterval time.Duration
+	MetricsRetention       time.Duration
+
+	// Database connection pool
+	DBMaxConns          int
+	DBMinConns          int // Idle connections kept open
+	DBMaxConnLifetime   time.Duration
+	DBMaxConnIdleTime   time.Duration
+	DBHealthCheckPeriod time.Duration
+
+	// Apply pending migrations on startup; disable when they run as a separate deploy step
+	RunMigrationsOnStart bool
+
+	// API keys
+	APIKeyRateLimit         int
+	ServiceAccountRateLimit int // Per service account across all of its keys
+
+	// Account deletion
+	AccountDeletionGracePeriod time.Duration
+
+	// Storage
+	StorageBackend  string
+	LocalStorageDir string
+
+	// AWS S3 and S3-compatible endpoints (MinIO)
 	AWSAccessKeyID     string
 	AWSSecretAccessKey string
 	AWSRegion          string
 	S3BucketName       string
+	S3Endpoint         string
+	S3UsePathStyle     bool
+
+	// Google Cloud Storage (HMAC interoperability keys)
+	GCSBucketName    string
+	GCSHMACAccessKey string
+	GCSHMACSecret    string
+
+	// Azure Blob Storage
+	AzureStorageAccount   string
+	AzureStorageKey       string
+	AzureStorageContainer string
+	AzureStorageEndpoint  string
+
+	// Images
+	ImageAVIFEnabled      bool
+	ImageWorkers          int
+	PhotoDailyUploadLimit int
+	PhotoCleanupInterval  time.Duration
+
+	// Map views: restaurants in the bounds above which clusters replace markers, and the
+	// clusters per side of the grid
+	MapClusterThreshold int
+	MapClusterGridSize  int
+
+	// Scheduled database backups to storage (0 = disabled) and how long they are kept (0 = forever)
+	BackupInterval  time.Duration
+	BackupRetention time.Duration
+
+	// Response cache for categories, food types and the unfiltered restaurant list
+	CacheBackend string        // "memory", "redis" or "none"
+	CacheTTL     time.Duration // 0 disables caching
+	RedisURL     string
+
+	// Google Places lookups cached in the cache backend (0 disables)
+	PlacesSearchCacheTTL time.Duration
+	PlaceDetailsCacheTTL time.Duration
+
+	// Event bus sharing changes between instances
+	EventsBackend string // "postgres" (LISTEN/NOTIFY) or "local"
+
+	// Cluster mode requires state shared between replicas (see docs/DEPLOYMENT.md)
+	ClusterMode    bool
+	InstanceName   string // Identifies the replica's metrics snapshots; "" for a single instance
+	RateLimitStore string // "memory" or "redis"
+
+	// Upload moderation
+	ModerationProvider      string
+	ModerationEndpoint      string
+	ModerationAPIKey        string
+	ModerationMinConfidence float64
+
+	// Suggestions
+	SuggestionApprovalMode string
+
+	// OpenTelemetry tracing
+	TracingEnabled  bool
+	OTelServiceName string
+
+	// Admin-only pprof and expvar endpoints under /api/debug/
+	DebugEndpointsEnabled bool
+
+	// Access log, separate from the application logs: "stdout", "stderr" or a file path
+	// (empty = off), in combined, common or json format
+	AccessLog       string
+	AccessLogFormat string
+
+	// Access to the metrics and API docs endpoints, and the address of the internal
+	// listener serving those with internal access
+	MetricsAccess string
+	DocsAccess    string
+	InternalAddr  string
 
 	// Server
-	Port           string
-	AllowedOrigins []string
+	Port            string
+	AllowedOrigins  []string
+	TrustedProxies  []netip.Prefix // Reverse proxies whose X-Forwarded-For headers are trusted
+	HTTP2Enabled    bool           // Over TLS, and cleartext (h2c) from reverse proxies
+	HTTPCacheMaxAge time.Duration  // Cache-Control max-age of category, food type and restaurant lists
+
+	// Native HTTPS for deployments without a reverse proxy: a certificate and key, or
+	// certificates obtained from an ACME CA (Let's Encrypt) for the listed domains
+	TLSCertFile             string
+	TLSKeyFile              string
+	TLSAutocertDomains      []string
+	TLSAutocertEmail        string
+	TLSAutocertCacheDir     string
+	TLSAutocertDirectoryURL string // Empty = Let's Encrypt production
+	TLSRedirectAddr         string // Plain HTTP listener redirecting to HTTPS and answering ACME challenges
+
+	// Timeouts (0 = none)
+	RequestTimeout   time.Duration // Cancels the handler's database queries and outgoing calls
+	HTTPReadTimeout  time.Duration
+	HTTPWriteTimeout time.Duration
+	HTTPIdleTimeout  time.Duration
+
+	// Security headers; empty values omit the header
+	ContentSecurityPolicy         string
+	DocsContentSecurityPolicy     string // For the Swagger UI
+	FrontendContentSecurityPolicy string // For the embedded frontend
+	HSTSMaxAge                    time.Duration
+	HSTSIncludeSubdomains         bool
+	HSTSPreload                   bool
+	FrameOptions                  string
+	PermissionsPolicy             string
+	FrontendPermissionsPolicy     string
+
+	IdempotencyKeyTTL time.Duration // How long responses are replayed to retries with the same Idempotency-Key
+
+	// Optional readiness checks
+	HealthCheckStorage    bool
+	HealthCheckGoogleMaps bool
+
+	// Startup preflight checks: warn (report failures and start anyway), strict (exit on
+	// failures) or off
+	Preflight string
+
+	// Graceful shutdown
+	ShutdownDrainDelay time.Duration // Health checks fail for this long before the listener closes
+	ShutdownTimeout    time.Duration // Maximum wait for in-flight requests
 	Debug          bool
+	LogFormat      string // json, or console for human-readable output
+
+	// Log levels, changeable at runtime at /api/admin/log-level
+	LogLevel        string            // debug, info, warn or error
+	LogModuleLevels map[string]string // Modules logging at another level, e.g. photos=debug
+	LogDebugSample  int               // Debug messages per second and module (0 = all)
+
+	settings []Setting // Effective settings, for --validate-config
 }
 
-// Load loads and validates environment variables
+// Load loads and validates the configuration from environment variables and the config
+// file named by CONFIG_FILE, if any
 func Load() (*Config, error) {
+	return LoadFile(os.Getenv("CONFIG_FILE"))
+}
+
+func load() (*Config, error) {
 	cfg := &Config{
-		DatabaseURL:      os.Getenv("DATABASE_URL"),
-		GoogleMapsAPIKey: os.Getenv("GOOGLE_MAPS_API_KEY"),
+		DatabaseURL:      getEnv("DATABASE_URL"),
+		GoogleMapsAPIKey: getEnv("GOOGLE_MAPS_API_KEY"),
+		GooglePlacesAPI:  getEnvOrDefault("GOOGLE_PLACES_API", "legacy"),
+		PlacesProvider:   getEnvOrDefault("PLACES_PROVIDER", "google"),
+		NominatimURL:     getEnvOrDefault("NOMINATIM_URL", "https://nominatim.openstreetmap.org"),
+		NominatimEmail:   getEnv("NOMINATIM_EMAIL"),
 		AuthMode:         getEnvOrDefault("AUTH_MODE", "both"),
-		JWTSecretKey:     os.Getenv("JWT_SECRET_KEY"),
-		OIDCIssuerURL:    os.Getenv("OIDC_ISSUER_URL"),
-		OIDCClientID:     os.Getenv("OIDC_CLIENT_ID"),
-		OIDCClientSecret: os.Getenv("OIDC_CLIENT_SECRET"),
-		OIDCRedirectURL:  os.Getenv("OIDC_REDIRECT_URL"),
-		AWSAccessKeyID:       os.Getenv("AWS_ACCESS_KEY_ID"),
-		AWSSecretAccessKey:   os.Getenv("AWS_SECRET_ACCESS_KEY"),
-		AWSRegion:            os.Getenv("AWS_REGION"),
-		S3BucketName:         os.Getenv("S3_BUCKET_NAME"),
+		JWTSecretKey:     getEnv("JWT_SECRET_KEY"),
+		OIDCIssuerURL:    getEnv("OIDC_ISSUER_URL"),
+		OIDCClientID:     getEnv("OIDC_CLIENT_ID"),
+		OIDCClientSecret: getEnv("OIDC_CLIENT_SECRET"),
+		OIDCRedirectURL:  getEnv("OIDC_REDIRECT_URL"),
+		OIDCStateStore:   getEnvOrDefault("OIDC_STATE_STORE", "database"),
+		OIDCFrontendRedirectURL: getEnv("OIDC_FRONTEND_REDIRECT_URL"),
+		AuthCookieSecure:        getEnvOrDefault("AUTH_COOKIE_SECURE", "true") == "true",
+		AuthCookieSameSite:      getEnvOrDefault("AUTH_COOKIE_SAMESITE", "lax"),
+		RegistrationMode:        getEnvOrDefault("REGISTRATION_MODE", "open"),
+		InviteURL:               getEnvOrDefault("INVITE_URL", "http://localhost:3000/register"),
+		EmailBlockDisposable:    getEnvOrDefault("EMAIL_BLOCK_DISPOSABLE", "true") == "true",
+		EmailBlockedDomains:     splitAndTrim(getEnv("EMAIL_BLOCKED_DOMAINS"), ","),
+		EmailMXCheck:            getEnv("EMAIL_MX_CHECK") == "true",
+		SMTPHost:         getEnv("SMTP_HOST"),
+		SMTPUsername:     getEnv("SMTP_USERNAME"),
+		SMTPPassword:     getEnv("SMTP_PASSWORD"),
+		SMTPFrom:         getEnvOrDefault("SMTP_FROM", "Nom Database <noreply@localhost>"),
+		PasswordResetURL: getEnvOrDefault("PASSWORD_RESET_URL", "http://localhost:3000/reset-password"),
+		AWSAccessKeyID:       getEnv("AWS_ACCESS_KEY_ID"),
+		AWSSecretAccessKey:   getEnv("AWS_SECRET_ACCESS_KEY"),
+		AWSRegion:            getEnv("AWS_REGION"),
+		S3BucketName:         getEnv("S3_BUCKET_NAME"),
+		S3Endpoint:           getEnv("S3_ENDPOINT"),
+		S3UsePathStyle:       getEnv("S3_USE_PATH_STYLE") == "true",
+		StorageBackend:       getEnvOrDefault("STORAGE_BACKEND", "auto"),
+		LocalStorageDir:      getEnvOrDefault("LOCAL_STORAGE_DIR", "./uploads"),
+		GCSBucketName:        getEnv("GCS_BUCKET_NAME"),
+		GCSHMACAccessKey:     getEnv("GCS_HMAC_ACCESS_KEY"),
+		GCSHMACSecret:        getEnv("GCS_HMAC_SECRET"),
+		AzureStorageAccount:   getEnv("AZURE_STORAGE_ACCOUNT"),
+		AzureStorageKey:       getEnv("AZURE_STORAGE_KEY"),
+		AzureStorageContainer: getEnv("AZURE_STORAGE_CONTAINER"),
+		AzureStorageEndpoint:  getEnv("AZURE_STORAGE_ENDPOINT"),
+		ImageAVIFEnabled:     getEnv("IMAGE_AVIF_ENABLED") == "true",
+		SuggestionApprovalMode: getEnvOrDefault("SUGGESTION_APPROVAL_MODE", "single"),
+		CacheBackend:         getEnvOrDefault("CACHE_BACKEND", "memory"),
+		RedisURL:             getEnv("REDIS_URL"),
+		EventsBackend:        getEnvOrDefault("EVENTS_BACKEND", "postgres"),
 		Port:                 getEnvOrDefault("PORT", "8080"),
-		Debug:                os.Getenv("DEBUG") == "true",
+		Debug:                getEnv("DEBUG") == "true",
+		LogFormat:            getEnvOrDefault("LOG_FORMAT", "console"),
+		ModerationProvider:   getEnvOrDefault("MODERATION_PROVIDER", "none"),
+		ModerationEndpoint:   getEnv("MODERATION_ENDPOINT"),
+		ModerationAPIKey:     getEnv("MODERATION_API_KEY"),
+		TracingEnabled:       getEnv("OTEL_TRACING_ENABLED") == "true",
+		HealthCheckStorage:   getEnv("HEALTH_CHECK_STORAGE") == "true",
+		HealthCheckGoogleMaps: getEnv("HEALTH_CHECK_GOOGLE_MAPS") == "true",
+		RunMigrationsOnStart: getEnvOrDefault("RUN_MIGRATIONS_ON_START", "true") == "true",
+		OTelServiceName:      getEnvOrDefault("OTEL_SERVICE_NAME", "nomdb-backend"),
+		DebugEndpointsEnabled: getEnv("DEBUG_ENDPOINTS_ENABLED") == "true",
+		AccessLog:             getEnv("ACCESS_LOG"),
+		AccessLogFormat:       getEnvOrDefault("ACCESS_LOG_FORMAT", "combined"),
+		MetricsAccess:         getEnvOrDefault("METRICS_ACCESS", OpsAccessPublic),
+		DocsAccess:            getEnvOrDefault("DOCS_ACCESS", OpsAccessPublic),
+		InternalAddr:          getEnv("INTERNAL_ADDR"),
+		HTTP2Enabled:          getEnvOrDefault("HTTP2_ENABLED", "true") == "true",
+		TLSCertFile:             getEnv("TLS_CERT_FILE"),
+		TLSKeyFile:              getEnv("TLS_KEY_FILE"),
+		TLSAutocertDomains:      splitAndTrim(getEnv("TLS_AUTOCERT_DOMAINS"), ","),
+		TLSAutocertEmail:        getEnv("TLS_AUTOCERT_EMAIL"),
+		TLSAutocertCacheDir:     getEnvOrDefault("TLS_AUTOCERT_CACHE_DIR", "certs"),
+		TLSAutocertDirectoryURL: getEnv("TLS_AUTOCERT_DIRECTORY_URL"),
+		ContentSecurityPolicy:         lookupEnvOrDefault("CONTENT_SECURITY_POLICY", defaultContentSecurityPolicy),
+		DocsContentSecurityPolicy:     lookupEnvOrDefault("DOCS_CONTENT_SECURITY_POLICY", defaultDocsContentSecurityPolicy),
+		FrontendContentSecurityPolicy: lookupEnvOrDefault("FRONTEND_CONTENT_SECURITY_POLICY", defaultFrontendContentSecurityPolicy),
+		HSTSIncludeSubdomains:         getEnvOrDefault("HSTS_INCLUDE_SUBDOMAINS", "true") == "true",
+		HSTSPreload:                   getEnv("HSTS_PRELOAD") == "true",
+		FrameOptions:                  strings.ToUpper(lookupEnvOrDefault("FRAME_OPTIONS", "DENY")),
+		PermissionsPolicy:             lookupEnvOrDefault("PERMISSIONS_POLICY", defaultPermissionsPolicy),
+		FrontendPermissionsPolicy:     lookupEnvOrDefault("FRONTEND_PERMISSIONS_POLICY", defaultFrontendPermissionsPolicy),
+	}
+
+	// Validate required variables
+	var errors []string
+
+	photoLimit, err := strconv.Atoi(getEnvOrDefault("PHOTO_DAILY_UPLOAD_LIMIT", "20"))
+	if err != nil || photoLimit < 0 {
+		errors = append(errors, "PHOTO_DAILY_UPLOAD_LIMIT must be a non-negative integer")
+	}
+	cfg.PhotoDailyUploadLimit = photoLimit
+
+	mapClusterThreshold, err := strconv.Atoi(getEnvOrDefault("MAP_CLUSTER_THRESHOLD", "200"))
+	if err != nil || mapClusterThreshold < 1 {
+		errors = append(errors, "MAP_CLUSTER_THRESHOLD must be a positive integer")
+	}
+	cfg.MapClusterThreshold = mapClusterThreshold
+
+	mapClusterGridSize, err := strconv.Atoi(getEnvOrDefault("MAP_CLUSTER_GRID_SIZE", "8"))
+	if err != nil || mapClusterGridSize < 1 || mapClusterGridSize > 64 {
+		errors = append(errors, "MAP_CLUSTER_GRID_SIZE must be an integer between 1 and 64")
+	}
+	cfg.MapClusterGridSize = mapClusterGridSize
+
+	googleMapsTimeout, err := time.ParseDuration(getEnvOrDefault("GOOGLE_MAPS_TIMEOUT", "10s"))
+	if err != nil || googleMapsTimeout <= 0 {
+		errors = append(errors, "GOOGLE_MAPS_TIMEOUT must be a positive duration (e.g. 10s)")
+	}
+	cfg.GoogleMapsTimeout = googleMapsTimeout
+
+	googleMapsMaxRetries, err := strconv.Atoi(getEnvOrDefault("GOOGLE_MAPS_MAX_RETRIES", "2"))
+	if err != nil || googleMapsMaxRetries < 0 || googleMapsMaxRetries > 5 {
+		errors = append(errors, "GOOGLE_MAPS_MAX_RETRIES must be an integer between 0 and 5")
+	}
+	cfg.GoogleMapsMaxRetries = googleMapsMaxRetries
+
+	googleMapsBreakerThreshold, err := strconv.Atoi(getEnvOrDefault("GOOGLE_MAPS_BREAKER_THRESHOLD", "5"))
+	if err != nil || googleMapsBreakerThreshold < 0 {
+		errors = append(errors, "GOOGLE_MAPS_BREAKER_THRESHOLD must be a non-negative integer (0 to disable)")
+	}
+	cfg.GoogleMapsBreakerThreshold = googleMapsBreakerThreshold
+
+	googleMapsBreakerCooldown, err := time.ParseDuration(getEnvOrDefault("GOOGLE_MAPS_BREAKER_COOLDOWN", "30s"))
+	if err != nil || googleMapsBreakerCooldown <= 0 {
+		errors = append(errors, "GOOGLE_MAPS_BREAKER_COOLDOWN must be a positive duration (e.g. 30s)")
+	}
+	cfg.GoogleMapsBreakerCooldown = googleMapsBreakerCooldown
+
+	googleMapsDailyBudget, err := strconv.Atoi(getEnvOrDefault("GOOGLE_MAPS_DAILY_BUDGET", "0"))
+	if err != nil || googleMapsDailyBudget < 0 {
+		errors = append(errors, "GOOGLE_MAPS_DAILY_BUDGET must be a non-negative integer (0 for no limit)")
+	}
+	cfg.GoogleMapsDailyBudget = googleMapsDailyBudget
+
+	cfg.EnrichmentProvider = getEnvOrDefault("ENRICHMENT_PROVIDER", "none")
+	cfg.YelpAPIKey = getEnv("YELP_API_KEY")
+	cfg.FoursquareAPIKey = getEnv("FOURSQUARE_API_KEY")
+
+	// Set to empty values to search all kinds of places
+	cfg.GooglePlacesSearchType = lookupEnvOrDefault("GOOGLE_PLACES_SEARCH_TYPE", "restaurant")
+	cfg.GooglePlacesSearchKeyword = lookupEnvOrDefault("GOOGLE_PLACES_SEARCH_KEYWORD", "restaurant")
+
+	cfg.GoogleMapsSigningSecret = getEnv("GOOGLE_MAPS_SIGNING_SECRET")
+	if cfg.GoogleMapsSigningSecret != "" {
+		if _, err := base64.URLEncoding.DecodeString(cfg.GoogleMapsSigningSecret); err != nil {
+			errors = append(errors, "GOOGLE_MAPS_SIGNING_SECRET must be base64url-encoded as shown in the Google Cloud console")
+		}
+	}
+
+	nominatimMinInterval, err := time.ParseDuration(getEnvOrDefault("NOMINATIM_MIN_INTERVAL", "1s"))
+	if err != nil || nominatimMinInterval < 0 {
+		errors = append(errors, "NOMINATIM_MIN_INTERVAL must be a non-negative duration (e.g. 1s)")
+	}
+	cfg.NominatimMinInterval = nominatimMinInterval
+
+	placeSyncInterval, err := time.ParseDuration(getEnvOrDefault("PLACE_SYNC_INTERVAL", "0"))
+	if err != nil || placeSyncInterval < 0 {
+		errors = append(errors, "PLACE_SYNC_INTERVAL must be a non-negative duration (e.g. 24h, 0 to disable)")
+	}
+	cfg.PlaceSyncInterval = placeSyncInterval
+
+	placeSyncBatchSize, err := strconv.Atoi(getEnvOrDefault("PLACE_SYNC_BATCH_SIZE", "50"))
+	if err != nil || placeSyncBatchSize < 1 || placeSyncBatchSize > 1000 {
+		errors = append(errors, "PLACE_SYNC_BATCH_SIZE must be an integer between 1 and 1000")
+	}
+	cfg.PlaceSyncBatchSize = placeSyncBatchSize
+
+	webhookTimeout, err := time.ParseDuration(getEnvOrDefault("WEBHOOK_TIMEOUT", "10s"))
+	if err != nil || webhookTimeout <= 0 {
+		errors = append(errors, "WEBHOOK_TIMEOUT must be a positive duration (e.g. 10s)")
+	}
+	cfg.WebhookTimeout = webhookTimeout
+
+	webhookMaxAttempts, err := strconv.Atoi(getEnvOrDefault("WEBHOOK_MAX_ATTEMPTS", "8"))
+	if err != nil || webhookMaxAttempts < 1 || webhookMaxAttempts > 20 {
+		errors = append(errors, "WEBHOOK_MAX_ATTEMPTS must be an integer between 1 and 20")
+	}
+	cfg.WebhookMaxAttempts = webhookMaxAttempts
+
+	webhookPollInterval, err := time.ParseDuration(getEnvOrDefault("WEBHOOK_POLL_INTERVAL", "5s"))
+	if err != nil || webhookPollInterval < 0 {
+		errors = append(errors, "WEBHOOK_POLL_INTERVAL must be a non-negative duration (e.g. 5s, 0 to not deliver from this instance)")
+	}
+	cfg.WebhookPollInterval = webhookPollInterval
+
+	cfg.NotifyDiscordWebhookURL = getEnv("NOTIFY_DISCORD_WEBHOOK_URL")
+	cfg.NotifySlackWebhookURL = getEnv("NOTIFY_SLACK_WEBHOOK_URL")
+	cfg.NotifyEvents = splitAndTrim(lookupEnvOrDefault("NOTIFY_EVENTS", "suggestion.created,suggestion.converted,rating.first"), ",")
+	cfg.NotifyAppURL = getEnv("NOTIFY_APP_URL")
+	validNotifyEvents := []string{"suggestion.created", "suggestion.converted", "rating.first"}
+	for _, event := range cfg.NotifyEvents {
+		if !contains(validNotifyEvents, event) {
+			errors = append(errors, fmt.Sprintf("NOTIFY_EVENTS must be a comma-separated list of: %v", validNotifyEvents))
+			break
+		}
+	}
+	if cfg.NotifyDiscordWebhookURL != "" && !strings.HasPrefix(cfg.NotifyDiscordWebhookURL, "https://") {
+		errors = append(errors, "NOTIFY_DISCORD_WEBHOOK_URL must be an https URL")
+	}
+	if cfg.NotifySlackWebhookURL != "" && !strings.HasPrefix(cfg.NotifySlackWebhookURL, "https://") {
+		errors = append(errors, "NOTIFY_SLACK_WEBHOOK_URL must be an https URL")
+	}
+
+	cfg.PanicAlertWebhookURL = getEnv("PANIC_ALERT_WEBHOOK_URL")
+	cfg.SentryDSN = getEnv("SENTRY_DSN")
+	cfg.SentryEnvironment = getEnvOrDefault("SENTRY_ENVIRONMENT", "production")
+	if cfg.PanicAlertWebhookURL != "" && !strings.HasPrefix(cfg.PanicAlertWebhookURL, "https://") && !strings.HasPrefix(cfg.PanicAlertWebhookURL, "http://") {
+		errors = append(errors, "PANIC_ALERT_WEBHOOK_URL must be an http(s) URL")
+	}
+	if dsn, err := url.Parse(cfg.SentryDSN); cfg.SentryDSN != "" && (err != nil || dsn.User == nil || dsn.Host == "") {
+		errors = append(errors, "SENTRY_DSN must be a Sentry DSN (https://<key>@<host>/<project ID>)")
+	}
+	panicAlertInterval, err := time.ParseDuration(getEnvOrDefault("PANIC_ALERT_INTERVAL", "10m"))
+	if err != nil || panicAlertInterval < 0 {
+		errors = append(errors, "PANIC_ALERT_INTERVAL must be a non-negative duration (e.g. 10m)")
+	}
+	cfg.PanicAlertInterval = panicAlertInterval
+
+	cfg.Preflight = getEnvOrDefault("PREFLIGHT", PreflightWarn)
+	if !contains([]string{PreflightWarn, PreflightStrict, PreflightOff}, cfg.Preflight) {
+		errors = append(errors, "PREFLIGHT must be 'warn', 'strict' or 'off'")
+	}
+
+	imageWorkers, err := strconv.Atoi(getEnvOrDefault("IMAGE_PROCESSING_WORKERS", "2"))
+	if err != nil || imageWorkers < 0 {
+		errors = append(errors, "IMAGE_PROCESSING_WORKERS must be a non-negative integer")
+	}
+	cfg.ImageWorkers = imageWorkers
+
+	cleanupInterval, err := time.ParseDuration(getEnvOrDefault("PHOTO_CLEANUP_INTERVAL", "24h"))
+	if err != nil || cleanupInterval < 0 {
+		errors = append(errors, "PHOTO_CLEANUP_INTERVAL must be a non-negative duration (e.g. 24h, 0 to disable)")
+	}
+	cfg.PhotoCleanupInterval = cleanupInterval
+
+	backupInterval, err := time.ParseDuration(getEnvOrDefault("BACKUP_INTERVAL", "0"))
+	if err != nil || backupInterval < 0 {
+		errors = append(errors, "BACKUP_INTERVAL must be a non-negative duration (e.g. 24h, 0 to disable)")
+	}
+	cfg.BackupInterval = backupInterval
+
+	backupRetention, err := time.ParseDuration(getEnvOrDefault("BACKUP_RETENTION", "720h"))
+	if err != nil || backupRetention < 0 {
+		errors = append(errors, "BACKUP_RETENTION must be a non-negative duration (e.g. 720h, 0 to keep all)")
+	}
+	cfg.BackupRetention = backupRetention
+
+	// Cached restaurant lists contain photo URLs that are valid for an hour
+	cacheTTL, err := time.ParseDuration(getEnvOrDefault("CACHE_TTL", "5m"))
+	if err != nil || cacheTTL < 0 || cacheTTL > 30*time.Minute {
+		errors = append(errors, "CACHE_TTL must be a duration between 0 and 30m (e.g. 5m, 0 to disable)")
+	}
+	cfg.CacheTTL = cacheTTL
+
+	// Google Maps Platform terms allow caching Places content for at most 30 days
+	placesSearchCacheTTL, err := time.ParseDuration(getEnvOrDefault("PLACES_SEARCH_CACHE_TTL", "1h"))
+	if err != nil || placesSearchCacheTTL < 0 || placesSearchCacheTTL > 30*24*time.Hour {
+		errors = append(errors, "PLACES_SEARCH_CACHE_TTL must be a duration between 0 and 720h (e.g. 1h, 0 to disable)")
+	}
+	cfg.PlacesSearchCacheTTL = placesSearchCacheTTL
+
+	placeDetailsCacheTTL, err := time.ParseDuration(getEnvOrDefault("PLACE_DETAILS_CACHE_TTL", "24h"))
+	if err != nil || placeDetailsCacheTTL < 0 || placeDetailsCacheTTL > 30*24*time.Hour {
+		errors = append(errors, "PLACE_DETAILS_CACHE_TTL must be a duration between 0 and 720h (e.g. 24h, 0 to disable)")
+	}
+	cfg.PlaceDetailsCacheTTL = placeDetailsCacheTTL
+
+	smtpPort, err := strconv.Atoi(getEnvOrDefault("SMTP_PORT", "587"))
+	if err != nil || smtpPort <= 0 || smtpPort > 65535 {
+		errors = append(errors, "SMTP_PORT must be a valid port number")
+	}
+	cfg.SMTPPort = smtpPort
+
+	resetTTL, err := time.ParseDuration(getEnvOrDefault("PASSWORD_RESET_TOKEN_TTL", "1h"))
+	if err != nil || resetTTL <= 0 {
+		errors = append(errors, "PASSWORD_RESET_TOKEN_TTL must be a positive duration (e.g. 1h)")
+	}
+	cfg.PasswordResetTokenTTL = resetTTL
+
+	loginMaxAttempts, err := strconv.Atoi(getEnvOrDefault("LOGIN_MAX_ATTEMPTS", "5"))
+	if err != nil || loginMaxAttempts < 0 {
+		errors = append(errors, "LOGIN_MAX_ATTEMPTS must be a non-negative integer")
+	}
+	cfg.LoginMaxAttempts = loginMaxAttempts
+
+	loginIPMaxAttempts, err := strconv.Atoi(getEnvOrDefault("LOGIN_IP_MAX_ATTEMPTS", "20"))
+	if err != nil || loginIPMaxAttempts < 0 {
+		errors = append(errors, "LOGIN_IP_MAX_ATTEMPTS must be a non-negative integer")
+	}
+	cfg.LoginIPMaxAttempts = loginIPMaxAttempts
+
+	accessTTL, err := time.ParseDuration(getEnvOrDefault("JWT_ACCESS_TOKEN_TTL", "15m"))
+	if err != nil || accessTTL <= 0 {
+		errors = append(errors, "JWT_ACCESS_TOKEN_TTL must be a positive duration (e.g. 15m)")
+	}
+	cfg.AccessTokenTTL = accessTTL
+
+	refreshTTL, err := time.ParseDuration(getEnvOrDefault("JWT_REFRESH_TOKEN_TTL", "168h"))
+	if err != nil || refreshTTL <= 0 {
+		errors = append(errors, "JWT_REFRESH_TOKEN_TTL must be a positive duration (e.g. 168h)")
+	}
+	cfg.RefreshTokenTTL = refreshTTL
+
+	rememberMeTTL, err := time.ParseDuration(getEnvOrDefault("JWT_REMEMBER_ME_TTL", "2160h"))
+	if err != nil || rememberMeTTL <= 0 {
+		errors = append(errors, "JWT_REMEMBER_ME_TTL must be a positive duration (e.g. 2160h)")
+	} else if rememberMeTTL < refreshTTL {
+		errors = append(errors, "JWT_REMEMBER_ME_TTL must not be shorter than JWT_REFRESH_TOKEN_TTL")
+	}
+	cfg.RememberMeTTL = rememberMeTTL
+
+	lockoutDuration, err := time.ParseDuration(getEnvOrDefault("LOGIN_LOCKOUT_DURATION", "1m"))
+	if err != nil || lockoutDuration <= 0 {
+		errors = append(errors, "LOGIN_LOCKOUT_DURATION must be a positive duration (e.g. 1m)")
+	}
+	cfg.LoginLockoutDuration = lockoutDuration
+
+	rateLimitAuth, err := strconv.Atoi(getEnvOrDefault("RATE_LIMIT_AUTH", "20"))
+	if err != nil || rateLimitAuth < 0 {
+		errors = append(errors, "RATE_LIMIT_AUTH must be a non-negative integer")
+	}
+	cfg.RateLimitAuth = rateLimitAuth
+
+	rateLimitUpload, err := strconv.Atoi(getEnvOrDefault("RATE_LIMIT_UPLOAD", "30"))
+	if err != nil || rateLimitUpload < 0 {
+		errors = append(errors, "RATE_LIMIT_UPLOAD must be a non-negative integer")
 	}
+	cfg.RateLimitUpload = rateLimitUpload
+
+	rateLimitRead, err := strconv.Atoi(getEnvOrDefault("RATE_LIMIT_READ", "300"))
+	if err != nil || rateLimitRead < 0 {
+		errors = append(errors, "RATE_LIMIT_READ must be a non-negative integer")
+	}
+	cfg.RateLimitRead = rateLimitRead
+
+	rateLimitWrite, err := strconv.Atoi(getEnvOrDefault("RATE_LIMIT_WRITE", "100"))
+	if err != nil || rateLimitWrite < 0 {
+		errors = append(errors, "RATE_LIMIT_WRITE must be a non-negative integer")
+	}
+	cfg.RateLimitWrite = rateLimitWrite
+
+	rateLimitUser, err := strconv.Atoi(getEnvOrDefault("RATE_LIMIT_USER", "300"))
+	if err != nil || rateLimitUser < 0 {
+		errors = append(errors, "RATE_LIMIT_USER must be a non-negative integer")
+	}
+	cfg.RateLimitUser = rateLimitUser
+
+	concurrencyLimit, err := strconv.Atoi(getEnvOrDefault("CONCURRENCY_LIMIT", "100"))
+	if err != nil || concurrencyLimit < 0 {
+		errors = append(errors, "CONCURRENCY_LIMIT must be a non-negative integer")
+	}
+	cfg.ConcurrencyLimit = concurrencyLimit
+
+	concurrencyLimitAuth, err := strconv.Atoi(getEnvOrDefault("CONCURRENCY_LIMIT_AUTH", "20"))
+	if err != nil || concurrencyLimitAuth < 0 {
+		errors = append(errors, "CONCURRENCY_LIMIT_AUTH must be a non-negative integer")
+	}
+	cfg.ConcurrencyLimitAuth = concurrencyLimitAuth
+
+	concurrencyLimitUpload, err := strconv.Atoi(getEnvOrDefault("CONCURRENCY_LIMIT_UPLOAD", "10"))
+	if err != nil || concurrencyLimitUpload < 0 {
+		errors = append(errors, "CONCURRENCY_LIMIT_UPLOAD must be a non-negative integer")
+	}
+	cfg.ConcurrencyLimitUpload = concurrencyLimitUpload
+
+	concurrencyLimitRead, err := strconv.Atoi(getEnvOrDefault("CONCURRENCY_LIMIT_READ", "80"))
+	if err != nil || concurrencyLimitRead < 0 {
+		errors = append(errors, "CONCURRENCY_LIMIT_READ must be a non-negative integer")
+	}
+	cfg.ConcurrencyLimitRead = concurrencyLimitRead
+
+	concurrencyLimitWrite, err := strconv.Atoi(getEnvOrDefault("CONCURRENCY_LIMIT_WRITE", "40"))
+	if err != nil || concurrencyLimitWrite < 0 {
+		errors = append(errors, "CONCURRENCY_LIMIT_WRITE must be a non-negative integer")
+	}
+	cfg.ConcurrencyLimitWrite = concurrencyLimitWrite
+
+	concurrencyQueueTimeout, err := time.ParseDuration(getEnvOrDefault("CONCURRENCY_QUEUE_TIMEOUT", "100ms"))
+	if err != nil || concurrencyQueueTimeout < 0 {
+		errors = append(errors, "CONCURRENCY_QUEUE_TIMEOUT must be a non-negative duration (e.g. 100ms)")
+	}
+	cfg.ConcurrencyQueueTimeout = concurrencyQueueTimeout
+
+	slowRequestThreshold, err := time.ParseDuration(getEnvOrDefault("SLOW_REQUEST_THRESHOLD", "1s"))
+	if err != nil || slowRequestThreshold < 0 {
+		errors = append(errors, "SLOW_REQUEST_THRESHOLD must be a non-negative duration (e.g. 1s)")
+	}
+	cfg.SlowRequestThreshold = slowRequestThreshold
+
+	slowQueryThreshold, err := time.ParseDuration(getEnvOrDefault("SLOW_QUERY_THRESHOLD", "250ms"))
+	if err != nil || slowQueryThreshold < 0 {
+		errors = append(errors, "SLOW_QUERY_THRESHOLD must be a non-negative duration (e.g. 250ms)")
+	}
+	cfg.SlowQueryThreshold = slowQueryThreshold
+
+	metricsPersistInterval, err := time.ParseDuration(getEnvOrDefault("METRICS_PERSIST_INTERVAL", "5m"))
+	if err != nil || metricsPersistInterval < 0 {
+		errors = append(errors, "METRICS_PERSIST_INTERVAL must be a non-negative duration (e.g. 5m)")
+	}
+	cfg.MetricsPersistInterval = metricsPersistInterval
+
+	metricsRetention, err := time.ParseDuration(getEnvOrDefault("METRICS_RETENTION", "720h"))
+	if err != nil || metricsRetention < 0 {
+		errors = append(errors, "METRICS_RETENTION must be a non-negative duration (e.g. 720h)")
+	}
+	cfg.MetricsRetention = metricsRetention
+
+	dbMaxConns, err := strconv.Atoi(getEnvOrDefault("DB_MAX_CONNS", "25"))
+	if err != nil || dbMaxConns < 1 {
+		errors = append(errors, "DB_MAX_CONNS must be a positive integer")
+	}
+	cfg.DBMaxConns = dbMaxConns
+
+	dbMinConns, err := strconv.Atoi(getEnvOrDefault("DB_MIN_CONNS", "5"))
+	if err != nil || dbMinConns < 0 || dbMinConns > dbMaxConns {
+		errors = append(errors, "DB_MIN_CONNS must be a non-negative integer no greater than DB_MAX_CONNS")
+	}
+	cfg.DBMinConns = dbMinConns
+
+	dbMaxConnLifetime, err := time.ParseDuration(getEnvOrDefault("DB_MAX_CONN_LIFETIME", "1h"))
+	if err != nil || dbMaxConnLifetime <= 0 {
+		errors = append(errors, "DB_MAX_CONN_LIFETIME must be a positive duration (e.g. 1h)")
+	}
+	cfg.DBMaxConnLifetime = dbMaxConnLifetime
+
+	dbMaxConnIdleTime, err := time.ParseDuration(getEnvOrDefault("DB_MAX_CONN_IDLE_TIME", "30m"))
+	if err != nil || dbMaxConnIdleTime <= 0 {
+		errors = append(errors, "DB_MAX_CONN_IDLE_TIME must be a positive duration (e.g. 30m)")
+	}
+	cfg.DBMaxConnIdleTime = dbMaxConnIdleTime
+
+	dbHealthCheckPeriod, err := time.ParseDuration(getEnvOrDefault("DB_HEALTH_CHECK_PERIOD", "1m"))
+	if err != nil || dbHealthCheckPeriod <= 0 {
+		errors = append(errors, "DB_HEALTH_CHECK_PERIOD must be a positive duration (e.g. 1m)")
+	}
+	cfg.DBHealthCheckPeriod = dbHealthCheckPeriod
+
+	apiKeyRateLimit, err := strconv.Atoi(getEnvOrDefault("API_KEY_RATE_LIMIT", "60"))
+	if err != nil || apiKeyRateLimit < 0 {
+		errors = append(errors, "API_KEY_RATE_LIMIT must be a non-negative integer")
+	}
+	cfg.APIKeyRateLimit = apiKeyRateLimit
+
+	serviceAccountRateLimit, err := strconv.Atoi(getEnvOrDefault("SERVICE_ACCOUNT_RATE_LIMIT", "300"))
+	if err != nil || serviceAccountRateLimit < 0 {
+		errors = append(errors, "SERVICE_ACCOUNT_RATE_LIMIT must be a non-negative integer")
+	}
+	cfg.ServiceAccountRateLimit = serviceAccountRateLimit
+
+	deletionGrace, err := time.ParseDuration(getEnvOrDefault("ACCOUNT_DELETION_GRACE_PERIOD", "720h"))
+	if err != nil || deletionGrace < 0 {
+		errors = append(errors, "ACCOUNT_DELETION_GRACE_PERIOD must be a non-negative duration (e.g. 720h)")
+	}
+	cfg.AccountDeletionGracePeriod = deletionGrace
+
+	validModerationProviders := []string{"none", "http", "rekognition"}
+	if !contains(validModerationProviders, cfg.ModerationProvider) {
+		errors = append(errors, fmt.Sprintf("MODERATION_PROVIDER must be one of: %v", validModerationProviders))
+	}
+	if cfg.ModerationProvider == "http" && cfg.ModerationEndpoint == "" {
+		errors = append(errors, "MODERATION_ENDPOINT is required when MODERATION_PROVIDER=http")
+	}
+	minConfidence, err := strconv.ParseFloat(getEnvOrDefault("MODERATION_MIN_CONFIDENCE", "80"), 64)
+	if err != nil || minConfidence < 0 || minConfidence > 100 {
+		errors = append(errors, "MODERATION_MIN_CONFIDENCE must be a number between 0 and 100")
+	}
+	cfg.ModerationMinConfidence = minConfidence
 
 	// Parse allowed origins
-	allowedOrigins := os.Getenv("ALLOWED_ORIGINS")
+	allowedOrigins := getEnv("ALLOWED_ORIGINS")
 	if allowedOrigins != "" {
 		cfg.AllowedOrigins = splitAndTrim(allowedOrigins, ",")
 	} else {
 		cfg.AllowedOrigins = []string{"http://localhost:3000", "http://localhost:5173"}
 	}
 
-	// Validate required variables
-	var errors []string
+	requestTimeout, err := time.ParseDuration(getEnvOrDefault("REQUEST_TIMEOUT", "30s"))
+	if err != nil || requestTimeout < 0 {
+		errors = append(errors, "REQUEST_TIMEOUT must be a non-negative duration (e.g. 30s)")
+	}
+	cfg.RequestTimeout = requestTimeout
+
+	httpReadTimeout, err := time.ParseDuration(getEnvOrDefault("HTTP_READ_TIMEOUT", "60s"))
+	if err != nil || httpReadTimeout < 0 {
+		errors = append(errors, "HTTP_READ_TIMEOUT must be a non-negative duration (e.g. 60s)")
+	}
+	cfg.HTTPReadTimeout = httpReadTimeout
+
+	httpWriteTimeout, err := time.ParseDuration(getEnvOrDefault("HTTP_WRITE_TIMEOUT", "60s"))
+	if err != nil || httpWriteTimeout < 0 {
+		errors = append(errors, "HTTP_WRITE_TIMEOUT must be a non-negative duration (e.g. 60s)")
+	}
+	cfg.HTTPWriteTimeout = httpWriteTimeout
+
+	httpIdleTimeout, err := time.ParseDuration(getEnvOrDefault("HTTP_IDLE_TIMEOUT", "120s"))
+	if err != nil || httpIdleTimeout < 0 {
+		errors = append(errors, "HTTP_IDLE_TIMEOUT must be a non-negative duration (e.g. 120s)")
+	}
+	cfg.HTTPIdleTimeout = httpIdleTimeout
+
+	httpCacheMaxAge, err := time.ParseDuration(getEnvOrDefault("HTTP_CACHE_MAX_AGE", "60s"))
+	if err != nil || httpCacheMaxAge < 0 {
+		errors = append(errors, "HTTP_CACHE_MAX_AGE must be a non-negative duration (e.g. 60s, 0 to always revalidate)")
+	}
+	cfg.HTTPCacheMaxAge = httpCacheMaxAge
+
+	hstsMaxAge, err := time.ParseDuration(getEnvOrDefault("HSTS_MAX_AGE", "8760h"))
+	if err != nil || hstsMaxAge < 0 {
+		errors = append(errors, "HSTS_MAX_AGE must be a non-negative duration (e.g. 8760h, 0 to disable)")
+	}
+	cfg.HSTSMaxAge = hstsMaxAge
+	if cfg.HSTSPreload && (hstsMaxAge < 365*24*time.Hour || !cfg.HSTSIncludeSubdomains) {
+		errors = append(errors, "HSTS_PRELOAD requires HSTS_MAX_AGE of at least 8760h and HSTS_INCLUDE_SUBDOMAINS=true")
+	}
+	if !contains([]string{"", "DENY", "SAMEORIGIN"}, cfg.FrameOptions) {
+		errors = append(errors, "FRAME_OPTIONS must be DENY, SAMEORIGIN or empty")
+	}
+
+	idempotencyKeyTTL, err := time.ParseDuration(getEnvOrDefault("IDEMPOTENCY_KEY_TTL", "24h"))
+	if err != nil || idempotencyKeyTTL <= 0 {
+		errors = append(errors, "IDEMPOTENCY_KEY_TTL must be a positive duration (e.g. 24h)")
+	}
+	cfg.IdempotencyKeyTTL = idempotencyKeyTTL
+
+	shutdownDrainDelay, err := time.ParseDuration(getEnvOrDefault("SHUTDOWN_DRAIN_DELAY", "5s"))
+	if err != nil || shutdownDrainDelay < 0 {
+		errors = append(errors, "SHUTDOWN_DRAIN_DELAY must be a non-negative duration (e.g. 5s)")
+	}
+	cfg.ShutdownDrainDelay = shutdownDrainDelay
+
+	shutdownTimeout, err := time.ParseDuration(getEnvOrDefault("SHUTDOWN_TIMEOUT", "30s"))
+	if err != nil || shutdownTimeout < 0 {
+		errors = append(errors, "SHUTDOWN_TIMEOUT must be a non-negative duration (e.g. 30s)")
+	}
+	cfg.ShutdownTimeout = shutdownTimeout
+
+	for _, proxy := range splitAndTrim(getEnv("TRUSTED_PROXIES"), ",") {
+		prefix, err := parseIPPrefix(proxy)
+		if err != nil {
+			errors = append(errors, fmt.Sprintf("TRUSTED_PROXIES: %q is not an IP address or CIDR range", proxy))
+			continue
+		}
+		cfg.TrustedProxies = append(cfg.TrustedProxies, prefix)
+	}
 
 	if cfg.DatabaseURL == "" {
-		errors = append(errors, "DATABASE_URL is required")
+		cfg.DatabaseURL = databaseURLFromParts()
+	}
+	if cfg.DatabaseURL == "" {
+		errors = append(errors, "DATABASE_URL (or DB_HOST) is required")
 	}
 
 	// Validate auth mode
@@ -76,6 +876,84 @@ func Load() (*Config, error) {
 		errors = append(errors, fmt.Sprintf("AUTH_MODE must be one of: %v", validAuthModes))
 	}
 
+	// Validate storage backend
+	validStorageBackends := []string{"auto", "local", "s3", "minio", "gcs", "azure"}
+	if !contains(validStorageBackends, cfg.StorageBackend) {
+		errors = append(errors, fmt.Sprintf("STORAGE_BACKEND must be one of: %v", validStorageBackends))
+	}
+
+	validPlacesAPIs := []string{"legacy", "new"}
+	if !contains(validPlacesAPIs, cfg.GooglePlacesAPI) {
+		errors = append(errors, fmt.Sprintf("GOOGLE_PLACES_API must be one of: %v", validPlacesAPIs))
+	}
+
+	validPlacesProviders := []string{"google", "nominatim"}
+	if !contains(validPlacesProviders, cfg.PlacesProvider) {
+		errors = append(errors, fmt.Sprintf("PLACES_PROVIDER must be one of: %v", validPlacesProviders))
+	}
+
+	validEnrichmentProviders := []string{"none", "yelp", "foursquare"}
+	if !contains(validEnrichmentProviders, cfg.EnrichmentProvider) {
+		errors = append(errors, fmt.Sprintf("ENRICHMENT_PROVIDER must be one of: %v", validEnrichmentProviders))
+	}
+	if cfg.EnrichmentProvider == "yelp" && cfg.YelpAPIKey == "" {
+		errors = append(errors, "YELP_API_KEY is required when ENRICHMENT_PROVIDER=yelp")
+	}
+	if cfg.EnrichmentProvider == "foursquare" && cfg.FoursquareAPIKey == "" {
+		errors = append(errors, "FOURSQUARE_API_KEY is required when ENRICHMENT_PROVIDER=foursquare")
+	}
+
+	// Validate cache backend
+	validCacheBackends := []string{"memory", "redis", "none"}
+	if !contains(validCacheBackends, cfg.CacheBackend) {
+		errors = append(errors, fmt.Sprintf("CACHE_BACKEND must be one of: %v", validCacheBackends))
+	}
+	if cfg.CacheBackend == "redis" && cfg.RedisURL == "" {
+		errors = append(errors, "REDIS_URL is required when CACHE_BACKEND is redis")
+	}
+
+	// Validate event backend
+	validEventsBackends := []string{"postgres", "local"}
+	if !contains(validEventsBackends, cfg.EventsBackend) {
+		errors = append(errors, fmt.Sprintf("EVENTS_BACKEND must be one of: %v", validEventsBackends))
+	}
+
+	// Cluster mode: every replica must see the same rate limits, login flows and events
+	cfg.ClusterMode = getEnv("CLUSTER_MODE") == "true"
+	defaultRateLimitStore := "memory"
+	if cfg.ClusterMode {
+		defaultRateLimitStore = "redis"
+		hostname, _ := os.Hostname()
+		cfg.InstanceName = getEnvOrDefault("INSTANCE_NAME", hostname)
+	}
+	cfg.RateLimitStore = getEnvOrDefault("RATE_LIMIT_STORE", defaultRateLimitStore)
+	if !contains([]string{"memory", "redis"}, cfg.RateLimitStore) {
+		errors = append(errors, "RATE_LIMIT_STORE must be one of: memory, redis")
+	}
+	if cfg.RateLimitStore == "redis" && cfg.RedisURL == "" {
+		errors = append(errors, "REDIS_URL is required when RATE_LIMIT_STORE is redis")
+	}
+	if cfg.ClusterMode {
+		if cfg.RateLimitStore != "redis" {
+			errors = append(errors, "CLUSTER_MODE requires RATE_LIMIT_STORE=redis")
+		}
+		if cfg.EventsBackend != "postgres" {
+			errors = append(errors, "CLUSTER_MODE requires EVENTS_BACKEND=postgres")
+		}
+		if cfg.OIDCStateStore != "database" {
+			errors = append(errors, "CLUSTER_MODE requires OIDC_STATE_STORE=database")
+		}
+		if cfg.InstanceName == "" {
+			errors = append(errors, "INSTANCE_NAME is required in cluster mode when the hostname is unknown")
+		}
+	}
+
+	// Validate suggestion approval mode
+	validApprovalModes := []string{"single", "two_admin"}
+	if !contains(validApprovalModes, cfg.SuggestionApprovalMode) {
+		errors = append(errors, fmt.Sprintf("SUGGESTION_APPROVAL_MODE must be one of: %v", validApprovalModes))
+	}
+
 	// Validate auth-specific requirements
 	if cfg.AuthMode == "local" || cfg.AuthMode == "both" {
 		if cfg.JWTSecretKey == "" {
@@ -85,20 +963,98 @@ func Load() (*Config, error) {
 		}
 	}
 
-	if cfg.AuthMode == "oauth" || cfg.AuthMode == "both" {
-		if cfg.OIDCIssuerURL == "" {
-			errors = append(errors, "OIDC_ISSUER_URL is required for oauth/both auth mode")
+	providers, providerErrors := loadOAuthProviders(cfg)
+	cfg.OAuthProviders = providers
+	errors = append(errors, providerErrors...)
+
+	opsAccessLevels := []string{OpsAccessPublic, OpsAccessAdmin, OpsAccessInternal, OpsAccessDisabled}
+	for _, setting := range []struct{ name, access string }{
+		{"METRICS_ACCESS", cfg.MetricsAccess},
+		{"DOCS_ACCESS", cfg.DocsAccess},
+	} {
+		if !contains(opsAccessLevels, setting.access) {
+			errors = append(errors, fmt.Sprintf("%s must be one of: %v", setting.name, opsAccessLevels))
+		} else if setting.access == OpsAccessInternal && cfg.InternalAddr == "" {
+			errors = append(errors, fmt.Sprintf("%s=internal requires INTERNAL_ADDR", setting.name))
 		}
-		if cfg.OIDCClientID == "" {
-			errors = append(errors, "OIDC_CLIENT_ID is required for oauth/both auth mode")
+	}
+	if cfg.InternalAddr != "" && cfg.MetricsAccess != OpsAccessInternal && cfg.DocsAccess != OpsAccessInternal {
+		logger.Warn("⚠️  INTERNAL_ADDR is set, but neither METRICS_ACCESS nor DOCS_ACCESS is internal")
+	}
+	if (cfg.TLSCertFile == "") != (cfg.TLSKeyFile == "") {
+		errors = append(errors, "TLS_CERT_FILE and TLS_KEY_FILE must be set together")
+	}
+	if cfg.TLSCertFile != "" && len(cfg.TLSAutocertDomains) > 0 {
+		errors = append(errors, "TLS_CERT_FILE and TLS_AUTOCERT_DOMAINS cannot be used together")
+	}
+	// ACME HTTP-01 challenges arrive on port 80
+	defaultRedirectAddr := ""
+	if len(cfg.TLSAutocertDomains) > 0 {
+		defaultRedirectAddr = ":80"
+	}
+	cfg.TLSRedirectAddr = lookupEnvOrDefault("TLS_REDIRECT_ADDR", defaultRedirectAddr)
+	if cfg.TLSRedirectAddr != "" && !cfg.TLSEnabled() {
+		errors = append(errors, "TLS_REDIRECT_ADDR requires TLS_CERT_FILE or TLS_AUTOCERT_DOMAINS")
+	}
+
+	defaultLogLevel := "info"
+	if cfg.Debug {
+		defaultLogLevel = "debug"
+	}
+	logLevels := []string{"debug", "info", "warn", "error"}
+	cfg.LogLevel = getEnvOrDefault("LOG_LEVEL", defaultLogLevel)
+	if !contains(logLevels, cfg.LogLevel) {
+		errors = append(errors, fmt.Sprintf("LOG_LEVEL must be one of: %v", logLevels))
+	}
+	cfg.LogModuleLevels = map[string]string{}
+	for _, entry := range splitAndTrim(getEnv("LOG_MODULE_LEVELS"), ",") {
+		module, level, ok := strings.Cut(entry, "=")
+		if !ok || strings.TrimSpace(module) == "" || !contains(logLevels, strings.TrimSpace(level)) {
+			errors = append(errors, "LOG_MODULE_LEVELS must be a comma-separated list of module=level (e.g. photos=debug)")
+			break
 		}
-		if cfg.OIDCClientSecret == "" {
-			errors = append(errors, "OIDC_CLIENT_SECRET is required for oauth/both auth mode")
+		cfg.LogModuleLevels[strings.TrimSpace(module)] = strings.TrimSpace(level)
+	}
+	logDebugSample, err := strconv.Atoi(getEnvOrDefault("LOG_DEBUG_SAMPLE", "0"))
+	if err != nil || logDebugSample < 0 {
+		errors = append(errors, "LOG_DEBUG_SAMPLE must be a non-negative integer")
+	}
+	cfg.LogDebugSample = logDebugSample
+
+	if !contains([]string{"combined", "common", "json"}, cfg.AccessLogFormat) {
+		errors = append(errors, "ACCESS_LOG_FORMAT must be one of: combined, common, json")
+	}
+	if !contains([]string{"database", "memory"}, cfg.OIDCStateStore) {
+		errors = append(errors, "OIDC_STATE_STORE must be one of: database, memory")
+	}
+	if !contains([]string{"open", "invite", "closed"}, cfg.RegistrationMode) {
+		errors = append(errors, "REGISTRATION_MODE must be one of: open, invite, closed")
+	}
+	if !contains([]string{"lax", "strict", "none"}, cfg.AuthCookieSameSite) {
+		errors = append(errors, "AUTH_COOKIE_SAMESITE must be one of: lax, strict, none")
+	} else if cfg.AuthCookieSameSite == "none" && !cfg.AuthCookieSecure {
+		errors = append(errors, "AUTH_COOKIE_SAMESITE=none requires AUTH_COOKIE_SECURE=true")
+	}
+
+	if cfg.AuthMode == "oauth" || cfg.AuthMode == "both" {
+		if len(cfg.OAuthProviders) == 0 {
+			errors = append(errors, "OIDC_ISSUER_URL or OIDC_PROVIDERS is required for oauth/both auth mode")
+		}
+		if cfg.OIDCIssuerURL != "" || cfg.OIDCClientID != "" {
+			if cfg.OIDCIssuerURL == "" {
+				errors = append(errors, "OIDC_ISSUER_URL is required for oauth/both auth mode")
+			}
+			if cfg.OIDCClientID == "" {
+				errors = append(errors, "OIDC_CLIENT_ID is required for oauth/both auth mode")
+			}
+			if cfg.OIDCClientSecret == "" {
+				errors = append(errors, "OIDC_CLIENT_SECRET is required for oauth/both auth mode")
+			}
 		}
 	}
 
 	// Warn about optional but recommended variables
-	if cfg.GoogleMapsAPIKey == "" {
+	if cfg.GoogleMapsAPIKey == "" && cfg.PlacesProvider == "google" {
 		logger.Warn("⚠️  GOOGLE_MAPS_API_KEY not set - Google Maps features will be unavailable")
 	}
 
@@ -114,16 +1070,48 @@ func Load() (*Config, error) {
 	return cfg, nil
 }
 
+// databaseURLFromParts builds a connection string from DB_HOST, DB_PORT, DB_USER, DB_PASSWORD,
+// DB_NAME and DB_SSLMODE, or returns "" if DB_HOST is not set
+func databaseURLFromParts() string {
+	host := getEnv("DB_HOST")
+	if host == "" {
+		return ""
+	}
+	u := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(getEnvOrDefault("DB_USER", "nomdb"), getEnvOrDefault("DB_PASSWORD", "nomdb_secret")),
+		Host:     net.JoinHostPort(host, getEnvOrDefault("DB_PORT", "5432")),
+		Path:     "/" + getEnvOrDefault("DB_NAME", "nomdb"),
+		RawQuery: "sslmode=" + url.QueryEscape(getEnvOrDefault("DB_SSLMODE", "disable")),
+	}
+	return u.String()
+}
+
 // Helper functions
 
 func getEnvOrDefault(key, defaultValue string) string {
-	value := os.Getenv(key)
+	value := getEnv(key)
 	if value == "" {
+		values.record(key, defaultValue, SourceDefault)
 		return defaultValue
 	}
 	return value
 }
 
+// lookupEnvOrDefault is like getEnvOrDefault, but a variable set to an empty value stays empty
+// TLSEnabled reports whether the server terminates TLS itself
+func (c *Config) TLSEnabled() bool {
+	return c.TLSCertFile != "" || len(c.TLSAutocertDomains) > 0
+}
+
+func lookupEnvOrDefault(key, defaultValue string) string {
+	if value, ok := lookupEnv(key); ok {
+		return value
+	}
+	values.record(key, defaultValue, SourceDefault)
+	return defaultValue
+}
+
 func contains(slice []string, item string) bool {
 	for _, s := range slice {
 		if s == item {
@@ -133,6 +1121,19 @@ func contains(slice []string, item string) bool {
 	return false
 }
 
+// parseIPPrefix parses a CIDR range or a single address
+func parseIPPrefix(s string) (netip.Prefix, error) {
+	if strings.Contains(s, "/") {
+		prefix, err := netip.ParsePrefix(s)
+		return prefix.Masked(), err
+	}
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	return netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()), nil
+}
+
 func splitAndTrim(s, sep string) []string {
 	parts := strings.Split(s, sep)
 	result := make([]string, 0, len(parts))
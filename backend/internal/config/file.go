@@ -0,0 +1,334 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/nomdb/backend/internal/logger"
+	"go.yaml.in/yaml/v3"
+)
+
+// Where a setting's effective value came from; the environment overrides the config file
+const (
+	SourceEnv     = "env"
+	SourceFile    = "file"
+	SourceDefault = "default"
+	SourceUnset   = "unset"
+)
+
+const redacted = "[redacted]"
+
+// Setting is a configuration value as Load resolved it
+type Setting struct {
+	Name   string
+	Value  string // Secrets are redacted
+	Source string
+}
+
+// source resolves settings for one Load: the environment first, then the config file. Every
+// setting read is recorded, so the effective configuration can be shown.
+type source struct {
+	file     map[string]string
+	settings map[string]Setting
+}
+
+var (
+	loadMu sync.Mutex
+	values = &source{}
+)
+
+func (s *source) lookup(key string) (string, bool) {
+	if value, ok := os.LookupEnv(key); ok {
+		s.record(key, value, SourceEnv)
+		return value, true
+	}
+	if value, ok := s.file[key]; ok {
+		s.record(key, value, SourceFile)
+		return value, true
+	}
+	s.record(key, "", SourceUnset)
+	return "", false
+}
+
+func (s *source) record(key, value, source string) {
+	if s.settings == nil {
+		s.settings = make(map[string]Setting)
+	}
+	s.settings[key] = Setting{Name: key, Value: redact(key, value), Source: source}
+}
+
+func getEnv(key string) string {
+	value, _ := values.lookup(key)
+	return value
+}
+
+func lookupEnv(key string) (string, bool) {
+	return values.lookup(key)
+}
+
+// LoadFile loads the configuration from the YAML or TOML file at path, if not empty, with
+// environment variables taking precedence over the file
+func LoadFile(path string) (*Config, error) {
+	file := map[string]string{}
+	if path != "" {
+		var err error
+		if file, err = readFile(path); err != nil {
+			return nil, err
+		}
+	}
+
+	loadMu.Lock()
+	defer loadMu.Unlock()
+	values = &source{file: file}
+	defer func() { values = &source{} }()
+
+	cfg, err := load()
+	if err != nil {
+		return nil, err
+	}
+	for key := range file {
+		if _, ok := values.settings[key]; !ok {
+			logger.Warn("⚠️  Unknown setting %s in %s", key, path)
+		}
+	}
+	for _, setting := range values.settings {
+		cfg.settings = append(cfg.settings, setting)
+	}
+	sort.Slice(cfg.settings, func(i, j int) bool { return cfg.settings[i].Name < cfg.settings[j].Name })
+	return cfg, nil
+}
+
+// Effective returns every setting read, sorted by name, with secrets redacted
+func (c *Config) Effective() []Setting {
+	return append([]Setting(nil), c.settings...)
+}
+
+// redact hides secrets and the passwords of connection URLs
+func redact(key, value string) string {
+	if value == "" {
+		return value
+	}
+	if strings.Contains(key, "SECRET") || strings.Contains(key, "PASSWORD") ||
+		strings.HasSuffix(key, "_KEY") || strings.HasSuffix(key, "WEBHOOK_URL") || strings.HasSuffix(key, "_DSN") {
+		return redacted
+	}
+	if strings.HasSuffix(key, "_URL") {
+		if u, err := url.Parse(value); err == nil && u.User != nil {
+			return u.Redacted()
+		}
+	}
+	return value
+}
+
+// readFile reads a config file into settings named like their environment variables:
+// keys are upper-cased, nested tables are joined with "_" and lists with ","
+func readFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read config file: %w", err)
+	}
+
+	var tree map[string]any
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &tree)
+	case ".toml":
+		tree, err = parseTOML(string(data))
+	default:
+		return nil, fmt.Errorf("config file %s: unsupported format %q (use .yaml, .yml or .toml)", path, ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("config file %s: %w", path, err)
+	}
+
+	settings := make(map[string]string)
+	if err := flatten(settings, "", tree); err != nil {
+		return nil, fmt.Errorf("config file %s: %w", path, err)
+	}
+	return settings, nil
+}
+
+func flatten(settings map[string]string, prefix string, tree map[string]any) error {
+	for key, value := range tree {
+		name := strings.ToUpper(strings.NewReplacer("-", "_", ".", "_").Replace(key))
+		if prefix != "" {
+			name = prefix + "_" + name
+		}
+		if table, ok := value.(map[string]any); ok {
+			if err := flatten(settings, name, table); err != nil {
+				return err
+			}
+			continue
+		}
+		if list, ok := value.([]any); ok {
+			items := make([]string, 0, len(list))
+			for _, item := range list {
+				s, err := scalar(name, item)
+				if err != nil {
+					return err
+				}
+				items = append(items, s)
+			}
+			settings[name] = strings.Join(items, ",")
+			continue
+		}
+		s, err := scalar(name, value)
+		if err != nil {
+			return err
+		}
+		settings[name] = s
+	}
+	return nil
+}
+
+func scalar(name string, value any) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case int:
+		return strconv.Itoa(v), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	default:
+		return "", fmt.Errorf("%s: unsupported value %v", name, value)
+	}
+}
+
+// parseTOML parses the subset of TOML a flat configuration needs: tables, dotted keys, and
+// strings, numbers, booleans and single-line arrays of them
+func parseTOML(data string) (map[string]any, error) {
+	tree := make(map[string]any)
+	table := tree
+	for i, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(stripTOMLComment(line))
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "[") {
+			if !strings.HasSuffix(line, "]") || strings.HasPrefix(line, "[[") {
+				return nil, fmt.Errorf("line %d: invalid table %q", i+1, line)
+			}
+			table = tree
+			for _, part := range strings.Split(strings.Trim(line, "[]"), ".") {
+				table = subtable(table, strings.TrimSpace(part))
+			}
+			continue
+		}
+
+		key, raw, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected key = value", i+1)
+		}
+		key = strings.TrimSpace(key)
+		value, err := parseTOMLValue(strings.TrimSpace(raw))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		parts := strings.Split(key, ".")
+		parent := table
+		for _, part := range parts[:len(parts)-1] {
+			parent = subtable(parent, strings.TrimSpace(part))
+		}
+		parent[strings.TrimSpace(parts[len(parts)-1])] = value
+	}
+	return tree, nil
+}
+
+func subtable(table map[string]any, key string) map[string]any {
+	if sub, ok := table[key].(map[string]any); ok {
+		return sub
+	}
+	sub := make(map[string]any)
+	table[key] = sub
+	return sub
+}
+
+func parseTOMLValue(raw string) (any, error) {
+	switch {
+	case raw == "":
+		return nil, fmt.Errorf("missing value")
+	case strings.HasPrefix(raw, `"`):
+		value, err := strconv.Unquote(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid string %s", raw)
+		}
+		return value, nil
+	case strings.HasPrefix(raw, "'"):
+		if len(raw) < 2 || !strings.HasSuffix(raw, "'") {
+			return nil, fmt.Errorf("unterminated string %s", raw)
+		}
+		return raw[1 : len(raw)-1], nil
+	case strings.HasPrefix(raw, "["):
+		if !strings.HasSuffix(raw, "]") {
+			return nil, fmt.Errorf("arrays must be on one line")
+		}
+		var items []any
+		for _, item := range splitTOMLArray(raw[1 : len(raw)-1]) {
+			value, err := parseTOMLValue(item)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, value)
+		}
+		return items, nil
+	case raw == "true" || raw == "false":
+		return raw == "true", nil
+	}
+	if n, err := strconv.ParseInt(strings.ReplaceAll(raw, "_", ""), 10, 64); err == nil {
+		return n, nil
+	}
+	if f, err := strconv.ParseFloat(strings.ReplaceAll(raw, "_", ""), 64); err == nil {
+		return f, nil
+	}
+	return nil, fmt.Errorf("invalid value %s (quote strings)", raw)
+}
+
+// stripTOMLComment removes a trailing # comment outside of strings
+func stripTOMLComment(line string) string {
+	var quote rune
+	for i, c := range line {
+		switch {
+		case quote != 0 && c == quote && (quote == '\'' || i == 0 || line[i-1] != '\\'):
+			quote = 0
+		case quote == 0 && (c == '"' || c == '\''):
+			quote = c
+		case quote == 0 && c == '#':
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// splitTOMLArray splits the items of an array on commas outside of strings
+func splitTOMLArray(s string) []string {
+	var items []string
+	var quote rune
+	start := 0
+	for i, c := range s {
+		switch {
+		case quote != 0 && c == quote && (quote == '\'' || s[i-1] != '\\'):
+			quote = 0
+		case quote == 0 && (c == '"' || c == '\''):
+			quote = c
+		case quote == 0 && c == ',':
+			items = append(items, strings.TrimSpace(s[start:i]))
+			start = i + 1
+		}
+	}
+	if last := strings.TrimSpace(s[start:]); last != "" {
+		items = append(items, last)
+	}
+	return items
+}
@@ -0,0 +1,129 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeConfigFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadFileYAML(t *testing.T) {
+	path := writeConfigFile(t, "nomdb.yaml", `
+database_url: postgres://nomdb:hunter2@db:5432/nomdb
+auth_mode: local
+jwt_secret_key: a-secret-of-at-least-thirty-two-characters
+port: 9090
+google_maps:
+  timeout: 5s
+  max_retries: 1
+allowed_origins:
+  - https://nomdb.example.com
+  - https://admin.example.com
+`)
+	t.Setenv("PORT", "7070")
+
+	cfg, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile failed: %v", err)
+	}
+	if cfg.Port != "7070" {
+		t.Errorf("Expected the environment to override the file, got port %s", cfg.Port)
+	}
+	if cfg.GoogleMapsTimeout != 5*time.Second || cfg.GoogleMapsMaxRetries != 1 {
+		t.Errorf("Expected nested keys to be flattened, got %s and %d", cfg.GoogleMapsTimeout, cfg.GoogleMapsMaxRetries)
+	}
+	if len(cfg.AllowedOrigins) != 2 || cfg.AllowedOrigins[1] != "https://admin.example.com" {
+		t.Errorf("Unexpected allowed origins %v", cfg.AllowedOrigins)
+	}
+
+	settings := map[string]Setting{}
+	for _, setting := range cfg.Effective() {
+		settings[setting.Name] = setting
+	}
+	for name, want := range map[string]Setting{
+		"PORT":           {Name: "PORT", Value: "7070", Source: SourceEnv},
+		"AUTH_MODE":      {Name: "AUTH_MODE", Value: "local", Source: SourceFile},
+		"JWT_SECRET_KEY": {Name: "JWT_SECRET_KEY", Value: redacted, Source: SourceFile},
+		"DATABASE_URL":   {Name: "DATABASE_URL", Value: "postgres://nomdb:xxxxx@db:5432/nomdb", Source: SourceFile},
+		"CACHE_BACKEND":  {Name: "CACHE_BACKEND", Value: "memory", Source: SourceDefault},
+		"REDIS_URL":      {Name: "REDIS_URL", Source: SourceUnset},
+	} {
+		if settings[name] != want {
+			t.Errorf("Expected %+v, got %+v", want, settings[name])
+		}
+	}
+}
+
+func TestLoadFileTOML(t *testing.T) {
+	path := writeConfigFile(t, "nomdb.toml", `
+# Connection
+db_host = "db"
+db_password = 'p@ss word'
+auth_mode = "local"
+jwt_secret_key = "a-secret-of-at-least-thirty-two-characters" # inline comment
+trusted_proxies = ["10.0.0.0/8", "192.168.1.1"]
+
+[google_maps]
+max_retries = 3
+breaker.cooldown = "1m"
+`)
+	t.Setenv("DATABASE_URL", "")
+
+	cfg, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile failed: %v", err)
+	}
+	if cfg.DatabaseURL != "postgres://nomdb:p%40ss%20word@db:5432/nomdb?sslmode=disable" {
+		t.Errorf("Expected the URL to be built from DB_* settings, got %s", cfg.DatabaseURL)
+	}
+	if cfg.GoogleMapsMaxRetries != 3 || cfg.GoogleMapsBreakerCooldown != time.Minute {
+		t.Errorf("Expected table keys to be flattened, got %d and %s", cfg.GoogleMapsMaxRetries, cfg.GoogleMapsBreakerCooldown)
+	}
+	if len(cfg.TrustedProxies) != 2 {
+		t.Errorf("Unexpected trusted proxies %v", cfg.TrustedProxies)
+	}
+}
+
+func TestReadFileErrors(t *testing.T) {
+	tests := map[string]string{
+		"nomdb.json": `{"port": 8080}`,
+		"bad.toml":   "port = 8080\nname",
+		"array.toml": "origins = [\n\"a\"\n]",
+		"bare.toml":  "mode = local",
+		"bad.yaml":   "port: [8080",
+	}
+	for name, content := range tests {
+		if _, err := readFile(writeConfigFile(t, name, content)); err == nil {
+			t.Errorf("Expected %s to be rejected", name)
+		}
+	}
+}
+
+func TestRedact(t *testing.T) {
+	tests := []struct{ key, value, want string }{
+		{"SMTP_PASSWORD", "hunter2", redacted},
+		{"AWS_SECRET_ACCESS_KEY", "abc", redacted},
+		{"YELP_API_KEY", "abc", redacted},
+		{"NOTIFY_SLACK_WEBHOOK_URL", "https://hooks.slack.com/services/T/B/X", redacted},
+		{"SENTRY_DSN", "https://abc@o1.ingest.sentry.io/42", redacted},
+		{"REDIS_URL", "redis://:hunter2@cache:6379/0", "redis://:xxxxx@cache:6379/0"},
+		{"NOMINATIM_URL", "https://nominatim.openstreetmap.org", "https://nominatim.openstreetmap.org"},
+		{"AWS_ACCESS_KEY_ID", "AKIA", "AKIA"},
+		{"JWT_ACCESS_TOKEN_TTL", "15m", "15m"},
+		{"SMTP_PASSWORD", "", ""},
+	}
+	for _, tt := range tests {
+		if got := redact(tt.key, tt.value); got != tt.want {
+			t.Errorf("redact(%s, %q) = %q, want %q", tt.key, tt.value, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// OAuth provider types
+const (
+	OAuthTypeOIDC   = "oidc"   // Any OpenID Connect issuer (Authentik, Keycloak, Auth0, ...)
+	OAuthTypeGoogle = "google" // OIDC with Google's issuer preset
+	OAuthTypeGitHub = "github" // GitHub OAuth apps (not OIDC)
+)
+
+// GoogleIssuerURL is the OIDC issuer used for Google providers
+const GoogleIssuerURL = "https://accounts.google.com"
+
+// LegacyOAuthProviderName is the provider configured through the unprefixed OIDC_* variables
+const LegacyOAuthProviderName = "oidc"
+
+// OAuthProviderConfig configures one login provider
+type OAuthProviderConfig struct {
+	Name         string // Used in URLs and stored with linked identities
+	DisplayName  string
+	Type         string
+	IssuerURL    string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	GroupsClaim  string   // Claim listing the user's groups or roles; dots select nested claims
+	AdminGroups  []string // Members of any of these groups are admins; empty disables the mapping
+}
+
+// DefaultGroupsClaim is the claim Authentik and most other providers put groups in
+const DefaultGroupsClaim = "groups"
+
+var providerNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// providerEnvName returns the variable OIDC_<NAME>_<KEY>, e.g. OIDC_GOOGLE_CLIENT_ID
+func providerEnvName(name, key string) string {
+	return "OIDC_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_")) + "_" + key
+}
+
+func providerEnv(name, key string) string {
+	return getEnv(providerEnvName(name, key))
+}
+
+// loadOAuthProviders reads the providers listed in OIDC_PROVIDERS, plus the legacy
+// single provider from OIDC_ISSUER_URL/OIDC_CLIENT_ID/OIDC_CLIENT_SECRET if set
+func loadOAuthProviders(cfg *Config) ([]OAuthProviderConfig, []string) {
+	var providers []OAuthProviderConfig
+	var errors []string
+
+	groupsClaim := getEnvOrDefault("OIDC_GROUPS_CLAIM", DefaultGroupsClaim)
+	adminGroups := splitAndTrim(getEnv("OIDC_ADMIN_GROUPS"), ",")
+
+	if cfg.OIDCIssuerURL != "" || cfg.OIDCClientID != "" {
+		redirectURL := cfg.OIDCRedirectURL
+		if redirectURL == "" {
+			redirectURL = "http://localhost:8080/api/auth/oidc/callback"
+		}
+		providers = append(providers, OAuthProviderConfig{
+			Name:         LegacyOAuthProviderName,
+			DisplayName:  getEnvOrDefault("OIDC_DISPLAY_NAME", "Single Sign-On"),
+			Type:         OAuthTypeOIDC,
+			IssuerURL:    cfg.OIDCIssuerURL,
+			ClientID:     cfg.OIDCClientID,
+			ClientSecret: cfg.OIDCClientSecret,
+			RedirectURL:  redirectURL,
+			GroupsClaim:  groupsClaim,
+			AdminGroups:  adminGroups,
+		})
+	}
+
+	seen := map[string]bool{LegacyOAuthProviderName: len(providers) > 0}
+	for _, name := range splitAndTrim(getEnv("OIDC_PROVIDERS"), ",") {
+		name = strings.ToLower(name)
+		if !providerNamePattern.MatchString(name) {
+			errors = append(errors, fmt.Sprintf("OIDC_PROVIDERS: invalid provider name %q (use lowercase letters, digits and dashes)", name))
+			continue
+		}
+		if seen[name] {
+			errors = append(errors, fmt.Sprintf("OIDC_PROVIDERS: provider %q is configured twice", name))
+			continue
+		}
+		seen[name] = true
+
+		provider := OAuthProviderConfig{
+			Name:         name,
+			DisplayName:  providerEnv(name, "DISPLAY_NAME"),
+			Type:         providerEnv(name, "TYPE"),
+			IssuerURL:    providerEnv(name, "ISSUER_URL"),
+			ClientID:     providerEnv(name, "CLIENT_ID"),
+			ClientSecret: providerEnv(name, "CLIENT_SECRET"),
+			RedirectURL:  providerEnv(name, "REDIRECT_URL"),
+			GroupsClaim:  providerEnv(name, "GROUPS_CLAIM"),
+			AdminGroups:  adminGroups,
+		}
+		if provider.GroupsClaim == "" {
+			provider.GroupsClaim = groupsClaim
+		}
+		if groups, ok := lookupEnv(providerEnvName(name, "ADMIN_GROUPS")); ok {
+			provider.AdminGroups = splitAndTrim(groups, ",")
+		}
+		if provider.Type == "" {
+			switch name {
+			case OAuthTypeGoogle, OAuthTypeGitHub:
+				provider.Type = name
+			default:
+				provider.Type = OAuthTypeOIDC
+			}
+		}
+		if provider.DisplayName == "" {
+			provider.DisplayName = strings.ToUpper(name[:1]) + name[1:]
+		}
+		if provider.RedirectURL == "" {
+			provider.RedirectURL = fmt.Sprintf("http://localhost:8080/api/auth/oidc/%s/callback", name)
+		}
+
+		switch provider.Type {
+		case OAuthTypeGoogle:
+			if provider.IssuerURL == "" {
+				provider.IssuerURL = GoogleIssuerURL
+			}
+		case OAuthTypeOIDC:
+			if provider.IssuerURL == "" {
+				errors = append(errors, fmt.Sprintf("%s is required for OIDC provider %q", providerEnvName(name, "ISSUER_URL"), name))
+			}
+		case OAuthTypeGitHub:
+			// GitHub has no groups claim, so the global admin groups do not apply
+			if _, ok := lookupEnv(providerEnvName(name, "ADMIN_GROUPS")); ok && len(provider.AdminGroups) > 0 {
+				errors = append(errors, fmt.Sprintf("%s is not supported for GitHub providers", providerEnvName(name, "ADMIN_GROUPS")))
+			}
+			provider.AdminGroups = nil
+		default:
+			errors = append(errors, fmt.Sprintf("provider %q has unknown type %q (must be one of: oidc, google, github)", name, provider.Type))
+		}
+		if provider.ClientID == "" || provider.ClientSecret == "" {
+			errors = append(errors, fmt.Sprintf("client ID and secret are required for provider %q", name))
+		}
+
+		providers = append(providers, provider)
+	}
+
+	return providers, errors
+}
@@ -0,0 +1,111 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoadOAuthProviders(t *testing.T) {
+	t.Setenv("OIDC_PROVIDERS", "authentik, google,github")
+	t.Setenv("OIDC_AUTHENTIK_ISSUER_URL", "https://auth.example.com/application/o/nomdb/")
+	t.Setenv("OIDC_AUTHENTIK_CLIENT_ID", "authentik-id")
+	t.Setenv("OIDC_AUTHENTIK_CLIENT_SECRET", "authentik-secret")
+	t.Setenv("OIDC_GOOGLE_CLIENT_ID", "google-id")
+	t.Setenv("OIDC_GOOGLE_CLIENT_SECRET", "google-secret")
+	t.Setenv("OIDC_GITHUB_CLIENT_ID", "github-id")
+	t.Setenv("OIDC_GITHUB_CLIENT_SECRET", "github-secret")
+	t.Setenv("OIDC_GITHUB_DISPLAY_NAME", "GitHub")
+
+	providers, errs := loadOAuthProviders(&Config{})
+	if len(errs) > 0 {
+		t.Fatalf("Expected no errors, got %v", errs)
+	}
+	if len(providers) != 3 {
+		t.Fatalf("Expected 3 providers, got %d", len(providers))
+	}
+
+	authentik, google, github := providers[0], providers[1], providers[2]
+	if authentik.Type != OAuthTypeOIDC || authentik.DisplayName != "Authentik" {
+		t.Errorf("Unexpected authentik provider: %+v", authentik)
+	}
+	if authentik.RedirectURL != "http://localhost:8080/api/auth/oidc/authentik/callback" {
+		t.Errorf("Expected provider-qualified redirect URL, got %s", authentik.RedirectURL)
+	}
+	if google.Type != OAuthTypeGoogle || google.IssuerURL != GoogleIssuerURL {
+		t.Errorf("Expected the Google issuer preset, got %+v", google)
+	}
+	if github.Type != OAuthTypeGitHub || github.DisplayName != "GitHub" {
+		t.Errorf("Unexpected github provider: %+v", github)
+	}
+}
+
+func TestLoadOAuthProvidersLegacy(t *testing.T) {
+	t.Setenv("OIDC_PROVIDERS", "")
+
+	providers, errs := loadOAuthProviders(&Config{
+		OIDCIssuerURL:    "https://keycloak.example.com/realms/nomdb",
+		OIDCClientID:     "id",
+		OIDCClientSecret: "secret",
+	})
+	if len(errs) > 0 {
+		t.Fatalf("Expected no errors, got %v", errs)
+	}
+	if len(providers) != 1 || providers[0].Name != LegacyOAuthProviderName {
+		t.Fatalf("Expected the legacy provider, got %+v", providers)
+	}
+	if providers[0].RedirectURL != "http://localhost:8080/api/auth/oidc/callback" {
+		t.Errorf("Expected the legacy redirect URL, got %s", providers[0].RedirectURL)
+	}
+}
+
+func TestLoadOAuthProvidersErrors(t *testing.T) {
+	t.Setenv("OIDC_PROVIDERS", "keycloak,Bad Name,okta")
+	t.Setenv("OIDC_KEYCLOAK_CLIENT_ID", "id")
+	t.Setenv("OIDC_KEYCLOAK_CLIENT_SECRET", "secret")
+	t.Setenv("OIDC_OKTA_TYPE", "saml")
+
+	_, errs := loadOAuthProviders(&Config{})
+	joined := strings.Join(errs, "\n")
+
+	for _, expected := range []string{
+		"OIDC_KEYCLOAK_ISSUER_URL is required",
+		`invalid provider name "bad name"`,
+		`unknown type "saml"`,
+		`client ID and secret are required for provider "okta"`,
+	} {
+		if !strings.Contains(joined, expected) {
+			t.Errorf("Expected error containing %q, got:\n%s", expected, joined)
+		}
+	}
+}
+
+func TestLoadOAuthProvidersAdminGroups(t *testing.T) {
+	t.Setenv("OIDC_PROVIDERS", "authentik,keycloak,github")
+	t.Setenv("OIDC_ADMIN_GROUPS", "nomdb-admins, ops")
+	t.Setenv("OIDC_AUTHENTIK_ISSUER_URL", "https://auth.example.com/application/o/nomdb/")
+	t.Setenv("OIDC_AUTHENTIK_CLIENT_ID", "id")
+	t.Setenv("OIDC_AUTHENTIK_CLIENT_SECRET", "secret")
+	t.Setenv("OIDC_KEYCLOAK_ISSUER_URL", "https://keycloak.example.com/realms/nomdb")
+	t.Setenv("OIDC_KEYCLOAK_CLIENT_ID", "id")
+	t.Setenv("OIDC_KEYCLOAK_CLIENT_SECRET", "secret")
+	t.Setenv("OIDC_KEYCLOAK_GROUPS_CLAIM", "realm_access.roles")
+	t.Setenv("OIDC_KEYCLOAK_ADMIN_GROUPS", "nomdb-admin")
+	t.Setenv("OIDC_GITHUB_CLIENT_ID", "id")
+	t.Setenv("OIDC_GITHUB_CLIENT_SECRET", "secret")
+
+	providers, errs := loadOAuthProviders(&Config{})
+	if len(errs) > 0 {
+		t.Fatalf("Expected no errors, got %v", errs)
+	}
+
+	authentik, keycloak, github := providers[0], providers[1], providers[2]
+	if authentik.GroupsClaim != DefaultGroupsClaim || strings.Join(authentik.AdminGroups, ",") != "nomdb-admins,ops" {
+		t.Errorf("Expected the global admin groups, got %q %v", authentik.GroupsClaim, authentik.AdminGroups)
+	}
+	if keycloak.GroupsClaim != "realm_access.roles" || strings.Join(keycloak.AdminGroups, ",") != "nomdb-admin" {
+		t.Errorf("Expected the provider's admin groups, got %q %v", keycloak.GroupsClaim, keycloak.AdminGroups)
+	}
+	if len(github.AdminGroups) != 0 {
+		t.Errorf("Expected no admin group mapping for GitHub, got %v", github.AdminGroups)
+	}
+}
@@ -0,0 +1,96 @@
+package database
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// queryDuration is observed by queryTracer for every query
+var queryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Namespace: "nomdb",
+	Name:      "db_query_duration_seconds",
+	Help:      "Database query latency by query name.",
+	Buckets:   []float64{0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5},
+}, []string{"query"})
+
+// QueryDurationCollector returns the Prometheus histogram of query latencies
+func QueryDurationCollector() prometheus.Collector {
+	return queryDuration
+}
+
+// PoolStats returns the statistics of the connection pool for /api/metrics, nil until
+// the pool is connected
+func PoolStats() map[string]interface{} {
+	if pool == nil {
+		return nil
+	}
+	stat := pool.Stat()
+	return map[string]interface{}{
+		"acquired_connections": stat.AcquiredConns(),
+		"idle_connections":     stat.IdleConns(),
+		"total_connections":    stat.TotalConns(),
+		"max_connections":      stat.MaxConns(),
+		"acquires":             stat.AcquireCount(),
+		"empty_acquires":       stat.EmptyAcquireCount(),
+		"acquire_wait_time":    stat.AcquireDuration().String(),
+	}
+}
+
+// poolCollector exports the statistics of the connection pool to Prometheus
+type poolCollector struct {
+	acquiredConns        *prometheus.Desc
+	idleConns            *prometheus.Desc
+	constructingConns    *prometheus.Desc
+	totalConns           *prometheus.Desc
+	maxConns             *prometheus.Desc
+	acquireCount         *prometheus.Desc
+	acquireDuration      *prometheus.Desc
+	emptyAcquireCount    *prometheus.Desc
+	canceledAcquireCount *prometheus.Desc
+}
+
+// NewPoolCollector returns a Prometheus collector for the pool of Connect. It reports
+// nothing until the pool is connected.
+func NewPoolCollector() prometheus.Collector {
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName("nomdb", "db_pool", name), help, nil, nil)
+	}
+	return &poolCollector{
+		acquiredConns:        desc("acquired_connections", "Connections currently in use."),
+		idleConns:            desc("idle_connections", "Idle connections in the pool."),
+		constructingConns:    desc("constructing_connections", "Connections currently being established."),
+		totalConns:           desc("total_connections", "Total connections in the pool."),
+		maxConns:             desc("max_connections", "Maximum size of the pool."),
+		acquireCount:         desc("acquires_total", "Successful connection acquisitions."),
+		acquireDuration:      desc("acquire_duration_seconds_total", "Total time spent waiting for connections."),
+		emptyAcquireCount:    desc("empty_acquires_total", "Acquisitions that had to wait because the pool was empty."),
+		canceledAcquireCount: desc("canceled_acquires_total", "Acquisitions canceled by their context."),
+	}
+}
+
+func (c *poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.acquiredConns
+	ch <- c.idleConns
+	ch <- c.constructingConns
+	ch <- c.totalConns
+	ch <- c.maxConns
+	ch <- c.acquireCount
+	ch <- c.acquireDuration
+	ch <- c.emptyAcquireCount
+	ch <- c.canceledAcquireCount
+}
+
+func (c *poolCollector) Collect(ch chan<- prometheus.Metric) {
+	if pool == nil {
+		return
+	}
+	stat := pool.Stat()
+	ch <- prometheus.MustNewConstMetric(c.acquiredConns, prometheus.GaugeValue, float64(stat.AcquiredConns()))
+	ch <- prometheus.MustNewConstMetric(c.idleConns, prometheus.GaugeValue, float64(stat.IdleConns()))
+	ch <- prometheus.MustNewConstMetric(c.constructingConns, prometheus.GaugeValue, float64(stat.ConstructingConns()))
+	ch <- prometheus.MustNewConstMetric(c.totalConns, prometheus.GaugeValue, float64(stat.TotalConns()))
+	ch <- prometheus.MustNewConstMetric(c.maxConns, prometheus.GaugeValue, float64(stat.MaxConns()))
+	ch <- prometheus.MustNewConstMetric(c.acquireCount, prometheus.CounterValue, float64(stat.AcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.acquireDuration, prometheus.CounterValue, stat.AcquireDuration().Seconds())
+	ch <- prometheus.MustNewConstMetric(c.emptyAcquireCount, prometheus.CounterValue, float64(stat.EmptyAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.canceledAcquireCount, prometheus.CounterValue, float64(stat.CanceledAcquireCount()))
+}
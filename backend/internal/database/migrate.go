@@ -1,67 +1,213 @@
 package database
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+	"time"
 
 	"github.com/golang-migrate/migrate/v4"
 	_ "github.com/golang-migrate/migrate/v4/database/postgres"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/golang-migrate/migrate/v4/source"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/jackc/pgx/v5"
+	"github.com/nomdb/backend/db"
 	"github.com/nomdb/backend/internal/logger"
 )
 
-// RunMigrations runs all pending migrations
-func RunMigrations(databaseURL string, migrationsPath string) error {
-	m, err := migrate.New(
-		fmt.Sprintf("file://%s", migrationsPath),
-		databaseURL,
-	)
+// MigrationStatus describes one embedded migration and whether it is applied
+type MigrationStatus struct {
+	Version uint
+	Name    string
+	Applied bool
+	Dirty   bool
+	// AppliedAt is nil for pending migrations and for migrations applied before
+	// the history was recorded
+	AppliedAt *time.Time
+}
+
+// schema_migrations only holds the current version, so the time each migration
+// was applied is kept alongside it
+const createMigrationHistory = `CREATE TABLE IF NOT EXISTS schema_migrations_history (
+	version BIGINT PRIMARY KEY,
+	applied_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+)`
+
+// migrationLockKey is the Postgres advisory lock held while migrating, so replicas
+// starting together migrate one after the other
+const migrationLockKey int64 = 0x6e6f6d6462 // "nomdb"
+
+// instanceName identifies this process in the migration logs
+func instanceName() string {
+	host, err := os.Hostname()
 	if err != nil {
-		return fmt.Errorf("failed to create migrate instance: %w", err)
+		host = "unknown"
 	}
-	defer m.Close()
+	return fmt.Sprintf("%s (pid %d)", host, os.Getpid())
+}
 
-	// Run all pending migrations
-	if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
-		return fmt.Errorf("failed to run migrations: %w", err)
+// withMigrationLock runs fn while holding the migration advisory lock on a dedicated
+// connection, waiting for other instances to release it first
+func withMigrationLock(databaseURL string, fn func() error) error {
+	ctx := context.Background()
+	conn, err := pgx.Connect(ctx, databaseURL)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
 	}
+	defer conn.Close(ctx)
 
-	if errors.Is(err, migrate.ErrNoChange) {
-		logger.Info("✓ Database schema is up to date")
-	} else {
-		logger.Info("✓ Database migrations completed successfully")
+	var acquired bool
+	if err := conn.QueryRow(ctx, `SELECT pg_try_advisory_lock($1)`, migrationLockKey).Scan(&acquired); err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	if !acquired {
+		logger.Info("⏳ Another instance is migrating the database, waiting for it to finish")
+		if _, err := conn.Exec(ctx, `SELECT pg_advisory_lock($1)`, migrationLockKey); err != nil {
+			return fmt.Errorf("failed to acquire migration lock: %w", err)
+		}
 	}
+	defer func() {
+		if _, err := conn.Exec(ctx, `SELECT pg_advisory_unlock($1)`, migrationLockKey); err != nil {
+			logger.Warn("⚠️  Failed to release migration lock: %v", err)
+		}
+	}()
 
-	return nil
+	return fn()
 }
 
-// MigrateDown rolls back the last migration
-func MigrateDown(databaseURL string, migrationsPath string) error {
-	m, err := migrate.New(
-		fmt.Sprintf("file://%s", migrationsPath),
-		databaseURL,
-	)
+// newMigrate opens the migrations embedded in the binary against the database, so
+// they run regardless of the working directory
+func newMigrate(databaseURL string) (*migrate.Migrate, error) {
+	source, err := iofs.New(db.Migrations, db.MigrationsDir)
 	if err != nil {
-		return fmt.Errorf("failed to create migrate instance: %w", err)
+		return nil, fmt.Errorf("failed to read embedded migrations: %w", err)
 	}
-	defer m.Close()
+	m, err := migrate.NewWithSourceInstance("iofs", source, databaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create migrate instance: %w", err)
+	}
+	return m, nil
+}
+
+// runMigrate runs fn against the embedded migrations under the migration lock and
+// records the resulting state in the migration history, also when fn fails part-way
+func runMigrate(databaseURL string, fn func(m *migrate.Migrate) error) error {
+	return withMigrationLock(databaseURL, func() error {
+		m, err := newMigrate(databaseURL)
+		if err != nil {
+			return err
+		}
+		defer m.Close()
+
+		err = fn(m)
+
+		version, dirty, verr := m.Version()
+		if verr != nil && !errors.Is(verr, migrate.ErrNilVersion) {
+			logger.Warn("⚠️  Failed to read migration version for history: %v", verr)
+			return err
+		}
+		if herr := recordMigrationHistory(databaseURL, version, dirty); herr != nil {
+			logger.Warn("⚠️  Failed to record migration history: %v", herr)
+		}
+		return err
+	})
+}
 
-	if err := m.Steps(-1); err != nil {
-		return fmt.Errorf("failed to rollback migration: %w", err)
+// RunMigrations runs all pending migrations
+func RunMigrations(databaseURL string) error {
+	return runMigrate(databaseURL, func(m *migrate.Migrate) error {
+		// Another instance may have migrated while this one waited for the lock
+		from, _, _ := m.Version()
+		err := m.Up()
+		if err != nil && !errors.Is(err, migrate.ErrNoChange) {
+			return fmt.Errorf("failed to run migrations: %w", err)
+		}
+
+		if errors.Is(err, migrate.ErrNoChange) {
+			logger.Info("✓ Database schema is up to date")
+		} else {
+			to, _, _ := m.Version()
+			logger.Info("✓ Database migrations completed successfully (version %d → %d) by instance %s", from, to, instanceName())
+		}
+		return nil
+	})
+}
+
+// MigrateDown rolls back the last migration
+func MigrateDown(databaseURL string) error {
+	return runMigrate(databaseURL, func(m *migrate.Migrate) error {
+		if err := m.Steps(-1); err != nil {
+			return fmt.Errorf("failed to rollback migration: %w", err)
+		}
+
+		logger.Info("✓ Rolled back last migration")
+		return nil
+	})
+}
+
+// MigrateTo migrates up or down to the given version
+func MigrateTo(databaseURL string, version uint) error {
+	return runMigrate(databaseURL, func(m *migrate.Migrate) error {
+		err := m.Migrate(version)
+		if errors.Is(err, migrate.ErrNoChange) {
+			logger.Info("✓ Already at migration version %d", version)
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to migrate to version %d: %w", version, err)
+		}
+
+		logger.Info("✓ Migrated to version %d", version)
+		return nil
+	})
+}
+
+// MigrateSteps applies n migrations, or rolls back -n migrations when n is negative
+func MigrateSteps(databaseURL string, n int) error {
+	if n == 0 {
+		return errors.New("number of steps must not be 0")
 	}
+	return runMigrate(databaseURL, func(m *migrate.Migrate) error {
+		if err := m.Steps(n); err != nil {
+			return fmt.Errorf("failed to migrate %d steps: %w", n, err)
+		}
 
-	logger.Info("✓ Rolled back last migration")
-	return nil
+		logger.Info("✓ Migrated %d steps", n)
+		return nil
+	})
+}
+
+// MigrateRedo rolls back the last migration and applies it again
+func MigrateRedo(databaseURL string) error {
+	return runMigrate(databaseURL, func(m *migrate.Migrate) error {
+		version, _, err := m.Version()
+		if errors.Is(err, migrate.ErrNilVersion) {
+			return errors.New("no migration has been applied")
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get migration version: %w", err)
+		}
+
+		if err := m.Steps(-1); err != nil {
+			return fmt.Errorf("failed to rollback migration %d: %w", version, err)
+		}
+		if err := m.Steps(1); err != nil {
+			return fmt.Errorf("failed to reapply migration %d: %w", version, err)
+		}
+
+		logger.Info("✓ Redid migration %d", version)
+		return nil
+	})
 }
 
 // MigrateVersion gets the current migration version
-func MigrateVersion(databaseURL string, migrationsPath string) (uint, bool, error) {
-	m, err := migrate.New(
-		fmt.Sprintf("file://%s", migrationsPath),
-		databaseURL,
-	)
+func MigrateVersion(databaseURL string) (uint, bool, error) {
+	m, err := newMigrate(databaseURL)
 	if err != nil {
-		return 0, false, fmt.Errorf("failed to create migrate instance: %w", err)
+		return 0, false, err
 	}
 	defer m.Close()
 
@@ -74,20 +220,154 @@ func MigrateVersion(databaseURL string, migrationsPath string) (uint, bool, erro
 }
 
 // MigrateForce forces a specific migration version (use with caution)
-func MigrateForce(databaseURL string, migrationsPath string, version int) error {
-	m, err := migrate.New(
-		fmt.Sprintf("file://%s", migrationsPath),
-		databaseURL,
-	)
+func MigrateForce(databaseURL string, version int) error {
+	return runMigrate(databaseURL, func(m *migrate.Migrate) error {
+		if err := m.Force(version); err != nil {
+			return fmt.Errorf("failed to force migration version: %w", err)
+		}
+
+		logger.Info("✓ Forced migration version to %d", version)
+		return nil
+	})
+}
+
+// MigrationStatuses lists the embedded migrations with whether and when they were applied
+func MigrationStatuses(databaseURL string) ([]MigrationStatus, error) {
+	migrations, err := embeddedMigrations()
 	if err != nil {
-		return fmt.Errorf("failed to create migrate instance: %w", err)
+		return nil, err
 	}
-	defer m.Close()
+	version, dirty, err := MigrateVersion(databaseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	conn, err := pgx.Connect(ctx, databaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer conn.Close(ctx)
+
+	appliedAt := make(map[uint]time.Time)
+	var hasHistory bool
+	if err := conn.QueryRow(ctx, `SELECT to_regclass('schema_migrations_history') IS NOT NULL`).Scan(&hasHistory); err != nil {
+		return nil, fmt.Errorf("failed to read migration history: %w", err)
+	}
+	if !hasHistory {
+		return migrationStatuses(migrations, version, dirty, appliedAt), nil
+	}
+
+	rows, err := conn.Query(ctx, `SELECT version, applied_at FROM schema_migrations_history`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migration history: %w", err)
+	}
+	for rows.Next() {
+		var v int64
+		var at time.Time
+		if err := rows.Scan(&v, &at); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to read migration history: %w", err)
+		}
+		appliedAt[uint(v)] = at
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read migration history: %w", err)
+	}
+
+	return migrationStatuses(migrations, version, dirty, appliedAt), nil
+}
+
+// recordMigrationHistory records migrations up to version as applied and forgets the
+// ones above it; a dirty version has not been applied
+func recordMigrationHistory(databaseURL string, version uint, dirty bool) error {
+	migrations, err := embeddedMigrations()
+	if err != nil {
+		return err
+	}
+	var applied []int64
+	for _, status := range migrationStatuses(migrations, version, dirty, nil) {
+		if status.Applied {
+			applied = append(applied, int64(status.Version))
+		}
+	}
+	if applied == nil {
+		applied = []int64{}
+	}
+
+	ctx := context.Background()
+	conn, err := pgx.Connect(ctx, databaseURL)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer conn.Close(ctx)
+
+	return RunInTx(ctx, conn, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, createMigrationHistory); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, `DELETE FROM schema_migrations_history WHERE NOT (version = ANY($1::bigint[]))`, applied); err != nil {
+			return err
+		}
+		_, err := tx.Exec(ctx, `
+			INSERT INTO schema_migrations_history (version)
+			SELECT v FROM unnest($1::bigint[]) AS v
+			ON CONFLICT DO NOTHING
+		`, applied)
+		return err
+	})
+}
+
+// LatestMigration returns the version of the newest migration embedded in the binary,
+// which the schema is at once all migrations ran
+func LatestMigration() (uint, error) {
+	migrations, err := embeddedMigrations()
+	if err != nil {
+		return 0, err
+	}
+	if len(migrations) == 0 {
+		return 0, fmt.Errorf("no embedded migrations")
+	}
+	return migrations[len(migrations)-1].Version, nil
+}
 
-	if err := m.Force(version); err != nil {
-		return fmt.Errorf("failed to force migration version: %w", err)
+// embeddedMigrations lists the up migrations embedded in the binary by version
+func embeddedMigrations() ([]MigrationStatus, error) {
+	entries, err := fs.ReadDir(db.Migrations, db.MigrationsDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read embedded migrations: %w", err)
 	}
 
-	logger.Info("✓ Forced migration version to %d", version)
-	return nil
+	var migrations []MigrationStatus
+	for _, entry := range entries {
+		parsed, err := source.DefaultParse(entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration file %s: %w", entry.Name(), err)
+		}
+		if parsed.Direction != source.Up {
+			continue
+		}
+		migrations = append(migrations, MigrationStatus{
+			Version: parsed.Version,
+			Name:    parsed.Identifier,
+		})
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// migrationStatuses marks the migrations up to version as applied, with the time
+// from appliedAt when known
+func migrationStatuses(migrations []MigrationStatus, version uint, dirty bool, appliedAt map[uint]time.Time) []MigrationStatus {
+	statuses := make([]MigrationStatus, len(migrations))
+	for i, migration := range migrations {
+		migration.Dirty = dirty && migration.Version == version
+		migration.Applied = migration.Version <= version && !migration.Dirty
+		if at, ok := appliedAt[migration.Version]; ok && migration.Applied {
+			migration.AppliedAt = &at
+		}
+		statuses[i] = migration
+	}
+	return statuses
 }
@@ -0,0 +1,68 @@
+package database
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEmbeddedMigrations(t *testing.T) {
+	migrations, err := embeddedMigrations()
+	if err != nil {
+		t.Fatalf("Failed to list migrations: %v", err)
+	}
+	if len(migrations) == 0 {
+		t.Fatal("Expected embedded migrations")
+	}
+	for i, m := range migrations {
+		if m.Name == "" {
+			t.Errorf("Migration %d has no name", m.Version)
+		}
+		if i > 0 && migrations[i-1].Version >= m.Version {
+			t.Errorf("Migrations not sorted: %d before %d", migrations[i-1].Version, m.Version)
+		}
+	}
+
+	latest, err := LatestMigration()
+	if err != nil || latest != migrations[len(migrations)-1].Version {
+		t.Errorf("LatestMigration() = %d, %v; want %d", latest, err, migrations[len(migrations)-1].Version)
+	}
+}
+
+func TestMigrationStatuses(t *testing.T) {
+	migrations := []MigrationStatus{{Version: 1, Name: "init"}, {Version: 2, Name: "users"}, {Version: 3, Name: "ratings"}}
+	applied := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		version   uint
+		dirty     bool
+		appliedAt map[uint]time.Time
+		want      []string
+	}{
+		{name: "nothing applied", version: 0, want: []string{"pending", "pending", "pending"}},
+		{name: "partially applied", version: 2, want: []string{"applied", "applied", "pending"}},
+		{name: "dirty", version: 3, dirty: true, want: []string{"applied", "applied", "dirty"}},
+		{name: "with history", version: 3, appliedAt: map[uint]time.Time{1: applied, 3: applied}, want: []string{"applied@", "applied", "applied@"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			statuses := migrationStatuses(migrations, tt.version, tt.dirty, tt.appliedAt)
+			for i, s := range statuses {
+				got := "pending"
+				switch {
+				case s.Dirty:
+					got = "dirty"
+				case s.Applied:
+					got = "applied"
+				}
+				if s.AppliedAt != nil {
+					got += "@"
+				}
+				if got != tt.want[i] {
+					t.Errorf("Migration %d: expected %s, got %s", s.Version, tt.want[i], got)
+				}
+			}
+		})
+	}
+}
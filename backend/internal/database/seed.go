@@ -0,0 +1,123 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/nomdb/backend/db"
+	"github.com/nomdb/backend/internal/auth"
+	"github.com/nomdb/backend/internal/logger"
+)
+
+// demoSeedSet is the fixture set that also creates the demo admin user
+const demoSeedSet = "demo"
+
+// Demo admin created with the demo fixtures
+const (
+	DemoAdminEmail    = "admin@nomdb.local"
+	DemoAdminUsername = "admin"
+)
+
+type seedFile struct {
+	name string
+	sql  string
+}
+
+// SeedEnvironments lists the environments that have seed fixtures
+func SeedEnvironments() []string {
+	envs := make([]string, 0, len(db.SeedSets))
+	for env := range db.SeedSets {
+		envs = append(envs, env)
+	}
+	sort.Strings(envs)
+	return envs
+}
+
+// Seed loads the seed fixtures of the environment in one transaction. Fixtures are
+// idempotent, so seeding twice leaves the data unchanged. Environments with the
+// demo fixtures also get a demo admin with adminPassword.
+func Seed(databaseURL, environment, adminPassword string) error {
+	files, withDemo, err := seedFiles(environment)
+	if err != nil {
+		return err
+	}
+
+	var adminHash string
+	if withDemo {
+		if adminPassword == "" {
+			return fmt.Errorf("admin password required for the %s fixtures", demoSeedSet)
+		}
+		if adminHash, err = auth.HashPassword(adminPassword, nil); err != nil {
+			return fmt.Errorf("failed to hash admin password: %w", err)
+		}
+	}
+
+	ctx := context.Background()
+	conn, err := pgx.Connect(ctx, databaseURL)
+	if err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer conn.Close(ctx)
+
+	return RunInTx(ctx, conn, func(tx pgx.Tx) error {
+		for _, file := range files {
+			if _, err := tx.Exec(ctx, file.sql); err != nil {
+				return fmt.Errorf("failed to load %s: %w", file.name, err)
+			}
+			logger.Info("✓ Loaded %s", file.name)
+		}
+
+		if withDemo {
+			tag, err := tx.Exec(ctx, `
+				INSERT INTO users (email, username, password_hash, provider, full_name, is_admin, email_verified)
+				VALUES ($1, $2, $3, 'local', 'Demo Admin', true, true)
+				ON CONFLICT DO NOTHING
+			`, DemoAdminEmail, DemoAdminUsername, adminHash)
+			if err != nil {
+				return fmt.Errorf("failed to create demo admin: %w", err)
+			}
+			if tag.RowsAffected() > 0 {
+				logger.Info("✓ Created demo admin %s", DemoAdminEmail)
+			}
+		}
+		return nil
+	})
+}
+
+// seedFiles returns the fixtures of the environment in load order and whether they
+// include the demo fixtures
+func seedFiles(environment string) ([]seedFile, bool, error) {
+	sets, ok := db.SeedSets[environment]
+	if !ok {
+		return nil, false, fmt.Errorf("unknown seed environment %q (expected one of %s)",
+			environment, strings.Join(SeedEnvironments(), ", "))
+	}
+
+	var files []seedFile
+	withDemo := false
+	for _, set := range sets {
+		if set == demoSeedSet {
+			withDemo = true
+		}
+		dir := path.Join("seeds", set)
+		entries, err := fs.ReadDir(db.Seeds, dir)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to read seed fixtures %s: %w", set, err)
+		}
+		// ReadDir returns the entries sorted by file name
+		for _, entry := range entries {
+			name := path.Join(dir, entry.Name())
+			sql, err := fs.ReadFile(db.Seeds, name)
+			if err != nil {
+				return nil, false, fmt.Errorf("failed to read %s: %w", name, err)
+			}
+			files = append(files, seedFile{name: name, sql: string(sql)})
+		}
+	}
+	return files, withDemo, nil
+}
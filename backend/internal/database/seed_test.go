@@ -0,0 +1,44 @@
+package database
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSeedFiles(t *testing.T) {
+	tests := []struct {
+		env      string
+		withDemo bool
+	}{
+		{env: "production", withDemo: false},
+		{env: "development", withDemo: true},
+		{env: "ci", withDemo: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.env, func(t *testing.T) {
+			files, withDemo, err := seedFiles(tt.env)
+			if err != nil {
+				t.Fatalf("Failed to read fixtures: %v", err)
+			}
+			if withDemo != tt.withDemo {
+				t.Errorf("Expected demo fixtures %v, got %v", tt.withDemo, withDemo)
+			}
+			if len(files) == 0 || files[0].name != "seeds/base/001_categories.sql" {
+				t.Fatalf("Expected base fixtures first, got %v", files)
+			}
+			for _, f := range files {
+				if !tt.withDemo && strings.HasPrefix(f.name, "seeds/demo/") {
+					t.Errorf("Unexpected demo fixture %s", f.name)
+				}
+				if !strings.Contains(f.sql, "ON CONFLICT") && !strings.Contains(f.sql, "NOT EXISTS") {
+					t.Errorf("Fixture %s is not idempotent", f.name)
+				}
+			}
+		})
+	}
+
+	if _, _, err := seedFiles("staging"); err == nil {
+		t.Error("Expected an error for an unknown environment")
+	}
+}
@@ -0,0 +1,98 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/nomdb/backend/internal/logger"
+	"github.com/nomdb/backend/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// maxTracedStatementLength truncates long statements such as batch inserts in spans
+const maxTracedStatementLength = 2048
+
+// queryTracer creates a client span for each query and logs slow queries. Arguments
+// are never recorded.
+type queryTracer struct{}
+
+type queryStartKey struct{}
+
+// queryStart is kept in the query context until TraceQueryEnd, which has no statement
+type queryStart struct {
+	statement string
+	time      time.Time
+}
+
+func (queryTracer) TraceQueryStart(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	statement := strings.TrimSpace(data.SQL)
+	if len(statement) > maxTracedStatementLength {
+		statement = statement[:maxTracedStatementLength]
+	}
+
+	ctx, _ = tracing.Tracer().Start(ctx, "db "+queryOperation(statement),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system.name", "postgresql"),
+			attribute.String("db.query.text", statement),
+		))
+	return context.WithValue(ctx, queryStartKey{}, queryStart{statement: statement, time: time.Now()})
+}
+
+func (queryTracer) TraceQueryEnd(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryEndData) {
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(attribute.Int64("db.response.rows_affected", data.CommandTag.RowsAffected()))
+
+	// No rows is an expected outcome of lookups, not a failed query
+	err := data.Err
+	if errors.Is(err, pgx.ErrNoRows) {
+		err = nil
+	}
+	tracing.EndSpan(span, err)
+
+	if start, ok := ctx.Value(queryStartKey{}).(queryStart); ok {
+		duration := time.Since(start.time)
+		queryDuration.WithLabelValues(queryName(start.statement)).Observe(duration.Seconds())
+		logger.LogDatabaseQuery(ctx, strings.Join(strings.Fields(start.statement), " "),
+			duration, data.CommandTag.RowsAffected())
+	}
+}
+
+// queryNameComment names a query for the latency metric, e.g. "-- name: GetRestaurant"
+const queryNameComment = "-- name:"
+
+// tablePattern finds the table a statement reads or writes first
+var tablePattern = regexp.MustCompile(`(?i)\b(?:FROM|INTO|UPDATE)\s+([a-z_][a-z0-9_.]*)`)
+
+// queryName labels a statement in the latency metric: the name of a leading
+// "-- name:" comment, or else its operation and first table (e.g. "SELECT restaurants"),
+// keeping the number of label values bounded
+func queryName(statement string) string {
+	if strings.HasPrefix(statement, "--") {
+		comment, rest, _ := strings.Cut(statement, "\n")
+		if name, ok := strings.CutPrefix(comment, queryNameComment); ok && strings.TrimSpace(name) != "" {
+			return strings.TrimSpace(name)
+		}
+		statement = strings.TrimSpace(rest)
+	}
+
+	operation := queryOperation(statement)
+	if match := tablePattern.FindStringSubmatch(statement); match != nil {
+		return operation + " " + strings.ToLower(match[1])
+	}
+	return operation
+}
+
+// queryOperation returns the leading keyword of a statement, e.g. SELECT
+func queryOperation(statement string) string {
+	fields := strings.Fields(statement)
+	if len(fields) == 0 {
+		return "QUERY"
+	}
+	return strings.ToUpper(fields[0])
+}
@@ -0,0 +1,77 @@
+package database
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/nomdb/backend/internal/logger"
+	"github.com/rs/zerolog"
+)
+
+func TestQueryOperation(t *testing.T) {
+	tests := map[string]string{
+		"SELECT id FROM users":                       "SELECT",
+		"insert into ratings VALUES ($1)":            "INSERT",
+		"\n\t\tWITH u AS (SELECT 1) SELECT * FROM u": "WITH",
+		"": "QUERY",
+	}
+	for statement, expected := range tests {
+		if got := queryOperation(statement); got != expected {
+			t.Errorf("queryOperation(%q) = %q, expected %q", statement, got, expected)
+		}
+	}
+}
+
+func TestQueryName(t *testing.T) {
+	tests := map[string]string{
+		"-- name: GetRestaurant\nSELECT * FROM restaurants WHERE id = $1":              "GetRestaurant",
+		"SELECT id FROM Restaurants r LEFT JOIN ratings rt ON r.id = rt.restaurant_id": "SELECT restaurants",
+		"INSERT INTO ratings (restaurant_id) VALUES ($1)":                              "INSERT ratings",
+		"UPDATE restaurant_suggestions s SET status = $1":                              "UPDATE restaurant_suggestions",
+		"DELETE FROM photos WHERE id = $1":                                             "DELETE photos",
+		"SELECT 1":                                                                     "SELECT",
+		"-- unnamed\nSELECT 1 FROM users":                                              "SELECT users",
+	}
+	for statement, expected := range tests {
+		if got := queryName(statement); got != expected {
+			t.Errorf("queryName(%q) = %q, expected %q", statement, got, expected)
+		}
+	}
+}
+
+func TestQueryTracer_LogsSlowQueries(t *testing.T) {
+	var buf bytes.Buffer
+	previous := logger.Logger
+	logger.Logger = zerolog.New(&buf)
+	defer func() { logger.Logger = previous }()
+	defer logger.SetSlowThresholds(time.Second, 250*time.Millisecond)
+
+	tracer := queryTracer{}
+	ctx := context.WithValue(context.Background(), logger.RequestIDKey, "req-123")
+	runQuery := func() {
+		queryCtx := tracer.TraceQueryStart(ctx, nil, pgx.TraceQueryStartData{SQL: "SELECT *\n\t\tFROM restaurants WHERE id = $1"})
+		time.Sleep(2 * time.Millisecond)
+		tracer.TraceQueryEnd(queryCtx, nil, pgx.TraceQueryEndData{CommandTag: pgconn.NewCommandTag("SELECT 1")})
+	}
+
+	logger.SetSlowThresholds(time.Second, time.Hour)
+	runQuery()
+	if buf.Len() != 0 {
+		t.Errorf("Expected fast query not to be logged, got %s", buf.String())
+	}
+
+	logger.SetSlowThresholds(time.Second, time.Millisecond)
+	runQuery()
+	output := buf.String()
+	for _, expected := range []string{`"level":"warn"`, "Slow database query", `"request_id":"req-123"`,
+		`"query":"SELECT * FROM restaurants WHERE id = $1"`, `"rows":1`} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected log to contain %s, got %s", expected, output)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// TxBeginner starts transactions; implemented by *pgxpool.Pool and pgx.Tx (which
+// starts a savepoint)
+type TxBeginner interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// WithTx runs fn in a transaction on the pool, committing if fn returns nil and rolling
+// back if it returns an error or panics
+func WithTx(ctx context.Context, fn func(tx pgx.Tx) error) error {
+	return RunInTx(ctx, pool, fn)
+}
+
+// RunInTx runs fn in a transaction started on db, as WithTx does on the pool
+func RunInTx(ctx context.Context, db TxBeginner, fn func(tx pgx.Tx) error) error {
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	// Rollback after a commit is a no-op
+	defer tx.Rollback(ctx)
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v4"
+)
+
+func TestRunInTx(t *testing.T) {
+	ctx := context.Background()
+	errInsert := errors.New("insert failed")
+
+	tests := []struct {
+		name   string
+		expect func(mock pgxmock.PgxPoolIface)
+		fn     func(tx pgx.Tx) error
+		err    error
+	}{
+		{
+			name: "commits on success",
+			expect: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT").WillReturnResult(pgxmock.NewResult("INSERT", 1))
+				mock.ExpectCommit()
+			},
+			fn: func(tx pgx.Tx) error {
+				_, err := tx.Exec(ctx, "INSERT INTO food_types (name) VALUES ('Pizza')")
+				return err
+			},
+		},
+		{
+			name: "rolls back on error",
+			expect: func(mock pgxmock.PgxPoolIface) {
+				mock.ExpectBegin()
+				mock.ExpectExec("INSERT").WillReturnError(errInsert)
+				mock.ExpectRollback()
+			},
+			fn: func(tx pgx.Tx) error {
+				_, err := tx.Exec(ctx, "INSERT INTO food_types (name) VALUES ('Pizza')")
+				return err
+			},
+			err: errInsert,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatalf("Failed to create mock: %v", err)
+			}
+			defer mock.Close()
+			tt.expect(mock)
+
+			if err := RunInTx(ctx, mock, tt.fn); !errors.Is(err, tt.err) {
+				t.Errorf("Expected error %v, got %v", tt.err, err)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unmet expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestRunInTx_RollsBackOnPanic(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatalf("Failed to create mock: %v", err)
+	}
+	defer mock.Close()
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("Expected the panic to propagate")
+			}
+		}()
+		RunInTx(context.Background(), mock, func(tx pgx.Tx) error {
+			panic("boom")
+		})
+	}()
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unmet expectations: %v", err)
+	}
+}
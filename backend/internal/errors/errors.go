@@ -7,33 +7,91 @@ import (
 	"github.com/nomdb/backend/internal/logger"
 )
 
-// ErrorResponse represents a structured API error response
+// ContentType is the media type of error responses (RFC 7807)
+const ContentType = "application/problem+json"
+
+// ErrorResponse represents a structured API error response in the RFC 7807 problem
+// details format
 type ErrorResponse struct {
-	Error   string `json:"error"`           // User-friendly error message
-	Code    string `json:"code"`            // Error code for programmatic handling
-	Status  int    `json:"status"`          // HTTP status code
-	Details string `json:"details,omitempty"` // Additional details (optional)
+	Type      string `json:"type"`                 // Always about:blank; Code identifies the error
+	Title     string `json:"title"`                // HTTP status text
+	Status    int    `json:"status"`               // HTTP status code
+	Detail    string `json:"detail"`               // User-friendly error message
+	Code      string `json:"code"`                 // Error code for programmatic handling
+	Details   string `json:"details,omitempty"`    // Additional details (optional)
+	RequestID string `json:"request_id,omitempty"` // Matches the X-Request-ID header and the server logs
+
+	cause string // Logged but never sent to the client
 }
 
 // Error codes for programmatic handling
 const (
-	CodeValidationError   = "VALIDATION_ERROR"
-	CodeNotFound          = "NOT_FOUND"
-	CodeDuplicate         = "DUPLICATE_ENTRY"
-	CodeUnauthorized      = "UNAUTHORIZED"
-	CodeForbidden         = "FORBIDDEN"
-	CodeInternalError     = "INTERNAL_ERROR"
-	CodeBadRequest        = "BAD_REQUEST"
-	CodeConflict          = "CONFLICT"
-	CodeRateLimitExceeded = "RATE_LIMIT_EXCEEDED"
+	CodeValidationError      = "VALIDATION_ERROR"
+	CodeNotFound             = "NOT_FOUND"
+	CodeDuplicate            = "DUPLICATE_ENTRY"
+	CodeUnauthorized         = "UNAUTHORIZED"
+	CodeForbidden            = "FORBIDDEN"
+	CodeInternalError        = "INTERNAL_ERROR"
+	CodeBadRequest           = "BAD_REQUEST"
+	CodeConflict             = "CONFLICT"
+	CodeRateLimitExceeded    = "RATE_LIMIT_EXCEEDED"
+	CodeMethodNotAllowed     = "METHOD_NOT_ALLOWED"
+	CodePayloadTooLarge      = "PAYLOAD_TOO_LARGE"
+	CodeUnsupportedMediaType = "UNSUPPORTED_MEDIA_TYPE"
+	CodePreconditionFailed   = "PRECONDITION_FAILED"
+	CodeServiceUnavailable   = "SERVICE_UNAVAILABLE"
+	CodeBadGateway           = "BAD_GATEWAY"
 )
 
+// codeForStatus returns the error code of responses created from a status alone
+func codeForStatus(status int) string {
+	switch status {
+	case http.StatusUnauthorized:
+		return CodeUnauthorized
+	case http.StatusForbidden:
+		return CodeForbidden
+	case http.StatusNotFound, http.StatusGone:
+		return CodeNotFound
+	case http.StatusMethodNotAllowed:
+		return CodeMethodNotAllowed
+	case http.StatusConflict:
+		return CodeConflict
+	case http.StatusPreconditionFailed:
+		return CodePreconditionFailed
+	case http.StatusRequestEntityTooLarge:
+		return CodePayloadTooLarge
+	case http.StatusUnsupportedMediaType:
+		return CodeUnsupportedMediaType
+	case http.StatusUnprocessableEntity:
+		return CodeValidationError
+	case http.StatusTooManyRequests:
+		return CodeRateLimitExceeded
+	case http.StatusBadGateway:
+		return CodeBadGateway
+	case http.StatusServiceUnavailable:
+		return CodeServiceUnavailable
+	}
+	if status >= http.StatusInternalServerError {
+		return CodeInternalError
+	}
+	return CodeBadRequest
+}
+
+// New creates an error with the code matching status
+func New(status int, message string) *ErrorResponse {
+	return &ErrorResponse{
+		Detail: message,
+		Code:   codeForStatus(status),
+		Status: status,
+	}
+}
+
 // Common error constructors
 
 // BadRequest creates a 400 Bad Request error
 func BadRequest(message string) *ErrorResponse {
 	return &ErrorResponse{
-		Error:  message,
+		Detail: message,
 		Code:   CodeBadRequest,
 		Status: http.StatusBadRequest,
 	}
@@ -42,7 +100,7 @@ func BadRequest(message string) *ErrorResponse {
 // ValidationError creates a 400 Validation Error
 func ValidationError(message string, details string) *ErrorResponse {
 	return &ErrorResponse{
-		Error:   message,
+		Detail:  message,
 		Code:    CodeValidationError,
 		Status:  http.StatusBadRequest,
 		Details: details,
@@ -52,7 +110,7 @@ func ValidationError(message string, details string) *ErrorResponse {
 // NotFound creates a 404 Not Found error
 func NotFound(resource string) *ErrorResponse {
 	return &ErrorResponse{
-		Error:  resource + " not found",
+		Detail: resource + " not found",
 		Code:   CodeNotFound,
 		Status: http.StatusNotFound,
 	}
@@ -61,7 +119,7 @@ func NotFound(resource string) *ErrorResponse {
 // Duplicate creates a 409 Conflict error for duplicate entries
 func Duplicate(resource string) *ErrorResponse {
 	return &ErrorResponse{
-		Error:  resource + " already exists",
+		Detail: resource + " already exists",
 		Code:   CodeDuplicate,
 		Status: http.StatusConflict,
 	}
@@ -70,26 +128,27 @@ func Duplicate(resource string) *ErrorResponse {
 // Conflict creates a 409 Conflict error
 func Conflict(message string) *ErrorResponse {
 	return &ErrorResponse{
-		Error:  message,
+		Detail: message,
 		Code:   CodeConflict,
 		Status: http.StatusConflict,
 	}
 }
 
-// InternalError creates a 500 Internal Server Error
+// InternalError creates a 500 Internal Server Error. The message is logged, the
+// client only sees a generic error.
 func InternalError(message string) *ErrorResponse {
 	return &ErrorResponse{
-		Error:  "Internal server error",
+		Detail: "Internal server error",
 		Code:   CodeInternalError,
 		Status: http.StatusInternalServerError,
-		Details: message, // Log the actual error but don't expose to client in production
+		cause:  message,
 	}
 }
 
 // Unauthorized creates a 401 Unauthorized error
 func Unauthorized(message string) *ErrorResponse {
 	return &ErrorResponse{
-		Error:  message,
+		Detail: message,
 		Code:   CodeUnauthorized,
 		Status: http.StatusUnauthorized,
 	}
@@ -98,29 +157,64 @@ func Unauthorized(message string) *ErrorResponse {
 // Forbidden creates a 403 Forbidden error
 func Forbidden(message string) *ErrorResponse {
 	return &ErrorResponse{
-		Error:  message,
+		Detail: message,
 		Code:   CodeForbidden,
 		Status: http.StatusForbidden,
 	}
 }
 
-// RespondWithError writes a JSON error response to the client
+// messages holds the translations of error messages by locale
+var messages = map[string]map[string]string{
+	"de": germanMessages,
+}
+
+// Translate returns message in locale, or message itself if it has no translation
+func Translate(locale, message string) string {
+	if translated, ok := messages[locale][message]; ok {
+		return translated
+	}
+	return message
+}
+
+// RespondWithError writes a JSON error response to the client. The request ID is taken
+// from the X-Request-ID response header set by the request ID middleware, and the
+// message is translated into the Content-Language set by the locale middleware. Codes
+// and titles are never translated.
 func RespondWithError(w http.ResponseWriter, err *ErrorResponse) {
-	w.Header().Set("Content-Type", "application/json")
+	err.Type = "about:blank"
+	err.Title = http.StatusText(err.Status)
+	err.RequestID = w.Header().Get("X-Request-ID")
+	err.Detail = Translate(w.Header().Get("Content-Language"), err.Detail)
+
+	// Drop headers meant for the success response, as http.Error does
+	w.Header().Del("Content-Length")
+	w.Header().Set("Content-Type", ContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
 	w.WriteHeader(err.Status)
 
 	if encodeErr := json.NewEncoder(w).Encode(err); encodeErr != nil {
 		logger.Error("Failed to encode error response: %v", encodeErr)
 	}
 
-	// Log the error with context
-	if err.Status >= 500 {
-		logger.Error("Error %d: %s - %s (details: %s)", err.Status, err.Code, err.Error, err.Details)
+	// Log the error with context; overload is reported by metrics, logging each
+	// rejected request would add to it
+	if err.Status >= 500 && err.Status != http.StatusServiceUnavailable {
+		logger.Error("Error %d: %s - %s (cause: %s, request: %s)", err.Status, err.Code, err.Detail, err.cause, err.RequestID)
 	} else if err.Status >= 400 {
-		logger.Warn("Error %d: %s - %s", err.Status, err.Code, err.Error)
+		logger.Debug("Error %d: %s - %s", err.Status, err.Code, err.Detail)
 	}
 }
 
+// Error replies with a JSON error of status, like http.Error
+func Error(w http.ResponseWriter, message string, status int) {
+	RespondWithError(w, New(status, message))
+}
+
+// Internal replies with a generic 500 error, logging err without exposing it
+func Internal(w http.ResponseWriter, err error) {
+	RespondWithError(w, InternalError(err.Error()))
+}
+
 // HandleDatabaseError converts common database errors to structured responses
 func HandleDatabaseError(err error, resource string) *ErrorResponse {
 	if err == nil {
@@ -0,0 +1,107 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestError(t *testing.T) {
+	rec := httptest.NewRecorder()
+	rec.Header().Set("X-Request-ID", "req-123")
+	rec.Header().Set("Content-Length", "42")
+	Error(rec, "Restaurant not found", http.StatusNotFound)
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404, got %d", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != ContentType {
+		t.Errorf("Expected Content-Type %s, got %s", ContentType, ct)
+	}
+	if rec.Header().Get("Content-Length") != "" {
+		t.Error("Expected Content-Length to be removed")
+	}
+
+	var response ErrorResponse
+	if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	expected := ErrorResponse{
+		Type:      "about:blank",
+		Title:     "Not Found",
+		Status:    http.StatusNotFound,
+		Detail:    "Restaurant not found",
+		Code:      CodeNotFound,
+		RequestID: "req-123",
+	}
+	if response != expected {
+		t.Errorf("Expected %+v, got %+v", expected, response)
+	}
+}
+
+func TestError_Translated(t *testing.T) {
+	rec := httptest.NewRecorder()
+	rec.Header().Set("Content-Language", "de")
+	Error(rec, "Restaurant not found", http.StatusNotFound)
+
+	var response ErrorResponse
+	if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response.Detail != "Restaurant nicht gefunden" {
+		t.Errorf("Expected a German message, got %q", response.Detail)
+	}
+	if response.Code != CodeNotFound || response.Title != "Not Found" {
+		t.Errorf("Expected the code and title to stay untranslated, got %q and %q", response.Code, response.Title)
+	}
+
+	// Messages without a translation stay English
+	if got := Translate("de", "Invalid width: 0"); got != "Invalid width: 0" {
+		t.Errorf("Expected the message to be kept, got %q", got)
+	}
+}
+
+func TestInternal_HidesCause(t *testing.T) {
+	rec := httptest.NewRecorder()
+	Internal(rec, fmt.Errorf(`ERROR: relation "restaurants" does not exist (SQLSTATE 42P01)`))
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("Expected 500, got %d", rec.Code)
+	}
+	body := rec.Body.String()
+	if strings.Contains(body, "relation") || strings.Contains(body, "SQLSTATE") {
+		t.Errorf("Expected the cause not to be sent to the client, got %s", body)
+	}
+	if !strings.Contains(body, `"code":"INTERNAL_ERROR"`) {
+		t.Errorf("Expected INTERNAL_ERROR code, got %s", body)
+	}
+}
+
+func TestCodeForStatus(t *testing.T) {
+	tests := []struct {
+		status int
+		code   string
+	}{
+		{http.StatusBadRequest, CodeBadRequest},
+		{http.StatusUnauthorized, CodeUnauthorized},
+		{http.StatusForbidden, CodeForbidden},
+		{http.StatusNotFound, CodeNotFound},
+		{http.StatusConflict, CodeConflict},
+		{http.StatusRequestEntityTooLarge, CodePayloadTooLarge},
+		{http.StatusUnprocessableEntity, CodeValidationError},
+		{http.StatusTooManyRequests, CodeRateLimitExceeded},
+		{http.StatusTeapot, CodeBadRequest},
+		{http.StatusInternalServerError, CodeInternalError},
+		{http.StatusServiceUnavailable, CodeServiceUnavailable},
+		{http.StatusGatewayTimeout, CodeInternalError},
+	}
+
+	for _, tt := range tests {
+		if code := codeForStatus(tt.status); code != tt.code {
+			t.Errorf("codeForStatus(%d) = %s, want %s", tt.status, code, tt.code)
+		}
+	}
+}
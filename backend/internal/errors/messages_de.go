@@ -0,0 +1,124 @@
+package errors
+
+// germanMessages translates the error messages of the handlers and middleware into
+// German. Messages missing here, such as those with variable parts, stay English.
+var germanMessages = map[string]string{
+	// Generic
+	"Internal server error":                                    "Interner Serverfehler",
+	"Invalid request body":                                     "Ungültiger Anfragetext",
+	"Not found":                                                "Nicht gefunden",
+	"Method not allowed":                                       "Methode nicht erlaubt",
+	"Unauthorized":                                             "Nicht angemeldet",
+	"Invalid cursor":                                           "Ungültiger Cursor",
+	"Invalid status":                                           "Ungültiger Status",
+	"Content-Type must be application/json":                    "Content-Type muss application/json sein",
+	"Cannot perform operation due to related data":             "Der Vorgang ist wegen verknüpfter Daten nicht möglich",
+	"Rate limit exceeded. Please try again later.":             "Zu viele Anfragen. Bitte versuche es später erneut.",
+	"Server is busy. Please try again shortly.":                "Der Server ist ausgelastet. Bitte versuche es gleich noch einmal.",
+	"Query parameter 'q' is required":                          "Der Parameter 'q' ist erforderlich",
+	"Name is required":                                         "Name ist erforderlich",
+	"Name must be 1-100 characters":                            "Der Name muss 1 bis 100 Zeichen lang sein",
+	"Name is required (max 255 characters)":                    "Name ist erforderlich (höchstens 255 Zeichen)",
+	"Streaming unsupported":                                    "Streaming wird nicht unterstützt",
+	"Idempotency-Key must be at most 255 characters":           "Idempotency-Key darf höchstens 255 Zeichen lang sein",
+	"A request with this Idempotency-Key is still in progress": "Eine Anfrage mit diesem Idempotency-Key wird noch bearbeitet",
+	"A request with this Idempotency-Key failed, retry it":     "Eine Anfrage mit diesem Idempotency-Key ist fehlgeschlagen, bitte wiederholen",
+	"An unexpected error occurred. Please mention the request ID when reporting it.": "Ein unerwarteter Fehler ist aufgetreten. Bitte gib die Request-ID an, wenn du ihn meldest.",
+
+	// Authentication and permissions
+	"Missing authorization header":                                 "Authorization-Header fehlt",
+	"Invalid token":                                                "Ungültiges Token",
+	"Token has expired":                                            "Das Token ist abgelaufen",
+	"Invalid credentials":                                          "Ungültige Anmeldedaten",
+	"Account is disabled":                                          "Das Konto ist deaktiviert",
+	"Authentication service not available":                         "Der Anmeldedienst ist nicht verfügbar",
+	"Missing or invalid CSRF token":                                "CSRF-Token fehlt oder ist ungültig",
+	"Refresh token is required":                                    "Refresh-Token ist erforderlich",
+	"Refresh token expired":                                        "Das Refresh-Token ist abgelaufen",
+	"Registration is closed":                                       "Die Registrierung ist geschlossen",
+	"Invalid email format":                                         "Ungültiges E-Mail-Format",
+	"User with this email or username already exists":              "Ein Benutzer mit dieser E-Mail-Adresse oder diesem Benutzernamen existiert bereits",
+	"Invalid or expired reset token":                               "Ungültiges oder abgelaufenes Token zum Zurücksetzen",
+	"Token and password are required":                              "Token und Passwort sind erforderlich",
+	"Too many password reset requests. Please try again later.":    "Zu viele Anfragen zum Zurücksetzen des Passworts. Bitte versuche es später erneut.",
+	"Unknown login provider":                                       "Unbekannter Anmeldeanbieter",
+	"OIDC not configured":                                          "OIDC ist nicht konfiguriert",
+	"Forbidden - admin access required":                            "Verboten - Administratorrechte erforderlich",
+	"Forbidden - API key lacks the admin scope":                    "Verboten - dem API-Schlüssel fehlt der Admin-Bereich",
+	"API key does not have the required scope":                     "Dem API-Schlüssel fehlt der erforderliche Bereich",
+	"API key rate limit exceeded. Please try again later.":         "Anfragelimit des API-Schlüssels überschritten. Bitte versuche es später erneut.",
+	"Service account rate limit exceeded. Please try again later.": "Anfragelimit des Dienstkontos überschritten. Bitte versuche es später erneut.",
+	"Only admins can create keys with the admin scope":             "Nur Administratoren können Schlüssel mit dem Admin-Bereich erstellen",
+	"Invalid API key ID":                                           "Ungültige API-Schlüssel-ID",
+	"Invalid user ID":                                              "Ungültige Benutzer-ID",
+	"User not found":                                               "Benutzer nicht gefunden",
+	"Invalid invite ID":                                            "Ungültige Einladungs-ID",
+	"Invite not found":                                             "Einladung nicht gefunden",
+	"Service account not found":                                    "Dienstkonto nicht gefunden",
+	"Service account is disabled":                                  "Das Dienstkonto ist deaktiviert",
+
+	// Restaurants, ratings, categories and food types
+	"Invalid restaurant ID":          "Ungültige Restaurant-ID",
+	"Restaurant not found":           "Restaurant nicht gefunden",
+	"Restaurant ID is required":      "Restaurant-ID ist erforderlich",
+	"This restaurant already exists": "Dieses Restaurant existiert bereits",
+	"This restaurant already exists in the database. Please search for it instead.": "Dieses Restaurant ist bereits in der Datenbank. Bitte suche stattdessen danach.",
+	"Ratings must be between 1 and 5":                                               "Bewertungen müssen zwischen 1 und 5 liegen",
+	"Rating not found":                                                              "Bewertung nicht gefunden",
+	"Only the author or an admin can delete this rating":                            "Nur der Verfasser oder ein Administrator kann diese Bewertung löschen",
+	"Invalid category ID":                                                           "Ungültige Kategorie-ID",
+	"Category not found":                                                            "Kategorie nicht gefunden",
+	"Invalid food type ID":                                                          "Ungültige Speiseart-ID",
+	"Food type not found":                                                           "Speiseart nicht gefunden",
+	"Unsupported language":                                                          "Nicht unterstützte Sprache",
+	"Translation not found":                                                         "Übersetzung nicht gefunden",
+
+	// Suggestions
+	"Invalid suggestion ID":                     "Ungültige Vorschlags-ID",
+	"Suggestion not found":                      "Vorschlag nicht gefunden",
+	"This suggestion already exists":            "Dieser Vorschlag existiert bereits",
+	"You have already approved this suggestion": "Du hast diesen Vorschlag bereits freigegeben",
+	"Note must be at most 255 characters":       "Die Notiz darf höchstens 255 Zeichen lang sein",
+
+	// Photos
+	"Invalid photo ID":                            "Ungültige Foto-ID",
+	"Photo not found":                             "Foto nicht gefunden",
+	"Caption is required":                         "Bildunterschrift ist erforderlich",
+	"Failed to read file":                         "Die Datei konnte nicht gelesen werden",
+	"Only image files are allowed":                "Nur Bilddateien sind erlaubt",
+	"Only JPEG, PNG, and WebP images are allowed": "Nur JPEG-, PNG- und WebP-Bilder sind erlaubt",
+	"Invalid photo type. Must be one of: menu, food, interior, receipt": "Ungültiger Fototyp. Erlaubt sind: menu, food, interior, receipt",
+	"Only the uploader or an admin can modify this photo":               "Nur der Hochladende oder ein Administrator kann dieses Foto ändern",
+	"Restaurant has no photos":                                          "Das Restaurant hat keine Fotos",
+	"Upload not found":                                                  "Upload nicht gefunden",
+	"Upload token is required":                                          "Upload-Token ist erforderlich",
+	"Upload token expired":                                              "Das Upload-Token ist abgelaufen",
+	"Upload already confirmed":                                          "Der Upload wurde bereits bestätigt",
+	"Upload belongs to another user":                                    "Der Upload gehört einem anderen Benutzer",
+	"URL is required":                                                   "URL ist erforderlich",
+	"URL is not allowed":                                                "Diese URL ist nicht erlaubt",
+
+	// Google Maps
+	"Place not found":                            "Ort nicht gefunden",
+	"Place ID is required":                       "Orts-ID ist erforderlich",
+	"Travel times require a Google Maps API key": "Fahrzeiten erfordern einen Google-Maps-API-Schlüssel",
+	"Google Maps is temporarily unavailable":     "Google Maps ist vorübergehend nicht verfügbar",
+	"The daily Google Maps budget of this server is used up, try again after midnight UTC": "Das tägliche Google-Maps-Kontingent dieses Servers ist aufgebraucht, versuche es nach Mitternacht UTC erneut",
+
+	// Spaces
+	"Space not found":                                              "Bereich nicht gefunden",
+	"Only members can change this space":                           "Nur Mitglieder können diesen Bereich ändern",
+	"Forbidden - space admin access required":                      "Verboten - Administratorrechte für den Bereich erforderlich",
+	"Forbidden - only owners may change owners":                    "Verboten - nur Eigentümer können Eigentümer ändern",
+	"The default space is open to all users and cannot be changed": "Der Standardbereich ist für alle Benutzer offen und kann nicht geändert werden",
+	"The space needs an owner; make another member owner first":    "Der Bereich braucht einen Eigentümer; mache zuerst ein anderes Mitglied zum Eigentümer",
+	"Slug must be 2-50 lowercase letters, digits or dashes":        "Der Kurzname muss aus 2 bis 50 Kleinbuchstaben, Ziffern oder Bindestrichen bestehen",
+	"Role must be owner, admin or member":                          "Die Rolle muss owner, admin oder member sein",
+	"Role must be admin or member":                                 "Die Rolle muss admin oder member sein",
+
+	// Settings and webhooks
+	"Unknown setting":           "Unbekannte Einstellung",
+	"Setting is not overridden": "Die Einstellung ist nicht überschrieben",
+	"Invalid webhook ID":        "Ungültige Webhook-ID",
+	"Webhook not found":         "Webhook nicht gefunden",
+}
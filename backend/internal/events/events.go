@@ -0,0 +1,173 @@
+// Package events broadcasts entity changes to subscribers in this process and, with the
+// postgres backend, to every other server instance through LISTEN/NOTIFY. Caches use it
+// to drop entries written on another replica, and the SSE streams to deliver live
+// updates whichever instance handled the write.
+package events
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/nomdb/backend/internal/logger"
+)
+
+// Event backends selectable via EVENTS_BACKEND
+const (
+	BackendLocal    = "local"
+	BackendPostgres = "postgres"
+)
+
+// Channel is the Postgres notification channel shared by all instances
+const Channel = "nomdb_events"
+
+// maxPayload keeps notifications below the 8000 byte NOTIFY payload limit
+const maxPayload = 7900
+
+// Internal event types, not streamed to clients
+const (
+	// TypeCacheInvalidated carries the cache keys deleted by a write
+	TypeCacheInvalidated = "cache.invalidated"
+	// TypeLogLevelsChanged carries log levels an admin set, for every instance to apply
+	TypeLogLevelsChanged = "log_levels.changed"
+	// TypeResync is delivered locally when the listener reconnects, since
+	// notifications sent while it was disconnected are lost
+	TypeResync = "events.resync"
+)
+
+// Event describes a change
+type Event struct {
+	Type         string          `json:"type"`                                // e.g. restaurant.updated, photo.ready
+	ID           int             `json:"id,omitempty"`                        // ID of the changed entity
+	RestaurantID int             `json:"restaurant_id,omitempty"`             // Restaurant the entity belongs to
+	SpaceID      int             `json:"space_id,omitempty"`                  // Space of the entity, 0 for data of every space
+	Keys         []string        `json:"keys,omitempty"`                      // Cache keys of cache.invalidated
+	Data         json.RawMessage `json:"data,omitempty" swaggertype:"object"` // Type-specific payload
+
+	// Remote is set on events published by another instance
+	Remote bool `json:"-"`
+}
+
+// Handler receives events. Handlers run on the publishing goroutine (or the listener)
+// and must not block.
+type Handler func(Event)
+
+// Config selects the event backend
+type Config struct {
+	Backend string
+	Pool    *pgxpool.Pool // Required for the postgres backend
+}
+
+// envelope is the notification payload
+type envelope struct {
+	Origin string `json:"origin"`
+	Event  Event  `json:"event"`
+}
+
+var (
+	mu       sync.RWMutex
+	handlers = make(map[int]Handler)
+	nextID   int
+
+	// instanceID identifies this process, so it ignores its own notifications
+	instanceID = newInstanceID()
+
+	// notify sends a payload to the other instances; nil with the local backend
+	notify func(ctx context.Context, payload string) error
+)
+
+// Init configures the event backend and, for postgres, starts listening for
+// notifications of other instances
+func Init(cfg Config) error {
+	switch cfg.Backend {
+	case BackendLocal, "":
+		notify = nil
+		logger.Info("✅ Event bus: local (changes are not shared with other instances)")
+	case BackendPostgres:
+		if cfg.Pool == nil {
+			return fmt.Errorf("the postgres event backend requires a database pool")
+		}
+		pool := cfg.Pool
+		notify = func(ctx context.Context, payload string) error {
+			_, err := pool.Exec(ctx, "SELECT pg_notify($1, $2)", Channel, payload)
+			return err
+		}
+		go listen(pool)
+		logger.Info("✅ Event bus: postgres (LISTEN %s)", Channel)
+	default:
+		return fmt.Errorf("unknown event backend %q", cfg.Backend)
+	}
+	return nil
+}
+
+// Subscribe registers h for all events and returns a function removing it
+func Subscribe(h Handler) func() {
+	mu.Lock()
+	defer mu.Unlock()
+	id := nextID
+	nextID++
+	handlers[id] = h
+	return func() {
+		mu.Lock()
+		defer mu.Unlock()
+		delete(handlers, id)
+	}
+}
+
+// Publish delivers e to the local subscribers and broadcasts it to the other
+// instances. Broadcast errors are logged; the change itself already happened.
+func Publish(ctx context.Context, e Event) {
+	dispatch(e)
+	if notify == nil {
+		return
+	}
+
+	payload, err := json.Marshal(envelope{Origin: instanceID, Event: e})
+	if err != nil {
+		logger.Warn("Failed to encode event %s: %v", e.Type, err)
+		return
+	}
+	if len(payload) > maxPayload {
+		logger.Warn("Event %s is too large to broadcast (%d bytes)", e.Type, len(payload))
+		return
+	}
+	// Deliver even if the client that caused the change has gone
+	if err := notify(context.WithoutCancel(ctx), string(payload)); err != nil {
+		logger.Warn("Failed to broadcast event %s: %v", e.Type, err)
+	}
+}
+
+// dispatch calls the local handlers
+func dispatch(e Event) {
+	mu.RLock()
+	defer mu.RUnlock()
+	for _, h := range handlers {
+		h(e)
+	}
+}
+
+// receive dispatches a notification of another instance
+func receive(payload string) {
+	var env envelope
+	if err := json.Unmarshal([]byte(payload), &env); err != nil {
+		logger.Warn("Ignoring invalid event notification: %v", err)
+		return
+	}
+	if env.Origin == instanceID {
+		return
+	}
+	env.Event.Remote = true
+	dispatch(env.Event)
+}
+
+func newInstanceID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		panic(fmt.Sprintf("failed to generate instance ID: %v", err))
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,115 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// capture subscribes for the duration of the test and returns the received events
+func capture(t *testing.T) *[]Event {
+	t.Helper()
+	var received []Event
+	unsubscribe := Subscribe(func(e Event) { received = append(received, e) })
+	t.Cleanup(unsubscribe)
+	return &received
+}
+
+// withNotify replaces the broadcast with one recording payloads
+func withNotify(t *testing.T, err error) *[]string {
+	t.Helper()
+	var payloads []string
+	notify = func(_ context.Context, payload string) error {
+		payloads = append(payloads, payload)
+		return err
+	}
+	t.Cleanup(func() { notify = nil })
+	return &payloads
+}
+
+func TestPublish_Local(t *testing.T) {
+	received := capture(t)
+
+	Publish(context.Background(), Event{Type: "restaurant.updated", ID: 7})
+	if len(*received) != 1 || (*received)[0].Type != "restaurant.updated" || (*received)[0].Remote {
+		t.Errorf("Expected one local event, got %+v", *received)
+	}
+}
+
+func TestPublish_Broadcast(t *testing.T) {
+	received := capture(t)
+	payloads := withNotify(t, nil)
+
+	Publish(context.Background(), Event{Type: TypeCacheInvalidated, Keys: []string{"categories"}})
+	if len(*payloads) != 1 {
+		t.Fatalf("Expected one notification, got %d", len(*payloads))
+	}
+
+	// The own notification comes back and is ignored
+	receive((*payloads)[0])
+	if len(*received) != 1 {
+		t.Errorf("Expected own notifications to be ignored, got %d events", len(*received))
+	}
+
+	// The same notification from another instance is delivered as remote
+	var env envelope
+	if err := json.Unmarshal([]byte((*payloads)[0]), &env); err != nil {
+		t.Fatalf("Invalid payload: %v", err)
+	}
+	env.Origin = "other"
+	payload, _ := json.Marshal(env)
+	receive(string(payload))
+	if len(*received) != 2 {
+		t.Fatalf("Expected the remote event, got %d events", len(*received))
+	}
+	if e := (*received)[1]; !e.Remote || e.Type != TypeCacheInvalidated || len(e.Keys) != 1 || e.Keys[0] != "categories" {
+		t.Errorf("Unexpected remote event %+v", e)
+	}
+}
+
+func TestPublish_TooLarge(t *testing.T) {
+	received := capture(t)
+	payloads := withNotify(t, nil)
+
+	data, _ := json.Marshal(strings.Repeat("x", maxPayload))
+	Publish(context.Background(), Event{Type: "photo.failed", Data: data})
+	if len(*payloads) != 0 {
+		t.Error("Expected oversized events not to be broadcast")
+	}
+	if len(*received) != 1 {
+		t.Error("Expected oversized events to be delivered locally")
+	}
+}
+
+func TestPublish_BroadcastError(t *testing.T) {
+	received := capture(t)
+	withNotify(t, errors.New("connection refused"))
+
+	Publish(context.Background(), Event{Type: "rating.created"})
+	if len(*received) != 1 {
+		t.Error("Expected local delivery despite the broadcast error")
+	}
+}
+
+func TestReceive_Invalid(t *testing.T) {
+	received := capture(t)
+	receive("not json")
+	if len(*received) != 0 {
+		t.Errorf("Expected invalid notifications to be ignored, got %+v", *received)
+	}
+}
+
+func TestInit(t *testing.T) {
+	t.Cleanup(func() { notify = nil })
+	if err := Init(Config{Backend: "kafka"}); err == nil {
+		t.Error("Expected an error for an unknown backend")
+	}
+	if err := Init(Config{Backend: BackendPostgres}); err == nil {
+		t.Error("Expected an error without a pool")
+	}
+	if err := Init(Config{Backend: BackendLocal}); err != nil || notify != nil {
+		t.Errorf("Expected the local backend not to broadcast (%v)", err)
+	}
+}
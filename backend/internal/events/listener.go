@@ -0,0 +1,60 @@
+package events
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/nomdb/backend/internal/logger"
+)
+
+// Reconnect delays of the listener after losing its connection
+const (
+	minReconnectDelay = time.Second
+	maxReconnectDelay = 30 * time.Second
+)
+
+// listen receives notifications on a dedicated connection for the lifetime of the
+// process, reconnecting with backoff
+func listen(pool *pgxpool.Pool) {
+	delay := minReconnectDelay
+	connected := false
+	for {
+		err := listenOnce(context.Background(), pool, func() {
+			delay = minReconnectDelay
+			if connected {
+				// Notifications sent while disconnected are lost
+				logger.Info("✅ Event listener reconnected")
+				dispatch(Event{Type: TypeResync, Remote: true})
+			}
+			connected = true
+		})
+		logger.Warn("⚠️  Event listener disconnected: %v; reconnecting in %s", err, delay)
+		time.Sleep(delay)
+		delay = min(delay*2, maxReconnectDelay)
+	}
+}
+
+// listenOnce listens until the connection fails. The connection is taken out of the
+// pool, so it never goes back to it in LISTEN state.
+func listenOnce(ctx context.Context, pool *pgxpool.Pool, onListening func()) error {
+	pooled, err := pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	conn := pooled.Hijack()
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+Channel); err != nil {
+		return err
+	}
+	onListening()
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		receive(notification.Payload)
+	}
+}
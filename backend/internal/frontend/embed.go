@@ -0,0 +1,15 @@
+//go:build embedfrontend
+
+package frontend
+
+import (
+	"embed"
+	"io/fs"
+)
+
+//go:embed all:dist
+var dist embed.FS
+
+func init() {
+	assets, _ = fs.Sub(dist, "dist")
+}
@@ -0,0 +1,87 @@
+// Package frontend serves the web frontend from the server binary, so small deployments
+// need no separate web server. The built frontend is embedded when the server is built
+// with the embedfrontend tag after copying frontend/dist to internal/frontend/dist (make
+// single-binary); other builds embed nothing, and the frontend is served by nginx.
+package frontend
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/fs"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	apperrors "github.com/nomdb/backend/internal/errors"
+)
+
+// assets are the embedded files, nil if the frontend is not embedded
+var assets fs.FS
+
+// Enabled reports whether the frontend is embedded
+func Enabled() bool {
+	return assets != nil
+}
+
+// Handler serves the embedded frontend. Paths without a file extension that match no
+// file get index.html, so client-side routes survive reloads; API paths and missing
+// files get a JSON 404. Hashed files under assets/ are cached for a year, everything
+// else is revalidated by ETag.
+func Handler() http.Handler {
+	return newHandler(assets)
+}
+
+func newHandler(files fs.FS) http.Handler {
+	etags := map[string]string{}
+	fs.WalkDir(files, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := fs.ReadFile(files, name)
+		if err != nil {
+			return err
+		}
+		sum := sha256.Sum256(data)
+		etags[name] = `"` + hex.EncodeToString(sum[:16]) + `"`
+		return nil
+	})
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api" || strings.HasPrefix(r.URL.Path, "/api/") {
+			apperrors.Error(w, "Not found", http.StatusNotFound)
+			return
+		}
+
+		name := strings.TrimPrefix(path.Clean(r.URL.Path), "/")
+		if _, ok := etags[name]; !ok {
+			if path.Ext(name) != "" {
+				apperrors.Error(w, "Not found", http.StatusNotFound)
+				return
+			}
+			name = "index.html"
+		}
+
+		file, err := files.Open(name)
+		if err != nil {
+			apperrors.Error(w, "Not found", http.StatusNotFound)
+			return
+		}
+		defer file.Close()
+		content, ok := file.(io.ReadSeeker)
+		if !ok {
+			apperrors.Error(w, "Not found", http.StatusNotFound)
+			return
+		}
+
+		if strings.HasPrefix(name, "assets/") {
+			w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+		} else {
+			w.Header().Set("Cache-Control", "no-cache")
+		}
+		w.Header().Set("ETag", etags[name])
+		// Embedded files have no modification time; ServeContent sets the type by extension
+		http.ServeContent(w, r, name, time.Time{}, content)
+	})
+}
@@ -0,0 +1,64 @@
+package frontend
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestHandler(t *testing.T) {
+	files := fstest.MapFS{
+		"index.html":          {Data: []byte("<!doctype html><div id=root></div>")},
+		"assets/index-abc.js": {Data: []byte("console.log(1)")},
+		"favicon.svg":         {Data: []byte("<svg/>")},
+	}
+	handler := newHandler(files)
+
+	tests := []struct {
+		path         string
+		status       int
+		body         string
+		cacheControl string
+	}{
+		{"/", http.StatusOK, "<!doctype html>", "no-cache"},
+		{"/restaurants/12", http.StatusOK, "<!doctype html>", "no-cache"},
+		{"/assets/index-abc.js", http.StatusOK, "console.log", "public, max-age=31536000, immutable"},
+		{"/favicon.svg", http.StatusOK, "<svg/>", "no-cache"},
+		{"/assets/missing.js", http.StatusNotFound, "NOT_FOUND", ""},
+		{"/api/unknown", http.StatusNotFound, "NOT_FOUND", ""},
+		{"/../index.html", http.StatusOK, "<!doctype html>", "no-cache"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if rec.Code != tt.status || !strings.Contains(rec.Body.String(), tt.body) {
+				t.Errorf("Expected %d with %q, got %d %s", tt.status, tt.body, rec.Code, rec.Body)
+			}
+			if got := rec.Header().Get("Cache-Control"); got != tt.cacheControl {
+				t.Errorf("Expected Cache-Control %q, got %q", tt.cacheControl, got)
+			}
+		})
+	}
+}
+
+func TestHandler_ETag(t *testing.T) {
+	handler := newHandler(fstest.MapFS{"index.html": {Data: []byte("<!doctype html>")}})
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	etag := rec.Header().Get("ETag")
+	if etag == "" || rec.Header().Get("Content-Type") != "text/html; charset=utf-8" {
+		t.Fatalf("Expected an HTML response with an ETag, got %v", rec.Header())
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/some/route", nil)
+	req.Header.Set("If-None-Match", etag)
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotModified {
+		t.Errorf("Expected 304, got %d", rec.Code)
+	}
+}
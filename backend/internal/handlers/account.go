@@ -0,0 +1,386 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/jackc/pgx/v5"
+	"github.com/nomdb/backend/internal/auth"
+	"github.com/nomdb/backend/internal/cache"
+	"github.com/nomdb/backend/internal/database"
+	apperrors "github.com/nomdb/backend/internal/errors"
+	"github.com/nomdb/backend/internal/logger"
+	"github.com/nomdb/backend/internal/models"
+	"github.com/nomdb/backend/internal/storage"
+)
+
+// Account deletion modes, mirroring the CHECK constraint on users.deletion_mode
+const (
+	deletionModeAnonymize = "anonymize"
+	deletionModePurge     = "purge"
+)
+
+// accountPurgeInterval is how often accounts past their grace period are removed
+const accountPurgeInterval = time.Hour
+
+var accountDeletionGracePeriod = 30 * 24 * time.Hour
+
+// InitAccountDeletion sets the grace period before deleted accounts are purged and
+// starts the purge job
+func InitAccountDeletion(gracePeriod time.Duration) {
+	accountDeletionGracePeriod = gracePeriod
+
+	logger.Info("✅ Account deletion grace period: %s", gracePeriod)
+	go func() {
+		ticker := time.NewTicker(accountPurgeInterval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if err := purgeDeletedAccounts(context.Background()); err != nil {
+				logger.Error("❌ Account purge failed: %v", err)
+			}
+		}
+	}()
+}
+
+// purgeDeletedAccounts permanently removes accounts whose grace period has ended
+func purgeDeletedAccounts(ctx context.Context) error {
+	rows, err := database.GetPool().Query(ctx,
+		`SELECT id, deletion_mode FROM users
+		WHERE deletion_requested_at IS NOT NULL AND deletion_requested_at < $1`,
+		time.Now().Add(-accountDeletionGracePeriod))
+	if err != nil {
+		return err
+	}
+
+	type pendingDeletion struct {
+		id   int
+		mode string
+	}
+	var pending []pendingDeletion
+	for rows.Next() {
+		var p pendingDeletion
+		if err := rows.Scan(&p.id, &p.mode); err != nil {
+			rows.Close()
+			return err
+		}
+		pending = append(pending, p)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, p := range pending {
+		if err := purgeAccount(ctx, p.id, p.mode); err != nil {
+			logger.Error("❌ Failed to purge account %d: %v", p.id, err)
+			continue
+		}
+		logger.Info("🗑️  Account %d purged (%s)", p.id, p.mode)
+	}
+	return nil
+}
+
+// purgeAccount deletes a user. In purge mode their ratings, photos and suggestions are
+// deleted too; otherwise they are kept and the foreign keys detach them from the user.
+func purgeAccount(ctx context.Context, userID int, mode string) error {
+	tx, err := database.GetPool().Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var photoFiles []string
+	if mode == deletionModePurge {
+		for _, query := range []string{
+			"DELETE FROM ratings WHERE user_id = $1",
+			"DELETE FROM restaurant_suggestions WHERE user_id = $1",
+			"DELETE FROM photo_uploads WHERE user_id = $1",
+		} {
+			if _, err := tx.Exec(ctx, query, userID); err != nil {
+				return err
+			}
+		}
+
+		rows, err := tx.Query(ctx, "DELETE FROM menu_photos WHERE uploaded_by_user_id = $1 RETURNING filename", userID)
+		if err != nil {
+			return err
+		}
+		for rows.Next() {
+			var filename string
+			if err := rows.Scan(&filename); err != nil {
+				rows.Close()
+				return err
+			}
+			photoFiles = append(photoFiles, filename)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+	} else {
+		// Uploaded file names often contain names or phone numbers
+		if _, err := tx.Exec(ctx,
+			"UPDATE menu_photos SET original_filename = NULL WHERE uploaded_by_user_id = $1", userID); err != nil {
+			return err
+		}
+	}
+
+	// Sessions, API keys and approvals cascade; other references are set to NULL
+	result, err := tx.Exec(ctx, "DELETE FROM users WHERE id = $1 AND deletion_requested_at IS NOT NULL", userID)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("account %d is no longer scheduled for deletion", userID)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+	// Purged ratings and photos change averages and covers in the list
+	cache.Invalidate(ctx, cache.KeyRestaurants)
+
+	store := storage.Get()
+	for _, filename := range photoFiles {
+		deletePhotoFiles(ctx, store, filename)
+	}
+	return nil
+}
+
+// @Summary Delete my account
+// @Description Deactivate the current account and schedule it for permanent deletion after a grace period. With mode "anonymize" (default) ratings, photos and suggestions are kept without any link to the account; with "purge" they are deleted. All sessions and API keys are revoked immediately.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body models.DeleteAccountRequest true "Password confirmation and deletion mode"
+// @Success 202 {object} models.DeleteAccountResponse
+// @Failure 400 {object} errors.ErrorResponse "Invalid request"
+// @Failure 401 {object} errors.ErrorResponse "Invalid password"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /auth/me/delete [post]
+func DeleteMyAccount(w http.ResponseWriter, r *http.Request) {
+	user, ok := requireSessionUser(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.DeleteAccountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apperrors.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.Mode == "" {
+		req.Mode = deletionModeAnonymize
+	}
+	if req.Mode != deletionModeAnonymize && req.Mode != deletionModePurge {
+		apperrors.Error(w, "Invalid mode. Must be one of: anonymize, purge", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+
+	// The context user is loaded without the password hash
+	account, err := getUserByID(ctx, user.ID)
+	if err != nil {
+		logger.Error("Failed to fetch user: %v", err)
+		apperrors.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if account.PasswordHash != nil {
+		valid, err := auth.VerifyPassword(req.Password, *account.PasswordHash)
+		if err != nil {
+			logger.Error("Failed to verify password: %v", err)
+			apperrors.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+		if !valid {
+			apperrors.Error(w, "Invalid password", http.StatusUnauthorized)
+			return
+		}
+	}
+
+	tx, err := database.GetPool().Begin(ctx)
+	if err != nil {
+		logger.Error("Failed to start transaction: %v", err)
+		apperrors.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	defer tx.Rollback(ctx)
+
+	var requestedAt time.Time
+	err = tx.QueryRow(ctx,
+		`UPDATE users SET is_active = false, deletion_requested_at = NOW(), deletion_mode = $1
+		WHERE id = $2
+		RETURNING deletion_requested_at`,
+		req.Mode, user.ID).Scan(&requestedAt)
+	if err != nil {
+		logger.Error("Failed to schedule account deletion: %v", err)
+		apperrors.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	for _, query := range []string{
+		"DELETE FROM sessions WHERE user_id = $1",
+		"DELETE FROM api_keys WHERE user_id = $1",
+		"DELETE FROM password_reset_tokens WHERE user_id = $1",
+	} {
+		if _, err := tx.Exec(ctx, query, user.ID); err != nil {
+			logger.Error("Failed to revoke credentials: %v", err)
+			apperrors.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		logger.Error("Failed to commit account deletion: %v", err)
+		apperrors.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	logger.Info("🗑️  Account %d scheduled for deletion (%s)", user.ID, req.Mode)
+	recordAudit(ctx, r, auditEvent{
+		Event:      AuditAccountDeleted,
+		TargetType: "user",
+		TargetID:   strconv.Itoa(user.ID),
+		Details:    map[string]any{"mode": req.Mode},
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	if err := json.NewEncoder(w).Encode(models.DeleteAccountResponse{
+		Mode:        req.Mode,
+		ScheduledAt: requestedAt.Add(accountDeletionGracePeriod),
+	}); err != nil {
+		logger.Error("Failed to encode response: %v", err)
+	}
+}
+
+// queryExportRows returns query results as column name to value maps
+func queryExportRows(ctx context.Context, query string, args ...any) ([]map[string]any, error) {
+	rows, err := database.GetPool().Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []map[string]any{}
+	fields := rows.FieldDescriptions()
+	for rows.Next() {
+		values, err := rows.Values()
+		if err != nil {
+			return nil, err
+		}
+		row := make(map[string]any, len(fields))
+		for i, field := range fields {
+			row[field.Name] = values[i]
+		}
+		result = append(result, row)
+	}
+	return result, rows.Err()
+}
+
+// @Summary Export my data
+// @Description Download a JSON archive of the current account and everything it has contributed: ratings, photos, suggestions, created restaurants, sessions, API keys (without secrets) and followed users.
+// @Tags Auth
+// @Produce json
+// @Success 200 {object} models.AccountExport
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /auth/me/export [get]
+func ExportMyAccount(w http.ResponseWriter, r *http.Request) {
+	user, ok := requireSessionUser(w, r)
+	if !ok {
+		return
+	}
+
+	ctx := r.Context()
+	account, err := getUserByID(ctx, user.ID)
+	if err != nil {
+		logger.Error("Failed to fetch user: %v", err)
+		apperrors.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	export := models.AccountExport{ExportedAt: time.Now(), User: *account}
+	for _, section := range []struct {
+		target *[]map[string]any
+		query  string
+	}{
+		{&export.Ratings, "SELECT * FROM ratings WHERE user_id = $1 ORDER BY created_at"},
+		{&export.Photos, `SELECT id, restaurant_id, filename, original_filename, caption, file_size, mime_type, photo_type, position, is_cover, status, moderation_status, created_at, updated_at
+			FROM menu_photos WHERE uploaded_by_user_id = $1 ORDER BY created_at`},
+		{&export.Suggestions, "SELECT * FROM restaurant_suggestions WHERE user_id = $1 ORDER BY created_at"},
+		{&export.SuggestionEvents, "SELECT * FROM suggestion_events WHERE user_id = $1 ORDER BY created_at"},
+		{&export.Restaurants, "SELECT id, name, address, created_at FROM restaurants WHERE created_by = $1 ORDER BY created_at"},
+		{&export.Sessions, "SELECT id, created_at, last_used_at, expires_at, ip_address, user_agent FROM sessions WHERE user_id = $1 ORDER BY created_at"},
+		{&export.APIKeys, "SELECT id, key_prefix, name, scopes, rate_limit, last_used_at, expires_at, is_active, created_at FROM api_keys WHERE user_id = $1 ORDER BY created_at"},
+		{&export.Following, "SELECT followee_id, created_at FROM user_follows WHERE follower_id = $1 ORDER BY created_at"},
+		{&export.Badges, "SELECT badge, awarded_at FROM user_badges WHERE user_id = $1 ORDER BY awarded_at"},
+	} {
+		rows, err := queryExportRows(ctx, section.query, user.ID)
+		if err != nil {
+			logger.Error("Failed to export account %d: %v", user.ID, err)
+			apperrors.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+		*section.target = rows
+	}
+
+	logger.Info("📦 Account %d exported its data", user.ID)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="nomdb-export-%s.json"`, archiveName(account.Username, strconv.Itoa(account.ID))))
+	w.Header().Set("Cache-Control", "no-store")
+	if err := json.NewEncoder(w).Encode(export); err != nil {
+		logger.Error("Failed to encode response: %v", err)
+	}
+}
+
+// @Summary Restore a deleted account
+// @Description Cancel a pending account deletion during the grace period and reactivate the account. Admin only.
+// @Tags Auth
+// @Param id path int true "User ID"
+// @Success 204 "Account restored"
+// @Failure 400 {object} errors.ErrorResponse "Invalid user ID"
+// @Failure 404 {object} errors.ErrorResponse "No pending deletion for this user"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /admin/users/{id}/restore [post]
+func RestoreUser(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	userID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		apperrors.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	var id int
+	err = database.GetPool().QueryRow(r.Context(),
+		`UPDATE users SET is_active = true, deletion_requested_at = NULL, deletion_mode = NULL
+		WHERE id = $1 AND deletion_requested_at IS NOT NULL
+		RETURNING id`, userID).Scan(&id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			apperrors.Error(w, "No pending deletion for this user", http.StatusNotFound)
+			return
+		}
+		logger.Error("Failed to restore user: %v", err)
+		apperrors.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	if admin, ok := GetUserFromContext(r); ok {
+		logger.Info("♻️  Account %d restored by %s", userID, admin.Username)
+	}
+	recordAdminAction(r.Context(), r, "restore_user", "user", userID, nil)
+	w.WriteHeader(http.StatusNoContent)
+}
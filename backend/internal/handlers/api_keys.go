@@ -0,0 +1,234 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/nomdb/backend/internal/auth"
+	"github.com/nomdb/backend/internal/database"
+	apperrors "github.com/nomdb/backend/internal/errors"
+	"github.com/nomdb/backend/internal/logger"
+	"github.com/nomdb/backend/internal/middleware"
+	"github.com/nomdb/backend/internal/models"
+)
+
+const apiKeyColumns = `id, user_id, key_prefix, name, scopes, rate_limit, last_used_at, expires_at, is_active, created_at`
+
+func scanAPIKey(row interface{ Scan(...any) error }, key *models.APIKey) error {
+	return row.Scan(&key.ID, &key.UserID, &key.KeyPrefix, &key.Name, &key.Scopes, &key.RateLimit,
+		&key.LastUsedAt, &key.ExpiresAt, &key.IsActive, &key.CreatedAt)
+}
+
+// requireSessionUser returns the user of a request authenticated with a login session.
+// API keys cannot manage API keys, so a leaked key cannot mint new ones.
+func requireSessionUser(w http.ResponseWriter, r *http.Request) (*models.User, bool) {
+	if _, ok := middleware.GetAPIKeyFromRequest(r); ok {
+		apperrors.Error(w, "API keys cannot be used to manage API keys", http.StatusForbidden)
+		return nil, false
+	}
+	user, ok := GetUserFromContext(r)
+	if !ok {
+		apperrors.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return nil, false
+	}
+	return user, true
+}
+
+// @Summary Create an API key
+// @Description Generate an API key for programmatic access, sent in the X-API-Key header. The key is only returned in this response; only its hash is stored. Keys with the write scope may also read; the admin scope can only be granted by admins.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body models.CreateAPIKeyRequest true "Key name, scopes, expiry and rate limit"
+// @Success 201 {object} models.CreateAPIKeyResponse
+// @Failure 400 {object} errors.ErrorResponse "Invalid request"
+// @Failure 403 {object} errors.ErrorResponse "Scope not allowed"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /keys [post]
+func CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	user, ok := requireSessionUser(w, r)
+	if !ok {
+		return
+	}
+	createAPIKey(w, r, user)
+}
+
+// createAPIKey creates an API key owned by owner from the request body. The admin scope
+// can only be granted to keys of admins.
+func createAPIKey(w http.ResponseWriter, r *http.Request, owner *models.User) {
+	var req models.CreateAPIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apperrors.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.Name == "" || len(req.Name) > 255 {
+		apperrors.Error(w, "Name is required (max 255 characters)", http.StatusBadRequest)
+		return
+	}
+
+	if len(req.Scopes) == 0 {
+		req.Scopes = []string{auth.ScopeRead}
+	}
+	scopes := make([]string, 0, len(req.Scopes))
+	seen := make(map[string]bool)
+	for _, scope := range req.Scopes {
+		if !auth.IsValidScope(scope) {
+			apperrors.Error(w, "Invalid scope. Must be one of: read, write, admin", http.StatusBadRequest)
+			return
+		}
+		if scope == auth.ScopeAdmin && !owner.IsAdmin {
+			apperrors.Error(w, "Only admins can create keys with the admin scope", http.StatusForbidden)
+			return
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			scopes = append(scopes, scope)
+		}
+	}
+
+	var expiresAt *time.Time
+	if req.ExpiresInDays != nil {
+		if *req.ExpiresInDays <= 0 {
+			apperrors.Error(w, "expires_in_days must be positive", http.StatusBadRequest)
+			return
+		}
+		t := time.Now().AddDate(0, 0, *req.ExpiresInDays)
+		expiresAt = &t
+	}
+
+	if req.RateLimit != nil && *req.RateLimit <= 0 {
+		apperrors.Error(w, "rate_limit must be positive", http.StatusBadRequest)
+		return
+	}
+
+	key, prefix, hash, err := auth.GenerateAPIKey()
+	if err != nil {
+		logger.Error("Failed to generate API key: %v", err)
+		apperrors.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	var apiKey models.APIKey
+	err = scanAPIKey(database.GetPool().QueryRow(r.Context(),
+		`INSERT INTO api_keys (user_id, key_hash, key_prefix, name, scopes, rate_limit, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING `+apiKeyColumns,
+		owner.ID, hash, prefix, req.Name, scopes, req.RateLimit, expiresAt), &apiKey)
+	if err != nil {
+		logger.Error("Failed to create API key: %v", err)
+		apperrors.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	logger.Info("🔑 API key %d (%s) created for %s with scopes %v", apiKey.ID, prefix, owner.Username, scopes)
+	recordAudit(r.Context(), r, auditEvent{
+		Event:      AuditAPIKeyCreated,
+		TargetType: "api_key",
+		TargetID:   strconv.Itoa(apiKey.ID),
+		Details:    map[string]any{"prefix": prefix, "scopes": scopes, "owner_user_id": owner.ID},
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(models.CreateAPIKeyResponse{Key: key, APIKey: apiKey}); err != nil {
+		logger.Error("Failed to encode response: %v", err)
+	}
+}
+
+// @Summary List API keys
+// @Description List the current user's API keys. The keys themselves are never returned, only their prefix.
+// @Tags Auth
+// @Produce json
+// @Success 200 {array} models.APIKey
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /keys [get]
+func ListAPIKeys(w http.ResponseWriter, r *http.Request) {
+	user, ok := requireSessionUser(w, r)
+	if !ok {
+		return
+	}
+	listAPIKeys(w, r, user.ID)
+}
+
+// listAPIKeys responds with the API keys owned by a user
+func listAPIKeys(w http.ResponseWriter, r *http.Request, ownerID int) {
+	rows, err := database.GetPool().Query(r.Context(),
+		`SELECT `+apiKeyColumns+` FROM api_keys WHERE user_id = $1 ORDER BY created_at DESC`, ownerID)
+	if err != nil {
+		logger.Error("Failed to list API keys: %v", err)
+		apperrors.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	defer rows.Close()
+
+	keys := []models.APIKey{}
+	for rows.Next() {
+		var key models.APIKey
+		if err := scanAPIKey(rows, &key); err != nil {
+			logger.Error("Failed to scan API key: %v", err)
+			apperrors.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+		keys = append(keys, key)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(keys); err != nil {
+		logger.Error("Failed to encode response: %v", err)
+	}
+}
+
+// @Summary Revoke an API key
+// @Description Permanently delete one of the current user's API keys
+// @Tags Auth
+// @Param id path int true "API key ID"
+// @Success 204 "API key revoked"
+// @Failure 400 {object} errors.ErrorResponse "Invalid API key ID"
+// @Failure 404 {object} errors.ErrorResponse "API key not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /keys/{id} [delete]
+func DeleteAPIKey(w http.ResponseWriter, r *http.Request) {
+	user, ok := requireSessionUser(w, r)
+	if !ok {
+		return
+	}
+
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		apperrors.Error(w, "Invalid API key ID", http.StatusBadRequest)
+		return
+	}
+	deleteAPIKey(w, r, user, id)
+}
+
+// deleteAPIKey deletes an API key of owner
+func deleteAPIKey(w http.ResponseWriter, r *http.Request, owner *models.User, id int) {
+	result, err := database.GetPool().Exec(r.Context(),
+		"DELETE FROM api_keys WHERE id = $1 AND user_id = $2", id, owner.ID)
+	if err != nil {
+		logger.Error("Failed to delete API key: %v", err)
+		apperrors.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if result.RowsAffected() == 0 {
+		apperrors.Error(w, "API key not found", http.StatusNotFound)
+		return
+	}
+
+	logger.Info("🔑 API key %d of %s revoked", id, owner.Username)
+	recordAudit(r.Context(), r, auditEvent{
+		Event:      AuditAPIKeyDeleted,
+		TargetType: "api_key",
+		TargetID:   strconv.Itoa(id),
+		Details:    map[string]any{"owner_user_id": owner.ID},
+	})
+	w.WriteHeader(http.StatusNoContent)
+}
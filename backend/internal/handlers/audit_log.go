@@ -0,0 +1,250 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/nomdb/backend/internal/database"
+	apperrors "github.com/nomdb/backend/internal/errors"
+	"github.com/nomdb/backend/internal/logger"
+	"github.com/nomdb/backend/internal/middleware"
+	"github.com/nomdb/backend/internal/models"
+)
+
+// Audit log events
+const (
+	AuditLogin          = "login"
+	AuditLoginFailed    = "login_failed"
+	AuditLogout         = "logout"
+	AuditTokenRefresh   = "token_refresh"
+	AuditRegister       = "register"
+	AuditPasswordReset  = "password_reset"
+	AuditRoleGranted    = "role_granted"
+	AuditRoleRevoked    = "role_revoked"
+	AuditAccountDeleted = "account_deleted"
+	AuditAPIKeyCreated  = "api_key_created"
+	AuditAPIKeyDeleted  = "api_key_deleted"
+	AuditAdminAction    = "admin_action"
+)
+
+// auditLogMaxDetailBytes drops oversized details rather than bloating the table
+const auditLogMaxDetailBytes = 4096
+
+// auditEvent is an entry to record in the audit log
+type auditEvent struct {
+	Event      string
+	ActorID    *int           // Defaults to the authenticated user of the request
+	TargetType string         // e.g. user, invite, restaurant
+	TargetID   string         // ID of the target within its type
+	Details    map[string]any // Event specific context, never secrets
+}
+
+// recordAudit appends an event to the audit log with the client IP and user agent of r,
+// which may be nil for background jobs. Failures are logged but never fail the
+// surrounding request.
+func recordAudit(ctx context.Context, r *http.Request, e auditEvent) {
+	var ipAddress, userAgent *string
+	if r != nil {
+		if e.ActorID == nil {
+			if user, ok := GetUserFromContext(r); ok {
+				e.ActorID = &user.ID
+			}
+		}
+		// Requests of scripts and service accounts name the key they used
+		if apiKey, ok := middleware.GetAPIKeyFromRequest(r); ok {
+			if e.Details == nil {
+				e.Details = map[string]any{}
+			}
+			e.Details["api_key_id"] = apiKey.ID
+		}
+		ip := middleware.ClientIP(r)
+		ipAddress = &ip
+		if ua := r.UserAgent(); ua != "" {
+			userAgent = &ua
+		}
+	}
+
+	var details []byte
+	if len(e.Details) > 0 {
+		var err error
+		details, err = json.Marshal(e.Details)
+		if err != nil || len(details) > auditLogMaxDetailBytes {
+			details = nil
+		}
+	}
+
+	// The action already happened, so record it even if the client has gone away
+	_, err := database.GetPool().Exec(context.WithoutCancel(ctx),
+		`INSERT INTO audit_log (event, actor_user_id, target_type, target_id, ip_address, user_agent, details)
+		VALUES ($1, $2, NULLIF($3, ''), NULLIF($4, ''), $5, $6, $7)`,
+		e.Event, e.ActorID, e.TargetType, e.TargetID, ipAddress, userAgent, details)
+	if err != nil {
+		logger.Warn("Failed to record audit event %s: %v", e.Event, err)
+	}
+}
+
+// recordAdminAction records an action performed by an admin on a target
+func recordAdminAction(ctx context.Context, r *http.Request, action, targetType string, targetID int, details map[string]any) {
+	if details == nil {
+		details = map[string]any{}
+	}
+	details["action"] = action
+	recordAudit(ctx, r, auditEvent{
+		Event:      AuditAdminAction,
+		TargetType: targetType,
+		TargetID:   strconv.Itoa(targetID),
+		Details:    details,
+	})
+}
+
+// @Summary Get the audit log
+// @Description Get security-sensitive events (logins, failed logins, token refreshes, password changes, role grants, admin actions), newest first. The cursor of the next page, if any, is returned in X-Next-Cursor. Admin only.
+// @Tags Auth
+// @Produce json
+// @Param event query string false "Comma-separated events, e.g. login_failed,role_granted"
+// @Param user_id query int false "Acting user ID"
+// @Param target_type query string false "Target type, e.g. user"
+// @Param target_id query string false "Target ID"
+// @Param ip query string false "Client IP address"
+// @Param from query string false "Only events at or after this time (RFC 3339)"
+// @Param to query string false "Only events before this time (RFC 3339)"
+// @Param limit query int false "Number of entries per page (default: 20, max: 100)"
+// @Param cursor query string false "Pagination cursor from X-Next-Cursor"
+// @Success 200 {array} models.AuditLogEntry
+// @Header 200 {string} X-Next-Cursor "Cursor of the next page"
+// @Failure 400 {object} errors.ErrorResponse "Invalid filter or cursor"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /admin/audit-log [get]
+func GetAuditLog(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	pagination := ParsePaginationParams(r)
+
+	q := sq.Select("a.id", "a.event", "a.actor_user_id", "u.username", "a.target_type", "a.target_id",
+		"a.ip_address", "a.user_agent", "a.details", "a.created_at").
+		From("audit_log a").
+		LeftJoin("users u ON a.actor_user_id = u.id").
+		PlaceholderFormat(sq.Dollar)
+
+	if events := query.Get("event"); events != "" {
+		q = q.Where("a.event = ANY(?)", splitAuditEvents(events))
+	}
+	if userID := query.Get("user_id"); userID != "" {
+		id, err := strconv.Atoi(userID)
+		if err != nil {
+			apperrors.Error(w, "Invalid user_id", http.StatusBadRequest)
+			return
+		}
+		q = q.Where(sq.Eq{"a.actor_user_id": id})
+	}
+	if targetType := query.Get("target_type"); targetType != "" {
+		q = q.Where(sq.Eq{"a.target_type": targetType})
+	}
+	if targetID := query.Get("target_id"); targetID != "" {
+		q = q.Where(sq.Eq{"a.target_id": targetID})
+	}
+	if ip := query.Get("ip"); ip != "" {
+		q = q.Where(sq.Eq{"a.ip_address": ip})
+	}
+	for _, bound := range []struct {
+		param string
+		where func(time.Time) sq.Sqlizer
+	}{
+		{"from", func(t time.Time) sq.Sqlizer { return sq.GtOrEq{"a.created_at": t} }},
+		{"to", func(t time.Time) sq.Sqlizer { return sq.Lt{"a.created_at": t} }},
+	} {
+		value := query.Get(bound.param)
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			apperrors.Error(w, "Invalid "+bound.param+" time, expected RFC 3339", http.StatusBadRequest)
+			return
+		}
+		q = q.Where(bound.where(t))
+	}
+	if pagination.Cursor != "" {
+		beforeID, err := DecodeCursor(pagination.Cursor)
+		if err != nil {
+			apperrors.Error(w, "Invalid cursor", http.StatusBadRequest)
+			return
+		}
+		q = q.Where(sq.Lt{"a.id": beforeID})
+	}
+
+	sql, args, err := q.OrderBy("a.id DESC").Limit(uint64(pagination.Limit + 1)).ToSql()
+	if err != nil {
+		apperrors.Internal(w, err)
+		return
+	}
+
+	rows, err := database.GetPool().Query(r.Context(), sql, args...)
+	if err != nil {
+		logger.Error("Failed to query audit log: %v", err)
+		apperrors.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	defer rows.Close()
+
+	entries := []models.AuditLogEntry{}
+	for rows.Next() {
+		var e models.AuditLogEntry
+		if err := rows.Scan(&e.ID, &e.Event, &e.ActorUserID, &e.ActorUsername, &e.TargetType, &e.TargetID,
+			&e.IPAddress, &e.UserAgent, &e.Details, &e.CreatedAt); err != nil {
+			logger.Error("Failed to scan audit log entry: %v", err)
+			apperrors.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+		entries = append(entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		logger.Error("Failed to read audit log: %v", err)
+		apperrors.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	if len(entries) > pagination.Limit {
+		entries = entries[:pagination.Limit]
+		w.Header().Set("X-Next-Cursor", EncodeCursor(int(entries[len(entries)-1].ID)))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(entries); err != nil {
+		logger.Error("Failed to encode response: %v", err)
+	}
+}
+
+// splitAuditEvents splits a comma-separated event filter
+func splitAuditEvents(value string) []string {
+	var events []string
+	for _, event := range strings.Split(value, ",") {
+		if event = strings.TrimSpace(event); event != "" {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+// recordLoginFailure records a failed login with the given method (password or a login
+// provider). userID is nil when no account matched.
+func recordLoginFailure(ctx context.Context, r *http.Request, userID *int, method, email, reason string) {
+	email = strings.ToLower(strings.TrimSpace(email))
+	if len(email) > 254 {
+		email = email[:254]
+	}
+	e := auditEvent{
+		Event:   AuditLoginFailed,
+		Details: map[string]any{"method": method, "email": email, "reason": reason},
+	}
+	if userID != nil {
+		e.TargetType = "user"
+		e.TargetID = strconv.Itoa(*userID)
+	}
+	recordAudit(ctx, r, e)
+}
@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestSplitAuditEvents(t *testing.T) {
+	events := splitAuditEvents(" login_failed, ,role_granted,")
+	expected := []string{"login_failed", "role_granted"}
+	if !reflect.DeepEqual(events, expected) {
+		t.Errorf("Expected %v, got %v", expected, events)
+	}
+}
+
+func TestGetAuditLogInvalidFilters(t *testing.T) {
+	tests := []string{
+		"/api/admin/audit-log?user_id=abc",
+		"/api/admin/audit-log?from=yesterday",
+		"/api/admin/audit-log?to=2024-13-01",
+		"/api/admin/audit-log?cursor=not-base64!",
+	}
+
+	for _, target := range tests {
+		rec := httptest.NewRecorder()
+		GetAuditLog(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", target, rec.Code)
+		}
+	}
+}
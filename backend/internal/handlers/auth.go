@@ -2,27 +2,39 @@ package handlers
 
 import (
 	"context"
-	"database/sql"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"net/http"
-	"os"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/jackc/pgx/v5"
 	"github.com/nomdb/backend/internal/auth"
 	"github.com/nomdb/backend/internal/database"
+	apperrors "github.com/nomdb/backend/internal/errors"
 	"github.com/nomdb/backend/internal/logger"
+	"github.com/nomdb/backend/internal/middleware"
 	"github.com/nomdb/backend/internal/models"
+	"github.com/nomdb/backend/internal/services"
 )
 
-// InitAuthService initializes the JWT service and returns it
-func InitAuthService() *auth.JWTService {
-	secretKey := os.Getenv("JWT_SECRET_KEY")
+// emailValidator checks the email addresses of new local accounts
+var emailValidator = services.NewEmailValidator(services.EmailValidationConfig{BlockDisposable: true})
+
+// InitEmailValidation replaces the validator for new account emails
+func InitEmailValidation(validator *services.EmailValidator) {
+	emailValidator = validator
+	logger.Info("📧 Email validation: %d blocked domains", validator.BlockedDomainCount())
+}
+
+// InitAuthService initializes the JWT service with the given secret and token lifetimes
+// and returns it
+func InitAuthService(secretKey, authMode string, accessTokenDuration, refreshTokenDuration, rememberMeDuration time.Duration) *auth.JWTService {
 	if secretKey == "" {
-		authMode := os.Getenv("AUTH_MODE")
 		if authMode == "local" || authMode == "both" || authMode == "" {
 			logger.Fatal("JWT_SECRET_KEY environment variable is required for local/both auth mode")
 		}
@@ -30,12 +42,9 @@ func InitAuthService() *auth.JWTService {
 		return nil
 	}
 
-	accessTokenDuration := 15 * time.Minute
-	refreshTokenDuration := 7 * 24 * time.Hour
-
-	jwtService := auth.NewJWTService(secretKey, accessTokenDuration, refreshTokenDuration)
+	jwtService := auth.NewJWTService(secretKey, accessTokenDuration, refreshTokenDuration, rememberMeDuration)
 	auth.SetGlobalJWTService(jwtService)
-	logger.Info("🔐 JWT service initialized (access: %v, refresh: %v)", accessTokenDuration, refreshTokenDuration)
+	logger.Info("🔐 JWT service initialized (access: %v, refresh: %v, remember me: %v)", accessTokenDuration, refreshTokenDuration, rememberMeDuration)
 	return jwtService
 }
 
@@ -44,38 +53,53 @@ func getJWTService() *auth.JWTService {
 }
 
 // @Summary Register a new user
-// @Description Create a new user account with email and password
+// @Description Create a new user account with email and password. Requires an invite code when REGISTRATION_MODE=invite.
 // @Tags Auth
 // @Accept json
 // @Produce json
 // @Param request body models.RegisterRequest true "Registration details"
 // @Success 201 {object} models.LoginResponse
-// @Failure 400 {string} string "Invalid request"
-// @Failure 409 {string} string "User already exists"
-// @Failure 500 {string} string "Internal server error"
+// @Failure 400 {object} errors.ErrorResponse "Invalid request"
+// @Failure 403 {object} errors.ErrorResponse "Registration closed or invalid invite"
+// @Failure 409 {object} errors.ErrorResponse "User already exists"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
 // @Router /auth/register [post]
 func Register(w http.ResponseWriter, r *http.Request) {
 	var req models.RegisterRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		apperrors.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if settingValue(settingRegistrationMode) == RegistrationClosed {
+		apperrors.Error(w, "Registration is closed", http.StatusForbidden)
 		return
 	}
 
 	// Validate input
 	if req.Email == "" || req.Username == "" || req.Password == "" {
-		http.Error(w, "Email, username, and password are required", http.StatusBadRequest)
+		apperrors.Error(w, "Email, username, and password are required", http.StatusBadRequest)
 		return
 	}
 
-	// Basic email validation
-	if !isValidEmail(req.Email) {
-		http.Error(w, "Invalid email format", http.StatusBadRequest)
+	// Validate and normalize the email
+	email, err := emailValidator.Validate(r.Context(), req.Email)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrDisposableEmail):
+			apperrors.Error(w, "Disposable email addresses are not allowed", http.StatusBadRequest)
+		case errors.Is(err, services.ErrEmailDomainNoMail):
+			apperrors.Error(w, "Email domain does not accept mail", http.StatusBadRequest)
+		default:
+			apperrors.Error(w, "Invalid email format", http.StatusBadRequest)
+		}
 		return
 	}
+	req.Email = email
 
 	// Password strength check
-	if len(req.Password) < 8 {
-		http.Error(w, "Password must be at least 8 characters", http.StatusBadRequest)
+	if len(req.Password) < minPasswordLength {
+		apperrors.Error(w, fmt.Sprintf("Password must be at least %d characters", minPasswordLength), http.StatusBadRequest)
 		return
 	}
 
@@ -83,26 +107,67 @@ func Register(w http.ResponseWriter, r *http.Request) {
 	passwordHash, err := auth.HashPassword(req.Password, nil)
 	if err != nil {
 		logger.Error("Failed to hash password: %v", err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		apperrors.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	// Emails are unique regardless of case; older accounts may not be stored lowercased
+	ctx := r.Context()
+	var emailTaken bool
+	if err := database.GetPool().QueryRow(ctx,
+		"SELECT EXISTS(SELECT 1 FROM users WHERE lower(email) = $1)", req.Email).Scan(&emailTaken); err != nil {
+		logger.Error("Failed to check email: %v", err)
+		apperrors.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if emailTaken {
+		apperrors.Error(w, "User with this email or username already exists", http.StatusConflict)
+		return
+	}
+
+	// Create user, redeeming the invite in the same transaction
+	tx, err := database.GetPool().Begin(ctx)
+	if err != nil {
+		logger.Error("Failed to begin transaction: %v", err)
+		apperrors.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	defer tx.Rollback(ctx)
+
+	var inviteHash string
+	if req.InviteCode != "" {
+		inviteHash = hashInviteCode(req.InviteCode)
+	}
+	inviteID, err := admitNewUser(ctx, tx, inviteHash)
+	if err != nil {
+		if errors.Is(err, errInvalidInvite) || errors.Is(err, errRegistrationClosed) {
+			apperrors.Error(w, "A valid invite code is required to register", http.StatusForbidden)
+			return
+		}
+		logger.Error("Failed to redeem invite: %v", err)
+		apperrors.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
 
-	// Create user
-	ctx := context.Background()
 	var userID int
-	err = database.GetPool().QueryRow(ctx,
-		`INSERT INTO users (email, username, password_hash, provider, full_name, email_verified)
-		VALUES ($1, $2, $3, 'local', $4, false)
+	err = tx.QueryRow(ctx,
+		`INSERT INTO users (email, username, password_hash, provider, full_name, email_verified, invite_id)
+		VALUES ($1, $2, $3, 'local', $4, false, $5)
 		RETURNING id`,
-		req.Email, req.Username, passwordHash, req.FullName).Scan(&userID)
+		req.Email, req.Username, passwordHash, req.FullName, inviteID).Scan(&userID)
 
 	if err != nil {
 		if isDuplicateKeyError(err) {
-			http.Error(w, "User with this email or username already exists", http.StatusConflict)
+			apperrors.Error(w, "User with this email or username already exists", http.StatusConflict)
 			return
 		}
 		logger.Error("Failed to create user: %v", err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		apperrors.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if err := tx.Commit(ctx); err != nil {
+		logger.Error("Failed to commit registration: %v", err)
+		apperrors.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
 
@@ -110,24 +175,35 @@ func Register(w http.ResponseWriter, r *http.Request) {
 	user, err := getUserByID(ctx, userID)
 	if err != nil {
 		logger.Error("Failed to fetch created user: %v", err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		apperrors.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
 
 	// Generate tokens
 	jwtService := getJWTService()
 	if jwtService == nil {
-		http.Error(w, "Authentication service not available", http.StatusInternalServerError)
+		apperrors.Error(w, "Authentication service not available", http.StatusInternalServerError)
 		return
 	}
-	response, err := generateLoginResponseWithService(ctx, user, r, jwtService)
+	response, err := generateLoginResponseWithService(ctx, user, r, jwtService, false)
 	if err != nil {
 		logger.Error("Failed to generate tokens: %v", err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		apperrors.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
 
 	logger.Info("User registered: %s (ID: %d)", user.Email, user.ID)
+	details := map[string]any{"method": "password"}
+	if inviteID != nil {
+		details["invite_id"] = *inviteID
+	}
+	recordAudit(ctx, r, auditEvent{
+		Event:      AuditRegister,
+		ActorID:    &user.ID,
+		TargetType: "user",
+		TargetID:   strconv.Itoa(user.ID),
+		Details:    details,
+	})
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
@@ -135,6 +211,7 @@ func Register(w http.ResponseWriter, r *http.Request) {
 		logger.Error("Failed to encode response: %v", err)
 	}
 }
+
 // @Summary Login
 // @Description Login with email and password
 // @Tags Auth
@@ -142,60 +219,98 @@ func Register(w http.ResponseWriter, r *http.Request) {
 // @Produce json
 // @Param request body models.LoginRequest true "Login credentials"
 // @Success 200 {object} models.LoginResponse
-// @Failure 400 {string} string "Invalid request"
-// @Failure 401 {string} string "Invalid credentials"
-// @Failure 500 {string} string "Internal server error"
+// @Failure 400 {object} errors.ErrorResponse "Invalid request"
+// @Failure 401 {object} errors.ErrorResponse "Invalid credentials"
+// @Failure 429 {object} errors.ErrorResponse "Too many failed attempts, account or client temporarily locked"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
 // @Router /auth/login [post]
 func Login(w http.ResponseWriter, r *http.Request) {
 	var req models.LoginRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		apperrors.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
 
 	if req.Email == "" || req.Password == "" {
-		http.Error(w, "Email and password are required", http.StatusBadRequest)
+		apperrors.Error(w, "Email and password are required", http.StatusBadRequest)
 		return
 	}
 
-	ctx := context.Background()
+	ctx := r.Context()
+
+	// Brute-force protection: refuse early while this client is locked out
+	ip := middleware.ClientIP(r)
+	if remaining := ipLoginFailures.lockedFor(ip, time.Now()); remaining > 0 {
+		recordLoginFailure(ctx, r, nil, "password", req.Email, "ip_locked")
+		respondLoginLocked(w, remaining)
+		return
+	}
 
 	// Fetch user
 	user, err := getUserByEmail(ctx, req.Email)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			http.Error(w, "Invalid credentials", http.StatusUnauthorized)
+		if errors.Is(err, pgx.ErrNoRows) {
+			ipLoginFailures.recordFailure(ip, time.Now())
+			recordLoginFailure(ctx, r, nil, "password", req.Email, "unknown_email")
+			apperrors.Error(w, "Invalid credentials", http.StatusUnauthorized)
 			return
 		}
 		logger.Error("Failed to fetch user: %v", err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		apperrors.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
 
 	// Check if user is active
 	if !user.IsActive {
-		http.Error(w, "Account is disabled", http.StatusUnauthorized)
+		recordLoginFailure(ctx, r, &user.ID, "password", req.Email, "account_disabled")
+		apperrors.Error(w, "Account is disabled", http.StatusUnauthorized)
+		return
+	}
+
+	// Service accounts authenticate with API keys only
+	if user.IsServiceAccount() {
+		recordLoginFailure(ctx, r, &user.ID, "password", req.Email, "service_account")
+		apperrors.Error(w, "Invalid credentials", http.StatusUnauthorized)
 		return
 	}
 
 	// Verify password
 	if user.PasswordHash == nil {
-		http.Error(w, "Invalid credentials - OAuth user", http.StatusUnauthorized)
+		recordLoginFailure(ctx, r, &user.ID, "password", req.Email, "no_password")
+		apperrors.Error(w, "Invalid credentials - OAuth user", http.StatusUnauthorized)
+		return
+	}
+
+	remaining, err := accountLockedFor(ctx, user.ID)
+	if err != nil {
+		logger.Error("Failed to check account lockout: %v", err)
+		apperrors.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if remaining > 0 {
+		recordLoginFailure(ctx, r, &user.ID, "password", req.Email, "account_locked")
+		respondLoginLocked(w, remaining)
 		return
 	}
 
 	valid, err := auth.VerifyPassword(req.Password, *user.PasswordHash)
 	if err != nil {
 		logger.Error("Failed to verify password: %v", err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		apperrors.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
 
 	if !valid {
-		http.Error(w, "Invalid credentials", http.StatusUnauthorized)
+		ipLoginFailures.recordFailure(ip, time.Now())
+		recordAccountLoginFailure(ctx, user.ID)
+		recordLoginFailure(ctx, r, &user.ID, "password", req.Email, "invalid_password")
+		apperrors.Error(w, "Invalid credentials", http.StatusUnauthorized)
 		return
 	}
 
+	ipLoginFailures.reset(ip)
+	resetAccountLoginFailures(ctx, user.ID)
+
 	// Update last login
 	_, err = database.GetPool().Exec(ctx, "UPDATE users SET last_login_at = $1 WHERE id = $2", time.Now(), user.ID)
 	if err != nil {
@@ -205,17 +320,24 @@ func Login(w http.ResponseWriter, r *http.Request) {
 	// Generate tokens
 	jwtService := getJWTService()
 	if jwtService == nil {
-		http.Error(w, "Authentication service not available", http.StatusInternalServerError)
+		apperrors.Error(w, "Authentication service not available", http.StatusInternalServerError)
 		return
 	}
-	response, err := generateLoginResponseWithService(ctx, user, r, jwtService)
+	response, err := generateLoginResponseWithService(ctx, user, r, jwtService, req.RememberMe)
 	if err != nil {
 		logger.Error("Failed to generate tokens: %v", err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		apperrors.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
 
 	logger.Info("User logged in: %s (ID: %d)", user.Email, user.ID)
+	recordAudit(ctx, r, auditEvent{
+		Event:      AuditLogin,
+		ActorID:    &user.ID,
+		TargetType: "user",
+		TargetID:   strconv.Itoa(user.ID),
+		Details:    map[string]any{"method": "password", "remember_me": req.RememberMe},
+	})
 
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(response); err != nil {
@@ -224,44 +346,48 @@ func Login(w http.ResponseWriter, r *http.Request) {
 }
 
 // @Summary Refresh token
-// @Description Get a new access token using a refresh token
+// @Description Get a new access token using a refresh token. The token may be sent in the body or, after a browser login through OIDC, in the nomdb_refresh_token cookie; cookie-based refreshes do not return the refresh token.
 // @Tags Auth
 // @Accept json
 // @Produce json
-// @Param request body models.RefreshTokenRequest true "Refresh token"
+// @Param request body models.RefreshTokenRequest false "Refresh token (optional when the cookie is set)"
 // @Success 200 {object} models.LoginResponse
-// @Failure 400 {string} string "Invalid request"
-// @Failure 401 {string} string "Invalid or expired refresh token"
-// @Failure 500 {string} string "Internal server error"
+// @Failure 400 {object} errors.ErrorResponse "Invalid request"
+// @Failure 401 {object} errors.ErrorResponse "Invalid or expired refresh token"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
 // @Router /auth/refresh [post]
 func RefreshToken(w http.ResponseWriter, r *http.Request) {
 	var req models.RefreshTokenRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		apperrors.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
 
-	if req.RefreshToken == "" {
-		http.Error(w, "Refresh token is required", http.StatusBadRequest)
+	refreshToken, fromCookie := refreshTokenFromRequest(r, req.RefreshToken)
+	if refreshToken == "" {
+		apperrors.Error(w, "Refresh token is required", http.StatusBadRequest)
 		return
 	}
 
-	ctx := context.Background()
+	ctx := r.Context()
 
 	// Fetch session
 	var session models.Session
 	var userID int
 	err := database.GetPool().QueryRow(ctx,
 		`SELECT id, user_id, expires_at FROM sessions WHERE refresh_token = $1`,
-		req.RefreshToken).Scan(&session.ID, &userID, &session.ExpiresAt)
+		refreshToken).Scan(&session.ID, &userID, &session.ExpiresAt)
 
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			http.Error(w, "Invalid refresh token", http.StatusUnauthorized)
+		if errors.Is(err, pgx.ErrNoRows) {
+			if fromCookie {
+				clearRefreshTokenCookie(w)
+			}
+			apperrors.Error(w, "Invalid refresh token", http.StatusUnauthorized)
 			return
 		}
 		logger.Error("Failed to fetch session: %v", err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		apperrors.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
 
@@ -271,7 +397,10 @@ func RefreshToken(w http.ResponseWriter, r *http.Request) {
 		if _, err := database.GetPool().Exec(ctx, "DELETE FROM sessions WHERE id = $1", session.ID); err != nil {
 			logger.Warn("Failed to delete expired session: %v", err)
 		}
-		http.Error(w, "Refresh token expired", http.StatusUnauthorized)
+		if fromCookie {
+			clearRefreshTokenCookie(w)
+		}
+		apperrors.Error(w, "Refresh token expired", http.StatusUnauthorized)
 		return
 	}
 
@@ -287,36 +416,45 @@ func RefreshToken(w http.ResponseWriter, r *http.Request) {
 	user, err := getUserByID(ctx, userID)
 	if err != nil {
 		logger.Error("Failed to fetch user: %v", err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		apperrors.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
 
 	// Check if user is active
 	if !user.IsActive {
-		http.Error(w, "Account is disabled", http.StatusUnauthorized)
+		apperrors.Error(w, "Account is disabled", http.StatusUnauthorized)
 		return
 	}
 
 	// Generate new access token (keep same refresh token)
 	jwtService := getJWTService()
 	if jwtService == nil {
-		http.Error(w, "Authentication service not available", http.StatusInternalServerError)
+		apperrors.Error(w, "Authentication service not available", http.StatusInternalServerError)
 		return
 	}
 	accessToken, err := jwtService.GenerateAccessToken(user)
 	if err != nil {
 		logger.Error("Failed to generate access token: %v", err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		apperrors.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
 
 	response := models.LoginResponse{
-		AccessToken:  accessToken,
-		RefreshToken: req.RefreshToken,
-		TokenType:    "Bearer",
-		ExpiresIn:    int(jwtService.GetAccessTokenDuration().Seconds()),
-		User:         *user,
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   int(jwtService.GetAccessTokenDuration().Seconds()),
+		User:        *user,
 	}
+	if !fromCookie {
+		response.RefreshToken = refreshToken
+	}
+
+	recordAudit(ctx, r, auditEvent{
+		Event:      AuditTokenRefresh,
+		ActorID:    &user.ID,
+		TargetType: "session",
+		TargetID:   strconv.Itoa(session.ID),
+	})
 
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(response); err != nil {
@@ -325,28 +463,41 @@ func RefreshToken(w http.ResponseWriter, r *http.Request) {
 }
 
 // @Summary Logout
-// @Description Invalidate refresh token
+// @Description Invalidate refresh token, sent in the body or the nomdb_refresh_token cookie (which is cleared)
 // @Tags Auth
 // @Accept json
 // @Produce json
-// @Param request body models.RefreshTokenRequest true "Refresh token to invalidate"
+// @Param request body models.RefreshTokenRequest false "Refresh token to invalidate"
 // @Success 200 {string} string "Logged out successfully"
-// @Failure 400 {string} string "Invalid request"
+// @Failure 400 {object} errors.ErrorResponse "Invalid request"
 // @Router /auth/logout [post]
 func Logout(w http.ResponseWriter, r *http.Request) {
 	var req models.RefreshTokenRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		apperrors.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
 
-	if req.RefreshToken != "" {
-		ctx := context.Background()
-		_, err := database.GetPool().Exec(ctx, "DELETE FROM sessions WHERE refresh_token = $1", req.RefreshToken)
-		if err != nil {
+	refreshToken, fromCookie := refreshTokenFromRequest(r, req.RefreshToken)
+	if refreshToken != "" {
+		ctx := r.Context()
+		var userID, sessionID int
+		err := database.GetPool().QueryRow(ctx,
+			"DELETE FROM sessions WHERE refresh_token = $1 RETURNING user_id, id", refreshToken).Scan(&userID, &sessionID)
+		if err == nil {
+			recordAudit(ctx, r, auditEvent{
+				Event:      AuditLogout,
+				ActorID:    &userID,
+				TargetType: "session",
+				TargetID:   strconv.Itoa(sessionID),
+			})
+		} else if !errors.Is(err, pgx.ErrNoRows) {
 			logger.Warn("Failed to delete session: %v", err)
 		}
 	}
+	if fromCookie {
+		clearRefreshTokenCookie(w)
+	}
 
 	w.WriteHeader(http.StatusOK)
 	_, _ = w.Write([]byte("Logged out successfully"))
@@ -357,7 +508,7 @@ func Logout(w http.ResponseWriter, r *http.Request) {
 // @Tags Auth
 // @Produce json
 // @Success 200 {object} models.User
-// @Failure 401 {string} string "Unauthorized"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
 // @Security BearerAuth
 // @Router /auth/me [get]
 func GetMe(w http.ResponseWriter, r *http.Request) {
@@ -390,7 +541,7 @@ func getUserByEmail(ctx context.Context, email string) (*models.User, error) {
 	err := database.GetPool().QueryRow(ctx,
 		`SELECT id, email, username, password_hash, provider, provider_id, full_name, avatar_url,
 		is_active, is_admin, email_verified, last_login_at, created_at, updated_at
-		FROM users WHERE email = $1`, email).Scan(
+		FROM users WHERE lower(email) = lower($1)`, strings.TrimSpace(email)).Scan(
 		&user.ID, &user.Email, &user.Username, &user.PasswordHash, &user.Provider, &user.ProviderID,
 		&user.FullName, &user.AvatarURL, &user.IsActive, &user.IsAdmin, &user.EmailVerified,
 		&user.LastLoginAt, &user.CreatedAt, &user.UpdatedAt)
@@ -400,7 +551,7 @@ func getUserByEmail(ctx context.Context, email string) (*models.User, error) {
 	return &user, nil
 }
 
-func generateLoginResponseWithService(ctx context.Context, user *models.User, r *http.Request, jwtSvc *auth.JWTService) (*models.LoginResponse, error) {
+func generateLoginResponseWithService(ctx context.Context, user *models.User, r *http.Request, jwtSvc *auth.JWTService, rememberMe bool) (*models.LoginResponse, error) {
 	// Generate access token
 	accessToken, err := jwtSvc.GenerateAccessToken(user)
 	if err != nil {
@@ -414,8 +565,9 @@ func generateLoginResponseWithService(ctx context.Context, user *models.User, r
 	}
 
 	// Store session
-	expiresAt := time.Now().Add(jwtSvc.GetRefreshTokenDuration())
-	ipAddress := r.RemoteAddr
+	refreshDuration := jwtSvc.RefreshTokenDurationFor(rememberMe)
+	expiresAt := time.Now().Add(refreshDuration)
+	ipAddress := middleware.ClientIP(r)
 	userAgent := r.UserAgent()
 
 	_, err = database.GetPool().Exec(ctx,
@@ -427,19 +579,15 @@ func generateLoginResponseWithService(ctx context.Context, user *models.User, r
 	}
 
 	return &models.LoginResponse{
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
-		TokenType:    "Bearer",
-		ExpiresIn:    int(jwtSvc.GetAccessTokenDuration().Seconds()),
-		User:         *user,
+		AccessToken:      accessToken,
+		RefreshToken:     refreshToken,
+		TokenType:        "Bearer",
+		ExpiresIn:        int(jwtSvc.GetAccessTokenDuration().Seconds()),
+		RefreshExpiresIn: int(refreshDuration.Seconds()),
+		User:             *user,
 	}, nil
 }
 
-func isValidEmail(email string) bool {
-	// Basic email validation - you might want to use a more robust library
-	return len(email) > 3 && strings.Contains(email, "@") && strings.Contains(email, ".")
-}
-
 func isDuplicateKeyError(err error) bool {
 	// Check for PostgreSQL duplicate key error
 	return err != nil && (strings.Contains(err.Error(), "duplicate key") || strings.Contains(err.Error(), "unique constraint"))
@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"time"
+
+	apperrors "github.com/nomdb/backend/internal/errors"
+	"github.com/nomdb/backend/internal/logger"
+	"github.com/nomdb/backend/internal/middleware"
+	"github.com/nomdb/backend/internal/models"
+)
+
+// RefreshTokenCookie carries the refresh token of browser logins completed through a
+// redirect to the frontend. It is HttpOnly and limited to the auth endpoints.
+const RefreshTokenCookie = "nomdb_refresh_token"
+
+const refreshTokenCookiePath = "/api/auth"
+
+var (
+	oidcFrontendRedirectURL string // Empty: the OIDC callback responds with JSON
+	authCookieSecure        = true
+	authCookieSameSite      = http.SameSiteLaxMode
+)
+
+// InitAuthCookies configures where OIDC logins redirect to and the refresh token cookie
+func InitAuthCookies(frontendRedirectURL string, secure bool, sameSite string) {
+	oidcFrontendRedirectURL = frontendRedirectURL
+	authCookieSecure = secure
+	switch sameSite {
+	case "strict":
+		authCookieSameSite = http.SameSiteStrictMode
+	case "none":
+		authCookieSameSite = http.SameSiteNoneMode
+	default:
+		authCookieSameSite = http.SameSiteLaxMode
+	}
+
+	if frontendRedirectURL != "" {
+		logger.Info("🍪 OIDC logins redirect to %s with a refresh token cookie", frontendRedirectURL)
+	}
+	if !secure {
+		logger.Warn("⚠️  AUTH_COOKIE_SECURE=false - refresh token cookies are sent over plain HTTP")
+	}
+}
+
+// setRefreshTokenCookie stores a refresh token in the browser for maxAge
+func setRefreshTokenCookie(w http.ResponseWriter, token string, maxAge time.Duration) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     RefreshTokenCookie,
+		Value:    token,
+		Path:     refreshTokenCookiePath,
+		MaxAge:   int(maxAge.Seconds()),
+		HttpOnly: true,
+		Secure:   authCookieSecure,
+		SameSite: authCookieSameSite,
+	})
+}
+
+// clearRefreshTokenCookie removes the refresh token cookie
+func clearRefreshTokenCookie(w http.ResponseWriter) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     RefreshTokenCookie,
+		Value:    "",
+		Path:     refreshTokenCookiePath,
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   authCookieSecure,
+		SameSite: authCookieSameSite,
+	})
+}
+
+// @Summary Get a CSRF token
+// @Description Issue a CSRF token for cookie-based auth. POST, PUT, PATCH and DELETE requests sent with the refresh token cookie (e.g. cookie-based refresh and logout) must repeat it in the X-CSRF-Token header. The token is also stored in the HttpOnly nomdb_csrf cookie and reused while that cookie exists.
+// @Tags Auth
+// @Produce json
+// @Success 200 {object} models.CSRFTokenResponse
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /auth/csrf [get]
+func GetCSRFToken(w http.ResponseWriter, r *http.Request) {
+	token := ""
+	if cookie, err := r.Cookie(middleware.CSRFCookie); err == nil && cookie.Value != "" {
+		token = cookie.Value
+	} else {
+		b := make([]byte, 32)
+		if _, err := rand.Read(b); err != nil {
+			apperrors.Internal(w, err)
+			return
+		}
+		token = base64.RawURLEncoding.EncodeToString(b)
+	}
+
+	// Not limited to the auth endpoints, so future cookie-authenticated routes share it
+	http.SetCookie(w, &http.Cookie{
+		Name:     middleware.CSRFCookie,
+		Value:    token,
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   authCookieSecure,
+		SameSite: authCookieSameSite,
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if err := json.NewEncoder(w).Encode(models.CSRFTokenResponse{CSRFToken: token}); err != nil {
+		logger.Error("Failed to encode response: %v", err)
+	}
+}
+
+// refreshTokenFromRequest returns the refresh token of the request body, falling back to
+// the refresh token cookie
+func refreshTokenFromRequest(r *http.Request, bodyToken string) (string, bool) {
+	if bodyToken != "" {
+		return bodyToken, false
+	}
+	cookie, err := r.Cookie(RefreshTokenCookie)
+	if err != nil || cookie.Value == "" {
+		return "", false
+	}
+	return cookie.Value, true
+}
+
+// redirectToFrontend sends the browser back to the frontend after an OIDC login. An empty
+// errorCode signals success; otherwise it is passed as the error query parameter.
+func redirectToFrontend(w http.ResponseWriter, r *http.Request, errorCode string) {
+	target, err := url.Parse(oidcFrontendRedirectURL)
+	if err != nil {
+		apperrors.Error(w, "Invalid frontend redirect URL", http.StatusInternalServerError)
+		return
+	}
+	query := target.Query()
+	if errorCode != "" {
+		query.Set("error", errorCode)
+	} else {
+		query.Set("login", "success")
+	}
+	target.RawQuery = query.Encode()
+	http.Redirect(w, r, target.String(), http.StatusSeeOther)
+}
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRefreshTokenCookie(t *testing.T) {
+	defer func() { authCookieSecure, authCookieSameSite = true, http.SameSiteLaxMode }()
+	InitAuthCookies("", true, "strict")
+
+	rec := httptest.NewRecorder()
+	setRefreshTokenCookie(rec, "token", time.Hour)
+
+	cookies := rec.Result().Cookies()
+	if len(cookies) != 1 {
+		t.Fatalf("Expected 1 cookie, got %d", len(cookies))
+	}
+	cookie := cookies[0]
+	if cookie.Name != RefreshTokenCookie || cookie.Value != "token" {
+		t.Errorf("Unexpected cookie: %+v", cookie)
+	}
+	if !cookie.HttpOnly || !cookie.Secure || cookie.SameSite != http.SameSiteStrictMode {
+		t.Errorf("Expected an HttpOnly, Secure, SameSite=Strict cookie, got %+v", cookie)
+	}
+	if cookie.Path != "/api/auth" || cookie.MaxAge != 3600 {
+		t.Errorf("Expected path /api/auth and max age 3600, got %s and %d", cookie.Path, cookie.MaxAge)
+	}
+}
+
+func TestRefreshTokenFromRequest(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/api/auth/refresh", nil)
+	req.AddCookie(&http.Cookie{Name: RefreshTokenCookie, Value: "from-cookie"})
+
+	if token, fromCookie := refreshTokenFromRequest(req, "from-body"); token != "from-body" || fromCookie {
+		t.Errorf("Expected the body token to take precedence, got %q (cookie: %v)", token, fromCookie)
+	}
+	if token, fromCookie := refreshTokenFromRequest(req, ""); token != "from-cookie" || !fromCookie {
+		t.Errorf("Expected the cookie token, got %q (cookie: %v)", token, fromCookie)
+	}
+
+	empty := httptest.NewRequest(http.MethodPost, "/api/auth/refresh", nil)
+	if token, _ := refreshTokenFromRequest(empty, ""); token != "" {
+		t.Errorf("Expected no token, got %q", token)
+	}
+}
+
+func TestOIDCCallbackError(t *testing.T) {
+	defer func() { oidcFrontendRedirectURL = "" }()
+	req := httptest.NewRequest(http.MethodGet, "/api/auth/oidc/google/callback", nil)
+
+	rec := httptest.NewRecorder()
+	oidcCallbackError(rec, req, "invalid_state", "Invalid or expired state", http.StatusBadRequest)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 without a frontend redirect, got %d", rec.Code)
+	}
+
+	oidcFrontendRedirectURL = "https://app.example.com/login?next=%2Fmap"
+	rec = httptest.NewRecorder()
+	oidcCallbackError(rec, req, "invalid_state", "Invalid or expired state", http.StatusBadRequest)
+	if rec.Code != http.StatusSeeOther {
+		t.Fatalf("Expected 303, got %d", rec.Code)
+	}
+	expected := "https://app.example.com/login?error=invalid_state&next=%2Fmap"
+	if location := rec.Header().Get("Location"); location != expected {
+		t.Errorf("Expected redirect to %s, got %s", expected, location)
+	}
+}
@@ -0,0 +1,204 @@
+package handlers
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nomdb/backend/internal/backup"
+	"github.com/nomdb/backend/internal/database"
+	apperrors "github.com/nomdb/backend/internal/errors"
+	"github.com/nomdb/backend/internal/logger"
+	"github.com/nomdb/backend/internal/models"
+	"github.com/nomdb/backend/internal/storage"
+)
+
+// backupsPrefix is the storage prefix of database backups
+const backupsPrefix = "backups"
+
+// errBackupRunning is returned when this instance is already taking a backup
+var errBackupRunning = errors.New("backup already running")
+
+var backupMu sync.Mutex
+
+// StartBackupJob periodically backs up the database to storage and deletes backups
+// older than retention (0 keeps all). The newest backup is always kept.
+func StartBackupJob(interval, retention time.Duration) {
+	if interval <= 0 {
+		logger.Debug("Database backup job disabled")
+		return
+	}
+
+	logger.Info("✅ Database backup job scheduled every %s", interval)
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if !backupMu.TryLock() {
+				logger.Warn("⚠️  Skipping scheduled backup: %v", errBackupRunning)
+				continue
+			}
+			_, err := runBackup(context.Background(), backupKey(time.Now()))
+			backupMu.Unlock()
+			if errors.Is(err, backup.ErrLocked) {
+				logger.Info("Skipping scheduled backup: %v", err)
+				continue
+			}
+			if err != nil {
+				logger.Error("❌ Database backup failed: %v", err)
+				continue
+			}
+			if retention > 0 {
+				if err := pruneBackups(context.Background(), time.Now().Add(-retention)); err != nil {
+					logger.Error("❌ Failed to prune database backups: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+func backupKey(t time.Time) string {
+	return fmt.Sprintf("%s/nomdb-%s.jsonl.gz", backupsPrefix, t.UTC().Format("20060102T150405Z"))
+}
+
+// runBackup exports the database to a temporary file and uploads it under key. The
+// export is spooled to disk so the snapshot transaction does not stay open for the
+// upload, and so every backend gets a body of known length.
+func runBackup(ctx context.Context, key string) (*models.BackupInfo, error) {
+	startedAt := time.Now()
+	tmp, err := os.CreateTemp("", "nomdb-backup-*.jsonl.gz")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	gz := gzip.NewWriter(tmp)
+	stats, err := backup.Export(ctx, database.GetPool(), gz)
+	if err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write backup: %w", err)
+	}
+	size, err := tmp.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	if err := storage.Get().Upload(ctx, key, tmp, "application/gzip"); err != nil {
+		return nil, err
+	}
+
+	logger.Info("💾 Database backup %s: %d rows from %d tables at schema version %d (%d bytes) in %s",
+		key, stats.Rows, len(stats.Tables), stats.SchemaVersion, size, time.Since(startedAt).Round(time.Millisecond))
+	return &models.BackupInfo{
+		Key:           key,
+		Size:          size,
+		CreatedAt:     startedAt,
+		SchemaVersion: stats.SchemaVersion,
+		Rows:          stats.Rows,
+		Tables:        stats.Tables,
+	}, nil
+}
+
+// listBackups returns the backups in storage, newest first
+func listBackups(ctx context.Context) ([]models.BackupInfo, error) {
+	objects, err := storage.Get().List(ctx, backupsPrefix+"/")
+	if err != nil {
+		return nil, err
+	}
+	backups := make([]models.BackupInfo, 0, len(objects))
+	for _, obj := range objects {
+		if !strings.HasSuffix(obj.Key, ".jsonl.gz") {
+			continue
+		}
+		backups = append(backups, models.BackupInfo{Key: obj.Key, Size: obj.Size, CreatedAt: obj.LastModified})
+	}
+	sort.Slice(backups, func(i, j int) bool { return backups[i].CreatedAt.After(backups[j].CreatedAt) })
+	return backups, nil
+}
+
+// pruneBackups deletes backups created before cutoff, except the newest one
+func pruneBackups(ctx context.Context, cutoff time.Time) error {
+	backups, err := listBackups(ctx)
+	if err != nil {
+		return err
+	}
+	for i, b := range backups {
+		if i == 0 || !b.CreatedAt.Before(cutoff) {
+			continue
+		}
+		if err := storage.Get().Delete(ctx, b.Key); err != nil {
+			logger.Warn("Failed to delete backup %s: %v", b.Key, err)
+			continue
+		}
+		logger.Info("🗑️  Deleted database backup %s", b.Key)
+	}
+	return nil
+}
+
+// @Summary Back up the database
+// @Description Start a logical backup of the database: every table as gzip-compressed JSON lines, uploaded to the storage backend under backups/. Runs in the background; the backup appears in GET /admin/backups when done. Restore it with `migrate restore`. Admin only.
+// @Tags Admin
+// @Produce json
+// @Success 202 {object} models.BackupStatus "Backup started"
+// @Failure 409 {object} errors.ErrorResponse "Backup already running"
+// @Security BearerAuth
+// @Router /admin/backup [post]
+func CreateBackup(w http.ResponseWriter, r *http.Request) {
+	if !backupMu.TryLock() {
+		apperrors.Error(w, errBackupRunning.Error(), http.StatusConflict)
+		return
+	}
+
+	key := backupKey(time.Now())
+	go func() {
+		defer backupMu.Unlock()
+		if _, err := runBackup(context.Background(), key); err != nil {
+			logger.Error("❌ Database backup failed: %v", err)
+		}
+	}()
+
+	recordAudit(r.Context(), r, auditEvent{
+		Event:   AuditAdminAction,
+		Details: map[string]any{"action": "database_backup", "key": key},
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(models.BackupStatus{Status: "started", Key: key})
+}
+
+// @Summary List database backups
+// @Description List the database backups in storage, newest first. Admin only.
+// @Tags Admin
+// @Produce json
+// @Success 200 {array} models.BackupInfo "Backups"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /admin/backups [get]
+func ListBackups(w http.ResponseWriter, r *http.Request) {
+	backups, err := listBackups(r.Context())
+	if err != nil {
+		logger.Error("Failed to list backups: %v", err)
+		apperrors.Internal(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(backups)
+}
@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/nomdb/backend/internal/events"
+	"github.com/nomdb/backend/internal/i18n"
+	"github.com/nomdb/backend/internal/logger"
+	"github.com/nomdb/backend/internal/models"
+	"github.com/nomdb/backend/internal/repository"
+)
+
+// badges are the milestones users are awarded for, with their names and descriptions by
+// locale. Awarded badges are stored by key, so keys must not change; migration
+// 000040_user_badges awarded them for earlier contributions.
+var badges = []struct {
+	key          string
+	names        map[string]string
+	descriptions map[string]string
+	earned       func(repository.BadgeProgress) bool
+}{
+	{
+		"first_rating",
+		map[string]string{"en": "First Bite", "de": "Erster Bissen"},
+		map[string]string{"en": "Rated a restaurant for the first time", "de": "Zum ersten Mal ein Restaurant bewertet"},
+		func(p repository.BadgeProgress) bool { return p.Ratings >= 1 },
+	},
+	{
+		"cuisine_explorer",
+		map[string]string{"en": "Cuisine Explorer", "de": "Küchenentdecker"},
+		map[string]string{"en": "Rated restaurants of 10 different food types", "de": "Restaurants mit 10 verschiedenen Küchen bewertet"},
+		func(p repository.BadgeProgress) bool { return p.FoodTypes >= 10 },
+	},
+	{
+		"trendsetter",
+		map[string]string{"en": "Trendsetter", "de": "Trendsetter"},
+		map[string]string{"en": "Had 5 suggestions converted to restaurants", "de": "5 Vorschläge wurden zu Restaurants"},
+		func(p repository.BadgeProgress) bool { return p.ConvertedSuggestions >= 5 },
+	},
+}
+
+const badgeEvaluationTimeout = 15 * time.Second
+
+var badgesInitOnce sync.Once
+
+// InitBadges awards badges to the authors of ratings and converted suggestions as they
+// are published
+func InitBadges() {
+	badgesInitOnce.Do(func() {
+		events.Subscribe(func(e events.Event) {
+			// The instance that published the event evaluates it
+			if e.Remote {
+				return
+			}
+			switch e.Type {
+			case eventRatingCreated, eventSuggestionConverted:
+				go evaluateBadges(e)
+			}
+		})
+	})
+}
+
+// localizedBadges returns the badges named in locale
+func localizedBadges(locale string) []models.Badge {
+	list := make([]models.Badge, 0, len(badges))
+	for _, b := range badges {
+		list = append(list, models.Badge{
+			Key:         b.key,
+			Name:        localizedBadgeText(b.names, locale),
+			Description: localizedBadgeText(b.descriptions, locale),
+		})
+	}
+	return list
+}
+
+func localizedBadgeText(texts map[string]string, locale string) string {
+	if text, ok := texts[locale]; ok {
+		return text
+	}
+	return texts[i18n.Default]
+}
+
+// localizeUserBadges names awarded badges in locale, leaving out badges no longer defined
+func localizeUserBadges(awarded []models.UserBadge, locale string) []models.UserBadge {
+	localized := make([]models.UserBadge, 0, len(awarded))
+	for _, ub := range awarded {
+		for _, b := range badges {
+			if b.key == ub.Key {
+				ub.Name = localizedBadgeText(b.names, locale)
+				ub.Description = localizedBadgeText(b.descriptions, locale)
+				localized = append(localized, ub)
+				break
+			}
+		}
+	}
+	return localized
+}
+
+// evaluateBadges awards the author of a rating or converted suggestion the badges they
+// earned. Badges are best effort: failures are logged and caught up on the next event.
+func evaluateBadges(e events.Event) {
+	ctx, cancel := context.WithTimeout(context.Background(), badgeEvaluationTimeout)
+	defer cancel()
+
+	var userID *int
+	var err error
+	switch e.Type {
+	case eventRatingCreated:
+		userID, err = ratingRepo.AuthorID(ctx, e.ID)
+	case eventSuggestionConverted:
+		userID, err = restaurantRepo.SuggestedBy(ctx, e.RestaurantID)
+	}
+	if err != nil {
+		logger.Warn("Failed to find the author of %s %d for badges: %v", e.Type, e.ID, err)
+		return
+	}
+	// Anonymous contributions earn no badges
+	if userID == nil {
+		return
+	}
+
+	awarded, err := awardBadges(ctx, *userID)
+	if err != nil {
+		logger.Warn("Failed to award badges to user %d: %v", *userID, err)
+		return
+	}
+	for _, badge := range awarded {
+		logger.Info("🏅 User %d earned the %s badge", *userID, badge)
+	}
+}
+
+// awardBadges awards a user the badges their contributions so far earned, and returns
+// those newly awarded
+func awardBadges(ctx context.Context, userID int) ([]string, error) {
+	progress, err := repos.Badges.Progress(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	var earned []string
+	for _, b := range badges {
+		if b.earned(*progress) {
+			earned = append(earned, b.key)
+		}
+	}
+	if len(earned) == 0 {
+		return nil, nil
+	}
+	return repos.Badges.Award(ctx, userID, earned)
+}
@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nomdb/backend/internal/events"
+	"github.com/nomdb/backend/internal/models"
+	"github.com/pashagolub/pgxmock/v4"
+)
+
+func TestEvaluateBadges(t *testing.T) {
+	t.Run("rating author earns milestones", func(t *testing.T) {
+		mock := withMockRepositories(t)
+		author := 8
+		mock.ExpectQuery(`SELECT user_id FROM ratings`).WithArgs(42).
+			WillReturnRows(pgxmock.NewRows([]string{"user_id"}).AddRow(&author))
+		mock.ExpectQuery(`COUNT\(DISTINCT rft.food_type_id\)`).WithArgs(author).
+			WillReturnRows(pgxmock.NewRows([]string{"ratings", "food_types", "converted"}).AddRow(12, 10, 4))
+		mock.ExpectQuery(`INSERT INTO user_badges`).WithArgs(author, []string{"first_rating", "cuisine_explorer"}).
+			WillReturnRows(pgxmock.NewRows([]string{"badge"}).AddRow("cuisine_explorer"))
+
+		evaluateBadges(events.Event{Type: eventRatingCreated, ID: 42, RestaurantID: 3})
+	})
+
+	t.Run("converted suggestion of an anonymous user", func(t *testing.T) {
+		mock := withMockRepositories(t)
+		mock.ExpectQuery(`SELECT suggested_by_user_id FROM restaurants`).WithArgs(9).
+			WillReturnRows(pgxmock.NewRows([]string{"suggested_by_user_id"}).AddRow(nil))
+
+		evaluateBadges(events.Event{Type: eventSuggestionConverted, ID: 4, RestaurantID: 9})
+	})
+}
+
+func TestLocalizeUserBadges(t *testing.T) {
+	now := time.Now()
+	awarded := []models.UserBadge{{Key: "first_rating", AwardedAt: now}, {Key: "retired", AwardedAt: now}}
+
+	localized := localizeUserBadges(awarded, "de")
+	if len(localized) != 1 || localized[0].Name != "Erster Bissen" || localized[0].Description == "" {
+		t.Errorf("Expected only the defined badge in German, got %+v", localized)
+	}
+	if badges := localizedBadges("fr"); len(badges) != 3 || badges[0].Name != "First Bite" {
+		t.Errorf("Expected the English names for unknown locales, got %+v", badges)
+	}
+}
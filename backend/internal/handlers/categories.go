@@ -3,47 +3,203 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"regexp"
 	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/gorilla/mux"
+	"github.com/jackc/pgx/v5"
+	"github.com/nomdb/backend/internal/cache"
 	"github.com/nomdb/backend/internal/database"
+	apperrors "github.com/nomdb/backend/internal/errors"
 	"github.com/nomdb/backend/internal/logger"
 	"github.com/nomdb/backend/internal/models"
 )
 
 // GetCategories godoc
 // @Summary List all categories
-// @Description Get a list of all cultural categories
+// @Description Get a list of all cultural categories. Cacheable for HTTP_CACHE_MAX_AGE; revalidate with If-None-Match or If-Modified-Since to get 304 Not Modified while unchanged.
 // @Tags Categories
 // @Accept json
 // @Produce json
 // @Success 200 {array} models.Category "List of categories"
-// @Failure 500 {object} map[string]string "Internal server error"
+// @Success 304 "Not modified"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
 // @Router /categories [get]
 func GetCategories(w http.ResponseWriter, r *http.Request) {
-	rows, err := database.GetPool().Query(context.Background(),
-		"SELECT id, name, created_at, updated_at FROM categories ORDER BY name")
+	categories, err := cachedCategories(r.Context())
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		apperrors.Internal(w, err)
 		return
 	}
+	localizeCategories(r.Context(), categories)
+
+	writeCacheableJSON(w, r, categories, cache.KeyCategories)
+}
+
+// GetCategoryTree godoc
+// @Summary Get the category tree
+// @Description Get the top-level categories with their subcategories nested under children, each level sorted by name. Filtering restaurants by a category also matches its descendants. Cacheable like the category list.
+// @Tags Categories
+// @Produce json
+// @Success 200 {array} models.CategoryNode "Top-level categories"
+// @Success 304 "Not modified"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /categories/tree [get]
+func GetCategoryTree(w http.ResponseWriter, r *http.Request) {
+	categories, err := cachedCategories(r.Context())
+	if err != nil {
+		apperrors.Internal(w, err)
+		return
+	}
+	localizeCategories(r.Context(), categories)
+
+	writeCacheableJSON(w, r, categoryTree(categories), cache.KeyCategories)
+}
+
+// categoryTree nests categories under their parents, keeping their order. Categories
+// whose parent is missing are placed at the top level.
+func categoryTree(categories []models.Category) []models.CategoryNode {
+	children := map[int][]models.Category{}
+	ids := map[int]bool{}
+	for _, c := range categories {
+		ids[c.ID] = true
+	}
+	var roots []models.Category
+	for _, c := range categories {
+		if c.ParentID != nil && ids[*c.ParentID] && *c.ParentID != c.ID {
+			children[*c.ParentID] = append(children[*c.ParentID], c)
+		} else {
+			roots = append(roots, c)
+		}
+	}
+
+	var build func(level []models.Category) []models.CategoryNode
+	build = func(level []models.Category) []models.CategoryNode {
+		nodes := make([]models.CategoryNode, 0, len(level))
+		for _, c := range level {
+			nodes = append(nodes, models.CategoryNode{Category: c, Children: build(children[c.ID])})
+		}
+		return nodes
+	}
+	return build(roots)
+}
+
+// validCategoryParent checks that parentID exists and, when moving category id, is
+// neither the category itself nor one of its descendants
+func validCategoryParent(ctx context.Context, id, parentID int) (bool, error) {
+	var valid bool
+	err := database.GetPool().QueryRow(ctx,
+		`WITH RECURSIVE subtree AS (
+			SELECT id FROM categories WHERE id = $1
+			UNION SELECT c.id FROM categories c JOIN subtree ON c.parent_id = subtree.id
+		)
+		SELECT EXISTS (SELECT 1 FROM categories WHERE id = $2)
+			AND NOT EXISTS (SELECT 1 FROM subtree WHERE id = $2)`,
+		id, parentID).Scan(&valid)
+	return valid, err
+}
+
+// categoryParent returns the parent to store for a request's parent_id, nil for the
+// top level, writing the error response if it is invalid
+func categoryParent(w http.ResponseWriter, r *http.Request, id int, parentID *int) (*int, bool) {
+	if parentID == nil || *parentID == 0 {
+		return nil, true
+	}
+	valid, err := validCategoryParent(r.Context(), id, *parentID)
+	if err != nil {
+		apperrors.Internal(w, err)
+		return nil, false
+	}
+	if !valid {
+		apperrors.Error(w, "Parent category not found or inside this category", http.StatusBadRequest)
+		return nil, false
+	}
+	return parentID, true
+}
+
+// categoryColumns are the columns of a category scanned by categoryFields
+const categoryColumns = "id, name, parent_id, icon, color, created_at, updated_at"
+
+func categoryFields(c *models.Category) []any {
+	return []any{&c.ID, &c.Name, &c.ParentID, &c.Icon, &c.Color, &c.CreatedAt, &c.UpdatedAt}
+}
+
+// maxIconLength is the length of icons in characters, enough for an emoji made of
+// several code points or a short icon name
+const maxIconLength = 32
+
+var hexColorPattern = regexp.MustCompile(`^#[0-9a-f]{6}$`)
+
+// validAppearance validates the icon and color of a category or food type, lowercasing
+// the color, and writes the error response if one is invalid. Empty values are valid:
+// they remove the icon or color.
+func validAppearance(w http.ResponseWriter, icon, color *string) bool {
+	if icon != nil {
+		*icon = strings.TrimSpace(*icon)
+		if utf8.RuneCountInString(*icon) > maxIconLength || strings.ContainsFunc(*icon, unicode.IsControl) {
+			apperrors.Error(w, fmt.Sprintf("Icon must be an emoji or a name of up to %d characters", maxIconLength), http.StatusBadRequest)
+			return false
+		}
+	}
+	if color != nil {
+		*color = strings.ToLower(strings.TrimSpace(*color))
+		if *color != "" && !hexColorPattern.MatchString(*color) {
+			apperrors.Error(w, "Color must be a hex color such as #e4572e", http.StatusBadRequest)
+			return false
+		}
+	}
+	return true
+}
+
+// keepOrClear is the SQL updating column to the text parameter param: unchanged when it
+// is null, removed when it is empty
+func keepOrClear(column, param string) string {
+	return fmt.Sprintf("CASE WHEN %[2]s::text IS NULL THEN %[1]s ELSE NULLIF(%[2]s, '') END", column, param)
+}
+
+// cachedCategories returns all categories sorted by name, from the cache if possible
+func cachedCategories(ctx context.Context) ([]models.Category, error) {
+	var categories []models.Category
+	if cache.GetJSON(ctx, cache.KeyCategories, &categories) {
+		return categories, nil
+	}
+	categories, err := queryCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	cache.SetJSON(ctx, cache.KeyCategories, categories)
+	return categories, nil
+}
+
+func queryCategories(ctx context.Context) ([]models.Category, error) {
+	rows, err := database.GetPool().Query(ctx,
+		`SELECT c.id, c.name, c.parent_id, c.icon, c.color, c.created_at, c.updated_at, COUNT(r.id)
+		FROM categories c
+		LEFT JOIN restaurants r ON r.category_id = c.id
+		GROUP BY c.id
+		ORDER BY c.name`)
+	if err != nil {
+		return nil, err
+	}
 	defer rows.Close()
 
 	categories := []models.Category{}
 	for rows.Next() {
 		var c models.Category
-		if err := rows.Scan(&c.ID, &c.Name, &c.CreatedAt, &c.UpdatedAt); err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
+		var count int
+		if err := rows.Scan(append(categoryFields(&c), &count)...); err != nil {
+			return nil, err
 		}
+		c.RestaurantCount = &count
 		categories = append(categories, c)
 	}
-
-	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(categories); err != nil {
-		logger.Error("Failed to encode response: %v", err)
-	}
+	return categories, rows.Err()
 }
 
 // GetCategory godoc
@@ -54,25 +210,25 @@ func GetCategories(w http.ResponseWriter, r *http.Request) {
 // @Produce json
 // @Param id path int true "Category ID"
 // @Success 200 {object} models.Category "Category details"
-// @Failure 400 {object} map[string]string "Invalid category ID"
-// @Failure 404 {object} map[string]string "Category not found"
+// @Failure 400 {object} errors.ErrorResponse "Invalid category ID"
+// @Failure 404 {object} errors.ErrorResponse "Category not found"
 // @Router /categories/{id} [get]
 func GetCategory(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id, err := strconv.Atoi(vars["id"])
 	if err != nil {
-		http.Error(w, "Invalid category ID", http.StatusBadRequest)
+		apperrors.Error(w, "Invalid category ID", http.StatusBadRequest)
 		return
 	}
 
 	var c models.Category
-	err = database.GetPool().QueryRow(context.Background(),
-		"SELECT id, name, created_at, updated_at FROM categories WHERE id = $1", id).
-		Scan(&c.ID, &c.Name, &c.CreatedAt, &c.UpdatedAt)
+	err = database.GetPool().QueryRow(r.Context(),
+		"SELECT "+categoryColumns+" FROM categories WHERE id = $1", id).Scan(categoryFields(&c)...)
 	if err != nil {
-		http.Error(w, "Category not found", http.StatusNotFound)
+		apperrors.Error(w, "Category not found", http.StatusNotFound)
 		return
 	}
+	translate(translatedNames(r.Context()), &c, nil)
 
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(c); err != nil {
@@ -82,35 +238,46 @@ func GetCategory(w http.ResponseWriter, r *http.Request) {
 
 // CreateCategory godoc
 // @Summary Create a new category
-// @Description Create a new cultural category with the provided name
+// @Description Create a new cultural category with the provided name, under the category parent_id if given, with an optional icon (an emoji or a name of up to 32 characters) and hex color
 // @Tags Categories
 // @Accept json
 // @Produce json
 // @Param category body models.CreateCategoryRequest true "Category creation request"
 // @Success 201 {object} models.Category "Created category"
-// @Failure 400 {object} map[string]string "Invalid request body or name is required"
-// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 400 {object} errors.ErrorResponse "Invalid request body, name is required, invalid icon or color, or parent category not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
 // @Router /categories [post]
 func CreateCategory(w http.ResponseWriter, r *http.Request) {
 	var req models.CreateCategoryRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		apperrors.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
 
 	if req.Name == "" {
-		http.Error(w, "Name is required", http.StatusBadRequest)
+		apperrors.Error(w, "Name is required", http.StatusBadRequest)
+		return
+	}
+
+	if !validAppearance(w, req.Icon, req.Color) {
+		return
+	}
+	parentID, ok := categoryParent(w, r, 0, req.ParentID)
+	if !ok {
 		return
 	}
 
 	var c models.Category
-	err := database.GetPool().QueryRow(context.Background(),
-		"INSERT INTO categories (name) VALUES ($1) RETURNING id, name, created_at, updated_at",
-		req.Name).Scan(&c.ID, &c.Name, &c.CreatedAt, &c.UpdatedAt)
+	err := database.GetPool().QueryRow(r.Context(),
+		`INSERT INTO categories (name, parent_id, icon, color) VALUES ($1, $2, NULLIF($3, ''), NULLIF($4, ''))
+		RETURNING `+categoryColumns,
+		req.Name, parentID, req.Icon, req.Color).Scan(categoryFields(&c)...)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		apperrors.Internal(w, err)
 		return
 	}
+	cache.Invalidate(r.Context(), cache.KeyCategories)
+	publishChange(r.Context(), eventCategoryCreated, 0, 0, c.ID)
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
@@ -119,43 +286,56 @@ func CreateCategory(w http.ResponseWriter, r *http.Request) {
 
 // UpdateCategory godoc
 // @Summary Update a category
-// @Description Update an existing category's name
+// @Description Update an existing category's name, and its parent, icon and color if given: parent_id 0 moves it to the top level, and an empty icon or color removes it. A category cannot be moved under itself or one of its descendants.
 // @Tags Categories
 // @Accept json
 // @Produce json
 // @Param id path int true "Category ID"
 // @Param category body models.CreateCategoryRequest true "Category update request"
 // @Success 200 {object} models.Category "Updated category"
-// @Failure 400 {object} map[string]string "Invalid request or name is required"
-// @Failure 404 {object} map[string]string "Category not found"
+// @Failure 400 {object} errors.ErrorResponse "Invalid request, name is required, invalid icon or color, or invalid parent category"
+// @Failure 404 {object} errors.ErrorResponse "Category not found"
 // @Router /categories/{id} [put]
 func UpdateCategory(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id, err := strconv.Atoi(vars["id"])
 	if err != nil {
-		http.Error(w, "Invalid category ID", http.StatusBadRequest)
+		apperrors.Error(w, "Invalid category ID", http.StatusBadRequest)
 		return
 	}
 
 	var req models.CreateCategoryRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		apperrors.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
 
 	if req.Name == "" {
-		http.Error(w, "Name is required", http.StatusBadRequest)
+		apperrors.Error(w, "Name is required", http.StatusBadRequest)
+		return
+	}
+
+	if !validAppearance(w, req.Icon, req.Color) {
+		return
+	}
+	parentID, ok := categoryParent(w, r, id, req.ParentID)
+	if !ok {
 		return
 	}
 
 	var c models.Category
-	err = database.GetPool().QueryRow(context.Background(),
-		"UPDATE categories SET name = $1, updated_at = NOW() WHERE id = $2 RETURNING id, name, created_at, updated_at",
-		req.Name, id).Scan(&c.ID, &c.Name, &c.CreatedAt, &c.UpdatedAt)
+	err = database.GetPool().QueryRow(r.Context(),
+		`UPDATE categories SET name = $1, parent_id = CASE WHEN $2 THEN $3 ELSE parent_id END,
+			icon = `+keepOrClear("icon", "$5")+`, color = `+keepOrClear("color", "$6")+`, updated_at = NOW()
+		WHERE id = $4 RETURNING `+categoryColumns,
+		req.Name, req.ParentID != nil, parentID, id, req.Icon, req.Color).Scan(categoryFields(&c)...)
 	if err != nil {
-		http.Error(w, "Category not found", http.StatusNotFound)
+		apperrors.Error(w, "Category not found", http.StatusNotFound)
 		return
 	}
+	// Restaurants in the list embed their category's name
+	cache.Invalidate(r.Context(), cache.KeyCategories, cache.KeyRestaurants)
+	publishChange(r.Context(), eventCategoryUpdated, 0, 0, c.ID)
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(c)
@@ -163,35 +343,106 @@ func UpdateCategory(w http.ResponseWriter, r *http.Request) {
 
 // DeleteCategory godoc
 // @Summary Delete a category
-// @Description Delete a category by ID
+// @Description Delete a category by ID. Its subcategories move to the top level. A category with restaurants can only be deleted with reassign_to, which moves its restaurants and suggestions to another category. Admin only.
 // @Tags Categories
 // @Accept json
 // @Produce json
 // @Param id path int true "Category ID"
+// @Param reassign_to query int false "Category to move the restaurants and suggestions of the deleted category to"
 // @Success 204 "Category deleted successfully"
-// @Failure 400 {object} map[string]string "Invalid category ID"
-// @Failure 404 {object} map[string]string "Category not found"
-// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 400 {object} errors.ErrorResponse "Invalid category ID or reassign_to"
+// @Failure 403 {object} errors.ErrorResponse "Admin access required"
+// @Failure 404 {object} errors.ErrorResponse "Category not found"
+// @Failure 409 {object} errors.ErrorResponse "Category has restaurants and no reassign_to"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
 // @Router /categories/{id} [delete]
 func DeleteCategory(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id, err := strconv.Atoi(vars["id"])
 	if err != nil {
-		http.Error(w, "Invalid category ID", http.StatusBadRequest)
+		apperrors.Error(w, "Invalid category ID", http.StatusBadRequest)
+		return
+	}
+
+	var reassignTo *int
+	if value := r.URL.Query().Get("reassign_to"); value != "" {
+		target, err := strconv.Atoi(value)
+		if err != nil || target == id {
+			apperrors.Error(w, "Invalid reassign_to category", http.StatusBadRequest)
+			return
+		}
+		reassignTo = &target
+	}
+
+	ctx := r.Context()
+	tx, err := database.GetPool().Begin(ctx)
+	if err != nil {
+		apperrors.Internal(w, err)
 		return
 	}
+	defer tx.Rollback(ctx)
 
-	result, err := database.GetPool().Exec(context.Background(),
-		"DELETE FROM categories WHERE id = $1", id)
+	count, err := lockForDelete(ctx, tx, "categories", id,
+		"SELECT COUNT(*) FROM restaurants WHERE category_id = $1")
+	if errors.Is(err, pgx.ErrNoRows) {
+		apperrors.Error(w, "Category not found", http.StatusNotFound)
+		return
+	}
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		apperrors.Internal(w, err)
 		return
 	}
 
-	if result.RowsAffected() == 0 {
-		http.Error(w, "Category not found", http.StatusNotFound)
+	details := map[string]any{}
+	if reassignTo != nil {
+		var exists bool
+		if err := tx.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM categories WHERE id = $1)", *reassignTo).Scan(&exists); err != nil {
+			apperrors.Internal(w, err)
+			return
+		}
+		if !exists {
+			apperrors.Error(w, "Category to reassign to not found", http.StatusBadRequest)
+			return
+		}
+		for _, query := range []string{
+			"UPDATE restaurants SET category_id = $1, updated_at = NOW() WHERE category_id = $2",
+			"UPDATE restaurant_suggestions SET suggested_category_id = $1, updated_at = NOW() WHERE suggested_category_id = $2",
+		} {
+			if _, err := tx.Exec(ctx, query, *reassignTo, id); err != nil {
+				apperrors.Internal(w, err)
+				return
+			}
+		}
+		details["reassign_to"] = *reassignTo
+		details["restaurants"] = count
+	} else if count > 0 {
+		apperrors.Error(w, fmt.Sprintf("Category has %d restaurants; move them to another category with reassign_to", count), http.StatusConflict)
+		return
+	}
+
+	if _, err := tx.Exec(ctx, "DELETE FROM categories WHERE id = $1", id); err != nil {
+		apperrors.Internal(w, err)
+		return
+	}
+	if err := tx.Commit(ctx); err != nil {
+		apperrors.Internal(w, err)
 		return
 	}
 
+	cache.Invalidate(ctx, cache.KeyCategories, cache.KeyRestaurants)
+	publishChange(ctx, eventCategoryDeleted, 0, 0, id)
+	recordAdminAction(ctx, r, "delete_category", "category", id, details)
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// lockForDelete locks row id of table until tx ends, so that no restaurant starts using
+// it before it is deleted, and returns the number of restaurants using it counted by
+// countQuery. It returns pgx.ErrNoRows if the row does not exist.
+func lockForDelete(ctx context.Context, tx pgx.Tx, table string, id int, countQuery string) (int, error) {
+	if err := tx.QueryRow(ctx, "SELECT id FROM "+table+" WHERE id = $1 FOR UPDATE", id).Scan(&id); err != nil {
+		return 0, err
+	}
+	var count int
+	err := tx.QueryRow(ctx, countQuery, id).Scan(&count)
+	return count, err
+}
@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/nomdb/backend/internal/cache"
+	"github.com/nomdb/backend/internal/models"
+)
+
+// withMemoryCache enables the in-process response cache for a test
+func withMemoryCache(t *testing.T) {
+	t.Helper()
+	if err := cache.Init(cache.Config{Backend: cache.BackendMemory, TTL: time.Minute}); err != nil {
+		t.Fatalf("Failed to initialize cache: %v", err)
+	}
+	t.Cleanup(func() { cache.Init(cache.Config{Backend: cache.BackendNone}) })
+}
+
+func TestGetCategories_Cached(t *testing.T) {
+	withMemoryCache(t)
+	// Served from the cache without touching the (uninitialized) database
+	cache.SetJSON(context.Background(), cache.KeyCategories, []models.Category{{ID: 1, Name: "Italian"}})
+
+	rec := httptest.NewRecorder()
+	GetCategories(rec, httptest.NewRequest(http.MethodGet, "/api/categories", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var categories []models.Category
+	if err := json.Unmarshal(rec.Body.Bytes(), &categories); err != nil || len(categories) != 1 || categories[0].Name != "Italian" {
+		t.Errorf("Expected the cached categories, got %s (%v)", rec.Body.String(), err)
+	}
+}
+
+func TestCategoryTree(t *testing.T) {
+	asian, japanese, missing := 1, 2, 99
+	categories := []models.Category{
+		{ID: 1, Name: "Asian"},
+		{ID: 3, Name: "Italian"},
+		{ID: 2, Name: "Japanese", ParentID: &asian},
+		{ID: 4, Name: "Korean", ParentID: &asian},
+		{ID: 5, Name: "Ramen", ParentID: &japanese},
+		{ID: 6, Name: "Orphan", ParentID: &missing},
+	}
+
+	tree := categoryTree(categories)
+	if len(tree) != 3 || tree[0].Name != "Asian" || tree[1].Name != "Italian" || tree[2].Name != "Orphan" {
+		t.Fatalf("Expected Asian, Italian and the orphan at the top level, got %+v", tree)
+	}
+	children := tree[0].Children
+	if len(children) != 2 || children[0].Name != "Japanese" || children[1].Name != "Korean" {
+		t.Fatalf("Expected Japanese and Korean under Asian, got %+v", children)
+	}
+	if len(children[0].Children) != 1 || children[0].Children[0].Name != "Ramen" {
+		t.Errorf("Expected Ramen under Japanese, got %+v", children[0].Children)
+	}
+	if tree[1].Children == nil {
+		t.Error("Expected leaves to have an empty list of children")
+	}
+}
+
+func TestGetCategoryTree_Cached(t *testing.T) {
+	withMemoryCache(t)
+	asian := 1
+	cache.SetJSON(context.Background(), cache.KeyCategories, []models.Category{
+		{ID: 1, Name: "Asian"}, {ID: 2, Name: "Japanese", ParentID: &asian},
+	})
+
+	rec := httptest.NewRecorder()
+	GetCategoryTree(rec, httptest.NewRequest(http.MethodGet, "/api/categories/tree", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var tree []models.CategoryNode
+	if err := json.Unmarshal(rec.Body.Bytes(), &tree); err != nil || len(tree) != 1 || len(tree[0].Children) != 1 {
+		t.Errorf("Expected Japanese nested under Asian, got %s (%v)", rec.Body.String(), err)
+	}
+}
+
+func TestDeleteCategory_InvalidReassignTo(t *testing.T) {
+	for _, value := range []string{"abc", "4"} {
+		req := httptest.NewRequest(http.MethodDelete, "/api/categories/4?reassign_to="+value, nil)
+		req = mux.SetURLVars(req, map[string]string{"id": "4"})
+		rec := httptest.NewRecorder()
+		DeleteCategory(rec, req)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("reassign_to=%s: expected 400, got %d", value, rec.Code)
+		}
+	}
+}
+
+func TestValidAppearance(t *testing.T) {
+	str := func(s string) *string { return &s }
+	tests := []struct {
+		name        string
+		icon, color *string
+		wantOK      bool
+		wantColor   string
+	}{
+		{"omitted", nil, nil, true, ""},
+		{"emoji and color", str("🍣"), str(" #E4572E "), true, "#e4572e"},
+		{"joined emoji", str("👨‍🍳"), nil, true, ""},
+		{"icon name", str("ramen-bowl"), nil, true, ""},
+		{"removed", str(""), str(""), true, ""},
+		{"icon too long", str(strings.Repeat("x", maxIconLength+1)), nil, false, ""},
+		{"control characters", str("a\nb"), nil, false, ""},
+		{"short color", nil, str("#fff"), false, ""},
+		{"color name", nil, str("red"), false, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			if ok := validAppearance(rec, tt.icon, tt.color); ok != tt.wantOK {
+				t.Fatalf("Expected valid=%v, got %v: %s", tt.wantOK, ok, rec.Body.String())
+			}
+			if tt.wantColor != "" && *tt.color != tt.wantColor {
+				t.Errorf("Expected color %s, got %s", tt.wantColor, *tt.color)
+			}
+		})
+	}
+}
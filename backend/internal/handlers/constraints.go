@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	apperrors "github.com/nomdb/backend/internal/errors"
+	"github.com/nomdb/backend/internal/logger"
+)
+
+// PostgreSQL error codes of constraint violations caused by invalid input
+const (
+	pgNumericOutOfRange   = "22003"
+	pgForeignKeyViolation = "23503"
+	pgCheckViolation      = "23514"
+)
+
+// writeConstraintViolation responds with 400 if err is a check, foreign key or numeric
+// range violation, so invalid input the handlers do not validate themselves is reported
+// as a client error instead of a 500. It reports whether a response was written.
+func writeConstraintViolation(w http.ResponseWriter, err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+
+	var msg string
+	switch pgErr.Code {
+	case pgCheckViolation:
+		msg = constraintMessage(pgErr.ConstraintName)
+	case pgNumericOutOfRange:
+		// Does not name the column; coordinates are the only bounded numeric input
+		msg = "Value is out of range"
+	case pgForeignKeyViolation:
+		switch {
+		case strings.Contains(pgErr.ConstraintName, "category"):
+			msg = "Category not found"
+		case strings.Contains(pgErr.ConstraintName, "food_type"):
+			msg = "Food type not found"
+		case strings.Contains(pgErr.ConstraintName, "restaurant_id"):
+			msg = "Restaurant not found"
+		default:
+			msg = "Referenced resource not found"
+		}
+	default:
+		return false
+	}
+
+	logger.Warn("Constraint violation %s (%s): %s", pgErr.Code, pgErr.ConstraintName, pgErr.Message)
+	apperrors.Error(w, msg, http.StatusBadRequest)
+	return true
+}
+
+// constraintMessage describes a violated check constraint
+func constraintMessage(constraint string) string {
+	switch {
+	case strings.Contains(constraint, "latitude"):
+		return "Latitude must be between -90 and 90"
+	case strings.Contains(constraint, "longitude"):
+		return "Longitude must be between -180 and 180"
+	case strings.Contains(constraint, "rating"):
+		return "Ratings must be between 1 and 5"
+	}
+	return "Invalid value"
+}
@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestWriteConstraintViolation(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		handled bool
+		message string
+	}{
+		{"latitude check", &pgconn.PgError{Code: "23514", ConstraintName: "restaurants_latitude_range"}, true, "Latitude must be between -90 and 90"},
+		{"longitude check", &pgconn.PgError{Code: "23514", ConstraintName: "restaurant_suggestions_longitude_range"}, true, "Longitude must be between -180 and 180"},
+		{"rating check", &pgconn.PgError{Code: "23514", ConstraintName: "ratings_food_rating_check"}, true, "Ratings must be between 1 and 5"},
+		{"numeric overflow", &pgconn.PgError{Code: "22003"}, true, "Value is out of range"},
+		{"unknown category", &pgconn.PgError{Code: "23503", ConstraintName: "restaurants_category_id_fkey"}, true, "Category not found"},
+		{"unknown food type", &pgconn.PgError{Code: "23503", ConstraintName: "restaurant_food_types_food_type_id_fkey"}, true, "Food type not found"},
+		{"wrapped", fmt.Errorf("insert: %w", &pgconn.PgError{Code: "23514", ConstraintName: "ratings_service_rating_check"}), true, "Ratings must be between 1 and 5"},
+		{"unique violation", &pgconn.PgError{Code: "23505"}, false, ""},
+		{"other error", fmt.Errorf("connection reset"), false, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			if got := writeConstraintViolation(rec, tt.err); got != tt.handled {
+				t.Fatalf("Expected handled=%v, got %v", tt.handled, got)
+			}
+			if !tt.handled {
+				return
+			}
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("Expected 400, got %d", rec.Code)
+			}
+			if !strings.Contains(rec.Body.String(), tt.message) {
+				t.Errorf("Expected %q in the response, got %s", tt.message, rec.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package handlers
+
+import (
+	"expvar"
+	"net/http"
+	"net/http/pprof"
+)
+
+// DebugHandler serves the runtime diagnostics of net/http/pprof under
+// /api/debug/pprof/ and the expvar variables (memory statistics, command line) under
+// /api/debug/vars. It must only be mounted behind admin authentication: profiles
+// expose the process's internals.
+func DebugHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile) // Extends the write deadline itself
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.Handle("/debug/vars", expvar.Handler())
+
+	// pprof.Index resolves profile names relative to /debug/pprof/
+	return http.StripPrefix("/api", mux)
+}
@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDebugHandler(t *testing.T) {
+	handler := DebugHandler()
+
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{"/api/debug/pprof/", "goroutine"},
+		{"/api/debug/pprof/goroutine?debug=1", "goroutine profile:"},
+		{"/api/debug/pprof/cmdline", ""},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest("GET", tt.path, nil))
+
+		if rec.Code != http.StatusOK {
+			t.Errorf("%s: expected status 200, got %d", tt.path, rec.Code)
+		}
+		if !strings.Contains(rec.Body.String(), tt.expected) {
+			t.Errorf("%s: expected body to contain %q", tt.path, tt.expected)
+		}
+	}
+}
+
+func TestDebugHandler_Vars(t *testing.T) {
+	rec := httptest.NewRecorder()
+	DebugHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/api/debug/vars", nil))
+
+	var vars map[string]json.RawMessage
+	if err := json.NewDecoder(rec.Body).Decode(&vars); err != nil {
+		t.Fatalf("Failed to decode expvar response: %v", err)
+	}
+	if _, ok := vars["memstats"]; !ok {
+		t.Error("Expected memstats in expvar response")
+	}
+}
@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	apperrors "github.com/nomdb/backend/internal/errors"
+	"github.com/nomdb/backend/internal/logger"
+	"github.com/nomdb/backend/internal/models"
+	"github.com/nomdb/backend/internal/repository"
+	"github.com/nomdb/backend/internal/services"
+)
+
+// enrichmentProvider looks up review site data of restaurants; nil unless configured
+var enrichmentProvider services.EnrichmentProvider
+
+// InitEnrichmentProvider enables review site lookups with p
+func InitEnrichmentProvider(p services.EnrichmentProvider) {
+	enrichmentProvider = p
+	logger.Info("✅ Restaurant enrichment via %s", p.Name())
+}
+
+// enrichmentQuery identifies a restaurant to a review site by its name and coordinates,
+// or its address without them. ok is false when it has neither.
+func enrichmentQuery(rest *models.Restaurant) (services.EnrichmentQuery, bool) {
+	query := services.EnrichmentQuery{Name: rest.Name}
+	switch {
+	case rest.Latitude != nil && rest.Longitude != nil:
+		query.Latitude, query.Longitude = rest.Latitude, rest.Longitude
+	case rest.Address != nil && *rest.Address != "":
+		query.Address = *rest.Address
+	default:
+		return query, false
+	}
+	return query, true
+}
+
+// @Summary Fetch review site data of a restaurant
+// @Description Look up the restaurant on the configured review site (ENRICHMENT_PROVIDER, Yelp or Foursquare) by its name near its location, and store the rating, price level and categories found, replacing earlier data of that site. The data is shown in external_sources of the restaurant detail. Admin only.
+// @Tags Restaurants
+// @Produce json
+// @Param id path int true "Restaurant ID"
+// @Success 200 {object} models.ExternalSource "Stored review site data"
+// @Failure 400 {object} errors.ErrorResponse "Invalid restaurant ID"
+// @Failure 404 {object} errors.ErrorResponse "Restaurant not found or no matching business"
+// @Failure 422 {object} errors.ErrorResponse "Restaurant has no coordinates or address"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Failure 503 {object} errors.ErrorResponse "No review site configured"
+// @Security BearerAuth
+// @Router /admin/restaurants/{id}/external-sources [post]
+func FetchExternalSource(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		apperrors.Error(w, "Invalid restaurant ID", http.StatusBadRequest)
+		return
+	}
+	if enrichmentProvider == nil {
+		apperrors.Error(w, "No review site is configured", http.StatusServiceUnavailable)
+		return
+	}
+
+	ctx := r.Context()
+	rest, err := restaurantRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			apperrors.Error(w, "Restaurant not found", http.StatusNotFound)
+			return
+		}
+		apperrors.Internal(w, err)
+		return
+	}
+	query, ok := enrichmentQuery(rest)
+	if !ok {
+		apperrors.Error(w, "Restaurant has no coordinates or address to look it up by", http.StatusUnprocessableEntity)
+		return
+	}
+
+	source, err := enrichmentProvider.Lookup(ctx, query)
+	if errors.Is(err, services.ErrNoExternalMatch) {
+		apperrors.Error(w, "No matching business found on "+enrichmentProvider.Name(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		apperrors.Error(w, "Failed to look up the restaurant on "+enrichmentProvider.Name(), http.StatusBadGateway)
+		return
+	}
+	if err := repos.ExternalSources.Upsert(ctx, id, source); err != nil {
+		apperrors.Internal(w, err)
+		return
+	}
+
+	recordAdminAction(ctx, r, "fetch_external_source", "restaurant", id, map[string]any{
+		"provider":    source.Provider,
+		"external_id": source.ExternalID,
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(source)
+}
+
+// @Summary Remove review site data of a restaurant
+// @Description Remove the data of a review site from a restaurant, e.g. after a wrong match. Admin only.
+// @Tags Restaurants
+// @Param id path int true "Restaurant ID"
+// @Param provider path string true "Review site (yelp, foursquare)"
+// @Success 204 "Removed"
+// @Failure 400 {object} errors.ErrorResponse "Invalid restaurant ID"
+// @Failure 404 {object} errors.ErrorResponse "No data of the review site"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /admin/restaurants/{id}/external-sources/{provider} [delete]
+func DeleteExternalSource(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		apperrors.Error(w, "Invalid restaurant ID", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	if err := repos.ExternalSources.Delete(ctx, id, vars["provider"]); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			apperrors.Error(w, "No data of this review site", http.StatusNotFound)
+			return
+		}
+		apperrors.Internal(w, err)
+		return
+	}
+
+	recordAdminAction(ctx, r, "delete_external_source", "restaurant", id, map[string]any{"provider": vars["provider"]})
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/nomdb/backend/internal/models"
+	"github.com/nomdb/backend/internal/services"
+)
+
+func TestEnrichmentQuery(t *testing.T) {
+	lat, lng, address, empty := 52.5, 13.4, "Main St 1", ""
+
+	query, ok := enrichmentQuery(&models.Restaurant{Name: "Luigi's", Latitude: &lat, Longitude: &lng, Address: &address})
+	if !ok || query.Latitude == nil || query.Address != "" {
+		t.Errorf("Expected coordinates, got %+v", query)
+	}
+	query, ok = enrichmentQuery(&models.Restaurant{Name: "Luigi's", Address: &address})
+	if !ok || query.Latitude != nil || query.Address != address {
+		t.Errorf("Expected the address, got %+v", query)
+	}
+	if _, ok := enrichmentQuery(&models.Restaurant{Name: "Luigi's", Address: &empty}); ok {
+		t.Error("Expected restaurants without location to be rejected")
+	}
+}
+
+func fetchExternalSourceRequest() *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/api/admin/restaurants/3/external-sources", nil)
+	return mux.SetURLVars(req, map[string]string{"id": "3"})
+}
+
+func TestFetchExternalSource_NotConfigured(t *testing.T) {
+	rec := httptest.NewRecorder()
+	FetchExternalSource(rec, fetchExternalSourceRequest())
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected 503, got %d", rec.Code)
+	}
+}
+
+func TestFetchExternalSource_NoLocation(t *testing.T) {
+	mock := withMockRepositories(t)
+	enrichmentProvider = services.NewYelpProvider("key", time.Second)
+	t.Cleanup(func() { enrichmentProvider = nil })
+	expectRestaurantLocation(mock, nil, nil)
+
+	// Looking up a name alone would match businesses anywhere
+	rec := httptest.NewRecorder()
+	FetchExternalSource(rec, fetchExternalSourceRequest())
+	if rec.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected 422, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
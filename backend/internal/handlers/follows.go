@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	apperrors "github.com/nomdb/backend/internal/errors"
+	"github.com/nomdb/backend/internal/models"
+	"github.com/nomdb/backend/internal/repository"
+)
+
+// followTarget parses the {id} of a user to follow or unfollow, writing an error if it is
+// invalid or the current user
+func followTarget(w http.ResponseWriter, r *http.Request) (*models.User, int, bool) {
+	user, ok := GetUserFromContext(r)
+	if !ok {
+		apperrors.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return nil, 0, false
+	}
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		apperrors.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return nil, 0, false
+	}
+	if id == user.ID {
+		apperrors.Error(w, "You cannot follow yourself", http.StatusBadRequest)
+		return nil, 0, false
+	}
+	return user, id, true
+}
+
+// @Summary Follow a user
+// @Description Follow a user, to limit the activity feed to the ratings and suggestions of the users you follow. Following a user again has no effect.
+// @Tags Users
+// @Param id path int true "User ID"
+// @Success 204 "Following the user"
+// @Failure 400 {object} errors.ErrorResponse "Invalid user ID or the current user"
+// @Failure 404 {object} errors.ErrorResponse "User not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /users/{id}/follow [post]
+func FollowUser(w http.ResponseWriter, r *http.Request) {
+	user, id, ok := followTarget(w, r)
+	if !ok {
+		return
+	}
+
+	if err := repos.Follows.Follow(r.Context(), user.ID, id); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			apperrors.Error(w, "User not found", http.StatusNotFound)
+			return
+		}
+		apperrors.Internal(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Unfollow a user
+// @Description Stop following a user.
+// @Tags Users
+// @Param id path int true "User ID"
+// @Success 204 "No longer following the user"
+// @Failure 400 {object} errors.ErrorResponse "Invalid user ID"
+// @Failure 404 {object} errors.ErrorResponse "Not following the user"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /users/{id}/follow [delete]
+func UnfollowUser(w http.ResponseWriter, r *http.Request) {
+	user, id, ok := followTarget(w, r)
+	if !ok {
+		return
+	}
+
+	if err := repos.Follows.Unfollow(r.Context(), user.ID, id); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			apperrors.Error(w, "Not following this user", http.StatusNotFound)
+			return
+		}
+		apperrors.Internal(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary List followed users
+// @Description List the users the current user follows, by username.
+// @Tags Auth
+// @Produce json
+// @Success 200 {array} models.FollowedUser
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /auth/me/following [get]
+func GetFollowing(w http.ResponseWriter, r *http.Request) {
+	user, ok := GetUserFromContext(r)
+	if !ok {
+		apperrors.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	users, err := repos.Follows.Following(r.Context(), user.ID)
+	if err != nil {
+		apperrors.Internal(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(users)
+}
+
+// @Summary Get the activity feed
+// @Description Recent ratings and suggestions in the space, newest first, optionally only those of the users the current user follows. For the next page, pass the created_at of the last item as before.
+// @Tags Users
+// @Produce json
+// @Param following query bool false "Only activity of followed users"
+// @Param before query string false "Only activity created before this time (RFC 3339)"
+// @Param limit query int false "Number of items (default 20, max 100)"
+// @Success 200 {array} models.ActivityItem
+// @Failure 400 {object} errors.ErrorResponse "Invalid parameter"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /feed [get]
+func GetFeed(w http.ResponseWriter, r *http.Request) {
+	user, ok := GetUserFromContext(r)
+	if !ok {
+		apperrors.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	query := r.URL.Query()
+	filter := repository.ActivityFilter{
+		SpaceID: spaceID(r),
+		Limit:   ParsePaginationParams(r).Limit,
+	}
+	if value := query.Get("following"); value != "" {
+		following, err := strconv.ParseBool(value)
+		if err != nil {
+			apperrors.Error(w, "Invalid following. Must be true or false", http.StatusBadRequest)
+			return
+		}
+		if following {
+			filter.FollowerID = user.ID
+		}
+	}
+	if value := query.Get("before"); value != "" {
+		before, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			apperrors.Error(w, "Invalid before. Must be an RFC 3339 time", http.StatusBadRequest)
+			return
+		}
+		filter.Before = &before
+	}
+
+	items, err := repos.Follows.Feed(r.Context(), filter)
+	if err != nil {
+		apperrors.Internal(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(items)
+}
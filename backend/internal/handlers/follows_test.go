@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/nomdb/backend/internal/models"
+	"github.com/nomdb/backend/internal/repository"
+	"github.com/pashagolub/pgxmock/v4"
+)
+
+func TestFollowUser_Self(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/api/users/7/follow", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "7"})
+	req = req.WithContext(context.WithValue(req.Context(), models.UserContextKey, &models.User{ID: 7}))
+
+	rec := httptest.NewRecorder()
+	FollowUser(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400, got %d", rec.Code)
+	}
+}
+
+func feedRequest(query string) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, "/api/feed"+query, nil)
+	return req.WithContext(context.WithValue(req.Context(), models.UserContextKey, &models.User{ID: 7}))
+}
+
+func TestGetFeed(t *testing.T) {
+	t.Run("followed users", func(t *testing.T) {
+		mock := withMockRepositories(t)
+		restaurantID := 3
+		mock.ExpectQuery(`FROM user_follows`).WithArgs(repository.DefaultSpaceID, 7, (*time.Time)(nil), 5).
+			WillReturnRows(pgxmock.NewRows([]string{"type", "id", "user_id", "username", "restaurant_id", "name", "score", "comment", "status", "created_at"}).
+				AddRow("rating", 5, 8, "anna", &restaurantID, "Luigi's", nil, nil, nil, time.Now()))
+
+		rec := httptest.NewRecorder()
+		GetFeed(rec, feedRequest("?following=true&limit=5"))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		var items []models.ActivityItem
+		json.NewDecoder(rec.Body).Decode(&items)
+		if len(items) != 1 || items[0].Username != "anna" {
+			t.Errorf("Unexpected feed %+v", items)
+		}
+	})
+
+	for _, query := range []string{"?following=maybe", "?before=yesterday"} {
+		rec := httptest.NewRecorder()
+		GetFeed(rec, feedRequest(query))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected 400 for %s, got %d", query, rec.Code)
+		}
+	}
+}
@@ -3,44 +3,86 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"slices"
 	"strconv"
 
 	"github.com/gorilla/mux"
+	"github.com/jackc/pgx/v5"
+	"github.com/nomdb/backend/internal/cache"
 	"github.com/nomdb/backend/internal/database"
+	apperrors "github.com/nomdb/backend/internal/errors"
+	"github.com/nomdb/backend/internal/logger"
 	"github.com/nomdb/backend/internal/models"
 )
 
 // GetFoodTypes godoc
 // @Summary List all food types
-// @Description Get a list of all food types ordered by name
+// @Description Get a list of all food types ordered by name, with the number of restaurants serving each. Cacheable for HTTP_CACHE_MAX_AGE; revalidate with If-None-Match or If-Modified-Since to get 304 Not Modified while unchanged.
 // @Tags Food Types
 // @Accept json
 // @Produce json
 // @Success 200 {array} models.FoodType "List of food types"
-// @Failure 500 {object} map[string]string "Internal server error"
+// @Success 304 "Not modified"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
 // @Router /food-types [get]
 func GetFoodTypes(w http.ResponseWriter, r *http.Request) {
-	rows, err := database.GetPool().Query(context.Background(),
-		"SELECT id, name, created_at, updated_at FROM food_types ORDER BY name")
+	foodTypes, err := cachedFoodTypes(r.Context())
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		apperrors.Internal(w, err)
 		return
 	}
+	localizeFoodTypes(r.Context(), foodTypes)
+
+	writeCacheableJSON(w, r, foodTypes, cache.KeyFoodTypes)
+}
+
+// cachedFoodTypes returns all food types sorted by name, from the cache if possible
+func cachedFoodTypes(ctx context.Context) ([]models.FoodType, error) {
+	var foodTypes []models.FoodType
+	if cache.GetJSON(ctx, cache.KeyFoodTypes, &foodTypes) {
+		return foodTypes, nil
+	}
+	foodTypes, err := queryFoodTypes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	cache.SetJSON(ctx, cache.KeyFoodTypes, foodTypes)
+	return foodTypes, nil
+}
+
+// foodTypeColumns are the columns of a food type scanned by foodTypeFields
+const foodTypeColumns = "id, name, icon, color, created_at, updated_at"
+
+func foodTypeFields(ft *models.FoodType) []any {
+	return []any{&ft.ID, &ft.Name, &ft.Icon, &ft.Color, &ft.CreatedAt, &ft.UpdatedAt}
+}
+
+func queryFoodTypes(ctx context.Context) ([]models.FoodType, error) {
+	rows, err := database.GetPool().Query(ctx,
+		`SELECT f.id, f.name, f.icon, f.color, f.created_at, f.updated_at, COUNT(rft.restaurant_id)
+		FROM food_types f
+		LEFT JOIN restaurant_food_types rft ON rft.food_type_id = f.id
+		GROUP BY f.id
+		ORDER BY f.name`)
+	if err != nil {
+		return nil, err
+	}
 	defer rows.Close()
 
 	foodTypes := []models.FoodType{}
 	for rows.Next() {
 		var ft models.FoodType
-		if err := rows.Scan(&ft.ID, &ft.Name, &ft.CreatedAt, &ft.UpdatedAt); err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
+		var count int
+		if err := rows.Scan(append(foodTypeFields(&ft), &count)...); err != nil {
+			return nil, err
 		}
+		ft.RestaurantCount = &count
 		foodTypes = append(foodTypes, ft)
 	}
-
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(foodTypes)
+	return foodTypes, rows.Err()
 }
 
 // GetFoodType godoc
@@ -51,106 +93,141 @@ func GetFoodTypes(w http.ResponseWriter, r *http.Request) {
 // @Produce json
 // @Param id path int true "Food Type ID"
 // @Success 200 {object} models.FoodType "Food type details"
-// @Failure 400 {object} map[string]string "Invalid food type ID"
-// @Failure 404 {object} map[string]string "Food type not found"
+// @Failure 400 {object} errors.ErrorResponse "Invalid food type ID"
+// @Failure 404 {object} errors.ErrorResponse "Food type not found"
 // @Router /food-types/{id} [get]
 func GetFoodType(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id, err := strconv.Atoi(vars["id"])
 	if err != nil {
-		http.Error(w, "Invalid food type ID", http.StatusBadRequest)
+		apperrors.Error(w, "Invalid food type ID", http.StatusBadRequest)
 		return
 	}
 
 	var ft models.FoodType
-	err = database.GetPool().QueryRow(context.Background(),
-		"SELECT id, name, created_at, updated_at FROM food_types WHERE id = $1", id).
-		Scan(&ft.ID, &ft.Name, &ft.CreatedAt, &ft.UpdatedAt)
+	err = database.GetPool().QueryRow(r.Context(),
+		"SELECT "+foodTypeColumns+" FROM food_types WHERE id = $1", id).Scan(foodTypeFields(&ft)...)
 	if err != nil {
-		http.Error(w, "Food type not found", http.StatusNotFound)
+		apperrors.Error(w, "Food type not found", http.StatusNotFound)
 		return
 	}
+	foodTypes := []models.FoodType{ft}
+	translate(translatedNames(r.Context()), nil, foodTypes)
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(ft)
+	json.NewEncoder(w).Encode(foodTypes[0])
 }
 
 // CreateFoodType godoc
 // @Summary Create a new food type
-// @Description Create a new food type with the provided name
+// @Description Create a new food type with the provided name, which must differ from the names of the other food types ignoring case, and an optional icon (an emoji or a name of up to 32 characters) and hex color
 // @Tags Food Types
 // @Accept json
 // @Produce json
 // @Param foodType body models.CreateFoodTypeRequest true "Food type creation request"
 // @Success 201 {object} models.FoodType "Created food type"
-// @Failure 400 {object} map[string]string "Invalid request body or name is required"
-// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 400 {object} errors.ErrorResponse "Invalid request body, name is required or invalid icon or color"
+// @Failure 409 {object} errors.ErrorResponse "A food type with this name exists"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
 // @Router /food-types [post]
 func CreateFoodType(w http.ResponseWriter, r *http.Request) {
 	var req models.CreateFoodTypeRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		apperrors.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
 
 	if req.Name == "" {
-		http.Error(w, "Name is required", http.StatusBadRequest)
+		apperrors.Error(w, "Name is required", http.StatusBadRequest)
+		return
+	}
+	if !validAppearance(w, req.Icon, req.Color) || !checkFoodTypeName(w, r, 0, req.Name) {
 		return
 	}
 
 	var ft models.FoodType
-	err := database.GetPool().QueryRow(context.Background(),
-		"INSERT INTO food_types (name) VALUES ($1) RETURNING id, name, created_at, updated_at",
-		req.Name).Scan(&ft.ID, &ft.Name, &ft.CreatedAt, &ft.UpdatedAt)
+	err := database.GetPool().QueryRow(r.Context(),
+		`INSERT INTO food_types (name, icon, color) VALUES ($1, NULLIF($2, ''), NULLIF($3, ''))
+		RETURNING `+foodTypeColumns,
+		req.Name, req.Icon, req.Color).Scan(foodTypeFields(&ft)...)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		apperrors.Internal(w, err)
 		return
 	}
+	cache.Invalidate(r.Context(), cache.KeyFoodTypes)
+	publishChange(r.Context(), eventFoodTypeCreated, 0, 0, ft.ID)
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(ft)
 }
 
+// checkFoodTypeName responds with 409 if a food type other than id has name, ignoring
+// case, so "Sushi" and "sushi" do not become two food types. It reports whether the
+// name is free.
+func checkFoodTypeName(w http.ResponseWriter, r *http.Request, id int, name string) bool {
+	var taken bool
+	err := database.GetPool().QueryRow(r.Context(),
+		"SELECT EXISTS (SELECT 1 FROM food_types WHERE LOWER(name) = LOWER($1) AND id <> $2)", name, id).Scan(&taken)
+	if err != nil {
+		apperrors.Internal(w, err)
+		return false
+	}
+	if taken {
+		apperrors.Error(w, "A food type with this name already exists", http.StatusConflict)
+		return false
+	}
+	return true
+}
+
 // UpdateFoodType godoc
 // @Summary Update a food type
-// @Description Update an existing food type's name
+// @Description Update an existing food type's name, which must differ from the names of the other food types ignoring case, and its icon and color if given; an empty icon or color removes it
 // @Tags Food Types
 // @Accept json
 // @Produce json
 // @Param id path int true "Food Type ID"
 // @Param foodType body models.CreateFoodTypeRequest true "Food type update request"
 // @Success 200 {object} models.FoodType "Updated food type"
-// @Failure 400 {object} map[string]string "Invalid request or name is required"
-// @Failure 404 {object} map[string]string "Food type not found"
+// @Failure 400 {object} errors.ErrorResponse "Invalid request, name is required or invalid icon or color"
+// @Failure 404 {object} errors.ErrorResponse "Food type not found"
+// @Failure 409 {object} errors.ErrorResponse "Another food type has this name"
 // @Router /food-types/{id} [put]
 func UpdateFoodType(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id, err := strconv.Atoi(vars["id"])
 	if err != nil {
-		http.Error(w, "Invalid food type ID", http.StatusBadRequest)
+		apperrors.Error(w, "Invalid food type ID", http.StatusBadRequest)
 		return
 	}
 
 	var req models.CreateFoodTypeRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		apperrors.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
 
 	if req.Name == "" {
-		http.Error(w, "Name is required", http.StatusBadRequest)
+		apperrors.Error(w, "Name is required", http.StatusBadRequest)
+		return
+	}
+	if !validAppearance(w, req.Icon, req.Color) || !checkFoodTypeName(w, r, id, req.Name) {
 		return
 	}
 
 	var ft models.FoodType
-	err = database.GetPool().QueryRow(context.Background(),
-		"UPDATE food_types SET name = $1, updated_at = NOW() WHERE id = $2 RETURNING id, name, created_at, updated_at",
-		req.Name, id).Scan(&ft.ID, &ft.Name, &ft.CreatedAt, &ft.UpdatedAt)
+	err = database.GetPool().QueryRow(r.Context(),
+		`UPDATE food_types SET name = $1, icon = `+keepOrClear("icon", "$3")+`, color = `+keepOrClear("color", "$4")+`,
+			updated_at = NOW()
+		WHERE id = $2 RETURNING `+foodTypeColumns,
+		req.Name, id, req.Icon, req.Color).Scan(foodTypeFields(&ft)...)
 	if err != nil {
-		http.Error(w, "Food type not found", http.StatusNotFound)
+		apperrors.Error(w, "Food type not found", http.StatusNotFound)
 		return
 	}
+	// Restaurants in the list embed their food types
+	cache.Invalidate(r.Context(), cache.KeyFoodTypes, cache.KeyRestaurants)
+	publishChange(r.Context(), eventFoodTypeUpdated, 0, 0, ft.ID)
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(ft)
@@ -158,35 +235,193 @@ func UpdateFoodType(w http.ResponseWriter, r *http.Request) {
 
 // DeleteFoodType godoc
 // @Summary Delete a food type
-// @Description Delete a food type by ID
+// @Description Delete a food type by ID. Food types served by restaurants cannot be deleted. Admin only.
 // @Tags Food Types
 // @Accept json
 // @Produce json
 // @Param id path int true "Food Type ID"
 // @Success 204 "Food type deleted successfully"
-// @Failure 400 {object} map[string]string "Invalid food type ID"
-// @Failure 404 {object} map[string]string "Food type not found"
-// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 400 {object} errors.ErrorResponse "Invalid food type ID"
+// @Failure 403 {object} errors.ErrorResponse "Admin access required"
+// @Failure 404 {object} errors.ErrorResponse "Food type not found"
+// @Failure 409 {object} errors.ErrorResponse "Food type still served by restaurants"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
 // @Router /food-types/{id} [delete]
 func DeleteFoodType(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id, err := strconv.Atoi(vars["id"])
 	if err != nil {
-		http.Error(w, "Invalid food type ID", http.StatusBadRequest)
+		apperrors.Error(w, "Invalid food type ID", http.StatusBadRequest)
 		return
 	}
 
-	result, err := database.GetPool().Exec(context.Background(),
-		"DELETE FROM food_types WHERE id = $1", id)
+	ctx := r.Context()
+	tx, err := database.GetPool().Begin(ctx)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		apperrors.Internal(w, err)
+		return
+	}
+	defer tx.Rollback(ctx)
+
+	// Food types in use are kept rather than silently removed from their restaurants
+	count, err := lockForDelete(ctx, tx, "food_types", id,
+		"SELECT COUNT(*) FROM restaurant_food_types WHERE food_type_id = $1")
+	if errors.Is(err, pgx.ErrNoRows) {
+		apperrors.Error(w, "Food type not found", http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		apperrors.Internal(w, err)
+		return
+	}
+	if count > 0 {
+		apperrors.Error(w, fmt.Sprintf("Food type is served by %d restaurants; remove it from them first", count), http.StatusConflict)
 		return
 	}
 
-	if result.RowsAffected() == 0 {
-		http.Error(w, "Food type not found", http.StatusNotFound)
+	if _, err := tx.Exec(ctx, "DELETE FROM food_types WHERE id = $1", id); err != nil {
+		apperrors.Internal(w, err)
+		return
+	}
+	if err := tx.Commit(ctx); err != nil {
+		apperrors.Internal(w, err)
 		return
 	}
 
+	cache.Invalidate(r.Context(), cache.KeyFoodTypes, cache.KeyRestaurants)
+	publishChange(r.Context(), eventFoodTypeDeleted, 0, 0, id)
+	recordAdminAction(r.Context(), r, "delete_food_type", "food_type", id, nil)
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// MergeFoodTypes godoc
+// @Summary Merge duplicate food types
+// @Description Merge duplicate food types, such as "sushi" and "Sushi/Japanese", into the food type id: their restaurants and suggestions get the food type instead, their translations are kept for locales the food type has no name in, and the duplicates are deleted. Admin only.
+// @Tags Food Types
+// @Accept json
+// @Produce json
+// @Param id path int true "Food type to keep"
+// @Param merge body models.MergeFoodTypesRequest true "Food types to merge into it"
+// @Success 200 {object} models.FoodType "The merged food type"
+// @Failure 400 {object} errors.ErrorResponse "Invalid food type ID or no duplicates given"
+// @Failure 403 {object} errors.ErrorResponse "Admin access required"
+// @Failure 404 {object} errors.ErrorResponse "Food type or duplicate not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /food-types/{id}/merge [post]
+func MergeFoodTypes(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		apperrors.Error(w, "Invalid food type ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.MergeFoodTypesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apperrors.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	duplicates := slices.Compact(slices.Sorted(slices.Values(req.DuplicateIDs)))
+	if len(duplicates) == 0 {
+		apperrors.Error(w, "duplicate_ids is required", http.StatusBadRequest)
+		return
+	}
+	if slices.Contains(duplicates, id) {
+		apperrors.Error(w, "A food type cannot be merged into itself", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	tx, err := database.GetPool().Begin(ctx)
+	if err != nil {
+		apperrors.Internal(w, err)
+		return
+	}
+	defer tx.Rollback(ctx)
+
+	// Lock the food types so that no restaurant starts using a duplicate meanwhile
+	rows, err := tx.Query(ctx, "SELECT id FROM food_types WHERE id = ANY($1) ORDER BY id FOR UPDATE",
+		append([]int{id}, duplicates...))
+	if err != nil {
+		apperrors.Internal(w, err)
+		return
+	}
+	found := map[int]bool{}
+	for rows.Next() {
+		var foundID int
+		if err := rows.Scan(&foundID); err != nil {
+			rows.Close()
+			apperrors.Internal(w, err)
+			return
+		}
+		found[foundID] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		apperrors.Internal(w, err)
+		return
+	}
+	if !found[id] {
+		apperrors.Error(w, "Food type not found", http.StatusNotFound)
+		return
+	}
+	for _, duplicate := range duplicates {
+		if !found[duplicate] {
+			apperrors.Error(w, fmt.Sprintf("Food type %d not found", duplicate), http.StatusNotFound)
+			return
+		}
+	}
+
+	// Places having both the food type and a duplicate keep one row
+	for _, query := range []string{
+		`INSERT INTO restaurant_food_types (restaurant_id, food_type_id)
+		SELECT DISTINCT restaurant_id, $1 FROM restaurant_food_types WHERE food_type_id = ANY($2)
+		ON CONFLICT DO NOTHING`,
+		`INSERT INTO suggestion_food_types (suggestion_id, food_type_id)
+		SELECT DISTINCT suggestion_id, $1 FROM suggestion_food_types WHERE food_type_id = ANY($2)
+		ON CONFLICT DO NOTHING`,
+		`INSERT INTO food_type_translations (food_type_id, locale, name, updated_at)
+		SELECT DISTINCT ON (locale) $1, locale, name, updated_at FROM food_type_translations
+		WHERE food_type_id = ANY($2) ORDER BY locale, updated_at DESC
+		ON CONFLICT DO NOTHING`,
+	} {
+		if _, err := tx.Exec(ctx, query, id, duplicates); err != nil {
+			apperrors.Internal(w, err)
+			return
+		}
+	}
+	// The links and translations of the duplicates cascade
+	if _, err := tx.Exec(ctx, "DELETE FROM food_types WHERE id = ANY($1)", duplicates); err != nil {
+		apperrors.Internal(w, err)
+		return
+	}
+
+	var ft models.FoodType
+	var count int
+	err = tx.QueryRow(ctx,
+		`SELECT `+foodTypeColumns+`, (SELECT COUNT(*) FROM restaurant_food_types WHERE food_type_id = f.id)
+		FROM food_types f WHERE id = $1`, id).Scan(append(foodTypeFields(&ft), &count)...)
+	if err != nil {
+		apperrors.Internal(w, err)
+		return
+	}
+	ft.RestaurantCount = &count
+	if err := tx.Commit(ctx); err != nil {
+		apperrors.Internal(w, err)
+		return
+	}
+
+	logger.Info("🔀 Merged food types %v into %d (%s)", duplicates, id, ft.Name)
+	cache.Invalidate(ctx, cache.KeyFoodTypes, cache.KeyRestaurants, cache.KeyTranslations)
+	for _, duplicate := range duplicates {
+		publishChange(ctx, eventFoodTypeDeleted, 0, 0, duplicate)
+	}
+	publishChange(ctx, eventFoodTypeUpdated, 0, 0, id)
+	recordAdminAction(ctx, r, "merge_food_types", "food_type", id, map[string]any{"duplicate_ids": duplicates})
+
+	foodTypes := []models.FoodType{ft}
+	translate(translatedNames(ctx), nil, foodTypes)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(foodTypes[0])
+}
@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+func TestMergeFoodTypes_Validation(t *testing.T) {
+	tests := []struct {
+		name string
+		id   string
+		body string
+	}{
+		{"invalid ID", "abc", `{"duplicate_ids":[2]}`},
+		{"invalid body", "1", `{`},
+		{"no duplicates", "1", `{"duplicate_ids":[]}`},
+		{"merged into itself", "1", `{"duplicate_ids":[2,1]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/food-types/"+tt.id+"/merge", strings.NewReader(tt.body))
+			req = mux.SetURLVars(req, map[string]string{"id": tt.id})
+			rec := httptest.NewRecorder()
+			MergeFoodTypes(rec, req)
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("Expected 400, got %d: %s", rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
@@ -2,61 +2,174 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
+	"math"
 	"net/http"
+	"net/url"
+	"strconv"
+	"time"
 
 	"github.com/gorilla/mux"
+	apperrors "github.com/nomdb/backend/internal/errors"
+	"github.com/nomdb/backend/internal/logger"
 	"github.com/nomdb/backend/internal/services"
 )
 
 var mapsService = services.NewGoogleMapsService()
 
+// placesProvider answers place searches, place details and city geocoding; Google
+// Maps unless another provider is configured. Photos and travel times need Google.
+var placesProvider services.PlacesProvider = mapsService
+
+// InitPlacesProvider replaces Google Maps for place search and geocoding
+func InitPlacesProvider(p services.PlacesProvider) {
+	placesProvider = p
+	logger.Info("✅ Places provider: %s", p.Name())
+}
+
+// InitGoogleMaps configures the API key, timeouts, retries, the circuit breaker and the daily budget
+// of Maps API calls, counted in the database once InitRepositories was called
+func InitGoogleMaps(opts services.GoogleMapsOptions) {
+	if repos != nil {
+		opts.UsageStore = repos.MapsUsage
+	}
+	mapsService.Configure(opts)
+	if opts.APIKey == "" {
+		logger.Warn("⚠️  Google Maps API key not configured - map features will be disabled")
+	} else {
+		logger.Info("🗺️  Google Maps service initialized")
+	}
+	logger.Info("✅ Google Maps client: %s timeout, %d retries, circuit breaker after %d failed calls for %s",
+		opts.Timeout, opts.MaxRetries, opts.BreakerThreshold, opts.BreakerCooldown)
+	if opts.DailyBudget > 0 {
+		logger.Info("💰 Google Maps daily budget: %d calls", opts.DailyBudget)
+	}
+}
+
+// writeMapsError answers 503 with Retry-After while the Maps circuit breaker is open or
+// the daily budget is spent, so clients can fall back to manual entry instead of waiting
+// on Google
+func writeMapsError(w http.ResponseWriter, err error) {
+	if errors.Is(err, services.ErrPlaceNotFound) {
+		apperrors.Error(w, "Place not found", http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, services.ErrMapsUnavailable) {
+		w.Header().Set("Retry-After", strconv.Itoa(max(int(math.Ceil(mapsService.RetryAfter().Seconds())), 1)))
+		apperrors.Error(w, "Google Maps is temporarily unavailable, try again later", http.StatusServiceUnavailable)
+		return
+	}
+	if errors.Is(err, services.ErrMapsBudgetExceeded) {
+		w.Header().Set("Retry-After", strconv.Itoa(max(int(math.Ceil(time.Until(mapsBudget.BudgetResetsAt()).Seconds())), 1)))
+		apperrors.Error(w, "The daily Google Maps budget of this server is used up, try again after midnight UTC", http.StatusServiceUnavailable)
+		return
+	}
+	apperrors.Internal(w, err)
+}
+
+// parsePlaceSearch validates the parameters of a place search, returning a message for
+// the client when they are invalid
+func parsePlaceSearch(params url.Values) (services.PlaceSearch, string) {
+	search := services.PlaceSearch{Query: params.Get("q"), PageToken: params.Get("page_token")}
+	if search.Query == "" {
+		return search, "Query parameter 'q' is required"
+	}
+
+	latParam, lngParam, radiusParam := params.Get("lat"), params.Get("lng"), params.Get("radius")
+	if latParam == "" && lngParam == "" {
+		if radiusParam != "" {
+			return search, "radius requires lat and lng"
+		}
+		return search, ""
+	}
+	lat, err := strconv.ParseFloat(latParam, 64)
+	if err != nil || lat < -90 || lat > 90 {
+		return search, "lat must be between -90 and 90 and is required with lng"
+	}
+	lng, err := strconv.ParseFloat(lngParam, 64)
+	if err != nil || lng < -180 || lng > 180 {
+		return search, "lng must be between -180 and 180 and is required with lat"
+	}
+	radius := float64(nearbyDefaultRadiusKm)
+	if radiusParam != "" {
+		radius, err = strconv.ParseFloat(radiusParam, 64)
+		if err != nil || radius <= 0 || radius > nearbyMaxRadiusKm {
+			return search, "radius must be greater than 0 and at most 50 km"
+		}
+	}
+	search.Latitude, search.Longitude = &lat, &lng
+	search.Radius = max(int(radius*1000), 1)
+	return search, ""
+}
+
 // @Summary Search for places
-// @Description Search for places using Google Maps Places API
+// @Description Search for places using the configured places provider (Google Maps Places API or Nominatim), optionally preferring places near a location. With Google Maps, the token of the next page, if any, is returned in X-Next-Page-Token; send it with the same parameters to continue the search. Google may take a few seconds to make a new token valid.
 // @Tags Google Maps
 // @Accept json
 // @Produce json
 // @Param q query string true "Search query"
+// @Param lat query number false "Latitude to bias results towards (with lng)"
+// @Param lng query number false "Longitude to bias results towards (with lat)"
+// @Param radius query number false "Bias radius in km around lat/lng (default 5, max 50)"
+// @Param page_token query string false "Next page token from X-Next-Page-Token"
 // @Success 200 {array} models.GooglePlaceResult "List of matching places"
-// @Failure 400 {object} map[string]string "Missing query parameter"
-// @Failure 500 {object} map[string]string "Internal server error"
+// @Header 200 {string} X-Next-Page-Token "Token of the next page"
+// @Failure 400 {object} errors.ErrorResponse "Invalid parameters"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Failure 503 {object} errors.ErrorResponse "Google Maps temporarily unavailable"
 // @Router /places/search [get]
 func SearchPlaces(w http.ResponseWriter, r *http.Request) {
-	query := r.URL.Query().Get("q")
-	if query == "" {
-		http.Error(w, "Query parameter 'q' is required", http.StatusBadRequest)
+	search, msg := parsePlaceSearch(r.URL.Query())
+	if msg != "" {
+		apperrors.Error(w, msg, http.StatusBadRequest)
 		return
 	}
 
-	results, err := mapsService.SearchPlaces(query)
+	page, err := placesProvider.SearchPlaces(r.Context(), search)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeMapsError(w, err)
 		return
 	}
 
+	if page.NextPageToken != "" {
+		w.Header().Set("X-Next-Page-Token", page.NextPageToken)
+	}
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(results)
+	json.NewEncoder(w).Encode(page.Results)
 }
 
 // @Summary Geocode cities
-// @Description Geocode city names to get coordinates using Google Maps Geocoding API
+// @Description Geocode city names to get coordinates using the configured places provider. With Google Maps the coordinates take one place details request per city; with coordinates=false only names are returned, with latitude and longitude 0.
 // @Tags Google Maps
 // @Accept json
 // @Produce json
 // @Param q query string true "City name to geocode"
+// @Param coordinates query bool false "Look up the coordinates of each city (default true)"
 // @Success 200 {array} models.GooglePlaceResult "List of geocoded cities"
-// @Failure 400 {object} map[string]string "Missing query parameter"
-// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 400 {object} errors.ErrorResponse "Missing query parameter"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Failure 503 {object} errors.ErrorResponse "Google Maps temporarily unavailable"
 // @Router /geocode/cities [get]
 func GeocodeCities(w http.ResponseWriter, r *http.Request) {
 	query := r.URL.Query().Get("q")
 	if query == "" {
-		http.Error(w, "Query parameter 'q' is required", http.StatusBadRequest)
+		apperrors.Error(w, "Query parameter 'q' is required", http.StatusBadRequest)
 		return
 	}
 
-	results, err := mapsService.GeocodeCities(query)
+	coordinates := true
+	if v := r.URL.Query().Get("coordinates"); v != "" {
+		parsed, err := strconv.ParseBool(v)
+		if err != nil {
+			apperrors.Error(w, "coordinates must be true or false", http.StatusBadRequest)
+			return
+		}
+		coordinates = parsed
+	}
+
+	results, err := placesProvider.GeocodeCities(r.Context(), query, coordinates)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeMapsError(w, err)
 		return
 	}
 
@@ -65,29 +178,66 @@ func GeocodeCities(w http.ResponseWriter, r *http.Request) {
 }
 
 // @Summary Get place details
-// @Description Get detailed information about a place using Google Maps Place Details API
+// @Description Get detailed information about a place using the configured places provider
 // @Tags Google Maps
 // @Accept json
 // @Produce json
-// @Param placeId path string true "Google Place ID"
+// @Param placeId path string true "Place ID of a search result (Google Place ID, or OSM ID such as N123 with Nominatim)"
 // @Success 200 {object} models.GooglePlaceResult "Place details"
-// @Failure 400 {object} map[string]string "Missing place ID"
-// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 400 {object} errors.ErrorResponse "Missing place ID"
+// @Failure 404 {object} errors.ErrorResponse "Place not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Failure 503 {object} errors.ErrorResponse "Google Maps temporarily unavailable"
 // @Router /places/{placeId} [get]
 func GetPlaceDetails(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	placeID := vars["placeId"]
 	if placeID == "" {
-		http.Error(w, "Place ID is required", http.StatusBadRequest)
+		apperrors.Error(w, "Place ID is required", http.StatusBadRequest)
 		return
 	}
 
-	result, err := mapsService.GetPlaceDetails(placeID)
+	result, err := placesProvider.GetPlaceDetails(r.Context(), placeID)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeMapsError(w, err)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(result)
 }
+
+// GetPlacePhoto godoc
+// @Summary Get a place photo
+// @Description Redirect to a short-lived URL of a photo listed in the photos of a place search or place details result. Requires GOOGLE_PLACES_API=new. Show the photo with its attributions.
+// @Tags Google Maps
+// @Param name query string true "Photo name (places/{placeId}/photos/{photoId})"
+// @Param max_width query int false "Maximum width in pixels (default 800, max 4800)"
+// @Success 302 "Redirect to the photo"
+// @Failure 400 {object} errors.ErrorResponse "Invalid photo name or width"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Failure 503 {object} errors.ErrorResponse "Google Maps temporarily unavailable"
+// @Router /places/photo [get]
+func GetPlacePhoto(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("name")
+	if !services.ValidPlacePhotoName(name) {
+		apperrors.Error(w, "Query parameter 'name' must be a place photo name", http.StatusBadRequest)
+		return
+	}
+	maxWidth := 800
+	if v := r.URL.Query().Get("max_width"); v != "" {
+		width, err := strconv.Atoi(v)
+		if err != nil || width < 1 || width > 4800 {
+			apperrors.Error(w, "max_width must be between 1 and 4800", http.StatusBadRequest)
+			return
+		}
+		maxWidth = width
+	}
+
+	photoURL, err := mapsService.PlacePhotoURL(r.Context(), name, maxWidth)
+	if err != nil {
+		writeMapsError(w, err)
+		return
+	}
+	http.Redirect(w, r, photoURL, http.StatusFound)
+}
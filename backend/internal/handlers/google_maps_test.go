@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/nomdb/backend/internal/models"
+	"github.com/nomdb/backend/internal/services"
+)
+
+func TestParsePlaceSearch(t *testing.T) {
+	search, msg := parsePlaceSearch(url.Values{"q": {"pizza"}})
+	if msg != "" || search.Query != "pizza" || search.Latitude != nil {
+		t.Errorf("Unexpected search %+v (%s)", search, msg)
+	}
+
+	search, msg = parsePlaceSearch(url.Values{"q": {"pizza"}, "lat": {"52.5"}, "lng": {"13.4"}, "page_token": {"page-2"}})
+	if msg != "" || *search.Latitude != 52.5 || *search.Longitude != 13.4 || search.Radius != 5000 || search.PageToken != "page-2" {
+		t.Errorf("Unexpected search %+v (%s)", search, msg)
+	}
+
+	search, msg = parsePlaceSearch(url.Values{"q": {"pizza"}, "lat": {"52.5"}, "lng": {"13.4"}, "radius": {"0.5"}})
+	if msg != "" || search.Radius != 500 {
+		t.Errorf("Expected a radius of 500 m, got %+v (%s)", search, msg)
+	}
+
+	for _, params := range []url.Values{
+		{},
+		{"q": {"pizza"}, "lat": {"52.5"}},
+		{"q": {"pizza"}, "lng": {"13.4"}},
+		{"q": {"pizza"}, "radius": {"2"}},
+		{"q": {"pizza"}, "lat": {"91"}, "lng": {"13.4"}},
+		{"q": {"pizza"}, "lat": {"52.5"}, "lng": {"13.4"}, "radius": {"51"}},
+	} {
+		if _, msg := parsePlaceSearch(params); msg == "" {
+			t.Errorf("Expected %v to be rejected", params)
+		}
+	}
+}
+
+// pagedPlacesProvider returns one result and a next page token for every search
+type pagedPlacesProvider struct {
+	fakePlacesProvider
+	search services.PlaceSearch
+}
+
+func (p *pagedPlacesProvider) SearchPlaces(_ context.Context, search services.PlaceSearch) (*services.PlaceSearchResults, error) {
+	p.search = search
+	return &services.PlaceSearchResults{
+		Results:       []models.GooglePlaceResult{{PlaceID: "luigi", Name: "Luigi's"}},
+		NextPageToken: "page-2",
+	}, nil
+}
+
+func TestSearchPlaces_NextPageToken(t *testing.T) {
+	provider := &pagedPlacesProvider{}
+	withPlacesProvider(t, provider)
+
+	rec := httptest.NewRecorder()
+	SearchPlaces(rec, httptest.NewRequest(http.MethodGet, "/api/places/search?q=pizza&lat=52.5&lng=13.4&radius=2", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec.Header().Get("X-Next-Page-Token") != "page-2" {
+		t.Errorf("Expected the next page token, got %q", rec.Header().Get("X-Next-Page-Token"))
+	}
+	if provider.search.Radius != 2000 {
+		t.Errorf("Unexpected search %+v", provider.search)
+	}
+}
@@ -0,0 +1,190 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/nomdb/backend/internal/database"
+	"github.com/nomdb/backend/internal/logger"
+	"github.com/nomdb/backend/internal/models"
+	"github.com/nomdb/backend/internal/storage"
+)
+
+// healthCheckTimeout bounds each dependency check of the readiness probe
+const healthCheckTimeout = 3 * time.Second
+
+// shuttingDown is closed when the server starts draining connections before exiting
+var (
+	shuttingDown     = make(chan struct{})
+	shuttingDownOnce sync.Once
+)
+
+var (
+	expectedMigration     uint
+	healthCheckStorage    bool
+	healthCheckGoogleMaps bool
+)
+
+// InitHealthChecks configures the readiness probe: the migration version the database
+// must be at, and whether storage and Google Maps reachability are checked
+func InitHealthChecks(migrationVersion uint, checkStorage, checkGoogleMaps bool) {
+	expectedMigration = migrationVersion
+	healthCheckStorage = checkStorage
+	healthCheckGoogleMaps = checkGoogleMaps
+}
+
+// BeginShutdown marks the server as draining: health checks fail so load balancers
+// stop sending traffic, and long-lived event streams end so they do not hold up the
+// shutdown
+func BeginShutdown() {
+	shuttingDownOnce.Do(func() { close(shuttingDown) })
+}
+
+// isShuttingDown reports whether BeginShutdown was called
+func isShuttingDown() bool {
+	select {
+	case <-shuttingDown:
+		return true
+	default:
+		return false
+	}
+}
+
+// @Summary Liveness probe
+// @Description Returns 200 as long as the process serves requests, without checking dependencies. Use it to decide when to restart the container.
+// @Tags Health
+// @Produce plain
+// @Success 200 {string} string "OK"
+// @Router /health/live [get]
+func LivenessCheck(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	if r.Method == http.MethodGet {
+		_, _ = w.Write([]byte("OK"))
+	}
+}
+
+// @Summary Readiness probe
+// @Description Checks the database connection and migration version, plus storage and Google Maps reachability when HEALTH_CHECK_STORAGE / HEALTH_CHECK_GOOGLE_MAPS are enabled. Returns 503 if any check fails or the server is shutting down, so load balancers only route to instances that can serve requests. /api/health is an alias.
+// @Tags Health
+// @Produce json
+// @Success 200 {object} models.HealthResponse
+// @Failure 503 {object} models.HealthResponse
+// @Router /health/ready [get]
+// @Router /health [get]
+func ReadinessCheck(w http.ResponseWriter, r *http.Request) {
+	checks := map[string]func(context.Context) (string, error){
+		"database":   checkDatabase,
+		"migrations": checkMigrations,
+	}
+	if healthCheckStorage {
+		checks["storage"] = checkStorage
+	}
+	if healthCheckGoogleMaps {
+		checks["google_maps"] = checkGoogleMaps
+	}
+
+	response := models.HealthResponse{Status: "ok", Checks: runHealthChecks(r.Context(), checks, healthCheckTimeout)}
+	for name, check := range response.Checks {
+		if check.Status != "ok" {
+			logger.Warn("🩺 Readiness check %s failed: %s", name, check.Error)
+			response.Status = "unavailable"
+		}
+	}
+	if isShuttingDown() {
+		response.Status = "shutting_down"
+	}
+
+	status := http.StatusOK
+	if response.Status != "ok" {
+		status = http.StatusServiceUnavailable
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	if r.Method == http.MethodHead {
+		return
+	}
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		logger.Error("Failed to encode response: %v", err)
+	}
+}
+
+// runHealthChecks runs checks concurrently, each with timeout
+func runHealthChecks(ctx context.Context, checks map[string]func(context.Context) (string, error), timeout time.Duration) map[string]models.HealthCheck {
+	results := make(map[string]models.HealthCheck, len(checks))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			checkCtx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+
+			start := time.Now()
+			details, err := check(checkCtx)
+			result := models.HealthCheck{Status: "ok", LatencyMs: time.Since(start).Milliseconds(), Details: details}
+			if err != nil {
+				result.Status = "error"
+				result.Error = err.Error()
+			}
+
+			mu.Lock()
+			results[name] = result
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+func checkDatabase(ctx context.Context) (string, error) {
+	pool := database.GetPool()
+	if pool == nil {
+		return "", fmt.Errorf("not connected")
+	}
+	return "", pool.Ping(ctx)
+}
+
+// checkMigrations verifies that the schema is at the version applied on startup and no
+// migration failed halfway
+func checkMigrations(ctx context.Context) (string, error) {
+	pool := database.GetPool()
+	if pool == nil {
+		return "", fmt.Errorf("not connected")
+	}
+	var version int64
+	var dirty bool
+	err := pool.QueryRow(ctx,
+		"SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&version, &dirty)
+	if err != nil {
+		return "", err
+	}
+	details := fmt.Sprintf("version %d", version)
+	if dirty {
+		return details, fmt.Errorf("migration %d is dirty", version)
+	}
+	if uint(version) != expectedMigration {
+		return details, fmt.Errorf("expected version %d", expectedMigration)
+	}
+	return details, nil
+}
+
+// checkStorage lists an empty prefix, which needs the backend to be reachable and the
+// credentials to be valid
+func checkStorage(ctx context.Context) (string, error) {
+	store := storage.Get()
+	if store == nil {
+		return "", fmt.Errorf("not initialized")
+	}
+	_, err := store.List(ctx, "health-check/")
+	return store.Name(), err
+}
+
+func checkGoogleMaps(ctx context.Context) (string, error) {
+	return placesProvider.Name(), placesProvider.Ping(ctx)
+}
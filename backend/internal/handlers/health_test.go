@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/nomdb/backend/internal/models"
+)
+
+func TestLivenessCheck(t *testing.T) {
+	rec := httptest.NewRecorder()
+	LivenessCheck(rec, httptest.NewRequest(http.MethodGet, "/api/health/live", nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != "OK" {
+		t.Errorf("Expected 200 OK, got %d %q", rec.Code, rec.Body.String())
+	}
+}
+
+func TestReadinessCheck_DatabaseDown(t *testing.T) {
+	// Tests run without a database connection
+	rec := httptest.NewRecorder()
+	ReadinessCheck(rec, httptest.NewRequest(http.MethodGet, "/api/health/ready", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("Expected 503 without a database, got %d", rec.Code)
+	}
+
+	var response models.HealthResponse
+	if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if response.Status != "unavailable" {
+		t.Errorf("Expected status unavailable, got %s", response.Status)
+	}
+	if check := response.Checks["database"]; check.Status != "error" || check.Error == "" {
+		t.Errorf("Expected a failed database check, got %+v", check)
+	}
+	if _, ok := response.Checks["storage"]; ok {
+		t.Error("Expected the storage check to be disabled by default")
+	}
+}
+
+func TestReadinessCheck_ShuttingDown(t *testing.T) {
+	defer func() {
+		shuttingDown = make(chan struct{})
+		shuttingDownOnce = sync.Once{}
+	}()
+
+	BeginShutdown()
+	BeginShutdown() // Must be safe to call twice
+
+	rec := httptest.NewRecorder()
+	ReadinessCheck(rec, httptest.NewRequest(http.MethodGet, "/api/health", nil))
+	var response models.HealthResponse
+	if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if rec.Code != http.StatusServiceUnavailable || response.Status != "shutting_down" {
+		t.Errorf("Expected 503 shutting_down, got %d %s", rec.Code, response.Status)
+	}
+
+	rec = httptest.NewRecorder()
+	LivenessCheck(rec, httptest.NewRequest(http.MethodHead, "/api/health/live", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected liveness to pass while draining, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/nomdb/backend/internal/cache"
+	apperrors "github.com/nomdb/backend/internal/errors"
+	"github.com/nomdb/backend/internal/i18n"
+	"github.com/nomdb/backend/internal/logger"
+)
+
+// listCacheMaxAge is how long browsers and CDNs may reuse category, food type and
+// restaurant lists without revalidating them
+var listCacheMaxAge = time.Minute
+
+// InitHTTPCache configures the Cache-Control max-age of cacheable list endpoints
+func InitHTTPCache(maxAge time.Duration) {
+	listCacheMaxAge = maxAge
+	logger.Info("✅ List responses cacheable for %s", maxAge)
+}
+
+// writeCacheableJSON writes v as the response of a public GET endpoint whose data is
+// invalidated under cacheKeys, with Cache-Control, ETag and Last-Modified headers; it
+// was last modified when the most recently invalidated key was.
+// Revalidations of an unchanged response get 304 Not Modified. Responses vary by space
+// (and by language, as all responses do), and those of private spaces may only be cached
+// by the browser.
+func writeCacheableJSON(w http.ResponseWriter, r *http.Request, v any, cacheKeys ...string) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		apperrors.Internal(w, err)
+		return
+	}
+	body = append(body, '\n')
+
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+	if i18n.FromContext(r.Context()) != i18n.Default {
+		// Translated names change without the list changing
+		cacheKeys = append(cacheKeys, cache.KeyTranslations)
+	}
+	var modified time.Time
+	for _, key := range cacheKeys {
+		if keyModified := cache.Modified(key); keyModified.After(modified) {
+			modified = keyModified
+		}
+	}
+	lastModified := modified.UTC().Truncate(time.Second)
+
+	header := w.Header()
+	visibility := "public"
+	if !currentSpace(r).Public {
+		visibility = "private"
+	}
+	if listCacheMaxAge > 0 {
+		header.Set("Cache-Control", fmt.Sprintf("%s, max-age=%d", visibility, int(listCacheMaxAge.Seconds())))
+	} else {
+		header.Set("Cache-Control", "no-cache")
+	}
+	header.Set("ETag", etag)
+	header.Set("Last-Modified", lastModified.Format(http.TimeFormat))
+	header.Add("Vary", SpaceHeader)
+
+	if notModified(r, etag, lastModified) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	header.Set("Content-Type", "application/json")
+	w.Write(body)
+}
+
+// notModified evaluates If-None-Match, or If-Modified-Since if there is none. ETags are
+// compared weakly, as compression turns them into weak ones.
+func notModified(r *http.Request, etag string, lastModified time.Time) bool {
+	if match := r.Header.Get("If-None-Match"); match != "" {
+		for _, candidate := range strings.Split(match, ",") {
+			candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+			if candidate == etag || candidate == "*" {
+				return true
+			}
+		}
+		return false
+	}
+	since, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	return err == nil && !lastModified.After(since)
+}
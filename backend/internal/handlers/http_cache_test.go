@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/nomdb/backend/internal/cache"
+)
+
+func TestWriteCacheableJSON(t *testing.T) {
+	serve := func(header http.Header) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/food-types", nil)
+		for name, values := range header {
+			req.Header[name] = values
+		}
+		rec := httptest.NewRecorder()
+		writeCacheableJSON(rec, req, []string{"Pizza", "Ramen"}, cache.KeyFoodTypes)
+		return rec
+	}
+
+	rec := serve(nil)
+	if rec.Code != http.StatusOK || rec.Body.String() != `["Pizza","Ramen"]`+"\n" {
+		t.Fatalf("Unexpected response %d %s", rec.Code, rec.Body)
+	}
+	if cc := rec.Header().Get("Cache-Control"); cc != "public, max-age=60" {
+		t.Errorf("Unexpected Cache-Control %q", cc)
+	}
+	if vary := rec.Header().Get("Vary"); vary != SpaceHeader {
+		t.Errorf("Expected responses to vary by space, got %q", vary)
+	}
+	etag, lastModified := rec.Header().Get("ETag"), rec.Header().Get("Last-Modified")
+	if etag == "" || lastModified == "" {
+		t.Fatalf("Expected validators, got ETag %q and Last-Modified %q", etag, lastModified)
+	}
+
+	tests := []struct {
+		name   string
+		header http.Header
+		want   int
+	}{
+		{"matching ETag", http.Header{"If-None-Match": {etag}}, http.StatusNotModified},
+		{"weak ETag from a compressed response", http.Header{"If-None-Match": {`"other", W/` + etag}}, http.StatusNotModified},
+		{"changed ETag", http.Header{"If-None-Match": {`"other"`}, "If-Modified-Since": {lastModified}}, http.StatusOK},
+		{"not modified since", http.Header{"If-Modified-Since": {lastModified}}, http.StatusNotModified},
+		{"modified since", http.Header{"If-Modified-Since": {time.Unix(0, 0).UTC().Format(http.TimeFormat)}}, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(tt.header)
+			if rec.Code != tt.want {
+				t.Errorf("Expected %d, got %d", tt.want, rec.Code)
+			}
+			if rec.Code == http.StatusNotModified && rec.Body.Len() != 0 {
+				t.Error("Expected no body with 304")
+			}
+		})
+	}
+
+	listCacheMaxAge = 0
+	defer func() { listCacheMaxAge = time.Minute }()
+	if cc := serve(nil).Header().Get("Cache-Control"); cc != "no-cache" {
+		t.Errorf("Expected a zero max-age to require revalidation, got %q", cc)
+	}
+}
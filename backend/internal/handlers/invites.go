@@ -0,0 +1,269 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/jackc/pgx/v5"
+	"github.com/nomdb/backend/internal/database"
+	apperrors "github.com/nomdb/backend/internal/errors"
+	"github.com/nomdb/backend/internal/logger"
+	"github.com/nomdb/backend/internal/models"
+)
+
+// Registration modes selectable via REGISTRATION_MODE
+const (
+	RegistrationOpen   = "open"   // Anyone can create an account
+	RegistrationInvite = "invite" // New accounts need an invite code
+	RegistrationClosed = "closed" // No new accounts
+)
+
+const (
+	defaultInviteExpiryDays = 7
+	inviteCodePrefixLength  = 8
+)
+
+var (
+	registrationMode = RegistrationOpen
+	inviteURL        = "http://localhost:3000/register"
+
+	errRegistrationClosed = errors.New("registration is closed")
+	errInvalidInvite      = errors.New("invalid or expired invite")
+)
+
+const inviteColumns = `i.id, i.code_prefix, i.note, i.max_uses, i.use_count, i.expires_at,
+	i.created_by_user_id, i.revoked_at, i.created_at,
+	COALESCE((SELECT array_agg(u.username ORDER BY u.created_at) FROM users u WHERE u.invite_id = i.id), '{}')`
+
+func scanInvite(row interface{ Scan(...any) error }, invite *models.Invite) error {
+	return row.Scan(&invite.ID, &invite.CodePrefix, &invite.Note, &invite.MaxUses, &invite.UseCount,
+		&invite.ExpiresAt, &invite.CreatedByUserID, &invite.RevokedAt, &invite.CreatedAt, &invite.RedeemedBy)
+}
+
+// InitRegistration sets who may create accounts and the frontend page invite links point to
+func InitRegistration(mode, registerURL string) {
+	registrationMode = mode
+	inviteURL = registerURL
+	logger.Info("📝 Registration mode: %s", mode)
+}
+
+func hashInviteCode(code string) string {
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
+
+func generateInviteCode() (string, error) {
+	b := make([]byte, 18)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// inviteLink returns the frontend registration URL for an invite code
+func inviteLink(code string) string {
+	link, err := url.Parse(inviteURL)
+	if err != nil {
+		return inviteURL + "?invite=" + url.QueryEscape(code)
+	}
+	query := link.Query()
+	query.Set("invite", code)
+	link.RawQuery = query.Encode()
+	return link.String()
+}
+
+// admitNewUser checks that a new account may be created under the registration mode,
+// redeeming the invite with the given code hash in tx when invites are required. It
+// returns the ID of the redeemed invite, if any.
+func admitNewUser(ctx context.Context, tx pgx.Tx, inviteHash string) (*int, error) {
+	switch settingValue(settingRegistrationMode) {
+	case RegistrationOpen:
+		return nil, nil
+	case RegistrationInvite:
+		if inviteHash == "" {
+			return nil, errInvalidInvite
+		}
+		var inviteID int
+		err := tx.QueryRow(ctx,
+			`UPDATE invites SET use_count = use_count + 1
+			WHERE code_hash = $1 AND revoked_at IS NULL
+			AND (expires_at IS NULL OR expires_at > NOW()) AND use_count < max_uses
+			RETURNING id`, inviteHash).Scan(&inviteID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errInvalidInvite
+		}
+		if err != nil {
+			return nil, err
+		}
+		return &inviteID, nil
+	default:
+		return nil, errRegistrationClosed
+	}
+}
+
+// @Summary Create an invite
+// @Description Create an invite code for registering while REGISTRATION_MODE=invite. The code is only returned in this response; only its hash is stored. Admin only.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body models.CreateInviteRequest true "Note, maximum uses and expiry"
+// @Success 201 {object} models.CreateInviteResponse
+// @Failure 400 {object} errors.ErrorResponse "Invalid request"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /admin/invites [post]
+func CreateInvite(w http.ResponseWriter, r *http.Request) {
+	user, ok := GetUserFromContext(r)
+	if !ok {
+		apperrors.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.CreateInviteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apperrors.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if len(req.Note) > 255 {
+		apperrors.Error(w, "Note must be at most 255 characters", http.StatusBadRequest)
+		return
+	}
+	maxUses := 1
+	if req.MaxUses != nil {
+		if *req.MaxUses <= 0 {
+			apperrors.Error(w, "max_uses must be positive", http.StatusBadRequest)
+			return
+		}
+		maxUses = *req.MaxUses
+	}
+	expiryDays := defaultInviteExpiryDays
+	if req.ExpiresInDays != nil {
+		if *req.ExpiresInDays < 0 {
+			apperrors.Error(w, "expires_in_days must not be negative", http.StatusBadRequest)
+			return
+		}
+		expiryDays = *req.ExpiresInDays
+	}
+	var expiresAt *time.Time
+	if expiryDays > 0 {
+		t := time.Now().AddDate(0, 0, expiryDays)
+		expiresAt = &t
+	}
+	var note *string
+	if req.Note != "" {
+		note = &req.Note
+	}
+
+	code, err := generateInviteCode()
+	if err != nil {
+		logger.Error("Failed to generate invite code: %v", err)
+		apperrors.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	var invite models.Invite
+	err = scanInvite(database.GetPool().QueryRow(r.Context(),
+		`WITH i AS (
+			INSERT INTO invites (code_hash, code_prefix, note, max_uses, expires_at, created_by_user_id)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			RETURNING *
+		)
+		SELECT `+inviteColumns+` FROM i`,
+		hashInviteCode(code), code[:inviteCodePrefixLength], note, maxUses, expiresAt, user.ID), &invite)
+	if err != nil {
+		logger.Error("Failed to create invite: %v", err)
+		apperrors.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	logger.Info("📝 Invite %d (%s) created by %s for %d use(s)", invite.ID, invite.CodePrefix, user.Username, maxUses)
+	recordAdminAction(r.Context(), r, "create_invite", "invite", invite.ID,
+		map[string]any{"prefix": invite.CodePrefix, "max_uses": maxUses})
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(models.CreateInviteResponse{Code: code, URL: inviteLink(code), Invite: invite}); err != nil {
+		logger.Error("Failed to encode response: %v", err)
+	}
+}
+
+// @Summary List invites
+// @Description List all invites with their usage and the users who registered with them. Admin only.
+// @Tags Auth
+// @Produce json
+// @Success 200 {array} models.Invite
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /admin/invites [get]
+func ListInvites(w http.ResponseWriter, r *http.Request) {
+	rows, err := database.GetPool().Query(r.Context(),
+		`SELECT `+inviteColumns+` FROM invites i ORDER BY i.created_at DESC`)
+	if err != nil {
+		logger.Error("Failed to list invites: %v", err)
+		apperrors.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	defer rows.Close()
+
+	invites := []models.Invite{}
+	for rows.Next() {
+		var invite models.Invite
+		if err := scanInvite(rows, &invite); err != nil {
+			logger.Error("Failed to scan invite: %v", err)
+			apperrors.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+		invites = append(invites, invite)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(invites); err != nil {
+		logger.Error("Failed to encode response: %v", err)
+	}
+}
+
+// @Summary Revoke an invite
+// @Description Revoke an invite so it can no longer be used. Accounts created with it are kept. Admin only.
+// @Tags Auth
+// @Param id path int true "Invite ID"
+// @Success 204 "Invite revoked"
+// @Failure 400 {object} errors.ErrorResponse "Invalid invite ID"
+// @Failure 404 {object} errors.ErrorResponse "Invite not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /admin/invites/{id} [delete]
+func RevokeInvite(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		apperrors.Error(w, "Invalid invite ID", http.StatusBadRequest)
+		return
+	}
+
+	result, err := database.GetPool().Exec(r.Context(),
+		"UPDATE invites SET revoked_at = COALESCE(revoked_at, NOW()) WHERE id = $1", id)
+	if err != nil {
+		logger.Error("Failed to revoke invite: %v", err)
+		apperrors.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if result.RowsAffected() == 0 {
+		apperrors.Error(w, "Invite not found", http.StatusNotFound)
+		return
+	}
+
+	logger.Info("📝 Invite %d revoked", id)
+	recordAdminAction(r.Context(), r, "revoke_invite", "invite", id, nil)
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestGenerateInviteCode(t *testing.T) {
+	code, err := generateInviteCode()
+	if err != nil {
+		t.Fatalf("Failed to generate invite code: %v", err)
+	}
+	if len(code) < inviteCodePrefixLength*2 {
+		t.Errorf("Expected a code longer than twice the prefix, got %q", code)
+	}
+
+	other, _ := generateInviteCode()
+	if code == other {
+		t.Error("Expected unique invite codes")
+	}
+	if hashInviteCode(code) == hashInviteCode(other) {
+		t.Error("Expected different hashes for different codes")
+	}
+}
+
+func TestInviteLink(t *testing.T) {
+	defer func(original string) { inviteURL = original }(inviteURL)
+
+	inviteURL = "https://app.example.com/register?lang=de"
+	expected := "https://app.example.com/register?invite=abc%2Bdef&lang=de"
+	if link := inviteLink("abc+def"); link != expected {
+		t.Errorf("Expected %s, got %s", expected, link)
+	}
+}
+
+func TestAdmitNewUserWithoutInvite(t *testing.T) {
+	defer func(original string) { registrationMode = original }(registrationMode)
+	ctx := context.Background()
+
+	registrationMode = RegistrationOpen
+	if inviteID, err := admitNewUser(ctx, nil, ""); err != nil || inviteID != nil {
+		t.Errorf("Expected open registration to admit anyone, got %v, %v", inviteID, err)
+	}
+
+	registrationMode = RegistrationClosed
+	if _, err := admitNewUser(ctx, nil, hashInviteCode("code")); !errors.Is(err, errRegistrationClosed) {
+		t.Errorf("Expected errRegistrationClosed, got %v", err)
+	}
+
+	registrationMode = RegistrationInvite
+	if _, err := admitNewUser(ctx, nil, ""); !errors.Is(err, errInvalidInvite) {
+		t.Errorf("Expected errInvalidInvite without an invite, got %v", err)
+	}
+}
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	apperrors "github.com/nomdb/backend/internal/errors"
+	"github.com/nomdb/backend/internal/i18n"
+	"github.com/nomdb/backend/internal/repository"
+)
+
+// Leaderboard periods
+const (
+	leaderboardPeriodMonth = "month"
+	leaderboardPeriodAll   = "all"
+)
+
+// monthStart returns the start of the month of t, in UTC
+func monthStart(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// @Summary Get the leaderboard
+// @Description The users of the space with the most ratings, the most suggestions converted to restaurants and the most photos, this month (UTC) or of all time. Service accounts and deactivated users are not ranked.
+// @Tags Users
+// @Produce json
+// @Param period query string false "month (default) or all"
+// @Param limit query int false "Number of users per ranking (default 20, max 100)"
+// @Success 200 {object} models.Leaderboard
+// @Failure 400 {object} errors.ErrorResponse "Invalid period"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /leaderboard [get]
+func GetLeaderboard(w http.ResponseWriter, r *http.Request) {
+	period := r.URL.Query().Get("period")
+	var since *time.Time
+	switch period {
+	case "", leaderboardPeriodMonth:
+		period = leaderboardPeriodMonth
+		start := monthStart(time.Now())
+		since = &start
+	case leaderboardPeriodAll:
+	default:
+		apperrors.Error(w, "Invalid period. Must be month or all", http.StatusBadRequest)
+		return
+	}
+
+	board, err := repos.Stats.Leaderboard(r.Context(), spaceID(r), since, ParsePaginationParams(r).Limit)
+	if err != nil {
+		apperrors.Internal(w, err)
+		return
+	}
+	board.Period = period
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(board)
+}
+
+// @Summary Get the contribution stats of a user
+// @Description The profile of a user: their ratings, suggestions, suggestions converted to restaurants and photos in the space, in total and this month (UTC), and the badges they were awarded.
+// @Tags Users
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} models.UserStats
+// @Failure 400 {object} errors.ErrorResponse "Invalid user ID"
+// @Failure 404 {object} errors.ErrorResponse "User not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /users/{id}/stats [get]
+func GetUserStats(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		apperrors.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	stats, err := repos.Stats.UserStats(ctx, spaceID(r), id, monthStart(time.Now()))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			apperrors.Error(w, "User not found", http.StatusNotFound)
+			return
+		}
+		apperrors.Internal(w, err)
+		return
+	}
+	awarded, err := repos.Badges.ListByUser(ctx, id)
+	if err != nil {
+		apperrors.Internal(w, err)
+		return
+	}
+	stats.Badges = localizeUserBadges(awarded, i18n.FromContext(ctx))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(stats)
+}
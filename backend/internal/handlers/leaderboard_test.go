@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/nomdb/backend/internal/models"
+	"github.com/nomdb/backend/internal/repository"
+	"github.com/pashagolub/pgxmock/v4"
+)
+
+func TestMonthStart(t *testing.T) {
+	at := time.Date(2026, 10, 15, 1, 30, 0, 0, time.FixedZone("CEST", 2*60*60))
+	if got := monthStart(at); !got.Equal(time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected the first of October in UTC, got %v", got)
+	}
+	// Still September in UTC
+	at = time.Date(2026, 10, 1, 1, 0, 0, 0, time.FixedZone("CEST", 2*60*60))
+	if got := monthStart(at); !got.Equal(time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected the first of September in UTC, got %v", got)
+	}
+}
+
+func TestGetLeaderboard(t *testing.T) {
+	t.Run("all time", func(t *testing.T) {
+		mock := withMockRepositories(t)
+		columns := []string{"id", "username", "avatar_url", "count"}
+		for i := 0; i < 3; i++ {
+			mock.ExpectQuery(`GROUP BY u.id`).WithArgs(repository.DefaultSpaceID, (*time.Time)(nil), 5).
+				WillReturnRows(pgxmock.NewRows(columns).AddRow(8, "anna", nil, 3))
+		}
+
+		rec := httptest.NewRecorder()
+		GetLeaderboard(rec, httptest.NewRequest(http.MethodGet, "/api/leaderboard?period=all&limit=5", nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		var board models.Leaderboard
+		json.NewDecoder(rec.Body).Decode(&board)
+		if board.Period != "all" || board.Since != nil || len(board.Photos) != 1 || board.Photos[0].Rank != 1 {
+			t.Errorf("Unexpected leaderboard %+v", board)
+		}
+	})
+
+	t.Run("invalid period", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		GetLeaderboard(rec, httptest.NewRequest(http.MethodGet, "/api/leaderboard?period=week", nil))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected 400, got %d", rec.Code)
+		}
+	})
+}
+
+func TestGetUserStats_InvalidID(t *testing.T) {
+	req := mux.SetURLVars(httptest.NewRequest(http.MethodGet, "/api/users/me/stats", nil), map[string]string{"id": "me"})
+	rec := httptest.NewRecorder()
+	GetUserStats(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,201 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	apperrors "github.com/nomdb/backend/internal/errors"
+	"github.com/nomdb/backend/internal/events"
+	"github.com/nomdb/backend/internal/middleware"
+	"github.com/nomdb/backend/internal/models"
+)
+
+// Change events streamed at /api/events. Clients refetch the entity; events carry IDs only.
+const (
+	eventRestaurantCreated   = "restaurant.created"
+	eventRestaurantUpdated   = "restaurant.updated"
+	eventRestaurantDeleted   = "restaurant.deleted"
+	eventRatingCreated       = "rating.created"
+	eventRatingUpdated       = "rating.updated"
+	eventRatingDeleted       = "rating.deleted"
+	eventSuggestionCreated   = "suggestion.created"
+	eventSuggestionUpdated   = "suggestion.updated"
+	eventSuggestionConverted = "suggestion.converted"
+	eventSuggestionDeleted   = "suggestion.deleted"
+	eventCategoryCreated     = "category.created"
+	eventCategoryUpdated     = "category.updated"
+	eventCategoryDeleted     = "category.deleted"
+	eventFoodTypeCreated     = "food_type.created"
+	eventFoodTypeUpdated     = "food_type.updated"
+	eventFoodTypeDeleted     = "food_type.deleted"
+)
+
+// Photo processing outcomes, streamed at /api/restaurants/{id}/photos/events with the
+// photo's status in the event data
+const (
+	eventPhotoReady  = "photo.ready"
+	eventPhotoFailed = "photo.failed"
+)
+
+const liveUpdateHeartbeat = 30 * time.Second
+
+// liveUpdateBroker fans out events of the event bus to SSE clients
+type liveUpdateBroker struct {
+	mu          sync.Mutex
+	subscribers map[chan events.Event]func(events.Event) bool
+}
+
+var (
+	liveUpdates         = &liveUpdateBroker{subscribers: make(map[chan events.Event]func(events.Event) bool)}
+	liveUpdatesInitOnce sync.Once
+)
+
+// InitLiveUpdates feeds the SSE streams from the event bus, so clients receive changes
+// made through any instance
+func InitLiveUpdates() {
+	liveUpdatesInitOnce.Do(func() { events.Subscribe(liveUpdates.deliver) })
+}
+
+// subscribe returns a channel receiving the events matching filter
+func (b *liveUpdateBroker) subscribe(filter func(events.Event) bool) chan events.Event {
+	ch := make(chan events.Event, 16)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subscribers[ch] = filter
+	return ch
+}
+
+func (b *liveUpdateBroker) unsubscribe(ch chan events.Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.subscribers, ch)
+}
+
+// deliver sends an event without blocking; slow subscribers miss events
+func (b *liveUpdateBroker) deliver(e events.Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch, filter := range b.subscribers {
+		if !filter(e) {
+			continue
+		}
+		select {
+		case ch <- e:
+		default:
+		}
+	}
+}
+
+// publishChange announces a change of an entity of a space (0 for categories and food
+// types, which every space shares) to live update clients of every instance
+func publishChange(ctx context.Context, eventType string, spaceID, restaurantID, id int) {
+	events.Publish(ctx, events.Event{Type: eventType, ID: id, RestaurantID: restaurantID, SpaceID: spaceID})
+}
+
+// publishPhotoEvent announces the outcome of background photo processing of a photo of
+// a restaurant of the space
+func publishPhotoEvent(ctx context.Context, spaceID int, event models.PhotoEvent) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+	events.Publish(ctx, events.Event{
+		Type: event.Type, ID: event.PhotoID, RestaurantID: event.RestaurantID, SpaceID: spaceID, Data: data,
+	})
+}
+
+// StreamEvents godoc
+// @Summary Stream live updates
+// @Description Server-Sent Events stream of changes to restaurants, ratings, suggestions, categories, food types and photos, made through any server instance, of the selected space and of the categories and food types all spaces share. Events carry the type, entity ID, restaurant ID and space ID; clients refetch what changed. An events.resync event means changes may have been missed and everything should be reloaded.
+// @Tags Events
+// @Produce text/event-stream
+// @Param restaurant_id query int false "Only events of this restaurant"
+// @Success 200 {object} events.Event "Event stream"
+// @Failure 400 {object} errors.ErrorResponse "Invalid restaurant ID"
+// @Router /events [get]
+func StreamEvents(w http.ResponseWriter, r *http.Request) {
+	restaurantID := 0
+	if v := r.URL.Query().Get("restaurant_id"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil || id <= 0 {
+			apperrors.Error(w, "Invalid restaurant ID", http.StatusBadRequest)
+			return
+		}
+		restaurantID = id
+	}
+
+	streamEvents(w, r, liveUpdateFilter(spaceID(r), restaurantID), func(e events.Event) ([]byte, error) {
+		return json.Marshal(e)
+	})
+}
+
+// liveUpdateFilter matches the events streamed at /api/events to a client of the space,
+// following all restaurants or only restaurantID
+func liveUpdateFilter(spaceID, restaurantID int) func(events.Event) bool {
+	return func(e events.Event) bool {
+		if e.Type == events.TypeCacheInvalidated || e.Type == events.TypeLogLevelsChanged {
+			return false
+		}
+		// Changes of other spaces stay there; categories and food types are shared
+		if e.SpaceID != 0 && e.SpaceID != spaceID {
+			return false
+		}
+		return restaurantID == 0 || e.RestaurantID == restaurantID || e.Type == events.TypeResync
+	}
+}
+
+// streamEvents writes the events matching filter as Server-Sent Events, encoding each
+// with encode, until the client leaves or the server shuts down
+func streamEvents(w http.ResponseWriter, r *http.Request, filter func(events.Event) bool, encode func(events.Event) ([]byte, error)) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		apperrors.Error(w, "Streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	// The stream stays open until the client leaves
+	middleware.DisableWriteTimeout(w)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	ch := liveUpdates.subscribe(filter)
+	defer liveUpdates.unsubscribe(ch)
+
+	heartbeat := time.NewTicker(liveUpdateHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-shuttingDown:
+			// Clients reconnect, to another instance if this one is going away
+			return
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": ping\n\n")
+			flusher.Flush()
+		case event := <-ch:
+			data, err := encode(event)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data)
+			flusher.Flush()
+		}
+	}
+}
+
+// isPhotoEvent reports whether e is a processing event of a photo of restaurantID
+func isPhotoEvent(e events.Event, restaurantID int) bool {
+	return strings.HasPrefix(e.Type, "photo.") && e.RestaurantID == restaurantID && len(e.Data) > 0
+}
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/nomdb/backend/internal/events"
+	"github.com/nomdb/backend/internal/models"
+)
+
+func TestLiveUpdateBroker(t *testing.T) {
+	broker := &liveUpdateBroker{subscribers: make(map[chan events.Event]func(events.Event) bool)}
+
+	subscribed := broker.subscribe(func(e events.Event) bool { return isPhotoEvent(e, 1) })
+	other := broker.subscribe(func(e events.Event) bool { return isPhotoEvent(e, 2) })
+
+	data, _ := json.Marshal(models.PhotoEvent{Type: eventPhotoReady, PhotoID: 10, RestaurantID: 1, Status: photoStatusReady})
+	broker.deliver(events.Event{Type: eventPhotoReady, ID: 10, RestaurantID: 1, Data: data})
+
+	select {
+	case event := <-subscribed:
+		if event.ID != 10 || event.Type != eventPhotoReady {
+			t.Errorf("Unexpected event: %+v", event)
+		}
+	default:
+		t.Fatal("Expected subscriber of restaurant 1 to receive the event")
+	}
+
+	select {
+	case event := <-other:
+		t.Errorf("Subscriber of restaurant 2 should not receive events of restaurant 1, got %+v", event)
+	default:
+	}
+
+	broker.unsubscribe(subscribed)
+	broker.unsubscribe(other)
+	if len(broker.subscribers) != 0 {
+		t.Errorf("Expected no subscribers after unsubscribe, got %d", len(broker.subscribers))
+	}
+
+	// Delivering without subscribers or to a full channel must not block
+	full := broker.subscribe(func(events.Event) bool { return true })
+	for i := 0; i < cap(full)+5; i++ {
+		broker.deliver(events.Event{Type: "rating.created", ID: i, RestaurantID: 3})
+	}
+	if len(full) != cap(full) {
+		t.Errorf("Expected full channel (%d), got %d events", cap(full), len(full))
+	}
+}
+
+func TestLiveUpdates_FromEventBus(t *testing.T) {
+	InitLiveUpdates()
+	ch := liveUpdates.subscribe(func(e events.Event) bool { return isPhotoEvent(e, 5) })
+	defer liveUpdates.unsubscribe(ch)
+
+	publishChange(context.Background(), eventRestaurantUpdated, 1, 5, 5)
+	publishPhotoEvent(context.Background(), 1, models.PhotoEvent{Type: eventPhotoFailed, PhotoID: 8, RestaurantID: 5, Status: photoStatusFailed})
+
+	select {
+	case event := <-ch:
+		var photo models.PhotoEvent
+		if err := json.Unmarshal(event.Data, &photo); err != nil || photo.PhotoID != 8 || photo.Status != photoStatusFailed {
+			t.Errorf("Unexpected photo event %+v (%v)", photo, err)
+		}
+	default:
+		t.Fatal("Expected the photo event")
+	}
+	if len(ch) != 0 {
+		t.Error("Expected only photo events on the photo stream")
+	}
+}
+
+func TestLiveUpdateFilter(t *testing.T) {
+	filter := liveUpdateFilter(2, 0)
+	tests := []struct {
+		event events.Event
+		want  bool
+	}{
+		{events.Event{Type: eventRestaurantUpdated, ID: 5, RestaurantID: 5, SpaceID: 2}, true},
+		// Private spaces' changes do not reach clients of other spaces
+		{events.Event{Type: eventRestaurantUpdated, ID: 6, RestaurantID: 6, SpaceID: 3}, false},
+		{events.Event{Type: eventPhotoReady, ID: 8, RestaurantID: 6, SpaceID: 3}, false},
+		// Categories are shared by all spaces
+		{events.Event{Type: eventCategoryUpdated, ID: 1}, true},
+		{events.Event{Type: events.TypeResync}, true},
+		{events.Event{Type: events.TypeCacheInvalidated}, false},
+	}
+	for _, tt := range tests {
+		if got := filter(tt.event); got != tt.want {
+			t.Errorf("filter(%+v) = %v, want %v", tt.event, got, tt.want)
+		}
+	}
+
+	// Following a restaurant of another space does not reveal its events
+	if liveUpdateFilter(2, 6)(events.Event{Type: eventPhotoReady, ID: 8, RestaurantID: 6, SpaceID: 3}) {
+		t.Error("Expected events of restaurant 6 of space 3 to be hidden from space 2")
+	}
+}
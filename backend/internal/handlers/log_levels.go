@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+
+	apperrors "github.com/nomdb/backend/internal/errors"
+	"github.com/nomdb/backend/internal/events"
+	"github.com/nomdb/backend/internal/logger"
+	"github.com/nomdb/backend/internal/models"
+)
+
+var logLevelsInitOnce sync.Once
+
+// InitLogLevels applies the log levels admins set on other instances. They are not
+// stored, so a restarted instance logs at the configured levels again.
+func InitLogLevels() {
+	logLevelsInitOnce.Do(func() {
+		events.Subscribe(func(e events.Event) {
+			if e.Type != events.TypeLogLevelsChanged || !e.Remote {
+				return
+			}
+			var req models.LogLevelsRequest
+			if err := json.Unmarshal(e.Data, &req); err != nil {
+				logger.Warn("⚠️  Invalid log levels from another instance: %v", err)
+				return
+			}
+			if err := logger.SetLevels(req.Level, req.Modules); err != nil {
+				logger.Warn("⚠️  Failed to apply log levels from another instance: %v", err)
+				return
+			}
+			logger.Info("📝 Log level set to %s by another instance, modules: %v", req.Level, req.Modules)
+		})
+	})
+}
+
+// currentLogLevels returns the log levels in effect
+func currentLogLevels() models.LogLevels {
+	level, modules := logger.CurrentLevels()
+	return models.LogLevels{Level: level, Modules: modules, AvailableModules: logger.Modules()}
+}
+
+// @Summary Get log levels
+// @Description Get the default log level, the levels of modules that differ from it and the modules whose level can be set. Admin only.
+// @Tags Settings
+// @Produce json
+// @Success 200 {object} models.LogLevels
+// @Security BearerAuth
+// @Router /admin/log-level [get]
+func GetLogLevels(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(currentLogLevels())
+}
+
+// @Summary Set log levels
+// @Description Set the default log level (debug, info, warn or error) and the levels of modules, such as debug for the photos module only. Modules left out log at the default level. The change applies to every server instance within moments and lasts until they restart, when LOG_LEVEL and LOG_MODULE_LEVELS apply again. Admin only.
+// @Tags Settings
+// @Accept json
+// @Produce json
+// @Param levels body models.LogLevelsRequest true "Log levels"
+// @Success 200 {object} models.LogLevels
+// @Failure 400 {object} errors.ErrorResponse "Invalid level or unknown module"
+// @Security BearerAuth
+// @Router /admin/log-level [put]
+func UpdateLogLevels(w http.ResponseWriter, r *http.Request) {
+	var req models.LogLevelsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apperrors.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if err := logger.SetLevels(req.Level, req.Modules); err != nil {
+		apperrors.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	logger.Info("📝 Log level set to %s, modules: %v", req.Level, req.Modules)
+
+	ctx := r.Context()
+	recordAudit(ctx, r, auditEvent{Event: AuditAdminAction, TargetType: "log_level", TargetID: req.Level,
+		Details: map[string]any{"action": "update_log_levels", "modules": req.Modules}})
+	data, _ := json.Marshal(req)
+	events.Publish(ctx, events.Event{Type: events.TypeLogLevelsChanged, Data: data})
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(currentLogLevels())
+}
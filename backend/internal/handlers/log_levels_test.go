@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/nomdb/backend/internal/models"
+)
+
+func TestGetLogLevels(t *testing.T) {
+	rec := httptest.NewRecorder()
+	GetLogLevels(rec, httptest.NewRequest(http.MethodGet, "/api/admin/log-level", nil))
+
+	var levels models.LogLevels
+	if err := json.NewDecoder(rec.Body).Decode(&levels); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if levels.Level == "" || !slices.Contains(levels.AvailableModules, "photos") {
+		t.Errorf("Expected the default level and the photos module, got %+v", levels)
+	}
+}
+
+func TestUpdateLogLevelsValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"invalid JSON", `{`},
+		{"missing level", `{}`},
+		{"invalid level", `{"level": "verbose"}`},
+		{"unknown module", `{"level": "info", "modules": {"thumbnails": "debug"}}`},
+		{"invalid module level", `{"level": "info", "modules": {"photos": "trace"}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			UpdateLogLevels(rec, httptest.NewRequest(http.MethodPut, "/api/admin/log-level", strings.NewReader(tt.body)))
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("Expected 400, got %d", rec.Code)
+			}
+		})
+	}
+}
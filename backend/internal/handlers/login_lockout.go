@@ -0,0 +1,225 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/nomdb/backend/internal/database"
+	apperrors "github.com/nomdb/backend/internal/errors"
+	"github.com/nomdb/backend/internal/logger"
+)
+
+// loginLockoutMax caps the exponential backoff
+const loginLockoutMax = 24 * time.Hour
+
+var (
+	loginMaxAttempts   = 5
+	loginIPMaxAttempts = 20
+	loginLockoutBase   = time.Minute
+	ipLoginFailures    = newLoginFailureTracker()
+)
+
+// InitLoginLockout configures brute-force protection. Accounts are locked after
+// maxAttempts consecutive failures and client IPs after ipMaxAttempts; each further
+// failure doubles the lockout, starting at base. A limit of 0 disables that lockout.
+func InitLoginLockout(maxAttempts, ipMaxAttempts int, base time.Duration) {
+	loginMaxAttempts = maxAttempts
+	loginIPMaxAttempts = ipMaxAttempts
+	loginLockoutBase = base
+	ipLoginFailures.startCleanupTask(10 * time.Minute)
+
+	logger.Debug("Login lockout: account after %d, IP after %d failures (base %v)", maxAttempts, ipMaxAttempts, base)
+}
+
+// lockoutDuration returns how long to lock after the given number of consecutive
+// failures, or 0 while below the threshold
+func lockoutDuration(failures, threshold int) time.Duration {
+	if threshold <= 0 || failures < threshold {
+		return 0
+	}
+	d := loginLockoutBase
+	for i := threshold; i < failures && d < loginLockoutMax; i++ {
+		d *= 2
+	}
+	if d > loginLockoutMax {
+		d = loginLockoutMax
+	}
+	return d
+}
+
+// loginFailures is the failure state of one client IP
+type loginFailures struct {
+	count       int
+	lockedUntil time.Time
+	lastFailure time.Time
+}
+
+// loginFailureTracker counts failed logins per client IP in memory
+type loginFailureTracker struct {
+	mu      sync.Mutex
+	entries map[string]*loginFailures
+	once    sync.Once
+}
+
+func newLoginFailureTracker() *loginFailureTracker {
+	return &loginFailureTracker{entries: make(map[string]*loginFailures)}
+}
+
+// lockedFor returns the remaining lockout of ip, or 0 if it may try to log in
+func (t *loginFailureTracker) lockedFor(ip string, now time.Time) time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if entry, ok := t.entries[ip]; ok && now.Before(entry.lockedUntil) {
+		return entry.lockedUntil.Sub(now)
+	}
+	return 0
+}
+
+// recordFailure counts a failed login from ip and locks it once over the limit
+func (t *loginFailureTracker) recordFailure(ip string, now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	entry, ok := t.entries[ip]
+	if !ok {
+		entry = &loginFailures{}
+		t.entries[ip] = entry
+	}
+	entry.count++
+	entry.lastFailure = now
+
+	if d := lockoutDuration(entry.count, loginIPMaxAttempts); d > 0 {
+		entry.lockedUntil = now.Add(d)
+		logger.Warn("🔒 Login from %s locked for %v after %d failed attempts", ip, d, entry.count)
+	}
+}
+
+// reset forgets the failures of ip after a successful login
+func (t *loginFailureTracker) reset(ip string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.entries, ip)
+}
+
+// cleanup drops IPs that are not locked and have not failed for an hour
+func (t *loginFailureTracker) cleanup(now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for ip, entry := range t.entries {
+		if now.After(entry.lockedUntil) && now.Sub(entry.lastFailure) > time.Hour {
+			delete(t.entries, ip)
+		}
+	}
+}
+
+func (t *loginFailureTracker) startCleanupTask(interval time.Duration) {
+	t.once.Do(func() {
+		ticker := time.NewTicker(interval)
+		go func() {
+			for now := range ticker.C {
+				t.cleanup(now)
+			}
+		}()
+	})
+}
+
+// accountLockedFor returns the remaining lockout of a user account, or 0
+func accountLockedFor(ctx context.Context, userID int) (time.Duration, error) {
+	var lockedUntil *time.Time
+	err := database.GetPool().QueryRow(ctx,
+		"SELECT locked_until FROM users WHERE id = $1", userID).Scan(&lockedUntil)
+	if err != nil {
+		return 0, err
+	}
+	if lockedUntil == nil {
+		return 0, nil
+	}
+	if remaining := time.Until(*lockedUntil); remaining > 0 {
+		return remaining, nil
+	}
+	return 0, nil
+}
+
+// recordAccountLoginFailure counts a wrong password and locks the account once over the limit
+func recordAccountLoginFailure(ctx context.Context, userID int) {
+	pool := database.GetPool()
+
+	var failures int
+	err := pool.QueryRow(ctx,
+		"UPDATE users SET failed_login_attempts = failed_login_attempts + 1 WHERE id = $1 RETURNING failed_login_attempts",
+		userID).Scan(&failures)
+	if err != nil {
+		logger.Warn("Failed to record login failure: %v", err)
+		return
+	}
+
+	if d := lockoutDuration(failures, loginMaxAttempts); d > 0 {
+		if _, err := pool.Exec(ctx, "UPDATE users SET locked_until = $1 WHERE id = $2", time.Now().Add(d), userID); err != nil {
+			logger.Warn("Failed to lock account: %v", err)
+			return
+		}
+		logger.Warn("🔒 Account %d locked for %v after %d failed login attempts", userID, d, failures)
+	}
+}
+
+// resetAccountLoginFailures clears the failure count after a successful login
+func resetAccountLoginFailures(ctx context.Context, userID int) {
+	_, err := database.GetPool().Exec(ctx,
+		`UPDATE users SET failed_login_attempts = 0, locked_until = NULL
+		WHERE id = $1 AND (failed_login_attempts > 0 OR locked_until IS NOT NULL)`, userID)
+	if err != nil {
+		logger.Warn("Failed to reset login failures: %v", err)
+	}
+}
+
+// respondLoginLocked rejects a login attempt during a lockout
+func respondLoginLocked(w http.ResponseWriter, remaining time.Duration) {
+	seconds := int(math.Ceil(remaining.Seconds()))
+	w.Header().Set("Retry-After", strconv.Itoa(seconds))
+	apperrors.Error(w, fmt.Sprintf("Too many failed login attempts. Try again in %v", time.Duration(seconds)*time.Second), http.StatusTooManyRequests)
+}
+
+// @Summary Unlock a user account
+// @Description Clear the failed login counter and lockout of an account. Admin only.
+// @Tags Auth
+// @Param id path int true "User ID"
+// @Success 204 "Account unlocked"
+// @Failure 400 {object} errors.ErrorResponse "Invalid user ID"
+// @Failure 404 {object} errors.ErrorResponse "User not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /admin/users/{id}/unlock [post]
+func UnlockUser(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	userID, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		apperrors.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	result, err := database.GetPool().Exec(r.Context(),
+		"UPDATE users SET failed_login_attempts = 0, locked_until = NULL WHERE id = $1", userID)
+	if err != nil {
+		logger.Error("Failed to unlock user: %v", err)
+		apperrors.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if result.RowsAffected() == 0 {
+		apperrors.Error(w, "User not found", http.StatusNotFound)
+		return
+	}
+
+	if admin, ok := GetUserFromContext(r); ok {
+		logger.Info("🔓 Account %d unlocked by %s", userID, admin.Username)
+	}
+	recordAdminAction(r.Context(), r, "unlock_user", "user", userID, nil)
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLockoutDuration(t *testing.T) {
+	defer func(original time.Duration) { loginLockoutBase = original }(loginLockoutBase)
+	loginLockoutBase = time.Minute
+
+	tests := []struct {
+		failures  int
+		threshold int
+		expected  time.Duration
+	}{
+		{failures: 4, threshold: 5, expected: 0},
+		{failures: 5, threshold: 5, expected: time.Minute},
+		{failures: 6, threshold: 5, expected: 2 * time.Minute},
+		{failures: 8, threshold: 5, expected: 8 * time.Minute},
+		{failures: 100, threshold: 5, expected: loginLockoutMax},
+		{failures: 100, threshold: 0, expected: 0}, // Disabled
+	}
+
+	for _, tt := range tests {
+		if got := lockoutDuration(tt.failures, tt.threshold); got != tt.expected {
+			t.Errorf("lockoutDuration(%d, %d) = %v, expected %v", tt.failures, tt.threshold, got, tt.expected)
+		}
+	}
+}
+
+func TestLoginFailureTracker(t *testing.T) {
+	defer func(attempts int, base time.Duration) {
+		loginIPMaxAttempts, loginLockoutBase = attempts, base
+	}(loginIPMaxAttempts, loginLockoutBase)
+	loginIPMaxAttempts = 3
+	loginLockoutBase = time.Minute
+
+	tracker := newLoginFailureTracker()
+	now := time.Now()
+
+	for i := 0; i < 2; i++ {
+		tracker.recordFailure("10.0.0.1", now)
+	}
+	if d := tracker.lockedFor("10.0.0.1", now); d != 0 {
+		t.Fatalf("Expected no lockout below the limit, got %v", d)
+	}
+
+	tracker.recordFailure("10.0.0.1", now)
+	if d := tracker.lockedFor("10.0.0.1", now); d != time.Minute {
+		t.Errorf("Expected 1m lockout at the limit, got %v", d)
+	}
+	if d := tracker.lockedFor("10.0.0.2", now); d != 0 {
+		t.Errorf("Expected other IPs to be unaffected, got %v", d)
+	}
+	if d := tracker.lockedFor("10.0.0.1", now.Add(2*time.Minute)); d != 0 {
+		t.Errorf("Expected lockout to expire, got %v", d)
+	}
+
+	tracker.recordFailure("10.0.0.1", now.Add(2*time.Minute))
+	if d := tracker.lockedFor("10.0.0.1", now.Add(2*time.Minute)); d != 2*time.Minute {
+		t.Errorf("Expected lockout to double, got %v", d)
+	}
+
+	tracker.reset("10.0.0.1")
+	if d := tracker.lockedFor("10.0.0.1", now.Add(2*time.Minute)); d != 0 {
+		t.Errorf("Expected reset to clear the lockout, got %v", d)
+	}
+
+	tracker.recordFailure("10.0.0.3", now)
+	tracker.cleanup(now.Add(2 * time.Hour))
+	if len(tracker.entries) != 0 {
+		t.Errorf("Expected idle entries to be cleaned up, %d left", len(tracker.entries))
+	}
+}
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	apperrors "github.com/nomdb/backend/internal/errors"
+	"github.com/nomdb/backend/internal/models"
+)
+
+const (
+	mapsQuotaDefaultDays = 30
+	mapsQuotaMaxDays     = 366
+)
+
+// mapsBudget reports the calls of the day against the daily budget; the Google Maps
+// service unless replaced in tests
+var mapsBudget interface {
+	Usage(ctx context.Context) (budget, calls int)
+	BudgetResetsAt() time.Time
+} = mapsService
+
+// @Summary Get Google Maps usage
+// @Description Get the Google Maps calls made today (UTC) against the daily budget (GOOGLE_MAPS_DAILY_BUDGET, 0 for no limit), and the calls per day and operation. Retries count as calls. Admin only.
+// @Tags Google Maps
+// @Produce json
+// @Param days query int false "Days of history, today included (default 30, max 366)"
+// @Success 200 {object} models.MapsQuota "Usage and budget"
+// @Failure 400 {object} errors.ErrorResponse "Invalid days"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /admin/quota [get]
+func GetMapsQuota(w http.ResponseWriter, r *http.Request) {
+	days := mapsQuotaDefaultDays
+	if v := r.URL.Query().Get("days"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > mapsQuotaMaxDays {
+			apperrors.Error(w, "days must be between 1 and 366", http.StatusBadRequest)
+			return
+		}
+		days = n
+	}
+
+	ctx := r.Context()
+	resetsAt := mapsBudget.BudgetResetsAt()
+	history, err := repos.MapsUsage.Daily(ctx, resetsAt.AddDate(0, 0, -days))
+	if err != nil {
+		apperrors.Internal(w, err)
+		return
+	}
+
+	budget, used := mapsBudget.Usage(ctx)
+	quota := models.MapsQuota{Budget: budget, Used: used, ResetsAt: resetsAt, Days: history}
+	if budget > 0 {
+		remaining := max(budget-used, 0)
+		quota.Remaining = &remaining
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(quota)
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/nomdb/backend/internal/models"
+	"github.com/nomdb/backend/internal/services"
+	"github.com/pashagolub/pgxmock/v4"
+)
+
+// fakeMapsBudget reports fixed usage
+type fakeMapsBudget struct {
+	budget, calls int
+	resetsAt      time.Time
+}
+
+func (f *fakeMapsBudget) Usage(context.Context) (int, int) { return f.budget, f.calls }
+func (f *fakeMapsBudget) BudgetResetsAt() time.Time        { return f.resetsAt }
+
+func withMapsBudget(t *testing.T, fake *fakeMapsBudget) {
+	t.Helper()
+	original := mapsBudget
+	mapsBudget = fake
+	t.Cleanup(func() { mapsBudget = original })
+}
+
+func TestGetMapsQuota(t *testing.T) {
+	mock := withMockRepositories(t)
+	resetsAt := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+	withMapsBudget(t, &fakeMapsBudget{budget: 100, calls: 120, resetsAt: resetsAt})
+
+	// Seven days, today included
+	mock.ExpectQuery(`FROM maps_api_usage`).WithArgs(time.Date(2026, 10, 9, 0, 0, 0, 0, time.UTC)).
+		WillReturnRows(pgxmock.NewRows([]string{"day", "operation", "calls"}).
+			AddRow(time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC), "text_search", 120))
+
+	rec := httptest.NewRecorder()
+	GetMapsQuota(rec, httptest.NewRequest(http.MethodGet, "/api/admin/quota?days=7", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var quota models.MapsQuota
+	if err := json.NewDecoder(rec.Body).Decode(&quota); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if quota.Budget != 100 || quota.Used != 120 || quota.Remaining == nil || *quota.Remaining != 0 || len(quota.Days) != 1 {
+		t.Errorf("Unexpected quota %+v", quota)
+	}
+}
+
+func TestWriteMapsError_BudgetExceeded(t *testing.T) {
+	withMapsBudget(t, &fakeMapsBudget{resetsAt: time.Now().Add(90 * time.Minute)})
+
+	rec := httptest.NewRecorder()
+	writeMapsError(rec, services.ErrMapsBudgetExceeded)
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected 503, got %d", rec.Code)
+	}
+	if retryAfter := rec.Header().Get("Retry-After"); retryAfter != "5400" && retryAfter != "5399" {
+		t.Errorf("Expected a retry after midnight UTC, got %q", retryAfter)
+	}
+}
@@ -3,10 +3,13 @@ package handlers
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"image"
+	"io"
 	"net/http"
-	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
@@ -14,301 +17,481 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
+	"github.com/jackc/pgx/v5"
+	"github.com/nomdb/backend/internal/cache"
 	"github.com/nomdb/backend/internal/database"
+	apperrors "github.com/nomdb/backend/internal/errors"
 	"github.com/nomdb/backend/internal/logger"
 	"github.com/nomdb/backend/internal/models"
-	"github.com/nomdb/backend/internal/services"
+	"github.com/nomdb/backend/internal/storage"
 )
 
 const (
-	maxUploadSize    = 5 << 20 // 5MB
-	uploadsDir       = "./uploads/menu_photos"
+	photosPrefix     = "menu_photos"
 	thumbnailsSubdir = "thumbnails"
+	defaultPhotoType = "menu"
+	photoPageLimit   = 50 // Default page size of photo galleries
 )
 
-func init() {
-	// Create uploads directory if it doesn't exist (fallback for local storage)
-	if err := os.MkdirAll(uploadsDir, 0755); err != nil {
-		logger.Warn("Failed to create uploads directory: %v", err)
+// maxUploadSize is the size limit in bytes of photos passing through the server
+func maxUploadSize() int64 {
+	return int64(settingInt(settingUploadMaxSizeMB)) << 20
+}
+
+// uploadImageTypes are the content types accepted for uploads
+var uploadImageTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/webp": true,
+}
+
+// validPhotoTypes mirrors the CHECK constraint on menu_photos.photo_type
+var validPhotoTypes = map[string]bool{"menu": true, "food": true, "interior": true, "receipt": true}
+
+// photoKey returns the storage key of a photo file
+func photoKey(filename string) string {
+	return fmt.Sprintf("%s/%s", photosPrefix, filename)
+}
+
+// thumbnailKey returns the storage key of a photo's thumbnail
+func thumbnailKey(filename string) string {
+	base := strings.TrimSuffix(filename, filepath.Ext(filename))
+	return fmt.Sprintf("%s/%s/%s_thumb.jpg", photosPrefix, thumbnailsSubdir, base)
+}
+
+// menuPhotoURL returns a URL for a photo (presigned and valid for 1 hour on cloud storage)
+func menuPhotoURL(ctx context.Context, store storage.Storage, filename string) (string, error) {
+	return store.URL(ctx, photoKey(filename), time.Hour)
+}
+
+// photoCursor is the keyset position of a photo in gallery order
+type photoCursor struct {
+	Position  int
+	CreatedAt time.Time
+	ID        int
+}
+
+// encodePhotoCursor creates a base64-encoded cursor pointing after photo
+func encodePhotoCursor(photo models.MenuPhoto) string {
+	raw := fmt.Sprintf("%d|%s|%d", photo.Position, photo.CreatedAt.UTC().Format(time.RFC3339Nano), photo.ID)
+	return base64.StdEncoding.EncodeToString([]byte(raw))
+}
+
+// decodePhotoCursor decodes a cursor created by encodePhotoCursor (zero value for "")
+func decodePhotoCursor(cursor string) (photoCursor, error) {
+	var c photoCursor
+	if cursor == "" {
+		return c, nil
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(cursor)
+	if err != nil {
+		return c, err
 	}
+	parts := strings.Split(string(decoded), "|")
+	if len(parts) != 3 {
+		return c, fmt.Errorf("malformed cursor")
+	}
+	if c.Position, err = strconv.Atoi(parts[0]); err != nil {
+		return c, err
+	}
+	if c.CreatedAt, err = time.Parse(time.RFC3339Nano, parts[1]); err != nil {
+		return c, err
+	}
+	if c.ID, err = strconv.Atoi(parts[2]); err != nil {
+		return c, err
+	}
+	if c.ID <= 0 {
+		return c, fmt.Errorf("malformed cursor")
+	}
+	return c, nil
 }
 
 // @Summary Get menu photos for a restaurant
-// @Description Retrieve all menu photos for a specific restaurant with presigned URLs
+// @Description Retrieve a page of a restaurant's photos in display order with presigned URLs, optionally filtered by photo type. The total number of photos is returned in X-Total-Count and the cursor of the next page, if any, in X-Next-Cursor.
 // @Tags Photos
 // @Accept json
 // @Produce json
 // @Param restaurantId path int true "Restaurant ID"
+// @Param type query string false "Photo type (menu, food, interior, receipt)"
+// @Param limit query int false "Number of photos per page (default: 50, max: 100)"
+// @Param cursor query string false "Pagination cursor from X-Next-Cursor"
 // @Success 200 {array} models.MenuPhoto "List of menu photos"
-// @Failure 400 {object} map[string]string "Invalid restaurant ID"
-// @Failure 500 {object} map[string]string "Internal server error"
+// @Header 200 {integer} X-Total-Count "Total number of photos matching the filter"
+// @Header 200 {string} X-Next-Cursor "Cursor of the next page"
+// @Failure 400 {object} errors.ErrorResponse "Invalid restaurant ID, photo type or cursor"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
 // @Router /restaurants/{restaurantId}/photos [get]
 func GetMenuPhotos(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	restaurantID, err := strconv.Atoi(vars["restaurantId"])
 	if err != nil {
-		http.Error(w, "Invalid restaurant ID", http.StatusBadRequest)
+		apperrors.Error(w, "Invalid restaurant ID", http.StatusBadRequest)
+		return
+	}
+
+	photoType := r.URL.Query().Get("type")
+	if photoType != "" && !validPhotoTypes[photoType] {
+		apperrors.Error(w, "Invalid photo type. Must be one of: menu, food, interior, receipt", http.StatusBadRequest)
+		return
+	}
+
+	pagination := ParsePaginationParams(r)
+	if r.URL.Query().Get("limit") == "" {
+		pagination.Limit = photoPageLimit
+	}
+	cursor, err := decodePhotoCursor(pagination.Cursor)
+	if err != nil {
+		apperrors.Error(w, "Invalid cursor", http.StatusBadRequest)
 		return
 	}
 
-	ctx := context.Background()
-	rows, err := database.GetPool().Query(ctx,
-		`SELECT id, restaurant_id, filename, original_filename, caption, file_size, mime_type, created_at, updated_at
+	// Photos awaiting moderation are only listed for their uploader and admins
+	viewerID, isAdmin := photoViewer(r)
+
+	ctx := r.Context()
+	pool := database.GetPool()
+
+	var total int
+	err = pool.QueryRow(ctx,
+		`SELECT COUNT(*) FROM menu_photos
+		WHERE restaurant_id = $1 AND ($2 = '' OR photo_type = $2)
+			AND (moderation_status = 'approved' OR $3 OR uploaded_by_user_id = $4)`,
+		restaurantID, photoType, isAdmin, viewerID).Scan(&total)
+	if err != nil {
+		apperrors.Internal(w, err)
+		return
+	}
+
+	// Fetch one extra row to know whether there is a next page
+	rows, err := pool.Query(ctx,
+		`SELECT id, restaurant_id, filename, original_filename, caption, file_size, mime_type, photo_type, position, is_cover, status, processing_error, moderation_status, moderation_labels, uploaded_by_user_id, created_at, updated_at
 		FROM menu_photos
-		WHERE restaurant_id = $1
-		ORDER BY created_at DESC`, restaurantID)
+		WHERE restaurant_id = $1 AND ($2 = '' OR photo_type = $2)
+			AND (moderation_status = 'approved' OR $3 OR uploaded_by_user_id = $4)
+			AND ($5 = 0 OR position > $6 OR (position = $6 AND (created_at < $7 OR (created_at = $7 AND id < $5))))
+		ORDER BY position, created_at DESC, id DESC
+		LIMIT $8`,
+		restaurantID, photoType, isAdmin, viewerID, cursor.ID, cursor.Position, cursor.CreatedAt, pagination.Limit+1)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		apperrors.Internal(w, err)
 		return
 	}
 	defer rows.Close()
 
 	photos := []models.MenuPhoto{}
-	s3Service := services.GetS3Service()
+	store := storage.Get()
 
 	for rows.Next() {
 		var photo models.MenuPhoto
 		if err := rows.Scan(
 			&photo.ID, &photo.RestaurantID, &photo.Filename, &photo.OriginalFilename,
-			&photo.Caption, &photo.FileSize, &photo.MimeType, &photo.CreatedAt, &photo.UpdatedAt,
+			&photo.Caption, &photo.FileSize, &photo.MimeType, &photo.PhotoType, &photo.Position, &photo.IsCover, &photo.Status, &photo.ProcessingError, &photo.ModerationStatus, &photo.ModerationLabels, &photo.UploadedByUserID, &photo.CreatedAt, &photo.UpdatedAt,
 		); err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			apperrors.Internal(w, err)
 			return
 		}
 
-		// Generate URL based on storage type
-		if s3Service != nil {
-			// Generate presigned URL for S3 (valid for 1 hour)
-			presignedURL, err := s3Service.GetPresignedURL(ctx, fmt.Sprintf("menu_photos/%s", photo.Filename), time.Hour)
-			if err != nil {
-				http.Error(w, fmt.Sprintf("Failed to generate URL: %v", err), http.StatusInternalServerError)
-				return
-			}
-			photo.URL = presignedURL
-		} else {
-			// Use local file URL
-			photo.URL = fmt.Sprintf("/api/uploads/menu_photos/%s", photo.Filename)
-		}
-
 		photos = append(photos, photo)
 	}
+	if err := rows.Err(); err != nil {
+		apperrors.Internal(w, err)
+		return
+	}
+
+	w.Header().Set("X-Total-Count", strconv.Itoa(total))
+	if len(photos) > pagination.Limit {
+		photos = photos[:pagination.Limit]
+		w.Header().Set("X-Next-Cursor", encodePhotoCursor(photos[len(photos)-1]))
+	}
+
+	// Presigning is comparatively expensive, so only do it for the returned page
+	for i := range photos {
+		photos[i].URL, err = menuPhotoURL(ctx, store, photos[i].Filename)
+		if err != nil {
+			apperrors.Internal(w, fmt.Errorf("failed to generate URL: %w", err))
+			return
+		}
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(photos)
 }
 
+// getMenuPhotoByID loads a single photo with its URL
+func getMenuPhotoByID(ctx context.Context, store storage.Storage, id int) (*models.MenuPhoto, error) {
+	var photo models.MenuPhoto
+	err := database.GetPool().QueryRow(ctx,
+		`SELECT id, restaurant_id, filename, original_filename, caption, file_size, mime_type, photo_type, position, is_cover, status, processing_error, moderation_status, moderation_labels, uploaded_by_user_id, created_at, updated_at
+		FROM menu_photos WHERE id = $1`, id,
+	).Scan(
+		&photo.ID, &photo.RestaurantID, &photo.Filename, &photo.OriginalFilename,
+		&photo.Caption, &photo.FileSize, &photo.MimeType, &photo.PhotoType, &photo.Position, &photo.IsCover, &photo.Status, &photo.ProcessingError, &photo.ModerationStatus, &photo.ModerationLabels, &photo.UploadedByUserID, &photo.CreatedAt, &photo.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if photo.URL, err = menuPhotoURL(ctx, store, photo.Filename); err != nil {
+		return nil, err
+	}
+	return &photo, nil
+}
+
+// @Summary Get a menu photo
+// @Description Get a single photo, e.g. to poll its processing status after upload
+// @Tags Photos
+// @Produce json
+// @Param id path int true "Photo ID"
+// @Success 200 {object} models.MenuPhoto "Photo details"
+// @Failure 400 {object} errors.ErrorResponse "Invalid photo ID"
+// @Failure 404 {object} errors.ErrorResponse "Photo not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /photos/{id} [get]
+func GetMenuPhoto(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		apperrors.Error(w, "Invalid photo ID", http.StatusBadRequest)
+		return
+	}
+
+	photo, err := getMenuPhotoByID(r.Context(), storage.Get(), id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			apperrors.Error(w, "Photo not found", http.StatusNotFound)
+			return
+		}
+		apperrors.Internal(w, err)
+		return
+	}
+	if !canViewPhoto(r, photo) {
+		apperrors.Error(w, "Photo not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(photo)
+}
+
 // @Summary Upload a menu photo
-// @Description Upload a menu photo for a restaurant (JPEG, PNG, or WebP, max 5MB)
+// @Description Upload a menu photo for a restaurant (JPEG, PNG, or WebP, max 5MB unless changed in the upload_max_size_mb setting). The photo is resized in the background and has status "processing" until ready.
 // @Tags Photos
 // @Accept multipart/form-data
 // @Produce json
 // @Param restaurantId path int true "Restaurant ID"
 // @Param photo formData file true "Menu photo file"
 // @Param caption formData string true "Photo caption"
-// @Success 201 {object} models.UploadPhotoResponse "Uploaded photo details"
-// @Failure 400 {object} map[string]string "Invalid request or file"
-// @Failure 500 {object} map[string]string "Internal server error"
+// @Param photo_type formData string false "Photo type (menu, food, interior, receipt)" default(menu)
+// @Success 201 {object} models.UploadPhotoResponse "Uploaded and processed photo (synchronous processing)"
+// @Success 202 {object} models.UploadPhotoResponse "Uploaded photo, processing in the background"
+// @Failure 400 {object} errors.ErrorResponse "Invalid request or file"
+// @Failure 429 {object} errors.ErrorResponse "Daily upload limit reached"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Security BearerAuth
 // @Router /restaurants/{restaurantId}/photos [post]
 func UploadMenuPhoto(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	restaurantID, err := strconv.Atoi(vars["restaurantId"])
 	if err != nil {
-		http.Error(w, "Invalid restaurant ID", http.StatusBadRequest)
+		apperrors.Error(w, "Invalid restaurant ID", http.StatusBadRequest)
+		return
+	}
+
+	if !enforcePhotoUploadQuota(r.Context(), w, r) {
 		return
 	}
 
 	// Parse multipart form
-	r.Body = http.MaxBytesReader(w, r.Body, maxUploadSize)
-	if err := r.ParseMultipartForm(maxUploadSize); err != nil {
-		http.Error(w, "File too large", http.StatusBadRequest)
+	r.Body = http.MaxBytesReader(w, r.Body, maxUploadSize())
+	if err := r.ParseMultipartForm(maxUploadSize()); err != nil {
+		apperrors.Error(w, "File too large", http.StatusBadRequest)
 		return
 	}
 
 	// Get caption
 	caption := r.FormValue("caption")
 	if caption == "" {
-		http.Error(w, "Caption is required", http.StatusBadRequest)
+		apperrors.Error(w, "Caption is required", http.StatusBadRequest)
+		return
+	}
+
+	photoType := r.FormValue("photo_type")
+	if photoType == "" {
+		photoType = defaultPhotoType
+	}
+	if !validPhotoTypes[photoType] {
+		apperrors.Error(w, "Invalid photo type. Must be one of: menu, food, interior, receipt", http.StatusBadRequest)
 		return
 	}
 
 	// Get file
 	file, header, err := r.FormFile("photo")
 	if err != nil {
-		http.Error(w, "Failed to read file", http.StatusBadRequest)
+		apperrors.Error(w, "Failed to read file", http.StatusBadRequest)
 		return
 	}
 	defer file.Close()
 
 	// Validate file size
-	if header.Size > maxUploadSize {
-		http.Error(w, fmt.Sprintf("File too large. Maximum size is %d MB", maxUploadSize/(1<<20)), http.StatusBadRequest)
+	if header.Size > maxUploadSize() {
+		apperrors.Error(w, fmt.Sprintf("File too large. Maximum size is %d MB", maxUploadSize()/(1<<20)), http.StatusBadRequest)
 		return
 	}
 
 	// Validate file type
 	contentType := header.Header.Get("Content-Type")
 	if !strings.HasPrefix(contentType, "image/") {
-		http.Error(w, "Only image files are allowed", http.StatusBadRequest)
+		apperrors.Error(w, "Only image files are allowed", http.StatusBadRequest)
 		return
 	}
 
 	// Validate specific image types
-	validTypes := map[string]bool{
-		"image/jpeg": true,
-		"image/png":  true,
-		"image/webp": true,
-	}
-	if !validTypes[contentType] {
-		http.Error(w, "Only JPEG, PNG, and WebP images are allowed", http.StatusBadRequest)
+	if !uploadImageTypes[contentType] {
+		apperrors.Error(w, "Only JPEG, PNG, and WebP images are allowed", http.StatusBadRequest)
 		return
 	}
 
-	// Process image (resize, compress, generate thumbnail)
-	imageProcessor := services.NewImageProcessor()
-	fullImage, thumbnail, err := imageProcessor.ProcessUpload(file, header.Filename)
+	original, err := io.ReadAll(file)
 	if err != nil {
-		http.Error(w, fmt.Sprintf("Failed to process image: %v", err), http.StatusBadRequest)
+		apperrors.Error(w, "Failed to read file", http.StatusBadRequest)
+		return
+	}
+
+	savePhotoUpload(w, r, restaurantID, original, contentType, header.Filename, caption, photoType)
+}
+
+// savePhotoUpload stores an uploaded image, creates its photo row and hands it to the
+// processing pipeline, then writes the response
+func savePhotoUpload(w http.ResponseWriter, r *http.Request, restaurantID int, original []byte, contentType, originalFilename, caption, photoType string) {
+	// Reject undecodable files up front; full decoding happens during processing
+	if _, _, err := image.DecodeConfig(bytes.NewReader(original)); err != nil {
+		apperrors.Error(w, fmt.Sprintf("Failed to process image: %v", err), http.StatusBadRequest)
 		return
 	}
 
 	// Generate unique filename (always use .jpg extension after processing)
 	filename := uuid.New().String() + ".jpg"
-	thumbnailFilename := uuid.New().String() + "_thumb.jpg"
 
-	ctx := context.Background()
-	s3Service := services.GetS3Service()
-	var fileSize int64 = int64(len(fullImage))
-	var photoURL string
+	var uploaderID *int
+	if user, ok := GetUserFromContext(r); ok {
+		uploaderID = &user.ID
+	}
 
-	if s3Service != nil {
-		// Upload full image to S3
-		s3Key := fmt.Sprintf("menu_photos/%s", filename)
-		_, err = s3Service.UploadFile(ctx, s3Key, bytes.NewReader(fullImage), "image/jpeg")
-		if err != nil {
-			http.Error(w, fmt.Sprintf("Failed to upload file to S3: %v", err), http.StatusInternalServerError)
-			return
-		}
+	ctx := r.Context()
+	store := storage.Get()
 
-		// Upload thumbnail to S3
-		s3ThumbKey := fmt.Sprintf("menu_photos/thumbnails/%s", thumbnailFilename)
-		_, err = s3Service.UploadFile(ctx, s3ThumbKey, bytes.NewReader(thumbnail), "image/jpeg")
-		if err != nil {
-			http.Error(w, fmt.Sprintf("Failed to upload thumbnail to S3: %v", err), http.StatusInternalServerError)
-			return
-		}
+	// Persist the original; resizing and thumbnailing happen in a background worker
+	if err := store.Upload(ctx, originalKey(filename), bytes.NewReader(original), contentType); err != nil {
+		apperrors.Internal(w, fmt.Errorf("failed to save file: %w", err))
+		return
+	}
 
-		// Generate presigned URL for immediate response
-		photoURL, err = s3Service.GetPresignedURL(ctx, s3Key, time.Hour)
-		if err != nil {
-			http.Error(w, "Failed to generate URL", http.StatusInternalServerError)
-			return
-		}
-	} else {
-		// Fallback to local storage
-		filePath := filepath.Join(uploadsDir, filename)
-		if err := os.WriteFile(filePath, fullImage, 0644); err != nil {
-			http.Error(w, "Failed to save file", http.StatusInternalServerError)
-			return
-		}
+	// Save to database (always use image/jpeg as mime type after processing)
+	var photoID int
+	err := database.GetPool().QueryRow(ctx,
+		`INSERT INTO menu_photos (restaurant_id, filename, original_filename, caption, file_size, mime_type, uploaded_by_user_id, photo_type, status, moderation_status, position)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, (SELECT COALESCE(MAX(position) + 1, 0) FROM menu_photos WHERE restaurant_id = $1))
+		RETURNING id`,
+		restaurantID, filename, originalFilename, caption, len(original), "image/jpeg", uploaderID, photoType, photoStatusProcessing, initialModerationStatus(),
+	).Scan(&photoID)
+	if err != nil {
+		// Clean up uploaded files on database error
+		deletePhotoFiles(ctx, store, filename)
+		apperrors.Internal(w, err)
+		return
+	}
 
-		// Save thumbnail
-		thumbnailDir := filepath.Join(uploadsDir, thumbnailsSubdir)
-		if err := os.MkdirAll(thumbnailDir, 0755); err != nil {
-			logger.Warn("Failed to create thumbnail directory: %v", err)
-		}
-		thumbnailPath := filepath.Join(thumbnailDir, thumbnailFilename)
-		if err := os.WriteFile(thumbnailPath, thumbnail, 0644); err != nil {
-			http.Error(w, "Failed to save thumbnail", http.StatusInternalServerError)
-			return
-		}
+	respondWithUploadedPhoto(w, r, store, photoID)
+}
 
-		photoURL = fmt.Sprintf("/api/uploads/menu_photos/%s", filename)
+// respondWithUploadedPhoto hands a photo whose original is stored to the processing
+// workers, or processes it inline without them, and responds with the photo: 202 while
+// it is processed in the background, 201 once processed
+func respondWithUploadedPhoto(w http.ResponseWriter, r *http.Request, store storage.Storage, photoID int) {
+	ctx := r.Context()
+	status := http.StatusAccepted
+	if !enqueuePhotoProcessing(ctx, photoID) {
+		processPhoto(ctx, photoID)
+		status = http.StatusCreated
 	}
 
-	// Save to database (always use image/jpeg as mime type after processing)
-	var photo models.MenuPhoto
-	err = database.GetPool().QueryRow(ctx,
-		`INSERT INTO menu_photos (restaurant_id, filename, original_filename, caption, file_size, mime_type)
-		VALUES ($1, $2, $3, $4, $5, $6)
-		RETURNING id, restaurant_id, filename, original_filename, caption, file_size, mime_type, created_at, updated_at`,
-		restaurantID, filename, header.Filename, caption, int(fileSize), "image/jpeg",
-	).Scan(
-		&photo.ID, &photo.RestaurantID, &photo.Filename, &photo.OriginalFilename,
-		&photo.Caption, &photo.FileSize, &photo.MimeType, &photo.CreatedAt, &photo.UpdatedAt,
-	)
+	photo, err := getMenuPhotoByID(ctx, store, photoID)
 	if err != nil {
-		// Clean up uploaded file on database error
-		if s3Service != nil {
-			if delErr := s3Service.DeleteFile(ctx, fmt.Sprintf("menu_photos/%s", filename)); delErr != nil {
-				logger.Warn("Failed to delete file after database error: %v", delErr)
-			}
-		} else {
-			if delErr := os.Remove(filepath.Join(uploadsDir, filename)); delErr != nil {
-				logger.Warn("Failed to delete file after database error: %v", delErr)
-			}
-		}
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		apperrors.Internal(w, err)
 		return
 	}
 
-	// Set URL
-	photo.URL = photoURL
-
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(models.UploadPhotoResponse{Photo: photo})
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(models.UploadPhotoResponse{Photo: *photo})
 }
 
 // @Summary Update photo caption
-// @Description Update the caption of a menu photo
+// @Description Update the caption and/or type of a menu photo
 // @Tags Photos
 // @Accept json
 // @Produce json
 // @Param id path int true "Photo ID"
-// @Param caption body object{caption=string} true "Caption update request"
+// @Param caption body object{caption=string,photo_type=string} true "Caption/type update request"
 // @Success 200 {object} models.MenuPhoto "Updated photo"
-// @Failure 400 {object} map[string]string "Invalid request"
-// @Failure 404 {object} map[string]string "Photo not found"
-// @Failure 500 {object} map[string]string "Internal server error"
-// @Router /photos/{id} [put]
+// @Failure 400 {object} errors.ErrorResponse "Invalid request"
+// @Failure 403 {object} errors.ErrorResponse "Not the uploader or an admin"
+// @Failure 404 {object} errors.ErrorResponse "Photo not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /photos/{id} [patch]
 func UpdatePhotoCaption(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id, err := strconv.Atoi(vars["id"])
 	if err != nil {
-		http.Error(w, "Invalid photo ID", http.StatusBadRequest)
+		apperrors.Error(w, "Invalid photo ID", http.StatusBadRequest)
 		return
 	}
 
 	var req struct {
-		Caption string `json:"caption"`
+		Caption   string `json:"caption"`
+		PhotoType string `json:"photo_type"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		apperrors.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
 
-	if req.Caption == "" {
-		http.Error(w, "Caption is required", http.StatusBadRequest)
+	if req.Caption == "" && req.PhotoType == "" {
+		apperrors.Error(w, "Caption or photo_type is required", http.StatusBadRequest)
+		return
+	}
+
+	if req.PhotoType != "" && !validPhotoTypes[req.PhotoType] {
+		apperrors.Error(w, "Invalid photo type. Must be one of: menu, food, interior, receipt", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	if !authorizePhotoChange(ctx, w, r, id) {
 		return
 	}
 
-	ctx := context.Background()
 	var photo models.MenuPhoto
 	err = database.GetPool().QueryRow(ctx,
-		`UPDATE menu_photos SET caption = $1, updated_at = NOW()
+		`UPDATE menu_photos SET caption = COALESCE(NULLIF($1, ''), caption), photo_type = COALESCE(NULLIF($3, ''), photo_type), updated_at = NOW()
 		WHERE id = $2
-		RETURNING id, restaurant_id, filename, original_filename, caption, file_size, mime_type, created_at, updated_at`,
-		req.Caption, id,
+		RETURNING id, restaurant_id, filename, original_filename, caption, file_size, mime_type, photo_type, position, is_cover, status, processing_error, moderation_status, moderation_labels, uploaded_by_user_id, created_at, updated_at`,
+		req.Caption, id, req.PhotoType,
 	).Scan(
 		&photo.ID, &photo.RestaurantID, &photo.Filename, &photo.OriginalFilename,
-		&photo.Caption, &photo.FileSize, &photo.MimeType, &photo.CreatedAt, &photo.UpdatedAt,
+		&photo.Caption, &photo.FileSize, &photo.MimeType, &photo.PhotoType, &photo.Position, &photo.IsCover, &photo.Status, &photo.ProcessingError, &photo.ModerationStatus, &photo.ModerationLabels, &photo.UploadedByUserID, &photo.CreatedAt, &photo.UpdatedAt,
 	)
 	if err != nil {
-		http.Error(w, "Photo not found", http.StatusNotFound)
+		apperrors.Error(w, "Photo not found", http.StatusNotFound)
 		return
 	}
 
-	photo.URL = fmt.Sprintf("/api/uploads/menu_photos/%s", photo.Filename)
+	if photo.URL, err = menuPhotoURL(ctx, storage.Get(), photo.Filename); err != nil {
+		apperrors.Internal(w, fmt.Errorf("failed to generate URL: %w", err))
+		return
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(photo)
@@ -321,26 +504,31 @@ func UpdatePhotoCaption(w http.ResponseWriter, r *http.Request) {
 // @Produce json
 // @Param id path int true "Photo ID"
 // @Success 204 "Photo deleted successfully"
-// @Failure 400 {object} map[string]string "Invalid photo ID"
-// @Failure 404 {object} map[string]string "Photo not found"
-// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 400 {object} errors.ErrorResponse "Invalid photo ID"
+// @Failure 403 {object} errors.ErrorResponse "Not the uploader or an admin"
+// @Failure 404 {object} errors.ErrorResponse "Photo not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Security BearerAuth
 // @Router /photos/{id} [delete]
 func DeleteMenuPhoto(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id, err := strconv.Atoi(vars["id"])
 	if err != nil {
-		http.Error(w, "Invalid photo ID", http.StatusBadRequest)
+		apperrors.Error(w, "Invalid photo ID", http.StatusBadRequest)
 		return
 	}
 
-	ctx := context.Background()
+	ctx := r.Context()
+	if !authorizePhotoChange(ctx, w, r, id) {
+		return
+	}
 
 	// Get filename before deleting from DB
 	var filename string
 	err = database.GetPool().QueryRow(ctx,
 		"SELECT filename FROM menu_photos WHERE id = $1", id).Scan(&filename)
 	if err != nil {
-		http.Error(w, "Photo not found", http.StatusNotFound)
+		apperrors.Error(w, "Photo not found", http.StatusNotFound)
 		return
 	}
 
@@ -348,29 +536,34 @@ func DeleteMenuPhoto(w http.ResponseWriter, r *http.Request) {
 	result, err := database.GetPool().Exec(ctx,
 		"DELETE FROM menu_photos WHERE id = $1", id)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		apperrors.Internal(w, err)
 		return
 	}
 
 	if result.RowsAffected() == 0 {
-		http.Error(w, "Photo not found", http.StatusNotFound)
+		apperrors.Error(w, "Photo not found", http.StatusNotFound)
 		return
 	}
 
-	// Delete file from storage (non-fatal if fails)
-	s3Service := services.GetS3Service()
-	if s3Service != nil {
-		// Delete from S3
-		if delErr := s3Service.DeleteFile(ctx, fmt.Sprintf("menu_photos/%s", filename)); delErr != nil {
-			logger.Warn("Failed to delete file from S3: %v", delErr)
-		}
-	} else {
-		// Delete from local disk
-		filePath := filepath.Join(uploadsDir, filename)
-		if delErr := os.Remove(filePath); delErr != nil {
-			logger.Warn("Failed to delete file from disk: %v", delErr)
-		}
-	}
+	// Delete files from storage (non-fatal if fails)
+	deletePhotoFiles(ctx, storage.Get(), filename)
+	cache.Invalidate(ctx, cache.KeyRestaurants)
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// deletePhotoFiles removes a photo, its thumbnail, unprocessed original and all resized variants from storage (non-fatal)
+func deletePhotoFiles(ctx context.Context, store storage.Storage, filename string) {
+	if err := store.Delete(ctx, photoKey(filename)); err != nil {
+		logger.Warn("Failed to delete photo file %s: %v", filename, err)
+	}
+	if err := store.Delete(ctx, thumbnailKey(filename)); err != nil {
+		logger.Warn("Failed to delete thumbnail of %s: %v", filename, err)
+	}
+	if err := store.Delete(ctx, originalKey(filename)); err != nil {
+		logger.Warn("Failed to delete original of %s: %v", filename, err)
+	}
+	if err := store.DeletePrefix(ctx, variantPrefix(filename)); err != nil {
+		logger.Warn("Failed to delete resized variants of %s: %v", filename, err)
+	}
+}
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/nomdb/backend/internal/models"
+)
+
+func TestPhotoCursor(t *testing.T) {
+	createdAt := time.Date(2025, 3, 14, 15, 9, 26, 535897000, time.UTC)
+	photo := models.MenuPhoto{ID: 42, Position: 3, CreatedAt: createdAt}
+
+	cursor, err := decodePhotoCursor(encodePhotoCursor(photo))
+	if err != nil {
+		t.Fatalf("decodePhotoCursor failed: %v", err)
+	}
+	if cursor.ID != 42 || cursor.Position != 3 || !cursor.CreatedAt.Equal(createdAt) {
+		t.Errorf("Cursor did not round-trip: %+v", cursor)
+	}
+
+	empty, err := decodePhotoCursor("")
+	if err != nil || empty.ID != 0 {
+		t.Errorf("Expected zero cursor for empty string, got %+v, %v", empty, err)
+	}
+
+	invalid := []string{
+		"not base64!",
+		base64.StdEncoding.EncodeToString([]byte("42")),
+		base64.StdEncoding.EncodeToString([]byte("x|2025-03-14T15:09:26Z|42")),
+		base64.StdEncoding.EncodeToString([]byte("3|yesterday|42")),
+		base64.StdEncoding.EncodeToString([]byte("3|2025-03-14T15:09:26Z|0")),
+	}
+	for _, c := range invalid {
+		if _, err := decodePhotoCursor(c); err == nil {
+			t.Errorf("Expected error for cursor %q", c)
+		}
+	}
+}
+
+func TestArchiveEntryName(t *testing.T) {
+	original := "Speisekarte Sommer 2025!.JPG"
+	traversal := "../../etc/passwd"
+
+	tests := []struct {
+		name             string
+		index            int
+		originalFilename *string
+		expected         string
+	}{
+		{
+			name:     "Without original filename",
+			index:    0,
+			expected: "001_7_abc.jpg",
+		},
+		{
+			name:             "Original filename is sanitized",
+			index:            11,
+			originalFilename: &original,
+			expected:         "012_7_Speisekarte_Sommer_2025.jpg",
+		},
+		{
+			name:             "Path traversal is neutralized",
+			index:            1,
+			originalFilename: &traversal,
+			expected:         "002_7_etc_passwd.jpg",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := archiveEntryName(tt.index, 7, "abc.jpg", tt.originalFilename)
+			if got != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
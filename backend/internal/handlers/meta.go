@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/nomdb/backend/internal/cache"
+	apperrors "github.com/nomdb/backend/internal/errors"
+	"github.com/nomdb/backend/internal/i18n"
+	"github.com/nomdb/backend/internal/models"
+)
+
+// ratingDimensions are the aspects ratings score, with their names by locale
+var ratingDimensions = []struct {
+	key   string
+	names map[string]string
+}{
+	{"food", map[string]string{"en": "Food", "de": "Essen"}},
+	{"service", map[string]string{"en": "Service", "de": "Service"}},
+	{"ambiance", map[string]string{"en": "Ambiance", "de": "Ambiente"}},
+}
+
+// localizedRatingDimensions returns the rating dimensions named in locale
+func localizedRatingDimensions(locale string) []models.RatingDimension {
+	dimensions := make([]models.RatingDimension, 0, len(ratingDimensions))
+	for _, d := range ratingDimensions {
+		name, ok := d.names[locale]
+		if !ok {
+			name = d.names[i18n.Default]
+		}
+		dimensions = append(dimensions, models.RatingDimension{Key: d.key, Name: name, Min: minRating, Max: maxRating})
+	}
+	return dimensions
+}
+
+// GetMeta godoc
+// @Summary Get reference data
+// @Description Get the categories, food types, rating dimensions and badges in one response, as returned by their own endpoints, for clients to load at launch. The ETag covers all of them; revalidate with If-None-Match or If-Modified-Since to get 304 Not Modified while none changed.
+// @Tags Meta
+// @Produce json
+// @Success 200 {object} models.Meta
+// @Success 304 "Not modified"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /meta [get]
+func GetMeta(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	categories, err := cachedCategories(ctx)
+	if err != nil {
+		apperrors.Internal(w, err)
+		return
+	}
+	foodTypes, err := cachedFoodTypes(ctx)
+	if err != nil {
+		apperrors.Internal(w, err)
+		return
+	}
+	localizeCategories(ctx, categories)
+	localizeFoodTypes(ctx, foodTypes)
+
+	meta := models.Meta{
+		Categories:       categories,
+		FoodTypes:        foodTypes,
+		RatingDimensions: localizedRatingDimensions(i18n.FromContext(ctx)),
+		Badges:           localizedBadges(i18n.FromContext(ctx)),
+	}
+	writeCacheableJSON(w, r, meta, cache.KeyCategories, cache.KeyFoodTypes)
+}
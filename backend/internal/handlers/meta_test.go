@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nomdb/backend/internal/cache"
+	"github.com/nomdb/backend/internal/models"
+)
+
+func TestGetMeta(t *testing.T) {
+	withMemoryCache(t)
+	ctx := context.Background()
+	cache.SetJSON(ctx, cache.KeyCategories, []models.Category{{ID: 1, Name: "Italian"}})
+	cache.SetJSON(ctx, cache.KeyFoodTypes, []models.FoodType{{ID: 2, Name: "Pizza"}})
+
+	get := func(etag string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/meta", nil)
+		if etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		rec := httptest.NewRecorder()
+		GetMeta(rec, req)
+		return rec
+	}
+
+	rec := get("")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var meta models.Meta
+	if err := json.Unmarshal(rec.Body.Bytes(), &meta); err != nil {
+		t.Fatalf("Invalid response: %v", err)
+	}
+	if len(meta.Categories) != 1 || len(meta.FoodTypes) != 1 || len(meta.RatingDimensions) != 3 {
+		t.Errorf("Expected the cached categories and food types and 3 rating dimensions, got %+v", meta)
+	}
+	if d := meta.RatingDimensions[0]; d.Key != "food" || d.Name != "Food" || d.Min != 1 || d.Max != 5 {
+		t.Errorf("Unexpected rating dimension %+v", d)
+	}
+
+	etag := rec.Header().Get("ETag")
+	if rec := get(etag); rec.Code != http.StatusNotModified {
+		t.Errorf("Expected 304 for the current ETag, got %d", rec.Code)
+	}
+
+	// Changing either list changes the ETag
+	cache.Invalidate(ctx, cache.KeyFoodTypes)
+	cache.SetJSON(ctx, cache.KeyFoodTypes, []models.FoodType{{ID: 2, Name: "Pizza"}, {ID: 3, Name: "Ramen"}})
+	if rec := get(etag); rec.Code != http.StatusOK {
+		t.Errorf("Expected 200 after the food types changed, got %d", rec.Code)
+	}
+}
+
+func TestLocalizedRatingDimensions(t *testing.T) {
+	dimensions := localizedRatingDimensions("de")
+	if dimensions[0].Name != "Essen" || dimensions[2].Name != "Ambiente" {
+		t.Errorf("Expected German names, got %+v", dimensions)
+	}
+	if dimensions := localizedRatingDimensions("fr"); dimensions[0].Name != "Food" {
+		t.Errorf("Expected English names for other locales, got %+v", dimensions)
+	}
+}
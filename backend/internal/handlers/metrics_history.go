@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nomdb/backend/internal/database"
+	apperrors "github.com/nomdb/backend/internal/errors"
+	"github.com/nomdb/backend/internal/logger"
+	"github.com/nomdb/backend/internal/models"
+)
+
+// maxMetricsHistoryWindow bounds the number of snapshots returned at once
+const maxMetricsHistoryWindow = 90 * 24 * time.Hour
+
+// parseMetricsWindow parses a window like 7d, 12h or 30m
+func parseMetricsWindow(value string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, err
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	return time.ParseDuration(value)
+}
+
+// @Summary Get metrics history
+// @Description Get the persisted request metrics snapshots of a time window, oldest first. Snapshots are saved every METRICS_PERSIST_INTERVAL, so counts survive restarts; requests and errors are the increase since the previous snapshot.
+// @Tags Health
+// @Produce json
+// @Param window query string false "Time window, e.g. 7d, 12h or 30m (default 24h, max 90d)"
+// @Success 200 {object} models.MetricsHistoryResponse
+// @Failure 400 {object} errors.ErrorResponse "Invalid window"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /metrics/history [get]
+func GetMetricsHistory(w http.ResponseWriter, r *http.Request) {
+	windowParam := r.URL.Query().Get("window")
+	if windowParam == "" {
+		windowParam = "24h"
+	}
+	window, err := parseMetricsWindow(windowParam)
+	if err != nil || window <= 0 || window > maxMetricsHistoryWindow {
+		apperrors.Error(w, "window must be a duration like 7d, 12h or 30m, at most 90d", http.StatusBadRequest)
+		return
+	}
+
+	rows, err := database.GetPool().Query(r.Context(),
+		`SELECT recorded_at, counting_since, total_requests, total_errors, requests_by_status,
+			CASE WHEN response_time_count > 0 THEN response_time_total_ms / response_time_count ELSE 0 END,
+			p50_response_time_ms, p95_response_time_ms, p99_response_time_ms, instance
+		FROM metrics_snapshots WHERE recorded_at >= $1 ORDER BY recorded_at, id`,
+		time.Now().Add(-window))
+	if err != nil {
+		logger.Error("Failed to fetch metrics snapshots: %v", err)
+		apperrors.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	defer rows.Close()
+
+	response := models.MetricsHistoryResponse{Window: windowParam, Snapshots: []models.MetricsSnapshot{}}
+	previousByInstance := map[string]models.MetricsSnapshot{}
+	for rows.Next() {
+		var snapshot models.MetricsSnapshot
+		var countingSince time.Time
+		if err := rows.Scan(&snapshot.RecordedAt, &countingSince, &snapshot.TotalRequests, &snapshot.TotalErrors,
+			&snapshot.RequestsByStatus, &snapshot.AvgResponseTimeMs, &snapshot.P50ResponseTimeMs,
+			&snapshot.P95ResponseTimeMs, &snapshot.P99ResponseTimeMs, &snapshot.Instance); err != nil {
+			logger.Error("Failed to scan metrics snapshot: %v", err)
+			apperrors.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+		response.CountingSince = &countingSince
+
+		if previous, ok := previousByInstance[snapshot.Instance]; ok {
+			requests := counterIncrease(previous.TotalRequests, snapshot.TotalRequests)
+			errorCount := counterIncrease(previous.TotalErrors, snapshot.TotalErrors)
+			snapshot.Requests, snapshot.Errors = &requests, &errorCount
+		}
+		previousByInstance[snapshot.Instance] = snapshot
+		response.Snapshots = append(response.Snapshots, snapshot)
+	}
+	if err := rows.Err(); err != nil {
+		logger.Error("Failed to read metrics snapshots: %v", err)
+		apperrors.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		logger.Error("Failed to encode response: %v", err)
+	}
+}
+
+// counterIncrease returns the increase of a counter, which restarts from zero when the
+// metrics could not be restored
+func counterIncrease(previous, current int64) int64 {
+	if current < previous {
+		return current
+	}
+	return current - previous
+}
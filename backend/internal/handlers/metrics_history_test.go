@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseMetricsWindow(t *testing.T) {
+	tests := map[string]time.Duration{
+		"7d":  7 * 24 * time.Hour,
+		"12h": 12 * time.Hour,
+		"30m": 30 * time.Minute,
+	}
+	for value, expected := range tests {
+		got, err := parseMetricsWindow(value)
+		if err != nil || got != expected {
+			t.Errorf("parseMetricsWindow(%q) = %s, %v; expected %s", value, got, err, expected)
+		}
+	}
+
+	for _, value := range []string{"", "d", "7 days", "1w"} {
+		if _, err := parseMetricsWindow(value); err == nil {
+			t.Errorf("Expected parseMetricsWindow(%q) to fail", value)
+		}
+	}
+}
+
+func TestCounterIncrease(t *testing.T) {
+	if got := counterIncrease(100, 150); got != 50 {
+		t.Errorf("Expected increase of 50, got %d", got)
+	}
+	// The counters restarted from zero
+	if got := counterIncrease(100, 20); got != 20 {
+		t.Errorf("Expected increase of 20 after a reset, got %d", got)
+	}
+}
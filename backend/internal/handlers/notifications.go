@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/nomdb/backend/internal/events"
+	"github.com/nomdb/backend/internal/logger"
+	"github.com/nomdb/backend/internal/services"
+)
+
+// Chat notifications selectable via NOTIFY_EVENTS
+const (
+	NotifySuggestionCreated   = "suggestion.created"
+	NotifySuggestionConverted = "suggestion.converted"
+	NotifyFirstRating         = "rating.first" // First rating of a restaurant
+)
+
+// notifyEventsByBusType maps event bus types to the chat notifications they may trigger
+var notifyEventsByBusType = map[string]string{
+	eventSuggestionCreated:   NotifySuggestionCreated,
+	eventSuggestionConverted: NotifySuggestionConverted,
+	eventRatingCreated:       NotifyFirstRating,
+}
+
+const notifyTimeout = 15 * time.Second
+
+var (
+	chatNotifiers      []services.Notifier
+	chatNotifyEvents   map[string]bool
+	chatNotifyAppURL   string
+	chatNotifyInitOnce sync.Once
+)
+
+// InitNotifications posts the enabled events (Notify* constants) to the chats of
+// notifiers, linking to appURL when set. Without notifiers nothing is posted.
+func InitNotifications(notifiers []services.Notifier, enabled []string, appURL string) {
+	if len(notifiers) == 0 || len(enabled) == 0 {
+		logger.Debug("Chat notifications disabled")
+		return
+	}
+	chatNotifyInitOnce.Do(func() {
+		chatNotifiers = notifiers
+		chatNotifyEvents = make(map[string]bool, len(enabled))
+		for _, event := range enabled {
+			chatNotifyEvents[event] = true
+		}
+		chatNotifyAppURL = appURL
+
+		events.Subscribe(func(e events.Event) {
+			// The instance that published the event posts it
+			if e.Remote {
+				return
+			}
+			if _, ok := notifyEventsByBusType[e.Type]; ok {
+				go postNotification(e)
+			}
+		})
+
+		names := make([]string, 0, len(notifiers))
+		for _, n := range notifiers {
+			names = append(names, n.Name())
+		}
+		logger.Info("✅ Chat notifications to %v for %v", names, enabled)
+	})
+}
+
+// postNotification sends the notification of an event to every chat. Chats are
+// best effort: failures are logged, not retried.
+func postNotification(e events.Event) {
+	ctx, cancel := context.WithTimeout(context.Background(), notifyTimeout)
+	defer cancel()
+
+	n, err := chatNotification(ctx, e)
+	if err != nil {
+		logger.Warn("Failed to prepare chat notification of %s %d: %v", e.Type, e.ID, err)
+		return
+	}
+	if n == nil {
+		return
+	}
+	for _, notifier := range chatNotifiers {
+		if err := notifier.Notify(ctx, *n); err != nil {
+			logger.Warn("⚠️  Failed to post %s notification to %s: %v", e.Type, notifier.Name(), err)
+		}
+	}
+}
+
+// chatNotification describes an event for a chat, or returns nil when the event is not
+// notified. The chats are shared by the instance, so only events of public spaces are
+// posted to them.
+func chatNotification(ctx context.Context, e events.Event) (*services.Notification, error) {
+	if !chatNotifyEvents[notifyEventsByBusType[e.Type]] {
+		return nil, nil
+	}
+	public, err := repos.Spaces.IsPublic(ctx, e.SpaceID)
+	if err != nil {
+		return nil, err
+	}
+	if !public {
+		return nil, nil
+	}
+
+	switch e.Type {
+	case eventSuggestionCreated:
+		sug, err := suggestionRepo.GetByID(ctx, e.ID)
+		if err != nil {
+			return nil, err
+		}
+		n := &services.Notification{Title: "💡 New suggestion: " + sug.Name, URL: chatNotifyAppURL}
+		if sug.Address != nil {
+			n.Text = *sug.Address
+		}
+		return n, nil
+
+	case eventSuggestionConverted:
+		rest, err := restaurantRepo.GetByID(ctx, e.RestaurantID)
+		if err != nil {
+			return nil, err
+		}
+		n := &services.Notification{Title: "🎉 " + rest.Name + " was added to the restaurants", URL: chatNotifyAppURL}
+		if rest.Address != nil {
+			n.Text = *rest.Address
+		}
+		return n, nil
+
+	case eventRatingCreated:
+		rest, err := restaurantRepo.GetByID(ctx, e.RestaurantID)
+		if err != nil {
+			return nil, err
+		}
+		if rest.AvgRating == nil || rest.AvgRating.Count != 1 {
+			return nil, nil
+		}
+		avg := rest.AvgRating
+		return &services.Notification{
+			Title: "⭐ First rating for " + rest.Name,
+			Text:  fmt.Sprintf("%.1f/5 (food %.0f, service %.0f, ambiance %.0f)", avg.Overall, avg.Food, avg.Service, avg.Ambiance),
+			URL:   chatNotifyAppURL,
+		}, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nomdb/backend/internal/events"
+	"github.com/pashagolub/pgxmock/v4"
+)
+
+func withNotifyEvents(t *testing.T, enabled ...string) {
+	t.Helper()
+	chatNotifyEvents = map[string]bool{}
+	for _, event := range enabled {
+		chatNotifyEvents[event] = true
+	}
+	t.Cleanup(func() { chatNotifyEvents = nil })
+}
+
+// expectSpace mocks looking up whether space 1 is public
+func expectSpace(mock pgxmock.PgxPoolIface, public bool) {
+	mock.ExpectQuery(`SELECT public FROM spaces`).WithArgs(1).
+		WillReturnRows(pgxmock.NewRows([]string{"public"}).AddRow(public))
+}
+
+// expectRatedRestaurant mocks loading restaurant 3 with count ratings
+func expectRatedRestaurant(mock pgxmock.PgxPoolIface, count int) {
+	now := time.Now()
+	mock.ExpectQuery(`FROM restaurants r`).WithArgs(3).
+		WillReturnRows(pgxmock.NewRows(append(syncColumns, "c.id", "c.name", "avg_food", "avg_service", "avg_ambiance", "rating_count")).
+			AddRow(3, "Luigi's", nil, nil, nil, nil, nil, nil, nil, nil, now, now, nil, nil, nil, nil, 5.0, 4.0, 4.0, count))
+	mock.ExpectQuery(`FROM food_types ft`).WithArgs(3).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "name", "created_at", "updated_at"}))
+}
+
+func TestChatNotification_FirstRating(t *testing.T) {
+	mock := withMockRepositories(t)
+	withNotifyEvents(t, NotifyFirstRating)
+	expectSpace(mock, true)
+	expectRatedRestaurant(mock, 1)
+
+	n, err := chatNotification(context.Background(), events.Event{Type: eventRatingCreated, ID: 8, RestaurantID: 3, SpaceID: 1})
+	if err != nil {
+		t.Fatalf("chatNotification failed: %v", err)
+	}
+	if n == nil || n.Title != "⭐ First rating for Luigi's" || n.Text != "4.3/5 (food 5, service 4, ambiance 4)" {
+		t.Errorf("Unexpected notification %+v", n)
+	}
+}
+
+func TestChatNotification_LaterRating(t *testing.T) {
+	mock := withMockRepositories(t)
+	withNotifyEvents(t, NotifyFirstRating)
+	expectSpace(mock, true)
+	expectRatedRestaurant(mock, 2)
+
+	n, err := chatNotification(context.Background(), events.Event{Type: eventRatingCreated, ID: 9, RestaurantID: 3, SpaceID: 1})
+	if err != nil || n != nil {
+		t.Errorf("Expected no notification, got %+v, %v", n, err)
+	}
+}
+
+func TestChatNotification_Disabled(t *testing.T) {
+	withMockRepositories(t)
+	withNotifyEvents(t, NotifyFirstRating)
+
+	// Nothing is loaded for events that are not notified
+	n, err := chatNotification(context.Background(), events.Event{Type: eventSuggestionCreated, ID: 4})
+	if err != nil || n != nil {
+		t.Errorf("Expected no notification, got %+v, %v", n, err)
+	}
+}
+
+func TestChatNotification_PrivateSpace(t *testing.T) {
+	mock := withMockRepositories(t)
+	withNotifyEvents(t, NotifySuggestionCreated)
+	expectSpace(mock, false)
+
+	// The instance's chats do not see the suggestions of private spaces
+	n, err := chatNotification(context.Background(), events.Event{Type: eventSuggestionCreated, ID: 4, SpaceID: 1})
+	if err != nil || n != nil {
+		t.Errorf("Expected no notification, got %+v, %v", n, err)
+	}
+}
@@ -3,189 +3,366 @@ package handlers
 import (
 	"context"
 	"crypto/rand"
-	"database/sql"
+	"crypto/subtle"
 	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
-	"os"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/gorilla/mux"
+	"github.com/jackc/pgx/v5"
+	"github.com/nomdb/backend/internal/config"
 	"github.com/nomdb/backend/internal/database"
+	apperrors "github.com/nomdb/backend/internal/errors"
 	"github.com/nomdb/backend/internal/logger"
+	"github.com/nomdb/backend/internal/middleware"
 	"github.com/nomdb/backend/internal/models"
 	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/github"
 )
 
+// gitHubAPIURL is a variable so tests can point it at a fake server
+var gitHubAPIURL = "https://api.github.com"
+
+// errOAuthEmailTaken is returned when a new external identity uses the email of an
+// existing account but the provider has not verified that email
+var errOAuthEmailTaken = errors.New("an account with this email already exists")
+
+// oauthProvider is a configured external login provider
+type oauthProvider struct {
+	name        string
+	displayName string
+	kind        string
+	config      *oauth2.Config
+	verifier    *oidc.IDTokenVerifier // nil for GitHub, which has no ID tokens
+	groupsClaim string
+	adminGroups []string // Empty: admin rights are managed in the database only
+}
+
 var (
-	oidcProvider *oidc.Provider
-	oidcConfig   *oauth2.Config
-	oidcVerifier *oidc.IDTokenVerifier
-	oidcStateStore = make(map[string]time.Time) // In production, use Redis
-)
+	oauthProviders       = make(map[string]*oauthProvider)
+	oauthProviderNames   []string // In configuration order
+	defaultOAuthProvider string   // Used by the unqualified /auth/oidc/login routes
 
-// InitOIDC initializes OIDC provider (Authentik or any OIDC-compliant provider)
-func InitOIDC() error {
-	issuerURL := os.Getenv("OIDC_ISSUER_URL")
-	clientID := os.Getenv("OIDC_CLIENT_ID")
-	clientSecret := os.Getenv("OIDC_CLIENT_SECRET")
-	redirectURL := os.Getenv("OIDC_REDIRECT_URL")
+	oidcCleanup sync.Once
+)
 
-	if issuerURL == "" || clientID == "" || clientSecret == "" {
+// InitOIDC initializes the configured login providers (Authentik, Keycloak, Google,
+// GitHub or any OIDC-compliant provider) and the store for pending logins. Providers that
+// fail to initialize are skipped.
+func InitOIDC(providers []config.OAuthProviderConfig, stateStore string) error {
+	if len(providers) == 0 {
 		logger.Warn("OIDC not configured - OIDC login will not be available")
-		logger.Debug("Required: OIDC_ISSUER_URL, OIDC_CLIENT_ID, OIDC_CLIENT_SECRET")
+		logger.Debug("Required: OIDC_ISSUER_URL, OIDC_CLIENT_ID, OIDC_CLIENT_SECRET or OIDC_PROVIDERS")
 		return nil
 	}
 
-	if redirectURL == "" {
-		redirectURL = "http://localhost:8080/api/auth/oidc/callback"
+	store, err := newOIDCStateStore(stateStore)
+	if err != nil {
+		return err
 	}
+	oidcStates = store
+	logger.Info("🔐 OIDC login state store: %s", stateStore)
 
-	// Initialize OIDC provider
 	ctx := context.Background()
-	provider, err := oidc.NewProvider(ctx, issuerURL)
-	if err != nil {
-		return fmt.Errorf("failed to initialize OIDC provider: %w", err)
+	var failed []string
+	for _, cfg := range providers {
+		provider, err := newOAuthProvider(ctx, cfg)
+		if err != nil {
+			logger.Error("❌ Failed to initialize login provider %s: %v", cfg.Name, err)
+			failed = append(failed, cfg.Name)
+			continue
+		}
+
+		oauthProviders[cfg.Name] = provider
+		oauthProviderNames = append(oauthProviderNames, cfg.Name)
+		if defaultOAuthProvider == "" {
+			defaultOAuthProvider = cfg.Name
+		}
+		logger.Info("🔐 Login provider %s configured (%s)", cfg.Name, cfg.Type)
+		logger.Debug("%s redirect URL: %s", cfg.Name, cfg.RedirectURL)
+		if len(cfg.AdminGroups) > 0 {
+			logger.Info("🔐 %s: admin rights follow the %q claim (admin groups: %s)", cfg.Name, cfg.GroupsClaim, strings.Join(cfg.AdminGroups, ", "))
+		}
+	}
+
+	// Start cleanup task for state store
+	oidcCleanup.Do(func() { go cleanupOIDCStates() })
+
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to initialize login providers: %s", strings.Join(failed, ", "))
 	}
+	return nil
+}
 
-	oidcProvider = provider
-	oidcVerifier = provider.Verifier(&oidc.Config{ClientID: clientID})
+// newOAuthProvider discovers an OIDC issuer or sets up a GitHub OAuth app
+func newOAuthProvider(ctx context.Context, cfg config.OAuthProviderConfig) (*oauthProvider, error) {
+	provider := &oauthProvider{
+		name:        cfg.Name,
+		displayName: cfg.DisplayName,
+		kind:        cfg.Type,
+		groupsClaim: cfg.GroupsClaim,
+		adminGroups: cfg.AdminGroups,
+		config: &oauth2.Config{
+			ClientID:     cfg.ClientID,
+			ClientSecret: cfg.ClientSecret,
+			RedirectURL:  cfg.RedirectURL,
+		},
+	}
 
-	// Configure OAuth2
-	oidcConfig = &oauth2.Config{
-		ClientID:     clientID,
-		ClientSecret: clientSecret,
-		RedirectURL:  redirectURL,
-		Endpoint:     provider.Endpoint(),
-		Scopes:       []string{oidc.ScopeOpenID, "profile", "email"},
+	if cfg.Type == config.OAuthTypeGitHub {
+		provider.config.Endpoint = github.Endpoint
+		provider.config.Scopes = []string{"read:user", "user:email"}
+		return provider, nil
 	}
 
-	logger.Info("🔐 OIDC configured with issuer: %s", issuerURL)
-	logger.Debug("OIDC redirect URL: %s", redirectURL)
+	issuer, err := oidc.NewProvider(ctx, cfg.IssuerURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize OIDC provider: %w", err)
+	}
+	provider.verifier = issuer.Verifier(&oidc.Config{ClientID: cfg.ClientID})
+	provider.config.Endpoint = issuer.Endpoint()
+	provider.config.Scopes = []string{oidc.ScopeOpenID, "profile", "email"}
+	return provider, nil
+}
 
-	// Start cleanup task for state store
-	go cleanupOIDCStates()
+// oauthProviderFromRequest returns the provider named in the URL, or the default one
+// for the unqualified legacy routes
+func oauthProviderFromRequest(r *http.Request) (*oauthProvider, bool) {
+	name := mux.Vars(r)["provider"]
+	if name == "" {
+		name = defaultOAuthProvider
+	}
+	provider, ok := oauthProviders[name]
+	return provider, ok
+}
 
-	return nil
+// @Summary List login providers
+// @Description List the external login providers the frontend can offer, and whether email/password login is enabled
+// @Tags Auth
+// @Produce json
+// @Success 200 {object} models.AuthProvidersResponse
+// @Router /auth/providers [get]
+func GetAuthProviders(w http.ResponseWriter, r *http.Request) {
+	mode := middleware.GetAuthMode()
+	response := models.AuthProvidersResponse{
+		LocalLogin:   mode == middleware.AuthModeLocal || mode == middleware.AuthModeBoth,
+		Registration: settingValue(settingRegistrationMode),
+		Providers:    []models.AuthProvider{},
+	}
+
+	if mode == middleware.AuthModeOAuth || mode == middleware.AuthModeBoth {
+		for _, name := range oauthProviderNames {
+			provider := oauthProviders[name]
+			response.Providers = append(response.Providers, models.AuthProvider{
+				Name:        provider.name,
+				DisplayName: provider.displayName,
+				Type:        provider.kind,
+				LoginURL:    "/api/auth/oidc/" + provider.name + "/login",
+			})
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
 }
 
 // @Summary OIDC login
-// @Description Initiate OIDC/Authentik login flow
+// @Description Initiate the login flow of an external provider. Without a provider in the path, the first configured provider is used.
 // @Tags Auth
 // @Produce json
-// @Success 302 {string} string "Redirect to OIDC provider"
+// @Param provider path string true "Provider name from /auth/providers"
+// @Param invite query string false "Invite code, required to create an account when REGISTRATION_MODE=invite"
+// @Success 302 {string} string "Redirect to the provider"
+// @Failure 404 {object} errors.ErrorResponse "Unknown provider"
+// @Router /auth/oidc/{provider}/login [get]
 // @Router /auth/oidc/login [get]
 func OIDCLogin(w http.ResponseWriter, r *http.Request) {
-	if oidcConfig == nil {
-		http.Error(w, "OIDC not configured", http.StatusServiceUnavailable)
+	if len(oauthProviders) == 0 {
+		apperrors.Error(w, "OIDC not configured", http.StatusServiceUnavailable)
+		return
+	}
+	provider, ok := oauthProviderFromRequest(r)
+	if !ok {
+		apperrors.Error(w, "Unknown login provider", http.StatusNotFound)
 		return
 	}
 
-	// Generate random state
+	// Generate random state and nonce
 	state, err := generateOIDCState()
 	if err != nil {
 		logger.Error("Failed to generate OIDC state: %v", err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		apperrors.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	nonce, err := generateOIDCState()
+	if err != nil {
+		logger.Error("Failed to generate OIDC nonce: %v", err)
+		apperrors.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
 
-	// Store state with expiry
-	oidcStateStore[state] = time.Now().Add(10 * time.Minute)
+	// Store the pending login with its PKCE verifier
+	pending := oidcState{
+		Provider:     provider.name,
+		CodeVerifier: oauth2.GenerateVerifier(),
+		Nonce:        nonce,
+		ExpiresAt:    time.Now().Add(oidcStateTTL),
+	}
+	if invite := r.URL.Query().Get("invite"); invite != "" {
+		pending.InviteHash = hashInviteCode(invite)
+	}
+	if err := oidcStates.Save(r.Context(), state, pending); err != nil {
+		logger.Error("Failed to store OIDC state: %v", err)
+		apperrors.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
 
-	// Redirect to OIDC provider
-	url := oidcConfig.AuthCodeURL(state, oauth2.AccessTypeOffline)
+	// Redirect to the provider
+	opts := []oauth2.AuthCodeOption{oauth2.AccessTypeOffline, oauth2.S256ChallengeOption(pending.CodeVerifier)}
+	if provider.verifier != nil {
+		opts = append(opts, oidc.Nonce(nonce))
+	}
+	url := provider.config.AuthCodeURL(state, opts...)
 	http.Redirect(w, r, url, http.StatusTemporaryRedirect)
 }
 
 // @Summary OIDC callback
-// @Description Handle OIDC/Authentik callback
+// @Description Handle the callback of an external login provider. If OIDC_FRONTEND_REDIRECT_URL is set, the refresh token is set as an HttpOnly cookie and the browser is redirected to the frontend (with ?login=success or ?error=<code>); otherwise the tokens are returned as JSON.
 // @Tags Auth
 // @Produce json
+// @Param provider path string true "Provider name"
 // @Param code query string true "Authorization code"
 // @Param state query string true "OIDC state"
 // @Success 200 {object} models.LoginResponse
-// @Failure 400 {string} string "Invalid request"
-// @Failure 401 {string} string "Invalid state or code"
-// @Failure 500 {string} string "Internal server error"
+// @Success 303 {string} string "Redirect to the frontend"
+// @Failure 400 {object} errors.ErrorResponse "Invalid request"
+// @Failure 401 {object} errors.ErrorResponse "Invalid state or code"
+// @Failure 404 {object} errors.ErrorResponse "Unknown provider"
+// @Failure 403 {object} errors.ErrorResponse "Registration closed or invalid invite"
+// @Failure 409 {object} errors.ErrorResponse "Email belongs to another account"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /auth/oidc/{provider}/callback [get]
 // @Router /auth/oidc/callback [get]
 func OIDCCallback(w http.ResponseWriter, r *http.Request) {
-	if oidcConfig == nil || oidcVerifier == nil {
-		http.Error(w, "OIDC not configured", http.StatusServiceUnavailable)
+	if len(oauthProviders) == 0 {
+		oidcCallbackError(w, r, "not_configured", "OIDC not configured", http.StatusServiceUnavailable)
+		return
+	}
+	provider, ok := oauthProviderFromRequest(r)
+	if !ok {
+		oidcCallbackError(w, r, "unknown_provider", "Unknown login provider", http.StatusNotFound)
 		return
 	}
 
-	// Verify state
+	// Verify state, which must have been issued for this provider
 	state := r.URL.Query().Get("state")
-	expiry, exists := oidcStateStore[state]
-	if !exists || time.Now().After(expiry) {
-		http.Error(w, "Invalid or expired state", http.StatusBadRequest)
+	if state == "" {
+		oidcCallbackError(w, r, "invalid_state", "Invalid or expired state", http.StatusBadRequest)
 		return
 	}
-	delete(oidcStateStore, state)
-
-	// Get authorization code
-	code := r.URL.Query().Get("code")
-	if code == "" {
-		http.Error(w, "Missing authorization code", http.StatusBadRequest)
+	pending, err := oidcStates.Take(r.Context(), state)
+	if err != nil {
+		logger.Error("Failed to load OIDC state: %v", err)
+		oidcCallbackError(w, r, "server_error", "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if pending == nil || time.Now().After(pending.ExpiresAt) || pending.Provider != provider.name {
+		oidcCallbackError(w, r, "invalid_state", "Invalid or expired state", http.StatusBadRequest)
 		return
 	}
 
-	// Exchange code for token
-	ctx := context.Background()
-	oauth2Token, err := oidcConfig.Exchange(ctx, code)
-	if err != nil {
-		logger.Error("Failed to exchange code: %v", err)
-		http.Error(w, "Failed to exchange authorization code", http.StatusInternalServerError)
+	// The provider reports a denied or failed authorization instead of a code
+	if providerError := r.URL.Query().Get("error"); providerError != "" {
+		logger.Warn("Login with %s failed: %s", provider.name, providerError)
+		oidcCallbackError(w, r, "access_denied", "Authorization failed: "+providerError, http.StatusUnauthorized)
 		return
 	}
 
-	// Extract ID Token
-	rawIDToken, ok := oauth2Token.Extra("id_token").(string)
-	if !ok {
-		http.Error(w, "No id_token in response", http.StatusInternalServerError)
+	// Get authorization code
+	code := r.URL.Query().Get("code")
+	if code == "" {
+		oidcCallbackError(w, r, "missing_code", "Missing authorization code", http.StatusBadRequest)
 		return
 	}
 
-	// Verify ID Token
-	idToken, err := oidcVerifier.Verify(ctx, rawIDToken)
+	// Exchange code for token
+	ctx := r.Context()
+	oauth2Token, err := provider.config.Exchange(ctx, code, oauth2.VerifierOption(pending.CodeVerifier))
 	if err != nil {
-		logger.Error("Failed to verify ID token: %v", err)
-		http.Error(w, "Failed to verify ID token", http.StatusUnauthorized)
+		logger.Error("Failed to exchange code with %s: %v", provider.name, err)
+		oidcCallbackError(w, r, "exchange_failed", "Failed to exchange authorization code", http.StatusInternalServerError)
 		return
 	}
 
-	// Extract claims
-	var claims struct {
-		Email         string `json:"email"`
-		EmailVerified bool   `json:"email_verified"`
-		Name          string `json:"name"`
-		PreferredUsername string `json:"preferred_username"`
-		Picture       string `json:"picture"`
-		Sub           string `json:"sub"` // Subject - unique user ID
-	}
-	if err := idToken.Claims(&claims); err != nil {
-		logger.Error("Failed to parse claims: %v", err)
-		http.Error(w, "Failed to parse user information", http.StatusInternalServerError)
+	claims, err := provider.identity(ctx, oauth2Token, pending.Nonce)
+	if err != nil {
+		logger.Error("Failed to read identity from %s: %v", provider.name, err)
+		oidcCallbackError(w, r, "invalid_identity", "Failed to verify user information", http.StatusUnauthorized)
 		return
 	}
 
 	// Validate required claims
 	if claims.Email == "" || claims.Sub == "" {
-		http.Error(w, "Missing required user information (email or sub)", http.StatusBadRequest)
+		oidcCallbackError(w, r, "missing_claims", "Missing required user information (email or sub)", http.StatusBadRequest)
 		return
 	}
+	claims.Email = strings.ToLower(strings.TrimSpace(claims.Email))
 
 	// Find or create user
-	user, err := findOrCreateOIDCUser(ctx, &claims)
+	user, err := findOrCreateOIDCUser(ctx, provider.name, claims, pending.InviteHash)
 	if err != nil {
+		if errors.Is(err, errOAuthEmailTaken) {
+			recordLoginFailure(ctx, r, nil, provider.name, claims.Email, "email_taken")
+			oidcCallbackError(w, r, "email_taken", "An account with this email already exists. Sign in with it first to link this provider.", http.StatusConflict)
+			return
+		}
+		if errors.Is(err, errRegistrationClosed) {
+			oidcCallbackError(w, r, "registration_closed", "Registration is closed", http.StatusForbidden)
+			return
+		}
+		if errors.Is(err, errInvalidInvite) {
+			oidcCallbackError(w, r, "invalid_invite", "A valid invite code is required to register", http.StatusForbidden)
+			return
+		}
 		logger.Error("Failed to find/create user: %v", err)
-		http.Error(w, "Failed to process user", http.StatusInternalServerError)
+		oidcCallbackError(w, r, "server_error", "Failed to process user", http.StatusInternalServerError)
+		return
+	}
+
+	if !user.IsActive {
+		recordLoginFailure(ctx, r, &user.ID, provider.name, claims.Email, "account_disabled")
+		oidcCallbackError(w, r, "account_disabled", "Account is disabled", http.StatusUnauthorized)
 		return
 	}
 
+	// Keep admin rights in sync with the provider's groups
+	wasAdmin := user.IsAdmin
+	if err := provider.syncAdminFromGroups(ctx, user, claims.Groups); err != nil {
+		logger.Error("Failed to update admin rights: %v", err)
+		oidcCallbackError(w, r, "server_error", "Failed to process user", http.StatusInternalServerError)
+		return
+	}
+	if user.IsAdmin != wasAdmin {
+		event := AuditRoleGranted
+		if !user.IsAdmin {
+			event = AuditRoleRevoked
+		}
+		recordAudit(ctx, r, auditEvent{
+			Event:      event,
+			ActorID:    &user.ID,
+			TargetType: "user",
+			TargetID:   strconv.Itoa(user.ID),
+			Details:    map[string]any{"role": "admin", "source": provider.name + " groups"},
+		})
+	}
+
 	// Update last login
 	_, err = database.GetPool().Exec(ctx, "UPDATE users SET last_login_at = $1 WHERE id = $2", time.Now(), user.ID)
 	if err != nil {
@@ -195,63 +372,244 @@ func OIDCCallback(w http.ResponseWriter, r *http.Request) {
 	// Generate tokens
 	jwtService := getJWTService()
 	if jwtService == nil {
-		http.Error(w, "Authentication service not available", http.StatusInternalServerError)
+		oidcCallbackError(w, r, "server_error", "Authentication service not available", http.StatusInternalServerError)
 		return
 	}
-	response, err := generateLoginResponseWithService(ctx, user, r, jwtService)
+	response, err := generateLoginResponseWithService(ctx, user, r, jwtService, false)
 	if err != nil {
 		logger.Error("Failed to generate tokens: %v", err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		oidcCallbackError(w, r, "server_error", "Internal server error", http.StatusInternalServerError)
 		return
 	}
 
-	logger.Info("User logged in via OIDC: %s (ID: %d)", user.Email, user.ID)
+	logger.Info("User logged in via %s: %s (ID: %d)", provider.name, user.Email, user.ID)
+	recordAudit(ctx, r, auditEvent{
+		Event:      AuditLogin,
+		ActorID:    &user.ID,
+		TargetType: "user",
+		TargetID:   strconv.Itoa(user.ID),
+		Details:    map[string]any{"method": provider.name},
+	})
+
+	// Browser logins: hand the refresh token over in an HttpOnly cookie and send the
+	// user back to the frontend, which obtains an access token from /auth/refresh
+	if oidcFrontendRedirectURL != "" {
+		setRefreshTokenCookie(w, response.RefreshToken, jwtService.GetRefreshTokenDuration())
+		redirectToFrontend(w, r, "")
+		return
+	}
 
-	// In a real app, you might redirect to frontend with tokens in URL params or cookies
-	// For now, return JSON
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
 }
 
 // Helper functions
 
+// oidcCallbackError reports a failed login, as a redirect to the frontend if one is configured
+func oidcCallbackError(w http.ResponseWriter, r *http.Request, code, message string, status int) {
+	if oidcFrontendRedirectURL != "" {
+		redirectToFrontend(w, r, code)
+		return
+	}
+	apperrors.Error(w, message, status)
+}
+
+// OIDCClaims is the user information received from a login provider
 type OIDCClaims struct {
-	Email             string
-	EmailVerified     bool
-	Name              string
-	PreferredUsername string
-	Picture           string
-	Sub               string
+	Email             string   `json:"email"`
+	EmailVerified     bool     `json:"email_verified"`
+	Name              string   `json:"name"`
+	PreferredUsername string   `json:"preferred_username"`
+	Picture           string   `json:"picture"`
+	Sub               string   `json:"sub"` // Subject - unique user ID at the provider
+	Groups            []string `json:"-"`   // From the provider's configured groups claim
 }
 
-func findOrCreateOIDCUser(ctx context.Context, claims *struct {
-	Email             string `json:"email"`
-	EmailVerified     bool   `json:"email_verified"`
-	Name              string `json:"name"`
-	PreferredUsername string `json:"preferred_username"`
-	Picture           string `json:"picture"`
-	Sub               string `json:"sub"`
-}) (*models.User, error) {
-	provider := "oidc"
-
-	// Try to find existing user by provider ID
+// identity returns the verified user information for an access token. For OIDC
+// providers, the ID token must carry the nonce sent with the login request.
+func (p *oauthProvider) identity(ctx context.Context, token *oauth2.Token, nonce string) (*OIDCClaims, error) {
+	if p.verifier == nil {
+		return fetchGitHubIdentity(ctx, p.config.Client(ctx, token))
+	}
+
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return nil, errors.New("no id_token in response")
+	}
+	idToken, err := p.verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify ID token: %w", err)
+	}
+	if subtle.ConstantTimeCompare([]byte(idToken.Nonce), []byte(nonce)) != 1 {
+		return nil, errors.New("ID token nonce does not match")
+	}
+
+	var claims OIDCClaims
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, fmt.Errorf("failed to parse claims: %w", err)
+	}
+	if len(p.adminGroups) > 0 {
+		var raw map[string]any
+		if err := idToken.Claims(&raw); err != nil {
+			return nil, fmt.Errorf("failed to parse claims: %w", err)
+		}
+		claims.Groups = claimStrings(raw, p.groupsClaim)
+	}
+	return &claims, nil
+}
+
+// claimStrings returns the string values of a claim, following dots into nested objects
+// (e.g. "realm_access.roles" for Keycloak). A single string is returned as one value.
+func claimStrings(claims map[string]any, path string) []string {
+	var value any = claims
+	for _, key := range strings.Split(path, ".") {
+		object, ok := value.(map[string]any)
+		if !ok {
+			return nil
+		}
+		value = object[key]
+	}
+
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+	case []any:
+		values := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
+
+// isAdminByGroups reports whether any of groups is one of the provider's admin groups
+func (p *oauthProvider) isAdminByGroups(groups []string) bool {
+	for _, group := range groups {
+		for _, adminGroup := range p.adminGroups {
+			if group == adminGroup {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// syncAdminFromGroups grants or revokes admin rights according to the user's groups at
+// the provider. Without configured admin groups, admin rights are left unchanged.
+func (p *oauthProvider) syncAdminFromGroups(ctx context.Context, user *models.User, groups []string) error {
+	if len(p.adminGroups) == 0 {
+		return nil
+	}
+	isAdmin := p.isAdminByGroups(groups)
+	if user.IsAdmin == isAdmin {
+		return nil
+	}
+
+	if _, err := database.GetPool().Exec(ctx,
+		"UPDATE users SET is_admin = $1, updated_at = NOW() WHERE id = $2", isAdmin, user.ID); err != nil {
+		return err
+	}
+	user.IsAdmin = isAdmin
+	if isAdmin {
+		logger.Info("👑 %s granted admin rights by %s groups", user.Username, p.name)
+	} else {
+		logger.Info("👑 %s lost admin rights: not in an admin group of %s", user.Username, p.name)
+	}
+	return nil
+}
+
+// fetchGitHubIdentity reads the profile and primary email of a GitHub user
+func fetchGitHubIdentity(ctx context.Context, client *http.Client) (*OIDCClaims, error) {
+	var profile struct {
+		ID        int64  `json:"id"`
+		Login     string `json:"login"`
+		Name      string `json:"name"`
+		Email     string `json:"email"`
+		AvatarURL string `json:"avatar_url"`
+	}
+	if err := getGitHubJSON(ctx, client, "/user", &profile); err != nil {
+		return nil, err
+	}
+
+	claims := &OIDCClaims{
+		Sub:               strconv.FormatInt(profile.ID, 10),
+		Name:              profile.Name,
+		PreferredUsername: profile.Login,
+		Picture:           profile.AvatarURL,
+		Email:             profile.Email,
+	}
+
+	// The profile email is optional and may be unverified, prefer the verified primary
+	var emails []struct {
+		Email    string `json:"email"`
+		Primary  bool   `json:"primary"`
+		Verified bool   `json:"verified"`
+	}
+	if err := getGitHubJSON(ctx, client, "/user/emails", &emails); err != nil {
+		return nil, err
+	}
+	for _, e := range emails {
+		if e.Verified && (e.Primary || !claims.EmailVerified) {
+			claims.Email = e.Email
+			claims.EmailVerified = true
+		}
+	}
+
+	return claims, nil
+}
+
+func getGitHubJSON(ctx context.Context, client *http.Client, path string, target any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, gitHubAPIURL+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("GitHub request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GitHub %s returned %d", path, resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(target)
+}
+
+// findOrCreateOIDCUser resolves an external identity to an account. Identities are
+// keyed by provider and subject; a new identity is linked to an existing account with
+// the same email only if the provider verified that email.
+func findOrCreateOIDCUser(ctx context.Context, provider string, claims *OIDCClaims, inviteHash string) (*models.User, error) {
+	pool := database.GetPool()
+
+	// Try to find existing user by provider identity
 	var user models.User
-	err := database.GetPool().QueryRow(ctx,
-		`SELECT id, email, username, provider, provider_id, full_name, avatar_url,
-		is_active, is_admin, email_verified, last_login_at, created_at, updated_at
-		FROM users WHERE provider = $1 AND provider_id = $2`,
+	err := pool.QueryRow(ctx,
+		`SELECT u.id, u.email, u.username, u.provider, u.provider_id, u.full_name, u.avatar_url,
+		u.is_active, u.is_admin, u.email_verified, u.last_login_at, u.created_at, u.updated_at
+		FROM user_identities i JOIN users u ON u.id = i.user_id
+		WHERE i.provider = $1 AND i.subject = $2`,
 		provider, claims.Sub).Scan(
 		&user.ID, &user.Email, &user.Username, &user.Provider, &user.ProviderID,
 		&user.FullName, &user.AvatarURL, &user.IsActive, &user.IsAdmin,
 		&user.EmailVerified, &user.LastLoginAt, &user.CreatedAt, &user.UpdatedAt)
 
 	if err == nil {
-		// User exists, update info if changed
-		if user.Email != claims.Email || (user.FullName != nil && *user.FullName != claims.Name) {
-			_, err = database.GetPool().Exec(ctx,
-				`UPDATE users SET email = $1, full_name = $2, avatar_url = $3, email_verified = $4
-				WHERE id = $5`,
-				claims.Email, claims.Name, claims.Picture, claims.EmailVerified, user.ID)
+		if _, err := pool.Exec(ctx,
+			"UPDATE user_identities SET email = $1, last_login_at = NOW() WHERE provider = $2 AND subject = $3",
+			claims.Email, provider, claims.Sub); err != nil {
+			logger.Warn("Failed to update identity: %v", err)
+		}
+
+		// Only the provider the account was created with owns the profile
+		if user.Provider == provider && (user.FullName == nil || *user.FullName != claims.Name) {
+			_, err = pool.Exec(ctx,
+				`UPDATE users SET full_name = $1, avatar_url = $2 WHERE id = $3`,
+				claims.Name, claims.Picture, user.ID)
 			if err != nil {
 				logger.Warn("Failed to update user info: %v", err)
 			}
@@ -259,46 +617,106 @@ func findOrCreateOIDCUser(ctx context.Context, claims *struct {
 		return &user, nil
 	}
 
-	if !errors.Is(err, sql.ErrNoRows) {
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+
+	// Link the identity to an existing account with the same email
+	var userID int
+	err = pool.QueryRow(ctx, `SELECT id FROM users WHERE lower(email) = lower($1)`, claims.Email).Scan(&userID)
+	if err == nil {
+		if !claims.EmailVerified {
+			return nil, errOAuthEmailTaken
+		}
+		if err := linkIdentity(ctx, userID, provider, claims); err != nil {
+			return nil, err
+		}
+		logger.Info("Linked %s identity to existing account %d", provider, userID)
+		return getUserByID(ctx, userID)
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
 		return nil, err
 	}
 
 	// User doesn't exist, create new one
-	// Generate username from email or preferred_username
-	username := claims.PreferredUsername
-	if username == "" {
-		username = generateUsernameFromEmail(claims.Email)
+	// Generate username from preferred_username or email
+	base := claims.PreferredUsername
+	if base == "" {
+		base = generateUsernameFromEmail(claims.Email)
+	}
+	username, err := availableUsername(ctx, base)
+	if err != nil {
+		return nil, err
 	}
 
-	var userID int
-	err = database.GetPool().QueryRow(ctx,
-		`INSERT INTO users (email, username, provider, provider_id, full_name, avatar_url, email_verified, password_hash)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, NULL)
-		RETURNING id`,
-		claims.Email, username, provider, claims.Sub, claims.Name, claims.Picture, claims.EmailVerified).Scan(&userID)
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
 
+	inviteID, err := admitNewUser(ctx, tx, inviteHash)
 	if err != nil {
-		// Check if email already exists (user might have registered locally)
-		err2 := database.GetPool().QueryRow(ctx,
-			`SELECT id FROM users WHERE email = $1`, claims.Email).Scan(&userID)
-		if err2 == nil {
-			// Link OIDC to existing account
-			_, err = database.GetPool().Exec(ctx,
-				`UPDATE users SET provider = $1, provider_id = $2, avatar_url = $3
-				WHERE id = $4`,
-				provider, claims.Sub, claims.Picture, userID)
-			if err != nil {
-				return nil, err
-			}
-		} else {
-			return nil, err
-		}
+		return nil, err
+	}
+
+	err = tx.QueryRow(ctx,
+		`INSERT INTO users (email, username, provider, provider_id, full_name, avatar_url, email_verified, password_hash, invite_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NULL, $8)
+		RETURNING id`,
+		claims.Email, username, provider, claims.Sub, claims.Name, claims.Picture, claims.EmailVerified, inviteID).Scan(&userID)
+	if err != nil {
+		return nil, err
+	}
+	_, err = tx.Exec(ctx,
+		`INSERT INTO user_identities (user_id, provider, subject, email, last_login_at) VALUES ($1, $2, $3, $4, NOW())`,
+		userID, provider, claims.Sub, claims.Email)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
 	}
 
 	// Fetch created user
 	return getUserByID(ctx, userID)
 }
 
+// linkIdentity attaches an external identity to an account
+func linkIdentity(ctx context.Context, userID int, provider string, claims *OIDCClaims) error {
+	_, err := database.GetPool().Exec(ctx,
+		`INSERT INTO user_identities (user_id, provider, subject, email, last_login_at)
+		VALUES ($1, $2, $3, $4, NOW())`,
+		userID, provider, claims.Sub, claims.Email)
+	return err
+}
+
+// availableUsername returns base, or base with a numeric suffix if it is taken
+func availableUsername(ctx context.Context, base string) (string, error) {
+	base = strings.TrimSpace(base)
+	if base == "" {
+		base = "user"
+	}
+	if len(base) > 90 {
+		base = base[:90]
+	}
+
+	candidate := base
+	for i := 2; i <= 100; i++ {
+		var taken bool
+		err := database.GetPool().QueryRow(ctx,
+			"SELECT EXISTS(SELECT 1 FROM users WHERE username = $1)", candidate).Scan(&taken)
+		if err != nil {
+			return "", err
+		}
+		if !taken {
+			return candidate, nil
+		}
+		candidate = fmt.Sprintf("%s%d", base, i)
+	}
+	return "", fmt.Errorf("no available username for %q", base)
+}
+
 func generateOIDCState() (string, error) {
 	b := make([]byte, 32)
 	if _, err := rand.Read(b); err != nil {
@@ -321,11 +739,8 @@ func cleanupOIDCStates() {
 	defer ticker.Stop()
 
 	for range ticker.C {
-		now := time.Now()
-		for state, expiry := range oidcStateStore {
-			if now.After(expiry) {
-				delete(oidcStateStore, state)
-			}
+		if err := oidcStates.Cleanup(context.Background()); err != nil {
+			logger.Warn("Failed to clean up OIDC states: %v", err)
 		}
 	}
 }
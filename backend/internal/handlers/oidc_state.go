@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/nomdb/backend/internal/database"
+)
+
+// OIDC state store backends selectable via OIDC_STATE_STORE
+const (
+	OIDCStateStoreDatabase = "database" // Shared by all replicas and survives restarts
+	OIDCStateStoreMemory   = "memory"   // Single instance only
+)
+
+// oidcStateTTL is how long a user has to complete a login at the provider
+const oidcStateTTL = 10 * time.Minute
+
+// oidcState is a pending login, keyed by the state parameter
+type oidcState struct {
+	Provider     string
+	CodeVerifier string // PKCE verifier sent with the code exchange
+	Nonce        string // Must match the nonce claim of the ID token
+	InviteHash   string // Invite presented with the login, redeemed if it creates an account
+	ExpiresAt    time.Time
+}
+
+// oidcStateStore keeps pending logins between the redirect to the provider and its callback
+type oidcStateStore interface {
+	// Save stores a pending login under state
+	Save(ctx context.Context, state string, pending oidcState) error
+	// Take removes and returns the pending login of state, or nil if there is none.
+	// Expired logins are returned too; callers must check ExpiresAt.
+	Take(ctx context.Context, state string) (*oidcState, error)
+	// Cleanup removes expired logins
+	Cleanup(ctx context.Context) error
+}
+
+var oidcStates oidcStateStore = newMemoryOIDCStateStore()
+
+// newOIDCStateStore returns the state store for an OIDC_STATE_STORE value
+func newOIDCStateStore(backend string) (oidcStateStore, error) {
+	switch backend {
+	case OIDCStateStoreDatabase:
+		return databaseOIDCStateStore{}, nil
+	case OIDCStateStoreMemory:
+		return newMemoryOIDCStateStore(), nil
+	default:
+		return nil, fmt.Errorf("unknown OIDC state store %q", backend)
+	}
+}
+
+// memoryOIDCStateStore keeps pending logins in process memory
+type memoryOIDCStateStore struct {
+	mu     sync.Mutex
+	states map[string]oidcState
+}
+
+func newMemoryOIDCStateStore() *memoryOIDCStateStore {
+	return &memoryOIDCStateStore{states: make(map[string]oidcState)}
+}
+
+func (s *memoryOIDCStateStore) Save(ctx context.Context, state string, pending oidcState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.states[state] = pending
+	return nil
+}
+
+func (s *memoryOIDCStateStore) Take(ctx context.Context, state string) (*oidcState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pending, ok := s.states[state]
+	if !ok {
+		return nil, nil
+	}
+	delete(s.states, state)
+	return &pending, nil
+}
+
+func (s *memoryOIDCStateStore) Cleanup(ctx context.Context) error {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for state, pending := range s.states {
+		if now.After(pending.ExpiresAt) {
+			delete(s.states, state)
+		}
+	}
+	return nil
+}
+
+// databaseOIDCStateStore keeps pending logins in oidc_login_states. Only a hash of the
+// state is stored.
+type databaseOIDCStateStore struct{}
+
+func hashOIDCState(state string) string {
+	sum := sha256.Sum256([]byte(state))
+	return hex.EncodeToString(sum[:])
+}
+
+func (databaseOIDCStateStore) Save(ctx context.Context, state string, pending oidcState) error {
+	_, err := database.GetPool().Exec(ctx,
+		`INSERT INTO oidc_login_states (state_hash, provider, code_verifier, nonce, invite_hash, expires_at)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6)`,
+		hashOIDCState(state), pending.Provider, pending.CodeVerifier, pending.Nonce, pending.InviteHash, pending.ExpiresAt)
+	return err
+}
+
+func (databaseOIDCStateStore) Take(ctx context.Context, state string) (*oidcState, error) {
+	var pending oidcState
+	err := database.GetPool().QueryRow(ctx,
+		`DELETE FROM oidc_login_states WHERE state_hash = $1
+		RETURNING provider, code_verifier, nonce, COALESCE(invite_hash, ''), expires_at`,
+		hashOIDCState(state)).Scan(&pending.Provider, &pending.CodeVerifier, &pending.Nonce, &pending.InviteHash, &pending.ExpiresAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &pending, nil
+}
+
+func (databaseOIDCStateStore) Cleanup(ctx context.Context) error {
+	_, err := database.GetPool().Exec(ctx, "DELETE FROM oidc_login_states WHERE expires_at < NOW()")
+	return err
+}
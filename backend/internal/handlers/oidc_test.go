@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestFetchGitHubIdentity(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/user":
+			w.Write([]byte(`{"id": 583231, "login": "octocat", "name": "The Octocat", "email": "public@example.com", "avatar_url": "https://example.com/a.png"}`))
+		case "/user/emails":
+			w.Write([]byte(`[
+				{"email": "old@example.com", "primary": false, "verified": true},
+				{"email": "octocat@example.com", "primary": true, "verified": true},
+				{"email": "unverified@example.com", "primary": false, "verified": false}
+			]`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	defer func(original string) { gitHubAPIURL = original }(gitHubAPIURL)
+	gitHubAPIURL = server.URL
+
+	claims, err := fetchGitHubIdentity(context.Background(), server.Client())
+	if err != nil {
+		t.Fatalf("Failed to fetch identity: %v", err)
+	}
+
+	if claims.Sub != "583231" || claims.PreferredUsername != "octocat" {
+		t.Errorf("Unexpected identity: %+v", claims)
+	}
+	if claims.Email != "octocat@example.com" || !claims.EmailVerified {
+		t.Errorf("Expected the verified primary email, got %s (verified: %v)", claims.Email, claims.EmailVerified)
+	}
+}
+
+func TestMemoryOIDCStateStore(t *testing.T) {
+	ctx := context.Background()
+	store := newMemoryOIDCStateStore()
+
+	pending := oidcState{Provider: "google", CodeVerifier: "verifier", Nonce: "nonce", ExpiresAt: time.Now().Add(oidcStateTTL)}
+	if err := store.Save(ctx, "state", pending); err != nil {
+		t.Fatalf("Failed to save state: %v", err)
+	}
+	if err := store.Save(ctx, "expired", oidcState{Provider: "google", ExpiresAt: time.Now().Add(-time.Minute)}); err != nil {
+		t.Fatalf("Failed to save state: %v", err)
+	}
+
+	got, err := store.Take(ctx, "state")
+	if err != nil || got == nil {
+		t.Fatalf("Expected the pending login, got %v (err: %v)", got, err)
+	}
+	if *got != pending {
+		t.Errorf("Expected %+v, got %+v", pending, *got)
+	}
+
+	// States are single-use
+	if got, _ := store.Take(ctx, "state"); got != nil {
+		t.Error("Expected a state to be consumed by Take")
+	}
+
+	if err := store.Cleanup(ctx); err != nil {
+		t.Fatalf("Failed to clean up: %v", err)
+	}
+	if got, _ := store.Take(ctx, "expired"); got != nil {
+		t.Error("Expected Cleanup to remove expired states")
+	}
+}
+
+func TestNewOIDCStateStore(t *testing.T) {
+	if _, err := newOIDCStateStore(OIDCStateStoreMemory); err != nil {
+		t.Errorf("Expected memory store, got %v", err)
+	}
+	if _, err := newOIDCStateStore("redis"); err == nil {
+		t.Error("Expected an error for an unknown store")
+	}
+}
+
+func TestClaimStrings(t *testing.T) {
+	claims := map[string]any{
+		"groups":       []any{"nomdb-admins", "staff", 42},
+		"role":         "admin",
+		"realm_access": map[string]any{"roles": []any{"offline_access", "nomdb-admin"}},
+	}
+
+	tests := []struct {
+		path     string
+		expected []string
+	}{
+		{"groups", []string{"nomdb-admins", "staff"}},
+		{"role", []string{"admin"}},
+		{"realm_access.roles", []string{"offline_access", "nomdb-admin"}},
+		{"realm_access.missing", nil},
+		{"role.nested", nil},
+		{"missing", nil},
+	}
+
+	for _, tt := range tests {
+		got := claimStrings(claims, tt.path)
+		if len(got) != len(tt.expected) {
+			t.Errorf("claimStrings(%q) = %v, expected %v", tt.path, got, tt.expected)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.expected[i] {
+				t.Errorf("claimStrings(%q) = %v, expected %v", tt.path, got, tt.expected)
+				break
+			}
+		}
+	}
+}
+
+func TestIsAdminByGroups(t *testing.T) {
+	provider := &oauthProvider{adminGroups: []string{"nomdb-admins", "ops"}}
+
+	if !provider.isAdminByGroups([]string{"staff", "ops"}) {
+		t.Error("Expected a member of an admin group to be an admin")
+	}
+	if provider.isAdminByGroups([]string{"staff"}) {
+		t.Error("Expected a user outside the admin groups not to be an admin")
+	}
+	if provider.isAdminByGroups(nil) {
+		t.Error("Expected a user without groups not to be an admin")
+	}
+}
@@ -1,12 +1,20 @@
 package handlers
 
 import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 
+	"github.com/nomdb/backend/internal/logger"
 	"github.com/nomdb/backend/internal/models"
+	"github.com/nomdb/backend/internal/repository"
 )
 
 const (
@@ -76,3 +84,93 @@ func BuildPaginatedResponse(data interface{}, hasMore bool, nextID int) models.P
 
 	return response
 }
+
+// cursorKey signs sort cursors, so clients cannot craft sort values
+var cursorKey []byte
+
+// InitCursorSigning derives the sort cursor signing key from secret (the JWT secret).
+// Without a secret a random key is used, and cursors only work on this instance until
+// it restarts.
+func InitCursorSigning(secret string) {
+	if secret == "" {
+		cursorKey = make([]byte, 32)
+		rand.Read(cursorKey)
+		logger.Warn("⚠️  No secret for pagination cursors - using a random key, cursors will not work across instances or restarts")
+		return
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("nomdb pagination cursor"))
+	cursorKey = mac.Sum(nil)
+}
+
+// sortCursor is the payload of a sort cursor
+type sortCursor struct {
+	Sort  repository.RestaurantSort `json:"s"`
+	Value any                       `json:"v,omitempty"`
+	ID    int                       `json:"i"`
+}
+
+// errCursorSort is returned for a cursor of another sort order than requested
+var errCursorSort = errors.New("cursor does not match sort")
+
+// EncodeSortCursor creates a signed cursor pointing after key in the sort order:
+// base64url(JSON) "." base64url(HMAC-SHA256)
+func EncodeSortCursor(sort repository.RestaurantSort, key repository.PageKey) string {
+	payload, _ := json.Marshal(sortCursor{Sort: sort, Value: key.Value, ID: key.ID})
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(signCursor(encoded))
+}
+
+// DecodeSortCursor verifies a cursor from EncodeSortCursor for the sort order. For
+// SortByID, plain ID cursors from EncodeCursor are accepted too.
+func DecodeSortCursor(cursor string, sort repository.RestaurantSort) (*repository.PageKey, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+	encoded, signature, ok := strings.Cut(cursor, ".")
+	if !ok {
+		if sort != repository.SortByID {
+			return nil, errCursorSort
+		}
+		id, err := DecodeCursor(cursor)
+		if err != nil {
+			return nil, err
+		}
+		return &repository.PageKey{ID: id}, nil
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(sig, signCursor(encoded)) {
+		return nil, errors.New("invalid cursor signature")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+	var c sortCursor
+	if err := json.Unmarshal(payload, &c); err != nil {
+		return nil, err
+	}
+	if c.Sort != sort {
+		return nil, errCursorSort
+	}
+
+	// JSON numbers decode as float64, which is what rating keys are
+	switch sort {
+	case repository.SortByName:
+		if _, ok := c.Value.(string); !ok {
+			return nil, errors.New("invalid cursor value")
+		}
+	case repository.SortByRating:
+		if _, ok := c.Value.(float64); !ok {
+			return nil, errors.New("invalid cursor value")
+		}
+	}
+	return &repository.PageKey{Value: c.Value, ID: c.ID}, nil
+}
+
+func signCursor(encoded string) []byte {
+	mac := hmac.New(sha256.New, cursorKey)
+	mac.Write([]byte(encoded))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nomdb/backend/internal/repository"
+)
+
+func TestSortCursor(t *testing.T) {
+	InitCursorSigning("test-secret")
+
+	for _, tc := range []struct {
+		sort repository.RestaurantSort
+		key  repository.PageKey
+	}{
+		{repository.SortByID, repository.PageKey{ID: 40}},
+		{repository.SortByName, repository.PageKey{Value: "luigi's", ID: 3}},
+		{repository.SortByRating, repository.PageKey{Value: 3.6666666666666665, ID: 12}},
+	} {
+		cursor := EncodeSortCursor(tc.sort, tc.key)
+		key, err := DecodeSortCursor(cursor, tc.sort)
+		if err != nil {
+			t.Errorf("%s: DecodeSortCursor failed: %v", tc.sort, err)
+			continue
+		}
+		if *key != tc.key {
+			t.Errorf("%s: expected %+v, got %+v", tc.sort, tc.key, *key)
+		}
+	}
+}
+
+func TestSortCursor_Invalid(t *testing.T) {
+	InitCursorSigning("test-secret")
+	cursor := EncodeSortCursor(repository.SortByName, repository.PageKey{Value: "luigi's", ID: 3})
+	payload, signature, _ := strings.Cut(cursor, ".")
+
+	if _, err := DecodeSortCursor(cursor, repository.SortByRating); err == nil {
+		t.Error("Expected an error for a cursor of another sort")
+	}
+	if _, err := DecodeSortCursor(payload+"x."+signature, repository.SortByName); err == nil {
+		t.Error("Expected an error for a modified payload")
+	}
+	if _, err := DecodeSortCursor(EncodeCursor(3), repository.SortByName); err == nil {
+		t.Error("Expected an error for an ID cursor with a non-ID sort")
+	}
+
+	InitCursorSigning("other-secret")
+	if _, err := DecodeSortCursor(cursor, repository.SortByName); err == nil {
+		t.Error("Expected an error for a cursor signed with another key")
+	}
+}
+
+func TestSortCursor_LegacyIDCursor(t *testing.T) {
+	key, err := DecodeSortCursor(EncodeCursor(40), repository.SortByID)
+	if err != nil || key == nil || key.ID != 40 {
+		t.Errorf("Expected ID cursors to keep working, got %+v (%v)", key, err)
+	}
+	if key, err := DecodeSortCursor("", repository.SortByName); key != nil || err != nil {
+		t.Errorf("Expected no key for an empty cursor, got %+v (%v)", key, err)
+	}
+}
@@ -0,0 +1,281 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/nomdb/backend/internal/auth"
+	"github.com/nomdb/backend/internal/database"
+	apperrors "github.com/nomdb/backend/internal/errors"
+	"github.com/nomdb/backend/internal/logger"
+	"github.com/nomdb/backend/internal/middleware"
+	"github.com/nomdb/backend/internal/models"
+	"github.com/nomdb/backend/internal/services"
+	"golang.org/x/time/rate"
+)
+
+const (
+	minPasswordLength = 8
+
+	// A handful of reset emails per address per hour, counted by each replica rather
+	// than in the shared rate limit store
+	passwordResetRate  = 20 * time.Minute
+	passwordResetBurst = 3
+)
+
+// forgotPasswordResponse is returned whether or not the address has an account,
+// so the endpoint cannot be used to discover registered emails
+const forgotPasswordResponse = "If an account exists for this email, a password reset link has been sent"
+
+var (
+	emailSender          services.EmailSender = &services.LogEmailSender{}
+	passwordResetURL     string
+	passwordResetTTL     = time.Hour
+	passwordResetLimiter = middleware.NewIPRateLimiter(rate.Every(passwordResetRate), passwordResetBurst)
+)
+
+// InitPasswordReset configures how reset links are delivered. resetURL is the frontend
+// page that receives the token as ?token=...
+func InitPasswordReset(sender services.EmailSender, resetURL string, tokenTTL time.Duration) {
+	emailSender = sender
+	passwordResetURL = resetURL
+	passwordResetTTL = tokenTTL
+	passwordResetLimiter.StartCleanupTask(time.Hour)
+}
+
+// hashResetToken returns the form of a reset token stored in the database
+func hashResetToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// generateResetToken returns a random token and its hash
+func generateResetToken() (string, string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	token := hex.EncodeToString(b)
+	return token, hashResetToken(token), nil
+}
+
+// passwordResetLink builds the link sent to the user
+func passwordResetLink(token string) (string, error) {
+	u, err := url.Parse(passwordResetURL)
+	if err != nil {
+		return "", err
+	}
+	q := u.Query()
+	q.Set("token", token)
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+// @Summary Request a password reset
+// @Description Email a single-use password reset link to a local account. The response is the same whether or not the email is registered.
+// @Tags Auth
+// @Accept json
+// @Produce plain
+// @Param request body models.ForgotPasswordRequest true "Account email"
+// @Success 202 {string} string "Reset link sent if the account exists"
+// @Failure 400 {object} errors.ErrorResponse "Invalid request"
+// @Failure 429 {object} errors.ErrorResponse "Too many reset requests for this email"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /auth/forgot-password [post]
+func ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	var req models.ForgotPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apperrors.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	email, err := services.NormalizeEmail(req.Email)
+	if err != nil {
+		apperrors.Error(w, "Invalid email format", http.StatusBadRequest)
+		return
+	}
+
+	if !passwordResetLimiter.GetLimiter(email).Allow() {
+		apperrors.Error(w, "Too many password reset requests. Please try again later.", http.StatusTooManyRequests)
+		return
+	}
+
+	ctx := r.Context()
+
+	user, err := getUserByEmail(ctx, email)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		logger.Error("Failed to fetch user: %v", err)
+		apperrors.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	// Only active accounts with a password can be recovered this way; OIDC users
+	// reset their password at the identity provider
+	if user != nil && user.IsActive && user.PasswordHash != nil {
+		if err := issuePasswordReset(ctx, user, r); err != nil {
+			logger.Error("Failed to issue password reset for user %d: %v", user.ID, err)
+			apperrors.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+	_, _ = w.Write([]byte(forgotPasswordResponse))
+}
+
+// issuePasswordReset replaces any outstanding reset token of the user and emails the new link
+func issuePasswordReset(ctx context.Context, user *models.User, r *http.Request) error {
+	token, tokenHash, err := generateResetToken()
+	if err != nil {
+		return err
+	}
+	link, err := passwordResetLink(token)
+	if err != nil {
+		return fmt.Errorf("invalid PASSWORD_RESET_URL: %w", err)
+	}
+
+	pool := database.GetPool()
+	if _, err := pool.Exec(ctx, "DELETE FROM password_reset_tokens WHERE user_id = $1", user.ID); err != nil {
+		return err
+	}
+	_, err = pool.Exec(ctx,
+		`INSERT INTO password_reset_tokens (user_id, token_hash, expires_at, ip_address)
+		VALUES ($1, $2, $3, $4)`,
+		user.ID, tokenHash, time.Now().Add(passwordResetTTL), middleware.ClientIP(r))
+	if err != nil {
+		return err
+	}
+
+	msg := services.EmailMessage{
+		To:      user.Email,
+		Subject: "Reset your Nom Database password",
+		Body: fmt.Sprintf(`Hi %s,
+
+Someone requested a password reset for your Nom Database account.
+Open the link below within %v to choose a new password:
+
+%s
+
+If you did not request this, you can ignore this email. Your password will not change.
+`, user.Username, passwordResetTTL, link),
+	}
+
+	// Send in the background so the response time does not reveal whether the account exists
+	go func() {
+		sendCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if err := emailSender.Send(sendCtx, msg); err != nil {
+			logger.Error("❌ Failed to send password reset email to user %d: %v", user.ID, err)
+		}
+	}()
+
+	logger.Info("🔑 Password reset requested for user %d", user.ID)
+	return nil
+}
+
+// @Summary Reset password
+// @Description Set a new password using a token from a password reset email. The token can only be used once, and all sessions of the user are signed out.
+// @Tags Auth
+// @Accept json
+// @Produce plain
+// @Param request body models.ResetPasswordRequest true "Reset token and new password"
+// @Success 200 {string} string "Password has been reset"
+// @Failure 400 {object} errors.ErrorResponse "Invalid request or invalid/expired token"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /auth/reset-password [post]
+func ResetPassword(w http.ResponseWriter, r *http.Request) {
+	var req models.ResetPasswordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apperrors.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.Token == "" || req.Password == "" {
+		apperrors.Error(w, "Token and password are required", http.StatusBadRequest)
+		return
+	}
+	if len(req.Password) < minPasswordLength {
+		apperrors.Error(w, fmt.Sprintf("Password must be at least %d characters", minPasswordLength), http.StatusBadRequest)
+		return
+	}
+
+	passwordHash, err := auth.HashPassword(req.Password, nil)
+	if err != nil {
+		logger.Error("Failed to hash password: %v", err)
+		apperrors.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	ctx := r.Context()
+	tx, err := database.GetPool().Begin(ctx)
+	if err != nil {
+		logger.Error("Failed to start transaction: %v", err)
+		apperrors.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	defer tx.Rollback(ctx)
+
+	// Consuming the token in the same statement that checks it keeps it single-use
+	var userID int
+	err = tx.QueryRow(ctx,
+		`UPDATE password_reset_tokens SET used_at = NOW()
+		WHERE token_hash = $1 AND used_at IS NULL AND expires_at > NOW()
+		RETURNING user_id`,
+		hashResetToken(req.Token)).Scan(&userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			apperrors.Error(w, "Invalid or expired reset token", http.StatusBadRequest)
+			return
+		}
+		logger.Error("Failed to consume reset token: %v", err)
+		apperrors.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	result, err := tx.Exec(ctx,
+		"UPDATE users SET password_hash = $1 WHERE id = $2 AND is_active",
+		passwordHash, userID)
+	if err != nil {
+		logger.Error("Failed to update password: %v", err)
+		apperrors.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if result.RowsAffected() == 0 {
+		apperrors.Error(w, "Invalid or expired reset token", http.StatusBadRequest)
+		return
+	}
+
+	// Sign out everywhere, whoever knew the old password loses access
+	if _, err := tx.Exec(ctx, "DELETE FROM sessions WHERE user_id = $1", userID); err != nil {
+		logger.Error("Failed to revoke sessions: %v", err)
+		apperrors.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		logger.Error("Failed to commit password reset: %v", err)
+		apperrors.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	logger.Info("🔑 Password reset completed for user %d", userID)
+	recordAudit(ctx, r, auditEvent{
+		Event:      AuditPasswordReset,
+		ActorID:    &userID,
+		TargetType: "user",
+		TargetID:   strconv.Itoa(userID),
+	})
+
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte("Password has been reset"))
+}
@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestGenerateResetToken(t *testing.T) {
+	token, tokenHash, err := generateResetToken()
+	if err != nil {
+		t.Fatalf("Failed to generate token: %v", err)
+	}
+	if len(token) != 64 || len(tokenHash) != 64 {
+		t.Errorf("Expected 64 hex chars, got token %d and hash %d", len(token), len(tokenHash))
+	}
+	if token == tokenHash {
+		t.Error("Expected the stored hash to differ from the token")
+	}
+	if hashResetToken(token) != tokenHash {
+		t.Error("Expected hashing the token to reproduce the stored hash")
+	}
+}
+
+func TestPasswordResetLink(t *testing.T) {
+	defer func(original string) { passwordResetURL = original }(passwordResetURL)
+
+	passwordResetURL = "https://nomdb.example.com/reset-password?lang=de"
+	link, err := passwordResetLink("abc123")
+	if err != nil {
+		t.Fatalf("Failed to build link: %v", err)
+	}
+
+	u, err := url.Parse(link)
+	if err != nil {
+		t.Fatalf("Invalid link %s: %v", link, err)
+	}
+	if u.Query().Get("token") != "abc123" || u.Query().Get("lang") != "de" {
+		t.Errorf("Expected token and existing query parameters, got %s", link)
+	}
+}
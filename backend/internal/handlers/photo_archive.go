@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"archive/zip"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/jackc/pgx/v5"
+	"github.com/nomdb/backend/internal/database"
+	apperrors "github.com/nomdb/backend/internal/errors"
+	"github.com/nomdb/backend/internal/middleware"
+	"github.com/nomdb/backend/internal/storage"
+)
+
+// unsafeArchiveChars matches characters replaced in archive and entry names
+var unsafeArchiveChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// archiveName turns a free-form name into a safe file name
+func archiveName(name, fallback string) string {
+	safe := strings.Trim(unsafeArchiveChars.ReplaceAllString(name, "_"), "_.")
+	if safe == "" {
+		return fallback
+	}
+	if len(safe) > 100 {
+		safe = safe[:100]
+	}
+	return safe
+}
+
+// archiveEntryName names a photo inside the archive so entries sort in gallery order
+// and never collide
+func archiveEntryName(index, photoID int, filename string, originalFilename *string) string {
+	ext := filepath.Ext(filename)
+	base := strings.TrimSuffix(filename, ext)
+	if originalFilename != nil {
+		base = archiveName(strings.TrimSuffix(*originalFilename, filepath.Ext(*originalFilename)), base)
+	}
+	return fmt.Sprintf("%03d_%d_%s%s", index+1, photoID, base, ext)
+}
+
+// @Summary Download all photos of a restaurant
+// @Description Stream a zip archive of every visible photo of a restaurant in gallery order. Files are fetched from storage on the fly and stored uncompressed.
+// @Tags Photos
+// @Produce application/zip
+// @Param restaurantId path int true "Restaurant ID"
+// @Success 200 {file} binary "Zip archive"
+// @Failure 400 {object} errors.ErrorResponse "Invalid restaurant ID"
+// @Failure 404 {object} errors.ErrorResponse "Restaurant not found or has no photos"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /restaurants/{restaurantId}/photos/archive [get]
+func DownloadPhotoArchive(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	restaurantID, err := strconv.Atoi(vars["restaurantId"])
+	if err != nil {
+		apperrors.Error(w, "Invalid restaurant ID", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	pool := database.GetPool()
+
+	var restaurantName string
+	err = pool.QueryRow(ctx, "SELECT name FROM restaurants WHERE id = $1", restaurantID).Scan(&restaurantName)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			apperrors.Error(w, "Restaurant not found", http.StatusNotFound)
+			return
+		}
+		apperrors.Internal(w, err)
+		return
+	}
+
+	// Same visibility as the gallery, minus photos that are still processing
+	viewerID, isAdmin := photoViewer(r)
+	rows, err := pool.Query(ctx,
+		`SELECT id, filename, original_filename, updated_at
+		FROM menu_photos
+		WHERE restaurant_id = $1 AND status = 'ready'
+			AND (moderation_status = 'approved' OR $2 OR uploaded_by_user_id = $3)
+		ORDER BY position, created_at DESC, id DESC`,
+		restaurantID, isAdmin, viewerID)
+	if err != nil {
+		apperrors.Internal(w, err)
+		return
+	}
+
+	type archivePhoto struct {
+		header   *zip.FileHeader
+		filename string
+	}
+	var photos []archivePhoto
+	for rows.Next() {
+		var id int
+		var filename string
+		var originalFilename *string
+		header := &zip.FileHeader{Method: zip.Store}
+		if err := rows.Scan(&id, &filename, &originalFilename, &header.Modified); err != nil {
+			rows.Close()
+			apperrors.Internal(w, err)
+			return
+		}
+		header.Name = archiveEntryName(len(photos), id, filename, originalFilename)
+		photos = append(photos, archivePhoto{header: header, filename: filename})
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		apperrors.Internal(w, err)
+		return
+	}
+
+	if len(photos) == 0 {
+		apperrors.Error(w, "Restaurant has no photos", http.StatusNotFound)
+		return
+	}
+
+	name := archiveName(restaurantName, fmt.Sprintf("restaurant-%d", restaurantID))
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s-photos.zip"`, name))
+	w.Header().Set("Cache-Control", "no-store")
+
+	// From here on the status is sent, so failures can only abort the stream. Large
+	// archives may take longer than the server's write timeout.
+	middleware.DisableWriteTimeout(w)
+	store := storage.Get()
+	zw := zip.NewWriter(w)
+	for _, photo := range photos {
+		if err := writeArchiveEntry(ctx, zw, store, photo.header, photo.filename); err != nil {
+			photoLog.Error("❌ Photo archive for restaurant %d aborted: %v", restaurantID, err)
+			return
+		}
+	}
+	if err := zw.Close(); err != nil {
+		photoLog.Error("❌ Failed to finish photo archive for restaurant %d: %v", restaurantID, err)
+		return
+	}
+
+	photoLog.Debug("Streamed %d photos of restaurant %d as zip", len(photos), restaurantID)
+}
+
+// writeArchiveEntry copies one stored photo into the archive
+func writeArchiveEntry(ctx context.Context, zw *zip.Writer, store storage.Storage, header *zip.FileHeader, filename string) error {
+	data, err := store.Download(ctx, photoKey(filename))
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", filename, err)
+	}
+
+	entry, err := zw.CreateHeader(header)
+	if err != nil {
+		return err
+	}
+	_, err = entry.Write(data)
+	return err
+}
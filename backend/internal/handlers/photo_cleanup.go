@@ -0,0 +1,226 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nomdb/backend/internal/database"
+	apperrors "github.com/nomdb/backend/internal/errors"
+	"github.com/nomdb/backend/internal/models"
+	"github.com/nomdb/backend/internal/storage"
+)
+
+// photoCleanupGracePeriod protects files that were just written but whose database row
+// does not exist yet (uploads in progress, unconfirmed direct uploads)
+const photoCleanupGracePeriod = time.Hour
+
+// errPhotoCleanupRunning is returned when a reconciliation is already in progress
+var errPhotoCleanupRunning = errors.New("photo cleanup already running")
+
+var photoCleanupMu sync.Mutex
+
+// StartPhotoCleanupJob periodically removes stored files that no longer belong to a photo.
+// Photos whose files are missing are only reported; removing them requires the admin endpoint.
+func StartPhotoCleanupJob(interval time.Duration) {
+	if interval <= 0 {
+		photoLog.Debug("Photo cleanup job disabled")
+		return
+	}
+
+	photoLog.Info("✅ Photo cleanup job scheduled every %s", interval)
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if _, err := reconcilePhotoStorage(context.Background(), false, false); err != nil {
+				photoLog.Error("❌ Photo cleanup failed: %v", err)
+			}
+		}
+	}()
+}
+
+// reconcilePhotoStorage compares stored files against menu_photos. Files without a photo
+// are deleted unless dryRun is set; photos without a file are reported and, with
+// removeMissing, deleted as well.
+func reconcilePhotoStorage(ctx context.Context, dryRun, removeMissing bool) (*models.PhotoCleanupReport, error) {
+	if !photoCleanupMu.TryLock() {
+		return nil, errPhotoCleanupRunning
+	}
+	defer photoCleanupMu.Unlock()
+
+	report := &models.PhotoCleanupReport{
+		DryRun:          dryRun,
+		OrphanedObjects: []string{},
+		MissingFiles:    []int{},
+		StartedAt:       time.Now(),
+	}
+	pool := database.GetPool()
+	store := storage.Get()
+
+	// Expired direct uploads will never be confirmed, so their files become orphans below
+	if !dryRun {
+		result, err := pool.Exec(ctx, "DELETE FROM photo_uploads WHERE expires_at < NOW()")
+		if err != nil {
+			return nil, err
+		}
+		report.ExpiredUploads = int(result.RowsAffected())
+	}
+
+	// Load the database side before listing storage: anything uploaded in between is
+	// covered by the grace period instead of being reported as missing
+	type photoFile struct {
+		id       int
+		filename string
+		isNew    bool
+	}
+	var photos []photoFile
+	cutoff := time.Now().Add(-photoCleanupGracePeriod)
+	rows, err := pool.Query(ctx, "SELECT id, filename, created_at > $1 FROM menu_photos", cutoff)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var p photoFile
+		if err := rows.Scan(&p.id, &p.filename, &p.isNew); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		photos = append(photos, p)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	known := make(map[string]bool)
+	variantBases := make(map[string]bool)
+	addKnown := func(filename string) {
+		known[photoKey(filename)] = true
+		known[thumbnailKey(filename)] = true
+		known[originalKey(filename)] = true
+		variantBases[strings.TrimSuffix(filename, filepath.Ext(filename))] = true
+	}
+	for _, p := range photos {
+		addKnown(p.filename)
+	}
+
+	pending, err := pool.Query(ctx, "SELECT filename FROM photo_uploads")
+	if err != nil {
+		return nil, err
+	}
+	for pending.Next() {
+		var filename string
+		if err := pending.Scan(&filename); err != nil {
+			pending.Close()
+			return nil, err
+		}
+		addKnown(filename)
+	}
+	pending.Close()
+	if err := pending.Err(); err != nil {
+		return nil, err
+	}
+
+	objects, err := store.List(ctx, photosPrefix+"/")
+	if err != nil {
+		return nil, err
+	}
+	report.ObjectsScanned = len(objects)
+
+	present := make(map[string]bool, len(objects))
+	variantsDir := photosPrefix + "/" + variantsSubdir + "/"
+	for _, obj := range objects {
+		present[obj.Key] = true
+		if known[obj.Key] || obj.LastModified.After(cutoff) {
+			continue
+		}
+		if rest, ok := strings.CutPrefix(obj.Key, variantsDir); ok {
+			if i := strings.LastIndex(rest, "_"); i > 0 && variantBases[rest[:i]] {
+				continue
+			}
+		}
+
+		report.OrphanedObjects = append(report.OrphanedObjects, obj.Key)
+		if dryRun {
+			continue
+		}
+		if err := store.Delete(ctx, obj.Key); err != nil {
+			photoLog.Warn("Failed to delete orphaned file %s: %v", obj.Key, err)
+			continue
+		}
+		report.DeletedObjects++
+	}
+
+	var missing []photoFile
+	for _, p := range photos {
+		if !p.isNew && !present[photoKey(p.filename)] {
+			missing = append(missing, p)
+			report.MissingFiles = append(report.MissingFiles, p.id)
+		}
+	}
+
+	if removeMissing && !dryRun && len(missing) > 0 {
+		result, err := pool.Exec(ctx, "DELETE FROM menu_photos WHERE id = ANY($1)", report.MissingFiles)
+		if err != nil {
+			return nil, err
+		}
+		report.DeletedPhotos = int(result.RowsAffected())
+		for _, p := range missing {
+			deletePhotoFiles(ctx, store, p.filename)
+		}
+	}
+
+	report.FinishedAt = time.Now()
+	photoLog.Info("🧹 Photo cleanup: %d objects scanned, %d orphaned (%d deleted), %d photos missing files (%d removed), %d expired uploads",
+		report.ObjectsScanned, len(report.OrphanedObjects), report.DeletedObjects,
+		len(report.MissingFiles), report.DeletedPhotos, report.ExpiredUploads)
+	return report, nil
+}
+
+// @Summary Reconcile photo storage
+// @Description Compare stored photo files against the database. Files without a photo (older than one hour) are deleted and photos whose file is missing are reported. Admin only.
+// @Tags Photos
+// @Produce json
+// @Param dry_run query bool false "Only report, do not delete anything"
+// @Param remove_missing query bool false "Also delete photos whose original file is missing"
+// @Success 200 {object} models.PhotoCleanupReport "Cleanup report"
+// @Failure 409 {object} errors.ErrorResponse "Cleanup already running"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /admin/photos/cleanup [post]
+func CleanupPhotoStorage(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	dryRun := r.URL.Query().Get("dry_run") == "true"
+	removeMissing := r.URL.Query().Get("remove_missing") == "true"
+
+	report, err := reconcilePhotoStorage(ctx, dryRun, removeMissing)
+	if err != nil {
+		if errors.Is(err, errPhotoCleanupRunning) {
+			apperrors.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		photoLog.Error("❌ Photo cleanup failed: %v", err)
+		apperrors.Internal(w, err)
+		return
+	}
+
+	recordAudit(ctx, r, auditEvent{
+		Event: AuditAdminAction,
+		Details: map[string]any{
+			"action":          "photo_cleanup",
+			"dry_run":         dryRun,
+			"deleted_objects": report.DeletedObjects,
+			"deleted_photos":  report.DeletedPhotos,
+		},
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/nomdb/backend/internal/storage"
+)
+
+// localUploadsPrefix is the URL prefix under which local storage files are served
+const localUploadsPrefix = "/api/uploads/"
+
+// uploadCacheControl lets browsers and proxies cache files forever: every stored file
+// has a unique name and is never modified in place
+const uploadCacheControl = "public, max-age=31536000, immutable"
+
+// servableUploadTypes maps the extensions of files that may be served to the only
+// content type they are served with
+var servableUploadTypes = map[string]string{
+	".jpg":  "image/jpeg",
+	".jpeg": "image/jpeg",
+	".png":  "image/png",
+	".webp": "image/webp",
+	".avif": "image/avif",
+}
+
+// ServeLocalUpload serves photos, thumbnails and variants from local storage with
+// immutable caching, conditional requests (ETag / Last-Modified) and range support.
+// Anything that is not a known image type (unprocessed originals, temp files) is hidden.
+func ServeLocalUpload(w http.ResponseWriter, r *http.Request) {
+	local, ok := storage.Get().(*storage.LocalStorage)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	key := strings.TrimPrefix(r.URL.Path, localUploadsPrefix)
+	if key == "" || strings.Contains(key, "..") || strings.Contains(key, "\\") || path.Clean("/"+key) != "/"+key {
+		http.NotFound(w, r)
+		return
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if strings.HasPrefix(segment, ".") {
+			http.NotFound(w, r)
+			return
+		}
+	}
+
+	contentType, ok := servableUploadTypes[strings.ToLower(path.Ext(key))]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	f, fi, err := local.Open(key)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	defer f.Close()
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Cache-Control", uploadCacheControl)
+	w.Header().Set("ETag", fmt.Sprintf(`"%x-%x"`, fi.ModTime().UnixNano(), fi.Size()))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	// Files are never documents; keep the browser from executing anything in them
+	w.Header().Set("Content-Security-Policy", "default-src 'none'; sandbox")
+
+	http.ServeContent(w, r, path.Base(key), fi.ModTime(), f)
+}
@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/nomdb/backend/internal/storage"
+)
+
+func TestServeLocalUpload(t *testing.T) {
+	if err := storage.Init(storage.Config{Backend: storage.BackendLocal, LocalDir: t.TempDir()}); err != nil {
+		t.Fatalf("storage.Init failed: %v", err)
+	}
+	store := storage.Get()
+	ctx := context.Background()
+	for key, body := range map[string]string{
+		"menu_photos/photo.jpg":           "0123456789",
+		"menu_photos/originals/photo":     "original",
+		"menu_photos/.hidden.jpg":         "hidden",
+		"menu_photos/drawing.svg":         "<svg/>",
+		"menu_photos/variants/p_1x1.webp": "webp",
+	} {
+		if err := store.Upload(ctx, key, strings.NewReader(body), "application/octet-stream"); err != nil {
+			t.Fatalf("Upload(%s) failed: %v", key, err)
+		}
+	}
+
+	tests := []struct {
+		name           string
+		path           string
+		headers        map[string]string
+		expectedStatus int
+		expectedType   string
+		expectedBody   string
+	}{
+		{
+			name:           "Photo",
+			path:           "/api/uploads/menu_photos/photo.jpg",
+			expectedStatus: http.StatusOK,
+			expectedType:   "image/jpeg",
+			expectedBody:   "0123456789",
+		},
+		{
+			name:           "Variant",
+			path:           "/api/uploads/menu_photos/variants/p_1x1.webp",
+			expectedStatus: http.StatusOK,
+			expectedType:   "image/webp",
+			expectedBody:   "webp",
+		},
+		{
+			name:           "Range request",
+			path:           "/api/uploads/menu_photos/photo.jpg",
+			headers:        map[string]string{"Range": "bytes=2-5"},
+			expectedStatus: http.StatusPartialContent,
+			expectedBody:   "2345",
+		},
+		{
+			name:           "Unprocessed original",
+			path:           "/api/uploads/menu_photos/originals/photo",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Dotfile",
+			path:           "/api/uploads/menu_photos/.hidden.jpg",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Non-image type",
+			path:           "/api/uploads/menu_photos/drawing.svg",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Path traversal",
+			path:           "/api/uploads/menu_photos/../menu_photos/photo.jpg",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Directory",
+			path:           "/api/uploads/menu_photos/",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Missing file",
+			path:           "/api/uploads/menu_photos/missing.jpg",
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/", nil)
+			req.URL.Path = tt.path
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			rr := httptest.NewRecorder()
+
+			ServeLocalUpload(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Fatalf("Expected status %d, got %d", tt.expectedStatus, rr.Code)
+			}
+			if tt.expectedType != "" && rr.Header().Get("Content-Type") != tt.expectedType {
+				t.Errorf("Expected Content-Type %s, got %s", tt.expectedType, rr.Header().Get("Content-Type"))
+			}
+			if tt.expectedBody != "" && rr.Body.String() != tt.expectedBody {
+				t.Errorf("Expected body %q, got %q", tt.expectedBody, rr.Body.String())
+			}
+			if rr.Code == http.StatusOK && rr.Header().Get("Cache-Control") != uploadCacheControl {
+				t.Errorf("Expected immutable Cache-Control, got %q", rr.Header().Get("Cache-Control"))
+			}
+		})
+	}
+
+	t.Run("Conditional request", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/uploads/menu_photos/photo.jpg", nil)
+		rr := httptest.NewRecorder()
+		ServeLocalUpload(rr, req)
+		etag := rr.Header().Get("ETag")
+		if etag == "" {
+			t.Fatal("Expected ETag header")
+		}
+
+		req = httptest.NewRequest("GET", "/api/uploads/menu_photos/photo.jpg", nil)
+		req.Header.Set("If-None-Match", etag)
+		rr = httptest.NewRecorder()
+		ServeLocalUpload(rr, req)
+		if rr.Code != http.StatusNotModified {
+			t.Errorf("Expected status 304, got %d", rr.Code)
+		}
+	})
+}
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+	apperrors "github.com/nomdb/backend/internal/errors"
+	"github.com/nomdb/backend/internal/models"
+	"github.com/nomdb/backend/internal/services"
+)
+
+// photoImportClient fetches remote images; it refuses to connect to internal addresses
+var photoImportClient = services.NewSafeHTTPClient()
+
+// importedFilename derives an original filename from the URL an image was fetched from
+func importedFilename(finalPath string) string {
+	name := path.Base(finalPath)
+	if name == "." || name == "/" {
+		return "imported"
+	}
+	return name
+}
+
+// @Summary Upload a menu photo from a URL
+// @Description Download an image from a public http(s) URL server-side (JPEG, PNG, or WebP, max 5MB unless changed in the upload_max_size_mb setting) and add it to the restaurant's photos. URLs resolving to private or internal addresses are rejected.
+// @Tags Photos
+// @Accept json
+// @Produce json
+// @Param restaurantId path int true "Restaurant ID"
+// @Param photo body models.UploadPhotoFromURLRequest true "Image URL, caption and type"
+// @Success 201 {object} models.UploadPhotoResponse "Imported and processed photo (synchronous processing)"
+// @Success 202 {object} models.UploadPhotoResponse "Imported photo, processing in the background"
+// @Failure 400 {object} errors.ErrorResponse "Invalid request, URL or image"
+// @Failure 429 {object} errors.ErrorResponse "Daily upload limit reached"
+// @Failure 502 {object} errors.ErrorResponse "Image could not be downloaded"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /restaurants/{restaurantId}/photos/from-url [post]
+func UploadMenuPhotoFromURL(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	restaurantID, err := strconv.Atoi(vars["restaurantId"])
+	if err != nil {
+		apperrors.Error(w, "Invalid restaurant ID", http.StatusBadRequest)
+		return
+	}
+
+	if !enforcePhotoUploadQuota(r.Context(), w, r) {
+		return
+	}
+
+	var req models.UploadPhotoFromURLRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apperrors.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if strings.TrimSpace(req.URL) == "" {
+		apperrors.Error(w, "URL is required", http.StatusBadRequest)
+		return
+	}
+	if req.Caption == "" {
+		apperrors.Error(w, "Caption is required", http.StatusBadRequest)
+		return
+	}
+	if req.PhotoType == "" {
+		req.PhotoType = defaultPhotoType
+	}
+	if !validPhotoTypes[req.PhotoType] {
+		apperrors.Error(w, "Invalid photo type. Must be one of: menu, food, interior, receipt", http.StatusBadRequest)
+		return
+	}
+
+	result, err := services.SafeFetch(r.Context(), photoImportClient, req.URL, maxUploadSize())
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidURL):
+			apperrors.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, services.ErrResponseTooLarge):
+			apperrors.Error(w, fmt.Sprintf("File too large. Maximum size is %d MB", maxUploadSize()/(1<<20)), http.StatusBadRequest)
+		case errors.Is(err, services.ErrForbiddenAddress):
+			photoLog.Ctx(r.Context()).Warn("⚠️  Blocked photo import from %s: %v", req.URL, err)
+			apperrors.Error(w, "URL is not allowed", http.StatusBadRequest)
+		default:
+			photoLog.Ctx(r.Context()).Debug("Photo import from %s failed: %v", req.URL, err)
+			apperrors.Error(w, fmt.Sprintf("Failed to download image: %v", err), http.StatusBadGateway)
+		}
+		return
+	}
+
+	// Trust the bytes rather than the remote server's Content-Type header
+	contentType := http.DetectContentType(result.Body)
+	if !uploadImageTypes[contentType] {
+		apperrors.Error(w, "Only JPEG, PNG, and WebP images are allowed", http.StatusBadRequest)
+		return
+	}
+
+	photoLog.Ctx(r.Context()).Debug("Imported %d bytes from %s", len(result.Body), result.FinalURL.Redacted())
+
+	savePhotoUpload(w, r, restaurantID, result.Body, contentType, importedFilename(result.FinalURL.Path), req.Caption, req.PhotoType)
+}
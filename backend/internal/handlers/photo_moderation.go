@@ -0,0 +1,239 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/jackc/pgx/v5"
+	"github.com/nomdb/backend/internal/cache"
+	"github.com/nomdb/backend/internal/database"
+	apperrors "github.com/nomdb/backend/internal/errors"
+	"github.com/nomdb/backend/internal/models"
+	"github.com/nomdb/backend/internal/services"
+	"github.com/nomdb/backend/internal/storage"
+)
+
+// Moderation states, mirroring the CHECK constraint on menu_photos.moderation_status
+const (
+	moderationPending  = "pending"
+	moderationFlagged  = "flagged"
+	moderationApproved = "approved"
+)
+
+// moderationErrorLabel marks photos queued for review because the provider failed
+const moderationErrorLabel = "moderation_error"
+
+// photoModerator checks new uploads before they become visible (nil disables moderation)
+var photoModerator services.ModerationProvider
+
+// InitPhotoModeration configures the moderation provider for uploads
+func InitPhotoModeration(provider services.ModerationProvider) {
+	photoModerator = provider
+	if provider == nil {
+		photoLog.Debug("Photo moderation disabled")
+	}
+}
+
+// initialModerationStatus is the moderation state of a new photo
+func initialModerationStatus() string {
+	if photoModerator == nil {
+		return moderationApproved
+	}
+	return moderationPending
+}
+
+// moderateImage runs the provider on an image. Provider failures queue the photo for
+// human review rather than publishing it unchecked.
+func moderateImage(ctx context.Context, image []byte, contentType string) (string, []string) {
+	if photoModerator == nil {
+		return moderationApproved, nil
+	}
+
+	result, err := photoModerator.Moderate(ctx, image, contentType)
+	if err != nil {
+		photoLog.Ctx(ctx).Error("❌ Image moderation failed: %v", err)
+		return moderationFlagged, []string{moderationErrorLabel}
+	}
+	if result.Flagged {
+		return moderationFlagged, result.Labels
+	}
+	return moderationApproved, nil
+}
+
+// setPhotoModeration records an automated moderation verdict
+func setPhotoModeration(ctx context.Context, photoID int, status string, labels []string) error {
+	if status == moderationFlagged {
+		photoLog.Ctx(ctx).Warn("🚩 Photo %d flagged for moderation: %v", photoID, labels)
+	}
+	_, err := database.GetPool().Exec(ctx,
+		`UPDATE menu_photos SET moderation_status = $1, moderation_labels = $2, updated_at = NOW()
+		WHERE id = $3 AND moderation_status = $4`,
+		status, labels, photoID, moderationPending)
+	return err
+}
+
+// photoViewer returns the ID and admin flag of the requesting user (0 when anonymous),
+// used to show unmoderated photos to their uploader and to admins
+func photoViewer(r *http.Request) (int, bool) {
+	if user, ok := GetUserFromContext(r); ok {
+		return user.ID, user.IsAdmin
+	}
+	return 0, false
+}
+
+// canViewPhoto reports whether the requesting user may see a photo in its moderation state
+func canViewPhoto(r *http.Request, photo *models.MenuPhoto) bool {
+	if photo.ModerationStatus == moderationApproved {
+		return true
+	}
+	viewerID, isAdmin := photoViewer(r)
+	return isAdmin || (photo.UploadedByUserID != nil && *photo.UploadedByUserID == viewerID)
+}
+
+// @Summary List the photo moderation queue
+// @Description List photos awaiting an automated check (pending) or human review (flagged), oldest first. Admin only.
+// @Tags Photos
+// @Produce json
+// @Param status query string false "Filter by moderation status (pending, flagged)"
+// @Success 200 {array} models.MenuPhoto "Photos awaiting moderation"
+// @Failure 400 {object} errors.ErrorResponse "Invalid status"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /admin/photos/moderation [get]
+func GetPhotoModerationQueue(w http.ResponseWriter, r *http.Request) {
+	status := r.URL.Query().Get("status")
+	if status != "" && status != moderationPending && status != moderationFlagged {
+		apperrors.Error(w, "Invalid status. Must be one of: pending, flagged", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	rows, err := database.GetPool().Query(ctx,
+		`SELECT id, restaurant_id, filename, original_filename, caption, file_size, mime_type, photo_type, position, is_cover, status, processing_error, moderation_status, moderation_labels, uploaded_by_user_id, created_at, updated_at
+		FROM menu_photos
+		WHERE moderation_status <> 'approved' AND ($1 = '' OR moderation_status = $1)
+		ORDER BY created_at`, status)
+	if err != nil {
+		apperrors.Internal(w, err)
+		return
+	}
+	defer rows.Close()
+
+	photos := []models.MenuPhoto{}
+	store := storage.Get()
+
+	for rows.Next() {
+		var photo models.MenuPhoto
+		if err := rows.Scan(
+			&photo.ID, &photo.RestaurantID, &photo.Filename, &photo.OriginalFilename,
+			&photo.Caption, &photo.FileSize, &photo.MimeType, &photo.PhotoType, &photo.Position, &photo.IsCover, &photo.Status, &photo.ProcessingError, &photo.ModerationStatus, &photo.ModerationLabels, &photo.UploadedByUserID, &photo.CreatedAt, &photo.UpdatedAt,
+		); err != nil {
+			apperrors.Internal(w, err)
+			return
+		}
+
+		photo.URL, err = menuPhotoURL(ctx, store, photo.Filename)
+		if err != nil {
+			apperrors.Internal(w, fmt.Errorf("failed to generate URL: %w", err))
+			return
+		}
+
+		photos = append(photos, photo)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(photos)
+}
+
+// @Summary Moderate a photo
+// @Description Approve a quarantined photo, making it visible, or reject it, deleting the photo and its files. Admin only.
+// @Tags Photos
+// @Accept json
+// @Produce json
+// @Param id path int true "Photo ID"
+// @Param decision body models.ModeratePhotoRequest true "Moderation decision"
+// @Success 200 {object} models.MenuPhoto "Approved photo"
+// @Success 204 "Photo rejected and deleted"
+// @Failure 400 {object} errors.ErrorResponse "Invalid request"
+// @Failure 404 {object} errors.ErrorResponse "Photo not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /admin/photos/{id}/moderation [post]
+func ModeratePhoto(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		apperrors.Error(w, "Invalid photo ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.ModeratePhotoRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apperrors.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	user, ok := GetUserFromContext(r)
+	if !ok {
+		apperrors.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	ctx := r.Context()
+	store := storage.Get()
+
+	switch req.Action {
+	case "approve":
+		result, err := database.GetPool().Exec(ctx,
+			`UPDATE menu_photos SET moderation_status = $1, moderated_by_user_id = $2, moderated_at = NOW(), updated_at = NOW()
+			WHERE id = $3`,
+			moderationApproved, user.ID, id)
+		if err != nil {
+			apperrors.Internal(w, err)
+			return
+		}
+		if result.RowsAffected() == 0 {
+			apperrors.Error(w, "Photo not found", http.StatusNotFound)
+			return
+		}
+
+		photoLog.Info("✅ Photo %d approved by %s", id, user.Username)
+		recordAdminAction(ctx, r, "approve_photo", "photo", id, nil)
+		cache.Invalidate(ctx, cache.KeyRestaurants)
+
+		photo, err := getMenuPhotoByID(ctx, store, id)
+		if err != nil {
+			apperrors.Internal(w, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(photo)
+
+	case "reject":
+		var filename string
+		err := database.GetPool().QueryRow(ctx,
+			"DELETE FROM menu_photos WHERE id = $1 RETURNING filename", id).Scan(&filename)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				apperrors.Error(w, "Photo not found", http.StatusNotFound)
+				return
+			}
+			apperrors.Internal(w, err)
+			return
+		}
+
+		deletePhotoFiles(ctx, store, filename)
+		photoLog.Info("🗑️  Photo %d rejected by %s", id, user.Username)
+		recordAdminAction(ctx, r, "reject_photo", "photo", id, nil)
+		cache.Invalidate(ctx, cache.KeyRestaurants)
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		apperrors.Error(w, "Invalid action. Must be one of: approve, reject", http.StatusBadRequest)
+	}
+}
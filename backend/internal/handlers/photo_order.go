@@ -0,0 +1,249 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/jackc/pgx/v5"
+	"github.com/nomdb/backend/internal/cache"
+	"github.com/nomdb/backend/internal/database"
+	apperrors "github.com/nomdb/backend/internal/errors"
+	"github.com/nomdb/backend/internal/models"
+	"github.com/nomdb/backend/internal/storage"
+)
+
+// getCoverPhotosForRestaurantsBatch returns the cover photo of each restaurant. Restaurants
+// without an explicit cover fall back to their first photo in display order.
+func getCoverPhotosForRestaurantsBatch(ctx context.Context, restaurantIDs []int) (map[int]*models.MenuPhoto, error) {
+	rows, err := database.GetPool().Query(ctx,
+		`SELECT DISTINCT ON (restaurant_id)
+			id, restaurant_id, filename, original_filename, caption, file_size, mime_type, photo_type, position, is_cover, status, processing_error, moderation_status, moderation_labels, uploaded_by_user_id, created_at, updated_at
+		FROM menu_photos
+		WHERE restaurant_id = ANY($1) AND status = 'ready' AND moderation_status = 'approved'
+		ORDER BY restaurant_id, is_cover DESC, position, created_at DESC`, restaurantIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	store := storage.Get()
+	covers := make(map[int]*models.MenuPhoto)
+	for rows.Next() {
+		var photo models.MenuPhoto
+		if err := rows.Scan(
+			&photo.ID, &photo.RestaurantID, &photo.Filename, &photo.OriginalFilename,
+			&photo.Caption, &photo.FileSize, &photo.MimeType, &photo.PhotoType, &photo.Position, &photo.IsCover, &photo.Status, &photo.ProcessingError, &photo.ModerationStatus, &photo.ModerationLabels, &photo.UploadedByUserID, &photo.CreatedAt, &photo.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		if photo.URL, err = menuPhotoURL(ctx, store, photo.Filename); err != nil {
+			return nil, err
+		}
+		covers[photo.RestaurantID] = &photo
+	}
+	return covers, rows.Err()
+}
+
+// @Summary Reorder menu photos
+// @Description Set the display order of a restaurant's photos. The list must contain every photo of the restaurant exactly once.
+// @Tags Photos
+// @Accept json
+// @Produce json
+// @Param restaurantId path int true "Restaurant ID"
+// @Param order body models.ReorderPhotosRequest true "Photo IDs in display order"
+// @Success 200 {array} models.MenuPhoto "Photos in their new order"
+// @Failure 400 {object} errors.ErrorResponse "Invalid request"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /restaurants/{restaurantId}/photos/order [patch]
+func ReorderMenuPhotos(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	restaurantID, err := strconv.Atoi(vars["restaurantId"])
+	if err != nil {
+		apperrors.Error(w, "Invalid restaurant ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.ReorderPhotosRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apperrors.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+
+	// Only photos the user can see take part; quarantined photos keep their position
+	viewerID, isAdmin := photoViewer(r)
+	rows, err := database.GetPool().Query(ctx,
+		`SELECT id FROM menu_photos
+		WHERE restaurant_id = $1 AND (moderation_status = 'approved' OR $2 OR uploaded_by_user_id = $3)`,
+		restaurantID, isAdmin, viewerID)
+	if err != nil {
+		apperrors.Internal(w, err)
+		return
+	}
+	existing := make(map[int]bool)
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			apperrors.Internal(w, err)
+			return
+		}
+		existing[id] = true
+	}
+	rows.Close()
+
+	if len(req.PhotoIDs) != len(existing) {
+		apperrors.Error(w, fmt.Sprintf("photo_ids must list all %d photos of the restaurant", len(existing)), http.StatusBadRequest)
+		return
+	}
+	seen := make(map[int]bool, len(req.PhotoIDs))
+	for _, id := range req.PhotoIDs {
+		if !existing[id] {
+			apperrors.Error(w, fmt.Sprintf("Photo %d does not belong to this restaurant", id), http.StatusBadRequest)
+			return
+		}
+		if seen[id] {
+			apperrors.Error(w, fmt.Sprintf("Photo %d is listed more than once", id), http.StatusBadRequest)
+			return
+		}
+		seen[id] = true
+	}
+
+	_, err = database.GetPool().Exec(ctx,
+		`UPDATE menu_photos m SET position = o.ord - 1, updated_at = NOW()
+		FROM unnest($1::int[]) WITH ORDINALITY AS o(id, ord)
+		WHERE m.id = o.id AND m.restaurant_id = $2`,
+		req.PhotoIDs, restaurantID)
+	if err != nil {
+		apperrors.Internal(w, err)
+		return
+	}
+
+	photoLog.Debug("Reordered %d photos for restaurant %d", len(req.PhotoIDs), restaurantID)
+	cache.Invalidate(ctx, cache.KeyRestaurants)
+
+	GetMenuPhotos(w, r)
+}
+
+// @Summary Set cover photo
+// @Description Make a photo the cover (hero) image of its restaurant, replacing any previous cover
+// @Tags Photos
+// @Produce json
+// @Param id path int true "Photo ID"
+// @Success 200 {object} models.MenuPhoto "Updated photo"
+// @Failure 400 {object} errors.ErrorResponse "Invalid photo ID"
+// @Failure 404 {object} errors.ErrorResponse "Photo not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /photos/{id}/cover [put]
+func SetCoverPhoto(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		apperrors.Error(w, "Invalid photo ID", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+
+	tx, err := database.GetPool().Begin(ctx)
+	if err != nil {
+		apperrors.Internal(w, err)
+		return
+	}
+	defer tx.Rollback(ctx)
+
+	var restaurantID int
+	err = tx.QueryRow(ctx,
+		"SELECT restaurant_id FROM menu_photos WHERE id = $1 FOR UPDATE", id).Scan(&restaurantID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			apperrors.Error(w, "Photo not found", http.StatusNotFound)
+			return
+		}
+		apperrors.Internal(w, err)
+		return
+	}
+
+	// Clear the previous cover first, only one cover per restaurant is allowed
+	_, err = tx.Exec(ctx,
+		"UPDATE menu_photos SET is_cover = false, updated_at = NOW() WHERE restaurant_id = $1 AND is_cover AND id <> $2",
+		restaurantID, id)
+	if err != nil {
+		apperrors.Internal(w, err)
+		return
+	}
+
+	var photo models.MenuPhoto
+	err = tx.QueryRow(ctx,
+		`UPDATE menu_photos SET is_cover = true, updated_at = NOW()
+		WHERE id = $1
+		RETURNING id, restaurant_id, filename, original_filename, caption, file_size, mime_type, photo_type, position, is_cover, status, processing_error, moderation_status, moderation_labels, uploaded_by_user_id, created_at, updated_at`,
+		id,
+	).Scan(
+		&photo.ID, &photo.RestaurantID, &photo.Filename, &photo.OriginalFilename,
+		&photo.Caption, &photo.FileSize, &photo.MimeType, &photo.PhotoType, &photo.Position, &photo.IsCover, &photo.Status, &photo.ProcessingError, &photo.ModerationStatus, &photo.ModerationLabels, &photo.UploadedByUserID, &photo.CreatedAt, &photo.UpdatedAt,
+	)
+	if err != nil {
+		apperrors.Internal(w, err)
+		return
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		apperrors.Internal(w, err)
+		return
+	}
+
+	photo.URL, err = menuPhotoURL(ctx, storage.Get(), photo.Filename)
+	if err != nil {
+		apperrors.Error(w, "Failed to generate URL", http.StatusInternalServerError)
+		return
+	}
+
+	photoLog.Info("Photo %d set as cover for restaurant %d", id, restaurantID)
+	cache.Invalidate(ctx, cache.KeyRestaurants)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(photo)
+}
+
+// @Summary Unset cover photo
+// @Description Remove the cover flag from a photo. The restaurant falls back to its first photo in display order.
+// @Tags Photos
+// @Param id path int true "Photo ID"
+// @Success 204 "Cover flag removed"
+// @Failure 400 {object} errors.ErrorResponse "Invalid photo ID"
+// @Failure 404 {object} errors.ErrorResponse "Photo not found or not the cover"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /photos/{id}/cover [delete]
+func UnsetCoverPhoto(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		apperrors.Error(w, "Invalid photo ID", http.StatusBadRequest)
+		return
+	}
+
+	result, err := database.GetPool().Exec(r.Context(),
+		"UPDATE menu_photos SET is_cover = false, updated_at = NOW() WHERE id = $1 AND is_cover", id)
+	if err != nil {
+		apperrors.Internal(w, err)
+		return
+	}
+
+	if result.RowsAffected() == 0 {
+		apperrors.Error(w, "Photo not found or not the cover", http.StatusNotFound)
+		return
+	}
+	cache.Invalidate(r.Context(), cache.KeyRestaurants)
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/nomdb/backend/internal/database"
+	apperrors "github.com/nomdb/backend/internal/errors"
+	"github.com/nomdb/backend/internal/models"
+)
+
+// photoDailyUploadLimit is the number of photos a non-admin user may upload per
+// rolling 24 hours (0 disables the quota)
+var photoDailyUploadLimit = 20
+
+// InitPhotoLimits configures the per-user photo upload quota
+func InitPhotoLimits(dailyUploadLimit int) {
+	photoDailyUploadLimit = dailyUploadLimit
+	if dailyUploadLimit > 0 {
+		photoLog.Info("✅ Photo uploads limited to %d per user per day", dailyUploadLimit)
+	} else {
+		photoLog.Debug("Photo upload quota disabled")
+	}
+}
+
+// checkPhotoUploadQuota reports whether the user may upload another photo. Pending
+// direct uploads count towards the quota so presigning cannot be used to bypass it.
+func checkPhotoUploadQuota(ctx context.Context, user *models.User) (bool, error) {
+	limit := settingInt(settingPhotoDailyUploadLimit)
+	if limit <= 0 || user.IsAdmin {
+		return true, nil
+	}
+
+	var uploaded int
+	err := database.GetPool().QueryRow(ctx,
+		`SELECT
+			(SELECT COUNT(*) FROM menu_photos WHERE uploaded_by_user_id = $1 AND created_at > NOW() - INTERVAL '24 hours') +
+			(SELECT COUNT(*) FROM photo_uploads WHERE user_id = $1 AND expires_at > NOW())`,
+		user.ID).Scan(&uploaded)
+	if err != nil {
+		return false, err
+	}
+
+	return uploaded < limit, nil
+}
+
+// enforcePhotoUploadQuota writes an error response and returns false if the current
+// user may not upload another photo
+func enforcePhotoUploadQuota(ctx context.Context, w http.ResponseWriter, r *http.Request) bool {
+	if !requireFeature(w, settingPhotoUploadsEnabled, "Photo uploads are disabled") {
+		return false
+	}
+	user, ok := GetUserFromContext(r)
+	if !ok {
+		apperrors.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return false
+	}
+
+	allowed, err := checkPhotoUploadQuota(ctx, user)
+	if err != nil {
+		apperrors.Internal(w, err)
+		return false
+	}
+	if !allowed {
+		photoLog.Ctx(ctx).Warn("Photo upload quota exceeded for %s (ID: %d)", user.Username, user.ID)
+		apperrors.Error(w, fmt.Sprintf("Upload limit reached: at most %d photos per day", settingInt(settingPhotoDailyUploadLimit)), http.StatusTooManyRequests)
+		return false
+	}
+
+	return true
+}
+
+// authorizePhotoChange writes an error response and returns false unless the current
+// user uploaded the photo or is an admin of it or its space. Photos without a recorded
+// uploader can only be changed by admins.
+func authorizePhotoChange(ctx context.Context, w http.ResponseWriter, r *http.Request, photoID int) bool {
+	user, ok := GetUserFromContext(r)
+	if !ok {
+		apperrors.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return false
+	}
+
+	var uploaderID *int
+	err := database.GetPool().QueryRow(ctx,
+		"SELECT uploaded_by_user_id FROM menu_photos WHERE id = $1", photoID).Scan(&uploaderID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			apperrors.Error(w, "Photo not found", http.StatusNotFound)
+			return false
+		}
+		apperrors.Internal(w, err)
+		return false
+	}
+
+	if isSpaceAdmin(r, user) || (uploaderID != nil && *uploaderID == user.ID) {
+		return true
+	}
+
+	apperrors.Error(w, "Only the uploader or an admin can modify this photo", http.StatusForbidden)
+	return false
+}
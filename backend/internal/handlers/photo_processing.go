@@ -0,0 +1,237 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/jackc/pgx/v5"
+	"github.com/nomdb/backend/internal/cache"
+	"github.com/nomdb/backend/internal/database"
+	apperrors "github.com/nomdb/backend/internal/errors"
+	"github.com/nomdb/backend/internal/events"
+	"github.com/nomdb/backend/internal/logger"
+	"github.com/nomdb/backend/internal/models"
+	"github.com/nomdb/backend/internal/services"
+	"github.com/nomdb/backend/internal/storage"
+)
+
+// Photo processing states, mirroring the CHECK constraint on menu_photos.status
+const (
+	photoStatusProcessing = "processing"
+	photoStatusReady      = "ready"
+	photoStatusFailed     = "failed"
+)
+
+const (
+	originalsSubdir          = "originals"
+	photoProcessingQueueSize = 100
+)
+
+// photoLog logs the photo pipeline: uploads, processing, moderation and cleanup
+var photoLog = logger.Module("photos")
+
+// photoJob is a photo waiting for a worker, with the ID of the upload request so the
+// processing logs and storage calls can be traced back to it
+type photoJob struct {
+	photoID   int
+	requestID string
+}
+
+// photoProcessingQueue holds photos waiting for a worker. It is nil when processing
+// runs synchronously inside the upload request.
+var photoProcessingQueue chan photoJob
+
+// originalKey returns the storage key of an uploaded file awaiting processing
+func originalKey(filename string) string {
+	base := strings.TrimSuffix(filename, filepath.Ext(filename))
+	return fmt.Sprintf("%s/%s/%s", photosPrefix, originalsSubdir, base)
+}
+
+// InitPhotoProcessing starts the background image workers. With zero workers uploads
+// are processed synchronously and answered with 201 as before.
+func InitPhotoProcessing(workers int) {
+	if workers <= 0 {
+		photoLog.Info("Photo processing runs synchronously")
+		return
+	}
+
+	photoProcessingQueue = make(chan photoJob, photoProcessingQueueSize)
+	for i := 0; i < workers; i++ {
+		go func() {
+			for job := range photoProcessingQueue {
+				ctx := context.Background()
+				if job.requestID != "" {
+					ctx = context.WithValue(ctx, logger.RequestIDKey, job.requestID)
+				}
+				processPhoto(ctx, job.photoID)
+			}
+		}()
+	}
+	photoLog.Info("✅ Photo processing: %d background workers", workers)
+
+	// Resume photos whose processing was interrupted by a restart
+	go func() {
+		rows, err := database.GetPool().Query(context.Background(),
+			"SELECT id FROM menu_photos WHERE status = $1 ORDER BY created_at", photoStatusProcessing)
+		if err != nil {
+			photoLog.Error("❌ Failed to load unprocessed photos: %v", err)
+			return
+		}
+		var ids []int
+		for rows.Next() {
+			var id int
+			if err := rows.Scan(&id); err == nil {
+				ids = append(ids, id)
+			}
+		}
+		rows.Close()
+
+		if len(ids) > 0 {
+			photoLog.Info("Resuming processing of %d photos", len(ids))
+		}
+		for _, id := range ids {
+			photoProcessingQueue <- photoJob{photoID: id}
+		}
+	}()
+}
+
+// enqueuePhotoProcessing hands a photo uploaded in the request of ctx to the workers,
+// returning false if processing is synchronous or the queue is full
+func enqueuePhotoProcessing(ctx context.Context, photoID int) bool {
+	if photoProcessingQueue == nil {
+		return false
+	}
+	select {
+	case photoProcessingQueue <- photoJob{photoID: photoID, requestID: logger.RequestID(ctx)}:
+		return true
+	default:
+		photoLog.Ctx(ctx).Warn("Photo processing queue full, processing photo %d inline", photoID)
+		return false
+	}
+}
+
+// processPhoto resizes and compresses a photo's original, stores the result and its
+// thumbnail, and marks the photo ready (or failed)
+func processPhoto(ctx context.Context, photoID int) {
+	log := photoLog.Ctx(ctx)
+	pool := database.GetPool()
+	store := storage.Get()
+
+	var filename string
+	var originalFilename *string
+	var restaurantID, spaceID int
+	err := pool.QueryRow(ctx,
+		`SELECT p.filename, p.original_filename, p.restaurant_id, r.space_id
+		FROM menu_photos p JOIN restaurants r ON r.id = p.restaurant_id
+		WHERE p.id = $1 AND p.status = $2`,
+		photoID, photoStatusProcessing).Scan(&filename, &originalFilename, &restaurantID, &spaceID)
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			log.Error("❌ Failed to load photo %d for processing: %v", photoID, err)
+		}
+		return
+	}
+
+	fullImage, err := processPhotoFiles(ctx, store, filename, originalFilename)
+	if err != nil {
+		log.Error("❌ Processing photo %d failed: %v", photoID, err)
+		message := err.Error()
+		if _, dbErr := pool.Exec(ctx,
+			"UPDATE menu_photos SET status = $1, processing_error = $2, updated_at = NOW() WHERE id = $3",
+			photoStatusFailed, message, photoID); dbErr != nil {
+			log.Error("❌ Failed to mark photo %d as failed: %v", photoID, dbErr)
+		}
+		publishPhotoEvent(ctx, spaceID, models.PhotoEvent{
+			Type: eventPhotoFailed, PhotoID: photoID, RestaurantID: restaurantID, Status: photoStatusFailed, Error: &message,
+		})
+		return
+	}
+
+	// Moderate the processed image, which is smaller than the original
+	if photoModerator != nil {
+		status, labels := moderateImage(ctx, fullImage, "image/jpeg")
+		if err := setPhotoModeration(ctx, photoID, status, labels); err != nil {
+			log.Error("❌ Failed to store moderation result of photo %d: %v", photoID, err)
+		}
+	}
+
+	result, err := pool.Exec(ctx,
+		`UPDATE menu_photos SET status = $1, processing_error = NULL, file_size = $2, updated_at = NOW()
+		WHERE id = $3 AND status = $4`,
+		photoStatusReady, len(fullImage), photoID, photoStatusProcessing)
+	if err != nil {
+		log.Error("❌ Failed to mark photo %d as ready: %v", photoID, err)
+		return
+	}
+	if result.RowsAffected() == 0 {
+		// Deleted while processing
+		deletePhotoFiles(ctx, store, filename)
+		return
+	}
+
+	if err := store.Delete(ctx, originalKey(filename)); err != nil {
+		log.Warn("Failed to delete original of %s: %v", filename, err)
+	}
+
+	log.Debug("Photo %d processed (%d bytes)", photoID, len(fullImage))
+	// The photo may now be the restaurant's cover in the list
+	cache.Invalidate(ctx, cache.KeyRestaurants)
+	publishPhotoEvent(ctx, spaceID, models.PhotoEvent{
+		Type: eventPhotoReady, PhotoID: photoID, RestaurantID: restaurantID, Status: photoStatusReady,
+	})
+}
+
+// processPhotoFiles generates the stored image and thumbnail from the original upload,
+// returning the stored image
+func processPhotoFiles(ctx context.Context, store storage.Storage, filename string, originalFilename *string) ([]byte, error) {
+	original, err := store.Download(ctx, originalKey(filename))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read original: %w", err)
+	}
+
+	name := filename
+	if originalFilename != nil {
+		name = *originalFilename
+	}
+	fullImage, thumbnail, err := services.NewImageProcessor().ProcessUpload(bytes.NewReader(original), name)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := store.Upload(ctx, photoKey(filename), bytes.NewReader(fullImage), "image/jpeg"); err != nil {
+		return nil, err
+	}
+	if err := store.Upload(ctx, thumbnailKey(filename), bytes.NewReader(thumbnail), "image/jpeg"); err != nil {
+		return nil, err
+	}
+	return fullImage, nil
+}
+
+// @Summary Stream photo processing events
+// @Description Server-Sent Events stream of photo.ready and photo.failed events for a restaurant's uploads
+// @Tags Photos
+// @Produce text/event-stream
+// @Param restaurantId path int true "Restaurant ID"
+// @Success 200 {object} models.PhotoEvent "Event stream"
+// @Failure 400 {object} errors.ErrorResponse "Invalid restaurant ID"
+// @Router /restaurants/{restaurantId}/photos/events [get]
+func StreamPhotoEvents(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	restaurantID, err := strconv.Atoi(vars["restaurantId"])
+	if err != nil {
+		apperrors.Error(w, "Invalid restaurant ID", http.StatusBadRequest)
+		return
+	}
+
+	filter := func(e events.Event) bool { return isPhotoEvent(e, restaurantID) }
+	streamEvents(w, r, filter, func(e events.Event) ([]byte, error) {
+		return e.Data, nil
+	})
+}
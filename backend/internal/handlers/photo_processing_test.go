@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"context"
+	"testing"
+)
+
+func TestOriginalKey(t *testing.T) {
+	got := originalKey("0f8fad5b-d9cb-469f-a165-70867728950e.jpg")
+	expected := "menu_photos/originals/0f8fad5b-d9cb-469f-a165-70867728950e"
+	if got != expected {
+		t.Errorf("Expected %s, got %s", expected, got)
+	}
+}
+
+func TestEnqueuePhotoProcessing(t *testing.T) {
+	previous := photoProcessingQueue
+	t.Cleanup(func() { photoProcessingQueue = previous })
+
+	// Synchronous processing
+	photoProcessingQueue = nil
+	if enqueuePhotoProcessing(context.Background(), 1) {
+		t.Error("Expected photos to be processed inline without workers")
+	}
+
+	photoProcessingQueue = make(chan photoJob, 1)
+	if !enqueuePhotoProcessing(context.Background(), 2) {
+		t.Fatal("Expected the photo to be queued")
+	}
+	if job := <-photoProcessingQueue; job.photoID != 2 {
+		t.Errorf("Expected photo 2 to be queued, got %d", job.photoID)
+	}
+
+	// A full queue falls back to inline processing
+	photoProcessingQueue <- photoJob{photoID: 3}
+	if enqueuePhotoProcessing(context.Background(), 4) {
+		t.Error("Expected photos to be processed inline when the queue is full")
+	}
+}
@@ -0,0 +1,311 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/nomdb/backend/internal/database"
+	apperrors "github.com/nomdb/backend/internal/errors"
+	"github.com/nomdb/backend/internal/models"
+	"github.com/nomdb/backend/internal/storage"
+)
+
+const (
+	maxDirectUploadSize   = 25 << 20 // 25MB; uploads go straight to storage, only the processing worker reads them
+	presignedUploadExpiry = 15 * time.Minute
+	contentSniffLength    = 512
+)
+
+// Content types accepted by direct uploads
+var directUploadTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/webp": true,
+}
+
+type pendingPhotoUpload struct {
+	RestaurantID     int
+	UserID           *int
+	Filename         string
+	OriginalFilename *string
+	Caption          string
+	ContentType      string
+	PhotoType        string
+	FileSize         int64
+	ExpiresAt        time.Time
+}
+
+func generateUploadToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// @Summary Request a presigned photo upload
+// @Description Get a presigned PUT URL so the client can upload a photo directly to cloud storage (JPEG, PNG, or WebP, max 25MB). Call the confirm endpoint with the returned upload token once the PUT succeeds. Not available with local disk storage.
+// @Tags Photos
+// @Accept json
+// @Produce json
+// @Param restaurantId path int true "Restaurant ID"
+// @Param upload body models.PresignPhotoUploadRequest true "Upload details"
+// @Success 200 {object} models.PresignPhotoUploadResponse "Presigned upload URL and token"
+// @Failure 400 {object} errors.ErrorResponse "Invalid request"
+// @Failure 404 {object} errors.ErrorResponse "Restaurant not found"
+// @Failure 429 {object} errors.ErrorResponse "Daily upload limit reached"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Failure 501 {object} errors.ErrorResponse "Storage backend does not support direct uploads"
+// @Security BearerAuth
+// @Router /restaurants/{restaurantId}/photos/presign [post]
+func PresignMenuPhotoUpload(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	restaurantID, err := strconv.Atoi(vars["restaurantId"])
+	if err != nil {
+		apperrors.Error(w, "Invalid restaurant ID", http.StatusBadRequest)
+		return
+	}
+
+	if !enforcePhotoUploadQuota(r.Context(), w, r) {
+		return
+	}
+
+	var req models.PresignPhotoUploadRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apperrors.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.Caption == "" {
+		apperrors.Error(w, "Caption is required", http.StatusBadRequest)
+		return
+	}
+
+	if req.PhotoType == "" {
+		req.PhotoType = defaultPhotoType
+	}
+	if !validPhotoTypes[req.PhotoType] {
+		apperrors.Error(w, "Invalid photo type. Must be one of: menu, food, interior, receipt", http.StatusBadRequest)
+		return
+	}
+
+	if !directUploadTypes[req.ContentType] {
+		apperrors.Error(w, "Only JPEG, PNG, and WebP images are allowed", http.StatusBadRequest)
+		return
+	}
+
+	if req.FileSize <= 0 || req.FileSize > maxDirectUploadSize {
+		apperrors.Error(w, fmt.Sprintf("File size must be between 1 byte and %d MB", maxDirectUploadSize/(1<<20)), http.StatusBadRequest)
+		return
+	}
+
+	token, err := generateUploadToken()
+	if err != nil {
+		apperrors.Error(w, "Failed to generate upload token", http.StatusInternalServerError)
+		return
+	}
+
+	var userID *int
+	if user, ok := GetUserFromContext(r); ok {
+		userID = &user.ID
+	}
+
+	var originalFilename *string
+	if req.Filename != "" {
+		originalFilename = &req.Filename
+	}
+
+	ctx := r.Context()
+	// Clients upload the original, which processing turns into a JPEG like multipart uploads
+	filename := uuid.New().String() + ".jpg"
+	expiresAt := time.Now().Add(presignedUploadExpiry)
+
+	uploadURL, headers, err := storage.Get().PresignUpload(ctx, originalKey(filename), req.ContentType, req.FileSize, presignedUploadExpiry)
+	if errors.Is(err, storage.ErrNotSupported) {
+		apperrors.Error(w, "Direct uploads are not supported by the configured storage, use the multipart upload endpoint instead", http.StatusNotImplemented)
+		return
+	}
+	if err != nil {
+		photoLog.Ctx(ctx).Error("Failed to presign upload for restaurant %d: %v", restaurantID, err)
+		apperrors.Error(w, "Failed to generate upload URL", http.StatusInternalServerError)
+		return
+	}
+
+	_, err = database.GetPool().Exec(ctx,
+		`INSERT INTO photo_uploads (token, restaurant_id, user_id, filename, original_filename, caption, content_type, file_size, expires_at, photo_type)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`,
+		token, restaurantID, userID, filename, originalFilename, req.Caption, req.ContentType, req.FileSize, expiresAt, req.PhotoType)
+	if err != nil {
+		if pgErr, ok := err.(*pgconn.PgError); ok && pgErr.Code == "23503" {
+			apperrors.Error(w, "Restaurant not found", http.StatusNotFound)
+			return
+		}
+		apperrors.Internal(w, err)
+		return
+	}
+
+	photoLog.Ctx(ctx).Debug("Presigned upload %s issued for restaurant %d", filename, restaurantID)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(models.PresignPhotoUploadResponse{
+		UploadURL:   uploadURL,
+		UploadToken: token,
+		Method:      http.MethodPut,
+		Headers:     headers,
+		ExpiresAt:   expiresAt,
+	})
+}
+
+// @Summary Confirm a presigned photo upload
+// @Description Validate a photo uploaded through a presigned URL and add it to the restaurant. The stored object must match the size and type declared at presign time. Like multipart uploads, the photo is resized and thumbnailed in the background (202, status "processing") when a processing queue is configured, or before responding (201) otherwise.
+// @Tags Photos
+// @Accept json
+// @Produce json
+// @Param restaurantId path int true "Restaurant ID"
+// @Param confirm body models.ConfirmPhotoUploadRequest true "Upload token"
+// @Success 201 {object} models.UploadPhotoResponse "Uploaded and processed photo (synchronous processing)"
+// @Success 202 {object} models.UploadPhotoResponse "Uploaded photo, processing in the background"
+// @Failure 400 {object} errors.ErrorResponse "Invalid request or uploaded file"
+// @Failure 403 {object} errors.ErrorResponse "Upload belongs to another user"
+// @Failure 404 {object} errors.ErrorResponse "Upload not found"
+// @Failure 409 {object} errors.ErrorResponse "Upload already confirmed"
+// @Failure 410 {object} errors.ErrorResponse "Upload token expired"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Failure 501 {object} errors.ErrorResponse "Storage backend does not support direct uploads"
+// @Security BearerAuth
+// @Router /restaurants/{restaurantId}/photos/confirm [post]
+func ConfirmMenuPhotoUpload(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	restaurantID, err := strconv.Atoi(vars["restaurantId"])
+	if err != nil {
+		apperrors.Error(w, "Invalid restaurant ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.ConfirmPhotoUploadRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apperrors.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.UploadToken == "" {
+		apperrors.Error(w, "Upload token is required", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+
+	var upload pendingPhotoUpload
+	err = database.GetPool().QueryRow(ctx,
+		`SELECT restaurant_id, user_id, filename, original_filename, caption, content_type, photo_type, file_size, expires_at
+		FROM photo_uploads WHERE token = $1 AND restaurant_id = $2`,
+		req.UploadToken, restaurantID,
+	).Scan(
+		&upload.RestaurantID, &upload.UserID, &upload.Filename, &upload.OriginalFilename,
+		&upload.Caption, &upload.ContentType, &upload.PhotoType, &upload.FileSize, &upload.ExpiresAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			apperrors.Error(w, "Upload not found", http.StatusNotFound)
+			return
+		}
+		apperrors.Internal(w, err)
+		return
+	}
+
+	if user, ok := GetUserFromContext(r); ok && upload.UserID != nil && *upload.UserID != user.ID {
+		apperrors.Error(w, "Upload belongs to another user", http.StatusForbidden)
+		return
+	}
+
+	store := storage.Get()
+	key := originalKey(upload.Filename)
+
+	if time.Now().After(upload.ExpiresAt) {
+		discardPhotoUpload(ctx, store, req.UploadToken, key)
+		apperrors.Error(w, "Upload token expired", http.StatusGone)
+		return
+	}
+
+	// The token stays valid if the object is not there yet so the client can retry the PUT
+	info, err := store.Stat(ctx, key)
+	if err != nil {
+		apperrors.Error(w, "Uploaded file not found, PUT the file to the upload URL first", http.StatusBadRequest)
+		return
+	}
+
+	if info.Size != upload.FileSize || info.Size > maxDirectUploadSize {
+		discardPhotoUpload(ctx, store, req.UploadToken, key)
+		apperrors.Error(w, "Uploaded file size does not match the declared size", http.StatusBadRequest)
+		return
+	}
+
+	head, err := store.ReadHead(ctx, key, contentSniffLength)
+	if err != nil {
+		apperrors.Internal(w, fmt.Errorf("failed to read uploaded file: %w", err))
+		return
+	}
+	if detected := http.DetectContentType(head); detected != upload.ContentType {
+		discardPhotoUpload(ctx, store, req.UploadToken, key)
+		apperrors.Error(w, fmt.Sprintf("Uploaded file is not a valid %s image", upload.ContentType), http.StatusBadRequest)
+		return
+	}
+
+	// Claim the token so concurrent confirmations cannot create duplicate photos
+	result, err := database.GetPool().Exec(ctx, "DELETE FROM photo_uploads WHERE token = $1", req.UploadToken)
+	if err != nil {
+		apperrors.Internal(w, err)
+		return
+	}
+	if result.RowsAffected() == 0 {
+		apperrors.Error(w, "Upload already confirmed", http.StatusConflict)
+		return
+	}
+
+	caption := upload.Caption
+	if req.Caption != nil && *req.Caption != "" {
+		caption = *req.Caption
+	}
+
+	// The upload is the original the processing pipeline expects, so it is processed in
+	// place like multipart uploads without passing through this request
+	var photoID int
+	err = database.GetPool().QueryRow(ctx,
+		`INSERT INTO menu_photos (restaurant_id, filename, original_filename, caption, file_size, mime_type, uploaded_by_user_id, photo_type, status, moderation_status, position)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, (SELECT COALESCE(MAX(position) + 1, 0) FROM menu_photos WHERE restaurant_id = $1))
+		RETURNING id`,
+		upload.RestaurantID, upload.Filename, upload.OriginalFilename, caption, int(upload.FileSize), "image/jpeg", upload.UserID, upload.PhotoType, photoStatusProcessing, initialModerationStatus(),
+	).Scan(&photoID)
+	if err != nil {
+		if delErr := store.Delete(ctx, key); delErr != nil {
+			photoLog.Ctx(ctx).Warn("Failed to delete file after database error: %v", delErr)
+		}
+		apperrors.Internal(w, err)
+		return
+	}
+
+	photoLog.Ctx(ctx).Info("✅ Direct upload confirmed for restaurant %d: %s (%d bytes)", restaurantID, upload.Filename, upload.FileSize)
+
+	respondWithUploadedPhoto(w, r, store, photoID)
+}
+
+// discardPhotoUpload removes a rejected upload's token and stored object (non-fatal)
+func discardPhotoUpload(ctx context.Context, store storage.Storage, token, key string) {
+	if _, err := database.GetPool().Exec(ctx, "DELETE FROM photo_uploads WHERE token = $1", token); err != nil {
+		photoLog.Ctx(ctx).Warn("Failed to delete pending upload: %v", err)
+	}
+	if err := store.Delete(ctx, key); err != nil {
+		photoLog.Ctx(ctx).Warn("Failed to delete rejected upload from storage: %v", err)
+	}
+}
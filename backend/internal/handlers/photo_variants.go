@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/nomdb/backend/internal/database"
+	apperrors "github.com/nomdb/backend/internal/errors"
+	"github.com/nomdb/backend/internal/models"
+	"github.com/nomdb/backend/internal/services"
+	"github.com/nomdb/backend/internal/storage"
+)
+
+const (
+	variantsSubdir      = "variants"
+	variantCacheControl = "public, max-age=31536000, immutable"
+)
+
+// Output formats supported by the resize endpoint, mapped to file extensions
+var variantFormats = map[string]string{
+	"jpeg": ".jpg",
+	"jpg":  ".jpg",
+	"png":  ".png",
+	"webp": ".webp",
+	"avif": ".avif",
+}
+
+// avifNegotiation controls whether format=auto may pick AVIF (explicit format=avif always works)
+var avifNegotiation = false
+
+// InitPhotoVariants configures which formats are picked by Accept-header negotiation
+func InitPhotoVariants(avifEnabled bool) {
+	avifNegotiation = avifEnabled
+	if avifEnabled {
+		photoLog.Info("✅ Photo variants: AVIF enabled for content negotiation")
+	}
+}
+
+// variantSizes are the bounds variants may be resized to. Every variant is stored for
+// good, so arbitrary sizes would let clients fill storage and keep the CPU busy.
+var variantSizes = []int{160, 320, 640, 1280, 1920}
+
+// parseVariantDimension parses an optional w/h query value, one of variantSizes
+// (0 means unbounded)
+func parseVariantDimension(value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || !slices.Contains(variantSizes, n) {
+		return 0, fmt.Errorf("must be one of %s", strings.Trim(fmt.Sprint(variantSizes), "[]"))
+	}
+	return n, nil
+}
+
+// variantPrefix is the storage key prefix shared by all resized variants of a photo
+func variantPrefix(filename string) string {
+	base := strings.TrimSuffix(filename, filepath.Ext(filename))
+	return fmt.Sprintf("%s/%s/%s_", photosPrefix, variantsSubdir, base)
+}
+
+// variantKey derives the cache key of a resized variant from the original photo filename
+func variantKey(filename string, width, height int, ext string) string {
+	return fmt.Sprintf("%s%dx%d%s", variantPrefix(filename), width, height, ext)
+}
+
+// @Summary Get a resized photo
+// @Description Serve a menu photo resized to fit within the requested bounds, each 160, 320, 640, 1280 or 1920 pixels. Variants are generated on first request and cached in storage. Images are never upscaled. Requests count against the photo upload rate and concurrency limits. With format=auto (the default) the output format is negotiated from the Accept header (AVIF if enabled, then WebP, then JPEG).
+// @Tags Photos
+// @Produce image/jpeg
+// @Produce image/png
+// @Produce image/webp
+// @Produce image/avif
+// @Param id path int true "Photo ID"
+// @Param w query int false "Maximum width in pixels (160, 320, 640, 1280 or 1920)"
+// @Param h query int false "Maximum height in pixels (160, 320, 640, 1280 or 1920)"
+// @Param format query string false "Output format (auto, jpeg, png, webp, avif)" default(auto)
+// @Success 200 {file} file "Resized image"
+// @Success 302 "Redirect to cached variant in storage"
+// @Failure 400 {object} errors.ErrorResponse "Invalid parameters"
+// @Failure 404 {object} errors.ErrorResponse "Photo not found"
+// @Failure 409 {object} errors.ErrorResponse "Photo is still processing or failed"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /photos/{id}/image [get]
+func GetPhotoImage(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		apperrors.Error(w, "Invalid photo ID", http.StatusBadRequest)
+		return
+	}
+
+	query := r.URL.Query()
+	width, err := parseVariantDimension(query.Get("w"))
+	if err != nil {
+		apperrors.Error(w, "Invalid width: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	height, err := parseVariantDimension(query.Get("h"))
+	if err != nil {
+		apperrors.Error(w, "Invalid height: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	format := strings.ToLower(query.Get("format"))
+	if format == "" || format == "auto" {
+		format = services.NegotiateImageFormat(r.Header.Get("Accept"), avifNegotiation)
+		w.Header().Set("Vary", "Accept")
+	}
+	ext, ok := variantFormats[format]
+	if !ok {
+		apperrors.Error(w, "Invalid format. Supported formats: auto, jpeg, png, webp, avif", http.StatusBadRequest)
+		return
+	}
+	if format == "jpg" {
+		format = "jpeg"
+	}
+
+	ctx := r.Context()
+
+	var photo models.MenuPhoto
+	err = database.GetPool().QueryRow(ctx,
+		"SELECT filename, status, moderation_status, uploaded_by_user_id FROM menu_photos WHERE id = $1", id,
+	).Scan(&photo.Filename, &photo.Status, &photo.ModerationStatus, &photo.UploadedByUserID)
+	if err != nil || !canViewPhoto(r, &photo) {
+		apperrors.Error(w, "Photo not found", http.StatusNotFound)
+		return
+	}
+	if photo.Status != photoStatusReady {
+		apperrors.Error(w, fmt.Sprintf("Photo is not available (status: %s)", photo.Status), http.StatusConflict)
+		return
+	}
+	filename := photo.Filename
+
+	key := variantKey(filename, width, height, ext)
+	store := storage.Get()
+
+	// Cached variants are served straight from storage
+	if _, err := store.Stat(ctx, key); err == nil {
+		url, err := store.URL(ctx, key, time.Hour)
+		if err != nil {
+			apperrors.Error(w, "Failed to generate URL", http.StatusInternalServerError)
+			return
+		}
+		http.Redirect(w, r, url, http.StatusFound)
+		return
+	}
+
+	original, err := store.Download(ctx, photoKey(filename))
+	if err != nil {
+		apperrors.Internal(w, fmt.Errorf("failed to read photo: %w", err))
+		return
+	}
+
+	data, contentType, err := services.NewImageProcessor().ResizeVariant(bytes.NewReader(original), width, height, format)
+	if err != nil {
+		apperrors.Internal(w, fmt.Errorf("failed to process image: %w", err))
+		return
+	}
+
+	// Caching is best effort, the variant is still served if the upload fails
+	if err := store.Upload(ctx, key, bytes.NewReader(data), contentType); err != nil {
+		photoLog.Warn("Failed to cache photo variant %s: %v", key, err)
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	w.Header().Set("Cache-Control", variantCacheControl)
+	w.Write(data)
+}
@@ -0,0 +1,26 @@
+package handlers
+
+import "testing"
+
+func TestParseVariantDimension(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    int
+		wantErr bool
+	}{
+		{"", 0, false},
+		{"320", 320, false},
+		{"1920", 1920, false},
+		// Only the fixed sizes are resized to
+		{"321", 0, true},
+		{"1", 0, true},
+		{"3840", 0, true},
+		{"wide", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := parseVariantDimension(tt.value)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parseVariantDimension(%q) = %d, %v", tt.value, got, err)
+		}
+	}
+}
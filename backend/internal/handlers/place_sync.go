@@ -0,0 +1,283 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/nomdb/backend/internal/cache"
+	apperrors "github.com/nomdb/backend/internal/errors"
+	"github.com/nomdb/backend/internal/logger"
+	"github.com/nomdb/backend/internal/models"
+	"github.com/nomdb/backend/internal/repository"
+	"github.com/nomdb/backend/internal/services"
+)
+
+// maxPlaceSyncBatch bounds the restaurants synced by one run, each costing a place
+// details request
+const maxPlaceSyncBatch = 1000
+
+var (
+	// errPlaceSyncRunning is returned when a sync is already in progress
+	errPlaceSyncRunning = errors.New("place sync already running")
+	// errPlaceSyncDisabled is returned when no places provider is configured
+	errPlaceSyncDisabled = errors.New("places provider not configured")
+)
+
+var placeSyncMu sync.Mutex
+
+// StartPlaceSyncJob periodically compares batchSize restaurants with their place details,
+// least recently synced first, and queues the differences for review
+func StartPlaceSyncJob(interval time.Duration, batchSize int) {
+	if interval <= 0 {
+		logger.Debug("Place sync job disabled")
+		return
+	}
+
+	logger.Info("✅ Place sync job scheduled every %s (%d restaurants per run)", interval, batchSize)
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if _, err := syncPlaces(context.Background(), batchSize); err != nil {
+				logger.Error("❌ Place sync failed: %v", err)
+			}
+		}
+	}()
+}
+
+// syncPlaces fetches the place details of up to limit restaurants and records how they
+// differ. It stops early while the places provider is unavailable.
+func syncPlaces(ctx context.Context, limit int) (*models.PlaceSyncReport, error) {
+	if !placesProvider.Enabled() {
+		return nil, errPlaceSyncDisabled
+	}
+	if !placeSyncMu.TryLock() {
+		return nil, errPlaceSyncRunning
+	}
+	defer placeSyncMu.Unlock()
+
+	report := &models.PlaceSyncReport{StartedAt: time.Now()}
+	restaurants, err := repos.PlaceChanges.DueForSync(ctx, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, rest := range restaurants {
+		place, err := placesProvider.GetPlaceDetails(ctx, *rest.GooglePlaceID)
+		if errors.Is(err, services.ErrMapsUnavailable) || errors.Is(err, services.ErrMapsBudgetExceeded) || ctx.Err() != nil {
+			logger.Warn("⚠️  Place sync stopped after %d restaurants: %v", report.Checked, err)
+			break
+		}
+		if err != nil {
+			// Move on, so a place that no longer exists does not block the others
+			logger.Warn("Failed to sync place of restaurant %d: %v", rest.ID, err)
+			report.Failed++
+			if err := repos.PlaceChanges.MarkSynced(ctx, rest.ID); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		recorded, err := repos.PlaceChanges.Record(ctx, rest.ID, placeChanges(rest, place))
+		if err != nil {
+			return nil, err
+		}
+		report.Checked++
+		report.Changes += recorded
+	}
+
+	report.FinishedAt = time.Now()
+	logger.Info("🔄 Place sync: %d restaurants checked, %d changes queued, %d failed",
+		report.Checked, report.Changes, report.Failed)
+	return report, nil
+}
+
+// placeChanges compares a restaurant with its place details. Values missing from the
+// details are not proposed as removals, since providers leave out fields they lack.
+func placeChanges(rest models.Restaurant, place *models.GooglePlaceResult) []models.PlaceChange {
+	var changes []models.PlaceChange
+	add := func(field string, oldValue, newValue any) {
+		change := models.PlaceChange{RestaurantID: rest.ID, Field: field}
+		change.NewValue, _ = json.Marshal(newValue)
+		if oldValue != nil {
+			change.OldValue, _ = json.Marshal(oldValue)
+		}
+		changes = append(changes, change)
+	}
+	compare := func(field string, current *string, value string) {
+		if value != "" && (current == nil || *current != value) {
+			if current == nil {
+				add(field, nil, value)
+			} else {
+				add(field, *current, value)
+			}
+		}
+	}
+
+	compare("phone", rest.Phone, place.Phone)
+	compare("website", rest.Website, place.Website)
+	compare("business_status", rest.BusinessStatus, place.BusinessStatus)
+	if place.OpeningHours != nil && len(place.OpeningHours.Weekdays) > 0 &&
+		!slices.Equal(rest.OpeningHours, place.OpeningHours.Weekdays) {
+		if rest.OpeningHours == nil {
+			add("opening_hours", nil, place.OpeningHours.Weekdays)
+		} else {
+			add("opening_hours", rest.OpeningHours, place.OpeningHours.Weekdays)
+		}
+	}
+	return changes
+}
+
+// @Summary Sync restaurants with their places
+// @Description Fetch the place details of restaurants with a Google Place ID, least recently synced first, and queue differences in phone, website, business status and opening hours for review. Admin only.
+// @Tags Restaurants
+// @Produce json
+// @Param limit query int false "Restaurants to sync (default 50, max 1000)"
+// @Success 200 {object} models.PlaceSyncReport "Sync report"
+// @Failure 400 {object} errors.ErrorResponse "Invalid limit"
+// @Failure 409 {object} errors.ErrorResponse "Sync already running"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Failure 503 {object} errors.ErrorResponse "Places provider not configured"
+// @Security BearerAuth
+// @Router /admin/places/sync [post]
+func SyncPlaces(w http.ResponseWriter, r *http.Request) {
+	limit := 50
+	if v := r.URL.Query().Get("limit"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 1 || parsed > maxPlaceSyncBatch {
+			apperrors.Error(w, "limit must be between 1 and 1000", http.StatusBadRequest)
+			return
+		}
+		limit = parsed
+	}
+
+	ctx := r.Context()
+	report, err := syncPlaces(ctx, limit)
+	if err != nil {
+		switch {
+		case errors.Is(err, errPlaceSyncRunning):
+			apperrors.Error(w, err.Error(), http.StatusConflict)
+		case errors.Is(err, errPlaceSyncDisabled):
+			apperrors.Error(w, err.Error(), http.StatusServiceUnavailable)
+		default:
+			logger.Error("❌ Place sync failed: %v", err)
+			apperrors.Internal(w, err)
+		}
+		return
+	}
+
+	recordAudit(ctx, r, auditEvent{
+		Event: AuditAdminAction,
+		Details: map[string]any{
+			"action":  "place_sync",
+			"checked": report.Checked,
+			"changes": report.Changes,
+		},
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+// @Summary List place changes
+// @Description List differences between restaurants and their place details found by the place sync, oldest first. Admin only.
+// @Tags Restaurants
+// @Produce json
+// @Param status query string false "Filter by status (pending, accepted, rejected)"
+// @Success 200 {array} models.PlaceChange "Place changes"
+// @Failure 400 {object} errors.ErrorResponse "Invalid status"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /admin/place-changes [get]
+func GetPlaceChanges(w http.ResponseWriter, r *http.Request) {
+	status := r.URL.Query().Get("status")
+	switch status {
+	case "", repository.PlaceChangePending, repository.PlaceChangeAccepted, repository.PlaceChangeRejected:
+	default:
+		apperrors.Error(w, "Invalid status. Must be one of: pending, accepted, rejected", http.StatusBadRequest)
+		return
+	}
+
+	changes, err := repos.PlaceChanges.List(r.Context(), status)
+	if err != nil {
+		apperrors.Internal(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(changes)
+}
+
+// @Summary Review a place change
+// @Description Accept a pending place change, writing the new value to the restaurant, or reject it; rejected values are not proposed again. Admin only.
+// @Tags Restaurants
+// @Accept json
+// @Produce json
+// @Param id path int true "Place change ID"
+// @Param decision body models.ReviewPlaceChangeRequest true "Review decision"
+// @Success 200 {object} models.PlaceChange "Reviewed change"
+// @Failure 400 {object} errors.ErrorResponse "Invalid request"
+// @Failure 404 {object} errors.ErrorResponse "No pending change with this ID"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /admin/place-changes/{id} [post]
+func ReviewPlaceChange(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		apperrors.Error(w, "Invalid place change ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.ReviewPlaceChangeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apperrors.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.Action != "accept" && req.Action != "reject" {
+		apperrors.Error(w, "Invalid action. Must be one of: accept, reject", http.StatusBadRequest)
+		return
+	}
+
+	user, ok := GetUserFromContext(r)
+	if !ok {
+		apperrors.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	ctx := r.Context()
+	change, err := repos.PlaceChanges.Review(ctx, id, req.Action == "accept", &user.ID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			apperrors.Error(w, "Pending place change not found", http.StatusNotFound)
+			return
+		}
+		apperrors.Internal(w, err)
+		return
+	}
+
+	logger.Info("✅ Place change %d (%s of restaurant %d) %sed by %s", id, change.Field, change.RestaurantID, req.Action, user.Username)
+	recordAdminAction(ctx, r, req.Action+"_place_change", "restaurant", change.RestaurantID, map[string]any{
+		"place_change_id": id,
+		"field":           change.Field,
+	})
+	if change.Status == repository.PlaceChangeAccepted {
+		cache.Invalidate(ctx, cache.KeyRestaurants)
+		// Admins review changes of every space; the event goes to the restaurant's
+		if space, err := repos.Spaces.SpaceOf(ctx, repository.SpaceOfRestaurant, change.RestaurantID); err != nil {
+			logger.Warn("Failed to find the space of restaurant %d: %v", change.RestaurantID, err)
+		} else {
+			publishChange(ctx, eventRestaurantUpdated, space, change.RestaurantID, change.RestaurantID)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(change)
+}
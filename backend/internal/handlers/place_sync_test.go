@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nomdb/backend/internal/models"
+	"github.com/nomdb/backend/internal/services"
+	"github.com/pashagolub/pgxmock/v4"
+)
+
+// fakePlacesProvider answers place details from a map; places missing from it are not found
+type fakePlacesProvider struct {
+	services.PlacesProvider
+	places map[string]*models.GooglePlaceResult
+	err    error // Returned for every place when set
+}
+
+func (f *fakePlacesProvider) Name() string  { return "fake" }
+func (f *fakePlacesProvider) Enabled() bool { return true }
+
+func (f *fakePlacesProvider) GetPlaceDetails(_ context.Context, placeID string) (*models.GooglePlaceResult, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	if place, ok := f.places[placeID]; ok {
+		return place, nil
+	}
+	return nil, services.ErrPlaceNotFound
+}
+
+func withPlacesProvider(t *testing.T, fake services.PlacesProvider) {
+	t.Helper()
+	original := placesProvider
+	placesProvider = fake
+	t.Cleanup(func() { placesProvider = original })
+}
+
+func TestPlaceChanges(t *testing.T) {
+	phone, status := "030 123", "OPERATIONAL"
+	rest := models.Restaurant{ID: 3, Phone: &phone, BusinessStatus: &status, OpeningHours: []string{"Monday: Closed"}}
+
+	changes := placeChanges(rest, &models.GooglePlaceResult{
+		Phone:          "+49 30 123",
+		Website:        "https://luigis.example",
+		BusinessStatus: "CLOSED_PERMANENTLY",
+		OpeningHours:   &models.GoogleOpeningHours{Weekdays: []string{"Monday: Closed"}},
+	})
+
+	got := map[string][2]string{}
+	for _, change := range changes {
+		if change.RestaurantID != 3 {
+			t.Errorf("Unexpected restaurant of %+v", change)
+		}
+		got[change.Field] = [2]string{string(change.OldValue), string(change.NewValue)}
+	}
+	want := map[string][2]string{
+		"phone":           {`"030 123"`, `"+49 30 123"`},
+		"website":         {"", `"https://luigis.example"`},
+		"business_status": {`"OPERATIONAL"`, `"CLOSED_PERMANENTLY"`},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Expected changes %v, got %v", want, got)
+	}
+	for field, values := range want {
+		if got[field] != values {
+			t.Errorf("%s: expected %v, got %v", field, values, got[field])
+		}
+	}
+
+	// Fields missing from the details are not proposed as removals
+	if changes := placeChanges(rest, &models.GooglePlaceResult{Phone: phone}); len(changes) != 0 {
+		t.Errorf("Expected no changes, got %+v", changes)
+	}
+}
+
+var syncColumns = []string{
+	"id", "name", "description", "address", "phone", "website", "latitude", "longitude",
+	"google_place_id", "category_id", "created_at", "updated_at", "business_status", "opening_hours",
+}
+
+func TestSyncPlaces(t *testing.T) {
+	mock := withMockRepositories(t)
+	withPlacesProvider(t, &fakePlacesProvider{places: map[string]*models.GooglePlaceResult{
+		"place-a": {PlaceID: "place-a", Phone: "+49 30 123"},
+	}})
+
+	now := time.Now()
+	placeA, placeB := "place-a", "place-b"
+	mock.ExpectQuery(`ORDER BY place_synced_at NULLS FIRST`).WithArgs(10).
+		WillReturnRows(pgxmock.NewRows(syncColumns).
+			AddRow(1, "Luigi's", nil, nil, nil, nil, nil, nil, &placeA, nil, now, now, nil, nil).
+			AddRow(2, "Gone", nil, nil, nil, nil, nil, nil, &placeB, nil, now, now, nil, nil))
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM restaurant_place_changes`).WithArgs(1, []string{"phone"}).
+		WillReturnResult(pgxmock.NewResult("DELETE", 0))
+	mock.ExpectExec(`INSERT INTO restaurant_place_changes`).
+		WithArgs(1, "phone", json.RawMessage(nil), json.RawMessage(`"+49 30 123"`)).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	mock.ExpectExec(`UPDATE restaurants SET place_synced_at`).WithArgs(1).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	mock.ExpectCommit()
+	// The place that cannot be found still moves to the back of the queue
+	mock.ExpectExec(`UPDATE restaurants SET place_synced_at`).WithArgs(2).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+	report, err := syncPlaces(context.Background(), 10)
+	if err != nil {
+		t.Fatalf("syncPlaces failed: %v", err)
+	}
+	if report.Checked != 1 || report.Changes != 1 || report.Failed != 1 {
+		t.Errorf("Unexpected report %+v", report)
+	}
+}
+
+func TestSyncPlaces_StopsWhileUnavailable(t *testing.T) {
+	mock := withMockRepositories(t)
+	withPlacesProvider(t, &fakePlacesProvider{err: services.ErrMapsUnavailable})
+
+	now := time.Now()
+	placeA := "place-a"
+	mock.ExpectQuery(`ORDER BY place_synced_at NULLS FIRST`).WithArgs(10).
+		WillReturnRows(pgxmock.NewRows(syncColumns).
+			AddRow(1, "Luigi's", nil, nil, nil, nil, nil, nil, &placeA, nil, now, now, nil, nil))
+
+	// Nothing is marked synced, so the restaurants are tried first next time
+	report, err := syncPlaces(context.Background(), 10)
+	if err != nil {
+		t.Fatalf("syncPlaces failed: %v", err)
+	}
+	if report.Checked != 0 || report.Failed != 0 {
+		t.Errorf("Unexpected report %+v", report)
+	}
+}
+
+func TestSyncPlaces_Running(t *testing.T) {
+	withPlacesProvider(t, &fakePlacesProvider{})
+	placeSyncMu.Lock()
+	defer placeSyncMu.Unlock()
+
+	if _, err := syncPlaces(context.Background(), 10); !errors.Is(err, errPlaceSyncRunning) {
+		t.Errorf("Expected errPlaceSyncRunning, got %v", err)
+	}
+}
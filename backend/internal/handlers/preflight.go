@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/nomdb/backend/internal/database"
+	"github.com/nomdb/backend/internal/models"
+	"github.com/nomdb/backend/internal/storage"
+)
+
+// preflightTimeout bounds each startup check; writing to cloud storage takes several
+// round trips
+const preflightTimeout = 10 * time.Second
+
+// preflightPrefix holds the objects the storage check writes
+const preflightPrefix = "preflight/"
+
+// Preflight checks the dependencies before the server accepts traffic: the database
+// connection, that the schema is at the newest migration of this binary, that storage
+// is writable, and that the Google Maps API key and places provider work. Checks of
+// unconfigured dependencies pass with details saying they were skipped.
+func Preflight(ctx context.Context) map[string]models.HealthCheck {
+	checks := map[string]func(context.Context) (string, error){
+		"database":    checkDatabase,
+		"migrations":  checkSchemaVersion,
+		"storage":     checkStorageWritable,
+		"google_maps": checkGoogleMapsKey,
+	}
+	if placesProvider != mapsService {
+		checks["places_provider"] = checkGoogleMaps
+	}
+	return runHealthChecks(ctx, checks, preflightTimeout)
+}
+
+// checkSchemaVersion verifies that the schema is at the newest migration embedded in
+// the binary, so the queries of this version find the columns they expect
+func checkSchemaVersion(ctx context.Context) (string, error) {
+	latest, err := database.LatestMigration()
+	if err != nil {
+		return "", err
+	}
+	pool := database.GetPool()
+	if pool == nil {
+		return "", fmt.Errorf("not connected")
+	}
+
+	var version int64
+	var dirty bool
+	err = pool.QueryRow(ctx,
+		"SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&version, &dirty)
+	if err != nil {
+		return "", fmt.Errorf("no migrations applied: %w", err)
+	}
+	details := fmt.Sprintf("version %d, binary expects %d", version, latest)
+	switch {
+	case dirty:
+		return details, fmt.Errorf("migration %d is dirty", version)
+	case uint(version) < latest:
+		return details, fmt.Errorf("%d migrations pending", latest-uint(version))
+	case uint(version) > latest:
+		return details, fmt.Errorf("the schema is newer than this binary")
+	}
+	return details, nil
+}
+
+// checkStorageWritable writes, reads back and deletes a test object
+func checkStorageWritable(ctx context.Context) (string, error) {
+	store := storage.Get()
+	if store == nil {
+		return "", fmt.Errorf("not initialized")
+	}
+
+	host, _ := os.Hostname()
+	key := preflightPrefix + host + "-" + strconv.FormatInt(time.Now().UnixNano(), 36)
+	content := []byte("nomdb preflight check")
+	if err := store.Upload(ctx, key, bytes.NewReader(content), "text/plain"); err != nil {
+		return store.Name(), fmt.Errorf("write failed: %w", err)
+	}
+	read, err := store.Download(ctx, key)
+	deleteErr := store.Delete(ctx, key)
+	switch {
+	case err != nil:
+		return store.Name(), fmt.Errorf("read failed: %w", err)
+	case !bytes.Equal(read, content):
+		return store.Name(), fmt.Errorf("read back %d bytes, wrote %d", len(read), len(content))
+	case deleteErr != nil:
+		return store.Name(), fmt.Errorf("delete failed: %w", deleteErr)
+	}
+	return store.Name(), nil
+}
+
+// checkGoogleMapsKey verifies that Google accepts the API key, if one is configured
+func checkGoogleMapsKey(ctx context.Context) (string, error) {
+	if !mapsService.Enabled() {
+		return "skipped, no API key", nil
+	}
+	if err := mapsService.CheckAPIKey(ctx); err != nil {
+		return "", err
+	}
+	return "API key accepted", nil
+}
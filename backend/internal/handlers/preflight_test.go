@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/nomdb/backend/internal/storage"
+)
+
+func TestPreflight_WithoutDatabase(t *testing.T) {
+	if err := storage.Init(storage.Config{Backend: storage.BackendLocal, LocalDir: t.TempDir()}); err != nil {
+		t.Fatalf("storage.Init failed: %v", err)
+	}
+
+	// Tests run without a database connection or Google Maps API key
+	results := Preflight(context.Background())
+	for _, name := range []string{"database", "migrations"} {
+		if results[name].Status != "error" {
+			t.Errorf("Expected the %s check to fail, got %+v", name, results[name])
+		}
+	}
+	if check := results["storage"]; check.Status != "ok" {
+		t.Errorf("Expected local storage to be writable, got %+v", check)
+	}
+	if check := results["google_maps"]; check.Status != "ok" || check.Details != "skipped, no API key" {
+		t.Errorf("Expected the key check to be skipped, got %+v", check)
+	}
+}
+
+func TestCheckStorageWritable_CleansUp(t *testing.T) {
+	if err := storage.Init(storage.Config{Backend: storage.BackendLocal, LocalDir: t.TempDir()}); err != nil {
+		t.Fatalf("storage.Init failed: %v", err)
+	}
+	if _, err := checkStorageWritable(context.Background()); err != nil {
+		t.Fatalf("checkStorageWritable failed: %v", err)
+	}
+	objects, err := storage.Get().List(context.Background(), preflightPrefix)
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(objects) != 0 {
+		t.Errorf("Expected the test object to be deleted, got %v", objects)
+	}
+}
@@ -1,14 +1,25 @@
 package handlers
 
 import (
-	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/gorilla/mux"
-	"github.com/nomdb/backend/internal/database"
+	"github.com/nomdb/backend/internal/cache"
+	apperrors "github.com/nomdb/backend/internal/errors"
 	"github.com/nomdb/backend/internal/models"
+	"github.com/nomdb/backend/internal/repository"
+)
+
+// Bounds of the food, service and ambiance ratings
+const (
+	minRating = 1
+	maxRating = 5
 )
 
 // GetRatings godoc
@@ -19,35 +30,22 @@ import (
 // @Produce json
 // @Param restaurantId path int true "Restaurant ID"
 // @Success 200 {array} models.Rating "List of ratings"
-// @Failure 400 {object} map[string]string "Invalid restaurant ID"
-// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 400 {object} errors.ErrorResponse "Invalid restaurant ID"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
 // @Router /restaurants/{restaurantId}/ratings [get]
 func GetRatings(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	restaurantID, err := strconv.Atoi(vars["restaurantId"])
 	if err != nil {
-		http.Error(w, "Invalid restaurant ID", http.StatusBadRequest)
+		apperrors.Error(w, "Invalid restaurant ID", http.StatusBadRequest)
 		return
 	}
 
-	rows, err := database.GetPool().Query(context.Background(),
-		`SELECT id, restaurant_id, food_rating, service_rating, ambiance_rating, comment, created_at
-		FROM ratings WHERE restaurant_id = $1 ORDER BY created_at DESC`, restaurantID)
+	ratings, err := ratingRepo.ListByRestaurant(r.Context(), restaurantID)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		apperrors.Internal(w, err)
 		return
 	}
-	defer rows.Close()
-
-	ratings := []models.Rating{}
-	for rows.Next() {
-		var rt models.Rating
-		if err := rows.Scan(&rt.ID, &rt.RestaurantID, &rt.FoodRating, &rt.ServiceRating, &rt.AmbianceRating, &rt.Comment, &rt.CreatedAt); err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		ratings = append(ratings, rt)
-	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(ratings)
@@ -61,48 +59,51 @@ func GetRatings(w http.ResponseWriter, r *http.Request) {
 // @Produce json
 // @Param rating body models.CreateRatingRequest true "Rating creation request"
 // @Success 201 {object} models.Rating "Created rating"
-// @Failure 400 {object} map[string]string "Invalid request"
-// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 400 {object} errors.ErrorResponse "Invalid request"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
 // @Router /ratings [post]
 func CreateRating(w http.ResponseWriter, r *http.Request) {
 	var req models.CreateRatingRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		apperrors.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
 
 	if req.RestaurantID == 0 {
-		http.Error(w, "Restaurant ID is required", http.StatusBadRequest)
+		apperrors.Error(w, "Restaurant ID is required", http.StatusBadRequest)
 		return
 	}
 
-	if req.FoodRating < 1 || req.FoodRating > 5 ||
-		req.ServiceRating < 1 || req.ServiceRating > 5 ||
-		req.AmbianceRating < 1 || req.AmbianceRating > 5 {
-		http.Error(w, "Ratings must be between 1 and 5", http.StatusBadRequest)
+	if req.FoodRating < minRating || req.FoodRating > maxRating ||
+		req.ServiceRating < minRating || req.ServiceRating > maxRating ||
+		req.AmbianceRating < minRating || req.AmbianceRating > maxRating {
+		apperrors.Error(w, "Ratings must be between 1 and 5", http.StatusBadRequest)
 		return
 	}
 
-	// Check if restaurant exists
-	var exists bool
-	err := database.GetPool().QueryRow(context.Background(),
-		"SELECT EXISTS(SELECT 1 FROM restaurants WHERE id = $1)", req.RestaurantID).Scan(&exists)
+	// Check if restaurant exists in the space
+	exists, err := restaurantRepo.Exists(r.Context(), spaceID(r), req.RestaurantID)
 	if err != nil || !exists {
-		http.Error(w, "Restaurant not found", http.StatusNotFound)
+		apperrors.Error(w, "Restaurant not found", http.StatusNotFound)
 		return
 	}
 
-	var rt models.Rating
-	err = database.GetPool().QueryRow(context.Background(),
-		`INSERT INTO ratings (restaurant_id, food_rating, service_rating, ambiance_rating, comment)
-		VALUES ($1, $2, $3, $4, $5)
-		RETURNING id, restaurant_id, food_rating, service_rating, ambiance_rating, comment, created_at`,
-		req.RestaurantID, req.FoodRating, req.ServiceRating, req.AmbianceRating, req.Comment,
-	).Scan(&rt.ID, &rt.RestaurantID, &rt.FoodRating, &rt.ServiceRating, &rt.AmbianceRating, &rt.Comment, &rt.CreatedAt)
+	var authorID *int
+	if user, ok := GetUserFromContext(r); ok {
+		authorID = &user.ID
+	}
+
+	rt, err := ratingRepo.Create(r.Context(), req, authorID)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		if writeConstraintViolation(w, err) {
+			return
+		}
+		apperrors.Internal(w, err)
 		return
 	}
+	// The list shows average ratings
+	cache.Invalidate(r.Context(), cache.KeyRestaurants)
+	publishChange(r.Context(), eventRatingCreated, spaceID(r), rt.RestaurantID, rt.ID)
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
@@ -111,35 +112,192 @@ func CreateRating(w http.ResponseWriter, r *http.Request) {
 
 // DeleteRating godoc
 // @Summary Delete a rating
-// @Description Delete a rating by ID
+// @Description Delete a rating by ID. Only the author or an admin may delete a rating.
 // @Tags Ratings
 // @Accept json
 // @Produce json
 // @Param id path int true "Rating ID"
 // @Success 204 "Rating deleted successfully"
-// @Failure 400 {object} map[string]string "Invalid rating ID"
-// @Failure 404 {object} map[string]string "Rating not found"
-// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 400 {object} errors.ErrorResponse "Invalid rating ID"
+// @Failure 403 {object} errors.ErrorResponse "Not the author of the rating"
+// @Failure 404 {object} errors.ErrorResponse "Rating not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
 // @Router /ratings/{id} [delete]
 func DeleteRating(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id, err := strconv.Atoi(vars["id"])
 	if err != nil {
-		http.Error(w, "Invalid rating ID", http.StatusBadRequest)
+		apperrors.Error(w, "Invalid rating ID", http.StatusBadRequest)
+		return
+	}
+
+	user, ok := GetUserFromContext(r)
+	if !ok {
+		apperrors.Error(w, "Unauthorized", http.StatusUnauthorized)
 		return
 	}
 
-	result, err := database.GetPool().Exec(context.Background(),
-		"DELETE FROM ratings WHERE id = $1", id)
+	ctx := r.Context()
+
+	// Ratings without a recorded author can only be deleted by admins of the instance or space
+	authorID, err := ratingRepo.AuthorID(ctx, id)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		if errors.Is(err, repository.ErrNotFound) {
+			apperrors.Error(w, "Rating not found", http.StatusNotFound)
+			return
+		}
+		apperrors.Internal(w, err)
+		return
+	}
+	if !isSpaceAdmin(r, user) && (authorID == nil || *authorID != user.ID) {
+		apperrors.Error(w, "Only the author or an admin can delete this rating", http.StatusForbidden)
 		return
 	}
 
-	if result.RowsAffected() == 0 {
-		http.Error(w, "Rating not found", http.StatusNotFound)
+	restaurantID, err := ratingRepo.Delete(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			apperrors.Error(w, "Rating not found", http.StatusNotFound)
+			return
+		}
+		apperrors.Internal(w, err)
 		return
 	}
+	cache.Invalidate(ctx, cache.KeyRestaurants)
+	publishChange(ctx, eventRatingDeleted, spaceID(r), restaurantID, id)
 
+	// Moderation of other users' ratings is an admin action
+	if authorID == nil || *authorID != user.ID {
+		recordAdminAction(ctx, r, "delete_rating", "rating", id, nil)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// maxOwnerResponseLength bounds the owner's response to a rating
+const maxOwnerResponseLength = 2000
+
+// SetRatingResponse godoc
+// @Summary Respond to a rating
+// @Description Set the official response of the restaurant's owner to a rating, replacing an earlier one. A rating has at most one response. Owner of the restaurant only.
+// @Tags Ratings
+// @Accept json
+// @Produce json
+// @Param id path int true "Rating ID"
+// @Param response body models.OwnerResponseRequest true "Response"
+// @Success 200 {object} models.Rating "Rating with the response"
+// @Failure 400 {object} errors.ErrorResponse "Invalid request"
+// @Failure 403 {object} errors.ErrorResponse "Not the owner of the restaurant"
+// @Failure 404 {object} errors.ErrorResponse "Rating not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /ratings/{id}/response [put]
+func SetRatingResponse(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		apperrors.Error(w, "Invalid rating ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.OwnerResponseRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apperrors.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	response := strings.TrimSpace(req.Response)
+	if response == "" {
+		apperrors.Error(w, "Response is required", http.StatusBadRequest)
+		return
+	}
+	if utf8.RuneCountInString(response) > maxOwnerResponseLength {
+		apperrors.Error(w, fmt.Sprintf("Response must be at most %d characters", maxOwnerResponseLength), http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	restaurantID, err := ratingRepo.RestaurantID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			apperrors.Error(w, "Rating not found", http.StatusNotFound)
+			return
+		}
+		apperrors.Internal(w, err)
+		return
+	}
+	if _, ok := restaurantOwner(w, r, restaurantID); !ok {
+		return
+	}
+
+	rt, err := ratingRepo.SetOwnerResponse(ctx, id, &response)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			apperrors.Error(w, "Rating not found", http.StatusNotFound)
+			return
+		}
+		apperrors.Internal(w, err)
+		return
+	}
+	publishChange(ctx, eventRatingUpdated, spaceID(r), rt.RestaurantID, rt.ID)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rt)
+}
+
+// DeleteRatingResponse godoc
+// @Summary Delete a rating response
+// @Description Delete the owner's response to a rating. Only the owner of the restaurant or an admin may delete it.
+// @Tags Ratings
+// @Param id path int true "Rating ID"
+// @Success 204 "Response deleted"
+// @Failure 400 {object} errors.ErrorResponse "Invalid rating ID"
+// @Failure 403 {object} errors.ErrorResponse "Not the owner of the restaurant"
+// @Failure 404 {object} errors.ErrorResponse "Rating not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /ratings/{id}/response [delete]
+func DeleteRatingResponse(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		apperrors.Error(w, "Invalid rating ID", http.StatusBadRequest)
+		return
+	}
+
+	user, ok := GetUserFromContext(r)
+	if !ok {
+		apperrors.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	ctx := r.Context()
+	restaurantID, err := ratingRepo.RestaurantID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			apperrors.Error(w, "Rating not found", http.StatusNotFound)
+			return
+		}
+		apperrors.Internal(w, err)
+		return
+	}
+
+	// Admins of the instance or space moderate responses
+	moderated := isSpaceAdmin(r, user)
+	if !moderated {
+		if _, ok := restaurantOwner(w, r, restaurantID); !ok {
+			return
+		}
+	}
+
+	if _, err := ratingRepo.SetOwnerResponse(ctx, id, nil); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			apperrors.Error(w, "Rating not found", http.StatusNotFound)
+			return
+		}
+		apperrors.Internal(w, err)
+		return
+	}
+	publishChange(ctx, eventRatingUpdated, spaceID(r), restaurantID, id)
+
+	if moderated {
+		recordAdminAction(ctx, r, "delete_rating_response", "rating", id, nil)
+	}
 	w.WriteHeader(http.StatusNoContent)
 }
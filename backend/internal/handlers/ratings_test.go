@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/nomdb/backend/internal/auth"
+	"github.com/nomdb/backend/internal/cache"
+	"github.com/nomdb/backend/internal/models"
+	"github.com/nomdb/backend/internal/repository"
+	"github.com/pashagolub/pgxmock/v4"
+)
+
+// withMockRepositories points the handlers at repositories backed by a pgxmock pool
+func withMockRepositories(t *testing.T) pgxmock.PgxPoolIface {
+	t.Helper()
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatalf("Failed to create mock: %v", err)
+	}
+	InitRepositories(repository.New(mock))
+	t.Cleanup(func() {
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Unmet expectations: %v", err)
+		}
+		mock.Close()
+		repos, restaurantRepo, ratingRepo, suggestionRepo = nil, nil, nil, nil
+	})
+	return mock
+}
+
+func deleteRatingRequest(id string, user *models.User) *http.Request {
+	req := httptest.NewRequest(http.MethodDelete, "/api/ratings/"+id, nil)
+	req = mux.SetURLVars(req, map[string]string{"id": id})
+	return req.WithContext(context.WithValue(req.Context(), models.UserContextKey, user))
+}
+
+func TestDeleteRating(t *testing.T) {
+	author := 7
+
+	t.Run("author deletes own rating", func(t *testing.T) {
+		mock := withMockRepositories(t)
+		withMemoryCache(t)
+		cache.SetJSON(context.Background(), cache.KeyRestaurants, []models.Restaurant{})
+		mock.ExpectQuery(`SELECT user_id FROM ratings`).WithArgs(3).
+			WillReturnRows(pgxmock.NewRows([]string{"user_id"}).AddRow(&author))
+		mock.ExpectQuery(`DELETE FROM ratings`).WithArgs(3).
+			WillReturnRows(pgxmock.NewRows([]string{"restaurant_id"}).AddRow(9))
+
+		rec := httptest.NewRecorder()
+		DeleteRating(rec, deleteRatingRequest("3", &models.User{ID: author}))
+		if rec.Code != http.StatusNoContent {
+			t.Errorf("Expected 204, got %d: %s", rec.Code, rec.Body.String())
+		}
+		// Average ratings in the restaurant list changed
+		var cached []models.Restaurant
+		if cache.GetJSON(context.Background(), cache.KeyRestaurants, &cached) {
+			t.Error("Expected the cached restaurant list to be invalidated")
+		}
+	})
+
+	t.Run("other user is forbidden", func(t *testing.T) {
+		mock := withMockRepositories(t)
+		mock.ExpectQuery(`SELECT user_id FROM ratings`).WithArgs(3).
+			WillReturnRows(pgxmock.NewRows([]string{"user_id"}).AddRow(&author))
+
+		rec := httptest.NewRecorder()
+		DeleteRating(rec, deleteRatingRequest("3", &models.User{ID: 8}))
+		if rec.Code != http.StatusForbidden {
+			t.Errorf("Expected 403, got %d", rec.Code)
+		}
+	})
+
+	t.Run("anonymous rating needs an admin", func(t *testing.T) {
+		mock := withMockRepositories(t)
+		mock.ExpectQuery(`SELECT user_id FROM ratings`).WithArgs(3).
+			WillReturnRows(pgxmock.NewRows([]string{"user_id"}).AddRow(nil))
+
+		rec := httptest.NewRecorder()
+		DeleteRating(rec, deleteRatingRequest("3", &models.User{ID: 8}))
+		if rec.Code != http.StatusForbidden {
+			t.Errorf("Expected 403, got %d", rec.Code)
+		}
+	})
+
+	t.Run("admin's key without the admin scope is forbidden", func(t *testing.T) {
+		mock := withMockRepositories(t)
+		mock.ExpectQuery(`SELECT user_id FROM ratings`).WithArgs(3).
+			WillReturnRows(pgxmock.NewRows([]string{"user_id"}).AddRow(&author))
+
+		req := deleteRatingRequest("3", &models.User{ID: 8, IsAdmin: true})
+		key := &models.APIKey{ID: 2, Scopes: []string{auth.ScopeRead, auth.ScopeWrite}}
+		req = req.WithContext(context.WithValue(req.Context(), models.APIKeyContextKey, key))
+
+		rec := httptest.NewRecorder()
+		DeleteRating(rec, req)
+		if rec.Code != http.StatusForbidden {
+			t.Errorf("Expected 403, got %d", rec.Code)
+		}
+	})
+
+	t.Run("missing rating", func(t *testing.T) {
+		mock := withMockRepositories(t)
+		mock.ExpectQuery(`SELECT user_id FROM ratings`).WithArgs(3).WillReturnError(pgx.ErrNoRows)
+
+		rec := httptest.NewRecorder()
+		DeleteRating(rec, deleteRatingRequest("3", &models.User{ID: author}))
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("Expected 404, got %d", rec.Code)
+		}
+	})
+}
+
+func TestCreateRating_UnknownRestaurant(t *testing.T) {
+	mock := withMockRepositories(t)
+	mock.ExpectQuery(`SELECT EXISTS`).WithArgs(42, repository.DefaultSpaceID).
+		WillReturnRows(pgxmock.NewRows([]string{"exists"}).AddRow(false))
+
+	rec := httptest.NewRecorder()
+	CreateRating(rec, httptest.NewRequest(http.MethodPost, "/api/ratings",
+		strings.NewReader(`{"restaurant_id": 42, "food_rating": 5, "service_rating": 4, "ambiance_rating": 3}`)))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404, got %d", rec.Code)
+	}
+}
+
+func TestCreateRating_ConstraintViolation(t *testing.T) {
+	mock := withMockRepositories(t)
+	mock.ExpectQuery(`SELECT EXISTS`).WithArgs(42, repository.DefaultSpaceID).
+		WillReturnRows(pgxmock.NewRows([]string{"exists"}).AddRow(true))
+	// The restaurant is deleted between the check and the insert
+	mock.ExpectQuery(`INSERT INTO ratings`).
+		WithArgs(42, 5, 4, 3, pgxmock.AnyArg(), pgxmock.AnyArg()).
+		WillReturnError(&pgconn.PgError{Code: "23503", ConstraintName: "ratings_restaurant_id_fkey"})
+
+	rec := httptest.NewRecorder()
+	CreateRating(rec, httptest.NewRequest(http.MethodPost, "/api/ratings",
+		strings.NewReader(`{"restaurant_id": 42, "food_rating": 5, "service_rating": 4, "ambiance_rating": 3}`)))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,21 @@
+package handlers
+
+import (
+	"github.com/nomdb/backend/internal/repository"
+)
+
+// Repositories used by the restaurant, rating and suggestion handlers, set by InitRepositories
+var (
+	repos          *repository.Repositories
+	restaurantRepo repository.RestaurantRepository
+	ratingRepo     repository.RatingRepository
+	suggestionRepo repository.SuggestionRepository
+)
+
+// InitRepositories sets the repositories the handlers read and write through
+func InitRepositories(r *repository.Repositories) {
+	repos = r
+	restaurantRepo = r.Restaurants
+	ratingRepo = r.Ratings
+	suggestionRepo = r.Suggestions
+}
@@ -0,0 +1,267 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/nomdb/backend/internal/cache"
+	apperrors "github.com/nomdb/backend/internal/errors"
+	"github.com/nomdb/backend/internal/logger"
+	"github.com/nomdb/backend/internal/models"
+	"github.com/nomdb/backend/internal/repository"
+)
+
+// restaurantOwner checks that the user of the request owns the restaurant, writing 401
+// or 403 otherwise
+func restaurantOwner(w http.ResponseWriter, r *http.Request, restaurantID int) (*models.User, bool) {
+	user, ok := GetUserFromContext(r)
+	if !ok {
+		apperrors.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return nil, false
+	}
+	ownerID, err := repos.Claims.OwnerID(r.Context(), restaurantID)
+	if err != nil {
+		apperrors.Internal(w, err)
+		return nil, false
+	}
+	if ownerID == nil || *ownerID != user.ID {
+		apperrors.Error(w, "Only the owner of the restaurant can do this", http.StatusForbidden)
+		return nil, false
+	}
+	return user, true
+}
+
+// @Summary Claim a restaurant
+// @Description Claim to own a restaurant. Once an admin approves the claim, the user can respond to the restaurant's ratings and update its phone, website and opening hours.
+// @Tags Restaurants
+// @Accept json
+// @Produce json
+// @Param id path int true "Restaurant ID"
+// @Param claim body models.CreateRestaurantClaimRequest false "Claim"
+// @Success 201 {object} models.RestaurantClaim "Pending claim"
+// @Failure 400 {object} errors.ErrorResponse "Invalid request"
+// @Failure 404 {object} errors.ErrorResponse "Restaurant not found"
+// @Failure 409 {object} errors.ErrorResponse "The restaurant has an owner or the user has a pending claim"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /restaurants/{id}/claims [post]
+func ClaimRestaurant(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		apperrors.Error(w, "Invalid restaurant ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.CreateRestaurantClaimRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		apperrors.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	user, ok := GetUserFromContext(r)
+	if !ok {
+		apperrors.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	ctx := r.Context()
+	exists, err := restaurantRepo.Exists(ctx, spaceID(r), id)
+	if err != nil || !exists {
+		apperrors.Error(w, "Restaurant not found", http.StatusNotFound)
+		return
+	}
+	ownerID, err := repos.Claims.OwnerID(ctx, id)
+	if err != nil {
+		apperrors.Internal(w, err)
+		return
+	}
+	if ownerID != nil {
+		apperrors.Error(w, "The restaurant already has an owner", http.StatusConflict)
+		return
+	}
+
+	claim, err := repos.Claims.Create(ctx, id, user.ID, req.Message)
+	if err != nil {
+		if errors.Is(err, repository.ErrClaimPending) {
+			apperrors.Error(w, "You already have a pending claim on this restaurant", http.StatusConflict)
+			return
+		}
+		apperrors.Internal(w, err)
+		return
+	}
+	logger.Info("%s claimed restaurant %d", user.Username, id)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(claim)
+}
+
+// @Summary List my restaurant claims
+// @Description List the restaurant claims of the current user with their status, newest first.
+// @Tags Auth
+// @Produce json
+// @Success 200 {array} models.RestaurantClaim
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /auth/me/restaurant-claims [get]
+func GetMyRestaurantClaims(w http.ResponseWriter, r *http.Request) {
+	user, ok := GetUserFromContext(r)
+	if !ok {
+		apperrors.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	claims, err := repos.Claims.ListByUser(r.Context(), user.ID)
+	if err != nil {
+		apperrors.Internal(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(claims)
+}
+
+// @Summary List restaurant claims
+// @Description List claims of users to own restaurants, oldest first. Admin only.
+// @Tags Restaurants
+// @Produce json
+// @Param status query string false "Filter by status (pending, approved, rejected)"
+// @Success 200 {array} models.RestaurantClaim "Restaurant claims"
+// @Failure 400 {object} errors.ErrorResponse "Invalid status"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /admin/restaurant-claims [get]
+func GetRestaurantClaims(w http.ResponseWriter, r *http.Request) {
+	status := r.URL.Query().Get("status")
+	switch status {
+	case "", repository.ClaimPending, repository.ClaimApproved, repository.ClaimRejected:
+	default:
+		apperrors.Error(w, "Invalid status. Must be one of: pending, approved, rejected", http.StatusBadRequest)
+		return
+	}
+
+	claims, err := repos.Claims.List(r.Context(), status)
+	if err != nil {
+		apperrors.Internal(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(claims)
+}
+
+// @Summary Review a restaurant claim
+// @Description Approve a pending claim, making its user the owner of the restaurant and rejecting the other pending claims on it, or reject it. Admin only.
+// @Tags Restaurants
+// @Accept json
+// @Produce json
+// @Param id path int true "Claim ID"
+// @Param decision body models.ReviewRestaurantClaimRequest true "Review decision"
+// @Success 200 {object} models.RestaurantClaim "Reviewed claim"
+// @Failure 400 {object} errors.ErrorResponse "Invalid request"
+// @Failure 404 {object} errors.ErrorResponse "No pending claim with this ID"
+// @Failure 409 {object} errors.ErrorResponse "The restaurant already has an owner"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /admin/restaurant-claims/{id} [post]
+func ReviewRestaurantClaim(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		apperrors.Error(w, "Invalid claim ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.ReviewRestaurantClaimRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apperrors.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.Action != "approve" && req.Action != "reject" {
+		apperrors.Error(w, "Invalid action. Must be one of: approve, reject", http.StatusBadRequest)
+		return
+	}
+
+	user, ok := GetUserFromContext(r)
+	if !ok {
+		apperrors.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	ctx := r.Context()
+	claim, err := repos.Claims.Review(ctx, id, req.Action == "approve", &user.ID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			apperrors.Error(w, "Pending claim not found", http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, repository.ErrAlreadyOwned) {
+			apperrors.Error(w, "The restaurant already has an owner", http.StatusConflict)
+			return
+		}
+		apperrors.Internal(w, err)
+		return
+	}
+
+	logger.Info("✅ Claim %d of %s on restaurant %d %s by %s", id, claim.Username, claim.RestaurantID, claim.Status, user.Username)
+	recordAdminAction(ctx, r, req.Action+"_restaurant_claim", "restaurant", claim.RestaurantID, map[string]any{
+		"claim_id": id,
+		"user_id":  claim.UserID,
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(claim)
+}
+
+// @Summary Update business details
+// @Description Update the phone, website and opening hours of a restaurant. Fields left out are kept; an empty phone, website or list of opening hours clears it. Owner of the restaurant only.
+// @Tags Restaurants
+// @Accept json
+// @Produce json
+// @Param id path int true "Restaurant ID"
+// @Param details body models.UpdateRestaurantBusinessRequest true "Business details"
+// @Success 200 {object} models.Restaurant "Updated restaurant"
+// @Failure 400 {object} errors.ErrorResponse "Invalid request"
+// @Failure 403 {object} errors.ErrorResponse "Not the owner of the restaurant"
+// @Failure 404 {object} errors.ErrorResponse "Restaurant not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /restaurants/{id}/business [patch]
+func UpdateRestaurantBusiness(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		apperrors.Error(w, "Invalid restaurant ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.UpdateRestaurantBusinessRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apperrors.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if _, ok := restaurantOwner(w, r, id); !ok {
+		return
+	}
+
+	ctx := r.Context()
+	rest, err := restaurantRepo.UpdateBusiness(ctx, id, req)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			apperrors.Error(w, "Restaurant not found", http.StatusNotFound)
+			return
+		}
+		apperrors.Internal(w, err)
+		return
+	}
+
+	rest.FoodTypes, _ = restaurantRepo.FoodTypes(ctx, rest.ID)
+	cache.Invalidate(ctx, cache.KeyRestaurants)
+	publishChange(ctx, eventRestaurantUpdated, spaceID(r), rest.ID, rest.ID)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rest)
+}
@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/jackc/pgx/v5"
+	"github.com/nomdb/backend/internal/models"
+	"github.com/nomdb/backend/internal/repository"
+	"github.com/pashagolub/pgxmock/v4"
+)
+
+func ownerRequest(method, target, body string, vars map[string]string, user *models.User) *http.Request {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req = mux.SetURLVars(req, vars)
+	return req.WithContext(context.WithValue(req.Context(), models.UserContextKey, user))
+}
+
+func TestClaimRestaurant_AlreadyOwned(t *testing.T) {
+	mock := withMockRepositories(t)
+	mock.ExpectQuery(`SELECT EXISTS`).WithArgs(3, repository.DefaultSpaceID).
+		WillReturnRows(pgxmock.NewRows([]string{"exists"}).AddRow(true))
+	mock.ExpectQuery(`SELECT user_id FROM restaurant_claims`).WithArgs(3).
+		WillReturnRows(pgxmock.NewRows([]string{"user_id"}).AddRow(8))
+
+	rec := httptest.NewRecorder()
+	ClaimRestaurant(rec, ownerRequest(http.MethodPost, "/api/restaurants/3/claims", `{"message":"Mine"}`,
+		map[string]string{"id": "3"}, &models.User{ID: 7}))
+	if rec.Code != http.StatusConflict {
+		t.Errorf("Expected 409, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestReviewRestaurantClaim_InvalidAction(t *testing.T) {
+	rec := httptest.NewRecorder()
+	ReviewRestaurantClaim(rec, ownerRequest(http.MethodPost, "/api/admin/restaurant-claims/5", `{"action":"accept"}`,
+		map[string]string{"id": "5"}, &models.User{ID: 1, IsAdmin: true}))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400, got %d", rec.Code)
+	}
+}
+
+func TestSetRatingResponse(t *testing.T) {
+	owner := 7
+	response := "Thanks for the visit!"
+
+	t.Run("owner responds", func(t *testing.T) {
+		mock := withMockRepositories(t)
+		now := time.Now()
+		mock.ExpectQuery(`SELECT restaurant_id FROM ratings`).WithArgs(4).
+			WillReturnRows(pgxmock.NewRows([]string{"restaurant_id"}).AddRow(3))
+		mock.ExpectQuery(`SELECT user_id FROM restaurant_claims`).WithArgs(3).
+			WillReturnRows(pgxmock.NewRows([]string{"user_id"}).AddRow(owner))
+		mock.ExpectQuery(`UPDATE ratings SET owner_response`).WithArgs(4, &response).
+			WillReturnRows(pgxmock.NewRows([]string{"id", "restaurant_id", "food_rating", "service_rating", "ambiance_rating",
+				"comment", "user_id", "created_at", "owner_response", "owner_response_at"}).
+				AddRow(4, 3, 5, 4, 3, nil, nil, now, &response, &now))
+
+		rec := httptest.NewRecorder()
+		SetRatingResponse(rec, ownerRequest(http.MethodPut, "/api/ratings/4/response", `{"response":"  Thanks for the visit!  "}`,
+			map[string]string{"id": "4"}, &models.User{ID: owner}))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		var rt models.Rating
+		json.NewDecoder(rec.Body).Decode(&rt)
+		if rt.OwnerResponse == nil || rt.OwnerResponse.Response != response {
+			t.Errorf("Expected the response embedded in the rating, got %+v", rt.OwnerResponse)
+		}
+	})
+
+	t.Run("other user is forbidden", func(t *testing.T) {
+		mock := withMockRepositories(t)
+		mock.ExpectQuery(`SELECT restaurant_id FROM ratings`).WithArgs(4).
+			WillReturnRows(pgxmock.NewRows([]string{"restaurant_id"}).AddRow(3))
+		mock.ExpectQuery(`SELECT user_id FROM restaurant_claims`).WithArgs(3).WillReturnError(pgx.ErrNoRows)
+
+		rec := httptest.NewRecorder()
+		SetRatingResponse(rec, ownerRequest(http.MethodPut, "/api/ratings/4/response", `{"response":"Thanks"}`,
+			map[string]string{"id": "4"}, &models.User{ID: 8}))
+		if rec.Code != http.StatusForbidden {
+			t.Errorf("Expected 403, got %d", rec.Code)
+		}
+	})
+
+	t.Run("empty response", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		SetRatingResponse(rec, ownerRequest(http.MethodPut, "/api/ratings/4/response", `{"response":"  "}`,
+			map[string]string{"id": "4"}, &models.User{ID: owner}))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected 400, got %d", rec.Code)
+		}
+	})
+}
+
+func TestUpdateRestaurantBusiness_NotOwner(t *testing.T) {
+	mock := withMockRepositories(t)
+	mock.ExpectQuery(`SELECT user_id FROM restaurant_claims`).WithArgs(3).
+		WillReturnRows(pgxmock.NewRows([]string{"user_id"}).AddRow(8))
+
+	rec := httptest.NewRecorder()
+	UpdateRestaurantBusiness(rec, ownerRequest(http.MethodPatch, "/api/restaurants/3/business", `{"phone":"+49 30 123"}`,
+		map[string]string{"id": "3"}, &models.User{ID: 7}))
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("Expected 403, got %d", rec.Code)
+	}
+}
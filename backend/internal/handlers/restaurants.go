@@ -3,62 +3,32 @@ package handlers
 import (
 	"context"
 	"encoding/json"
-	"fmt"
+	"errors"
 	"net/http"
 	"strconv"
 	"strings"
 
 	"github.com/gorilla/mux"
 	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/nomdb/backend/internal/cache"
 	"github.com/nomdb/backend/internal/database"
+	apperrors "github.com/nomdb/backend/internal/errors"
 	"github.com/nomdb/backend/internal/logger"
 	"github.com/nomdb/backend/internal/models"
+	"github.com/nomdb/backend/internal/repository"
 )
 
-func getFoodTypesForRestaurant(ctx context.Context, restaurantID int) ([]models.FoodType, error) {
-	rows, err := database.GetPool().Query(ctx,
-		`SELECT ft.id, ft.name, ft.created_at, ft.updated_at
-		FROM food_types ft
-		JOIN restaurant_food_types rft ON ft.id = rft.food_type_id
-		WHERE rft.restaurant_id = $1
-		ORDER BY ft.name`, restaurantID)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	var foodTypes []models.FoodType
-	for rows.Next() {
-		var ft models.FoodType
-		if err := rows.Scan(&ft.ID, &ft.Name, &ft.CreatedAt, &ft.UpdatedAt); err != nil {
-			return nil, err
-		}
-		foodTypes = append(foodTypes, ft)
-	}
-	return foodTypes, nil
-}
-
 func getFoodTypesForRestaurantsBatch(ctx context.Context, restaurantIDs []int) (map[int][]models.FoodType, error) {
 	if len(restaurantIDs) == 0 {
 		return make(map[int][]models.FoodType), nil
 	}
 
-	// Build dynamic query with placeholders
-	placeholders := make([]string, len(restaurantIDs))
-	args := make([]interface{}, len(restaurantIDs))
-	for i, id := range restaurantIDs {
-		placeholders[i] = fmt.Sprintf("$%d", i+1)
-		args[i] = id
-	}
-
-	query := fmt.Sprintf(`
+	rows, err := database.GetPool().Query(ctx, `
 		SELECT rft.restaurant_id, ft.id, ft.name, ft.created_at, ft.updated_at
 		FROM food_types ft
 		JOIN restaurant_food_types rft ON ft.id = rft.food_type_id
-		WHERE rft.restaurant_id IN (%s)
-		ORDER BY rft.restaurant_id, ft.name`, strings.Join(placeholders, ","))
-
-	rows, err := database.GetPool().Query(ctx, query, args...)
+		WHERE rft.restaurant_id = ANY($1)
+		ORDER BY rft.restaurant_id, ft.name`, restaurantIDs)
 	if err != nil {
 		return nil, err
 	}
@@ -81,22 +51,12 @@ func getFoodTypesForSuggestionsBatch(ctx context.Context, suggestionIDs []int) (
 		return make(map[int][]models.FoodType), nil
 	}
 
-	// Build dynamic query with placeholders
-	placeholders := make([]string, len(suggestionIDs))
-	args := make([]interface{}, len(suggestionIDs))
-	for i, id := range suggestionIDs {
-		placeholders[i] = fmt.Sprintf("$%d", i+1)
-		args[i] = id
-	}
-
-	query := fmt.Sprintf(`
+	rows, err := database.GetPool().Query(ctx, `
 		SELECT sft.suggestion_id, ft.id, ft.name, ft.created_at, ft.updated_at
 		FROM food_types ft
 		JOIN suggestion_food_types sft ON ft.id = sft.food_type_id
-		WHERE sft.suggestion_id IN (%s)
-		ORDER BY sft.suggestion_id, ft.name`, strings.Join(placeholders, ","))
-
-	rows, err := database.GetPool().Query(ctx, query, args...)
+		WHERE sft.suggestion_id = ANY($1)
+		ORDER BY sft.suggestion_id, ft.name`, suggestionIDs)
 	if err != nil {
 		return nil, err
 	}
@@ -114,52 +74,9 @@ func getFoodTypesForSuggestionsBatch(ctx context.Context, suggestionIDs []int) (
 	return result, nil
 }
 
-func getFoodTypesForSuggestion(ctx context.Context, suggestionID int) ([]models.FoodType, error) {
-	rows, err := database.GetPool().Query(ctx,
-		`SELECT ft.id, ft.name, ft.created_at, ft.updated_at
-		FROM food_types ft
-		JOIN suggestion_food_types sft ON ft.id = sft.food_type_id
-		WHERE sft.suggestion_id = $1
-		ORDER BY ft.name`, suggestionID)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	var foodTypes []models.FoodType
-	for rows.Next() {
-		var ft models.FoodType
-		if err := rows.Scan(&ft.ID, &ft.Name, &ft.CreatedAt, &ft.UpdatedAt); err != nil {
-			return nil, err
-		}
-		foodTypes = append(foodTypes, ft)
-	}
-	return foodTypes, nil
-}
-
-func setFoodTypesForRestaurant(ctx context.Context, restaurantID int, foodTypeIDs []int) error {
-	// Delete existing food types
-	_, err := database.GetPool().Exec(ctx,
-		"DELETE FROM restaurant_food_types WHERE restaurant_id = $1", restaurantID)
-	if err != nil {
-		return err
-	}
-
-	// Insert new food types
-	for _, ftID := range foodTypeIDs {
-		_, err := database.GetPool().Exec(ctx,
-			"INSERT INTO restaurant_food_types (restaurant_id, food_type_id) VALUES ($1, $2)",
-			restaurantID, ftID)
-		if err != nil {
-			return err
-		}
-	}
-	return nil
-}
-
 // GetRestaurants godoc
 // @Summary List all restaurants
-// @Description Get a list of all restaurants with optional filtering by category, food types, and location
+// @Description Get a list of all restaurants with optional filtering by category, food types, and location. Cacheable for HTTP_CACHE_MAX_AGE; revalidate with If-None-Match or If-Modified-Since to get 304 Not Modified while unchanged.
 // @Tags Restaurants
 // @Accept json
 // @Produce json
@@ -169,291 +86,52 @@ func setFoodTypesForRestaurant(ctx context.Context, restaurantID int, foodTypeID
 // @Param lng query number false "Longitude for distance filtering"
 // @Param radius query number false "Radius in kilometers for distance filtering"
 // @Success 200 {array} models.Restaurant "List of restaurants"
-// @Failure 500 {object} map[string]string "Internal server error"
+// @Success 304 "Not modified"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
 // @Router /restaurants [get]
 func GetRestaurants(w http.ResponseWriter, r *http.Request) {
-	ctx := context.Background()
+	ctx := r.Context()
 
 	// Parse query parameters for filtering
 	queryParams := r.URL.Query()
-	categoryID := queryParams.Get("category_id")
-	foodTypeIDs := queryParams.Get("food_type_ids") // comma-separated
-	lat := queryParams.Get("lat")
-	lng := queryParams.Get("lng")
-	radius := queryParams.Get("radius") // in kilometers
-
-	// Always include suggestions in the restaurant list
-	includeSuggestions := true
-
-	// Build dynamic query with filters using UNION to include both restaurants and suggestions
-	var args []interface{}
-
-	// Build restaurant query
-	var restaurantConditions []string
-	var restaurantArgs []interface{}
-	restaurantArgIndex := 1
-
-	if categoryID != "" {
-		if catID, err := strconv.Atoi(categoryID); err == nil {
-			restaurantConditions = append(restaurantConditions, fmt.Sprintf("r.category_id = $%d", restaurantArgIndex))
-			restaurantArgs = append(restaurantArgs, catID)
-			restaurantArgIndex++
-		}
-	}
-
-	if foodTypeIDs != "" {
-		ftIDs := strings.Split(foodTypeIDs, ",")
-		var validIDs []int
-		for _, idStr := range ftIDs {
-			if id, err := strconv.Atoi(strings.TrimSpace(idStr)); err == nil {
-				validIDs = append(validIDs, id)
-			}
-		}
-		if len(validIDs) > 0 {
-			placeholders := make([]string, len(validIDs))
-			for i, id := range validIDs {
-				placeholders[i] = fmt.Sprintf("$%d", restaurantArgIndex)
-				restaurantArgs = append(restaurantArgs, id)
-				restaurantArgIndex++
-			}
-			restaurantConditions = append(restaurantConditions, fmt.Sprintf(`r.id IN (
-				SELECT DISTINCT restaurant_id FROM restaurant_food_types
-				WHERE food_type_id IN (%s)
-			)`, strings.Join(placeholders, ",")))
-		}
-	}
-
-	// Location/radius filter
-	var distanceSelect string
-	var distanceOrder string
-	var hasDistance bool
-
-	if lat != "" && lng != "" && radius != "" {
-		latVal, latErr := strconv.ParseFloat(lat, 64)
-		lngVal, lngErr := strconv.ParseFloat(lng, 64)
-		radiusVal, radErr := strconv.ParseFloat(radius, 64)
-
-		if latErr == nil && lngErr == nil && radErr == nil {
-			hasDistance = true
-			distanceSelect = fmt.Sprintf(`,
-				(6371 * acos(
-					cos(radians($%d)) * cos(radians(r.latitude)) *
-					cos(radians(r.longitude) - radians($%d)) +
-					sin(radians($%d)) * sin(radians(r.latitude))
-				)) as distance`, restaurantArgIndex, restaurantArgIndex+1, restaurantArgIndex+2)
-			restaurantArgs = append(restaurantArgs, latVal, lngVal, latVal)
-
-			restaurantConditions = append(restaurantConditions, fmt.Sprintf(`r.latitude IS NOT NULL AND r.longitude IS NOT NULL AND
-				(6371 * acos(
-					cos(radians($%d)) * cos(radians(r.latitude)) *
-					cos(radians(r.longitude) - radians($%d)) +
-					sin(radians($%d)) * sin(radians(r.latitude))
-				)) <= $%d`, restaurantArgIndex+3, restaurantArgIndex+4, restaurantArgIndex+5, restaurantArgIndex+6))
-			restaurantArgs = append(restaurantArgs, latVal, lngVal, latVal, radiusVal)
-			restaurantArgIndex += 7
-
-			distanceOrder = "distance ASC,"
-		}
-	}
-
-	restaurantWhereClause := ""
-	if len(restaurantConditions) > 0 {
-		restaurantWhereClause = "WHERE " + strings.Join(restaurantConditions, " AND ")
-	}
-
-	restaurantQuery := fmt.Sprintf(`
-		SELECT
-			r.id, r.name, r.description, r.address, r.phone, r.website, r.latitude, r.longitude,
-			r.google_place_id, r.category_id, r.created_at, r.updated_at,
-			c.id, c.name,
-			COALESCE(AVG(rt.food_rating), 0) as avg_food,
-			COALESCE(AVG(rt.service_rating), 0) as avg_service,
-			COALESCE(AVG(rt.ambiance_rating), 0) as avg_ambiance,
-			COUNT(rt.id) as rating_count,
-			false as is_suggestion,
-			NULL::integer as suggestion_id,
-			NULL::text as status
-			%s
-		FROM restaurants r
-		LEFT JOIN categories c ON r.category_id = c.id
-		LEFT JOIN ratings rt ON r.id = rt.restaurant_id
-		%s
-		GROUP BY r.id, c.id
-	`, distanceSelect, restaurantWhereClause)
-
-	args = restaurantArgs
-
-	// Build suggestion query if requested
-	var finalQuery string
-	if includeSuggestions {
-		// Build suggestion conditions similar to restaurant conditions
-		var suggestionConditions []string
-		suggestionArgIndex := len(args) + 1
-
-		if categoryID != "" {
-			if catID, err := strconv.Atoi(categoryID); err == nil {
-				suggestionConditions = append(suggestionConditions, fmt.Sprintf("s.suggested_category_id = $%d", suggestionArgIndex))
-				args = append(args, catID)
-				suggestionArgIndex++
-			}
-		}
-
-		if foodTypeIDs != "" {
-			ftIDs := strings.Split(foodTypeIDs, ",")
-			var validIDs []int
-			for _, idStr := range ftIDs {
-				if id, err := strconv.Atoi(strings.TrimSpace(idStr)); err == nil {
-					validIDs = append(validIDs, id)
-				}
-			}
-			if len(validIDs) > 0 {
-				placeholders := make([]string, len(validIDs))
-				for i, id := range validIDs {
-					placeholders[i] = fmt.Sprintf("$%d", suggestionArgIndex)
-					args = append(args, id)
-					suggestionArgIndex++
-				}
-				suggestionConditions = append(suggestionConditions, fmt.Sprintf(`s.id IN (
-					SELECT DISTINCT suggestion_id FROM suggestion_food_types
-					WHERE food_type_id IN (%s)
-				)`, strings.Join(placeholders, ",")))
-			}
-		}
-
-		// Add status filter to only show pending suggestions
-		suggestionConditions = append(suggestionConditions, "s.status = 'pending'")
-
-		var suggestionDistanceSelect string
-		if hasDistance {
-			latVal, _ := strconv.ParseFloat(lat, 64)
-			lngVal, _ := strconv.ParseFloat(lng, 64)
-			radiusVal, _ := strconv.ParseFloat(radius, 64)
-
-			suggestionDistanceSelect = fmt.Sprintf(`,
-				(6371 * acos(
-					cos(radians($%d)) * cos(radians(s.latitude)) *
-					cos(radians(s.longitude) - radians($%d)) +
-					sin(radians($%d)) * sin(radians(s.latitude))
-				)) as distance`, suggestionArgIndex, suggestionArgIndex+1, suggestionArgIndex+2)
-			args = append(args, latVal, lngVal, latVal)
-
-			suggestionConditions = append(suggestionConditions, fmt.Sprintf(`s.latitude IS NOT NULL AND s.longitude IS NOT NULL AND
-				(6371 * acos(
-					cos(radians($%d)) * cos(radians(s.latitude)) *
-					cos(radians(s.longitude) - radians($%d)) +
-					sin(radians($%d)) * sin(radians(s.latitude))
-				)) <= $%d`, suggestionArgIndex+3, suggestionArgIndex+4, suggestionArgIndex+5, suggestionArgIndex+6))
-			args = append(args, latVal, lngVal, latVal, radiusVal)
-		}
-
-		suggestionWhereClause := ""
-		if len(suggestionConditions) > 0 {
-			suggestionWhereClause = "WHERE " + strings.Join(suggestionConditions, " AND ")
+	filter := parseRestaurantFilter(queryParams)
+	filter.SpaceID = spaceID(r)
+
+	lat, latErr := strconv.ParseFloat(queryParams.Get("lat"), 64)
+	lng, lngErr := strconv.ParseFloat(queryParams.Get("lng"), 64)
+	radius, radErr := strconv.ParseFloat(queryParams.Get("radius"), 64) // in kilometers
+	if latErr == nil && lngErr == nil && radErr == nil {
+		filter.Near = &repository.GeoRadius{Lat: lat, Lng: lng, RadiusKm: radius}
+	}
+
+	// Only the unfiltered list of the default space, the default view, is cached
+	cacheable := filter.SpaceID == repository.DefaultSpaceID &&
+		filter.CategoryID == nil && len(filter.FoodTypeIDs) == 0 && filter.Near == nil
+	if cacheable {
+		var cached []models.Restaurant
+		if cache.GetJSON(ctx, cache.KeyRestaurants, &cached) {
+			localizeRestaurants(ctx, cached)
+			writeCacheableJSON(w, r, cached, cache.KeyRestaurants)
+			return
 		}
-
-		suggestionQuery := fmt.Sprintf(`
-			SELECT
-				s.id, s.name, NULL::text as description, s.address, s.phone, s.website, s.latitude, s.longitude,
-				s.google_place_id, s.suggested_category_id as category_id, s.created_at, s.updated_at,
-				c.id, c.name,
-				0.0 as avg_food,
-				0.0 as avg_service,
-				0.0 as avg_ambiance,
-				0 as rating_count,
-				true as is_suggestion,
-				s.id as suggestion_id,
-				s.status
-				%s
-			FROM restaurant_suggestions s
-			LEFT JOIN categories c ON s.suggested_category_id = c.id
-			%s
-		`, suggestionDistanceSelect, suggestionWhereClause)
-
-		finalQuery = fmt.Sprintf(`
-			SELECT * FROM (
-				%s
-				UNION ALL
-				%s
-			) combined
-			ORDER BY %s created_at DESC
-		`, restaurantQuery, suggestionQuery, distanceOrder)
-	} else {
-		finalQuery = fmt.Sprintf(`
-			%s
-			ORDER BY %s r.created_at DESC
-		`, restaurantQuery, distanceOrder)
 	}
 
-	rows, err := database.GetPool().Query(ctx, finalQuery, args...)
+	// Suggestions are always included in the restaurant list
+	restaurants, err := restaurantRepo.ListWithSuggestions(ctx, filter)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		apperrors.Internal(w, err)
 		return
 	}
-	defer rows.Close()
 
-	restaurants := []models.Restaurant{}
 	restaurantIDs := []int{}
 	suggestionIDs := []int{}
-
-	for rows.Next() {
-		var rest models.Restaurant
-		var catID *int
-		var catName *string
-		var avgFood, avgService, avgAmbiance float64
-		var ratingCount int
-		var distance *float64
-
-		var err error
-		if hasDistance {
-			err = rows.Scan(
-				&rest.ID, &rest.Name, &rest.Description, &rest.Address, &rest.Phone, &rest.Website, &rest.Latitude, &rest.Longitude,
-				&rest.GooglePlaceID, &rest.CategoryID, &rest.CreatedAt, &rest.UpdatedAt,
-				&catID, &catName,
-				&avgFood, &avgService, &avgAmbiance, &ratingCount,
-				&rest.IsSuggestion, &rest.SuggestionID, &rest.Status,
-				&distance,
-			)
-		} else {
-			err = rows.Scan(
-				&rest.ID, &rest.Name, &rest.Description, &rest.Address, &rest.Phone, &rest.Website, &rest.Latitude, &rest.Longitude,
-				&rest.GooglePlaceID, &rest.CategoryID, &rest.CreatedAt, &rest.UpdatedAt,
-				&catID, &catName,
-				&avgFood, &avgService, &avgAmbiance, &ratingCount,
-				&rest.IsSuggestion, &rest.SuggestionID, &rest.Status,
-			)
-		}
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-
-		if distance != nil {
-			rest.Distance = distance
-		}
-
-		if catID != nil && catName != nil {
-			rest.Category = &models.Category{ID: *catID, Name: *catName}
-		}
-
-		if ratingCount > 0 {
-			overall := (avgFood + avgService + avgAmbiance) / 3
-			rest.AvgRating = &models.AvgRating{
-				Food:     avgFood,
-				Service:  avgService,
-				Ambiance: avgAmbiance,
-				Overall:  overall,
-				Count:    ratingCount,
-			}
-		}
-
+	for _, rest := range restaurants {
 		// Collect IDs for batch food type lookup
 		if rest.IsSuggestion {
 			suggestionIDs = append(suggestionIDs, rest.ID)
 		} else {
 			restaurantIDs = append(restaurantIDs, rest.ID)
 		}
-
-		restaurants = append(restaurants, rest)
 	}
 
 	// Batch fetch food types for all restaurants
@@ -461,7 +139,7 @@ func GetRestaurants(w http.ResponseWriter, r *http.Request) {
 	if len(restaurantIDs) > 0 {
 		foodTypeMap, err := getFoodTypesForRestaurantsBatch(ctx, restaurantIDs)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			apperrors.Internal(w, err)
 			return
 		}
 		restaurantFoodTypes = foodTypeMap
@@ -472,23 +150,39 @@ func GetRestaurants(w http.ResponseWriter, r *http.Request) {
 	if len(suggestionIDs) > 0 {
 		foodTypeMap, err := getFoodTypesForSuggestionsBatch(ctx, suggestionIDs)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			apperrors.Internal(w, err)
 			return
 		}
 		suggestionFoodTypes = foodTypeMap
 	}
 
-	// Assign food types to restaurants
+	// Batch fetch cover photos (non-fatal, cards fall back to no image)
+	coverPhotos := make(map[int]*models.MenuPhoto)
+	if len(restaurantIDs) > 0 {
+		covers, err := getCoverPhotosForRestaurantsBatch(ctx, restaurantIDs)
+		if err != nil {
+			logger.Warn("Failed to fetch cover photos: %v", err)
+		} else {
+			coverPhotos = covers
+		}
+	}
+
+	// Assign food types and cover photos to restaurants
 	for i := range restaurants {
 		if restaurants[i].IsSuggestion {
 			restaurants[i].FoodTypes = suggestionFoodTypes[restaurants[i].ID]
 		} else {
 			restaurants[i].FoodTypes = restaurantFoodTypes[restaurants[i].ID]
+			restaurants[i].CoverPhoto = coverPhotos[restaurants[i].ID]
 		}
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(restaurants)
+	if cacheable {
+		cache.SetJSON(ctx, cache.KeyRestaurants, restaurants)
+	}
+	localizeRestaurants(ctx, restaurants)
+
+	writeCacheableJSON(w, r, restaurants, cache.KeyRestaurants)
 }
 
 // GetRestaurant godoc
@@ -499,74 +193,38 @@ func GetRestaurants(w http.ResponseWriter, r *http.Request) {
 // @Produce json
 // @Param id path int true "Restaurant ID"
 // @Success 200 {object} models.Restaurant "Restaurant details"
-// @Failure 400 {object} map[string]string "Invalid restaurant ID"
-// @Failure 404 {object} map[string]string "Restaurant not found"
-// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 400 {object} errors.ErrorResponse "Invalid restaurant ID"
+// @Failure 404 {object} errors.ErrorResponse "Restaurant not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
 // @Router /restaurants/{id} [get]
 func GetRestaurant(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id, err := strconv.Atoi(vars["id"])
 	if err != nil {
-		http.Error(w, "Invalid restaurant ID", http.StatusBadRequest)
+		apperrors.Error(w, "Invalid restaurant ID", http.StatusBadRequest)
 		return
 	}
 
-	ctx := context.Background()
-	query := `
-		SELECT
-			r.id, r.name, r.description, r.address, r.phone, r.website, r.latitude, r.longitude,
-			r.google_place_id, r.category_id, r.created_at, r.updated_at,
-			c.id, c.name,
-			COALESCE(AVG(rt.food_rating), 0) as avg_food,
-			COALESCE(AVG(rt.service_rating), 0) as avg_service,
-			COALESCE(AVG(rt.ambiance_rating), 0) as avg_ambiance,
-			COUNT(rt.id) as rating_count
-		FROM restaurants r
-		LEFT JOIN categories c ON r.category_id = c.id
-		LEFT JOIN ratings rt ON r.id = rt.restaurant_id
-		WHERE r.id = $1
-		GROUP BY r.id, c.id
-	`
-
-	var rest models.Restaurant
-	var catID *int
-	var catName *string
-	var avgFood, avgService, avgAmbiance float64
-	var ratingCount int
-
-	err = database.GetPool().QueryRow(ctx, query, id).Scan(
-		&rest.ID, &rest.Name, &rest.Description, &rest.Address, &rest.Phone, &rest.Website, &rest.Latitude, &rest.Longitude,
-		&rest.GooglePlaceID, &rest.CategoryID, &rest.CreatedAt, &rest.UpdatedAt,
-		&catID, &catName,
-		&avgFood, &avgService, &avgAmbiance, &ratingCount,
-	)
+	ctx := r.Context()
+	rest, err := restaurantRepo.GetByID(ctx, id)
 	if err != nil {
-		http.Error(w, "Restaurant not found", http.StatusNotFound)
+		if errors.Is(err, repository.ErrNotFound) {
+			apperrors.Error(w, "Restaurant not found", http.StatusNotFound)
+			return
+		}
+		apperrors.Internal(w, err)
 		return
 	}
 
-	if catID != nil && catName != nil {
-		rest.Category = &models.Category{ID: *catID, Name: *catName}
-	}
-
-	// Get food types
-	foodTypes, err := getFoodTypesForRestaurant(ctx, rest.ID)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
+	if covers, err := getCoverPhotosForRestaurantsBatch(ctx, []int{rest.ID}); err != nil {
+		logger.Warn("Failed to fetch cover photo for restaurant %d: %v", rest.ID, err)
+	} else {
+		rest.CoverPhoto = covers[rest.ID]
 	}
-	rest.FoodTypes = foodTypes
-
-	if ratingCount > 0 {
-		overall := (avgFood + avgService + avgAmbiance) / 3
-		rest.AvgRating = &models.AvgRating{
-			Food:     avgFood,
-			Service:  avgService,
-			Ambiance: avgAmbiance,
-			Overall:  overall,
-			Count:    ratingCount,
-		}
+	if rest.ExternalSources, err = repos.ExternalSources.ForRestaurant(ctx, rest.ID); err != nil {
+		logger.Warn("Failed to fetch external sources of restaurant %d: %v", rest.ID, err)
 	}
+	translate(translatedNames(ctx), rest.Category, rest.FoodTypes)
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(rest)
@@ -580,63 +238,66 @@ func GetRestaurant(w http.ResponseWriter, r *http.Request) {
 // @Produce json
 // @Param restaurant body models.CreateRestaurantRequest true "Restaurant creation request"
 // @Success 201 {object} models.Restaurant "Created restaurant"
-// @Failure 400 {object} map[string]string "Invalid request body"
-// @Failure 409 {object} map[string]string "Restaurant already exists"
-// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 400 {object} errors.ErrorResponse "Invalid request body"
+// @Failure 409 {object} errors.ErrorResponse "Restaurant already exists"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
 // @Router /restaurants [post]
 func CreateRestaurant(w http.ResponseWriter, r *http.Request) {
 	var req models.CreateRestaurantRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		apperrors.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
 
 	if req.Name == "" {
-		http.Error(w, "Name is required", http.StatusBadRequest)
+		apperrors.Error(w, "Name is required", http.StatusBadRequest)
 		return
 	}
+	req.SpaceID = spaceID(r)
 
-	ctx := context.Background()
+	ctx := r.Context()
 
-	var rest models.Restaurant
-	err := database.GetPool().QueryRow(ctx,
-		`INSERT INTO restaurants (name, description, address, phone, website, latitude, longitude, google_place_id, category_id)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
-		RETURNING id, name, description, address, phone, website, latitude, longitude, google_place_id, category_id, created_at, updated_at`,
-		req.Name, req.Description, req.Address, req.Phone, req.Website, req.Latitude, req.Longitude, req.GooglePlaceID, req.CategoryID,
-	).Scan(
-		&rest.ID, &rest.Name, &rest.Description, &rest.Address, &rest.Phone, &rest.Website, &rest.Latitude, &rest.Longitude,
-		&rest.GooglePlaceID, &rest.CategoryID, &rest.CreatedAt, &rest.UpdatedAt,
-	)
+	// The restaurant and its food types are created together or not at all
+	var rest *models.Restaurant
+	err := repos.WithTx(ctx, func(tx *repository.Repositories) error {
+		var err error
+		if rest, err = tx.Restaurants.Create(ctx, req); err != nil {
+			return err
+		}
+		if len(req.FoodTypeIDs) > 0 {
+			return tx.Restaurants.SetFoodTypes(ctx, rest.ID, req.FoodTypeIDs)
+		}
+		return nil
+	})
 	if err != nil {
 		// Check if it's a unique constraint violation
 		if pgErr, ok := err.(*pgconn.PgError); ok {
 			if pgErr.Code == "23505" { // unique_violation
 				logger.Warn("Duplicate restaurant creation attempt: %s", req.Name)
 				if strings.Contains(pgErr.ConstraintName, "google_place_id") {
-					http.Error(w, "A restaurant with this Google Place ID already exists", http.StatusConflict)
+					apperrors.Error(w, "A restaurant with this Google Place ID already exists", http.StatusConflict)
 				} else if strings.Contains(pgErr.ConstraintName, "name_address") {
-					http.Error(w, "A restaurant with this name and address already exists", http.StatusConflict)
+					apperrors.Error(w, "A restaurant with this name and address already exists", http.StatusConflict)
 				} else {
-					http.Error(w, "This restaurant already exists", http.StatusConflict)
+					apperrors.Error(w, "This restaurant already exists", http.StatusConflict)
 				}
 				return
 			}
 		}
+		if writeConstraintViolation(w, err) {
+			return
+		}
 		logger.Error("Failed to create restaurant: %v", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		apperrors.Internal(w, err)
 		return
 	}
 
-	// Set food types
 	if len(req.FoodTypeIDs) > 0 {
-		if err := setFoodTypesForRestaurant(ctx, rest.ID, req.FoodTypeIDs); err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		foodTypes, _ := getFoodTypesForRestaurant(ctx, rest.ID)
-		rest.FoodTypes = foodTypes
+		rest.FoodTypes, _ = restaurantRepo.FoodTypes(ctx, rest.ID)
 	}
+	// The category and food type lists count restaurants
+	cache.Invalidate(ctx, cache.KeyRestaurants, cache.KeyCategories, cache.KeyFoodTypes)
+	publishChange(ctx, eventRestaurantCreated, spaceID(r), rest.ID, rest.ID)
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
@@ -652,61 +313,54 @@ func CreateRestaurant(w http.ResponseWriter, r *http.Request) {
 // @Param id path int true "Restaurant ID"
 // @Param restaurant body models.UpdateRestaurantRequest true "Restaurant update request"
 // @Success 200 {object} models.Restaurant "Updated restaurant"
-// @Failure 400 {object} map[string]string "Invalid request"
-// @Failure 404 {object} map[string]string "Restaurant not found"
-// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 400 {object} errors.ErrorResponse "Invalid request"
+// @Failure 404 {object} errors.ErrorResponse "Restaurant not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
 // @Router /restaurants/{id} [put]
 func UpdateRestaurant(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id, err := strconv.Atoi(vars["id"])
 	if err != nil {
-		http.Error(w, "Invalid restaurant ID", http.StatusBadRequest)
+		apperrors.Error(w, "Invalid restaurant ID", http.StatusBadRequest)
 		return
 	}
 
 	var req models.UpdateRestaurantRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		apperrors.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
 
-	ctx := context.Background()
-
-	var rest models.Restaurant
-	err = database.GetPool().QueryRow(ctx,
-		`UPDATE restaurants SET
-			name = COALESCE($1, name),
-			description = COALESCE($2, description),
-			address = COALESCE($3, address),
-			phone = COALESCE($4, phone),
-			website = COALESCE($5, website),
-			latitude = COALESCE($6, latitude),
-			longitude = COALESCE($7, longitude),
-			google_place_id = COALESCE($8, google_place_id),
-			category_id = COALESCE($9, category_id),
-			updated_at = NOW()
-		WHERE id = $10
-		RETURNING id, name, description, address, phone, website, latitude, longitude, google_place_id, category_id, created_at, updated_at`,
-		req.Name, req.Description, req.Address, req.Phone, req.Website, req.Latitude, req.Longitude, req.GooglePlaceID, req.CategoryID, id,
-	).Scan(
-		&rest.ID, &rest.Name, &rest.Description, &rest.Address, &rest.Phone, &rest.Website, &rest.Latitude, &rest.Longitude,
-		&rest.GooglePlaceID, &rest.CategoryID, &rest.CreatedAt, &rest.UpdatedAt,
-	)
-	if err != nil {
-		http.Error(w, "Restaurant not found", http.StatusNotFound)
-		return
-	}
+	ctx := r.Context()
 
-	// Update food types if provided
-	if req.FoodTypeIDs != nil {
-		if err := setFoodTypesForRestaurant(ctx, rest.ID, req.FoodTypeIDs); err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+	var rest *models.Restaurant
+	err = repos.WithTx(ctx, func(tx *repository.Repositories) error {
+		var err error
+		if rest, err = tx.Restaurants.Update(ctx, id, req); err != nil {
+			return err
+		}
+		// Update food types if provided
+		if req.FoodTypeIDs != nil {
+			return tx.Restaurants.SetFoodTypes(ctx, rest.ID, req.FoodTypeIDs)
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			apperrors.Error(w, "Restaurant not found", http.StatusNotFound)
+			return
+		}
+		if writeConstraintViolation(w, err) {
 			return
 		}
+		apperrors.Internal(w, err)
+		return
 	}
 
-	foodTypes, _ := getFoodTypesForRestaurant(ctx, rest.ID)
-	rest.FoodTypes = foodTypes
+	rest.FoodTypes, _ = restaurantRepo.FoodTypes(ctx, rest.ID)
+	// The category and food type lists count restaurants
+	cache.Invalidate(ctx, cache.KeyRestaurants, cache.KeyCategories, cache.KeyFoodTypes)
+	publishChange(ctx, eventRestaurantUpdated, spaceID(r), rest.ID, rest.ID)
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(rest)
@@ -714,36 +368,38 @@ func UpdateRestaurant(w http.ResponseWriter, r *http.Request) {
 
 // DeleteRestaurant godoc
 // @Summary Delete a restaurant
-// @Description Delete a restaurant by ID
+// @Description Delete a restaurant by ID. Admins and owners and admins of its space only.
 // @Tags Restaurants
 // @Accept json
 // @Produce json
 // @Param id path int true "Restaurant ID"
 // @Success 204 "Restaurant deleted successfully"
-// @Failure 400 {object} map[string]string "Invalid restaurant ID"
-// @Failure 404 {object} map[string]string "Restaurant not found"
-// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 400 {object} errors.ErrorResponse "Invalid restaurant ID"
+// @Failure 403 {object} errors.ErrorResponse "Space admin access required"
+// @Failure 404 {object} errors.ErrorResponse "Restaurant not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
 // @Router /restaurants/{id} [delete]
 func DeleteRestaurant(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id, err := strconv.Atoi(vars["id"])
 	if err != nil {
-		http.Error(w, "Invalid restaurant ID", http.StatusBadRequest)
-		return
-	}
-
-	result, err := database.GetPool().Exec(context.Background(),
-		"DELETE FROM restaurants WHERE id = $1", id)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		apperrors.Error(w, "Invalid restaurant ID", http.StatusBadRequest)
 		return
 	}
 
-	if result.RowsAffected() == 0 {
-		http.Error(w, "Restaurant not found", http.StatusNotFound)
+	if err := restaurantRepo.Delete(r.Context(), id); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			apperrors.Error(w, "Restaurant not found", http.StatusNotFound)
+			return
+		}
+		apperrors.Internal(w, err)
 		return
 	}
 
+	// The category and food type lists count restaurants
+	cache.Invalidate(r.Context(), cache.KeyRestaurants, cache.KeyCategories, cache.KeyFoodTypes)
+	publishChange(r.Context(), eventRestaurantDeleted, spaceID(r), id, id)
+	recordAdminAction(r.Context(), r, "delete_restaurant", "restaurant", id, nil)
 	w.WriteHeader(http.StatusNoContent)
 }
 
@@ -755,17 +411,17 @@ func DeleteRestaurant(w http.ResponseWriter, r *http.Request) {
 // @Produce json
 // @Param q query string true "Search query string"
 // @Success 200 {array} models.Restaurant "List of matching restaurants and suggestions"
-// @Failure 400 {object} map[string]string "Query parameter 'q' is required"
-// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 400 {object} errors.ErrorResponse "Query parameter 'q' is required"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
 // @Router /search [get]
 func GlobalSearch(w http.ResponseWriter, r *http.Request) {
 	query := r.URL.Query().Get("q")
 	if query == "" {
-		http.Error(w, "Query parameter 'q' is required", http.StatusBadRequest)
+		apperrors.Error(w, "Query parameter 'q' is required", http.StatusBadRequest)
 		return
 	}
 
-	ctx := context.Background()
+	ctx := r.Context()
 	searchPattern := "%" + strings.ToLower(query) + "%"
 
 	// Search restaurants
@@ -784,7 +440,7 @@ func GlobalSearch(w http.ResponseWriter, r *http.Request) {
 		FROM restaurants r
 		LEFT JOIN categories c ON r.category_id = c.id
 		LEFT JOIN ratings rat ON r.id = rat.restaurant_id
-		WHERE LOWER(r.name) LIKE $1
+		WHERE LOWER(r.name) LIKE $1 AND r.space_id = $2
 		GROUP BY r.id, r.name, r.description, r.address, r.phone, r.website, r.latitude, r.longitude,
 			r.google_place_id, r.category_id, r.created_at, r.updated_at, c.id, c.name
 
@@ -800,16 +456,16 @@ func GlobalSearch(w http.ResponseWriter, r *http.Request) {
 			s.status
 		FROM restaurant_suggestions s
 		LEFT JOIN categories c ON s.suggested_category_id = c.id
-		WHERE LOWER(s.name) LIKE $1
+		WHERE LOWER(s.name) LIKE $1 AND s.space_id = $2
 			AND s.status = 'pending'
 
 		ORDER BY 2
 		LIMIT 20
 	`
 
-	rows, err := database.GetPool().Query(ctx, restaurantsQuery, searchPattern)
+	rows, err := database.GetPool().Query(ctx, restaurantsQuery, searchPattern, spaceID(r))
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		apperrors.Internal(w, err)
 		return
 	}
 	defer rows.Close()
@@ -837,7 +493,7 @@ func GlobalSearch(w http.ResponseWriter, r *http.Request) {
 			&isSuggestion, &suggestionID, &status,
 		)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			apperrors.Internal(w, err)
 			return
 		}
 
@@ -880,7 +536,7 @@ func GlobalSearch(w http.ResponseWriter, r *http.Request) {
 	if len(restaurantIDs) > 0 {
 		foodTypeMap, err := getFoodTypesForRestaurantsBatch(ctx, restaurantIDs)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			apperrors.Internal(w, err)
 			return
 		}
 		restaurantFoodTypes = foodTypeMap
@@ -891,7 +547,7 @@ func GlobalSearch(w http.ResponseWriter, r *http.Request) {
 	if len(suggestionIDs) > 0 {
 		foodTypeMap, err := getFoodTypesForSuggestionsBatch(ctx, suggestionIDs)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			apperrors.Internal(w, err)
 			return
 		}
 		suggestionFoodTypes = foodTypeMap
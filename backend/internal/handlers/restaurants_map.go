@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	apperrors "github.com/nomdb/backend/internal/errors"
+	"github.com/nomdb/backend/internal/logger"
+	"github.com/nomdb/backend/internal/models"
+	"github.com/nomdb/backend/internal/repository"
+)
+
+// Map clustering: bounds with more restaurants than mapClusterThreshold are answered
+// with a mapClusterGridSize x mapClusterGridSize grid of clusters instead of markers
+var (
+	mapClusterThreshold = 200
+	mapClusterGridSize  = 8
+)
+
+// InitMapClustering configures when and how finely map views are clustered
+func InitMapClustering(threshold, gridSize int) {
+	mapClusterThreshold = threshold
+	mapClusterGridSize = gridSize
+	logger.Info("✅ Map views clustered above %d restaurants (%dx%d grid)", threshold, gridSize, gridSize)
+}
+
+// parseMapBounds reads the north-east and south-west corners of a map view
+func parseMapBounds(params url.Values) (repository.MapBounds, bool) {
+	var values [4]float64
+	for i, name := range []string{"ne_lat", "ne_lng", "sw_lat", "sw_lng"} {
+		v, err := strconv.ParseFloat(params.Get(name), 64)
+		if err != nil {
+			return repository.MapBounds{}, false
+		}
+		values[i] = v
+	}
+	bounds := repository.MapBounds{North: values[0], East: values[1], South: values[2], West: values[3]}
+
+	validLat := func(v float64) bool { return v >= -90 && v <= 90 }
+	validLng := func(v float64) bool { return v >= -180 && v <= 180 }
+	if !validLat(bounds.North) || !validLat(bounds.South) || !validLng(bounds.East) || !validLng(bounds.West) ||
+		bounds.North <= bounds.South || bounds.East == bounds.West {
+		return repository.MapBounds{}, false
+	}
+	return bounds, true
+}
+
+// GetRestaurantsInBounds godoc
+// @Summary Get restaurants in a map area
+// @Description Get lightweight markers of the restaurants inside a map view. Above a configured number of restaurants (MAP_CLUSTER_THRESHOLD) the response holds grid clusters with counts instead, and markers is empty. A view crossing the antimeridian has sw_lng greater than ne_lng.
+// @Tags Restaurants
+// @Produce json
+// @Param ne_lat query number true "Latitude of the north-east corner"
+// @Param ne_lng query number true "Longitude of the north-east corner"
+// @Param sw_lat query number true "Latitude of the south-west corner"
+// @Param sw_lng query number true "Longitude of the south-west corner"
+// @Param category_id query int false "Filter by category ID"
+// @Param food_type_ids query string false "Filter by food type IDs (comma-separated)"
+// @Param q query string false "Search query for name or description"
+// @Success 200 {object} models.MapBoundsResponse "Markers or clusters"
+// @Failure 400 {object} errors.ErrorResponse "Invalid bounds"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /restaurants/in-bounds [get]
+func GetRestaurantsInBounds(w http.ResponseWriter, r *http.Request) {
+	bounds, ok := parseMapBounds(r.URL.Query())
+	if !ok {
+		apperrors.Error(w, "ne_lat, ne_lng, sw_lat and sw_lng are required: latitudes between -90 and 90 with ne_lat above sw_lat, longitudes between -180 and 180", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	filter := parseRestaurantFilter(r.URL.Query())
+	filter.Search = r.URL.Query().Get("q")
+	filter.SpaceID = spaceID(r)
+
+	// One more than the threshold tells whether to cluster
+	markers, err := restaurantRepo.MarkersInBounds(ctx, filter, bounds, mapClusterThreshold+1)
+	if err != nil {
+		logger.Error("Failed to query restaurants in bounds: %v", err)
+		apperrors.Internal(w, err)
+		return
+	}
+
+	resp := models.MapBoundsResponse{Markers: markers, Clusters: []models.MapCluster{}, Total: len(markers)}
+	if len(markers) > mapClusterThreshold {
+		clusters, err := restaurantRepo.ClustersInBounds(ctx, filter, bounds, mapClusterGridSize)
+		if err != nil {
+			logger.Error("Failed to cluster restaurants in bounds: %v", err)
+			apperrors.Internal(w, err)
+			return
+		}
+		resp = models.MapBoundsResponse{Markers: []models.MapMarker{}, Clusters: clusters, Clustered: true}
+		for _, c := range clusters {
+			resp.Total += c.Count
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
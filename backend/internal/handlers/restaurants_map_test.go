@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/nomdb/backend/internal/models"
+	"github.com/nomdb/backend/internal/repository"
+	"github.com/pashagolub/pgxmock/v4"
+)
+
+func TestParseMapBounds(t *testing.T) {
+	tests := []struct {
+		query string
+		valid bool
+	}{
+		{"ne_lat=53&ne_lng=14&sw_lat=52&sw_lng=13", true},
+		{"ne_lat=10&ne_lng=-170&sw_lat=-10&sw_lng=170", true}, // Crosses the antimeridian
+		{"ne_lat=53&ne_lng=14&sw_lat=52", false},
+		{"ne_lat=abc&ne_lng=14&sw_lat=52&sw_lng=13", false},
+		{"ne_lat=52&ne_lng=14&sw_lat=53&sw_lng=13", false}, // North below south
+		{"ne_lat=91&ne_lng=14&sw_lat=52&sw_lng=13", false},
+		{"ne_lat=53&ne_lng=181&sw_lat=52&sw_lng=13", false},
+		{"ne_lat=53&ne_lng=13&sw_lat=52&sw_lng=13", false}, // No width
+	}
+	for _, tt := range tests {
+		params, _ := url.ParseQuery(tt.query)
+		if _, ok := parseMapBounds(params); ok != tt.valid {
+			t.Errorf("%s: expected valid=%v, got %v", tt.query, tt.valid, ok)
+		}
+	}
+}
+
+func TestGetRestaurantsInBounds(t *testing.T) {
+	markerColumns := []string{"id", "name", "latitude", "longitude", "category_id", "rating"}
+	const path = "/api/restaurants/in-bounds?ne_lat=53&ne_lng=14&sw_lat=52&sw_lng=13"
+
+	threshold := mapClusterThreshold
+	t.Cleanup(func() { mapClusterThreshold = threshold })
+	mapClusterThreshold = 2
+
+	decode := func(t *testing.T, rec *httptest.ResponseRecorder) models.MapBoundsResponse {
+		t.Helper()
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		var resp models.MapBoundsResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("Invalid response: %v", err)
+		}
+		return resp
+	}
+
+	t.Run("markers below the threshold", func(t *testing.T) {
+		mock := withMockRepositories(t)
+		mock.ExpectQuery(`FROM restaurants r .* LIMIT 3`).
+			WithArgs(52.0, 53.0, 13.0, 14.0, repository.DefaultSpaceID).
+			WillReturnRows(pgxmock.NewRows(markerColumns).
+				AddRow(1, "Luigi's", 52.5, 13.4, nil, nil).
+				AddRow(2, "Mario's", 52.6, 13.5, nil, nil))
+
+		rec := httptest.NewRecorder()
+		GetRestaurantsInBounds(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		resp := decode(t, rec)
+		if resp.Clustered || len(resp.Markers) != 2 || resp.Total != 2 || resp.Clusters == nil {
+			t.Errorf("Expected 2 markers, got %+v", resp)
+		}
+	})
+
+	t.Run("clusters above the threshold", func(t *testing.T) {
+		mock := withMockRepositories(t)
+		mock.ExpectQuery(`FROM restaurants r .* LIMIT 3`).
+			WithArgs(52.0, 53.0, 13.0, 14.0, repository.DefaultSpaceID).
+			WillReturnRows(pgxmock.NewRows(markerColumns).
+				AddRow(1, "A", 52.1, 13.1, nil, nil).
+				AddRow(2, "B", 52.2, 13.2, nil, nil).
+				AddRow(3, "C", 52.9, 13.9, nil, nil))
+		mock.ExpectQuery(`AS cell_row`).
+			WithArgs(pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(),
+				pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(),
+				pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg()).
+			WillReturnRows(pgxmock.NewRows([]string{"cell_row", "cell_col", "count", "lat", "lng_offset", "restaurant_id"}).
+				AddRow(0, 0, 240, 52.2, 0.2, nil).
+				AddRow(7, 7, 60, 52.9, 0.9, nil))
+
+		rec := httptest.NewRecorder()
+		GetRestaurantsInBounds(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		resp := decode(t, rec)
+		if !resp.Clustered || len(resp.Markers) != 0 || len(resp.Clusters) != 2 || resp.Total != 300 {
+			t.Errorf("Expected 2 clusters of 300 restaurants, got %+v", resp)
+		}
+		if resp.Clusters[0].Longitude != 13.2 {
+			t.Errorf("Expected the centroid longitude 13.2, got %v", resp.Clusters[0].Longitude)
+		}
+	})
+
+	t.Run("invalid bounds", func(t *testing.T) {
+		withMockRepositories(t)
+		rec := httptest.NewRecorder()
+		GetRestaurantsInBounds(rec, httptest.NewRequest(http.MethodGet, "/api/restaurants/in-bounds?ne_lat=53", nil))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected 400, got %d", rec.Code)
+		}
+	})
+}
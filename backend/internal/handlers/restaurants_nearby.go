@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+
+	apperrors "github.com/nomdb/backend/internal/errors"
+	"github.com/nomdb/backend/internal/logger"
+	"github.com/nomdb/backend/internal/models"
+	"github.com/nomdb/backend/internal/repository"
+	"github.com/nomdb/backend/internal/services"
+)
+
+const (
+	nearbyDefaultLimit    = 20
+	nearbyMaxLimit        = 100
+	nearbyDefaultRadiusKm = 5
+	nearbyMaxRadiusKm     = 50
+	nearbyMaxMinutes      = 120
+)
+
+// nearbySpeedKmh bounds how far a travel mode gets in a given time, so the straight-line
+// search radius holds every restaurant reachable within max_minutes
+var nearbySpeedKmh = map[string]float64{
+	services.TravelModeWalking: 6,
+	services.TravelModeDriving: 100,
+}
+
+// travelTimes computes travel times for nearby searches; replaced in tests
+var travelTimes interface {
+	Enabled() bool
+	TravelTimes(ctx context.Context, origin services.LatLng, destinations []services.LatLng, mode string) ([]*time.Duration, error)
+} = mapsService
+
+var errTravelTimesUnavailable = errors.New("travel times unavailable")
+
+// nearbyQuery is a parsed nearby search
+type nearbyQuery struct {
+	filter     repository.RestaurantFilter
+	mode       string // Empty for straight-line distance only
+	maxMinutes float64
+	limit      int
+}
+
+// parseNearbyQuery validates the parameters of a nearby search, returning a message for
+// the client when they are invalid
+func parseNearbyQuery(params url.Values) (nearbyQuery, string) {
+	lat, err := strconv.ParseFloat(params.Get("lat"), 64)
+	if err != nil || lat < -90 || lat > 90 {
+		return nearbyQuery{}, "lat is required and must be between -90 and 90"
+	}
+	lng, err := strconv.ParseFloat(params.Get("lng"), 64)
+	if err != nil || lng < -180 || lng > 180 {
+		return nearbyQuery{}, "lng is required and must be between -180 and 180"
+	}
+
+	q := nearbyQuery{limit: nearbyDefaultLimit}
+	if v := params.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 || limit > nearbyMaxLimit {
+			return nearbyQuery{}, "limit must be between 1 and 100"
+		}
+		q.limit = limit
+	}
+
+	radius := float64(nearbyDefaultRadiusKm)
+	if q.mode = params.Get("mode"); q.mode != "" {
+		speed, ok := nearbySpeedKmh[q.mode]
+		if !ok {
+			return nearbyQuery{}, "Invalid mode. Must be one of: walking, driving"
+		}
+		q.maxMinutes, err = strconv.ParseFloat(params.Get("max_minutes"), 64)
+		if err != nil || q.maxMinutes < 1 || q.maxMinutes > nearbyMaxMinutes {
+			return nearbyQuery{}, "max_minutes is required with mode and must be between 1 and 120"
+		}
+		radius = min(speed*q.maxMinutes/60, nearbyMaxRadiusKm)
+	} else if params.Get("max_minutes") != "" {
+		return nearbyQuery{}, "max_minutes requires mode"
+	}
+	if v := params.Get("radius"); v != "" {
+		radius, err = strconv.ParseFloat(v, 64)
+		if err != nil || radius <= 0 || radius > nearbyMaxRadiusKm {
+			return nearbyQuery{}, "radius must be greater than 0 and at most 50 km"
+		}
+	}
+
+	q.filter = parseRestaurantFilter(params)
+	q.filter.Search = params.Get("q")
+	q.filter.Near = &repository.GeoRadius{Lat: lat, Lng: lng, RadiusKm: radius}
+	return q, ""
+}
+
+// nearbyRestaurants returns the restaurants closest to the query's location. With a
+// travel mode, twice the limit of the closest candidates are asked for travel times and
+// those reachable within max_minutes are returned, quickest first.
+func nearbyRestaurants(ctx context.Context, q nearbyQuery) ([]models.Restaurant, error) {
+	if q.mode == "" {
+		return restaurantRepo.ListNearby(ctx, q.filter, q.limit)
+	}
+
+	candidates, err := restaurantRepo.ListNearby(ctx, q.filter, min(q.limit*2, nearbyMaxLimit))
+	if err != nil || len(candidates) == 0 {
+		return candidates, err
+	}
+
+	destinations := make([]services.LatLng, len(candidates))
+	for i, c := range candidates {
+		// Nearby listings only hold restaurants with coordinates
+		destinations[i] = services.LatLng{Lat: *c.Latitude, Lng: *c.Longitude}
+	}
+	origin := services.LatLng{Lat: q.filter.Near.Lat, Lng: q.filter.Near.Lng}
+	times, err := travelTimes.TravelTimes(ctx, origin, destinations, q.mode)
+	if err != nil {
+		return nil, errors.Join(errTravelTimesUnavailable, err)
+	}
+
+	reachable := []models.Restaurant{}
+	for i, c := range candidates {
+		if times[i] == nil {
+			continue
+		}
+		minutes := times[i].Minutes()
+		if minutes > q.maxMinutes {
+			continue
+		}
+		c.TravelMinutes = &minutes
+		reachable = append(reachable, c)
+	}
+	sort.SliceStable(reachable, func(i, j int) bool {
+		return *reachable[i].TravelMinutes < *reachable[j].TravelMinutes
+	})
+	if len(reachable) > q.limit {
+		reachable = reachable[:q.limit]
+	}
+	return reachable, nil
+}
+
+// GetRestaurantsNearby godoc
+// @Summary Get restaurants near a location
+// @Description Get the restaurants closest to a location, nearest first, with their distance in km. With mode and max_minutes, the closest candidates are checked with the Google Distance Matrix API and only those reachable within max_minutes are returned, quickest first, with their travel time.
+// @Tags Restaurants
+// @Produce json
+// @Param lat query number true "Latitude of the location"
+// @Param lng query number true "Longitude of the location"
+// @Param limit query int false "Maximum number of restaurants (default 20, max 100)"
+// @Param radius query number false "Search radius in km (default 5, or how far the mode gets in max_minutes; max 50)"
+// @Param mode query string false "Travel mode: walking or driving"
+// @Param max_minutes query number false "Maximum travel time in minutes (1 to 120), required with mode"
+// @Param category_id query int false "Filter by category ID"
+// @Param food_type_ids query string false "Filter by food type IDs (comma-separated)"
+// @Param q query string false "Search query for name or description"
+// @Success 200 {array} models.Restaurant "Nearby restaurants"
+// @Failure 400 {object} errors.ErrorResponse "Invalid parameters"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Failure 502 {object} errors.ErrorResponse "Travel times could not be retrieved"
+// @Failure 503 {object} errors.ErrorResponse "Travel times not configured or Google Maps temporarily unavailable"
+// @Router /restaurants/nearby [get]
+func GetRestaurantsNearby(w http.ResponseWriter, r *http.Request) {
+	q, msg := parseNearbyQuery(r.URL.Query())
+	if msg != "" {
+		apperrors.Error(w, msg, http.StatusBadRequest)
+		return
+	}
+	if q.mode != "" && !travelTimes.Enabled() {
+		apperrors.Error(w, "Travel times require a Google Maps API key", http.StatusServiceUnavailable)
+		return
+	}
+	q.filter.SpaceID = spaceID(r)
+
+	ctx := r.Context()
+	restaurants, err := nearbyRestaurants(ctx, q)
+	if errors.Is(err, services.ErrMapsUnavailable) || errors.Is(err, services.ErrMapsBudgetExceeded) {
+		writeMapsError(w, err)
+		return
+	}
+	if errors.Is(err, errTravelTimesUnavailable) {
+		logger.Error("Failed to get travel times: %v", err)
+		apperrors.Error(w, "Failed to get travel times", http.StatusBadGateway)
+		return
+	}
+	if err != nil {
+		logger.Error("Failed to query nearby restaurants: %v", err)
+		apperrors.Internal(w, err)
+		return
+	}
+
+	attachRestaurantListDetails(ctx, restaurants)
+	localizeRestaurants(ctx, restaurants)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(restaurants)
+}
@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/nomdb/backend/internal/repository"
+	"github.com/nomdb/backend/internal/services"
+	"github.com/pashagolub/pgxmock/v4"
+)
+
+// fakeTravelTimes answers travel time requests with fixed minutes per destination
+type fakeTravelTimes struct {
+	enabled bool
+	minutes []float64 // Negative for destinations without a route
+	err     error
+	calls   int
+}
+
+func (f *fakeTravelTimes) Enabled() bool { return f.enabled }
+
+func (f *fakeTravelTimes) TravelTimes(_ context.Context, _ services.LatLng, destinations []services.LatLng, _ string) ([]*time.Duration, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	times := make([]*time.Duration, len(destinations))
+	for i := range destinations {
+		if f.minutes[i] >= 0 {
+			d := time.Duration(f.minutes[i] * float64(time.Minute))
+			times[i] = &d
+		}
+	}
+	return times, nil
+}
+
+func withTravelTimes(t *testing.T, fake *fakeTravelTimes) {
+	t.Helper()
+	original := travelTimes
+	travelTimes = fake
+	t.Cleanup(func() { travelTimes = original })
+}
+
+var nearbyColumns = []string{
+	"id", "name", "description", "address", "phone", "website", "latitude", "longitude",
+	"google_place_id", "category_id", "created_at", "updated_at", "business_status", "opening_hours",
+	"c.id", "c.name",
+	"avg_food", "avg_service", "avg_ambiance", "rating_count",
+	"is_suggestion", "suggestion_id", "status", "distance",
+}
+
+func nearbyRow(rows *pgxmock.Rows, id int, distance float64) *pgxmock.Rows {
+	lat, lng, now := 52.5, 13.4, time.Now()
+	return rows.AddRow(id, "Restaurant", nil, nil, nil, nil, &lat, &lng, nil, nil, now, now, nil, nil, nil, nil,
+		0.0, 0.0, 0.0, 0, false, nil, nil, &distance)
+}
+
+func TestParseNearbyQuery(t *testing.T) {
+	tests := []struct {
+		query  string
+		valid  bool
+		radius float64
+		limit  int
+	}{
+		{"lat=52.5&lng=13.4", true, 5, 20},
+		{"lat=52.5&lng=13.4&limit=5&radius=2", true, 2, 5},
+		{"lat=52.5&lng=13.4&mode=walking&max_minutes=15", true, 1.5, 20},
+		{"lat=52.5&lng=13.4&mode=driving&max_minutes=60", true, 50, 20}, // Capped
+		{"lat=52.5&lng=13.4&mode=walking&max_minutes=15&radius=3", true, 3, 20},
+		{"lng=13.4", false, 0, 0},
+		{"lat=91&lng=13.4", false, 0, 0},
+		{"lat=52.5&lng=181", false, 0, 0},
+		{"lat=52.5&lng=13.4&limit=101", false, 0, 0},
+		{"lat=52.5&lng=13.4&radius=51", false, 0, 0},
+		{"lat=52.5&lng=13.4&mode=cycling&max_minutes=15", false, 0, 0},
+		{"lat=52.5&lng=13.4&mode=walking", false, 0, 0},
+		{"lat=52.5&lng=13.4&mode=walking&max_minutes=121", false, 0, 0},
+		{"lat=52.5&lng=13.4&max_minutes=15", false, 0, 0},
+	}
+	for _, tt := range tests {
+		params, _ := url.ParseQuery(tt.query)
+		q, msg := parseNearbyQuery(params)
+		if (msg == "") != tt.valid {
+			t.Errorf("%s: expected valid=%v, got %q", tt.query, tt.valid, msg)
+			continue
+		}
+		if tt.valid && (q.filter.Near.RadiusKm != tt.radius || q.limit != tt.limit) {
+			t.Errorf("%s: expected radius %v and limit %d, got %v and %d", tt.query, tt.radius, tt.limit, q.filter.Near.RadiusKm, q.limit)
+		}
+	}
+}
+
+func TestNearbyRestaurants_TravelTime(t *testing.T) {
+	mock := withMockRepositories(t)
+	fake := &fakeTravelTimes{enabled: true, minutes: []float64{14, -1, 6, 25}}
+	withTravelTimes(t, fake)
+
+	// Twice the limit of candidates, closest first
+	rows := pgxmock.NewRows(nearbyColumns)
+	for i, d := range []float64{0.3, 0.5, 0.9, 1.2} {
+		nearbyRow(rows, i+1, d)
+	}
+	mock.ExpectQuery(`ORDER BY distance ASC, r\.id ASC LIMIT 4`).
+		WithArgs(pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), 1.5).
+		WillReturnRows(rows)
+
+	params, _ := url.ParseQuery("lat=52.5&lng=13.4&mode=walking&max_minutes=15&limit=2")
+	q, _ := parseNearbyQuery(params)
+	restaurants, err := nearbyRestaurants(context.Background(), q)
+	if err != nil {
+		t.Fatalf("nearbyRestaurants failed: %v", err)
+	}
+
+	// Unroutable and too distant candidates are dropped, the rest sorted by travel time
+	if len(restaurants) != 2 || restaurants[0].ID != 3 || restaurants[1].ID != 1 {
+		t.Fatalf("Expected restaurants 3 and 1, got %+v", restaurants)
+	}
+	if restaurants[0].TravelMinutes == nil || *restaurants[0].TravelMinutes != 6 {
+		t.Errorf("Expected 6 travel minutes, got %v", restaurants[0].TravelMinutes)
+	}
+}
+
+func TestNearbyRestaurants_Distance(t *testing.T) {
+	mock := withMockRepositories(t)
+	fake := &fakeTravelTimes{enabled: true}
+	withTravelTimes(t, fake)
+
+	mock.ExpectQuery(`ORDER BY distance ASC, r\.id ASC LIMIT 20`).
+		WithArgs(52.5, 13.4, 52.5, 52.5, 13.4, 52.5, 5.0).
+		WillReturnRows(nearbyRow(pgxmock.NewRows(nearbyColumns), 1, 0.3))
+
+	params, _ := url.ParseQuery("lat=52.5&lng=13.4")
+	q, _ := parseNearbyQuery(params)
+	restaurants, err := nearbyRestaurants(context.Background(), q)
+	if err != nil || len(restaurants) != 1 || restaurants[0].TravelMinutes != nil {
+		t.Fatalf("Expected one restaurant without travel time, got %+v (%v)", restaurants, err)
+	}
+	if fake.calls != 0 {
+		t.Error("Expected no travel time requests without a mode")
+	}
+}
+
+func TestGetRestaurantsNearby_Errors(t *testing.T) {
+	t.Run("invalid parameters", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		GetRestaurantsNearby(rec, httptest.NewRequest(http.MethodGet, "/api/restaurants/nearby?lat=52.5", nil))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected 400, got %d", rec.Code)
+		}
+	})
+
+	t.Run("travel times not configured", func(t *testing.T) {
+		withTravelTimes(t, &fakeTravelTimes{})
+		rec := httptest.NewRecorder()
+		GetRestaurantsNearby(rec, httptest.NewRequest(http.MethodGet,
+			"/api/restaurants/nearby?lat=52.5&lng=13.4&mode=driving&max_minutes=10", nil))
+		if rec.Code != http.StatusServiceUnavailable {
+			t.Errorf("Expected 503, got %d", rec.Code)
+		}
+	})
+
+	t.Run("distance matrix failure", func(t *testing.T) {
+		mock := withMockRepositories(t)
+		withTravelTimes(t, &fakeTravelTimes{enabled: true, err: errors.New("OVER_QUERY_LIMIT")})
+		mock.ExpectQuery(`ORDER BY distance ASC`).
+			WithArgs(pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), repository.DefaultSpaceID, pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg()).
+			WillReturnRows(nearbyRow(pgxmock.NewRows(nearbyColumns), 1, 0.3))
+
+		rec := httptest.NewRecorder()
+		GetRestaurantsNearby(rec, httptest.NewRequest(http.MethodGet,
+			"/api/restaurants/nearby?lat=52.5&lng=13.4&mode=driving&max_minutes=10", nil))
+		if rec.Code != http.StatusBadGateway {
+			t.Errorf("Expected 502, got %d", rec.Code)
+		}
+	})
+}
@@ -2,192 +2,112 @@ package handlers
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 
-	"github.com/nomdb/backend/internal/database"
+	"github.com/nomdb/backend/internal/cache"
+	apperrors "github.com/nomdb/backend/internal/errors"
 	"github.com/nomdb/backend/internal/logger"
 	"github.com/nomdb/backend/internal/models"
+	"github.com/nomdb/backend/internal/repository"
 )
 
+// parseRestaurantFilter reads the category_id and food_type_ids (comma-separated)
+// filters shared by the restaurant listings, ignoring invalid values
+func parseRestaurantFilter(params url.Values) repository.RestaurantFilter {
+	var filter repository.RestaurantFilter
+	if catID, err := strconv.Atoi(params.Get("category_id")); err == nil {
+		filter.CategoryID = &catID
+	}
+	if foodTypeIDs := params.Get("food_type_ids"); foodTypeIDs != "" {
+		for _, idStr := range strings.Split(foodTypeIDs, ",") {
+			if id, err := strconv.Atoi(strings.TrimSpace(idStr)); err == nil {
+				filter.FoodTypeIDs = append(filter.FoodTypeIDs, id)
+			}
+		}
+	}
+	return filter
+}
+
 // GetRestaurantsPaginated godoc
 // @Summary Get paginated list of restaurants
-// @Description Get restaurants with cursor-based pagination and optional filtering by category, food types, and search query
+// @Description Get restaurants with cursor-based pagination and optional filtering by category, food types, and search query. Cursors are only valid for the sort they were returned with. Cacheable for HTTP_CACHE_MAX_AGE; revalidate with If-None-Match or If-Modified-Since to get 304 Not Modified while unchanged.
 // @Tags Restaurants
 // @Accept json
 // @Produce json
-// @Param cursor query string false "Pagination cursor (encoded last ID)"
+// @Param cursor query string false "Pagination cursor (next_cursor of the previous page)"
+// @Param sort query string false "Sort order: id (default, oldest first), name (A to Z) or rating (highest first)"
 // @Param limit query int false "Number of items per page (default 20, max 100)"
 // @Param category_id query int false "Filter by category ID"
 // @Param food_type_ids query string false "Filter by food type IDs (comma-separated)"
 // @Param q query string false "Search query for name or description"
 // @Success 200 {object} models.PaginatedResponse "Paginated list of restaurants"
-// @Failure 400 {object} map[string]string "Invalid cursor or parameters"
-// @Failure 500 {object} map[string]string "Internal server error"
+// @Success 304 "Not modified"
+// @Failure 400 {object} errors.ErrorResponse "Invalid cursor or parameters"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
 // @Router /restaurants/paginated [get]
 func GetRestaurantsPaginated(w http.ResponseWriter, r *http.Request) {
-	ctx := context.Background()
+	ctx := r.Context()
 
 	// Parse pagination parameters
 	pagination := ParsePaginationParams(r)
 
-	// Decode cursor to get last ID
-	lastID, err := DecodeCursor(pagination.Cursor)
+	sort := repository.RestaurantSort(r.URL.Query().Get("sort"))
+	if sort == "" {
+		sort = repository.SortByID
+	}
+	if !repository.ValidSort(sort) {
+		apperrors.Error(w, "Invalid sort. Must be one of: id, name, rating", http.StatusBadRequest)
+		return
+	}
+
+	// Decode cursor to get the position after which the page starts
+	after, err := DecodeSortCursor(pagination.Cursor, sort)
 	if err != nil {
-		logger.Error("Invalid cursor: %v", err)
-		http.Error(w, "Invalid cursor", http.StatusBadRequest)
+		logger.Debug("Invalid cursor: %v", err)
+		apperrors.Error(w, "Invalid cursor", http.StatusBadRequest)
 		return
 	}
 
 	// Parse query parameters for filtering
-	queryParams := r.URL.Query()
-	categoryID := queryParams.Get("category_id")
-	foodTypeIDs := queryParams.Get("food_type_ids")
-	searchQuery := queryParams.Get("q")
-
-	// Build query with filters and pagination
-	var conditions []string
-	var args []interface{}
-	argIndex := 1
-
-	// Cursor-based pagination - only get items after the last ID
-	if lastID > 0 {
-		conditions = append(conditions, fmt.Sprintf("r.id > $%d", argIndex))
-		args = append(args, lastID)
-		argIndex++
-	}
+	filter := parseRestaurantFilter(r.URL.Query())
+	filter.Search = r.URL.Query().Get("q")
+	filter.SpaceID = spaceID(r)
 
-	// Category filter
-	if categoryID != "" {
-		if catID, parseErr := strconv.Atoi(categoryID); parseErr == nil {
-			conditions = append(conditions, fmt.Sprintf("r.category_id = $%d", argIndex))
-			args = append(args, catID)
-			argIndex++
-		}
+	restaurants, next, err := restaurantRepo.ListPage(ctx, filter, sort, after, pagination.Limit)
+	if err != nil {
+		logger.Error("Failed to query restaurants: %v", err)
+		apperrors.Error(w, "Failed to fetch restaurants", http.StatusInternalServerError)
+		return
 	}
 
-	// Food type filter
-	if foodTypeIDs != "" {
-		ftIDs := strings.Split(foodTypeIDs, ",")
-		var validIDs []int
-		for _, idStr := range ftIDs {
-			if id, parseErr := strconv.Atoi(strings.TrimSpace(idStr)); parseErr == nil {
-				validIDs = append(validIDs, id)
-			}
-		}
-		if len(validIDs) > 0 {
-			placeholders := make([]string, len(validIDs))
-			for i, id := range validIDs {
-				placeholders[i] = fmt.Sprintf("$%d", argIndex)
-				args = append(args, id)
-				argIndex++
-			}
-			conditions = append(conditions, fmt.Sprintf(`r.id IN (
-				SELECT DISTINCT restaurant_id FROM restaurant_food_types
-				WHERE food_type_id IN (%s)
-			)`, strings.Join(placeholders, ",")))
-		}
-	}
+	attachRestaurantListDetails(ctx, restaurants)
+	localizeRestaurants(ctx, restaurants)
 
-	// Search query filter
-	if searchQuery != "" {
-		conditions = append(conditions, fmt.Sprintf("(r.name ILIKE $%d OR r.description ILIKE $%d)", argIndex, argIndex))
-		args = append(args, "%"+searchQuery+"%")
-		argIndex++
+	// Build paginated response
+	var nextCursor *string
+	if next != nil {
+		cursor := EncodeSortCursor(sort, *next)
+		nextCursor = &cursor
 	}
 
-	whereClause := ""
-	if len(conditions) > 0 {
-		whereClause = "WHERE " + strings.Join(conditions, " AND ")
+	response := models.PaginatedResponse{
+		Data:       restaurants,
+		NextCursor: nextCursor,
+		HasMore:    next != nil,
 	}
 
-	// Fetch one more than limit to determine if there are more results
-	fetchLimit := pagination.Limit + 1
-
-	query := fmt.Sprintf(`
-		SELECT
-			r.id, r.name, r.description, r.address, r.phone, r.website, r.latitude, r.longitude,
-			r.google_place_id, r.category_id, r.created_at, r.updated_at,
-			c.id, c.name,
-			COALESCE(AVG(rt.food_rating), 0) as avg_food,
-			COALESCE(AVG(rt.service_rating), 0) as avg_service,
-			COALESCE(AVG(rt.ambiance_rating), 0) as avg_ambiance,
-			COUNT(rt.id) as rating_count
-		FROM restaurants r
-		LEFT JOIN categories c ON r.category_id = c.id
-		LEFT JOIN ratings rt ON r.id = rt.restaurant_id
-		%s
-		GROUP BY r.id, c.id
-		ORDER BY r.id ASC
-		LIMIT $%d
-	`, whereClause, argIndex)
-
-	args = append(args, fetchLimit)
-
-	rows, err := database.GetPool().Query(ctx, query, args...)
-	if err != nil {
-		logger.Error("Failed to query restaurants: %v", err)
-		http.Error(w, "Failed to fetch restaurants", http.StatusInternalServerError)
-		return
-	}
-	defer rows.Close()
+	writeCacheableJSON(w, r, response, cache.KeyRestaurants)
+}
 
-	var restaurants []models.Restaurant
+// attachRestaurantListDetails adds food types and cover photos to listed restaurants.
+// Failures are logged and the list is returned without them.
+func attachRestaurantListDetails(ctx context.Context, restaurants []models.Restaurant) {
 	var restaurantIDs []int
-	var lastFetchedID int
-
-	for rows.Next() {
-		var restaurant models.Restaurant
-		var categoryID *int
-		var categoryName *string
-		var avgFood, avgService, avgAmbiance float64
-		var ratingCount int
-
-		err := rows.Scan(
-			&restaurant.ID, &restaurant.Name, &restaurant.Description, &restaurant.Address,
-			&restaurant.Phone, &restaurant.Website, &restaurant.Latitude, &restaurant.Longitude,
-			&restaurant.GooglePlaceID, &restaurant.CategoryID, &restaurant.CreatedAt, &restaurant.UpdatedAt,
-			&categoryID, &categoryName,
-			&avgFood, &avgService, &avgAmbiance, &ratingCount,
-		)
-		if err != nil {
-			logger.Error("Failed to scan restaurant: %v", err)
-			http.Error(w, "Failed to process restaurants", http.StatusInternalServerError)
-			return
-		}
-
-		if categoryID != nil && categoryName != nil {
-			restaurant.Category = &models.Category{
-				ID:   *categoryID,
-				Name: *categoryName,
-			}
-		}
-
-		if ratingCount > 0 {
-			overall := (avgFood + avgService + avgAmbiance) / 3
-			restaurant.AvgRating = &models.AvgRating{
-				Food:     avgFood,
-				Service:  avgService,
-				Ambiance: avgAmbiance,
-				Overall:  overall,
-				Count:    ratingCount,
-			}
-		}
-
-		restaurants = append(restaurants, restaurant)
+	for _, restaurant := range restaurants {
 		restaurantIDs = append(restaurantIDs, restaurant.ID)
-		lastFetchedID = restaurant.ID
-	}
-
-	// Check if there are more results
-	hasMore := len(restaurants) > pagination.Limit
-	if hasMore {
-		// Remove the extra item
-		restaurants = restaurants[:pagination.Limit]
-		restaurantIDs = restaurantIDs[:pagination.Limit]
 	}
 
 	// Fetch food types for all restaurants in batch
@@ -205,19 +125,15 @@ func GetRestaurantsPaginated(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	// Build paginated response
-	var nextCursor *string
-	if hasMore && lastFetchedID > 0 {
-		cursor := EncodeCursor(lastFetchedID)
-		nextCursor = &cursor
-	}
-
-	response := models.PaginatedResponse{
-		Data:       restaurants,
-		NextCursor: nextCursor,
-		HasMore:    hasMore,
+	// Fetch cover photos for all restaurants in batch
+	if len(restaurantIDs) > 0 {
+		covers, err := getCoverPhotosForRestaurantsBatch(ctx, restaurantIDs)
+		if err != nil {
+			logger.Warn("Failed to fetch cover photos: %v", err)
+		} else {
+			for i := range restaurants {
+				restaurants[i].CoverPhoto = covers[restaurants[i].ID]
+			}
+		}
 	}
-
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
 }
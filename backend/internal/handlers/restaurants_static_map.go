@@ -0,0 +1,196 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	apperrors "github.com/nomdb/backend/internal/errors"
+	"github.com/nomdb/backend/internal/models"
+	"github.com/nomdb/backend/internal/repository"
+	"github.com/nomdb/backend/internal/services"
+)
+
+// staticMaps renders map images; the Google Maps service unless replaced in tests
+var staticMaps interface {
+	Enabled() bool
+	StaticMap(ctx context.Context, opts services.StaticMapOptions) ([]byte, string, error)
+} = mapsService
+
+// maxStaticMapSize is the largest width and height of the Static Maps API
+const maxStaticMapSize = 640
+
+// parseStaticMapQuery reads the size, zoom and scale of a map image, returning an error
+// message for invalid values
+func parseStaticMapQuery(params url.Values) (services.StaticMapOptions, string) {
+	opts := services.StaticMapOptions{Width: 400, Height: 300, Zoom: 15, Scale: 1}
+	for _, p := range []struct {
+		name     string
+		value    *int
+		min, max int
+	}{
+		{"width", &opts.Width, 1, maxStaticMapSize},
+		{"height", &opts.Height, 1, maxStaticMapSize},
+		{"zoom", &opts.Zoom, 0, 21},
+		{"scale", &opts.Scale, 1, 2},
+	} {
+		v := params.Get(p.name)
+		if v == "" {
+			continue
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil || n < p.min || n > p.max {
+			return opts, fmt.Sprintf("%s must be between %d and %d", p.name, p.min, p.max)
+		}
+		*p.value = n
+	}
+	return opts, ""
+}
+
+// restaurantLocation is where maps of a restaurant point to: its coordinates, or its
+// address without them. It is empty when the restaurant has neither.
+func restaurantLocation(rest *models.Restaurant) string {
+	if rest.Latitude != nil && rest.Longitude != nil {
+		return strconv.FormatFloat(*rest.Latitude, 'f', -1, 64) + "," + strconv.FormatFloat(*rest.Longitude, 'f', -1, 64)
+	}
+	if rest.Address != nil {
+		return *rest.Address
+	}
+	return ""
+}
+
+// mapDirections links to directions to a restaurant in Google Maps and Apple Maps,
+// which need no API key
+func mapDirections(rest *models.Restaurant, location string) models.MapDirections {
+	google := url.Values{}
+	google.Set("api", "1")
+	google.Set("destination", location)
+	// Place IDs of other providers mean nothing to Google Maps
+	if rest.GooglePlaceID != nil && placesProvider.Name() == services.PlacesProviderGoogle {
+		google.Set("destination_place_id", *rest.GooglePlaceID)
+	}
+
+	apple := url.Values{}
+	apple.Set("daddr", location)
+	apple.Set("q", rest.Name)
+
+	return models.MapDirections{
+		Google: "https://www.google.com/maps/dir/?" + google.Encode(),
+		Apple:  "https://maps.apple.com/?" + apple.Encode(),
+	}
+}
+
+// locatedRestaurant loads the restaurant of the request and its location, writing the
+// error response if either is missing
+func locatedRestaurant(w http.ResponseWriter, r *http.Request) (*models.Restaurant, string, bool) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		apperrors.Error(w, "Invalid restaurant ID", http.StatusBadRequest)
+		return nil, "", false
+	}
+
+	rest, err := restaurantRepo.GetByID(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			apperrors.Error(w, "Restaurant not found", http.StatusNotFound)
+			return nil, "", false
+		}
+		apperrors.Internal(w, err)
+		return nil, "", false
+	}
+
+	location := restaurantLocation(rest)
+	if location == "" {
+		apperrors.Error(w, "Restaurant has no coordinates or address", http.StatusNotFound)
+		return nil, "", false
+	}
+	return rest, location, true
+}
+
+// GetRestaurantMap godoc
+// @Summary Get map links of a restaurant
+// @Description Get the URL of a map image of a restaurant, served by the API so clients need no Maps API key, and deep links to directions in Google Maps and Apple Maps. The image URL is missing when Google Maps is not configured.
+// @Tags Restaurants
+// @Produce json
+// @Param id path int true "Restaurant ID"
+// @Param width query int false "Image width in pixels (default 400, max 640)"
+// @Param height query int false "Image height in pixels (default 300, max 640)"
+// @Param zoom query int false "Zoom level from 0 (world) to 21 (buildings) (default 15)"
+// @Param scale query int false "1, or 2 for high-density screens (default 1)"
+// @Success 200 {object} models.RestaurantMap "Map image URL and directions links"
+// @Failure 400 {object} errors.ErrorResponse "Invalid parameters"
+// @Failure 404 {object} errors.ErrorResponse "Restaurant not found or without location"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /restaurants/{id}/map [get]
+func GetRestaurantMap(w http.ResponseWriter, r *http.Request) {
+	opts, msg := parseStaticMapQuery(r.URL.Query())
+	if msg != "" {
+		apperrors.Error(w, msg, http.StatusBadRequest)
+		return
+	}
+	rest, location, ok := locatedRestaurant(w, r)
+	if !ok {
+		return
+	}
+
+	result := models.RestaurantMap{Directions: mapDirections(rest, location)}
+	if staticMaps.Enabled() {
+		params := url.Values{}
+		params.Set("width", strconv.Itoa(opts.Width))
+		params.Set("height", strconv.Itoa(opts.Height))
+		params.Set("zoom", strconv.Itoa(opts.Zoom))
+		params.Set("scale", strconv.Itoa(opts.Scale))
+		result.ImageURL = fmt.Sprintf("/api/restaurants/%d/map/image?%s", rest.ID, params.Encode())
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// GetRestaurantMapImage godoc
+// @Summary Get a map image of a restaurant
+// @Description Get a Google Static Maps image with a marker at the restaurant, fetched with the server's API key. Browsers may cache it for a day.
+// @Tags Restaurants
+// @Produce png
+// @Param id path int true "Restaurant ID"
+// @Param width query int false "Image width in pixels (default 400, max 640)"
+// @Param height query int false "Image height in pixels (default 300, max 640)"
+// @Param zoom query int false "Zoom level from 0 (world) to 21 (buildings) (default 15)"
+// @Param scale query int false "1, or 2 for high-density screens (default 1)"
+// @Success 200 {file} binary "Map image"
+// @Failure 400 {object} errors.ErrorResponse "Invalid parameters"
+// @Failure 404 {object} errors.ErrorResponse "Restaurant not found or without location"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Failure 503 {object} errors.ErrorResponse "Google Maps not configured or temporarily unavailable"
+// @Router /restaurants/{id}/map/image [get]
+func GetRestaurantMapImage(w http.ResponseWriter, r *http.Request) {
+	opts, msg := parseStaticMapQuery(r.URL.Query())
+	if msg != "" {
+		apperrors.Error(w, msg, http.StatusBadRequest)
+		return
+	}
+	if !staticMaps.Enabled() {
+		apperrors.Error(w, "Map images are not available", http.StatusServiceUnavailable)
+		return
+	}
+	_, location, ok := locatedRestaurant(w, r)
+	if !ok {
+		return
+	}
+
+	opts.Location = location
+	image, contentType, err := staticMaps.StaticMap(r.Context(), opts)
+	if err != nil {
+		writeMapsError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Cache-Control", "public, max-age=86400")
+	w.Write(image)
+}
@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/nomdb/backend/internal/models"
+	"github.com/nomdb/backend/internal/services"
+	"github.com/pashagolub/pgxmock/v4"
+)
+
+// fakeStaticMaps returns a fixed image and remembers the options it was asked for
+type fakeStaticMaps struct {
+	enabled bool
+	opts    services.StaticMapOptions
+}
+
+func (f *fakeStaticMaps) Enabled() bool { return f.enabled }
+
+func (f *fakeStaticMaps) StaticMap(_ context.Context, opts services.StaticMapOptions) ([]byte, string, error) {
+	f.opts = opts
+	return []byte("png"), "image/png", nil
+}
+
+func withStaticMaps(t *testing.T, fake *fakeStaticMaps) {
+	t.Helper()
+	original := staticMaps
+	staticMaps = fake
+	t.Cleanup(func() { staticMaps = original })
+}
+
+func TestParseStaticMapQuery(t *testing.T) {
+	opts, msg := parseStaticMapQuery(url.Values{})
+	if msg != "" || opts != (services.StaticMapOptions{Width: 400, Height: 300, Zoom: 15, Scale: 1}) {
+		t.Errorf("Unexpected defaults %+v (%s)", opts, msg)
+	}
+
+	opts, msg = parseStaticMapQuery(url.Values{"width": {"640"}, "zoom": {"0"}, "scale": {"2"}})
+	if msg != "" || opts.Width != 640 || opts.Height != 300 || opts.Zoom != 0 || opts.Scale != 2 {
+		t.Errorf("Unexpected options %+v (%s)", opts, msg)
+	}
+
+	for _, params := range []url.Values{{"width": {"641"}}, {"height": {"0"}}, {"zoom": {"22"}}, {"scale": {"3"}}, {"width": {"wide"}}} {
+		if _, msg := parseStaticMapQuery(params); msg == "" {
+			t.Errorf("Expected %v to be rejected", params)
+		}
+	}
+}
+
+func TestMapDirections(t *testing.T) {
+	withPlacesProvider(t, &fakePlacesProvider{})
+	placeID := "place-a"
+	rest := &models.Restaurant{Name: "Luigi's", GooglePlaceID: &placeID}
+
+	directions := mapDirections(rest, "52.5,13.4")
+	if directions.Google != "https://www.google.com/maps/dir/?api=1&destination=52.5%2C13.4" {
+		t.Errorf("Unexpected Google link %s", directions.Google)
+	}
+	if directions.Apple != "https://maps.apple.com/?daddr=52.5%2C13.4&q=Luigi%27s" {
+		t.Errorf("Unexpected Apple link %s", directions.Apple)
+	}
+
+	// Google place IDs let Google Maps show the place itself
+	withPlacesProvider(t, mapsService)
+	directions = mapDirections(rest, "52.5,13.4")
+	if directions.Google != "https://www.google.com/maps/dir/?api=1&destination=52.5%2C13.4&destination_place_id=place-a" {
+		t.Errorf("Unexpected Google link %s", directions.Google)
+	}
+}
+
+func TestRestaurantLocation(t *testing.T) {
+	lat, lng, address := 52.5, 13.4, "Main St 1"
+	if got := restaurantLocation(&models.Restaurant{Latitude: &lat, Longitude: &lng, Address: &address}); got != "52.5,13.4" {
+		t.Errorf("Expected coordinates, got %q", got)
+	}
+	if got := restaurantLocation(&models.Restaurant{Address: &address}); got != address {
+		t.Errorf("Expected the address, got %q", got)
+	}
+	if got := restaurantLocation(&models.Restaurant{Latitude: &lat}); got != "" {
+		t.Errorf("Expected no location, got %q", got)
+	}
+}
+
+func expectRestaurantLocation(mock pgxmock.PgxPoolIface, lat, lng *float64) {
+	now := time.Now()
+	mock.ExpectQuery(`FROM restaurants r`).WithArgs(3).
+		WillReturnRows(pgxmock.NewRows(append(syncColumns, "c.id", "c.name", "avg_food", "avg_service", "avg_ambiance", "rating_count")).
+			AddRow(3, "Luigi's", nil, nil, nil, nil, lat, lng, nil, nil, now, now, nil, nil, nil, nil, 0.0, 0.0, 0.0, 0))
+	mock.ExpectQuery(`FROM food_types ft`).WithArgs(3).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "name", "created_at", "updated_at"}))
+}
+
+func restaurantMapRequest(path string) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	return mux.SetURLVars(req, map[string]string{"id": "3"})
+}
+
+func TestGetRestaurantMap(t *testing.T) {
+	mock := withMockRepositories(t)
+	withStaticMaps(t, &fakeStaticMaps{enabled: true})
+	lat, lng := 52.5, 13.4
+	expectRestaurantLocation(mock, &lat, &lng)
+
+	rec := httptest.NewRecorder()
+	GetRestaurantMap(rec, restaurantMapRequest("/api/restaurants/3/map?zoom=12"))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var result models.RestaurantMap
+	if err := json.NewDecoder(rec.Body).Decode(&result); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if result.ImageURL != "/api/restaurants/3/map/image?height=300&scale=1&width=400&zoom=12" || result.Directions.Apple == "" {
+		t.Errorf("Unexpected map %+v", result)
+	}
+}
+
+func TestGetRestaurantMap_NoLocation(t *testing.T) {
+	mock := withMockRepositories(t)
+	withStaticMaps(t, &fakeStaticMaps{enabled: true})
+	expectRestaurantLocation(mock, nil, nil)
+
+	rec := httptest.NewRecorder()
+	GetRestaurantMap(rec, restaurantMapRequest("/api/restaurants/3/map"))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
+
+func TestGetRestaurantMapImage(t *testing.T) {
+	mock := withMockRepositories(t)
+	fake := &fakeStaticMaps{enabled: true}
+	withStaticMaps(t, fake)
+	lat, lng := 52.5, 13.4
+	expectRestaurantLocation(mock, &lat, &lng)
+
+	rec := httptest.NewRecorder()
+	GetRestaurantMapImage(rec, restaurantMapRequest("/api/restaurants/3/map/image?scale=2"))
+	if rec.Code != http.StatusOK || rec.Body.String() != "png" || rec.Header().Get("Content-Type") != "image/png" {
+		t.Fatalf("Unexpected response %d %s: %s", rec.Code, rec.Header(), rec.Body.String())
+	}
+	if fake.opts.Location != "52.5,13.4" || fake.opts.Scale != 2 {
+		t.Errorf("Unexpected options %+v", fake.opts)
+	}
+	if rec.Header().Get("Cache-Control") == "" {
+		t.Error("Expected the image to be cacheable")
+	}
+}
+
+func TestGetRestaurantMapImage_Disabled(t *testing.T) {
+	withStaticMaps(t, &fakeStaticMaps{})
+
+	rec := httptest.NewRecorder()
+	GetRestaurantMapImage(rec, restaurantMapRequest("/api/restaurants/3/map/image"))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected 503, got %d", rec.Code)
+	}
+}
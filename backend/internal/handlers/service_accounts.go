@@ -0,0 +1,286 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"regexp"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/jackc/pgx/v5"
+	"github.com/nomdb/backend/internal/database"
+	apperrors "github.com/nomdb/backend/internal/errors"
+	"github.com/nomdb/backend/internal/logger"
+	"github.com/nomdb/backend/internal/models"
+)
+
+// serviceAccountEmailDomain gives service accounts a unique address that can never
+// receive mail (.invalid is reserved by RFC 2606), so no login provider can claim it
+const serviceAccountEmailDomain = "service-accounts.invalid"
+
+var serviceAccountNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{2,49}$`)
+
+const serviceAccountColumns = `u.id, u.username, u.full_name, u.is_admin, u.is_active, u.api_rate_limit,
+	(SELECT COUNT(*) FROM api_keys k WHERE k.user_id = u.id),
+	(SELECT MAX(k.last_used_at) FROM api_keys k WHERE k.user_id = u.id),
+	u.created_at`
+
+func scanServiceAccount(row interface{ Scan(...any) error }, account *models.ServiceAccount) error {
+	return row.Scan(&account.ID, &account.Name, &account.Description, &account.IsAdmin, &account.IsActive,
+		&account.RateLimit, &account.APIKeyCount, &account.LastUsedAt, &account.CreatedAt)
+}
+
+// serviceAccountFromRequest loads the service account of the {id} path parameter
+func serviceAccountFromRequest(w http.ResponseWriter, r *http.Request) (*models.User, bool) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		apperrors.Error(w, "Invalid service account ID", http.StatusBadRequest)
+		return nil, false
+	}
+
+	account, err := getUserByID(r.Context(), id)
+	if errors.Is(err, pgx.ErrNoRows) || (err == nil && !account.IsServiceAccount()) {
+		apperrors.Error(w, "Service account not found", http.StatusNotFound)
+		return nil, false
+	}
+	if err != nil {
+		logger.Error("Failed to fetch service account: %v", err)
+		apperrors.Error(w, "Internal server error", http.StatusInternalServerError)
+		return nil, false
+	}
+	return account, true
+}
+
+// @Summary Create a service account
+// @Description Create a non-interactive account for automation such as import scripts and bots. Service accounts have no password and cannot log in; they authenticate with API keys created under /admin/service-accounts/{id}/keys. Their actions appear under their own name in the audit log, and their requests are rate limited per account (SERVICE_ACCOUNT_RATE_LIMIT unless rate_limit is set). Admin only.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body models.CreateServiceAccountRequest true "Name, description, admin rights and rate limit"
+// @Success 201 {object} models.ServiceAccount
+// @Failure 400 {object} errors.ErrorResponse "Invalid request"
+// @Failure 409 {object} errors.ErrorResponse "Name already taken"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /admin/service-accounts [post]
+func CreateServiceAccount(w http.ResponseWriter, r *http.Request) {
+	if _, ok := requireSessionUser(w, r); !ok {
+		return
+	}
+
+	var req models.CreateServiceAccountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apperrors.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if !serviceAccountNamePattern.MatchString(req.Name) {
+		apperrors.Error(w, "Name must be 3-50 lowercase letters, digits, dashes or underscores", http.StatusBadRequest)
+		return
+	}
+	if len(req.Description) > 255 {
+		apperrors.Error(w, "Description must be at most 255 characters", http.StatusBadRequest)
+		return
+	}
+	if req.RateLimit != nil && *req.RateLimit <= 0 {
+		apperrors.Error(w, "rate_limit must be positive", http.StatusBadRequest)
+		return
+	}
+	var description *string
+	if req.Description != "" {
+		description = &req.Description
+	}
+
+	var account models.ServiceAccount
+	err := scanServiceAccount(database.GetPool().QueryRow(r.Context(),
+		`WITH u AS (
+			INSERT INTO users (email, username, provider, full_name, is_admin, email_verified, api_rate_limit)
+			VALUES ($1, $2, $3, $4, $5, false, $6)
+			RETURNING *
+		)
+		SELECT `+serviceAccountColumns+` FROM u`,
+		req.Name+"@"+serviceAccountEmailDomain, req.Name, models.ProviderServiceAccount,
+		description, req.IsAdmin, req.RateLimit), &account)
+	if err != nil {
+		if isDuplicateKeyError(err) {
+			apperrors.Error(w, "A user with this name already exists", http.StatusConflict)
+			return
+		}
+		logger.Error("Failed to create service account: %v", err)
+		apperrors.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	logger.Info("🤖 Service account %s (ID: %d) created", account.Name, account.ID)
+	recordAdminAction(r.Context(), r, "create_service_account", "user", account.ID,
+		map[string]any{"name": account.Name, "is_admin": account.IsAdmin})
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if err := json.NewEncoder(w).Encode(account); err != nil {
+		logger.Error("Failed to encode response: %v", err)
+	}
+}
+
+// @Summary List service accounts
+// @Description List service accounts with their number of API keys and when they were last used. Admin only.
+// @Tags Auth
+// @Produce json
+// @Success 200 {array} models.ServiceAccount
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /admin/service-accounts [get]
+func ListServiceAccounts(w http.ResponseWriter, r *http.Request) {
+	rows, err := database.GetPool().Query(r.Context(),
+		`SELECT `+serviceAccountColumns+` FROM users u WHERE u.provider = $1 ORDER BY u.username`,
+		models.ProviderServiceAccount)
+	if err != nil {
+		logger.Error("Failed to list service accounts: %v", err)
+		apperrors.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	defer rows.Close()
+
+	accounts := []models.ServiceAccount{}
+	for rows.Next() {
+		var account models.ServiceAccount
+		if err := scanServiceAccount(rows, &account); err != nil {
+			logger.Error("Failed to scan service account: %v", err)
+			apperrors.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+		accounts = append(accounts, account)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(accounts); err != nil {
+		logger.Error("Failed to encode response: %v", err)
+	}
+}
+
+// @Summary Disable a service account
+// @Description Disable a service account and delete all of its API keys. The account is kept so its contributions and audit log entries stay attributed. Admin only.
+// @Tags Auth
+// @Param id path int true "Service account ID"
+// @Success 204 "Service account disabled"
+// @Failure 400 {object} errors.ErrorResponse "Invalid service account ID"
+// @Failure 404 {object} errors.ErrorResponse "Service account not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /admin/service-accounts/{id} [delete]
+func DisableServiceAccount(w http.ResponseWriter, r *http.Request) {
+	if _, ok := requireSessionUser(w, r); !ok {
+		return
+	}
+	account, ok := serviceAccountFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	ctx := r.Context()
+	tx, err := database.GetPool().Begin(ctx)
+	if err != nil {
+		logger.Error("Failed to begin transaction: %v", err)
+		apperrors.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, "UPDATE users SET is_active = false, updated_at = NOW() WHERE id = $1", account.ID); err != nil {
+		logger.Error("Failed to disable service account: %v", err)
+		apperrors.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	result, err := tx.Exec(ctx, "DELETE FROM api_keys WHERE user_id = $1", account.ID)
+	if err != nil {
+		logger.Error("Failed to delete service account API keys: %v", err)
+		apperrors.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if err := tx.Commit(ctx); err != nil {
+		logger.Error("Failed to commit service account disabling: %v", err)
+		apperrors.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	logger.Info("🤖 Service account %s (ID: %d) disabled, %d API key(s) deleted", account.Username, account.ID, result.RowsAffected())
+	recordAdminAction(ctx, r, "disable_service_account", "user", account.ID,
+		map[string]any{"deleted_api_keys": result.RowsAffected()})
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Create a service account API key
+// @Description Generate an API key for a service account. The key is only returned in this response. The admin scope requires an admin service account. Admin only.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param id path int true "Service account ID"
+// @Param request body models.CreateAPIKeyRequest true "Key name, scopes, expiry and rate limit"
+// @Success 201 {object} models.CreateAPIKeyResponse
+// @Failure 400 {object} errors.ErrorResponse "Invalid request or disabled service account"
+// @Failure 403 {object} errors.ErrorResponse "Scope not allowed"
+// @Failure 404 {object} errors.ErrorResponse "Service account not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /admin/service-accounts/{id}/keys [post]
+func CreateServiceAccountAPIKey(w http.ResponseWriter, r *http.Request) {
+	if _, ok := requireSessionUser(w, r); !ok {
+		return
+	}
+	account, ok := serviceAccountFromRequest(w, r)
+	if !ok {
+		return
+	}
+	if !account.IsActive {
+		apperrors.Error(w, "Service account is disabled", http.StatusBadRequest)
+		return
+	}
+	createAPIKey(w, r, account)
+}
+
+// @Summary List service account API keys
+// @Description List the API keys of a service account. Admin only.
+// @Tags Auth
+// @Produce json
+// @Param id path int true "Service account ID"
+// @Success 200 {array} models.APIKey
+// @Failure 404 {object} errors.ErrorResponse "Service account not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /admin/service-accounts/{id}/keys [get]
+func ListServiceAccountAPIKeys(w http.ResponseWriter, r *http.Request) {
+	account, ok := serviceAccountFromRequest(w, r)
+	if !ok {
+		return
+	}
+	listAPIKeys(w, r, account.ID)
+}
+
+// @Summary Revoke a service account API key
+// @Description Permanently delete an API key of a service account. Admin only.
+// @Tags Auth
+// @Param id path int true "Service account ID"
+// @Param keyId path int true "API key ID"
+// @Success 204 "API key revoked"
+// @Failure 400 {object} errors.ErrorResponse "Invalid ID"
+// @Failure 404 {object} errors.ErrorResponse "Service account or API key not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /admin/service-accounts/{id}/keys/{keyId} [delete]
+func DeleteServiceAccountAPIKey(w http.ResponseWriter, r *http.Request) {
+	if _, ok := requireSessionUser(w, r); !ok {
+		return
+	}
+	account, ok := serviceAccountFromRequest(w, r)
+	if !ok {
+		return
+	}
+	keyID, err := strconv.Atoi(mux.Vars(r)["keyId"])
+	if err != nil {
+		apperrors.Error(w, "Invalid API key ID", http.StatusBadRequest)
+		return
+	}
+	deleteAPIKey(w, r, account, keyID)
+}
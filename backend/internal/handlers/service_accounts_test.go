@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/nomdb/backend/internal/models"
+)
+
+func TestServiceAccountNamePattern(t *testing.T) {
+	valid := []string{"importer", "yelp-sync", "bot_01"}
+	invalid := []string{"", "ab", "Importer", "-bot", "import bot", "bot@example.com", strings.Repeat("a", 51)}
+
+	for _, name := range valid {
+		if !serviceAccountNamePattern.MatchString(name) {
+			t.Errorf("Expected %q to be valid", name)
+		}
+	}
+	for _, name := range invalid {
+		if serviceAccountNamePattern.MatchString(name) {
+			t.Errorf("Expected %q to be invalid", name)
+		}
+	}
+}
+
+func TestCreateServiceAccountValidation(t *testing.T) {
+	admin := &models.User{ID: 1, Username: "admin", IsAdmin: true}
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"invalid JSON", `{`},
+		{"invalid name", `{"name": "Import Bot"}`},
+		{"long description", `{"name": "importer", "description": "` + strings.Repeat("x", 256) + `"}`},
+		{"non-positive rate limit", `{"name": "importer", "rate_limit": 0}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/admin/service-accounts", strings.NewReader(tt.body))
+			req = req.WithContext(context.WithValue(req.Context(), models.UserContextKey, admin))
+			rec := httptest.NewRecorder()
+			CreateServiceAccount(rec, req)
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("Expected 400, got %d", rec.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,432 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gorilla/mux"
+	apperrors "github.com/nomdb/backend/internal/errors"
+	"github.com/nomdb/backend/internal/events"
+	"github.com/nomdb/backend/internal/logger"
+	"github.com/nomdb/backend/internal/middleware"
+	"github.com/nomdb/backend/internal/models"
+	"github.com/nomdb/backend/internal/repository"
+)
+
+// Runtime settings admins can change without a redeploy
+const (
+	settingRateLimitAuth         = "rate_limit_auth"
+	settingRateLimitUpload       = "rate_limit_upload"
+	settingRateLimitRead         = "rate_limit_read"
+	settingRateLimitWrite        = "rate_limit_write"
+	settingRateLimitUser         = "rate_limit_user"
+	settingUploadMaxSizeMB       = "upload_max_size_mb"
+	settingPhotoDailyUploadLimit = "photo_daily_upload_limit"
+	settingRegistrationMode      = "registration_mode"
+	settingSuggestionsEnabled    = "suggestions_enabled"
+	settingPhotoUploadsEnabled   = "photo_uploads_enabled"
+	settingDebugLogRoutes        = "debug_log_routes"
+)
+
+// Setting types
+const (
+	settingTypeInt    = "int"
+	settingTypeBool   = "bool"
+	settingTypeString = "string"
+)
+
+// eventSettingsChanged makes the other instances reload the settings; it is also
+// streamed to clients, which may refetch /api/features
+const eventSettingsChanged = "settings.changed"
+
+// defaultUploadMaxSizeMB is the size limit of photos passing through the server; the
+// request body limit of 10MB bounds it
+const defaultUploadMaxSizeMB = 5
+
+// settingDefinition describes a runtime setting
+type settingDefinition struct {
+	key          string
+	kind         string
+	description  string
+	options      []string      // Allowed values of string settings
+	min, max     int           // Bounds of int settings
+	feature      bool          // A feature flag, listed at /api/features
+	defaultValue func() string // The startup configuration
+}
+
+var settingDefinitions = []*settingDefinition{
+	{key: settingRateLimitAuth, kind: settingTypeInt, max: 100000, defaultValue: rateLimitDefault(func(l middleware.RateLimits) int { return l.Auth }),
+		description: "Requests per minute per IP to login, registration, token refresh and password resets (0 = unlimited)"},
+	{key: settingRateLimitUpload, kind: settingTypeInt, max: 100000, defaultValue: rateLimitDefault(func(l middleware.RateLimits) int { return l.Upload }),
+		description: "Photo upload requests per minute per IP (0 = unlimited)"},
+	{key: settingRateLimitRead, kind: settingTypeInt, max: 100000, defaultValue: rateLimitDefault(func(l middleware.RateLimits) int { return l.Read }),
+		description: "GET requests per minute per IP (0 = unlimited)"},
+	{key: settingRateLimitWrite, kind: settingTypeInt, max: 100000, defaultValue: rateLimitDefault(func(l middleware.RateLimits) int { return l.Write }),
+		description: "Other requests per minute per IP (0 = unlimited)"},
+	{key: settingRateLimitUser, kind: settingTypeInt, max: 100000, defaultValue: rateLimitDefault(func(l middleware.RateLimits) int { return l.User }),
+		description: "Requests per minute per signed-in user across all of their IPs (0 = unlimited)"},
+	{key: settingUploadMaxSizeMB, kind: settingTypeInt, min: 1, max: 10, defaultValue: func() string { return strconv.Itoa(defaultUploadMaxSizeMB) },
+		description: "Maximum size of uploaded and imported photos in MB; direct uploads to cloud storage allow 25MB"},
+	{key: settingPhotoDailyUploadLimit, kind: settingTypeInt, max: 10000, defaultValue: func() string { return strconv.Itoa(photoDailyUploadLimit) },
+		description: "Photos a non-admin user may upload per 24 hours (0 = unlimited)"},
+	{key: settingRegistrationMode, kind: settingTypeString, options: []string{RegistrationOpen, RegistrationInvite, RegistrationClosed},
+		defaultValue: func() string { return registrationMode }, description: "Who may create accounts"},
+	{key: settingSuggestionsEnabled, kind: settingTypeBool, feature: true, defaultValue: func() string { return "true" },
+		description: "Users can suggest restaurants"},
+	{key: settingPhotoUploadsEnabled, kind: settingTypeBool, feature: true, defaultValue: func() string { return "true" },
+		description: "Users can upload photos"},
+	{key: settingDebugLogRoutes, kind: settingTypeString, defaultValue: func() string { return "" },
+		description: "Comma-separated routes whose request and response bodies are logged with secrets redacted, e.g. \"POST /api/restaurants, /api/ratings/{id}\" or * for all"},
+}
+
+var (
+	settingsMu       sync.RWMutex
+	settingOverrides = map[string]repository.StoredSetting{}
+	settingsInitOnce sync.Once
+
+	// Rate limits are applied to the limiter when they change instead of being read per request
+	settingsRateLimiter *middleware.RouteRateLimiter
+	startupRateLimits   middleware.RateLimits
+	appliedRateLimits   middleware.RateLimits
+)
+
+func rateLimitDefault(limit func(middleware.RateLimits) int) func() string {
+	return func() string { return strconv.Itoa(limit(startupRateLimits)) }
+}
+
+// InitSettings loads the settings admins changed and keeps them in sync with the other
+// instances. limits are the startup rate limits of rateLimiter.
+func InitSettings(rateLimiter *middleware.RouteRateLimiter, limits middleware.RateLimits) {
+	settingsInitOnce.Do(func() {
+		settingsRateLimiter = rateLimiter
+		startupRateLimits = limits
+		appliedRateLimits = limits
+
+		if err := reloadSettings(context.Background()); err != nil {
+			logger.Error("❌ Failed to load settings, using the startup configuration: %v", err)
+		}
+		events.Subscribe(func(e events.Event) {
+			if (e.Type == eventSettingsChanged && e.Remote) || e.Type == events.TypeResync {
+				go func() {
+					if err := reloadSettings(context.Background()); err != nil {
+						logger.Error("❌ Failed to reload settings: %v", err)
+					}
+				}()
+			}
+		})
+
+		settingsMu.RLock()
+		defer settingsMu.RUnlock()
+		logger.Info("⚙️  Runtime settings: %d of %d overridden", len(settingOverrides), len(settingDefinitions))
+	})
+}
+
+// reloadSettings replaces the cached overrides with those in the database
+func reloadSettings(ctx context.Context) error {
+	stored, err := repos.Settings.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	overrides := make(map[string]repository.StoredSetting, len(stored))
+	for _, s := range stored {
+		def := settingDefinitionOf(s.Key)
+		if def == nil {
+			continue // Removed in this version
+		}
+		if _, msg := def.parse(def.typed(s.Value)); msg != "" {
+			logger.Warn("⚠️  Ignoring invalid setting %s=%q: %s", s.Key, s.Value, msg)
+			continue
+		}
+		overrides[s.Key] = s
+	}
+
+	settingsMu.Lock()
+	settingOverrides = overrides
+	settingsMu.Unlock()
+	applySettings()
+	return nil
+}
+
+// applySettings hands the debug log routes to the logging middleware and changed rate
+// limits to the rate limiter
+func applySettings() {
+	middleware.SetDebugRoutes(strings.Split(settingValue(settingDebugLogRoutes), ","))
+	if settingsRateLimiter == nil {
+		return
+	}
+	limits := middleware.RateLimits{
+		Auth:   settingInt(settingRateLimitAuth),
+		Upload: settingInt(settingRateLimitUpload),
+		Read:   settingInt(settingRateLimitRead),
+		Write:  settingInt(settingRateLimitWrite),
+		User:   settingInt(settingRateLimitUser),
+	}
+	settingsMu.Lock()
+	changed := limits != appliedRateLimits
+	appliedRateLimits = limits
+	settingsMu.Unlock()
+	if changed {
+		settingsRateLimiter.SetLimits(limits)
+	}
+}
+
+func settingDefinitionOf(key string) *settingDefinition {
+	for _, def := range settingDefinitions {
+		if def.key == key {
+			return def
+		}
+	}
+	return nil
+}
+
+// settingValue returns the effective value of a setting
+func settingValue(key string) string {
+	settingsMu.RLock()
+	override, ok := settingOverrides[key]
+	settingsMu.RUnlock()
+	if ok {
+		return override.Value
+	}
+	return settingDefinitionOf(key).defaultValue()
+}
+
+func settingInt(key string) int {
+	n, _ := strconv.Atoi(settingValue(key))
+	return n
+}
+
+func settingBool(key string) bool {
+	return settingValue(key) == "true"
+}
+
+// requireFeature writes a 403 response and returns false if a feature flag is off
+func requireFeature(w http.ResponseWriter, key, message string) bool {
+	if settingBool(key) {
+		return true
+	}
+	apperrors.Error(w, message, http.StatusForbidden)
+	return false
+}
+
+// parse validates a JSON value for the setting, returning it as stored or a message for
+// the client
+func (def *settingDefinition) parse(value any) (string, string) {
+	switch def.kind {
+	case settingTypeInt:
+		n, ok := value.(float64)
+		if !ok || n != math.Trunc(n) || n < float64(def.min) || n > float64(def.max) {
+			return "", def.key + " must be an integer from " + strconv.Itoa(def.min) + " to " + strconv.Itoa(def.max)
+		}
+		return strconv.Itoa(int(n)), ""
+	case settingTypeBool:
+		b, ok := value.(bool)
+		if !ok {
+			return "", def.key + " must be true or false"
+		}
+		return strconv.FormatBool(b), ""
+	default:
+		s, ok := value.(string)
+		if len(def.options) == 0 {
+			if !ok || len(s) > 1000 {
+				return "", def.key + " must be a string of at most 1000 characters"
+			}
+			return s, ""
+		}
+		if !ok || !slices.Contains(def.options, s) {
+			return "", def.key + " must be one of: " + strings.Join(def.options, ", ")
+		}
+		return s, ""
+	}
+}
+
+// typed converts a stored value to its JSON type
+func (def *settingDefinition) typed(value string) any {
+	switch def.kind {
+	case settingTypeInt:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return value
+		}
+		return float64(n)
+	case settingTypeBool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return value
+		}
+		return b
+	default:
+		return value
+	}
+}
+
+// setting describes a setting with its effective value
+func (def *settingDefinition) setting() models.Setting {
+	s := models.Setting{
+		Key:         def.key,
+		Type:        def.kind,
+		Value:       def.typed(settingValue(def.key)),
+		Default:     def.typed(def.defaultValue()),
+		Description: def.description,
+		Options:     def.options,
+	}
+	if def.kind == settingTypeInt {
+		s.Min, s.Max = &def.min, &def.max
+	}
+
+	settingsMu.RLock()
+	override, ok := settingOverrides[def.key]
+	settingsMu.RUnlock()
+	if ok {
+		s.Overridden = true
+		s.UpdatedByUserID = override.UpdatedByUserID
+		s.UpdatedAt = &override.UpdatedAt
+	}
+	return s
+}
+
+// changeSetting caches a change made through this instance, applies it and tells the
+// other instances; a nil override resets the setting
+func changeSetting(ctx context.Context, r *http.Request, key string, override *repository.StoredSetting) {
+	settingsMu.Lock()
+	if override != nil {
+		settingOverrides[key] = *override
+	} else {
+		delete(settingOverrides, key)
+	}
+	settingsMu.Unlock()
+	applySettings()
+
+	details := map[string]any{"action": "reset_setting"}
+	if override != nil {
+		details = map[string]any{"action": "update_setting", "value": override.Value}
+	}
+	recordAudit(ctx, r, auditEvent{Event: AuditAdminAction, TargetType: "setting", TargetID: key, Details: details})
+
+	data, _ := json.Marshal(map[string]string{"key": key})
+	events.Publish(ctx, events.Event{Type: eventSettingsChanged, Data: data})
+}
+
+// @Summary List settings
+// @Description List the settings that can be changed at runtime (rate limits, upload limits, registration mode and feature flags) with their effective and default values. Admin only.
+// @Tags Settings
+// @Produce json
+// @Success 200 {array} models.Setting
+// @Security BearerAuth
+// @Router /admin/settings [get]
+func ListSettings(w http.ResponseWriter, r *http.Request) {
+	settings := make([]models.Setting, 0, len(settingDefinitions))
+	for _, def := range settingDefinitions {
+		settings = append(settings, def.setting())
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(settings)
+}
+
+// @Summary Change a setting
+// @Description Override a setting with a value of its type. The change applies to every server instance within moments and persists until the setting is reset. Admin only.
+// @Tags Settings
+// @Accept json
+// @Produce json
+// @Param key path string true "Setting key"
+// @Param setting body models.SettingRequest true "New value"
+// @Success 200 {object} models.Setting
+// @Failure 400 {object} errors.ErrorResponse "Invalid value"
+// @Failure 404 {object} errors.ErrorResponse "Unknown setting"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /admin/settings/{key} [put]
+func UpdateSetting(w http.ResponseWriter, r *http.Request) {
+	def := settingDefinitionOf(mux.Vars(r)["key"])
+	if def == nil {
+		apperrors.Error(w, "Unknown setting", http.StatusNotFound)
+		return
+	}
+
+	var req models.SettingRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apperrors.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	value, msg := def.parse(req.Value)
+	if msg != "" {
+		apperrors.Error(w, msg, http.StatusBadRequest)
+		return
+	}
+
+	var userID *int
+	if user, ok := GetUserFromContext(r); ok {
+		userID = &user.ID
+	}
+	ctx := r.Context()
+	stored, err := repos.Settings.Set(ctx, def.key, value, userID)
+	if err != nil {
+		apperrors.Internal(w, err)
+		return
+	}
+	changeSetting(ctx, r, def.key, &stored)
+	logger.Info("⚙️  Setting %s changed to %s", def.key, value)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(def.setting())
+}
+
+// @Summary Reset a setting
+// @Description Remove the override of a setting, so the startup configuration applies again on every server instance. Admin only.
+// @Tags Settings
+// @Produce json
+// @Param key path string true "Setting key"
+// @Success 200 {object} models.Setting
+// @Failure 404 {object} errors.ErrorResponse "Unknown or not overridden setting"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /admin/settings/{key} [delete]
+func ResetSetting(w http.ResponseWriter, r *http.Request) {
+	def := settingDefinitionOf(mux.Vars(r)["key"])
+	if def == nil {
+		apperrors.Error(w, "Unknown setting", http.StatusNotFound)
+		return
+	}
+
+	ctx := r.Context()
+	if err := repos.Settings.Delete(ctx, def.key); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			apperrors.Error(w, "Setting is not overridden", http.StatusNotFound)
+			return
+		}
+		apperrors.Internal(w, err)
+		return
+	}
+	changeSetting(ctx, r, def.key, nil)
+	logger.Info("⚙️  Setting %s reset to %s", def.key, def.defaultValue())
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(def.setting())
+}
+
+// @Summary Get feature flags
+// @Description Get which optional features are enabled, so clients can hide those that are not. A settings.changed live update event is sent when they change.
+// @Tags Settings
+// @Produce json
+// @Success 200 {object} map[string]bool
+// @Router /features [get]
+func GetFeatures(w http.ResponseWriter, r *http.Request) {
+	features := map[string]bool{}
+	for _, def := range settingDefinitions {
+		if def.feature {
+			features[def.key] = settingBool(def.key)
+		}
+	}
+
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(features)
+}
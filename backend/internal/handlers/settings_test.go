@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/nomdb/backend/internal/repository"
+	"github.com/pashagolub/pgxmock/v4"
+)
+
+// overrideSettings replaces the cached overrides for the duration of a test
+func overrideSettings(t *testing.T, values map[string]string) {
+	t.Helper()
+	overrides := map[string]repository.StoredSetting{}
+	for key, value := range values {
+		overrides[key] = repository.StoredSetting{Key: key, Value: value, UpdatedAt: time.Now()}
+	}
+	settingsMu.Lock()
+	settingOverrides = overrides
+	settingsMu.Unlock()
+	t.Cleanup(func() {
+		settingsMu.Lock()
+		settingOverrides = map[string]repository.StoredSetting{}
+		settingsMu.Unlock()
+	})
+}
+
+func TestParseSettingValue(t *testing.T) {
+	tests := []struct {
+		key     string
+		value   any
+		want    string
+		wantErr bool
+	}{
+		{settingUploadMaxSizeMB, 8.0, "8", false},
+		{settingUploadMaxSizeMB, 8.5, "", true},
+		{settingUploadMaxSizeMB, 0.0, "", true},
+		{settingUploadMaxSizeMB, 11.0, "", true},
+		{settingUploadMaxSizeMB, "8", "", true},
+		{settingRateLimitAuth, 0.0, "0", false},
+		{settingSuggestionsEnabled, false, "false", false},
+		{settingSuggestionsEnabled, "false", "", true},
+		{settingRegistrationMode, RegistrationInvite, RegistrationInvite, false},
+		{settingRegistrationMode, "everyone", "", true},
+		{settingDebugLogRoutes, "POST /api/restaurants", "POST /api/restaurants", false},
+		{settingDebugLogRoutes, 1.0, "", true},
+	}
+	for _, tt := range tests {
+		got, msg := settingDefinitionOf(tt.key).parse(tt.value)
+		if got != tt.want || (msg != "") != tt.wantErr {
+			t.Errorf("parse(%s, %v) = %q, %q; want %q, error %v", tt.key, tt.value, got, msg, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestSettingValueDefaults(t *testing.T) {
+	if got := maxUploadSize(); got != defaultUploadMaxSizeMB<<20 {
+		t.Errorf("Expected the default upload size, got %d", got)
+	}
+
+	overrideSettings(t, map[string]string{settingUploadMaxSizeMB: "8", settingRegistrationMode: RegistrationClosed})
+	if got := maxUploadSize(); got != 8<<20 {
+		t.Errorf("Expected the overridden upload size, got %d", got)
+	}
+	if got := settingValue(settingRegistrationMode); got != RegistrationClosed {
+		t.Errorf("Expected the overridden registration mode, got %s", got)
+	}
+}
+
+func TestReloadSettings(t *testing.T) {
+	mock := withMockRepositories(t)
+	overrideSettings(t, nil)
+
+	mock.ExpectQuery(`SELECT key, value, updated_by_user_id, updated_at FROM settings`).
+		WillReturnRows(pgxmock.NewRows([]string{"key", "value", "updated_by_user_id", "updated_at"}).
+			AddRow(settingSuggestionsEnabled, "false", (*int)(nil), time.Now()).
+			AddRow(settingUploadMaxSizeMB, "50", (*int)(nil), time.Now()).
+			AddRow("removed_setting", "1", (*int)(nil), time.Now()))
+
+	if err := reloadSettings(context.Background()); err != nil {
+		t.Fatalf("reloadSettings failed: %v", err)
+	}
+	if settingBool(settingSuggestionsEnabled) {
+		t.Error("Expected suggestions to be disabled")
+	}
+	if got := settingInt(settingUploadMaxSizeMB); got != defaultUploadMaxSizeMB {
+		t.Errorf("Expected the invalid override to be ignored, got %d", got)
+	}
+}
+
+func TestUpdateSettingValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		key  string
+		body string
+		want int
+	}{
+		{"unknown setting", "dark_mode", `{"value": true}`, http.StatusNotFound},
+		{"invalid JSON", settingSuggestionsEnabled, `{`, http.StatusBadRequest},
+		{"wrong type", settingSuggestionsEnabled, `{"value": "yes"}`, http.StatusBadRequest},
+		{"missing value", settingRateLimitRead, `{}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPut, "/api/admin/settings/"+tt.key, strings.NewReader(tt.body))
+			req = mux.SetURLVars(req, map[string]string{"key": tt.key})
+			rec := httptest.NewRecorder()
+			UpdateSetting(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("Expected %d, got %d", tt.want, rec.Code)
+			}
+		})
+	}
+}
+
+func TestResetSettingNotOverridden(t *testing.T) {
+	mock := withMockRepositories(t)
+	mock.ExpectExec(`DELETE FROM settings WHERE key = \$1`).WithArgs(settingRateLimitRead).
+		WillReturnResult(pgxmock.NewResult("DELETE", 0))
+
+	req := httptest.NewRequest(http.MethodDelete, "/api/admin/settings/"+settingRateLimitRead, nil)
+	req = mux.SetURLVars(req, map[string]string{"key": settingRateLimitRead})
+	rec := httptest.NewRecorder()
+	ResetSetting(rec, req)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404, got %d", rec.Code)
+	}
+}
+
+func TestFeatureFlags(t *testing.T) {
+	overrideSettings(t, map[string]string{settingSuggestionsEnabled: "false"})
+
+	rec := httptest.NewRecorder()
+	GetFeatures(rec, httptest.NewRequest(http.MethodGet, "/api/features", nil))
+	var features map[string]bool
+	if err := json.NewDecoder(rec.Body).Decode(&features); err != nil {
+		t.Fatal(err)
+	}
+	if features[settingSuggestionsEnabled] || !features[settingPhotoUploadsEnabled] || len(features) != 2 {
+		t.Errorf("Unexpected features %v", features)
+	}
+
+	rec = httptest.NewRecorder()
+	CreateSuggestion(rec, httptest.NewRequest(http.MethodPost, "/api/suggestions", strings.NewReader(`{"name": "Luigi's"}`)))
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("Expected disabled suggestions to be rejected with 403, got %d", rec.Code)
+	}
+}
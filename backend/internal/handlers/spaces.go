@@ -0,0 +1,776 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/nomdb/backend/internal/auth"
+	apperrors "github.com/nomdb/backend/internal/errors"
+	"github.com/nomdb/backend/internal/logger"
+	"github.com/nomdb/backend/internal/middleware"
+	"github.com/nomdb/backend/internal/models"
+	"github.com/nomdb/backend/internal/repository"
+)
+
+// SpaceHeader selects the space of a request by its slug. EventSource and image URLs,
+// which can't send headers, use the space query parameter instead.
+const SpaceHeader = "X-Space"
+
+var spaceSlugPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{1,49}$`)
+
+// defaultSpace is open to all users and needs no lookup; its members and settings are
+// managed as those of the instance
+var defaultSpace = models.Space{ID: repository.DefaultSpaceID, Slug: "default", Name: "Default", Public: true}
+
+type spaceContextKey struct{}
+
+// spaceRoleRank orders the roles of space members by the rights they grant
+var spaceRoleRank = map[string]int{
+	repository.SpaceRoleMember: 1,
+	repository.SpaceRoleAdmin:  2,
+	repository.SpaceRoleOwner:  3,
+}
+
+// spaceEntities are the route prefixes addressing a restaurant, suggestion, rating or
+// photo by ID, which must belong to the space of the request
+var spaceEntities = []struct {
+	prefix, entity, notFound string
+}{
+	{"/api/restaurants/", repository.SpaceOfRestaurant, "Restaurant not found"},
+	{"/api/suggestions/", repository.SpaceOfSuggestion, "Suggestion not found"},
+	{"/api/ratings/", repository.SpaceOfRating, "Rating not found"},
+	{"/api/photos/", repository.SpaceOfPhoto, "Photo not found"},
+}
+
+// currentSpace returns the space set by SpaceMiddleware, or the default space
+func currentSpace(r *http.Request) *models.Space {
+	if space, ok := r.Context().Value(spaceContextKey{}).(*models.Space); ok {
+		return space
+	}
+	return &defaultSpace
+}
+
+// spaceID returns the ID of the space of the request
+func spaceID(r *http.Request) int {
+	return currentSpace(r).ID
+}
+
+// spaceRole returns the role of the user in the space: owner for admins of the instance,
+// and "" for users who aren't members
+func spaceRole(user *models.User, space *models.Space) string {
+	switch {
+	case user == nil:
+		return ""
+	case user.IsAdmin:
+		return repository.SpaceRoleOwner
+	case space.Role != nil:
+		return *space.Role
+	}
+	return ""
+}
+
+// canReadSpace reports whether the user may see the space and its restaurants
+func canReadSpace(user *models.User, space *models.Space) bool {
+	return space.Public || spaceRole(user, space) != ""
+}
+
+// findSpace looks up the space with the slug and the user's role in it
+func findSpace(ctx context.Context, slug string, user *models.User) (*models.Space, error) {
+	if slug == defaultSpace.Slug {
+		space := defaultSpace
+		return &space, nil
+	}
+	userID := 0
+	if user != nil {
+		userID = user.ID
+	}
+	return repos.Spaces.Get(ctx, slug, userID)
+}
+
+// SpaceMiddleware sets the space of the request, chosen with the X-Space header or the
+// space query parameter, and the default space otherwise. Private spaces are hidden from
+// non-members, and only members may change a space other than the default one. Requests
+// for a restaurant, suggestion, rating or photo of another space get 404.
+func SpaceMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		slug := r.Header.Get(SpaceHeader)
+		if slug == "" {
+			slug = r.URL.Query().Get("space")
+		}
+		user, _ := GetUserFromContext(r)
+
+		space := &defaultSpace
+		if slug != "" {
+			var err error
+			space, err = findSpace(r.Context(), slug, user)
+			if errors.Is(err, repository.ErrNotFound) || (err == nil && !canReadSpace(user, space)) {
+				apperrors.Error(w, "Space not found", http.StatusNotFound)
+				return
+			}
+			if err != nil {
+				apperrors.Internal(w, err)
+				return
+			}
+		}
+		if r.Method != http.MethodGet && r.Method != http.MethodHead &&
+			space.ID != repository.DefaultSpaceID && spaceRole(user, space) == "" {
+			apperrors.Error(w, "Only members can change this space", http.StatusForbidden)
+			return
+		}
+
+		if !entityInSpace(w, r, space.ID) {
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), spaceContextKey{}, space)))
+	})
+}
+
+// entityInSpace checks that the restaurant, suggestion, rating or photo the route
+// addresses belongs to the space, writing 404 otherwise. Missing entities are left to
+// the handler.
+func entityInSpace(w http.ResponseWriter, r *http.Request, spaceID int) bool {
+	route := mux.CurrentRoute(r)
+	if route == nil {
+		return true
+	}
+	template, err := route.GetPathTemplate()
+	if err != nil {
+		return true
+	}
+	vars := mux.Vars(r)
+	idStr := vars["restaurantId"]
+	if idStr == "" {
+		idStr = vars["id"]
+	}
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		return true
+	}
+
+	for _, e := range spaceEntities {
+		if !strings.HasPrefix(template, e.prefix) {
+			continue
+		}
+		entitySpace, err := repos.Spaces.SpaceOf(r.Context(), e.entity, id)
+		if errors.Is(err, repository.ErrNotFound) {
+			return true
+		}
+		if err != nil {
+			apperrors.Internal(w, err)
+			return false
+		}
+		if entitySpace != spaceID {
+			apperrors.Error(w, e.notFound, http.StatusNotFound)
+			return false
+		}
+		return true
+	}
+	return true
+}
+
+// isSpaceAdmin reports whether the user is an admin of the instance, or an owner or
+// admin of the space of the request. Requests with an API key need its admin scope too.
+func isSpaceAdmin(r *http.Request, user *models.User) bool {
+	if apiKey, ok := middleware.GetAPIKeyFromRequest(r); ok && !auth.HasScope(apiKey.Scopes, auth.ScopeAdmin) {
+		return false
+	}
+	return spaceRoleRank[spaceRole(user, currentSpace(r))] >= spaceRoleRank[repository.SpaceRoleAdmin]
+}
+
+// SpaceAdminMiddleware allows admins of the instance, and owners and admins of the space
+// of the request
+func SpaceAdminMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, ok := GetUserFromContext(r)
+		if !ok {
+			apperrors.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+		if apiKey, ok := middleware.GetAPIKeyFromRequest(r); ok && !auth.HasScope(apiKey.Scopes, auth.ScopeAdmin) {
+			apperrors.Error(w, "Forbidden - API key lacks the admin scope", http.StatusForbidden)
+			return
+		}
+		if !isSpaceAdmin(r, user) {
+			apperrors.Error(w, "Forbidden - space admin access required", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// spaceFromPath loads the space of the {slug} path parameter with the user's role,
+// writing 404 if the user may not see it
+func spaceFromPath(w http.ResponseWriter, r *http.Request) (*models.Space, bool) {
+	user, _ := GetUserFromContext(r)
+	space, err := findSpace(r.Context(), mux.Vars(r)["slug"], user)
+	if errors.Is(err, repository.ErrNotFound) || (err == nil && !canReadSpace(user, space)) {
+		apperrors.Error(w, "Space not found", http.StatusNotFound)
+		return nil, false
+	}
+	if err != nil {
+		apperrors.Internal(w, err)
+		return nil, false
+	}
+	return space, true
+}
+
+// managedSpaceFromPath loads the space of the {slug} path parameter for a change that
+// needs at least minRole in it. The default space is managed as the instance.
+func managedSpaceFromPath(w http.ResponseWriter, r *http.Request, minRole string) (*models.Space, *models.User, bool) {
+	user, ok := GetUserFromContext(r)
+	if !ok {
+		apperrors.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return nil, nil, false
+	}
+	space, ok := spaceFromPath(w, r)
+	if !ok {
+		return nil, nil, false
+	}
+	if space.ID == repository.DefaultSpaceID {
+		apperrors.Error(w, "The default space is open to all users and cannot be changed", http.StatusBadRequest)
+		return nil, nil, false
+	}
+	if spaceRoleRank[spaceRole(user, space)] < spaceRoleRank[minRole] {
+		apperrors.Error(w, "Forbidden - space "+minRole+" access required", http.StatusForbidden)
+		return nil, nil, false
+	}
+	return space, user, true
+}
+
+// @Summary List spaces
+// @Description List the public spaces and those the user is a member of, with the user's role. Admins see all spaces. Requests choose a space with the X-Space header (or the space query parameter); without it they use the default space.
+// @Tags Spaces
+// @Produce json
+// @Success 200 {array} models.Space
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /spaces [get]
+func ListSpaces(w http.ResponseWriter, r *http.Request) {
+	userID, all := 0, false
+	if user, ok := GetUserFromContext(r); ok {
+		userID, all = user.ID, user.IsAdmin
+	}
+	spaces, err := repos.Spaces.List(r.Context(), userID, all)
+	if err != nil {
+		apperrors.Internal(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(spaces)
+}
+
+// @Summary Create a space
+// @Description Create a space with its own restaurants, suggestions, ratings and photos. The creator becomes its owner. Private spaces are only visible to their members.
+// @Tags Spaces
+// @Accept json
+// @Produce json
+// @Param request body models.CreateSpaceRequest true "Slug, name and visibility"
+// @Success 201 {object} models.Space
+// @Failure 400 {object} errors.ErrorResponse "Invalid request"
+// @Failure 409 {object} errors.ErrorResponse "Slug already taken"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /spaces [post]
+func CreateSpace(w http.ResponseWriter, r *http.Request) {
+	user, ok := GetUserFromContext(r)
+	if !ok {
+		apperrors.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.CreateSpaceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apperrors.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	if !spaceSlugPattern.MatchString(req.Slug) {
+		apperrors.Error(w, "Slug must be 2-50 lowercase letters, digits or dashes", http.StatusBadRequest)
+		return
+	}
+	if req.Name == "" || len(req.Name) > 100 {
+		apperrors.Error(w, "Name must be 1-100 characters", http.StatusBadRequest)
+		return
+	}
+
+	space, err := repos.Spaces.Create(r.Context(), req, user.ID)
+	if err != nil {
+		if isDuplicateKeyError(err) {
+			apperrors.Error(w, "A space with this slug already exists", http.StatusConflict)
+			return
+		}
+		apperrors.Internal(w, err)
+		return
+	}
+
+	logger.Info("🏘️  Space %s (ID: %d) created by %s", space.Slug, space.ID, user.Username)
+	recordAdminAction(r.Context(), r, "create_space", "space", space.ID,
+		map[string]any{"slug": space.Slug, "public": space.Public})
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(space)
+}
+
+// @Summary Get a space
+// @Description Get a space with the user's role in it. Private spaces are only visible to their members and admins.
+// @Tags Spaces
+// @Produce json
+// @Param slug path string true "Space slug"
+// @Success 200 {object} models.Space
+// @Failure 404 {object} errors.ErrorResponse "Space not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /spaces/{slug} [get]
+func GetSpace(w http.ResponseWriter, r *http.Request) {
+	space, ok := spaceFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(space)
+}
+
+// @Summary Update a space
+// @Description Rename a space or change its visibility. Space owners and admins only.
+// @Tags Spaces
+// @Accept json
+// @Produce json
+// @Param slug path string true "Space slug"
+// @Param request body models.UpdateSpaceRequest true "Fields to change"
+// @Success 200 {object} models.Space
+// @Failure 400 {object} errors.ErrorResponse "Invalid request, or the default space"
+// @Failure 403 {object} errors.ErrorResponse "Space admin access required"
+// @Failure 404 {object} errors.ErrorResponse "Space not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /spaces/{slug} [put]
+func UpdateSpace(w http.ResponseWriter, r *http.Request) {
+	space, _, ok := managedSpaceFromPath(w, r, repository.SpaceRoleAdmin)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateSpaceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apperrors.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" || len(name) > 100 {
+			apperrors.Error(w, "Name must be 1-100 characters", http.StatusBadRequest)
+			return
+		}
+		req.Name = &name
+	}
+
+	updated, err := repos.Spaces.Update(r.Context(), space.ID, req)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			apperrors.Error(w, "Space not found", http.StatusNotFound)
+			return
+		}
+		apperrors.Internal(w, err)
+		return
+	}
+	updated.Role = space.Role
+
+	recordAdminAction(r.Context(), r, "update_space", "space", space.ID,
+		map[string]any{"name": updated.Name, "public": updated.Public})
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updated)
+}
+
+// @Summary Delete a space
+// @Description Delete a space with its restaurants, suggestions, ratings, photos, members and invites. The default space cannot be deleted. Space owners only.
+// @Tags Spaces
+// @Param slug path string true "Space slug"
+// @Success 204 "Space deleted"
+// @Failure 400 {object} errors.ErrorResponse "Not possible for the default space"
+// @Failure 403 {object} errors.ErrorResponse "Space owner access required"
+// @Failure 404 {object} errors.ErrorResponse "Space not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /spaces/{slug} [delete]
+func DeleteSpace(w http.ResponseWriter, r *http.Request) {
+	space, user, ok := managedSpaceFromPath(w, r, repository.SpaceRoleOwner)
+	if !ok {
+		return
+	}
+
+	ctx := r.Context()
+	if err := repos.Spaces.Delete(ctx, space.ID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			apperrors.Error(w, "Space not found", http.StatusNotFound)
+			return
+		}
+		apperrors.Internal(w, err)
+		return
+	}
+
+	logger.Info("🏘️  Space %s (ID: %d) deleted by %s", space.Slug, space.ID, user.Username)
+	recordAdminAction(ctx, r, "delete_space", "space", space.ID, map[string]any{"slug": space.Slug})
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary List the members of a space
+// @Description List the members of a space with their roles. Members of the space only.
+// @Tags Spaces
+// @Produce json
+// @Param slug path string true "Space slug"
+// @Success 200 {array} models.SpaceMember
+// @Failure 400 {object} errors.ErrorResponse "Not possible for the default space"
+// @Failure 403 {object} errors.ErrorResponse "Space member access required"
+// @Failure 404 {object} errors.ErrorResponse "Space not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /spaces/{slug}/members [get]
+func ListSpaceMembers(w http.ResponseWriter, r *http.Request) {
+	space, _, ok := managedSpaceFromPath(w, r, repository.SpaceRoleMember)
+	if !ok {
+		return
+	}
+
+	members, err := repos.Spaces.Members(r.Context(), space.ID)
+	if err != nil {
+		apperrors.Internal(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(members)
+}
+
+// @Summary Add or change a member of a space
+// @Description Add a user to a space, or change their role (owner, admin or member). Space owners and admins only; only owners may grant or take away the owner role. A space keeps at least one owner.
+// @Tags Spaces
+// @Accept json
+// @Produce json
+// @Param slug path string true "Space slug"
+// @Param userId path int true "User ID"
+// @Param request body models.SpaceMemberRequest true "Role"
+// @Success 200 {object} models.SpaceMember
+// @Failure 400 {object} errors.ErrorResponse "Invalid request, or the default space"
+// @Failure 403 {object} errors.ErrorResponse "Space admin access required"
+// @Failure 404 {object} errors.ErrorResponse "Space or user not found"
+// @Failure 409 {object} errors.ErrorResponse "The space needs an owner"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /spaces/{slug}/members/{userId} [put]
+func SetSpaceMember(w http.ResponseWriter, r *http.Request) {
+	space, user, ok := managedSpaceFromPath(w, r, repository.SpaceRoleAdmin)
+	if !ok {
+		return
+	}
+	userID, err := strconv.Atoi(mux.Vars(r)["userId"])
+	if err != nil {
+		apperrors.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.SpaceMemberRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apperrors.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	if _, ok := spaceRoleRank[req.Role]; !ok {
+		apperrors.Error(w, "Role must be owner, admin or member", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	if spaceRole(user, space) != repository.SpaceRoleOwner {
+		current, err := memberRole(ctx, space.ID, userID)
+		if err != nil {
+			apperrors.Internal(w, err)
+			return
+		}
+		if req.Role == repository.SpaceRoleOwner || current == repository.SpaceRoleOwner {
+			apperrors.Error(w, "Forbidden - only owners may change owners", http.StatusForbidden)
+			return
+		}
+	}
+
+	member, err := repos.Spaces.SetMember(ctx, space.ID, userID, req.Role)
+	if err != nil {
+		if !writeSpaceMemberError(w, err, "User not found") {
+			apperrors.Internal(w, err)
+		}
+		return
+	}
+
+	recordAdminAction(ctx, r, "set_space_member", "space", space.ID,
+		map[string]any{"user_id": userID, "role": req.Role})
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(member)
+}
+
+// @Summary Remove a member from a space
+// @Description Remove a user from a space. Members may leave a space; removing others needs a space owner or admin, and removing owners a space owner. A space keeps at least one owner.
+// @Tags Spaces
+// @Param slug path string true "Space slug"
+// @Param userId path int true "User ID"
+// @Success 204 "Member removed"
+// @Failure 400 {object} errors.ErrorResponse "Invalid user ID, or the default space"
+// @Failure 403 {object} errors.ErrorResponse "Space admin access required"
+// @Failure 404 {object} errors.ErrorResponse "Space or member not found"
+// @Failure 409 {object} errors.ErrorResponse "The space needs an owner"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /spaces/{slug}/members/{userId} [delete]
+func RemoveSpaceMember(w http.ResponseWriter, r *http.Request) {
+	space, user, ok := managedSpaceFromPath(w, r, repository.SpaceRoleMember)
+	if !ok {
+		return
+	}
+	userID, err := strconv.Atoi(mux.Vars(r)["userId"])
+	if err != nil {
+		apperrors.Error(w, "Invalid user ID", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	if role := spaceRole(user, space); userID != user.ID && role != repository.SpaceRoleOwner {
+		current, err := memberRole(ctx, space.ID, userID)
+		if err != nil {
+			apperrors.Internal(w, err)
+			return
+		}
+		if role != repository.SpaceRoleAdmin || current == repository.SpaceRoleOwner {
+			apperrors.Error(w, "Forbidden - space admin access required", http.StatusForbidden)
+			return
+		}
+	}
+
+	if err := repos.Spaces.RemoveMember(ctx, space.ID, userID); err != nil {
+		if !writeSpaceMemberError(w, err, "Member not found") {
+			apperrors.Internal(w, err)
+		}
+		return
+	}
+
+	recordAdminAction(ctx, r, "remove_space_member", "space", space.ID, map[string]any{"user_id": userID})
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// memberRole returns the role of a user in a space, or "" if they are not a member
+func memberRole(ctx context.Context, spaceID, userID int) (string, error) {
+	members, err := repos.Spaces.Members(ctx, spaceID)
+	if err != nil {
+		return "", err
+	}
+	for _, m := range members {
+		if m.UserID == userID {
+			return m.Role, nil
+		}
+	}
+	return "", nil
+}
+
+// writeSpaceMemberError writes the response to ErrNotFound and ErrLastOwner, reporting
+// whether err was one of them
+func writeSpaceMemberError(w http.ResponseWriter, err error, notFound string) bool {
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		apperrors.Error(w, notFound, http.StatusNotFound)
+	case errors.Is(err, repository.ErrLastOwner):
+		apperrors.Error(w, "The space needs an owner; make another member owner first", http.StatusConflict)
+	default:
+		return false
+	}
+	return true
+}
+
+// @Summary Create a space invite
+// @Description Create an invite code for joining a space with a role (admin or member). The code is only returned in this response; only its hash is stored. Space owners and admins only.
+// @Tags Spaces
+// @Accept json
+// @Produce json
+// @Param slug path string true "Space slug"
+// @Param request body models.CreateSpaceInviteRequest true "Role, maximum uses and expiry"
+// @Success 201 {object} models.CreateSpaceInviteResponse
+// @Failure 400 {object} errors.ErrorResponse "Invalid request, or the default space"
+// @Failure 403 {object} errors.ErrorResponse "Space admin access required"
+// @Failure 404 {object} errors.ErrorResponse "Space not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /spaces/{slug}/invites [post]
+func CreateSpaceInvite(w http.ResponseWriter, r *http.Request) {
+	space, user, ok := managedSpaceFromPath(w, r, repository.SpaceRoleAdmin)
+	if !ok {
+		return
+	}
+
+	var req models.CreateSpaceInviteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apperrors.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	invite := models.SpaceInvite{SpaceID: space.ID, Role: repository.SpaceRoleMember, MaxUses: 1, CreatedByUserID: &user.ID}
+	if req.Role != "" {
+		if req.Role != repository.SpaceRoleAdmin && req.Role != repository.SpaceRoleMember {
+			apperrors.Error(w, "Role must be admin or member", http.StatusBadRequest)
+			return
+		}
+		invite.Role = req.Role
+	}
+	if req.MaxUses != nil {
+		if *req.MaxUses <= 0 {
+			apperrors.Error(w, "max_uses must be positive", http.StatusBadRequest)
+			return
+		}
+		invite.MaxUses = *req.MaxUses
+	}
+	expiryDays := defaultInviteExpiryDays
+	if req.ExpiresInDays != nil {
+		if *req.ExpiresInDays < 0 {
+			apperrors.Error(w, "expires_in_days must not be negative", http.StatusBadRequest)
+			return
+		}
+		expiryDays = *req.ExpiresInDays
+	}
+	if expiryDays > 0 {
+		t := time.Now().AddDate(0, 0, expiryDays)
+		invite.ExpiresAt = &t
+	}
+
+	code, err := generateInviteCode()
+	if err != nil {
+		apperrors.Internal(w, err)
+		return
+	}
+	invite.CodePrefix = code[:inviteCodePrefixLength]
+
+	created, err := repos.Spaces.CreateInvite(r.Context(), invite, hashInviteCode(code))
+	if err != nil {
+		apperrors.Internal(w, err)
+		return
+	}
+
+	logger.Info("🏘️  Invite %d (%s) to space %s created by %s", created.ID, created.CodePrefix, space.Slug, user.Username)
+	recordAdminAction(r.Context(), r, "create_space_invite", "space", space.ID,
+		map[string]any{"invite_id": created.ID, "prefix": created.CodePrefix, "role": created.Role})
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(models.CreateSpaceInviteResponse{Code: code, Invite: *created})
+}
+
+// @Summary List space invites
+// @Description List the invites of a space with their usage. Space owners and admins only.
+// @Tags Spaces
+// @Produce json
+// @Param slug path string true "Space slug"
+// @Success 200 {array} models.SpaceInvite
+// @Failure 400 {object} errors.ErrorResponse "Not possible for the default space"
+// @Failure 403 {object} errors.ErrorResponse "Space admin access required"
+// @Failure 404 {object} errors.ErrorResponse "Space not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /spaces/{slug}/invites [get]
+func ListSpaceInvites(w http.ResponseWriter, r *http.Request) {
+	space, _, ok := managedSpaceFromPath(w, r, repository.SpaceRoleAdmin)
+	if !ok {
+		return
+	}
+
+	invites, err := repos.Spaces.Invites(r.Context(), space.ID)
+	if err != nil {
+		apperrors.Internal(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(invites)
+}
+
+// @Summary Revoke a space invite
+// @Description Revoke an invite so it can no longer be used. Members who joined with it are kept. Space owners and admins only.
+// @Tags Spaces
+// @Param slug path string true "Space slug"
+// @Param id path int true "Invite ID"
+// @Success 204 "Invite revoked"
+// @Failure 400 {object} errors.ErrorResponse "Invalid invite ID, or the default space"
+// @Failure 403 {object} errors.ErrorResponse "Space admin access required"
+// @Failure 404 {object} errors.ErrorResponse "Space or invite not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /spaces/{slug}/invites/{id} [delete]
+func RevokeSpaceInvite(w http.ResponseWriter, r *http.Request) {
+	space, _, ok := managedSpaceFromPath(w, r, repository.SpaceRoleAdmin)
+	if !ok {
+		return
+	}
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		apperrors.Error(w, "Invalid invite ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := repos.Spaces.RevokeInvite(r.Context(), space.ID, id); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			apperrors.Error(w, "Invite not found", http.StatusNotFound)
+			return
+		}
+		apperrors.Internal(w, err)
+		return
+	}
+
+	recordAdminAction(r.Context(), r, "revoke_space_invite", "space", space.ID, map[string]any{"invite_id": id})
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary Join a space
+// @Description Join a space with an invite code, getting the role of the invite.
+// @Tags Spaces
+// @Accept json
+// @Produce json
+// @Param request body models.JoinSpaceRequest true "Invite code"
+// @Success 200 {object} models.Space
+// @Failure 400 {object} errors.ErrorResponse "Invalid request"
+// @Failure 404 {object} errors.ErrorResponse "Invalid or expired invite"
+// @Failure 409 {object} errors.ErrorResponse "Already a member of the space"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /spaces/join [post]
+func JoinSpace(w http.ResponseWriter, r *http.Request) {
+	user, ok := GetUserFromContext(r)
+	if !ok {
+		apperrors.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.JoinSpaceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Code == "" {
+		apperrors.Error(w, "An invite code is required", http.StatusBadRequest)
+		return
+	}
+
+	space, err := repos.Spaces.Join(r.Context(), hashInviteCode(req.Code), user.ID)
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			apperrors.Error(w, "Invalid or expired invite", http.StatusNotFound)
+		case errors.Is(err, repository.ErrAlreadyMember):
+			apperrors.Error(w, "Already a member of this space", http.StatusConflict)
+		default:
+			apperrors.Internal(w, err)
+		}
+		return
+	}
+
+	logger.Info("🏘️  %s joined space %s as %s", user.Username, space.Slug, *space.Role)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(space)
+}
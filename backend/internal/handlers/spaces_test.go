@@ -0,0 +1,218 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/nomdb/backend/internal/models"
+	"github.com/nomdb/backend/internal/repository"
+	"github.com/pashagolub/pgxmock/v4"
+)
+
+var spaceColumns = []string{"id", "slug", "name", "public", "created_by_user_id", "created_at", "updated_at", "role"}
+
+// serveInSpace routes a request through SpaceMiddleware to a handler answering with the
+// ID of the space it got
+func serveInSpace(method, template, path, space string, user *models.User) *httptest.ResponseRecorder {
+	router := mux.NewRouter()
+	api := router.PathPrefix("/api").Subrouter()
+	api.Use(SpaceMiddleware)
+	api.HandleFunc(template, func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(spaceID(r))
+	}).Methods(method)
+
+	req := httptest.NewRequest(method, path, nil)
+	if space != "" {
+		req.Header.Set(SpaceHeader, space)
+	}
+	if user != nil {
+		req = req.WithContext(context.WithValue(req.Context(), models.UserContextKey, user))
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestSpaceMiddleware(t *testing.T) {
+	now := time.Now()
+	member := repository.SpaceRoleMember
+	alice := &models.User{ID: 7, Username: "alice"}
+
+	t.Run("default space without a header", func(t *testing.T) {
+		withMockRepositories(t)
+		rec := serveInSpace(http.MethodPost, "/restaurants", "/api/restaurants", "", alice)
+		if rec.Code != http.StatusOK || strings.TrimSpace(rec.Body.String()) != "1" {
+			t.Errorf("Expected the default space, got %d %s", rec.Code, rec.Body)
+		}
+	})
+
+	t.Run("private space hidden from non-members", func(t *testing.T) {
+		mock := withMockRepositories(t)
+		mock.ExpectQuery(`FROM spaces s`).WithArgs("office", 7).
+			WillReturnRows(pgxmock.NewRows(spaceColumns).AddRow(2, "office", "Office", false, (*int)(nil), now, now, (*string)(nil)))
+
+		if rec := serveInSpace(http.MethodGet, "/restaurants", "/api/restaurants", "office", alice); rec.Code != http.StatusNotFound {
+			t.Errorf("Expected 404, got %d", rec.Code)
+		}
+	})
+
+	t.Run("member of a private space", func(t *testing.T) {
+		mock := withMockRepositories(t)
+		mock.ExpectQuery(`FROM spaces s`).WithArgs("office", 7).
+			WillReturnRows(pgxmock.NewRows(spaceColumns).AddRow(2, "office", "Office", false, (*int)(nil), now, now, &member))
+
+		rec := serveInSpace(http.MethodPost, "/restaurants", "/api/restaurants", "office", alice)
+		if rec.Code != http.StatusOK || strings.TrimSpace(rec.Body.String()) != "2" {
+			t.Errorf("Expected the office space, got %d %s", rec.Code, rec.Body)
+		}
+	})
+
+	t.Run("public space read-only for non-members", func(t *testing.T) {
+		mock := withMockRepositories(t)
+		rows := func() *pgxmock.Rows {
+			return pgxmock.NewRows(spaceColumns).AddRow(3, "club", "Club", true, (*int)(nil), now, now, (*string)(nil))
+		}
+		mock.ExpectQuery(`FROM spaces s`).WithArgs("club", 0).WillReturnRows(rows())
+		mock.ExpectQuery(`FROM spaces s`).WithArgs("club", 7).WillReturnRows(rows())
+
+		if rec := serveInSpace(http.MethodGet, "/restaurants", "/api/restaurants", "club", nil); rec.Code != http.StatusOK {
+			t.Errorf("Expected anonymous reads to be allowed, got %d", rec.Code)
+		}
+		if rec := serveInSpace(http.MethodPost, "/restaurants", "/api/restaurants", "club", alice); rec.Code != http.StatusForbidden {
+			t.Errorf("Expected 403 for a non-member write, got %d", rec.Code)
+		}
+	})
+
+	t.Run("unknown space", func(t *testing.T) {
+		mock := withMockRepositories(t)
+		mock.ExpectQuery(`FROM spaces s`).WithArgs("nowhere", 0).WillReturnRows(pgxmock.NewRows(spaceColumns))
+
+		if rec := serveInSpace(http.MethodGet, "/restaurants", "/api/restaurants", "nowhere", nil); rec.Code != http.StatusNotFound {
+			t.Errorf("Expected 404, got %d", rec.Code)
+		}
+	})
+
+	t.Run("entity of another space", func(t *testing.T) {
+		mock := withMockRepositories(t)
+		mock.ExpectQuery(`SELECT r.space_id FROM ratings rt`).WithArgs(12).
+			WillReturnRows(pgxmock.NewRows([]string{"space_id"}).AddRow(2))
+		mock.ExpectQuery(`SELECT space_id FROM restaurants`).WithArgs(5).
+			WillReturnRows(pgxmock.NewRows([]string{"space_id"}).AddRow(1))
+
+		rec := serveInSpace(http.MethodDelete, "/ratings/{id}", "/api/ratings/12", "", alice)
+		if rec.Code != http.StatusNotFound || !strings.Contains(rec.Body.String(), "Rating not found") {
+			t.Errorf("Expected 404 for a rating of another space, got %d %s", rec.Code, rec.Body)
+		}
+		if rec := serveInSpace(http.MethodGet, "/restaurants/{restaurantId}/photos", "/api/restaurants/5/photos", "", nil); rec.Code != http.StatusOK {
+			t.Errorf("Expected a restaurant of the space to pass, got %d", rec.Code)
+		}
+	})
+}
+
+func TestSpaceAdminMiddleware(t *testing.T) {
+	admin, member := repository.SpaceRoleAdmin, repository.SpaceRoleMember
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusNoContent) })
+
+	tests := []struct {
+		name string
+		user *models.User
+		role *string
+		want int
+	}{
+		{"space admin", &models.User{ID: 7}, &admin, http.StatusNoContent},
+		{"space member", &models.User{ID: 7}, &member, http.StatusForbidden},
+		{"instance admin", &models.User{ID: 1, IsAdmin: true}, nil, http.StatusNoContent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			space := &models.Space{ID: 2, Slug: "office", Role: tt.role}
+			ctx := context.WithValue(context.Background(), models.UserContextKey, tt.user)
+			ctx = context.WithValue(ctx, spaceContextKey{}, space)
+			rec := httptest.NewRecorder()
+			SpaceAdminMiddleware(next).ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/api/suggestions/4", nil).WithContext(ctx))
+			if rec.Code != tt.want {
+				t.Errorf("Expected %d, got %d", tt.want, rec.Code)
+			}
+		})
+	}
+}
+
+func TestCreateSpaceValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"invalid JSON", `{`},
+		{"uppercase slug", `{"slug": "Office", "name": "Office"}`},
+		{"one character slug", `{"slug": "o", "name": "Office"}`},
+		{"missing name", `{"slug": "office", "name": "  "}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/spaces", strings.NewReader(tt.body))
+			req = req.WithContext(context.WithValue(req.Context(), models.UserContextKey, &models.User{ID: 7}))
+			rec := httptest.NewRecorder()
+			CreateSpace(rec, req)
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("Expected 400, got %d", rec.Code)
+			}
+		})
+	}
+}
+
+func TestDefaultSpaceCannotBeChanged(t *testing.T) {
+	req := httptest.NewRequest(http.MethodDelete, "/api/spaces/default", nil)
+	req = mux.SetURLVars(req, map[string]string{"slug": "default"})
+	req = req.WithContext(context.WithValue(req.Context(), models.UserContextKey, &models.User{ID: 1, IsAdmin: true}))
+	rec := httptest.NewRecorder()
+	DeleteSpace(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400, got %d", rec.Code)
+	}
+}
+
+func TestSetSpaceMember_OnlyOwnersChangeOwners(t *testing.T) {
+	mock := withMockRepositories(t)
+	now := time.Now()
+	admin := repository.SpaceRoleAdmin
+	mock.ExpectQuery(`FROM spaces s`).WithArgs("office", 7).
+		WillReturnRows(pgxmock.NewRows(spaceColumns).AddRow(2, "office", "Office", false, (*int)(nil), now, now, &admin))
+	mock.ExpectQuery(`FROM space_members m`).WithArgs(2).
+		WillReturnRows(pgxmock.NewRows([]string{"user_id", "username", "role", "created_at"}).
+			AddRow(8, "bob", repository.SpaceRoleOwner, now))
+
+	req := httptest.NewRequest(http.MethodPut, "/api/spaces/office/members/8", strings.NewReader(`{"role": "member"}`))
+	req = mux.SetURLVars(req, map[string]string{"slug": "office", "userId": "8"})
+	req = req.WithContext(context.WithValue(req.Context(), models.UserContextKey, &models.User{ID: 7}))
+	rec := httptest.NewRecorder()
+	SetSpaceMember(rec, req)
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("Expected an admin demoting an owner to get 403, got %d", rec.Code)
+	}
+}
+
+func TestJoinSpace_AlreadyMember(t *testing.T) {
+	mock := withMockRepositories(t)
+	now := time.Now()
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FROM space_invites i`).WithArgs(hashInviteCode("abc")).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "slug", "name", "public", "created_by_user_id", "created_at", "updated_at", "i.id", "i.role"}).
+			AddRow(2, "office", "Office", false, (*int)(nil), now, now, 11, repository.SpaceRoleMember))
+	mock.ExpectExec(`INSERT INTO space_members`).WithArgs(2, 7, repository.SpaceRoleMember).
+		WillReturnResult(pgxmock.NewResult("INSERT", 0))
+	mock.ExpectRollback()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/spaces/join", strings.NewReader(`{"code": "abc"}`))
+	req = req.WithContext(context.WithValue(req.Context(), models.UserContextKey, &models.User{ID: 7}))
+	rec := httptest.NewRecorder()
+	JoinSpace(rec, req)
+	if rec.Code != http.StatusConflict {
+		t.Errorf("Expected 409, got %d", rec.Code)
+	}
+}
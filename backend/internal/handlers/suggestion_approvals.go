@@ -0,0 +1,239 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/nomdb/backend/internal/cache"
+	"github.com/nomdb/backend/internal/database"
+	apperrors "github.com/nomdb/backend/internal/errors"
+	"github.com/nomdb/backend/internal/logger"
+	"github.com/nomdb/backend/internal/models"
+	"github.com/nomdb/backend/internal/repository"
+)
+
+// Suggestion approval modes
+const (
+	ApprovalModeSingle   = "single"    // Any authenticated user can convert a suggestion
+	ApprovalModeTwoAdmin = "two_admin" // Two distinct admins of the instance or the suggestion's space must approve before conversion
+
+	requiredAdminApprovals = 2
+)
+
+var suggestionApprovalMode = ApprovalModeSingle
+
+// InitSuggestionWorkflow configures the review workflow suggestions go through before conversion
+func InitSuggestionWorkflow(mode string) {
+	switch mode {
+	case ApprovalModeTwoAdmin:
+		suggestionApprovalMode = ApprovalModeTwoAdmin
+		logger.Info("✅ Suggestion workflow: %d distinct admin approvals required before conversion", requiredAdminApprovals)
+	case ApprovalModeSingle, "":
+		suggestionApprovalMode = ApprovalModeSingle
+		logger.Debug("Suggestion workflow: single-step conversion")
+	default:
+		logger.Warn("Unknown suggestion approval mode '%s', defaulting to '%s'", mode, ApprovalModeSingle)
+		suggestionApprovalMode = ApprovalModeSingle
+	}
+}
+
+func requiredApprovals() int {
+	if suggestionApprovalMode == ApprovalModeTwoAdmin {
+		return requiredAdminApprovals
+	}
+	return 0
+}
+
+func buildApprovalStatus(ctx context.Context, suggestionID int) (*models.SuggestionApprovalStatus, error) {
+	approvals, err := suggestionRepo.Approvals(ctx, suggestionID)
+	if err != nil {
+		return nil, err
+	}
+
+	required := requiredApprovals()
+	return &models.SuggestionApprovalStatus{
+		SuggestionID:      suggestionID,
+		Mode:              suggestionApprovalMode,
+		RequiredApprovals: required,
+		Approvals:         approvals,
+		CanConvert:        len(approvals) >= required,
+	}, nil
+}
+
+func suggestionExists(ctx context.Context, suggestionID int) (bool, error) {
+	var exists bool
+	err := database.GetPool().QueryRow(ctx,
+		"SELECT EXISTS(SELECT 1 FROM restaurant_suggestions WHERE id = $1)", suggestionID).Scan(&exists)
+	return exists, err
+}
+
+// @Summary Get suggestion approvals
+// @Description Get the approvals recorded for a suggestion by admins of the instance or its space, and whether it can be converted
+// @Tags Suggestions
+// @Produce json
+// @Param id path int true "Suggestion ID"
+// @Success 200 {object} models.SuggestionApprovalStatus "Approval status"
+// @Failure 400 {object} errors.ErrorResponse "Invalid suggestion ID"
+// @Failure 404 {object} errors.ErrorResponse "Suggestion not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /suggestions/{id}/approvals [get]
+func GetSuggestionApprovals(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		apperrors.Error(w, "Invalid suggestion ID", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+
+	exists, err := suggestionExists(ctx, id)
+	if err != nil {
+		apperrors.Internal(w, err)
+		return
+	}
+	if !exists {
+		apperrors.Error(w, "Suggestion not found", http.StatusNotFound)
+		return
+	}
+
+	status, err := buildApprovalStatus(ctx, id)
+	if err != nil {
+		apperrors.Internal(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(status)
+}
+
+// @Summary Approve a suggestion
+// @Description Record the current admin's approval of a suggestion. Admins of the instance and owners and admins of the suggestion's space may approve. Once the required number of distinct admins have approved, a pending suggestion is moved to "approved".
+// @Tags Suggestions
+// @Accept json
+// @Produce json
+// @Param id path int true "Suggestion ID"
+// @Param approval body models.ApproveSuggestionRequest false "Optional approval note"
+// @Success 201 {object} models.SuggestionApprovalStatus "Updated approval status"
+// @Failure 400 {object} errors.ErrorResponse "Invalid request"
+// @Failure 403 {object} errors.ErrorResponse "Space admin access required"
+// @Failure 404 {object} errors.ErrorResponse "Suggestion not found"
+// @Failure 409 {object} errors.ErrorResponse "Already approved by this admin"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /suggestions/{id}/approvals [post]
+func ApproveSuggestion(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		apperrors.Error(w, "Invalid suggestion ID", http.StatusBadRequest)
+		return
+	}
+
+	user, ok := GetUserFromContext(r)
+	if !ok {
+		apperrors.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.ApproveSuggestionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && err != io.EOF {
+		apperrors.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+
+	if err := suggestionRepo.Approve(ctx, id, user.ID, req.Note); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrAlreadyApproved):
+			apperrors.Error(w, "You have already approved this suggestion", http.StatusConflict)
+		case errors.Is(err, repository.ErrNotFound):
+			apperrors.Error(w, "Suggestion not found", http.StatusNotFound)
+		default:
+			logger.Error("Failed to record approval for suggestion %d: %v", id, err)
+			apperrors.Internal(w, err)
+		}
+		return
+	}
+
+	logger.Info("Suggestion %d approved by %s (ID: %d)", id, user.Username, user.ID)
+
+	status, err := buildApprovalStatus(ctx, id)
+	if err != nil {
+		apperrors.Internal(w, err)
+		return
+	}
+
+	// Promote pending suggestions once enough distinct admins signed off
+	if suggestionApprovalMode == ApprovalModeTwoAdmin && status.CanConvert {
+		promoted, err := suggestionRepo.MarkApproved(ctx, id)
+		if err != nil {
+			logger.Warn("Failed to mark suggestion %d as approved: %v", id, err)
+		} else if promoted {
+			fromStatus := "pending"
+			note := fmt.Sprintf("Approved by %d admins", len(status.Approvals))
+			recordSuggestionEvent(ctx, r, id, &fromStatus, "approved", &note)
+			cache.Invalidate(ctx, cache.KeyRestaurants)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(status)
+}
+
+// @Summary Revoke a suggestion approval
+// @Description Withdraw the current admin's approval of a suggestion
+// @Tags Suggestions
+// @Produce json
+// @Param id path int true "Suggestion ID"
+// @Success 200 {object} models.SuggestionApprovalStatus "Updated approval status"
+// @Failure 400 {object} errors.ErrorResponse "Invalid suggestion ID"
+// @Failure 403 {object} errors.ErrorResponse "Space admin access required"
+// @Failure 404 {object} errors.ErrorResponse "Approval not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /suggestions/{id}/approvals [delete]
+func RevokeSuggestionApproval(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		apperrors.Error(w, "Invalid suggestion ID", http.StatusBadRequest)
+		return
+	}
+
+	user, ok := GetUserFromContext(r)
+	if !ok {
+		apperrors.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	ctx := r.Context()
+
+	if err := suggestionRepo.RevokeApproval(ctx, id, user.ID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			apperrors.Error(w, "Approval not found", http.StatusNotFound)
+			return
+		}
+		apperrors.Internal(w, err)
+		return
+	}
+
+	logger.Info("Approval for suggestion %d revoked by %s (ID: %d)", id, user.Username, user.ID)
+
+	status, err := buildApprovalStatus(ctx, id)
+	if err != nil {
+		apperrors.Internal(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(status)
+}
@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/nomdb/backend/internal/models"
+	"github.com/nomdb/backend/internal/repository"
+	"github.com/pashagolub/pgxmock/v4"
+)
+
+// spaceAdminRequest is a request of user in a space they are an admin of
+func spaceAdminRequest(method, target, body string, user *models.User) *http.Request {
+	role := repository.SpaceRoleAdmin
+	space := &models.Space{ID: 5, Slug: "office", Role: &role}
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req = mux.SetURLVars(req, map[string]string{"id": "4"})
+	ctx := context.WithValue(req.Context(), models.UserContextKey, user)
+	return req.WithContext(context.WithValue(ctx, spaceContextKey{}, space))
+}
+
+func TestSuggestionApprovals_SpaceAdminsConvert(t *testing.T) {
+	mock := withMockRepositories(t)
+	withMemoryCache(t)
+	InitSuggestionWorkflow(ApprovalModeTwoAdmin)
+	t.Cleanup(func() { InitSuggestionWorkflow(ApprovalModeSingle) })
+
+	now := time.Now()
+	anna, ben := &models.User{ID: 21, Username: "anna"}, &models.User{ID: 22, Username: "ben"}
+	approvalColumns := []string{"id", "suggestion_id", "user_id", "username", "note", "created_at"}
+	bothApproved := func() *pgxmock.Rows {
+		return pgxmock.NewRows(approvalColumns).AddRow(1, 4, anna.ID, "anna", nil, now).AddRow(2, 4, ben.ID, "ben", nil, now)
+	}
+	anyArg := pgxmock.AnyArg()
+
+	// Neither is an admin of the instance; the space admins' approvals count
+	mock.ExpectExec(`INSERT INTO suggestion_approvals`).WithArgs(4, anna.ID, (*string)(nil)).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	mock.ExpectQuery(`m.role IN \('owner', 'admin'\)`).WithArgs(4).
+		WillReturnRows(pgxmock.NewRows(approvalColumns).AddRow(1, 4, anna.ID, "anna", nil, now))
+	mock.ExpectExec(`INSERT INTO suggestion_approvals`).WithArgs(4, ben.ID, (*string)(nil)).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	mock.ExpectQuery(`m.role IN \('owner', 'admin'\)`).WithArgs(4).WillReturnRows(bothApproved())
+	mock.ExpectExec(`UPDATE restaurant_suggestions SET status = 'approved'`).WithArgs(4).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	mock.ExpectExec(`INSERT INTO suggestion_events`).WithArgs(4, 5, &ben.ID, anyArg, "approved", anyArg).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+	approve := SpaceAdminMiddleware(http.HandlerFunc(ApproveSuggestion))
+	for _, user := range []*models.User{anna, ben} {
+		rec := httptest.NewRecorder()
+		approve.ServeHTTP(rec, spaceAdminRequest(http.MethodPost, "/api/suggestions/4/approvals", "", user))
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("Expected 201 for %s, got %d: %s", user.Username, rec.Code, rec.Body.String())
+		}
+		if user == ben {
+			var status models.SuggestionApprovalStatus
+			json.NewDecoder(rec.Body).Decode(&status)
+			if !status.CanConvert || len(status.Approvals) != 2 {
+				t.Errorf("Expected the suggestion to be convertible, got %+v", status)
+			}
+		}
+	}
+
+	// The approved suggestion converts
+	mock.ExpectQuery(`FROM restaurant_suggestions s`).WithArgs(4).
+		WillReturnRows(pgxmock.NewRows([]string{
+			"id", "name", "address", "phone", "website", "latitude", "longitude", "google_place_id",
+			"suggested_category_id", "notes", "status", "created_at", "updated_at", "user_id", "c.id", "c.name",
+		}).AddRow(4, "Noodle Bar", nil, nil, nil, nil, nil, nil, nil, nil, "approved", now, now, nil, nil, nil))
+	mock.ExpectQuery(`JOIN suggestion_food_types`).WithArgs(4).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "name", "created_at", "updated_at"}))
+	mock.ExpectQuery(`m.role IN \('owner', 'admin'\)`).WithArgs(4).WillReturnRows(bothApproved())
+	mock.ExpectBegin()
+	mock.ExpectQuery(`DELETE FROM restaurant_suggestions`).WithArgs(4).
+		WillReturnRows(pgxmock.NewRows([]string{"status"}).AddRow("approved"))
+	mock.ExpectQuery(`INSERT INTO restaurants`).
+		WithArgs("Noodle Bar", anyArg, anyArg, anyArg, anyArg, anyArg, anyArg, anyArg, anyArg, 5, anyArg).
+		WillReturnRows(pgxmock.NewRows([]string{
+			"id", "name", "description", "address", "phone", "website", "latitude", "longitude",
+			"google_place_id", "category_id", "created_at", "updated_at", "business_status", "opening_hours",
+		}).AddRow(9, "Noodle Bar", nil, nil, nil, nil, nil, nil, nil, nil, now, now, nil, nil))
+	mock.ExpectQuery(`INSERT INTO ratings`).WithArgs(9, 5, 4, 3, anyArg, anyArg).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "restaurant_id", "food_rating", "service_rating", "ambiance_rating",
+			"comment", "user_id", "created_at", "owner_response", "owner_response_at"}).
+			AddRow(1, 9, 5, 4, 3, nil, nil, now, nil, nil))
+	mock.ExpectCommit()
+	mock.ExpectExec(`INSERT INTO suggestion_events`).WithArgs(4, 5, &anna.ID, anyArg, suggestionEventConverted, anyArg).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+	rec := httptest.NewRecorder()
+	ConvertSuggestion(rec, spaceAdminRequest(http.MethodPost, "/api/suggestions/4/convert",
+		`{"food_rating": 5, "service_rating": 4, "ambiance_rating": 3}`, anna))
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	apperrors "github.com/nomdb/backend/internal/errors"
+	"github.com/nomdb/backend/internal/logger"
+	"github.com/nomdb/backend/internal/repository"
+)
+
+// Pseudo-statuses recorded in the activity log for terminal transitions
+const (
+	suggestionEventConverted = "converted"
+	suggestionEventDeleted   = "deleted"
+)
+
+// recordSuggestionEvent appends a status transition to the suggestion activity log.
+// Failures are logged but never fail the surrounding request.
+func recordSuggestionEvent(ctx context.Context, r *http.Request, suggestionID int, fromStatus *string, toStatus string, note *string) {
+	var userID *int
+	if user, ok := GetUserFromContext(r); ok {
+		userID = &user.ID
+	}
+
+	if err := suggestionRepo.RecordEvent(ctx, spaceID(r), suggestionID, userID, fromStatus, toStatus, note); err != nil {
+		logger.Warn("Failed to record event for suggestion %d (%v -> %s): %v", suggestionID, fromStatus, toStatus, err)
+	}
+}
+
+// @Summary Get suggestion activity log
+// @Description Get the status transitions recorded for a suggestion (who, when, from → to, note). History remains available in the suggestion's space after it is converted or deleted.
+// @Tags Suggestions
+// @Produce json
+// @Param id path int true "Suggestion ID"
+// @Success 200 {array} models.SuggestionEvent "Suggestion events in chronological order"
+// @Failure 400 {object} errors.ErrorResponse "Invalid suggestion ID"
+// @Failure 404 {object} errors.ErrorResponse "Suggestion not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /suggestions/{id}/events [get]
+func GetSuggestionEvents(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		apperrors.Error(w, "Invalid suggestion ID", http.StatusBadRequest)
+		return
+	}
+
+	events, err := suggestionRepo.Events(r.Context(), spaceID(r), id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			apperrors.Error(w, "Suggestion not found", http.StatusNotFound)
+			return
+		}
+		apperrors.Internal(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(events)
+}
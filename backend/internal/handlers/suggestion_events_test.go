@@ -0,0 +1,26 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/nomdb/backend/internal/models"
+	"github.com/pashagolub/pgxmock/v4"
+)
+
+func TestGetSuggestionEvents_OtherSpace(t *testing.T) {
+	mock := withMockRepositories(t)
+
+	// Suggestion 4 was deleted from another space: its history is not shown in space 5
+	mock.ExpectQuery(`WHERE e.suggestion_id = \$1 AND e.space_id = \$2`).WithArgs(4, 5).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "suggestion_id", "user_id", "username", "from_status", "to_status", "note", "created_at"}))
+	mock.ExpectQuery(`FROM restaurant_suggestions WHERE id = \$1 AND space_id = \$2`).WithArgs(4, 5).
+		WillReturnRows(pgxmock.NewRows([]string{"exists"}).AddRow(false))
+
+	rec := httptest.NewRecorder()
+	GetSuggestionEvents(rec, spaceAdminRequest(http.MethodGet, "/api/suggestions/4/events", "", &models.User{ID: 21}))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
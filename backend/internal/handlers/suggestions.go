@@ -1,40 +1,22 @@
 package handlers
 
 import (
-	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
 	"strings"
 
 	"github.com/gorilla/mux"
 	"github.com/jackc/pgx/v5/pgconn"
-	"github.com/nomdb/backend/internal/database"
+	"github.com/nomdb/backend/internal/cache"
+	apperrors "github.com/nomdb/backend/internal/errors"
 	"github.com/nomdb/backend/internal/logger"
 	"github.com/nomdb/backend/internal/models"
+	"github.com/nomdb/backend/internal/repository"
 )
 
-// Helper functions for suggestion food types
-func setFoodTypesForSuggestion(ctx context.Context, suggestionID int, foodTypeIDs []int) error {
-	// Delete existing food types
-	_, err := database.GetPool().Exec(ctx,
-		"DELETE FROM suggestion_food_types WHERE suggestion_id = $1", suggestionID)
-	if err != nil {
-		return err
-	}
-
-	// Insert new food types
-	for _, ftID := range foodTypeIDs {
-		_, err := database.GetPool().Exec(ctx,
-			"INSERT INTO suggestion_food_types (suggestion_id, food_type_id) VALUES ($1, $2)",
-			suggestionID, ftID)
-		if err != nil {
-			return err
-		}
-	}
-	return nil
-}
-
 // @Summary List all restaurant suggestions
 // @Description Get a list of all restaurant suggestions with optional status filter
 // @Tags Suggestions
@@ -42,78 +24,15 @@ func setFoodTypesForSuggestion(ctx context.Context, suggestionID int, foodTypeID
 // @Produce json
 // @Param status query string false "Filter by status (pending, approved, tested, rejected)"
 // @Success 200 {array} models.RestaurantSuggestion "List of suggestions"
-// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
 // @Router /suggestions [get]
 func GetSuggestions(w http.ResponseWriter, r *http.Request) {
-	ctx := context.Background()
-	statusFilter := r.URL.Query().Get("status")
-
-	var query string
-	var args []interface{}
-
-	if statusFilter != "" {
-		query = `
-			SELECT
-				s.id, s.name, s.address, s.phone, s.website, s.latitude, s.longitude,
-				s.google_place_id, s.suggested_category_id, s.notes, s.status,
-				s.created_at, s.updated_at,
-				c.id, c.name
-			FROM restaurant_suggestions s
-			LEFT JOIN categories c ON s.suggested_category_id = c.id
-			WHERE s.status = $1
-			ORDER BY s.created_at DESC
-		`
-		args = append(args, statusFilter)
-	} else {
-		query = `
-			SELECT
-				s.id, s.name, s.address, s.phone, s.website, s.latitude, s.longitude,
-				s.google_place_id, s.suggested_category_id, s.notes, s.status,
-				s.created_at, s.updated_at,
-				c.id, c.name
-			FROM restaurant_suggestions s
-			LEFT JOIN categories c ON s.suggested_category_id = c.id
-			ORDER BY s.created_at DESC
-		`
-	}
-
-	rows, err := database.GetPool().Query(ctx, query, args...)
+	suggestions, err := suggestionRepo.List(r.Context(), spaceID(r), r.URL.Query().Get("status"))
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		apperrors.Internal(w, err)
 		return
 	}
-	defer rows.Close()
-
-	suggestions := []models.RestaurantSuggestion{}
-	for rows.Next() {
-		var sug models.RestaurantSuggestion
-		var catID *int
-		var catName *string
-
-		if err := rows.Scan(
-			&sug.ID, &sug.Name, &sug.Address, &sug.Phone, &sug.Website, &sug.Latitude, &sug.Longitude,
-			&sug.GooglePlaceID, &sug.SuggestedCategoryID, &sug.Notes, &sug.Status,
-			&sug.CreatedAt, &sug.UpdatedAt,
-			&catID, &catName,
-		); err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-
-		if catID != nil && catName != nil {
-			sug.Category = &models.Category{ID: *catID, Name: *catName}
-		}
-
-		// Get food types
-		foodTypes, err := getFoodTypesForSuggestion(ctx, sug.ID)
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		sug.FoodTypes = foodTypes
-
-		suggestions = append(suggestions, sug)
-	}
+	localizeSuggestions(r.Context(), suggestions)
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(suggestions)
@@ -126,56 +45,28 @@ func GetSuggestions(w http.ResponseWriter, r *http.Request) {
 // @Produce json
 // @Param id path int true "Suggestion ID"
 // @Success 200 {object} models.RestaurantSuggestion "Suggestion details"
-// @Failure 400 {object} map[string]string "Invalid suggestion ID"
-// @Failure 404 {object} map[string]string "Suggestion not found"
-// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 400 {object} errors.ErrorResponse "Invalid suggestion ID"
+// @Failure 404 {object} errors.ErrorResponse "Suggestion not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
 // @Router /suggestions/{id} [get]
 func GetSuggestion(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id, err := strconv.Atoi(vars["id"])
 	if err != nil {
-		http.Error(w, "Invalid suggestion ID", http.StatusBadRequest)
+		apperrors.Error(w, "Invalid suggestion ID", http.StatusBadRequest)
 		return
 	}
 
-	ctx := context.Background()
-	query := `
-		SELECT
-			s.id, s.name, s.address, s.phone, s.website, s.latitude, s.longitude,
-			s.google_place_id, s.suggested_category_id, s.notes, s.status,
-			s.created_at, s.updated_at,
-			c.id, c.name
-		FROM restaurant_suggestions s
-		LEFT JOIN categories c ON s.suggested_category_id = c.id
-		WHERE s.id = $1
-	`
-
-	var sug models.RestaurantSuggestion
-	var catID *int
-	var catName *string
-
-	err = database.GetPool().QueryRow(ctx, query, id).Scan(
-		&sug.ID, &sug.Name, &sug.Address, &sug.Phone, &sug.Website, &sug.Latitude, &sug.Longitude,
-		&sug.GooglePlaceID, &sug.SuggestedCategoryID, &sug.Notes, &sug.Status,
-		&sug.CreatedAt, &sug.UpdatedAt,
-		&catID, &catName,
-	)
+	sug, err := suggestionRepo.GetByID(r.Context(), id)
 	if err != nil {
-		http.Error(w, "Suggestion not found", http.StatusNotFound)
-		return
-	}
-
-	if catID != nil && catName != nil {
-		sug.Category = &models.Category{ID: *catID, Name: *catName}
-	}
-
-	// Get food types
-	foodTypes, err := getFoodTypesForSuggestion(ctx, sug.ID)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		if errors.Is(err, repository.ErrNotFound) {
+			apperrors.Error(w, "Suggestion not found", http.StatusNotFound)
+			return
+		}
+		apperrors.Internal(w, err)
 		return
 	}
-	sug.FoodTypes = foodTypes
+	translate(translatedNames(r.Context()), sug.Category, sug.FoodTypes)
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(sug)
@@ -188,92 +79,92 @@ func GetSuggestion(w http.ResponseWriter, r *http.Request) {
 // @Produce json
 // @Param suggestion body models.CreateSuggestionRequest true "Suggestion creation request"
 // @Success 201 {object} models.RestaurantSuggestion "Created suggestion"
-// @Failure 400 {object} map[string]string "Invalid request body"
-// @Failure 409 {object} map[string]string "Suggestion already exists or restaurant exists"
-// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 400 {object} errors.ErrorResponse "Invalid request body"
+// @Failure 403 {object} errors.ErrorResponse "Suggestions are disabled"
+// @Failure 409 {object} errors.ErrorResponse "Suggestion already exists or restaurant exists"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
 // @Router /suggestions [post]
 func CreateSuggestion(w http.ResponseWriter, r *http.Request) {
+	if !requireFeature(w, settingSuggestionsEnabled, "Suggestions are disabled") {
+		return
+	}
+
 	var req models.CreateSuggestionRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		apperrors.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
 
 	if req.Name == "" {
-		http.Error(w, "Name is required", http.StatusBadRequest)
+		apperrors.Error(w, "Name is required", http.StatusBadRequest)
 		return
 	}
+	req.SpaceID = spaceID(r)
 
-	ctx := context.Background()
+	ctx := r.Context()
 
 	// Check if restaurant already exists in the restaurants table
-	var existingRestaurantID int
-	var checkQuery string
-	var checkArgs []interface{}
-
-	if req.GooglePlaceID != nil && *req.GooglePlaceID != "" {
-		// Check by Google Place ID first
-		checkQuery = "SELECT id FROM restaurants WHERE google_place_id = $1"
-		checkArgs = []interface{}{*req.GooglePlaceID}
-	} else if req.Address != nil && *req.Address != "" {
-		// Check by name and address combination
-		checkQuery = "SELECT id FROM restaurants WHERE LOWER(name) = LOWER($1) AND LOWER(address) = LOWER($2)"
-		checkArgs = []interface{}{req.Name, *req.Address}
+	existingRestaurantID, err := restaurantRepo.FindExisting(ctx, req.SpaceID, req.Name, req.Address, req.GooglePlaceID)
+	if err == nil {
+		logger.Warn("Attempt to create suggestion for existing restaurant: %s (ID: %d)", req.Name, existingRestaurantID)
+		apperrors.Error(w, "This restaurant already exists in the database. Please search for it instead.", http.StatusConflict)
+		return
+	}
+	if !errors.Is(err, repository.ErrNotFound) {
+		apperrors.Internal(w, err)
+		return
 	}
 
-	if checkQuery != "" {
-		err := database.GetPool().QueryRow(ctx, checkQuery, checkArgs...).Scan(&existingRestaurantID)
-		if err == nil {
-			// Restaurant already exists
-			logger.Warn("Attempt to create suggestion for existing restaurant: %s (ID: %d)", req.Name, existingRestaurantID)
-			http.Error(w, "This restaurant already exists in the database. Please search for it instead.", http.StatusConflict)
-			return
-		}
-		// If error is "no rows", that's fine - restaurant doesn't exist
+	var suggestedBy *int
+	if user, ok := GetUserFromContext(r); ok {
+		suggestedBy = &user.ID
 	}
 
-	var sug models.RestaurantSuggestion
-	err := database.GetPool().QueryRow(ctx,
-		`INSERT INTO restaurant_suggestions (name, address, phone, website, latitude, longitude, google_place_id, suggested_category_id, notes)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
-		RETURNING id, name, address, phone, website, latitude, longitude, google_place_id, suggested_category_id, notes, status, created_at, updated_at`,
-		req.Name, req.Address, req.Phone, req.Website, req.Latitude, req.Longitude, req.GooglePlaceID, req.SuggestedCategoryID, req.Notes,
-	).Scan(
-		&sug.ID, &sug.Name, &sug.Address, &sug.Phone, &sug.Website, &sug.Latitude, &sug.Longitude,
-		&sug.GooglePlaceID, &sug.SuggestedCategoryID, &sug.Notes, &sug.Status, &sug.CreatedAt, &sug.UpdatedAt,
-	)
+	// The suggestion and its food types are created together or not at all
+	var sug *models.RestaurantSuggestion
+	err = repos.WithTx(ctx, func(tx *repository.Repositories) error {
+		var err error
+		if sug, err = tx.Suggestions.Create(ctx, req, suggestedBy); err != nil {
+			return err
+		}
+		if len(req.FoodTypeIDs) > 0 {
+			return tx.Suggestions.SetFoodTypes(ctx, sug.ID, req.FoodTypeIDs)
+		}
+		return nil
+	})
 	if err != nil {
 		// Check if it's a unique constraint violation
 		if pgErr, ok := err.(*pgconn.PgError); ok {
 			if pgErr.Code == "23505" { // unique_violation
 				logger.Warn("Duplicate suggestion creation attempt: %s", req.Name)
 				if strings.Contains(pgErr.ConstraintName, "google_place_id") {
-					http.Error(w, "A suggestion for this restaurant (Google Place ID) already exists", http.StatusConflict)
+					apperrors.Error(w, "A suggestion for this restaurant (Google Place ID) already exists", http.StatusConflict)
 				} else if strings.Contains(pgErr.ConstraintName, "name_address") {
-					http.Error(w, "A suggestion for this restaurant (name and address) already exists", http.StatusConflict)
+					apperrors.Error(w, "A suggestion for this restaurant (name and address) already exists", http.StatusConflict)
 				} else {
-					http.Error(w, "This suggestion already exists", http.StatusConflict)
+					apperrors.Error(w, "This suggestion already exists", http.StatusConflict)
 				}
 				return
 			}
 		}
+		if writeConstraintViolation(w, err) {
+			return
+		}
 		logger.Error("Failed to create suggestion: %v", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		apperrors.Internal(w, err)
 		return
 	}
 
-	// Set food types
+	recordSuggestionEvent(ctx, r, sug.ID, nil, sug.Status, nil)
+	// Pending suggestions are listed with the restaurants
+	cache.Invalidate(ctx, cache.KeyRestaurants)
+	publishChange(ctx, eventSuggestionCreated, spaceID(r), 0, sug.ID)
+
 	if len(req.FoodTypeIDs) > 0 {
-		if err := setFoodTypesForSuggestion(ctx, sug.ID, req.FoodTypeIDs); err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+		if sug.FoodTypes, err = suggestionRepo.FoodTypes(ctx, sug.ID); err != nil {
+			apperrors.Internal(w, err)
 			return
 		}
-		foodTypes, err := getFoodTypesForSuggestion(ctx, sug.ID)
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
-		sug.FoodTypes = foodTypes
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -289,54 +180,53 @@ func CreateSuggestion(w http.ResponseWriter, r *http.Request) {
 // @Param id path int true "Suggestion ID"
 // @Param status body models.UpdateSuggestionStatusRequest true "Status update request"
 // @Success 200 {object} models.RestaurantSuggestion "Updated suggestion"
-// @Failure 400 {object} map[string]string "Invalid request or status"
-// @Failure 404 {object} map[string]string "Suggestion not found"
-// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 400 {object} errors.ErrorResponse "Invalid request or status"
+// @Failure 404 {object} errors.ErrorResponse "Suggestion not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
 // @Router /suggestions/{id}/status [patch]
 func UpdateSuggestionStatus(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id, err := strconv.Atoi(vars["id"])
 	if err != nil {
-		http.Error(w, "Invalid suggestion ID", http.StatusBadRequest)
+		apperrors.Error(w, "Invalid suggestion ID", http.StatusBadRequest)
 		return
 	}
 
 	var req models.UpdateSuggestionStatusRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		apperrors.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
 
 	// Validate status
 	validStatuses := map[string]bool{"pending": true, "approved": true, "tested": true, "rejected": true}
 	if !validStatuses[req.Status] {
-		http.Error(w, "Invalid status", http.StatusBadRequest)
+		apperrors.Error(w, "Invalid status", http.StatusBadRequest)
 		return
 	}
 
-	ctx := context.Background()
-
-	var sug models.RestaurantSuggestion
-	err = database.GetPool().QueryRow(ctx,
-		`UPDATE restaurant_suggestions SET status = $1, updated_at = NOW()
-		WHERE id = $2
-		RETURNING id, name, address, phone, website, latitude, longitude, google_place_id, suggested_category_id, notes, status, created_at, updated_at`,
-		req.Status, id,
-	).Scan(
-		&sug.ID, &sug.Name, &sug.Address, &sug.Phone, &sug.Website, &sug.Latitude, &sug.Longitude,
-		&sug.GooglePlaceID, &sug.SuggestedCategoryID, &sug.Notes, &sug.Status, &sug.CreatedAt, &sug.UpdatedAt,
-	)
+	ctx := r.Context()
+
+	sug, previousStatus, err := suggestionRepo.UpdateStatus(ctx, id, req.Status)
 	if err != nil {
-		http.Error(w, "Suggestion not found", http.StatusNotFound)
+		if errors.Is(err, repository.ErrNotFound) {
+			apperrors.Error(w, "Suggestion not found", http.StatusNotFound)
+			return
+		}
+		apperrors.Internal(w, err)
 		return
 	}
 
-	foodTypes, err := getFoodTypesForSuggestion(ctx, sug.ID)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	if previousStatus != sug.Status || req.Note != nil {
+		recordSuggestionEvent(ctx, r, sug.ID, &previousStatus, sug.Status, req.Note)
+	}
+	cache.Invalidate(ctx, cache.KeyRestaurants)
+	publishChange(ctx, eventSuggestionUpdated, spaceID(r), 0, sug.ID)
+
+	if sug.FoodTypes, err = suggestionRepo.FoodTypes(ctx, sug.ID); err != nil {
+		apperrors.Internal(w, err)
 		return
 	}
-	sug.FoodTypes = foodTypes
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(sug)
@@ -350,40 +240,52 @@ func UpdateSuggestionStatus(w http.ResponseWriter, r *http.Request) {
 // @Param id path int true "Suggestion ID"
 // @Param conversion body models.ConvertSuggestionRequest true "Conversion request with ratings"
 // @Success 200 {object} map[string]interface{} "Conversion result with restaurant_id"
-// @Failure 400 {object} map[string]string "Invalid request or ratings"
-// @Failure 404 {object} map[string]string "Suggestion not found"
-// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 400 {object} errors.ErrorResponse "Invalid request or ratings"
+// @Failure 404 {object} errors.ErrorResponse "Suggestion not found"
+// @Failure 409 {object} errors.ErrorResponse "Required admin approvals missing"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
 // @Router /suggestions/{id}/convert [post]
 func ConvertSuggestion(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id, err := strconv.Atoi(vars["id"])
 	if err != nil {
-		http.Error(w, "Invalid suggestion ID", http.StatusBadRequest)
+		apperrors.Error(w, "Invalid suggestion ID", http.StatusBadRequest)
 		return
 	}
 
 	var req models.ConvertSuggestionRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		apperrors.Error(w, "Invalid request body", http.StatusBadRequest)
 		return
 	}
 
-	ctx := context.Background()
+	ctx := r.Context()
 
 	// Get the suggestion
-	var sug models.RestaurantSuggestion
-	err = database.GetPool().QueryRow(ctx,
-		`SELECT id, name, address, phone, website, latitude, longitude, google_place_id, suggested_category_id, status
-		FROM restaurant_suggestions WHERE id = $1`, id,
-	).Scan(
-		&sug.ID, &sug.Name, &sug.Address, &sug.Phone, &sug.Website, &sug.Latitude, &sug.Longitude,
-		&sug.GooglePlaceID, &sug.SuggestedCategoryID, &sug.Status,
-	)
+	sug, err := suggestionRepo.GetByID(ctx, id)
 	if err != nil {
-		http.Error(w, "Suggestion not found", http.StatusNotFound)
+		if errors.Is(err, repository.ErrNotFound) {
+			apperrors.Error(w, "Suggestion not found", http.StatusNotFound)
+			return
+		}
+		apperrors.Internal(w, err)
 		return
 	}
 
+	// Enforce the two-admin review workflow when enabled
+	if suggestionApprovalMode == ApprovalModeTwoAdmin {
+		approvals, err := suggestionRepo.Approvals(ctx, sug.ID)
+		if err != nil {
+			apperrors.Internal(w, err)
+			return
+		}
+		if len(approvals) < requiredAdminApprovals {
+			apperrors.Error(w, fmt.Sprintf("Suggestion requires approval from %d distinct admins before conversion (%d/%d)",
+				requiredAdminApprovals, len(approvals), requiredAdminApprovals), http.StatusConflict)
+			return
+		}
+	}
+
 	// Determine category (override if provided, otherwise use suggested)
 	categoryID := sug.SuggestedCategoryID
 	if req.CategoryID != nil {
@@ -394,61 +296,76 @@ func ConvertSuggestion(w http.ResponseWriter, r *http.Request) {
 	if req.FoodRating < 1 || req.FoodRating > 5 ||
 		req.ServiceRating < 1 || req.ServiceRating > 5 ||
 		req.AmbianceRating < 1 || req.AmbianceRating > 5 {
-		http.Error(w, "Ratings must be between 1 and 5", http.StatusBadRequest)
+		apperrors.Error(w, "Ratings must be between 1 and 5", http.StatusBadRequest)
 		return
 	}
 
-	// Create restaurant
+	// Convert in one transaction, so a failure can't leave a restaurant without its
+	// food types or rating, or the suggestion behind. Deleting the suggestion first
+	// locks it, so a concurrent conversion finds it gone.
 	var restaurantID int
-	err = database.GetPool().QueryRow(ctx,
-		`INSERT INTO restaurants (name, description, address, phone, website, latitude, longitude, google_place_id, category_id)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
-		RETURNING id`,
-		sug.Name, req.Description, sug.Address, sug.Phone, sug.Website, sug.Latitude, sug.Longitude, sug.GooglePlaceID, categoryID,
-	).Scan(&restaurantID)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
+	err = repos.WithTx(ctx, func(tx *repository.Repositories) error {
+		if _, err := tx.Suggestions.Delete(ctx, sug.ID); err != nil {
+			return err
+		}
 
-	// Copy food types from suggestion to restaurant
-	foodTypes, err := getFoodTypesForSuggestion(ctx, sug.ID)
-	if err != nil {
-		logger.Warn("Failed to get food types for suggestion %d: %v", sug.ID, err)
-	}
-	if len(foodTypes) > 0 {
-		var foodTypeIDs []int
-		for _, ft := range foodTypes {
-			foodTypeIDs = append(foodTypeIDs, ft.ID)
+		rest, err := tx.Restaurants.Create(ctx, models.CreateRestaurantRequest{
+			Name:          sug.Name,
+			Description:   req.Description,
+			Address:       sug.Address,
+			Phone:         sug.Phone,
+			Website:       sug.Website,
+			Latitude:      sug.Latitude,
+			Longitude:     sug.Longitude,
+			GooglePlaceID: sug.GooglePlaceID,
+			CategoryID:    categoryID,
+			SpaceID:       spaceID(r),
+			SuggestedBy:   sug.UserID,
+		})
+		if err != nil {
+			return err
 		}
-		for _, ftID := range foodTypeIDs {
-			_, err := database.GetPool().Exec(ctx,
-				"INSERT INTO restaurant_food_types (restaurant_id, food_type_id) VALUES ($1, $2)",
-				restaurantID, ftID)
-			if err != nil {
-				// Non-fatal, continue
-				continue
+		restaurantID = rest.ID
+
+		// Copy food types from suggestion to restaurant
+		if len(sug.FoodTypes) > 0 {
+			foodTypeIDs := make([]int, len(sug.FoodTypes))
+			for i, ft := range sug.FoodTypes {
+				foodTypeIDs[i] = ft.ID
+			}
+			if err := tx.Restaurants.SetFoodTypes(ctx, rest.ID, foodTypeIDs); err != nil {
+				return err
 			}
 		}
-	}
 
-	// Create initial rating from the conversion
-	_, err = database.GetPool().Exec(ctx,
-		`INSERT INTO ratings (restaurant_id, food_rating, service_rating, ambiance_rating, comment)
-		VALUES ($1, $2, $3, $4, $5)`,
-		restaurantID, req.FoodRating, req.ServiceRating, req.AmbianceRating, req.Comment,
-	)
+		// Create initial rating from the conversion
+		_, err = tx.Ratings.Create(ctx, models.CreateRatingRequest{
+			RestaurantID:   rest.ID,
+			FoodRating:     req.FoodRating,
+			ServiceRating:  req.ServiceRating,
+			AmbianceRating: req.AmbianceRating,
+			Comment:        req.Comment,
+		}, nil)
+		return err
+	})
 	if err != nil {
-		logger.Warn("Failed to create initial rating for restaurant %d: %v", restaurantID, err)
+		if errors.Is(err, repository.ErrNotFound) {
+			apperrors.Error(w, "Suggestion not found", http.StatusNotFound)
+			return
+		}
+		if writeConstraintViolation(w, err) {
+			return
+		}
+		logger.Error("Failed to convert suggestion %d: %v", sug.ID, err)
+		apperrors.Internal(w, err)
+		return
 	}
 
-	// Delete the suggestion after successful conversion
-	_, err = database.GetPool().Exec(ctx,
-		"DELETE FROM restaurant_suggestions WHERE id = $1", id)
-	if err != nil {
-		// Non-fatal, but log it
-		logger.Warn("Failed to delete converted suggestion %d: %v", id, err)
-	}
+	conversionNote := fmt.Sprintf("Converted to restaurant #%d", restaurantID)
+	recordSuggestionEvent(ctx, r, sug.ID, &sug.Status, suggestionEventConverted, &conversionNote)
+	// The category and food type lists count restaurants
+	cache.Invalidate(ctx, cache.KeyRestaurants, cache.KeyCategories, cache.KeyFoodTypes)
+	publishChange(ctx, eventSuggestionConverted, spaceID(r), restaurantID, sug.ID)
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]interface{}{
@@ -458,35 +375,41 @@ func ConvertSuggestion(w http.ResponseWriter, r *http.Request) {
 }
 
 // @Summary Delete a suggestion
-// @Description Delete a restaurant suggestion by ID
+// @Description Delete a restaurant suggestion by ID. Admins and owners and admins of its space only.
 // @Tags Suggestions
 // @Accept json
 // @Produce json
 // @Param id path int true "Suggestion ID"
 // @Success 204 "Suggestion deleted successfully"
-// @Failure 400 {object} map[string]string "Invalid suggestion ID"
-// @Failure 404 {object} map[string]string "Suggestion not found"
-// @Failure 500 {object} map[string]string "Internal server error"
+// @Failure 400 {object} errors.ErrorResponse "Invalid suggestion ID"
+// @Failure 403 {object} errors.ErrorResponse "Space admin access required"
+// @Failure 404 {object} errors.ErrorResponse "Suggestion not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
 // @Router /suggestions/{id} [delete]
 func DeleteSuggestion(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id, err := strconv.Atoi(vars["id"])
 	if err != nil {
-		http.Error(w, "Invalid suggestion ID", http.StatusBadRequest)
+		apperrors.Error(w, "Invalid suggestion ID", http.StatusBadRequest)
 		return
 	}
 
-	result, err := database.GetPool().Exec(context.Background(),
-		"DELETE FROM restaurant_suggestions WHERE id = $1", id)
+	ctx := r.Context()
+
+	previousStatus, err := suggestionRepo.Delete(ctx, id)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		if errors.Is(err, repository.ErrNotFound) {
+			apperrors.Error(w, "Suggestion not found", http.StatusNotFound)
+			return
+		}
+		apperrors.Internal(w, err)
 		return
 	}
 
-	if result.RowsAffected() == 0 {
-		http.Error(w, "Suggestion not found", http.StatusNotFound)
-		return
-	}
+	recordSuggestionEvent(ctx, r, id, &previousStatus, suggestionEventDeleted, nil)
+	cache.Invalidate(ctx, cache.KeyRestaurants)
+	publishChange(ctx, eventSuggestionDeleted, spaceID(r), 0, id)
+	recordAdminAction(ctx, r, "delete_suggestion", "suggestion", id, map[string]any{"status": previousStatus})
 
 	w.WriteHeader(http.StatusNoContent)
 }
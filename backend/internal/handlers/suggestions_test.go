@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/nomdb/backend/internal/repository"
+	"github.com/pashagolub/pgxmock/v4"
+)
+
+func TestConvertSuggestion_RollsBackOnFailure(t *testing.T) {
+	mock := withMockRepositories(t)
+	now := time.Now()
+	author := 12
+
+	mock.ExpectQuery(`FROM restaurant_suggestions s`).WithArgs(4).
+		WillReturnRows(pgxmock.NewRows([]string{
+			"id", "name", "address", "phone", "website", "latitude", "longitude", "google_place_id",
+			"suggested_category_id", "notes", "status", "created_at", "updated_at", "user_id", "c.id", "c.name",
+		}).AddRow(4, "Noodle Bar", nil, nil, nil, nil, nil, nil, nil, nil, "approved", now, now, &author, nil, nil))
+	mock.ExpectQuery(`JOIN suggestion_food_types`).WithArgs(4).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "name", "created_at", "updated_at"}).AddRow(1, "Ramen", now, now))
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`DELETE FROM restaurant_suggestions`).WithArgs(4).
+		WillReturnRows(pgxmock.NewRows([]string{"status"}).AddRow("approved"))
+	anyArg := pgxmock.AnyArg()
+	mock.ExpectQuery(`INSERT INTO restaurants`).
+		WithArgs("Noodle Bar", anyArg, anyArg, anyArg, anyArg, anyArg, anyArg, anyArg, anyArg, repository.DefaultSpaceID, &author).
+		WillReturnRows(pgxmock.NewRows([]string{
+			"id", "name", "description", "address", "phone", "website", "latitude", "longitude",
+			"google_place_id", "category_id", "created_at", "updated_at", "business_status", "opening_hours",
+		}).AddRow(9, "Noodle Bar", nil, nil, nil, nil, nil, nil, nil, nil, now, now, nil, nil))
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM restaurant_food_types`).WithArgs(9, []int{1}).WillReturnResult(pgxmock.NewResult("DELETE", 0))
+	mock.ExpectExec(`INSERT INTO restaurant_food_types`).WithArgs(9, []int{1}).WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	mock.ExpectCommit()
+	mock.ExpectQuery(`INSERT INTO ratings`).WithArgs(9, 5, 4, 3, anyArg, anyArg).WillReturnError(errors.New("connection reset"))
+	// The suggestion, restaurant and food types are all rolled back
+	mock.ExpectRollback()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/suggestions/4/convert",
+		strings.NewReader(`{"food_rating": 5, "service_rating": 4, "ambiance_rating": 3}`))
+	req = mux.SetURLVars(req, map[string]string{"id": "4"})
+	rec := httptest.NewRecorder()
+	ConvertSuggestion(rec, req)
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("Expected 500, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
@@ -0,0 +1,320 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/nomdb/backend/internal/cache"
+	apperrors "github.com/nomdb/backend/internal/errors"
+	"github.com/nomdb/backend/internal/i18n"
+	"github.com/nomdb/backend/internal/logger"
+	"github.com/nomdb/backend/internal/models"
+	"github.com/nomdb/backend/internal/repository"
+)
+
+// translatedNames returns the category and food type names in the locale of the
+// request, or nil for the default locale, whose names are the stored ones. The
+// translations of all locales are cached together; failing to load them is logged and
+// leaves names untranslated.
+func translatedNames(ctx context.Context) *repository.TranslatedNames {
+	locale := i18n.FromContext(ctx)
+	if locale == i18n.Default || repos == nil {
+		return nil
+	}
+
+	var all map[string]repository.TranslatedNames
+	if !cache.GetJSON(ctx, cache.KeyTranslations, &all) {
+		var err error
+		if all, err = repos.Translations.All(ctx); err != nil {
+			logger.Warn("Failed to load translations: %v", err)
+			return nil
+		}
+		cache.SetJSON(ctx, cache.KeyTranslations, all)
+	}
+	names, ok := all[locale]
+	if !ok {
+		return nil
+	}
+	return &names
+}
+
+// translate replaces the names of a category and food types with their translations
+func translate(names *repository.TranslatedNames, category *models.Category, foodTypes []models.FoodType) {
+	if names == nil {
+		return
+	}
+	if category != nil {
+		if name, ok := names.Categories[category.ID]; ok {
+			category.Name = name
+		}
+	}
+	for i := range foodTypes {
+		if name, ok := names.FoodTypes[foodTypes[i].ID]; ok {
+			foodTypes[i].Name = name
+		}
+	}
+}
+
+// localizeCategories translates a category list and sorts it by the translated names
+func localizeCategories(ctx context.Context, categories []models.Category) {
+	names := translatedNames(ctx)
+	if names == nil {
+		return
+	}
+	for i := range categories {
+		translate(names, &categories[i], nil)
+	}
+	sort.SliceStable(categories, func(i, j int) bool {
+		return strings.ToLower(categories[i].Name) < strings.ToLower(categories[j].Name)
+	})
+}
+
+// localizeFoodTypes translates a food type list and sorts it by the translated names
+func localizeFoodTypes(ctx context.Context, foodTypes []models.FoodType) {
+	names := translatedNames(ctx)
+	if names == nil {
+		return
+	}
+	translate(names, nil, foodTypes)
+	sort.SliceStable(foodTypes, func(i, j int) bool {
+		return strings.ToLower(foodTypes[i].Name) < strings.ToLower(foodTypes[j].Name)
+	})
+}
+
+// localizeRestaurants translates the categories and food types of restaurants
+func localizeRestaurants(ctx context.Context, restaurants []models.Restaurant) {
+	names := translatedNames(ctx)
+	for i := range restaurants {
+		translate(names, restaurants[i].Category, restaurants[i].FoodTypes)
+	}
+}
+
+// localizeSuggestions translates the categories and food types of suggestions
+func localizeSuggestions(ctx context.Context, suggestions []models.RestaurantSuggestion) {
+	names := translatedNames(ctx)
+	for i := range suggestions {
+		translate(names, suggestions[i].Category, suggestions[i].FoodTypes)
+	}
+}
+
+// translationTarget is the category or food type whose translations a request manages
+type translationTarget struct {
+	kind     string
+	idError  string // Response to an invalid ID
+	notFound string
+	event    string // Live update event announcing changed names
+	cacheKey string // Cached list embedding the names
+}
+
+var (
+	categoryTranslations = translationTarget{
+		kind:     repository.TranslationCategory,
+		idError:  "Invalid category ID",
+		notFound: "Category not found",
+		event:    eventCategoryUpdated,
+		cacheKey: cache.KeyCategories,
+	}
+	foodTypeTranslations = translationTarget{
+		kind:     repository.TranslationFoodType,
+		idError:  "Invalid food type ID",
+		notFound: "Food type not found",
+		event:    eventFoodTypeUpdated,
+		cacheKey: cache.KeyFoodTypes,
+	}
+)
+
+// parse reads the ID and, for PUT and DELETE, the locale of a translation request,
+// responding with 400 on invalid values
+func (t translationTarget) parse(w http.ResponseWriter, r *http.Request) (int, string, bool) {
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		apperrors.Error(w, t.idError, http.StatusBadRequest)
+		return 0, "", false
+	}
+	locale, ok := vars["locale"]
+	if ok && (locale == i18n.Default || !i18n.IsSupported(locale)) {
+		apperrors.Error(w, "Unsupported language", http.StatusBadRequest)
+		return 0, "", false
+	}
+	return id, locale, true
+}
+
+func (t translationTarget) list(w http.ResponseWriter, r *http.Request) {
+	id, _, ok := t.parse(w, r)
+	if !ok {
+		return
+	}
+	translations, err := repos.Translations.List(r.Context(), t.kind, id)
+	if err != nil {
+		apperrors.Internal(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(translations)
+}
+
+func (t translationTarget) set(w http.ResponseWriter, r *http.Request) {
+	id, locale, ok := t.parse(w, r)
+	if !ok {
+		return
+	}
+	var req models.TranslationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apperrors.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" || len(req.Name) > 100 {
+		apperrors.Error(w, "Name must be 1-100 characters", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	translation, err := repos.Translations.Set(ctx, t.kind, id, locale, req.Name)
+	if errors.Is(err, repository.ErrNotFound) {
+		apperrors.Error(w, t.notFound, http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		apperrors.Internal(w, err)
+		return
+	}
+	t.changed(ctx, id)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(translation)
+}
+
+func (t translationTarget) delete(w http.ResponseWriter, r *http.Request) {
+	id, locale, ok := t.parse(w, r)
+	if !ok {
+		return
+	}
+	ctx := r.Context()
+	if err := repos.Translations.Delete(ctx, t.kind, id, locale); errors.Is(err, repository.ErrNotFound) {
+		apperrors.Error(w, "Translation not found", http.StatusNotFound)
+		return
+	} else if err != nil {
+		apperrors.Internal(w, err)
+		return
+	}
+	t.changed(ctx, id)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// changed invalidates the cached translations and the lists showing them, and tells
+// clients to refetch the names
+func (t translationTarget) changed(ctx context.Context, id int) {
+	cache.Invalidate(ctx, cache.KeyTranslations, t.cacheKey, cache.KeyRestaurants)
+	publishChange(ctx, t.event, 0, 0, id)
+}
+
+// GetCategoryTranslations godoc
+// @Summary List translations of a category
+// @Description Names of a category in other languages than English. Category lists and restaurants use them for requests whose Accept-Language prefers that language.
+// @Tags Categories
+// @Produce json
+// @Param id path int true "Category ID"
+// @Success 200 {array} models.Translation "Translations by locale"
+// @Failure 400 {object} errors.ErrorResponse "Invalid category ID"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /categories/{id}/translations [get]
+func GetCategoryTranslations(w http.ResponseWriter, r *http.Request) {
+	categoryTranslations.list(w, r)
+}
+
+// SetCategoryTranslation godoc
+// @Summary Set the translation of a category
+// @Description Create or replace the name of a category in a supported language other than English (de)
+// @Tags Categories
+// @Accept json
+// @Produce json
+// @Param id path int true "Category ID"
+// @Param locale path string true "Language, e.g. de"
+// @Param translation body models.TranslationRequest true "Translated name"
+// @Success 200 {object} models.Translation "Stored translation"
+// @Failure 400 {object} errors.ErrorResponse "Invalid category ID, unsupported language or invalid name"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 404 {object} errors.ErrorResponse "Category not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /categories/{id}/translations/{locale} [put]
+func SetCategoryTranslation(w http.ResponseWriter, r *http.Request) {
+	categoryTranslations.set(w, r)
+}
+
+// DeleteCategoryTranslation godoc
+// @Summary Delete the translation of a category
+// @Description Remove the name of a category in a language, falling back to the English name. Admin only.
+// @Tags Categories
+// @Param id path int true "Category ID"
+// @Param locale path string true "Language, e.g. de"
+// @Success 204 "Translation deleted"
+// @Failure 400 {object} errors.ErrorResponse "Invalid category ID or unsupported language"
+// @Failure 403 {object} errors.ErrorResponse "Admin access required"
+// @Failure 404 {object} errors.ErrorResponse "Translation not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /categories/{id}/translations/{locale} [delete]
+func DeleteCategoryTranslation(w http.ResponseWriter, r *http.Request) {
+	categoryTranslations.delete(w, r)
+}
+
+// GetFoodTypeTranslations godoc
+// @Summary List translations of a food type
+// @Description Names of a food type in other languages than English. Food type lists and restaurants use them for requests whose Accept-Language prefers that language.
+// @Tags Food Types
+// @Produce json
+// @Param id path int true "Food type ID"
+// @Success 200 {array} models.Translation "Translations by locale"
+// @Failure 400 {object} errors.ErrorResponse "Invalid food type ID"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Router /food-types/{id}/translations [get]
+func GetFoodTypeTranslations(w http.ResponseWriter, r *http.Request) {
+	foodTypeTranslations.list(w, r)
+}
+
+// SetFoodTypeTranslation godoc
+// @Summary Set the translation of a food type
+// @Description Create or replace the name of a food type in a supported language other than English (de)
+// @Tags Food Types
+// @Accept json
+// @Produce json
+// @Param id path int true "Food type ID"
+// @Param locale path string true "Language, e.g. de"
+// @Param translation body models.TranslationRequest true "Translated name"
+// @Success 200 {object} models.Translation "Stored translation"
+// @Failure 400 {object} errors.ErrorResponse "Invalid food type ID, unsupported language or invalid name"
+// @Failure 401 {object} errors.ErrorResponse "Unauthorized"
+// @Failure 404 {object} errors.ErrorResponse "Food type not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /food-types/{id}/translations/{locale} [put]
+func SetFoodTypeTranslation(w http.ResponseWriter, r *http.Request) {
+	foodTypeTranslations.set(w, r)
+}
+
+// DeleteFoodTypeTranslation godoc
+// @Summary Delete the translation of a food type
+// @Description Remove the name of a food type in a language, falling back to the English name. Admin only.
+// @Tags Food Types
+// @Param id path int true "Food type ID"
+// @Param locale path string true "Language, e.g. de"
+// @Success 204 "Translation deleted"
+// @Failure 400 {object} errors.ErrorResponse "Invalid food type ID or unsupported language"
+// @Failure 403 {object} errors.ErrorResponse "Admin access required"
+// @Failure 404 {object} errors.ErrorResponse "Translation not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /food-types/{id}/translations/{locale} [delete]
+func DeleteFoodTypeTranslation(w http.ResponseWriter, r *http.Request) {
+	foodTypeTranslations.delete(w, r)
+}
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/nomdb/backend/internal/cache"
+	"github.com/nomdb/backend/internal/i18n"
+	"github.com/nomdb/backend/internal/models"
+	"github.com/nomdb/backend/internal/repository"
+	"github.com/pashagolub/pgxmock/v4"
+)
+
+var translationColumns = []string{"kind", "id", "locale", "name"}
+
+func TestGetCategories_Translated(t *testing.T) {
+	withMemoryCache(t)
+	mock := withMockRepositories(t)
+	cache.SetJSON(context.Background(), cache.KeyCategories, []models.Category{{ID: 1, Name: "Asian"}, {ID: 2, Name: "Italian"}, {ID: 3, Name: "Thai"}})
+	// Loaded once, then served from the cache
+	mock.ExpectQuery(`FROM category_translations`).
+		WillReturnRows(pgxmock.NewRows(translationColumns).
+			AddRow(repository.TranslationCategory, 1, i18n.German, "Asiatisch").
+			AddRow(repository.TranslationCategory, 2, i18n.German, "Italienisch"))
+
+	for _, want := range [][]string{{"Asiatisch", "Italienisch", "Thai"}, {"Asiatisch", "Italienisch", "Thai"}} {
+		req := httptest.NewRequest(http.MethodGet, "/api/categories", nil)
+		req = req.WithContext(i18n.WithLocale(req.Context(), i18n.German))
+		rec := httptest.NewRecorder()
+		GetCategories(rec, req)
+
+		var categories []models.Category
+		if err := json.Unmarshal(rec.Body.Bytes(), &categories); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		var names []string
+		for _, c := range categories {
+			names = append(names, c.Name)
+		}
+		if strings.Join(names, ",") != strings.Join(want, ",") {
+			t.Errorf("Expected %v, got %v", want, names)
+		}
+	}
+
+	// English requests get the stored names without loading translations
+	rec := httptest.NewRecorder()
+	GetCategories(rec, httptest.NewRequest(http.MethodGet, "/api/categories", nil))
+	if !strings.Contains(rec.Body.String(), `"Italian"`) {
+		t.Errorf("Expected English names, got %s", rec.Body.String())
+	}
+}
+
+func TestLocalizeRestaurants(t *testing.T) {
+	mock := withMockRepositories(t)
+	mock.ExpectQuery(`FROM category_translations`).
+		WillReturnRows(pgxmock.NewRows(translationColumns).
+			AddRow(repository.TranslationCategory, 4, i18n.German, "Französisch").
+			AddRow(repository.TranslationFoodType, 8, i18n.German, "Meeresfrüchte"))
+
+	restaurants := []models.Restaurant{{
+		ID:        1,
+		Category:  &models.Category{ID: 4, Name: "French"},
+		FoodTypes: []models.FoodType{{ID: 8, Name: "Seafood"}, {ID: 9, Name: "Steak"}},
+	}, {ID: 2}}
+	localizeRestaurants(i18n.WithLocale(context.Background(), i18n.German), restaurants)
+
+	if restaurants[0].Category.Name != "Französisch" {
+		t.Errorf("Expected the German category name, got %q", restaurants[0].Category.Name)
+	}
+	if restaurants[0].FoodTypes[0].Name != "Meeresfrüchte" || restaurants[0].FoodTypes[1].Name != "Steak" {
+		t.Errorf("Expected untranslated food types to keep their name, got %+v", restaurants[0].FoodTypes)
+	}
+}
+
+func TestSetCategoryTranslation(t *testing.T) {
+	request := func(locale, body string) *http.Request {
+		req := httptest.NewRequest(http.MethodPut, "/api/categories/4/translations/"+locale, strings.NewReader(body))
+		return mux.SetURLVars(req, map[string]string{"id": "4", "locale": locale})
+	}
+
+	t.Run("validation", func(t *testing.T) {
+		tests := []struct {
+			name   string
+			locale string
+			body   string
+		}{
+			{"unsupported language", "fr", `{"name": "Français"}`},
+			{"default language", i18n.English, `{"name": "French"}`},
+			{"blank name", i18n.German, `{"name": " "}`},
+			{"long name", i18n.German, `{"name": "` + strings.Repeat("a", 101) + `"}`},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				rec := httptest.NewRecorder()
+				SetCategoryTranslation(rec, request(tt.locale, tt.body))
+				if rec.Code != http.StatusBadRequest {
+					t.Errorf("Expected 400, got %d", rec.Code)
+				}
+			})
+		}
+	})
+
+	t.Run("unknown category", func(t *testing.T) {
+		mock := withMockRepositories(t)
+		mock.ExpectQuery(`INSERT INTO category_translations`).WithArgs(4, i18n.German, "Französisch").
+			WillReturnRows(pgxmock.NewRows([]string{"updated_at"}))
+
+		rec := httptest.NewRecorder()
+		SetCategoryTranslation(rec, request(i18n.German, `{"name": "Französisch"}`))
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("Expected 404, got %d", rec.Code)
+		}
+	})
+}
@@ -0,0 +1,465 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	apperrors "github.com/nomdb/backend/internal/errors"
+	"github.com/nomdb/backend/internal/events"
+	"github.com/nomdb/backend/internal/logger"
+	"github.com/nomdb/backend/internal/models"
+	"github.com/nomdb/backend/internal/repository"
+	"github.com/nomdb/backend/internal/services"
+)
+
+// Events webhooks can subscribe to
+const (
+	webhookRestaurantCreated   = "restaurant.created"
+	webhookRatingCreated       = "rating.created"
+	webhookSuggestionConverted = "suggestion.converted"
+	webhookPhotoUploaded       = "photo.uploaded"
+)
+
+var webhookEventTypes = []string{webhookRestaurantCreated, webhookRatingCreated, webhookSuggestionConverted, webhookPhotoUploaded}
+
+// webhookEventsByBusType maps event bus types to the webhook events they trigger. A photo
+// counts as uploaded once processing made it visible.
+var webhookEventsByBusType = map[string]string{
+	eventRestaurantCreated:   webhookRestaurantCreated,
+	eventRatingCreated:       webhookRatingCreated,
+	eventSuggestionConverted: webhookSuggestionConverted,
+	eventPhotoReady:          webhookPhotoUploaded,
+}
+
+const (
+	// webhookBatchSize is how many deliveries a worker run claims and sends concurrently
+	webhookBatchSize = 20
+	// webhookFirstRetry doubles with every failed attempt up to webhookMaxRetry
+	webhookFirstRetry = 30 * time.Second
+	webhookMaxRetry   = 6 * time.Hour
+	// webhookDeliveryRetention is how long delivered and failed deliveries stay in the log
+	webhookDeliveryRetention = 30 * 24 * time.Hour
+	maxWebhookDeliveries     = 200
+)
+
+// webhookSender posts deliveries; an interface so tests can replace it
+type webhookSender interface {
+	Send(ctx context.Context, url, secret, event string, deliveryID int64, body []byte) (int, error)
+}
+
+var (
+	webhooks           webhookSender
+	webhookMaxAttempts = 8
+	webhookLease       = time.Minute
+	webhookInitOnce    sync.Once
+)
+
+// webhookPayload is the body of webhook requests. Like live updates, it carries IDs;
+// receivers fetch the entities from the API.
+type webhookPayload struct {
+	Event      string             `json:"event"`
+	OccurredAt time.Time          `json:"occurred_at"`
+	Data       webhookPayloadData `json:"data"`
+}
+
+type webhookPayloadData struct {
+	ID           int `json:"id"`
+	RestaurantID int `json:"restaurant_id,omitempty"`
+}
+
+// InitWebhooks queues deliveries of events published by this instance and, with a
+// positive pollInterval, delivers due deliveries of every instance's events, trying
+// each up to maxAttempts times
+func InitWebhooks(sender *services.WebhookSender, timeout time.Duration, maxAttempts int, pollInterval time.Duration) {
+	webhookInitOnce.Do(func() {
+		webhooks = sender
+		webhookMaxAttempts = maxAttempts
+		webhookLease = timeout + 30*time.Second
+
+		events.Subscribe(func(e events.Event) {
+			// The instance that published the event queues it
+			if e.Remote {
+				return
+			}
+			if _, ok := webhookEventsByBusType[e.Type]; ok {
+				go enqueueWebhookEvent(context.Background(), e, time.Now())
+			}
+		})
+
+		if pollInterval <= 0 {
+			logger.Info("✅ Webhook events are queued; deliveries are left to other instances")
+			return
+		}
+		logger.Info("✅ Webhook deliveries every %s (up to %d attempts)", pollInterval, maxAttempts)
+		go runWebhookDeliveries(pollInterval)
+	})
+}
+
+// enqueueWebhookEvent queues a delivery of an event bus event to the webhooks subscribed to it
+func enqueueWebhookEvent(ctx context.Context, e events.Event, occurredAt time.Time) {
+	event, ok := webhookEventsByBusType[e.Type]
+	if !ok {
+		return
+	}
+	payload, err := json.Marshal(webhookPayload{
+		Event:      event,
+		OccurredAt: occurredAt.UTC(),
+		Data:       webhookPayloadData{ID: e.ID, RestaurantID: e.RestaurantID},
+	})
+	if err != nil {
+		logger.Error("❌ Failed to encode webhook event %s: %v", event, err)
+		return
+	}
+	queued, err := repos.Webhooks.Enqueue(ctx, event, payload)
+	if err != nil {
+		logger.Error("❌ Failed to queue webhook event %s: %v", event, err)
+		return
+	}
+	if queued > 0 {
+		logger.Debug("Queued %s for %d webhooks", event, queued)
+	}
+}
+
+func runWebhookDeliveries(pollInterval time.Duration) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	lastPrune := time.Time{}
+	for range ticker.C {
+		ctx := context.Background()
+		// Keep going while full batches are due, e.g. after an endpoint came back
+		for {
+			claimed, err := deliverDueWebhooks(ctx)
+			if err != nil {
+				logger.Error("❌ Failed to claim webhook deliveries: %v", err)
+			}
+			if err != nil || claimed < webhookBatchSize {
+				break
+			}
+		}
+
+		if time.Since(lastPrune) >= time.Hour {
+			lastPrune = time.Now()
+			if pruned, err := repos.Webhooks.PruneDeliveries(ctx, lastPrune.Add(-webhookDeliveryRetention)); err != nil {
+				logger.Warn("Failed to prune webhook deliveries: %v", err)
+			} else if pruned > 0 {
+				logger.Debug("Pruned %d webhook deliveries", pruned)
+			}
+		}
+	}
+}
+
+// deliverDueWebhooks sends a batch of due deliveries and records the outcomes. It returns
+// the number of deliveries claimed.
+func deliverDueWebhooks(ctx context.Context) (int, error) {
+	due, err := repos.Webhooks.ClaimDue(ctx, webhookBatchSize, webhookLease)
+	if err != nil {
+		return 0, err
+	}
+
+	var wg sync.WaitGroup
+	for _, d := range due {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			deliverWebhook(ctx, d)
+		}()
+	}
+	wg.Wait()
+	return len(due), nil
+}
+
+// deliverWebhook sends one delivery, scheduling a retry with exponential backoff when it
+// fails and attempts remain
+func deliverWebhook(ctx context.Context, d repository.DueWebhookDelivery) {
+	statusCode, err := webhooks.Send(ctx, d.URL, d.Secret, d.Event, d.ID, d.Payload)
+	if err == nil {
+		if err := repos.Webhooks.MarkDelivered(ctx, d.ID, statusCode); err != nil {
+			logger.Error("❌ Failed to record webhook delivery %d: %v", d.ID, err)
+		}
+		return
+	}
+
+	attempt := d.Attempts + 1
+	var retryAt *time.Time
+	if attempt < webhookMaxAttempts {
+		next := time.Now().Add(webhookRetryDelay(attempt))
+		retryAt = &next
+		logger.Warn("⚠️  Webhook delivery %d to webhook %d failed (attempt %d), retrying at %s: %v",
+			d.ID, d.WebhookID, attempt, next.Format(time.RFC3339), err)
+	} else {
+		logger.Error("❌ Webhook delivery %d to webhook %d failed after %d attempts: %v", d.ID, d.WebhookID, attempt, err)
+	}
+
+	var code *int
+	if statusCode > 0 {
+		code = &statusCode
+	}
+	if err := repos.Webhooks.MarkFailed(ctx, d.ID, code, err.Error(), retryAt); err != nil {
+		logger.Error("❌ Failed to record webhook delivery %d: %v", d.ID, err)
+	}
+}
+
+// webhookRetryDelay is the wait after the given failed attempt: 30s, 1m, 2m, ... up to 6h
+func webhookRetryDelay(attempt int) time.Duration {
+	delay := webhookFirstRetry
+	for i := 1; i < attempt && delay < webhookMaxRetry; i++ {
+		delay *= 2
+	}
+	return min(delay, webhookMaxRetry)
+}
+
+// webhookFromRequest validates a webhook registration or change
+func webhookFromRequest(req *models.WebhookRequest) (*models.Webhook, string) {
+	endpoint, err := url.Parse(req.URL)
+	if err != nil || (endpoint.Scheme != "https" && endpoint.Scheme != "http") || endpoint.Host == "" || len(req.URL) > 2048 {
+		return nil, "url must be an absolute http or https URL"
+	}
+	if len(req.Events) == 0 {
+		return nil, "events must name at least one event"
+	}
+	subscribed := []string{}
+	for _, event := range req.Events {
+		if !slices.Contains(webhookEventTypes, event) {
+			return nil, "Invalid event " + strconv.Quote(event) + ". Must be one of: restaurant.created, rating.created, suggestion.converted, photo.uploaded"
+		}
+		if !slices.Contains(subscribed, event) {
+			subscribed = append(subscribed, event)
+		}
+	}
+	if req.Secret != "" && (len(req.Secret) < 16 || len(req.Secret) > 255) {
+		return nil, "secret must be 16 to 255 characters"
+	}
+	if len(req.Description) > 255 {
+		return nil, "description must be at most 255 characters"
+	}
+
+	webhook := &models.Webhook{URL: req.URL, Secret: req.Secret, Events: subscribed, Active: true}
+	if req.Description != "" {
+		webhook.Description = &req.Description
+	}
+	if req.Active != nil {
+		webhook.Active = *req.Active
+	}
+	return webhook, ""
+}
+
+func generateWebhookSecret() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
+
+// @Summary List webhooks
+// @Description List the registered webhooks, without their secrets. Admin only.
+// @Tags Webhooks
+// @Produce json
+// @Success 200 {array} models.Webhook
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /admin/webhooks [get]
+func ListWebhooks(w http.ResponseWriter, r *http.Request) {
+	list, err := repos.Webhooks.List(r.Context())
+	if err != nil {
+		apperrors.Internal(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(list)
+}
+
+// @Summary Register a webhook
+// @Description Register an endpoint receiving the chosen events (restaurant.created, rating.created, suggestion.converted, photo.uploaded) as POST requests signed with the secret (X-Nomdb-Signature: t=<unix time>,v1=<hex HMAC-SHA256 of "<unix time>.<body>">). A secret is generated when none is given; it is only returned here. Failed deliveries are retried with backoff (WEBHOOK_MAX_ATTEMPTS). Admin only.
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param webhook body models.WebhookRequest true "Endpoint, events and secret"
+// @Success 201 {object} models.Webhook "Registered webhook with its secret"
+// @Failure 400 {object} errors.ErrorResponse "Invalid request"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /admin/webhooks [post]
+func CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	var req models.WebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apperrors.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	webhook, msg := webhookFromRequest(&req)
+	if msg != "" {
+		apperrors.Error(w, msg, http.StatusBadRequest)
+		return
+	}
+	if webhook.Secret == "" {
+		secret, err := generateWebhookSecret()
+		if err != nil {
+			apperrors.Internal(w, err)
+			return
+		}
+		webhook.Secret = secret
+	}
+	if user, ok := GetUserFromContext(r); ok {
+		webhook.CreatedByUserID = &user.ID
+	}
+
+	ctx := r.Context()
+	if err := repos.Webhooks.Create(ctx, webhook); err != nil {
+		apperrors.Internal(w, err)
+		return
+	}
+
+	logger.Info("🔔 Webhook %d registered for %v", webhook.ID, webhook.Events)
+	recordAdminAction(ctx, r, "create_webhook", "webhook", webhook.ID, map[string]any{
+		"url":    webhook.URL,
+		"events": webhook.Events,
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(webhook)
+}
+
+// @Summary Change a webhook
+// @Description Replace the endpoint, events, description and active flag of a webhook, and its secret when one is given. Pending deliveries of inactive webhooks wait until they are activated again. Admin only.
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param id path int true "Webhook ID"
+// @Param webhook body models.WebhookRequest true "Endpoint, events and secret"
+// @Success 200 {object} models.Webhook
+// @Failure 400 {object} errors.ErrorResponse "Invalid request"
+// @Failure 404 {object} errors.ErrorResponse "Webhook not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /admin/webhooks/{id} [put]
+func UpdateWebhook(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		apperrors.Error(w, "Invalid webhook ID", http.StatusBadRequest)
+		return
+	}
+
+	var req models.WebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		apperrors.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+	webhook, msg := webhookFromRequest(&req)
+	if msg != "" {
+		apperrors.Error(w, msg, http.StatusBadRequest)
+		return
+	}
+	webhook.ID = id
+
+	ctx := r.Context()
+	if err := repos.Webhooks.Update(ctx, webhook); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			apperrors.Error(w, "Webhook not found", http.StatusNotFound)
+			return
+		}
+		apperrors.Internal(w, err)
+		return
+	}
+	webhook.Secret = ""
+
+	recordAdminAction(ctx, r, "update_webhook", "webhook", id, map[string]any{
+		"url":            webhook.URL,
+		"events":         webhook.Events,
+		"active":         webhook.Active,
+		"secret_changed": req.Secret != "",
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(webhook)
+}
+
+// @Summary Delete a webhook
+// @Description Remove a webhook with its pending deliveries and delivery log. Admin only.
+// @Tags Webhooks
+// @Param id path int true "Webhook ID"
+// @Success 204 "Deleted"
+// @Failure 400 {object} errors.ErrorResponse "Invalid webhook ID"
+// @Failure 404 {object} errors.ErrorResponse "Webhook not found"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /admin/webhooks/{id} [delete]
+func DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		apperrors.Error(w, "Invalid webhook ID", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	if err := repos.Webhooks.Delete(ctx, id); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			apperrors.Error(w, "Webhook not found", http.StatusNotFound)
+			return
+		}
+		apperrors.Internal(w, err)
+		return
+	}
+
+	recordAdminAction(ctx, r, "delete_webhook", "webhook", id, nil)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// @Summary List deliveries of a webhook
+// @Description List the deliveries of a webhook, newest first, with their payload, attempts, and the response status and error of the last attempt, to debug an endpoint. Finished deliveries are kept for 30 days. Admin only.
+// @Tags Webhooks
+// @Produce json
+// @Param id path int true "Webhook ID"
+// @Param status query string false "Filter by status (pending, delivered, failed)"
+// @Param limit query int false "Deliveries to return (default 50, max 200)"
+// @Success 200 {array} models.WebhookDelivery
+// @Failure 400 {object} errors.ErrorResponse "Invalid parameters"
+// @Failure 500 {object} errors.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /admin/webhooks/{id}/deliveries [get]
+func GetWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		apperrors.Error(w, "Invalid webhook ID", http.StatusBadRequest)
+		return
+	}
+
+	query := r.URL.Query()
+	status := query.Get("status")
+	switch status {
+	case "", repository.WebhookDeliveryPending, repository.WebhookDeliveryDelivered, repository.WebhookDeliveryFailed:
+	default:
+		apperrors.Error(w, "Invalid status. Must be one of: pending, delivered, failed", http.StatusBadRequest)
+		return
+	}
+	limit := 50
+	if v := query.Get("limit"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 1 || parsed > maxWebhookDeliveries {
+			apperrors.Error(w, "limit must be between 1 and 200", http.StatusBadRequest)
+			return
+		}
+		limit = parsed
+	}
+
+	deliveries, err := repos.Webhooks.Deliveries(r.Context(), id, status, limit)
+	if err != nil {
+		apperrors.Internal(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(deliveries)
+}
@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/nomdb/backend/internal/events"
+	"github.com/nomdb/backend/internal/models"
+	"github.com/pashagolub/pgxmock/v4"
+)
+
+func TestWebhookFromRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     models.WebhookRequest
+		wantErr bool
+	}{
+		{"valid", models.WebhookRequest{URL: "https://example.com/hook", Events: []string{"rating.created"}}, false},
+		{"relative URL", models.WebhookRequest{URL: "/hook", Events: []string{"rating.created"}}, true},
+		{"other scheme", models.WebhookRequest{URL: "ftp://example.com/hook", Events: []string{"rating.created"}}, true},
+		{"no events", models.WebhookRequest{URL: "https://example.com/hook"}, true},
+		{"unknown event", models.WebhookRequest{URL: "https://example.com/hook", Events: []string{"rating.deleted"}}, true},
+		{"short secret", models.WebhookRequest{URL: "https://example.com/hook", Events: []string{"rating.created"}, Secret: "abc"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, msg := webhookFromRequest(&tt.req)
+			if (msg != "") != tt.wantErr {
+				t.Errorf("webhookFromRequest() message = %q, wantErr %v", msg, tt.wantErr)
+			}
+		})
+	}
+
+	active := false
+	webhook, _ := webhookFromRequest(&models.WebhookRequest{
+		URL: "https://example.com/hook", Events: []string{"rating.created", "rating.created"}, Active: &active,
+	})
+	if len(webhook.Events) != 1 || webhook.Active || webhook.Description != nil {
+		t.Errorf("Unexpected webhook %+v", webhook)
+	}
+}
+
+func TestWebhookRetryDelay(t *testing.T) {
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{5, 8 * time.Minute},
+		{20, 6 * time.Hour},
+	}
+	for _, tt := range tests {
+		if got := webhookRetryDelay(tt.attempt); got != tt.want {
+			t.Errorf("webhookRetryDelay(%d) = %s, want %s", tt.attempt, got, tt.want)
+		}
+	}
+}
+
+func TestEnqueueWebhookEvent(t *testing.T) {
+	mock := withMockRepositories(t)
+	occurredAt := time.Date(2026, 10, 15, 9, 30, 0, 0, time.UTC)
+
+	// Photos count as uploaded once processed
+	mock.ExpectExec(`INSERT INTO webhook_deliveries`).
+		WithArgs("photo.uploaded", []byte(`{"event":"photo.uploaded","occurred_at":"2026-10-15T09:30:00Z","data":{"id":9,"restaurant_id":3}}`)).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+	enqueueWebhookEvent(context.Background(), events.Event{Type: eventPhotoReady, ID: 9, RestaurantID: 3}, occurredAt)
+	// Not a webhook event
+	enqueueWebhookEvent(context.Background(), events.Event{Type: eventRatingDeleted, ID: 4}, occurredAt)
+}
+
+// fakeWebhookSender fails deliveries to failURL with a 500
+type fakeWebhookSender struct {
+	failURL string
+}
+
+func (f fakeWebhookSender) Send(_ context.Context, url, _, _ string, _ int64, _ []byte) (int, error) {
+	if url == f.failURL {
+		return http.StatusInternalServerError, errors.New("unexpected status 500")
+	}
+	return http.StatusOK, nil
+}
+
+func withWebhookSender(t *testing.T, sender webhookSender) {
+	t.Helper()
+	previous := webhooks
+	webhooks = sender
+	t.Cleanup(func() { webhooks = previous })
+}
+
+func TestDeliverDueWebhooks(t *testing.T) {
+	mock := withMockRepositories(t)
+	mock.MatchExpectationsInOrder(false)
+	withWebhookSender(t, fakeWebhookSender{failURL: "https://down.example.com"})
+	now := time.Now()
+	columns := []string{"id", "webhook_id", "event", "payload", "status", "attempts", "next_attempt_at",
+		"last_status_code", "last_error", "delivered_at", "created_at", "url", "secret"}
+
+	mock.ExpectQuery(`FOR UPDATE OF d SKIP LOCKED`).WithArgs(webhookBatchSize, webhookLease.Seconds()).
+		WillReturnRows(pgxmock.NewRows(columns).
+			AddRow(int64(1), 1, "rating.created", []byte(`{}`), "pending", 0, now, nil, nil, nil, now, "https://up.example.com", "s3cret").
+			AddRow(int64(2), 2, "rating.created", []byte(`{}`), "pending", 0, now, nil, nil, nil, now, "https://down.example.com", "s3cret").
+			AddRow(int64(3), 2, "restaurant.created", []byte(`{}`), "pending", webhookMaxAttempts-1, now, nil, nil, nil, now, "https://down.example.com", "s3cret"))
+	mock.ExpectExec(`SET status = 'delivered'`).WithArgs(int64(1), http.StatusOK).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	code := http.StatusInternalServerError
+	// Retried after the first attempt, failed for good after the last
+	mock.ExpectExec(`SET status = \$2`).WithArgs(int64(2), "pending", &code, "unexpected status 500", pgxmock.AnyArg()).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	mock.ExpectExec(`SET status = \$2`).WithArgs(int64(3), "failed", &code, "unexpected status 500", (*time.Time)(nil)).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+	claimed, err := deliverDueWebhooks(context.Background())
+	if err != nil || claimed != 3 {
+		t.Errorf("deliverDueWebhooks = %d, %v; want 3 deliveries", claimed, err)
+	}
+}
+
+func TestGetWebhookDeliveries_InvalidStatus(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/api/admin/webhooks/2/deliveries?status=sent", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "2"})
+	rec := httptest.NewRecorder()
+
+	GetWebhookDeliveries(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,87 @@
+// Package i18n negotiates the language of responses. The locale of a request is chosen
+// from its Accept-Language header by the locale middleware and carried in the request
+// context; category and food type names and error messages are translated into it.
+package i18n
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Supported locales
+const (
+	English = "en"
+	German  = "de"
+
+	// Default is the language of stored names and of messages in the code
+	Default = English
+)
+
+// Supported lists the locales responses can be translated into
+var Supported = []string{English, German}
+
+type contextKey struct{}
+
+// IsSupported reports whether locale is one of Supported
+func IsSupported(locale string) bool {
+	for _, l := range Supported {
+		if l == locale {
+			return true
+		}
+	}
+	return false
+}
+
+// Negotiate returns the supported locale preferred by an Accept-Language header, or
+// Default. Regional variants match their language ("de-AT" selects "de"), and ranges
+// with q=0 are excluded.
+func Negotiate(acceptLanguage string) string {
+	type weighted struct {
+		locale string
+		q      float64
+	}
+	var ranges []weighted
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		language, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+		if q <= 0 || language == "" {
+			continue
+		}
+		if language == "*" {
+			language = Default
+		}
+		ranges = append(ranges, weighted{language, q})
+	}
+
+	// Equal weights keep the order of the header
+	sort.SliceStable(ranges, func(i, j int) bool { return ranges[i].q > ranges[j].q })
+	for _, r := range ranges {
+		if IsSupported(r.locale) {
+			return r.locale
+		}
+	}
+	return Default
+}
+
+// WithLocale returns a context carrying locale
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, contextKey{}, locale)
+}
+
+// FromContext returns the locale of the request, or Default outside of requests
+func FromContext(ctx context.Context) string {
+	if locale, ok := ctx.Value(contextKey{}).(string); ok {
+		return locale
+	}
+	return Default
+}
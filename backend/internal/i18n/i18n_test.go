@@ -0,0 +1,39 @@
+package i18n
+
+import (
+	"context"
+	"testing"
+)
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"", English},
+		{"de", German},
+		{"de-AT,de;q=0.9,en;q=0.8", German},
+		{"fr-FR,fr;q=0.9,de;q=0.8,en;q=0.7", German},
+		{"en;q=0.5,de;q=0.8", German},
+		{"en-GB, de", English},
+		{"DE-de", German},
+		{"de;q=0,fr", English},
+		{"fr", English},
+		{"*", English},
+		{"de;q=abc,en", English},
+	}
+	for _, tt := range tests {
+		if got := Negotiate(tt.header); got != tt.want {
+			t.Errorf("Negotiate(%q) = %q, want %q", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	if got := FromContext(context.Background()); got != Default {
+		t.Errorf("Expected the default locale outside requests, got %q", got)
+	}
+	if got := FromContext(WithLocale(context.Background(), German)); got != German {
+		t.Errorf("Expected %q, got %q", German, got)
+	}
+}
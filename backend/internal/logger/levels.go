@@ -0,0 +1,204 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// Levels accepted by SetLevels
+var levelNames = []string{"debug", "info", "warn", "error"}
+
+// levelState is the log levels in effect: a default and overrides per module, with the
+// debug samplers that go with them
+type levelState struct {
+	base           zerolog.Level
+	modules        map[string]zerolog.Level
+	sampler        zerolog.Sampler
+	moduleSamplers map[string]zerolog.Sampler
+}
+
+var (
+	levels atomic.Pointer[levelState]
+
+	// debugPerSecond caps the debug messages per second of each module and of those
+	// logged without one (0 = unlimited)
+	debugPerSecond int
+
+	modulesMu sync.Mutex
+	modules   = map[string]*ModuleLogger{}
+)
+
+// ModuleLogger logs the messages of one part of the server, such as the photo pipeline,
+// at a level that can be set apart from the default with SetLevels
+type ModuleLogger struct {
+	name      string
+	requestID string // Set by Ctx
+}
+
+// Module returns the logger of the named module, registering it so that SetLevels
+// accepts the name. Declare module loggers as package variables.
+func Module(name string) *ModuleLogger {
+	modulesMu.Lock()
+	defer modulesMu.Unlock()
+	if m, ok := modules[name]; ok {
+		return m
+	}
+	m := &ModuleLogger{name: name}
+	modules[name] = m
+	return m
+}
+
+// Modules returns the names of the registered modules
+func Modules() []string {
+	modulesMu.Lock()
+	defer modulesMu.Unlock()
+	return slices.Sorted(maps.Keys(modules))
+}
+
+// Ctx returns the module logger adding the request ID of ctx to messages, so the work
+// done for an API request can be found by its ID
+func (m *ModuleLogger) Ctx(ctx context.Context) *ModuleLogger {
+	id := RequestID(ctx)
+	if id == "" {
+		return m
+	}
+	return &ModuleLogger{name: m.name, requestID: id}
+}
+
+// level returns the level in effect for the module
+func (m *ModuleLogger) level(state *levelState) zerolog.Level {
+	if level, ok := state.modules[m.name]; ok {
+		return level
+	}
+	return state.base
+}
+
+// event starts a message of the module at level, nil if it is not logged
+func (m *ModuleLogger) event(level zerolog.Level) *zerolog.Event {
+	state := levels.Load()
+	l := Logger.Level(m.level(state)).Sample(state.moduleSamplers[m.name])
+	event := l.WithLevel(level).Str("module", m.name)
+	if m.requestID != "" {
+		event = event.Str("request_id", m.requestID)
+	}
+	return event
+}
+
+// DebugEnabled reports whether debug messages of the module are logged, to skip
+// preparing expensive ones
+func (m *ModuleLogger) DebugEnabled() bool {
+	return m.level(levels.Load()) <= zerolog.DebugLevel
+}
+
+// Debug logs a debug message of the module
+func (m *ModuleLogger) Debug(format string, v ...interface{}) {
+	m.event(zerolog.DebugLevel).Msgf(format, v...)
+}
+
+// Info logs an info message of the module
+func (m *ModuleLogger) Info(format string, v ...interface{}) {
+	m.event(zerolog.InfoLevel).Msgf(format, v...)
+}
+
+// Warn logs a warning of the module
+func (m *ModuleLogger) Warn(format string, v ...interface{}) {
+	m.event(zerolog.WarnLevel).Msgf(format, v...)
+}
+
+// Error logs an error of the module
+func (m *ModuleLogger) Error(format string, v ...interface{}) {
+	m.event(zerolog.ErrorLevel).Msgf(format, v...)
+}
+
+// base returns Logger with the default level and debug sampler
+func base() *zerolog.Logger {
+	state := levels.Load()
+	l := Logger.Level(state.base).Sample(state.sampler)
+	return &l
+}
+
+// SetLevels sets the default log level and the levels of modules that differ from it
+// (debug, info, warn or error), replacing earlier overrides. Unknown levels and
+// modules are rejected.
+func SetLevels(defaultLevel string, moduleLevels map[string]string) error {
+	state := &levelState{modules: make(map[string]zerolog.Level, len(moduleLevels))}
+	var err error
+	if state.base, err = parseLevel(defaultLevel); err != nil {
+		return err
+	}
+	registered := Modules()
+	for name, value := range moduleLevels {
+		if !slices.Contains(registered, name) {
+			return fmt.Errorf("unknown log module %q, expected one of %v", name, registered)
+		}
+		if state.modules[name], err = parseLevel(value); err != nil {
+			return err
+		}
+	}
+	storeLevels(state)
+	return nil
+}
+
+// SetDebugSampling caps the debug messages logged per second by each module, and by the
+// rest of the server together, dropping the others; 0 logs all of them
+func SetDebugSampling(perSecond int) {
+	modulesMu.Lock()
+	debugPerSecond = perSecond
+	modulesMu.Unlock()
+
+	current := levels.Load()
+	storeLevels(&levelState{base: current.base, modules: current.modules})
+}
+
+// storeLevels puts state into effect with fresh debug samplers. The zerolog global level
+// is the lowest level in effect, as it filters messages before the level of a logger.
+func storeLevels(state *levelState) {
+	modulesMu.Lock()
+	perSecond := debugPerSecond
+	modulesMu.Unlock()
+
+	state.sampler = newDebugSampler(perSecond)
+	state.moduleSamplers = make(map[string]zerolog.Sampler)
+	for _, name := range Modules() {
+		state.moduleSamplers[name] = newDebugSampler(perSecond)
+	}
+
+	lowest := state.base
+	for _, level := range state.modules {
+		lowest = min(lowest, level)
+	}
+	zerolog.SetGlobalLevel(lowest)
+	levels.Store(state)
+}
+
+// newDebugSampler lets through perSecond debug messages a second, nil for all
+func newDebugSampler(perSecond int) zerolog.Sampler {
+	if perSecond <= 0 {
+		return nil
+	}
+	return zerolog.LevelSampler{DebugSampler: &zerolog.BurstSampler{Burst: uint32(perSecond), Period: time.Second}}
+}
+
+// CurrentLevels returns the default log level and the overridden levels of modules
+func CurrentLevels() (string, map[string]string) {
+	state := levels.Load()
+	moduleLevels := make(map[string]string, len(state.modules))
+	for name, level := range state.modules {
+		moduleLevels[name] = level.String()
+	}
+	return state.base.String(), moduleLevels
+}
+
+func parseLevel(value string) (zerolog.Level, error) {
+	if !slices.Contains(levelNames, value) {
+		return zerolog.NoLevel, fmt.Errorf("invalid log level %q, expected one of %v", value, levelNames)
+	}
+	return zerolog.ParseLevel(value)
+}
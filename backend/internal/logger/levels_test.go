@@ -0,0 +1,121 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+// captureLogs sends the log output to a buffer and restores the levels afterwards
+func captureLogs(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	previous, previousLevels := Logger, levels.Load()
+	Logger = zerolog.New(&buf)
+	t.Cleanup(func() {
+		Logger = previous
+		SetDebugSampling(0)
+		storeLevels(&levelState{base: previousLevels.base, modules: previousLevels.modules})
+	})
+	return &buf
+}
+
+func TestModuleLevels(t *testing.T) {
+	buf := captureLogs(t)
+	photos := Module("test_photos")
+	storage := Module("test_storage")
+
+	if err := SetLevels("info", map[string]string{"test_photos": "debug", "test_storage": "error"}); err != nil {
+		t.Fatalf("SetLevels failed: %v", err)
+	}
+	Debug("default debug")
+	photos.Debug("photos debug")
+	storage.Warn("storage warning")
+	storage.Error("storage error")
+	Info("default info")
+
+	out := buf.String()
+	for _, msg := range []string{"photos debug", "storage error", "default info"} {
+		if !strings.Contains(out, msg) {
+			t.Errorf("Expected %q to be logged, got %s", msg, out)
+		}
+	}
+	for _, msg := range []string{"default debug", "storage warning"} {
+		if strings.Contains(out, msg) {
+			t.Errorf("Expected %q not to be logged, got %s", msg, out)
+		}
+	}
+	if !strings.Contains(out, `"module":"test_photos"`) {
+		t.Errorf("Expected module messages to name the module, got %s", out)
+	}
+	if !photos.DebugEnabled() || IsDebugMode() {
+		t.Error("Expected debug for the photos module only")
+	}
+
+	level, modules := CurrentLevels()
+	if level != "info" || modules["test_photos"] != "debug" || len(modules) != 2 {
+		t.Errorf("Unexpected levels %s %v", level, modules)
+	}
+}
+
+func TestSetLevelsValidation(t *testing.T) {
+	captureLogs(t)
+	if err := SetLevels("verbose", nil); err == nil {
+		t.Error("Expected an invalid level to be rejected")
+	}
+	if err := SetLevels("info", map[string]string{"no_such_module": "debug"}); err == nil {
+		t.Error("Expected an unknown module to be rejected")
+	}
+	if err := SetLevels("trace", nil); err == nil {
+		t.Error("Expected levels other than debug, info, warn and error to be rejected")
+	}
+}
+
+func TestDebugSampling(t *testing.T) {
+	buf := captureLogs(t)
+	photos := Module("test_sampled")
+	if err := SetLevels("debug", nil); err != nil {
+		t.Fatalf("SetLevels failed: %v", err)
+	}
+	SetDebugSampling(3)
+
+	for i := 0; i < 10; i++ {
+		photos.Debug("sampled")
+		Debug("default")
+	}
+	photos.Info("not sampled")
+
+	out := buf.String()
+	if n := strings.Count(out, `"message":"sampled"`); n != 3 {
+		t.Errorf("Expected 3 sampled debug messages of the module, got %d", n)
+	}
+	if n := strings.Count(out, `"message":"default"`); n != 3 {
+		t.Errorf("Expected 3 debug messages without module, got %d", n)
+	}
+	if !strings.Contains(out, "not sampled") {
+		t.Error("Expected messages above debug not to be sampled")
+	}
+}
+
+func TestModuleLoggerCtx(t *testing.T) {
+	buf := captureLogs(t)
+	photos := Module("test_ctx")
+
+	ctx := context.WithValue(context.Background(), RequestIDKey, "req-123")
+	photos.Ctx(ctx).Info("with request")
+	photos.Ctx(context.Background()).Info("without request")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 messages, got %s", buf.String())
+	}
+	if !strings.Contains(lines[0], `"request_id":"req-123"`) {
+		t.Errorf("Expected the request ID of the context, got %s", lines[0])
+	}
+	if strings.Contains(lines[1], "request_id") {
+		t.Errorf("Expected no request ID outside of requests, got %s", lines[1])
+	}
+}
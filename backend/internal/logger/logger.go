@@ -10,8 +10,11 @@ import (
 )
 
 var (
-	debugMode bool
-	Logger    zerolog.Logger
+	Logger zerolog.Logger
+
+	// Requests and database queries taking at least this long are logged as warnings
+	slowRequestThreshold = time.Second
+	slowQueryThreshold   = 250 * time.Millisecond
 )
 
 // Context key for request ID
@@ -19,22 +22,36 @@ type contextKey string
 
 const RequestIDKey contextKey = "request_id"
 
+// RequestIDHeader carries the request ID of API requests, and of the calls they make to
+// other services
+const RequestIDHeader = "X-Request-ID"
+
+// databaseLog logs database queries; set its level to debug to log every query
+var databaseLog = Module("database")
+
 func init() {
-	// Check if debug mode is enabled
-	debugMode = os.Getenv("DEBUG") == "true"
+	Configure(os.Getenv("DEBUG") == "true", os.Getenv("LOG_FORMAT"))
+}
 
+// Configure sets debug logging and the output format: json, or anything else for
+// human-readable console output. init applies DEBUG and LOG_FORMAT so logging works
+// before the configuration is loaded. Module levels set before are kept.
+func Configure(debug bool, format string) {
 	// Configure zerolog
 	zerolog.TimeFieldFormat = time.RFC3339
 
 	// Set log level
-	if debugMode {
-		zerolog.SetGlobalLevel(zerolog.DebugLevel)
-	} else {
-		zerolog.SetGlobalLevel(zerolog.InfoLevel)
+	state := &levelState{base: zerolog.InfoLevel}
+	if debug {
+		state.base = zerolog.DebugLevel
+	}
+	if current := levels.Load(); current != nil {
+		state.modules = current.modules
 	}
+	storeLevels(state)
 
 	// Use pretty console output in development
-	if os.Getenv("LOG_FORMAT") != "json" {
+	if format != "json" {
 		Logger = log.Output(zerolog.ConsoleWriter{
 			Out:        os.Stdout,
 			TimeFormat: "2006-01-02 15:04:05",
@@ -46,12 +63,12 @@ func init() {
 
 // Debug logs a debug message with optional fields
 func Debug(format string, v ...interface{}) {
-	Logger.Debug().Msgf(format, v...)
+	base().Debug().Msgf(format, v...)
 }
 
 // DebugWithFields logs a debug message with structured fields
 func DebugWithFields(msg string, fields map[string]interface{}) {
-	event := Logger.Debug()
+	event := base().Debug()
 	for k, v := range fields {
 		event = event.Interface(k, v)
 	}
@@ -60,12 +77,12 @@ func DebugWithFields(msg string, fields map[string]interface{}) {
 
 // Info logs an info message
 func Info(format string, v ...interface{}) {
-	Logger.Info().Msgf(format, v...)
+	base().Info().Msgf(format, v...)
 }
 
 // InfoWithFields logs an info message with structured fields
 func InfoWithFields(msg string, fields map[string]interface{}) {
-	event := Logger.Info()
+	event := base().Info()
 	for k, v := range fields {
 		event = event.Interface(k, v)
 	}
@@ -74,12 +91,12 @@ func InfoWithFields(msg string, fields map[string]interface{}) {
 
 // Warn logs a warning message
 func Warn(format string, v ...interface{}) {
-	Logger.Warn().Msgf(format, v...)
+	base().Warn().Msgf(format, v...)
 }
 
 // WarnWithFields logs a warning message with structured fields
 func WarnWithFields(msg string, fields map[string]interface{}) {
-	event := Logger.Warn()
+	event := base().Warn()
 	for k, v := range fields {
 		event = event.Interface(k, v)
 	}
@@ -88,12 +105,12 @@ func WarnWithFields(msg string, fields map[string]interface{}) {
 
 // Error logs an error message
 func Error(format string, v ...interface{}) {
-	Logger.Error().Msgf(format, v...)
+	base().Error().Msgf(format, v...)
 }
 
 // ErrorWithFields logs an error message with structured fields
 func ErrorWithFields(msg string, fields map[string]interface{}) {
-	event := Logger.Error()
+	event := base().Error()
 	for k, v := range fields {
 		event = event.Interface(k, v)
 	}
@@ -102,26 +119,39 @@ func ErrorWithFields(msg string, fields map[string]interface{}) {
 
 // Fatal logs a fatal message and exits
 func Fatal(format string, v ...interface{}) {
-	Logger.Fatal().Msgf(format, v...)
+	base().Fatal().Msgf(format, v...)
 }
 
-// IsDebugMode returns whether debug mode is enabled
+// IsDebugMode returns whether debug messages are logged by default
 func IsDebugMode() bool {
-	return debugMode
+	return levels.Load().base <= zerolog.DebugLevel
+}
+
+// RequestID returns the ID of the API request ctx belongs to, "" outside of requests
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(RequestIDKey).(string)
+	return id
 }
 
 // WithRequestID creates a context-aware logger with request ID
 func WithRequestID(ctx context.Context) *zerolog.Logger {
 	if requestID, ok := ctx.Value(RequestIDKey).(string); ok {
-		logger := Logger.With().Str("request_id", requestID).Logger()
+		logger := base().With().Str("request_id", requestID).Logger()
 		return &logger
 	}
-	return &Logger
+	return base()
+}
+
+// SetSlowThresholds sets the durations from which requests and database queries are
+// logged as slow (0 = never)
+func SetSlowThresholds(request, query time.Duration) {
+	slowRequestThreshold = request
+	slowQueryThreshold = query
 }
 
 // LogRequest logs an HTTP request with structured data
 func LogRequest(method, path, requestID, ip string, duration time.Duration, status int, bytes int64) {
-	event := Logger.Info()
+	event := base().Info()
 
 	if requestID != "" {
 		event = event.Str("request_id", requestID)
@@ -139,20 +169,59 @@ func LogRequest(method, path, requestID, ip string, duration time.Duration, stat
 
 // LogError logs an error with additional context
 func LogError(err error, msg string, fields map[string]interface{}) {
-	event := Logger.Error().Err(err)
+	event := base().Error().Err(err)
 	for k, v := range fields {
 		event = event.Interface(k, v)
 	}
 	event.Msg(msg)
 }
 
-// LogDatabaseQuery logs a database query with performance metrics
-func LogDatabaseQuery(query string, duration time.Duration, rows int) {
-	if debugMode {
-		Logger.Debug().
-			Str("query", query).
-			Dur("duration", duration).
-			Int("rows", rows).
-			Msg("Database query executed")
+// LogSlowRequest warns about a request that took at least the slow request threshold.
+// Only the names of query parameters are logged, as their values may be sensitive.
+func LogSlowRequest(method, route, path, requestID string, queryParams []string, duration time.Duration, status int) {
+	if slowRequestThreshold <= 0 || duration < slowRequestThreshold {
+		return
+	}
+
+	event := base().Warn()
+	if requestID != "" {
+		event = event.Str("request_id", requestID)
+	}
+	if len(queryParams) > 0 {
+		event = event.Strs("query_params", queryParams)
+	}
+
+	event.
+		Str("method", method).
+		Str("route", route).
+		Str("path", path).
+		Dur("duration", duration).
+		Dur("threshold", slowRequestThreshold).
+		Int("status", status).
+		Msg("Slow HTTP request")
+}
+
+// LogDatabaseQuery logs a database query with performance metrics: as a warning if it
+// took at least the slow query threshold, otherwise only in debug mode
+func LogDatabaseQuery(ctx context.Context, query string, duration time.Duration, rows int64) {
+	slow := slowQueryThreshold > 0 && duration >= slowQueryThreshold
+	if !slow && !databaseLog.DebugEnabled() {
+		return
 	}
+
+	event := databaseLog.event(zerolog.DebugLevel)
+	msg := "Database query executed"
+	if slow {
+		event = databaseLog.event(zerolog.WarnLevel).Dur("threshold", slowQueryThreshold)
+		msg = "Slow database query"
+	}
+	if requestID, ok := ctx.Value(RequestIDKey).(string); ok {
+		event = event.Str("request_id", requestID)
+	}
+
+	event.
+		Str("query", query).
+		Dur("duration", duration).
+		Int64("rows", rows).
+		Msg(msg)
 }
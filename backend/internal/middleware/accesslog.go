@@ -0,0 +1,157 @@
+package middleware
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nomdb/backend/internal/logger"
+)
+
+// Access log formats
+const (
+	AccessLogCombined = "combined" // Apache/NCSA combined log format
+	AccessLogCommon   = "common"   // Apache/NCSA common log format
+	AccessLogJSON     = "json"     // One JSON object per line
+)
+
+// accessLogTimeFormat is the %t timestamp of the Apache formats
+const accessLogTimeFormat = "02/Jan/2006:15:04:05 -0700"
+
+// sensitiveQueryParams have their values replaced in access logs: OAuth codes and
+// state, and tokens some clients pass in the query string
+var sensitiveQueryParams = map[string]bool{
+	"code":         true,
+	"state":        true,
+	"token":        true,
+	"access_token": true,
+	"api_key":      true,
+}
+
+// accessLogEntry is a line of the JSON access log
+type accessLogEntry struct {
+	Time       string  `json:"time"`
+	RemoteAddr string  `json:"remote_addr"`
+	Method     string  `json:"method"`
+	URI        string  `json:"uri"`
+	Protocol   string  `json:"protocol"`
+	Status     int     `json:"status"`
+	Bytes      int64   `json:"bytes"`
+	DurationMS float64 `json:"duration_ms"`
+	Referer    string  `json:"referer,omitempty"`
+	UserAgent  string  `json:"user_agent,omitempty"`
+	RequestID  string  `json:"request_id,omitempty"`
+}
+
+// AccessLogMiddleware writes a line per request to out, separately from the
+// application logs, for tools like GoAccess or Loki that parse standard access logs.
+// It should run outermost, so that requests rejected by other middleware and panics
+// answered by the recovery middleware are logged too.
+func AccessLogMiddleware(out io.Writer, format string) func(http.Handler) http.Handler {
+	var mu sync.Mutex
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			rw := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+
+			next.ServeHTTP(rw, r)
+
+			line := formatAccessLog(format, r, rw, start, time.Since(start))
+			mu.Lock()
+			defer mu.Unlock()
+			if _, err := io.WriteString(out, line); err != nil {
+				logger.Error("Failed to write access log: %v", err)
+			}
+		})
+	}
+}
+
+// formatAccessLog returns the access log line of a request, including the newline
+func formatAccessLog(format string, r *http.Request, rw *responseWriter, start time.Time, duration time.Duration) string {
+	uri := redactedRequestURI(r.URL)
+
+	if format == AccessLogJSON {
+		line, err := json.Marshal(accessLogEntry{
+			Time:       start.Format(time.RFC3339),
+			RemoteAddr: ClientIP(r),
+			Method:     r.Method,
+			URI:        uri,
+			Protocol:   r.Proto,
+			Status:     rw.statusCode,
+			Bytes:      rw.bytes,
+			DurationMS: float64(duration.Microseconds()) / 1000,
+			Referer:    r.Referer(),
+			UserAgent:  r.UserAgent(),
+			RequestID:  rw.Header().Get("X-Request-ID"),
+		})
+		if err != nil {
+			return "" // Cannot happen, all fields are strings and numbers
+		}
+		return string(line) + "\n"
+	}
+
+	bytes := "-"
+	if rw.bytes > 0 {
+		bytes = strconv.FormatInt(rw.bytes, 10)
+	}
+	// Users are authenticated inside the router, so %u is unknown here
+	line := fmt.Sprintf(`%s - - [%s] "%s %s %s" %d %s`,
+		ClientIP(r), start.Format(accessLogTimeFormat),
+		escapeAccessLog(r.Method), escapeAccessLog(uri), escapeAccessLog(r.Proto),
+		rw.statusCode, bytes)
+	if format == AccessLogCombined {
+		line += fmt.Sprintf(` "%s" "%s"`, accessLogField(r.Referer()), accessLogField(r.UserAgent()))
+	}
+	return line + "\n"
+}
+
+// redactedRequestURI returns the path and query of u with the values of sensitive
+// query parameters replaced
+func redactedRequestURI(u *url.URL) string {
+	if u.RawQuery == "" {
+		return u.RequestURI()
+	}
+
+	params := strings.Split(u.RawQuery, "&")
+	for i, param := range params {
+		name, _, found := strings.Cut(param, "=")
+		if decoded, err := url.QueryUnescape(name); err == nil && found && sensitiveQueryParams[strings.ToLower(decoded)] {
+			params[i] = name + "=REDACTED"
+		}
+	}
+	return u.EscapedPath() + "?" + strings.Join(params, "&")
+}
+
+// accessLogField returns "-" for empty header values, as Apache does
+func accessLogField(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return escapeAccessLog(value)
+}
+
+// escapeAccessLog escapes quotes, backslashes and control characters as Apache does,
+// so client-supplied values cannot forge log lines
+func escapeAccessLog(value string) string {
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case c == '"' || c == '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case c < 0x20 || c == 0x7f:
+			fmt.Fprintf(&b, `\x%02x`, c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
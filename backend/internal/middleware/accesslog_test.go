@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func serveAccessLogged(t *testing.T, format string, req *http.Request) string {
+	t.Helper()
+	var buf bytes.Buffer
+	handler := AccessLogMiddleware(&buf, format)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-ID", "req-123")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte("hello"))
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	return buf.String()
+}
+
+func TestAccessLogMiddleware_Combined(t *testing.T) {
+	req := httptest.NewRequest("POST", "/api/restaurants?sort=name", nil)
+	req.RemoteAddr = "203.0.113.7:51234"
+	req.Header.Set("Referer", "https://nomdb.example/")
+	req.Header.Set("User-Agent", `curl/8.0 "quoted"`)
+
+	line := serveAccessLogged(t, AccessLogCombined, req)
+
+	pattern := regexp.MustCompile(`^203\.0\.113\.7 - - \[\d{2}/\w{3}/\d{4}:\d{2}:\d{2}:\d{2} [+-]\d{4}\] "POST /api/restaurants\?sort=name HTTP/1\.1" 201 5 "https://nomdb\.example/" "curl/8\.0 \\"quoted\\""\n$`)
+	if !pattern.MatchString(line) {
+		t.Errorf("Unexpected combined log line: %q", line)
+	}
+}
+
+func TestAccessLogMiddleware_Common(t *testing.T) {
+	req := httptest.NewRequest("GET", "/api/categories", nil)
+	req.Header.Set("User-Agent", "curl/8.0")
+
+	line := serveAccessLogged(t, AccessLogCommon, req)
+
+	if !strings.HasSuffix(line, `"GET /api/categories HTTP/1.1" 201 5`+"\n") {
+		t.Errorf("Unexpected common log line: %q", line)
+	}
+}
+
+func TestAccessLogMiddleware_JSON(t *testing.T) {
+	req := httptest.NewRequest("GET", "/api/auth/oidc/callback?code=secret&state=abc&provider=google", nil)
+
+	line := serveAccessLogged(t, AccessLogJSON, req)
+
+	var entry accessLogEntry
+	if err := json.Unmarshal([]byte(line), &entry); err != nil {
+		t.Fatalf("Failed to decode JSON access log line %q: %v", line, err)
+	}
+	if entry.Method != "GET" || entry.Status != http.StatusCreated || entry.Bytes != 5 || entry.RequestID != "req-123" {
+		t.Errorf("Unexpected JSON access log entry: %+v", entry)
+	}
+	if entry.URI != "/api/auth/oidc/callback?code=REDACTED&state=REDACTED&provider=google" {
+		t.Errorf("Expected sensitive query parameters to be redacted, got %s", entry.URI)
+	}
+}
+
+func TestEscapeAccessLog(t *testing.T) {
+	tests := map[string]string{
+		"plain":           "plain",
+		`say "hi"`:        `say \"hi\"`,
+		`back\slash`:      `back\\slash`,
+		"line\nbreak":     `line\x0abreak`,
+		"tab\tand\x7fdel": `tab\x09and\x7fdel`,
+	}
+	for input, expected := range tests {
+		if got := escapeAccessLog(input); got != expected {
+			t.Errorf("escapeAccessLog(%q) = %q, expected %q", input, got, expected)
+		}
+	}
+}
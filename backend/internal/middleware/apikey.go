@@ -0,0 +1,156 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/nomdb/backend/internal/auth"
+	"github.com/nomdb/backend/internal/database"
+	apperrors "github.com/nomdb/backend/internal/errors"
+	"github.com/nomdb/backend/internal/logger"
+	"github.com/nomdb/backend/internal/models"
+	"golang.org/x/time/rate"
+)
+
+// APIKeyHeader carries the API key of programmatic clients
+const APIKeyHeader = "X-API-Key"
+
+// apiKeyLimiters holds one token bucket per API key, service account or user
+type apiKeyLimiters struct {
+	name     string // Prefixes the keys of the buckets in the shared store
+	mu       sync.Mutex
+	limiters map[int]*rate.Limiter
+}
+
+var (
+	defaultAPIKeyRateLimit         = 60  // requests per minute
+	defaultServiceAccountRateLimit = 300 // requests per minute
+	keyLimiters                    = newAPIKeyLimiters("api_key")
+	serviceAccountLimiters         = newAPIKeyLimiters("service_account")
+)
+
+// InitAPIKeyAuth sets the default rate limits in requests per minute: per API key, and
+// per service account across all of its keys
+func InitAPIKeyAuth(requestsPerMinute, serviceAccountRequestsPerMinute int) {
+	defaultAPIKeyRateLimit = requestsPerMinute
+	defaultServiceAccountRateLimit = serviceAccountRequestsPerMinute
+	logger.Info("🔑 API key rate limit: %d req/min per key, %d req/min per service account (defaults)",
+		requestsPerMinute, serviceAccountRequestsPerMinute)
+}
+
+func newAPIKeyLimiters(name string) *apiKeyLimiters {
+	return &apiKeyLimiters{name: name, limiters: make(map[int]*rate.Limiter)}
+}
+
+// take takes a token from the bucket of a key, which allows perMinute requests with an
+// equal burst
+func (l *apiKeyLimiters) take(ctx context.Context, keyID, perMinute int) TokenBucket {
+	return takeToken(ctx, l.name+":"+strconv.Itoa(keyID),
+		func() *rate.Limiter { return l.get(keyID, perMinute) }, perMinute, perMinute)
+}
+
+// get returns the in-process limiter of a key, allowing perMinute requests with an
+// equal burst
+func (l *apiKeyLimiters) get(keyID, perMinute int) *rate.Limiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	limiter, ok := l.limiters[keyID]
+	if !ok {
+		limiter = rate.NewLimiter(rate.Every(time.Minute/time.Duration(perMinute)), perMinute)
+		l.limiters[keyID] = limiter
+	}
+	return limiter
+}
+
+// reset drops all limiters, so they are created again with the current limit
+func (l *apiKeyLimiters) reset() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.limiters = make(map[int]*rate.Limiter)
+}
+
+// APIKeyAuthMiddleware authenticates requests carrying an X-API-Key header as the
+// key's owner, enforcing the key's scopes and rate limit. Requests without the header
+// pass through unchanged to the JWT middlewares.
+func APIKeyAuthMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(APIKeyHeader)
+		if key == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ctx := r.Context()
+		var apiKey models.APIKey
+		var user models.User
+		var accountRateLimit *int
+		err := database.GetPool().QueryRow(ctx,
+			`SELECT k.id, k.user_id, k.key_prefix, k.name, k.scopes, k.rate_limit, k.expires_at, k.is_active, k.created_at,
+			u.id, u.email, u.username, u.provider, u.provider_id, u.full_name, u.avatar_url,
+			u.is_active, u.is_admin, u.email_verified, u.last_login_at, u.created_at, u.updated_at, u.api_rate_limit
+			FROM api_keys k JOIN users u ON u.id = k.user_id
+			WHERE k.key_hash = $1 AND k.is_active AND (k.expires_at IS NULL OR k.expires_at > NOW())`,
+			auth.HashAPIKey(key)).Scan(
+			&apiKey.ID, &apiKey.UserID, &apiKey.KeyPrefix, &apiKey.Name, &apiKey.Scopes, &apiKey.RateLimit,
+			&apiKey.ExpiresAt, &apiKey.IsActive, &apiKey.CreatedAt,
+			&user.ID, &user.Email, &user.Username, &user.Provider, &user.ProviderID,
+			&user.FullName, &user.AvatarURL, &user.IsActive, &user.IsAdmin,
+			&user.EmailVerified, &user.LastLoginAt, &user.CreatedAt, &user.UpdatedAt, &accountRateLimit)
+		if err != nil {
+			apperrors.Error(w, "Invalid API key", http.StatusUnauthorized)
+			return
+		}
+
+		if !user.IsActive {
+			apperrors.Error(w, "Account is disabled", http.StatusUnauthorized)
+			return
+		}
+
+		if !auth.HasScope(apiKey.Scopes, auth.ScopeForMethod(r.Method)) {
+			apperrors.Error(w, "API key does not have the required scope", http.StatusForbidden)
+			return
+		}
+
+		perMinute := defaultAPIKeyRateLimit
+		if apiKey.RateLimit != nil {
+			perMinute = *apiKey.RateLimit
+		}
+		if perMinute > 0 && !allowRequest(w, keyLimiters.take(ctx, apiKey.ID, perMinute), perMinute) {
+			apperrors.Error(w, "API key rate limit exceeded. Please try again later.", http.StatusTooManyRequests)
+			return
+		}
+
+		// Service accounts share one budget across all of their keys
+		if user.IsServiceAccount() {
+			perMinute := defaultServiceAccountRateLimit
+			if accountRateLimit != nil {
+				perMinute = *accountRateLimit
+			}
+			if perMinute > 0 && !allowRequest(w, serviceAccountLimiters.take(ctx, user.ID, perMinute), perMinute) {
+				apperrors.Error(w, "Service account rate limit exceeded. Please try again later.", http.StatusTooManyRequests)
+				return
+			}
+		}
+
+		go func(id int) {
+			if _, err := database.GetPool().Exec(context.Background(),
+				"UPDATE api_keys SET last_used_at = NOW() WHERE id = $1", id); err != nil {
+				logger.Warn("Failed to update API key last_used_at: %v", err)
+			}
+		}(apiKey.ID)
+
+		ctx = context.WithValue(ctx, models.UserContextKey, &user)
+		ctx = context.WithValue(ctx, models.APIKeyContextKey, &apiKey)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// GetAPIKeyFromRequest returns the API key a request was authenticated with, if any
+func GetAPIKeyFromRequest(r *http.Request) (*models.APIKey, bool) {
+	apiKey, ok := r.Context().Value(models.APIKeyContextKey).(*models.APIKey)
+	return apiKey, ok
+}
@@ -3,11 +3,11 @@ package middleware
 import (
 	"context"
 	"net/http"
-	"os"
 	"strings"
 
 	"github.com/nomdb/backend/internal/auth"
 	"github.com/nomdb/backend/internal/database"
+	apperrors "github.com/nomdb/backend/internal/errors"
 	"github.com/nomdb/backend/internal/logger"
 	"github.com/nomdb/backend/internal/models"
 )
@@ -27,14 +27,12 @@ var (
 	jwtService      *auth.JWTService
 )
 
-// InitAuthMiddleware initializes the authentication middleware
-func InitAuthMiddleware(jwtSvc *auth.JWTService) {
+// InitAuthMiddleware initializes the authentication middleware for the given AUTH_MODE
+func InitAuthMiddleware(jwtSvc *auth.JWTService, mode string) {
 	if jwtSvc != nil {
 		jwtService = jwtSvc
 	}
 
-	// Get auth mode from environment
-	mode := os.Getenv("AUTH_MODE")
 	switch mode {
 	case "none":
 		currentAuthMode = AuthModeNone
@@ -67,6 +65,12 @@ func GetAuthMode() AuthMode {
 // AuthMiddleware validates JWT tokens and adds user to context
 func AuthMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Already authenticated by APIKeyAuthMiddleware
+		if _, ok := GetAPIKeyFromRequest(r); ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
 		// Skip auth if mode is 'none'
 		if currentAuthMode == AuthModeNone {
 			// Create a dummy user for testing
@@ -85,14 +89,14 @@ func AuthMiddleware(next http.Handler) http.Handler {
 		// Extract token from Authorization header
 		authHeader := r.Header.Get("Authorization")
 		if authHeader == "" {
-			http.Error(w, "Missing authorization header", http.StatusUnauthorized)
+			apperrors.Error(w, "Missing authorization header", http.StatusUnauthorized)
 			return
 		}
 
 		// Check for Bearer token
 		parts := strings.SplitN(authHeader, " ", 2)
 		if len(parts) != 2 || parts[0] != "Bearer" {
-			http.Error(w, "Invalid authorization header format", http.StatusUnauthorized)
+			apperrors.Error(w, "Invalid authorization header format", http.StatusUnauthorized)
 			return
 		}
 
@@ -102,9 +106,9 @@ func AuthMiddleware(next http.Handler) http.Handler {
 		claims, err := jwtService.ValidateAccessToken(tokenString)
 		if err != nil {
 			if err == auth.ErrExpiredToken {
-				http.Error(w, "Token has expired", http.StatusUnauthorized)
+				apperrors.Error(w, "Token has expired", http.StatusUnauthorized)
 			} else {
-				http.Error(w, "Invalid token", http.StatusUnauthorized)
+				apperrors.Error(w, "Invalid token", http.StatusUnauthorized)
 			}
 			return
 		}
@@ -122,13 +126,17 @@ func AuthMiddleware(next http.Handler) http.Handler {
 
 		if err != nil {
 			logger.Warn("User not found for token: %d", claims.UserID)
-			http.Error(w, "User not found", http.StatusUnauthorized)
+			apperrors.Error(w, "User not found", http.StatusUnauthorized)
 			return
 		}
 
 		// Check if user is active
 		if !user.IsActive {
-			http.Error(w, "Account is disabled", http.StatusUnauthorized)
+			apperrors.Error(w, "Account is disabled", http.StatusUnauthorized)
+			return
+		}
+
+		if !allowUser(w, r, &user) {
 			return
 		}
 
@@ -141,6 +149,11 @@ func AuthMiddleware(next http.Handler) http.Handler {
 // OptionalAuthMiddleware validates JWT tokens if present but allows anonymous access
 func OptionalAuthMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := GetAPIKeyFromRequest(r); ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
 		// Skip auth if mode is 'none'
 		if currentAuthMode == AuthModeNone {
 			dummyUser := &models.User{
@@ -196,6 +209,10 @@ func OptionalAuthMiddleware(next http.Handler) http.Handler {
 			return
 		}
 
+		if !allowUser(w, r, &user) {
+			return
+		}
+
 		// Add user to context
 		ctx = context.WithValue(ctx, models.UserContextKey, &user)
 		next.ServeHTTP(w, r.WithContext(ctx))
@@ -207,12 +224,17 @@ func AdminOnlyMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		user, ok := r.Context().Value(models.UserContextKey).(*models.User)
 		if !ok {
-			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			apperrors.Error(w, "Unauthorized", http.StatusUnauthorized)
 			return
 		}
 
 		if !user.IsAdmin {
-			http.Error(w, "Forbidden - admin access required", http.StatusForbidden)
+			apperrors.Error(w, "Forbidden - admin access required", http.StatusForbidden)
+			return
+		}
+
+		if apiKey, ok := GetAPIKeyFromRequest(r); ok && !auth.HasScope(apiKey.Scopes, auth.ScopeAdmin) {
+			apperrors.Error(w, "Forbidden - API key lacks the admin scope", http.StatusForbidden)
 			return
 		}
 
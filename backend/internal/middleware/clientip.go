@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+
+	"github.com/nomdb/backend/internal/logger"
+)
+
+// trustedProxies are the reverse proxies whose X-Forwarded-For and X-Real-IP headers
+// are believed. Without any, the headers are ignored so clients cannot spoof their IP.
+var trustedProxies []netip.Prefix
+
+// InitTrustedProxies sets the addresses of trusted reverse proxies (TRUSTED_PROXIES)
+func InitTrustedProxies(proxies []netip.Prefix) {
+	trustedProxies = proxies
+	if len(proxies) == 0 {
+		logger.Info("🌍 No trusted proxies: X-Forwarded-For is ignored")
+		return
+	}
+	logger.Info("🌍 Trusted proxies: %v", proxies)
+}
+
+func isTrustedProxy(addr netip.Addr) bool {
+	for _, prefix := range trustedProxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseIP parses an address with or without a port, e.g. from RemoteAddr
+func parseIP(value string) (netip.Addr, bool) {
+	value = strings.TrimSpace(value)
+	if host, _, err := net.SplitHostPort(value); err == nil {
+		value = host
+	}
+	addr, err := netip.ParseAddr(value)
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	return addr.Unmap(), true
+}
+
+// ClientIP returns the address of the client that sent r. Forwarding headers are only
+// used when the request comes from a trusted proxy: X-Forwarded-For is read right to
+// left, skipping trusted proxies, and the first untrusted hop is the client. Hops left
+// of it were supplied by the client and cannot be trusted.
+func ClientIP(r *http.Request) string {
+	remote, ok := parseIP(r.RemoteAddr)
+	if !ok {
+		return r.RemoteAddr
+	}
+	if !isTrustedProxy(remote) {
+		return remote.String()
+	}
+
+	var hops []string
+	for _, header := range r.Header.Values("X-Forwarded-For") {
+		hops = append(hops, strings.Split(header, ",")...)
+	}
+	if len(hops) == 0 {
+		if realIP, ok := parseIP(r.Header.Get("X-Real-IP")); ok {
+			return realIP.String()
+		}
+		return remote.String()
+	}
+
+	client := remote
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop, ok := parseIP(hops[i])
+		if !ok {
+			// Garbage in the chain: the last trusted hop is all we know
+			break
+		}
+		client = hop
+		if !isTrustedProxy(hop) {
+			break
+		}
+	}
+	return client.String()
+}
@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+)
+
+func TestClientIP(t *testing.T) {
+	defer func(previous []netip.Prefix) { trustedProxies = previous }(trustedProxies)
+	trustedProxies = []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")}
+
+	tests := []struct {
+		name, remoteAddr, forwardedFor, realIP, expected string
+	}{
+		{"direct client", "203.0.113.7:5000", "", "", "203.0.113.7"},
+		{"spoofed header from untrusted client", "203.0.113.7:5000", "1.2.3.4", "", "203.0.113.7"},
+		{"behind trusted proxy", "10.0.0.2:80", "198.51.100.1", "", "198.51.100.1"},
+		{"client-supplied hops are skipped", "10.0.0.2:80", "1.2.3.4, 198.51.100.1", "", "198.51.100.1"},
+		{"chain of trusted proxies", "10.0.0.2:80", "198.51.100.1, 10.0.0.3", "", "198.51.100.1"},
+		{"only trusted hops", "10.0.0.2:80", "10.0.0.4, 10.0.0.3", "", "10.0.0.4"},
+		{"garbage hop", "10.0.0.2:80", "198.51.100.1, not-an-ip", "", "10.0.0.2"},
+		{"X-Real-IP from trusted proxy", "10.0.0.2:80", "", "198.51.100.9", "198.51.100.9"},
+		{"IPv6 client", "[2001:db8::1]:443", "", "", "2001:db8::1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/api/restaurants", nil)
+			req.RemoteAddr = tt.remoteAddr
+			if tt.forwardedFor != "" {
+				req.Header.Set("X-Forwarded-For", tt.forwardedFor)
+			}
+			if tt.realIP != "" {
+				req.Header.Set("X-Real-IP", tt.realIP)
+			}
+			if got := ClientIP(req); got != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestClientIP_NoTrustedProxies(t *testing.T) {
+	defer func(previous []netip.Prefix) { trustedProxies = previous }(trustedProxies)
+	trustedProxies = nil
+
+	req := httptest.NewRequest("GET", "/api/restaurants", nil)
+	req.RemoteAddr = "10.0.0.2:80"
+	req.Header.Set("X-Forwarded-For", "198.51.100.1")
+	if got := ClientIP(req); got != "10.0.0.2" {
+		t.Errorf("Expected X-Forwarded-For to be ignored without trusted proxies, got %s", got)
+	}
+}
@@ -4,47 +4,246 @@ import (
 	"compress/gzip"
 	"io"
 	"net/http"
+	"strconv"
 	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
 )
 
-type gzipResponseWriter struct {
-	io.Writer
+// minCompressSize is the smallest response worth compressing; below it the encoding
+// overhead outweighs the savings
+const minCompressSize = 1024
+
+// brotliLevel trades ratio for speed; levels above 5 are too slow for dynamic responses
+const brotliLevel = 4
+
+// compressor is implemented by both *gzip.Writer and *brotli.Writer
+type compressor interface {
+	io.WriteCloser
+	Flush() error
+	Reset(io.Writer)
+}
+
+var compressorPools = map[string]*sync.Pool{
+	"br": {New: func() any {
+		return brotli.NewWriterLevel(nil, brotliLevel)
+	}},
+	"gzip": {New: func() any {
+		return gzip.NewWriter(nil)
+	}},
+}
+
+// compressResponseWriter decides whether to compress once the status, headers and the
+// first minCompressSize bytes of the response are known. Until then writes are buffered.
+type compressResponseWriter struct {
 	http.ResponseWriter
+	encoding    string
+	statusCode  int
+	wroteHeader bool // The handler set the status
+	decided     bool // The status and headers were sent to the client
+	compressor  compressor
+	buf         []byte
 }
 
-func (w gzipResponseWriter) Write(b []byte) (int, error) {
-	return w.Writer.Write(b)
+func (cw *compressResponseWriter) WriteHeader(code int) {
+	if cw.wroteHeader {
+		return
+	}
+	if code < http.StatusOK {
+		// Informational responses like 103 Early Hints go out as is
+		cw.ResponseWriter.WriteHeader(code)
+		return
+	}
+	cw.wroteHeader = true
+	cw.statusCode = code
+
+	if !cw.compressible(cw.Header().Get("Content-Type")) {
+		cw.passThrough()
+		return
+	}
+	if length, err := strconv.Atoi(cw.Header().Get("Content-Length")); err == nil && length < minCompressSize {
+		cw.passThrough()
+	}
+}
+
+func (cw *compressResponseWriter) Write(b []byte) (int, error) {
+	if !cw.wroteHeader {
+		cw.WriteHeader(http.StatusOK)
+	}
+	if cw.decided {
+		if cw.compressor != nil {
+			return cw.compressor.Write(b)
+		}
+		return cw.ResponseWriter.Write(b)
+	}
+
+	cw.buf = append(cw.buf, b...)
+	if len(cw.buf) >= minCompressSize {
+		if err := cw.startCompression(); err != nil {
+			return 0, err
+		}
+	}
+	return len(b), nil
+}
+
+// Flush sends buffered data to the client (needed for streaming responses). Streams
+// are compressed even if their first chunk is small.
+func (cw *compressResponseWriter) Flush() {
+	if !cw.wroteHeader {
+		cw.WriteHeader(http.StatusOK)
+	}
+	if !cw.decided {
+		cw.startCompression()
+	}
+	if cw.compressor != nil {
+		cw.compressor.Flush()
+	}
+	http.NewResponseController(cw.ResponseWriter).Flush()
+}
+
+// Unwrap lets http.ResponseController reach the underlying connection
+func (cw *compressResponseWriter) Unwrap() http.ResponseWriter {
+	return cw.ResponseWriter
+}
+
+// compressible reports whether a response of the status and content type set so far
+// may be compressed
+func (cw *compressResponseWriter) compressible(contentType string) bool {
+	switch cw.statusCode {
+	case http.StatusNoContent, http.StatusNotModified, http.StatusPartialContent:
+		return false
+	}
+	if cw.Header().Get("Content-Encoding") != "" {
+		return false
+	}
+
+	// Images, videos and archives are already compressed
+	for _, prefix := range []string{"image/", "video/", "audio/", "font/woff", "application/zip", "application/gzip", "application/octet-stream"} {
+		if strings.HasPrefix(contentType, prefix) {
+			return strings.HasPrefix(contentType, "image/svg+xml")
+		}
+	}
+	return true
 }
 
-// CompressionMiddleware adds gzip compression to responses when client supports it
+// passThrough sends the response uncompressed
+func (cw *compressResponseWriter) passThrough() error {
+	cw.decided = true
+	cw.ResponseWriter.WriteHeader(cw.statusCode)
+	if len(cw.buf) == 0 {
+		return nil
+	}
+	_, err := cw.ResponseWriter.Write(cw.buf)
+	cw.buf = nil
+	return err
+}
+
+// startCompression sends the headers of a compressed response and the buffered data
+func (cw *compressResponseWriter) startCompression() error {
+	header := cw.Header()
+	if header.Get("Content-Type") == "" && len(cw.buf) > 0 {
+		// Sniff the type now, as net/http would on the first write
+		header.Set("Content-Type", http.DetectContentType(cw.buf))
+	}
+	if !cw.compressible(header.Get("Content-Type")) {
+		return cw.passThrough()
+	}
+
+	header.Set("Content-Encoding", cw.encoding)
+	header.Del("Content-Length")
+	// The compressed bytes differ, so a strong validator no longer applies
+	if etag := header.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+		header.Set("ETag", "W/"+etag)
+	}
+
+	cw.decided = true
+	cw.ResponseWriter.WriteHeader(cw.statusCode)
+
+	cw.compressor = compressorPools[cw.encoding].Get().(compressor)
+	cw.compressor.Reset(cw.ResponseWriter)
+	_, err := cw.compressor.Write(cw.buf)
+	cw.buf = nil
+	return err
+}
+
+// close sends what is still buffered: small responses uncompressed, and the remainder
+// of compressed ones
+func (cw *compressResponseWriter) close() {
+	if !cw.decided {
+		if !cw.wroteHeader {
+			return // Nothing was written; net/http sends an empty 200
+		}
+		cw.passThrough()
+		return
+	}
+	if cw.compressor != nil {
+		cw.compressor.Close()
+		compressorPools[cw.encoding].Put(cw.compressor)
+		cw.compressor = nil
+	}
+}
+
+// negotiateEncoding picks brotli or gzip from an Accept-Encoding header, honouring
+// q-values, or returns "" if the client accepts neither
+func negotiateEncoding(acceptEncoding string) string {
+	best, bestQ := "", 0.0
+	wildcardQ := -1.0
+	qualities := map[string]float64{}
+
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if coding == "*" {
+			wildcardQ = q
+		} else {
+			qualities[coding] = q
+		}
+	}
+
+	// Prefer brotli on equal quality, as it compresses better
+	for _, encoding := range []string{"br", "gzip"} {
+		q, ok := qualities[encoding]
+		if !ok {
+			q = wildcardQ
+		}
+		if q > bestQ {
+			best, bestQ = encoding, q
+		}
+	}
+	return best
+}
+
+// CompressionMiddleware compresses responses with brotli or gzip, as negotiated with
+// the client. Small responses, empty ones (HEAD, 204, 304) and already compressed
+// content types are sent as is.
 func CompressionMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Check if client supports gzip
-		if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+		// Uploaded files and photo archives are already compressed, and uploads are
+		// served with range support, which compression would break
+		if strings.HasPrefix(r.URL.Path, "/api/uploads/") ||
+			strings.HasSuffix(r.URL.Path, "/photos/archive") {
 			next.ServeHTTP(w, r)
 			return
 		}
 
-		// Don't compress if content is already compressed or is an image
-		contentType := w.Header().Get("Content-Type")
-		if strings.Contains(contentType, "image/") ||
-		   strings.Contains(contentType, "video/") ||
-		   strings.Contains(contentType, "application/zip") ||
-		   strings.Contains(contentType, "application/gzip") {
+		w.Header().Add("Vary", "Accept-Encoding")
+		encoding := negotiateEncoding(r.Header.Get("Accept-Encoding"))
+		if encoding == "" || r.Method == http.MethodHead {
 			next.ServeHTTP(w, r)
 			return
 		}
 
-		// Set gzip headers
-		w.Header().Set("Content-Encoding", "gzip")
-		w.Header().Del("Content-Length") // Content-Length will be different after compression
-
-		// Create gzip writer
-		gz := gzip.NewWriter(w)
-		defer gz.Close()
-
-		// Wrap response writer
-		gzw := gzipResponseWriter{Writer: gz, ResponseWriter: w}
-		next.ServeHTTP(gzw, r)
+		cw := &compressResponseWriter{ResponseWriter: w, encoding: encoding, statusCode: http.StatusOK}
+		defer cw.close()
+		next.ServeHTTP(cw, r)
 	})
 }
@@ -0,0 +1,201 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+)
+
+func TestNegotiateEncoding(t *testing.T) {
+	tests := []struct {
+		acceptEncoding string
+		expected       string
+	}{
+		{"", ""},
+		{"gzip", "gzip"},
+		{"br", "br"},
+		{"gzip, deflate, br", "br"},
+		{"br;q=0.5, gzip", "gzip"},
+		{"br;q=0, gzip;q=0", ""},
+		{"identity", ""},
+		{"*", "br"},
+		{"*, br;q=0", "gzip"},
+		{"GZIP", "gzip"},
+	}
+
+	for _, tt := range tests {
+		if got := negotiateEncoding(tt.acceptEncoding); got != tt.expected {
+			t.Errorf("negotiateEncoding(%q) = %q, want %q", tt.acceptEncoding, got, tt.expected)
+		}
+	}
+}
+
+func TestCompressionMiddleware(t *testing.T) {
+	large := strings.Repeat(`{"name":"Pizza Palace"},`, 100)
+
+	tests := []struct {
+		name             string
+		method           string
+		acceptEncoding   string
+		handler          http.HandlerFunc
+		expectedEncoding string
+		expectedStatus   int
+	}{
+		{
+			name:           "gzip",
+			acceptEncoding: "gzip",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				io.WriteString(w, large)
+			},
+			expectedEncoding: "gzip",
+			expectedStatus:   http.StatusOK,
+		},
+		{
+			name:           "brotli preferred",
+			acceptEncoding: "gzip, deflate, br",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusCreated)
+				io.WriteString(w, large)
+			},
+			expectedEncoding: "br",
+			expectedStatus:   http.StatusCreated,
+		},
+		{
+			name:           "small response",
+			acceptEncoding: "gzip",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				io.WriteString(w, `{"id":1}`)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "image",
+			acceptEncoding: "gzip",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "image/jpeg")
+				io.WriteString(w, large)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "sniffed image",
+			acceptEncoding: "gzip",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				io.WriteString(w, "\x89PNG\x0D\x0A\x1A\x0A"+large)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "no content",
+			acceptEncoding: "gzip",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNoContent)
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name:           "HEAD",
+			method:         http.MethodHead,
+			acceptEncoding: "gzip",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "not accepted",
+			acceptEncoding: "identity",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				io.WriteString(w, large)
+			},
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			method := tt.method
+			if method == "" {
+				method = http.MethodGet
+			}
+			req := httptest.NewRequest(method, "/api/restaurants", nil)
+			req.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			rec := httptest.NewRecorder()
+			CompressionMiddleware(tt.handler).ServeHTTP(rec, req)
+
+			if rec.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+			if encoding := rec.Header().Get("Content-Encoding"); encoding != tt.expectedEncoding {
+				t.Errorf("Expected Content-Encoding %q, got %q", tt.expectedEncoding, encoding)
+			}
+			if rec.Header().Get("Vary") != "Accept-Encoding" {
+				t.Error("Expected Vary: Accept-Encoding")
+			}
+
+			var body io.Reader = rec.Body
+			switch tt.expectedEncoding {
+			case "gzip":
+				gz, err := gzip.NewReader(rec.Body)
+				if err != nil {
+					t.Fatalf("Invalid gzip response: %v", err)
+				}
+				body = gz
+			case "br":
+				body = brotli.NewReader(rec.Body)
+			}
+			decoded, err := io.ReadAll(body)
+			if err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if tt.expectedEncoding != "" && string(decoded) != large {
+				t.Error("Decompressed body does not match")
+			}
+		})
+	}
+}
+
+func TestCompressionMiddleware_Streaming(t *testing.T) {
+	handler := CompressionMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		io.WriteString(w, "data: {}\n\n")
+		w.(http.Flusher).Flush()
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/restaurants/1/photos/events", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if !rec.Flushed {
+		t.Error("Expected the response to be flushed")
+	}
+	if rec.Header().Get("Content-Encoding") != "gzip" {
+		t.Errorf("Expected flushed streams to be compressed, got %q", rec.Header().Get("Content-Encoding"))
+	}
+}
+
+func TestCompressionMiddleware_WeakensETag(t *testing.T) {
+	handler := CompressionMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("ETag", `"abc"`)
+		io.WriteString(w, strings.Repeat("x", minCompressSize))
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/restaurants", nil)
+	req.Header.Set("Accept-Encoding", "br")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if etag := rec.Header().Get("ETag"); etag != `W/"abc"` {
+		t.Errorf("Expected weak ETag, got %s", etag)
+	}
+}
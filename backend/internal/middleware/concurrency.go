@@ -0,0 +1,141 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	apperrors "github.com/nomdb/backend/internal/errors"
+	"github.com/nomdb/backend/internal/logger"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ConcurrencyLimits caps the number of requests served at once, in total and per route
+// group (0 = unlimited). Requests wait up to QueueTimeout for a free slot before they
+// are shed with 503.
+type ConcurrencyLimits struct {
+	Global       int
+	Auth         int
+	Upload       int
+	Read         int
+	Write        int
+	QueueTimeout time.Duration
+}
+
+// ConcurrencyLimiter holds a semaphore for all requests and one per route group
+type ConcurrencyLimiter struct {
+	global       chan struct{} // nil when unlimited
+	groups       map[string]chan struct{}
+	queueTimeout time.Duration
+}
+
+var httpRequestsShed = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "nomdb",
+	Name:      "http_requests_shed_total",
+	Help:      "HTTP requests rejected with 503 because the concurrency limit of their route group was reached.",
+}, []string{"group"})
+
+func init() {
+	MetricsRegistry.MustRegister(httpRequestsShed)
+}
+
+func newSemaphore(size int) chan struct{} {
+	if size <= 0 {
+		return nil
+	}
+	return make(chan struct{}, size)
+}
+
+// NewConcurrencyLimiter creates the semaphores for limits
+func NewConcurrencyLimiter(limits ConcurrencyLimits) *ConcurrencyLimiter {
+	logger.Info("🚦 Concurrency limits (in-flight requests): %d total; auth %d, uploads %d, reads %d, writes %d (0 = unlimited)",
+		limits.Global, limits.Auth, limits.Upload, limits.Read, limits.Write)
+
+	return &ConcurrencyLimiter{
+		global: newSemaphore(limits.Global),
+		groups: map[string]chan struct{}{
+			RateLimitGroupAuth:   newSemaphore(limits.Auth),
+			RateLimitGroupUpload: newSemaphore(limits.Upload),
+			RateLimitGroupRead:   newSemaphore(limits.Read),
+			RateLimitGroupWrite:  newSemaphore(limits.Write),
+		},
+		queueTimeout: limits.QueueTimeout,
+	}
+}
+
+// acquire takes a slot of sem, waiting until ctx is done. A nil semaphore is unlimited.
+func acquire(ctx context.Context, sem chan struct{}) bool {
+	if sem == nil {
+		return true
+	}
+	select {
+	case sem <- struct{}{}:
+		return true
+	default:
+	}
+	select {
+	case sem <- struct{}{}:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func release(sem chan struct{}) {
+	if sem != nil {
+		<-sem
+	}
+}
+
+// isShedExempt reports whether r bypasses the concurrency limits: health checks and
+// metrics must answer under load, long-lived streams would hold slots indefinitely, and
+// frontend files need no database connection
+func isShedExempt(r *http.Request) bool {
+	return strings.HasPrefix(r.URL.Path, "/api/health") ||
+		r.URL.Path == "/metrics" ||
+		isStreamingRequest(r) ||
+		isFrontendRequest(r)
+}
+
+// ConcurrencyLimitMiddleware sheds load once the limit of the request's route group or
+// the global limit is reached, answering 503 with Retry-After instead of queueing
+// requests until the database pool is exhausted
+func ConcurrencyLimitMiddleware(limiter *ConcurrencyLimiter) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if isShedExempt(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			group := rateLimitGroup(r)
+			groupSem := limiter.groups[group]
+
+			ctx, cancel := context.WithTimeout(r.Context(), limiter.queueTimeout)
+			defer cancel()
+
+			// Take the group slot first, so a saturated group does not hold global slots
+			if !acquire(ctx, groupSem) {
+				shed(w, r, group)
+				return
+			}
+			defer release(groupSem)
+			if !acquire(ctx, limiter.global) {
+				shed(w, r, group)
+				return
+			}
+			defer release(limiter.global)
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func shed(w http.ResponseWriter, r *http.Request, group string) {
+	httpRequestsShed.WithLabelValues(group).Inc()
+	logger.Debug("🚦 Shedding %s %s: too many concurrent %s requests", r.Method, r.URL.Path, group)
+
+	w.Header().Set("Retry-After", "1")
+	apperrors.Error(w, "Server is busy. Please try again shortly.", http.StatusServiceUnavailable)
+}
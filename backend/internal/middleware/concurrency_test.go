@@ -0,0 +1,102 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// blockingHandler holds requests until release is closed
+func blockingHandler(started *sync.WaitGroup, release chan struct{}) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started.Done()
+		<-release
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+func TestConcurrencyLimitMiddleware_ShedsGroup(t *testing.T) {
+	var started sync.WaitGroup
+	release := make(chan struct{})
+	handler := ConcurrencyLimitMiddleware(NewConcurrencyLimiter(ConcurrencyLimits{
+		Read:         1,
+		QueueTimeout: 10 * time.Millisecond,
+	}))(blockingHandler(&started, release))
+
+	started.Add(1)
+	done := make(chan int)
+	go func() {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/restaurants", nil))
+		done <- rec.Code
+	}()
+	started.Wait()
+
+	// The read group is full
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/restaurants/1", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected 503 while saturated, got %d", rec.Code)
+	}
+	if rec.Header().Get("Retry-After") == "" {
+		t.Error("Expected Retry-After header")
+	}
+
+	// Other groups and health checks are not affected
+	started.Add(1)
+	go func() {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/ratings", nil))
+		done <- rec.Code
+	}()
+	started.Wait()
+	started.Add(1)
+	go func() {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/health/live", nil))
+		done <- rec.Code
+	}()
+	started.Wait()
+
+	close(release)
+	for range 3 {
+		if code := <-done; code != http.StatusOK {
+			t.Errorf("Expected 200, got %d", code)
+		}
+	}
+
+	// Slots are released
+	started.Add(1)
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/restaurants", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected 200 after release, got %d", rec.Code)
+	}
+}
+
+func TestConcurrencyLimitMiddleware_QueuesUntilTimeout(t *testing.T) {
+	var started sync.WaitGroup
+	release := make(chan struct{})
+	handler := ConcurrencyLimitMiddleware(NewConcurrencyLimiter(ConcurrencyLimits{
+		Global:       1,
+		QueueTimeout: time.Second,
+	}))(blockingHandler(&started, release))
+
+	started.Add(1)
+	go handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/restaurants", nil))
+	started.Wait()
+
+	// The queued request gets the slot once the first one finishes
+	started.Add(1)
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		close(release)
+	}()
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/ratings", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected the queued request to be served, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	apperrors "github.com/nomdb/backend/internal/errors"
+)
+
+const (
+	// CSRFCookie holds the CSRF token issued by GET /api/auth/csrf
+	CSRFCookie = "nomdb_csrf"
+	// CSRFHeader must repeat the CSRF token on state-changing requests sent with cookies
+	CSRFHeader = "X-CSRF-Token"
+)
+
+// CSRFMiddleware protects cookie-authenticated requests with a double-submit token:
+// state-changing requests carrying one of authCookies must send the value of the CSRF
+// cookie in the X-CSRF-Token header. Other sites can make the browser send the cookies,
+// but can neither read them nor set custom headers. Requests authenticated with an
+// Authorization or X-API-Key header only are not affected.
+func CSRFMiddleware(authCookies ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if isSafeMethod(r.Method) || !hasAnyCookie(r, authCookies) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			cookie, err := r.Cookie(CSRFCookie)
+			header := r.Header.Get(CSRFHeader)
+			if err != nil || cookie.Value == "" || header == "" ||
+				subtle.ConstantTimeCompare([]byte(cookie.Value), []byte(header)) != 1 {
+				apperrors.Error(w, "Missing or invalid CSRF token", http.StatusForbidden)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// isSafeMethod reports whether method must not change state (RFC 9110)
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	}
+	return false
+}
+
+func hasAnyCookie(r *http.Request, names []string) bool {
+	for _, name := range names {
+		if cookie, err := r.Cookie(name); err == nil && cookie.Value != "" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCSRFMiddleware(t *testing.T) {
+	handler := CSRFMiddleware("refresh_token")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name           string
+		method         string
+		authCookie     bool
+		csrfCookie     string
+		csrfHeader     string
+		expectedStatus int
+	}{
+		{"safe method with cookie", http.MethodGet, true, "", "", http.StatusOK},
+		{"POST without auth cookie", http.MethodPost, false, "", "", http.StatusOK},
+		{"POST with cookie and matching token", http.MethodPost, true, "token-1", "token-1", http.StatusOK},
+		{"POST with cookie without token", http.MethodPost, true, "", "", http.StatusForbidden},
+		{"POST with cookie and header only", http.MethodPost, true, "", "token-1", http.StatusForbidden},
+		{"POST with cookie and CSRF cookie only", http.MethodPost, true, "token-1", "", http.StatusForbidden},
+		{"DELETE with mismatched token", http.MethodDelete, true, "token-1", "token-2", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/api/auth/logout", nil)
+			if tt.authCookie {
+				req.AddCookie(&http.Cookie{Name: "refresh_token", Value: "secret"})
+			}
+			if tt.csrfCookie != "" {
+				req.AddCookie(&http.Cookie{Name: CSRFCookie, Value: tt.csrfCookie})
+			}
+			if tt.csrfHeader != "" {
+				req.Header.Set(CSRFHeader, tt.csrfHeader)
+			}
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, rec.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"sync/atomic"
+)
+
+// debugBodyLimit is the size up to which debug logging captures request and response
+// bodies; larger bodies are summarized
+const debugBodyLimit = 16 * 1024
+
+// DebugAllRoutes enables debug logging for every route
+const DebugAllRoutes = "*"
+
+// redacted replaces the values of sensitive fields in debug logs
+const redacted = "[REDACTED]"
+
+// sensitiveBodyFields have their values redacted in debug logs in addition to fields
+// whose names contain password, secret or token: API keys and invite codes, which are
+// only returned once
+var sensitiveBodyFields = map[string]bool{
+	"key":     true,
+	"api_key": true,
+	"code":    true,
+}
+
+// debugRoutes holds the route templates whose bodies LoggingMiddleware logs
+var debugRoutes atomic.Pointer[map[string]bool]
+
+// SetDebugRoutes enables logging of request and response bodies for routes, given as
+// route templates (e.g. /api/restaurants/{id}), optionally preceded by a method
+// ("POST /api/restaurants"), or DebugAllRoutes. An empty list disables it.
+func SetDebugRoutes(routes []string) {
+	enabled := make(map[string]bool, len(routes))
+	for _, route := range routes {
+		if route = strings.Join(strings.Fields(route), " "); route != "" {
+			enabled[route] = true
+		}
+	}
+	debugRoutes.Store(&enabled)
+}
+
+// debugLogged reports whether the bodies of requests to route are logged
+func debugLogged(method, route string) bool {
+	enabled := debugRoutes.Load()
+	if enabled == nil || len(*enabled) == 0 {
+		return false
+	}
+	return (*enabled)[DebugAllRoutes] || (*enabled)[route] || (*enabled)[method+" "+route]
+}
+
+// captureRequestBody returns up to debugBodyLimit+1 bytes of the body of r, leaving
+// the full body readable for the handler
+func captureRequestBody(r *http.Request) []byte {
+	if r.Body == nil || r.Body == http.NoBody {
+		return nil
+	}
+	captured, _ := io.ReadAll(io.LimitReader(r.Body, debugBodyLimit+1))
+	r.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(captured), r.Body), r.Body}
+	return captured
+}
+
+// debugBody returns a body for the debug log: JSON with sensitive fields redacted, or
+// a summary for other and oversized bodies, which may contain secrets that cannot be
+// found. size is the full size if known, -1 otherwise.
+func debugBody(contentType string, body []byte, size int64) any {
+	if len(body) == 0 {
+		return nil
+	}
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if mediaType == "" {
+		mediaType = "unknown content type"
+	}
+	if size < 0 {
+		size = int64(len(body))
+	}
+	if len(body) > debugBodyLimit {
+		return fmt.Sprintf("%s, more than %d bytes", mediaType, debugBodyLimit)
+	}
+
+	var value any
+	if !strings.HasSuffix(mediaType, "json") || json.Unmarshal(body, &value) != nil {
+		return fmt.Sprintf("%s, %d bytes", mediaType, size)
+	}
+	return redactFields(value)
+}
+
+// redactFields replaces the values of sensitive fields in decoded JSON
+func redactFields(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for name, field := range v {
+			if sensitiveBodyField(name) {
+				v[name] = redacted
+			} else {
+				v[name] = redactFields(field)
+			}
+		}
+	case []any:
+		for i := range v {
+			v[i] = redactFields(v[i])
+		}
+	}
+	return value
+}
+
+func sensitiveBodyField(name string) bool {
+	name = strings.ToLower(name)
+	return sensitiveBodyFields[name] || strings.Contains(name, "password") ||
+		strings.Contains(name, "secret") || strings.Contains(name, "token")
+}
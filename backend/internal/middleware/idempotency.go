@@ -0,0 +1,223 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/nomdb/backend/internal/database"
+	apperrors "github.com/nomdb/backend/internal/errors"
+	"github.com/nomdb/backend/internal/logger"
+)
+
+// IdempotencyKeyHeader lets clients retry a POST request without repeating its effect
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+const (
+	maxIdempotencyKeyLength = 255
+	// Larger responses are not stored; retries of such requests are executed again
+	maxIdempotentResponseSize  = 1 << 20
+	idempotencyCleanupInterval = time.Hour
+)
+
+var idempotencyKeyTTL = 24 * time.Hour
+
+// InitIdempotency sets how long responses are kept for retries and starts the job that
+// deletes expired ones
+func InitIdempotency(ttl time.Duration) {
+	idempotencyKeyTTL = ttl
+
+	logger.Info("✅ Idempotency keys kept for %s", ttl)
+	go func() {
+		ticker := time.NewTicker(idempotencyCleanupInterval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			result, err := database.GetPool().Exec(context.Background(),
+				"DELETE FROM idempotency_keys WHERE created_at < $1", time.Now().Add(-idempotencyKeyTTL))
+			if err != nil {
+				logger.Error("❌ Idempotency key cleanup failed: %v", err)
+				continue
+			}
+			logger.Debug("Deleted %d expired idempotency keys", result.RowsAffected())
+		}
+	}()
+}
+
+// IdempotencyMiddleware honours the Idempotency-Key header of authenticated POST
+// requests. The first request with a key is executed and its response stored for the
+// user; retries with the same key and body replay that response instead of creating a
+// duplicate. Reusing a key for a different request is rejected with 422, and retries
+// while the first request is still running with 409. Server errors are not stored, so
+// they can be retried. Must run after the auth middleware.
+func IdempotencyMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(IdempotencyKeyHeader)
+		user, ok := GetUserFromRequest(r)
+		if key == "" || r.Method != http.MethodPost || !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			apperrors.Error(w, "Idempotency-Key must be at most 255 characters", http.StatusBadRequest)
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			apperrors.Error(w, "Failed to read request body", http.StatusBadRequest)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		requestHash := idempotencyRequestHash(r, body)
+
+		// Claim the key, taking over keys whose stored response has expired
+		ctx := r.Context()
+		result, err := database.GetPool().Exec(ctx,
+			`INSERT INTO idempotency_keys (user_id, idempotency_key, request_hash)
+			VALUES ($1, $2, $3)
+			ON CONFLICT (user_id, idempotency_key) DO UPDATE
+			SET request_hash = EXCLUDED.request_hash, status_code = NULL, content_type = NULL,
+				response_body = NULL, created_at = NOW()
+			WHERE idempotency_keys.created_at < $4`,
+			user.ID, key, requestHash, time.Now().Add(-idempotencyKeyTTL))
+		if err != nil {
+			logger.Error("Failed to store idempotency key: %v", err)
+			apperrors.Error(w, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+		if result.RowsAffected() == 0 {
+			replayIdempotentResponse(w, r, user.ID, key, requestHash)
+			return
+		}
+
+		rec := &idempotencyRecorder{ResponseWriter: w, statusCode: http.StatusOK}
+		stored := false
+		defer func() {
+			// Release the key if the response is not stored (including on panics), so
+			// the request can be retried
+			if !stored {
+				releaseIdempotencyKey(user.ID, key)
+			}
+		}()
+
+		next.ServeHTTP(rec, r)
+
+		if rec.statusCode >= http.StatusInternalServerError || rec.overflow {
+			return
+		}
+		_, err = database.GetPool().Exec(context.WithoutCancel(ctx),
+			`UPDATE idempotency_keys SET status_code = $3, content_type = $4, response_body = $5
+			WHERE user_id = $1 AND idempotency_key = $2`,
+			user.ID, key, rec.statusCode, rec.Header().Get("Content-Type"), rec.body.Bytes())
+		if err != nil {
+			logger.Error("Failed to store idempotent response: %v", err)
+			return
+		}
+		stored = true
+	})
+}
+
+// replayIdempotentResponse answers a request whose key is already taken
+func replayIdempotentResponse(w http.ResponseWriter, r *http.Request, userID int, key, requestHash string) {
+	var storedHash string
+	var statusCode *int
+	var contentType *string
+	var body []byte
+	err := database.GetPool().QueryRow(r.Context(),
+		`SELECT request_hash, status_code, content_type, response_body FROM idempotency_keys
+		WHERE user_id = $1 AND idempotency_key = $2`,
+		userID, key).Scan(&storedHash, &statusCode, &contentType, &body)
+	if errors.Is(err, pgx.ErrNoRows) {
+		// The first request failed and released the key in the meantime
+		apperrors.Error(w, "A request with this Idempotency-Key failed, retry it", http.StatusConflict)
+		return
+	}
+	if err != nil {
+		logger.Error("Failed to fetch idempotency key: %v", err)
+		apperrors.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	if storedHash != requestHash {
+		apperrors.Error(w, "Idempotency-Key was already used for a different request", http.StatusUnprocessableEntity)
+		return
+	}
+	if statusCode == nil {
+		apperrors.Error(w, "A request with this Idempotency-Key is still in progress", http.StatusConflict)
+		return
+	}
+
+	logger.Debug("Replaying response for idempotency key of user %d", userID)
+	if contentType != nil && *contentType != "" {
+		w.Header().Set("Content-Type", *contentType)
+	}
+	w.Header().Set("Idempotent-Replayed", "true")
+	w.WriteHeader(*statusCode)
+	w.Write(body)
+}
+
+// releaseIdempotencyKey deletes a key whose response was not stored
+func releaseIdempotencyKey(userID int, key string) {
+	_, err := database.GetPool().Exec(context.Background(),
+		"DELETE FROM idempotency_keys WHERE user_id = $1 AND idempotency_key = $2 AND status_code IS NULL",
+		userID, key)
+	if err != nil {
+		logger.Error("Failed to release idempotency key: %v", err)
+	}
+}
+
+// idempotencyRequestHash identifies a request by method, path and body. Multipart
+// boundaries are random per request, so they are left out.
+func idempotencyRequestHash(r *http.Request, body []byte) string {
+	if _, params, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err == nil && params["boundary"] != "" {
+		body = bytes.ReplaceAll(body, []byte(params["boundary"]), nil)
+	}
+
+	h := sha256.New()
+	io.WriteString(h, r.Method+" "+r.URL.Path+"\n")
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// idempotencyRecorder passes the response through while keeping a copy to store
+type idempotencyRecorder struct {
+	http.ResponseWriter
+	statusCode  int
+	body        bytes.Buffer
+	overflow    bool
+	wroteHeader bool
+}
+
+func (rec *idempotencyRecorder) WriteHeader(code int) {
+	if !rec.wroteHeader {
+		rec.statusCode = code
+		rec.wroteHeader = true
+	}
+	rec.ResponseWriter.WriteHeader(code)
+}
+
+func (rec *idempotencyRecorder) Write(b []byte) (int, error) {
+	rec.wroteHeader = true
+	if !rec.overflow {
+		if rec.body.Len()+len(b) > maxIdempotentResponseSize {
+			rec.overflow = true
+			rec.body.Reset()
+		} else {
+			rec.body.Write(b)
+		}
+	}
+	return rec.ResponseWriter.Write(b)
+}
+
+// Unwrap lets http.ResponseController reach the underlying connection
+func (rec *idempotencyRecorder) Unwrap() http.ResponseWriter {
+	return rec.ResponseWriter
+}
@@ -0,0 +1,114 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/nomdb/backend/internal/models"
+)
+
+func TestIdempotencyRequestHash(t *testing.T) {
+	newRequest := func(path, contentType, body string) (*http.Request, []byte) {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", contentType)
+		return req, []byte(body)
+	}
+
+	a := idempotencyRequestHash(newRequest("/api/ratings", "application/json", `{"rating":5}`))
+	b := idempotencyRequestHash(newRequest("/api/ratings", "application/json", `{"rating":5}`))
+	if a != b {
+		t.Error("Expected identical requests to have the same hash")
+	}
+	if a == idempotencyRequestHash(newRequest("/api/ratings", "application/json", `{"rating":4}`)) {
+		t.Error("Expected different bodies to have different hashes")
+	}
+	if a == idempotencyRequestHash(newRequest("/api/suggestions", "application/json", `{"rating":5}`)) {
+		t.Error("Expected different paths to have different hashes")
+	}
+
+	multipart := func(boundary string) string {
+		return idempotencyRequestHash(newRequest("/api/restaurants/1/photos",
+			"multipart/form-data; boundary="+boundary,
+			"--"+boundary+"\r\nContent-Disposition: form-data; name=\"caption\"\r\n\r\nMenu\r\n--"+boundary+"--\r\n"))
+	}
+	if multipart("abc123") != multipart("xyz789") {
+		t.Error("Expected multipart boundaries to be ignored")
+	}
+}
+
+func TestIdempotencyMiddleware_PassThrough(t *testing.T) {
+	calls := 0
+	handler := IdempotencyMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	user := &models.User{ID: 1}
+	tests := []struct {
+		name   string
+		method string
+		key    string
+		user   *models.User
+	}{
+		{"no key", http.MethodPost, "", user},
+		{"not a POST", http.MethodPut, "key-1", user},
+		{"anonymous", http.MethodPost, "key-1", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls = 0
+			req := httptest.NewRequest(tt.method, "/api/ratings", strings.NewReader("{}"))
+			if tt.key != "" {
+				req.Header.Set(IdempotencyKeyHeader, tt.key)
+			}
+			if tt.user != nil {
+				req = req.WithContext(context.WithValue(req.Context(), models.UserContextKey, tt.user))
+			}
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if calls != 1 || rec.Code != http.StatusCreated {
+				t.Errorf("Expected the request to pass through, got %d calls and status %d", calls, rec.Code)
+			}
+		})
+	}
+}
+
+func TestIdempotencyMiddleware_KeyTooLong(t *testing.T) {
+	handler := IdempotencyMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Handler should not be called")
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/ratings", strings.NewReader("{}"))
+	req.Header.Set(IdempotencyKeyHeader, strings.Repeat("k", maxIdempotencyKeyLength+1))
+	req = req.WithContext(context.WithValue(req.Context(), models.UserContextKey, &models.User{ID: 1}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400, got %d", rec.Code)
+	}
+}
+
+func TestIdempotencyRecorder(t *testing.T) {
+	w := httptest.NewRecorder()
+	rec := &idempotencyRecorder{ResponseWriter: w, statusCode: http.StatusOK}
+	rec.WriteHeader(http.StatusCreated)
+	rec.Write([]byte(`{"id":1}`))
+
+	if rec.statusCode != http.StatusCreated || rec.body.String() != `{"id":1}` {
+		t.Errorf("Expected recorded 201 {\"id\":1}, got %d %s", rec.statusCode, rec.body.String())
+	}
+	if w.Code != http.StatusCreated || w.Body.String() != `{"id":1}` {
+		t.Error("Expected the response to be passed through")
+	}
+
+	rec.Write(make([]byte, maxIdempotentResponseSize))
+	if !rec.overflow || rec.body.Len() != 0 {
+		t.Error("Expected oversized responses not to be kept")
+	}
+}
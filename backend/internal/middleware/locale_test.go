@@ -0,0 +1,37 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/nomdb/backend/internal/errors"
+	"github.com/nomdb/backend/internal/i18n"
+)
+
+func TestLocaleMiddleware(t *testing.T) {
+	var locale string
+	handler := LocaleMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		locale = i18n.FromContext(r.Context())
+		errors.Error(w, "Restaurant not found", http.StatusNotFound)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/restaurants/1", nil)
+	req.Header.Set("Accept-Language", "de-DE,de;q=0.9,en;q=0.8")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if locale != i18n.German {
+		t.Errorf("Expected the German locale in the context, got %q", locale)
+	}
+	if got := rec.Header().Get("Content-Language"); got != i18n.German {
+		t.Errorf("Expected Content-Language de, got %q", got)
+	}
+	if got := rec.Header().Get("Vary"); got != "Accept-Language" {
+		t.Errorf("Expected Vary: Accept-Language, got %q", got)
+	}
+	if body := rec.Body.String(); !strings.Contains(body, "Restaurant nicht gefunden") {
+		t.Errorf("Expected a German error message, got %s", body)
+	}
+}
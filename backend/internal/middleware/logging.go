@@ -1,8 +1,10 @@
 package middleware
 
 import (
+	"bytes"
 	"context"
 	"net/http"
+	"slices"
 	"strings"
 	"time"
 
@@ -14,6 +16,7 @@ type responseWriter struct {
 	http.ResponseWriter
 	statusCode int
 	bytes      int64
+	body       *bytes.Buffer // Start of the response body when debug logging, else nil
 }
 
 func (rw *responseWriter) WriteHeader(code int) {
@@ -24,9 +27,23 @@ func (rw *responseWriter) WriteHeader(code int) {
 func (rw *responseWriter) Write(b []byte) (int, error) {
 	n, err := rw.ResponseWriter.Write(b)
 	rw.bytes += int64(n)
+	if rw.body != nil && rw.body.Len() <= debugBodyLimit {
+		rw.body.Write(b[:min(n, debugBodyLimit+1-rw.body.Len())])
+	}
 	return n, err
 }
 
+func (rw *responseWriter) Flush() {
+	if f, ok := rw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap lets http.ResponseController reach the underlying connection
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
 // RequestIDMiddleware adds a unique request ID to each request
 func RequestIDMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -51,7 +68,7 @@ func RequestIDMiddleware(next http.Handler) http.Handler {
 func LoggingMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Skip logging for health check requests from Docker
-		if r.URL.Path == "/api/health" && r.Header.Get("User-Agent") == "Wget" {
+		if strings.HasPrefix(r.URL.Path, "/api/health") && r.Header.Get("User-Agent") == "Wget" {
 			next.ServeHTTP(w, r)
 			return
 		}
@@ -71,15 +88,20 @@ func LoggingMiddleware(next http.Handler) http.Handler {
 			bytes:          0,
 		}
 
-		// Get client IP (handle X-Forwarded-For for proxies)
-		clientIP := r.RemoteAddr
-		if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
-			clientIP = strings.Split(forwarded, ",")[0]
-		}
+		// Get client IP (X-Forwarded-For is only used behind trusted proxies)
+		clientIP := ClientIP(r)
 
 		// Log incoming request
 		logger.Debug("→ %s %s from %s", r.Method, r.URL.Path, clientIP)
 
+		// Capture the bodies of routes admins enabled debug logging for
+		var requestBody []byte
+		debug := debugLogged(r.Method, RouteFromContext(r.Context()))
+		if debug {
+			requestBody = captureRequestBody(r)
+			rw.body = &bytes.Buffer{}
+		}
+
 		// Call the next handler
 		next.ServeHTTP(rw, r)
 
@@ -87,7 +109,7 @@ func LoggingMiddleware(next http.Handler) http.Handler {
 		duration := time.Since(start)
 
 		// Record metrics
-		GetMetrics().RecordRequest(r.Method, r.URL.Path, rw.statusCode, duration)
+		GetMetrics().RecordRequest(r.Method, RouteFromContext(r.Context()), rw.statusCode, duration)
 
 		// Log response with structured logging
 		logger.LogRequest(
@@ -99,9 +121,39 @@ func LoggingMiddleware(next http.Handler) http.Handler {
 			rw.statusCode,
 			rw.bytes,
 		)
+		logger.LogSlowRequest(r.Method, RouteFromContext(r.Context()), r.URL.Path, requestID,
+			queryParamNames(r), duration, rw.statusCode)
+		if debug {
+			logDebugRequest(r, rw, requestID, requestBody, duration)
+		}
+	})
+}
+
+// logDebugRequest logs a request with its redacted request and response bodies
+func logDebugRequest(r *http.Request, rw *responseWriter, requestID string, requestBody []byte, duration time.Duration) {
+	logger.InfoWithFields("🐞 Request debug log", map[string]interface{}{
+		"request_id":    requestID,
+		"method":        r.Method,
+		"uri":           redactedRequestURI(r.URL),
+		"route":         RouteFromContext(r.Context()),
+		"status":        rw.statusCode,
+		"duration_ms":   float64(duration.Microseconds()) / 1000,
+		"request_body":  debugBody(r.Header.Get("Content-Type"), requestBody, r.ContentLength),
+		"response_body": debugBody(rw.Header().Get("Content-Type"), rw.body.Bytes(), rw.bytes),
 	})
 }
 
+// queryParamNames returns the sorted names of the query parameters of r
+func queryParamNames(r *http.Request) []string {
+	query := r.URL.Query()
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
 func getStatusEmoji(statusCode int) string {
 	switch {
 	case statusCode >= 500:
@@ -1,12 +1,19 @@
 package middleware
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/nomdb/backend/internal/logger"
+	"github.com/rs/zerolog"
 )
 
 func TestRequestIDMiddleware(t *testing.T) {
@@ -203,6 +210,102 @@ func TestLoggingMiddleware_XForwardedFor(t *testing.T) {
 	}
 }
 
+func TestLoggingMiddleware_SlowRequest(t *testing.T) {
+	var buf bytes.Buffer
+	previous := logger.Logger
+	logger.Logger = zerolog.New(&buf)
+	defer func() { logger.Logger = previous }()
+	logger.SetSlowThresholds(time.Millisecond, 250*time.Millisecond)
+	defer logger.SetSlowThresholds(time.Second, 250*time.Millisecond)
+
+	handler := LoggingMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(2 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest("GET", "/api/restaurants/nearby?radius=500&lat=52.5&lng=13.4", nil)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	output := buf.String()
+	for _, expected := range []string{"Slow HTTP request", `"level":"warn"`, `"query_params":["lat","lng","radius"]`} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected log to contain %s, got %s", expected, output)
+		}
+	}
+	if strings.Contains(output, "52.5") {
+		t.Errorf("Expected query parameter values not to be logged, got %s", output)
+	}
+}
+
+func TestLoggingMiddleware_DebugBodies(t *testing.T) {
+	var buf bytes.Buffer
+	previous := logger.Logger
+	logger.Logger = zerolog.New(&buf)
+	defer func() { logger.Logger = previous }()
+	SetDebugRoutes([]string{"POST /api/auth/login", " /api/ratings/{id} "})
+	defer SetDebugRoutes(nil)
+
+	handler := LoggingMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if !strings.Contains(string(body), "hunter2") {
+			t.Errorf("Expected the handler to read the full body, got %s", body)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token":"eyJ.secret","user":{"id":1,"username":"alice"}}`))
+	}))
+	serve := func(method, route string) string {
+		buf.Reset()
+		req := httptest.NewRequest(method, "/api/auth/login?token=abc", strings.NewReader(`{"email":"alice@example.com","password":"hunter2"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req = req.WithContext(context.WithValue(req.Context(), routeContextKey{}, route))
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+		return buf.String()
+	}
+
+	output := serve(http.MethodPost, "/api/auth/login")
+	for _, expected := range []string{"Request debug log", `"email":"alice@example.com"`, `"username":"alice"`, `"password":"[REDACTED]"`, `"access_token":"[REDACTED]"`, "token=REDACTED"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected log to contain %s, got %s", expected, output)
+		}
+	}
+	for _, secret := range []string{"hunter2", "eyJ.secret", "abc"} {
+		if strings.Contains(output, secret) {
+			t.Errorf("Expected %s to be redacted, got %s", secret, output)
+		}
+	}
+
+	if output := serve(http.MethodPut, "/api/ratings/{id}"); !strings.Contains(output, "Request debug log") {
+		t.Errorf("Expected routes without a method to match every method, got %s", output)
+	}
+	if output := serve(http.MethodGet, "/api/auth/login"); strings.Contains(output, "Request debug log") {
+		t.Errorf("Expected other methods not to be debug logged, got %s", output)
+	}
+}
+
+func TestDebugBody(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		size        int64
+		want        string
+	}{
+		{"empty", "application/json", "", 0, "null"},
+		{"JSON", "application/json; charset=utf-8", `{"name":"Pho","items":[{"api_key":"k"}]}`, -1, `{"items":[{"api_key":"[REDACTED]"}],"name":"Pho"}`},
+		{"invalid JSON", "application/json", `{"password":`, 12, `"application/json, 12 bytes"`},
+		{"multipart", "multipart/form-data; boundary=x", "--x", 2048, `"multipart/form-data, 2048 bytes"`},
+		{"too large", "application/json", strings.Repeat("a", debugBodyLimit+1), -1, fmt.Sprintf(`"application/json, more than %d bytes"`, debugBodyLimit)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _ := json.Marshal(debugBody(tt.contentType, []byte(tt.body), tt.size))
+			if string(got) != tt.want {
+				t.Errorf("Expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
 func TestResponseWriter_WriteHeader(t *testing.T) {
 	rec := httptest.NewRecorder()
 	rw := &responseWriter{
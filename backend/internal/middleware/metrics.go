@@ -1,6 +1,8 @@
 package middleware
 
 import (
+	"math/rand/v2"
+	"slices"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -8,16 +10,23 @@ import (
 	"github.com/nomdb/backend/internal/logger"
 )
 
+// maxResponseTimeSamples is the size of the response time reservoir percentiles are
+// computed from
+const maxResponseTimeSamples = 1000
+
 // Metrics holds application metrics
 type Metrics struct {
 	TotalRequests     uint64
 	TotalErrors       uint64
 	RequestsByMethod  map[string]*uint64
-	RequestsByPath    map[string]*uint64
+	RequestsByPath    map[string]*uint64 // Keyed by route template, e.g. /api/restaurants/{id}
 	RequestsByStatus  map[int]*uint64
-	ResponseTimes     []time.Duration
+	ResponseTimes     []time.Duration // Uniform random sample of all response times
+	responseTimeCount uint64
+	responseTimeTotal time.Duration
 	mu                sync.RWMutex
 	lastLogTime       time.Time
+	countingSince     time.Time // Earlier than lastLogTime when restored from a snapshot
 }
 
 var (
@@ -32,8 +41,9 @@ func GetMetrics() *Metrics {
 			RequestsByMethod: make(map[string]*uint64),
 			RequestsByPath:   make(map[string]*uint64),
 			RequestsByStatus: make(map[int]*uint64),
-			ResponseTimes:    make([]time.Duration, 0, 1000),
+			ResponseTimes:    make([]time.Duration, 0, maxResponseTimeSamples),
 			lastLogTime:      time.Now(),
+			countingSince:    time.Now(),
 		}
 
 		// Start periodic metrics logging
@@ -42,8 +52,9 @@ func GetMetrics() *Metrics {
 	return appMetrics
 }
 
-// RecordRequest records metrics for an HTTP request
-func (m *Metrics) RecordRequest(method, path string, status int, duration time.Duration) {
+// RecordRequest records metrics for an HTTP request. route should be a route template
+// rather than the raw path, which would create an entry per restaurant ID.
+func (m *Metrics) RecordRequest(method, route string, status int, duration time.Duration) {
 	// Increment total requests
 	atomic.AddUint64(&m.TotalRequests, 1)
 
@@ -60,14 +71,12 @@ func (m *Metrics) RecordRequest(method, path string, status int, duration time.D
 	}
 	atomic.AddUint64(m.RequestsByMethod[method], 1)
 
-	// Record by path (limit to prevent memory bloat)
-	if len(m.RequestsByPath) < 100 {
-		if m.RequestsByPath[path] == nil {
-			var count uint64
-			m.RequestsByPath[path] = &count
-		}
-		atomic.AddUint64(m.RequestsByPath[path], 1)
+	// Record by route template (bounded by the number of routes)
+	if m.RequestsByPath[route] == nil {
+		var count uint64
+		m.RequestsByPath[route] = &count
 	}
+	atomic.AddUint64(m.RequestsByPath[route], 1)
 
 	// Record by status
 	if m.RequestsByStatus[status] == nil {
@@ -76,9 +85,14 @@ func (m *Metrics) RecordRequest(method, path string, status int, duration time.D
 	}
 	atomic.AddUint64(m.RequestsByStatus[status], 1)
 
-	// Record response time (limit array size)
-	if len(m.ResponseTimes) < 1000 {
+	// Record response time: the average covers every request, percentiles a reservoir
+	// sample that stays representative however many requests are served
+	m.responseTimeCount++
+	m.responseTimeTotal += duration
+	if len(m.ResponseTimes) < maxResponseTimeSamples {
 		m.ResponseTimes = append(m.ResponseTimes, duration)
+	} else if i := rand.Uint64N(m.responseTimeCount); i < maxResponseTimeSamples {
+		m.ResponseTimes[i] = duration
 	}
 	m.mu.Unlock()
 }
@@ -90,12 +104,8 @@ func (m *Metrics) GetStats() map[string]interface{} {
 
 	// Calculate average response time
 	var avgDuration time.Duration
-	if len(m.ResponseTimes) > 0 {
-		var total time.Duration
-		for _, d := range m.ResponseTimes {
-			total += d
-		}
-		avgDuration = total / time.Duration(len(m.ResponseTimes))
+	if m.responseTimeCount > 0 {
+		avgDuration = m.responseTimeTotal / time.Duration(m.responseTimeCount)
 	}
 
 	// Calculate percentiles (p50, p95, p99)
@@ -128,6 +138,7 @@ func (m *Metrics) GetStats() map[string]interface{} {
 		"p95_response_time": p95.String(),
 		"p99_response_time": p99.String(),
 		"uptime":          time.Since(m.lastLogTime).String(),
+		"counting_since":  m.countingSince,
 	}
 }
 
@@ -137,18 +148,8 @@ func (m *Metrics) calculatePercentiles() (p50, p95, p99 time.Duration) {
 		return
 	}
 
-	// Simple percentile calculation (could be optimized with sorting)
-	sorted := make([]time.Duration, len(m.ResponseTimes))
-	copy(sorted, m.ResponseTimes)
-
-	// Bubble sort (fine for small arrays)
-	for i := 0; i < len(sorted); i++ {
-		for j := i + 1; j < len(sorted); j++ {
-			if sorted[i] > sorted[j] {
-				sorted[i], sorted[j] = sorted[j], sorted[i]
-			}
-		}
-	}
+	sorted := slices.Clone(m.ResponseTimes)
+	slices.Sort(sorted)
 
 	p50Index := int(float64(len(sorted)) * 0.50)
 	p95Index := int(float64(len(sorted)) * 0.95)
@@ -188,6 +189,9 @@ func (m *Metrics) Reset() {
 	m.RequestsByMethod = make(map[string]*uint64)
 	m.RequestsByPath = make(map[string]*uint64)
 	m.RequestsByStatus = make(map[int]*uint64)
-	m.ResponseTimes = make([]time.Duration, 0, 1000)
+	m.ResponseTimes = make([]time.Duration, 0, maxResponseTimeSamples)
+	m.responseTimeCount = 0
+	m.responseTimeTotal = 0
 	m.lastLogTime = time.Now()
+	m.countingSince = m.lastLogTime
 }
@@ -0,0 +1,191 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/nomdb/backend/internal/database"
+	"github.com/nomdb/backend/internal/logger"
+)
+
+// metricsPersistenceEnabled is set by InitMetricsPersistence
+var (
+	metricsPersistenceEnabled bool
+	metricsRetention          time.Duration
+	metricsInstance           string // Records and restores the snapshots of this instance
+)
+
+// metricsSnapshot is the persisted state of Metrics. The response time sample is not
+// kept, so percentiles start over after a restart.
+type metricsSnapshot struct {
+	CountingSince     time.Time
+	TotalRequests     uint64
+	TotalErrors       uint64
+	ByMethod          map[string]uint64
+	ByRoute           map[string]uint64
+	ByStatus          map[int]uint64
+	ResponseTimeCount uint64
+	ResponseTimeTotal time.Duration
+	P50, P95, P99     time.Duration
+}
+
+// snapshot returns the current counters of m
+func (m *Metrics) snapshot() metricsSnapshot {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	s := metricsSnapshot{
+		CountingSince:     m.countingSince,
+		TotalRequests:     atomic.LoadUint64(&m.TotalRequests),
+		TotalErrors:       atomic.LoadUint64(&m.TotalErrors),
+		ByMethod:          make(map[string]uint64, len(m.RequestsByMethod)),
+		ByRoute:           make(map[string]uint64, len(m.RequestsByPath)),
+		ByStatus:          make(map[int]uint64, len(m.RequestsByStatus)),
+		ResponseTimeCount: m.responseTimeCount,
+		ResponseTimeTotal: m.responseTimeTotal,
+	}
+	for k, v := range m.RequestsByMethod {
+		s.ByMethod[k] = atomic.LoadUint64(v)
+	}
+	for k, v := range m.RequestsByPath {
+		s.ByRoute[k] = atomic.LoadUint64(v)
+	}
+	for k, v := range m.RequestsByStatus {
+		s.ByStatus[k] = atomic.LoadUint64(v)
+	}
+	s.P50, s.P95, s.P99 = m.calculatePercentiles()
+	return s
+}
+
+// restore adds the counters of a snapshot to m, keeping requests recorded since the
+// process started
+func (m *Metrics) restore(s metricsSnapshot) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	atomic.AddUint64(&m.TotalRequests, s.TotalRequests)
+	atomic.AddUint64(&m.TotalErrors, s.TotalErrors)
+	addCounts(m.RequestsByMethod, s.ByMethod)
+	addCounts(m.RequestsByPath, s.ByRoute)
+	addCounts(m.RequestsByStatus, s.ByStatus)
+	m.responseTimeCount += s.ResponseTimeCount
+	m.responseTimeTotal += s.ResponseTimeTotal
+	if !s.CountingSince.IsZero() {
+		m.countingSince = s.CountingSince
+	}
+}
+
+func addCounts[K comparable](counters map[K]*uint64, counts map[K]uint64) {
+	for k, count := range counts {
+		if counters[k] == nil {
+			counters[k] = new(uint64)
+		}
+		atomic.AddUint64(counters[k], count)
+	}
+}
+
+// InitMetricsPersistence restores the request metrics from the latest snapshot of
+// instance and saves a snapshot every interval, deleting those older than retention.
+// An interval of 0 disables persistence. Each replica of a cluster needs its own,
+// stable instance name to restore only its own counters; a single instance uses "".
+func InitMetricsPersistence(interval, retention time.Duration, instance string) {
+	if interval <= 0 {
+		logger.Info("📊 Metrics persistence disabled")
+		return
+	}
+	metricsPersistenceEnabled = true
+	metricsRetention = retention
+	metricsInstance = instance
+
+	ctx := context.Background()
+	if err := restoreMetrics(ctx); err != nil {
+		logger.Warn("⚠️  Failed to restore metrics, counting from zero: %v", err)
+	}
+
+	logger.Info("📊 Metrics persisted every %s, kept for %s", interval, retention)
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			SaveMetricsSnapshot(ctx)
+		}
+	}()
+}
+
+// restoreMetrics loads the latest snapshot into the metrics
+func restoreMetrics(ctx context.Context) error {
+	var s metricsSnapshot
+	var byMethod, byRoute, byStatus []byte
+	var responseTimeTotalMS float64
+	err := database.GetPool().QueryRow(ctx,
+		`SELECT counting_since, total_requests, total_errors, requests_by_method, requests_by_route,
+			requests_by_status, response_time_count, response_time_total_ms
+		FROM metrics_snapshots WHERE instance = $1 ORDER BY recorded_at DESC, id DESC LIMIT 1`, metricsInstance).
+		Scan(&s.CountingSince, &s.TotalRequests, &s.TotalErrors, &byMethod, &byRoute,
+			&byStatus, &s.ResponseTimeCount, &responseTimeTotalMS)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, field := range []struct {
+		data   []byte
+		target any
+	}{{byMethod, &s.ByMethod}, {byRoute, &s.ByRoute}, {byStatus, &s.ByStatus}} {
+		if err := json.Unmarshal(field.data, field.target); err != nil {
+			return err
+		}
+	}
+	s.ResponseTimeTotal = time.Duration(responseTimeTotalMS * float64(time.Millisecond))
+
+	GetMetrics().restore(s)
+	logger.Info("📊 Restored %d requests counted since %s", s.TotalRequests, s.CountingSince.Format(time.RFC3339))
+	return nil
+}
+
+// SaveMetricsSnapshot persists the current metrics and deletes expired snapshots. It
+// does nothing unless persistence is enabled; call it on shutdown to keep the counts
+// since the last snapshot.
+func SaveMetricsSnapshot(ctx context.Context) {
+	if !metricsPersistenceEnabled {
+		return
+	}
+
+	s := GetMetrics().snapshot()
+	byMethod, _ := json.Marshal(s.ByMethod)
+	byRoute, _ := json.Marshal(s.ByRoute)
+	byStatus, _ := json.Marshal(s.ByStatus)
+
+	pool := database.GetPool()
+	_, err := pool.Exec(ctx,
+		`INSERT INTO metrics_snapshots (counting_since, total_requests, total_errors, requests_by_method,
+			requests_by_route, requests_by_status, response_time_count, response_time_total_ms,
+			p50_response_time_ms, p95_response_time_ms, p99_response_time_ms, instance)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`,
+		s.CountingSince, s.TotalRequests, s.TotalErrors, byMethod, byRoute, byStatus,
+		s.ResponseTimeCount, durationMS(s.ResponseTimeTotal), durationMS(s.P50), durationMS(s.P95), durationMS(s.P99), metricsInstance)
+	if err != nil {
+		logger.Error("❌ Failed to save metrics snapshot: %v", err)
+		return
+	}
+
+	if metricsRetention > 0 {
+		result, err := pool.Exec(ctx, "DELETE FROM metrics_snapshots WHERE recorded_at < $1", time.Now().Add(-metricsRetention))
+		if err != nil {
+			logger.Error("❌ Failed to delete expired metrics snapshots: %v", err)
+			return
+		}
+		logger.Debug("Saved metrics snapshot, deleted %d expired ones", result.RowsAffected())
+	}
+}
+
+func durationMS(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
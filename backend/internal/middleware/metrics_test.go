@@ -2,8 +2,12 @@ package middleware
 
 import (
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
+
+	"github.com/gorilla/mux"
 )
 
 func TestMetrics_RecordRequest(t *testing.T) {
@@ -291,24 +295,31 @@ func TestMetrics_Reset(t *testing.T) {
 	}
 }
 
-func TestMetrics_PathLimit(t *testing.T) {
-	metrics := &Metrics{
-		RequestsByMethod: make(map[string]*uint64),
-		RequestsByPath:   make(map[string]*uint64),
-		RequestsByStatus: make(map[int]*uint64),
-		ResponseTimes:    make([]time.Duration, 0, 1000),
-		lastLogTime:      time.Now(),
+func TestMetrics_RouteTemplates(t *testing.T) {
+	router := mux.NewRouter()
+	router.HandleFunc("/api/restaurants/{id}", func(w http.ResponseWriter, r *http.Request) {})
+	handler := RouteMiddleware(router)(LoggingMiddleware(router))
+
+	metrics := GetMetrics()
+	metrics.mu.RLock()
+	initial := uint64(0)
+	if count := metrics.RequestsByPath["/api/restaurants/{id}"]; count != nil {
+		initial = *count
 	}
+	metrics.mu.RUnlock()
 
-	// Try to record 150 different paths (limit is 100)
 	for i := 0; i < 150; i++ {
-		path := fmt.Sprintf("/api/test/%d", i)
-		metrics.RecordRequest("GET", path, 200, 10*time.Millisecond)
+		path := fmt.Sprintf("/api/restaurants/%d", i)
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
 	}
 
-	// Verify path count is limited to 100
-	if len(metrics.RequestsByPath) > 100 {
-		t.Errorf("Expected RequestsByPath to be limited to 100, got %d", len(metrics.RequestsByPath))
+	metrics.mu.RLock()
+	defer metrics.mu.RUnlock()
+	if count := metrics.RequestsByPath["/api/restaurants/{id}"]; count == nil || *count != initial+150 {
+		t.Error("Expected requests to be recorded under their route template")
+	}
+	if metrics.RequestsByPath["/api/restaurants/7"] != nil {
+		t.Error("Expected raw paths not to be recorded")
 	}
 }
 
@@ -332,6 +343,33 @@ func TestMetrics_ResponseTimeLimit(t *testing.T) {
 	}
 }
 
+func TestMetrics_ResponseTimesKeepRecording(t *testing.T) {
+	metrics := &Metrics{
+		RequestsByMethod: make(map[string]*uint64),
+		RequestsByPath:   make(map[string]*uint64),
+		RequestsByStatus: make(map[int]*uint64),
+		ResponseTimes:    make([]time.Duration, 0, 1000),
+		lastLogTime:      time.Now(),
+	}
+
+	// 1000 fast requests followed by 9000 slow ones
+	for i := 0; i < 10000; i++ {
+		duration := time.Millisecond
+		if i >= 1000 {
+			duration = 100 * time.Millisecond
+		}
+		metrics.RecordRequest("GET", "/api/test", 200, duration)
+	}
+
+	p50, _, _ := metrics.calculatePercentiles()
+	if p50 != 100*time.Millisecond {
+		t.Errorf("Expected p50 to reflect requests after the first 1000, got %v", p50)
+	}
+	if avg := metrics.GetStats()["avg_response_time"]; avg != "90.1ms" {
+		t.Errorf("Expected the average over all requests to be 90.1ms, got %v", avg)
+	}
+}
+
 func TestGetMetrics_Singleton(t *testing.T) {
 	// Get metrics instance twice
 	m1 := GetMetrics()
@@ -388,3 +426,44 @@ func BenchmarkMetrics_CalculatePercentiles(b *testing.B) {
 		metrics.calculatePercentiles()
 	}
 }
+
+func TestMetrics_SnapshotRestore(t *testing.T) {
+	newMetrics := func() *Metrics {
+		return &Metrics{
+			RequestsByMethod: make(map[string]*uint64),
+			RequestsByPath:   make(map[string]*uint64),
+			RequestsByStatus: make(map[int]*uint64),
+			ResponseTimes:    make([]time.Duration, 0, 1000),
+			lastLogTime:      time.Now(),
+			countingSince:    time.Now(),
+		}
+	}
+
+	before := newMetrics()
+	before.countingSince = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	before.RecordRequest("GET", "/api/restaurants", 200, 10*time.Millisecond)
+	before.RecordRequest("POST", "/api/restaurants", 500, 30*time.Millisecond)
+	snapshot := before.snapshot()
+
+	// Requests served before the snapshot was restored are kept
+	after := newMetrics()
+	after.RecordRequest("GET", "/api/restaurants", 200, 20*time.Millisecond)
+	after.restore(snapshot)
+
+	stats := after.GetStats()
+	if stats["total_requests"] != uint64(3) || stats["total_errors"] != uint64(1) {
+		t.Errorf("Expected 3 requests and 1 error, got %v and %v", stats["total_requests"], stats["total_errors"])
+	}
+	if byPath := stats["requests_by_path"].(map[string]uint64); byPath["/api/restaurants"] != 3 {
+		t.Errorf("Expected 3 requests for /api/restaurants, got %d", byPath["/api/restaurants"])
+	}
+	if byStatus := stats["requests_by_status"].(map[int]uint64); byStatus[200] != 2 || byStatus[500] != 1 {
+		t.Errorf("Unexpected requests by status: %v", byStatus)
+	}
+	if stats["avg_response_time"] != "20ms" {
+		t.Errorf("Expected average response time 20ms, got %v", stats["avg_response_time"])
+	}
+	if !after.countingSince.Equal(before.countingSince) {
+		t.Errorf("Expected counting start %s, got %s", before.countingSince, after.countingSince)
+	}
+}
@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+func TestPrometheusMiddleware_RouteTemplate(t *testing.T) {
+	router := mux.NewRouter()
+	api := router.PathPrefix("/api").Subrouter()
+	api.HandleFunc("/restaurants/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}).Methods("DELETE")
+
+	handler := PrometheusMiddleware(router)(router)
+	for _, path := range []string{"/api/restaurants/1", "/api/restaurants/2", "/no/such/path"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodDelete, path, nil))
+	}
+
+	rec := httptest.NewRecorder()
+	PrometheusHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := rec.Body.String()
+
+	expected := []string{
+		`nomdb_http_requests_total{method="DELETE",route="/api/restaurants/{id}",status="204"} 2`,
+		`nomdb_http_requests_total{method="DELETE",route="unmatched",status="404"} 1`,
+		`nomdb_http_request_duration_seconds_count{method="DELETE",route="/api/restaurants/{id}"} 2`,
+		"go_goroutines",
+	}
+	for _, line := range expected {
+		if !strings.Contains(body, line) {
+			t.Errorf("Expected metrics to contain %q", line)
+		}
+	}
+	if strings.Contains(body, "/api/restaurants/1") {
+		t.Error("Expected raw paths not to be used as labels")
+	}
+}
+
+func TestMetricMethod(t *testing.T) {
+	if metricMethod(http.MethodPatch) != http.MethodPatch {
+		t.Error("Expected standard methods to be kept")
+	}
+	if metricMethod("PROPFIND") != "OTHER" {
+		t.Error("Expected non-standard methods to be folded into OTHER")
+	}
+}
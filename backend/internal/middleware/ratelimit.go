@@ -1,13 +1,38 @@
 package middleware
 
 import (
+	"math"
 	"net/http"
+	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	apperrors "github.com/nomdb/backend/internal/errors"
+	"github.com/nomdb/backend/internal/logger"
+	"github.com/nomdb/backend/internal/models"
 	"golang.org/x/time/rate"
 )
 
+// Route groups with separate per-IP rate limits
+const (
+	RateLimitGroupAuth   = "auth"   // Login, registration, token refresh and password resets
+	RateLimitGroupUpload = "upload" // Photo uploads and resizing
+	RateLimitGroupRead   = "read"   // GET, HEAD and OPTIONS requests
+	RateLimitGroupWrite  = "write"  // Everything else
+)
+
+// RateLimits configures the per-IP limit of each route group and the limit per
+// authenticated user across all of their IPs, in requests per minute (0 = unlimited)
+type RateLimits struct {
+	Auth   int
+	Upload int
+	Read   int
+	Write  int
+	User   int
+}
+
 // IPRateLimiter manages rate limiters for different IP addresses
 type IPRateLimiter struct {
 	ips map[string]*rate.Limiter
@@ -16,6 +41,23 @@ type IPRateLimiter struct {
 	b   int
 }
 
+// RouteRateLimiter limits requests per IP with a separate budget per route group, so
+// browsing does not use up the budget for uploads and logins
+type RouteRateLimiter struct {
+	mu     sync.RWMutex
+	groups map[string]*IPRateLimiter // nil for unlimited groups
+}
+
+// defaultUserRateLimit is the per-user limit in requests per minute
+var defaultUserRateLimit atomic.Int64
+
+func init() {
+	defaultUserRateLimit.Store(300)
+}
+
+// userLimiters holds one token bucket per authenticated user
+var userLimiters = newAPIKeyLimiters("user")
+
 // NewIPRateLimiter creates a new IP-based rate limiter
 // r: requests per second
 // b: burst size (max requests in short burst)
@@ -42,6 +84,120 @@ func (i *IPRateLimiter) GetLimiter(ip string) *rate.Limiter {
 	return limiter
 }
 
+// newPerMinuteLimiter returns an IP rate limiter allowing perMinute requests per minute
+// with bursts of a fifth of that, or nil for 0 (unlimited)
+func newPerMinuteLimiter(perMinute int) *IPRateLimiter {
+	if perMinute <= 0 {
+		return nil
+	}
+	return NewIPRateLimiter(rate.Every(time.Minute/time.Duration(perMinute)), max(perMinute/5, 1))
+}
+
+// NewRouteRateLimiter creates per-IP rate limiters for the route groups and sets the
+// per-user limit
+func NewRouteRateLimiter(limits RateLimits) *RouteRateLimiter {
+	l := &RouteRateLimiter{}
+	l.SetLimits(limits)
+	return l
+}
+
+// SetLimits replaces the limits of the route groups and the per-user limit. Clients
+// start over with full buckets.
+func (l *RouteRateLimiter) SetLimits(limits RateLimits) {
+	groups := map[string]*IPRateLimiter{
+		RateLimitGroupAuth:   newPerMinuteLimiter(limits.Auth),
+		RateLimitGroupUpload: newPerMinuteLimiter(limits.Upload),
+		RateLimitGroupRead:   newPerMinuteLimiter(limits.Read),
+		RateLimitGroupWrite:  newPerMinuteLimiter(limits.Write),
+	}
+	l.mu.Lock()
+	l.groups = groups
+	l.mu.Unlock()
+	defaultUserRateLimit.Store(int64(limits.User))
+	userLimiters.reset()
+
+	logger.Info("🔒 Rate limiting enabled (req/min per IP): auth %d, uploads %d, reads %d, writes %d; %d per user (0 = unlimited)",
+		limits.Auth, limits.Upload, limits.Read, limits.Write, limits.User)
+}
+
+// group returns the limiter of a route group, nil if it is unlimited
+func (l *RouteRateLimiter) group(name string) *IPRateLimiter {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.groups[name]
+}
+
+// StartCleanupTask periodically clears the limiters of all route groups
+func (l *RouteRateLimiter) StartCleanupTask(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	go func() {
+		for range ticker.C {
+			l.mu.RLock()
+			for _, limiter := range l.groups {
+				if limiter != nil {
+					limiter.CleanupStaleEntries()
+				}
+			}
+			l.mu.RUnlock()
+		}
+	}()
+}
+
+// rateLimitGroup returns the route group of a request
+func rateLimitGroup(r *http.Request) string {
+	switch {
+	// Resizing a photo costs like an upload, even though it is a read
+	case strings.HasPrefix(r.URL.Path, "/api/photos/") && strings.HasSuffix(r.URL.Path, "/image"):
+		return RateLimitGroupUpload
+	case r.Method == http.MethodGet || r.Method == http.MethodHead || r.Method == http.MethodOptions:
+		return RateLimitGroupRead
+	case strings.HasPrefix(r.URL.Path, "/api/auth/"):
+		return RateLimitGroupAuth
+	case strings.HasPrefix(r.URL.Path, "/api/restaurants/") && strings.Contains(r.URL.Path, "/photos"):
+		return RateLimitGroupUpload
+	default:
+		return RateLimitGroupWrite
+	}
+}
+
+// isFrontendRequest reports whether r is for a page or file of the embedded frontend.
+// They are served from memory, and a single page load fetches several, so they are not
+// rate limited or shed like API requests.
+func isFrontendRequest(r *http.Request) bool {
+	return r.URL.Path != "/api" && !strings.HasPrefix(r.URL.Path, "/api/") && r.URL.Path != "/metrics"
+}
+
+// allowRequest sets the X-RateLimit-* headers for the bucket a token was taken from,
+// plus Retry-After when the request is rejected. When several limits apply to a
+// request, the headers describe the one with the fewest remaining requests.
+func allowRequest(w http.ResponseWriter, bucket TokenBucket, perMinute int) bool {
+	header := w.Header()
+	remaining := max(int(bucket.Tokens), 0)
+	if current, err := strconv.Atoi(header.Get("X-RateLimit-Remaining")); err != nil || remaining <= current || !bucket.Allowed {
+		header.Set("X-RateLimit-Limit", strconv.Itoa(perMinute))
+		header.Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+		// Seconds until the bucket is full again
+		header.Set("X-RateLimit-Reset", strconv.Itoa(int(math.Ceil((float64(bucket.Burst)-bucket.Tokens)/bucket.PerSecond))))
+	}
+	if !bucket.Allowed {
+		header.Set("Retry-After", strconv.Itoa(max(int(math.Ceil((1-bucket.Tokens)/bucket.PerSecond)), 1)))
+	}
+	return bucket.Allowed
+}
+
+// allowUser applies the per-user rate limit to requests authenticated with a session
+func allowUser(w http.ResponseWriter, r *http.Request, user *models.User) bool {
+	perMinute := int(defaultUserRateLimit.Load())
+	if perMinute <= 0 {
+		return true
+	}
+	if allowRequest(w, userLimiters.take(r.Context(), user.ID, perMinute), perMinute) {
+		return true
+	}
+	apperrors.Error(w, "Rate limit exceeded. Please try again later.", http.StatusTooManyRequests)
+	return false
+}
+
 // CleanupStaleEntries removes inactive rate limiters (run periodically)
 func (i *IPRateLimiter) CleanupStaleEntries() {
 	i.mu.Lock()
@@ -52,20 +208,27 @@ func (i *IPRateLimiter) CleanupStaleEntries() {
 	i.ips = make(map[string]*rate.Limiter)
 }
 
-// RateLimitMiddleware creates a rate limiting middleware
-// Example: 100 requests per minute = rate.Every(time.Minute/100), burst: 20
-func RateLimitMiddleware(limiter *IPRateLimiter) func(http.Handler) http.Handler {
+// RateLimitMiddleware creates a rate limiting middleware applying the per-IP limit of
+// the request's route group
+func RateLimitMiddleware(limiter *RouteRateLimiter) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			// Get IP address (handle X-Forwarded-For and X-Real-IP headers)
-			ip := getIPAddress(r)
+			group := rateLimitGroup(r)
+			groupLimiter := limiter.group(group)
+			if groupLimiter == nil || isFrontendRequest(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
 
-			// Get rate limiter for this IP
-			ipLimiter := limiter.GetLimiter(ip)
+			// Get IP address (X-Forwarded-For is only used behind trusted proxies)
+			ip := ClientIP(r)
 
 			// Check if request is allowed
-			if !ipLimiter.Allow() {
-				http.Error(w, "Rate limit exceeded. Please try again later.", http.StatusTooManyRequests)
+			perMinute := int(math.Round(float64(groupLimiter.r) * 60))
+			bucket := takeToken(r.Context(), "ip:"+group+":"+ip,
+				func() *rate.Limiter { return groupLimiter.GetLimiter(ip) }, perMinute, groupLimiter.b)
+			if !allowRequest(w, bucket, perMinute) {
+				apperrors.Error(w, "Rate limit exceeded. Please try again later.", http.StatusTooManyRequests)
 				return
 			}
 
@@ -74,25 +237,6 @@ func RateLimitMiddleware(limiter *IPRateLimiter) func(http.Handler) http.Handler
 	}
 }
 
-// getIPAddress extracts the real IP address from the request
-func getIPAddress(r *http.Request) string {
-	// Check X-Forwarded-For header (used by proxies/load balancers)
-	forwarded := r.Header.Get("X-Forwarded-For")
-	if forwarded != "" {
-		// Take the first IP if multiple are present
-		return forwarded
-	}
-
-	// Check X-Real-IP header
-	realIP := r.Header.Get("X-Real-IP")
-	if realIP != "" {
-		return realIP
-	}
-
-	// Fall back to RemoteAddr
-	return r.RemoteAddr
-}
-
 // StartCleanupTask starts a background goroutine to clean up stale rate limiters
 func (i *IPRateLimiter) StartCleanupTask(interval time.Duration) {
 	ticker := time.NewTicker(interval)
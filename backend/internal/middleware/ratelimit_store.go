@@ -0,0 +1,150 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/nomdb/backend/internal/logger"
+	"github.com/redis/go-redis/v9"
+	"golang.org/x/time/rate"
+)
+
+// Rate limit stores selectable via RATE_LIMIT_STORE
+const (
+	RateLimitStoreMemory = "memory"
+	RateLimitStoreRedis  = "redis"
+)
+
+// TokenBucket is the state of a rate limit after taking a token for a request
+type TokenBucket struct {
+	Allowed   bool
+	Tokens    float64 // Left after the request
+	Burst     int
+	PerSecond float64
+}
+
+// RateLimitStore keeps the token buckets of the rate limits where all instances see
+// them, so clients get the same budget however many replicas serve them.
+// RedisRateLimitStore implements it; without a store the buckets are kept in process.
+type RateLimitStore interface {
+	// Name returns the backend identifier for logging
+	Name() string
+	// Take takes a token from the bucket of key, which holds up to burst tokens and
+	// refills perMinute tokens a minute
+	Take(ctx context.Context, key string, perMinute, burst int) (TokenBucket, error)
+}
+
+var _ RateLimitStore = (*RedisRateLimitStore)(nil)
+
+// rateLimitStore is the shared store set by SetRateLimitStore, nil for in-process buckets
+var rateLimitStore atomic.Pointer[RateLimitStore]
+
+// rateLimitStoreDown is set while the shared store fails, so that an outage is logged
+// when it starts and ends rather than on every request
+var rateLimitStoreDown atomic.Bool
+
+// SetRateLimitStore keeps the token buckets of all rate limits in store instead of in
+// process; nil goes back to in-process buckets
+func SetRateLimitStore(store RateLimitStore) {
+	rateLimitStoreDown.Store(false)
+	if store == nil {
+		rateLimitStore.Store(nil)
+		return
+	}
+	rateLimitStore.Store(&store)
+	logger.Info("🔒 Rate limits shared between instances in %s", store.Name())
+}
+
+// takeToken takes a token from the bucket of key in the shared store, or from the
+// in-process limiter returned by local when there is none. When the shared store
+// fails, requests are limited per instance rather than rejected until it recovers.
+func takeToken(ctx context.Context, key string, local func() *rate.Limiter, perMinute, burst int) TokenBucket {
+	if store := rateLimitStore.Load(); store != nil {
+		bucket, err := (*store).Take(ctx, key, perMinute, burst)
+		if err == nil {
+			if rateLimitStoreDown.CompareAndSwap(true, false) {
+				logger.Info("✅ Rate limit store %s recovered, rate limits are shared again", (*store).Name())
+			}
+			return bucket
+		}
+		if rateLimitStoreDown.CompareAndSwap(false, true) {
+			logger.Warn("⚠️  Rate limit store %s failed, limiting in process until it recovers: %v", (*store).Name(), err)
+		}
+	}
+
+	limiter := local()
+	now := time.Now()
+	allowed := limiter.AllowN(now, 1)
+	return TokenBucket{
+		Allowed:   allowed,
+		Tokens:    limiter.TokensAt(now),
+		Burst:     limiter.Burst(),
+		PerSecond: float64(limiter.Limit()),
+	}
+}
+
+// redisRateLimitPrefix namespaces the token buckets in a shared Redis
+const redisRateLimitPrefix = "nomdb:ratelimit:"
+
+// takeTokenScript refills a bucket for the time since it was last used and takes a
+// token if there is one, atomically. Redis' clock is used so that instances with
+// skewed clocks agree. Buckets expire once they would be full again.
+var takeTokenScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+
+local bucket = redis.call('HMGET', KEYS[1], 'tokens', 'at')
+local tokens = tonumber(bucket[1]) or burst
+local at = tonumber(bucket[2]) or now
+tokens = math.min(burst, tokens + math.max(0, now - at) * rate)
+
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'at', now)
+redis.call('PEXPIRE', KEYS[1], math.ceil((burst - tokens) / rate) + 1000)
+return {allowed, tostring(tokens)}
+`)
+
+// RedisRateLimitStore keeps token buckets in Redis
+type RedisRateLimitStore struct {
+	client *redis.Client
+}
+
+// NewRedisRateLimitStore creates a store keeping token buckets in the Redis of client
+func NewRedisRateLimitStore(client *redis.Client) *RedisRateLimitStore {
+	return &RedisRateLimitStore{client: client}
+}
+
+func (s *RedisRateLimitStore) Name() string { return RateLimitStoreRedis }
+
+func (s *RedisRateLimitStore) Take(ctx context.Context, key string, perMinute, burst int) (TokenBucket, error) {
+	perMillisecond := float64(perMinute) / float64(time.Minute/time.Millisecond)
+	result, err := takeTokenScript.Run(ctx, s.client, []string{redisRateLimitPrefix + key},
+		strconv.FormatFloat(perMillisecond, 'g', -1, 64), burst).Slice()
+	if err != nil {
+		return TokenBucket{}, err
+	}
+	if len(result) != 2 {
+		return TokenBucket{}, fmt.Errorf("unexpected script result %v", result)
+	}
+	allowed, _ := result[0].(int64)
+	tokensValue, _ := result[1].(string)
+	tokens, err := strconv.ParseFloat(tokensValue, 64)
+	if err != nil {
+		return TokenBucket{}, fmt.Errorf("unexpected token count %v", result[1])
+	}
+	return TokenBucket{
+		Allowed:   allowed == 1,
+		Tokens:    tokens,
+		Burst:     burst,
+		PerSecond: float64(perMinute) / 60,
+	}, nil
+}
@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func newRedisRateLimitStore(t *testing.T) (*RedisRateLimitStore, *miniredis.Miniredis) {
+	t.Helper()
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return NewRedisRateLimitStore(client), server
+}
+
+func TestRedisRateLimitStore(t *testing.T) {
+	store, server := newRedisRateLimitStore(t)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		bucket, err := store.Take(ctx, "ip:auth:192.0.2.1", 60, 3)
+		if err != nil {
+			t.Fatalf("Take failed: %v", err)
+		}
+		if !bucket.Allowed {
+			t.Fatalf("Expected request %d within the burst to be allowed", i+1)
+		}
+	}
+	bucket, err := store.Take(ctx, "ip:auth:192.0.2.1", 60, 3)
+	if err != nil {
+		t.Fatalf("Take failed: %v", err)
+	}
+	if bucket.Allowed || bucket.Tokens >= 1 || bucket.Burst != 3 || bucket.PerSecond != 1 {
+		t.Errorf("Expected an empty bucket after the burst, got %+v", bucket)
+	}
+
+	// Other keys have their own buckets
+	if bucket, _ := store.Take(ctx, "ip:auth:192.0.2.2", 60, 3); !bucket.Allowed {
+		t.Error("Expected another IP to be allowed")
+	}
+	if ttl := server.TTL(redisRateLimitPrefix + "ip:auth:192.0.2.1"); ttl <= 0 {
+		t.Errorf("Expected buckets to expire, got TTL %v", ttl)
+	}
+}
+
+func TestRateLimitMiddleware_SharedStore(t *testing.T) {
+	store, server := newRedisRateLimitStore(t)
+	SetRateLimitStore(store)
+	defer SetRateLimitStore(nil)
+
+	// Two instances share the budget of a client: a burst of 1 for 5 logins a minute
+	instances := []http.Handler{
+		RateLimitMiddleware(NewRouteRateLimiter(RateLimits{Auth: 5}))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})),
+		RateLimitMiddleware(NewRouteRateLimiter(RateLimits{Auth: 5}))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})),
+	}
+	login := func(instance http.Handler) int {
+		rec := httptest.NewRecorder()
+		instance.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/auth/login", nil))
+		return rec.Code
+	}
+	if code := login(instances[0]); code != http.StatusOK {
+		t.Fatalf("Expected the first login to pass, got %d", code)
+	}
+	if code := login(instances[1]); code != http.StatusTooManyRequests {
+		t.Fatalf("Expected the other instance to apply the shared limit, got %d", code)
+	}
+
+	// Without Redis, instances fall back to their own buckets
+	server.Close()
+	if code := login(instances[1]); code != http.StatusOK {
+		t.Errorf("Expected the in-process limit when Redis is down, got %d", code)
+	}
+	if !rateLimitStoreDown.Load() {
+		t.Error("Expected the outage to be recorded")
+	}
+
+	// Once Redis is back, the budget is shared again
+	if err := server.Restart(); err != nil {
+		t.Fatalf("Failed to restart Redis: %v", err)
+	}
+	login(instances[0])
+	if rateLimitStoreDown.Load() {
+		t.Error("Expected the recovery to be recorded")
+	}
+}
@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRateLimitGroup(t *testing.T) {
+	tests := []struct {
+		method, path, expected string
+	}{
+		{http.MethodGet, "/api/restaurants", RateLimitGroupRead},
+		{http.MethodGet, "/api/auth/providers", RateLimitGroupRead},
+		{http.MethodPost, "/api/auth/login", RateLimitGroupAuth},
+		{http.MethodPost, "/api/restaurants/1/photos", RateLimitGroupUpload},
+		{http.MethodPost, "/api/restaurants/1/photos/presign", RateLimitGroupUpload},
+		{http.MethodGet, "/api/photos/1/image", RateLimitGroupUpload},
+		{http.MethodGet, "/api/photos/1", RateLimitGroupRead},
+		{http.MethodPost, "/api/ratings", RateLimitGroupWrite},
+		{http.MethodPut, "/api/restaurants/1", RateLimitGroupWrite},
+	}
+	for _, tt := range tests {
+		if got := rateLimitGroup(httptest.NewRequest(tt.method, tt.path, nil)); got != tt.expected {
+			t.Errorf("%s %s: expected group %s, got %s", tt.method, tt.path, tt.expected, got)
+		}
+	}
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	limiter := NewRouteRateLimiter(RateLimits{Auth: 5, Read: 60, Write: 0})
+	handler := RateLimitMiddleware(limiter)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	// Auth allows a burst of 1 (a fifth of 5 per minute)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/auth/login", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected the first login to pass, got %d", rec.Code)
+	}
+	if rec.Header().Get("X-RateLimit-Limit") != "5" || rec.Header().Get("X-RateLimit-Remaining") != "0" {
+		t.Errorf("Expected rate limit headers, got limit %q, remaining %q",
+			rec.Header().Get("X-RateLimit-Limit"), rec.Header().Get("X-RateLimit-Remaining"))
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/auth/login", nil))
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("Expected the second login to be rate limited, got %d", rec.Code)
+	}
+	if retryAfter := rec.Header().Get("Retry-After"); retryAfter == "" || retryAfter == "0" {
+		t.Errorf("Expected a Retry-After header, got %q", retryAfter)
+	}
+
+	// Reads have their own budget
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/restaurants", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected reads not to be limited by logins, got %d", rec.Code)
+	}
+
+	// Frontend files are not limited
+	for i := 0; i < 5; i++ {
+		rec = httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/assets/index.js", nil))
+		if rec.Code != http.StatusOK || rec.Header().Get("X-RateLimit-Limit") != "" {
+			t.Fatalf("Expected frontend files not to be rate limited, got %d", rec.Code)
+		}
+	}
+
+	// 0 disables the limit of a group
+	for i := 0; i < 50; i++ {
+		rec = httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/ratings", nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected unlimited writes, got %d on request %d", rec.Code, i+1)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nomdb/backend/internal/errors"
+	"github.com/nomdb/backend/internal/logger"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// panicMessage is the detail of responses to requests that panicked
+const panicMessage = "An unexpected error occurred. Please mention the request ID when reporting it."
+
+// fingerprintFrames is the number of stack frames below the panic that identify it
+const fingerprintFrames = 5
+
+var httpPanics = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "nomdb",
+	Name:      "http_panics_total",
+	Help:      "Panics recovered while serving HTTP requests by fingerprint.",
+}, []string{"fingerprint"})
+
+func init() {
+	MetricsRegistry.MustRegister(httpPanics)
+}
+
+// PanicReport describes a panic recovered while serving a request
+type PanicReport struct {
+	Fingerprint string // The same for panics of the same type at the same place
+	Message     string
+	Stack       string
+	Method      string
+	Path        string
+	RequestID   string
+	Occurrences int // Since the last alert for the fingerprint, including this one
+	Time        time.Time
+}
+
+// panicAlerter throttles alerts to one per fingerprint and interval
+type panicAlerter struct {
+	alert    func(PanicReport)
+	interval time.Duration
+
+	mu          sync.Mutex
+	lastAlerted map[string]time.Time
+	occurrences map[string]int // Since the last alert
+}
+
+var panicAlerts atomic.Pointer[panicAlerter]
+
+// SetPanicAlerter makes RecoveryMiddleware call alert, in a goroutine, for recovered
+// panics. Panics with the same fingerprint are alerted at most once per interval; the
+// next alert tells how often they occurred in between.
+func SetPanicAlerter(alert func(PanicReport), interval time.Duration) {
+	panicAlerts.Store(&panicAlerter{
+		alert:       alert,
+		interval:    interval,
+		lastAlerted: make(map[string]time.Time),
+		occurrences: make(map[string]int),
+	})
+}
+
+// report alerts a panic unless its fingerprint was alerted within the interval
+func (a *panicAlerter) report(p PanicReport) {
+	a.mu.Lock()
+	a.occurrences[p.Fingerprint]++
+	if last, ok := a.lastAlerted[p.Fingerprint]; ok && p.Time.Sub(last) < a.interval {
+		a.mu.Unlock()
+		return
+	}
+	p.Occurrences = a.occurrences[p.Fingerprint]
+	a.lastAlerted[p.Fingerprint] = p.Time
+	delete(a.occurrences, p.Fingerprint)
+	a.mu.Unlock()
+
+	go a.alert(p)
+}
+
+// panicFingerprint identifies a panic by the type of its value and the functions it
+// passed through, leaving out messages and line numbers, which vary for the same bug.
+// pcs is the stack of the deferred function that recovered the panic.
+func panicFingerprint(value any, pcs []uintptr) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%T", value)
+
+	frames := runtime.CallersFrames(pcs)
+	panicked := false
+	for n := 0; n < fingerprintFrames; {
+		frame, more := frames.Next()
+		if panicked && !strings.HasPrefix(frame.Function, "runtime.") {
+			fmt.Fprintf(h, "\n%s", frame.Function)
+			n++
+		}
+		// Frames up to runtime.gopanic belong to the recovery
+		panicked = panicked || frame.Function == "runtime.gopanic"
+		if !more {
+			break
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))[:12]
+}
+
+// RecoveryMiddleware recovers from panics, answering with a 500 error that carries the
+// request ID for users to report. Panics are logged with their stack trace, counted by
+// fingerprint in the metrics and alerted if SetPanicAlerter was called.
+func RecoveryMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			value := recover()
+			if value == nil {
+				return
+			}
+			// net/http aborts responses quietly with this panic
+			if value == http.ErrAbortHandler {
+				panic(value)
+			}
+
+			pcs := make([]uintptr, 64)
+			report := PanicReport{
+				Fingerprint: panicFingerprint(value, pcs[:runtime.Callers(1, pcs)]),
+				Message:     fmt.Sprint(value),
+				Stack:       string(debug.Stack()),
+				Method:      r.Method,
+				Path:        r.URL.Path,
+				RequestID:   w.Header().Get("X-Request-ID"),
+				Time:        time.Now(),
+			}
+			// Panics before the request ID middleware still get an ID to report
+			if report.RequestID == "" {
+				report.RequestID = uuid.New().String()
+				w.Header().Set("X-Request-ID", report.RequestID)
+			}
+
+			httpPanics.WithLabelValues(report.Fingerprint).Inc()
+			logger.ErrorWithFields(fmt.Sprintf("💥 PANIC recovered: %s\nStack trace:\n%s", report.Message, report.Stack), map[string]interface{}{
+				"fingerprint": report.Fingerprint,
+				"request_id":  report.RequestID,
+				"method":      report.Method,
+				"path":        report.Path,
+			})
+			if alerter := panicAlerts.Load(); alerter != nil {
+				alerter.report(report)
+			}
+
+			err := errors.InternalError("panic " + report.Fingerprint)
+			err.Detail = panicMessage
+			errors.RespondWithError(w, err)
+		}()
+
+		next.ServeHTTP(w, r)
+	})
+}
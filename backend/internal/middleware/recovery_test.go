@@ -0,0 +1,131 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/nomdb/backend/internal/errors"
+)
+
+func panicking(value any) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(value)
+	})
+}
+
+func nilMapWrite(w http.ResponseWriter, r *http.Request) {
+	var m map[string]int
+	m["boom"]++
+}
+
+func recoverPanic(t *testing.T, handler http.Handler) (*httptest.ResponseRecorder, errors.ErrorResponse) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	RecoveryMiddleware(RequestIDMiddleware(handler)).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/restaurants/1", nil))
+
+	var body errors.ErrorResponse
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("Failed to decode error response: %v", err)
+	}
+	return rec, body
+}
+
+func TestRecoveryMiddleware(t *testing.T) {
+	rec, body := recoverPanic(t, panicking("boom"))
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("Expected status 500, got %d", rec.Code)
+	}
+	if body.Detail != panicMessage || body.Code != errors.CodeInternalError {
+		t.Errorf("Unexpected error response %+v", body)
+	}
+	if body.RequestID == "" || body.RequestID != rec.Header().Get("X-Request-ID") {
+		t.Errorf("Expected the request ID %q in the response, got %q", rec.Header().Get("X-Request-ID"), body.RequestID)
+	}
+}
+
+func TestRecoveryMiddleware_RequestIDWithoutMiddleware(t *testing.T) {
+	rec := httptest.NewRecorder()
+	RecoveryMiddleware(panicking("boom")).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if rec.Header().Get("X-Request-ID") == "" {
+		t.Error("Expected a request ID for panics before the request ID middleware")
+	}
+}
+
+func TestRecoveryMiddleware_AbortHandler(t *testing.T) {
+	defer func() {
+		if recover() != http.ErrAbortHandler {
+			t.Error("Expected http.ErrAbortHandler to be re-panicked")
+		}
+	}()
+	RecoveryMiddleware(panicking(http.ErrAbortHandler)).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+}
+
+func TestRecoveryMiddleware_Alerts(t *testing.T) {
+	alerts := make(chan PanicReport, 10)
+	SetPanicAlerter(func(p PanicReport) { alerts <- p }, time.Hour)
+	defer panicAlerts.Store(nil)
+
+	_, body := recoverPanic(t, http.HandlerFunc(nilMapWrite))
+	select {
+	case alert := <-alerts:
+		if alert.Fingerprint == "" || alert.Occurrences != 1 || alert.RequestID != body.RequestID || alert.Path != "/api/restaurants/1" {
+			t.Errorf("Unexpected alert %+v", alert)
+		}
+		if alert.Message != "assignment to entry in nil map" || alert.Stack == "" {
+			t.Errorf("Expected the panic message and stack, got %q", alert.Message)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected an alert")
+	}
+
+	// The same panic is not alerted again within the interval, but counted
+	recoverPanic(t, http.HandlerFunc(nilMapWrite))
+	recoverPanic(t, http.HandlerFunc(nilMapWrite))
+	select {
+	case alert := <-alerts:
+		t.Fatalf("Expected repeated panics not to be alerted, got %+v", alert)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	alerter := panicAlerts.Load()
+	alerter.mu.Lock()
+	for fingerprint := range alerter.lastAlerted {
+		alerter.lastAlerted[fingerprint] = time.Now().Add(-2 * time.Hour)
+	}
+	alerter.mu.Unlock()
+	recoverPanic(t, http.HandlerFunc(nilMapWrite))
+	select {
+	case alert := <-alerts:
+		if alert.Occurrences != 3 {
+			t.Errorf("Expected 3 occurrences since the last alert, got %d", alert.Occurrences)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected an alert after the interval")
+	}
+}
+
+func TestPanicFingerprint(t *testing.T) {
+	fingerprint := func(handler http.Handler) string {
+		alerts := make(chan PanicReport, 1)
+		SetPanicAlerter(func(p PanicReport) { alerts <- p }, 0)
+		defer panicAlerts.Store(nil)
+		recoverPanic(t, handler)
+		return (<-alerts).Fingerprint
+	}
+
+	first := fingerprint(http.HandlerFunc(nilMapWrite))
+	if second := fingerprint(http.HandlerFunc(nilMapWrite)); second != first {
+		t.Errorf("Expected the same panic to have the same fingerprint, got %s and %s", first, second)
+	}
+	if other := fingerprint(panicking("boom")); other == first {
+		t.Errorf("Expected panics elsewhere to have another fingerprint, got %s", other)
+	}
+	if len(first) != 12 {
+		t.Errorf("Expected a 12 character fingerprint, got %q", first)
+	}
+}
@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// unmatchedRoute labels requests that matched no route, so unknown paths cannot blow up
+// the number of time series or metric entries
+const unmatchedRoute = "unmatched"
+
+type routeContextKey struct{}
+
+// RouteMiddleware resolves the route template of router (e.g. /api/restaurants/{id})
+// once per request and stores it in the request context for the metrics, tracing and
+// logging middleware, which run outside the router and cannot see mux's current route
+func RouteMiddleware(router *mux.Router) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := context.WithValue(r.Context(), routeContextKey{}, routeTemplate(router, r))
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// RouteFromContext returns the route template stored by RouteMiddleware, or
+// unmatchedRoute if it did not run
+func RouteFromContext(ctx context.Context) string {
+	if route, ok := ctx.Value(routeContextKey{}).(string); ok {
+		return route
+	}
+	return unmatchedRoute
+}
+
+// requestRoute returns the route template of r, resolving it with router when
+// RouteMiddleware did not run
+func requestRoute(router *mux.Router, r *http.Request) string {
+	if route, ok := r.Context().Value(routeContextKey{}).(string); ok {
+		return route
+	}
+	return routeTemplate(router, r)
+}
+
+// routeTemplate returns the path template of the route r matches
+func routeTemplate(router *mux.Router, r *http.Request) string {
+	var match mux.RouteMatch
+	if !router.Match(r, &match) || match.Route == nil {
+		return unmatchedRoute
+	}
+	template, err := match.Route.GetPathTemplate()
+	if err != nil {
+		return unmatchedRoute
+	}
+	return template
+}
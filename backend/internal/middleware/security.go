@@ -1,46 +1,121 @@
 package middleware
 
 import (
+	"fmt"
+	"net"
 	"net/http"
-	"runtime/debug"
 	"strings"
+	"time"
 
 	"github.com/nomdb/backend/internal/errors"
-	"github.com/nomdb/backend/internal/logger"
 )
 
+// SecurityHeaders configures the headers set by SecurityHeadersMiddleware. Empty
+// values omit the header.
+type SecurityHeaders struct {
+	ContentSecurityPolicy     string
+	DocsContentSecurityPolicy string        // For the Swagger UI under /api/docs/
+	HSTSMaxAge                time.Duration // 0 disables Strict-Transport-Security
+	HSTSIncludeSubdomains     bool
+	HSTSPreload               bool
+	FrameOptions              string // DENY or SAMEORIGIN
+	PermissionsPolicy         string
+
+	// For pages of the embedded frontend, outside of /api/; empty when the frontend is
+	// not served, leaving those paths to the API policies
+	FrontendContentSecurityPolicy string
+	FrontendPermissionsPolicy     string
+}
+
 // SecurityHeadersMiddleware adds security headers to all responses
-func SecurityHeadersMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Prevent clickjacking attacks
-		w.Header().Set("X-Frame-Options", "DENY")
+func SecurityHeadersMiddleware(cfg SecurityHeaders) func(http.Handler) http.Handler {
+	hsts := ""
+	if cfg.HSTSMaxAge > 0 {
+		hsts = fmt.Sprintf("max-age=%d", int64(cfg.HSTSMaxAge.Seconds()))
+		if cfg.HSTSIncludeSubdomains {
+			hsts += "; includeSubDomains"
+		}
+		if cfg.HSTSPreload {
+			hsts += "; preload"
+		}
+	}
 
-		// Prevent MIME type sniffing
-		w.Header().Set("X-Content-Type-Options", "nosniff")
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			setHeader := func(name, value string) {
+				if value != "" {
+					w.Header().Set(name, value)
+				}
+			}
 
-		// Enable XSS protection (legacy but still useful)
-		w.Header().Set("X-XSS-Protection", "1; mode=block")
+			// Prevent clickjacking attacks
+			setHeader("X-Frame-Options", cfg.FrameOptions)
 
-		// Referrer policy
-		w.Header().Set("Referrer-Policy", "strict-origin-when-cross-origin")
+			// Prevent MIME type sniffing
+			w.Header().Set("X-Content-Type-Options", "nosniff")
 
-		// Content Security Policy (adjust based on your needs)
-		// This is a basic policy - tighten for production
-		csp := []string{
-			"default-src 'self'",
-			"img-src 'self' data: https:",
-			"script-src 'self' 'unsafe-inline' 'unsafe-eval'", // Adjust based on your frontend needs
-			"style-src 'self' 'unsafe-inline'",
-		}
-		w.Header().Set("Content-Security-Policy", strings.Join(csp, "; "))
+			// Enable XSS protection (legacy but still useful)
+			w.Header().Set("X-XSS-Protection", "1; mode=block")
 
-		// Strict Transport Security (HSTS) - only enable if using HTTPS
-		// w.Header().Set("Strict-Transport-Security", "max-age=31536000; includeSubDomains")
+			// Referrer policy
+			w.Header().Set("Referrer-Policy", "strict-origin-when-cross-origin")
 
-		// Permissions Policy (formerly Feature-Policy)
-		w.Header().Set("Permissions-Policy", "geolocation=(), microphone=(), camera=()")
+			frontendPage := cfg.FrontendContentSecurityPolicy != "" && !strings.HasPrefix(r.URL.Path, "/api/")
+			switch {
+			case strings.HasPrefix(r.URL.Path, "/api/docs/"):
+				setHeader("Content-Security-Policy", cfg.DocsContentSecurityPolicy)
+			case frontendPage:
+				setHeader("Content-Security-Policy", cfg.FrontendContentSecurityPolicy)
+			default:
+				setHeader("Content-Security-Policy", cfg.ContentSecurityPolicy)
+			}
 
-		next.ServeHTTP(w, r)
+			// Browsers ignore HSTS received over plain HTTP, and it must not be sent there
+			if isHTTPS(r) {
+				setHeader("Strict-Transport-Security", hsts)
+			}
+
+			// Permissions Policy (formerly Feature-Policy)
+			if frontendPage {
+				setHeader("Permissions-Policy", cfg.FrontendPermissionsPolicy)
+			} else {
+				setHeader("Permissions-Policy", cfg.PermissionsPolicy)
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// isHTTPS reports whether the client connected with TLS, directly or to a trusted
+// proxy that sets X-Forwarded-Proto
+func isHTTPS(r *http.Request) bool {
+	if r.TLS != nil {
+		return true
+	}
+	remote, ok := parseIP(r.RemoteAddr)
+	return ok && isTrustedProxy(remote) && strings.EqualFold(r.Header.Get("X-Forwarded-Proto"), "https")
+}
+
+// HTTPSRedirectHandler redirects plain HTTP requests to the same URL over HTTPS on
+// httpsPort, which is left out of the URL if it is 443
+func HTTPSRedirectHandler(httpsPort string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		host = strings.Trim(host, "[]")
+		if host == "" {
+			errors.Error(w, "Host header is required", http.StatusBadRequest)
+			return
+		}
+		if httpsPort != "443" {
+			host = net.JoinHostPort(host, httpsPort)
+		} else if strings.Contains(host, ":") {
+			host = "[" + host + "]"
+		}
+		http.Redirect(w, r, "https://"+host+r.URL.RequestURI(), http.StatusPermanentRedirect)
 	})
 }
 
@@ -69,7 +144,7 @@ func ValidateContentTypeMiddleware(next http.Handler) http.Handler {
 		if strings.Contains(r.URL.Path, "/photos") && r.Method == "POST" {
 			contentType := r.Header.Get("Content-Type")
 			if !strings.HasPrefix(contentType, "multipart/form-data") {
-				http.Error(w, "Invalid Content-Type for photo upload", http.StatusUnsupportedMediaType)
+				errors.Error(w, "Invalid Content-Type for photo upload", http.StatusUnsupportedMediaType)
 				return
 			}
 			next.ServeHTTP(w, r)
@@ -80,7 +155,7 @@ func ValidateContentTypeMiddleware(next http.Handler) http.Handler {
 		if r.Method == "POST" || r.Method == "PUT" || r.Method == "PATCH" {
 			contentType := r.Header.Get("Content-Type")
 			if contentType != "" && !strings.Contains(contentType, "application/json") {
-				http.Error(w, "Content-Type must be application/json", http.StatusUnsupportedMediaType)
+				errors.Error(w, "Content-Type must be application/json", http.StatusUnsupportedMediaType)
 				return
 			}
 		}
@@ -105,20 +180,3 @@ func SanitizeInputMiddleware(next http.Handler) http.Handler {
 		next.ServeHTTP(w, r)
 	})
 }
-
-// RecoveryMiddleware recovers from panics and returns a 500 error
-func RecoveryMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		defer func() {
-			if err := recover(); err != nil {
-				// Log the panic with stack trace
-				logger.Error("PANIC recovered: %v\nStack trace:\n%s", err, string(debug.Stack()))
-
-				// Return structured error response
-				errors.RespondWithError(w, errors.InternalError("An unexpected error occurred"))
-			}
-		}()
-
-		next.ServeHTTP(w, r)
-	})
-}
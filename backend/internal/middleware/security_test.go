@@ -0,0 +1,126 @@
+package middleware
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+)
+
+func TestSecurityHeadersMiddleware(t *testing.T) {
+	handler := SecurityHeadersMiddleware(SecurityHeaders{
+		ContentSecurityPolicy:     "default-src 'none'",
+		DocsContentSecurityPolicy: "default-src 'self'",
+		HSTSMaxAge:                365 * 24 * time.Hour,
+		HSTSIncludeSubdomains:     true,
+		FrameOptions:              "DENY",
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	serve := func(req *http.Request) http.Header {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Header()
+	}
+
+	header := serve(httptest.NewRequest(http.MethodGet, "/api/restaurants", nil))
+	if csp := header.Get("Content-Security-Policy"); csp != "default-src 'none'" {
+		t.Errorf("Expected the API CSP, got %q", csp)
+	}
+	if header.Get("X-Frame-Options") != "DENY" {
+		t.Errorf("Expected X-Frame-Options DENY, got %q", header.Get("X-Frame-Options"))
+	}
+	if header.Get("Strict-Transport-Security") != "" {
+		t.Error("Expected no HSTS over plain HTTP")
+	}
+	if _, ok := header["Permissions-Policy"]; ok {
+		t.Error("Expected an empty Permissions-Policy to be omitted")
+	}
+
+	header = serve(httptest.NewRequest(http.MethodGet, "/api/docs/index.html", nil))
+	if csp := header.Get("Content-Security-Policy"); csp != "default-src 'self'" {
+		t.Errorf("Expected the docs CSP, got %q", csp)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/restaurants", nil)
+	req.TLS = &tls.ConnectionState{}
+	if hsts := serve(req).Get("Strict-Transport-Security"); hsts != "max-age=31536000; includeSubDomains" {
+		t.Errorf("Expected HSTS over HTTPS, got %q", hsts)
+	}
+}
+
+func TestSecurityHeadersMiddleware_Frontend(t *testing.T) {
+	handler := SecurityHeadersMiddleware(SecurityHeaders{
+		ContentSecurityPolicy:         "default-src 'none'",
+		PermissionsPolicy:             "geolocation=()",
+		FrontendContentSecurityPolicy: "default-src 'self'",
+		FrontendPermissionsPolicy:     "geolocation=(self)",
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	tests := []struct {
+		path, csp, permissions string
+	}{
+		{"/", "default-src 'self'", "geolocation=(self)"},
+		{"/restaurants/12", "default-src 'self'", "geolocation=(self)"},
+		{"/api/restaurants", "default-src 'none'", "geolocation=()"},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+		if csp := rec.Header().Get("Content-Security-Policy"); csp != tt.csp {
+			t.Errorf("%s: expected CSP %q, got %q", tt.path, tt.csp, csp)
+		}
+		if permissions := rec.Header().Get("Permissions-Policy"); permissions != tt.permissions {
+			t.Errorf("%s: expected Permissions-Policy %q, got %q", tt.path, tt.permissions, permissions)
+		}
+	}
+}
+
+func TestIsHTTPS_ForwardedProto(t *testing.T) {
+	defer InitTrustedProxies(nil)
+	InitTrustedProxies([]netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")})
+
+	tests := []struct {
+		remoteAddr string
+		proto      string
+		expected   bool
+	}{
+		{"10.0.0.2:1234", "https", true},
+		{"10.0.0.2:1234", "http", false},
+		{"203.0.113.5:1234", "https", false}, // Untrusted clients cannot claim HTTPS
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/api/restaurants", nil)
+		req.RemoteAddr = tt.remoteAddr
+		req.Header.Set("X-Forwarded-Proto", tt.proto)
+		if got := isHTTPS(req); got != tt.expected {
+			t.Errorf("isHTTPS(%s, %s) = %v, want %v", tt.remoteAddr, tt.proto, got, tt.expected)
+		}
+	}
+}
+
+func TestHTTPSRedirectHandler(t *testing.T) {
+	tests := []struct {
+		port     string
+		host     string
+		expected string
+	}{
+		{"443", "nomdb.example.com", "https://nomdb.example.com/api/restaurants?page=2"},
+		{"443", "nomdb.example.com:80", "https://nomdb.example.com/api/restaurants?page=2"},
+		{"8443", "nomdb.example.com:8080", "https://nomdb.example.com:8443/api/restaurants?page=2"},
+		{"443", "[2001:db8::1]:80", "https://[2001:db8::1]/api/restaurants?page=2"},
+		{"8443", "[2001:db8::1]", "https://[2001:db8::1]:8443/api/restaurants?page=2"},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodPost, "/api/restaurants?page=2", nil)
+		req.Host = tt.host
+		rec := httptest.NewRecorder()
+		HTTPSRedirectHandler(tt.port).ServeHTTP(rec, req)
+		if rec.Code != http.StatusPermanentRedirect || rec.Header().Get("Location") != tt.expected {
+			t.Errorf("Redirect of %s to port %s = %d %s, want %s", tt.host, tt.port, rec.Code, rec.Header().Get("Location"), tt.expected)
+		}
+	}
+}
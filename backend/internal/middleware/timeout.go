@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/nomdb/backend/internal/logger"
+)
+
+// TimeoutMiddleware cancels the context of requests after timeout, aborting their
+// database queries and outgoing calls. Streaming responses are exempt; they lift the
+// server's write timeout themselves with DisableWriteTimeout.
+func TimeoutMiddleware(timeout time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if timeout <= 0 || isStreamingRequest(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(r.Context(), timeout)
+			defer cancel()
+			next.ServeHTTP(w, r.WithContext(ctx))
+
+			if ctx.Err() == context.DeadlineExceeded {
+				logger.Warn("⏱️  %s %s exceeded the request timeout of %s", r.Method, r.URL.Path, timeout)
+			}
+		})
+	}
+}
+
+// isStreamingRequest reports whether r is answered with a long-lived stream: photo
+// event streams, photo archives, and CPU profiles and execution traces, which take as
+// long as the client asks
+func isStreamingRequest(r *http.Request) bool {
+	return strings.HasSuffix(r.URL.Path, "/photos/events") ||
+		strings.HasSuffix(r.URL.Path, "/photos/archive") ||
+		r.URL.Path == "/api/debug/pprof/profile" ||
+		r.URL.Path == "/api/debug/pprof/trace"
+}
+
+// DisableWriteTimeout lifts the http.Server write timeout for a long-lived response
+func DisableWriteTimeout(w http.ResponseWriter) {
+	if err := http.NewResponseController(w).SetWriteDeadline(time.Time{}); err != nil {
+		logger.Debug("Failed to disable write timeout: %v", err)
+	}
+}
@@ -20,6 +20,15 @@ type User struct {
 	UpdatedAt     time.Time  `json:"updated_at"`
 }
 
+// ProviderServiceAccount is the provider of service accounts: non-interactive users
+// without a password that authenticate with API keys only
+const ProviderServiceAccount = "service"
+
+// IsServiceAccount reports whether u is a service account
+func (u *User) IsServiceAccount() bool {
+	return u.Provider == ProviderServiceAccount
+}
+
 // Session represents a user session with refresh token
 type Session struct {
 	ID           int       `json:"id"`
@@ -37,13 +46,77 @@ type APIKey struct {
 	ID         int        `json:"id"`
 	UserID     *int       `json:"user_id"`
 	KeyHash    string     `json:"-"` // Never send to client
+	KeyPrefix  *string    `json:"key_prefix"`
 	Name       *string    `json:"name"`
+	Scopes     []string   `json:"scopes"`
+	RateLimit  *int       `json:"rate_limit"` // Requests per minute, nil for the server default
 	LastUsedAt *time.Time `json:"last_used_at"`
 	ExpiresAt  *time.Time `json:"expires_at"`
 	IsActive   bool       `json:"is_active"`
 	CreatedAt  time.Time  `json:"created_at"`
 }
 
+// Invite allows registering while REGISTRATION_MODE=invite
+type Invite struct {
+	ID              int        `json:"id"`
+	CodePrefix      string     `json:"code_prefix"`
+	Note            *string    `json:"note"`
+	MaxUses         int        `json:"max_uses"`
+	UseCount        int        `json:"use_count"`
+	ExpiresAt       *time.Time `json:"expires_at"`
+	CreatedByUserID *int       `json:"created_by_user_id"`
+	RevokedAt       *time.Time `json:"revoked_at"`
+	CreatedAt       time.Time  `json:"created_at"`
+	RedeemedBy      []string   `json:"redeemed_by"` // Usernames of the users who registered with the invite
+}
+
+type CreateInviteRequest struct {
+	Note          string `json:"note,omitempty"`
+	MaxUses       *int   `json:"max_uses,omitempty"`        // Default 1
+	ExpiresInDays *int   `json:"expires_in_days,omitempty"` // Default 7, 0 for no expiry
+}
+
+// CreateInviteResponse includes the invite code, which is only returned once
+type CreateInviteResponse struct {
+	Code   string `json:"code"`
+	URL    string `json:"url"`
+	Invite Invite `json:"invite"`
+}
+
+// ServiceAccount is a non-interactive user for automation such as import scripts and bots
+type ServiceAccount struct {
+	ID          int        `json:"id"`
+	Name        string     `json:"name"`
+	Description *string    `json:"description"`
+	IsAdmin     bool       `json:"is_admin"`
+	IsActive    bool       `json:"is_active"`
+	RateLimit   *int       `json:"rate_limit"` // Requests per minute across all keys, nil for the server default
+	APIKeyCount int        `json:"api_key_count"`
+	LastUsedAt  *time.Time `json:"last_used_at"` // Last use of any of its API keys
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+type CreateServiceAccountRequest struct {
+	Name        string `json:"name"` // Lowercase letters, digits, dashes and underscores
+	Description string `json:"description,omitempty"`
+	IsAdmin     bool   `json:"is_admin,omitempty"`
+	RateLimit   *int   `json:"rate_limit,omitempty"`
+}
+
+// AuditLogEntry is a security-sensitive event such as a login or an admin action
+type AuditLogEntry struct {
+	ID            int64          `json:"id"`
+	Event         string         `json:"event"`
+	ActorUserID   *int           `json:"actor_user_id"`
+	ActorUsername *string        `json:"actor_username"`
+	TargetType    *string        `json:"target_type"`
+	TargetID      *string        `json:"target_id"`
+	IPAddress     *string        `json:"ip_address"`
+	UserAgent     *string        `json:"user_agent"`
+	Details       map[string]any `json:"details"`
+	CreatedAt     time.Time      `json:"created_at"`
+}
+
 // Auth Request/Response types
 
 type RegisterRequest struct {
@@ -51,25 +124,95 @@ type RegisterRequest struct {
 	Username string `json:"username"`
 	Password string `json:"password"`
 	FullName string `json:"full_name,omitempty"`
+	// Required when REGISTRATION_MODE=invite
+	InviteCode string `json:"invite_code,omitempty"`
 }
 
 type LoginRequest struct {
-	Email    string `json:"email"`
-	Password string `json:"password"`
+	Email      string `json:"email"`
+	Password   string `json:"password"`
+	RememberMe bool   `json:"remember_me,omitempty"` // Keep the session for JWT_REMEMBER_ME_TTL instead of JWT_REFRESH_TOKEN_TTL
 }
 
 type LoginResponse struct {
 	AccessToken  string `json:"access_token"`
-	RefreshToken string `json:"refresh_token"`
+	RefreshToken string `json:"refresh_token,omitempty"` // Omitted when refreshed from the cookie
 	TokenType    string `json:"token_type"`
 	ExpiresIn    int    `json:"expires_in"` // seconds
-	User         User   `json:"user"`
+	// Seconds until the refresh token expires; omitted on refresh, which keeps the session's expiry
+	RefreshExpiresIn int  `json:"refresh_expires_in,omitempty"`
+	User             User `json:"user"`
 }
 
 type RefreshTokenRequest struct {
 	RefreshToken string `json:"refresh_token"`
 }
 
+type ForgotPasswordRequest struct {
+	Email string `json:"email"`
+}
+
+type ResetPasswordRequest struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
+
+type CreateAPIKeyRequest struct {
+	Name          string   `json:"name"`
+	Scopes        []string `json:"scopes"`                    // read, write, admin; defaults to read
+	ExpiresInDays *int     `json:"expires_in_days,omitempty"` // Never expires if omitted
+	RateLimit     *int     `json:"rate_limit,omitempty"`      // Requests per minute
+}
+
+type CreateAPIKeyResponse struct {
+	Key    string `json:"key"` // Only returned once
+	APIKey APIKey `json:"api_key"`
+}
+
+type DeleteAccountRequest struct {
+	Password string `json:"password,omitempty"` // Required for accounts with a password
+	Mode     string `json:"mode,omitempty"`     // anonymize (default) keeps contributions without the user, purge deletes them
+}
+
+type DeleteAccountResponse struct {
+	Mode        string    `json:"mode"`
+	ScheduledAt time.Time `json:"scheduled_at"` // When the account is permanently removed
+}
+
+// AccountExport is everything stored about a user, returned by the data export
+type AccountExport struct {
+	ExportedAt       time.Time        `json:"exported_at"`
+	User             User             `json:"user"`
+	Ratings          []map[string]any `json:"ratings"`
+	Photos           []map[string]any `json:"photos"`
+	Suggestions      []map[string]any `json:"suggestions"`
+	SuggestionEvents []map[string]any `json:"suggestion_events"`
+	Restaurants      []map[string]any `json:"restaurants"` // Restaurants created by the user
+	Sessions         []map[string]any `json:"sessions"`
+	APIKeys          []map[string]any `json:"api_keys"`
+	Following        []map[string]any `json:"following"` // Users the user follows
+	Badges           []map[string]any `json:"badges"`
+}
+
+// AuthProvider is an external login provider the frontend can render a button for
+type AuthProvider struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+	Type        string `json:"type"` // oidc, google, github
+	LoginURL    string `json:"login_url"`
+}
+
+type AuthProvidersResponse struct {
+	LocalLogin   bool           `json:"local_login"`  // Email/password login is enabled
+	Registration string         `json:"registration"` // open, invite or closed
+	Providers    []AuthProvider `json:"providers"`
+}
+
+// CSRFTokenResponse carries the token for the X-CSRF-Token header of cookie-authenticated requests
+type CSRFTokenResponse struct {
+	CSRFToken string `json:"csrf_token"`
+}
+
 type OAuthCallbackRequest struct {
 	Code  string `json:"code"`
 	State string `json:"state"`
@@ -79,3 +222,6 @@ type OAuthCallbackRequest struct {
 type ContextKey string
 
 const UserContextKey ContextKey = "user"
+
+// APIKeyContextKey holds the *APIKey of requests authenticated with an API key
+const APIKeyContextKey ContextKey = "api_key"
@@ -1,51 +1,80 @@
 package models
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 type Category struct {
-	ID        int       `json:"id"`
-	Name      string    `json:"name"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID              int       `json:"id"`
+	Name            string    `json:"name"`
+	ParentID        *int      `json:"parent_id"`                  // Null for top-level categories
+	Icon            *string   `json:"icon,omitempty"`             // An emoji or a short icon name
+	Color           *string   `json:"color,omitempty"`            // Hex color, e.g. #e4572e
+	RestaurantCount *int      `json:"restaurant_count,omitempty"` // Restaurants in the category, in category lists only
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// CategoryNode is a category with its subcategories
+type CategoryNode struct {
+	Category
+	Children []CategoryNode `json:"children"`
 }
 
 type FoodType struct {
-	ID        int       `json:"id"`
-	Name      string    `json:"name"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID              int       `json:"id"`
+	Name            string    `json:"name"`
+	Icon            *string   `json:"icon,omitempty"`             // An emoji or a short icon name
+	Color           *string   `json:"color,omitempty"`            // Hex color, e.g. #e4572e
+	RestaurantCount *int      `json:"restaurant_count,omitempty"` // Restaurants serving the food type, in food type lists only
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
 }
 
 type Restaurant struct {
-	ID            int        `json:"id"`
-	Name          string     `json:"name"`
-	Description   *string    `json:"description"`
-	Address       *string    `json:"address"`
-	Phone         *string    `json:"phone"`
-	Website       *string    `json:"website"`
-	Latitude      *float64   `json:"latitude"`
-	Longitude     *float64   `json:"longitude"`
-	GooglePlaceID *string    `json:"google_place_id"`
-	CategoryID    *int       `json:"category_id"`
-	Category      *Category  `json:"category,omitempty"`
-	FoodTypes     []FoodType `json:"food_types,omitempty"`
-	AvgRating     *AvgRating `json:"avg_rating,omitempty"`
-	Distance      *float64   `json:"distance,omitempty"` // Distance in km from search location
-	IsSuggestion  bool       `json:"is_suggestion"`      // Indicates if this is from suggestions table
-	SuggestionID  *int       `json:"suggestion_id,omitempty"`
-	Status        *string    `json:"status,omitempty"` // For suggestions: pending, approved, tested, rejected
-	CreatedAt     time.Time  `json:"created_at"`
-	UpdatedAt     time.Time  `json:"updated_at"`
+	ID              int              `json:"id"`
+	Name            string           `json:"name"`
+	Description     *string          `json:"description"`
+	Address         *string          `json:"address"`
+	Phone           *string          `json:"phone"`
+	Website         *string          `json:"website"`
+	Latitude        *float64         `json:"latitude"`
+	Longitude       *float64         `json:"longitude"`
+	GooglePlaceID   *string          `json:"google_place_id"`
+	BusinessStatus  *string          `json:"business_status,omitempty"` // From the place sync, e.g. CLOSED_PERMANENTLY
+	OpeningHours    []string         `json:"opening_hours,omitempty"`   // From the place sync, one line per day
+	CategoryID      *int             `json:"category_id"`
+	Category        *Category        `json:"category,omitempty"`
+	FoodTypes       []FoodType       `json:"food_types,omitempty"`
+	AvgRating       *AvgRating       `json:"avg_rating,omitempty"`
+	Distance        *float64         `json:"distance,omitempty"`       // Distance in km from search location
+	TravelMinutes   *float64         `json:"travel_minutes,omitempty"` // Travel time from the search location, when requested
+	IsSuggestion    bool             `json:"is_suggestion"`            // Indicates if this is from suggestions table
+	SuggestionID    *int             `json:"suggestion_id,omitempty"`
+	Status          *string          `json:"status,omitempty"` // For suggestions: pending, approved, tested, rejected
+	CoverPhoto      *MenuPhoto       `json:"cover_photo,omitempty"`
+	ExternalSources []ExternalSource `json:"external_sources,omitempty"` // Yelp or Foursquare data, on the restaurant detail
+	CreatedAt       time.Time        `json:"created_at"`
+	UpdatedAt       time.Time        `json:"updated_at"`
 }
 
 type Rating struct {
-	ID             int       `json:"id"`
-	RestaurantID   int       `json:"restaurant_id"`
-	FoodRating     int       `json:"food_rating"`
-	ServiceRating  int       `json:"service_rating"`
-	AmbianceRating int       `json:"ambiance_rating"`
-	Comment        *string   `json:"comment"`
-	CreatedAt      time.Time `json:"created_at"`
+	ID             int            `json:"id"`
+	RestaurantID   int            `json:"restaurant_id"`
+	FoodRating     int            `json:"food_rating"`
+	ServiceRating  int            `json:"service_rating"`
+	AmbianceRating int            `json:"ambiance_rating"`
+	Comment        *string        `json:"comment"`
+	UserID         *int           `json:"user_id"`        // Author, nil for ratings created before authentication
+	OwnerResponse  *OwnerResponse `json:"owner_response"` // Official response of the restaurant's owner, nil if none
+	CreatedAt      time.Time      `json:"created_at"`
+}
+
+// OwnerResponse is the official response of a restaurant's owner to a rating
+type OwnerResponse struct {
+	Response    string    `json:"response"`
+	RespondedAt time.Time `json:"responded_at"`
 }
 
 type AvgRating struct {
@@ -68,6 +97,8 @@ type CreateRestaurantRequest struct {
 	GooglePlaceID *string  `json:"google_place_id"`
 	CategoryID    *int     `json:"category_id"`
 	FoodTypeIDs   []int    `json:"food_type_ids"`
+	SpaceID       int      `json:"-"` // Set from the space of the request
+	SuggestedBy   *int     `json:"-"` // Author of the suggestion the restaurant was converted from
 }
 
 type UpdateRestaurantRequest struct {
@@ -92,10 +123,47 @@ type CreateRatingRequest struct {
 }
 
 type CreateCategoryRequest struct {
-	Name string `json:"name"`
+	Name     string  `json:"name"`
+	ParentID *int    `json:"parent_id,omitempty"` // 0 moves a category to the top level; omitted keeps the parent on update
+	Icon     *string `json:"icon,omitempty"`      // Empty removes the icon; omitted keeps it on update
+	Color    *string `json:"color,omitempty"`     // #rrggbb; empty removes the color, omitted keeps it on update
 }
 
 type CreateFoodTypeRequest struct {
+	Name  string  `json:"name"`
+	Icon  *string `json:"icon,omitempty"`  // Empty removes the icon; omitted keeps it on update
+	Color *string `json:"color,omitempty"` // #rrggbb; empty removes the color, omitted keeps it on update
+}
+
+// Meta is the reference data clients load at launch, in one response
+type Meta struct {
+	Categories       []Category        `json:"categories"`
+	FoodTypes        []FoodType        `json:"food_types"`
+	RatingDimensions []RatingDimension `json:"rating_dimensions"`
+	Badges           []Badge           `json:"badges"` // Badges users can be awarded
+}
+
+// RatingDimension is an aspect of restaurants that ratings score
+type RatingDimension struct {
+	Key  string `json:"key"`  // The rating field is <key>_rating
+	Name string `json:"name"` // In the language of the request
+	Min  int    `json:"min"`
+	Max  int    `json:"max"`
+}
+
+// MergeFoodTypesRequest lists the duplicates to merge into a food type
+type MergeFoodTypesRequest struct {
+	DuplicateIDs []int `json:"duplicate_ids"`
+}
+
+// Translation is the name of a category or food type in another language
+type Translation struct {
+	Locale    string    `json:"locale"`
+	Name      string    `json:"name"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type TranslationRequest struct {
 	Name string `json:"name"`
 }
 
@@ -112,6 +180,33 @@ type PaginatedResponse struct {
 	Total      *int        `json:"total,omitempty"`
 }
 
+// Map views
+type MapMarker struct {
+	ID         int      `json:"id"`
+	Name       string   `json:"name"`
+	Latitude   float64  `json:"latitude"`
+	Longitude  float64  `json:"longitude"`
+	CategoryID *int     `json:"category_id,omitempty"`
+	Rating     *float64 `json:"rating,omitempty"` // Overall average, omitted when unrated
+}
+
+// MapCluster aggregates the restaurants of a grid cell of the map
+type MapCluster struct {
+	Latitude     float64 `json:"latitude"`  // Centroid of the restaurants
+	Longitude    float64 `json:"longitude"` // Centroid of the restaurants
+	Count        int     `json:"count"`
+	RestaurantID *int    `json:"restaurant_id,omitempty"` // Set when the cell holds a single restaurant
+}
+
+// MapBoundsResponse holds markers, or clusters when there are too many restaurants in
+// the bounds to show individually
+type MapBoundsResponse struct {
+	Markers   []MapMarker  `json:"markers"`
+	Clusters  []MapCluster `json:"clusters"`
+	Total     int          `json:"total"`
+	Clustered bool         `json:"clustered"`
+}
+
 type GooglePlaceResult struct{
 	PlaceID   string  `json:"place_id"`
 	Name      string  `json:"name"`
@@ -120,6 +215,43 @@ type GooglePlaceResult struct{
 	Website   string  `json:"website,omitempty"`
 	Latitude  float64 `json:"latitude"`
 	Longitude float64 `json:"longitude"`
+
+	// Only filled in by Google place details
+	BusinessStatus string `json:"business_status,omitempty"` // OPERATIONAL, CLOSED_TEMPORARILY or CLOSED_PERMANENTLY
+
+	// Only filled in with the Places API (New), and opening hours by legacy place details
+	Rating       *float64            `json:"rating,omitempty"` // 1 to 5
+	RatingCount  *int                `json:"rating_count,omitempty"`
+	PriceLevel   *int                `json:"price_level,omitempty"` // 0 (free) to 4 (very expensive)
+	Photos       []GooglePlacePhoto  `json:"photos,omitempty"`
+	OpeningHours *GoogleOpeningHours `json:"opening_hours,omitempty"`
+}
+
+// RestaurantMap links to a map image of a restaurant and to directions to it
+type RestaurantMap struct {
+	ImageURL   string        `json:"image_url,omitempty"` // Static map served by the API; missing when Google Maps is not configured
+	Directions MapDirections `json:"directions"`
+}
+
+// MapDirections are deep links opening directions in map apps
+type MapDirections struct {
+	Google string `json:"google"`
+	Apple  string `json:"apple"`
+}
+
+// GooglePlacePhoto is a photo of a place; its URL is resolved through
+// /api/places/photo?name=, which keeps the API key on the server
+type GooglePlacePhoto struct {
+	Name         string   `json:"name"`
+	Width        int      `json:"width"`
+	Height       int      `json:"height"`
+	Attributions []string `json:"attributions,omitempty"` // Authors that must be credited when showing the photo
+}
+
+// GoogleOpeningHours are the regular opening hours of a place
+type GoogleOpeningHours struct {
+	OpenNow  *bool    `json:"open_now,omitempty"`
+	Weekdays []string `json:"weekdays"` // One line per day, e.g. "Monday: 11:00 AM – 10:00 PM"
 }
 
 // Restaurant Suggestion System
@@ -137,6 +269,7 @@ type RestaurantSuggestion struct {
 	FoodTypes           []FoodType `json:"food_types,omitempty"`
 	Notes               *string    `json:"notes"`
 	Status              string     `json:"status"`
+	UserID              *int       `json:"user_id"` // Author, nil for anonymous suggestions
 	CreatedAt           time.Time  `json:"created_at"`
 	UpdatedAt           time.Time  `json:"updated_at"`
 }
@@ -152,10 +285,12 @@ type CreateSuggestionRequest struct {
 	SuggestedCategoryID *int     `json:"suggested_category_id"`
 	FoodTypeIDs         []int    `json:"food_type_ids"`
 	Notes               *string  `json:"notes"`
+	SpaceID             int      `json:"-"` // Set from the space of the request
 }
 
 type UpdateSuggestionStatusRequest struct {
-	Status string `json:"status"`
+	Status string  `json:"status"`
+	Note   *string `json:"note,omitempty"`
 }
 
 type ConvertSuggestionRequest struct {
@@ -167,6 +302,349 @@ type ConvertSuggestionRequest struct {
 	Comment        *string `json:"comment"`
 }
 
+// Suggestion approvals (two-admin review workflow)
+type SuggestionApproval struct {
+	ID           int       `json:"id"`
+	SuggestionID int       `json:"suggestion_id"`
+	UserID       int       `json:"user_id"`
+	Username     string    `json:"username"`
+	Note         *string   `json:"note"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type SuggestionApprovalStatus struct {
+	SuggestionID      int                  `json:"suggestion_id"`
+	Mode              string               `json:"mode"`
+	RequiredApprovals int                  `json:"required_approvals"`
+	Approvals         []SuggestionApproval `json:"approvals"`
+	CanConvert        bool                 `json:"can_convert"`
+}
+
+type ApproveSuggestionRequest struct {
+	Note *string `json:"note"`
+}
+
+// SuggestionEvent is one entry of a suggestion's status history
+type SuggestionEvent struct {
+	ID           int       `json:"id"`
+	SuggestionID int       `json:"suggestion_id"`
+	UserID       *int      `json:"user_id"`
+	Username     *string   `json:"username"`
+	FromStatus   *string   `json:"from_status"`
+	ToStatus     string    `json:"to_status"`
+	Note         *string   `json:"note"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// PlaceChange is a difference between a restaurant and its place details found by the
+// place sync, applied once an admin accepts it
+type PlaceChange struct {
+	ID               int             `json:"id"`
+	RestaurantID     int             `json:"restaurant_id"`
+	RestaurantName   string          `json:"restaurant_name"`
+	Field            string          `json:"field"`                          // phone, website, business_status or opening_hours
+	OldValue         json.RawMessage `json:"old_value" swaggertype:"object"` // String, or array of strings for opening_hours; null when unset
+	NewValue         json.RawMessage `json:"new_value" swaggertype:"object"`
+	Status           string          `json:"status"` // pending, accepted or rejected
+	ReviewedByUserID *int            `json:"reviewed_by_user_id,omitempty"`
+	ReviewedAt       *time.Time      `json:"reviewed_at,omitempty"`
+	CreatedAt        time.Time       `json:"created_at"`
+}
+
+// PlaceSyncReport summarizes a place sync run
+type PlaceSyncReport struct {
+	Checked    int       `json:"checked"` // Restaurants whose place details were fetched
+	Changes    int       `json:"changes"` // Pending changes recorded
+	Failed     int       `json:"failed"`  // Restaurants whose place details could not be fetched
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+}
+
+// ReviewPlaceChangeRequest is an admin decision on a change found by the place sync
+type ReviewPlaceChangeRequest struct {
+	Action string `json:"action"` // accept, reject
+}
+
+// RestaurantClaim is a user's claim to own a restaurant's listing. Once an admin
+// approves it, the user may respond to ratings and update the restaurant's business
+// details.
+type RestaurantClaim struct {
+	ID               int        `json:"id"`
+	RestaurantID     int        `json:"restaurant_id"`
+	RestaurantName   string     `json:"restaurant_name"`
+	UserID           int        `json:"user_id"`
+	Username         string     `json:"username"`
+	Message          *string    `json:"message"`
+	Status           string     `json:"status"` // pending, approved or rejected
+	ReviewedByUserID *int       `json:"reviewed_by_user_id,omitempty"`
+	ReviewedAt       *time.Time `json:"reviewed_at,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+}
+
+// CreateRestaurantClaimRequest claims a restaurant for the user
+type CreateRestaurantClaimRequest struct {
+	Message *string `json:"message"` // For the admins, e.g. the user's role at the restaurant and how to verify it
+}
+
+// ReviewRestaurantClaimRequest is an admin decision on a restaurant claim
+type ReviewRestaurantClaimRequest struct {
+	Action string `json:"action"` // approve, reject
+}
+
+// OwnerResponseRequest sets the owner's response to a rating
+type OwnerResponseRequest struct {
+	Response string `json:"response"`
+}
+
+// UpdateRestaurantBusinessRequest is the update of a restaurant's business details by its
+// owner. Fields left out are kept; an empty phone or website clears it.
+type UpdateRestaurantBusinessRequest struct {
+	Phone        *string  `json:"phone"`
+	Website      *string  `json:"website"`
+	OpeningHours []string `json:"opening_hours"` // One line per day; an empty list clears them
+}
+
+// FollowedUser is a user the current user follows
+type FollowedUser struct {
+	ID         int       `json:"id"`
+	Username   string    `json:"username"`
+	AvatarURL  *string   `json:"avatar_url"`
+	FollowedAt time.Time `json:"followed_at"`
+}
+
+// ActivityItem is a rating or suggestion in the activity feed
+type ActivityItem struct {
+	Type         string    `json:"type"` // rating or suggestion
+	ID           int       `json:"id"`   // Of the rating or suggestion
+	UserID       int       `json:"user_id"`
+	Username     string    `json:"username"`
+	RestaurantID *int      `json:"restaurant_id"`    // The rated restaurant, nil for suggestions
+	Name         string    `json:"name"`             // Of the restaurant or suggested restaurant
+	Score        *float64  `json:"score,omitempty"`  // Average of the food, service and ambiance ratings
+	Comment      *string   `json:"comment"`          // The rating comment or suggestion notes
+	Status       *string   `json:"status,omitempty"` // Of suggestions
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// LeaderboardEntry is a user ranked by a count of contributions
+type LeaderboardEntry struct {
+	Rank      int     `json:"rank"`
+	UserID    int     `json:"user_id"`
+	Username  string  `json:"username"`
+	AvatarURL *string `json:"avatar_url"`
+	Count     int     `json:"count"`
+}
+
+// Leaderboard ranks the contributors of a space
+type Leaderboard struct {
+	Period               string             `json:"period"` // month or all
+	Since                *time.Time         `json:"since"`  // Start of the period, nil for all time
+	Ratings              []LeaderboardEntry `json:"ratings"`
+	ConvertedSuggestions []LeaderboardEntry `json:"converted_suggestions"` // Suggestions converted to restaurants
+	Photos               []LeaderboardEntry `json:"photos"`
+}
+
+// ContributionCounts counts the contributions of a user
+type ContributionCounts struct {
+	Ratings              int `json:"ratings"`
+	Suggestions          int `json:"suggestions"`           // Including converted ones
+	ConvertedSuggestions int `json:"converted_suggestions"` // Suggestions converted to restaurants
+	Photos               int `json:"photos"`
+}
+
+// UserStats are the contribution stats of a user
+type UserStats struct {
+	UserID      int                `json:"user_id"`
+	Username    string             `json:"username"`
+	AvatarURL   *string            `json:"avatar_url"`
+	MemberSince time.Time          `json:"member_since"`
+	Total       ContributionCounts `json:"total"`
+	ThisMonth   ContributionCounts `json:"this_month"` // Since the start of the month (UTC)
+	Badges      []UserBadge        `json:"badges"`     // Awarded for contributions in any space
+}
+
+// Badge is a milestone users are awarded for
+type Badge struct {
+	Key         string `json:"key"`
+	Name        string `json:"name"`        // In the language of the request
+	Description string `json:"description"` // In the language of the request
+}
+
+// UserBadge is a badge awarded to a user
+type UserBadge struct {
+	Key         string    `json:"key"`
+	Name        string    `json:"name"`        // In the language of the request
+	Description string    `json:"description"` // In the language of the request
+	AwardedAt   time.Time `json:"awarded_at"`
+}
+
+// MapsQuota reports Google Maps calls against the daily budget
+type MapsQuota struct {
+	Budget    int            `json:"budget"`              // Calls per UTC day, 0 for no limit
+	Used      int            `json:"used"`                // Calls today
+	Remaining *int           `json:"remaining,omitempty"` // Missing without a budget
+	ResetsAt  time.Time      `json:"resets_at"`
+	Days      []MapsUsageDay `json:"days"` // Newest first
+}
+
+// MapsUsageDay is the number of Google Maps calls on a UTC day
+type MapsUsageDay struct {
+	Date       string         `json:"date"` // YYYY-MM-DD
+	Calls      int            `json:"calls"`
+	Operations map[string]int `json:"operations"` // Calls by operation, e.g. text_search
+}
+
+// ExternalSource is restaurant data from a review site, matched by name and location
+type ExternalSource struct {
+	Provider    string    `json:"provider"` // yelp, foursquare
+	ExternalID  string    `json:"external_id"`
+	Name        string    `json:"name"` // Name of the matched business
+	URL         *string   `json:"url,omitempty"`
+	Rating      *float64  `json:"rating,omitempty"`       // 0 to 5
+	RatingCount *int      `json:"rating_count,omitempty"` // Reviews the rating is based on
+	PriceLevel  *int      `json:"price_level,omitempty"`  // 1 (inexpensive) to 4 (very expensive)
+	Categories  []string  `json:"categories"`
+	FetchedAt   time.Time `json:"fetched_at"`
+}
+
+// Webhook is an endpoint notified of events by signed POST requests
+type Webhook struct {
+	ID              int       `json:"id"`
+	URL             string    `json:"url"`
+	Secret          string    `json:"secret,omitempty"` // Only returned when the webhook is created
+	Events          []string  `json:"events"`           // restaurant.created, rating.created, suggestion.converted, photo.uploaded
+	Description     *string   `json:"description,omitempty"`
+	Active          bool      `json:"active"`
+	CreatedByUserID *int      `json:"created_by_user_id,omitempty"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// WebhookRequest registers or changes a webhook
+type WebhookRequest struct {
+	URL         string   `json:"url"`
+	Secret      string   `json:"secret,omitempty"` // Generated when empty on creation, kept when empty on update
+	Events      []string `json:"events"`
+	Description string   `json:"description,omitempty"`
+	Active      *bool    `json:"active,omitempty"` // Defaults to true
+}
+
+// WebhookDelivery is an event queued for, or delivered to, a webhook
+type WebhookDelivery struct {
+	ID             int64           `json:"id"`
+	WebhookID      int             `json:"webhook_id"`
+	Event          string          `json:"event"`
+	Payload        json.RawMessage `json:"payload" swaggertype:"object"` // Request body
+	Status         string          `json:"status"`                       // pending, delivered or failed
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  *time.Time      `json:"next_attempt_at,omitempty"`  // Only while pending
+	LastStatusCode *int            `json:"last_status_code,omitempty"` // Response status of the last attempt
+	LastError      *string         `json:"last_error,omitempty"`
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
+
+// Setting is a value admins can change at runtime; an override replaces the startup
+// configuration until it is reset
+type Setting struct {
+	Key             string     `json:"key"`
+	Type            string     `json:"type"`    // int, bool or string
+	Value           any        `json:"value"`   // Effective value
+	Default         any        `json:"default"` // From the startup configuration
+	Description     string     `json:"description"`
+	Options         []string   `json:"options,omitempty"` // Allowed values of string settings
+	Min             *int       `json:"min,omitempty"`     // Bounds of int settings
+	Max             *int       `json:"max,omitempty"`
+	Overridden      bool       `json:"overridden"`
+	UpdatedByUserID *int       `json:"updated_by_user_id,omitempty"` // Set while overridden
+	UpdatedAt       *time.Time `json:"updated_at,omitempty"`
+}
+
+// SettingRequest overrides a setting with a value of its type
+type SettingRequest struct {
+	Value any `json:"value"`
+}
+
+// LogLevels are the log levels of the server: the default and those of modules that
+// log at another level
+type LogLevels struct {
+	Level            string            `json:"level"`             // debug, info, warn or error
+	Modules          map[string]string `json:"modules"`           // e.g. {"photos": "debug"}
+	AvailableModules []string          `json:"available_modules"` // Modules whose level can be set
+}
+
+// LogLevelsRequest replaces the log levels; modules left out log at the default level
+type LogLevelsRequest struct {
+	Level   string            `json:"level"`
+	Modules map[string]string `json:"modules,omitempty"`
+}
+
+// Space is a separate list of restaurants and suggestions with its own members. Requests
+// choose one with the X-Space header; without it they use the default space.
+type Space struct {
+	ID              int       `json:"id"`
+	Slug            string    `json:"slug"`
+	Name            string    `json:"name"`
+	Public          bool      `json:"public"` // Readable by everyone, not only members
+	Role            *string   `json:"role"`   // Of the current user, nil if not a member
+	CreatedByUserID *int      `json:"created_by_user_id"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+type CreateSpaceRequest struct {
+	Slug   string `json:"slug"` // Lowercase letters, digits and dashes
+	Name   string `json:"name"`
+	Public bool   `json:"public"`
+}
+
+type UpdateSpaceRequest struct {
+	Name   *string `json:"name,omitempty"`
+	Public *bool   `json:"public,omitempty"`
+}
+
+// SpaceMember is a user's membership of a space
+type SpaceMember struct {
+	UserID    int       `json:"user_id"`
+	Username  string    `json:"username"`
+	Role      string    `json:"role"` // owner, admin or member
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type SpaceMemberRequest struct {
+	Role string `json:"role"`
+}
+
+// SpaceInvite lets users join a space with its role
+type SpaceInvite struct {
+	ID              int        `json:"id"`
+	SpaceID         int        `json:"space_id"`
+	CodePrefix      string     `json:"code_prefix"`
+	Role            string     `json:"role"` // admin or member
+	MaxUses         int        `json:"max_uses"`
+	UseCount        int        `json:"use_count"`
+	ExpiresAt       *time.Time `json:"expires_at"`
+	CreatedByUserID *int       `json:"created_by_user_id"`
+	RevokedAt       *time.Time `json:"revoked_at"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+type CreateSpaceInviteRequest struct {
+	Role          string `json:"role,omitempty"`            // Default member
+	MaxUses       *int   `json:"max_uses,omitempty"`        // Default 1
+	ExpiresInDays *int   `json:"expires_in_days,omitempty"` // Default 7, 0 for no expiry
+}
+
+// CreateSpaceInviteResponse includes the invite code, which is only returned once
+type CreateSpaceInviteResponse struct {
+	Code   string      `json:"code"`
+	Invite SpaceInvite `json:"invite"`
+}
+
+type JoinSpaceRequest struct {
+	Code string `json:"code"`
+}
+
 // Menu Photos
 type MenuPhoto struct {
 	ID               int       `json:"id"`
@@ -176,6 +654,14 @@ type MenuPhoto struct {
 	Caption          string    `json:"caption"`
 	FileSize         *int      `json:"file_size"`
 	MimeType         *string   `json:"mime_type"`
+	PhotoType        string    `json:"photo_type"` // menu, food, interior, receipt
+	Position         int       `json:"position"`
+	IsCover          bool      `json:"is_cover"`
+	Status           string    `json:"status"`                      // processing, ready, failed
+	ProcessingError  *string   `json:"processing_error,omitempty"`  // Set when status is failed
+	ModerationStatus string    `json:"moderation_status"`           // pending, flagged, approved
+	ModerationLabels []string  `json:"moderation_labels,omitempty"` // Reasons given by the moderation provider
+	UploadedByUserID *int      `json:"uploaded_by_user_id"`
 	URL              string    `json:"url"` // Computed field
 	CreatedAt        time.Time `json:"created_at"`
 	UpdatedAt        time.Time `json:"updated_at"`
@@ -184,3 +670,117 @@ type MenuPhoto struct {
 type UploadPhotoResponse struct {
 	Photo MenuPhoto `json:"photo"`
 }
+
+// ModeratePhotoRequest is an admin decision on a photo in the moderation queue
+type ModeratePhotoRequest struct {
+	Action string `json:"action"` // approve, reject
+}
+
+// PhotoEvent is streamed to clients when background processing of a photo finishes
+type PhotoEvent struct {
+	Type         string  `json:"type"` // photo.ready, photo.failed
+	PhotoID      int     `json:"photo_id"`
+	RestaurantID int     `json:"restaurant_id"`
+	Status       string  `json:"status"`
+	Error        *string `json:"error,omitempty"`
+}
+
+// Direct-to-S3 photo uploads
+type PresignPhotoUploadRequest struct {
+	Filename    string `json:"filename"`
+	ContentType string `json:"content_type"`
+	FileSize    int64  `json:"file_size"`
+	Caption     string `json:"caption"`
+	PhotoType   string `json:"photo_type,omitempty"` // Defaults to menu
+}
+
+type PresignPhotoUploadResponse struct {
+	UploadURL   string            `json:"upload_url"`
+	UploadToken string            `json:"upload_token"`
+	Method      string            `json:"method"`
+	Headers     map[string]string `json:"headers"` // Headers the client must send with the PUT
+	ExpiresAt   time.Time         `json:"expires_at"`
+}
+
+// UploadPhotoFromURLRequest imports a photo the server downloads from a remote URL
+type UploadPhotoFromURLRequest struct {
+	URL       string `json:"url"`
+	Caption   string `json:"caption"`
+	PhotoType string `json:"photo_type,omitempty"` // Defaults to menu
+}
+
+type ReorderPhotosRequest struct {
+	PhotoIDs []int `json:"photo_ids"` // Every photo of the restaurant, in display order
+}
+
+type ConfirmPhotoUploadRequest struct {
+	UploadToken string  `json:"upload_token"`
+	Caption     *string `json:"caption,omitempty"` // Overrides the caption given at presign time
+}
+
+// PhotoCleanupReport is the result of reconciling photo storage against menu_photos
+type PhotoCleanupReport struct {
+	DryRun          bool      `json:"dry_run"`
+	ObjectsScanned  int       `json:"objects_scanned"`
+	OrphanedObjects []string  `json:"orphaned_objects"` // Stored files with no matching photo
+	DeletedObjects  int       `json:"deleted_objects"`
+	MissingFiles    []int     `json:"missing_files"`   // IDs of photos whose original file is missing
+	DeletedPhotos   int       `json:"deleted_photos"`  // Only with remove_missing
+	ExpiredUploads  int       `json:"expired_uploads"` // Expired pending direct uploads removed
+	StartedAt       time.Time `json:"started_at"`
+	FinishedAt      time.Time `json:"finished_at"`
+}
+
+// HealthCheck is the result of checking one dependency
+type HealthCheck struct {
+	Status    string `json:"status"` // ok or error
+	LatencyMs int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+	Details   string `json:"details,omitempty"`
+}
+
+// HealthResponse is the result of the readiness check
+type HealthResponse struct {
+	Status string                 `json:"status"` // ok, unavailable or shutting_down
+	Checks map[string]HealthCheck `json:"checks"`
+}
+
+// MetricsSnapshot is a persisted point of the request metrics. Totals are cumulative
+// since the counting start; Requests and Errors are the increase since the previous
+// snapshot of the same instance (omitted for its first one in the window).
+type MetricsSnapshot struct {
+	RecordedAt        time.Time        `json:"recorded_at"`
+	Instance          string           `json:"instance,omitempty"` // Set in cluster mode
+	TotalRequests     int64            `json:"total_requests"`
+	TotalErrors       int64            `json:"total_errors"`
+	Requests          *int64           `json:"requests,omitempty"`
+	Errors            *int64           `json:"errors,omitempty"`
+	RequestsByStatus  map[string]int64 `json:"requests_by_status"`
+	AvgResponseTimeMs float64          `json:"avg_response_time_ms"`
+	P50ResponseTimeMs float64          `json:"p50_response_time_ms"`
+	P95ResponseTimeMs float64          `json:"p95_response_time_ms"`
+	P99ResponseTimeMs float64          `json:"p99_response_time_ms"`
+}
+
+// MetricsHistoryResponse lists the metrics snapshots of a time window
+type MetricsHistoryResponse struct {
+	Window        string            `json:"window"`
+	CountingSince *time.Time        `json:"counting_since,omitempty"` // When the counters last started from zero
+	Snapshots     []MetricsSnapshot `json:"snapshots"`
+}
+
+// BackupInfo describes a database backup in storage
+type BackupInfo struct {
+	Key           string         `json:"key"`
+	Size          int64          `json:"size"`
+	CreatedAt     time.Time      `json:"created_at"`
+	SchemaVersion uint           `json:"schema_version,omitempty"` // Only for a backup just taken
+	Rows          int            `json:"rows,omitempty"`
+	Tables        map[string]int `json:"tables,omitempty"` // Rows per table
+}
+
+// BackupStatus is the result of starting a backup
+type BackupStatus struct {
+	Status string `json:"status"` // started
+	Key    string `json:"key"`
+}
@@ -0,0 +1,84 @@
+package openapi
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"log"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/go-openapi/spec"
+	"github.com/swaggo/swag"
+)
+
+// routerAnnotation matches "@Router /restaurants/{id} [get]"
+var routerAnnotation = regexp.MustCompile(`@Router\s+(\S+)\s+\[(\w+)\]`)
+
+// ParseAnnotations parses the swag annotations below dir (the backend module), like
+// `swag init -g mainFile`. Operations without @ID are named after their handler
+// function: CreateRestaurant documents createRestaurant.
+func ParseAnnotations(dir, mainFile, handlersDir string) (*spec.Swagger, error) {
+	p := swag.New(swag.SetDebugger(log.New(io.Discard, "", 0)))
+	if err := p.ParseAPI(dir, mainFile, 100); err != nil {
+		return nil, fmt.Errorf("parse annotations: %w", err)
+	}
+	annotations := p.GetSwagger()
+
+	names, err := handlerNames(filepath.Join(dir, handlersDir))
+	if err != nil {
+		return nil, err
+	}
+	if annotations.Paths != nil {
+		for path, item := range annotations.Paths.Paths {
+			for _, method := range methods {
+				if op := annotatedOperation(item, method); op != nil && op.ID == "" {
+					op.ID = names[strings.ToLower(method)+" "+path]
+				}
+			}
+			annotations.Paths.Paths[path] = item
+		}
+	}
+	return annotations, nil
+}
+
+// handlerNames maps "method path" of the @Router annotations in dir to the lowerCamel
+// name of the annotated function
+func handlerNames(dir string) (map[string]string, error) {
+	packages, err := parser.ParseDir(token.NewFileSet(), dir, nil, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("parse handlers: %w", err)
+	}
+	names := map[string]string{}
+	for _, pkg := range packages {
+		for _, file := range pkg.Files {
+			for _, decl := range file.Decls {
+				fn, ok := decl.(*ast.FuncDecl)
+				if !ok || fn.Doc == nil {
+					continue
+				}
+				for _, match := range routerAnnotation.FindAllStringSubmatch(fn.Doc.Text(), -1) {
+					names[strings.ToLower(match[2])+" "+match[1]] = lowerFirst(fn.Name.Name)
+				}
+			}
+		}
+	}
+	return names, nil
+}
+
+func lowerFirst(s string) string {
+	runes := []rune(s)
+	// Leading initialisms are lowered as a whole: APIKeys -> apiKeys
+	i := 0
+	for i < len(runes) && unicode.IsUpper(runes[i]) {
+		i++
+	}
+	if i > 1 && i < len(runes) {
+		i-- // The last capital starts the next word
+	}
+	return strings.ToLower(string(runes[:i])) + string(runes[i:])
+}
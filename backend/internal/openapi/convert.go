@@ -0,0 +1,308 @@
+package openapi
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-openapi/spec"
+	apperrors "github.com/nomdb/backend/internal/errors"
+)
+
+const jsonContentType = "application/json"
+
+// converter translates swag's Swagger 2 annotations to OpenAPI 3
+type converter struct {
+	annotations *spec.Swagger
+	// names maps definition names (models.Restaurant) to schema names (Restaurant)
+	names map[string]string
+}
+
+// newConverter names schemas after their Go types, prefixing the package only where
+// two packages define the same type name
+func newConverter(annotations *spec.Swagger) *converter {
+	c := &converter{annotations: annotations, names: map[string]string{}}
+	count := map[string]int{}
+	for name := range annotations.Definitions {
+		count[typeName(name)]++
+	}
+	for name := range annotations.Definitions {
+		short := typeName(name)
+		if count[short] > 1 {
+			short = ""
+			for _, word := range splitWords(name) {
+				short += strings.ToUpper(word[:1]) + word[1:]
+			}
+		}
+		c.names[name] = short
+	}
+	return c
+}
+
+// typeName strips the package of a definition name
+func typeName(definition string) string {
+	return definition[strings.LastIndex(definition, ".")+1:]
+}
+
+func convertInfo(info *spec.Info) Info {
+	if info == nil {
+		return Info{Title: "API", Version: "1.0"}
+	}
+	converted := Info{Title: info.Title, Description: info.Description, Version: info.Version}
+	if info.Contact != nil {
+		converted.Contact = &Contact{Name: info.Contact.Name, URL: info.Contact.URL, Email: info.Contact.Email}
+	}
+	if info.License != nil {
+		converted.License = &License{Name: info.License.Name, URL: info.License.URL}
+	}
+	return converted
+}
+
+func (c *converter) components() Components {
+	components := Components{Schemas: map[string]Schema{}}
+	for name, definition := range c.annotations.Definitions {
+		components.Schemas[c.names[name]] = c.schema(&definition)
+	}
+	for name, definition := range c.annotations.SecurityDefinitions {
+		if components.SecuritySchemes == nil {
+			components.SecuritySchemes = map[string]SecurityScheme{}
+		}
+		scheme := SecurityScheme{Type: definition.Type, Description: definition.Description, In: definition.In, Name: definition.Name}
+		// OpenAPI 3 ignores API keys in the Authorization header; they are bearer tokens
+		if definition.Type == "apiKey" && definition.In == "header" && strings.EqualFold(definition.Name, "Authorization") {
+			scheme = SecurityScheme{Type: "http", Description: definition.Description, Scheme: "bearer", BearerFormat: "JWT"}
+		}
+		components.SecuritySchemes[name] = scheme
+	}
+	return components
+}
+
+// schema converts a Swagger 2 schema: references point to components, x-nullable
+// becomes nullable and files become binary strings
+func (c *converter) schema(s *spec.Schema) Schema {
+	if s == nil {
+		return nil
+	}
+	raw, err := json.Marshal(s)
+	if err != nil {
+		return Schema{}
+	}
+	var decoded Schema
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		return Schema{}
+	}
+	return c.rewrite(decoded).(Schema)
+}
+
+func (c *converter) rewrite(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for key, value := range v {
+			v[key] = c.rewrite(value)
+		}
+		if ref, ok := v["$ref"].(string); ok {
+			name := strings.TrimPrefix(ref, "#/definitions/")
+			if short, ok := c.names[name]; ok {
+				name = short
+			}
+			v["$ref"] = "#/components/schemas/" + name
+		}
+		if nullable, ok := v["x-nullable"]; ok {
+			delete(v, "x-nullable")
+			v["nullable"] = nullable
+		}
+		if v["type"] == "file" {
+			v["type"] = "string"
+			v["format"] = "binary"
+		}
+		return v
+	case []any:
+		for i, item := range v {
+			v[i] = c.rewrite(item)
+		}
+	}
+	return v
+}
+
+// simpleSchema converts the type of a non-body parameter or header
+func (c *converter) simpleSchema(s spec.SimpleSchema, validations spec.CommonValidations, items *spec.Items) Schema {
+	schema := Schema{}
+	if s.Type != "" {
+		schema["type"] = s.Type
+	}
+	if s.Format != "" {
+		schema["format"] = s.Format
+	}
+	if s.Default != nil {
+		schema["default"] = s.Default
+	}
+	if len(validations.Enum) > 0 {
+		schema["enum"] = validations.Enum
+	}
+	if validations.Minimum != nil {
+		schema["minimum"] = *validations.Minimum
+	}
+	if validations.Maximum != nil {
+		schema["maximum"] = *validations.Maximum
+	}
+	if validations.MaxLength != nil {
+		schema["maxLength"] = *validations.MaxLength
+	}
+	if items != nil {
+		schema["items"] = c.simpleSchema(items.SimpleSchema, items.CommonValidations, items.Items)
+	}
+	if s.Type == "file" {
+		schema["type"] = "string"
+		schema["format"] = "binary"
+	}
+	return schema
+}
+
+// operation converts the annotation of an operation on path
+func (c *converter) operation(op *spec.Operation, path string) *Operation {
+	converted := &Operation{
+		OperationID: op.ID,
+		Summary:     op.Summary,
+		Description: op.Description,
+		Tags:        op.Tags,
+		Deprecated:  op.Deprecated,
+		Security:    op.Security,
+		Responses:   map[string]*Response{},
+	}
+
+	consumes := op.Consumes
+	if len(consumes) == 0 {
+		consumes = c.annotations.Consumes
+	}
+	var form *Schema
+	documented := map[string]bool{}
+	inPath := map[string]bool{}
+	for _, name := range pathVariables(path) {
+		inPath[name] = true
+	}
+	for _, param := range op.Parameters {
+		// Handlers serving several routes document the path parameters of all of them
+		if param.In == "path" && !inPath[param.Name] {
+			continue
+		}
+		switch param.In {
+		case "body":
+			contentType := jsonContentType
+			if len(consumes) > 0 {
+				contentType = consumes[0]
+			}
+			converted.RequestBody = &RequestBody{
+				Description: param.Description,
+				Required:    param.Required,
+				Content:     map[string]MediaType{contentType: {Schema: c.schema(param.Schema)}},
+			}
+		case "formData":
+			if form == nil {
+				form = &Schema{"type": "object", "properties": Schema{}}
+			}
+			property := c.simpleSchema(param.SimpleSchema, param.CommonValidations, param.Items)
+			if param.Description != "" {
+				property["description"] = param.Description
+			}
+			(*form)["properties"].(Schema)[param.Name] = property
+			if param.Required {
+				required, _ := (*form)["required"].([]string)
+				(*form)["required"] = append(required, param.Name)
+			}
+		default:
+			documented[param.In+" "+param.Name] = true
+			converted.Parameters = append(converted.Parameters, Parameter{
+				Name:        param.Name,
+				In:          param.In,
+				Description: param.Description,
+				Required:    param.Required || param.In == "path",
+				Schema:      c.simpleSchema(param.SimpleSchema, param.CommonValidations, param.Items),
+			})
+		}
+	}
+	if form != nil {
+		contentType := "multipart/form-data"
+		for _, t := range consumes {
+			if t == "application/x-www-form-urlencoded" {
+				contentType = t
+			}
+		}
+		_, required := (*form)["required"]
+		converted.RequestBody = &RequestBody{Required: required, Content: map[string]MediaType{contentType: {Schema: *form}}}
+	}
+	// Path variables must be declared even where the annotation forgot them
+	for _, name := range pathVariables(path) {
+		if !documented["path "+name] {
+			converted.Parameters = append(converted.Parameters, Parameter{Name: name, In: "path", Required: true, Schema: Schema{"type": "string"}})
+		}
+	}
+
+	produces := op.Produces
+	if len(produces) == 0 {
+		produces = c.annotations.Produces
+	}
+	if len(produces) == 0 {
+		produces = []string{jsonContentType}
+	}
+	if op.Responses != nil {
+		for status, response := range op.Responses.StatusCodeResponses {
+			converted.Responses[strconv.Itoa(status)] = c.response(response, status, produces)
+		}
+		if op.Responses.Default != nil {
+			converted.Responses["default"] = c.response(*op.Responses.Default, 0, produces)
+		}
+	}
+	if len(converted.Responses) == 0 {
+		converted.Responses["default"] = &Response{Description: "Undocumented"}
+	}
+	return converted
+}
+
+func (c *converter) response(r spec.Response, status int, produces []string) *Response {
+	converted := &Response{Description: r.Description}
+	if converted.Description == "" {
+		converted.Description = "Status " + strconv.Itoa(status)
+	}
+	for name, header := range r.Headers {
+		if converted.Headers == nil {
+			converted.Headers = map[string]Header{}
+		}
+		converted.Headers[name] = Header{
+			Description: header.Description,
+			Schema:      c.simpleSchema(header.SimpleSchema, header.CommonValidations, header.Items),
+		}
+	}
+	if r.Schema == nil {
+		return converted
+	}
+
+	schema := c.schema(r.Schema)
+	converted.Content = map[string]MediaType{}
+	// Errors are problem details whatever the operation produces otherwise
+	if status == 0 || status >= 400 {
+		converted.Content[apperrors.ContentType] = MediaType{Schema: schema}
+		return converted
+	}
+	for _, contentType := range produces {
+		switch {
+		case contentType == jsonContentType || strings.HasSuffix(contentType, "+json"):
+			converted.Content[contentType] = MediaType{Schema: schema}
+		case strings.HasPrefix(contentType, "text/"):
+			converted.Content[contentType] = MediaType{Schema: Schema{"type": "string"}}
+		default:
+			converted.Content[contentType] = MediaType{Schema: Schema{"type": "string", "format": "binary"}}
+		}
+	}
+	return converted
+}
+
+// sortedKeys returns the keys of m in order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
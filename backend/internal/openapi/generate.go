@@ -0,0 +1,139 @@
+package openapi
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// GeneratedHeader starts every generated file; cmd/openapi -check compares the rest
+const GeneratedHeader = "Code generated by cmd/openapi. DO NOT EDIT."
+
+// documentedOperation is an operation of a document with its method and path
+type documentedOperation struct {
+	Method string // Uppercase
+	Path   string
+	*Operation
+}
+
+// operations lists the operations of doc ordered by path and method
+func (doc *Document) operations() []documentedOperation {
+	var ops []documentedOperation
+	for _, path := range sortedKeys(doc.Paths) {
+		item := doc.Paths[path]
+		methodNames := make([]string, 0, len(item))
+		for method := range item {
+			methodNames = append(methodNames, strings.ToUpper(method))
+		}
+		sort.Slice(methodNames, func(i, j int) bool { return methodIndex(methodNames[i]) < methodIndex(methodNames[j]) })
+		for _, method := range methodNames {
+			ops = append(ops, documentedOperation{Method: method, Path: path, Operation: item[strings.ToLower(method)]})
+		}
+	}
+	return ops
+}
+
+// Response kinds of generated client methods
+const (
+	responseNone = iota // No content, or only undocumented responses
+	responseJSON        // Decoded JSON
+	responseRaw         // The HTTP response itself: files, text and event streams
+)
+
+// successResponse returns how the client returns the first 2xx response of op, and its
+// JSON schema
+func (op *Operation) successResponse() (int, Schema) {
+	statuses := make([]int, 0, len(op.Responses))
+	for key := range op.Responses {
+		if status, err := strconv.Atoi(key); err == nil && status >= 200 && status < 300 {
+			statuses = append(statuses, status)
+		}
+	}
+	sort.Ints(statuses)
+	if len(statuses) == 0 {
+		return responseNone, nil
+	}
+	response := op.Responses[strconv.Itoa(statuses[0])]
+	if len(response.Content) == 0 {
+		return responseNone, nil
+	}
+	if media, ok := response.Content[jsonContentType]; ok && len(response.Content) == 1 {
+		return responseJSON, media.Schema
+	}
+	return responseRaw, nil
+}
+
+// jsonBody returns the JSON request body schema of op, if any
+func (op *Operation) jsonBody() (Schema, bool) {
+	if op.RequestBody == nil {
+		return nil, false
+	}
+	media, ok := op.RequestBody.Content[jsonContentType]
+	return media.Schema, ok
+}
+
+// formBody returns the multipart or URL-encoded request body schema of op, if any
+func (op *Operation) formBody() (string, Schema, bool) {
+	if op.RequestBody == nil {
+		return "", nil, false
+	}
+	for _, contentType := range []string{"multipart/form-data", "application/x-www-form-urlencoded"} {
+		if media, ok := op.RequestBody.Content[contentType]; ok {
+			return contentType, media.Schema, true
+		}
+	}
+	return "", nil, false
+}
+
+// schemaName returns the component name a schema references, if any
+func schemaName(s Schema) (string, bool) {
+	ref, ok := s["$ref"].(string)
+	if !ok {
+		return "", false
+	}
+	return strings.TrimPrefix(ref, "#/components/schemas/"), true
+}
+
+// requiredProperties returns the set of required properties of an object schema
+func requiredProperties(s Schema) map[string]bool {
+	required := map[string]bool{}
+	switch names := s["required"].(type) {
+	case []any:
+		for _, name := range names {
+			if name, ok := name.(string); ok {
+				required[name] = true
+			}
+		}
+	case []string:
+		for _, name := range names {
+			required[name] = true
+		}
+	}
+	return required
+}
+
+// properties returns the properties of an object schema
+func properties(s Schema) map[string]Schema {
+	props := map[string]Schema{}
+	switch p := s["properties"].(type) {
+	case map[string]any:
+		for name, prop := range p {
+			if prop, ok := prop.(map[string]any); ok {
+				props[name] = prop
+			}
+		}
+	}
+	return props
+}
+
+// summary returns the doc comment text of an operation
+func (op *Operation) summary() string {
+	text := op.Summary
+	if text == "" {
+		text = op.Description
+	}
+	if text == "" && op.Undocumented {
+		text = "Undocumented"
+	}
+	return strings.Join(strings.Fields(text), " ")
+}
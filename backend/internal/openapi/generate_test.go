@@ -0,0 +1,105 @@
+package openapi
+
+import (
+	"strings"
+	"testing"
+)
+
+// testDocument has a JSON, a multipart, a file and a no-content operation
+func testDocument() *Document {
+	object := func(required []any, props map[string]any) Schema {
+		return Schema{"type": "object", "required": required, "properties": props}
+	}
+	return &Document{
+		OpenAPI: Version,
+		Info:    Info{Title: "Test API", Version: "1.0"},
+		Paths: map[string]PathItem{
+			"/things/{id}": {
+				"get": {
+					OperationID: "getThing",
+					Summary:     "Get a thing",
+					Parameters: []Parameter{
+						{Name: "id", In: "path", Required: true, Schema: Schema{"type": "integer"}},
+						{Name: "include_tags", In: "query", Schema: Schema{"type": "boolean"}},
+					},
+					Responses: map[string]*Response{"200": {Content: map[string]MediaType{
+						jsonContentType: {Schema: Schema{"$ref": "#/components/schemas/Thing"}},
+					}}},
+				},
+				"delete": {
+					OperationID: "deleteThing",
+					Parameters:  []Parameter{{Name: "id", In: "path", Required: true, Schema: Schema{"type": "integer"}}},
+					Responses:   map[string]*Response{"204": {Description: "Deleted"}},
+				},
+			},
+			"/things/{id}/image": {"get": {
+				OperationID: "getThingImage",
+				Parameters:  []Parameter{{Name: "id", In: "path", Required: true, Schema: Schema{"type": "integer"}}},
+				Responses: map[string]*Response{"200": {Content: map[string]MediaType{
+					"image/png": {Schema: Schema{"type": "string", "format": "binary"}},
+				}}},
+			}},
+			"/things": {"post": {
+				OperationID: "uploadThing",
+				RequestBody: &RequestBody{Required: true, Content: map[string]MediaType{
+					"multipart/form-data": {Schema: object([]any{"file"}, map[string]any{
+						"file":    map[string]any{"type": "string", "format": "binary"},
+						"caption": map[string]any{"type": "string"},
+					})},
+				}},
+				Responses: map[string]*Response{"201": {Content: map[string]MediaType{
+					jsonContentType: {Schema: Schema{"$ref": "#/components/schemas/Thing"}},
+				}}},
+			}},
+		},
+		Components: Components{Schemas: map[string]Schema{
+			"Thing": object([]any{"id"}, map[string]any{
+				"id":         map[string]any{"type": "integer"},
+				"name":       map[string]any{"type": "string", "description": "Display name"},
+				"tags":       map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+				"updated_at": map[string]any{"type": "string", "format": "date-time"},
+			}),
+			"ErrorResponse": object(nil, map[string]any{"detail": map[string]any{"type": "string"}}),
+		}},
+	}
+}
+
+func TestGo(t *testing.T) {
+	src, err := Go(testDocument(), "client")
+	if err != nil {
+		t.Fatalf("Go failed: %v", err)
+	}
+	code := string(src)
+	for _, want := range []string{
+		"package client",
+		"ID int `json:\"id\"`",
+		"Name      *string    `json:\"name,omitempty\"`",
+		"Tags      []string   `json:\"tags,omitempty\"`",
+		"UpdatedAt *time.Time `json:\"updated_at,omitempty\"`",
+		"func (c *Client) GetThing(ctx context.Context, id int, params *GetThingParams) (*Thing, error)",
+		"func (c *Client) DeleteThing(ctx context.Context, id int) error",
+		"func (c *Client) GetThingImage(ctx context.Context, id int) (*http.Response, error)",
+		"func (c *Client) UploadThing(ctx context.Context, form UploadThingForm) (*Thing, error)",
+		"File    *FormFile",
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("Expected the Go client to contain %q", want)
+		}
+	}
+}
+
+func TestTypeScript(t *testing.T) {
+	code := string(TypeScript(testDocument()))
+	for _, want := range []string{
+		"export interface Thing {\n  id: number;\n  /** Display name */\n  name?: string;\n  tags?: string[];\n  updated_at?: string;\n}",
+		"getThing: (\n      args: {\n        id: number;\n        include_tags?: boolean;\n      },\n    ): Promise<Thing> =>",
+		"request(\"GET\", `/things/${encodeURIComponent(String(args[\"id\"]))}`, \"json\", { query: { \"include_tags\": args[\"include_tags\"] } })",
+		"deleteThing: (\n      args: {\n        id: number;\n      },\n    ): Promise<void> =>",
+		"getThingImage: (\n      args: {\n        id: number;\n      },\n    ): Promise<Response> =>",
+		"file: Blob;",
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("Expected the TypeScript client to contain %q", want)
+		}
+	}
+}
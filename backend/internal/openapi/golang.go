@@ -0,0 +1,502 @@
+package openapi
+
+import (
+	"fmt"
+	"go/format"
+	"go/token"
+	"regexp"
+	"strings"
+)
+
+// goInitialisms are written in capitals in Go names: user_id -> UserID
+var goInitialisms = map[string]bool{
+	"api": true, "csrf": true, "dns": true, "html": true, "http": true, "https": true, "id": true, "ids": true,
+	"ip": true, "json": true, "jwt": true, "oidc": true, "sql": true, "ttl": true, "uri": true, "url": true,
+	"urls": true, "uuid": true,
+}
+
+// goName converts a JSON or parameter name to an exported Go name
+func goName(name string) string {
+	var b strings.Builder
+	for _, word := range splitWords(camelBoundary.ReplaceAllString(name, "${1}_${2}")) {
+		lower := strings.ToLower(word)
+		switch {
+		case lower == "ids":
+			b.WriteString("IDs")
+		case lower == "urls":
+			b.WriteString("URLs")
+		case goInitialisms[lower]:
+			b.WriteString(strings.ToUpper(word))
+		default:
+			b.WriteString(strings.ToUpper(word[:1]) + word[1:])
+		}
+	}
+	if b.Len() == 0 || !token.IsIdentifier(b.String()) {
+		return "X" + b.String()
+	}
+	return b.String()
+}
+
+// camelBoundary matches the start of words within camelCase names
+var camelBoundary = regexp.MustCompile(`([a-z0-9])([A-Z])`)
+
+// goParamName converts a name to an unexported Go identifier
+func goParamName(name string) string {
+	param := lowerFirst(goName(name))
+	if token.IsKeyword(param) {
+		param += "Param"
+	}
+	return param
+}
+
+// goType returns the Go type of a schema. optional values are pointers, except slices
+// and maps whose nil value already means unset.
+func goType(s Schema, optional bool) string {
+	t, reference := goBaseType(s)
+	if reference && (optional || s["nullable"] == true) {
+		return "*" + t
+	}
+	return t
+}
+
+// goBaseType returns the Go type of a schema, and whether it is a value type that needs a
+// pointer to be optional
+func goBaseType(s Schema) (string, bool) {
+	if name, ok := schemaName(s); ok {
+		return name, true
+	}
+	if all, ok := s["allOf"].([]any); ok && len(all) == 1 {
+		if part, ok := all[0].(map[string]any); ok {
+			return goBaseType(part)
+		}
+	}
+	switch s["type"] {
+	case "string":
+		switch s["format"] {
+		case "binary":
+			return "[]byte", false
+		case "date-time":
+			return "time.Time", true
+		}
+		return "string", true
+	case "integer":
+		if s["format"] == "int64" {
+			return "int64", true
+		}
+		return "int", true
+	case "number":
+		return "float64", true
+	case "boolean":
+		return "bool", true
+	case "array":
+		items, _ := s["items"].(map[string]any)
+		return "[]" + goType(items, false), false
+	case "object":
+		if len(properties(s)) == 0 {
+			if additional, ok := s["additionalProperties"].(map[string]any); ok {
+				return "map[string]" + goType(additional, false), false
+			}
+			return "map[string]any", false
+		}
+		return goStruct(s), true
+	}
+	return "json.RawMessage", false
+}
+
+// goStruct returns a struct type literal of the properties of s
+func goStruct(s Schema) string {
+	required := requiredProperties(s)
+	var b strings.Builder
+	b.WriteString("struct {\n")
+	props := properties(s)
+	for _, name := range sortedKeys(props) {
+		prop := props[name]
+		if description, ok := prop["description"].(string); ok && description != "" {
+			fmt.Fprintf(&b, "// %s\n", goComment(description))
+		}
+		tag := name
+		if !required[name] {
+			tag += ",omitempty"
+		}
+		fmt.Fprintf(&b, "%s %s `json:%q`\n", goName(name), goType(prop, !required[name]), tag)
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+func goComment(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// Go generates the Go client package of doc
+func Go(doc *Document, pkg string) ([]byte, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "// %s\n\n", GeneratedHeader)
+	fmt.Fprintf(&b, "// Package %s is a client of the %s (version %s), generated from its OpenAPI\n", pkg, doc.Info.Title, doc.Info.Version)
+	b.WriteString("// document. Methods return an *Error for non-2xx responses.\n")
+	fmt.Fprintf(&b, "package %s\n\n", pkg)
+
+	var decls strings.Builder
+	for _, name := range sortedKeys(doc.Components.Schemas) {
+		s := doc.Components.Schemas[name]
+		if description, ok := s["description"].(string); ok && description != "" {
+			fmt.Fprintf(&decls, "// %s %s\n", name, goComment(description))
+		}
+		t, _ := goBaseType(s)
+		fmt.Fprintf(&decls, "type %s %s\n\n", name, t)
+	}
+	for _, op := range doc.operations() {
+		writeGoOperation(&decls, op)
+	}
+
+	imports := []string{"bytes", "context", "encoding/json", "fmt", "io", "mime/multipart", "net/http", "net/url", "strings"}
+	if strings.Contains(decls.String(), "time.Time") {
+		imports = append(imports, "time")
+	}
+	b.WriteString("import (\n")
+	for _, path := range imports {
+		fmt.Fprintf(&b, "%q\n", path)
+	}
+	b.WriteString(")\n\n")
+	b.WriteString(goRuntime)
+	b.WriteString(decls.String())
+
+	src, err := format.Source([]byte(b.String()))
+	if err != nil {
+		return nil, fmt.Errorf("format Go client: %w", err)
+	}
+	return src, nil
+}
+
+func writeGoOperation(b *strings.Builder, op documentedOperation) {
+	method := goName(op.OperationID)
+	args := []string{"ctx context.Context"}
+	path := fmt.Sprintf("%q", op.Path)
+	var queryParams, headerParams []Parameter
+	for _, param := range op.Parameters {
+		switch param.In {
+		case "path":
+			name := goParamName(param.Name)
+			args = append(args, name+" "+goType(param.Schema, false))
+			path = strings.Replace(path, "{"+param.Name+"}", `"+url.PathEscape(fmt.Sprint(`+name+`))+"`, 1)
+		case "query":
+			queryParams = append(queryParams, param)
+		case "header":
+			headerParams = append(headerParams, param)
+		}
+	}
+	path = strings.TrimSuffix(path, `+""`)
+
+	// Query and header parameters go in a struct, since most are optional
+	if len(queryParams)+len(headerParams) > 0 {
+		fmt.Fprintf(b, "// %sParams are the query and header parameters of %s\n", method, method)
+		fmt.Fprintf(b, "type %sParams struct {\n", method)
+		for _, param := range append(queryParams, headerParams...) {
+			if param.Description != "" {
+				fmt.Fprintf(b, "// %s\n", goComment(param.Description))
+			}
+			fmt.Fprintf(b, "%s %s\n", goName(param.Name), goType(param.Schema, !param.Required))
+		}
+		b.WriteString("}\n\n")
+		args = append(args, "params *"+method+"Params")
+	}
+
+	body := "nil"
+	if schema, ok := op.jsonBody(); ok {
+		t := goType(schema, false)
+		if _, named := schemaName(schema); !named && strings.HasPrefix(t, "struct") {
+			fmt.Fprintf(b, "// %sBody is the request body of %s\n", method, method)
+			fmt.Fprintf(b, "type %sBody %s\n\n", method, t)
+			t = method + "Body"
+		}
+		args = append(args, "body "+t)
+		body = "jsonBody(body)"
+	} else if contentType, schema, ok := op.formBody(); ok {
+		required := requiredProperties(schema)
+		props := properties(schema)
+		fmt.Fprintf(b, "// %sForm is the form of %s\n", method, method)
+		fmt.Fprintf(b, "type %sForm struct {\n", method)
+		for _, name := range sortedKeys(props) {
+			prop := props[name]
+			if description, ok := prop["description"].(string); ok && description != "" {
+				fmt.Fprintf(b, "// %s\n", goComment(description))
+			}
+			t := goType(prop, !required[name])
+			if prop["format"] == "binary" {
+				t = "*FormFile"
+			}
+			fmt.Fprintf(b, "%s %s\n", goName(name), t)
+		}
+		b.WriteString("}\n\n")
+		args = append(args, "form "+method+"Form")
+		var fields []string
+		for _, name := range sortedKeys(props) {
+			fields = append(fields, fmt.Sprintf("%q: form.%s", name, goName(name)))
+		}
+		body = fmt.Sprintf("formBody(%q, map[string]any{%s})", contentType, strings.Join(fields, ", "))
+	}
+
+	kind, schema := op.successResponse()
+	result := "error"
+	switch kind {
+	case responseJSON:
+		result = "(" + goResultType(schema) + ", error)"
+	case responseRaw:
+		result = "(*http.Response, error)"
+	}
+
+	fmt.Fprintf(b, "// %s calls %s %s: %s\n", method, op.Method, op.Path, op.summary())
+	if kind == responseRaw {
+		b.WriteString("// The caller closes the response body.\n")
+	}
+	fmt.Fprintf(b, "func (c *Client) %s(%s) %s {\n", method, strings.Join(args, ", "), result)
+	if len(queryParams)+len(headerParams) > 0 {
+		b.WriteString("query, header := url.Values{}, http.Header{}\n")
+		b.WriteString("if params != nil {\n")
+		for _, param := range queryParams {
+			writeGoParamSet(b, "query", param)
+		}
+		for _, param := range headerParams {
+			writeGoParamSet(b, "header", param)
+		}
+		b.WriteString("}\n")
+		fmt.Fprintf(b, "resp, err := c.do(ctx, %q, %s, query, header, %s)\n", op.Method, path, body)
+	} else {
+		fmt.Fprintf(b, "resp, err := c.do(ctx, %q, %s, nil, nil, %s)\n", op.Method, path, body)
+	}
+	switch kind {
+	case responseJSON:
+		t := goResultType(schema)
+		b.WriteString("if err != nil {\nreturn nil, err\n}\n")
+		if strings.HasPrefix(t, "*") {
+			fmt.Fprintf(b, "var result %s\n", t[1:])
+			b.WriteString("if err := decode(resp, &result); err != nil {\nreturn nil, err\n}\nreturn &result, nil\n")
+		} else {
+			fmt.Fprintf(b, "var result %s\n", t)
+			b.WriteString("if err := decode(resp, &result); err != nil {\nreturn nil, err\n}\nreturn result, nil\n")
+		}
+	case responseRaw:
+		b.WriteString("return resp, err\n")
+	default:
+		b.WriteString("if err != nil {\nreturn err\n}\nreturn resp.Body.Close()\n")
+	}
+	b.WriteString("}\n\n")
+}
+
+// goResultType returns the result type of a JSON response: a pointer to structs, the
+// value of slices and maps
+func goResultType(s Schema) string {
+	t, reference := goBaseType(s)
+	if reference {
+		return "*" + t
+	}
+	return t
+}
+
+func writeGoParamSet(b *strings.Builder, values string, param Parameter) {
+	name := goName(param.Name)
+	t, reference := goBaseType(param.Schema)
+	switch {
+	case reference && !param.Required:
+		fmt.Fprintf(b, "if params.%s != nil {\n%s.Set(%q, fmt.Sprint(*params.%s))\n}\n", name, values, param.Name, name)
+	case strings.HasPrefix(t, "[]"):
+		fmt.Fprintf(b, "for _, v := range params.%s {\n%s.Add(%q, fmt.Sprint(v))\n}\n", name, values, param.Name)
+	default:
+		fmt.Fprintf(b, "%s.Set(%q, fmt.Sprint(params.%s))\n", values, param.Name, name)
+	}
+}
+
+// goRuntime is the hand-written part of the Go client
+const goRuntime = `// Client calls the API at BaseURL
+type Client struct {
+	BaseURL    string // Including /api, e.g. https://nomdb.example.com/api
+	HTTPClient *http.Client
+
+	// RequestEditor, when set, edits every request before it is sent, e.g. to add
+	// the Authorization or X-API-Key header
+	RequestEditor func(req *http.Request) error
+}
+
+// NewClient creates a client of the API at baseURL
+func NewClient(baseURL string) *Client {
+	return &Client{BaseURL: strings.TrimSuffix(baseURL, "/"), HTTPClient: http.DefaultClient}
+}
+
+// WithBearerToken returns a RequestEditor that authenticates with an access token
+func WithBearerToken(token string) func(req *http.Request) error {
+	return func(req *http.Request) error {
+		req.Header.Set("Authorization", "Bearer "+token)
+		return nil
+	}
+}
+
+// WithAPIKey returns a RequestEditor that authenticates with an API key
+func WithAPIKey(key string) func(req *http.Request) error {
+	return func(req *http.Request) error {
+		req.Header.Set("X-API-Key", key)
+		return nil
+	}
+}
+
+// Error is a non-2xx response. Problem holds the problem details (RFC 7807) when the
+// API sent them.
+type Error struct {
+	StatusCode int
+	Problem    *ErrorResponse
+	Body       []byte
+}
+
+func (e *Error) Error() string {
+	if e.Problem != nil && e.Problem.Detail != nil {
+		return fmt.Sprintf("%d %s: %s", e.StatusCode, http.StatusText(e.StatusCode), *e.Problem.Detail)
+	}
+	return fmt.Sprintf("%d %s", e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+// FormFile is a file field of a multipart form
+type FormFile struct {
+	Name    string // File name sent to the API
+	Content io.Reader
+}
+
+// requestBody is an encoded request body with its content type
+type requestBody struct {
+	contentType string
+	content     io.Reader
+	err         error
+}
+
+func jsonBody(v any) *requestBody {
+	data, err := json.Marshal(v)
+	return &requestBody{contentType: "application/json", content: bytes.NewReader(data), err: err}
+}
+
+// formBody encodes fields, leaving out nil pointers and files
+func formBody(contentType string, fields map[string]any) *requestBody {
+	values := url.Values{}
+	var files []string
+	for name, value := range fields {
+		switch v := value.(type) {
+		case *FormFile:
+			if v != nil {
+				files = append(files, name)
+			}
+		default:
+			if s, ok := formValue(v); ok {
+				values.Set(name, s)
+			}
+		}
+	}
+	if contentType != "multipart/form-data" {
+		return &requestBody{contentType: contentType, content: strings.NewReader(values.Encode())}
+	}
+
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	for name := range values {
+		if err := w.WriteField(name, values.Get(name)); err != nil {
+			return &requestBody{err: err}
+		}
+	}
+	for _, name := range files {
+		file := fields[name].(*FormFile)
+		part, err := w.CreateFormFile(name, file.Name)
+		if err == nil {
+			_, err = io.Copy(part, file.Content)
+		}
+		if err != nil {
+			return &requestBody{err: err}
+		}
+	}
+	if err := w.Close(); err != nil {
+		return &requestBody{err: err}
+	}
+	return &requestBody{contentType: w.FormDataContentType(), content: &buf}
+}
+
+// formValue formats a form field, dereferencing pointers; nil pointers are left out
+func formValue(v any) (string, bool) {
+	switch v := v.(type) {
+	case *string:
+		if v == nil {
+			return "", false
+		}
+		return *v, true
+	case *int:
+		if v == nil {
+			return "", false
+		}
+		return fmt.Sprint(*v), true
+	case *float64:
+		if v == nil {
+			return "", false
+		}
+		return fmt.Sprint(*v), true
+	case *bool:
+		if v == nil {
+			return "", false
+		}
+		return fmt.Sprint(*v), true
+	}
+	return fmt.Sprint(v), true
+}
+
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, header http.Header, body *requestBody) (*http.Response, error) {
+	target := c.BaseURL + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	var content io.Reader
+	if body != nil {
+		if body.err != nil {
+			return nil, body.err
+		}
+		content = body.content
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, content)
+	if err != nil {
+		return nil, err
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", body.contentType)
+	}
+	if c.RequestEditor != nil {
+		if err := c.RequestEditor(req); err != nil {
+			return nil, err
+		}
+	}
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		defer resp.Body.Close()
+		apiErr := &Error{StatusCode: resp.StatusCode}
+		apiErr.Body, _ = io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+		var problem ErrorResponse
+		if json.Unmarshal(apiErr.Body, &problem) == nil {
+			apiErr.Problem = &problem
+		}
+		return nil, apiErr
+	}
+	return resp, nil
+}
+
+// decode decodes a JSON response into v and closes it
+func decode(resp *http.Response, v any) error {
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+`
@@ -0,0 +1,341 @@
+// Package openapi builds an OpenAPI 3 document of the API from the router, which decides
+// which operations exist, and the swag annotations of the handlers, which describe them.
+// Annotations that disagree with the router are reported as drift. It also generates the
+// TypeScript and Go clients from the document (cmd/openapi).
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/go-openapi/spec"
+	"github.com/gorilla/mux"
+)
+
+// Version is the OpenAPI version of generated documents
+const Version = "3.0.3"
+
+// Document is the subset of an OpenAPI 3 document the generator produces
+type Document struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       Info                `json:"info"`
+	Servers    []Server            `json:"servers"`
+	Tags       []Tag               `json:"tags,omitempty"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components Components          `json:"components"`
+}
+
+type Info struct {
+	Title       string   `json:"title"`
+	Description string   `json:"description,omitempty"`
+	Version     string   `json:"version"`
+	Contact     *Contact `json:"contact,omitempty"`
+	License     *License `json:"license,omitempty"`
+}
+
+type Contact struct {
+	Name  string `json:"name,omitempty"`
+	URL   string `json:"url,omitempty"`
+	Email string `json:"email,omitempty"`
+}
+
+type License struct {
+	Name string `json:"name"`
+	URL  string `json:"url,omitempty"`
+}
+
+type Server struct {
+	URL string `json:"url"`
+}
+
+type Tag struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// PathItem maps lowercase HTTP methods to operations
+type PathItem map[string]*Operation
+
+type Operation struct {
+	OperationID string                `json:"operationId"`
+	Summary     string                `json:"summary,omitempty"`
+	Description string                `json:"description,omitempty"`
+	Tags        []string              `json:"tags,omitempty"`
+	Parameters  []Parameter           `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]*Response  `json:"responses"`
+	Security    []map[string][]string `json:"security,omitempty"`
+	Deprecated  bool                  `json:"deprecated,omitempty"`
+	// Undocumented marks routes without annotations; only their path parameters are known
+	Undocumented bool `json:"x-undocumented,omitempty"`
+}
+
+type Parameter struct {
+	Name        string `json:"name"`
+	In          string `json:"in"` // path, query or header
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+	Schema      Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Description string               `json:"description,omitempty"`
+	Required    bool                 `json:"required,omitempty"`
+	Content     map[string]MediaType `json:"content"`
+}
+
+type MediaType struct {
+	Schema Schema `json:"schema,omitempty"`
+}
+
+type Response struct {
+	Description string               `json:"description"`
+	Headers     map[string]Header    `json:"headers,omitempty"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+type Header struct {
+	Description string `json:"description,omitempty"`
+	Schema      Schema `json:"schema"`
+}
+
+type Components struct {
+	Schemas         map[string]Schema         `json:"schemas"`
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+type SecurityScheme struct {
+	Type         string `json:"type"` // http or apiKey
+	Description  string `json:"description,omitempty"`
+	Scheme       string `json:"scheme,omitempty"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+	In           string `json:"in,omitempty"`
+	Name         string `json:"name,omitempty"`
+}
+
+// Schema is a JSON schema object as decoded from JSON
+type Schema = map[string]any
+
+// Drift is a disagreement between the router and the annotations
+type Drift struct {
+	Method  string
+	Path    string
+	Message string
+}
+
+func (d Drift) String() string {
+	return fmt.Sprintf("%s %s: %s", d.Method, d.Path, d.Message)
+}
+
+// methods are the HTTP methods documented, in document order
+var methods = []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+
+// route is an operation served by the router
+type route struct {
+	method string
+	path   string // Relative to the base path, with plain {name} variables
+}
+
+// pathVariablePattern strips the patterns of mux path variables: {id:[0-9]+} -> {id}
+var pathVariablePattern = regexp.MustCompile(`\{([^}:]+):[^}]*\}`)
+
+// Routes lists the operations of router below basePath. HEAD and OPTIONS are left out,
+// as are prefix routes such as file servers, whose paths are not fixed.
+func Routes(router *mux.Router, basePath string) ([]route, error) {
+	var routes []route
+	err := router.Walk(func(r *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		template, err := r.GetPathTemplate()
+		if err != nil {
+			return nil
+		}
+		routeMethods, err := r.GetMethods()
+		if err != nil {
+			return nil
+		}
+		if pattern, err := r.GetPathRegexp(); err != nil || !strings.HasSuffix(pattern, "$") {
+			return nil
+		}
+		if !strings.HasPrefix(template, basePath+"/") {
+			return nil
+		}
+		path := pathVariablePattern.ReplaceAllString(strings.TrimPrefix(template, basePath), "{$1}")
+		for _, method := range routeMethods {
+			if method == http.MethodHead || method == http.MethodOptions {
+				continue
+			}
+			routes = append(routes, route{method: method, path: path})
+		}
+		return nil
+	})
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].path != routes[j].path {
+			return routes[i].path < routes[j].path
+		}
+		return methodIndex(routes[i].method) < methodIndex(routes[j].method)
+	})
+	return routes, err
+}
+
+func methodIndex(method string) int {
+	for i, m := range methods {
+		if m == method {
+			return i
+		}
+	}
+	return len(methods)
+}
+
+// annotatedOperation returns the annotation of method on item, if any
+func annotatedOperation(item spec.PathItem, method string) *spec.Operation {
+	switch method {
+	case http.MethodGet:
+		return item.Get
+	case http.MethodPost:
+		return item.Post
+	case http.MethodPut:
+		return item.Put
+	case http.MethodPatch:
+		return item.Patch
+	case http.MethodDelete:
+		return item.Delete
+	}
+	return nil
+}
+
+// Build documents the routes of router with the annotations. Routes without annotations
+// are documented with their path parameters only; annotations of routes that do not
+// exist are dropped, except that an annotation with the wrong method documents the only
+// unannotated method of its path. Both are reported as drift.
+func Build(router *mux.Router, annotations *spec.Swagger) (*Document, []Drift, error) {
+	basePath := strings.TrimSuffix(annotations.BasePath, "/")
+	routes, err := Routes(router, basePath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := newConverter(annotations)
+	doc := &Document{
+		OpenAPI:    Version,
+		Info:       convertInfo(annotations.Info),
+		Servers:    []Server{{URL: basePath}},
+		Paths:      map[string]PathItem{},
+		Components: c.components(),
+	}
+	for _, tag := range annotations.Tags {
+		doc.Tags = append(doc.Tags, Tag{Name: tag.Name, Description: tag.Description})
+	}
+
+	annotated := map[string]spec.PathItem{}
+	if annotations.Paths != nil {
+		for path, item := range annotations.Paths.Paths {
+			annotated[pathVariablePattern.ReplaceAllString(path, "{$1}")] = item
+		}
+	}
+	routed := map[route]bool{}
+	for _, rt := range routes {
+		routed[rt] = true
+	}
+
+	// Pair routes with annotations, exactly or by path alone when the method is wrong
+	var drift []Drift
+	used := map[route]*spec.Operation{}
+	for _, rt := range routes {
+		if op := annotatedOperation(annotated[rt.path], rt.method); op != nil {
+			used[rt] = op
+		}
+	}
+	for _, path := range sortedKeys(annotated) {
+		for _, method := range methods {
+			op := annotatedOperation(annotated[path], method)
+			if op == nil || routed[route{method, path}] {
+				continue
+			}
+			var candidates []route
+			for _, rt := range routes {
+				if rt.path == path && used[rt] == nil {
+					candidates = append(candidates, rt)
+				}
+			}
+			if len(candidates) == 1 {
+				used[candidates[0]] = op
+				drift = append(drift, Drift{method, path, "documented as " + method + " but routed as " + candidates[0].method})
+				continue
+			}
+			drift = append(drift, Drift{method, path, "documented but not routed"})
+		}
+	}
+
+	ids := map[string]bool{}
+	for _, rt := range routes {
+		var op *Operation
+		if annotation := used[rt]; annotation != nil {
+			op = c.operation(annotation, rt.path)
+		} else {
+			op = undocumentedOperation(rt.path)
+			drift = append(drift, Drift{rt.method, rt.path, "routed but not documented"})
+		}
+		// A handler serving several routes documents them all under its name
+		if op.OperationID == "" || ids[op.OperationID] {
+			op.OperationID = derivedOperationID(rt.method, rt.path)
+		}
+		ids[op.OperationID] = true
+
+		if doc.Paths[rt.path] == nil {
+			doc.Paths[rt.path] = PathItem{}
+		}
+		doc.Paths[rt.path][strings.ToLower(rt.method)] = op
+	}
+	return doc, drift, nil
+}
+
+// pathVariables returns the variable names of a path template in order
+func pathVariables(path string) []string {
+	var names []string
+	for _, part := range strings.Split(path, "/") {
+		if strings.HasPrefix(part, "{") && strings.HasSuffix(part, "}") {
+			names = append(names, part[1:len(part)-1])
+		}
+	}
+	return names
+}
+
+func undocumentedOperation(path string) *Operation {
+	op := &Operation{
+		Undocumented: true,
+		Responses:    map[string]*Response{"default": {Description: "Undocumented"}},
+	}
+	for _, name := range pathVariables(path) {
+		op.Parameters = append(op.Parameters, Parameter{Name: name, In: "path", Required: true, Schema: Schema{"type": "string"}})
+	}
+	return op
+}
+
+// derivedOperationID names an operation after its method and path:
+// GET /restaurants/{id}/map -> getRestaurantsByIdMap
+func derivedOperationID(method, path string) string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(method))
+	for _, part := range strings.Split(path, "/") {
+		if part == "" {
+			continue
+		}
+		if strings.HasPrefix(part, "{") {
+			b.WriteString("By")
+			part = strings.Trim(part, "{}")
+		}
+		for _, word := range splitWords(part) {
+			b.WriteString(strings.ToUpper(word[:1]) + word[1:])
+		}
+	}
+	return b.String()
+}
+
+// splitWords splits identifiers at dashes, underscores, dots and spaces
+func splitWords(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool {
+		return r == '-' || r == '_' || r == '.' || r == ' '
+	})
+}
@@ -0,0 +1,172 @@
+package openapi
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/gorilla/mux"
+)
+
+func noop(w http.ResponseWriter, r *http.Request) {}
+
+// annotated returns an operation annotation with an ID and a 200 response of schema
+func annotated(id string, schema *spec.Schema) *spec.Operation {
+	op := spec.NewOperation(id)
+	op.RespondsWith(200, spec.NewResponse().WithDescription("OK").WithSchema(schema))
+	return op
+}
+
+func testAnnotations() *spec.Swagger {
+	thing := spec.RefSchema("#/definitions/models.Thing")
+	return &spec.Swagger{SwaggerProps: spec.SwaggerProps{
+		Info:     &spec.Info{InfoProps: spec.InfoProps{Title: "Test API", Version: "1.0"}},
+		BasePath: "/api",
+		Paths: &spec.Paths{Paths: map[string]spec.PathItem{
+			"/things/{id}": {PathItemProps: spec.PathItemProps{
+				Get: annotated("getThing", thing),
+				Put: annotated("updateThing", thing), // Routed as PATCH
+			}},
+			"/gone": {PathItemProps: spec.PathItemProps{Get: annotated("getGone", nil)}},
+		}},
+		Definitions: spec.Definitions{
+			"models.Thing": *spec.StringProperty().WithDescription("A thing"),
+		},
+	}}
+}
+
+func testRouter() *mux.Router {
+	r := mux.NewRouter()
+	r.PathPrefix("/api/uploads/").HandlerFunc(noop).Methods("GET")
+	api := r.PathPrefix("/api").Subrouter()
+	api.HandleFunc("/things/{id:[0-9]+}", noop).Methods("GET", "HEAD")
+	api.HandleFunc("/things/{id}", noop).Methods("PATCH")
+	api.HandleFunc("/things/{id}/stats", noop).Methods("GET")
+	return r
+}
+
+func TestBuild_Drift(t *testing.T) {
+	doc, drift, err := Build(testRouter(), testAnnotations())
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	var messages []string
+	for _, d := range drift {
+		messages = append(messages, d.String())
+	}
+	want := []string{
+		"GET /gone: documented but not routed",
+		"PUT /things/{id}: documented as PUT but routed as PATCH",
+		"GET /things/{id}/stats: routed but not documented",
+	}
+	if strings.Join(messages, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Unexpected drift:\n%s\nwant:\n%s", strings.Join(messages, "\n"), strings.Join(want, "\n"))
+	}
+
+	// The router decides which operations exist
+	if len(doc.Paths) != 2 || doc.Paths["/gone"] != nil || doc.Paths["/uploads/"] != nil {
+		t.Errorf("Unexpected paths %v", sortedKeys(doc.Paths))
+	}
+	item := doc.Paths["/things/{id}"]
+	if item["head"] != nil || item["put"] != nil {
+		t.Errorf("Unexpected methods %v", sortedKeys(item))
+	}
+	if op := item["patch"]; op == nil || op.OperationID != "updateThing" {
+		t.Errorf("Expected the PUT annotation to document PATCH, got %+v", op)
+	}
+	stats := doc.Paths["/things/{id}/stats"]["get"]
+	if !stats.Undocumented || stats.OperationID != "getThingsByIdStats" || len(stats.Parameters) != 1 || stats.Parameters[0].Name != "id" {
+		t.Errorf("Unexpected undocumented operation %+v", stats)
+	}
+}
+
+func TestBuild_Schemas(t *testing.T) {
+	doc, _, err := Build(testRouter(), testAnnotations())
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	if _, ok := doc.Components.Schemas["Thing"]; !ok {
+		t.Errorf("Expected schemas named after their Go types, got %v", sortedKeys(doc.Components.Schemas))
+	}
+	response := doc.Paths["/things/{id}"]["get"].Responses["200"]
+	if ref := response.Content["application/json"].Schema["$ref"]; ref != "#/components/schemas/Thing" {
+		t.Errorf("Expected a reference to the component, got %v", ref)
+	}
+	// Path parameters the annotation left out are declared
+	if params := doc.Paths["/things/{id}"]["get"].Parameters; len(params) != 1 || params[0].In != "path" || !params[0].Required {
+		t.Errorf("Unexpected parameters %+v", params)
+	}
+}
+
+func TestConvertOperation(t *testing.T) {
+	c := newConverter(&spec.Swagger{SwaggerProps: spec.SwaggerProps{Definitions: spec.Definitions{
+		"errors.ErrorResponse": *spec.StringProperty(),
+	}}})
+	op := spec.NewOperation("uploadPhoto").
+		WithConsumes("multipart/form-data").
+		WithProduces("application/json").
+		AddParam(spec.PathParam("restaurantId").Typed("integer", "")).
+		AddParam(spec.FileParam("photo").AsRequired()).
+		AddParam(spec.FormDataParam("caption").Typed("string", "")).
+		RespondsWith(201, spec.NewResponse().WithDescription("Created").WithSchema(spec.StringProperty())).
+		RespondsWith(400, spec.NewResponse().WithDescription("Invalid").WithSchema(spec.RefSchema("#/definitions/errors.ErrorResponse")))
+
+	converted := c.operation(op, "/restaurants/{restaurantId}/photos")
+
+	form := converted.RequestBody.Content["multipart/form-data"].Schema
+	photo := properties(form)["photo"]
+	if photo["type"] != "string" || photo["format"] != "binary" {
+		t.Errorf("Expected the file as a binary string, got %v", photo)
+	}
+	if required := requiredProperties(form); !required["photo"] || required["caption"] {
+		t.Errorf("Unexpected required fields %v", form["required"])
+	}
+	if !converted.RequestBody.Required {
+		t.Error("Expected the form to be required")
+	}
+	if len(converted.Parameters) != 1 || converted.Parameters[0].Schema["type"] != "integer" {
+		t.Errorf("Unexpected parameters %+v", converted.Parameters)
+	}
+	// Errors are problem details, not the produced type
+	problem := converted.Responses["400"].Content["application/problem+json"].Schema
+	if problem["$ref"] != "#/components/schemas/ErrorResponse" {
+		t.Errorf("Unexpected error response %v", converted.Responses["400"].Content)
+	}
+}
+
+func TestConvertComponents_BearerAuth(t *testing.T) {
+	annotations := &spec.Swagger{SwaggerProps: spec.SwaggerProps{SecurityDefinitions: spec.SecurityDefinitions{
+		"BearerAuth": spec.APIKeyAuth("Authorization", "header"),
+		"ApiKeyAuth": spec.APIKeyAuth("X-API-Key", "header"),
+	}}}
+	schemes := newConverter(annotations).components().SecuritySchemes
+
+	if bearer := schemes["BearerAuth"]; bearer.Type != "http" || bearer.Scheme != "bearer" {
+		t.Errorf("Expected an Authorization API key to be a bearer token, got %+v", bearer)
+	}
+	if key := schemes["ApiKeyAuth"]; key.Type != "apiKey" || key.Name != "X-API-Key" || key.In != "header" {
+		t.Errorf("Unexpected API key scheme %+v", key)
+	}
+}
+
+func TestNames(t *testing.T) {
+	tests := []struct{ got, want string }{
+		{lowerFirst("CreateRestaurant"), "createRestaurant"},
+		{lowerFirst("APIKeys"), "apiKeys"},
+		{lowerFirst("OIDCLogin"), "oidcLogin"},
+		{derivedOperationID("GET", "/restaurants/{id}/map"), "getRestaurantsByIdMap"},
+		{derivedOperationID("POST", "/admin/photos/cleanup"), "postAdminPhotosCleanup"},
+		{goName("google_place_id"), "GooglePlaceID"},
+		{goName("restaurantId"), "RestaurantID"},
+		{goName("listAPIKeys"), "ListAPIKeys"},
+		{goParamName("type"), "typeParam"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("Got %q, want %q", tt.got, tt.want)
+		}
+	}
+}
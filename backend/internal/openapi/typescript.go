@@ -0,0 +1,313 @@
+package openapi
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// tsIdentifier matches property names that need no quotes
+var tsIdentifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+func tsProperty(name string) string {
+	if tsIdentifier.MatchString(name) {
+		return name
+	}
+	return fmt.Sprintf("%q", name)
+}
+
+// tsType returns the TypeScript type of a schema
+func tsType(s Schema) string {
+	t := tsBaseType(s)
+	if s["nullable"] == true {
+		t += " | null"
+	}
+	return t
+}
+
+func tsBaseType(s Schema) string {
+	if name, ok := schemaName(s); ok {
+		return name
+	}
+	if all, ok := s["allOf"].([]any); ok {
+		var parts []string
+		for _, part := range all {
+			if part, ok := part.(map[string]any); ok {
+				parts = append(parts, tsType(part))
+			}
+		}
+		return strings.Join(parts, " & ")
+	}
+	if enum, ok := s["enum"].([]any); ok && len(enum) > 0 {
+		var values []string
+		for _, value := range enum {
+			values = append(values, fmt.Sprintf("%#v", value))
+		}
+		return strings.Join(values, " | ")
+	}
+	switch s["type"] {
+	case "string":
+		if s["format"] == "binary" {
+			return "Blob"
+		}
+		return "string"
+	case "integer", "number":
+		return "number"
+	case "boolean":
+		return "boolean"
+	case "array":
+		items, _ := s["items"].(map[string]any)
+		item := tsType(items)
+		if strings.ContainsAny(item, " |&") {
+			item = "(" + item + ")"
+		}
+		return item + "[]"
+	case "object":
+		props := properties(s)
+		if len(props) == 0 {
+			if additional, ok := s["additionalProperties"].(map[string]any); ok {
+				return "Record<string, " + tsType(additional) + ">"
+			}
+			return "Record<string, unknown>"
+		}
+		return tsObject(s, "")
+	}
+	return "unknown"
+}
+
+// tsObject returns an object type literal of the properties of s
+func tsObject(s Schema, indent string) string {
+	required := requiredProperties(s)
+	var b strings.Builder
+	b.WriteString("{\n")
+	props := properties(s)
+	for _, name := range sortedKeys(props) {
+		prop := props[name]
+		if description, ok := prop["description"].(string); ok && description != "" {
+			fmt.Fprintf(&b, "%s  /** %s */\n", indent, tsComment(description))
+		}
+		optional := "?"
+		if required[name] {
+			optional = ""
+		}
+		fmt.Fprintf(&b, "%s  %s%s: %s;\n", indent, tsProperty(name), optional, tsType(prop))
+	}
+	b.WriteString(indent + "}")
+	return b.String()
+}
+
+func tsComment(text string) string {
+	return strings.ReplaceAll(strings.Join(strings.Fields(text), " "), "*/", "*\\/")
+}
+
+// TypeScript generates the TypeScript client module of doc
+func TypeScript(doc *Document) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "// %s\n", GeneratedHeader)
+	fmt.Fprintf(&b, "// %s %s (OpenAPI %s)\n\n", doc.Info.Title, doc.Info.Version, doc.OpenAPI)
+	b.WriteString(tsRuntime)
+
+	for _, name := range sortedKeys(doc.Components.Schemas) {
+		s := doc.Components.Schemas[name]
+		if description, ok := s["description"].(string); ok && description != "" {
+			fmt.Fprintf(&b, "/** %s */\n", tsComment(description))
+		}
+		if s["type"] == "object" && len(properties(s)) > 0 {
+			fmt.Fprintf(&b, "export interface %s %s\n\n", name, tsObject(s, ""))
+			continue
+		}
+		fmt.Fprintf(&b, "export type %s = %s;\n\n", name, tsType(s))
+	}
+
+	b.WriteString("/** Creates a client of the API. Every method rejects with an ApiError on non-2xx responses. */\n")
+	b.WriteString("export function createClient(options: ClientOptions = {}) {\n")
+	b.WriteString("  const request = newRequester(options);\n")
+	b.WriteString("  return {\n")
+	for _, op := range doc.operations() {
+		writeTSOperation(&b, op)
+	}
+	b.WriteString("  };\n}\n\n")
+	b.WriteString("export type Client = ReturnType<typeof createClient>;\n")
+	return []byte(b.String())
+}
+
+func writeTSOperation(b *strings.Builder, op documentedOperation) {
+	var fields []string
+	anyRequired := false
+	var pathParams, queryParams, headerParams []string
+	for _, param := range op.Parameters {
+		optional := "?"
+		if param.Required {
+			optional = ""
+			anyRequired = true
+		}
+		field := tsProperty(param.Name) + optional + ": " + tsType(param.Schema) + ";"
+		if param.Description != "" {
+			field = "/** " + tsComment(param.Description) + " */\n        " + field
+		}
+		fields = append(fields, field)
+		switch param.In {
+		case "path":
+			pathParams = append(pathParams, param.Name)
+		case "query":
+			queryParams = append(queryParams, param.Name)
+		case "header":
+			headerParams = append(headerParams, param.Name)
+		}
+	}
+	body := ""
+	if schema, ok := op.jsonBody(); ok {
+		optional := "?"
+		if op.RequestBody.Required {
+			optional = ""
+			anyRequired = true
+		}
+		fields = append(fields, "body"+optional+": "+tsType(schema)+";")
+		body = "json: args.body"
+	} else if _, schema, ok := op.formBody(); ok {
+		optional := "?"
+		if op.RequestBody.Required {
+			optional = ""
+			anyRequired = true
+		}
+		fields = append(fields, "form"+optional+": "+tsObject(schema, "        ")+";")
+		body = "form: args.form"
+	}
+
+	kind, schema := op.successResponse()
+	result := "void"
+	responseKind := "none"
+	switch kind {
+	case responseJSON:
+		result = tsType(schema)
+		responseKind = "json"
+	case responseRaw:
+		result = "Response"
+		responseKind = "raw"
+	}
+
+	path := op.Path
+	for _, name := range pathParams {
+		path = strings.ReplaceAll(path, "{"+name+"}", "${encodeURIComponent(String(args["+fmt.Sprintf("%q", name)+"]))}")
+	}
+
+	fmt.Fprintf(b, "    /** %s */\n", tsComment(op.summary()))
+	signature := "()"
+	if len(fields) > 0 {
+		defaultArgs := ""
+		if !anyRequired {
+			defaultArgs = " = {}"
+		}
+		signature = "(\n      args: {\n        " + strings.Join(fields, "\n        ") + "\n      }" + defaultArgs + ",\n    )"
+	}
+	fmt.Fprintf(b, "    %s: %s: Promise<%s> =>\n", op.OperationID, signature, result)
+	var init []string
+	if len(queryParams) > 0 {
+		var pairs []string
+		for _, name := range queryParams {
+			pairs = append(pairs, fmt.Sprintf("%q: args[%q]", name, name))
+		}
+		init = append(init, "query: { "+strings.Join(pairs, ", ")+" }")
+	}
+	if len(headerParams) > 0 {
+		var pairs []string
+		for _, name := range headerParams {
+			pairs = append(pairs, fmt.Sprintf("%q: args[%q]", name, name))
+		}
+		init = append(init, "headers: { "+strings.Join(pairs, ", ")+" }")
+	}
+	if body != "" {
+		init = append(init, body)
+	}
+	options := "{}"
+	if len(init) > 0 {
+		options = "{ " + strings.Join(init, ", ") + " }"
+	}
+	fmt.Fprintf(b, "      request(%q, `%s`, %q, %s) as Promise<%s>,\n", op.Method, path, responseKind, options, result)
+}
+
+// tsRuntime is the hand-written part of the TypeScript client
+const tsRuntime = `export interface ClientOptions {
+  /** Base URL of the API, including /api. Defaults to "/api" (same origin). */
+  baseUrl?: string;
+  /** fetch implementation, e.g. for Node.js < 18 or tests */
+  fetch?: typeof fetch;
+  /** Headers sent with every request, e.g. Authorization or X-API-Key */
+  headers?: Record<string, string> | (() => Record<string, string> | Promise<Record<string, string>>);
+  /** Credentials mode of requests; "include" sends cookies cross-origin */
+  credentials?: RequestCredentials;
+}
+
+/** A non-2xx response. problem holds the RFC 7807 problem details when the API sent them. */
+export class ApiError extends Error {
+  constructor(
+    readonly status: number,
+    readonly problem: ErrorResponse | undefined,
+    readonly response: Response,
+  ) {
+    super(problem?.detail || ` + "`HTTP ${status}`" + `);
+    this.name = "ApiError";
+  }
+}
+
+type QueryValue = string | number | boolean | null | undefined;
+
+interface RequestParts {
+  query?: Record<string, QueryValue>;
+  headers?: Record<string, QueryValue>;
+  json?: unknown;
+  form?: Record<string, unknown>;
+}
+
+function newRequester(options: ClientOptions) {
+  const baseUrl = (options.baseUrl ?? "/api").replace(/\/$/, "");
+  const fetchImpl = options.fetch ?? globalThis.fetch.bind(globalThis);
+
+  return async (method: string, path: string, kind: "json" | "raw" | "none", init: RequestParts): Promise<unknown> => {
+    const query = new URLSearchParams();
+    for (const [name, value] of Object.entries(init.query ?? {})) {
+      if (value !== undefined && value !== null) query.set(name, String(value));
+    }
+    const headers: Record<string, string> = {
+      ...(typeof options.headers === "function" ? await options.headers() : options.headers),
+    };
+    for (const [name, value] of Object.entries(init.headers ?? {})) {
+      if (value !== undefined && value !== null) headers[name] = String(value);
+    }
+
+    let body: BodyInit | undefined;
+    if (init.json !== undefined) {
+      headers["Content-Type"] = "application/json";
+      body = JSON.stringify(init.json);
+    } else if (init.form !== undefined) {
+      const form = new FormData();
+      for (const [name, value] of Object.entries(init.form)) {
+        if (value === undefined || value === null) continue;
+        form.append(name, value instanceof Blob ? value : String(value));
+      }
+      body = form;
+    }
+
+    const search = query.toString();
+    const response = await fetchImpl(baseUrl + path + (search ? "?" + search : ""), {
+      method,
+      headers,
+      body,
+      credentials: options.credentials,
+    });
+    if (!response.ok) {
+      let problem: ErrorResponse | undefined;
+      try {
+        problem = (await response.clone().json()) as ErrorResponse;
+      } catch {
+        problem = undefined;
+      }
+      throw new ApiError(response.status, problem, response);
+    }
+    if (kind === "raw") return response;
+    if (kind === "none" || response.status === 204) return undefined;
+    return response.json();
+  };
+}
+
+`
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/nomdb/backend/internal/models"
+)
+
+// BadgeProgress counts the contributions of a user that badges are awarded for, across
+// all spaces
+type BadgeProgress struct {
+	Ratings              int
+	FoodTypes            int // Distinct food types of the rated restaurants
+	ConvertedSuggestions int
+}
+
+// BadgeRepository reads and writes the badges awarded to users
+type BadgeRepository interface {
+	// Progress counts the contributions of a user that badges are awarded for
+	Progress(ctx context.Context, userID int) (*BadgeProgress, error)
+	// Award gives a user the badges it does not have yet, and returns those newly awarded.
+	// Service accounts are not awarded badges.
+	Award(ctx context.Context, userID int, badges []string) ([]string, error)
+	// ListByUser returns the badges of a user by key and award time, oldest first
+	ListByUser(ctx context.Context, userID int) ([]models.UserBadge, error)
+}
+
+type badgeRepo struct {
+	db DB
+}
+
+func (r *badgeRepo) Progress(ctx context.Context, userID int) (*BadgeProgress, error) {
+	var p BadgeProgress
+	err := r.db.QueryRow(ctx,
+		`SELECT
+			(SELECT COUNT(*) FROM ratings WHERE user_id = $1),
+			(SELECT COUNT(DISTINCT rft.food_type_id)
+				FROM ratings rt
+				JOIN restaurant_food_types rft ON rft.restaurant_id = rt.restaurant_id
+				WHERE rt.user_id = $1),
+			(SELECT COUNT(*) FROM restaurants WHERE suggested_by_user_id = $1)`, userID).
+		Scan(&p.Ratings, &p.FoodTypes, &p.ConvertedSuggestions)
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (r *badgeRepo) Award(ctx context.Context, userID int, badges []string) ([]string, error) {
+	rows, err := r.db.Query(ctx,
+		`INSERT INTO user_badges (user_id, badge)
+		SELECT u.id, b.badge FROM users u, unnest($2::text[]) AS b(badge)
+		WHERE u.id = $1 AND u.provider <> 'service'
+		ON CONFLICT DO NOTHING
+		RETURNING badge`, userID, badges)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	awarded := []string{}
+	for rows.Next() {
+		var badge string
+		if err := rows.Scan(&badge); err != nil {
+			return nil, err
+		}
+		awarded = append(awarded, badge)
+	}
+	return awarded, rows.Err()
+}
+
+func (r *badgeRepo) ListByUser(ctx context.Context, userID int) ([]models.UserBadge, error) {
+	rows, err := r.db.Query(ctx,
+		"SELECT badge, awarded_at FROM user_badges WHERE user_id = $1 ORDER BY awarded_at, badge", userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	badges := []models.UserBadge{}
+	for rows.Next() {
+		var b models.UserBadge
+		if err := rows.Scan(&b.Key, &b.AwardedAt); err != nil {
+			return nil, err
+		}
+		badges = append(badges, b)
+	}
+	return badges, rows.Err()
+}
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pashagolub/pgxmock/v4"
+)
+
+func TestBadgesProgress(t *testing.T) {
+	mock, repos := newMock(t)
+	mock.ExpectQuery(`COUNT\(DISTINCT rft.food_type_id\)`).WithArgs(8).
+		WillReturnRows(pgxmock.NewRows([]string{"ratings", "food_types", "converted"}).AddRow(14, 6, 2))
+
+	progress, err := repos.Badges.Progress(context.Background(), 8)
+	if err != nil {
+		t.Fatalf("Progress failed: %v", err)
+	}
+	if *progress != (BadgeProgress{Ratings: 14, FoodTypes: 6, ConvertedSuggestions: 2}) {
+		t.Errorf("Unexpected progress %+v", progress)
+	}
+}
+
+func TestBadgesAward_OnlyNewBadges(t *testing.T) {
+	mock, repos := newMock(t)
+	// first_rating was already awarded, so only cuisine_explorer is returned
+	mock.ExpectQuery(`INSERT INTO user_badges`).WithArgs(8, []string{"first_rating", "cuisine_explorer"}).
+		WillReturnRows(pgxmock.NewRows([]string{"badge"}).AddRow("cuisine_explorer"))
+
+	awarded, err := repos.Badges.Award(context.Background(), 8, []string{"first_rating", "cuisine_explorer"})
+	if err != nil {
+		t.Fatalf("Award failed: %v", err)
+	}
+	if len(awarded) != 1 || awarded[0] != "cuisine_explorer" {
+		t.Errorf("Expected only cuisine_explorer to be new, got %v", awarded)
+	}
+}
+
+func TestBadgesListByUser(t *testing.T) {
+	mock, repos := newMock(t)
+	now := time.Now()
+	mock.ExpectQuery(`FROM user_badges WHERE user_id = \$1`).WithArgs(8).
+		WillReturnRows(pgxmock.NewRows([]string{"badge", "awarded_at"}).AddRow("first_rating", now))
+
+	badges, err := repos.Badges.ListByUser(context.Background(), 8)
+	if err != nil {
+		t.Fatalf("ListByUser failed: %v", err)
+	}
+	if len(badges) != 1 || badges[0].Key != "first_rating" || !badges[0].AwardedAt.Equal(now) {
+		t.Errorf("Unexpected badges %+v", badges)
+	}
+}
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/nomdb/backend/internal/models"
+)
+
+// ExternalSourceRepository stores review site data of restaurants
+type ExternalSourceRepository interface {
+	// ForRestaurant returns the data of a restaurant, by provider name
+	ForRestaurant(ctx context.Context, restaurantID int) ([]models.ExternalSource, error)
+	// Upsert stores the data of a restaurant, replacing earlier data of the same provider
+	Upsert(ctx context.Context, restaurantID int, source *models.ExternalSource) error
+	// Delete removes the data of a provider from a restaurant, or returns ErrNotFound
+	Delete(ctx context.Context, restaurantID int, provider string) error
+}
+
+type externalSourceRepo struct {
+	db DB
+}
+
+func (r *externalSourceRepo) ForRestaurant(ctx context.Context, restaurantID int) ([]models.ExternalSource, error) {
+	rows, err := r.db.Query(ctx,
+		`SELECT provider, external_id, name, url, rating, rating_count, price_level, categories, fetched_at
+		FROM external_sources
+		WHERE restaurant_id = $1
+		ORDER BY provider`, restaurantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sources []models.ExternalSource
+	for rows.Next() {
+		var s models.ExternalSource
+		if err := rows.Scan(&s.Provider, &s.ExternalID, &s.Name, &s.URL, &s.Rating, &s.RatingCount,
+			&s.PriceLevel, &s.Categories, &s.FetchedAt); err != nil {
+			return nil, err
+		}
+		sources = append(sources, s)
+	}
+	return sources, rows.Err()
+}
+
+func (r *externalSourceRepo) Upsert(ctx context.Context, restaurantID int, s *models.ExternalSource) error {
+	_, err := r.db.Exec(ctx,
+		`INSERT INTO external_sources
+			(restaurant_id, provider, external_id, name, url, rating, rating_count, price_level, categories, fetched_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT (restaurant_id, provider) DO UPDATE SET
+			external_id = EXCLUDED.external_id, name = EXCLUDED.name, url = EXCLUDED.url,
+			rating = EXCLUDED.rating, rating_count = EXCLUDED.rating_count, price_level = EXCLUDED.price_level,
+			categories = EXCLUDED.categories, fetched_at = EXCLUDED.fetched_at`,
+		restaurantID, s.Provider, s.ExternalID, s.Name, s.URL, s.Rating, s.RatingCount, s.PriceLevel, s.Categories, s.FetchedAt)
+	return err
+}
+
+func (r *externalSourceRepo) Delete(ctx context.Context, restaurantID int, provider string) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM external_sources WHERE restaurant_id = $1 AND provider = $2`, restaurantID, provider)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
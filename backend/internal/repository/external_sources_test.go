@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pashagolub/pgxmock/v4"
+)
+
+func TestExternalSourcesForRestaurant(t *testing.T) {
+	mock, repos := newMock(t)
+	now := time.Now()
+	url, rating, count := "https://www.yelp.com/biz/luigis", 4.5, 120
+
+	mock.ExpectQuery(`FROM external_sources`).WithArgs(3).
+		WillReturnRows(pgxmock.NewRows([]string{"provider", "external_id", "name", "url", "rating", "rating_count", "price_level", "categories", "fetched_at"}).
+			AddRow("yelp", "luigis", "Luigi's", &url, &rating, &count, nil, []string{"Pizza"}, now))
+
+	sources, err := repos.ExternalSources.ForRestaurant(context.Background(), 3)
+	if err != nil {
+		t.Fatalf("ForRestaurant failed: %v", err)
+	}
+	if len(sources) != 1 || sources[0].Provider != "yelp" || *sources[0].Rating != 4.5 || sources[0].PriceLevel != nil {
+		t.Errorf("Unexpected sources %+v", sources)
+	}
+}
+
+func TestExternalSourcesDelete_NotFound(t *testing.T) {
+	mock, repos := newMock(t)
+
+	mock.ExpectExec(`DELETE FROM external_sources`).WithArgs(3, "yelp").
+		WillReturnResult(pgxmock.NewResult("DELETE", 0))
+
+	if err := repos.ExternalSources.Delete(context.Background(), 3, "yelp"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/nomdb/backend/internal/models"
+)
+
+// ActivityFilter selects the ratings and suggestions of the activity feed
+type ActivityFilter struct {
+	SpaceID    int
+	FollowerID int        // Only activity of the users this user follows, 0 for everyone's
+	Before     *time.Time // Only activity older than this, for the next page
+	Limit      int
+}
+
+// FollowRepository reads and writes who follows whom, and the activity feed
+type FollowRepository interface {
+	// Follow makes followerID follow userID, which it may already; ErrNotFound if the
+	// user does not exist
+	Follow(ctx context.Context, followerID, userID int) error
+	// Unfollow stops followerID following userID; ErrNotFound if it did not
+	Unfollow(ctx context.Context, followerID, userID int) error
+	// Following returns the users followerID follows, by username
+	Following(ctx context.Context, followerID int) ([]models.FollowedUser, error)
+	// Feed returns up to filter.Limit ratings and suggestions of the space by users with
+	// an account, newest first
+	Feed(ctx context.Context, filter ActivityFilter) ([]models.ActivityItem, error)
+}
+
+type followRepo struct {
+	db DB
+}
+
+func (r *followRepo) Follow(ctx context.Context, followerID, userID int) error {
+	_, err := r.db.Exec(ctx,
+		`INSERT INTO user_follows (follower_id, followee_id) VALUES ($1, $2)
+		ON CONFLICT DO NOTHING`, followerID, userID)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23503" { // foreign_key_violation
+		return ErrNotFound
+	}
+	return err
+}
+
+func (r *followRepo) Unfollow(ctx context.Context, followerID, userID int) error {
+	result, err := r.db.Exec(ctx,
+		"DELETE FROM user_follows WHERE follower_id = $1 AND followee_id = $2", followerID, userID)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *followRepo) Following(ctx context.Context, followerID int) ([]models.FollowedUser, error) {
+	rows, err := r.db.Query(ctx,
+		`SELECT u.id, u.username, u.avatar_url, f.created_at
+		FROM user_follows f
+		JOIN users u ON u.id = f.followee_id
+		WHERE f.follower_id = $1
+		ORDER BY LOWER(u.username)`, followerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := []models.FollowedUser{}
+	for rows.Next() {
+		var u models.FollowedUser
+		if err := rows.Scan(&u.ID, &u.Username, &u.AvatarURL, &u.FollowedAt); err != nil {
+			return nil, err
+		}
+		users = append(users, u)
+	}
+	return users, rows.Err()
+}
+
+func (r *followRepo) Feed(ctx context.Context, filter ActivityFilter) ([]models.ActivityItem, error) {
+	rows, err := r.db.Query(ctx,
+		`SELECT type, id, user_id, username, restaurant_id, name, score, comment, status, created_at
+		FROM (
+			SELECT 'rating' AS type, rt.id, rt.user_id, u.username, rt.restaurant_id, r.name,
+				(rt.food_rating + rt.service_rating + rt.ambiance_rating) / 3.0::float8 AS score,
+				rt.comment, NULL::varchar AS status, rt.created_at
+			FROM ratings rt
+			JOIN restaurants r ON r.id = rt.restaurant_id
+			JOIN users u ON u.id = rt.user_id
+			WHERE r.space_id = $1
+			UNION ALL
+			SELECT 'suggestion', s.id, s.user_id, u.username, NULL::integer, s.name,
+				NULL::float8, s.notes, s.status, s.created_at
+			FROM restaurant_suggestions s
+			JOIN users u ON u.id = s.user_id
+			WHERE s.space_id = $1
+		) activity
+		WHERE ($2::integer = 0 OR user_id IN (SELECT followee_id FROM user_follows WHERE follower_id = $2))
+			AND ($3::timestamptz IS NULL OR created_at < $3)
+		ORDER BY created_at DESC, type, id DESC
+		LIMIT $4`,
+		filter.SpaceID, filter.FollowerID, filter.Before, filter.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []models.ActivityItem{}
+	for rows.Next() {
+		var item models.ActivityItem
+		if err := rows.Scan(&item.Type, &item.ID, &item.UserID, &item.Username, &item.RestaurantID, &item.Name,
+			&item.Score, &item.Comment, &item.Status, &item.CreatedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
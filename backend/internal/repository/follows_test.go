@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/pashagolub/pgxmock/v4"
+)
+
+func TestFollowsFollow(t *testing.T) {
+	mock, repos := newMock(t)
+	mock.ExpectExec(`INSERT INTO user_follows`).WithArgs(7, 8).WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	mock.ExpectExec(`INSERT INTO user_follows`).WithArgs(7, 99).
+		WillReturnError(&pgconn.PgError{Code: "23503", ConstraintName: "user_follows_followee_id_fkey"})
+
+	if err := repos.Follows.Follow(context.Background(), 7, 8); err != nil {
+		t.Fatalf("Follow failed: %v", err)
+	}
+	if err := repos.Follows.Follow(context.Background(), 7, 99); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for a missing user, got %v", err)
+	}
+}
+
+func TestFollowsUnfollow_NotFollowing(t *testing.T) {
+	mock, repos := newMock(t)
+	mock.ExpectExec(`DELETE FROM user_follows`).WithArgs(7, 8).WillReturnResult(pgxmock.NewResult("DELETE", 0))
+
+	if err := repos.Follows.Unfollow(context.Background(), 7, 8); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestFollowsFeed(t *testing.T) {
+	mock, repos := newMock(t)
+	now := time.Now()
+	before := now.Add(time.Hour)
+	restaurantID := 3
+	score := 4.0
+	status := "pending"
+
+	// Limited to followed users, older than the previous page
+	mock.ExpectQuery(`FROM user_follows WHERE follower_id = \$2`).WithArgs(1, 7, &before, 20).
+		WillReturnRows(pgxmock.NewRows([]string{"type", "id", "user_id", "username", "restaurant_id", "name", "score", "comment", "status", "created_at"}).
+			AddRow("rating", 5, 8, "anna", &restaurantID, "Luigi's", &score, nil, nil, now).
+			AddRow("suggestion", 2, 9, "ben", nil, "Pho 99", nil, nil, &status, now.Add(-time.Minute)))
+
+	items, err := repos.Follows.Feed(context.Background(), ActivityFilter{SpaceID: 1, FollowerID: 7, Before: &before, Limit: 20})
+	if err != nil {
+		t.Fatalf("Feed failed: %v", err)
+	}
+	if len(items) != 2 || items[0].Type != "rating" || *items[0].Score != 4 || items[1].RestaurantID != nil || *items[1].Status != status {
+		t.Errorf("Unexpected feed %+v", items)
+	}
+}
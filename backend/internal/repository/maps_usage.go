@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/nomdb/backend/internal/models"
+)
+
+// MapsUsageRepository counts Google Maps calls per UTC day and operation. It implements
+// services.MapsUsageStore.
+type MapsUsageRepository interface {
+	// Record counts a call of operation on day, returning the calls of all operations that day
+	Record(ctx context.Context, day time.Time, operation string) (int, error)
+	// Total returns the calls of all operations on day
+	Total(ctx context.Context, day time.Time) (int, error)
+	// Daily returns the calls per day since the given day, newest first
+	Daily(ctx context.Context, since time.Time) ([]models.MapsUsageDay, error)
+}
+
+type mapsUsageRepo struct {
+	db DB
+}
+
+func (r *mapsUsageRepo) Record(ctx context.Context, day time.Time, operation string) (int, error) {
+	// The updated row is not visible to the subquery, so its count is added separately
+	var calls int
+	err := r.db.QueryRow(ctx,
+		`WITH counted AS (
+			INSERT INTO maps_api_usage (day, operation, calls) VALUES ($1, $2, 1)
+			ON CONFLICT (day, operation) DO UPDATE SET calls = maps_api_usage.calls + 1
+			RETURNING calls
+		)
+		SELECT counted.calls + COALESCE((SELECT SUM(calls) FROM maps_api_usage WHERE day = $1 AND operation <> $2), 0)
+		FROM counted`, day, operation).Scan(&calls)
+	return calls, err
+}
+
+func (r *mapsUsageRepo) Total(ctx context.Context, day time.Time) (int, error) {
+	var calls int
+	err := r.db.QueryRow(ctx, `SELECT COALESCE(SUM(calls), 0) FROM maps_api_usage WHERE day = $1`, day).Scan(&calls)
+	return calls, err
+}
+
+func (r *mapsUsageRepo) Daily(ctx context.Context, since time.Time) ([]models.MapsUsageDay, error) {
+	rows, err := r.db.Query(ctx,
+		`SELECT day, operation, calls FROM maps_api_usage
+		WHERE day >= $1
+		ORDER BY day DESC, operation`, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	days := []models.MapsUsageDay{}
+	for rows.Next() {
+		var day time.Time
+		var operation string
+		var calls int
+		if err := rows.Scan(&day, &operation, &calls); err != nil {
+			return nil, err
+		}
+		date := day.Format(time.DateOnly)
+		if len(days) == 0 || days[len(days)-1].Date != date {
+			days = append(days, models.MapsUsageDay{Date: date, Operations: map[string]int{}})
+		}
+		days[len(days)-1].Calls += calls
+		days[len(days)-1].Operations[operation] = calls
+	}
+	return days, rows.Err()
+}
@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pashagolub/pgxmock/v4"
+)
+
+func TestMapsUsageRecord(t *testing.T) {
+	mock, repos := newMock(t)
+	day := time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(`INSERT INTO maps_api_usage .* ON CONFLICT \(day, operation\) DO UPDATE`).
+		WithArgs(day, "text_search").
+		WillReturnRows(pgxmock.NewRows([]string{"calls"}).AddRow(12))
+
+	calls, err := repos.MapsUsage.Record(context.Background(), day, "text_search")
+	if err != nil || calls != 12 {
+		t.Errorf("Unexpected result %d (%v)", calls, err)
+	}
+}
+
+func TestMapsUsageDaily(t *testing.T) {
+	mock, repos := newMock(t)
+	since := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	today, yesterday := time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC), time.Date(2026, 10, 14, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(`FROM maps_api_usage`).WithArgs(since).
+		WillReturnRows(pgxmock.NewRows([]string{"day", "operation", "calls"}).
+			AddRow(today, "place_details", 3).
+			AddRow(today, "text_search", 5).
+			AddRow(yesterday, "text_search", 1))
+
+	days, err := repos.MapsUsage.Daily(context.Background(), since)
+	if err != nil {
+		t.Fatalf("Daily failed: %v", err)
+	}
+	if len(days) != 2 || days[0].Date != "2026-10-15" || days[0].Calls != 8 || days[0].Operations["place_details"] != 3 ||
+		days[1].Date != "2026-10-14" || days[1].Calls != 1 {
+		t.Errorf("Unexpected days %+v", days)
+	}
+}
@@ -0,0 +1,193 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/nomdb/backend/internal/database"
+	"github.com/nomdb/backend/internal/models"
+)
+
+// Place change states, mirroring the CHECK constraint on restaurant_place_changes.status
+const (
+	PlaceChangePending  = "pending"
+	PlaceChangeAccepted = "accepted"
+	PlaceChangeRejected = "rejected"
+)
+
+// PlaceChangeRepository reads and writes the changes proposed by the place sync
+type PlaceChangeRepository interface {
+	// DueForSync returns up to limit restaurants with a Google Place ID, those never or
+	// least recently synced first
+	DueForSync(ctx context.Context, limit int) ([]models.Restaurant, error)
+	// Record makes changes the pending changes of a restaurant, replacing pending changes
+	// of the same fields and dropping those of other fields, and marks it synced. Changes
+	// to a value an admin already rejected are skipped. It returns the number of changes
+	// that are new or have a new value.
+	Record(ctx context.Context, restaurantID int, changes []models.PlaceChange) (int, error)
+	// MarkSynced moves a restaurant to the back of the sync order without recording changes
+	MarkSynced(ctx context.Context, restaurantID int) error
+	// List returns changes with the name of their restaurant, oldest first, optionally
+	// only those with status
+	List(ctx context.Context, status string) ([]models.PlaceChange, error)
+	// Review accepts or rejects a pending change by reviewerID; accepting applies it to
+	// the restaurant. ErrNotFound is returned if there is no pending change with id.
+	Review(ctx context.Context, id int, accept bool, reviewerID *int) (*models.PlaceChange, error)
+}
+
+type placeChangeRepo struct {
+	db DB
+}
+
+const placeChangeColumns = `pc.id, pc.restaurant_id, r.name, pc.field, pc.old_value, pc.new_value, pc.status, pc.reviewed_by_user_id, pc.reviewed_at, pc.created_at`
+
+func scanPlaceChange(row interface{ Scan(...any) error }, pc *models.PlaceChange) error {
+	return row.Scan(&pc.ID, &pc.RestaurantID, &pc.RestaurantName, &pc.Field, &pc.OldValue, &pc.NewValue,
+		&pc.Status, &pc.ReviewedByUserID, &pc.ReviewedAt, &pc.CreatedAt)
+}
+
+func (r *placeChangeRepo) DueForSync(ctx context.Context, limit int) ([]models.Restaurant, error) {
+	rows, err := r.db.Query(ctx,
+		`SELECT id, name, description, address, phone, website, latitude, longitude,
+			google_place_id, category_id, created_at, updated_at, business_status, opening_hours
+		FROM restaurants
+		WHERE google_place_id IS NOT NULL
+		ORDER BY place_synced_at NULLS FIRST, id
+		LIMIT $1`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	restaurants := []models.Restaurant{}
+	for rows.Next() {
+		var rest models.Restaurant
+		if err := scanRestaurant(rows, &rest); err != nil {
+			return nil, err
+		}
+		restaurants = append(restaurants, rest)
+	}
+	return restaurants, rows.Err()
+}
+
+func (r *placeChangeRepo) Record(ctx context.Context, restaurantID int, changes []models.PlaceChange) (int, error) {
+	fields := []string{}
+	for _, change := range changes {
+		fields = append(fields, change.Field)
+	}
+
+	recorded := 0
+	err := database.RunInTx(ctx, r.db, func(tx pgx.Tx) error {
+		recorded = 0
+		if _, err := tx.Exec(ctx,
+			`DELETE FROM restaurant_place_changes
+			WHERE restaurant_id = $1 AND status = 'pending' AND NOT (field = ANY($2::text[]))`,
+			restaurantID, fields); err != nil {
+			return err
+		}
+		for _, change := range changes {
+			result, err := tx.Exec(ctx,
+				`INSERT INTO restaurant_place_changes (restaurant_id, field, old_value, new_value)
+				SELECT $1::integer, $2::varchar, $3::jsonb, $4::jsonb
+				WHERE NOT EXISTS (
+					SELECT 1 FROM restaurant_place_changes
+					WHERE restaurant_id = $1 AND field = $2 AND status = 'rejected' AND new_value = $4
+				)
+				ON CONFLICT (restaurant_id, field) WHERE status = 'pending' DO UPDATE
+				SET old_value = EXCLUDED.old_value, new_value = EXCLUDED.new_value, created_at = NOW()
+				WHERE restaurant_place_changes.new_value IS DISTINCT FROM EXCLUDED.new_value`,
+				restaurantID, change.Field, change.OldValue, change.NewValue)
+			if err != nil {
+				return err
+			}
+			recorded += int(result.RowsAffected())
+		}
+		_, err := tx.Exec(ctx, "UPDATE restaurants SET place_synced_at = NOW() WHERE id = $1", restaurantID)
+		return err
+	})
+	return recorded, err
+}
+
+func (r *placeChangeRepo) MarkSynced(ctx context.Context, restaurantID int) error {
+	_, err := r.db.Exec(ctx, "UPDATE restaurants SET place_synced_at = NOW() WHERE id = $1", restaurantID)
+	return err
+}
+
+func (r *placeChangeRepo) List(ctx context.Context, status string) ([]models.PlaceChange, error) {
+	rows, err := r.db.Query(ctx,
+		`SELECT `+placeChangeColumns+`
+		FROM restaurant_place_changes pc
+		JOIN restaurants r ON r.id = pc.restaurant_id
+		WHERE $1 = '' OR pc.status = $1
+		ORDER BY pc.created_at, pc.id`, status)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	changes := []models.PlaceChange{}
+	for rows.Next() {
+		var change models.PlaceChange
+		if err := scanPlaceChange(rows, &change); err != nil {
+			return nil, err
+		}
+		changes = append(changes, change)
+	}
+	return changes, rows.Err()
+}
+
+func (r *placeChangeRepo) Review(ctx context.Context, id int, accept bool, reviewerID *int) (*models.PlaceChange, error) {
+	status := PlaceChangeRejected
+	if accept {
+		status = PlaceChangeAccepted
+	}
+
+	var change models.PlaceChange
+	err := database.RunInTx(ctx, r.db, func(tx pgx.Tx) error {
+		err := scanPlaceChange(tx.QueryRow(ctx,
+			`UPDATE restaurant_place_changes pc
+			SET status = $2, reviewed_by_user_id = $3, reviewed_at = NOW()
+			FROM restaurants r
+			WHERE pc.id = $1 AND pc.status = 'pending' AND r.id = pc.restaurant_id
+			RETURNING `+placeChangeColumns, id, status, reviewerID), &change)
+		if err != nil {
+			return notFound(err)
+		}
+		if !accept {
+			return nil
+		}
+		return applyPlaceChange(ctx, tx, change)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &change, nil
+}
+
+// applyPlaceChange writes the new value of a change to its restaurant
+func applyPlaceChange(ctx context.Context, db DB, change models.PlaceChange) error {
+	var value any
+	switch change.Field {
+	case "phone", "website", "business_status":
+		var s *string
+		if err := json.Unmarshal(change.NewValue, &s); err != nil {
+			return fmt.Errorf("invalid %s value: %w", change.Field, err)
+		}
+		value = s
+	case "opening_hours":
+		var lines []string
+		if err := json.Unmarshal(change.NewValue, &lines); err != nil {
+			return fmt.Errorf("invalid %s value: %w", change.Field, err)
+		}
+		value = lines
+	default:
+		return fmt.Errorf("unknown place change field %q", change.Field)
+	}
+
+	_, err := db.Exec(ctx, fmt.Sprintf(
+		"UPDATE restaurants SET %s = $1, updated_at = NOW() WHERE id = $2", change.Field),
+		value, change.RestaurantID)
+	return err
+}
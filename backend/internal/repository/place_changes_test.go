@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v4"
+)
+
+var placeChangeColumnNames = []string{
+	"id", "restaurant_id", "name", "field", "old_value", "new_value", "status", "reviewed_by_user_id", "reviewed_at", "created_at",
+}
+
+func TestPlaceChangesReview_Accept(t *testing.T) {
+	mock, repos := newMock(t)
+	now := time.Now()
+	reviewer := 7
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`UPDATE restaurant_place_changes pc`).WithArgs(4, PlaceChangeAccepted, &reviewer).
+		WillReturnRows(pgxmock.NewRows(placeChangeColumnNames).
+			AddRow(4, 3, "Luigi's", "opening_hours", json.RawMessage(nil), json.RawMessage(`["Monday: Closed"]`),
+				PlaceChangeAccepted, &reviewer, &now, now))
+	mock.ExpectExec(`UPDATE restaurants SET opening_hours = \$1`).WithArgs([]string{"Monday: Closed"}, 3).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	mock.ExpectCommit()
+
+	change, err := repos.PlaceChanges.Review(context.Background(), 4, true, &reviewer)
+	if err != nil {
+		t.Fatalf("Review failed: %v", err)
+	}
+	if change.RestaurantName != "Luigi's" || change.Status != PlaceChangeAccepted {
+		t.Errorf("Unexpected change %+v", change)
+	}
+}
+
+func TestPlaceChangesReview_Reject(t *testing.T) {
+	mock, repos := newMock(t)
+	now := time.Now()
+
+	// Rejecting leaves the restaurant as it is
+	mock.ExpectBegin()
+	mock.ExpectQuery(`UPDATE restaurant_place_changes pc`).WithArgs(4, PlaceChangeRejected, (*int)(nil)).
+		WillReturnRows(pgxmock.NewRows(placeChangeColumnNames).
+			AddRow(4, 3, "Luigi's", "phone", json.RawMessage(`"030 123"`), json.RawMessage(`"+49 30 123"`),
+				PlaceChangeRejected, nil, &now, now))
+	mock.ExpectCommit()
+
+	if _, err := repos.PlaceChanges.Review(context.Background(), 4, false, nil); err != nil {
+		t.Fatalf("Review failed: %v", err)
+	}
+}
+
+func TestPlaceChangesReview_NotPending(t *testing.T) {
+	mock, repos := newMock(t)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`UPDATE restaurant_place_changes pc`).WithArgs(4, PlaceChangeAccepted, (*int)(nil)).
+		WillReturnError(pgx.ErrNoRows)
+	mock.ExpectRollback()
+
+	if _, err := repos.PlaceChanges.Review(context.Background(), 4, true, nil); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestPlaceChangesRecord_NoChanges(t *testing.T) {
+	mock, repos := newMock(t)
+
+	// Pending changes the place no longer differs in are dropped
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM restaurant_place_changes`).WithArgs(3, []string{}).
+		WillReturnResult(pgxmock.NewResult("DELETE", 1))
+	mock.ExpectExec(`UPDATE restaurants SET place_synced_at`).WithArgs(3).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	mock.ExpectCommit()
+
+	recorded, err := repos.PlaceChanges.Record(context.Background(), 3, nil)
+	if err != nil || recorded != 0 {
+		t.Errorf("Unexpected result %d (%v)", recorded, err)
+	}
+}
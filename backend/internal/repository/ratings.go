@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/nomdb/backend/internal/models"
+)
+
+// RatingRepository reads and writes restaurant ratings
+type RatingRepository interface {
+	// ListByRestaurant returns the ratings of a restaurant, newest first
+	ListByRestaurant(ctx context.Context, restaurantID int) ([]models.Rating, error)
+	// Create stores a rating by authorID (nil for anonymous ratings)
+	Create(ctx context.Context, req models.CreateRatingRequest, authorID *int) (*models.Rating, error)
+	// AuthorID returns the author of a rating, nil if none was recorded
+	AuthorID(ctx context.Context, id int) (*int, error)
+	// RestaurantID returns the restaurant of a rating; ErrNotFound if there is none
+	RestaurantID(ctx context.Context, id int) (int, error)
+	// SetOwnerResponse sets the owner's response to a rating, or removes it if response
+	// is nil; ErrNotFound if the rating does not exist
+	SetOwnerResponse(ctx context.Context, id int, response *string) (*models.Rating, error)
+	// Delete deletes a rating and returns its restaurant
+	Delete(ctx context.Context, id int) (int, error)
+}
+
+type ratingRepo struct {
+	db DB
+}
+
+const ratingColumns = `id, restaurant_id, food_rating, service_rating, ambiance_rating, comment, user_id, created_at, owner_response, owner_response_at`
+
+func scanRating(row interface{ Scan(...any) error }, rt *models.Rating) error {
+	var response *string
+	var respondedAt *time.Time
+	if err := row.Scan(&rt.ID, &rt.RestaurantID, &rt.FoodRating, &rt.ServiceRating, &rt.AmbianceRating, &rt.Comment, &rt.UserID, &rt.CreatedAt,
+		&response, &respondedAt); err != nil {
+		return err
+	}
+	if response != nil && respondedAt != nil {
+		rt.OwnerResponse = &models.OwnerResponse{Response: *response, RespondedAt: *respondedAt}
+	}
+	return nil
+}
+
+func (r *ratingRepo) ListByRestaurant(ctx context.Context, restaurantID int) ([]models.Rating, error) {
+	rows, err := r.db.Query(ctx,
+		`SELECT `+ratingColumns+` FROM ratings WHERE restaurant_id = $1 ORDER BY created_at DESC`, restaurantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ratings := []models.Rating{}
+	for rows.Next() {
+		var rt models.Rating
+		if err := scanRating(rows, &rt); err != nil {
+			return nil, err
+		}
+		ratings = append(ratings, rt)
+	}
+	return ratings, rows.Err()
+}
+
+func (r *ratingRepo) Create(ctx context.Context, req models.CreateRatingRequest, authorID *int) (*models.Rating, error) {
+	var rt models.Rating
+	err := scanRating(r.db.QueryRow(ctx,
+		`INSERT INTO ratings (restaurant_id, food_rating, service_rating, ambiance_rating, comment, user_id)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING `+ratingColumns,
+		req.RestaurantID, req.FoodRating, req.ServiceRating, req.AmbianceRating, req.Comment, authorID,
+	), &rt)
+	if err != nil {
+		return nil, err
+	}
+	return &rt, nil
+}
+
+func (r *ratingRepo) AuthorID(ctx context.Context, id int) (*int, error) {
+	var authorID *int
+	if err := r.db.QueryRow(ctx, "SELECT user_id FROM ratings WHERE id = $1", id).Scan(&authorID); err != nil {
+		return nil, notFound(err)
+	}
+	return authorID, nil
+}
+
+func (r *ratingRepo) RestaurantID(ctx context.Context, id int) (int, error) {
+	var restaurantID int
+	err := r.db.QueryRow(ctx, "SELECT restaurant_id FROM ratings WHERE id = $1", id).Scan(&restaurantID)
+	return restaurantID, notFound(err)
+}
+
+func (r *ratingRepo) SetOwnerResponse(ctx context.Context, id int, response *string) (*models.Rating, error) {
+	var rt models.Rating
+	err := scanRating(r.db.QueryRow(ctx,
+		`UPDATE ratings SET
+			owner_response = $2::text,
+			owner_response_at = CASE WHEN $2::text IS NULL THEN NULL ELSE NOW() END
+		WHERE id = $1
+		RETURNING `+ratingColumns, id, response), &rt)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return &rt, nil
+}
+
+func (r *ratingRepo) Delete(ctx context.Context, id int) (int, error) {
+	var restaurantID int
+	err := r.db.QueryRow(ctx, "DELETE FROM ratings WHERE id = $1 RETURNING restaurant_id", id).Scan(&restaurantID)
+	return restaurantID, notFound(err)
+}
@@ -0,0 +1,122 @@
+// Package repository holds the SQL of the restaurant, rating and suggestion handlers
+// behind interfaces, so handlers can be tested against mocks.
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/nomdb/backend/internal/database"
+	"github.com/nomdb/backend/internal/models"
+)
+
+// ErrNotFound is returned when the requested row does not exist
+var ErrNotFound = errors.New("not found")
+
+// DB is the subset of pgx implemented by *pgxpool.Pool, pgx.Tx and pgxmock
+type DB interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// Repositories bundles the repositories handlers depend on
+type Repositories struct {
+	Restaurants     RestaurantRepository
+	Ratings         RatingRepository
+	Claims          RestaurantClaimRepository
+	Follows         FollowRepository
+	Stats           StatsRepository
+	Badges          BadgeRepository
+	Suggestions     SuggestionRepository
+	PlaceChanges    PlaceChangeRepository
+	MapsUsage       MapsUsageRepository
+	ExternalSources ExternalSourceRepository
+	Webhooks        WebhookRepository
+	Settings        SettingRepository
+	Spaces          SpaceRepository
+	Translations    TranslationRepository
+
+	db DB
+}
+
+// New creates the PostgreSQL repositories on db
+func New(db DB) *Repositories {
+	return &Repositories{
+		Restaurants:     &restaurantRepo{db: db},
+		Ratings:         &ratingRepo{db: db},
+		Claims:          &restaurantClaimRepo{db: db},
+		Follows:         &followRepo{db: db},
+		Stats:           &statsRepo{db: db},
+		Badges:          &badgeRepo{db: db},
+		Suggestions:     &suggestionRepo{db: db},
+		PlaceChanges:    &placeChangeRepo{db: db},
+		MapsUsage:       &mapsUsageRepo{db: db},
+		ExternalSources: &externalSourceRepo{db: db},
+		Webhooks:        &webhookRepo{db: db},
+		Settings:        &settingRepo{db: db},
+		Spaces:          &spaceRepo{db: db},
+		Translations:    &translationRepo{db: db},
+		db:              db,
+	}
+}
+
+// WithTx runs fn with repositories sharing one transaction, committed if fn returns nil.
+// Inside a transaction, it nests a savepoint.
+func (r *Repositories) WithTx(ctx context.Context, fn func(tx *Repositories) error) error {
+	return database.RunInTx(ctx, r.db, func(tx pgx.Tx) error {
+		return fn(New(tx))
+	})
+}
+
+// notFound maps pgx.ErrNoRows to ErrNotFound
+func notFound(err error) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrNotFound
+	}
+	return err
+}
+
+// queryFoodTypes runs a query returning id, name, created_at and updated_at of food types
+func queryFoodTypes(ctx context.Context, db DB, query string, args ...any) ([]models.FoodType, error) {
+	rows, err := db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var foodTypes []models.FoodType
+	for rows.Next() {
+		var ft models.FoodType
+		if err := rows.Scan(&ft.ID, &ft.Name, &ft.CreatedAt, &ft.UpdatedAt); err != nil {
+			return nil, err
+		}
+		foodTypes = append(foodTypes, ft)
+	}
+	return foodTypes, rows.Err()
+}
+
+// setFoodTypes makes foodTypeIDs the food types of row id in a join table, deleting the
+// food types not listed and inserting the missing ones in one transaction
+func setFoodTypes(ctx context.Context, db DB, table, column string, id int, foodTypeIDs []int) error {
+	if foodTypeIDs == nil {
+		// A nil slice is sent as NULL, which would match nothing in the delete
+		foodTypeIDs = []int{}
+	}
+	return database.RunInTx(ctx, db, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, fmt.Sprintf(
+			`DELETE FROM %s WHERE %s = $1 AND NOT (food_type_id = ANY($2::int[]))`, table, column),
+			id, foodTypeIDs); err != nil {
+			return err
+		}
+		_, err := tx.Exec(ctx, fmt.Sprintf(
+			`INSERT INTO %s (%s, food_type_id) SELECT $1, ft FROM unnest($2::int[]) AS ft
+			ON CONFLICT DO NOTHING`, table, column),
+			id, foodTypeIDs)
+		return err
+	})
+}
@@ -0,0 +1,377 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/nomdb/backend/internal/models"
+	"github.com/pashagolub/pgxmock/v4"
+)
+
+func newMock(t *testing.T) (pgxmock.PgxPoolIface, *Repositories) {
+	t.Helper()
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatalf("Failed to create mock: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Unmet expectations: %v", err)
+		}
+		mock.Close()
+	})
+	return mock, New(mock)
+}
+
+var ratingRowColumns = []string{"id", "restaurant_id", "food_rating", "service_rating", "ambiance_rating", "comment", "user_id", "created_at", "owner_response", "owner_response_at"}
+
+func TestRatingsListByRestaurant(t *testing.T) {
+	mock, repos := newMock(t)
+	now := time.Now()
+	author := 7
+	response := "Thanks for visiting"
+
+	mock.ExpectQuery(`FROM ratings WHERE restaurant_id = \$1 ORDER BY created_at DESC`).
+		WithArgs(3).
+		WillReturnRows(pgxmock.NewRows(ratingRowColumns).
+			AddRow(2, 3, 5, 4, 3, nil, &author, now, &response, &now).
+			AddRow(1, 3, 1, 2, 3, nil, nil, now, nil, nil))
+
+	ratings, err := repos.Ratings.ListByRestaurant(context.Background(), 3)
+	if err != nil {
+		t.Fatalf("ListByRestaurant failed: %v", err)
+	}
+	if len(ratings) != 2 || ratings[0].ID != 2 || ratings[0].UserID == nil || *ratings[0].UserID != 7 || ratings[1].UserID != nil {
+		t.Errorf("Unexpected ratings: %+v", ratings)
+	}
+	if ratings[0].OwnerResponse == nil || ratings[0].OwnerResponse.Response != response || ratings[1].OwnerResponse != nil {
+		t.Errorf("Expected the owner response of the first rating only, got %+v", ratings)
+	}
+}
+
+func TestRatingsListByRestaurant_Empty(t *testing.T) {
+	mock, repos := newMock(t)
+	mock.ExpectQuery(`FROM ratings`).WithArgs(3).WillReturnRows(pgxmock.NewRows(ratingRowColumns))
+
+	ratings, err := repos.Ratings.ListByRestaurant(context.Background(), 3)
+	if err != nil {
+		t.Fatalf("ListByRestaurant failed: %v", err)
+	}
+	// Encoded as [] rather than null
+	if ratings == nil || len(ratings) != 0 {
+		t.Errorf("Expected an empty slice, got %#v", ratings)
+	}
+}
+
+func TestRatingsCreate(t *testing.T) {
+	mock, repos := newMock(t)
+	comment := "Great"
+	req := models.CreateRatingRequest{RestaurantID: 3, FoodRating: 5, ServiceRating: 4, AmbianceRating: 3, Comment: &comment}
+
+	mock.ExpectQuery(`INSERT INTO ratings`).
+		WithArgs(3, 5, 4, 3, &comment, (*int)(nil)).
+		WillReturnRows(pgxmock.NewRows(ratingRowColumns).AddRow(9, 3, 5, 4, 3, &comment, nil, time.Now(), nil, nil))
+
+	rt, err := repos.Ratings.Create(context.Background(), req, nil)
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if rt.ID != 9 || rt.Comment == nil || *rt.Comment != comment {
+		t.Errorf("Unexpected rating: %+v", rt)
+	}
+}
+
+func TestRatingsAuthorID_NotFound(t *testing.T) {
+	mock, repos := newMock(t)
+	mock.ExpectQuery(`SELECT user_id FROM ratings`).WithArgs(4).WillReturnError(pgx.ErrNoRows)
+
+	if _, err := repos.Ratings.AuthorID(context.Background(), 4); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestRatingsSetOwnerResponse(t *testing.T) {
+	mock, repos := newMock(t)
+	now := time.Now()
+	response := "Thanks, see you soon"
+
+	mock.ExpectQuery(`UPDATE ratings SET owner_response = \$2::text`).WithArgs(4, &response).
+		WillReturnRows(pgxmock.NewRows(ratingRowColumns).AddRow(4, 3, 5, 4, 3, nil, nil, now, &response, &now))
+	mock.ExpectQuery(`UPDATE ratings SET owner_response`).WithArgs(5, (*string)(nil)).WillReturnError(pgx.ErrNoRows)
+
+	rt, err := repos.Ratings.SetOwnerResponse(context.Background(), 4, &response)
+	if err != nil {
+		t.Fatalf("SetOwnerResponse failed: %v", err)
+	}
+	if rt.OwnerResponse == nil || rt.OwnerResponse.Response != response || !rt.OwnerResponse.RespondedAt.Equal(now) {
+		t.Errorf("Unexpected owner response %+v", rt.OwnerResponse)
+	}
+	if _, err := repos.Ratings.SetOwnerResponse(context.Background(), 5, nil); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for a missing rating, got %v", err)
+	}
+}
+
+func TestRatingsDelete(t *testing.T) {
+	mock, repos := newMock(t)
+	mock.ExpectQuery(`DELETE FROM ratings`).WithArgs(4).
+		WillReturnRows(pgxmock.NewRows([]string{"restaurant_id"}).AddRow(9))
+	mock.ExpectQuery(`DELETE FROM ratings`).WithArgs(5).WillReturnError(pgx.ErrNoRows)
+
+	if restaurantID, err := repos.Ratings.Delete(context.Background(), 4); err != nil || restaurantID != 9 {
+		t.Errorf("Expected restaurant 9, got %d (%v)", restaurantID, err)
+	}
+	if _, err := repos.Ratings.Delete(context.Background(), 5); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for a missing rating, got %v", err)
+	}
+}
+
+var foodTypeColumns = []string{"id", "name", "created_at", "updated_at"}
+
+func TestRestaurantsGetByID(t *testing.T) {
+	mock, repos := newMock(t)
+	now := time.Now()
+	catID, catName := 2, "Italian"
+
+	mock.ExpectQuery(`FROM restaurants r`).WithArgs(1).
+		WillReturnRows(pgxmock.NewRows([]string{
+			"id", "name", "description", "address", "phone", "website", "latitude", "longitude",
+			"google_place_id", "category_id", "created_at", "updated_at", "business_status", "opening_hours",
+			"c.id", "c.name",
+			"avg_food", "avg_service", "avg_ambiance", "rating_count",
+		}).AddRow(1, "Luigi's", nil, nil, nil, nil, nil, nil, nil, &catID, now, now, nil, nil, &catID, &catName, 4.0, 3.0, 2.0, 2))
+	mock.ExpectQuery(`JOIN restaurant_food_types`).WithArgs(1).
+		WillReturnRows(pgxmock.NewRows(foodTypeColumns).AddRow(5, "Pizza", now, now))
+
+	rest, err := repos.Restaurants.GetByID(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("GetByID failed: %v", err)
+	}
+	if rest.Category == nil || rest.Category.Name != "Italian" {
+		t.Errorf("Expected the category to be set, got %+v", rest.Category)
+	}
+	if rest.AvgRating == nil || rest.AvgRating.Overall != 3 || rest.AvgRating.Count != 2 {
+		t.Errorf("Unexpected average rating: %+v", rest.AvgRating)
+	}
+	if len(rest.FoodTypes) != 1 || rest.FoodTypes[0].Name != "Pizza" {
+		t.Errorf("Unexpected food types: %+v", rest.FoodTypes)
+	}
+}
+
+func TestRestaurantsGetByID_NotFound(t *testing.T) {
+	mock, repos := newMock(t)
+	mock.ExpectQuery(`FROM restaurants r`).WithArgs(1).WillReturnError(pgx.ErrNoRows)
+
+	if _, err := repos.Restaurants.GetByID(context.Background(), 1); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestRestaurantsFindExisting(t *testing.T) {
+	placeID, address, empty := "ChIJ123", "1 Main St", ""
+
+	t.Run("by place ID", func(t *testing.T) {
+		mock, repos := newMock(t)
+		mock.ExpectQuery(`WHERE space_id = \$1 AND google_place_id = \$2`).WithArgs(3, placeID).
+			WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(8))
+
+		id, err := repos.Restaurants.FindExisting(context.Background(), 3, "Luigi's", &address, &placeID)
+		if err != nil || id != 8 {
+			t.Errorf("Expected restaurant 8, got %d, %v", id, err)
+		}
+	})
+
+	t.Run("by name and address", func(t *testing.T) {
+		mock, repos := newMock(t)
+		mock.ExpectQuery(`space_id = \$1 AND LOWER\(name\) = LOWER\(\$2\) AND LOWER\(address\) = LOWER\(\$3\)`).
+			WithArgs(3, "Luigi's", address).WillReturnError(pgx.ErrNoRows)
+
+		if _, err := repos.Restaurants.FindExisting(context.Background(), 3, "Luigi's", &address, &empty); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Expected ErrNotFound, got %v", err)
+		}
+	})
+
+	t.Run("name only", func(t *testing.T) {
+		// No query: a name alone is not enough to identify a restaurant
+		_, repos := newMock(t)
+		if _, err := repos.Restaurants.FindExisting(context.Background(), 3, "Luigi's", nil, nil); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Expected ErrNotFound, got %v", err)
+		}
+	})
+}
+
+func TestRestaurantsSetFoodTypes(t *testing.T) {
+	mock, repos := newMock(t)
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM restaurant_food_types WHERE restaurant_id = \$1 AND NOT \(food_type_id = ANY`).
+		WithArgs(1, []int{4, 6}).WillReturnResult(pgxmock.NewResult("DELETE", 1))
+	mock.ExpectExec(`INSERT INTO restaurant_food_types .* unnest\(\$2::int\[\]\) .* ON CONFLICT DO NOTHING`).
+		WithArgs(1, []int{4, 6}).WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	mock.ExpectCommit()
+
+	if err := repos.Restaurants.SetFoodTypes(context.Background(), 1, []int{4, 6}); err != nil {
+		t.Errorf("SetFoodTypes failed: %v", err)
+	}
+}
+
+func TestSuggestionsSetFoodTypes_Clear(t *testing.T) {
+	mock, repos := newMock(t)
+	// nil clears the food types rather than matching nothing
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM suggestion_food_types WHERE suggestion_id = \$1`).
+		WithArgs(2, []int{}).WillReturnResult(pgxmock.NewResult("DELETE", 3))
+	mock.ExpectExec(`INSERT INTO suggestion_food_types`).
+		WithArgs(2, []int{}).WillReturnResult(pgxmock.NewResult("INSERT", 0))
+	mock.ExpectCommit()
+
+	if err := repos.Suggestions.SetFoodTypes(context.Background(), 2, nil); err != nil {
+		t.Errorf("SetFoodTypes failed: %v", err)
+	}
+}
+
+func TestRestaurantsSetFoodTypes_RollsBack(t *testing.T) {
+	mock, repos := newMock(t)
+	// A failed insert rolls back the delete, keeping the previous food types
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM restaurant_food_types`).WithArgs(1, []int{4, 99}).WillReturnResult(pgxmock.NewResult("DELETE", 3))
+	mock.ExpectExec(`INSERT INTO restaurant_food_types`).WithArgs(1, []int{4, 99}).WillReturnError(errors.New("foreign key violation"))
+	mock.ExpectRollback()
+
+	if err := repos.Restaurants.SetFoodTypes(context.Background(), 1, []int{4, 99}); err == nil {
+		t.Error("Expected the insert error to be returned")
+	}
+}
+
+func TestRepositoriesWithTx(t *testing.T) {
+	mock, repos := newMock(t)
+	now := time.Now()
+	mock.ExpectBegin()
+	anyArg := pgxmock.AnyArg()
+	mock.ExpectQuery(`INSERT INTO restaurants`).
+		WithArgs("Luigi's", anyArg, anyArg, anyArg, anyArg, anyArg, anyArg, anyArg, anyArg, DefaultSpaceID, anyArg).
+		WillReturnRows(pgxmock.NewRows([]string{
+			"id", "name", "description", "address", "phone", "website", "latitude", "longitude",
+			"google_place_id", "category_id", "created_at", "updated_at", "business_status", "opening_hours",
+		}).AddRow(5, "Luigi's", nil, nil, nil, nil, nil, nil, nil, nil, now, now, nil, nil))
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM restaurant_food_types`).WithArgs(5, []int{4}).WillReturnResult(pgxmock.NewResult("DELETE", 0))
+	mock.ExpectExec(`INSERT INTO restaurant_food_types`).WithArgs(5, []int{4}).WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	mock.ExpectCommit()
+	mock.ExpectCommit()
+
+	err := repos.WithTx(context.Background(), func(tx *Repositories) error {
+		rest, err := tx.Restaurants.Create(context.Background(), models.CreateRestaurantRequest{Name: "Luigi's"})
+		if err != nil {
+			return err
+		}
+		return tx.Restaurants.SetFoodTypes(context.Background(), rest.ID, []int{4})
+	})
+	if err != nil {
+		t.Errorf("WithTx failed: %v", err)
+	}
+}
+
+var suggestionRowColumns = []string{
+	"id", "name", "address", "phone", "website", "latitude", "longitude", "google_place_id",
+	"suggested_category_id", "notes", "status", "created_at", "updated_at", "user_id",
+}
+
+func TestSuggestionsList(t *testing.T) {
+	mock, repos := newMock(t)
+	now := time.Now()
+
+	mock.ExpectQuery(`WHERE s.space_id = \$1 AND s.status = \$2 ORDER BY s.created_at DESC`).WithArgs(2, "pending").
+		WillReturnRows(pgxmock.NewRows(append(suggestionRowColumns, "c.id", "c.name")).
+			AddRow(4, "Noodle Bar", nil, nil, nil, nil, nil, nil, nil, nil, "pending", now, now, nil, nil, nil).
+			AddRow(3, "Taco Stand", nil, nil, nil, nil, nil, nil, nil, nil, "pending", now, now, nil, nil, nil))
+	mock.ExpectQuery(`JOIN suggestion_food_types`).WithArgs(4).
+		WillReturnRows(pgxmock.NewRows(foodTypeColumns).AddRow(1, "Ramen", now, now))
+	mock.ExpectQuery(`JOIN suggestion_food_types`).WithArgs(3).
+		WillReturnRows(pgxmock.NewRows(foodTypeColumns))
+
+	suggestions, err := repos.Suggestions.List(context.Background(), 2, "pending")
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(suggestions) != 2 || len(suggestions[0].FoodTypes) != 1 || suggestions[0].Category != nil {
+		t.Errorf("Unexpected suggestions: %+v", suggestions)
+	}
+}
+
+func TestSuggestionsUpdateStatus(t *testing.T) {
+	mock, repos := newMock(t)
+	now := time.Now()
+
+	mock.ExpectQuery(`UPDATE restaurant_suggestions s SET status = \$1`).WithArgs("approved", 4).
+		WillReturnRows(pgxmock.NewRows(append(suggestionRowColumns, "prev.status")).
+			AddRow(4, "Noodle Bar", nil, nil, nil, nil, nil, nil, nil, nil, "approved", now, now, nil, "pending"))
+	mock.ExpectQuery(`UPDATE restaurant_suggestions s SET status = \$1`).WithArgs("approved", 5).
+		WillReturnError(pgx.ErrNoRows)
+
+	sug, previous, err := repos.Suggestions.UpdateStatus(context.Background(), 4, "approved")
+	if err != nil {
+		t.Fatalf("UpdateStatus failed: %v", err)
+	}
+	if sug.Status != "approved" || previous != "pending" {
+		t.Errorf("Expected pending -> approved, got %s -> %s", previous, sug.Status)
+	}
+
+	if _, _, err := repos.Suggestions.UpdateStatus(context.Background(), 5, "approved"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestSuggestionsDelete_NotFound(t *testing.T) {
+	mock, repos := newMock(t)
+	mock.ExpectQuery(`DELETE FROM restaurant_suggestions WHERE id = \$1 RETURNING status`).WithArgs(4).
+		WillReturnError(pgx.ErrNoRows)
+
+	if _, err := repos.Suggestions.Delete(context.Background(), 4); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestSuggestionsApprove_Errors(t *testing.T) {
+	mock, repos := newMock(t)
+	mock.ExpectExec(`INSERT INTO suggestion_approvals`).WithArgs(4, 7, (*string)(nil)).
+		WillReturnError(&pgconn.PgError{Code: "23505", ConstraintName: "suggestion_approvals_suggestion_id_user_id_key"})
+	mock.ExpectExec(`INSERT INTO suggestion_approvals`).WithArgs(5, 7, (*string)(nil)).
+		WillReturnError(&pgconn.PgError{Code: "23503", ConstraintName: "suggestion_approvals_suggestion_id_fkey"})
+
+	if err := repos.Suggestions.Approve(context.Background(), 4, 7, nil); !errors.Is(err, ErrAlreadyApproved) {
+		t.Errorf("Expected ErrAlreadyApproved, got %v", err)
+	}
+	if err := repos.Suggestions.Approve(context.Background(), 5, 7, nil); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestSuggestionsEvents(t *testing.T) {
+	mock, repos := newMock(t)
+	now := time.Now()
+	eventColumns := []string{"id", "suggestion_id", "user_id", "username", "from_status", "to_status", "note", "created_at"}
+
+	// The history of a converted suggestion, in its space
+	mock.ExpectQuery(`WHERE e.suggestion_id = \$1 AND e.space_id = \$2`).WithArgs(4, 2).
+		WillReturnRows(pgxmock.NewRows(eventColumns).AddRow(1, 4, nil, nil, nil, "pending", nil, now))
+	// From another space: neither the history nor the suggestion is found there
+	mock.ExpectQuery(`WHERE e.suggestion_id = \$1 AND e.space_id = \$2`).WithArgs(4, 3).
+		WillReturnRows(pgxmock.NewRows(eventColumns))
+	mock.ExpectQuery(`FROM restaurant_suggestions WHERE id = \$1 AND space_id = \$2`).WithArgs(4, 3).
+		WillReturnRows(pgxmock.NewRows([]string{"exists"}).AddRow(false))
+
+	events, err := repos.Suggestions.Events(context.Background(), 2, 4)
+	if err != nil {
+		t.Fatalf("Events failed: %v", err)
+	}
+	if len(events) != 1 || events[0].ToStatus != "pending" {
+		t.Errorf("Unexpected events: %+v", events)
+	}
+
+	if _, err := repos.Suggestions.Events(context.Background(), 3, 4); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound from another space, got %v", err)
+	}
+}
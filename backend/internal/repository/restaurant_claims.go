@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/nomdb/backend/internal/database"
+	"github.com/nomdb/backend/internal/models"
+)
+
+// Restaurant claim states, mirroring the CHECK constraint on restaurant_claims.status
+const (
+	ClaimPending  = "pending"
+	ClaimApproved = "approved"
+	ClaimRejected = "rejected"
+)
+
+var (
+	// ErrClaimPending is returned when claiming a restaurant the user has a pending claim on
+	ErrClaimPending = errors.New("claim already pending")
+	// ErrAlreadyOwned is returned when approving a claim on a restaurant that has an owner
+	ErrAlreadyOwned = errors.New("restaurant already has an owner")
+)
+
+// RestaurantClaimRepository reads and writes the claims of users to own restaurants
+type RestaurantClaimRepository interface {
+	// Create stores a pending claim of userID on a restaurant; ErrClaimPending if the
+	// user has one already
+	Create(ctx context.Context, restaurantID, userID int, message *string) (*models.RestaurantClaim, error)
+	// OwnerID returns the owner of a restaurant, the user of its approved claim, or nil
+	OwnerID(ctx context.Context, restaurantID int) (*int, error)
+	// List returns claims with the names of their restaurant and user, oldest first,
+	// optionally only those with status
+	List(ctx context.Context, status string) ([]models.RestaurantClaim, error)
+	// ListByUser returns the claims of a user, newest first
+	ListByUser(ctx context.Context, userID int) ([]models.RestaurantClaim, error)
+	// Review approves or rejects a pending claim by reviewerID; approving rejects the
+	// other pending claims on the restaurant. ErrNotFound is returned if there is no
+	// pending claim with id, ErrAlreadyOwned if the restaurant has an owner.
+	Review(ctx context.Context, id int, approve bool, reviewerID *int) (*models.RestaurantClaim, error)
+}
+
+type restaurantClaimRepo struct {
+	db DB
+}
+
+const restaurantClaimColumns = `rc.id, rc.restaurant_id, r.name, rc.user_id, u.username, rc.message, rc.status, rc.reviewed_by_user_id, rc.reviewed_at, rc.created_at`
+
+const restaurantClaimJoins = `JOIN restaurants r ON r.id = rc.restaurant_id JOIN users u ON u.id = rc.user_id`
+
+func scanRestaurantClaim(row interface{ Scan(...any) error }, c *models.RestaurantClaim) error {
+	return row.Scan(&c.ID, &c.RestaurantID, &c.RestaurantName, &c.UserID, &c.Username, &c.Message,
+		&c.Status, &c.ReviewedByUserID, &c.ReviewedAt, &c.CreatedAt)
+}
+
+// isUniqueViolation reports whether err violates the unique index
+func isUniqueViolation(err error, index string) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == index
+}
+
+func (r *restaurantClaimRepo) Create(ctx context.Context, restaurantID, userID int, message *string) (*models.RestaurantClaim, error) {
+	var claim models.RestaurantClaim
+	err := scanRestaurantClaim(r.db.QueryRow(ctx,
+		`WITH rc AS (
+			INSERT INTO restaurant_claims (restaurant_id, user_id, message) VALUES ($1, $2, $3)
+			RETURNING *
+		)
+		SELECT `+restaurantClaimColumns+` FROM rc `+restaurantClaimJoins,
+		restaurantID, userID, message), &claim)
+	if isUniqueViolation(err, "idx_restaurant_claims_pending") {
+		return nil, ErrClaimPending
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &claim, nil
+}
+
+func (r *restaurantClaimRepo) OwnerID(ctx context.Context, restaurantID int) (*int, error) {
+	var ownerID int
+	err := r.db.QueryRow(ctx,
+		`SELECT user_id FROM restaurant_claims WHERE restaurant_id = $1 AND status = 'approved'`,
+		restaurantID).Scan(&ownerID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &ownerID, nil
+}
+
+func (r *restaurantClaimRepo) List(ctx context.Context, status string) ([]models.RestaurantClaim, error) {
+	return r.query(ctx,
+		`SELECT `+restaurantClaimColumns+` FROM restaurant_claims rc `+restaurantClaimJoins+`
+		WHERE $1 = '' OR rc.status = $1
+		ORDER BY rc.created_at, rc.id`, status)
+}
+
+func (r *restaurantClaimRepo) ListByUser(ctx context.Context, userID int) ([]models.RestaurantClaim, error) {
+	return r.query(ctx,
+		`SELECT `+restaurantClaimColumns+` FROM restaurant_claims rc `+restaurantClaimJoins+`
+		WHERE rc.user_id = $1
+		ORDER BY rc.created_at DESC, rc.id DESC`, userID)
+}
+
+func (r *restaurantClaimRepo) query(ctx context.Context, query string, args ...any) ([]models.RestaurantClaim, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	claims := []models.RestaurantClaim{}
+	for rows.Next() {
+		var claim models.RestaurantClaim
+		if err := scanRestaurantClaim(rows, &claim); err != nil {
+			return nil, err
+		}
+		claims = append(claims, claim)
+	}
+	return claims, rows.Err()
+}
+
+func (r *restaurantClaimRepo) Review(ctx context.Context, id int, approve bool, reviewerID *int) (*models.RestaurantClaim, error) {
+	status := ClaimRejected
+	if approve {
+		status = ClaimApproved
+	}
+
+	var claim models.RestaurantClaim
+	err := database.RunInTx(ctx, r.db, func(tx pgx.Tx) error {
+		err := scanRestaurantClaim(tx.QueryRow(ctx,
+			`WITH rc AS (
+				UPDATE restaurant_claims SET status = $2, reviewed_by_user_id = $3, reviewed_at = NOW()
+				WHERE id = $1 AND status = 'pending'
+				RETURNING *
+			)
+			SELECT `+restaurantClaimColumns+` FROM rc `+restaurantClaimJoins,
+			id, status, reviewerID), &claim)
+		if isUniqueViolation(err, "idx_restaurant_claims_owner") {
+			return ErrAlreadyOwned
+		}
+		if err != nil || !approve {
+			return notFound(err)
+		}
+		_, err = tx.Exec(ctx,
+			`UPDATE restaurant_claims SET status = 'rejected', reviewed_by_user_id = $2, reviewed_at = NOW()
+			WHERE restaurant_id = $1 AND status = 'pending'`,
+			claim.RestaurantID, reviewerID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &claim, nil
+}
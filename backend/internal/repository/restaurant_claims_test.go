@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/pashagolub/pgxmock/v4"
+)
+
+var restaurantClaimColumnNames = []string{
+	"id", "restaurant_id", "name", "user_id", "username", "message", "status", "reviewed_by_user_id", "reviewed_at", "created_at",
+}
+
+func TestRestaurantClaimsCreate_Pending(t *testing.T) {
+	mock, repos := newMock(t)
+	message := "I run the place"
+
+	mock.ExpectQuery(`INSERT INTO restaurant_claims`).WithArgs(3, 7, &message).
+		WillReturnRows(pgxmock.NewRows(restaurantClaimColumnNames).
+			AddRow(1, 3, "Luigi's", 7, "luigi", &message, ClaimPending, nil, nil, time.Now()))
+	mock.ExpectQuery(`INSERT INTO restaurant_claims`).WithArgs(3, 7, &message).
+		WillReturnError(&pgconn.PgError{Code: "23505", ConstraintName: "idx_restaurant_claims_pending"})
+
+	claim, err := repos.Claims.Create(context.Background(), 3, 7, &message)
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if claim.RestaurantName != "Luigi's" || claim.Username != "luigi" || claim.Status != ClaimPending {
+		t.Errorf("Unexpected claim %+v", claim)
+	}
+	if _, err := repos.Claims.Create(context.Background(), 3, 7, &message); !errors.Is(err, ErrClaimPending) {
+		t.Errorf("Expected ErrClaimPending for a second claim, got %v", err)
+	}
+}
+
+func TestRestaurantClaimsOwnerID(t *testing.T) {
+	mock, repos := newMock(t)
+	mock.ExpectQuery(`SELECT user_id FROM restaurant_claims`).WithArgs(3).
+		WillReturnRows(pgxmock.NewRows([]string{"user_id"}).AddRow(7))
+	mock.ExpectQuery(`SELECT user_id FROM restaurant_claims`).WithArgs(4).WillReturnError(pgx.ErrNoRows)
+
+	if ownerID, err := repos.Claims.OwnerID(context.Background(), 3); err != nil || ownerID == nil || *ownerID != 7 {
+		t.Errorf("Expected owner 7, got %v (%v)", ownerID, err)
+	}
+	if ownerID, err := repos.Claims.OwnerID(context.Background(), 4); err != nil || ownerID != nil {
+		t.Errorf("Expected no owner, got %v (%v)", ownerID, err)
+	}
+}
+
+func TestRestaurantClaimsReview_Approve(t *testing.T) {
+	mock, repos := newMock(t)
+	now := time.Now()
+	reviewer := 1
+
+	// Approving rejects the other pending claims on the restaurant
+	mock.ExpectBegin()
+	mock.ExpectQuery(`UPDATE restaurant_claims SET status = \$2`).WithArgs(5, ClaimApproved, &reviewer).
+		WillReturnRows(pgxmock.NewRows(restaurantClaimColumnNames).
+			AddRow(5, 3, "Luigi's", 7, "luigi", nil, ClaimApproved, &reviewer, &now, now))
+	mock.ExpectExec(`UPDATE restaurant_claims SET status = 'rejected'`).WithArgs(3, &reviewer).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 2))
+	mock.ExpectCommit()
+
+	claim, err := repos.Claims.Review(context.Background(), 5, true, &reviewer)
+	if err != nil {
+		t.Fatalf("Review failed: %v", err)
+	}
+	if claim.Status != ClaimApproved || claim.UserID != 7 {
+		t.Errorf("Unexpected claim %+v", claim)
+	}
+}
+
+func TestRestaurantClaimsReview_Errors(t *testing.T) {
+	mock, repos := newMock(t)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`UPDATE restaurant_claims`).WithArgs(5, ClaimRejected, (*int)(nil)).WillReturnError(pgx.ErrNoRows)
+	mock.ExpectRollback()
+	mock.ExpectBegin()
+	mock.ExpectQuery(`UPDATE restaurant_claims`).WithArgs(6, ClaimApproved, (*int)(nil)).
+		WillReturnError(&pgconn.PgError{Code: "23505", ConstraintName: "idx_restaurant_claims_owner"})
+	mock.ExpectRollback()
+
+	if _, err := repos.Claims.Review(context.Background(), 5, false, nil); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for a claim that is not pending, got %v", err)
+	}
+	if _, err := repos.Claims.Review(context.Background(), 6, true, nil); !errors.Is(err, ErrAlreadyOwned) {
+		t.Errorf("Expected ErrAlreadyOwned for an owned restaurant, got %v", err)
+	}
+}
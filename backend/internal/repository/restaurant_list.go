@@ -0,0 +1,294 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/nomdb/backend/internal/models"
+)
+
+// psql builds queries with PostgreSQL $n placeholders, numbered when the query is built
+var psql = sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+
+// RestaurantFilter narrows restaurant listings; zero values do not filter
+type RestaurantFilter struct {
+	SpaceID     int
+	CategoryID  *int       // Places in the category or one of its descendants
+	FoodTypeIDs []int      // Places with any of the food types
+	Near        *GeoRadius // Also adds the distance in km to the results
+	Search      string     // Case-insensitive substring of the name or description
+}
+
+// GeoRadius matches places within RadiusKm of a point
+type GeoRadius struct {
+	Lat, Lng, RadiusKm float64
+}
+
+// listSource describes where restaurants and suggestions keep the filtered columns,
+// so both are filtered by the same code
+type listSource struct {
+	alias          string
+	categoryColumn string
+	foodTypeTable  string
+	foodTypeKey    string
+	searchColumns  []string
+}
+
+var (
+	restaurantSource = listSource{
+		alias:          "r",
+		categoryColumn: "r.category_id",
+		foodTypeTable:  "restaurant_food_types",
+		foodTypeKey:    "restaurant_id",
+		searchColumns:  []string{"r.name", "r.description"},
+	}
+	suggestionSource = listSource{
+		alias:          "s",
+		categoryColumn: "s.suggested_category_id",
+		foodTypeTable:  "suggestion_food_types",
+		foodTypeKey:    "suggestion_id",
+		searchColumns:  []string{"s.name"},
+	}
+)
+
+// categorySubtree selects the IDs of a category and its descendants. UNION rather than
+// UNION ALL stops the recursion should the parents ever form a cycle.
+const categorySubtree = "WITH RECURSIVE subtree AS (SELECT id FROM categories WHERE id = ? " +
+	"UNION SELECT c.id FROM categories c JOIN subtree ON c.parent_id = subtree.id) SELECT id FROM subtree"
+
+// distance is the great-circle distance in km between the point and the place
+func (src listSource) distance(near *GeoRadius) sq.Sqlizer {
+	return sq.Expr(fmt.Sprintf(`(6371 * acos(
+		cos(radians(?)) * cos(radians(%[1]s.latitude)) *
+		cos(radians(%[1]s.longitude) - radians(?)) +
+		sin(radians(?)) * sin(radians(%[1]s.latitude))
+	))`, src.alias), near.Lat, near.Lng, near.Lat)
+}
+
+// apply adds the conditions of the filter, and the distance column when filtering by
+// distance, to a query on src
+func (f RestaurantFilter) apply(q sq.SelectBuilder, src listSource) sq.SelectBuilder {
+	if f.SpaceID != 0 {
+		q = q.Where(sq.Eq{src.alias + ".space_id": f.SpaceID})
+	}
+	if f.CategoryID != nil {
+		q = q.Where(fmt.Sprintf("%s IN (%s)", src.categoryColumn, categorySubtree), *f.CategoryID)
+	}
+	if len(f.FoodTypeIDs) > 0 {
+		q = q.Where(fmt.Sprintf("%s.id IN (SELECT %s FROM %s WHERE food_type_id = ANY(?))",
+			src.alias, src.foodTypeKey, src.foodTypeTable), f.FoodTypeIDs)
+	}
+	if f.Near != nil {
+		q = q.Column(sq.Alias(src.distance(f.Near), "distance")).
+			Where(fmt.Sprintf("%[1]s.latitude IS NOT NULL AND %[1]s.longitude IS NOT NULL", src.alias)).
+			Where(sq.ConcatExpr(src.distance(f.Near), sq.Expr(" <= ?", f.Near.RadiusKm)))
+	}
+	if f.Search != "" {
+		pattern := "%" + f.Search + "%"
+		search := sq.Or{}
+		for _, column := range src.searchColumns {
+			search = append(search, sq.ILike{column: pattern})
+		}
+		q = q.Where(search)
+	}
+	return q
+}
+
+// restaurantListQuery selects the columns scanned by queryList; suggestionListQuery
+// selects the same columns so the two can be combined
+func restaurantListQuery() sq.SelectBuilder {
+	return sq.Select(
+		"r.id", "r.name", "r.description", "r.address", "r.phone", "r.website", "r.latitude", "r.longitude",
+		"r.google_place_id", "r.category_id", "r.created_at", "r.updated_at", "r.business_status", "r.opening_hours",
+		"c.id", "c.name",
+		"COALESCE(AVG(rt.food_rating), 0) AS avg_food",
+		"COALESCE(AVG(rt.service_rating), 0) AS avg_service",
+		"COALESCE(AVG(rt.ambiance_rating), 0) AS avg_ambiance",
+		"COUNT(rt.id) AS rating_count",
+		"false AS is_suggestion",
+		"NULL::integer AS suggestion_id",
+		"NULL::text AS status",
+	).
+		From("restaurants r").
+		LeftJoin("categories c ON r.category_id = c.id").
+		LeftJoin("ratings rt ON r.id = rt.restaurant_id").
+		GroupBy("r.id", "c.id")
+}
+
+func suggestionListQuery() sq.SelectBuilder {
+	return sq.Select(
+		"s.id", "s.name", "NULL::text AS description", "s.address", "s.phone", "s.website", "s.latitude", "s.longitude",
+		"s.google_place_id", "s.suggested_category_id AS category_id", "s.created_at", "s.updated_at",
+		"NULL::varchar AS business_status", "NULL::text[] AS opening_hours",
+		"c.id", "c.name",
+		"0.0 AS avg_food",
+		"0.0 AS avg_service",
+		"0.0 AS avg_ambiance",
+		"0 AS rating_count",
+		"true AS is_suggestion",
+		"s.id AS suggestion_id",
+		"s.status",
+	).
+		From("restaurant_suggestions s").
+		LeftJoin("categories c ON s.suggested_category_id = c.id")
+}
+
+func (r *restaurantRepo) ListWithSuggestions(ctx context.Context, filter RestaurantFilter) ([]models.Restaurant, error) {
+	restaurants := filter.apply(restaurantListQuery(), restaurantSource)
+	suggestions := filter.apply(suggestionListQuery(), suggestionSource).
+		Where(sq.Eq{"s.status": "pending"})
+
+	order := []string{"created_at DESC"}
+	if filter.Near != nil {
+		order = append([]string{"distance ASC"}, order...)
+	}
+	query, args, err := psql.Select("*").
+		FromSelect(restaurants.SuffixExpr(sq.ConcatExpr("UNION ALL ", suggestions)), "combined").
+		OrderBy(order...).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+	list, _, err := r.queryList(ctx, filter.Near != nil, false, query, args...)
+	return list, err
+}
+
+func (r *restaurantRepo) ListNearby(ctx context.Context, filter RestaurantFilter, limit int) ([]models.Restaurant, error) {
+	if filter.Near == nil {
+		return nil, fmt.Errorf("nearby listing requires a location")
+	}
+	query, args, err := filter.apply(restaurantListQuery(), restaurantSource).
+		OrderBy("distance ASC", "r.id ASC").
+		Limit(uint64(limit)).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+	list, _, err := r.queryList(ctx, true, false, query, args...)
+	return list, err
+}
+
+// RestaurantSort is the order of restaurant pages; ties are broken by ID
+type RestaurantSort string
+
+const (
+	SortByID     RestaurantSort = "id"     // Oldest first
+	SortByName   RestaurantSort = "name"   // A to Z, case-insensitive
+	SortByRating RestaurantSort = "rating" // Highest average rating first, unrated last
+)
+
+// PageKey is the position of a restaurant in a sort order, where the next page starts
+type PageKey struct {
+	Value any // Sort value: string for SortByName, float64 for SortByRating, nil for SortByID
+	ID    int
+}
+
+// sortRating is the overall average rating as float8, so the value in a page key
+// compares equal to the row it was read from
+const sortRating = "COALESCE((AVG(rt.food_rating) + AVG(rt.service_rating) + AVG(rt.ambiance_rating))::float8 / 3, 0)"
+
+// ValidSort reports whether s is a supported sort order
+func ValidSort(s RestaurantSort) bool {
+	return s == SortByID || s == SortByName || s == SortByRating
+}
+
+func (r *restaurantRepo) ListPage(ctx context.Context, filter RestaurantFilter, sort RestaurantSort, after *PageKey, limit int) ([]models.Restaurant, *PageKey, error) {
+	q := filter.apply(restaurantListQuery(), restaurantSource)
+
+	switch sort {
+	case SortByID:
+		if after != nil {
+			q = q.Where(sq.Gt{"r.id": after.ID})
+		}
+		q = q.OrderBy("r.id ASC")
+	case SortByName:
+		q = q.Column("LOWER(r.name) AS sort_key")
+		if after != nil {
+			q = q.Where("(LOWER(r.name), r.id) > (?, ?)", after.Value, after.ID)
+		}
+		q = q.OrderBy("sort_key ASC", "r.id ASC")
+	case SortByRating:
+		q = q.Column(sortRating + " AS sort_key")
+		if after != nil {
+			// Aggregates can only be compared after grouping
+			q = q.Having(fmt.Sprintf("(%[1]s < ? OR (%[1]s = ? AND r.id > ?))", sortRating), after.Value, after.Value, after.ID)
+		}
+		q = q.OrderBy("sort_key DESC", "r.id ASC")
+	default:
+		return nil, nil, fmt.Errorf("unknown sort %q", sort)
+	}
+
+	// One more than the page to know whether there is a next one
+	query, args, err := q.Limit(uint64(limit + 1)).PlaceholderFormat(sq.Dollar).ToSql()
+	if err != nil {
+		return nil, nil, err
+	}
+	restaurants, keys, err := r.queryList(ctx, filter.Near != nil, sort != SortByID, query, args...)
+	if err != nil || len(restaurants) <= limit {
+		return restaurants, nil, err
+	}
+
+	restaurants = restaurants[:limit]
+	next := &PageKey{ID: restaurants[limit-1].ID}
+	if keys != nil {
+		next.Value = keys[limit-1]
+	}
+	return restaurants, next, nil
+}
+
+// queryList runs a listing query and scans its rows, followed by the distance column
+// when withDistance is set and the sort key column when withSortKey is set, whose
+// values are returned in row order
+func (r *restaurantRepo) queryList(ctx context.Context, withDistance, withSortKey bool, query string, args ...any) ([]models.Restaurant, []any, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	restaurants := []models.Restaurant{}
+	var keys []any
+	for rows.Next() {
+		var rest models.Restaurant
+		var catID *int
+		var catName *string
+		var avgFood, avgService, avgAmbiance float64
+		var ratingCount int
+		var key any
+
+		extra := []any{
+			&catID, &catName,
+			&avgFood, &avgService, &avgAmbiance, &ratingCount,
+			&rest.IsSuggestion, &rest.SuggestionID, &rest.Status,
+		}
+		if withDistance {
+			extra = append(extra, &rest.Distance)
+		}
+		if withSortKey {
+			extra = append(extra, &key)
+		}
+		if err := scanRestaurant(rows, &rest, extra...); err != nil {
+			return nil, nil, err
+		}
+		if withSortKey {
+			keys = append(keys, key)
+		}
+
+		if catID != nil && catName != nil {
+			rest.Category = &models.Category{ID: *catID, Name: *catName}
+		}
+		if ratingCount > 0 {
+			rest.AvgRating = &models.AvgRating{
+				Food:     avgFood,
+				Service:  avgService,
+				Ambiance: avgAmbiance,
+				Overall:  (avgFood + avgService + avgAmbiance) / 3,
+				Count:    ratingCount,
+			}
+		}
+		restaurants = append(restaurants, rest)
+	}
+	return restaurants, keys, rows.Err()
+}
@@ -0,0 +1,197 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/pashagolub/pgxmock/v4"
+)
+
+var listColumns = []string{
+	"id", "name", "description", "address", "phone", "website", "latitude", "longitude",
+	"google_place_id", "category_id", "created_at", "updated_at", "business_status", "opening_hours",
+	"c.id", "c.name",
+	"avg_food", "avg_service", "avg_ambiance", "rating_count",
+	"is_suggestion", "suggestion_id", "status",
+}
+
+func TestRestaurantFilterApply(t *testing.T) {
+	catID := 2
+	filter := RestaurantFilter{
+		CategoryID:  &catID,
+		FoodTypeIDs: []int{5, 7},
+		Near:        &GeoRadius{Lat: 52.5, Lng: 13.4, RadiusKm: 3},
+		Search:      "pizza",
+	}
+
+	query, args, err := filter.apply(restaurantListQuery(), restaurantSource).PlaceholderFormat(sq.Dollar).ToSql()
+	if err != nil {
+		t.Fatalf("ToSql failed: %v", err)
+	}
+	for _, want := range []string{
+		"r.category_id IN (WITH RECURSIVE subtree AS (SELECT id FROM categories WHERE id = $4 ",
+		"r.id IN (SELECT restaurant_id FROM restaurant_food_types WHERE food_type_id = ANY($5))",
+		"r.latitude IS NOT NULL AND r.longitude IS NOT NULL",
+		"<= $9",
+		"(r.name ILIKE $10 OR r.description ILIKE $11)",
+	} {
+		if !strings.Contains(query, want) {
+			t.Errorf("Expected %q in query:\n%s", want, query)
+		}
+	}
+	// The distance column's arguments come before the WHERE arguments
+	if !strings.Contains(query, "cos(radians($1))") {
+		t.Errorf("Expected the distance column to use $1, got:\n%s", query)
+	}
+	if len(args) != 11 || args[0] != 52.5 || args[1] != 13.4 || args[3] != 2 || args[8] != 3.0 || args[9] != "%pizza%" {
+		t.Errorf("Unexpected args: %v", args)
+	}
+}
+
+func TestRestaurantFilterApply_Space(t *testing.T) {
+	query, args, err := RestaurantFilter{SpaceID: 3}.apply(suggestionListQuery(), suggestionSource).PlaceholderFormat(sq.Dollar).ToSql()
+	if err != nil {
+		t.Fatalf("ToSql failed: %v", err)
+	}
+	if !strings.Contains(query, "WHERE s.space_id = $1") || len(args) != 1 || args[0] != 3 {
+		t.Errorf("Expected the space filter, got %s %v", query, args)
+	}
+}
+
+func TestRestaurantsListWithSuggestions(t *testing.T) {
+	mock, repos := newMock(t)
+	now := time.Now()
+	catID, catName := 2, "Italian"
+	suggestionID, status := 9, "pending"
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM (SELECT r.id`)+
+		`.*WHERE r\.category_id IN \(WITH RECURSIVE .* WHERE id = \$1 .*\) GROUP BY r\.id, c\.id UNION ALL SELECT s\.id`+
+		`.*WHERE s\.suggested_category_id IN \(WITH RECURSIVE .* WHERE id = \$2 .*\) AND s\.status = \$3\) AS combined ORDER BY created_at DESC`).
+		WithArgs(2, 2, "pending").
+		WillReturnRows(pgxmock.NewRows(listColumns).
+			AddRow(1, "Luigi's", nil, nil, nil, nil, nil, nil, nil, &catID, now, now, nil, nil, &catID, &catName, 4.0, 3.0, 2.0, 2, false, nil, nil).
+			AddRow(9, "Mario's", nil, nil, nil, nil, nil, nil, nil, &catID, now, now, nil, nil, &catID, &catName, 0.0, 0.0, 0.0, 0, true, &suggestionID, &status))
+
+	restaurants, err := repos.Restaurants.ListWithSuggestions(context.Background(), RestaurantFilter{CategoryID: &catID})
+	if err != nil {
+		t.Fatalf("ListWithSuggestions failed: %v", err)
+	}
+	if len(restaurants) != 2 {
+		t.Fatalf("Expected 2 results, got %d", len(restaurants))
+	}
+	if restaurants[0].AvgRating == nil || restaurants[0].AvgRating.Overall != 3 || restaurants[0].Category == nil {
+		t.Errorf("Unexpected restaurant: %+v", restaurants[0])
+	}
+	if !restaurants[1].IsSuggestion || restaurants[1].AvgRating != nil || *restaurants[1].SuggestionID != 9 {
+		t.Errorf("Unexpected suggestion: %+v", restaurants[1])
+	}
+}
+
+func TestRestaurantsListWithSuggestions_Near(t *testing.T) {
+	mock, repos := newMock(t)
+	now := time.Now()
+	distance := 1.5
+
+	// Each half of the union has 3 arguments for the distance column and 4 for the radius
+	mock.ExpectQuery(`ORDER BY distance ASC, created_at DESC`).
+		WithArgs(52.5, 13.4, 52.5, 52.5, 13.4, 52.5, 3.0, 52.5, 13.4, 52.5, 52.5, 13.4, 52.5, 3.0, "pending").
+		WillReturnRows(pgxmock.NewRows(append(listColumns, "distance")).
+			AddRow(1, "Luigi's", nil, nil, nil, nil, nil, nil, nil, nil, now, now, nil, nil, nil, nil, 0.0, 0.0, 0.0, 0, false, nil, nil, &distance))
+
+	restaurants, err := repos.Restaurants.ListWithSuggestions(context.Background(),
+		RestaurantFilter{Near: &GeoRadius{Lat: 52.5, Lng: 13.4, RadiusKm: 3}})
+	if err != nil {
+		t.Fatalf("ListWithSuggestions failed: %v", err)
+	}
+	if len(restaurants) != 1 || restaurants[0].Distance == nil || *restaurants[0].Distance != 1.5 {
+		t.Errorf("Expected the distance to be set, got %+v", restaurants)
+	}
+}
+
+func TestRestaurantsListPage(t *testing.T) {
+	mock, repos := newMock(t)
+
+	mock.ExpectQuery(`WHERE r\.id IN \(SELECT restaurant_id FROM restaurant_food_types WHERE food_type_id = ANY\(\$1\)\)`+
+		` AND \(r\.name ILIKE \$2 OR r\.description ILIKE \$3\) AND r\.id > \$4 GROUP BY r\.id, c\.id ORDER BY r\.id ASC LIMIT 21`).
+		WithArgs([]int{5}, "%pizza%", "%pizza%", 40).
+		WillReturnRows(pgxmock.NewRows(listColumns))
+
+	restaurants, next, err := repos.Restaurants.ListPage(context.Background(),
+		RestaurantFilter{FoodTypeIDs: []int{5}, Search: "pizza"}, SortByID, &PageKey{ID: 40}, 20)
+	if err != nil {
+		t.Fatalf("ListPage failed: %v", err)
+	}
+	// Encoded as [] rather than null
+	if restaurants == nil || len(restaurants) != 0 || next != nil {
+		t.Errorf("Expected an empty last page, got %+v (next %+v)", restaurants, next)
+	}
+}
+
+func TestRestaurantsListPage_ByName(t *testing.T) {
+	mock, repos := newMock(t)
+	now := time.Now()
+
+	mock.ExpectQuery(`LOWER\(r\.name\) AS sort_key FROM restaurants r .* WHERE \(LOWER\(r\.name\), r\.id\) > \(\$1, \$2\)`+
+		` GROUP BY r\.id, c\.id ORDER BY sort_key ASC, r\.id ASC LIMIT 3`).
+		WithArgs("bistro", 4).
+		WillReturnRows(pgxmock.NewRows(append(listColumns, "sort_key")).
+			AddRow(7, "Café", nil, nil, nil, nil, nil, nil, nil, nil, now, now, nil, nil, nil, nil, 0.0, 0.0, 0.0, 0, false, nil, nil, "café").
+			AddRow(2, "Diner", nil, nil, nil, nil, nil, nil, nil, nil, now, now, nil, nil, nil, nil, 0.0, 0.0, 0.0, 0, false, nil, nil, "diner").
+			AddRow(9, "Eatery", nil, nil, nil, nil, nil, nil, nil, nil, now, now, nil, nil, nil, nil, 0.0, 0.0, 0.0, 0, false, nil, nil, "eatery"))
+
+	restaurants, next, err := repos.Restaurants.ListPage(context.Background(),
+		RestaurantFilter{}, SortByName, &PageKey{Value: "bistro", ID: 4}, 2)
+	if err != nil {
+		t.Fatalf("ListPage failed: %v", err)
+	}
+	if len(restaurants) != 2 || restaurants[1].ID != 2 {
+		t.Fatalf("Expected the first 2 rows, got %+v", restaurants)
+	}
+	// The next page starts after the last restaurant of this one, not the extra row
+	if next == nil || next.ID != 2 || next.Value != "diner" {
+		t.Errorf("Expected the next key (diner, 2), got %+v", next)
+	}
+}
+
+func TestRestaurantsListPage_ByRating(t *testing.T) {
+	mock, repos := newMock(t)
+
+	mock.ExpectQuery(`GROUP BY r\.id, c\.id HAVING \(COALESCE\(.*\) < \$1 OR \(COALESCE\(.*\) = \$2 AND r\.id > \$3\)\)`+
+		` ORDER BY sort_key DESC, r\.id ASC LIMIT 11`).
+		WithArgs(4.5, 4.5, 12).
+		WillReturnRows(pgxmock.NewRows(append(listColumns, "sort_key")))
+
+	if _, _, err := repos.Restaurants.ListPage(context.Background(),
+		RestaurantFilter{}, SortByRating, &PageKey{Value: 4.5, ID: 12}, 10); err != nil {
+		t.Fatalf("ListPage failed: %v", err)
+	}
+}
+
+func TestRestaurantsListNearby(t *testing.T) {
+	mock, repos := newMock(t)
+	now := time.Now()
+	lat, lng, distance := 52.51, 13.41, 0.8
+
+	mock.ExpectQuery(`WHERE r\.latitude IS NOT NULL AND r\.longitude IS NOT NULL .* <= \$7`+
+		` GROUP BY r\.id, c\.id ORDER BY distance ASC, r\.id ASC LIMIT 5`).
+		WithArgs(52.5, 13.4, 52.5, 52.5, 13.4, 52.5, 2.0).
+		WillReturnRows(pgxmock.NewRows(append(listColumns, "distance")).
+			AddRow(1, "Luigi's", nil, nil, nil, nil, &lat, &lng, nil, nil, now, now, nil, nil, nil, nil, 0.0, 0.0, 0.0, 0, false, nil, nil, &distance))
+
+	restaurants, err := repos.Restaurants.ListNearby(context.Background(),
+		RestaurantFilter{Near: &GeoRadius{Lat: 52.5, Lng: 13.4, RadiusKm: 2}}, 5)
+	if err != nil {
+		t.Fatalf("ListNearby failed: %v", err)
+	}
+	if len(restaurants) != 1 || restaurants[0].Distance == nil || *restaurants[0].Distance != 0.8 {
+		t.Errorf("Expected the distance to be set, got %+v", restaurants)
+	}
+
+	if _, err := repos.Restaurants.ListNearby(context.Background(), RestaurantFilter{}, 5); err == nil {
+		t.Error("Expected an error without a location")
+	}
+}
@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/nomdb/backend/internal/models"
+)
+
+// MapBounds is the visible area of a map. West is greater than East when the area
+// crosses the antimeridian.
+type MapBounds struct {
+	North, South, East, West float64
+}
+
+// width is the longitude span of the bounds in degrees
+func (b MapBounds) width() float64 {
+	if b.West > b.East {
+		return b.East - b.West + 360
+	}
+	return b.East - b.West
+}
+
+// where matches restaurants located inside the bounds
+func (b MapBounds) where() sq.Sqlizer {
+	inside := sq.And{
+		sq.Expr("r.latitude BETWEEN ? AND ?", b.South, b.North),
+	}
+	if b.West > b.East {
+		return append(inside, sq.Expr("(r.longitude >= ? OR r.longitude <= ?)", b.West, b.East))
+	}
+	return append(inside, sq.Expr("r.longitude BETWEEN ? AND ?", b.West, b.East))
+}
+
+// lngOffset is the longitude of the restaurant east of the bounds' west edge, counting
+// across the antimeridian
+func (b MapBounds) lngOffset() sq.Sqlizer {
+	return sq.Expr("(CASE WHEN r.longitude >= ? THEN r.longitude - ? ELSE r.longitude - ? + 360 END)::float8",
+		b.West, b.West, b.West)
+}
+
+func (r *restaurantRepo) MarkersInBounds(ctx context.Context, filter RestaurantFilter, bounds MapBounds, limit int) ([]models.MapMarker, error) {
+	q := psql.Select(
+		"r.id", "r.name", "r.latitude::float8", "r.longitude::float8", "r.category_id",
+		"((AVG(rt.food_rating) + AVG(rt.service_rating) + AVG(rt.ambiance_rating))::float8 / 3) AS rating",
+	).
+		From("restaurants r").
+		LeftJoin("ratings rt ON r.id = rt.restaurant_id").
+		Where(bounds.where()).
+		GroupBy("r.id").
+		OrderBy("r.id").
+		Limit(uint64(limit))
+	filter.Near = nil // The bounds replace the radius
+	query, args, err := filter.apply(q, restaurantSource).ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	markers := []models.MapMarker{}
+	for rows.Next() {
+		var m models.MapMarker
+		if err := rows.Scan(&m.ID, &m.Name, &m.Latitude, &m.Longitude, &m.CategoryID, &m.Rating); err != nil {
+			return nil, err
+		}
+		markers = append(markers, m)
+	}
+	return markers, rows.Err()
+}
+
+func (r *restaurantRepo) ClustersInBounds(ctx context.Context, filter RestaurantFilter, bounds MapBounds, gridSize int) ([]models.MapCluster, error) {
+	cellHeight := (bounds.North - bounds.South) / float64(gridSize)
+	cellWidth := bounds.width() / float64(gridSize)
+
+	// Restaurants on the north or east edge belong to the last cell
+	row := sq.Expr("LEAST(FLOOR((r.latitude::float8 - ?) / ?)::int, ?)", bounds.South, cellHeight, gridSize-1)
+	col := sq.ConcatExpr("LEAST(FLOOR(", bounds.lngOffset(), sq.Expr(" / ?)::int, ?)", cellWidth, gridSize-1))
+	q := psql.Select().
+		Column(sq.Alias(row, "cell_row")).
+		Column(sq.Alias(col, "cell_col")).
+		Columns("COUNT(*)", "AVG(r.latitude)::float8").
+		Column(sq.ConcatExpr("AVG(", bounds.lngOffset(), ")")).
+		Column("CASE WHEN COUNT(*) = 1 THEN MIN(r.id) END").
+		From("restaurants r").
+		Where(bounds.where()).
+		GroupBy("cell_row", "cell_col").
+		OrderBy("cell_row", "cell_col")
+	filter.Near = nil
+	query, args, err := filter.apply(q, restaurantSource).ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	clusters := []models.MapCluster{}
+	for rows.Next() {
+		var c models.MapCluster
+		var cellRow, cellCol int
+		var offset float64
+		if err := rows.Scan(&cellRow, &cellCol, &c.Count, &c.Latitude, &offset, &c.RestaurantID); err != nil {
+			return nil, fmt.Errorf("failed to scan cluster: %w", err)
+		}
+		c.Longitude = bounds.West + offset
+		if c.Longitude > 180 {
+			c.Longitude -= 360
+		}
+		clusters = append(clusters, c)
+	}
+	return clusters, rows.Err()
+}
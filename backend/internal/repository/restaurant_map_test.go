@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/pashagolub/pgxmock/v4"
+)
+
+func TestMapBoundsWhere(t *testing.T) {
+	tests := []struct {
+		name   string
+		bounds MapBounds
+		want   string
+		width  float64
+	}{
+		{"regular", MapBounds{North: 53, South: 52, East: 14, West: 13}, "r.longitude BETWEEN ? AND ?", 1},
+		{"antimeridian", MapBounds{North: 10, South: -10, East: -170, West: 170}, "(r.longitude >= ? OR r.longitude <= ?)", 20},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, args, err := tt.bounds.where().ToSql()
+			if err != nil {
+				t.Fatalf("ToSql failed: %v", err)
+			}
+			if !strings.Contains(query, "r.latitude BETWEEN ? AND ?") || !strings.Contains(query, tt.want) {
+				t.Errorf("Unexpected condition: %s", query)
+			}
+			if len(args) != 4 || args[0] != tt.bounds.South || args[2] != tt.bounds.West {
+				t.Errorf("Unexpected args: %v", args)
+			}
+			if got := tt.bounds.width(); got != tt.width {
+				t.Errorf("Expected width %v, got %v", tt.width, got)
+			}
+		})
+	}
+}
+
+func TestRestaurantsMarkersInBounds(t *testing.T) {
+	mock, repos := newMock(t)
+	catID := 2
+	rating := 4.5
+	bounds := MapBounds{North: 53, South: 52, East: 14, West: 13}
+
+	mock.ExpectQuery(`SELECT r\.id, r\.name, r\.latitude::float8, r\.longitude::float8, r\.category_id, .* AS rating FROM restaurants r `+
+		`LEFT JOIN ratings rt ON r\.id = rt\.restaurant_id WHERE \(r\.latitude BETWEEN \$1 AND \$2 AND r\.longitude BETWEEN \$3 AND \$4\) `+
+		`AND r\.category_id IN \(WITH RECURSIVE .* WHERE id = \$5 .*\) GROUP BY r\.id ORDER BY r\.id LIMIT 201`).
+		WithArgs(52.0, 53.0, 13.0, 14.0, 2).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "name", "latitude", "longitude", "category_id", "rating"}).
+			AddRow(1, "Luigi's", 52.5, 13.4, &catID, &rating).
+			AddRow(2, "Mario's", 52.6, 13.5, nil, nil))
+
+	markers, err := repos.Restaurants.MarkersInBounds(context.Background(), RestaurantFilter{CategoryID: &catID}, bounds, 201)
+	if err != nil {
+		t.Fatalf("MarkersInBounds failed: %v", err)
+	}
+	if len(markers) != 2 || markers[0].Rating == nil || *markers[0].Rating != 4.5 || markers[1].Rating != nil {
+		t.Errorf("Unexpected markers: %+v", markers)
+	}
+}
+
+func TestRestaurantsClustersInBounds(t *testing.T) {
+	mock, repos := newMock(t)
+	id := 7
+	// Crosses the antimeridian: 20 degrees wide, 2 cells of 10
+	bounds := MapBounds{North: 10, South: -10, East: -170, West: 170}
+
+	mock.ExpectQuery(`SELECT \(LEAST\(FLOOR\(\(r\.latitude::float8 - \$1\) / \$2\)::int, \$3\)\) AS cell_row, `+
+		`\(LEAST\(FLOOR\(\(CASE WHEN r\.longitude >= \$4 .* / \$7\)::int, \$8\)\) AS cell_col, COUNT\(\*\), .* `+
+		`FROM restaurants r WHERE .* GROUP BY cell_row, cell_col ORDER BY cell_row, cell_col`).
+		WithArgs(-10.0, 10.0, 1, 170.0, 170.0, 170.0, 10.0, 1, 170.0, 170.0, 170.0, -10.0, 10.0, 170.0, -170.0).
+		WillReturnRows(pgxmock.NewRows([]string{"cell_row", "cell_col", "count", "lat", "lng_offset", "restaurant_id"}).
+			AddRow(0, 0, 120, -5.0, 5.0, nil).
+			AddRow(1, 1, 1, 5.0, 15.0, &id))
+
+	clusters, err := repos.Restaurants.ClustersInBounds(context.Background(), RestaurantFilter{}, bounds, 2)
+	if err != nil {
+		t.Fatalf("ClustersInBounds failed: %v", err)
+	}
+	if len(clusters) != 2 {
+		t.Fatalf("Expected 2 clusters, got %d", len(clusters))
+	}
+	if clusters[0].Count != 120 || clusters[0].Longitude != 175 || clusters[0].RestaurantID != nil {
+		t.Errorf("Unexpected cluster: %+v", clusters[0])
+	}
+	// Offsets past the antimeridian wrap around to negative longitudes
+	if clusters[1].Longitude != -175 || clusters[1].RestaurantID == nil || *clusters[1].RestaurantID != 7 {
+		t.Errorf("Unexpected cluster: %+v", clusters[1])
+	}
+}
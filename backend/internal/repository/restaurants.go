@@ -0,0 +1,217 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/nomdb/backend/internal/models"
+)
+
+// RestaurantRepository reads and writes restaurants and their food types
+type RestaurantRepository interface {
+	// GetByID returns a restaurant with its category, average rating and food types
+	GetByID(ctx context.Context, id int) (*models.Restaurant, error)
+	// Exists reports whether the restaurant exists in the space
+	Exists(ctx context.Context, spaceID, id int) (bool, error)
+	// SuggestedBy returns the author of the suggestion the restaurant was converted from,
+	// nil if there is none or it was anonymous
+	SuggestedBy(ctx context.Context, id int) (*int, error)
+	// FindExisting returns the ID of a restaurant of the space with the Google Place ID,
+	// or else with the name and address (case-insensitive); ErrNotFound if there is none
+	FindExisting(ctx context.Context, spaceID int, name string, address, googlePlaceID *string) (int, error)
+	Create(ctx context.Context, req models.CreateRestaurantRequest) (*models.Restaurant, error)
+	// Update changes the fields set in req; ErrNotFound if the restaurant does not exist
+	Update(ctx context.Context, id int, req models.UpdateRestaurantRequest) (*models.Restaurant, error)
+	// UpdateBusiness changes the business details set in req, clearing the phone or
+	// website if empty; ErrNotFound if the restaurant does not exist
+	UpdateBusiness(ctx context.Context, id int, req models.UpdateRestaurantBusinessRequest) (*models.Restaurant, error)
+	Delete(ctx context.Context, id int) error
+	FoodTypes(ctx context.Context, id int) ([]models.FoodType, error)
+	// SetFoodTypes replaces the food types of a restaurant, only deleting and inserting
+	// the differences
+	SetFoodTypes(ctx context.Context, id int, foodTypeIDs []int) error
+	// ListWithSuggestions returns the restaurants and pending suggestions matching the
+	// filter, nearest first when filtering by distance and otherwise newest first
+	ListWithSuggestions(ctx context.Context, filter RestaurantFilter) ([]models.Restaurant, error)
+	// ListNearby returns up to limit restaurants matching the filter, which must set
+	// Near, nearest first
+	ListNearby(ctx context.Context, filter RestaurantFilter, limit int) ([]models.Restaurant, error)
+	// ListPage returns up to limit restaurants matching the filter in the sort order,
+	// starting after the key (nil for the first page), and the key of the next page if
+	// there is one
+	ListPage(ctx context.Context, filter RestaurantFilter, sort RestaurantSort, after *PageKey, limit int) ([]models.Restaurant, *PageKey, error)
+	// MarkersInBounds returns up to limit restaurants inside the bounds matching the
+	// filter, by ID
+	MarkersInBounds(ctx context.Context, filter RestaurantFilter, bounds MapBounds, limit int) ([]models.MapMarker, error)
+	// ClustersInBounds groups the restaurants inside the bounds matching the filter
+	// into a gridSize x gridSize grid, returning the non-empty cells
+	ClustersInBounds(ctx context.Context, filter RestaurantFilter, bounds MapBounds, gridSize int) ([]models.MapCluster, error)
+}
+
+type restaurantRepo struct {
+	db DB
+}
+
+const restaurantReturning = `RETURNING id, name, description, address, phone, website, latitude, longitude, google_place_id, category_id, created_at, updated_at, business_status, opening_hours`
+
+func scanRestaurant(row interface{ Scan(...any) error }, rest *models.Restaurant, extra ...any) error {
+	return row.Scan(append([]any{
+		&rest.ID, &rest.Name, &rest.Description, &rest.Address, &rest.Phone, &rest.Website, &rest.Latitude, &rest.Longitude,
+		&rest.GooglePlaceID, &rest.CategoryID, &rest.CreatedAt, &rest.UpdatedAt, &rest.BusinessStatus, &rest.OpeningHours,
+	}, extra...)...)
+}
+
+func (r *restaurantRepo) GetByID(ctx context.Context, id int) (*models.Restaurant, error) {
+	var rest models.Restaurant
+	var catID *int
+	var catName *string
+	var avgFood, avgService, avgAmbiance float64
+	var ratingCount int
+
+	err := scanRestaurant(r.db.QueryRow(ctx,
+		`SELECT
+			r.id, r.name, r.description, r.address, r.phone, r.website, r.latitude, r.longitude,
+			r.google_place_id, r.category_id, r.created_at, r.updated_at, r.business_status, r.opening_hours,
+			c.id, c.name,
+			COALESCE(AVG(rt.food_rating), 0) as avg_food,
+			COALESCE(AVG(rt.service_rating), 0) as avg_service,
+			COALESCE(AVG(rt.ambiance_rating), 0) as avg_ambiance,
+			COUNT(rt.id) as rating_count
+		FROM restaurants r
+		LEFT JOIN categories c ON r.category_id = c.id
+		LEFT JOIN ratings rt ON r.id = rt.restaurant_id
+		WHERE r.id = $1
+		GROUP BY r.id, c.id`, id),
+		&rest, &catID, &catName, &avgFood, &avgService, &avgAmbiance, &ratingCount)
+	if err != nil {
+		return nil, notFound(err)
+	}
+
+	if catID != nil && catName != nil {
+		rest.Category = &models.Category{ID: *catID, Name: *catName}
+	}
+	if ratingCount > 0 {
+		rest.AvgRating = &models.AvgRating{
+			Food:     avgFood,
+			Service:  avgService,
+			Ambiance: avgAmbiance,
+			Overall:  (avgFood + avgService + avgAmbiance) / 3,
+			Count:    ratingCount,
+		}
+	}
+
+	if rest.FoodTypes, err = r.FoodTypes(ctx, rest.ID); err != nil {
+		return nil, err
+	}
+	return &rest, nil
+}
+
+func (r *restaurantRepo) Exists(ctx context.Context, spaceID, id int) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow(ctx, "SELECT EXISTS(SELECT 1 FROM restaurants WHERE id = $1 AND space_id = $2)", id, spaceID).Scan(&exists)
+	return exists, err
+}
+
+func (r *restaurantRepo) SuggestedBy(ctx context.Context, id int) (*int, error) {
+	var userID *int
+	if err := r.db.QueryRow(ctx, "SELECT suggested_by_user_id FROM restaurants WHERE id = $1", id).Scan(&userID); err != nil {
+		return nil, notFound(err)
+	}
+	return userID, nil
+}
+
+func (r *restaurantRepo) FindExisting(ctx context.Context, spaceID int, name string, address, googlePlaceID *string) (int, error) {
+	var id int
+	var err error
+	switch {
+	case googlePlaceID != nil && *googlePlaceID != "":
+		err = r.db.QueryRow(ctx, "SELECT id FROM restaurants WHERE space_id = $1 AND google_place_id = $2",
+			spaceID, *googlePlaceID).Scan(&id)
+	case address != nil && *address != "":
+		err = r.db.QueryRow(ctx,
+			"SELECT id FROM restaurants WHERE space_id = $1 AND LOWER(name) = LOWER($2) AND LOWER(address) = LOWER($3)",
+			spaceID, name, *address).Scan(&id)
+	default:
+		return 0, ErrNotFound
+	}
+	return id, notFound(err)
+}
+
+func (r *restaurantRepo) Create(ctx context.Context, req models.CreateRestaurantRequest) (*models.Restaurant, error) {
+	var rest models.Restaurant
+	err := scanRestaurant(r.db.QueryRow(ctx,
+		`INSERT INTO restaurants (name, description, address, phone, website, latitude, longitude, google_place_id, category_id, space_id, suggested_by_user_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		`+restaurantReturning,
+		req.Name, req.Description, req.Address, req.Phone, req.Website, req.Latitude, req.Longitude, req.GooglePlaceID, req.CategoryID, spaceOrDefault(req.SpaceID), req.SuggestedBy,
+	), &rest)
+	if err != nil {
+		return nil, err
+	}
+	return &rest, nil
+}
+
+func (r *restaurantRepo) Update(ctx context.Context, id int, req models.UpdateRestaurantRequest) (*models.Restaurant, error) {
+	var rest models.Restaurant
+	err := scanRestaurant(r.db.QueryRow(ctx,
+		`UPDATE restaurants SET
+			name = COALESCE($1, name),
+			description = COALESCE($2, description),
+			address = COALESCE($3, address),
+			phone = COALESCE($4, phone),
+			website = COALESCE($5, website),
+			latitude = COALESCE($6, latitude),
+			longitude = COALESCE($7, longitude),
+			google_place_id = COALESCE($8, google_place_id),
+			category_id = COALESCE($9, category_id),
+			updated_at = NOW()
+		WHERE id = $10
+		`+restaurantReturning,
+		req.Name, req.Description, req.Address, req.Phone, req.Website, req.Latitude, req.Longitude, req.GooglePlaceID, req.CategoryID, id,
+	), &rest)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return &rest, nil
+}
+
+func (r *restaurantRepo) UpdateBusiness(ctx context.Context, id int, req models.UpdateRestaurantBusinessRequest) (*models.Restaurant, error) {
+	var rest models.Restaurant
+	err := scanRestaurant(r.db.QueryRow(ctx,
+		`UPDATE restaurants SET
+			phone = CASE WHEN $1::text IS NULL THEN phone ELSE NULLIF($1, '') END,
+			website = CASE WHEN $2::text IS NULL THEN website ELSE NULLIF($2, '') END,
+			opening_hours = CASE WHEN $3::text[] IS NULL THEN opening_hours ELSE NULLIF($3, '{}') END,
+			updated_at = NOW()
+		WHERE id = $4
+		`+restaurantReturning,
+		req.Phone, req.Website, req.OpeningHours, id,
+	), &rest)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return &rest, nil
+}
+
+func (r *restaurantRepo) Delete(ctx context.Context, id int) error {
+	result, err := r.db.Exec(ctx, "DELETE FROM restaurants WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *restaurantRepo) FoodTypes(ctx context.Context, id int) ([]models.FoodType, error) {
+	return queryFoodTypes(ctx, r.db,
+		`SELECT ft.id, ft.name, ft.created_at, ft.updated_at
+		FROM food_types ft
+		JOIN restaurant_food_types rft ON ft.id = rft.food_type_id
+		WHERE rft.restaurant_id = $1
+		ORDER BY ft.name`, id)
+}
+
+func (r *restaurantRepo) SetFoodTypes(ctx context.Context, id int, foodTypeIDs []int) error {
+	return setFoodTypes(ctx, r.db, "restaurant_food_types", "restaurant_id", id, foodTypeIDs)
+}
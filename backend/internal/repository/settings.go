@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+// StoredSetting is an override of a runtime setting
+type StoredSetting struct {
+	Key             string
+	Value           string
+	UpdatedByUserID *int
+	UpdatedAt       time.Time
+}
+
+// SettingRepository stores the runtime settings admins changed
+type SettingRepository interface {
+	// List returns all overrides
+	List(ctx context.Context) ([]StoredSetting, error)
+	// Set stores an override of key, replacing any previous one
+	Set(ctx context.Context, key, value string, userID *int) (StoredSetting, error)
+	// Delete removes the override of key, or returns ErrNotFound
+	Delete(ctx context.Context, key string) error
+}
+
+type settingRepo struct {
+	db DB
+}
+
+func (r *settingRepo) List(ctx context.Context) ([]StoredSetting, error) {
+	rows, err := r.db.Query(ctx, `SELECT key, value, updated_by_user_id, updated_at FROM settings ORDER BY key`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var settings []StoredSetting
+	for rows.Next() {
+		var s StoredSetting
+		if err := rows.Scan(&s.Key, &s.Value, &s.UpdatedByUserID, &s.UpdatedAt); err != nil {
+			return nil, err
+		}
+		settings = append(settings, s)
+	}
+	return settings, rows.Err()
+}
+
+func (r *settingRepo) Set(ctx context.Context, key, value string, userID *int) (StoredSetting, error) {
+	s := StoredSetting{Key: key, Value: value, UpdatedByUserID: userID}
+	err := r.db.QueryRow(ctx,
+		`INSERT INTO settings (key, value, updated_by_user_id) VALUES ($1, $2, $3)
+		ON CONFLICT (key) DO UPDATE SET value = EXCLUDED.value, updated_by_user_id = EXCLUDED.updated_by_user_id, updated_at = NOW()
+		RETURNING updated_at`, key, value, userID).Scan(&s.UpdatedAt)
+	return s, err
+}
+
+func (r *settingRepo) Delete(ctx context.Context, key string) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM settings WHERE key = $1`, key)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pashagolub/pgxmock/v4"
+)
+
+func TestSettingSet(t *testing.T) {
+	mock, repos := newMock(t)
+	userID := 1
+	updatedAt := time.Now()
+
+	mock.ExpectQuery(`INSERT INTO settings .* ON CONFLICT \(key\) DO UPDATE`).
+		WithArgs("suggestions_enabled", "false", &userID).
+		WillReturnRows(pgxmock.NewRows([]string{"updated_at"}).AddRow(updatedAt))
+
+	s, err := repos.Settings.Set(context.Background(), "suggestions_enabled", "false", &userID)
+	if err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	if s.Value != "false" || *s.UpdatedByUserID != 1 || !s.UpdatedAt.Equal(updatedAt) {
+		t.Errorf("Unexpected setting %+v", s)
+	}
+}
+
+func TestSettingDelete(t *testing.T) {
+	mock, repos := newMock(t)
+
+	mock.ExpectExec(`DELETE FROM settings WHERE key = \$1`).WithArgs("rate_limit_read").
+		WillReturnResult(pgxmock.NewResult("DELETE", 0))
+
+	if err := repos.Settings.Delete(context.Background(), "rate_limit_read"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
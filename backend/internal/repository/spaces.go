@@ -0,0 +1,370 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/nomdb/backend/internal/database"
+	"github.com/nomdb/backend/internal/models"
+)
+
+// DefaultSpaceID is the space of the data created before spaces existed, used by
+// requests that don't choose a space
+const DefaultSpaceID = 1
+
+// Roles of space members
+const (
+	SpaceRoleOwner  = "owner"  // Manages the space and its members, and may delete it
+	SpaceRoleAdmin  = "admin"  // Manages members and moderates the space
+	SpaceRoleMember = "member" // Adds restaurants, suggestions, ratings and photos
+)
+
+// Entities whose space SpaceOf looks up
+const (
+	SpaceOfRestaurant = "restaurant"
+	SpaceOfSuggestion = "suggestion"
+	SpaceOfRating     = "rating"
+	SpaceOfPhoto      = "photo"
+)
+
+var spaceOfQueries = map[string]string{
+	SpaceOfRestaurant: `SELECT space_id FROM restaurants WHERE id = $1`,
+	SpaceOfSuggestion: `SELECT space_id FROM restaurant_suggestions WHERE id = $1`,
+	SpaceOfRating:     `SELECT r.space_id FROM ratings rt JOIN restaurants r ON r.id = rt.restaurant_id WHERE rt.id = $1`,
+	SpaceOfPhoto:      `SELECT r.space_id FROM menu_photos p JOIN restaurants r ON r.id = p.restaurant_id WHERE p.id = $1`,
+}
+
+var (
+	// ErrLastOwner is returned when removing or demoting the only owner of a space
+	ErrLastOwner = errors.New("a space needs an owner")
+	// ErrAlreadyMember is returned when joining a space the user is a member of
+	ErrAlreadyMember = errors.New("already a member")
+)
+
+// SpaceRepository reads and writes spaces, their members and their invites
+type SpaceRepository interface {
+	// Get returns the space with the slug and the role of userID in it (0 for anonymous
+	// users); ErrNotFound if there is none
+	Get(ctx context.Context, slug string, userID int) (*models.Space, error)
+	// List returns the public spaces and those userID is a member of, or all spaces if
+	// all is set, by name
+	List(ctx context.Context, userID int, all bool) ([]models.Space, error)
+	// Create stores a space with userID as its owner
+	Create(ctx context.Context, req models.CreateSpaceRequest, userID int) (*models.Space, error)
+	// Update changes the fields set in req; ErrNotFound if the space does not exist
+	Update(ctx context.Context, id int, req models.UpdateSpaceRequest) (*models.Space, error)
+	// Delete deletes a space with its restaurants, suggestions, members and invites
+	Delete(ctx context.Context, id int) error
+	// SpaceOf returns the space of an entity (one of the SpaceOf constants); ErrNotFound
+	// if it does not exist
+	SpaceOf(ctx context.Context, entity string, id int) (int, error)
+	// IsPublic reports whether a space is public; ErrNotFound if it does not exist
+	IsPublic(ctx context.Context, id int) (bool, error)
+
+	// Members returns the members of a space by username
+	Members(ctx context.Context, spaceID int) ([]models.SpaceMember, error)
+	// SetMember adds a user to a space or changes their role; ErrNotFound if the user
+	// does not exist, ErrLastOwner if it would leave the space without an owner
+	SetMember(ctx context.Context, spaceID, userID int, role string) (*models.SpaceMember, error)
+	// RemoveMember removes a user from a space; ErrNotFound if they are not a member,
+	// ErrLastOwner if they are its only owner
+	RemoveMember(ctx context.Context, spaceID, userID int) error
+
+	// CreateInvite stores an invite with the SHA-256 hash of its code
+	CreateInvite(ctx context.Context, invite models.SpaceInvite, codeHash string) (*models.SpaceInvite, error)
+	// Invites returns the invites of a space, newest first
+	Invites(ctx context.Context, spaceID int) ([]models.SpaceInvite, error)
+	// RevokeInvite revokes an invite of a space; ErrNotFound if there is none
+	RevokeInvite(ctx context.Context, spaceID, id int) error
+	// Join redeems a valid invite with the code hash, adding userID to its space with
+	// its role; ErrNotFound if there is no such invite, ErrAlreadyMember if userID is a
+	// member already
+	Join(ctx context.Context, codeHash string, userID int) (*models.Space, error)
+}
+
+type spaceRepo struct {
+	db DB
+}
+
+const spaceColumns = `s.id, s.slug, s.name, s.public, s.created_by_user_id, s.created_at, s.updated_at`
+
+func scanSpace(row interface{ Scan(...any) error }, space *models.Space, extra ...any) error {
+	return row.Scan(append([]any{
+		&space.ID, &space.Slug, &space.Name, &space.Public, &space.CreatedByUserID, &space.CreatedAt, &space.UpdatedAt,
+	}, extra...)...)
+}
+
+const spaceInviteColumns = `id, space_id, code_prefix, role, max_uses, use_count, expires_at, created_by_user_id, revoked_at, created_at`
+
+func scanSpaceInvite(row interface{ Scan(...any) error }, invite *models.SpaceInvite) error {
+	return row.Scan(&invite.ID, &invite.SpaceID, &invite.CodePrefix, &invite.Role, &invite.MaxUses, &invite.UseCount,
+		&invite.ExpiresAt, &invite.CreatedByUserID, &invite.RevokedAt, &invite.CreatedAt)
+}
+
+// spaceOrDefault returns the default space for requests without one
+func spaceOrDefault(spaceID int) int {
+	if spaceID == 0 {
+		return DefaultSpaceID
+	}
+	return spaceID
+}
+
+func (r *spaceRepo) Get(ctx context.Context, slug string, userID int) (*models.Space, error) {
+	var space models.Space
+	err := scanSpace(r.db.QueryRow(ctx,
+		`SELECT `+spaceColumns+`, m.role
+		FROM spaces s
+		LEFT JOIN space_members m ON m.space_id = s.id AND m.user_id = $2
+		WHERE s.slug = $1`, slug, userID), &space, &space.Role)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return &space, nil
+}
+
+func (r *spaceRepo) List(ctx context.Context, userID int, all bool) ([]models.Space, error) {
+	rows, err := r.db.Query(ctx,
+		`SELECT `+spaceColumns+`, m.role
+		FROM spaces s
+		LEFT JOIN space_members m ON m.space_id = s.id AND m.user_id = $1
+		WHERE s.public OR m.role IS NOT NULL OR $2
+		ORDER BY s.name`, userID, all)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	spaces := []models.Space{}
+	for rows.Next() {
+		var space models.Space
+		if err := scanSpace(rows, &space, &space.Role); err != nil {
+			return nil, err
+		}
+		spaces = append(spaces, space)
+	}
+	return spaces, rows.Err()
+}
+
+func (r *spaceRepo) Create(ctx context.Context, req models.CreateSpaceRequest, userID int) (*models.Space, error) {
+	var space models.Space
+	err := scanSpace(r.db.QueryRow(ctx,
+		`WITH s AS (
+			INSERT INTO spaces (slug, name, public, created_by_user_id)
+			VALUES ($1, $2, $3, $4)
+			RETURNING *
+		), m AS (
+			INSERT INTO space_members (space_id, user_id, role)
+			SELECT id, $4, 'owner' FROM s
+		)
+		SELECT `+spaceColumns+` FROM s`, req.Slug, req.Name, req.Public, userID), &space)
+	if err != nil {
+		return nil, err
+	}
+	owner := SpaceRoleOwner
+	space.Role = &owner
+	return &space, nil
+}
+
+func (r *spaceRepo) Update(ctx context.Context, id int, req models.UpdateSpaceRequest) (*models.Space, error) {
+	var space models.Space
+	err := scanSpace(r.db.QueryRow(ctx,
+		`UPDATE spaces s SET
+			name = COALESCE($2, name),
+			public = COALESCE($3, public),
+			updated_at = NOW()
+		WHERE id = $1
+		RETURNING `+spaceColumns, id, req.Name, req.Public), &space)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return &space, nil
+}
+
+func (r *spaceRepo) Delete(ctx context.Context, id int) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM spaces WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *spaceRepo) SpaceOf(ctx context.Context, entity string, id int) (int, error) {
+	var spaceID int
+	err := r.db.QueryRow(ctx, spaceOfQueries[entity], id).Scan(&spaceID)
+	return spaceID, notFound(err)
+}
+
+func (r *spaceRepo) IsPublic(ctx context.Context, id int) (bool, error) {
+	var public bool
+	err := r.db.QueryRow(ctx, "SELECT public FROM spaces WHERE id = $1", id).Scan(&public)
+	return public, notFound(err)
+}
+
+func (r *spaceRepo) Members(ctx context.Context, spaceID int) ([]models.SpaceMember, error) {
+	rows, err := r.db.Query(ctx,
+		`SELECT m.user_id, u.username, m.role, m.created_at
+		FROM space_members m
+		JOIN users u ON u.id = m.user_id
+		WHERE m.space_id = $1
+		ORDER BY u.username`, spaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	members := []models.SpaceMember{}
+	for rows.Next() {
+		var m models.SpaceMember
+		if err := rows.Scan(&m.UserID, &m.Username, &m.Role, &m.CreatedAt); err != nil {
+			return nil, err
+		}
+		members = append(members, m)
+	}
+	return members, rows.Err()
+}
+
+// lockOwners locks the owners of a space until the end of tx, so concurrent changes
+// can't remove the last one, and returns their user IDs
+func lockOwners(ctx context.Context, tx pgx.Tx, spaceID int) ([]int, error) {
+	rows, err := tx.Query(ctx,
+		`SELECT user_id FROM space_members WHERE space_id = $1 AND role = 'owner' FOR UPDATE`, spaceID)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowTo[int])
+}
+
+// isLastOwner reports whether userID is the only one of the owners
+func isLastOwner(owners []int, userID int) bool {
+	return len(owners) == 1 && owners[0] == userID
+}
+
+func (r *spaceRepo) SetMember(ctx context.Context, spaceID, userID int, role string) (*models.SpaceMember, error) {
+	var member models.SpaceMember
+	err := database.RunInTx(ctx, r.db, func(tx pgx.Tx) error {
+		owners, err := lockOwners(ctx, tx, spaceID)
+		if err != nil {
+			return err
+		}
+		if role != SpaceRoleOwner && isLastOwner(owners, userID) {
+			return ErrLastOwner
+		}
+		return tx.QueryRow(ctx,
+			`WITH m AS (
+				INSERT INTO space_members (space_id, user_id, role)
+				SELECT $1, id, $3 FROM users WHERE id = $2
+				ON CONFLICT (space_id, user_id) DO UPDATE SET role = EXCLUDED.role
+				RETURNING *
+			)
+			SELECT m.user_id, u.username, m.role, m.created_at FROM m JOIN users u ON u.id = m.user_id`,
+			spaceID, userID, role).Scan(&member.UserID, &member.Username, &member.Role, &member.CreatedAt)
+	})
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return &member, nil
+}
+
+func (r *spaceRepo) RemoveMember(ctx context.Context, spaceID, userID int) error {
+	return database.RunInTx(ctx, r.db, func(tx pgx.Tx) error {
+		owners, err := lockOwners(ctx, tx, spaceID)
+		if err != nil {
+			return err
+		}
+		if isLastOwner(owners, userID) {
+			return ErrLastOwner
+		}
+		tag, err := tx.Exec(ctx, `DELETE FROM space_members WHERE space_id = $1 AND user_id = $2`, spaceID, userID)
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return ErrNotFound
+		}
+		return nil
+	})
+}
+
+func (r *spaceRepo) CreateInvite(ctx context.Context, invite models.SpaceInvite, codeHash string) (*models.SpaceInvite, error) {
+	var created models.SpaceInvite
+	err := scanSpaceInvite(r.db.QueryRow(ctx,
+		`INSERT INTO space_invites (space_id, code_hash, code_prefix, role, max_uses, expires_at, created_by_user_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING `+spaceInviteColumns,
+		invite.SpaceID, codeHash, invite.CodePrefix, invite.Role, invite.MaxUses, invite.ExpiresAt, invite.CreatedByUserID,
+	), &created)
+	if err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+func (r *spaceRepo) Invites(ctx context.Context, spaceID int) ([]models.SpaceInvite, error) {
+	rows, err := r.db.Query(ctx,
+		`SELECT `+spaceInviteColumns+` FROM space_invites WHERE space_id = $1 ORDER BY created_at DESC`, spaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	invites := []models.SpaceInvite{}
+	for rows.Next() {
+		var invite models.SpaceInvite
+		if err := scanSpaceInvite(rows, &invite); err != nil {
+			return nil, err
+		}
+		invites = append(invites, invite)
+	}
+	return invites, rows.Err()
+}
+
+func (r *spaceRepo) RevokeInvite(ctx context.Context, spaceID, id int) error {
+	tag, err := r.db.Exec(ctx,
+		`UPDATE space_invites SET revoked_at = COALESCE(revoked_at, NOW()) WHERE id = $1 AND space_id = $2`, id, spaceID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *spaceRepo) Join(ctx context.Context, codeHash string, userID int) (*models.Space, error) {
+	var space models.Space
+	err := database.RunInTx(ctx, r.db, func(tx pgx.Tx) error {
+		var inviteID int
+		var role string
+		err := scanSpace(tx.QueryRow(ctx,
+			`SELECT `+spaceColumns+`, i.id, i.role
+			FROM space_invites i
+			JOIN spaces s ON s.id = i.space_id
+			WHERE i.code_hash = $1 AND i.revoked_at IS NULL
+			AND (i.expires_at IS NULL OR i.expires_at > NOW()) AND i.use_count < i.max_uses
+			FOR UPDATE OF i`, codeHash), &space, &inviteID, &role)
+		if err != nil {
+			return err
+		}
+		space.Role = &role
+
+		tag, err := tx.Exec(ctx,
+			`INSERT INTO space_members (space_id, user_id, role) VALUES ($1, $2, $3)
+			ON CONFLICT (space_id, user_id) DO NOTHING`, space.ID, userID, role)
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return ErrAlreadyMember
+		}
+		_, err = tx.Exec(ctx, `UPDATE space_invites SET use_count = use_count + 1 WHERE id = $1`, inviteID)
+		return err
+	})
+	if err != nil {
+		return nil, notFound(err)
+	}
+	return &space, nil
+}
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pashagolub/pgxmock/v4"
+)
+
+func TestSpaceSetMember_LastOwner(t *testing.T) {
+	mock, repos := newMock(t)
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT user_id FROM space_members WHERE space_id = \$1 AND role = 'owner' FOR UPDATE`).WithArgs(2).
+		WillReturnRows(pgxmock.NewRows([]string{"user_id"}).AddRow(5))
+	mock.ExpectRollback()
+
+	if _, err := repos.Spaces.SetMember(context.Background(), 2, 5, SpaceRoleAdmin); !errors.Is(err, ErrLastOwner) {
+		t.Errorf("Expected ErrLastOwner, got %v", err)
+	}
+}
+
+func TestSpaceSetMember_UnknownUser(t *testing.T) {
+	mock, repos := newMock(t)
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FOR UPDATE`).WithArgs(2).
+		WillReturnRows(pgxmock.NewRows([]string{"user_id"}).AddRow(5))
+	mock.ExpectQuery(`INSERT INTO space_members .* ON CONFLICT \(space_id, user_id\) DO UPDATE`).WithArgs(2, 99, SpaceRoleMember).
+		WillReturnRows(pgxmock.NewRows([]string{"user_id", "username", "role", "created_at"}))
+	mock.ExpectRollback()
+
+	if _, err := repos.Spaces.SetMember(context.Background(), 2, 99, SpaceRoleMember); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestSpaceRemoveMember(t *testing.T) {
+	t.Run("one of several owners", func(t *testing.T) {
+		mock, repos := newMock(t)
+		mock.ExpectBegin()
+		mock.ExpectQuery(`FOR UPDATE`).WithArgs(2).
+			WillReturnRows(pgxmock.NewRows([]string{"user_id"}).AddRow(5).AddRow(6))
+		mock.ExpectExec(`DELETE FROM space_members WHERE space_id = \$1 AND user_id = \$2`).WithArgs(2, 5).
+			WillReturnResult(pgxmock.NewResult("DELETE", 1))
+		mock.ExpectCommit()
+
+		if err := repos.Spaces.RemoveMember(context.Background(), 2, 5); err != nil {
+			t.Errorf("RemoveMember failed: %v", err)
+		}
+	})
+
+	t.Run("not a member", func(t *testing.T) {
+		mock, repos := newMock(t)
+		mock.ExpectBegin()
+		mock.ExpectQuery(`FOR UPDATE`).WithArgs(2).
+			WillReturnRows(pgxmock.NewRows([]string{"user_id"}).AddRow(5))
+		mock.ExpectExec(`DELETE FROM space_members`).WithArgs(2, 8).
+			WillReturnResult(pgxmock.NewResult("DELETE", 0))
+		mock.ExpectRollback()
+
+		if err := repos.Spaces.RemoveMember(context.Background(), 2, 8); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Expected ErrNotFound, got %v", err)
+		}
+	})
+}
+
+func TestSpaceJoin(t *testing.T) {
+	joinColumns := []string{"id", "slug", "name", "public", "created_by_user_id", "created_at", "updated_at", "i.id", "i.role"}
+	now := time.Now()
+
+	t.Run("redeems the invite", func(t *testing.T) {
+		mock, repos := newMock(t)
+		mock.ExpectBegin()
+		mock.ExpectQuery(`FROM space_invites i .* FOR UPDATE OF i`).WithArgs("hash").
+			WillReturnRows(pgxmock.NewRows(joinColumns).AddRow(2, "office", "Office", false, (*int)(nil), now, now, 11, SpaceRoleMember))
+		mock.ExpectExec(`INSERT INTO space_members`).WithArgs(2, 7, SpaceRoleMember).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		mock.ExpectExec(`UPDATE space_invites SET use_count = use_count \+ 1`).WithArgs(11).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+		mock.ExpectCommit()
+
+		space, err := repos.Spaces.Join(context.Background(), "hash", 7)
+		if err != nil {
+			t.Fatalf("Join failed: %v", err)
+		}
+		if space.Slug != "office" || space.Role == nil || *space.Role != SpaceRoleMember {
+			t.Errorf("Unexpected space %+v", space)
+		}
+	})
+
+	t.Run("already a member", func(t *testing.T) {
+		mock, repos := newMock(t)
+		mock.ExpectBegin()
+		mock.ExpectQuery(`FROM space_invites i`).WithArgs("hash").
+			WillReturnRows(pgxmock.NewRows(joinColumns).AddRow(2, "office", "Office", false, (*int)(nil), now, now, 11, SpaceRoleAdmin))
+		mock.ExpectExec(`INSERT INTO space_members`).WithArgs(2, 7, SpaceRoleAdmin).
+			WillReturnResult(pgxmock.NewResult("INSERT", 0))
+		mock.ExpectRollback()
+
+		// The invite is not used up
+		if _, err := repos.Spaces.Join(context.Background(), "hash", 7); !errors.Is(err, ErrAlreadyMember) {
+			t.Errorf("Expected ErrAlreadyMember, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/nomdb/backend/internal/models"
+)
+
+// StatsRepository aggregates the contributions of users to a space
+type StatsRepository interface {
+	// Leaderboard returns the top limit users of the space by ratings, converted
+	// suggestions and photos since the given time, or of all time if nil
+	Leaderboard(ctx context.Context, spaceID int, since *time.Time, limit int) (*models.Leaderboard, error)
+	// UserStats counts the contributions of a user to the space, in total and since the
+	// start of the month; ErrNotFound if the user does not exist
+	UserStats(ctx context.Context, spaceID, userID int, monthStart time.Time) (*models.UserStats, error)
+}
+
+type statsRepo struct {
+	db DB
+}
+
+// Rankings of the users of space $1 by contributions created since $2 (NULL for all
+// time). Service accounts and deactivated users are left out.
+const (
+	leaderboardRatings = `SELECT u.id, u.username, u.avatar_url, COUNT(*)
+		FROM ratings rt
+		JOIN restaurants r ON r.id = rt.restaurant_id
+		JOIN users u ON u.id = rt.user_id
+		WHERE r.space_id = $1 AND ($2::timestamptz IS NULL OR rt.created_at >= $2)
+			AND u.is_active AND u.provider <> 'service'
+		GROUP BY u.id
+		ORDER BY COUNT(*) DESC, LOWER(u.username)
+		LIMIT $3`
+	// Converted suggestions are deleted, and credited through the restaurant they became
+	leaderboardConvertedSuggestions = `SELECT u.id, u.username, u.avatar_url, COUNT(*)
+		FROM restaurants r
+		JOIN users u ON u.id = r.suggested_by_user_id
+		WHERE r.space_id = $1 AND ($2::timestamptz IS NULL OR r.created_at >= $2)
+			AND u.is_active AND u.provider <> 'service'
+		GROUP BY u.id
+		ORDER BY COUNT(*) DESC, LOWER(u.username)
+		LIMIT $3`
+	// Only photos shown on the restaurant count
+	leaderboardPhotos = `SELECT u.id, u.username, u.avatar_url, COUNT(*)
+		FROM menu_photos p
+		JOIN restaurants r ON r.id = p.restaurant_id
+		JOIN users u ON u.id = p.uploaded_by_user_id
+		WHERE r.space_id = $1 AND ($2::timestamptz IS NULL OR p.created_at >= $2)
+			AND p.status = 'ready' AND p.moderation_status = 'approved'
+			AND u.is_active AND u.provider <> 'service'
+		GROUP BY u.id
+		ORDER BY COUNT(*) DESC, LOWER(u.username)
+		LIMIT $3`
+)
+
+func (r *statsRepo) Leaderboard(ctx context.Context, spaceID int, since *time.Time, limit int) (*models.Leaderboard, error) {
+	board := &models.Leaderboard{Since: since}
+	for _, ranking := range []struct {
+		query   string
+		entries *[]models.LeaderboardEntry
+	}{
+		{leaderboardRatings, &board.Ratings},
+		{leaderboardConvertedSuggestions, &board.ConvertedSuggestions},
+		{leaderboardPhotos, &board.Photos},
+	} {
+		entries, err := r.ranking(ctx, ranking.query, spaceID, since, limit)
+		if err != nil {
+			return nil, err
+		}
+		*ranking.entries = entries
+	}
+	return board, nil
+}
+
+func (r *statsRepo) ranking(ctx context.Context, query string, spaceID int, since *time.Time, limit int) ([]models.LeaderboardEntry, error) {
+	rows, err := r.db.Query(ctx, query, spaceID, since, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []models.LeaderboardEntry{}
+	for rows.Next() {
+		var entry models.LeaderboardEntry
+		if err := rows.Scan(&entry.UserID, &entry.Username, &entry.AvatarURL, &entry.Count); err != nil {
+			return nil, err
+		}
+		entry.Rank = len(entries) + 1
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+func (r *statsRepo) UserStats(ctx context.Context, spaceID, userID int, monthStart time.Time) (*models.UserStats, error) {
+	var stats models.UserStats
+	total, recent := &stats.Total, &stats.ThisMonth
+	err := r.db.QueryRow(ctx,
+		`SELECT u.id, u.username, u.avatar_url, u.created_at,
+			rt.total, rt.recent, s.total, s.recent, cv.total, cv.recent, p.total, p.recent
+		FROM users u,
+		LATERAL (
+			SELECT COUNT(*), COUNT(*) FILTER (WHERE c.created_at >= $3)
+			FROM ratings c JOIN restaurants r ON r.id = c.restaurant_id
+			WHERE c.user_id = u.id AND r.space_id = $2
+		) rt(total, recent),
+		LATERAL (
+			SELECT COUNT(*), COUNT(*) FILTER (WHERE c.created_at >= $3)
+			FROM restaurant_suggestions c
+			WHERE c.user_id = u.id AND c.space_id = $2
+		) s(total, recent),
+		LATERAL (
+			SELECT COUNT(*), COUNT(*) FILTER (WHERE c.created_at >= $3)
+			FROM restaurants c
+			WHERE c.suggested_by_user_id = u.id AND c.space_id = $2
+		) cv(total, recent),
+		LATERAL (
+			SELECT COUNT(*), COUNT(*) FILTER (WHERE c.created_at >= $3)
+			FROM menu_photos c JOIN restaurants r ON r.id = c.restaurant_id
+			WHERE c.uploaded_by_user_id = u.id AND r.space_id = $2
+				AND c.status = 'ready' AND c.moderation_status = 'approved'
+		) p(total, recent)
+		WHERE u.id = $1`, userID, spaceID, monthStart).Scan(
+		&stats.UserID, &stats.Username, &stats.AvatarURL, &stats.MemberSince,
+		&total.Ratings, &recent.Ratings, &total.Suggestions, &recent.Suggestions,
+		&total.ConvertedSuggestions, &recent.ConvertedSuggestions, &total.Photos, &recent.Photos)
+	if err != nil {
+		return nil, notFound(err)
+	}
+	// Converted suggestions are no longer in restaurant_suggestions but still count
+	total.Suggestions += total.ConvertedSuggestions
+	recent.Suggestions += recent.ConvertedSuggestions
+	return &stats, nil
+}
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v4"
+)
+
+var leaderboardColumns = []string{"id", "username", "avatar_url", "count"}
+
+func TestStatsLeaderboard(t *testing.T) {
+	mock, repos := newMock(t)
+	since := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery(`FROM ratings rt`).WithArgs(1, &since, 10).
+		WillReturnRows(pgxmock.NewRows(leaderboardColumns).AddRow(8, "anna", nil, 12).AddRow(9, "ben", nil, 4))
+	mock.ExpectQuery(`JOIN users u ON u.id = r.suggested_by_user_id`).WithArgs(1, &since, 10).
+		WillReturnRows(pgxmock.NewRows(leaderboardColumns).AddRow(9, "ben", nil, 2))
+	mock.ExpectQuery(`FROM menu_photos p`).WithArgs(1, &since, 10).
+		WillReturnRows(pgxmock.NewRows(leaderboardColumns))
+
+	board, err := repos.Stats.Leaderboard(context.Background(), 1, &since, 10)
+	if err != nil {
+		t.Fatalf("Leaderboard failed: %v", err)
+	}
+	if len(board.Ratings) != 2 || board.Ratings[1].Rank != 2 || board.Ratings[1].Username != "ben" {
+		t.Errorf("Unexpected ratings ranking %+v", board.Ratings)
+	}
+	if len(board.ConvertedSuggestions) != 1 || board.ConvertedSuggestions[0].Count != 2 {
+		t.Errorf("Unexpected converted suggestions ranking %+v", board.ConvertedSuggestions)
+	}
+	if board.Photos == nil || len(board.Photos) != 0 {
+		t.Errorf("Expected an empty photos ranking, got %v", board.Photos)
+	}
+}
+
+func TestStatsUserStats(t *testing.T) {
+	mock, repos := newMock(t)
+	monthStart := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	columns := []string{"id", "username", "avatar_url", "created_at", "rt.total", "rt.recent", "s.total", "s.recent",
+		"cv.total", "cv.recent", "p.total", "p.recent"}
+
+	mock.ExpectQuery(`FROM users u,\s+LATERAL`).WithArgs(8, 1, monthStart).
+		WillReturnRows(pgxmock.NewRows(columns).AddRow(8, "anna", nil, monthStart.AddDate(-1, 0, 0), 12, 3, 4, 1, 2, 1, 5, 0))
+	mock.ExpectQuery(`FROM users u,\s+LATERAL`).WithArgs(99, 1, monthStart).WillReturnError(pgx.ErrNoRows)
+
+	stats, err := repos.Stats.UserStats(context.Background(), 1, 8, monthStart)
+	if err != nil {
+		t.Fatalf("UserStats failed: %v", err)
+	}
+	// Converted suggestions are counted with the suggestions
+	if stats.Total.Ratings != 12 || stats.Total.Suggestions != 6 || stats.ThisMonth.Suggestions != 2 || stats.Total.Photos != 5 {
+		t.Errorf("Unexpected stats %+v", stats)
+	}
+	if _, err := repos.Stats.UserStats(context.Background(), 1, 99, monthStart); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
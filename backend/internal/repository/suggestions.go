@@ -0,0 +1,290 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/nomdb/backend/internal/models"
+)
+
+// ErrAlreadyApproved is returned when a user approves a suggestion a second time
+var ErrAlreadyApproved = errors.New("suggestion already approved by this user")
+
+// SuggestionRepository reads and writes restaurant suggestions and their food types
+type SuggestionRepository interface {
+	// List returns the suggestions of a space with their category and food types, newest
+	// first, optionally only those with status
+	List(ctx context.Context, spaceID int, status string) ([]models.RestaurantSuggestion, error)
+	// GetByID returns a suggestion with its category and food types
+	GetByID(ctx context.Context, id int) (*models.RestaurantSuggestion, error)
+	// Create stores a suggestion by userID (nil for anonymous suggestions)
+	Create(ctx context.Context, req models.CreateSuggestionRequest, userID *int) (*models.RestaurantSuggestion, error)
+	// UpdateStatus sets the status of a suggestion and returns it with its previous status
+	UpdateStatus(ctx context.Context, id int, status string) (*models.RestaurantSuggestion, string, error)
+	// Delete deletes a suggestion and returns its last status
+	Delete(ctx context.Context, id int) (string, error)
+	FoodTypes(ctx context.Context, id int) ([]models.FoodType, error)
+	// SetFoodTypes replaces the food types of a suggestion, only deleting and inserting
+	// the differences
+	SetFoodTypes(ctx context.Context, id int, foodTypeIDs []int) error
+	// Approvals returns the approvals of a suggestion by users who may still approve it:
+	// admins of the instance, and owners and admins of the suggestion's space
+	Approvals(ctx context.Context, id int) ([]models.SuggestionApproval, error)
+	// Approve records userID's approval of a suggestion; ErrNotFound if there is no such
+	// suggestion, ErrAlreadyApproved if the user approved it before
+	Approve(ctx context.Context, id, userID int, note *string) error
+	// RevokeApproval removes userID's approval of a suggestion; ErrNotFound if there was none
+	RevokeApproval(ctx context.Context, id, userID int) error
+	// MarkApproved moves a pending suggestion to approved, reporting whether it was pending
+	MarkApproved(ctx context.Context, id int) (bool, error)
+	// RecordEvent appends a status transition by userID (nil if anonymous) to the
+	// activity log of a suggestion of the space
+	RecordEvent(ctx context.Context, spaceID, id int, userID *int, fromStatus *string, toStatus string, note *string) error
+	// Events returns the activity log of a suggestion of the space, oldest first. The log
+	// outlives the suggestion; ErrNotFound if there is neither a log nor a suggestion.
+	Events(ctx context.Context, spaceID, id int) ([]models.SuggestionEvent, error)
+}
+
+type suggestionRepo struct {
+	db DB
+}
+
+const suggestionSelect = `
+	SELECT
+		s.id, s.name, s.address, s.phone, s.website, s.latitude, s.longitude,
+		s.google_place_id, s.suggested_category_id, s.notes, s.status,
+		s.created_at, s.updated_at, s.user_id,
+		c.id, c.name
+	FROM restaurant_suggestions s
+	LEFT JOIN categories c ON s.suggested_category_id = c.id`
+
+func scanSuggestion(row interface{ Scan(...any) error }, sug *models.RestaurantSuggestion, extra ...any) error {
+	return row.Scan(append([]any{
+		&sug.ID, &sug.Name, &sug.Address, &sug.Phone, &sug.Website, &sug.Latitude, &sug.Longitude,
+		&sug.GooglePlaceID, &sug.SuggestedCategoryID, &sug.Notes, &sug.Status, &sug.CreatedAt, &sug.UpdatedAt, &sug.UserID,
+	}, extra...)...)
+}
+
+// scanSuggestionWithCategory scans a row of suggestionSelect
+func scanSuggestionWithCategory(row interface{ Scan(...any) error }, sug *models.RestaurantSuggestion) error {
+	var catID *int
+	var catName *string
+	if err := scanSuggestion(row, sug, &catID, &catName); err != nil {
+		return err
+	}
+	if catID != nil && catName != nil {
+		sug.Category = &models.Category{ID: *catID, Name: *catName}
+	}
+	return nil
+}
+
+func (r *suggestionRepo) List(ctx context.Context, spaceID int, status string) ([]models.RestaurantSuggestion, error) {
+	query := suggestionSelect + ` WHERE s.space_id = $1 ORDER BY s.created_at DESC`
+	args := []any{spaceID}
+	if status != "" {
+		query = suggestionSelect + ` WHERE s.space_id = $1 AND s.status = $2 ORDER BY s.created_at DESC`
+		args = append(args, status)
+	}
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	suggestions := []models.RestaurantSuggestion{}
+	for rows.Next() {
+		var sug models.RestaurantSuggestion
+		if err := scanSuggestionWithCategory(rows, &sug); err != nil {
+			return nil, err
+		}
+		suggestions = append(suggestions, sug)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Food types are fetched once all rows are read, as the connection is busy until then
+	for i := range suggestions {
+		if suggestions[i].FoodTypes, err = r.FoodTypes(ctx, suggestions[i].ID); err != nil {
+			return nil, err
+		}
+	}
+	return suggestions, nil
+}
+
+func (r *suggestionRepo) GetByID(ctx context.Context, id int) (*models.RestaurantSuggestion, error) {
+	var sug models.RestaurantSuggestion
+	if err := scanSuggestionWithCategory(r.db.QueryRow(ctx, suggestionSelect+` WHERE s.id = $1`, id), &sug); err != nil {
+		return nil, notFound(err)
+	}
+
+	var err error
+	if sug.FoodTypes, err = r.FoodTypes(ctx, sug.ID); err != nil {
+		return nil, err
+	}
+	return &sug, nil
+}
+
+func (r *suggestionRepo) Create(ctx context.Context, req models.CreateSuggestionRequest, userID *int) (*models.RestaurantSuggestion, error) {
+	var sug models.RestaurantSuggestion
+	err := scanSuggestion(r.db.QueryRow(ctx,
+		`INSERT INTO restaurant_suggestions (name, address, phone, website, latitude, longitude, google_place_id, suggested_category_id, notes, user_id, space_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING id, name, address, phone, website, latitude, longitude, google_place_id, suggested_category_id, notes, status, created_at, updated_at, user_id`,
+		req.Name, req.Address, req.Phone, req.Website, req.Latitude, req.Longitude, req.GooglePlaceID, req.SuggestedCategoryID, req.Notes, userID, spaceOrDefault(req.SpaceID),
+	), &sug)
+	if err != nil {
+		return nil, err
+	}
+	return &sug, nil
+}
+
+func (r *suggestionRepo) UpdateStatus(ctx context.Context, id int, status string) (*models.RestaurantSuggestion, string, error) {
+	// Capture the previous status in the same statement for the activity log
+	var sug models.RestaurantSuggestion
+	var previousStatus string
+	err := scanSuggestion(r.db.QueryRow(ctx,
+		`UPDATE restaurant_suggestions s SET status = $1, updated_at = NOW()
+		FROM (SELECT id, status FROM restaurant_suggestions WHERE id = $2 FOR UPDATE) prev
+		WHERE s.id = prev.id
+		RETURNING s.id, s.name, s.address, s.phone, s.website, s.latitude, s.longitude, s.google_place_id,
+			s.suggested_category_id, s.notes, s.status, s.created_at, s.updated_at, s.user_id, prev.status`,
+		status, id,
+	), &sug, &previousStatus)
+	if err != nil {
+		return nil, "", notFound(err)
+	}
+	return &sug, previousStatus, nil
+}
+
+func (r *suggestionRepo) Delete(ctx context.Context, id int) (string, error) {
+	var previousStatus string
+	err := r.db.QueryRow(ctx, "DELETE FROM restaurant_suggestions WHERE id = $1 RETURNING status", id).Scan(&previousStatus)
+	return previousStatus, notFound(err)
+}
+
+func (r *suggestionRepo) FoodTypes(ctx context.Context, id int) ([]models.FoodType, error) {
+	return queryFoodTypes(ctx, r.db,
+		`SELECT ft.id, ft.name, ft.created_at, ft.updated_at
+		FROM food_types ft
+		JOIN suggestion_food_types sft ON ft.id = sft.food_type_id
+		WHERE sft.suggestion_id = $1
+		ORDER BY ft.name`, id)
+}
+
+func (r *suggestionRepo) SetFoodTypes(ctx context.Context, id int, foodTypeIDs []int) error {
+	return setFoodTypes(ctx, r.db, "suggestion_food_types", "suggestion_id", id, foodTypeIDs)
+}
+
+func (r *suggestionRepo) Approvals(ctx context.Context, id int) ([]models.SuggestionApproval, error) {
+	rows, err := r.db.Query(ctx,
+		`SELECT sa.id, sa.suggestion_id, sa.user_id, u.username, sa.note, sa.created_at
+		FROM suggestion_approvals sa
+		JOIN restaurant_suggestions s ON s.id = sa.suggestion_id
+		JOIN users u ON sa.user_id = u.id
+		WHERE sa.suggestion_id = $1 AND (u.is_admin OR EXISTS (
+			SELECT 1 FROM space_members m
+			WHERE m.space_id = s.space_id AND m.user_id = u.id AND m.role IN ('owner', 'admin')))
+		ORDER BY sa.created_at`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	approvals := []models.SuggestionApproval{}
+	for rows.Next() {
+		var a models.SuggestionApproval
+		if err := rows.Scan(&a.ID, &a.SuggestionID, &a.UserID, &a.Username, &a.Note, &a.CreatedAt); err != nil {
+			return nil, err
+		}
+		approvals = append(approvals, a)
+	}
+	return approvals, rows.Err()
+}
+
+func (r *suggestionRepo) Approve(ctx context.Context, id, userID int, note *string) error {
+	_, err := r.db.Exec(ctx,
+		"INSERT INTO suggestion_approvals (suggestion_id, user_id, note) VALUES ($1, $2, $3)", id, userID, note)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "23505": // unique_violation
+			return ErrAlreadyApproved
+		case "23503": // foreign_key_violation
+			return ErrNotFound
+		}
+	}
+	return err
+}
+
+func (r *suggestionRepo) RevokeApproval(ctx context.Context, id, userID int) error {
+	result, err := r.db.Exec(ctx,
+		"DELETE FROM suggestion_approvals WHERE suggestion_id = $1 AND user_id = $2", id, userID)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *suggestionRepo) MarkApproved(ctx context.Context, id int) (bool, error) {
+	result, err := r.db.Exec(ctx,
+		`UPDATE restaurant_suggestions SET status = 'approved', updated_at = NOW()
+		WHERE id = $1 AND status = 'pending'`, id)
+	if err != nil {
+		return false, err
+	}
+	return result.RowsAffected() > 0, nil
+}
+
+func (r *suggestionRepo) RecordEvent(ctx context.Context, spaceID, id int, userID *int, fromStatus *string, toStatus string, note *string) error {
+	_, err := r.db.Exec(ctx,
+		`INSERT INTO suggestion_events (suggestion_id, space_id, user_id, from_status, to_status, note)
+		VALUES ($1, $2, $3, $4, $5, $6)`,
+		id, spaceOrDefault(spaceID), userID, fromStatus, toStatus, note)
+	return err
+}
+
+func (r *suggestionRepo) Events(ctx context.Context, spaceID, id int) ([]models.SuggestionEvent, error) {
+	rows, err := r.db.Query(ctx,
+		`SELECT e.id, e.suggestion_id, e.user_id, u.username, e.from_status, e.to_status, e.note, e.created_at
+		FROM suggestion_events e
+		LEFT JOIN users u ON e.user_id = u.id
+		WHERE e.suggestion_id = $1 AND e.space_id = $2
+		ORDER BY e.created_at, e.id`, id, spaceOrDefault(spaceID))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []models.SuggestionEvent{}
+	for rows.Next() {
+		var e models.SuggestionEvent
+		if err := rows.Scan(&e.ID, &e.SuggestionID, &e.UserID, &e.Username, &e.FromStatus, &e.ToStatus, &e.Note, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(events) > 0 {
+		return events, nil
+	}
+
+	// No history at all: distinguish "never existed" from "no transitions yet"
+	var exists bool
+	err = r.db.QueryRow(ctx,
+		"SELECT EXISTS(SELECT 1 FROM restaurant_suggestions WHERE id = $1 AND space_id = $2)", id, spaceOrDefault(spaceID)).Scan(&exists)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrNotFound
+	}
+	return events, nil
+}
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nomdb/backend/internal/models"
+)
+
+// Kinds of translated names
+const (
+	TranslationCategory = "category"
+	TranslationFoodType = "food_type"
+)
+
+// translationTables names the translation table, its foreign key and the translated
+// table of each kind
+var translationTables = map[string]struct{ table, column, parent string }{
+	TranslationCategory: {"category_translations", "category_id", "categories"},
+	TranslationFoodType: {"food_type_translations", "food_type_id", "food_types"},
+}
+
+// TranslatedNames are the names of categories and food types in one locale by ID
+type TranslatedNames struct {
+	Categories map[int]string `json:"categories"`
+	FoodTypes  map[int]string `json:"food_types"`
+}
+
+// TranslationRepository stores translated category and food type names. Kinds are the
+// Translation constants.
+type TranslationRepository interface {
+	// All returns every translation by locale
+	All(ctx context.Context) (map[string]TranslatedNames, error)
+	// List returns the translations of a category or food type by locale
+	List(ctx context.Context, kind string, id int) ([]models.Translation, error)
+	// Set stores the name of a category or food type in locale, replacing any previous
+	// one; ErrNotFound if the category or food type does not exist
+	Set(ctx context.Context, kind string, id int, locale, name string) (models.Translation, error)
+	// Delete removes a translation, or returns ErrNotFound
+	Delete(ctx context.Context, kind string, id int, locale string) error
+}
+
+type translationRepo struct {
+	db DB
+}
+
+func (r *translationRepo) All(ctx context.Context) (map[string]TranslatedNames, error) {
+	rows, err := r.db.Query(ctx,
+		`SELECT 'category', category_id, locale, name FROM category_translations
+		UNION ALL
+		SELECT 'food_type', food_type_id, locale, name FROM food_type_translations`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	all := map[string]TranslatedNames{}
+	for rows.Next() {
+		var kind, locale, name string
+		var id int
+		if err := rows.Scan(&kind, &id, &locale, &name); err != nil {
+			return nil, err
+		}
+		names, ok := all[locale]
+		if !ok {
+			names = TranslatedNames{Categories: map[int]string{}, FoodTypes: map[int]string{}}
+			all[locale] = names
+		}
+		if kind == TranslationCategory {
+			names.Categories[id] = name
+		} else {
+			names.FoodTypes[id] = name
+		}
+	}
+	return all, rows.Err()
+}
+
+func (r *translationRepo) List(ctx context.Context, kind string, id int) ([]models.Translation, error) {
+	t := translationTables[kind]
+	rows, err := r.db.Query(ctx,
+		fmt.Sprintf(`SELECT locale, name, updated_at FROM %s WHERE %s = $1 ORDER BY locale`, t.table, t.column), id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	translations := []models.Translation{}
+	for rows.Next() {
+		var translation models.Translation
+		if err := rows.Scan(&translation.Locale, &translation.Name, &translation.UpdatedAt); err != nil {
+			return nil, err
+		}
+		translations = append(translations, translation)
+	}
+	return translations, rows.Err()
+}
+
+func (r *translationRepo) Set(ctx context.Context, kind string, id int, locale, name string) (models.Translation, error) {
+	t := translationTables[kind]
+	translation := models.Translation{Locale: locale, Name: name}
+	err := r.db.QueryRow(ctx, fmt.Sprintf(
+		`INSERT INTO %[1]s (%[2]s, locale, name)
+		SELECT id, $2, $3 FROM %[3]s WHERE id = $1
+		ON CONFLICT (%[2]s, locale) DO UPDATE SET name = EXCLUDED.name, updated_at = NOW()
+		RETURNING updated_at`, t.table, t.column, t.parent), id, locale, name).Scan(&translation.UpdatedAt)
+	return translation, notFound(err)
+}
+
+func (r *translationRepo) Delete(ctx context.Context, kind string, id int, locale string) error {
+	t := translationTables[kind]
+	tag, err := r.db.Exec(ctx,
+		fmt.Sprintf(`DELETE FROM %s WHERE %s = $1 AND locale = $2`, t.table, t.column), id, locale)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
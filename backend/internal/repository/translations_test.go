@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/pashagolub/pgxmock/v4"
+)
+
+func TestTranslationsAll(t *testing.T) {
+	mock, repos := newMock(t)
+	mock.ExpectQuery(`FROM category_translations\s+UNION ALL\s+SELECT 'food_type', food_type_id, locale, name FROM food_type_translations`).
+		WillReturnRows(pgxmock.NewRows([]string{"kind", "id", "locale", "name"}).
+			AddRow(TranslationCategory, 1, "de", "Italienisch").
+			AddRow(TranslationFoodType, 8, "de", "Meeresfrüchte").
+			AddRow(TranslationFoodType, 8, "fr", "Fruits de mer"))
+
+	all, err := repos.Translations.All(context.Background())
+	if err != nil {
+		t.Fatalf("All failed: %v", err)
+	}
+	if all["de"].Categories[1] != "Italienisch" || all["de"].FoodTypes[8] != "Meeresfrüchte" {
+		t.Errorf("Unexpected German names %+v", all["de"])
+	}
+	if len(all["fr"].Categories) != 0 || all["fr"].FoodTypes[8] != "Fruits de mer" {
+		t.Errorf("Unexpected French names %+v", all["fr"])
+	}
+}
+
+func TestTranslationsSet(t *testing.T) {
+	mock, repos := newMock(t)
+	mock.ExpectQuery(`INSERT INTO food_type_translations \(food_type_id, locale, name\)\s+SELECT id, \$2, \$3 FROM food_types WHERE id = \$1\s+ON CONFLICT \(food_type_id, locale\) DO UPDATE`).
+		WithArgs(99, "de", "Salate").
+		WillReturnRows(pgxmock.NewRows([]string{"updated_at"}))
+
+	if _, err := repos.Translations.Set(context.Background(), TranslationFoodType, 99, "de", "Salate"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for an unknown food type, got %v", err)
+	}
+}
+
+func TestTranslationsDelete(t *testing.T) {
+	mock, repos := newMock(t)
+	mock.ExpectExec(`DELETE FROM category_translations WHERE category_id = \$1 AND locale = \$2`).WithArgs(4, "de").
+		WillReturnResult(pgxmock.NewResult("DELETE", 0))
+
+	if err := repos.Translations.Delete(context.Background(), TranslationCategory, 4, "de"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,221 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/nomdb/backend/internal/models"
+)
+
+// Webhook delivery states, mirroring the CHECK constraint on webhook_deliveries.status
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliveryDelivered = "delivered"
+	WebhookDeliveryFailed    = "failed"
+)
+
+// DueWebhookDelivery is a claimed delivery with the endpoint and secret of its webhook
+type DueWebhookDelivery struct {
+	models.WebhookDelivery
+	URL    string
+	Secret string
+}
+
+// WebhookRepository stores webhooks and queues their deliveries
+type WebhookRepository interface {
+	// List returns all webhooks, without their secrets
+	List(ctx context.Context) ([]models.Webhook, error)
+	// Create stores w, setting its ID and timestamps
+	Create(ctx context.Context, w *models.Webhook) error
+	// Update changes the URL, events, description and active flag of w, and its secret
+	// unless empty, and reloads it. ErrNotFound is returned if it does not exist.
+	Update(ctx context.Context, w *models.Webhook) error
+	// Delete removes a webhook and its deliveries, or returns ErrNotFound
+	Delete(ctx context.Context, id int) error
+	// Enqueue queues a delivery of payload to every active webhook subscribed to event
+	// and returns how many were queued
+	Enqueue(ctx context.Context, event string, payload []byte) (int, error)
+	// ClaimDue returns up to limit pending deliveries of active webhooks that are due,
+	// oldest first, and postpones them by lease so no other instance claims them meanwhile
+	ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]DueWebhookDelivery, error)
+	// MarkDelivered records a successful attempt
+	MarkDelivered(ctx context.Context, id int64, statusCode int) error
+	// MarkFailed records a failed attempt; the delivery is retried at retryAt, or fails
+	// for good when retryAt is nil. statusCode is nil when no response arrived.
+	MarkFailed(ctx context.Context, id int64, statusCode *int, message string, retryAt *time.Time) error
+	// Deliveries returns up to limit deliveries of a webhook, newest first, optionally
+	// only those with status
+	Deliveries(ctx context.Context, webhookID int, status string, limit int) ([]models.WebhookDelivery, error)
+	// PruneDeliveries deletes finished deliveries created before before
+	PruneDeliveries(ctx context.Context, before time.Time) (int64, error)
+}
+
+type webhookRepo struct {
+	db DB
+}
+
+const webhookColumns = `id, url, events, description, active, created_by_user_id, created_at, updated_at`
+
+func scanWebhook(row interface{ Scan(...any) error }, w *models.Webhook) error {
+	return row.Scan(&w.ID, &w.URL, &w.Events, &w.Description, &w.Active, &w.CreatedByUserID, &w.CreatedAt, &w.UpdatedAt)
+}
+
+const webhookDeliveryColumns = `d.id, d.webhook_id, d.event, d.payload, d.status, d.attempts, d.next_attempt_at,
+	d.last_status_code, d.last_error, d.delivered_at, d.created_at`
+
+func scanWebhookDelivery(row interface{ Scan(...any) error }, d *models.WebhookDelivery) error {
+	var nextAttemptAt time.Time
+	if err := row.Scan(&d.ID, &d.WebhookID, &d.Event, &d.Payload, &d.Status, &d.Attempts, &nextAttemptAt,
+		&d.LastStatusCode, &d.LastError, &d.DeliveredAt, &d.CreatedAt); err != nil {
+		return err
+	}
+	if d.Status == WebhookDeliveryPending {
+		d.NextAttemptAt = &nextAttemptAt
+	}
+	return nil
+}
+
+func (r *webhookRepo) List(ctx context.Context) ([]models.Webhook, error) {
+	rows, err := r.db.Query(ctx, `SELECT `+webhookColumns+` FROM webhooks ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	webhooks := []models.Webhook{}
+	for rows.Next() {
+		var w models.Webhook
+		if err := scanWebhook(rows, &w); err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, w)
+	}
+	return webhooks, rows.Err()
+}
+
+func (r *webhookRepo) Create(ctx context.Context, w *models.Webhook) error {
+	return r.db.QueryRow(ctx,
+		`INSERT INTO webhooks (url, secret, events, description, active, created_by_user_id)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at, updated_at`,
+		w.URL, w.Secret, w.Events, w.Description, w.Active, w.CreatedByUserID,
+	).Scan(&w.ID, &w.CreatedAt, &w.UpdatedAt)
+}
+
+func (r *webhookRepo) Update(ctx context.Context, w *models.Webhook) error {
+	err := scanWebhook(r.db.QueryRow(ctx,
+		`UPDATE webhooks SET url = $2, secret = COALESCE(NULLIF($3, ''), secret), events = $4,
+			description = $5, active = $6, updated_at = NOW()
+		WHERE id = $1
+		RETURNING `+webhookColumns,
+		w.ID, w.URL, w.Secret, w.Events, w.Description, w.Active), w)
+	return notFound(err)
+}
+
+func (r *webhookRepo) Delete(ctx context.Context, id int) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM webhooks WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *webhookRepo) Enqueue(ctx context.Context, event string, payload []byte) (int, error) {
+	tag, err := r.db.Exec(ctx,
+		`INSERT INTO webhook_deliveries (webhook_id, event, payload)
+		SELECT id, $1, $2 FROM webhooks WHERE active AND $1 = ANY(events)`,
+		event, payload)
+	if err != nil {
+		return 0, err
+	}
+	return int(tag.RowsAffected()), nil
+}
+
+func (r *webhookRepo) ClaimDue(ctx context.Context, limit int, lease time.Duration) ([]DueWebhookDelivery, error) {
+	rows, err := r.db.Query(ctx,
+		`WITH due AS (
+			SELECT d.id FROM webhook_deliveries d
+			JOIN webhooks w ON w.id = d.webhook_id
+			WHERE d.status = 'pending' AND d.next_attempt_at <= NOW() AND w.active
+			ORDER BY d.next_attempt_at, d.id
+			LIMIT $1
+			FOR UPDATE OF d SKIP LOCKED
+		)
+		UPDATE webhook_deliveries d SET next_attempt_at = NOW() + make_interval(secs => $2)
+		FROM due, webhooks w
+		WHERE d.id = due.id AND w.id = d.webhook_id
+		RETURNING `+webhookDeliveryColumns+`, w.url, w.secret`,
+		limit, lease.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []DueWebhookDelivery
+	for rows.Next() {
+		var d DueWebhookDelivery
+		var nextAttemptAt time.Time
+		if err := rows.Scan(&d.ID, &d.WebhookID, &d.Event, &d.Payload, &d.Status, &d.Attempts, &nextAttemptAt,
+			&d.LastStatusCode, &d.LastError, &d.DeliveredAt, &d.CreatedAt, &d.URL, &d.Secret); err != nil {
+			return nil, err
+		}
+		d.NextAttemptAt = &nextAttemptAt
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
+
+func (r *webhookRepo) MarkDelivered(ctx context.Context, id int64, statusCode int) error {
+	_, err := r.db.Exec(ctx,
+		`UPDATE webhook_deliveries SET status = 'delivered', attempts = attempts + 1, last_status_code = $2,
+			last_error = NULL, delivered_at = NOW()
+		WHERE id = $1`, id, statusCode)
+	return err
+}
+
+func (r *webhookRepo) MarkFailed(ctx context.Context, id int64, statusCode *int, message string, retryAt *time.Time) error {
+	status := WebhookDeliveryFailed
+	if retryAt != nil {
+		status = WebhookDeliveryPending
+	}
+	_, err := r.db.Exec(ctx,
+		`UPDATE webhook_deliveries SET status = $2, attempts = attempts + 1, last_status_code = $3,
+			last_error = $4, next_attempt_at = COALESCE($5, next_attempt_at)
+		WHERE id = $1`, id, status, statusCode, message, retryAt)
+	return err
+}
+
+func (r *webhookRepo) Deliveries(ctx context.Context, webhookID int, status string, limit int) ([]models.WebhookDelivery, error) {
+	rows, err := r.db.Query(ctx,
+		`SELECT `+webhookDeliveryColumns+`
+		FROM webhook_deliveries d
+		WHERE d.webhook_id = $1 AND ($2 = '' OR d.status = $2)
+		ORDER BY d.id DESC
+		LIMIT $3`, webhookID, status, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := []models.WebhookDelivery{}
+	for rows.Next() {
+		var d models.WebhookDelivery
+		if err := scanWebhookDelivery(rows, &d); err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
+
+func (r *webhookRepo) PruneDeliveries(ctx context.Context, before time.Time) (int64, error) {
+	tag, err := r.db.Exec(ctx,
+		`DELETE FROM webhook_deliveries WHERE status <> 'pending' AND created_at < $1`, before)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
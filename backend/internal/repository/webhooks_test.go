@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pashagolub/pgxmock/v4"
+)
+
+var webhookDeliveryRowColumns = []string{"id", "webhook_id", "event", "payload", "status", "attempts", "next_attempt_at",
+	"last_status_code", "last_error", "delivered_at", "created_at"}
+
+func TestWebhookEnqueue(t *testing.T) {
+	mock, repos := newMock(t)
+	payload := []byte(`{"event":"rating.created"}`)
+
+	mock.ExpectExec(`INSERT INTO webhook_deliveries .* WHERE active AND \$1 = ANY\(events\)`).
+		WithArgs("rating.created", payload).
+		WillReturnResult(pgxmock.NewResult("INSERT", 2))
+
+	queued, err := repos.Webhooks.Enqueue(context.Background(), "rating.created", payload)
+	if err != nil || queued != 2 {
+		t.Errorf("Enqueue = %d, %v; want 2 deliveries", queued, err)
+	}
+}
+
+func TestWebhookClaimDue(t *testing.T) {
+	mock, repos := newMock(t)
+	now := time.Now()
+
+	mock.ExpectQuery(`FOR UPDATE OF d SKIP LOCKED`).WithArgs(10, 40.0).
+		WillReturnRows(pgxmock.NewRows(append(webhookDeliveryRowColumns, "url", "secret")).
+			AddRow(int64(7), 2, "restaurant.created", []byte(`{}`), "pending", 1, now.Add(40*time.Second),
+				nil, nil, nil, now, "https://example.com/hook", "s3cret"))
+
+	deliveries, err := repos.Webhooks.ClaimDue(context.Background(), 10, 40*time.Second)
+	if err != nil {
+		t.Fatalf("ClaimDue failed: %v", err)
+	}
+	if len(deliveries) != 1 || deliveries[0].ID != 7 || deliveries[0].URL != "https://example.com/hook" ||
+		deliveries[0].Secret != "s3cret" || deliveries[0].Attempts != 1 {
+		t.Errorf("Unexpected deliveries %+v", deliveries)
+	}
+}
+
+func TestWebhookMarkFailed(t *testing.T) {
+	retryAt := time.Now().Add(time.Minute)
+	code := 500
+	tests := []struct {
+		name    string
+		retryAt *time.Time
+		status  string
+	}{
+		{"retried", &retryAt, WebhookDeliveryPending},
+		{"given up", nil, WebhookDeliveryFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock, repos := newMock(t)
+			mock.ExpectExec(`UPDATE webhook_deliveries SET status = \$2`).
+				WithArgs(int64(7), tt.status, &code, "HTTP 500", tt.retryAt).
+				WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+			if err := repos.Webhooks.MarkFailed(context.Background(), 7, &code, "HTTP 500", tt.retryAt); err != nil {
+				t.Errorf("MarkFailed failed: %v", err)
+			}
+		})
+	}
+}
+
+func TestWebhookDeliveries(t *testing.T) {
+	mock, repos := newMock(t)
+	now := time.Now()
+	code := 200
+
+	mock.ExpectQuery(`FROM webhook_deliveries d`).WithArgs(2, "", 50).
+		WillReturnRows(pgxmock.NewRows(webhookDeliveryRowColumns).
+			AddRow(int64(8), 2, "rating.created", []byte(`{}`), "pending", 0, now, nil, nil, nil, now).
+			AddRow(int64(7), 2, "restaurant.created", []byte(`{}`), "delivered", 1, now, &code, nil, &now, now))
+
+	deliveries, err := repos.Webhooks.Deliveries(context.Background(), 2, "", 50)
+	if err != nil {
+		t.Fatalf("Deliveries failed: %v", err)
+	}
+	// Only pending deliveries have a next attempt
+	if len(deliveries) != 2 || deliveries[0].NextAttemptAt == nil || deliveries[1].NextAttemptAt != nil {
+		t.Errorf("Unexpected deliveries %+v", deliveries)
+	}
+}
@@ -0,0 +1,274 @@
+// Package routes registers the API routes on a router. The server and the OpenAPI
+// generator (cmd/openapi) share it, so the generated spec lists the routes actually served.
+package routes
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	apperrors "github.com/nomdb/backend/internal/errors"
+	"github.com/nomdb/backend/internal/handlers"
+	"github.com/nomdb/backend/internal/logger"
+	"github.com/nomdb/backend/internal/middleware"
+)
+
+// Options selects optional routes
+type Options struct {
+	// DebugEndpoints serves pprof and expvar under /api/debug/ to admins
+	DebugEndpoints bool
+}
+
+// Register adds the API routes, and the JSON responses to unknown routes and methods, to r.
+// Metrics and API docs are added by the server, since they may be served by the internal
+// listener instead.
+func Register(r *mux.Router, opts Options) {
+	// Unknown routes and methods get the same JSON errors as the handlers
+	r.NotFoundHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		apperrors.Error(w, "Not found", http.StatusNotFound)
+	})
+	r.MethodNotAllowedHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		apperrors.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	})
+
+	// Serve files from local storage
+	r.PathPrefix("/api/uploads/").HandlerFunc(handlers.ServeLocalUpload).Methods("GET", "HEAD")
+
+	// API routes
+	api := r.PathPrefix("/api").Subrouter()
+	api.Use(middleware.CSRFMiddleware(handlers.RefreshTokenCookie))
+	api.Use(middleware.APIKeyAuthMiddleware)
+
+	// Public auth routes (no authentication required)
+	api.HandleFunc("/auth/register", handlers.Register).Methods("POST")
+	api.HandleFunc("/auth/login", handlers.Login).Methods("POST")
+	api.HandleFunc("/auth/refresh", handlers.RefreshToken).Methods("POST")
+	api.HandleFunc("/auth/logout", handlers.Logout).Methods("POST")
+	api.HandleFunc("/auth/forgot-password", handlers.ForgotPassword).Methods("POST")
+	api.HandleFunc("/auth/reset-password", handlers.ResetPassword).Methods("POST")
+	api.HandleFunc("/auth/providers", handlers.GetAuthProviders).Methods("GET")
+	api.HandleFunc("/auth/csrf", handlers.GetCSRFToken).Methods("GET")
+	api.HandleFunc("/auth/oidc/login", handlers.OIDCLogin).Methods("GET")
+	api.HandleFunc("/auth/oidc/callback", handlers.OIDCCallback).Methods("GET")
+	api.HandleFunc("/auth/oidc/{provider}/login", handlers.OIDCLogin).Methods("GET")
+	api.HandleFunc("/auth/oidc/{provider}/callback", handlers.OIDCCallback).Methods("GET")
+
+	// Protected auth routes (authentication required)
+	authRoutes := api.PathPrefix("/auth").Subrouter()
+	authRoutes.Use(middleware.AuthMiddleware)
+	authRoutes.HandleFunc("/me", handlers.GetMe).Methods("GET")
+	authRoutes.HandleFunc("/me/export", handlers.ExportMyAccount).Methods("GET")
+	authRoutes.HandleFunc("/me/delete", handlers.DeleteMyAccount).Methods("POST")
+	authRoutes.HandleFunc("/me/restaurant-claims", handlers.GetMyRestaurantClaims).Methods("GET")
+	authRoutes.HandleFunc("/me/following", handlers.GetFollowing).Methods("GET")
+
+	// Following other users and the activity feed of the space (authentication required)
+	usersProtected := api.PathPrefix("/users").Subrouter()
+	usersProtected.Use(middleware.AuthMiddleware)
+	usersProtected.HandleFunc("/{id}/follow", handlers.FollowUser).Methods("POST")
+	usersProtected.HandleFunc("/{id}/follow", handlers.UnfollowUser).Methods("DELETE")
+
+	feedRoutes := api.PathPrefix("/feed").Subrouter()
+	feedRoutes.Use(middleware.AuthMiddleware)
+	feedRoutes.Use(handlers.SpaceMiddleware)
+	feedRoutes.HandleFunc("", handlers.GetFeed).Methods("GET")
+
+	// API keys for programmatic access (sent as X-API-Key)
+	keyRoutes := api.PathPrefix("/keys").Subrouter()
+	keyRoutes.Use(middleware.AuthMiddleware)
+	keyRoutes.HandleFunc("", handlers.ListAPIKeys).Methods("GET")
+	keyRoutes.HandleFunc("", handlers.CreateAPIKey).Methods("POST")
+	keyRoutes.HandleFunc("/{id}", handlers.DeleteAPIKey).Methods("DELETE")
+
+	// Spaces: separate restaurant lists with their own members, chosen with X-Space
+	spacesPublic := api.PathPrefix("/spaces").Subrouter()
+	spacesPublic.Use(middleware.OptionalAuthMiddleware)
+	spacesPublic.HandleFunc("", handlers.ListSpaces).Methods("GET")
+	spacesPublic.HandleFunc("/{slug}", handlers.GetSpace).Methods("GET")
+
+	spacesProtected := api.PathPrefix("/spaces").Subrouter()
+	spacesProtected.Use(middleware.AuthMiddleware)
+	spacesProtected.HandleFunc("", handlers.CreateSpace).Methods("POST")
+	spacesProtected.HandleFunc("/join", handlers.JoinSpace).Methods("POST")
+	spacesProtected.HandleFunc("/{slug}", handlers.UpdateSpace).Methods("PUT")
+	spacesProtected.HandleFunc("/{slug}", handlers.DeleteSpace).Methods("DELETE")
+	spacesProtected.HandleFunc("/{slug}/members", handlers.ListSpaceMembers).Methods("GET")
+	spacesProtected.HandleFunc("/{slug}/members/{userId}", handlers.SetSpaceMember).Methods("PUT")
+	spacesProtected.HandleFunc("/{slug}/members/{userId}", handlers.RemoveSpaceMember).Methods("DELETE")
+	spacesProtected.HandleFunc("/{slug}/invites", handlers.CreateSpaceInvite).Methods("POST")
+	spacesProtected.HandleFunc("/{slug}/invites", handlers.ListSpaceInvites).Methods("GET")
+	spacesProtected.HandleFunc("/{slug}/invites/{id}", handlers.RevokeSpaceInvite).Methods("DELETE")
+
+	// Public read routes (no auth required for browsing), in the space of the request
+	publicRoutes := api.PathPrefix("").Subrouter()
+	publicRoutes.Use(middleware.OptionalAuthMiddleware)
+	publicRoutes.Use(handlers.SpaceMiddleware)
+
+	// Feature flags admins can toggle at runtime
+	publicRoutes.HandleFunc("/features", handlers.GetFeatures).Methods("GET")
+
+	// Reference data of the categories, food types and ratings in one response
+	publicRoutes.HandleFunc("/meta", handlers.GetMeta).Methods("GET")
+
+	// Contribution leaderboard and stats of the users of the space
+	publicRoutes.HandleFunc("/leaderboard", handlers.GetLeaderboard).Methods("GET")
+	publicRoutes.HandleFunc("/users/{id}/stats", handlers.GetUserStats).Methods("GET")
+
+	// Categories and their translated names (read-only public, write requires auth, delete requires admin)
+	publicRoutes.HandleFunc("/categories", handlers.GetCategories).Methods("GET")
+	publicRoutes.HandleFunc("/categories/tree", handlers.GetCategoryTree).Methods("GET")
+	publicRoutes.HandleFunc("/categories/{id}", handlers.GetCategory).Methods("GET")
+	publicRoutes.HandleFunc("/categories/{id}/translations", handlers.GetCategoryTranslations).Methods("GET")
+
+	categoriesProtected := api.PathPrefix("/categories").Subrouter()
+	categoriesProtected.Use(middleware.AuthMiddleware)
+	categoriesProtected.HandleFunc("", handlers.CreateCategory).Methods("POST")
+	categoriesProtected.HandleFunc("/{id}", handlers.UpdateCategory).Methods("PUT")
+	categoriesProtected.Handle("/{id}", middleware.AdminOnlyMiddleware(http.HandlerFunc(handlers.DeleteCategory))).Methods("DELETE")
+	categoriesProtected.HandleFunc("/{id}/translations/{locale}", handlers.SetCategoryTranslation).Methods("PUT")
+	categoriesProtected.Handle("/{id}/translations/{locale}", middleware.AdminOnlyMiddleware(http.HandlerFunc(handlers.DeleteCategoryTranslation))).Methods("DELETE")
+
+	// Food Types and their translated names (read-only public, write requires auth, delete and merge require admin)
+	publicRoutes.HandleFunc("/food-types", handlers.GetFoodTypes).Methods("GET")
+	publicRoutes.HandleFunc("/food-types/{id}", handlers.GetFoodType).Methods("GET")
+	publicRoutes.HandleFunc("/food-types/{id}/translations", handlers.GetFoodTypeTranslations).Methods("GET")
+
+	foodTypesProtected := api.PathPrefix("/food-types").Subrouter()
+	foodTypesProtected.Use(middleware.AuthMiddleware)
+	foodTypesProtected.HandleFunc("", handlers.CreateFoodType).Methods("POST")
+	foodTypesProtected.HandleFunc("/{id}", handlers.UpdateFoodType).Methods("PUT")
+	foodTypesProtected.Handle("/{id}", middleware.AdminOnlyMiddleware(http.HandlerFunc(handlers.DeleteFoodType))).Methods("DELETE")
+	foodTypesProtected.Handle("/{id}/merge", middleware.AdminOnlyMiddleware(http.HandlerFunc(handlers.MergeFoodTypes))).Methods("POST")
+	foodTypesProtected.HandleFunc("/{id}/translations/{locale}", handlers.SetFoodTypeTranslation).Methods("PUT")
+	foodTypesProtected.Handle("/{id}/translations/{locale}", middleware.AdminOnlyMiddleware(http.HandlerFunc(handlers.DeleteFoodTypeTranslation))).Methods("DELETE")
+
+	// Restaurants (read-only public, write requires auth, delete requires a space admin,
+	// business details require the owner)
+	publicRoutes.HandleFunc("/restaurants", handlers.GetRestaurants).Methods("GET")
+	publicRoutes.HandleFunc("/restaurants/paginated", handlers.GetRestaurantsPaginated).Methods("GET")
+	publicRoutes.HandleFunc("/restaurants/in-bounds", handlers.GetRestaurantsInBounds).Methods("GET")
+	publicRoutes.HandleFunc("/restaurants/nearby", handlers.GetRestaurantsNearby).Methods("GET")
+	publicRoutes.HandleFunc("/restaurants/{id}", handlers.GetRestaurant).Methods("GET")
+	publicRoutes.HandleFunc("/restaurants/{id}/map", handlers.GetRestaurantMap).Methods("GET")
+	publicRoutes.HandleFunc("/restaurants/{id}/map/image", handlers.GetRestaurantMapImage).Methods("GET")
+
+	restaurantsProtected := api.PathPrefix("/restaurants").Subrouter()
+	restaurantsProtected.Use(middleware.AuthMiddleware)
+	restaurantsProtected.Use(handlers.SpaceMiddleware)
+	restaurantsProtected.Handle("", middleware.IdempotencyMiddleware(http.HandlerFunc(handlers.CreateRestaurant))).Methods("POST")
+	restaurantsProtected.HandleFunc("/{id}", handlers.UpdateRestaurant).Methods("PUT")
+	restaurantsProtected.Handle("/{id}", handlers.SpaceAdminMiddleware(http.HandlerFunc(handlers.DeleteRestaurant))).Methods("DELETE")
+	restaurantsProtected.HandleFunc("/{id}/claims", handlers.ClaimRestaurant).Methods("POST")
+	restaurantsProtected.HandleFunc("/{id}/business", handlers.UpdateRestaurantBusiness).Methods("PATCH")
+
+	// Global Search (public)
+	publicRoutes.HandleFunc("/search", handlers.GlobalSearch).Methods("GET")
+
+	// Ratings (read public, write requires auth, delete requires the author or an admin,
+	// responses require the owner of the restaurant)
+	publicRoutes.HandleFunc("/restaurants/{restaurantId}/ratings", handlers.GetRatings).Methods("GET")
+
+	ratingsProtected := api.PathPrefix("/ratings").Subrouter()
+	ratingsProtected.Use(middleware.AuthMiddleware)
+	ratingsProtected.Use(handlers.SpaceMiddleware)
+	ratingsProtected.Handle("", middleware.IdempotencyMiddleware(http.HandlerFunc(handlers.CreateRating))).Methods("POST")
+	ratingsProtected.HandleFunc("/{id}", handlers.DeleteRating).Methods("DELETE")
+	ratingsProtected.HandleFunc("/{id}/response", handlers.SetRatingResponse).Methods("PUT")
+	ratingsProtected.HandleFunc("/{id}/response", handlers.DeleteRatingResponse).Methods("DELETE")
+
+	// Google Maps (proxied through backend - public with rate limiting)
+	publicRoutes.HandleFunc("/places/search", handlers.SearchPlaces).Methods("GET")
+	publicRoutes.HandleFunc("/places/photo", handlers.GetPlacePhoto).Methods("GET")
+	publicRoutes.HandleFunc("/places/{placeId}", handlers.GetPlaceDetails).Methods("GET")
+	publicRoutes.HandleFunc("/geocode/cities", handlers.GeocodeCities).Methods("GET")
+
+	// Restaurant Suggestions (requires auth, delete and approvals require a space admin)
+	suggestionsProtected := api.PathPrefix("/suggestions").Subrouter()
+	suggestionsProtected.Use(middleware.AuthMiddleware)
+	suggestionsProtected.Use(handlers.SpaceMiddleware)
+	suggestionsProtected.HandleFunc("", handlers.GetSuggestions).Methods("GET")
+	suggestionsProtected.HandleFunc("/{id}", handlers.GetSuggestion).Methods("GET")
+	suggestionsProtected.Handle("", middleware.IdempotencyMiddleware(http.HandlerFunc(handlers.CreateSuggestion))).Methods("POST")
+	suggestionsProtected.HandleFunc("/{id}/status", handlers.UpdateSuggestionStatus).Methods("PATCH")
+	suggestionsProtected.HandleFunc("/{id}/convert", handlers.ConvertSuggestion).Methods("POST")
+	suggestionsProtected.Handle("/{id}", handlers.SpaceAdminMiddleware(http.HandlerFunc(handlers.DeleteSuggestion))).Methods("DELETE")
+	suggestionsProtected.HandleFunc("/{id}/events", handlers.GetSuggestionEvents).Methods("GET")
+	suggestionsProtected.HandleFunc("/{id}/approvals", handlers.GetSuggestionApprovals).Methods("GET")
+	suggestionsProtected.Handle("/{id}/approvals", handlers.SpaceAdminMiddleware(http.HandlerFunc(handlers.ApproveSuggestion))).Methods("POST")
+	suggestionsProtected.Handle("/{id}/approvals", handlers.SpaceAdminMiddleware(http.HandlerFunc(handlers.RevokeSuggestionApproval))).Methods("DELETE")
+
+	// Menu Photos (read public, write requires auth)
+	publicRoutes.HandleFunc("/restaurants/{restaurantId}/photos", handlers.GetMenuPhotos).Methods("GET")
+	publicRoutes.HandleFunc("/restaurants/{restaurantId}/photos/events", handlers.StreamPhotoEvents).Methods("GET")
+
+	// Live updates of changes made through any instance (Server-Sent Events)
+	publicRoutes.HandleFunc("/events", handlers.StreamEvents).Methods("GET")
+	publicRoutes.HandleFunc("/restaurants/{restaurantId}/photos/archive", handlers.DownloadPhotoArchive).Methods("GET")
+	publicRoutes.HandleFunc("/photos/{id}", handlers.GetMenuPhoto).Methods("GET")
+	publicRoutes.HandleFunc("/photos/{id}/image", handlers.GetPhotoImage).Methods("GET")
+
+	photosProtected := api.PathPrefix("").Subrouter()
+	photosProtected.Use(middleware.AuthMiddleware)
+	photosProtected.Use(handlers.SpaceMiddleware)
+	photosProtected.Handle("/restaurants/{restaurantId}/photos", middleware.IdempotencyMiddleware(http.HandlerFunc(handlers.UploadMenuPhoto))).Methods("POST")
+	photosProtected.Handle("/restaurants/{restaurantId}/photos/from-url", middleware.IdempotencyMiddleware(http.HandlerFunc(handlers.UploadMenuPhotoFromURL))).Methods("POST")
+	photosProtected.HandleFunc("/restaurants/{restaurantId}/photos/presign", handlers.PresignMenuPhotoUpload).Methods("POST")
+	photosProtected.Handle("/restaurants/{restaurantId}/photos/confirm", middleware.IdempotencyMiddleware(http.HandlerFunc(handlers.ConfirmMenuPhotoUpload))).Methods("POST")
+	photosProtected.HandleFunc("/restaurants/{restaurantId}/photos/order", handlers.ReorderMenuPhotos).Methods("PATCH")
+	photosProtected.HandleFunc("/photos/{id}", handlers.UpdatePhotoCaption).Methods("PATCH")
+	photosProtected.HandleFunc("/photos/{id}/cover", handlers.SetCoverPhoto).Methods("PUT")
+	photosProtected.HandleFunc("/photos/{id}/cover", handlers.UnsetCoverPhoto).Methods("DELETE")
+	photosProtected.HandleFunc("/photos/{id}", handlers.DeleteMenuPhoto).Methods("DELETE")
+
+	// Admin maintenance routes
+	adminRoutes := api.PathPrefix("/admin").Subrouter()
+	adminRoutes.Use(middleware.AuthMiddleware)
+	adminRoutes.Handle("/audit-log", middleware.AdminOnlyMiddleware(http.HandlerFunc(handlers.GetAuditLog))).Methods("GET")
+	adminRoutes.Handle("/invites", middleware.AdminOnlyMiddleware(http.HandlerFunc(handlers.CreateInvite))).Methods("POST")
+	adminRoutes.Handle("/invites", middleware.AdminOnlyMiddleware(http.HandlerFunc(handlers.ListInvites))).Methods("GET")
+	adminRoutes.Handle("/invites/{id}", middleware.AdminOnlyMiddleware(http.HandlerFunc(handlers.RevokeInvite))).Methods("DELETE")
+	adminRoutes.Handle("/service-accounts", middleware.AdminOnlyMiddleware(http.HandlerFunc(handlers.CreateServiceAccount))).Methods("POST")
+	adminRoutes.Handle("/service-accounts", middleware.AdminOnlyMiddleware(http.HandlerFunc(handlers.ListServiceAccounts))).Methods("GET")
+	adminRoutes.Handle("/service-accounts/{id}", middleware.AdminOnlyMiddleware(http.HandlerFunc(handlers.DisableServiceAccount))).Methods("DELETE")
+	adminRoutes.Handle("/service-accounts/{id}/keys", middleware.AdminOnlyMiddleware(http.HandlerFunc(handlers.CreateServiceAccountAPIKey))).Methods("POST")
+	adminRoutes.Handle("/service-accounts/{id}/keys", middleware.AdminOnlyMiddleware(http.HandlerFunc(handlers.ListServiceAccountAPIKeys))).Methods("GET")
+	adminRoutes.Handle("/service-accounts/{id}/keys/{keyId}", middleware.AdminOnlyMiddleware(http.HandlerFunc(handlers.DeleteServiceAccountAPIKey))).Methods("DELETE")
+	adminRoutes.Handle("/users/{id}/restore", middleware.AdminOnlyMiddleware(http.HandlerFunc(handlers.RestoreUser))).Methods("POST")
+	adminRoutes.Handle("/users/{id}/unlock", middleware.AdminOnlyMiddleware(http.HandlerFunc(handlers.UnlockUser))).Methods("POST")
+	adminRoutes.Handle("/backup", middleware.AdminOnlyMiddleware(http.HandlerFunc(handlers.CreateBackup))).Methods("POST")
+	adminRoutes.Handle("/backups", middleware.AdminOnlyMiddleware(http.HandlerFunc(handlers.ListBackups))).Methods("GET")
+	adminRoutes.Handle("/photos/cleanup", middleware.AdminOnlyMiddleware(http.HandlerFunc(handlers.CleanupPhotoStorage))).Methods("POST")
+	adminRoutes.Handle("/photos/moderation", middleware.AdminOnlyMiddleware(http.HandlerFunc(handlers.GetPhotoModerationQueue))).Methods("GET")
+	adminRoutes.Handle("/photos/{id}/moderation", middleware.AdminOnlyMiddleware(http.HandlerFunc(handlers.ModeratePhoto))).Methods("POST")
+	adminRoutes.Handle("/places/sync", middleware.AdminOnlyMiddleware(http.HandlerFunc(handlers.SyncPlaces))).Methods("POST")
+	adminRoutes.Handle("/place-changes", middleware.AdminOnlyMiddleware(http.HandlerFunc(handlers.GetPlaceChanges))).Methods("GET")
+	adminRoutes.Handle("/place-changes/{id}", middleware.AdminOnlyMiddleware(http.HandlerFunc(handlers.ReviewPlaceChange))).Methods("POST")
+	adminRoutes.Handle("/restaurant-claims", middleware.AdminOnlyMiddleware(http.HandlerFunc(handlers.GetRestaurantClaims))).Methods("GET")
+	adminRoutes.Handle("/restaurant-claims/{id}", middleware.AdminOnlyMiddleware(http.HandlerFunc(handlers.ReviewRestaurantClaim))).Methods("POST")
+	adminRoutes.Handle("/restaurants/{id}/external-sources", middleware.AdminOnlyMiddleware(http.HandlerFunc(handlers.FetchExternalSource))).Methods("POST")
+	adminRoutes.Handle("/restaurants/{id}/external-sources/{provider}", middleware.AdminOnlyMiddleware(http.HandlerFunc(handlers.DeleteExternalSource))).Methods("DELETE")
+	adminRoutes.Handle("/webhooks", middleware.AdminOnlyMiddleware(http.HandlerFunc(handlers.ListWebhooks))).Methods("GET")
+	adminRoutes.Handle("/webhooks", middleware.AdminOnlyMiddleware(http.HandlerFunc(handlers.CreateWebhook))).Methods("POST")
+	adminRoutes.Handle("/webhooks/{id}", middleware.AdminOnlyMiddleware(http.HandlerFunc(handlers.UpdateWebhook))).Methods("PUT")
+	adminRoutes.Handle("/webhooks/{id}", middleware.AdminOnlyMiddleware(http.HandlerFunc(handlers.DeleteWebhook))).Methods("DELETE")
+	adminRoutes.Handle("/webhooks/{id}/deliveries", middleware.AdminOnlyMiddleware(http.HandlerFunc(handlers.GetWebhookDeliveries))).Methods("GET")
+	adminRoutes.Handle("/quota", middleware.AdminOnlyMiddleware(http.HandlerFunc(handlers.GetMapsQuota))).Methods("GET")
+	adminRoutes.Handle("/settings", middleware.AdminOnlyMiddleware(http.HandlerFunc(handlers.ListSettings))).Methods("GET")
+	adminRoutes.Handle("/settings/{key}", middleware.AdminOnlyMiddleware(http.HandlerFunc(handlers.UpdateSetting))).Methods("PUT")
+	adminRoutes.Handle("/settings/{key}", middleware.AdminOnlyMiddleware(http.HandlerFunc(handlers.ResetSetting))).Methods("DELETE")
+	adminRoutes.Handle("/log-level", middleware.AdminOnlyMiddleware(http.HandlerFunc(handlers.GetLogLevels))).Methods("GET")
+	adminRoutes.Handle("/log-level", middleware.AdminOnlyMiddleware(http.HandlerFunc(handlers.UpdateLogLevels))).Methods("PUT")
+
+	// Runtime diagnostics (pprof profiles, expvar) for admins, off unless enabled
+	if opts.DebugEndpoints {
+		debugRoutes := api.PathPrefix("/debug").Subrouter()
+		debugRoutes.Use(middleware.AuthMiddleware)
+		debugRoutes.PathPrefix("/").Handler(middleware.AdminOnlyMiddleware(handlers.DebugHandler())).Methods("GET", "POST")
+		logger.Warn("⚠️  Debug endpoints enabled under /api/debug/ (admins only)")
+	}
+
+	// Health checks (support both GET and HEAD for Docker healthcheck): readiness checks
+	// dependencies, liveness only that the process responds
+	api.HandleFunc("/health", handlers.ReadinessCheck).Methods("GET", "HEAD")
+	api.HandleFunc("/health/ready", handlers.ReadinessCheck).Methods("GET", "HEAD")
+	api.HandleFunc("/health/live", handlers.LivenessCheck).Methods("GET", "HEAD")
+}
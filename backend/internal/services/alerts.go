@@ -0,0 +1,164 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Alert is an error operators should look at, such as a recovered panic
+type Alert struct {
+	Fingerprint string            `json:"fingerprint"` // Groups alerts of the same error
+	Title       string            `json:"title"`       // e.g. "Panic in GET /api/restaurants/{id}"
+	Message     string            `json:"message"`
+	Stack       string            `json:"stack,omitempty"`
+	Tags        map[string]string `json:"tags,omitempty"` // e.g. request_id, method and path
+	Occurrences int               `json:"occurrences"`    // Including those not alerted since the last alert
+	Time        time.Time         `json:"time"`
+}
+
+// Alerter sends alerts to operators. WebhookAlerter and SentryAlerter implement it.
+type Alerter interface {
+	// Name identifies the destination in logs
+	Name() string
+	Alert(ctx context.Context, a Alert) error
+}
+
+var (
+	_ Alerter = (*WebhookAlerter)(nil)
+	_ Alerter = (*SentryAlerter)(nil)
+)
+
+// WebhookAlerter posts alerts as JSON to a URL
+type WebhookAlerter struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookAlerter creates an alerter posting to url
+func NewWebhookAlerter(url string, timeout time.Duration) *WebhookAlerter {
+	return &WebhookAlerter{url: url, client: &http.Client{Timeout: timeout}}
+}
+
+func (w *WebhookAlerter) Name() string { return "webhook" }
+
+func (w *WebhookAlerter) Alert(ctx context.Context, a Alert) error {
+	return postChatWebhook(ctx, w.client, w.url, a)
+}
+
+// SentryAlerter sends alerts as error events to a Sentry project through its envelope
+// endpoint, without the Sentry SDK
+type SentryAlerter struct {
+	dsn         string
+	endpoint    string
+	publicKey   string
+	environment string
+	client      *http.Client
+}
+
+// NewSentryAlerter creates an alerter for a Sentry DSN
+// (https://<public key>@<host>/<project ID>), tagging events with environment
+func NewSentryAlerter(dsn, environment string, timeout time.Duration) (*SentryAlerter, error) {
+	endpoint, publicKey, err := ParseSentryDSN(dsn)
+	if err != nil {
+		return nil, err
+	}
+	return &SentryAlerter{
+		dsn:         dsn,
+		endpoint:    endpoint,
+		publicKey:   publicKey,
+		environment: environment,
+		client:      &http.Client{Timeout: timeout},
+	}, nil
+}
+
+// ParseSentryDSN returns the envelope endpoint and public key of a Sentry DSN
+func ParseSentryDSN(dsn string) (endpoint, publicKey string, err error) {
+	u, err := url.Parse(dsn)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" || u.User == nil {
+		return "", "", fmt.Errorf("invalid Sentry DSN, expected https://<key>@<host>/<project ID>")
+	}
+	// The project ID is the last path segment, after an optional prefix
+	path, projectID := "", strings.Trim(u.Path, "/")
+	if i := strings.LastIndex(projectID, "/"); i >= 0 {
+		path, projectID = "/"+projectID[:i], projectID[i+1:]
+	}
+	if projectID == "" || u.User.Username() == "" {
+		return "", "", fmt.Errorf("invalid Sentry DSN, expected https://<key>@<host>/<project ID>")
+	}
+	return fmt.Sprintf("%s://%s%s/api/%s/envelope/", u.Scheme, u.Host, path, projectID), u.User.Username(), nil
+}
+
+func (s *SentryAlerter) Name() string { return "sentry" }
+
+// sentryEvent is the subset of the Sentry event payload alerts fill in
+type sentryEvent struct {
+	EventID     string            `json:"event_id"`
+	Timestamp   string            `json:"timestamp"`
+	Level       string            `json:"level"`
+	Platform    string            `json:"platform"`
+	Logger      string            `json:"logger"`
+	Environment string            `json:"environment,omitempty"`
+	Fingerprint []string          `json:"fingerprint"`
+	Message     map[string]string `json:"message"`
+	Tags        map[string]string `json:"tags,omitempty"`
+	Extra       map[string]any    `json:"extra,omitempty"`
+}
+
+func (s *SentryAlerter) Alert(ctx context.Context, a Alert) error {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return err
+	}
+	event := sentryEvent{
+		EventID:     hex.EncodeToString(id),
+		Timestamp:   a.Time.UTC().Format(time.RFC3339),
+		Level:       "fatal",
+		Platform:    "go",
+		Logger:      "nomdb",
+		Environment: s.environment,
+		Fingerprint: []string{a.Fingerprint},
+		Message:     map[string]string{"formatted": a.Title + ": " + a.Message},
+		Tags:        a.Tags,
+		Extra:       map[string]any{"stack": a.Stack, "occurrences": a.Occurrences},
+	}
+
+	// An envelope is a header line followed by items, each a header and a payload line
+	var body bytes.Buffer
+	enc := json.NewEncoder(&body)
+	for _, line := range []any{
+		map[string]string{"event_id": event.EventID, "dsn": s.dsn, "sent_at": time.Now().UTC().Format(time.RFC3339)},
+		map[string]string{"type": "event"},
+		event,
+	} {
+		if err := enc.Encode(line); err != nil {
+			return err
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-sentry-envelope")
+	req.Header.Set("X-Sentry-Auth", "Sentry sentry_version=7, sentry_client=nomdb/1.0, sentry_key="+s.publicKey)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		excerpt, _ := io.ReadAll(io.LimitReader(resp.Body, 256))
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(excerpt)))
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseSentryDSN(t *testing.T) {
+	tests := []struct {
+		dsn          string
+		wantEndpoint string
+		wantKey      string
+		wantErr      bool
+	}{
+		{"https://abc@o1.ingest.sentry.io/42", "https://o1.ingest.sentry.io/api/42/envelope/", "abc", false},
+		{"https://abc@sentry.example.com/sentry/7", "https://sentry.example.com/sentry/api/7/envelope/", "abc", false},
+		{"https://o1.ingest.sentry.io/42", "", "", true},
+		{"https://abc@o1.ingest.sentry.io/", "", "", true},
+		{"ftp://abc@o1.ingest.sentry.io/42", "", "", true},
+	}
+	for _, tt := range tests {
+		endpoint, key, err := ParseSentryDSN(tt.dsn)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseSentryDSN(%q) error = %v, want error %v", tt.dsn, err, tt.wantErr)
+			continue
+		}
+		if endpoint != tt.wantEndpoint || key != tt.wantKey {
+			t.Errorf("ParseSentryDSN(%q) = %q, %q", tt.dsn, endpoint, key)
+		}
+	}
+}
+
+var testAlert = Alert{
+	Fingerprint: "3f2a9c1b7d4e",
+	Title:       "Panic in GET /api/restaurants/1",
+	Message:     "assignment to entry in nil map",
+	Stack:       "goroutine 1 [running]:",
+	Tags:        map[string]string{"request_id": "req-1"},
+	Occurrences: 2,
+	Time:        time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+}
+
+func TestWebhookAlerter(t *testing.T) {
+	url, body := newChatServer(t, http.StatusOK)
+
+	if err := NewWebhookAlerter(url, time.Second).Alert(context.Background(), testAlert); err != nil {
+		t.Fatalf("Alert failed: %v", err)
+	}
+	if (*body)["fingerprint"] != testAlert.Fingerprint || (*body)["occurrences"] != 2.0 || (*body)["stack"] != testAlert.Stack {
+		t.Errorf("Unexpected alert %v", *body)
+	}
+}
+
+func TestSentryAlerter(t *testing.T) {
+	var auth string
+	var lines []map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/42/envelope/" {
+			t.Errorf("Unexpected path %s", r.URL.Path)
+		}
+		auth = r.Header.Get("X-Sentry-Auth")
+		scanner := bufio.NewScanner(r.Body)
+		for scanner.Scan() {
+			var line map[string]any
+			json.Unmarshal(scanner.Bytes(), &line)
+			lines = append(lines, line)
+		}
+	}))
+	defer server.Close()
+
+	alerter, err := NewSentryAlerter(strings.Replace(server.URL, "://", "://abc@", 1)+"/42", "staging", time.Second)
+	if err != nil {
+		t.Fatalf("NewSentryAlerter failed: %v", err)
+	}
+	if err := alerter.Alert(context.Background(), testAlert); err != nil {
+		t.Fatalf("Alert failed: %v", err)
+	}
+
+	if !strings.Contains(auth, "sentry_key=abc") {
+		t.Errorf("Expected the public key in the auth header, got %q", auth)
+	}
+	if len(lines) != 3 || lines[1]["type"] != "event" {
+		t.Fatalf("Expected an envelope with one event, got %v", lines)
+	}
+	event := lines[2]
+	if event["event_id"] != lines[0]["event_id"] || event["environment"] != "staging" || event["level"] != "fatal" {
+		t.Errorf("Unexpected event %v", event)
+	}
+	if fingerprint := event["fingerprint"].([]any); fingerprint[0] != testAlert.Fingerprint {
+		t.Errorf("Expected the alert fingerprint, got %v", fingerprint)
+	}
+	if tags := event["tags"].(map[string]any); tags["request_id"] != "req-1" {
+		t.Errorf("Expected the request ID tag, got %v", tags)
+	}
+}
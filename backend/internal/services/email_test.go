@@ -0,0 +1,25 @@
+package services
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuildEmail(t *testing.T) {
+	msg := buildEmail("Nom Database <noreply@example.com>", EmailMessage{
+		To:      "user@example.com",
+		Subject: "Reset\r\nBcc: attacker@example.com",
+		Body:    "line one\nline two",
+	})
+	text := string(msg)
+
+	if strings.Contains(text, "\r\nBcc:") {
+		t.Errorf("Expected header injection to be stripped, got:\n%s", text)
+	}
+	if !strings.Contains(text, "Subject: ResetBcc: attacker@example.com\r\n") {
+		t.Errorf("Expected subject on a single line, got:\n%s", text)
+	}
+	if !strings.HasSuffix(text, "\r\n\r\nline one\r\nline two") {
+		t.Errorf("Expected CRLF body after headers, got:\n%q", text)
+	}
+}
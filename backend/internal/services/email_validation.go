@@ -0,0 +1,203 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/mail"
+	"strings"
+	"time"
+)
+
+// Email validation errors
+var (
+	ErrInvalidEmail      = errors.New("invalid email address")
+	ErrDisposableEmail   = errors.New("disposable email addresses are not allowed")
+	ErrEmailDomainNoMail = errors.New("email domain does not accept mail")
+)
+
+// Maximum lengths from RFC 5321
+const (
+	maxEmailLength     = 254
+	maxEmailLocalPart  = 64
+	maxEmailDomainPart = 253
+)
+
+// mxLookupTimeout bounds DNS lookups during registration
+const mxLookupTimeout = 3 * time.Second
+
+// DefaultDisposableDomains are common throwaway email providers, blocked unless disabled
+var DefaultDisposableDomains = []string{
+	"10minutemail.com",
+	"discard.email",
+	"dispostable.com",
+	"emailondeck.com",
+	"fakeinbox.com",
+	"getairmail.com",
+	"getnada.com",
+	"guerrillamail.com",
+	"guerrillamail.net",
+	"guerrillamailblock.com",
+	"maildrop.cc",
+	"mailinator.com",
+	"mailnesia.com",
+	"mintemail.com",
+	"mohmal.com",
+	"moakt.com",
+	"sharklasers.com",
+	"spamgourmet.com",
+	"temp-mail.org",
+	"tempmail.com",
+	"tempmailo.com",
+	"throwawaymail.com",
+	"trashmail.com",
+	"yopmail.com",
+}
+
+// NormalizeEmail trims and lowercases an address and checks that it is a plain RFC 5322
+// address (no display name or comments) within the RFC 5321 length limits, with a
+// domain that has at least two labels.
+func NormalizeEmail(email string) (string, error) {
+	email = strings.ToLower(strings.TrimSpace(email))
+	if email == "" || len(email) > maxEmailLength {
+		return "", ErrInvalidEmail
+	}
+
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Name != "" || addr.Address != email {
+		return "", ErrInvalidEmail
+	}
+
+	at := strings.LastIndex(email, "@")
+	local, domain := email[:at], email[at+1:]
+	if len(local) > maxEmailLocalPart || !isValidEmailDomain(domain) {
+		return "", ErrInvalidEmail
+	}
+	return email, nil
+}
+
+// isValidEmailDomain checks for a hostname with at least two labels of letters, digits
+// and inner dashes, rejecting IP literals and single-label hosts such as "localhost"
+func isValidEmailDomain(domain string) bool {
+	if len(domain) > maxEmailDomainPart {
+		return false
+	}
+	labels := strings.Split(domain, ".")
+	if len(labels) < 2 {
+		return false
+	}
+	for _, label := range labels {
+		if label == "" || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		}
+		for _, c := range label {
+			if !(c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '-' || c > 127) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// EmailValidationConfig configures an EmailValidator
+type EmailValidationConfig struct {
+	BlockDisposable bool     // Block DefaultDisposableDomains
+	BlockedDomains  []string // Additional domains to block (subdomains are blocked too)
+	CheckMX         bool     // Require the domain to have MX (or A/AAAA) records
+}
+
+// EmailValidator validates addresses of new accounts
+type EmailValidator struct {
+	blocked  map[string]bool
+	checkMX  bool
+	resolver *net.Resolver
+}
+
+// NewEmailValidator returns a validator for the given configuration
+func NewEmailValidator(cfg EmailValidationConfig) *EmailValidator {
+	v := &EmailValidator{
+		blocked:  make(map[string]bool),
+		checkMX:  cfg.CheckMX,
+		resolver: net.DefaultResolver,
+	}
+	if cfg.BlockDisposable {
+		for _, domain := range DefaultDisposableDomains {
+			v.blocked[domain] = true
+		}
+	}
+	for _, domain := range cfg.BlockedDomains {
+		v.blocked[strings.ToLower(strings.TrimSpace(domain))] = true
+	}
+	return v
+}
+
+// BlockedDomainCount returns the number of blocked domains, for logging
+func (v *EmailValidator) BlockedDomainCount() int {
+	return len(v.blocked)
+}
+
+// Validate normalizes an address and checks it against the blocklist and, if enabled,
+// the domain's mail records. It returns the normalized address.
+func (v *EmailValidator) Validate(ctx context.Context, email string) (string, error) {
+	email, err := NormalizeEmail(email)
+	if err != nil {
+		return "", err
+	}
+
+	domain := email[strings.LastIndex(email, "@")+1:]
+	if v.isBlocked(domain) {
+		return "", ErrDisposableEmail
+	}
+
+	if v.checkMX {
+		if err := v.checkMailDomain(ctx, domain); err != nil {
+			return "", err
+		}
+	}
+	return email, nil
+}
+
+// isBlocked reports whether domain or one of its parent domains is blocked
+func (v *EmailValidator) isBlocked(domain string) bool {
+	for {
+		if v.blocked[domain] {
+			return true
+		}
+		dot := strings.IndexByte(domain, '.')
+		if dot < 0 {
+			return false
+		}
+		domain = domain[dot+1:]
+	}
+}
+
+// checkMailDomain requires MX records, or A/AAAA records as the implicit MX of RFC 5321.
+// Only a definite "no such host" rejects the address; DNS failures let it through so a
+// resolver outage does not block registrations.
+func (v *EmailValidator) checkMailDomain(ctx context.Context, domain string) error {
+	ctx, cancel := context.WithTimeout(ctx, mxLookupTimeout)
+	defer cancel()
+
+	records, err := v.resolver.LookupMX(ctx, domain)
+	if err == nil {
+		// A single "." MX record means the domain explicitly accepts no mail (RFC 7505)
+		if len(records) == 1 && records[0].Host == "." {
+			return ErrEmailDomainNoMail
+		}
+		if len(records) > 0 {
+			return nil
+		}
+	} else if !isNotFound(err) {
+		return nil
+	}
+
+	if _, err := v.resolver.LookupIPAddr(ctx, domain); err != nil && isNotFound(err) {
+		return ErrEmailDomainNoMail
+	}
+	return nil
+}
+
+func isNotFound(err error) bool {
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr) && dnsErr.IsNotFound
+}
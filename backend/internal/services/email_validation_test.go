@@ -0,0 +1,68 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeEmail(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		valid    bool
+	}{
+		{"user@example.com", "user@example.com", true},
+		{"  John.Doe+food@Example.COM ", "john.doe+food@example.com", true},
+		{"user@sub.example.co.uk", "user@sub.example.co.uk", true},
+		{"", "", false},
+		{"user", "", false},
+		{"user@", "", false},
+		{"@example.com", "", false},
+		{"user@localhost", "", false},
+		{"user@example..com", "", false},
+		{"user@-example.com", "", false},
+		{"user@[127.0.0.1]", "", false},
+		{"John <user@example.com>", "", false},
+		{"user@example.com, other@example.com", "", false},
+		{"a.b@c", "", false},
+		{strings.Repeat("a", 65) + "@example.com", "", false},
+		{"user@" + strings.Repeat("a", 250) + ".com", "", false},
+	}
+
+	for _, tt := range tests {
+		got, err := NormalizeEmail(tt.input)
+		if tt.valid {
+			if err != nil || got != tt.expected {
+				t.Errorf("NormalizeEmail(%q) = %q, %v; expected %q", tt.input, got, err, tt.expected)
+			}
+		} else if !errors.Is(err, ErrInvalidEmail) {
+			t.Errorf("NormalizeEmail(%q) = %q; expected ErrInvalidEmail", tt.input, got)
+		}
+	}
+}
+
+func TestEmailValidatorBlocklist(t *testing.T) {
+	ctx := context.Background()
+	v := NewEmailValidator(EmailValidationConfig{BlockDisposable: true, BlockedDomains: []string{" Example.org "}})
+
+	blocked := []string{"someone@mailinator.com", "someone@eu.mailinator.com", "someone@EXAMPLE.org"}
+	for _, email := range blocked {
+		if _, err := v.Validate(ctx, email); !errors.Is(err, ErrDisposableEmail) {
+			t.Errorf("Validate(%q) = %v; expected ErrDisposableEmail", email, err)
+		}
+	}
+
+	allowed := []string{"someone@example.com", "someone@notmailinator.com"}
+	for _, email := range allowed {
+		if _, err := v.Validate(ctx, email); err != nil {
+			t.Errorf("Validate(%q) = %v; expected no error", email, err)
+		}
+	}
+
+	permissive := NewEmailValidator(EmailValidationConfig{})
+	if _, err := permissive.Validate(ctx, "someone@mailinator.com"); err != nil {
+		t.Errorf("Expected disposable domains to be allowed when blocking is disabled, got %v", err)
+	}
+}
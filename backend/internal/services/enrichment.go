@@ -0,0 +1,95 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode"
+
+	"github.com/nomdb/backend/internal/models"
+	"github.com/nomdb/backend/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Enrichment providers selectable via ENRICHMENT_PROVIDER
+const (
+	EnrichmentProviderYelp       = "yelp"
+	EnrichmentProviderFoursquare = "foursquare"
+)
+
+// ErrNoExternalMatch is returned when no business of the provider matches the restaurant
+var ErrNoExternalMatch = errors.New("no matching business found")
+
+// EnrichmentQuery identifies a restaurant to look up: its name and its coordinates, or
+// its address without them
+type EnrichmentQuery struct {
+	Name      string
+	Latitude  *float64
+	Longitude *float64
+	Address   string
+}
+
+// EnrichmentProvider looks up review site data of restaurants. YelpProvider and
+// FoursquareProvider implement it.
+type EnrichmentProvider interface {
+	// Name returns the provider identifier, stored with the data
+	Name() string
+	// Lookup finds the business matching the restaurant, or ErrNoExternalMatch
+	Lookup(ctx context.Context, query EnrichmentQuery) (*models.ExternalSource, error)
+}
+
+var (
+	_ EnrichmentProvider = (*YelpProvider)(nil)
+	_ EnrichmentProvider = (*FoursquareProvider)(nil)
+)
+
+// enrichmentGet sends a GET request with header in a client span named after operation
+// and decodes the response into v
+func enrichmentGet(ctx context.Context, client *http.Client, operation, url string, header http.Header, v any) error {
+	ctx, span := tracing.Tracer().Start(ctx, operation, trace.WithSpanKind(trace.SpanKindClient))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		tracing.EndSpan(span, err)
+		return err
+	}
+	req.Header = header
+	span.SetAttributes(attribute.String("server.address", req.URL.Hostname()))
+
+	resp, err := client.Do(req)
+	if err != nil {
+		tracing.EndSpan(span, err)
+		return err
+	}
+	defer resp.Body.Close()
+	span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+	if resp.StatusCode != http.StatusOK {
+		err = fmt.Errorf("unexpected status %d", resp.StatusCode)
+	} else if decodeErr := json.NewDecoder(resp.Body).Decode(v); decodeErr != nil {
+		err = fmt.Errorf("failed to decode response: %w", decodeErr)
+	}
+	tracing.EndSpan(span, err)
+	return err
+}
+
+// normalizeBusinessName keeps the lowercase letters and digits of a name, so "Luigi's
+// Pizzeria" and "LUIGIS pizzeria" compare equal
+func normalizeBusinessName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, name)
+}
+
+// sameBusinessName reports whether a search result is the restaurant rather than
+// another business nearby: one name contains the other, as in "Luigi's" and "Luigi's
+// Pizzeria Berlin"
+func sameBusinessName(a, b string) bool {
+	a, b = normalizeBusinessName(a), normalizeBusinessName(b)
+	return a != "" && b != "" && (strings.Contains(a, b) || strings.Contains(b, a))
+}
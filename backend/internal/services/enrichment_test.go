@@ -0,0 +1,105 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSameBusinessName(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"Luigi's", "LUIGIS", true},
+		{"Luigi's Pizzeria Berlin", "Luigi's", true},
+		{"Luigi's", "Mario's", false},
+		{"!!!", "Luigi's", false},
+	}
+	for _, tt := range tests {
+		if got := sameBusinessName(tt.a, tt.b); got != tt.want {
+			t.Errorf("sameBusinessName(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+// newEnrichmentServer answers with body and records the request
+func newEnrichmentServer(t *testing.T, body string) (string, *http.Request) {
+	t.Helper()
+	var request http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request = *r
+		fmt.Fprint(w, body)
+	}))
+	t.Cleanup(server.Close)
+	return server.URL, &request
+}
+
+func TestYelpLookup(t *testing.T) {
+	baseURL, request := newEnrichmentServer(t, `{"businesses": [
+		{"id": "marios", "name": "Mario's"},
+		{"id": "luigis-berlin", "name": "Luigi's Pizzeria", "url": "https://www.yelp.com/biz/luigis-berlin?adjust_creative=x",
+			"rating": 4.5, "review_count": 120, "price": "$$", "categories": [{"alias": "pizza", "title": "Pizza"}]}]}`)
+	p := NewYelpProvider("yelp-key", time.Second)
+	p.baseURL = baseURL
+
+	lat, lng := 52.5, 13.4
+	source, err := p.Lookup(context.Background(), EnrichmentQuery{Name: "Luigi's", Latitude: &lat, Longitude: &lng})
+	if err != nil {
+		t.Fatalf("Lookup failed: %v", err)
+	}
+
+	q := request.URL.Query()
+	if request.URL.Path != "/v3/businesses/search" || q.Get("term") != "Luigi's" || q.Get("latitude") != "52.5" || q.Get("longitude") != "13.4" {
+		t.Errorf("Unexpected request %s", request.URL)
+	}
+	if request.Header.Get("Authorization") != "Bearer yelp-key" {
+		t.Errorf("Expected the API key, got %q", request.Header.Get("Authorization"))
+	}
+
+	// Mario's is closer, but not the restaurant
+	if source.ExternalID != "luigis-berlin" || *source.URL != "https://www.yelp.com/biz/luigis-berlin" {
+		t.Errorf("Unexpected source %+v", source)
+	}
+	if *source.Rating != 4.5 || *source.RatingCount != 120 || *source.PriceLevel != 2 || source.Categories[0] != "Pizza" {
+		t.Errorf("Unexpected details %+v", source)
+	}
+}
+
+func TestFoursquareLookup(t *testing.T) {
+	baseURL, request := newEnrichmentServer(t, `{"results": [{"fsq_id": "4b0", "name": "Luigi's", "rating": 8.6,
+		"price": 2, "stats": {"total_ratings": 40}, "categories": [{"name": "Pizzeria"}]}]}`)
+	p := NewFoursquareProvider("fsq-key", time.Second)
+	p.baseURL = baseURL
+
+	source, err := p.Lookup(context.Background(), EnrichmentQuery{Name: "Luigi's", Address: "Main St 1, Berlin"})
+	if err != nil {
+		t.Fatalf("Lookup failed: %v", err)
+	}
+
+	q := request.URL.Query()
+	if request.URL.Path != "/v3/places/search" || q.Get("near") != "Main St 1, Berlin" || q.Has("ll") {
+		t.Errorf("Unexpected request %s", request.URL)
+	}
+	if request.Header.Get("Authorization") != "fsq-key" {
+		t.Errorf("Expected the API key, got %q", request.Header.Get("Authorization"))
+	}
+	// Ratings out of 10 are halved
+	if *source.Rating != 4.3 || *source.RatingCount != 40 || *source.PriceLevel != 2 || *source.URL != "https://foursquare.com/v/4b0" {
+		t.Errorf("Unexpected source %+v", source)
+	}
+}
+
+func TestFoursquareLookup_NoMatch(t *testing.T) {
+	baseURL, _ := newEnrichmentServer(t, `{"results": [{"fsq_id": "4b1", "name": "Mario's"}]}`)
+	p := NewFoursquareProvider("fsq-key", time.Second)
+	p.baseURL = baseURL
+
+	if _, err := p.Lookup(context.Background(), EnrichmentQuery{Name: "Luigi's", Address: "Berlin"}); !errors.Is(err, ErrNoExternalMatch) {
+		t.Errorf("Expected ErrNoExternalMatch, got %v", err)
+	}
+}
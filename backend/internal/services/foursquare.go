@@ -0,0 +1,104 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/nomdb/backend/internal/logger"
+	"github.com/nomdb/backend/internal/models"
+)
+
+const foursquareBaseURL = "https://api.foursquare.com"
+
+// foursquareDiningCategory is the Foursquare category of restaurants, cafés and bars
+const foursquareDiningCategory = "13000"
+
+// FoursquareProvider looks up restaurants with the Foursquare Places API place search
+type FoursquareProvider struct {
+	apiKey  string
+	baseURL string
+	client  *http.Client
+}
+
+// NewFoursquareProvider creates a provider authenticating with a Foursquare API key
+func NewFoursquareProvider(apiKey string, timeout time.Duration) *FoursquareProvider {
+	return &FoursquareProvider{apiKey: apiKey, baseURL: foursquareBaseURL, client: &http.Client{Timeout: timeout}}
+}
+
+func (p *FoursquareProvider) Name() string { return EnrichmentProviderFoursquare }
+
+type foursquarePlace struct {
+	FSQID  string   `json:"fsq_id"`
+	Name   string   `json:"name"`
+	Rating *float64 `json:"rating"` // 0 to 10
+	Price  *int     `json:"price"`  // 1 to 4
+	Stats  *struct {
+		TotalRatings int `json:"total_ratings"`
+	} `json:"stats"`
+	Categories []struct {
+		Name string `json:"name"`
+	} `json:"categories"`
+}
+
+func (f foursquarePlace) source() *models.ExternalSource {
+	link := "https://foursquare.com/v/" + url.PathEscape(f.FSQID)
+	source := &models.ExternalSource{
+		Provider:   EnrichmentProviderFoursquare,
+		ExternalID: f.FSQID,
+		Name:       f.Name,
+		URL:        &link,
+		PriceLevel: f.Price,
+		Categories: make([]string, 0, len(f.Categories)),
+		FetchedAt:  time.Now(),
+	}
+	if f.Rating != nil {
+		// Ratings are stored on the 5 point scale of Yelp and Google
+		rating := *f.Rating / 2
+		source.Rating = &rating
+	}
+	if f.Stats != nil {
+		count := f.Stats.TotalRatings
+		source.RatingCount = &count
+	}
+	for _, c := range f.Categories {
+		source.Categories = append(source.Categories, c.Name)
+	}
+	return source
+}
+
+// Lookup searches dining places by name near the restaurant and returns the closest
+// one with a matching name
+func (p *FoursquareProvider) Lookup(ctx context.Context, query EnrichmentQuery) (*models.ExternalSource, error) {
+	params := url.Values{}
+	params.Set("query", query.Name)
+	if query.Latitude != nil && query.Longitude != nil {
+		params.Set("ll", strconv.FormatFloat(*query.Latitude, 'f', -1, 64)+","+strconv.FormatFloat(*query.Longitude, 'f', -1, 64))
+		params.Set("radius", "500")
+	} else {
+		params.Set("near", query.Address)
+	}
+	params.Set("categories", foursquareDiningCategory)
+	params.Set("fields", "fsq_id,name,rating,price,stats,categories")
+	params.Set("sort", "DISTANCE")
+	params.Set("limit", "5")
+
+	var resp struct {
+		Results []foursquarePlace `json:"results"`
+	}
+	header := http.Header{"Authorization": {p.apiKey}, "Accept": {"application/json"}}
+	if err := enrichmentGet(ctx, p.client, "foursquare.search", p.baseURL+"/v3/places/search?"+params.Encode(), header, &resp); err != nil {
+		logger.Error("Failed to search Foursquare: %v", err)
+		return nil, fmt.Errorf("failed to search Foursquare: %w", err)
+	}
+
+	for _, place := range resp.Results {
+		if sameBusinessName(place.Name, query.Name) {
+			return place.source(), nil
+		}
+	}
+	return nil, ErrNoExternalMatch
+}
@@ -1,29 +1,98 @@
 package services
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
-	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/nomdb/backend/internal/logger"
 	"github.com/nomdb/backend/internal/models"
 )
 
+// mapsLog logs the Google Maps requests
+var mapsLog = logger.Module("google_maps")
+
 type GoogleMapsService struct {
-	apiKey string
+	apiKey        string
+	baseURL       string
+	placesBaseURL string
+	placesAPI     string
+	client        *http.Client
+	maxRetries    int
+	retryDelay    time.Duration
+	breaker       *circuitBreaker
+	quota         *mapsQuota
+	signingKey    []byte // Signs Static Maps URLs when set
+	searchType    string // Place type searches are restricted to, if any
+	searchKeyword string // Appended to legacy text search queries, if any
 }
 
 func NewGoogleMapsService() *GoogleMapsService {
-	apiKey := os.Getenv("GOOGLE_MAPS_API_KEY")
-	if apiKey == "" {
-		logger.Warn("⚠️  Google Maps API key not configured - map features will be disabled")
-	} else {
-		logger.Info("🗺️  Google Maps service initialized")
+	s := &GoogleMapsService{
+		baseURL:       mapsBaseURL,
+		placesBaseURL: placesNewBaseURL,
+	}
+	s.Configure(DefaultGoogleMapsOptions)
+	return s
+}
+
+func (s *GoogleMapsService) Name() string { return PlacesProviderGoogle }
+
+// Enabled reports whether an API key is configured
+func (s *GoogleMapsService) Enabled() bool {
+	return s.apiKey != ""
+}
+
+// Ping checks that the Maps API is reachable. The request carries no API key, so it is
+// denied by Google and never counts against the quota.
+func (s *GoogleMapsService) Ping(ctx context.Context) error {
+	resp, err := s.do(ctx, "ping", s.legacyRequest("/maps/api/geocode/json", url.Values{}))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// CheckAPIKey verifies that Google accepts the API key with a geocoding request without
+// an address, which Google rejects as invalid after checking the key. It bypasses
+// retries, the circuit breaker and the daily budget.
+func (s *GoogleMapsService) CheckAPIKey(ctx context.Context) error {
+	if !s.Enabled() {
+		return fmt.Errorf("no API key configured")
 	}
-	return &GoogleMapsService{
-		apiKey: apiKey,
+	resp, err := s.do(ctx, "check_key", s.legacyRequest("/maps/api/geocode/json", url.Values{"key": {s.apiKey}}))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Status       string `json:"status"`
+		ErrorMessage string `json:"error_message"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	switch result.Status {
+	case "INVALID_REQUEST", "OK", "ZERO_RESULTS":
+		return nil
+	case "REQUEST_DENIED":
+		return fmt.Errorf("API key rejected: %s", result.ErrorMessage)
+	default:
+		return fmt.Errorf("Google Maps API error: %s %s", result.Status, result.ErrorMessage)
 	}
 }
 
@@ -39,7 +108,8 @@ type PlacesSearchResponse struct {
 			} `json:"location"`
 		} `json:"geometry"`
 	} `json:"results"`
-	Status string `json:"status"`
+	NextPageToken string `json:"next_page_token"`
+	Status        string `json:"status"`
 }
 
 type PlaceDetailsResponse struct {
@@ -50,45 +120,68 @@ type PlaceDetailsResponse struct {
 		FormattedPhoneNumber     string `json:"formatted_phone_number"`
 		InternationalPhoneNumber string `json:"international_phone_number"`
 		Website                  string `json:"website"`
+		BusinessStatus           string `json:"business_status"`
 		Geometry                 struct {
 			Location struct {
 				Lat float64 `json:"lat"`
 				Lng float64 `json:"lng"`
 			} `json:"location"`
 		} `json:"geometry"`
+		OpeningHours *struct {
+			OpenNow     *bool    `json:"open_now"`
+			WeekdayText []string `json:"weekday_text"`
+		} `json:"opening_hours"`
 	} `json:"result"`
 	Status string `json:"status"`
 }
 
-func (s *GoogleMapsService) SearchPlaces(query string) ([]models.GooglePlaceResult, error) {
+// SearchPlaces finds restaurants matching the search, cached for the search TTL of
+// InitPlacesCache
+func (s *GoogleMapsService) SearchPlaces(ctx context.Context, search PlaceSearch) (*PlaceSearchResults, error) {
+	// Results of the two API versions differ in their fields, so they are cached apart
+	return cachedPlaces(ctx, placesOperationSearch, s.placesAPI+":"+search.cacheKey(), func() (*PlaceSearchResults, error) {
+		return s.searchPlaces(ctx, search)
+	})
+}
+
+func (s *GoogleMapsService) searchPlaces(ctx context.Context, search PlaceSearch) (*PlaceSearchResults, error) {
 	if s.apiKey == "" {
-		logger.Error("Google Maps API key not configured")
+		mapsLog.Ctx(ctx).Error("Google Maps API key not configured")
 		return nil, fmt.Errorf("Google Maps API key not configured")
 	}
 
-	logger.Debug("🔍 Searching Google Maps for: %s", query)
+	mapsLog.Ctx(ctx).Debug("🔍 Searching Google Maps for: %s", search.Query)
+	if s.placesAPI == PlacesAPINew {
+		return s.searchPlacesNew(ctx, search)
+	}
 
-	baseURL := "https://maps.googleapis.com/maps/api/place/textsearch/json"
+	query := search.Query
+	if s.searchKeyword != "" {
+		query += " " + s.searchKeyword
+	}
 	params := url.Values{}
-	params.Set("query", query+" restaurant")
-	params.Set("type", "restaurant")
-	params.Set("key", s.apiKey)
-
-	resp, err := http.Get(baseURL + "?" + params.Encode())
-	if err != nil {
-		logger.Error("Failed to search Google Maps: %v", err)
-		return nil, fmt.Errorf("failed to search places: %w", err)
+	params.Set("query", query)
+	if s.searchType != "" {
+		params.Set("type", s.searchType)
 	}
-	defer resp.Body.Close()
+	if search.biased() {
+		params.Set("location", fmt.Sprintf("%f,%f", *search.Latitude, *search.Longitude))
+		params.Set("radius", strconv.Itoa(search.Radius))
+	}
+	// Google ignores the other parameters with a page token, which carries the search
+	if search.PageToken != "" {
+		params.Set("pagetoken", search.PageToken)
+	}
+	params.Set("key", s.apiKey)
 
 	var searchResp PlacesSearchResponse
-	if err := json.NewDecoder(resp.Body).Decode(&searchResp); err != nil {
-		logger.Error("Failed to decode Google Maps response: %v", err)
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if err := s.getJSON(ctx, "text_search", "/maps/api/place/textsearch/json", params, &searchResp); err != nil {
+		mapsLog.Ctx(ctx).Error("Failed to search Google Maps: %v", err)
+		return nil, fmt.Errorf("failed to search places: %w", err)
 	}
 
 	if searchResp.Status != "OK" && searchResp.Status != "ZERO_RESULTS" {
-		logger.Error("Google Maps API error: %s", searchResp.Status)
+		mapsLog.Ctx(ctx).Error("Google Maps API error: %s", searchResp.Status)
 		return nil, fmt.Errorf("Google Maps API error: %s", searchResp.Status)
 	}
 
@@ -103,27 +196,30 @@ func (s *GoogleMapsService) SearchPlaces(query string) ([]models.GooglePlaceResu
 		})
 	}
 
-	logger.Info("✅ Found %d places for query: %s", len(results), query)
-	return results, nil
+	mapsLog.Ctx(ctx).Info("✅ Found %d places for query: %s", len(results), search.Query)
+	return &PlaceSearchResults{Results: results, NextPageToken: searchResp.NextPageToken}, nil
 }
 
-func (s *GoogleMapsService) GeocodeCities(query string) ([]models.GooglePlaceResult, error) {
+// Detail lookups of GeocodeCities run concurrently, bounded in number and in time;
+// predictions whose coordinates are not in by the deadline are left out
+const (
+	geocodeDetailWorkers = 4
+	geocodeDetailTimeout = 3 * time.Second
+)
+
+// GeocodeCities autocompletes city names. With coordinates, the location of each city is
+// looked up too, one place details request per prediction; without, latitude and
+// longitude are 0.
+func (s *GoogleMapsService) GeocodeCities(ctx context.Context, query string, coordinates bool) ([]models.GooglePlaceResult, error) {
 	if s.apiKey == "" {
 		return nil, fmt.Errorf("Google Maps API key not configured")
 	}
 
-	baseURL := "https://maps.googleapis.com/maps/api/place/autocomplete/json"
 	params := url.Values{}
 	params.Set("input", query)
 	params.Set("types", "(cities)")
 	params.Set("key", s.apiKey)
 
-	resp, err := http.Get(baseURL + "?" + params.Encode())
-	if err != nil {
-		return nil, fmt.Errorf("failed to geocode: %w", err)
-	}
-	defer resp.Body.Close()
-
 	var autoResp struct {
 		Predictions []struct {
 			PlaceID              string `json:"place_id"`
@@ -136,90 +232,117 @@ func (s *GoogleMapsService) GeocodeCities(query string) ([]models.GooglePlaceRes
 		Status string `json:"status"`
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(&autoResp); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if err := s.getJSON(ctx, "autocomplete", "/maps/api/place/autocomplete/json", params, &autoResp); err != nil {
+		return nil, fmt.Errorf("failed to geocode: %w", err)
 	}
 
 	if autoResp.Status != "OK" && autoResp.Status != "ZERO_RESULTS" {
 		return nil, fmt.Errorf("Google Maps API error: %s", autoResp.Status)
 	}
 
-	// For each prediction, get the coordinates
-	results := make([]models.GooglePlaceResult, 0, len(autoResp.Predictions))
-	for _, p := range autoResp.Predictions {
-		// Get place details to retrieve coordinates
-		detailsURL := "https://maps.googleapis.com/maps/api/place/details/json"
-		detailParams := url.Values{}
-		detailParams.Set("place_id", p.PlaceID)
-		detailParams.Set("fields", "geometry")
-		detailParams.Set("key", s.apiKey)
-
-		detailResp, err := http.Get(detailsURL + "?" + detailParams.Encode())
-		if err != nil {
-			continue
+	cities := make([]models.GooglePlaceResult, len(autoResp.Predictions))
+	for i, p := range autoResp.Predictions {
+		cities[i] = models.GooglePlaceResult{
+			PlaceID: p.PlaceID,
+			Name:    p.StructuredFormatting.MainText,
+			Address: p.Description,
 		}
+	}
+	if !coordinates {
+		return cities, nil
+	}
 
-		var details struct {
-			Result struct {
-				Geometry struct {
-					Location struct {
-						Lat float64 `json:"lat"`
-						Lng float64 `json:"lng"`
-					} `json:"location"`
-				} `json:"geometry"`
-			} `json:"result"`
-			Status string `json:"status"`
-		}
+	ctx, cancel := context.WithTimeout(ctx, geocodeDetailTimeout)
+	defer cancel()
+
+	found := make([]bool, len(cities))
+	workers := make(chan struct{}, geocodeDetailWorkers)
+	var wg sync.WaitGroup
+	for i := range cities {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			workers <- struct{}{}
+			defer func() { <-workers }()
+			found[i] = s.locateCity(ctx, &cities[i])
+		}()
+	}
+	wg.Wait()
 
-		if err := json.NewDecoder(detailResp.Body).Decode(&details); err != nil {
-			detailResp.Body.Close()
-			continue
-		}
-		detailResp.Body.Close()
-
-		if details.Status == "OK" {
-			results = append(results, models.GooglePlaceResult{
-				PlaceID:   p.PlaceID,
-				Name:      p.StructuredFormatting.MainText,
-				Address:   p.Description,
-				Latitude:  details.Result.Geometry.Location.Lat,
-				Longitude: details.Result.Geometry.Location.Lng,
-			})
+	results := make([]models.GooglePlaceResult, 0, len(cities))
+	for i, city := range cities {
+		if found[i] {
+			results = append(results, city)
 		}
 	}
-
 	return results, nil
 }
 
-func (s *GoogleMapsService) GetPlaceDetails(placeID string) (*models.GooglePlaceResult, error) {
+// locateCity sets the coordinates of a city, reporting whether they were found
+func (s *GoogleMapsService) locateCity(ctx context.Context, city *models.GooglePlaceResult) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	params := url.Values{}
+	params.Set("place_id", city.PlaceID)
+	params.Set("fields", "geometry")
+	params.Set("key", s.apiKey)
+
+	var details struct {
+		Result struct {
+			Geometry struct {
+				Location struct {
+					Lat float64 `json:"lat"`
+					Lng float64 `json:"lng"`
+				} `json:"location"`
+			} `json:"geometry"`
+		} `json:"result"`
+		Status string `json:"status"`
+	}
+	if err := s.getJSON(ctx, "place_details", "/maps/api/place/details/json", params, &details); err != nil {
+		mapsLog.Ctx(ctx).Debug("Failed to locate %s: %v", city.Name, err)
+		return false
+	}
+	if details.Status != "OK" {
+		return false
+	}
+	city.Latitude = details.Result.Geometry.Location.Lat
+	city.Longitude = details.Result.Geometry.Location.Lng
+	return true
+}
+
+// GetPlaceDetails looks up a place, cached for the details TTL of InitPlacesCache
+func (s *GoogleMapsService) GetPlaceDetails(ctx context.Context, placeID string) (*models.GooglePlaceResult, error) {
+	return cachedPlaces(ctx, placesOperationDetails, s.placesAPI+":"+placeID, func() (*models.GooglePlaceResult, error) {
+		return s.getPlaceDetails(ctx, placeID)
+	})
+}
+
+func (s *GoogleMapsService) getPlaceDetails(ctx context.Context, placeID string) (*models.GooglePlaceResult, error) {
 	if s.apiKey == "" {
-		logger.Error("Google Maps API key not configured")
+		mapsLog.Ctx(ctx).Error("Google Maps API key not configured")
 		return nil, fmt.Errorf("Google Maps API key not configured")
 	}
 
-	logger.Debug("📍 Fetching place details for: %s", placeID)
+	mapsLog.Ctx(ctx).Debug("📍 Fetching place details for: %s", placeID)
+	if s.placesAPI == PlacesAPINew {
+		return s.getPlaceDetailsNew(ctx, placeID)
+	}
 
-	baseURL := "https://maps.googleapis.com/maps/api/place/details/json"
 	params := url.Values{}
 	params.Set("place_id", placeID)
-	params.Set("fields", "place_id,name,formatted_address,geometry,formatted_phone_number,international_phone_number,website")
+	params.Set("fields", "place_id,name,formatted_address,geometry,formatted_phone_number,international_phone_number,website,business_status,opening_hours")
 	params.Set("key", s.apiKey)
 
-	resp, err := http.Get(baseURL + "?" + params.Encode())
-	if err != nil {
-		logger.Error("Failed to get place details: %v", err)
-		return nil, fmt.Errorf("failed to get place details: %w", err)
-	}
-	defer resp.Body.Close()
-
 	var detailsResp PlaceDetailsResponse
-	if err := json.NewDecoder(resp.Body).Decode(&detailsResp); err != nil {
-		logger.Error("Failed to decode place details response: %v", err)
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if err := s.getJSON(ctx, "place_details", "/maps/api/place/details/json", params, &detailsResp); err != nil {
+		mapsLog.Ctx(ctx).Error("Failed to get place details: %v", err)
+		return nil, fmt.Errorf("failed to get place details: %w", err)
 	}
 
 	if detailsResp.Status != "OK" {
-		logger.Error("Google Maps API error for place details: %s", detailsResp.Status)
+		mapsLog.Ctx(ctx).Error("Google Maps API error for place details: %s", detailsResp.Status)
 		return nil, fmt.Errorf("Google Maps API error: %s", detailsResp.Status)
 	}
 
@@ -228,15 +351,94 @@ func (s *GoogleMapsService) GetPlaceDetails(placeID string) (*models.GooglePlace
 		phone = detailsResp.Result.FormattedPhoneNumber
 	}
 
-	logger.Info("✅ Retrieved place details: %s", detailsResp.Result.Name)
+	mapsLog.Ctx(ctx).Info("✅ Retrieved place details: %s", detailsResp.Result.Name)
+
+	result := &models.GooglePlaceResult{
+		PlaceID:        detailsResp.Result.PlaceID,
+		Name:           detailsResp.Result.Name,
+		Address:        detailsResp.Result.FormattedAddress,
+		Latitude:       detailsResp.Result.Geometry.Location.Lat,
+		Longitude:      detailsResp.Result.Geometry.Location.Lng,
+		Phone:          phone,
+		Website:        detailsResp.Result.Website,
+		BusinessStatus: detailsResp.Result.BusinessStatus,
+	}
+	if hours := detailsResp.Result.OpeningHours; hours != nil {
+		result.OpeningHours = &models.GoogleOpeningHours{OpenNow: hours.OpenNow, Weekdays: hours.WeekdayText}
+	}
+	return result, nil
+}
+
+// Travel modes of the Distance Matrix API
+const (
+	TravelModeWalking = "walking"
+	TravelModeDriving = "driving"
+)
+
+// maxMatrixDestinations is the number of destinations per Distance Matrix request
+const maxMatrixDestinations = 25
+
+// LatLng is a point on the map
+type LatLng struct {
+	Lat, Lng float64
+}
+
+func (p LatLng) String() string {
+	return strconv.FormatFloat(p.Lat, 'f', 6, 64) + "," + strconv.FormatFloat(p.Lng, 'f', 6, 64)
+}
+
+type distanceMatrixResponse struct {
+	Rows []struct {
+		Elements []struct {
+			Status   string `json:"status"`
+			Duration struct {
+				Value int `json:"value"` // Seconds
+			} `json:"duration"`
+		} `json:"elements"`
+	} `json:"rows"`
+	Status string `json:"status"`
+}
+
+// TravelTimes returns the travel time from origin to each destination by mode, nil for
+// destinations without a route. Every destination is a billed Distance Matrix element.
+func (s *GoogleMapsService) TravelTimes(ctx context.Context, origin LatLng, destinations []LatLng, mode string) ([]*time.Duration, error) {
+	if s.apiKey == "" {
+		return nil, fmt.Errorf("Google Maps API key not configured")
+	}
+
+	times := make([]*time.Duration, len(destinations))
+	for start := 0; start < len(destinations); start += maxMatrixDestinations {
+		batch := destinations[start:min(start+maxMatrixDestinations, len(destinations))]
+		points := make([]string, len(batch))
+		for i, p := range batch {
+			points[i] = p.String()
+		}
+
+		params := url.Values{}
+		params.Set("origins", origin.String())
+		params.Set("destinations", strings.Join(points, "|"))
+		params.Set("mode", mode)
+		params.Set("key", s.apiKey)
+
+		var matrix distanceMatrixResponse
+		if err := s.getJSON(ctx, "distance_matrix", "/maps/api/distancematrix/json", params, &matrix); err != nil {
+			return nil, fmt.Errorf("failed to get travel times: %w", err)
+		}
+		if matrix.Status != "OK" {
+			return nil, fmt.Errorf("Google Maps API error: %s", matrix.Status)
+		}
+		if len(matrix.Rows) != 1 || len(matrix.Rows[0].Elements) != len(batch) {
+			return nil, fmt.Errorf("unexpected distance matrix size")
+		}
+
+		for i, element := range matrix.Rows[0].Elements {
+			if element.Status == "OK" {
+				d := time.Duration(element.Duration.Value) * time.Second
+				times[start+i] = &d
+			}
+		}
+	}
 
-	return &models.GooglePlaceResult{
-		PlaceID:   detailsResp.Result.PlaceID,
-		Name:      detailsResp.Result.Name,
-		Address:   detailsResp.Result.FormattedAddress,
-		Latitude:  detailsResp.Result.Geometry.Location.Lat,
-		Longitude: detailsResp.Result.Geometry.Location.Lng,
-		Phone:     phone,
-		Website:   detailsResp.Result.Website,
-	}, nil
+	mapsLog.Ctx(ctx).Debug("🚶 Got %s travel times to %d destinations", mode, len(destinations))
+	return times, nil
 }
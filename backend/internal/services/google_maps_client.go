@@ -0,0 +1,271 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/nomdb/backend/internal/logger"
+	"github.com/nomdb/backend/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const mapsBaseURL = "https://maps.googleapis.com"
+
+// ErrMapsUnavailable is returned without calling Google while the circuit breaker is
+// open after repeated failures
+var ErrMapsUnavailable = errors.New("Google Maps is temporarily unavailable")
+
+// GoogleMapsOptions configures how the Maps API is called
+type GoogleMapsOptions struct {
+	APIKey           string         // Map features are disabled without one
+	Timeout          time.Duration  // Per attempt
+	MaxRetries       int            // Retries of timeouts, 5xx, 429 and OVER_QUERY_LIMIT
+	RetryDelay       time.Duration  // Before the first retry, doubled for each further one
+	BreakerThreshold int            // Consecutive failed calls opening the breaker, 0 disables it
+	BreakerCooldown  time.Duration  // How long calls fail fast before one is let through again
+	PlacesAPI        string         // PlacesAPILegacy or PlacesAPINew for place searches and details
+	SigningSecret    string         // URL signing secret of the Static Maps API (base64url), optional
+	SearchType       string         // Place type place searches are restricted to, empty for any
+	SearchKeyword    string         // Appended to queries of legacy text searches, empty for none
+	DailyBudget      int            // Calls per UTC day before calls fail with ErrMapsBudgetExceeded, 0 for no limit
+	UsageStore       MapsUsageStore // Shares the count of calls across restarts and replicas, optional
+}
+
+// DefaultGoogleMapsOptions are used until Configure is called
+var DefaultGoogleMapsOptions = GoogleMapsOptions{
+	Timeout:          10 * time.Second,
+	MaxRetries:       2,
+	RetryDelay:       200 * time.Millisecond,
+	BreakerThreshold: 5,
+	BreakerCooldown:  30 * time.Second,
+	PlacesAPI:        PlacesAPILegacy,
+	SearchType:       "restaurant",
+	SearchKeyword:    "restaurant",
+}
+
+// Configure applies opts; call before serving requests
+func (s *GoogleMapsService) Configure(opts GoogleMapsOptions) {
+	s.apiKey = opts.APIKey
+	s.client = &http.Client{Timeout: opts.Timeout}
+	s.maxRetries = opts.MaxRetries
+	s.retryDelay = opts.RetryDelay
+	s.breaker = &circuitBreaker{threshold: opts.BreakerThreshold, cooldown: opts.BreakerCooldown, now: time.Now}
+	s.quota = &mapsQuota{budget: opts.DailyBudget, store: opts.UsageStore, now: time.Now}
+	s.placesAPI = opts.PlacesAPI
+	s.searchType = opts.SearchType
+	s.searchKeyword = opts.SearchKeyword
+	s.signingKey = nil
+	if opts.SigningSecret != "" {
+		key, err := base64.URLEncoding.DecodeString(opts.SigningSecret)
+		if err != nil {
+			mapsLog.Error("Invalid Google Maps signing secret, static map URLs are not signed: %v", err)
+		} else {
+			s.signingKey = key
+		}
+	}
+}
+
+// RetryAfter returns how long the circuit breaker stays open, 0 when it is closed
+func (s *GoogleMapsService) RetryAfter() time.Duration {
+	return s.breaker.retryAfter()
+}
+
+// mapsRequest is a call of a Google Maps Platform API, sent again on retries
+type mapsRequest struct {
+	method string
+	url    string
+	body   []byte
+	header http.Header
+}
+
+// legacyRequest is a GET request of a Maps Web Service path
+func (s *GoogleMapsService) legacyRequest(path string, params url.Values) mapsRequest {
+	return mapsRequest{method: http.MethodGet, url: s.baseURL + path + "?" + params.Encode()}
+}
+
+// do sends a request in a client span named after operation, with the ID of the API
+// request it is made for. The URL is not recorded since it may contain the API key.
+func (s *GoogleMapsService) do(ctx context.Context, operation string, r mapsRequest) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, r.method, r.url, bytes.NewReader(r.body))
+	if err != nil {
+		return nil, err
+	}
+	maps.Copy(req.Header, r.header)
+	if id := logger.RequestID(ctx); id != "" {
+		req.Header.Set(logger.RequestIDHeader, id)
+	}
+
+	ctx, span := tracing.Tracer().Start(ctx, "google_maps."+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("server.address", req.URL.Hostname())))
+	resp, err := s.client.Do(req.WithContext(ctx))
+	if err == nil {
+		span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+	}
+	tracing.EndSpan(span, err)
+	return resp, err
+}
+
+// getJSON calls a Maps Web Service path and decodes the response into v
+func (s *GoogleMapsService) getJSON(ctx context.Context, operation, path string, params url.Values, v any) error {
+	return s.callJSON(ctx, operation, s.legacyRequest(path, params), v)
+}
+
+// callJSON sends a request and decodes the response into v. Transient failures are
+// retried with backoff; calls fail fast with ErrMapsUnavailable while the circuit
+// breaker is open, and with ErrMapsBudgetExceeded once the daily budget is spent. The
+// status in the response body is left to the caller, apart from the retried
+// OVER_QUERY_LIMIT and UNKNOWN_ERROR of the Web Service APIs.
+func (s *GoogleMapsService) callJSON(ctx context.Context, operation string, r mapsRequest, v any) error {
+	return s.call(ctx, operation, r, func(_ http.Header, body []byte) (bool, error) {
+		var status struct {
+			Status string `json:"status"`
+		}
+		if err := json.Unmarshal(body, &status); err != nil {
+			return false, fmt.Errorf("failed to decode response: %w", err)
+		}
+		if status.Status == "OVER_QUERY_LIMIT" || status.Status == "UNKNOWN_ERROR" {
+			return true, fmt.Errorf("Google Maps API error: %s", status.Status)
+		}
+		if err := json.Unmarshal(body, v); err != nil {
+			return false, fmt.Errorf("failed to decode response: %w", err)
+		}
+		return false, nil
+	})
+}
+
+// call sends a request with retries and the circuit breaker, handing the body of a 200
+// response to decode, which reports whether its failure is worth retrying
+func (s *GoogleMapsService) call(ctx context.Context, operation string, r mapsRequest, decode func(http.Header, []byte) (bool, error)) error {
+	if err := s.quota.allow(ctx); err != nil {
+		mapsLog.Ctx(ctx).Warn("⚠️  Google Maps %s skipped: %v", operation, err)
+		return err
+	}
+	if err := s.breaker.allow(); err != nil {
+		return err
+	}
+
+	var err error
+	retryable := false
+	for attempt := 0; ; attempt++ {
+		retryable, err = s.attempt(ctx, operation, r, decode)
+		if err == nil || !retryable || attempt >= s.maxRetries {
+			break
+		}
+		delay := s.retryDelay << attempt
+		mapsLog.Ctx(ctx).Warn("⚠️  Google Maps %s failed: %v; retrying in %s", operation, err, delay)
+		select {
+		case <-ctx.Done():
+			s.breaker.release()
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+
+	if ctx.Err() != nil {
+		// Cancelled requests say nothing about the health of Google
+		s.breaker.release()
+	} else {
+		// Errors Google answered with, such as REQUEST_DENIED, count as success
+		s.breaker.record(err != nil && retryable)
+	}
+	return err
+}
+
+// attempt makes one call of call, reporting whether a failure is worth retrying
+func (s *GoogleMapsService) attempt(ctx context.Context, operation string, r mapsRequest, decode func(http.Header, []byte) (bool, error)) (bool, error) {
+	resp, err := s.do(ctx, operation, r)
+	if err != nil {
+		return ctx.Err() == nil, err
+	}
+	defer resp.Body.Close()
+	// Every response Google sends may be billed, errors and retries included
+	s.quota.record(ctx, operation)
+
+	if resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests {
+		return true, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return ctx.Err() == nil, err
+	}
+	return decode(resp.Header, body)
+}
+
+// circuitBreaker opens after threshold consecutive failed calls. Once the cooldown has
+// passed, one call is let through: success closes the breaker, failure reopens it.
+type circuitBreaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	failures  int
+	openUntil time.Time
+	probing   bool // A call is testing whether Google is back
+	now       func() time.Time
+}
+
+// allow returns ErrMapsUnavailable while the breaker is open
+func (b *circuitBreaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.threshold <= 0 || b.failures < b.threshold {
+		return nil
+	}
+	if b.probing || b.now().Before(b.openUntil) {
+		return ErrMapsUnavailable
+	}
+	b.probing = true
+	return nil
+}
+
+// record counts the outcome of a call that allow let through
+func (b *circuitBreaker) record(failed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+	if b.threshold <= 0 {
+		return
+	}
+	if !failed {
+		if b.failures >= b.threshold {
+			mapsLog.Info("✅ Google Maps circuit breaker closed")
+		}
+		b.failures = 0
+		return
+	}
+	b.failures++
+	if b.failures >= b.threshold {
+		b.openUntil = b.now().Add(b.cooldown)
+		mapsLog.Warn("⚠️  Google Maps circuit breaker open for %s after %d failed calls", b.cooldown, b.failures)
+	}
+}
+
+// release ends a call that allow let through without counting its outcome
+func (b *circuitBreaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+}
+
+func (b *circuitBreaker) retryAfter() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.threshold <= 0 || b.failures < b.threshold {
+		return 0
+	}
+	return max(b.openUntil.Sub(b.now()), 0)
+}
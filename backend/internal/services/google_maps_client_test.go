@@ -0,0 +1,176 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nomdb/backend/internal/logger"
+)
+
+// newTestMapsService returns a service calling a test server that answers with the
+// responses in turn, repeating the last one
+func newTestMapsService(t *testing.T, opts GoogleMapsOptions, responses ...func(w http.ResponseWriter)) (*GoogleMapsService, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(calls.Add(1))
+		responses[min(n, len(responses))-1](w)
+	}))
+	t.Cleanup(server.Close)
+
+	s := &GoogleMapsService{baseURL: server.URL}
+	s.Configure(opts)
+	return s, &calls
+}
+
+func status(code int, body string) func(w http.ResponseWriter) {
+	return func(w http.ResponseWriter) {
+		w.WriteHeader(code)
+		fmt.Fprint(w, body)
+	}
+}
+
+var testMapsOptions = GoogleMapsOptions{
+	APIKey:           "test",
+	Timeout:          time.Second,
+	MaxRetries:       2,
+	RetryDelay:       time.Millisecond,
+	BreakerThreshold: 2,
+	BreakerCooldown:  time.Minute,
+}
+
+func TestGetJSON_Retries(t *testing.T) {
+	tests := []struct {
+		name      string
+		responses []func(w http.ResponseWriter)
+		wantErr   bool
+		wantCalls int32
+	}{
+		{"server error then success", []func(w http.ResponseWriter){
+			status(http.StatusBadGateway, ""), status(http.StatusOK, `{"status":"OK"}`),
+		}, false, 2},
+		{"over query limit then success", []func(w http.ResponseWriter){
+			status(http.StatusOK, `{"status":"OVER_QUERY_LIMIT"}`), status(http.StatusOK, `{"status":"OK"}`),
+		}, false, 2},
+		{"retries exhausted", []func(w http.ResponseWriter){
+			status(http.StatusServiceUnavailable, ""),
+		}, true, 3},
+		{"denied requests are not retried", []func(w http.ResponseWriter){
+			status(http.StatusOK, `{"status":"REQUEST_DENIED"}`),
+		}, false, 1},
+		{"client errors are not retried", []func(w http.ResponseWriter){
+			status(http.StatusBadRequest, ""),
+		}, true, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, calls := newTestMapsService(t, testMapsOptions, tt.responses...)
+			var resp struct {
+				Status string `json:"status"`
+			}
+			err := s.getJSON(context.Background(), "test", "/test", nil, &resp)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Expected error=%v, got %v", tt.wantErr, err)
+			}
+			if calls.Load() != tt.wantCalls {
+				t.Errorf("Expected %d calls, got %d", tt.wantCalls, calls.Load())
+			}
+		})
+	}
+}
+
+func TestGetJSON_Timeout(t *testing.T) {
+	opts := testMapsOptions
+	opts.Timeout = 20 * time.Millisecond
+	opts.MaxRetries = 0
+	s, _ := newTestMapsService(t, opts, func(w http.ResponseWriter) {
+		time.Sleep(200 * time.Millisecond)
+	})
+
+	start := time.Now()
+	if err := s.getJSON(context.Background(), "test", "/test", nil, &struct{}{}); err == nil {
+		t.Fatal("Expected a timeout")
+	}
+	if time.Since(start) > 150*time.Millisecond {
+		t.Errorf("Expected the call to give up after the timeout, took %s", time.Since(start))
+	}
+}
+
+func TestGetJSON_CircuitBreaker(t *testing.T) {
+	opts := testMapsOptions
+	opts.MaxRetries = 0
+	s, calls := newTestMapsService(t, opts,
+		status(http.StatusInternalServerError, ""),
+		status(http.StatusInternalServerError, ""),
+		status(http.StatusOK, `{"status":"OK"}`))
+	now := time.Now()
+	s.breaker.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	for range 2 {
+		s.getJSON(ctx, "test", "/test", nil, &struct{}{})
+	}
+	if err := s.getJSON(ctx, "test", "/test", nil, &struct{}{}); !errors.Is(err, ErrMapsUnavailable) {
+		t.Fatalf("Expected the breaker to be open, got %v", err)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("Expected no call while open, got %d calls", calls.Load())
+	}
+	if s.RetryAfter() != time.Minute {
+		t.Errorf("Expected a retry after the cooldown, got %s", s.RetryAfter())
+	}
+
+	// After the cooldown a call is let through and closes the breaker
+	now = now.Add(time.Minute)
+	if err := s.getJSON(ctx, "test", "/test", nil, &struct{}{}); err != nil {
+		t.Fatalf("Expected the probe to succeed, got %v", err)
+	}
+	if s.RetryAfter() != 0 {
+		t.Error("Expected the breaker to be closed")
+	}
+}
+
+func TestCircuitBreaker_FailedProbe(t *testing.T) {
+	now := time.Now()
+	b := &circuitBreaker{threshold: 1, cooldown: time.Minute, now: func() time.Time { return now }}
+	b.allow()
+	b.record(true)
+
+	now = now.Add(time.Minute)
+	if err := b.allow(); err != nil {
+		t.Fatalf("Expected a probe after the cooldown, got %v", err)
+	}
+	// Only one probe at a time
+	if err := b.allow(); !errors.Is(err, ErrMapsUnavailable) {
+		t.Errorf("Expected other calls to fail fast during the probe, got %v", err)
+	}
+	b.record(true)
+	if err := b.allow(); !errors.Is(err, ErrMapsUnavailable) {
+		t.Errorf("Expected a failed probe to reopen the breaker, got %v", err)
+	}
+}
+
+func TestGetJSON_RequestID(t *testing.T) {
+	var got atomic.Value
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got.Store(r.Header.Get(logger.RequestIDHeader))
+		fmt.Fprint(w, `{"status":"OK"}`)
+	}))
+	defer server.Close()
+	s := &GoogleMapsService{baseURL: server.URL}
+	s.Configure(testMapsOptions)
+
+	ctx := context.WithValue(context.Background(), logger.RequestIDKey, "req-123")
+	if err := s.getJSON(ctx, "test", "/test", nil, &struct{}{}); err != nil {
+		t.Fatalf("getJSON failed: %v", err)
+	}
+	if got.Load() != "req-123" {
+		t.Errorf("Expected the request ID to be sent to Google, got %q", got.Load())
+	}
+}
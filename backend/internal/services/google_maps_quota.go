@@ -0,0 +1,126 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/nomdb/backend/internal/logger"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ErrMapsBudgetExceeded is returned without calling Google once the calls of the day
+// reach the daily budget
+var ErrMapsBudgetExceeded = errors.New("Google Maps daily budget exhausted")
+
+var mapsRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "nomdb",
+	Name:      "google_maps_requests_total",
+	Help:      "Requests sent to Google Maps Platform APIs by operation, including retries.",
+}, []string{"operation"})
+
+// GoogleMapsRequestsCollector returns the Prometheus counter of Google Maps requests
+func GoogleMapsRequestsCollector() prometheus.Collector {
+	return mapsRequests
+}
+
+// MapsUsageStore keeps the count of Google Maps calls per UTC day across restarts and
+// replicas
+type MapsUsageStore interface {
+	// Record counts a call of operation on day, returning the calls of all operations that day
+	Record(ctx context.Context, day time.Time, operation string) (int, error)
+	// Total returns the calls of all operations on day
+	Total(ctx context.Context, day time.Time) (int, error)
+}
+
+// mapsQuota counts the calls of the current UTC day and refuses calls beyond the budget.
+// With a store, the count is that of all replicas as of the last call recorded.
+type mapsQuota struct {
+	mu     sync.Mutex
+	budget int // Calls per day, 0 for no limit
+	store  MapsUsageStore
+	day    time.Time
+	calls  int
+	now    func() time.Time
+}
+
+// utcDay returns the start of the UTC day of t
+func utcDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// rollover starts counting a new day, from the store's count if there is one; the
+// caller holds mu
+func (q *mapsQuota) rollover(ctx context.Context) {
+	today := utcDay(q.now())
+	if today.Equal(q.day) {
+		return
+	}
+	q.day, q.calls = today, 0
+	if q.store == nil {
+		return
+	}
+	calls, err := q.store.Total(ctx, today)
+	if err != nil {
+		logger.Warn("Failed to load Google Maps usage: %v", err)
+		return
+	}
+	q.calls = calls
+}
+
+// allow returns ErrMapsBudgetExceeded once the calls of the day reach the budget
+func (q *mapsQuota) allow(ctx context.Context) error {
+	if q.budget <= 0 {
+		return nil
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.rollover(ctx)
+	if q.calls >= q.budget {
+		return ErrMapsBudgetExceeded
+	}
+	return nil
+}
+
+// record counts a call of operation. Store errors are logged, the call is then only
+// counted by this instance.
+func (q *mapsQuota) record(ctx context.Context, operation string) {
+	mapsRequests.WithLabelValues(operation).Inc()
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	// The call was made, so it counts even if the request was cancelled meanwhile
+	ctx = context.WithoutCancel(ctx)
+	q.rollover(ctx)
+	q.calls++
+	if q.store == nil {
+		return
+	}
+	calls, err := q.store.Record(ctx, q.day, operation)
+	if err != nil {
+		logger.Warn("Failed to record Google Maps usage: %v", err)
+		return
+	}
+	q.calls = calls
+}
+
+// usage returns the budget and the calls of the current day
+func (q *mapsQuota) usage(ctx context.Context) (budget, calls int) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.rollover(ctx)
+	return q.budget, q.calls
+}
+
+// Usage returns the daily budget of Google Maps calls (0 for no limit) and the calls
+// made today (UTC)
+func (s *GoogleMapsService) Usage(ctx context.Context) (budget, calls int) {
+	return s.quota.usage(ctx)
+}
+
+// BudgetResetsAt returns when the calls of the day are reset: the next midnight UTC
+func (s *GoogleMapsService) BudgetResetsAt() time.Time {
+	return utcDay(s.quota.now()).AddDate(0, 0, 1)
+}
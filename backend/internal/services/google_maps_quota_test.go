@@ -0,0 +1,76 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// fakeUsageStore counts calls per day in memory
+type fakeUsageStore struct {
+	calls map[time.Time]int
+}
+
+func (f *fakeUsageStore) Record(_ context.Context, day time.Time, _ string) (int, error) {
+	f.calls[day]++
+	return f.calls[day], nil
+}
+
+func (f *fakeUsageStore) Total(_ context.Context, day time.Time) (int, error) {
+	return f.calls[day], nil
+}
+
+func TestDailyBudget(t *testing.T) {
+	now := time.Date(2026, 10, 15, 23, 0, 0, 0, time.UTC)
+	today := utcDay(now)
+	// Another replica already made a call today
+	store := &fakeUsageStore{calls: map[time.Time]int{today: 1}}
+
+	opts := testMapsOptions
+	opts.DailyBudget = 3
+	opts.UsageStore = store
+	s, calls := newTestMapsService(t, opts, status(http.StatusOK, `{"status":"OK"}`))
+	s.quota.now = func() time.Time { return now }
+
+	var v struct{}
+	for i := 0; i < 2; i++ {
+		if err := s.getJSON(context.Background(), "text_search", "/", nil, &v); err != nil {
+			t.Fatalf("Call %d failed: %v", i, err)
+		}
+	}
+	if err := s.getJSON(context.Background(), "text_search", "/", nil, &v); !errors.Is(err, ErrMapsBudgetExceeded) {
+		t.Errorf("Expected ErrMapsBudgetExceeded, got %v", err)
+	}
+	if calls.Load() != 2 || store.calls[today] != 3 {
+		t.Errorf("Expected 2 calls and 3 recorded, got %d and %d", calls.Load(), store.calls[today])
+	}
+	if budget, used := s.Usage(context.Background()); budget != 3 || used != 3 {
+		t.Errorf("Unexpected usage %d/%d", used, budget)
+	}
+
+	// The budget is reset at midnight UTC
+	if resetsAt := s.BudgetResetsAt(); !resetsAt.Equal(today.AddDate(0, 0, 1)) {
+		t.Errorf("Unexpected reset time %s", resetsAt)
+	}
+	now = now.Add(2 * time.Hour)
+	if err := s.getJSON(context.Background(), "text_search", "/", nil, &v); err != nil {
+		t.Errorf("Expected calls to be allowed the next day, got %v", err)
+	}
+}
+
+func TestDailyBudget_Retries(t *testing.T) {
+	opts := testMapsOptions
+	opts.DailyBudget = 10
+	s, _ := newTestMapsService(t, opts, status(http.StatusBadGateway, ""), status(http.StatusOK, `{"status":"OK"}`))
+
+	var v struct{}
+	if err := s.getJSON(context.Background(), "text_search", "/", nil, &v); err != nil {
+		t.Fatalf("getJSON failed: %v", err)
+	}
+	// Both attempts reached Google
+	if _, used := s.Usage(context.Background()); used != 2 {
+		t.Errorf("Expected 2 calls counted, got %d", used)
+	}
+}
@@ -0,0 +1,144 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSearchPlaces(t *testing.T) {
+	var query url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		fmt.Fprint(w, `{"status": "OK", "results": [{"place_id": "luigi", "name": "Luigi's"}], "next_page_token": "page-2"}`)
+	}))
+	t.Cleanup(server.Close)
+
+	opts := testMapsOptions
+	opts.SearchType, opts.SearchKeyword = "restaurant", "restaurant"
+	s := &GoogleMapsService{baseURL: server.URL}
+	s.Configure(opts)
+
+	lat, lng := 52.5, 13.4
+	page, err := s.searchPlaces(context.Background(), PlaceSearch{Query: "pizza", Latitude: &lat, Longitude: &lng, Radius: 2000})
+	if err != nil {
+		t.Fatalf("searchPlaces failed: %v", err)
+	}
+	if len(page.Results) != 1 || page.Results[0].PlaceID != "luigi" || page.NextPageToken != "page-2" {
+		t.Errorf("Unexpected page %+v", page)
+	}
+	if query.Get("query") != "pizza restaurant" || query.Get("type") != "restaurant" ||
+		query.Get("location") != "52.500000,13.400000" || query.Get("radius") != "2000" {
+		t.Errorf("Unexpected query %v", query)
+	}
+
+	// Without augmentation the query is sent as it is
+	s.Configure(testMapsOptions)
+	if _, err := s.searchPlaces(context.Background(), PlaceSearch{Query: "pizza", PageToken: "page-2"}); err != nil {
+		t.Fatalf("searchPlaces failed: %v", err)
+	}
+	if query.Get("query") != "pizza" || query.Has("type") || query.Has("location") || query.Get("pagetoken") != "page-2" {
+		t.Errorf("Unexpected query %v", query)
+	}
+}
+
+// newTestGeocodeService returns a service whose autocomplete returns the cities
+// Berlin, Bern and Bergen; details of Bern fail and each takes delay
+func newTestGeocodeService(t *testing.T, delay time.Duration) (*GoogleMapsService, *atomic.Int32) {
+	t.Helper()
+	var detailCalls atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/maps/api/place/autocomplete/json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"status": "OK", "predictions": [
+			{"place_id": "berlin", "description": "Berlin, Germany", "structured_formatting": {"main_text": "Berlin"}},
+			{"place_id": "bern", "description": "Bern, Switzerland", "structured_formatting": {"main_text": "Bern"}},
+			{"place_id": "bergen", "description": "Bergen, Norway", "structured_formatting": {"main_text": "Bergen"}}]}`)
+	})
+	mux.HandleFunc("/maps/api/place/details/json", func(w http.ResponseWriter, r *http.Request) {
+		detailCalls.Add(1)
+		time.Sleep(delay)
+		if r.URL.Query().Get("place_id") == "bern" {
+			fmt.Fprint(w, `{"status": "NOT_FOUND"}`)
+			return
+		}
+		fmt.Fprint(w, `{"status": "OK", "result": {"geometry": {"location": {"lat": 52.5, "lng": 13.4}}}}`)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	s := &GoogleMapsService{baseURL: server.URL}
+	s.Configure(testMapsOptions)
+	return s, &detailCalls
+}
+
+func TestGeocodeCities(t *testing.T) {
+	s, detailCalls := newTestGeocodeService(t, 100*time.Millisecond)
+
+	start := time.Now()
+	results, err := s.GeocodeCities(context.Background(), "ber", true)
+	if err != nil {
+		t.Fatalf("GeocodeCities failed: %v", err)
+	}
+	// The lookups run at the same time rather than one after another
+	if elapsed := time.Since(start); elapsed > 250*time.Millisecond {
+		t.Errorf("Expected concurrent detail lookups, took %s", elapsed)
+	}
+	if detailCalls.Load() != 3 {
+		t.Errorf("Expected 3 detail lookups, got %d", detailCalls.Load())
+	}
+	// Cities without coordinates are dropped, the order of the predictions is kept
+	if len(results) != 2 || results[0].Name != "Berlin" || results[1].Name != "Bergen" || results[1].Latitude != 52.5 {
+		t.Errorf("Unexpected results %+v", results)
+	}
+}
+
+func TestGeocodeCities_NamesOnly(t *testing.T) {
+	s, detailCalls := newTestGeocodeService(t, 0)
+
+	results, err := s.GeocodeCities(context.Background(), "ber", false)
+	if err != nil {
+		t.Fatalf("GeocodeCities failed: %v", err)
+	}
+	if len(results) != 3 || results[1].Address != "Bern, Switzerland" || results[1].Latitude != 0 {
+		t.Errorf("Unexpected results %+v", results)
+	}
+	if detailCalls.Load() != 0 {
+		t.Errorf("Expected no detail lookups, got %d", detailCalls.Load())
+	}
+}
+
+func TestCheckAPIKey(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		wantErr  bool
+	}{
+		{"accepted", `{"status": "INVALID_REQUEST", "results": []}`, false},
+		{"rejected", `{"status": "REQUEST_DENIED", "error_message": "The provided API key is invalid."}`, true},
+		{"over limit", `{"status": "OVER_QUERY_LIMIT"}`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var key string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				key = r.URL.Query().Get("key")
+				fmt.Fprint(w, tt.response)
+			}))
+			t.Cleanup(server.Close)
+
+			s := &GoogleMapsService{baseURL: server.URL}
+			s.Configure(testMapsOptions)
+			if err := s.CheckAPIKey(context.Background()); (err != nil) != tt.wantErr {
+				t.Errorf("CheckAPIKey() error = %v, want error %v", err, tt.wantErr)
+			}
+			if key != testMapsOptions.APIKey {
+				t.Errorf("Expected the API key to be sent, got %q", key)
+			}
+		})
+	}
+}
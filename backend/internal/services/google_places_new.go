@@ -0,0 +1,225 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/nomdb/backend/internal/logger"
+	"github.com/nomdb/backend/internal/models"
+)
+
+// Places API versions selectable via GOOGLE_PLACES_API
+const (
+	PlacesAPILegacy = "legacy" // Places Web Service (textsearch/details JSON endpoints)
+	PlacesAPINew    = "new"    // Places API (New) with field masks
+)
+
+const placesNewBaseURL = "https://places.googleapis.com"
+
+// placeFields are requested from the Places API (New); each field mask only bills for
+// the SKUs these fields belong to
+var placeFields = []string{
+	"id", "displayName", "formattedAddress", "location",
+	"rating", "userRatingCount", "priceLevel", "photos", "regularOpeningHours",
+}
+
+// placeDetailsFields adds the contact fields to placeFields
+var placeDetailsFields = append(append([]string{}, placeFields...),
+	"nationalPhoneNumber", "internationalPhoneNumber", "websiteUri", "businessStatus")
+
+// priceLevels maps the price levels of the Places API (New) to the 0-4 scale of the
+// legacy API
+var priceLevels = map[string]int{
+	"PRICE_LEVEL_FREE":           0,
+	"PRICE_LEVEL_INEXPENSIVE":    1,
+	"PRICE_LEVEL_MODERATE":       2,
+	"PRICE_LEVEL_EXPENSIVE":      3,
+	"PRICE_LEVEL_VERY_EXPENSIVE": 4,
+}
+
+// newPlace is a place of the Places API (New)
+type newPlace struct {
+	ID          string `json:"id"`
+	DisplayName struct {
+		Text string `json:"text"`
+	} `json:"displayName"`
+	FormattedAddress string `json:"formattedAddress"`
+	Location         struct {
+		Latitude  float64 `json:"latitude"`
+		Longitude float64 `json:"longitude"`
+	} `json:"location"`
+	Rating                   *float64 `json:"rating"`
+	UserRatingCount          *int     `json:"userRatingCount"`
+	PriceLevel               string   `json:"priceLevel"`
+	NationalPhoneNumber      string   `json:"nationalPhoneNumber"`
+	InternationalPhoneNumber string   `json:"internationalPhoneNumber"`
+	WebsiteURI               string   `json:"websiteUri"`
+	BusinessStatus           string   `json:"businessStatus"`
+	Photos                   []struct {
+		Name               string `json:"name"`
+		WidthPx            int    `json:"widthPx"`
+		HeightPx           int    `json:"heightPx"`
+		AuthorAttributions []struct {
+			DisplayName string `json:"displayName"`
+		} `json:"authorAttributions"`
+	} `json:"photos"`
+	RegularOpeningHours *struct {
+		OpenNow             *bool    `json:"openNow"`
+		WeekdayDescriptions []string `json:"weekdayDescriptions"`
+	} `json:"regularOpeningHours"`
+}
+
+func (p newPlace) result() models.GooglePlaceResult {
+	result := models.GooglePlaceResult{
+		PlaceID:     p.ID,
+		Name:        p.DisplayName.Text,
+		Address:     p.FormattedAddress,
+		Latitude:    p.Location.Latitude,
+		Longitude:   p.Location.Longitude,
+		Phone:       p.InternationalPhoneNumber,
+		Website:     p.WebsiteURI,
+		Rating:      p.Rating,
+		RatingCount: p.UserRatingCount,
+
+		BusinessStatus: p.BusinessStatus,
+	}
+	if result.Phone == "" {
+		result.Phone = p.NationalPhoneNumber
+	}
+	if level, ok := priceLevels[p.PriceLevel]; ok {
+		result.PriceLevel = &level
+	}
+	for _, photo := range p.Photos {
+		converted := models.GooglePlacePhoto{Name: photo.Name, Width: photo.WidthPx, Height: photo.HeightPx}
+		for _, author := range photo.AuthorAttributions {
+			converted.Attributions = append(converted.Attributions, author.DisplayName)
+		}
+		result.Photos = append(result.Photos, converted)
+	}
+	if hours := p.RegularOpeningHours; hours != nil {
+		result.OpeningHours = &models.GoogleOpeningHours{OpenNow: hours.OpenNow, Weekdays: hours.WeekdayDescriptions}
+	}
+	return result
+}
+
+// newPlacesRequest is a request of the Places API (New), authenticated by header so
+// the key stays out of URLs
+func (s *GoogleMapsService) newPlacesRequest(method, path string, body any, fields string) (mapsRequest, error) {
+	r := mapsRequest{
+		method: method,
+		url:    s.placesBaseURL + path,
+		header: http.Header{"X-Goog-Api-Key": {s.apiKey}, "X-Goog-FieldMask": {fields}},
+	}
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return mapsRequest{}, err
+		}
+		r.body = data
+		r.header.Set("Content-Type", "application/json")
+	}
+	return r, nil
+}
+
+func (s *GoogleMapsService) searchPlacesNew(ctx context.Context, search PlaceSearch) (*PlaceSearchResults, error) {
+	fields := make([]string, len(placeFields), len(placeFields)+1)
+	for i, f := range placeFields {
+		fields[i] = "places." + f
+	}
+	fields = append(fields, "nextPageToken")
+
+	// The page token only continues a search sent again with the same parameters
+	body := map[string]any{"textQuery": search.Query}
+	if s.searchType != "" {
+		body["includedType"] = s.searchType
+	}
+	if search.biased() {
+		body["locationBias"] = map[string]any{"circle": map[string]any{
+			"center": map[string]float64{"latitude": *search.Latitude, "longitude": *search.Longitude},
+			"radius": search.Radius,
+		}}
+	}
+	if search.PageToken != "" {
+		body["pageToken"] = search.PageToken
+	}
+	r, err := s.newPlacesRequest(http.MethodPost, "/v1/places:searchText", body, strings.Join(fields, ","))
+	if err != nil {
+		return nil, err
+	}
+
+	var searchResp struct {
+		Places        []newPlace `json:"places"`
+		NextPageToken string     `json:"nextPageToken"`
+	}
+	if err := s.callJSON(ctx, "search_text", r, &searchResp); err != nil {
+		logger.Error("Failed to search Google Places: %v", err)
+		return nil, fmt.Errorf("failed to search places: %w", err)
+	}
+
+	results := make([]models.GooglePlaceResult, 0, len(searchResp.Places))
+	for _, p := range searchResp.Places {
+		results = append(results, p.result())
+	}
+	logger.Info("✅ Found %d places for query: %s", len(results), search.Query)
+	return &PlaceSearchResults{Results: results, NextPageToken: searchResp.NextPageToken}, nil
+}
+
+func (s *GoogleMapsService) getPlaceDetailsNew(ctx context.Context, placeID string) (*models.GooglePlaceResult, error) {
+	r, err := s.newPlacesRequest(http.MethodGet, "/v1/places/"+url.PathEscape(placeID), nil,
+		strings.Join(placeDetailsFields, ","))
+	if err != nil {
+		return nil, err
+	}
+
+	var place newPlace
+	if err := s.callJSON(ctx, "place_details", r, &place); err != nil {
+		logger.Error("Failed to get place details: %v", err)
+		return nil, fmt.Errorf("failed to get place details: %w", err)
+	}
+	result := place.result()
+	logger.Info("✅ Retrieved place details: %s", result.Name)
+	return &result, nil
+}
+
+// placePhotoName matches photo names of the Places API (New)
+var placePhotoName = regexp.MustCompile(`^places/[A-Za-z0-9_-]+/photos/[A-Za-z0-9_-]+$`)
+
+// ValidPlacePhotoName reports whether name is a photo name returned by the Places API
+// (New), so requests made with it cannot reach other endpoints
+func ValidPlacePhotoName(name string) bool {
+	return placePhotoName.MatchString(name)
+}
+
+// PlacePhotoURL returns a short-lived URL of a place photo of the Places API (New),
+// which contains no API key and can be handed to clients
+func (s *GoogleMapsService) PlacePhotoURL(ctx context.Context, name string, maxWidth int) (string, error) {
+	if s.apiKey == "" {
+		return "", fmt.Errorf("Google Maps API key not configured")
+	}
+	if !ValidPlacePhotoName(name) {
+		return "", fmt.Errorf("invalid photo name")
+	}
+
+	params := url.Values{}
+	params.Set("maxWidthPx", strconv.Itoa(maxWidth))
+	params.Set("skipHttpRedirect", "true")
+	r := mapsRequest{
+		method: http.MethodGet,
+		url:    s.placesBaseURL + "/v1/" + name + "/media?" + params.Encode(),
+		header: http.Header{"X-Goog-Api-Key": {s.apiKey}},
+	}
+
+	var media struct {
+		PhotoURI string `json:"photoUri"`
+	}
+	if err := s.callJSON(ctx, "place_photo", r, &media); err != nil {
+		return "", fmt.Errorf("failed to get place photo: %w", err)
+	}
+	return media.PhotoURI, nil
+}
@@ -0,0 +1,163 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const testNewPlace = `{
+	"id": "ChIJ123",
+	"displayName": {"text": "Luigi's"},
+	"formattedAddress": "Main St 1, Berlin",
+	"location": {"latitude": 52.5, "longitude": 13.4},
+	"rating": 4.6,
+	"userRatingCount": 120,
+	"priceLevel": "PRICE_LEVEL_MODERATE",
+	"internationalPhoneNumber": "+49 30 123",
+	"websiteUri": "https://luigis.example",
+	"photos": [{"name": "places/ChIJ123/photos/AbC-1", "widthPx": 800, "heightPx": 600,
+		"authorAttributions": [{"displayName": "Anna"}]}],
+	"regularOpeningHours": {"openNow": true, "weekdayDescriptions": ["Monday: 11:00 AM – 10:00 PM"]}
+}`
+
+// newTestPlacesService returns a service using the Places API (New) of a test server,
+// which records the requests it receives
+func newTestPlacesService(t *testing.T, handler http.HandlerFunc) (*GoogleMapsService, *[]*http.Request) {
+	t.Helper()
+	var requests []*http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r)
+		handler(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	opts := testMapsOptions
+	opts.PlacesAPI = PlacesAPINew
+	opts.SearchType = "restaurant"
+	opts.APIKey = "test-key"
+	s := &GoogleMapsService{placesBaseURL: server.URL}
+	s.Configure(opts)
+	return s, &requests
+}
+
+func TestSearchPlacesNew(t *testing.T) {
+	var body map[string]any
+	s, requests := newTestPlacesService(t, func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&body)
+		fmt.Fprintf(w, `{"places": [%s], "nextPageToken": "page-2"}`, testNewPlace)
+	})
+
+	lat, lng := 52.5, 13.4
+	page, err := s.searchPlaces(context.Background(), PlaceSearch{Query: "pizza berlin", Latitude: &lat, Longitude: &lng, Radius: 2000, PageToken: "page-1"})
+	if err != nil {
+		t.Fatalf("searchPlaces failed: %v", err)
+	}
+	results := page.Results
+	if page.NextPageToken != "page-2" {
+		t.Errorf("Expected the next page token, got %q", page.NextPageToken)
+	}
+
+	r := (*requests)[0]
+	if r.Method != http.MethodPost || r.URL.Path != "/v1/places:searchText" {
+		t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+	}
+	if r.Header.Get("X-Goog-Api-Key") != "test-key" || r.URL.Query().Get("key") != "" {
+		t.Error("Expected the API key in the header only")
+	}
+	if mask := r.Header.Get("X-Goog-FieldMask"); !strings.HasPrefix(mask, "places.id,places.displayName") || strings.Contains(mask, "websiteUri") {
+		t.Errorf("Unexpected field mask %q", mask)
+	}
+	if !strings.HasSuffix(r.Header.Get("X-Goog-FieldMask"), ",nextPageToken") {
+		t.Errorf("Expected the next page token in the field mask, got %q", r.Header.Get("X-Goog-FieldMask"))
+	}
+	if body["textQuery"] != "pizza berlin" || body["includedType"] != "restaurant" || body["pageToken"] != "page-1" {
+		t.Errorf("Unexpected body %v", body)
+	}
+	circle := body["locationBias"].(map[string]any)["circle"].(map[string]any)
+	if circle["radius"] != 2000.0 || circle["center"].(map[string]any)["latitude"] != 52.5 {
+		t.Errorf("Unexpected location bias %v", circle)
+	}
+
+	if len(results) != 1 {
+		t.Fatalf("Expected one result, got %+v", results)
+	}
+	p := results[0]
+	if p.PlaceID != "ChIJ123" || p.Name != "Luigi's" || p.Latitude != 52.5 || p.Phone != "+49 30 123" {
+		t.Errorf("Unexpected result %+v", p)
+	}
+	if p.Rating == nil || *p.Rating != 4.6 || p.RatingCount == nil || *p.RatingCount != 120 {
+		t.Errorf("Expected the rating, got %v (%v)", p.Rating, p.RatingCount)
+	}
+	if p.PriceLevel == nil || *p.PriceLevel != 2 {
+		t.Errorf("Expected price level 2, got %v", p.PriceLevel)
+	}
+	if len(p.Photos) != 1 || p.Photos[0].Name != "places/ChIJ123/photos/AbC-1" || p.Photos[0].Attributions[0] != "Anna" {
+		t.Errorf("Unexpected photos %+v", p.Photos)
+	}
+	if p.OpeningHours == nil || !*p.OpeningHours.OpenNow || len(p.OpeningHours.Weekdays) != 1 {
+		t.Errorf("Unexpected opening hours %+v", p.OpeningHours)
+	}
+}
+
+func TestGetPlaceDetailsNew(t *testing.T) {
+	s, requests := newTestPlacesService(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, testNewPlace)
+	})
+
+	result, err := s.getPlaceDetails(context.Background(), "ChIJ123")
+	if err != nil {
+		t.Fatalf("getPlaceDetails failed: %v", err)
+	}
+	r := (*requests)[0]
+	if r.Method != http.MethodGet || r.URL.Path != "/v1/places/ChIJ123" {
+		t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+	}
+	if !strings.Contains(r.Header.Get("X-Goog-FieldMask"), "websiteUri") {
+		t.Errorf("Expected contact fields in the mask, got %q", r.Header.Get("X-Goog-FieldMask"))
+	}
+	if result.Website != "https://luigis.example" {
+		t.Errorf("Unexpected result %+v", result)
+	}
+}
+
+func TestPlacePhotoURL(t *testing.T) {
+	s, requests := newTestPlacesService(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"name": "places/ChIJ123/photos/AbC-1/media", "photoUri": "https://lh3.example/photo"}`)
+	})
+
+	photoURL, err := s.PlacePhotoURL(context.Background(), "places/ChIJ123/photos/AbC-1", 400)
+	if err != nil || photoURL != "https://lh3.example/photo" {
+		t.Fatalf("Unexpected photo URL %q (%v)", photoURL, err)
+	}
+	r := (*requests)[0]
+	if r.URL.Path != "/v1/places/ChIJ123/photos/AbC-1/media" || r.URL.Query().Get("maxWidthPx") != "400" ||
+		r.URL.Query().Get("skipHttpRedirect") != "true" {
+		t.Errorf("Unexpected request %s", r.URL)
+	}
+
+	if _, err := s.PlacePhotoURL(context.Background(), "places/x/photos/../../other", 400); err == nil {
+		t.Error("Expected invalid photo names to be rejected")
+	}
+	if len(*requests) != 1 {
+		t.Error("Expected no request for an invalid photo name")
+	}
+}
+
+func TestValidPlacePhotoName(t *testing.T) {
+	for name, valid := range map[string]bool{
+		"places/ChIJ123/photos/AbC-1_x": true,
+		"places/ChIJ123/photos/":        false,
+		"places/ChIJ123/reviews/abc":    false,
+		"places/a/photos/b/media":       false,
+		"places/a?b/photos/c":           false,
+	} {
+		if ValidPlacePhotoName(name) != valid {
+			t.Errorf("%s: expected valid=%v", name, valid)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSignMapsURL(t *testing.T) {
+	// Example of the Google Maps Platform URL signing documentation
+	key, _ := base64.URLEncoding.DecodeString("vNIXE0xscrmjlyV-12Nj_BvUPaw=")
+	if got := signMapsURL("/maps/api/geocode/json?address=New+York&client=clientID", key); got != "chaRF2hTJKOScPr-RQCEhZbSzIE=" {
+		t.Errorf("Unexpected signature %s", got)
+	}
+}
+
+func TestStaticMap(t *testing.T) {
+	var request *http.Request
+	contentType := "image/png"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request = r
+		w.Header().Set("Content-Type", contentType)
+		w.Write([]byte("png"))
+	}))
+	t.Cleanup(server.Close)
+
+	opts := testMapsOptions
+	opts.SigningSecret = "vNIXE0xscrmjlyV-12Nj_BvUPaw="
+	s := &GoogleMapsService{baseURL: server.URL}
+	s.Configure(opts)
+
+	image, gotType, err := s.StaticMap(context.Background(), StaticMapOptions{Location: "52.5,13.4", Width: 400, Height: 300, Zoom: 15, Scale: 2})
+	if err != nil || string(image) != "png" || gotType != "image/png" {
+		t.Fatalf("Unexpected result %q %q (%v)", image, gotType, err)
+	}
+
+	q := request.URL.Query()
+	if request.URL.Path != "/maps/api/staticmap" || q.Get("center") != "52.5,13.4" || q.Get("size") != "400x300" ||
+		q.Get("scale") != "2" || q.Get("markers") != "color:red|52.5,13.4" {
+		t.Errorf("Unexpected request %s", request.URL)
+	}
+	unsigned := request.URL.Path + "?" + request.URL.RawQuery[:len(request.URL.RawQuery)-len("&signature=")-len(q.Get("signature"))]
+	if q.Get("signature") != signMapsURL(unsigned, s.signingKey) {
+		t.Errorf("Expected the URL to be signed, got %s", request.URL)
+	}
+
+	// Error pages of the Static Maps API are not images
+	contentType = "text/plain"
+	if _, _, err := s.StaticMap(context.Background(), StaticMapOptions{Location: "52.5,13.4", Width: 1, Height: 1, Scale: 1}); err == nil {
+		t.Error("Expected an error for a non-image response")
+	}
+}
@@ -9,11 +9,18 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 
+	"github.com/gen2brain/avif"
+	"github.com/gen2brain/webp"
 	"github.com/nomdb/backend/internal/logger"
 	"golang.org/x/image/draw"
 )
 
+// photoLog logs image processing as part of the photo pipeline
+var photoLog = logger.Module("photos")
+
 const (
 	// MaxImageWidth is the maximum width for full-size images
 	MaxImageWidth = 1920
@@ -23,6 +30,12 @@ const (
 	ThumbnailSize = 200
 	// JPEGQuality is the quality setting for JPEG compression
 	JPEGQuality = 85
+	// WebPQuality is the quality setting for lossy WebP compression
+	WebPQuality = 80
+	// AVIFQuality is the quality setting for AVIF compression
+	AVIFQuality = 60
+	// AVIFSpeed trades encoding time for size (0=slowest, 10=fastest)
+	AVIFSpeed = 8
 )
 
 // ImageProcessor handles image processing operations
@@ -41,7 +54,12 @@ func (ip *ImageProcessor) ProcessUpload(file io.Reader, filename string) (fullIm
 		return nil, nil, fmt.Errorf("failed to decode image: %w", err)
 	}
 
-	logger.Debug("Processing image: format=%s, size=%dx%d", format, img.Bounds().Dx(), img.Bounds().Dy())
+	photoLog.Debug("Processing image: format=%s, size=%dx%d", format, img.Bounds().Dx(), img.Bounds().Dy())
+
+	// Uploads are stored as JPEG or PNG, other formats are only produced for variants
+	if format != "png" {
+		format = "jpeg"
+	}
 
 	// Resize full image if needed
 	resizedImg := ip.resizeImage(img, MaxImageWidth, MaxImageHeight)
@@ -59,11 +77,83 @@ func (ip *ImageProcessor) ProcessUpload(file io.Reader, filename string) (fullIm
 		return nil, nil, fmt.Errorf("failed to compress thumbnail: %w", err)
 	}
 
-	logger.Debug("Image processed: full=%d bytes, thumbnail=%d bytes", len(fullImage), len(thumbnail))
+	photoLog.Debug("Image processed: full=%d bytes, thumbnail=%d bytes", len(fullImage), len(thumbnail))
 
 	return fullImage, thumbnail, nil
 }
 
+// ResizeVariant decodes an image and re-encodes it to fit within width x height.
+// A zero width or height leaves that dimension bounded only by the full-size limits.
+// Images are never upscaled. Returns the encoded bytes and their content type.
+func (ip *ImageProcessor) ResizeVariant(file io.Reader, width, height int, format string) ([]byte, string, error) {
+	img, _, err := image.Decode(file)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	if width <= 0 || width > MaxImageWidth {
+		width = MaxImageWidth
+	}
+	if height <= 0 || height > MaxImageHeight {
+		height = MaxImageHeight
+	}
+
+	resized := ip.resizeImage(img, width, height)
+
+	data, err := ip.compressImage(resized, format)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to compress image: %w", err)
+	}
+
+	return data, VariantContentType(format), nil
+}
+
+// VariantContentType returns the content type produced by compressImage for a format
+func VariantContentType(format string) string {
+	switch format {
+	case "png":
+		return "image/png"
+	case "webp":
+		return "image/webp"
+	case "avif":
+		return "image/avif"
+	default:
+		return "image/jpeg"
+	}
+}
+
+// NegotiateImageFormat picks the smallest output format the client accepts based on its
+// Accept header. AVIF is only considered when allowAVIF is set since it is slow to encode.
+func NegotiateImageFormat(accept string, allowAVIF bool) string {
+	accepted := map[string]bool{}
+	for _, part := range strings.Split(accept, ",") {
+		params := strings.Split(part, ";")
+		mediaType := strings.ToLower(strings.TrimSpace(params[0]))
+
+		// An explicit q=0 means the type is not acceptable
+		rejected := false
+		for _, param := range params[1:] {
+			if q, ok := strings.CutPrefix(strings.TrimSpace(param), "q="); ok {
+				if weight, err := strconv.ParseFloat(q, 64); err == nil && weight == 0 {
+					rejected = true
+				}
+			}
+		}
+		if !rejected {
+			accepted[mediaType] = true
+		}
+	}
+
+	switch {
+	case allowAVIF && accepted["image/avif"]:
+		return "avif"
+	case accepted["image/webp"]:
+		return "webp"
+	default:
+		return "jpeg"
+	}
+}
+
 // resizeImage resizes an image to fit within maxWidth and maxHeight while maintaining aspect ratio
 func (ip *ImageProcessor) resizeImage(img image.Image, maxWidth, maxHeight int) image.Image {
 	bounds := img.Bounds()
@@ -85,7 +175,7 @@ func (ip *ImageProcessor) resizeImage(img image.Image, maxWidth, maxHeight int)
 		newWidth = int(float64(newHeight) * ratio)
 	}
 
-	logger.Debug("Resizing image from %dx%d to %dx%d", width, height, newWidth, newHeight)
+	photoLog.Debug("Resizing image from %dx%d to %dx%d", width, height, newWidth, newHeight)
 
 	// Create new image
 	dst := image.NewRGBA(image.Rect(0, 0, newWidth, newHeight))
@@ -96,7 +186,7 @@ func (ip *ImageProcessor) resizeImage(img image.Image, maxWidth, maxHeight int)
 	return dst
 }
 
-// compressImage compresses an image to JPEG or PNG format
+// compressImage compresses an image to JPEG, PNG, WebP or AVIF format
 func (ip *ImageProcessor) compressImage(img image.Image, format string) ([]byte, error) {
 	var buf bytes.Buffer
 
@@ -113,6 +203,14 @@ func (ip *ImageProcessor) compressImage(img image.Image, format string) ([]byte,
 		if err != nil {
 			return nil, err
 		}
+	case "webp":
+		if err := webp.Encode(&buf, img, webp.Options{Quality: WebPQuality, Method: webp.DefaultMethod}); err != nil {
+			return nil, err
+		}
+	case "avif":
+		if err := avif.Encode(&buf, img, avif.Options{Quality: AVIFQuality, QualityAlpha: AVIFQuality, Speed: AVIFSpeed}); err != nil {
+			return nil, err
+		}
 	default:
 		// Default to JPEG for unknown formats
 		err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: JPEGQuality})
@@ -174,6 +174,103 @@ func TestImageProcessor_CompressImage(t *testing.T) {
 	}
 }
 
+func TestImageProcessor_ResizeVariant(t *testing.T) {
+	processor := NewImageProcessor()
+
+	tests := []struct {
+		name         string
+		width        int
+		height       int
+		format       string
+		expectWidth  int
+		expectHeight int
+		expectType   string
+	}{
+		{
+			name:         "Width only keeps aspect ratio",
+			width:        400,
+			format:       "jpeg",
+			expectWidth:  400,
+			expectHeight: 200,
+			expectType:   "image/jpeg",
+		},
+		{
+			name:         "Height only keeps aspect ratio",
+			height:       100,
+			format:       "png",
+			expectWidth:  200,
+			expectHeight: 100,
+			expectType:   "image/png",
+		},
+		{
+			name:         "WebP output",
+			width:        600,
+			format:       "webp",
+			expectWidth:  600,
+			expectHeight: 300,
+			expectType:   "image/webp",
+		},
+		{
+			name:         "Bounds larger than source do not upscale",
+			width:        1600,
+			height:       1600,
+			format:       "jpeg",
+			expectWidth:  1200,
+			expectHeight: 600,
+			expectType:   "image/jpeg",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			if err := jpeg.Encode(buf, createTestImage(1200, 600), &jpeg.Options{Quality: 90}); err != nil {
+				t.Fatalf("Failed to encode test image: %v", err)
+			}
+
+			data, contentType, err := processor.ResizeVariant(buf, tt.width, tt.height, tt.format)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if contentType != tt.expectType {
+				t.Errorf("Expected content type %s, got %s", tt.expectType, contentType)
+			}
+
+			img, _, err := image.Decode(bytes.NewReader(data))
+			if err != nil {
+				t.Fatalf("Variant is not a valid image: %v", err)
+			}
+			if img.Bounds().Dx() != tt.expectWidth || img.Bounds().Dy() != tt.expectHeight {
+				t.Errorf("Expected %dx%d, got %dx%d", tt.expectWidth, tt.expectHeight, img.Bounds().Dx(), img.Bounds().Dy())
+			}
+		})
+	}
+}
+
+func TestNegotiateImageFormat(t *testing.T) {
+	tests := []struct {
+		name      string
+		accept    string
+		allowAVIF bool
+		expected  string
+	}{
+		{"Empty accept header", "", false, "jpeg"},
+		{"Legacy browser", "image/png,image/*;q=0.8,*/*;q=0.5", false, "jpeg"},
+		{"WebP capable browser", "image/webp,image/apng,image/*,*/*;q=0.8", false, "webp"},
+		{"AVIF ignored when disabled", "image/avif,image/webp,*/*", false, "webp"},
+		{"AVIF preferred when enabled", "image/avif,image/webp,*/*", true, "avif"},
+		{"Explicitly rejected WebP", "image/webp;q=0,image/jpeg", false, "jpeg"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NegotiateImageFormat(tt.accept, tt.allowAVIF); got != tt.expected {
+				t.Errorf("NegotiateImageFormat(%q) = %s, want %s", tt.accept, got, tt.expected)
+			}
+		})
+	}
+}
+
 // Helper function to create a test image
 func createTestImage(width, height int) image.Image {
 	img := image.NewRGBA(image.Rect(0, 0, width, height))
@@ -0,0 +1,173 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/rekognition"
+	"github.com/aws/aws-sdk-go-v2/service/rekognition/types"
+	"github.com/nomdb/backend/internal/logger"
+)
+
+// Moderation providers selectable via MODERATION_PROVIDER
+const (
+	ModerationProviderNone        = "none"
+	ModerationProviderHTTP        = "http"
+	ModerationProviderRekognition = "rekognition"
+)
+
+// Rekognition only accepts images up to 5MB as raw bytes
+const rekognitionMaxImageSize = 5 << 20
+
+// ModerationResult is the verdict of a moderation provider for one image
+type ModerationResult struct {
+	Flagged bool     `json:"flagged"`
+	Labels  []string `json:"labels"`
+}
+
+// ModerationProvider checks images for inappropriate content
+type ModerationProvider interface {
+	Name() string
+	Moderate(ctx context.Context, image []byte, contentType string) (*ModerationResult, error)
+}
+
+// ModerationConfig selects and configures a moderation provider
+type ModerationConfig struct {
+	Provider      string
+	Endpoint      string  // http: URL receiving the raw image
+	APIKey        string  // http: sent as a bearer token if set
+	MinConfidence float64 // rekognition: 0-100
+
+	AWSAccessKeyID     string
+	AWSSecretAccessKey string
+	AWSRegion          string
+}
+
+// NewModerationProvider creates the configured provider, or nil if moderation is disabled
+func NewModerationProvider(cfg ModerationConfig) (ModerationProvider, error) {
+	switch cfg.Provider {
+	case "", ModerationProviderNone:
+		return nil, nil
+	case ModerationProviderHTTP:
+		if cfg.Endpoint == "" {
+			return nil, fmt.Errorf("MODERATION_ENDPOINT is required for the http moderation provider")
+		}
+		logger.Info("🛡️  Image moderation via %s", cfg.Endpoint)
+		return &HTTPModerationProvider{
+			endpoint: cfg.Endpoint,
+			apiKey:   cfg.APIKey,
+			client:   &http.Client{Timeout: 30 * time.Second},
+		}, nil
+	case ModerationProviderRekognition:
+		provider, err := NewRekognitionModerationProvider(cfg)
+		if err != nil {
+			return nil, err
+		}
+		return provider, nil
+	default:
+		return nil, fmt.Errorf("unknown moderation provider %q", cfg.Provider)
+	}
+}
+
+// HTTPModerationProvider posts the image to a self-hosted classifier, which must answer
+// with {"flagged": bool, "labels": [string]}
+type HTTPModerationProvider struct {
+	endpoint string
+	apiKey   string
+	client   *http.Client
+}
+
+func (p *HTTPModerationProvider) Name() string {
+	return ModerationProviderHTTP
+}
+
+func (p *HTTPModerationProvider) Moderate(ctx context.Context, image []byte, contentType string) (*ModerationResult, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.endpoint, bytes.NewReader(image))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", contentType)
+	if p.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+p.apiKey)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("moderation request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("moderation endpoint returned %d: %s", resp.StatusCode, bytes.TrimSpace(body))
+	}
+
+	var result ModerationResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("invalid moderation response: %w", err)
+	}
+	return &result, nil
+}
+
+// RekognitionModerationProvider uses AWS Rekognition DetectModerationLabels
+type RekognitionModerationProvider struct {
+	client        *rekognition.Client
+	minConfidence float64
+}
+
+func NewRekognitionModerationProvider(cfg ModerationConfig) (*RekognitionModerationProvider, error) {
+	if cfg.AWSAccessKeyID == "" || cfg.AWSSecretAccessKey == "" || cfg.AWSRegion == "" {
+		return nil, fmt.Errorf("AWS credentials and region are required for Rekognition moderation")
+	}
+
+	awsCfg, err := config.LoadDefaultConfig(context.TODO(),
+		config.WithRegion(cfg.AWSRegion),
+		config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(
+			cfg.AWSAccessKeyID,
+			cfg.AWSSecretAccessKey,
+			"",
+		)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+
+	logger.Info("🛡️  Image moderation via AWS Rekognition (min confidence: %.0f)", cfg.MinConfidence)
+	return &RekognitionModerationProvider{
+		client:        rekognition.NewFromConfig(awsCfg),
+		minConfidence: cfg.MinConfidence,
+	}, nil
+}
+
+func (p *RekognitionModerationProvider) Name() string {
+	return ModerationProviderRekognition
+}
+
+func (p *RekognitionModerationProvider) Moderate(ctx context.Context, image []byte, contentType string) (*ModerationResult, error) {
+	if len(image) > rekognitionMaxImageSize {
+		return nil, fmt.Errorf("image too large for Rekognition (%d bytes)", len(image))
+	}
+
+	out, err := p.client.DetectModerationLabels(ctx, &rekognition.DetectModerationLabelsInput{
+		Image:         &types.Image{Bytes: image},
+		MinConfidence: aws.Float32(float32(p.minConfidence)),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("rekognition moderation failed: %w", err)
+	}
+
+	result := &ModerationResult{Labels: []string{}}
+	for _, label := range out.ModerationLabels {
+		result.Labels = append(result.Labels, aws.ToString(label.Name))
+	}
+	result.Flagged = len(result.Labels) > 0
+	return result, nil
+}
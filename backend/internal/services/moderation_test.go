@@ -0,0 +1,102 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHTTPModerationProvider(t *testing.T) {
+	tests := []struct {
+		name          string
+		status        int
+		body          string
+		expectFlagged bool
+		expectLabels  int
+		expectedError bool
+	}{
+		{
+			name:   "Clean image",
+			status: http.StatusOK,
+			body:   `{"flagged": false, "labels": []}`,
+		},
+		{
+			name:          "Flagged image",
+			status:        http.StatusOK,
+			body:          `{"flagged": true, "labels": ["Explicit Nudity", "Violence"]}`,
+			expectFlagged: true,
+			expectLabels:  2,
+		},
+		{
+			name:          "Provider error",
+			status:        http.StatusInternalServerError,
+			body:          "model unavailable",
+			expectedError: true,
+		},
+		{
+			name:          "Invalid response",
+			status:        http.StatusOK,
+			body:          "not json",
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("Content-Type") != "image/jpeg" {
+					t.Errorf("Expected image/jpeg content type, got %s", r.Header.Get("Content-Type"))
+				}
+				if r.Header.Get("Authorization") != "Bearer secret" {
+					t.Errorf("Expected bearer token, got %q", r.Header.Get("Authorization"))
+				}
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			provider, err := NewModerationProvider(ModerationConfig{
+				Provider: ModerationProviderHTTP,
+				Endpoint: server.URL,
+				APIKey:   "secret",
+			})
+			if err != nil {
+				t.Fatalf("NewModerationProvider failed: %v", err)
+			}
+
+			result, err := provider.Moderate(context.Background(), []byte("image"), "image/jpeg")
+			if tt.expectedError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if result.Flagged != tt.expectFlagged {
+				t.Errorf("Expected flagged=%v, got %v", tt.expectFlagged, result.Flagged)
+			}
+			if len(result.Labels) != tt.expectLabels {
+				t.Errorf("Expected %d labels, got %v", tt.expectLabels, result.Labels)
+			}
+		})
+	}
+}
+
+func TestNewModerationProvider_Disabled(t *testing.T) {
+	for _, name := range []string{"", ModerationProviderNone} {
+		provider, err := NewModerationProvider(ModerationConfig{Provider: name})
+		if err != nil || provider != nil {
+			t.Errorf("Expected no provider for %q, got %v, %v", name, provider, err)
+		}
+	}
+
+	if _, err := NewModerationProvider(ModerationConfig{Provider: "unknown"}); err == nil {
+		t.Error("Expected error for unknown provider")
+	}
+	if _, err := NewModerationProvider(ModerationConfig{Provider: ModerationProviderHTTP}); err == nil {
+		t.Error("Expected error for http provider without endpoint")
+	}
+}
@@ -0,0 +1,266 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nomdb/backend/internal/logger"
+	"github.com/nomdb/backend/internal/models"
+	"github.com/nomdb/backend/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ErrPlaceNotFound is returned for place IDs the provider does not know
+var ErrPlaceNotFound = errors.New("place not found")
+
+// NominatimOptions configures the OpenStreetMap Nominatim provider
+type NominatimOptions struct {
+	BaseURL     string        // e.g. https://nominatim.openstreetmap.org or a self-hosted instance
+	Email       string        // Contact sent with each request, as the usage policy of the public instance asks
+	MinInterval time.Duration // Between requests; the public instance allows one per second
+	Timeout     time.Duration
+}
+
+// NominatimProvider searches places in OpenStreetMap data through Nominatim. Place IDs
+// are OSM IDs prefixed with the element type: N (node), W (way) or R (relation).
+type NominatimProvider struct {
+	baseURL     string
+	email       string
+	minInterval time.Duration
+	client      *http.Client
+
+	mu   sync.Mutex
+	next time.Time // Earliest start of the next request
+}
+
+// NewNominatimProvider creates a provider for the Nominatim instance of opts
+func NewNominatimProvider(opts NominatimOptions) *NominatimProvider {
+	return &NominatimProvider{
+		baseURL:     strings.TrimSuffix(opts.BaseURL, "/"),
+		email:       opts.Email,
+		minInterval: opts.MinInterval,
+		client:      &http.Client{Timeout: opts.Timeout},
+	}
+}
+
+func (p *NominatimProvider) Name() string { return PlacesProviderNominatim }
+
+// Enabled is always true; Nominatim needs no API key
+func (p *NominatimProvider) Enabled() bool { return true }
+
+// nominatimFoodAmenities are the OSM amenity types returned by place searches
+var nominatimFoodAmenities = map[string]bool{
+	"restaurant": true, "cafe": true, "fast_food": true, "bar": true, "pub": true,
+	"biergarten": true, "food_court": true, "ice_cream": true,
+}
+
+// nominatimPlace is a result of the jsonv2 format
+type nominatimPlace struct {
+	OSMType     string            `json:"osm_type"`
+	OSMID       int64             `json:"osm_id"`
+	Lat         string            `json:"lat"`
+	Lon         string            `json:"lon"`
+	Category    string            `json:"category"`
+	Type        string            `json:"type"`
+	Name        string            `json:"name"`
+	DisplayName string            `json:"display_name"`
+	ExtraTags   map[string]string `json:"extratags"`
+}
+
+func (n nominatimPlace) result() models.GooglePlaceResult {
+	result := models.GooglePlaceResult{
+		PlaceID: strings.ToUpper(n.OSMType[:min(len(n.OSMType), 1)]) + strconv.FormatInt(n.OSMID, 10),
+		Name:    n.Name,
+		Address: n.DisplayName,
+		Phone:   n.ExtraTags["phone"],
+		Website: n.ExtraTags["website"],
+	}
+	result.Latitude, _ = strconv.ParseFloat(n.Lat, 64)
+	result.Longitude, _ = strconv.ParseFloat(n.Lon, 64)
+	if result.Name == "" {
+		result.Name, _, _ = strings.Cut(n.DisplayName, ",")
+	}
+	if result.Phone == "" {
+		result.Phone = n.ExtraTags["contact:phone"]
+	}
+	if result.Website == "" {
+		result.Website = n.ExtraTags["contact:website"]
+	}
+	return result
+}
+
+// wait spaces requests by the minimum interval
+func (p *NominatimProvider) wait(ctx context.Context) error {
+	p.mu.Lock()
+	start := time.Now()
+	if p.next.After(start) {
+		start = p.next
+	}
+	p.next = start.Add(p.minInterval)
+	p.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(time.Until(start)):
+		return nil
+	}
+}
+
+// get calls a Nominatim endpoint in a client span named after operation and decodes
+// the response into v
+func (p *NominatimProvider) get(ctx context.Context, operation, path string, params url.Values, v any) error {
+	if err := p.wait(ctx); err != nil {
+		return err
+	}
+	if p.email != "" {
+		params.Set("email", p.email)
+	}
+
+	ctx, span := tracing.Tracer().Start(ctx, "nominatim."+operation, trace.WithSpanKind(trace.SpanKindClient))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.baseURL+path+"?"+params.Encode(), nil)
+	if err != nil {
+		tracing.EndSpan(span, err)
+		return err
+	}
+	req.Header.Set("User-Agent", "nomdb (https://github.com/nomdb)")
+	span.SetAttributes(attribute.String("server.address", req.URL.Hostname()))
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		tracing.EndSpan(span, err)
+		return err
+	}
+	defer resp.Body.Close()
+	span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+	if resp.StatusCode != http.StatusOK {
+		err = fmt.Errorf("unexpected status %d", resp.StatusCode)
+	} else if decodeErr := json.NewDecoder(resp.Body).Decode(v); decodeErr != nil {
+		err = fmt.Errorf("failed to decode response: %w", decodeErr)
+	}
+	tracing.EndSpan(span, err)
+	return err
+}
+
+// SearchPlaces finds restaurants, cafés, bars and other places to eat, cached like
+// Google searches. A location biases the search towards a box around the circle.
+// Nominatim has no further pages, so no page token is returned.
+func (p *NominatimProvider) SearchPlaces(ctx context.Context, search PlaceSearch) (*PlaceSearchResults, error) {
+	if search.PageToken != "" {
+		return &PlaceSearchResults{Results: []models.GooglePlaceResult{}}, nil
+	}
+	return cachedPlaces(ctx, placesOperationSearch, PlacesProviderNominatim+":"+search.cacheKey(), func() (*PlaceSearchResults, error) {
+		params := url.Values{}
+		params.Set("q", search.Query)
+		params.Set("format", "jsonv2")
+		params.Set("extratags", "1")
+		params.Set("limit", "20")
+		if search.biased() {
+			params.Set("viewbox", nominatimViewbox(*search.Latitude, *search.Longitude, search.Radius))
+		}
+
+		var places []nominatimPlace
+		if err := p.get(ctx, "search", "/search", params, &places); err != nil {
+			logger.Error("Failed to search Nominatim: %v", err)
+			return nil, fmt.Errorf("failed to search places: %w", err)
+		}
+
+		results := make([]models.GooglePlaceResult, 0, len(places))
+		for _, place := range places {
+			if place.Category == "amenity" && nominatimFoodAmenities[place.Type] {
+				results = append(results, place.result())
+			}
+		}
+		logger.Info("✅ Found %d places for query: %s", len(results), search.Query)
+		return &PlaceSearchResults{Results: results}, nil
+	})
+}
+
+// metersPerDegree is the length of a degree of latitude
+const metersPerDegree = 111320
+
+// nominatimViewbox is the box around a circle of radius meters, as
+// "left,top,right,bottom" in degrees
+func nominatimViewbox(lat, lng float64, radius int) string {
+	dLat := float64(radius) / metersPerDegree
+	dLng := dLat / math.Max(math.Cos(lat*math.Pi/180), 0.01)
+	return fmt.Sprintf("%f,%f,%f,%f", lng-dLng, min(lat+dLat, 90), lng+dLng, max(lat-dLat, -90))
+}
+
+// nominatimPlaceID matches the place IDs of search results
+var nominatimPlaceID = regexp.MustCompile(`^[NWR][0-9]+$`)
+
+// GetPlaceDetails looks up a place by its OSM ID, cached like Google details
+func (p *NominatimProvider) GetPlaceDetails(ctx context.Context, placeID string) (*models.GooglePlaceResult, error) {
+	if !nominatimPlaceID.MatchString(placeID) {
+		return nil, ErrPlaceNotFound
+	}
+	return cachedPlaces(ctx, placesOperationDetails, PlacesProviderNominatim+":"+placeID, func() (*models.GooglePlaceResult, error) {
+		params := url.Values{}
+		params.Set("osm_ids", placeID)
+		params.Set("format", "jsonv2")
+		params.Set("extratags", "1")
+
+		var places []nominatimPlace
+		if err := p.get(ctx, "lookup", "/lookup", params, &places); err != nil {
+			logger.Error("Failed to get place details: %v", err)
+			return nil, fmt.Errorf("failed to get place details: %w", err)
+		}
+		if len(places) == 0 {
+			return nil, ErrPlaceNotFound
+		}
+		result := places[0].result()
+		return &result, nil
+	})
+}
+
+// GeocodeCities finds cities; Nominatim returns their coordinates with the search, so
+// coordinates costs nothing extra
+func (p *NominatimProvider) GeocodeCities(ctx context.Context, query string, coordinates bool) ([]models.GooglePlaceResult, error) {
+	params := url.Values{}
+	params.Set("q", query)
+	params.Set("format", "jsonv2")
+	params.Set("featureType", "city")
+	params.Set("limit", "5")
+
+	var places []nominatimPlace
+	if err := p.get(ctx, "search", "/search", params, &places); err != nil {
+		return nil, fmt.Errorf("failed to geocode: %w", err)
+	}
+
+	results := make([]models.GooglePlaceResult, 0, len(places))
+	for _, place := range places {
+		result := place.result()
+		if !coordinates {
+			result.Latitude, result.Longitude = 0, 0
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// Ping checks the status endpoint of the instance
+func (p *NominatimProvider) Ping(ctx context.Context) error {
+	var status struct {
+		Status  int    `json:"status"`
+		Message string `json:"message"`
+	}
+	if err := p.get(ctx, "status", "/status", url.Values{"format": {"json"}}, &status); err != nil {
+		return err
+	}
+	if status.Status != 0 {
+		return fmt.Errorf("nominatim status %d: %s", status.Status, status.Message)
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+const testNominatimRestaurant = `{"osm_type": "node", "osm_id": 123, "lat": "52.5", "lon": "13.4",
+	"category": "amenity", "type": "restaurant", "name": "Luigi's",
+	"display_name": "Luigi's, Main St 1, Berlin, Germany",
+	"extratags": {"contact:phone": "+49 30 123", "website": "https://luigis.example"}}`
+
+// newTestNominatim returns a provider for a test server, which records the requests it
+// receives
+func newTestNominatim(t *testing.T, handler http.HandlerFunc) (*NominatimProvider, *[]*http.Request) {
+	t.Helper()
+	var requests []*http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r)
+		handler(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	p := NewNominatimProvider(NominatimOptions{BaseURL: server.URL + "/", Email: "admin@example.com", Timeout: time.Second})
+	return p, &requests
+}
+
+func TestNominatimSearchPlaces(t *testing.T) {
+	p, requests := newTestNominatim(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `[%s, {"osm_type": "way", "osm_id": 7, "lat": "52.5", "lon": "13.4",
+			"category": "highway", "type": "residential", "name": "Luigi Street"}]`, testNominatimRestaurant)
+	})
+
+	lat, lng := 52.5, 13.4
+	page, err := p.SearchPlaces(context.Background(), PlaceSearch{Query: "luigi berlin", Latitude: &lat, Longitude: &lng, Radius: 11132})
+	if err != nil {
+		t.Fatalf("SearchPlaces failed: %v", err)
+	}
+	results := page.Results
+
+	r := (*requests)[0]
+	q := r.URL.Query()
+	if r.URL.Path != "/search" || q.Get("q") != "luigi berlin" || q.Get("format") != "jsonv2" || q.Get("extratags") != "1" {
+		t.Errorf("Unexpected request %s", r.URL)
+	}
+	if q.Get("viewbox") != "13.235732,52.600000,13.564268,52.400000" || q.Has("bounded") {
+		t.Errorf("Expected a viewbox biasing the search, got %q", q.Get("viewbox"))
+	}
+	if q.Get("email") != "admin@example.com" || r.Header.Get("User-Agent") == "" {
+		t.Error("Expected requests to identify the instance")
+	}
+
+	if len(results) != 1 {
+		t.Fatalf("Expected only the restaurant, got %+v", results)
+	}
+	got := results[0]
+	if got.PlaceID != "N123" || got.Name != "Luigi's" || got.Latitude != 52.5 || got.Longitude != 13.4 {
+		t.Errorf("Unexpected result %+v", got)
+	}
+	if got.Phone != "+49 30 123" || got.Website != "https://luigis.example" {
+		t.Errorf("Expected contact details from the extra tags, got %+v", got)
+	}
+}
+
+func TestNominatimGetPlaceDetails(t *testing.T) {
+	found := true
+	p, requests := newTestNominatim(t, func(w http.ResponseWriter, r *http.Request) {
+		if !found {
+			fmt.Fprint(w, `[]`)
+			return
+		}
+		fmt.Fprintf(w, `[%s]`, testNominatimRestaurant)
+	})
+
+	result, err := p.GetPlaceDetails(context.Background(), "N123")
+	if err != nil {
+		t.Fatalf("GetPlaceDetails failed: %v", err)
+	}
+	r := (*requests)[0]
+	if r.URL.Path != "/lookup" || r.URL.Query().Get("osm_ids") != "N123" {
+		t.Errorf("Unexpected request %s", r.URL)
+	}
+	if result.Name != "Luigi's" {
+		t.Errorf("Unexpected result %+v", result)
+	}
+
+	found = false
+	if _, err := p.GetPlaceDetails(context.Background(), "W9"); !errors.Is(err, ErrPlaceNotFound) {
+		t.Errorf("Expected ErrPlaceNotFound for an unknown place, got %v", err)
+	}
+	if _, err := p.GetPlaceDetails(context.Background(), "ChIJ123"); !errors.Is(err, ErrPlaceNotFound) {
+		t.Errorf("Expected ErrPlaceNotFound for a Google place ID, got %v", err)
+	}
+	if len(*requests) != 2 {
+		t.Errorf("Expected no request for an invalid place ID, got %d requests", len(*requests))
+	}
+}
+
+func TestNominatimGeocodeCities(t *testing.T) {
+	p, requests := newTestNominatim(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[{"osm_type": "relation", "osm_id": 62422, "lat": "52.52", "lon": "13.40",
+			"category": "boundary", "type": "administrative", "name": "Berlin", "display_name": "Berlin, Germany"}]`)
+	})
+
+	results, err := p.GeocodeCities(context.Background(), "berl", true)
+	if err != nil {
+		t.Fatalf("GeocodeCities failed: %v", err)
+	}
+	if q := (*requests)[0].URL.Query(); q.Get("featureType") != "city" {
+		t.Errorf("Expected a city search, got %s", (*requests)[0].URL)
+	}
+	if len(results) != 1 || results[0].PlaceID != "R62422" || results[0].Latitude != 52.52 {
+		t.Errorf("Unexpected results %+v", results)
+	}
+
+	results, err = p.GeocodeCities(context.Background(), "berl", false)
+	if err != nil || results[0].Latitude != 0 || results[0].Name != "Berlin" {
+		t.Errorf("Expected names only, got %+v (%v)", results, err)
+	}
+}
+
+func TestNominatimMinInterval(t *testing.T) {
+	p, _ := newTestNominatim(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"status": 0, "message": "OK"}`)
+	})
+	p.minInterval = 50 * time.Millisecond
+
+	start := time.Now()
+	for range 3 {
+		if err := p.Ping(context.Background()); err != nil {
+			t.Fatalf("Ping failed: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("Expected requests to be spaced by the minimum interval, took %s", elapsed)
+	}
+}
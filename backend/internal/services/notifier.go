@@ -0,0 +1,126 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Notification is a chat message about an event
+type Notification struct {
+	Title string // e.g. "New suggestion: Luigi's"
+	Text  string // Plain text details, may be empty
+	URL   string // Link of the title, may be empty
+}
+
+// Notifier posts notifications to a chat. DiscordNotifier and SlackNotifier implement it.
+type Notifier interface {
+	// Name identifies the chat in logs
+	Name() string
+	Notify(ctx context.Context, n Notification) error
+}
+
+var (
+	_ Notifier = (*DiscordNotifier)(nil)
+	_ Notifier = (*SlackNotifier)(nil)
+)
+
+// notificationColor is the accent color of Discord embeds
+const notificationColor = 0xF97316
+
+// postChatWebhook posts a JSON message to an incoming webhook URL of a chat
+func postChatWebhook(ctx context.Context, client *http.Client, webhookURL string, message any) error {
+	body, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		excerpt, _ := io.ReadAll(io.LimitReader(resp.Body, 256))
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(excerpt)))
+	}
+	return nil
+}
+
+// DiscordNotifier posts notifications as embeds to a Discord channel webhook
+type DiscordNotifier struct {
+	webhookURL string
+	client     *http.Client
+}
+
+// NewDiscordNotifier creates a notifier for a Discord webhook URL
+// (https://discord.com/api/webhooks/<id>/<token>)
+func NewDiscordNotifier(webhookURL string, timeout time.Duration) *DiscordNotifier {
+	return &DiscordNotifier{webhookURL: webhookURL, client: &http.Client{Timeout: timeout}}
+}
+
+func (d *DiscordNotifier) Name() string { return "discord" }
+
+type discordEmbed struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	URL         string `json:"url,omitempty"`
+	Color       int    `json:"color"`
+}
+
+func (d *DiscordNotifier) Notify(ctx context.Context, n Notification) error {
+	return postChatWebhook(ctx, d.client, d.webhookURL, map[string]any{
+		"username": "nomdb",
+		"embeds":   []discordEmbed{{Title: n.Title, Description: n.Text, URL: n.URL, Color: notificationColor}},
+		// Names of restaurants and users must not ping anyone
+		"allowed_mentions": map[string]any{"parse": []string{}},
+	})
+}
+
+// SlackNotifier posts notifications to a Slack incoming webhook
+type SlackNotifier struct {
+	webhookURL string
+	client     *http.Client
+}
+
+// NewSlackNotifier creates a notifier for a Slack incoming webhook URL
+// (https://hooks.slack.com/services/...)
+func NewSlackNotifier(webhookURL string, timeout time.Duration) *SlackNotifier {
+	return &SlackNotifier{webhookURL: webhookURL, client: &http.Client{Timeout: timeout}}
+}
+
+func (s *SlackNotifier) Name() string { return "slack" }
+
+// slackEscape escapes the characters Slack treats as markup in message text
+func slackEscape(text string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(text)
+}
+
+func (s *SlackNotifier) Notify(ctx context.Context, n Notification) error {
+	title := "*" + slackEscape(n.Title) + "*"
+	if n.URL != "" {
+		title = "*<" + n.URL + "|" + slackEscape(n.Title) + ">*"
+	}
+	text := title
+	if n.Text != "" {
+		text += "\n" + slackEscape(n.Text)
+	}
+	return postChatWebhook(ctx, s.client, s.webhookURL, map[string]any{
+		// text is the fallback shown in notifications
+		"text": n.Title,
+		"blocks": []map[string]any{{
+			"type": "section",
+			"text": map[string]string{"type": "mrkdwn", "text": text},
+		}},
+	})
+}
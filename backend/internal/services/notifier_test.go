@@ -0,0 +1,66 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// newChatServer records the JSON body posted to it and answers with status
+func newChatServer(t *testing.T, status int) (string, *map[string]any) {
+	t.Helper()
+	var body map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&body)
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return server.URL, &body
+}
+
+func TestDiscordNotifier(t *testing.T) {
+	url, body := newChatServer(t, http.StatusNoContent)
+
+	n := Notification{Title: "New suggestion: Luigi's", Text: "Main St 1", URL: "https://nomdb.example.com"}
+	if err := NewDiscordNotifier(url, time.Second).Notify(context.Background(), n); err != nil {
+		t.Fatalf("Notify failed: %v", err)
+	}
+
+	embeds := (*body)["embeds"].([]any)
+	embed := embeds[0].(map[string]any)
+	if embed["title"] != n.Title || embed["description"] != n.Text || embed["url"] != n.URL {
+		t.Errorf("Unexpected embed %v", embed)
+	}
+	if mentions := (*body)["allowed_mentions"].(map[string]any); len(mentions["parse"].([]any)) != 0 {
+		t.Errorf("Expected mentions to be disabled, got %v", mentions)
+	}
+}
+
+func TestSlackNotifier(t *testing.T) {
+	url, body := newChatServer(t, http.StatusOK)
+
+	n := Notification{Title: "First rating for A&B <Diner>", Text: "4.3/5", URL: "https://nomdb.example.com"}
+	if err := NewSlackNotifier(url, time.Second).Notify(context.Background(), n); err != nil {
+		t.Fatalf("Notify failed: %v", err)
+	}
+
+	block := (*body)["blocks"].([]any)[0].(map[string]any)
+	text := block["text"].(map[string]any)["text"]
+	if want := "*<https://nomdb.example.com|First rating for A&amp;B &lt;Diner&gt;>*\n4.3/5"; text != want {
+		t.Errorf("Slack text = %q, want %q", text, want)
+	}
+	if (*body)["text"] != n.Title {
+		t.Errorf("Expected the title as fallback text, got %v", (*body)["text"])
+	}
+}
+
+func TestNotify_ErrorStatus(t *testing.T) {
+	url, _ := newChatServer(t, http.StatusNotFound)
+
+	if err := NewSlackNotifier(url, time.Second).Notify(context.Background(), Notification{Title: "x"}); err == nil {
+		t.Error("Expected an error for a 404")
+	}
+}
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nomdb/backend/internal/models"
+)
+
+// Places providers selectable via PLACES_PROVIDER
+const (
+	PlacesProviderGoogle    = "google"
+	PlacesProviderNominatim = "nominatim"
+)
+
+// PlaceSearch is a place search, optionally biased towards places within Radius meters of
+// a location
+type PlaceSearch struct {
+	Query     string
+	Latitude  *float64
+	Longitude *float64
+	Radius    int    // Meters, at most 50000; used with a location
+	PageToken string // Continues an earlier search with its next page
+}
+
+// biased reports whether the search prefers places near a location
+func (s PlaceSearch) biased() bool {
+	return s.Latitude != nil && s.Longitude != nil
+}
+
+// cacheKey identifies the search in the places cache
+func (s PlaceSearch) cacheKey() string {
+	key := placesSearchKey(s.Query)
+	if s.biased() {
+		key += fmt.Sprintf(":%.4f,%.4f,%d", *s.Latitude, *s.Longitude, s.Radius)
+	}
+	if s.PageToken != "" {
+		key += ":" + s.PageToken
+	}
+	return key
+}
+
+// PlaceSearchResults is a page of search results
+type PlaceSearchResults struct {
+	Results       []models.GooglePlaceResult `json:"results"`
+	NextPageToken string                     `json:"next_page_token,omitempty"` // Empty on the last page
+}
+
+// PlacesProvider searches places, looks them up by ID and geocodes cities.
+// GoogleMapsService and NominatimProvider implement it.
+type PlacesProvider interface {
+	// Name returns the provider identifier for logging
+	Name() string
+	// Enabled reports whether the provider can be used
+	Enabled() bool
+	// SearchPlaces finds restaurants matching the search
+	SearchPlaces(ctx context.Context, search PlaceSearch) (*PlaceSearchResults, error)
+	// GetPlaceDetails looks up a place by the PlaceID of a search result
+	GetPlaceDetails(ctx context.Context, placeID string) (*models.GooglePlaceResult, error)
+	// GeocodeCities finds cities by name, with their coordinates unless coordinates is false
+	GeocodeCities(ctx context.Context, query string, coordinates bool) ([]models.GooglePlaceResult, error)
+	// Ping checks that the provider is reachable
+	Ping(ctx context.Context) error
+}
+
+var (
+	_ PlacesProvider = (*GoogleMapsService)(nil)
+	_ PlacesProvider = (*NominatimProvider)(nil)
+)
@@ -0,0 +1,86 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/nomdb/backend/internal/cache"
+	"github.com/nomdb/backend/internal/logger"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// MaxPlacesCacheTTL is how long the Google Maps Platform terms allow Places content
+// other than place IDs to be cached
+const MaxPlacesCacheTTL = 30 * 24 * time.Hour
+
+// Places lookups cached in the cache backend
+const (
+	placesOperationSearch  = "search"
+	placesOperationDetails = "details"
+)
+
+// placesCacheTTL holds the TTL per Places operation; missing or 0 disables caching
+var placesCacheTTL = map[string]time.Duration{}
+
+var placesCacheRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "nomdb",
+	Name:      "places_cache_requests_total",
+	Help:      "Google Places lookups by operation and cache result (hit or miss).",
+}, []string{"operation", "result"})
+
+// PlacesCacheCollector returns the Prometheus counter of Places cache hits and misses
+func PlacesCacheCollector() prometheus.Collector {
+	return placesCacheRequests
+}
+
+// InitPlacesCache caches place searches and place details in the cache backend for
+// the given TTLs (0 disables)
+func InitPlacesCache(searchTTL, detailsTTL time.Duration) {
+	placesCacheTTL = map[string]time.Duration{
+		placesOperationSearch:  searchTTL,
+		placesOperationDetails: detailsTTL,
+	}
+	logger.Info("✅ Places cache: search %s, details %s (%s)", searchTTL, detailsTTL, cache.Backend().Name())
+}
+
+// cachedPlaces returns the cached result of a Places lookup, calling fetch on a miss
+// and caching its result. Cache errors are logged and treated as a miss.
+func cachedPlaces[T any](ctx context.Context, operation, key string, fetch func() (T, error)) (T, error) {
+	ttl := placesCacheTTL[operation]
+	store := cache.Backend()
+	if ttl <= 0 || store.Name() == cache.BackendNone {
+		return fetch()
+	}
+
+	key = "places:" + operation + ":" + key
+	data, ok, err := store.Get(ctx, key)
+	if err != nil {
+		logger.Warn("Places cache get %s failed: %v", key, err)
+	}
+	if ok {
+		var v T
+		if err := json.Unmarshal(data, &v); err == nil {
+			placesCacheRequests.WithLabelValues(operation, "hit").Inc()
+			return v, nil
+		}
+	}
+	placesCacheRequests.WithLabelValues(operation, "miss").Inc()
+
+	v, err := fetch()
+	if err != nil {
+		return v, err
+	}
+	if data, err := json.Marshal(v); err == nil {
+		if err := store.Set(ctx, key, data, ttl); err != nil {
+			logger.Warn("Places cache set %s failed: %v", key, err)
+		}
+	}
+	return v, nil
+}
+
+// placesSearchKey folds queries differing only in case and spacing together
+func placesSearchKey(query string) string {
+	return strings.ToLower(strings.Join(strings.Fields(query), " "))
+}
@@ -0,0 +1,96 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nomdb/backend/internal/cache"
+	"github.com/nomdb/backend/internal/models"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func withPlacesCache(t *testing.T, backend string, searchTTL time.Duration) {
+	t.Helper()
+	if err := cache.Init(cache.Config{Backend: backend, TTL: 0}); err != nil {
+		t.Fatalf("cache.Init failed: %v", err)
+	}
+	InitPlacesCache(searchTTL, time.Hour)
+	placesCacheRequests.Reset()
+	t.Cleanup(func() {
+		cache.Init(cache.Config{Backend: cache.BackendNone})
+		placesCacheTTL = map[string]time.Duration{}
+	})
+}
+
+func TestCachedPlaces(t *testing.T) {
+	// The response cache is disabled by its zero TTL, the Places cache is not
+	withPlacesCache(t, cache.BackendMemory, time.Hour)
+	ctx := context.Background()
+
+	calls := 0
+	fetch := func() ([]models.GooglePlaceResult, error) {
+		calls++
+		return []models.GooglePlaceResult{{PlaceID: "abc", Name: "Luigi's"}}, nil
+	}
+	for range 2 {
+		results, err := cachedPlaces(ctx, placesOperationSearch, placesSearchKey("  Luigi's  Berlin"), fetch)
+		if err != nil || len(results) != 1 || results[0].PlaceID != "abc" {
+			t.Fatalf("Unexpected results %+v (%v)", results, err)
+		}
+	}
+	if _, err := cachedPlaces(ctx, placesOperationSearch, placesSearchKey("luigi's berlin"), fetch); err != nil {
+		t.Fatalf("cachedPlaces failed: %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("Expected one API call, got %d", calls)
+	}
+	if hits := testutil.ToFloat64(placesCacheRequests.WithLabelValues("search", "hit")); hits != 2 {
+		t.Errorf("Expected 2 hits, got %v", hits)
+	}
+	if misses := testutil.ToFloat64(placesCacheRequests.WithLabelValues("search", "miss")); misses != 1 {
+		t.Errorf("Expected 1 miss, got %v", misses)
+	}
+}
+
+func TestCachedPlaces_ErrorsNotCached(t *testing.T) {
+	withPlacesCache(t, cache.BackendMemory, time.Hour)
+	ctx := context.Background()
+
+	calls := 0
+	fetch := func() (*models.GooglePlaceResult, error) {
+		calls++
+		return nil, errors.New("OVER_QUERY_LIMIT")
+	}
+	cachedPlaces(ctx, placesOperationDetails, "abc", fetch)
+	cachedPlaces(ctx, placesOperationDetails, "abc", fetch)
+	if calls != 2 {
+		t.Errorf("Expected failed lookups to be retried, got %d calls", calls)
+	}
+}
+
+func TestCachedPlaces_Disabled(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		backend string
+		ttl     time.Duration
+	}{
+		{"zero TTL", cache.BackendMemory, 0},
+		{"no cache backend", cache.BackendNone, time.Hour},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			withPlacesCache(t, tt.backend, tt.ttl)
+			calls := 0
+			fetch := func() ([]models.GooglePlaceResult, error) {
+				calls++
+				return nil, nil
+			}
+			cachedPlaces(context.Background(), placesOperationSearch, "pizza", fetch)
+			cachedPlaces(context.Background(), placesOperationSearch, "pizza", fetch)
+			if calls != 2 {
+				t.Errorf("Expected no caching, got %d calls", calls)
+			}
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	safeFetchTimeout      = 15 * time.Second
+	safeFetchMaxRedirects = 3
+)
+
+// ErrForbiddenAddress is returned when a URL resolves to an address that must not be
+// reached from the server (loopback, private networks, cloud metadata, ...)
+var ErrForbiddenAddress = errors.New("address not allowed")
+
+// ErrInvalidURL is returned for malformed URLs and unsupported schemes
+var ErrInvalidURL = errors.New("invalid URL")
+
+// ErrResponseTooLarge is returned when a fetched body exceeds the size limit
+var ErrResponseTooLarge = errors.New("response too large")
+
+// nonPublicPrefixes are special-purpose ranges not covered by the net.IP helpers
+var nonPublicPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"), // Carrier-grade NAT
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("192.0.2.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("198.51.100.0/24"),
+	netip.MustParsePrefix("203.0.113.0/24"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("64:ff9b::/96"), // NAT64 can reach IPv4 internal ranges
+	netip.MustParsePrefix("64:ff9b:1::/48"),
+	netip.MustParsePrefix("2001:db8::/32"),
+}
+
+// IsPublicIP reports whether ip is a globally routable unicast address
+func IsPublicIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsMulticast() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() {
+		return false
+	}
+	addr, ok := netip.AddrFromSlice(ip)
+	if !ok {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range nonPublicPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// NewSafeHTTPClient returns an HTTP client for fetching user-supplied URLs. The
+// destination is checked after DNS resolution at connect time, so DNS rebinding and
+// redirects to internal addresses are blocked as well. Environment proxies are ignored.
+func NewSafeHTTPClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: 5 * time.Second,
+		Control: func(network, address string, c syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			ip := net.ParseIP(host)
+			if ip == nil || !IsPublicIP(ip) {
+				return fmt.Errorf("%w: %s", ErrForbiddenAddress, host)
+			}
+			return nil
+		},
+	}
+
+	return &http.Client{
+		Timeout: safeFetchTimeout,
+		Transport: &http.Transport{
+			Proxy:                 nil,
+			DialContext:           dialer.DialContext,
+			TLSHandshakeTimeout:   5 * time.Second,
+			ResponseHeaderTimeout: 10 * time.Second,
+			MaxIdleConns:          10,
+			IdleConnTimeout:       30 * time.Second,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= safeFetchMaxRedirects {
+				return fmt.Errorf("stopped after %d redirects", safeFetchMaxRedirects)
+			}
+			return validateFetchURL(req.URL)
+		},
+	}
+}
+
+// validateFetchURL allows only plain http(s) URLs on default ports without credentials
+func validateFetchURL(u *url.URL) error {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("%w: unsupported scheme %q", ErrInvalidURL, u.Scheme)
+	}
+	if u.User != nil {
+		return fmt.Errorf("%w: credentials are not allowed", ErrInvalidURL)
+	}
+	if u.Hostname() == "" {
+		return fmt.Errorf("%w: missing host", ErrInvalidURL)
+	}
+	if port := u.Port(); port != "" && port != "80" && port != "443" {
+		return fmt.Errorf("%w: port %s", ErrForbiddenAddress, port)
+	}
+	return nil
+}
+
+// FetchResult is a downloaded resource
+type FetchResult struct {
+	Body        []byte
+	ContentType string // Declared by the server, without parameters
+	FinalURL    *url.URL
+}
+
+// SafeFetch downloads a user-supplied URL with client, reading at most maxSize bytes
+func SafeFetch(ctx context.Context, client *http.Client, rawURL string, maxSize int64) (*FetchResult, error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidURL, err)
+	}
+	if err := validateFetchURL(u); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "image/jpeg, image/png, image/webp")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("remote server returned %d", resp.StatusCode)
+	}
+	if resp.ContentLength > maxSize {
+		return nil, ErrResponseTooLarge
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if int64(len(body)) > maxSize {
+		return nil, ErrResponseTooLarge
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	if i := strings.Index(contentType, ";"); i >= 0 {
+		contentType = contentType[:i]
+	}
+
+	return &FetchResult{
+		Body:        body,
+		ContentType: strings.ToLower(strings.TrimSpace(contentType)),
+		FinalURL:    resp.Request.URL,
+	}, nil
+}
@@ -0,0 +1,98 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nomdb/backend/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Headers of webhook requests
+const (
+	WebhookEventHeader     = "X-Nomdb-Event"
+	WebhookDeliveryHeader  = "X-Nomdb-Delivery"
+	WebhookSignatureHeader = "X-Nomdb-Signature"
+)
+
+// webhookResponseExcerpt is how much of an error response is kept for the delivery log
+const webhookResponseExcerpt = 256
+
+// SignWebhook returns the signature header of a webhook body sent at timestamp:
+// "t=<unix seconds>,v1=<hex HMAC-SHA256 of "<unix seconds>.<body>" with secret>".
+// Receivers recompute it and should reject old timestamps to prevent replays.
+func SignWebhook(secret string, timestamp time.Time, body []byte) string {
+	t := strconv.FormatInt(timestamp.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(t + "."))
+	mac.Write(body)
+	return "t=" + t + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// WebhookSender posts signed event payloads to webhook endpoints
+type WebhookSender struct {
+	client *http.Client
+	now    func() time.Time
+}
+
+// NewWebhookSender creates a sender giving up on endpoints after timeout. Redirects are
+// not followed, since they would drop the body of the POST.
+func NewWebhookSender(timeout time.Duration) *WebhookSender {
+	return &WebhookSender{
+		client: &http.Client{
+			Timeout: timeout,
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		now: time.Now,
+	}
+}
+
+// Send posts body to url. Any 2xx response is a success; otherwise the error includes
+// the start of the response body. statusCode is 0 when no response arrived.
+func (s *WebhookSender) Send(ctx context.Context, url, secret, event string, deliveryID int64, body []byte) (statusCode int, err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "webhook.deliver", trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("webhook.event", event), attribute.Int64("webhook.delivery_id", deliveryID)))
+	defer func() { tracing.EndSpan(span, err) }()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "nomdb-webhooks/1.0")
+	req.Header.Set(WebhookEventHeader, event)
+	req.Header.Set(WebhookDeliveryHeader, strconv.FormatInt(deliveryID, 10))
+	req.Header.Set(WebhookSignatureHeader, SignWebhook(secret, s.now(), body))
+	span.SetAttributes(attribute.String("server.address", req.URL.Hostname()))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		excerpt, _ := io.ReadAll(io.LimitReader(resp.Body, webhookResponseExcerpt))
+		message := fmt.Sprintf("unexpected status %d", resp.StatusCode)
+		if text := strings.TrimSpace(string(excerpt)); text != "" {
+			message += ": " + text
+		}
+		return resp.StatusCode, fmt.Errorf("%s", message)
+	}
+	// Drain the body so the connection is reused
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	return resp.StatusCode, nil
+}
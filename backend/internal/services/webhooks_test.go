@@ -0,0 +1,74 @@
+package services
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSignWebhook(t *testing.T) {
+	// echo -n '1700000000.{"event":"rating.created"}' | openssl dgst -sha256 -hmac s3cret
+	got := SignWebhook("s3cret", time.Unix(1700000000, 0), []byte(`{"event":"rating.created"}`))
+	want := "t=1700000000,v1=d6be8c30c30ba778ad097e8031ffdbab0e7ae76be7a951cf4f7f0f8fe15945c7"
+	if got != want {
+		t.Errorf("SignWebhook = %q, want %q", got, want)
+	}
+}
+
+func TestWebhookSend(t *testing.T) {
+	var header http.Header
+	var body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+		b, _ := io.ReadAll(r.Body)
+		body = string(b)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	sender := NewWebhookSender(time.Second)
+	sender.now = func() time.Time { return time.Unix(1700000000, 0) }
+	payload := []byte(`{"event":"rating.created"}`)
+
+	status, err := sender.Send(context.Background(), server.URL, "s3cret", "rating.created", 7, payload)
+	if err != nil || status != http.StatusAccepted {
+		t.Fatalf("Send = %d, %v", status, err)
+	}
+	if body != string(payload) || header.Get(WebhookEventHeader) != "rating.created" || header.Get(WebhookDeliveryHeader) != "7" {
+		t.Errorf("Unexpected request %v %q", header, body)
+	}
+	if header.Get(WebhookSignatureHeader) != SignWebhook("s3cret", time.Unix(1700000000, 0), payload) {
+		t.Errorf("Unexpected signature %q", header.Get(WebhookSignatureHeader))
+	}
+}
+
+func TestWebhookSend_Failures(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		status  int
+		message string
+	}{
+		{"error status", func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "database is down", http.StatusInternalServerError)
+		}, 500, "unexpected status 500: database is down"},
+		{"redirect", func(w http.ResponseWriter, r *http.Request) {
+			http.Redirect(w, r, "/elsewhere", http.StatusFound)
+		}, 302, "unexpected status 302"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(tt.handler)
+			defer server.Close()
+
+			status, err := NewWebhookSender(time.Second).Send(context.Background(), server.URL, "s3cret", "rating.created", 7, []byte(`{}`))
+			if status != tt.status || err == nil || !strings.HasPrefix(err.Error(), tt.message) {
+				t.Errorf("Send = %d, %v; want %d, %q", status, err, tt.status, tt.message)
+			}
+		})
+	}
+}
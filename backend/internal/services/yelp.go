@@ -0,0 +1,101 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nomdb/backend/internal/logger"
+	"github.com/nomdb/backend/internal/models"
+)
+
+const yelpBaseURL = "https://api.yelp.com"
+
+// YelpProvider looks up restaurants with the Yelp Fusion business search
+type YelpProvider struct {
+	apiKey  string
+	baseURL string
+	client  *http.Client
+}
+
+// NewYelpProvider creates a provider authenticating with a Yelp Fusion API key
+func NewYelpProvider(apiKey string, timeout time.Duration) *YelpProvider {
+	return &YelpProvider{apiKey: apiKey, baseURL: yelpBaseURL, client: &http.Client{Timeout: timeout}}
+}
+
+func (p *YelpProvider) Name() string { return EnrichmentProviderYelp }
+
+type yelpBusiness struct {
+	ID          string  `json:"id"`
+	Name        string  `json:"name"`
+	URL         string  `json:"url"`
+	Rating      float64 `json:"rating"`
+	ReviewCount int     `json:"review_count"`
+	Price       string  `json:"price"` // "$" to "$$$$", missing when unknown
+	Categories  []struct {
+		Title string `json:"title"`
+	} `json:"categories"`
+}
+
+func (b yelpBusiness) source() *models.ExternalSource {
+	source := &models.ExternalSource{
+		Provider:   EnrichmentProviderYelp,
+		ExternalID: b.ID,
+		Name:       b.Name,
+		Categories: make([]string, 0, len(b.Categories)),
+		FetchedAt:  time.Now(),
+	}
+	if b.URL != "" {
+		// Links carry tracking parameters of the API key
+		link, _, _ := strings.Cut(b.URL, "?")
+		source.URL = &link
+	}
+	if b.ReviewCount > 0 {
+		rating, count := b.Rating, b.ReviewCount
+		source.Rating, source.RatingCount = &rating, &count
+	}
+	if level := strings.Count(b.Price, "$"); level > 0 {
+		source.PriceLevel = &level
+	}
+	for _, c := range b.Categories {
+		source.Categories = append(source.Categories, c.Title)
+	}
+	return source
+}
+
+// Lookup searches businesses by name near the restaurant and returns the closest one
+// with a matching name
+func (p *YelpProvider) Lookup(ctx context.Context, query EnrichmentQuery) (*models.ExternalSource, error) {
+	params := url.Values{}
+	params.Set("term", query.Name)
+	if query.Latitude != nil && query.Longitude != nil {
+		params.Set("latitude", strconv.FormatFloat(*query.Latitude, 'f', -1, 64))
+		params.Set("longitude", strconv.FormatFloat(*query.Longitude, 'f', -1, 64))
+		params.Set("radius", "500")
+	} else {
+		params.Set("location", query.Address)
+	}
+	params.Set("categories", "restaurants,food,bars")
+	params.Set("sort_by", "distance")
+	params.Set("limit", "5")
+
+	var resp struct {
+		Businesses []yelpBusiness `json:"businesses"`
+	}
+	header := http.Header{"Authorization": {"Bearer " + p.apiKey}, "Accept": {"application/json"}}
+	if err := enrichmentGet(ctx, p.client, "yelp.search", p.baseURL+"/v3/businesses/search?"+params.Encode(), header, &resp); err != nil {
+		logger.Error("Failed to search Yelp: %v", err)
+		return nil, fmt.Errorf("failed to search Yelp: %w", err)
+	}
+
+	for _, b := range resp.Businesses {
+		if sameBusinessName(b.Name, query.Name) {
+			return b.source(), nil
+		}
+	}
+	return nil, ErrNoExternalMatch
+}
@@ -0,0 +1,191 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/container"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/sas"
+)
+
+// AzureStorage stores files in an Azure Blob Storage container
+type AzureStorage struct {
+	client    *azblob.Client
+	container *container.Client
+	account   string
+	name      string
+}
+
+// NewAzureStorage initializes an Azure Blob client with a shared account key.
+// endpoint defaults to https://<account>.blob.core.windows.net/ (set it for Azurite).
+func NewAzureStorage(account, key, containerName, endpoint string) (*AzureStorage, error) {
+	storageLog.Info("☁️  Initializing Azure Blob storage...")
+
+	if account == "" || key == "" || containerName == "" {
+		return nil, fmt.Errorf("AZURE_STORAGE_ACCOUNT, AZURE_STORAGE_KEY and AZURE_STORAGE_CONTAINER are required")
+	}
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://%s.blob.core.windows.net/", account)
+	}
+
+	cred, err := azblob.NewSharedKeyCredential(account, key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Azure credentials: %w", err)
+	}
+
+	client, err := azblob.NewClientWithSharedKeyCredential(endpoint, cred, &azblob.ClientOptions{
+		ClientOptions: policy.ClientOptions{PerCallPolicies: []policy.Policy{azureRequestIDPolicy{}}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Azure client: %w", err)
+	}
+
+	storageLog.Info("✅ Azure Blob storage initialized (account: %s, container: %s)", account, containerName)
+	return &AzureStorage{
+		client:    client,
+		container: client.ServiceClient().NewContainerClient(containerName),
+		account:   account,
+		name:      containerName,
+	}, nil
+}
+
+func (s *AzureStorage) Name() string {
+	return fmt.Sprintf("azure (account: %s, container: %s)", s.account, s.name)
+}
+
+func (s *AzureStorage) Upload(ctx context.Context, key string, body io.Reader, contentType string) error {
+	storageLog.Ctx(ctx).Debug("📤 Uploading file to Azure: %s (type: %s)", key, contentType)
+
+	_, err := s.client.UploadStream(ctx, s.name, key, body, &azblob.UploadStreamOptions{
+		HTTPHeaders: &blob.HTTPHeaders{BlobContentType: &contentType},
+	})
+	if err != nil {
+		storageLog.Ctx(ctx).Error("❌ Failed to upload file to Azure: %v", err)
+		return fmt.Errorf("failed to upload file to Azure: %w", err)
+	}
+
+	storageLog.Ctx(ctx).Info("✅ File uploaded to Azure: %s", key)
+	return nil
+}
+
+func (s *AzureStorage) Download(ctx context.Context, key string) ([]byte, error) {
+	resp, err := s.client.DownloadStream(ctx, s.name, key, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download file from Azure: %w", err)
+	}
+	defer resp.Body.Close()
+
+	return io.ReadAll(resp.Body)
+}
+
+func (s *AzureStorage) ReadHead(ctx context.Context, key string, n int) ([]byte, error) {
+	resp, err := s.client.DownloadStream(ctx, s.name, key, &azblob.DownloadStreamOptions{
+		Range: azblob.HTTPRange{Offset: 0, Count: int64(n)},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file from Azure: %w", err)
+	}
+	defer resp.Body.Close()
+
+	return io.ReadAll(io.LimitReader(resp.Body, int64(n)))
+}
+
+func (s *AzureStorage) Stat(ctx context.Context, key string) (*ObjectInfo, error) {
+	props, err := s.container.NewBlobClient(key).GetProperties(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat file in Azure: %w", err)
+	}
+
+	info := &ObjectInfo{}
+	if props.ContentLength != nil {
+		info.Size = *props.ContentLength
+	}
+	if props.ContentType != nil {
+		info.ContentType = *props.ContentType
+	}
+	return info, nil
+}
+
+func (s *AzureStorage) Delete(ctx context.Context, key string) error {
+	storageLog.Ctx(ctx).Debug("🗑️  Deleting file from Azure: %s", key)
+
+	if _, err := s.client.DeleteBlob(ctx, s.name, key, nil); err != nil {
+		storageLog.Ctx(ctx).Error("❌ Failed to delete file from Azure: %v", err)
+		return fmt.Errorf("failed to delete file from Azure: %w", err)
+	}
+	return nil
+}
+
+func (s *AzureStorage) DeletePrefix(ctx context.Context, prefix string) error {
+	pager := s.client.NewListBlobsFlatPager(s.name, &azblob.ListBlobsFlatOptions{Prefix: &prefix})
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list files in Azure: %w", err)
+		}
+		for _, item := range page.Segment.BlobItems {
+			if item.Name == nil {
+				continue
+			}
+			if err := s.Delete(ctx, *item.Name); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *AzureStorage) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	var objects []ObjectInfo
+	pager := s.client.NewListBlobsFlatPager(s.name, &azblob.ListBlobsFlatOptions{Prefix: &prefix})
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list files in Azure: %w", err)
+		}
+		for _, item := range page.Segment.BlobItems {
+			if item.Name == nil {
+				continue
+			}
+			info := ObjectInfo{Key: *item.Name}
+			if item.Properties != nil {
+				if item.Properties.ContentLength != nil {
+					info.Size = *item.Properties.ContentLength
+				}
+				if item.Properties.LastModified != nil {
+					info.LastModified = *item.Properties.LastModified
+				}
+			}
+			objects = append(objects, info)
+		}
+	}
+	return objects, nil
+}
+
+// URL generates a read-only SAS URL
+func (s *AzureStorage) URL(ctx context.Context, key string, expiry time.Duration) (string, error) {
+	url, err := s.container.NewBlobClient(key).GetSASURL(sas.BlobPermissions{Read: true}, time.Now().Add(expiry), nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate SAS URL: %w", err)
+	}
+	return url, nil
+}
+
+// PresignUpload generates a create-only SAS URL for a direct block blob upload
+func (s *AzureStorage) PresignUpload(ctx context.Context, key, contentType string, size int64, expiry time.Duration) (string, map[string]string, error) {
+	url, err := s.container.NewBlobClient(key).GetSASURL(sas.BlobPermissions{Create: true, Write: true}, time.Now().Add(expiry), nil)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to generate SAS upload URL: %w", err)
+	}
+	return url, map[string]string{
+		"Content-Type":   contentType,
+		"Content-Length": strconv.FormatInt(size, 10),
+		"x-ms-blob-type": "BlockBlob",
+	}, nil
+}
@@ -0,0 +1,95 @@
+package storage
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestLocalStorage_ListAndDeletePrefix(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewLocalStorage(t.TempDir(), "")
+	if err != nil {
+		t.Fatalf("NewLocalStorage failed: %v", err)
+	}
+
+	keys := []string{
+		"menu_photos/a.jpg",
+		"menu_photos/thumbnails/a_thumb.jpg",
+		"menu_photos/variants/a_100x100.webp",
+		"menu_photos/variants/b_100x100.webp",
+		"other/c.jpg",
+	}
+	for _, key := range keys {
+		if err := s.Upload(ctx, key, strings.NewReader("data"), "image/jpeg"); err != nil {
+			t.Fatalf("Upload(%s) failed: %v", key, err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		prefix   string
+		expected []string
+	}{
+		{
+			name:     "All photos",
+			prefix:   "menu_photos/",
+			expected: keys[:4],
+		},
+		{
+			name:     "Variants of one photo",
+			prefix:   "menu_photos/variants/a_",
+			expected: []string{"menu_photos/variants/a_100x100.webp"},
+		},
+		{
+			name:     "No matches",
+			prefix:   "missing/",
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			objects, err := s.List(ctx, tt.prefix)
+			if err != nil {
+				t.Fatalf("List failed: %v", err)
+			}
+			var got []string
+			for _, obj := range objects {
+				if obj.Size != 4 {
+					t.Errorf("Expected size 4 for %s, got %d", obj.Key, obj.Size)
+				}
+				got = append(got, obj.Key)
+			}
+			sort.Strings(got)
+			if strings.Join(got, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+
+	if err := s.DeletePrefix(ctx, "menu_photos/variants/a_"); err != nil {
+		t.Fatalf("DeletePrefix failed: %v", err)
+	}
+	objects, err := s.List(ctx, "menu_photos/variants/")
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(objects) != 1 || objects[0].Key != "menu_photos/variants/b_100x100.webp" {
+		t.Errorf("Expected only b variant to remain, got %v", objects)
+	}
+}
+
+func TestLocalStorage_RejectsTraversal(t *testing.T) {
+	s, err := NewLocalStorage(t.TempDir(), "")
+	if err != nil {
+		t.Fatalf("NewLocalStorage failed: %v", err)
+	}
+
+	for _, key := range []string{"../escape.jpg", "menu_photos/../../escape.jpg", ""} {
+		if err := s.Upload(context.Background(), key, strings.NewReader("x"), "image/jpeg"); err == nil {
+			t.Errorf("Expected Upload(%q) to fail", key)
+		}
+	}
+}
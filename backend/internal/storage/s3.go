@@ -0,0 +1,246 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// S3Options configures an S3 or S3-compatible (MinIO, GCS interop) backend
+type S3Options struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	Region          string
+	Bucket          string
+	Endpoint        string // Empty for AWS
+	UsePathStyle    bool   // Required by most self-hosted S3-compatible servers
+}
+
+// S3Storage stores files in an S3 bucket
+type S3Storage struct {
+	backend    string
+	client     *s3.Client
+	bucketName string
+	region     string
+	endpoint   string
+}
+
+// NewS3Storage initializes an S3 client with static credentials
+func NewS3Storage(backend string, opts S3Options) (*S3Storage, error) {
+	storageLog.Info("☁️  Initializing %s storage...", backend)
+
+	if opts.AccessKeyID == "" || opts.SecretAccessKey == "" || opts.Region == "" || opts.Bucket == "" {
+		return nil, fmt.Errorf("credentials, region or bucket name not configured")
+	}
+	if backend == BackendMinIO && opts.Endpoint == "" {
+		return nil, fmt.Errorf("S3_ENDPOINT is required for MinIO")
+	}
+
+	storageLog.Debug("Loading S3 config for region: %s, bucket: %s", opts.Region, opts.Bucket)
+
+	cfg, err := config.LoadDefaultConfig(context.TODO(),
+		config.WithRegion(opts.Region),
+		config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(
+			opts.AccessKeyID,
+			opts.SecretAccessKey,
+			"",
+		)),
+	)
+	if err != nil {
+		storageLog.Error("❌ Failed to load AWS config: %v", err)
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+
+	client := s3.NewFromConfig(cfg, func(o *s3.Options) {
+		if opts.Endpoint != "" {
+			o.BaseEndpoint = aws.String(opts.Endpoint)
+		}
+		o.UsePathStyle = opts.UsePathStyle
+		o.APIOptions = append(o.APIOptions, s3TracingMiddleware(opts.Bucket), s3RequestIDMiddleware)
+	})
+
+	storageLog.Info("✅ %s storage initialized (bucket: %s, region: %s)", backend, opts.Bucket, opts.Region)
+	return &S3Storage{
+		backend:    backend,
+		client:     client,
+		bucketName: opts.Bucket,
+		region:     opts.Region,
+		endpoint:   opts.Endpoint,
+	}, nil
+}
+
+func (s *S3Storage) Name() string {
+	if s.endpoint != "" {
+		return fmt.Sprintf("%s (bucket: %s, endpoint: %s)", s.backend, s.bucketName, s.endpoint)
+	}
+	return fmt.Sprintf("%s (bucket: %s, region: %s)", s.backend, s.bucketName, s.region)
+}
+
+// Upload uploads a file to S3
+func (s *S3Storage) Upload(ctx context.Context, key string, body io.Reader, contentType string) error {
+	storageLog.Ctx(ctx).Debug("📤 Uploading file to S3: %s (type: %s)", key, contentType)
+
+	_, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(s.bucketName),
+		Key:         aws.String(key),
+		Body:        body,
+		ContentType: aws.String(contentType),
+		ACL:         "private", // Use private ACL for security
+	})
+	if err != nil {
+		storageLog.Ctx(ctx).Error("❌ Failed to upload file to S3: %v", err)
+		return fmt.Errorf("failed to upload file to S3: %w", err)
+	}
+
+	storageLog.Ctx(ctx).Info("✅ File uploaded to S3: %s", key)
+	return nil
+}
+
+// Download reads a whole object from S3
+func (s *S3Storage) Download(ctx context.Context, key string) ([]byte, error) {
+	out, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucketName),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to download file from S3: %w", err)
+	}
+	defer out.Body.Close()
+
+	return io.ReadAll(out.Body)
+}
+
+// ReadHead returns the first n bytes of an object
+func (s *S3Storage) ReadHead(ctx context.Context, key string, n int) ([]byte, error) {
+	out, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucketName),
+		Key:    aws.String(key),
+		Range:  aws.String(fmt.Sprintf("bytes=0-%d", n-1)),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file from S3: %w", err)
+	}
+	defer out.Body.Close()
+
+	return io.ReadAll(io.LimitReader(out.Body, int64(n)))
+}
+
+// Stat returns the size and content type of an object
+func (s *S3Storage) Stat(ctx context.Context, key string) (*ObjectInfo, error) {
+	out, err := s.client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(s.bucketName),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat file in S3: %w", err)
+	}
+
+	return &ObjectInfo{Size: aws.ToInt64(out.ContentLength), ContentType: aws.ToString(out.ContentType)}, nil
+}
+
+// Delete deletes a file from S3
+func (s *S3Storage) Delete(ctx context.Context, key string) error {
+	storageLog.Ctx(ctx).Debug("🗑️  Deleting file from S3: %s", key)
+
+	_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucketName),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		storageLog.Ctx(ctx).Error("❌ Failed to delete file from S3: %v", err)
+		return fmt.Errorf("failed to delete file from S3: %w", err)
+	}
+
+	storageLog.Ctx(ctx).Info("✅ File deleted from S3: %s", key)
+	return nil
+}
+
+// DeletePrefix deletes every object whose key starts with prefix
+func (s *S3Storage) DeletePrefix(ctx context.Context, prefix string) error {
+	paginator := s3.NewListObjectsV2Paginator(s.client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(s.bucketName),
+		Prefix: aws.String(prefix),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list files in S3: %w", err)
+		}
+		for _, obj := range page.Contents {
+			if err := s.Delete(ctx, aws.ToString(obj.Key)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// List returns every object whose key starts with prefix
+func (s *S3Storage) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	var objects []ObjectInfo
+	paginator := s3.NewListObjectsV2Paginator(s.client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(s.bucketName),
+		Prefix: aws.String(prefix),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list files in S3: %w", err)
+		}
+		for _, obj := range page.Contents {
+			objects = append(objects, ObjectInfo{
+				Key:          aws.ToString(obj.Key),
+				Size:         aws.ToInt64(obj.Size),
+				LastModified: aws.ToTime(obj.LastModified),
+			})
+		}
+	}
+	return objects, nil
+}
+
+// URL generates a presigned URL for private file access
+func (s *S3Storage) URL(ctx context.Context, key string, expiry time.Duration) (string, error) {
+	presignClient := s3.NewPresignClient(s.client)
+
+	request, err := presignClient.PresignGetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucketName),
+		Key:    aws.String(key),
+	}, func(opts *s3.PresignOptions) {
+		opts.Expires = expiry
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to generate presigned URL: %w", err)
+	}
+
+	return request.URL, nil
+}
+
+// PresignUpload generates a presigned PUT URL for uploading an object directly to S3.
+// Content type and length are part of the signature, so the client must send matching headers.
+func (s *S3Storage) PresignUpload(ctx context.Context, key, contentType string, size int64, expiry time.Duration) (string, map[string]string, error) {
+	presignClient := s3.NewPresignClient(s.client)
+
+	request, err := presignClient.PresignPutObject(ctx, &s3.PutObjectInput{
+		Bucket:        aws.String(s.bucketName),
+		Key:           aws.String(key),
+		ContentType:   aws.String(contentType),
+		ContentLength: aws.Int64(size),
+	}, func(opts *s3.PresignOptions) {
+		opts.Expires = expiry
+	})
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to generate presigned upload URL: %w", err)
+	}
+
+	return request.URL, map[string]string{
+		"Content-Type":   contentType,
+		"Content-Length": strconv.FormatInt(size, 10),
+	}, nil
+}
@@ -0,0 +1,142 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/nomdb/backend/internal/logger"
+)
+
+// Storage backends selectable via STORAGE_BACKEND
+const (
+	BackendAuto  = "auto" // S3 if AWS credentials are set, local disk otherwise
+	BackendLocal = "local"
+	BackendS3    = "s3"
+	BackendMinIO = "minio"
+	BackendGCS   = "gcs"
+	BackendAzure = "azure"
+)
+
+// storageLog logs the storage backends
+var storageLog = logger.Module("storage")
+
+// ErrNotSupported is returned for operations a backend cannot perform (e.g. presigned uploads to local disk)
+var ErrNotSupported = errors.New("operation not supported by storage backend")
+
+// ObjectInfo describes a stored object
+type ObjectInfo struct {
+	Key          string
+	Size         int64
+	ContentType  string
+	LastModified time.Time
+}
+
+// Storage is a blob store for uploaded files. Keys are slash-separated paths such as
+// "menu_photos/<filename>", identical across backends.
+type Storage interface {
+	// Name returns the backend identifier for logging
+	Name() string
+	// Upload stores body under key
+	Upload(ctx context.Context, key string, body io.Reader, contentType string) error
+	// Download reads a whole object
+	Download(ctx context.Context, key string) ([]byte, error)
+	// ReadHead returns the first n bytes of an object, e.g. for content sniffing
+	ReadHead(ctx context.Context, key string, n int) ([]byte, error)
+	// Stat returns the size and content type of an object, or an error if it does not exist
+	Stat(ctx context.Context, key string) (*ObjectInfo, error)
+	// Delete removes an object
+	Delete(ctx context.Context, key string) error
+	// DeletePrefix removes every object whose key starts with prefix
+	DeletePrefix(ctx context.Context, prefix string) error
+	// List returns every object whose key starts with prefix (content types are not populated)
+	List(ctx context.Context, prefix string) ([]ObjectInfo, error)
+	// URL returns a URL clients can fetch the object from, valid for at least expiry
+	URL(ctx context.Context, key string, expiry time.Duration) (string, error)
+	// PresignUpload returns a URL and the headers a client must send to upload an object
+	// of the given type and size directly, bypassing the server
+	PresignUpload(ctx context.Context, key, contentType string, size int64, expiry time.Duration) (string, map[string]string, error)
+}
+
+// Config selects and configures a storage backend
+type Config struct {
+	Backend string
+
+	// Local disk
+	LocalDir       string
+	LocalURLPrefix string
+
+	// AWS S3 and S3-compatible endpoints (MinIO)
+	S3AccessKeyID     string
+	S3SecretAccessKey string
+	S3Region          string
+	S3Bucket          string
+	S3Endpoint        string
+	S3UsePathStyle    bool
+
+	// Google Cloud Storage (S3-compatible XML API with HMAC keys)
+	GCSAccessKeyID     string
+	GCSSecretAccessKey string
+	GCSBucket          string
+
+	// Azure Blob Storage
+	AzureAccountName string
+	AzureAccountKey  string
+	AzureContainer   string
+	AzureEndpoint    string
+}
+
+var store Storage
+
+// Init creates the configured storage backend
+func Init(cfg Config) error {
+	backend := cfg.Backend
+	if backend == "" || backend == BackendAuto {
+		backend = BackendLocal
+		if cfg.S3AccessKeyID != "" && cfg.S3SecretAccessKey != "" && cfg.S3Region != "" && cfg.S3Bucket != "" {
+			backend = BackendS3
+		}
+	}
+
+	var s Storage
+	var err error
+	switch backend {
+	case BackendLocal:
+		s, err = NewLocalStorage(cfg.LocalDir, cfg.LocalURLPrefix)
+	case BackendS3, BackendMinIO:
+		s, err = NewS3Storage(backend, S3Options{
+			AccessKeyID:     cfg.S3AccessKeyID,
+			SecretAccessKey: cfg.S3SecretAccessKey,
+			Region:          cfg.S3Region,
+			Bucket:          cfg.S3Bucket,
+			Endpoint:        cfg.S3Endpoint,
+			UsePathStyle:    cfg.S3UsePathStyle || backend == BackendMinIO,
+		})
+	case BackendGCS:
+		s, err = NewS3Storage(backend, S3Options{
+			AccessKeyID:     cfg.GCSAccessKeyID,
+			SecretAccessKey: cfg.GCSSecretAccessKey,
+			Region:          "auto",
+			Bucket:          cfg.GCSBucket,
+			Endpoint:        "https://storage.googleapis.com",
+		})
+	case BackendAzure:
+		s, err = NewAzureStorage(cfg.AzureAccountName, cfg.AzureAccountKey, cfg.AzureContainer, cfg.AzureEndpoint)
+	default:
+		return fmt.Errorf("unknown storage backend %q", backend)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to initialize %s storage: %w", backend, err)
+	}
+
+	store = s
+	storageLog.Info("✅ Photo storage: %s", s.Name())
+	return nil
+}
+
+// Get returns the initialized storage backend
+func Get() Storage {
+	return store
+}
@@ -0,0 +1,70 @@
+package storage
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	smithymiddleware "github.com/aws/smithy-go/middleware"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+	"github.com/nomdb/backend/internal/logger"
+	"github.com/nomdb/backend/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// s3TracingMiddleware wraps every S3 operation of a client in an OpenTelemetry span
+func s3TracingMiddleware(bucket string) func(*smithymiddleware.Stack) error {
+	return func(stack *smithymiddleware.Stack) error {
+		return stack.Initialize.Add(smithymiddleware.InitializeMiddlewareFunc("OpenTelemetrySpan",
+			func(ctx context.Context, in smithymiddleware.InitializeInput, next smithymiddleware.InitializeHandler) (
+				smithymiddleware.InitializeOutput, smithymiddleware.Metadata, error,
+			) {
+				operation := awsmiddleware.GetOperationName(ctx)
+				ctx, span := tracing.Tracer().Start(ctx, "s3."+operation,
+					trace.WithSpanKind(trace.SpanKindClient),
+					trace.WithAttributes(
+						attribute.String("rpc.system", "aws-api"),
+						attribute.String("rpc.service", "S3"),
+						attribute.String("rpc.method", operation),
+						attribute.String("aws.s3.bucket", bucket),
+					))
+				out, metadata, err := next.HandleInitialize(ctx, in)
+				tracing.EndSpan(span, err)
+				return out, metadata, err
+			}), smithymiddleware.Before)
+	}
+}
+
+// s3RequestIDMiddleware sends the ID of the API request along with S3 calls, for
+// S3-compatible servers that log request headers, such as MinIO in its audit log. It is
+// added after signing: the header stays unsigned, so presigned URLs do not require it.
+func s3RequestIDMiddleware(stack *smithymiddleware.Stack) error {
+	return stack.Finalize.Add(smithymiddleware.FinalizeMiddlewareFunc("RequestIDHeader",
+		func(ctx context.Context, in smithymiddleware.FinalizeInput, next smithymiddleware.FinalizeHandler) (
+			smithymiddleware.FinalizeOutput, smithymiddleware.Metadata, error,
+		) {
+			if req, ok := in.Request.(*smithyhttp.Request); ok {
+				if id := logger.RequestID(ctx); id != "" {
+					req.Header.Set(logger.RequestIDHeader, id)
+				}
+			}
+			return next.HandleFinalize(ctx, in)
+		}), smithymiddleware.After)
+}
+
+// azureClientRequestIDHeader is logged by Azure Storage for every request, and used
+// instead of a random ID when the client sets it
+const azureClientRequestIDHeader = "x-ms-client-request-id"
+
+// azureRequestIDPolicy sends the ID of the API request as the client request ID of
+// Azure Blob calls, so they can be found in the storage account's logs
+type azureRequestIDPolicy struct{}
+
+func (azureRequestIDPolicy) Do(req *policy.Request) (*http.Response, error) {
+	if id := logger.RequestID(req.Raw().Context()); id != "" {
+		req.Raw().Header.Set(azureClientRequestIDHeader, id)
+	}
+	return req.Next()
+}
@@ -0,0 +1,2338 @@
+// Code generated by cmd/openapi. DO NOT EDIT.
+// The Nom Database API 1.0 (OpenAPI 3.0.3)
+
+export interface ClientOptions {
+  /** Base URL of the API, including /api. Defaults to "/api" (same origin). */
+  baseUrl?: string;
+  /** fetch implementation, e.g. for Node.js < 18 or tests */
+  fetch?: typeof fetch;
+  /** Headers sent with every request, e.g. Authorization or X-API-Key */
+  headers?: Record<string, string> | (() => Record<string, string> | Promise<Record<string, string>>);
+  /** Credentials mode of requests; "include" sends cookies cross-origin */
+  credentials?: RequestCredentials;
+}
+
+/** A non-2xx response. problem holds the RFC 7807 problem details when the API sent them. */
+export class ApiError extends Error {
+  constructor(
+    readonly status: number,
+    readonly problem: ErrorResponse | undefined,
+    readonly response: Response,
+  ) {
+    super(problem?.detail || `HTTP ${status}`);
+    this.name = "ApiError";
+  }
+}
+
+type QueryValue = string | number | boolean | null | undefined;
+
+interface RequestParts {
+  query?: Record<string, QueryValue>;
+  headers?: Record<string, QueryValue>;
+  json?: unknown;
+  form?: Record<string, unknown>;
+}
+
+function newRequester(options: ClientOptions) {
+  const baseUrl = (options.baseUrl ?? "/api").replace(/\/$/, "");
+  const fetchImpl = options.fetch ?? globalThis.fetch.bind(globalThis);
+
+  return async (method: string, path: string, kind: "json" | "raw" | "none", init: RequestParts): Promise<unknown> => {
+    const query = new URLSearchParams();
+    for (const [name, value] of Object.entries(init.query ?? {})) {
+      if (value !== undefined && value !== null) query.set(name, String(value));
+    }
+    const headers: Record<string, string> = {
+      ...(typeof options.headers === "function" ? await options.headers() : options.headers),
+    };
+    for (const [name, value] of Object.entries(init.headers ?? {})) {
+      if (value !== undefined && value !== null) headers[name] = String(value);
+    }
+
+    let body: BodyInit | undefined;
+    if (init.json !== undefined) {
+      headers["Content-Type"] = "application/json";
+      body = JSON.stringify(init.json);
+    } else if (init.form !== undefined) {
+      const form = new FormData();
+      for (const [name, value] of Object.entries(init.form)) {
+        if (value === undefined || value === null) continue;
+        form.append(name, value instanceof Blob ? value : String(value));
+      }
+      body = form;
+    }
+
+    const search = query.toString();
+    const response = await fetchImpl(baseUrl + path + (search ? "?" + search : ""), {
+      method,
+      headers,
+      body,
+      credentials: options.credentials,
+    });
+    if (!response.ok) {
+      let problem: ErrorResponse | undefined;
+      try {
+        problem = (await response.clone().json()) as ErrorResponse;
+      } catch {
+        problem = undefined;
+      }
+      throw new ApiError(response.status, problem, response);
+    }
+    if (kind === "raw") return response;
+    if (kind === "none" || response.status === 204) return undefined;
+    return response.json();
+  };
+}
+
+export interface APIKey {
+  created_at?: string;
+  expires_at?: string;
+  id?: number;
+  is_active?: boolean;
+  key_prefix?: string;
+  last_used_at?: string;
+  name?: string;
+  /** Requests per minute, nil for the server default */
+  rate_limit?: number;
+  scopes?: string[];
+  user_id?: number;
+}
+
+export interface AccountExport {
+  api_keys?: (Record<string, unknown>)[];
+  badges?: (Record<string, unknown>)[];
+  exported_at?: string;
+  /** Users the user follows */
+  following?: (Record<string, unknown>)[];
+  photos?: (Record<string, unknown>)[];
+  ratings?: (Record<string, unknown>)[];
+  /** Restaurants created by the user */
+  restaurants?: (Record<string, unknown>)[];
+  sessions?: (Record<string, unknown>)[];
+  suggestion_events?: (Record<string, unknown>)[];
+  suggestions?: (Record<string, unknown>)[];
+  user?: User;
+}
+
+export interface ActivityItem {
+  /** The rating comment or suggestion notes */
+  comment?: string;
+  created_at?: string;
+  /** Of the rating or suggestion */
+  id?: number;
+  /** Of the restaurant or suggested restaurant */
+  name?: string;
+  /** The rated restaurant, nil for suggestions */
+  restaurant_id?: number;
+  /** Average of the food, service and ambiance ratings */
+  score?: number;
+  /** Of suggestions */
+  status?: string;
+  /** rating or suggestion */
+  type?: string;
+  user_id?: number;
+  username?: string;
+}
+
+export interface ApproveSuggestionRequest {
+  note?: string;
+}
+
+export interface AuditLogEntry {
+  actor_user_id?: number;
+  actor_username?: string;
+  created_at?: string;
+  details?: Record<string, unknown>;
+  event?: string;
+  id?: number;
+  ip_address?: string;
+  target_id?: string;
+  target_type?: string;
+  user_agent?: string;
+}
+
+export interface AuthProvider {
+  display_name?: string;
+  login_url?: string;
+  name?: string;
+  /** oidc, google, github */
+  type?: string;
+}
+
+export interface AuthProvidersResponse {
+  /** Email/password login is enabled */
+  local_login?: boolean;
+  providers?: AuthProvider[];
+  /** open, invite or closed */
+  registration?: string;
+}
+
+export interface AvgRating {
+  ambiance?: number;
+  count?: number;
+  food?: number;
+  overall?: number;
+  service?: number;
+}
+
+export interface BackupInfo {
+  created_at?: string;
+  key?: string;
+  rows?: number;
+  /** Only for a backup just taken */
+  schema_version?: number;
+  size?: number;
+  /** Rows per table */
+  tables?: Record<string, number>;
+}
+
+export interface BackupStatus {
+  key?: string;
+  /** started */
+  status?: string;
+}
+
+export interface Badge {
+  /** In the language of the request */
+  description?: string;
+  key?: string;
+  /** In the language of the request */
+  name?: string;
+}
+
+export interface CSRFTokenResponse {
+  csrf_token?: string;
+}
+
+export interface Category {
+  /** Hex color, e.g. #e4572e */
+  color?: string;
+  created_at?: string;
+  /** An emoji or a short icon name */
+  icon?: string;
+  id?: number;
+  name?: string;
+  /** Null for top-level categories */
+  parent_id?: number;
+  /** Restaurants in the category, in category lists only */
+  restaurant_count?: number;
+  updated_at?: string;
+}
+
+export interface CategoryNode {
+  children?: CategoryNode[];
+  /** Hex color, e.g. #e4572e */
+  color?: string;
+  created_at?: string;
+  /** An emoji or a short icon name */
+  icon?: string;
+  id?: number;
+  name?: string;
+  /** Null for top-level categories */
+  parent_id?: number;
+  /** Restaurants in the category, in category lists only */
+  restaurant_count?: number;
+  updated_at?: string;
+}
+
+export interface ConfirmPhotoUploadRequest {
+  /** Overrides the caption given at presign time */
+  caption?: string;
+  upload_token?: string;
+}
+
+export interface ContributionCounts {
+  /** Suggestions converted to restaurants */
+  converted_suggestions?: number;
+  photos?: number;
+  ratings?: number;
+  /** Including converted ones */
+  suggestions?: number;
+}
+
+export interface ConvertSuggestionRequest {
+  ambiance_rating?: number;
+  category_id?: number;
+  comment?: string;
+  description?: string;
+  food_rating?: number;
+  service_rating?: number;
+}
+
+export interface CreateAPIKeyRequest {
+  /** Never expires if omitted */
+  expires_in_days?: number;
+  name?: string;
+  /** Requests per minute */
+  rate_limit?: number;
+  /** read, write, admin; defaults to read */
+  scopes?: string[];
+}
+
+export interface CreateAPIKeyResponse {
+  api_key?: APIKey;
+  /** Only returned once */
+  key?: string;
+}
+
+export interface CreateCategoryRequest {
+  /** #rrggbb; empty removes the color, omitted keeps it on update */
+  color?: string;
+  /** Empty removes the icon; omitted keeps it on update */
+  icon?: string;
+  name?: string;
+  /** 0 moves a category to the top level; omitted keeps the parent on update */
+  parent_id?: number;
+}
+
+export interface CreateFoodTypeRequest {
+  /** #rrggbb; empty removes the color, omitted keeps it on update */
+  color?: string;
+  /** Empty removes the icon; omitted keeps it on update */
+  icon?: string;
+  name?: string;
+}
+
+export interface CreateInviteRequest {
+  /** Default 7, 0 for no expiry */
+  expires_in_days?: number;
+  /** Default 1 */
+  max_uses?: number;
+  note?: string;
+}
+
+export interface CreateInviteResponse {
+  code?: string;
+  invite?: Invite;
+  url?: string;
+}
+
+export interface CreateRatingRequest {
+  ambiance_rating?: number;
+  comment?: string;
+  food_rating?: number;
+  restaurant_id?: number;
+  service_rating?: number;
+}
+
+export interface CreateRestaurantClaimRequest {
+  /** For the admins, e.g. the user's role at the restaurant and how to verify it */
+  message?: string;
+}
+
+export interface CreateRestaurantRequest {
+  address?: string;
+  category_id?: number;
+  description?: string;
+  food_type_ids?: number[];
+  google_place_id?: string;
+  latitude?: number;
+  longitude?: number;
+  name?: string;
+  phone?: string;
+  website?: string;
+}
+
+export interface CreateServiceAccountRequest {
+  description?: string;
+  is_admin?: boolean;
+  /** Lowercase letters, digits, dashes and underscores */
+  name?: string;
+  rate_limit?: number;
+}
+
+export interface CreateSpaceInviteRequest {
+  /** Default 7, 0 for no expiry */
+  expires_in_days?: number;
+  /** Default 1 */
+  max_uses?: number;
+  /** Default member */
+  role?: string;
+}
+
+export interface CreateSpaceInviteResponse {
+  code?: string;
+  invite?: SpaceInvite;
+}
+
+export interface CreateSpaceRequest {
+  name?: string;
+  public?: boolean;
+  /** Lowercase letters, digits and dashes */
+  slug?: string;
+}
+
+export interface CreateSuggestionRequest {
+  address?: string;
+  food_type_ids?: number[];
+  google_place_id?: string;
+  latitude?: number;
+  longitude?: number;
+  name?: string;
+  notes?: string;
+  phone?: string;
+  suggested_category_id?: number;
+  website?: string;
+}
+
+export interface DeleteAccountRequest {
+  /** anonymize (default) keeps contributions without the user, purge deletes them */
+  mode?: string;
+  /** Required for accounts with a password */
+  password?: string;
+}
+
+export interface DeleteAccountResponse {
+  mode?: string;
+  /** When the account is permanently removed */
+  scheduled_at?: string;
+}
+
+export interface ErrorResponse {
+  /** Error code for programmatic handling */
+  code?: string;
+  /** User-friendly error message */
+  detail?: string;
+  /** Additional details (optional) */
+  details?: string;
+  /** Matches the X-Request-ID header and the server logs */
+  request_id?: string;
+  /** HTTP status code */
+  status?: number;
+  /** HTTP status text */
+  title?: string;
+  /** Always about:blank; Code identifies the error */
+  type?: string;
+}
+
+export interface Event {
+  /** Type-specific payload */
+  data?: Record<string, unknown>;
+  /** ID of the changed entity */
+  id?: number;
+  /** Cache keys of cache.invalidated */
+  keys?: string[];
+  /** Restaurant the entity belongs to */
+  restaurant_id?: number;
+  /** Space of the entity, 0 for data of every space */
+  space_id?: number;
+  /** e.g. restaurant.updated, photo.ready */
+  type?: string;
+}
+
+export interface ExternalSource {
+  categories?: string[];
+  external_id?: string;
+  fetched_at?: string;
+  /** Name of the matched business */
+  name?: string;
+  /** 1 (inexpensive) to 4 (very expensive) */
+  price_level?: number;
+  /** yelp, foursquare */
+  provider?: string;
+  /** 0 to 5 */
+  rating?: number;
+  /** Reviews the rating is based on */
+  rating_count?: number;
+  url?: string;
+}
+
+export interface FollowedUser {
+  avatar_url?: string;
+  followed_at?: string;
+  id?: number;
+  username?: string;
+}
+
+export interface FoodType {
+  /** Hex color, e.g. #e4572e */
+  color?: string;
+  created_at?: string;
+  /** An emoji or a short icon name */
+  icon?: string;
+  id?: number;
+  name?: string;
+  /** Restaurants serving the food type, in food type lists only */
+  restaurant_count?: number;
+  updated_at?: string;
+}
+
+export interface ForgotPasswordRequest {
+  email?: string;
+}
+
+export interface GoogleOpeningHours {
+  open_now?: boolean;
+  /** One line per day, e.g. "Monday: 11:00 AM – 10:00 PM" */
+  weekdays?: string[];
+}
+
+export interface GooglePlacePhoto {
+  /** Authors that must be credited when showing the photo */
+  attributions?: string[];
+  height?: number;
+  name?: string;
+  width?: number;
+}
+
+export interface GooglePlaceResult {
+  address?: string;
+  /** Only filled in by Google place details */
+  business_status?: string;
+  latitude?: number;
+  longitude?: number;
+  name?: string;
+  opening_hours?: GoogleOpeningHours;
+  phone?: string;
+  photos?: GooglePlacePhoto[];
+  place_id?: string;
+  /** 0 (free) to 4 (very expensive) */
+  price_level?: number;
+  /** Only filled in with the Places API (New), and opening hours by legacy place details */
+  rating?: number;
+  rating_count?: number;
+  website?: string;
+}
+
+export interface HealthCheck {
+  details?: string;
+  error?: string;
+  latency_ms?: number;
+  /** ok or error */
+  status?: string;
+}
+
+export interface HealthResponse {
+  checks?: Record<string, HealthCheck>;
+  /** ok, unavailable or shutting_down */
+  status?: string;
+}
+
+export interface Invite {
+  code_prefix?: string;
+  created_at?: string;
+  created_by_user_id?: number;
+  expires_at?: string;
+  id?: number;
+  max_uses?: number;
+  note?: string;
+  /** Usernames of the users who registered with the invite */
+  redeemed_by?: string[];
+  revoked_at?: string;
+  use_count?: number;
+}
+
+export interface JoinSpaceRequest {
+  code?: string;
+}
+
+export interface Leaderboard {
+  /** Suggestions converted to restaurants */
+  converted_suggestions?: LeaderboardEntry[];
+  /** month or all */
+  period?: string;
+  photos?: LeaderboardEntry[];
+  ratings?: LeaderboardEntry[];
+  /** Start of the period, nil for all time */
+  since?: string;
+}
+
+export interface LeaderboardEntry {
+  avatar_url?: string;
+  count?: number;
+  rank?: number;
+  user_id?: number;
+  username?: string;
+}
+
+export interface LogLevels {
+  /** Modules whose level can be set */
+  available_modules?: string[];
+  /** debug, info, warn or error */
+  level?: string;
+  /** e.g. {"photos": "debug"} */
+  modules?: Record<string, string>;
+}
+
+export interface LogLevelsRequest {
+  level?: string;
+  modules?: Record<string, string>;
+}
+
+export interface LoginRequest {
+  email?: string;
+  password?: string;
+  /** Keep the session for JWT_REMEMBER_ME_TTL instead of JWT_REFRESH_TOKEN_TTL */
+  remember_me?: boolean;
+}
+
+export interface LoginResponse {
+  access_token?: string;
+  /** seconds */
+  expires_in?: number;
+  /** Seconds until the refresh token expires; omitted on refresh, which keeps the session's expiry */
+  refresh_expires_in?: number;
+  /** Omitted when refreshed from the cookie */
+  refresh_token?: string;
+  token_type?: string;
+  user?: User;
+}
+
+export interface MapBoundsResponse {
+  clustered?: boolean;
+  clusters?: MapCluster[];
+  markers?: MapMarker[];
+  total?: number;
+}
+
+export interface MapCluster {
+  count?: number;
+  /** Centroid of the restaurants */
+  latitude?: number;
+  /** Centroid of the restaurants */
+  longitude?: number;
+  /** Set when the cell holds a single restaurant */
+  restaurant_id?: number;
+}
+
+export interface MapDirections {
+  apple?: string;
+  google?: string;
+}
+
+export interface MapMarker {
+  category_id?: number;
+  id?: number;
+  latitude?: number;
+  longitude?: number;
+  name?: string;
+  /** Overall average, omitted when unrated */
+  rating?: number;
+}
+
+export interface MapsQuota {
+  /** Calls per UTC day, 0 for no limit */
+  budget?: number;
+  /** Newest first */
+  days?: MapsUsageDay[];
+  /** Missing without a budget */
+  remaining?: number;
+  resets_at?: string;
+  /** Calls today */
+  used?: number;
+}
+
+export interface MapsUsageDay {
+  calls?: number;
+  /** YYYY-MM-DD */
+  date?: string;
+  /** Calls by operation, e.g. text_search */
+  operations?: Record<string, number>;
+}
+
+export interface MenuPhoto {
+  caption?: string;
+  created_at?: string;
+  file_size?: number;
+  filename?: string;
+  id?: number;
+  is_cover?: boolean;
+  mime_type?: string;
+  /** Reasons given by the moderation provider */
+  moderation_labels?: string[];
+  /** pending, flagged, approved */
+  moderation_status?: string;
+  original_filename?: string;
+  /** menu, food, interior, receipt */
+  photo_type?: string;
+  position?: number;
+  /** Set when status is failed */
+  processing_error?: string;
+  restaurant_id?: number;
+  /** processing, ready, failed */
+  status?: string;
+  updated_at?: string;
+  uploaded_by_user_id?: number;
+  /** Computed field */
+  url?: string;
+}
+
+export interface MergeFoodTypesRequest {
+  duplicate_ids?: number[];
+}
+
+export interface Meta {
+  /** Badges users can be awarded */
+  badges?: Badge[];
+  categories?: Category[];
+  food_types?: FoodType[];
+  rating_dimensions?: RatingDimension[];
+}
+
+export interface MetricsHistoryResponse {
+  /** When the counters last started from zero */
+  counting_since?: string;
+  snapshots?: MetricsSnapshot[];
+  window?: string;
+}
+
+export interface MetricsSnapshot {
+  avg_response_time_ms?: number;
+  errors?: number;
+  /** Set in cluster mode */
+  instance?: string;
+  p50_response_time_ms?: number;
+  p95_response_time_ms?: number;
+  p99_response_time_ms?: number;
+  recorded_at?: string;
+  requests?: number;
+  requests_by_status?: Record<string, number>;
+  total_errors?: number;
+  total_requests?: number;
+}
+
+export interface ModeratePhotoRequest {
+  /** approve, reject */
+  action?: string;
+}
+
+export interface OwnerResponse {
+  responded_at?: string;
+  response?: string;
+}
+
+export interface OwnerResponseRequest {
+  response?: string;
+}
+
+export interface PaginatedResponse {
+  data?: unknown;
+  has_more?: boolean;
+  next_cursor?: string;
+  total?: number;
+}
+
+export interface PhotoCleanupReport {
+  deleted_objects?: number;
+  /** Only with remove_missing */
+  deleted_photos?: number;
+  dry_run?: boolean;
+  /** Expired pending direct uploads removed */
+  expired_uploads?: number;
+  finished_at?: string;
+  /** IDs of photos whose original file is missing */
+  missing_files?: number[];
+  objects_scanned?: number;
+  /** Stored files with no matching photo */
+  orphaned_objects?: string[];
+  started_at?: string;
+}
+
+export interface PhotoEvent {
+  error?: string;
+  photo_id?: number;
+  restaurant_id?: number;
+  status?: string;
+  /** photo.ready, photo.failed */
+  type?: string;
+}
+
+export interface PlaceChange {
+  created_at?: string;
+  /** phone, website, business_status or opening_hours */
+  field?: string;
+  id?: number;
+  new_value?: Record<string, unknown>;
+  /** String, or array of strings for opening_hours; null when unset */
+  old_value?: Record<string, unknown>;
+  restaurant_id?: number;
+  restaurant_name?: string;
+  reviewed_at?: string;
+  reviewed_by_user_id?: number;
+  /** pending, accepted or rejected */
+  status?: string;
+}
+
+export interface PlaceSyncReport {
+  /** Pending changes recorded */
+  changes?: number;
+  /** Restaurants whose place details were fetched */
+  checked?: number;
+  /** Restaurants whose place details could not be fetched */
+  failed?: number;
+  finished_at?: string;
+  started_at?: string;
+}
+
+export interface PresignPhotoUploadRequest {
+  caption?: string;
+  content_type?: string;
+  file_size?: number;
+  filename?: string;
+  /** Defaults to menu */
+  photo_type?: string;
+}
+
+export interface PresignPhotoUploadResponse {
+  expires_at?: string;
+  /** Headers the client must send with the PUT */
+  headers?: Record<string, string>;
+  method?: string;
+  upload_token?: string;
+  upload_url?: string;
+}
+
+export interface Rating {
+  ambiance_rating?: number;
+  comment?: string;
+  created_at?: string;
+  food_rating?: number;
+  id?: number;
+  /** Official response of the restaurant's owner, nil if none */
+  owner_response?: OwnerResponse;
+  restaurant_id?: number;
+  service_rating?: number;
+  /** Author, nil for ratings created before authentication */
+  user_id?: number;
+}
+
+export interface RatingDimension {
+  /** The rating field is <key>_rating */
+  key?: string;
+  max?: number;
+  min?: number;
+  /** In the language of the request */
+  name?: string;
+}
+
+export interface RefreshTokenRequest {
+  refresh_token?: string;
+}
+
+export interface RegisterRequest {
+  email?: string;
+  full_name?: string;
+  /** Required when REGISTRATION_MODE=invite */
+  invite_code?: string;
+  password?: string;
+  username?: string;
+}
+
+export interface ReorderPhotosRequest {
+  /** Every photo of the restaurant, in display order */
+  photo_ids?: number[];
+}
+
+export interface ResetPasswordRequest {
+  password?: string;
+  token?: string;
+}
+
+export interface Restaurant {
+  address?: string;
+  avg_rating?: AvgRating;
+  /** From the place sync, e.g. CLOSED_PERMANENTLY */
+  business_status?: string;
+  category?: Category;
+  category_id?: number;
+  cover_photo?: MenuPhoto;
+  created_at?: string;
+  description?: string;
+  /** Distance in km from search location */
+  distance?: number;
+  /** Yelp or Foursquare data, on the restaurant detail */
+  external_sources?: ExternalSource[];
+  food_types?: FoodType[];
+  google_place_id?: string;
+  id?: number;
+  /** Indicates if this is from suggestions table */
+  is_suggestion?: boolean;
+  latitude?: number;
+  longitude?: number;
+  name?: string;
+  /** From the place sync, one line per day */
+  opening_hours?: string[];
+  phone?: string;
+  /** For suggestions: pending, approved, tested, rejected */
+  status?: string;
+  suggestion_id?: number;
+  /** Travel time from the search location, when requested */
+  travel_minutes?: number;
+  updated_at?: string;
+  website?: string;
+}
+
+export interface RestaurantClaim {
+  created_at?: string;
+  id?: number;
+  message?: string;
+  restaurant_id?: number;
+  restaurant_name?: string;
+  reviewed_at?: string;
+  reviewed_by_user_id?: number;
+  /** pending, approved or rejected */
+  status?: string;
+  user_id?: number;
+  username?: string;
+}
+
+export interface RestaurantMap {
+  directions?: MapDirections;
+  /** Static map served by the API; missing when Google Maps is not configured */
+  image_url?: string;
+}
+
+export interface RestaurantSuggestion {
+  address?: string;
+  category?: Category;
+  created_at?: string;
+  food_types?: FoodType[];
+  google_place_id?: string;
+  id?: number;
+  latitude?: number;
+  longitude?: number;
+  name?: string;
+  notes?: string;
+  phone?: string;
+  status?: string;
+  suggested_category_id?: number;
+  updated_at?: string;
+  /** Author, nil for anonymous suggestions */
+  user_id?: number;
+  website?: string;
+}
+
+export interface ReviewPlaceChangeRequest {
+  /** accept, reject */
+  action?: string;
+}
+
+export interface ReviewRestaurantClaimRequest {
+  /** approve, reject */
+  action?: string;
+}
+
+export interface ServiceAccount {
+  api_key_count?: number;
+  created_at?: string;
+  description?: string;
+  id?: number;
+  is_active?: boolean;
+  is_admin?: boolean;
+  /** Last use of any of its API keys */
+  last_used_at?: string;
+  name?: string;
+  /** Requests per minute across all keys, nil for the server default */
+  rate_limit?: number;
+}
+
+export interface Setting {
+  /** From the startup configuration */
+  default?: unknown;
+  description?: string;
+  key?: string;
+  max?: number;
+  /** Bounds of int settings */
+  min?: number;
+  /** Allowed values of string settings */
+  options?: string[];
+  overridden?: boolean;
+  /** int, bool or string */
+  type?: string;
+  updated_at?: string;
+  /** Set while overridden */
+  updated_by_user_id?: number;
+  /** Effective value */
+  value?: unknown;
+}
+
+export interface SettingRequest {
+  value?: unknown;
+}
+
+export interface Space {
+  created_at?: string;
+  created_by_user_id?: number;
+  id?: number;
+  name?: string;
+  /** Readable by everyone, not only members */
+  public?: boolean;
+  /** Of the current user, nil if not a member */
+  role?: string;
+  slug?: string;
+  updated_at?: string;
+}
+
+export interface SpaceInvite {
+  code_prefix?: string;
+  created_at?: string;
+  created_by_user_id?: number;
+  expires_at?: string;
+  id?: number;
+  max_uses?: number;
+  revoked_at?: string;
+  /** admin or member */
+  role?: string;
+  space_id?: number;
+  use_count?: number;
+}
+
+export interface SpaceMember {
+  created_at?: string;
+  /** owner, admin or member */
+  role?: string;
+  user_id?: number;
+  username?: string;
+}
+
+export interface SpaceMemberRequest {
+  role?: string;
+}
+
+export interface SuggestionApproval {
+  created_at?: string;
+  id?: number;
+  note?: string;
+  suggestion_id?: number;
+  user_id?: number;
+  username?: string;
+}
+
+export interface SuggestionApprovalStatus {
+  approvals?: SuggestionApproval[];
+  can_convert?: boolean;
+  mode?: string;
+  required_approvals?: number;
+  suggestion_id?: number;
+}
+
+export interface SuggestionEvent {
+  created_at?: string;
+  from_status?: string;
+  id?: number;
+  note?: string;
+  suggestion_id?: number;
+  to_status?: string;
+  user_id?: number;
+  username?: string;
+}
+
+export interface Translation {
+  locale?: string;
+  name?: string;
+  updated_at?: string;
+}
+
+export interface TranslationRequest {
+  name?: string;
+}
+
+export interface UpdateRestaurantBusinessRequest {
+  /** One line per day; an empty list clears them */
+  opening_hours?: string[];
+  phone?: string;
+  website?: string;
+}
+
+export interface UpdateRestaurantRequest {
+  address?: string;
+  category_id?: number;
+  description?: string;
+  food_type_ids?: number[];
+  google_place_id?: string;
+  latitude?: number;
+  longitude?: number;
+  name?: string;
+  phone?: string;
+  website?: string;
+}
+
+export interface UpdateSpaceRequest {
+  name?: string;
+  public?: boolean;
+}
+
+export interface UpdateSuggestionStatusRequest {
+  note?: string;
+  status?: string;
+}
+
+export interface UploadPhotoFromURLRequest {
+  caption?: string;
+  /** Defaults to menu */
+  photo_type?: string;
+  url?: string;
+}
+
+export interface UploadPhotoResponse {
+  photo?: MenuPhoto;
+}
+
+export interface User {
+  avatar_url?: string;
+  created_at?: string;
+  email?: string;
+  email_verified?: boolean;
+  full_name?: string;
+  id?: number;
+  is_active?: boolean;
+  is_admin?: boolean;
+  last_login_at?: string;
+  provider?: string;
+  provider_id?: string;
+  updated_at?: string;
+  username?: string;
+}
+
+export interface UserBadge {
+  awarded_at?: string;
+  /** In the language of the request */
+  description?: string;
+  key?: string;
+  /** In the language of the request */
+  name?: string;
+}
+
+export interface UserStats {
+  avatar_url?: string;
+  /** Awarded for contributions in any space */
+  badges?: UserBadge[];
+  member_since?: string;
+  /** Since the start of the month (UTC) */
+  this_month?: ContributionCounts;
+  total?: ContributionCounts;
+  user_id?: number;
+  username?: string;
+}
+
+export interface Webhook {
+  active?: boolean;
+  created_at?: string;
+  created_by_user_id?: number;
+  description?: string;
+  /** restaurant.created, rating.created, suggestion.converted, photo.uploaded */
+  events?: string[];
+  id?: number;
+  /** Only returned when the webhook is created */
+  secret?: string;
+  updated_at?: string;
+  url?: string;
+}
+
+export interface WebhookDelivery {
+  attempts?: number;
+  created_at?: string;
+  delivered_at?: string;
+  event?: string;
+  id?: number;
+  last_error?: string;
+  /** Response status of the last attempt */
+  last_status_code?: number;
+  /** Only while pending */
+  next_attempt_at?: string;
+  /** Request body */
+  payload?: Record<string, unknown>;
+  /** pending, delivered or failed */
+  status?: string;
+  webhook_id?: number;
+}
+
+export interface WebhookRequest {
+  /** Defaults to true */
+  active?: boolean;
+  description?: string;
+  events?: string[];
+  /** Generated when empty on creation, kept when empty on update */
+  secret?: string;
+  url?: string;
+}
+
+/** Creates a client of the API. Every method rejects with an ApiError on non-2xx responses. */
+export function createClient(options: ClientOptions = {}) {
+  const request = newRequester(options);
+  return {
+    /** Get the audit log */
+    getAuditLog: (
+      args: {
+        /** Comma-separated events, e.g. login_failed,role_granted */
+        event?: string;
+        /** Acting user ID */
+        user_id?: number;
+        /** Target type, e.g. user */
+        target_type?: string;
+        /** Target ID */
+        target_id?: string;
+        /** Client IP address */
+        ip?: string;
+        /** Only events at or after this time (RFC 3339) */
+        from?: string;
+        /** Only events before this time (RFC 3339) */
+        to?: string;
+        /** Number of entries per page (default: 20, max: 100) */
+        limit?: number;
+        /** Pagination cursor from X-Next-Cursor */
+        cursor?: string;
+      } = {},
+    ): Promise<AuditLogEntry[]> =>
+      request("GET", `/admin/audit-log`, "json", { query: { "event": args["event"], "user_id": args["user_id"], "target_type": args["target_type"], "target_id": args["target_id"], "ip": args["ip"], "from": args["from"], "to": args["to"], "limit": args["limit"], "cursor": args["cursor"] } }) as Promise<AuditLogEntry[]>,
+    /** Back up the database */
+    createBackup: (): Promise<BackupStatus> =>
+      request("POST", `/admin/backup`, "json", {}) as Promise<BackupStatus>,
+    /** List database backups */
+    listBackups: (): Promise<BackupInfo[]> =>
+      request("GET", `/admin/backups`, "json", {}) as Promise<BackupInfo[]>,
+    /** List invites */
+    listInvites: (): Promise<Invite[]> =>
+      request("GET", `/admin/invites`, "json", {}) as Promise<Invite[]>,
+    /** Create an invite */
+    createInvite: (
+      args: {
+        body: CreateInviteRequest;
+      },
+    ): Promise<CreateInviteResponse> =>
+      request("POST", `/admin/invites`, "json", { json: args.body }) as Promise<CreateInviteResponse>,
+    /** Revoke an invite */
+    revokeInvite: (
+      args: {
+        /** Invite ID */
+        id: number;
+      },
+    ): Promise<void> =>
+      request("DELETE", `/admin/invites/${encodeURIComponent(String(args["id"]))}`, "none", {}) as Promise<void>,
+    /** Get log levels */
+    getLogLevels: (): Promise<LogLevels> =>
+      request("GET", `/admin/log-level`, "json", {}) as Promise<LogLevels>,
+    /** Set log levels */
+    updateLogLevels: (
+      args: {
+        body: LogLevelsRequest;
+      },
+    ): Promise<LogLevels> =>
+      request("PUT", `/admin/log-level`, "json", { json: args.body }) as Promise<LogLevels>,
+    /** Reconcile photo storage */
+    cleanupPhotoStorage: (
+      args: {
+        /** Only report, do not delete anything */
+        dry_run?: boolean;
+        /** Also delete photos whose original file is missing */
+        remove_missing?: boolean;
+      } = {},
+    ): Promise<PhotoCleanupReport> =>
+      request("POST", `/admin/photos/cleanup`, "json", { query: { "dry_run": args["dry_run"], "remove_missing": args["remove_missing"] } }) as Promise<PhotoCleanupReport>,
+    /** List the photo moderation queue */
+    getPhotoModerationQueue: (
+      args: {
+        /** Filter by moderation status (pending, flagged) */
+        status?: string;
+      } = {},
+    ): Promise<MenuPhoto[]> =>
+      request("GET", `/admin/photos/moderation`, "json", { query: { "status": args["status"] } }) as Promise<MenuPhoto[]>,
+    /** Moderate a photo */
+    moderatePhoto: (
+      args: {
+        /** Photo ID */
+        id: number;
+        body: ModeratePhotoRequest;
+      },
+    ): Promise<MenuPhoto> =>
+      request("POST", `/admin/photos/${encodeURIComponent(String(args["id"]))}/moderation`, "json", { json: args.body }) as Promise<MenuPhoto>,
+    /** List place changes */
+    getPlaceChanges: (
+      args: {
+        /** Filter by status (pending, accepted, rejected) */
+        status?: string;
+      } = {},
+    ): Promise<PlaceChange[]> =>
+      request("GET", `/admin/place-changes`, "json", { query: { "status": args["status"] } }) as Promise<PlaceChange[]>,
+    /** Review a place change */
+    reviewPlaceChange: (
+      args: {
+        /** Place change ID */
+        id: number;
+        body: ReviewPlaceChangeRequest;
+      },
+    ): Promise<PlaceChange> =>
+      request("POST", `/admin/place-changes/${encodeURIComponent(String(args["id"]))}`, "json", { json: args.body }) as Promise<PlaceChange>,
+    /** Sync restaurants with their places */
+    syncPlaces: (
+      args: {
+        /** Restaurants to sync (default 50, max 1000) */
+        limit?: number;
+      } = {},
+    ): Promise<PlaceSyncReport> =>
+      request("POST", `/admin/places/sync`, "json", { query: { "limit": args["limit"] } }) as Promise<PlaceSyncReport>,
+    /** Get Google Maps usage */
+    getMapsQuota: (
+      args: {
+        /** Days of history, today included (default 30, max 366) */
+        days?: number;
+      } = {},
+    ): Promise<MapsQuota> =>
+      request("GET", `/admin/quota`, "json", { query: { "days": args["days"] } }) as Promise<MapsQuota>,
+    /** List restaurant claims */
+    getRestaurantClaims: (
+      args: {
+        /** Filter by status (pending, approved, rejected) */
+        status?: string;
+      } = {},
+    ): Promise<RestaurantClaim[]> =>
+      request("GET", `/admin/restaurant-claims`, "json", { query: { "status": args["status"] } }) as Promise<RestaurantClaim[]>,
+    /** Review a restaurant claim */
+    reviewRestaurantClaim: (
+      args: {
+        /** Claim ID */
+        id: number;
+        body: ReviewRestaurantClaimRequest;
+      },
+    ): Promise<RestaurantClaim> =>
+      request("POST", `/admin/restaurant-claims/${encodeURIComponent(String(args["id"]))}`, "json", { json: args.body }) as Promise<RestaurantClaim>,
+    /** Fetch review site data of a restaurant */
+    fetchExternalSource: (
+      args: {
+        /** Restaurant ID */
+        id: number;
+      },
+    ): Promise<ExternalSource> =>
+      request("POST", `/admin/restaurants/${encodeURIComponent(String(args["id"]))}/external-sources`, "json", {}) as Promise<ExternalSource>,
+    /** Remove review site data of a restaurant */
+    deleteExternalSource: (
+      args: {
+        /** Restaurant ID */
+        id: number;
+        /** Review site (yelp, foursquare) */
+        provider: string;
+      },
+    ): Promise<void> =>
+      request("DELETE", `/admin/restaurants/${encodeURIComponent(String(args["id"]))}/external-sources/${encodeURIComponent(String(args["provider"]))}`, "none", {}) as Promise<void>,
+    /** List service accounts */
+    listServiceAccounts: (): Promise<ServiceAccount[]> =>
+      request("GET", `/admin/service-accounts`, "json", {}) as Promise<ServiceAccount[]>,
+    /** Create a service account */
+    createServiceAccount: (
+      args: {
+        body: CreateServiceAccountRequest;
+      },
+    ): Promise<ServiceAccount> =>
+      request("POST", `/admin/service-accounts`, "json", { json: args.body }) as Promise<ServiceAccount>,
+    /** Disable a service account */
+    disableServiceAccount: (
+      args: {
+        /** Service account ID */
+        id: number;
+      },
+    ): Promise<void> =>
+      request("DELETE", `/admin/service-accounts/${encodeURIComponent(String(args["id"]))}`, "none", {}) as Promise<void>,
+    /** List service account API keys */
+    listServiceAccountAPIKeys: (
+      args: {
+        /** Service account ID */
+        id: number;
+      },
+    ): Promise<APIKey[]> =>
+      request("GET", `/admin/service-accounts/${encodeURIComponent(String(args["id"]))}/keys`, "json", {}) as Promise<APIKey[]>,
+    /** Create a service account API key */
+    createServiceAccountAPIKey: (
+      args: {
+        /** Service account ID */
+        id: number;
+        body: CreateAPIKeyRequest;
+      },
+    ): Promise<CreateAPIKeyResponse> =>
+      request("POST", `/admin/service-accounts/${encodeURIComponent(String(args["id"]))}/keys`, "json", { json: args.body }) as Promise<CreateAPIKeyResponse>,
+    /** Revoke a service account API key */
+    deleteServiceAccountAPIKey: (
+      args: {
+        /** Service account ID */
+        id: number;
+        /** API key ID */
+        keyId: number;
+      },
+    ): Promise<void> =>
+      request("DELETE", `/admin/service-accounts/${encodeURIComponent(String(args["id"]))}/keys/${encodeURIComponent(String(args["keyId"]))}`, "none", {}) as Promise<void>,
+    /** List settings */
+    listSettings: (): Promise<Setting[]> =>
+      request("GET", `/admin/settings`, "json", {}) as Promise<Setting[]>,
+    /** Change a setting */
+    updateSetting: (
+      args: {
+        /** Setting key */
+        key: string;
+        body: SettingRequest;
+      },
+    ): Promise<Setting> =>
+      request("PUT", `/admin/settings/${encodeURIComponent(String(args["key"]))}`, "json", { json: args.body }) as Promise<Setting>,
+    /** Reset a setting */
+    resetSetting: (
+      args: {
+        /** Setting key */
+        key: string;
+      },
+    ): Promise<Setting> =>
+      request("DELETE", `/admin/settings/${encodeURIComponent(String(args["key"]))}`, "json", {}) as Promise<Setting>,
+    /** Restore a deleted account */
+    restoreUser: (
+      args: {
+        /** User ID */
+        id: number;
+      },
+    ): Promise<void> =>
+      request("POST", `/admin/users/${encodeURIComponent(String(args["id"]))}/restore`, "none", {}) as Promise<void>,
+    /** Unlock a user account */
+    unlockUser: (
+      args: {
+        /** User ID */
+        id: number;
+      },
+    ): Promise<void> =>
+      request("POST", `/admin/users/${encodeURIComponent(String(args["id"]))}/unlock`, "none", {}) as Promise<void>,
+    /** List webhooks */
+    listWebhooks: (): Promise<Webhook[]> =>
+      request("GET", `/admin/webhooks`, "json", {}) as Promise<Webhook[]>,
+    /** Register a webhook */
+    createWebhook: (
+      args: {
+        body: WebhookRequest;
+      },
+    ): Promise<Webhook> =>
+      request("POST", `/admin/webhooks`, "json", { json: args.body }) as Promise<Webhook>,
+    /** Change a webhook */
+    updateWebhook: (
+      args: {
+        /** Webhook ID */
+        id: number;
+        body: WebhookRequest;
+      },
+    ): Promise<Webhook> =>
+      request("PUT", `/admin/webhooks/${encodeURIComponent(String(args["id"]))}`, "json", { json: args.body }) as Promise<Webhook>,
+    /** Delete a webhook */
+    deleteWebhook: (
+      args: {
+        /** Webhook ID */
+        id: number;
+      },
+    ): Promise<void> =>
+      request("DELETE", `/admin/webhooks/${encodeURIComponent(String(args["id"]))}`, "none", {}) as Promise<void>,
+    /** List deliveries of a webhook */
+    getWebhookDeliveries: (
+      args: {
+        /** Webhook ID */
+        id: number;
+        /** Filter by status (pending, delivered, failed) */
+        status?: string;
+        /** Deliveries to return (default 50, max 200) */
+        limit?: number;
+      },
+    ): Promise<WebhookDelivery[]> =>
+      request("GET", `/admin/webhooks/${encodeURIComponent(String(args["id"]))}/deliveries`, "json", { query: { "status": args["status"], "limit": args["limit"] } }) as Promise<WebhookDelivery[]>,
+    /** Get a CSRF token */
+    getCSRFToken: (): Promise<CSRFTokenResponse> =>
+      request("GET", `/auth/csrf`, "json", {}) as Promise<CSRFTokenResponse>,
+    /** Request a password reset */
+    forgotPassword: (
+      args: {
+        body: ForgotPasswordRequest;
+      },
+    ): Promise<Response> =>
+      request("POST", `/auth/forgot-password`, "raw", { json: args.body }) as Promise<Response>,
+    /** Login */
+    login: (
+      args: {
+        body: LoginRequest;
+      },
+    ): Promise<LoginResponse> =>
+      request("POST", `/auth/login`, "json", { json: args.body }) as Promise<LoginResponse>,
+    /** Logout */
+    logout: (
+      args: {
+        body?: RefreshTokenRequest;
+      } = {},
+    ): Promise<string> =>
+      request("POST", `/auth/logout`, "json", { json: args.body }) as Promise<string>,
+    /** Get current user */
+    getMe: (): Promise<User> =>
+      request("GET", `/auth/me`, "json", {}) as Promise<User>,
+    /** Delete my account */
+    deleteMyAccount: (
+      args: {
+        body: DeleteAccountRequest;
+      },
+    ): Promise<DeleteAccountResponse> =>
+      request("POST", `/auth/me/delete`, "json", { json: args.body }) as Promise<DeleteAccountResponse>,
+    /** Export my data */
+    exportMyAccount: (): Promise<AccountExport> =>
+      request("GET", `/auth/me/export`, "json", {}) as Promise<AccountExport>,
+    /** List followed users */
+    getFollowing: (): Promise<FollowedUser[]> =>
+      request("GET", `/auth/me/following`, "json", {}) as Promise<FollowedUser[]>,
+    /** List my restaurant claims */
+    getMyRestaurantClaims: (): Promise<RestaurantClaim[]> =>
+      request("GET", `/auth/me/restaurant-claims`, "json", {}) as Promise<RestaurantClaim[]>,
+    /** OIDC callback */
+    oidcCallback: (
+      args: {
+        /** Authorization code */
+        code: string;
+        /** OIDC state */
+        state: string;
+      },
+    ): Promise<LoginResponse> =>
+      request("GET", `/auth/oidc/callback`, "json", { query: { "code": args["code"], "state": args["state"] } }) as Promise<LoginResponse>,
+    /** OIDC login */
+    oidcLogin: (
+      args: {
+        /** Invite code, required to create an account when REGISTRATION_MODE=invite */
+        invite?: string;
+      } = {},
+    ): Promise<void> =>
+      request("GET", `/auth/oidc/login`, "none", { query: { "invite": args["invite"] } }) as Promise<void>,
+    /** OIDC callback */
+    getAuthOidcByProviderCallback: (
+      args: {
+        /** Provider name */
+        provider: string;
+        /** Authorization code */
+        code: string;
+        /** OIDC state */
+        state: string;
+      },
+    ): Promise<LoginResponse> =>
+      request("GET", `/auth/oidc/${encodeURIComponent(String(args["provider"]))}/callback`, "json", { query: { "code": args["code"], "state": args["state"] } }) as Promise<LoginResponse>,
+    /** OIDC login */
+    getAuthOidcByProviderLogin: (
+      args: {
+        /** Provider name from /auth/providers */
+        provider: string;
+        /** Invite code, required to create an account when REGISTRATION_MODE=invite */
+        invite?: string;
+      },
+    ): Promise<void> =>
+      request("GET", `/auth/oidc/${encodeURIComponent(String(args["provider"]))}/login`, "none", { query: { "invite": args["invite"] } }) as Promise<void>,
+    /** List login providers */
+    getAuthProviders: (): Promise<AuthProvidersResponse> =>
+      request("GET", `/auth/providers`, "json", {}) as Promise<AuthProvidersResponse>,
+    /** Refresh token */
+    refreshToken: (
+      args: {
+        body?: RefreshTokenRequest;
+      } = {},
+    ): Promise<LoginResponse> =>
+      request("POST", `/auth/refresh`, "json", { json: args.body }) as Promise<LoginResponse>,
+    /** Register a new user */
+    register: (
+      args: {
+        body: RegisterRequest;
+      },
+    ): Promise<LoginResponse> =>
+      request("POST", `/auth/register`, "json", { json: args.body }) as Promise<LoginResponse>,
+    /** Reset password */
+    resetPassword: (
+      args: {
+        body: ResetPasswordRequest;
+      },
+    ): Promise<Response> =>
+      request("POST", `/auth/reset-password`, "raw", { json: args.body }) as Promise<Response>,
+    /** List all categories */
+    getCategories: (): Promise<Category[]> =>
+      request("GET", `/categories`, "json", {}) as Promise<Category[]>,
+    /** Create a new category */
+    createCategory: (
+      args: {
+        body: CreateCategoryRequest;
+      },
+    ): Promise<Category> =>
+      request("POST", `/categories`, "json", { json: args.body }) as Promise<Category>,
+    /** Get the category tree */
+    getCategoryTree: (): Promise<CategoryNode[]> =>
+      request("GET", `/categories/tree`, "json", {}) as Promise<CategoryNode[]>,
+    /** Get a category by ID */
+    getCategory: (
+      args: {
+        /** Category ID */
+        id: number;
+      },
+    ): Promise<Category> =>
+      request("GET", `/categories/${encodeURIComponent(String(args["id"]))}`, "json", {}) as Promise<Category>,
+    /** Update a category */
+    updateCategory: (
+      args: {
+        /** Category ID */
+        id: number;
+        body: CreateCategoryRequest;
+      },
+    ): Promise<Category> =>
+      request("PUT", `/categories/${encodeURIComponent(String(args["id"]))}`, "json", { json: args.body }) as Promise<Category>,
+    /** Delete a category */
+    deleteCategory: (
+      args: {
+        /** Category ID */
+        id: number;
+        /** Category to move the restaurants and suggestions of the deleted category to */
+        reassign_to?: number;
+      },
+    ): Promise<void> =>
+      request("DELETE", `/categories/${encodeURIComponent(String(args["id"]))}`, "none", { query: { "reassign_to": args["reassign_to"] } }) as Promise<void>,
+    /** List translations of a category */
+    getCategoryTranslations: (
+      args: {
+        /** Category ID */
+        id: number;
+      },
+    ): Promise<Translation[]> =>
+      request("GET", `/categories/${encodeURIComponent(String(args["id"]))}/translations`, "json", {}) as Promise<Translation[]>,
+    /** Set the translation of a category */
+    setCategoryTranslation: (
+      args: {
+        /** Category ID */
+        id: number;
+        /** Language, e.g. de */
+        locale: string;
+        body: TranslationRequest;
+      },
+    ): Promise<Translation> =>
+      request("PUT", `/categories/${encodeURIComponent(String(args["id"]))}/translations/${encodeURIComponent(String(args["locale"]))}`, "json", { json: args.body }) as Promise<Translation>,
+    /** Delete the translation of a category */
+    deleteCategoryTranslation: (
+      args: {
+        /** Category ID */
+        id: number;
+        /** Language, e.g. de */
+        locale: string;
+      },
+    ): Promise<void> =>
+      request("DELETE", `/categories/${encodeURIComponent(String(args["id"]))}/translations/${encodeURIComponent(String(args["locale"]))}`, "none", {}) as Promise<void>,
+    /** Stream live updates */
+    streamEvents: (
+      args: {
+        /** Only events of this restaurant */
+        restaurant_id?: number;
+      } = {},
+    ): Promise<Response> =>
+      request("GET", `/events`, "raw", { query: { "restaurant_id": args["restaurant_id"] } }) as Promise<Response>,
+    /** Get feature flags */
+    getFeatures: (): Promise<Record<string, boolean>> =>
+      request("GET", `/features`, "json", {}) as Promise<Record<string, boolean>>,
+    /** Get the activity feed */
+    getFeed: (
+      args: {
+        /** Only activity of followed users */
+        following?: boolean;
+        /** Only activity created before this time (RFC 3339) */
+        before?: string;
+        /** Number of items (default 20, max 100) */
+        limit?: number;
+      } = {},
+    ): Promise<ActivityItem[]> =>
+      request("GET", `/feed`, "json", { query: { "following": args["following"], "before": args["before"], "limit": args["limit"] } }) as Promise<ActivityItem[]>,
+    /** List all food types */
+    getFoodTypes: (): Promise<FoodType[]> =>
+      request("GET", `/food-types`, "json", {}) as Promise<FoodType[]>,
+    /** Create a new food type */
+    createFoodType: (
+      args: {
+        body: CreateFoodTypeRequest;
+      },
+    ): Promise<FoodType> =>
+      request("POST", `/food-types`, "json", { json: args.body }) as Promise<FoodType>,
+    /** Get a food type by ID */
+    getFoodType: (
+      args: {
+        /** Food Type ID */
+        id: number;
+      },
+    ): Promise<FoodType> =>
+      request("GET", `/food-types/${encodeURIComponent(String(args["id"]))}`, "json", {}) as Promise<FoodType>,
+    /** Update a food type */
+    updateFoodType: (
+      args: {
+        /** Food Type ID */
+        id: number;
+        body: CreateFoodTypeRequest;
+      },
+    ): Promise<FoodType> =>
+      request("PUT", `/food-types/${encodeURIComponent(String(args["id"]))}`, "json", { json: args.body }) as Promise<FoodType>,
+    /** Delete a food type */
+    deleteFoodType: (
+      args: {
+        /** Food Type ID */
+        id: number;
+      },
+    ): Promise<void> =>
+      request("DELETE", `/food-types/${encodeURIComponent(String(args["id"]))}`, "none", {}) as Promise<void>,
+    /** Merge duplicate food types */
+    mergeFoodTypes: (
+      args: {
+        /** Food type to keep */
+        id: number;
+        body: MergeFoodTypesRequest;
+      },
+    ): Promise<FoodType> =>
+      request("POST", `/food-types/${encodeURIComponent(String(args["id"]))}/merge`, "json", { json: args.body }) as Promise<FoodType>,
+    /** List translations of a food type */
+    getFoodTypeTranslations: (
+      args: {
+        /** Food type ID */
+        id: number;
+      },
+    ): Promise<Translation[]> =>
+      request("GET", `/food-types/${encodeURIComponent(String(args["id"]))}/translations`, "json", {}) as Promise<Translation[]>,
+    /** Set the translation of a food type */
+    setFoodTypeTranslation: (
+      args: {
+        /** Food type ID */
+        id: number;
+        /** Language, e.g. de */
+        locale: string;
+        body: TranslationRequest;
+      },
+    ): Promise<Translation> =>
+      request("PUT", `/food-types/${encodeURIComponent(String(args["id"]))}/translations/${encodeURIComponent(String(args["locale"]))}`, "json", { json: args.body }) as Promise<Translation>,
+    /** Delete the translation of a food type */
+    deleteFoodTypeTranslation: (
+      args: {
+        /** Food type ID */
+        id: number;
+        /** Language, e.g. de */
+        locale: string;
+      },
+    ): Promise<void> =>
+      request("DELETE", `/food-types/${encodeURIComponent(String(args["id"]))}/translations/${encodeURIComponent(String(args["locale"]))}`, "none", {}) as Promise<void>,
+    /** Geocode cities */
+    geocodeCities: (
+      args: {
+        /** City name to geocode */
+        q: string;
+        /** Look up the coordinates of each city (default true) */
+        coordinates?: boolean;
+      },
+    ): Promise<GooglePlaceResult[]> =>
+      request("GET", `/geocode/cities`, "json", { query: { "q": args["q"], "coordinates": args["coordinates"] } }) as Promise<GooglePlaceResult[]>,
+    /** Readiness probe */
+    readinessCheck: (): Promise<HealthResponse> =>
+      request("GET", `/health`, "json", {}) as Promise<HealthResponse>,
+    /** Liveness probe */
+    livenessCheck: (): Promise<Response> =>
+      request("GET", `/health/live`, "raw", {}) as Promise<Response>,
+    /** Readiness probe */
+    getHealthReady: (): Promise<HealthResponse> =>
+      request("GET", `/health/ready`, "json", {}) as Promise<HealthResponse>,
+    /** List API keys */
+    listAPIKeys: (): Promise<APIKey[]> =>
+      request("GET", `/keys`, "json", {}) as Promise<APIKey[]>,
+    /** Create an API key */
+    createAPIKey: (
+      args: {
+        body: CreateAPIKeyRequest;
+      },
+    ): Promise<CreateAPIKeyResponse> =>
+      request("POST", `/keys`, "json", { json: args.body }) as Promise<CreateAPIKeyResponse>,
+    /** Revoke an API key */
+    deleteAPIKey: (
+      args: {
+        /** API key ID */
+        id: number;
+      },
+    ): Promise<void> =>
+      request("DELETE", `/keys/${encodeURIComponent(String(args["id"]))}`, "none", {}) as Promise<void>,
+    /** Get the leaderboard */
+    getLeaderboard: (
+      args: {
+        /** month (default) or all */
+        period?: string;
+        /** Number of users per ranking (default 20, max 100) */
+        limit?: number;
+      } = {},
+    ): Promise<Leaderboard> =>
+      request("GET", `/leaderboard`, "json", { query: { "period": args["period"], "limit": args["limit"] } }) as Promise<Leaderboard>,
+    /** Get reference data */
+    getMeta: (): Promise<Meta> =>
+      request("GET", `/meta`, "json", {}) as Promise<Meta>,
+    /** Get a menu photo */
+    getMenuPhoto: (
+      args: {
+        /** Photo ID */
+        id: number;
+      },
+    ): Promise<MenuPhoto> =>
+      request("GET", `/photos/${encodeURIComponent(String(args["id"]))}`, "json", {}) as Promise<MenuPhoto>,
+    /** Update photo caption */
+    updatePhotoCaption: (
+      args: {
+        /** Photo ID */
+        id: number;
+        body: {
+  caption?: string;
+  photo_type?: string;
+};
+      },
+    ): Promise<MenuPhoto> =>
+      request("PATCH", `/photos/${encodeURIComponent(String(args["id"]))}`, "json", { json: args.body }) as Promise<MenuPhoto>,
+    /** Delete a menu photo */
+    deleteMenuPhoto: (
+      args: {
+        /** Photo ID */
+        id: number;
+      },
+    ): Promise<void> =>
+      request("DELETE", `/photos/${encodeURIComponent(String(args["id"]))}`, "none", {}) as Promise<void>,
+    /** Set cover photo */
+    setCoverPhoto: (
+      args: {
+        /** Photo ID */
+        id: number;
+      },
+    ): Promise<MenuPhoto> =>
+      request("PUT", `/photos/${encodeURIComponent(String(args["id"]))}/cover`, "json", {}) as Promise<MenuPhoto>,
+    /** Unset cover photo */
+    unsetCoverPhoto: (
+      args: {
+        /** Photo ID */
+        id: number;
+      },
+    ): Promise<void> =>
+      request("DELETE", `/photos/${encodeURIComponent(String(args["id"]))}/cover`, "none", {}) as Promise<void>,
+    /** Get a resized photo */
+    getPhotoImage: (
+      args: {
+        /** Photo ID */
+        id: number;
+        /** Maximum width in pixels (160, 320, 640, 1280 or 1920) */
+        w?: number;
+        /** Maximum height in pixels (160, 320, 640, 1280 or 1920) */
+        h?: number;
+        /** Output format (auto, jpeg, png, webp, avif) */
+        format?: string;
+      },
+    ): Promise<Response> =>
+      request("GET", `/photos/${encodeURIComponent(String(args["id"]))}/image`, "raw", { query: { "w": args["w"], "h": args["h"], "format": args["format"] } }) as Promise<Response>,
+    /** Get a place photo */
+    getPlacePhoto: (
+      args: {
+        /** Photo name (places/{placeId}/photos/{photoId}) */
+        name: string;
+        /** Maximum width in pixels (default 800, max 4800) */
+        max_width?: number;
+      },
+    ): Promise<void> =>
+      request("GET", `/places/photo`, "none", { query: { "name": args["name"], "max_width": args["max_width"] } }) as Promise<void>,
+    /** Search for places */
+    searchPlaces: (
+      args: {
+        /** Search query */
+        q: string;
+        /** Latitude to bias results towards (with lng) */
+        lat?: number;
+        /** Longitude to bias results towards (with lat) */
+        lng?: number;
+        /** Bias radius in km around lat/lng (default 5, max 50) */
+        radius?: number;
+        /** Next page token from X-Next-Page-Token */
+        page_token?: string;
+      },
+    ): Promise<GooglePlaceResult[]> =>
+      request("GET", `/places/search`, "json", { query: { "q": args["q"], "lat": args["lat"], "lng": args["lng"], "radius": args["radius"], "page_token": args["page_token"] } }) as Promise<GooglePlaceResult[]>,
+    /** Get place details */
+    getPlaceDetails: (
+      args: {
+        /** Place ID of a search result (Google Place ID, or OSM ID such as N123 with Nominatim) */
+        placeId: string;
+      },
+    ): Promise<GooglePlaceResult> =>
+      request("GET", `/places/${encodeURIComponent(String(args["placeId"]))}`, "json", {}) as Promise<GooglePlaceResult>,
+    /** Create a new rating */
+    createRating: (
+      args: {
+        body: CreateRatingRequest;
+      },
+    ): Promise<Rating> =>
+      request("POST", `/ratings`, "json", { json: args.body }) as Promise<Rating>,
+    /** Delete a rating */
+    deleteRating: (
+      args: {
+        /** Rating ID */
+        id: number;
+      },
+    ): Promise<void> =>
+      request("DELETE", `/ratings/${encodeURIComponent(String(args["id"]))}`, "none", {}) as Promise<void>,
+    /** Respond to a rating */
+    setRatingResponse: (
+      args: {
+        /** Rating ID */
+        id: number;
+        body: OwnerResponseRequest;
+      },
+    ): Promise<Rating> =>
+      request("PUT", `/ratings/${encodeURIComponent(String(args["id"]))}/response`, "json", { json: args.body }) as Promise<Rating>,
+    /** Delete a rating response */
+    deleteRatingResponse: (
+      args: {
+        /** Rating ID */
+        id: number;
+      },
+    ): Promise<void> =>
+      request("DELETE", `/ratings/${encodeURIComponent(String(args["id"]))}/response`, "none", {}) as Promise<void>,
+    /** List all restaurants */
+    getRestaurants: (
+      args: {
+        /** Filter by category ID */
+        category_id?: number;
+        /** Filter by food type IDs (comma-separated) */
+        food_type_ids?: string;
+        /** Latitude for distance filtering */
+        lat?: number;
+        /** Longitude for distance filtering */
+        lng?: number;
+        /** Radius in kilometers for distance filtering */
+        radius?: number;
+      } = {},
+    ): Promise<Restaurant[]> =>
+      request("GET", `/restaurants`, "json", { query: { "category_id": args["category_id"], "food_type_ids": args["food_type_ids"], "lat": args["lat"], "lng": args["lng"], "radius": args["radius"] } }) as Promise<Restaurant[]>,
+    /** Create a new restaurant */
+    createRestaurant: (
+      args: {
+        body: CreateRestaurantRequest;
+      },
+    ): Promise<Restaurant> =>
+      request("POST", `/restaurants`, "json", { json: args.body }) as Promise<Restaurant>,
+    /** Get restaurants in a map area */
+    getRestaurantsInBounds: (
+      args: {
+        /** Latitude of the north-east corner */
+        ne_lat: number;
+        /** Longitude of the north-east corner */
+        ne_lng: number;
+        /** Latitude of the south-west corner */
+        sw_lat: number;
+        /** Longitude of the south-west corner */
+        sw_lng: number;
+        /** Filter by category ID */
+        category_id?: number;
+        /** Filter by food type IDs (comma-separated) */
+        food_type_ids?: string;
+        /** Search query for name or description */
+        q?: string;
+      },
+    ): Promise<MapBoundsResponse> =>
+      request("GET", `/restaurants/in-bounds`, "json", { query: { "ne_lat": args["ne_lat"], "ne_lng": args["ne_lng"], "sw_lat": args["sw_lat"], "sw_lng": args["sw_lng"], "category_id": args["category_id"], "food_type_ids": args["food_type_ids"], "q": args["q"] } }) as Promise<MapBoundsResponse>,
+    /** Get restaurants near a location */
+    getRestaurantsNearby: (
+      args: {
+        /** Latitude of the location */
+        lat: number;
+        /** Longitude of the location */
+        lng: number;
+        /** Maximum number of restaurants (default 20, max 100) */
+        limit?: number;
+        /** Search radius in km (default 5, or how far the mode gets in max_minutes; max 50) */
+        radius?: number;
+        /** Travel mode: walking or driving */
+        mode?: string;
+        /** Maximum travel time in minutes (1 to 120), required with mode */
+        max_minutes?: number;
+        /** Filter by category ID */
+        category_id?: number;
+        /** Filter by food type IDs (comma-separated) */
+        food_type_ids?: string;
+        /** Search query for name or description */
+        q?: string;
+      },
+    ): Promise<Restaurant[]> =>
+      request("GET", `/restaurants/nearby`, "json", { query: { "lat": args["lat"], "lng": args["lng"], "limit": args["limit"], "radius": args["radius"], "mode": args["mode"], "max_minutes": args["max_minutes"], "category_id": args["category_id"], "food_type_ids": args["food_type_ids"], "q": args["q"] } }) as Promise<Restaurant[]>,
+    /** Get paginated list of restaurants */
+    getRestaurantsPaginated: (
+      args: {
+        /** Pagination cursor (next_cursor of the previous page) */
+        cursor?: string;
+        /** Sort order: id (default, oldest first), name (A to Z) or rating (highest first) */
+        sort?: string;
+        /** Number of items per page (default 20, max 100) */
+        limit?: number;
+        /** Filter by category ID */
+        category_id?: number;
+        /** Filter by food type IDs (comma-separated) */
+        food_type_ids?: string;
+        /** Search query for name or description */
+        q?: string;
+      } = {},
+    ): Promise<PaginatedResponse> =>
+      request("GET", `/restaurants/paginated`, "json", { query: { "cursor": args["cursor"], "sort": args["sort"], "limit": args["limit"], "category_id": args["category_id"], "food_type_ids": args["food_type_ids"], "q": args["q"] } }) as Promise<PaginatedResponse>,
+    /** Get a restaurant by ID */
+    getRestaurant: (
+      args: {
+        /** Restaurant ID */
+        id: number;
+      },
+    ): Promise<Restaurant> =>
+      request("GET", `/restaurants/${encodeURIComponent(String(args["id"]))}`, "json", {}) as Promise<Restaurant>,
+    /** Update a restaurant */
+    updateRestaurant: (
+      args: {
+        /** Restaurant ID */
+        id: number;
+        body: UpdateRestaurantRequest;
+      },
+    ): Promise<Restaurant> =>
+      request("PUT", `/restaurants/${encodeURIComponent(String(args["id"]))}`, "json", { json: args.body }) as Promise<Restaurant>,
+    /** Delete a restaurant */
+    deleteRestaurant: (
+      args: {
+        /** Restaurant ID */
+        id: number;
+      },
+    ): Promise<void> =>
+      request("DELETE", `/restaurants/${encodeURIComponent(String(args["id"]))}`, "none", {}) as Promise<void>,
+    /** Update business details */
+    updateRestaurantBusiness: (
+      args: {
+        /** Restaurant ID */
+        id: number;
+        body: UpdateRestaurantBusinessRequest;
+      },
+    ): Promise<Restaurant> =>
+      request("PATCH", `/restaurants/${encodeURIComponent(String(args["id"]))}/business`, "json", { json: args.body }) as Promise<Restaurant>,
+    /** Claim a restaurant */
+    claimRestaurant: (
+      args: {
+        /** Restaurant ID */
+        id: number;
+        body?: CreateRestaurantClaimRequest;
+      },
+    ): Promise<RestaurantClaim> =>
+      request("POST", `/restaurants/${encodeURIComponent(String(args["id"]))}/claims`, "json", { json: args.body }) as Promise<RestaurantClaim>,
+    /** Get map links of a restaurant */
+    getRestaurantMap: (
+      args: {
+        /** Restaurant ID */
+        id: number;
+        /** Image width in pixels (default 400, max 640) */
+        width?: number;
+        /** Image height in pixels (default 300, max 640) */
+        height?: number;
+        /** Zoom level from 0 (world) to 21 (buildings) (default 15) */
+        zoom?: number;
+        /** 1, or 2 for high-density screens (default 1) */
+        scale?: number;
+      },
+    ): Promise<RestaurantMap> =>
+      request("GET", `/restaurants/${encodeURIComponent(String(args["id"]))}/map`, "json", { query: { "width": args["width"], "height": args["height"], "zoom": args["zoom"], "scale": args["scale"] } }) as Promise<RestaurantMap>,
+    /** Get a map image of a restaurant */
+    getRestaurantMapImage: (
+      args: {
+        /** Restaurant ID */
+        id: number;
+        /** Image width in pixels (default 400, max 640) */
+        width?: number;
+        /** Image height in pixels (default 300, max 640) */
+        height?: number;
+        /** Zoom level from 0 (world) to 21 (buildings) (default 15) */
+        zoom?: number;
+        /** 1, or 2 for high-density screens (default 1) */
+        scale?: number;
+      },
+    ): Promise<Response> =>
+      request("GET", `/restaurants/${encodeURIComponent(String(args["id"]))}/map/image`, "raw", { query: { "width": args["width"], "height": args["height"], "zoom": args["zoom"], "scale": args["scale"] } }) as Promise<Response>,
+    /** Get menu photos for a restaurant */
+    getMenuPhotos: (
+      args: {
+        /** Restaurant ID */
+        restaurantId: number;
+        /** Photo type (menu, food, interior, receipt) */
+        type?: string;
+        /** Number of photos per page (default: 50, max: 100) */
+        limit?: number;
+        /** Pagination cursor from X-Next-Cursor */
+        cursor?: string;
+      },
+    ): Promise<MenuPhoto[]> =>
+      request("GET", `/restaurants/${encodeURIComponent(String(args["restaurantId"]))}/photos`, "json", { query: { "type": args["type"], "limit": args["limit"], "cursor": args["cursor"] } }) as Promise<MenuPhoto[]>,
+    /** Upload a menu photo */
+    uploadMenuPhoto: (
+      args: {
+        /** Restaurant ID */
+        restaurantId: number;
+        form: {
+          /** Photo caption */
+          caption: string;
+          /** Menu photo file */
+          photo: Blob;
+          /** Photo type (menu, food, interior, receipt) */
+          photo_type?: string;
+        };
+      },
+    ): Promise<UploadPhotoResponse> =>
+      request("POST", `/restaurants/${encodeURIComponent(String(args["restaurantId"]))}/photos`, "json", { form: args.form }) as Promise<UploadPhotoResponse>,
+    /** Download all photos of a restaurant */
+    downloadPhotoArchive: (
+      args: {
+        /** Restaurant ID */
+        restaurantId: number;
+      },
+    ): Promise<Response> =>
+      request("GET", `/restaurants/${encodeURIComponent(String(args["restaurantId"]))}/photos/archive`, "raw", {}) as Promise<Response>,
+    /** Confirm a presigned photo upload */
+    confirmMenuPhotoUpload: (
+      args: {
+        /** Restaurant ID */
+        restaurantId: number;
+        body: ConfirmPhotoUploadRequest;
+      },
+    ): Promise<UploadPhotoResponse> =>
+      request("POST", `/restaurants/${encodeURIComponent(String(args["restaurantId"]))}/photos/confirm`, "json", { json: args.body }) as Promise<UploadPhotoResponse>,
+    /** Stream photo processing events */
+    streamPhotoEvents: (
+      args: {
+        /** Restaurant ID */
+        restaurantId: number;
+      },
+    ): Promise<Response> =>
+      request("GET", `/restaurants/${encodeURIComponent(String(args["restaurantId"]))}/photos/events`, "raw", {}) as Promise<Response>,
+    /** Upload a menu photo from a URL */
+    uploadMenuPhotoFromURL: (
+      args: {
+        /** Restaurant ID */
+        restaurantId: number;
+        body: UploadPhotoFromURLRequest;
+      },
+    ): Promise<UploadPhotoResponse> =>
+      request("POST", `/restaurants/${encodeURIComponent(String(args["restaurantId"]))}/photos/from-url`, "json", { json: args.body }) as Promise<UploadPhotoResponse>,
+    /** Reorder menu photos */
+    reorderMenuPhotos: (
+      args: {
+        /** Restaurant ID */
+        restaurantId: number;
+        body: ReorderPhotosRequest;
+      },
+    ): Promise<MenuPhoto[]> =>
+      request("PATCH", `/restaurants/${encodeURIComponent(String(args["restaurantId"]))}/photos/order`, "json", { json: args.body }) as Promise<MenuPhoto[]>,
+    /** Request a presigned photo upload */
+    presignMenuPhotoUpload: (
+      args: {
+        /** Restaurant ID */
+        restaurantId: number;
+        body: PresignPhotoUploadRequest;
+      },
+    ): Promise<PresignPhotoUploadResponse> =>
+      request("POST", `/restaurants/${encodeURIComponent(String(args["restaurantId"]))}/photos/presign`, "json", { json: args.body }) as Promise<PresignPhotoUploadResponse>,
+    /** Get ratings for a restaurant */
+    getRatings: (
+      args: {
+        /** Restaurant ID */
+        restaurantId: number;
+      },
+    ): Promise<Rating[]> =>
+      request("GET", `/restaurants/${encodeURIComponent(String(args["restaurantId"]))}/ratings`, "json", {}) as Promise<Rating[]>,
+    /** Global search for restaurants and suggestions */
+    globalSearch: (
+      args: {
+        /** Search query string */
+        q: string;
+      },
+    ): Promise<Restaurant[]> =>
+      request("GET", `/search`, "json", { query: { "q": args["q"] } }) as Promise<Restaurant[]>,
+    /** List spaces */
+    listSpaces: (): Promise<Space[]> =>
+      request("GET", `/spaces`, "json", {}) as Promise<Space[]>,
+    /** Create a space */
+    createSpace: (
+      args: {
+        body: CreateSpaceRequest;
+      },
+    ): Promise<Space> =>
+      request("POST", `/spaces`, "json", { json: args.body }) as Promise<Space>,
+    /** Join a space */
+    joinSpace: (
+      args: {
+        body: JoinSpaceRequest;
+      },
+    ): Promise<Space> =>
+      request("POST", `/spaces/join`, "json", { json: args.body }) as Promise<Space>,
+    /** Get a space */
+    getSpace: (
+      args: {
+        /** Space slug */
+        slug: string;
+      },
+    ): Promise<Space> =>
+      request("GET", `/spaces/${encodeURIComponent(String(args["slug"]))}`, "json", {}) as Promise<Space>,
+    /** Update a space */
+    updateSpace: (
+      args: {
+        /** Space slug */
+        slug: string;
+        body: UpdateSpaceRequest;
+      },
+    ): Promise<Space> =>
+      request("PUT", `/spaces/${encodeURIComponent(String(args["slug"]))}`, "json", { json: args.body }) as Promise<Space>,
+    /** Delete a space */
+    deleteSpace: (
+      args: {
+        /** Space slug */
+        slug: string;
+      },
+    ): Promise<void> =>
+      request("DELETE", `/spaces/${encodeURIComponent(String(args["slug"]))}`, "none", {}) as Promise<void>,
+    /** List space invites */
+    listSpaceInvites: (
+      args: {
+        /** Space slug */
+        slug: string;
+      },
+    ): Promise<SpaceInvite[]> =>
+      request("GET", `/spaces/${encodeURIComponent(String(args["slug"]))}/invites`, "json", {}) as Promise<SpaceInvite[]>,
+    /** Create a space invite */
+    createSpaceInvite: (
+      args: {
+        /** Space slug */
+        slug: string;
+        body: CreateSpaceInviteRequest;
+      },
+    ): Promise<CreateSpaceInviteResponse> =>
+      request("POST", `/spaces/${encodeURIComponent(String(args["slug"]))}/invites`, "json", { json: args.body }) as Promise<CreateSpaceInviteResponse>,
+    /** Revoke a space invite */
+    revokeSpaceInvite: (
+      args: {
+        /** Space slug */
+        slug: string;
+        /** Invite ID */
+        id: number;
+      },
+    ): Promise<void> =>
+      request("DELETE", `/spaces/${encodeURIComponent(String(args["slug"]))}/invites/${encodeURIComponent(String(args["id"]))}`, "none", {}) as Promise<void>,
+    /** List the members of a space */
+    listSpaceMembers: (
+      args: {
+        /** Space slug */
+        slug: string;
+      },
+    ): Promise<SpaceMember[]> =>
+      request("GET", `/spaces/${encodeURIComponent(String(args["slug"]))}/members`, "json", {}) as Promise<SpaceMember[]>,
+    /** Add or change a member of a space */
+    setSpaceMember: (
+      args: {
+        /** Space slug */
+        slug: string;
+        /** User ID */
+        userId: number;
+        body: SpaceMemberRequest;
+      },
+    ): Promise<SpaceMember> =>
+      request("PUT", `/spaces/${encodeURIComponent(String(args["slug"]))}/members/${encodeURIComponent(String(args["userId"]))}`, "json", { json: args.body }) as Promise<SpaceMember>,
+    /** Remove a member from a space */
+    removeSpaceMember: (
+      args: {
+        /** Space slug */
+        slug: string;
+        /** User ID */
+        userId: number;
+      },
+    ): Promise<void> =>
+      request("DELETE", `/spaces/${encodeURIComponent(String(args["slug"]))}/members/${encodeURIComponent(String(args["userId"]))}`, "none", {}) as Promise<void>,
+    /** List all restaurant suggestions */
+    getSuggestions: (
+      args: {
+        /** Filter by status (pending, approved, tested, rejected) */
+        status?: string;
+      } = {},
+    ): Promise<RestaurantSuggestion[]> =>
+      request("GET", `/suggestions`, "json", { query: { "status": args["status"] } }) as Promise<RestaurantSuggestion[]>,
+    /** Create a new restaurant suggestion */
+    createSuggestion: (
+      args: {
+        body: CreateSuggestionRequest;
+      },
+    ): Promise<RestaurantSuggestion> =>
+      request("POST", `/suggestions`, "json", { json: args.body }) as Promise<RestaurantSuggestion>,
+    /** Get a suggestion by ID */
+    getSuggestion: (
+      args: {
+        /** Suggestion ID */
+        id: number;
+      },
+    ): Promise<RestaurantSuggestion> =>
+      request("GET", `/suggestions/${encodeURIComponent(String(args["id"]))}`, "json", {}) as Promise<RestaurantSuggestion>,
+    /** Delete a suggestion */
+    deleteSuggestion: (
+      args: {
+        /** Suggestion ID */
+        id: number;
+      },
+    ): Promise<void> =>
+      request("DELETE", `/suggestions/${encodeURIComponent(String(args["id"]))}`, "none", {}) as Promise<void>,
+    /** Get suggestion approvals */
+    getSuggestionApprovals: (
+      args: {
+        /** Suggestion ID */
+        id: number;
+      },
+    ): Promise<SuggestionApprovalStatus> =>
+      request("GET", `/suggestions/${encodeURIComponent(String(args["id"]))}/approvals`, "json", {}) as Promise<SuggestionApprovalStatus>,
+    /** Approve a suggestion */
+    approveSuggestion: (
+      args: {
+        /** Suggestion ID */
+        id: number;
+        body?: ApproveSuggestionRequest;
+      },
+    ): Promise<SuggestionApprovalStatus> =>
+      request("POST", `/suggestions/${encodeURIComponent(String(args["id"]))}/approvals`, "json", { json: args.body }) as Promise<SuggestionApprovalStatus>,
+    /** Revoke a suggestion approval */
+    revokeSuggestionApproval: (
+      args: {
+        /** Suggestion ID */
+        id: number;
+      },
+    ): Promise<SuggestionApprovalStatus> =>
+      request("DELETE", `/suggestions/${encodeURIComponent(String(args["id"]))}/approvals`, "json", {}) as Promise<SuggestionApprovalStatus>,
+    /** Convert suggestion to restaurant */
+    convertSuggestion: (
+      args: {
+        /** Suggestion ID */
+        id: number;
+        body: ConvertSuggestionRequest;
+      },
+    ): Promise<Record<string, unknown>> =>
+      request("POST", `/suggestions/${encodeURIComponent(String(args["id"]))}/convert`, "json", { json: args.body }) as Promise<Record<string, unknown>>,
+    /** Get suggestion activity log */
+    getSuggestionEvents: (
+      args: {
+        /** Suggestion ID */
+        id: number;
+      },
+    ): Promise<SuggestionEvent[]> =>
+      request("GET", `/suggestions/${encodeURIComponent(String(args["id"]))}/events`, "json", {}) as Promise<SuggestionEvent[]>,
+    /** Update suggestion status */
+    updateSuggestionStatus: (
+      args: {
+        /** Suggestion ID */
+        id: number;
+        body: UpdateSuggestionStatusRequest;
+      },
+    ): Promise<RestaurantSuggestion> =>
+      request("PATCH", `/suggestions/${encodeURIComponent(String(args["id"]))}/status`, "json", { json: args.body }) as Promise<RestaurantSuggestion>,
+    /** Follow a user */
+    followUser: (
+      args: {
+        /** User ID */
+        id: number;
+      },
+    ): Promise<void> =>
+      request("POST", `/users/${encodeURIComponent(String(args["id"]))}/follow`, "none", {}) as Promise<void>,
+    /** Unfollow a user */
+    unfollowUser: (
+      args: {
+        /** User ID */
+        id: number;
+      },
+    ): Promise<void> =>
+      request("DELETE", `/users/${encodeURIComponent(String(args["id"]))}/follow`, "none", {}) as Promise<void>,
+    /** Get the contribution stats of a user */
+    getUserStats: (
+      args: {
+        /** User ID */
+        id: number;
+      },
+    ): Promise<UserStats> =>
+      request("GET", `/users/${encodeURIComponent(String(args["id"]))}/stats`, "json", {}) as Promise<UserStats>,
+  };
+}
+
+export type Client = ReturnType<typeof createClient>;
@@ -99,6 +99,20 @@ export interface GooglePlaceResult {
   longitude: number;
 }
 
+// Errors are RFC 7807 problem details; fall back to the raw body for non-API responses
+async function errorMessage(response: Response, fallback: string): Promise<string> {
+  const body = await response.text();
+  try {
+    const problem = JSON.parse(body);
+    if (typeof problem?.detail === 'string') {
+      return problem.detail;
+    }
+  } catch {
+    // Not JSON
+  }
+  return body || fallback;
+}
+
 async function fetchApi<T>(endpoint: string, options?: RequestInit): Promise<T> {
   const response = await fetch(`${API_URL}/api${endpoint}`, {
     headers: {
@@ -108,8 +122,7 @@ async function fetchApi<T>(endpoint: string, options?: RequestInit): Promise<T>
   });
 
   if (!response.ok) {
-    const error = await response.text();
-    throw new Error(error || 'An error occurred');
+    throw new Error(await errorMessage(response, 'An error occurred'));
   }
 
   if (response.status === 204) {
@@ -330,8 +343,7 @@ export const uploadMenuPhoto = async (restaurantId: number, photo: File, caption
   });
 
   if (!response.ok) {
-    const error = await response.text();
-    throw new Error(error || 'Failed to upload photo');
+    throw new Error(await errorMessage(response, 'Failed to upload photo'));
   }
 
   return response.json();